@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+)
+
+func main() {
+	reset := flag.Bool("reset", false, "Empty the projection and its cursors first, rebuilding it from the first indexed event")
+	batch := flag.Int("batch", services.DefaultActivityProjectionBatchSize, "Indexer events copied per query")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: go run ./cmd/backfill-activity [-reset] [-batch N]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// The projection table comes from the migrations, so run cmd/migrate first
+	if err := database.EnsureMigrated(); err != nil {
+		log.Fatalf("Database not ready: %v", err)
+	}
+
+	// Running servers skip their projector runs while the backfill holds the lock
+	ctx := context.Background()
+	lock := services.NewSyncLock(database.GetDB(), services.SyncServiceActivityProjection, cfg.Sync.InstanceID, cfg.Sync.LockStaleAfter)
+	lease, err := lock.TryAcquire(ctx)
+	if err != nil {
+		log.Fatalf("Failed to take the activity projection lock: %v", err)
+	}
+	defer lease.Release()
+
+	projector := services.NewDefaultActivityProjector(*batch, cfg.Reorg.LookbackBlocks, 0)
+	if *reset {
+		if err := projector.Reset(ctx); err != nil {
+			log.Fatalf("Failed to reset the activity projection: %v", err)
+		}
+		log.Println("Emptied the activity projection")
+	}
+
+	result, err := projector.Run(ctx)
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+	for _, info := range models.EventActivityTypes() {
+		log.Printf("Projected %d new %s activities", result.Projected[info.Type], info.Type)
+	}
+}
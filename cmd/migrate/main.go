@@ -1,24 +1,99 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"strconv"
 
 	"sukuk-be/internal/config"
 	"sukuk-be/internal/database"
 )
 
+const usage = `Usage: go run ./cmd/migrate <command>
+
+Commands:
+  up             Apply all pending migrations
+  down [N]       Roll back the last N migrations (default 1)
+  status         Show applied and pending migrations
+  force VERSION  Mark migrations up to VERSION as applied without running them`
+
 func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Setup database (create if needed, connect, migrate)
-	if err := database.SetupDatabase(cfg); err != nil {
-		log.Fatalf("Failed to setup database: %v", err)
+	// Create database if needed and connect
+	if err := database.CreateDatabaseIfNotExists(cfg); err != nil {
+		log.Fatalf("Failed to create database: %v", err)
+	}
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer database.Close()
 
-	log.Println("Migrations completed successfully!")
+	migrator, err := database.NewMigrator(database.GetDB())
+	if err != nil {
+		log.Fatalf("Failed to initialize migrator: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("Migration failed after applying %d migration(s): %v", applied, err)
+		}
+		log.Printf("Applied %d migration(s), schema at version %d", applied, migrator.LatestVersion())
+
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps: %s", os.Args[2])
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		if err != nil {
+			log.Fatalf("Rollback failed after reverting %d migration(s): %v", rolledBack, err)
+		}
+		log.Printf("Rolled back %d migration(s)", rolledBack)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("Failed to get migration status: %v", err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", status.Version, status.Name, state)
+		}
+
+	case "force":
+		if len(os.Args) < 3 {
+			log.Fatal("force requires a VERSION argument")
+		}
+		version, err := strconv.Atoi(os.Args[2])
+		if err != nil || version < 0 {
+			log.Fatalf("Invalid version: %s", os.Args[2])
+		}
+		if err := migrator.Force(version); err != nil {
+			log.Fatalf("Failed to force version: %v", err)
+		}
+		log.Printf("Schema version forced to %d", version)
+
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/database"
+)
+
+func main() {
+	profileNames := make([]string, 0, len(database.SeedProfiles()))
+	for _, profile := range database.SeedProfiles() {
+		profileNames = append(profileNames, string(profile))
+	}
+
+	profileFlag := flag.String("profile", string(database.SeedProfileDemo), "Seed profile: "+strings.Join(profileNames, ", "))
+	wipe := flag.Bool("wipe", false, "Empty the seeded tables and drop the synthetic indexer tables first (only when APP_ENV is development or test)")
+	confirmWipe := flag.Bool("confirm-wipe", false, "Confirm -wipe, which deletes every row of the seeded tables")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: go run ./cmd/seed [-profile minimal|demo|load-test] [-wipe -confirm-wipe]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	profile, err := database.ParseSeedProfile(*profileFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if err := database.Connect(cfg); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.Close()
+
+	// Seeding writes to the migrated schema, so run cmd/migrate first
+	if err := database.EnsureMigrated(); err != nil {
+		log.Fatalf("Database not ready: %v", err)
+	}
+
+	if *wipe {
+		if err := database.WipeSeedData(database.GetDB(), cfg.App.Environment, *confirmWipe); err != nil {
+			log.Fatalf("Failed to wipe seed data: %v", err)
+		}
+		log.Println("Wiped seeded tables")
+	}
+
+	if err := database.SeedData(database.GetDB(), profile); err != nil {
+		log.Fatalf("Failed to seed %s profile: %v", profile, err)
+	}
+	log.Printf("Seeded %s profile", profile)
+}
@@ -24,128 +24,209 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/system/force-sync": {
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Manually trigger blockchain event synchronization",
-                "consumes": [
-                    "application/json"
-                ],
+        "/activities": {
+            "get": {
+                "description": "Newest purchases, redemption requests and yield claims across all sukuk, globally ordered by timestamp. Addresses are EIP-55 checksummed and amounts are returned raw and formatted in their payment token. Pass next_cursor as ?cursor= for the following page; the first page is cached for a few seconds. Transfers between wallets are only listed per wallet, so filtering by transfer_in or transfer_out returns an empty page",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "activities"
+                ],
+                "summary": "Get the platform activity feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of activities to return (max 100; defaults to the activity_feed.default_limit setting)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "purchase",
+                            "redemption_request",
+                            "yield_claim"
+                        ],
+                        "type": "string",
+                        "description": "Only return this activity type",
+                        "name": "type",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Force blockchain sync",
                 "responses": {
                     "200": {
-                        "description": "Sync triggered",
+                        "description": "Activity feed page",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityFeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor or activity type",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/admin/system/sync-status": {
+        "/admin/analytics/cohorts": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get the current blockchain synchronization status and last processed event",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Bucket investors by the month of their first purchase (Asia/Jakarta) and count, per cohort, the investors matching metric in each month since, as a heatmap matrix: values[N] and rates[N] are the count and fraction of the cohort N months after its first purchase month, up to the to month. repeat_purchase counts investors with a purchase in the month, so month 0 is the whole cohort. retained_balance counts investors whose latest holder_update as of the end of the month is non-zero in at least one sukuk. Results are cached for an hour",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Admin"
+                    "admin"
+                ],
+                "summary": "Get investor cohort analytics",
+                "parameters": [
+                    {
+                        "enum": [
+                            "repeat_purchase",
+                            "retained_balance"
+                        ],
+                        "type": "string",
+                        "default": "repeat_purchase",
+                        "description": "Metric to count",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01",
+                        "description": "First cohort month, YYYY-MM; defaults to 11 months before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12",
+                        "description": "Last cohort and activity month, YYYY-MM; defaults to the current month. At most 24 months from from",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get blockchain sync status",
                 "responses": {
                     "200": {
-                        "description": "Sync status",
+                        "description": "Cohort matrix",
+                        "schema": {
+                            "$ref": "#/definitions/models.CohortAnalyticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown metric, malformed or future month, or more than 24 months",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/debug/indexer": {
+        "/admin/api-keys/{id}/usage": {
             "get": {
-                "description": "Test connection to indexer database and query sample data",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Hourly requests, errors (status 400 and above) and response bytes of an API key, identified by the api_key_id fingerprint shown in the access log (first 12 hex digits of the key's SHA-256), with totals. Only requests the key authenticated are counted. from is rounded down and to up to whole hours; to defaults to now and from to a day before to, at most 31 days. Counts not yet flushed to the database are included. Hours without traffic are left out",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debug"
+                    "admin"
                 ],
-                "summary": "Debug indexer connection",
+                "summary": "Get API key usage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Sukuk address to test",
-                        "name": "address",
+                        "example": "3f2a9c1b7d4e",
+                        "description": "API key fingerprint",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window start (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window end, exclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Debug information",
+                        "description": "Hourly usage and totals",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyUsageReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid key ID or window",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
-                    }
-                }
-            }
-        },
-        "/debug/indexer-tables": {
-            "get": {
-                "description": "Get all discovered hash-prefixed indexer tables with metadata and row counts",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "debug"
-                ],
-                "summary": "List indexer tables",
-                "responses": {
-                    "200": {
-                        "description": "Discovered indexer tables",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.IndexerTablesResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -160,9 +241,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/debug/indexer-tables/prefix/{hash_prefix}": {
+        "/admin/digest/{address}": {
             "get": {
-                "description": "Get all tables that share the same hash prefix (deployment)",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Compiles yield distributions on sukuk the address holds (with its pro-rata entitlement from the current balance), its redemption requests and approvals, its holder_update balance changes, and held sukuk maturing within 30 days. Without since, the window starts where the previous digest ended (or 24 hours ago for the first) and the next digest starts where this one ends, so events are never repeated. An explicit since replays from that time and leaves the bookkeeping alone. Amounts are raw values",
                 "consumes": [
                     "application/json"
                 ],
@@ -170,29 +256,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "debug"
+                    "admin"
                 ],
-                "summary": "Get tables by hash prefix",
+                "summary": "Get the activity digest of an address",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"f243\"",
-                        "description": "Hash prefix",
-                        "name": "hash_prefix",
+                        "description": "Investor address",
+                        "name": "address",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Unix timestamp in seconds to replay from",
+                        "name": "since",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Tables with hash prefix",
+                        "description": "Digest",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.DigestResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid hash prefix",
+                        "description": "Invalid address or since",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Another digest for this address was compiled at the same time",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -212,9 +320,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/debug/indexer-tables/validate": {
-            "get": {
-                "description": "Validate that all discovered indexer tables have expected columns",
+        "/admin/indexer-tables/overrides": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Pin the table read for an event type (the table name suffix, e.g. holder_update), bypassing the max block / row count discovery heuristic. An empty table name removes the override. Every table must exist, belong to the event type and have the common event columns, or nothing is changed. Takes effect on the next indexer read",
                 "consumes": [
                     "application/json"
                 ],
@@ -222,15 +335,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "debug"
+                    "admin"
+                ],
+                "summary": "Set indexer table overrides",
+                "parameters": [
+                    {
+                        "description": "Table name per event type",
+                        "name": "overrides",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IndexerTableOverridesRequest"
+                        }
+                    }
                 ],
-                "summary": "Validate indexer tables",
                 "responses": {
                     "200": {
-                        "description": "Validation results",
+                        "description": "Overrides after the change",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IndexerTableOverride"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or non-existent table",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -245,9 +389,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/debug/indexer-tables/{table_name}": {
+        "/admin/investors": {
             "get": {
-                "description": "Get detailed information about a specific indexer table including column structure",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all investor profiles with optional filtering by KYC status",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,29 +404,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "debug"
+                    "admin"
                 ],
-                "summary": "Get table details",
+                "summary": "List investor profiles",
                 "parameters": [
                     {
+                        "enum": [
+                            "pending",
+                            "verified",
+                            "rejected"
+                        ],
                         "type": "string",
-                        "example": "\"f243__sukuk_purchase\"",
-                        "description": "Table name",
-                        "name": "table_name",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by KYC status",
+                        "name": "kyc_status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Table details",
+                        "description": "List of investor profiles",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InvestorProfile"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid table name",
+                        "description": "Invalid KYC status",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -285,8 +439,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Table not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -304,11 +458,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Get overall system health including database and sync status",
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an investor profile linked to a wallet address. New profiles start with pending KYC status.",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,30 +473,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "admin"
                 ],
-                "summary": "Get system health",
-                "responses": {
-                    "200": {
-                        "description": "System health",
+                "summary": "Create investor profile",
+                "parameters": [
+                    {
+                        "description": "Investor profile",
+                        "name": "investor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvestorProfileCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created investor profile",
+                        "schema": {
+                            "$ref": "#/definitions/models.InvestorProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Investor profile already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/owned-sukuk/{address}": {
+        "/admin/investors/{address}": {
             "get": {
-                "description": "Get sukuk metadata for sukuk tokens owned by a specific wallet address. Only returns sukuk with metadata_ready=true by default.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get an investor profile by wallet address, including KYC reviews",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,14 +548,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "owned-sukuk"
+                    "admin"
                 ],
-                "summary": "Get sukuk owned by address",
+                "summary": "Get investor profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
+                        "description": "Investor wallet address",
                         "name": "address",
                         "in": "path",
                         "required": true
@@ -362,13 +562,22 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Owned sukuk metadata",
+                        "description": "Investor profile",
                         "schema": {
-                            "$ref": "#/definitions/handlers.OwnedSukukResponse"
+                            "$ref": "#/definitions/models.InvestorProfile"
                         }
                     },
-                    "400": {
-                        "description": "Invalid address",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Investor profile not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -386,11 +595,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/portfolio/{address}": {
-            "get": {
-                "description": "Get complete portfolio showing all sukuk holdings with current balances and claimable yields",
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update investor details or KYC status. A rejected investor can only leave rejected, to pending or verified, by recording a new review.",
                 "consumes": [
                     "application/json"
                 ],
@@ -398,28 +610,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "portfolio"
+                    "admin"
                 ],
-                "summary": "Get user portfolio",
+                "summary": "Update investor profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
+                        "description": "Investor wallet address",
                         "name": "address",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "investor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InvestorProfileUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User portfolio with holdings",
+                        "description": "Updated investor profile",
                         "schema": {
-                            "$ref": "#/definitions/models.PortfolioResponse"
+                            "$ref": "#/definitions/models.InvestorProfile"
                         }
                     },
                     "400": {
-                        "description": "Invalid address",
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Investor profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid KYC status transition",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -437,11 +684,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/redemptions": {
-            "get": {
-                "description": "Get all redemption requests with their approval status, supports pagination",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove an investor profile and its KYC review history",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,77 +699,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "redemptions"
+                    "admin"
                 ],
-                "summary": "Get all redemptions",
+                "summary": "Delete investor profile",
                 "parameters": [
                     {
-                        "maximum": 200,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Number of redemptions to return",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 0,
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of redemptions to skip",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "requested",
-                            "approved",
-                            "rejected",
-                            "completed"
-                        ],
                         "type": "string",
-                        "description": "Filter by status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of redemptions with status",
+                        "description": "Investor profile deleted",
                         "schema": {
-                            "$ref": "#/definitions/models.RedemptionListResponse"
+                            "$ref": "#/definitions/handlers.MessageResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/redemptions/stats": {
-            "get": {
-                "description": "Get comprehensive statistics about all redemptions",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "redemptions"
-                ],
-                "summary": "Get redemption statistics",
-                "responses": {
-                    "200": {
-                        "description": "Redemption statistics",
+                    },
+                    "404": {
+                        "description": "Investor profile not found",
                         "schema": {
-                            "$ref": "#/definitions/models.RedemptionStatsResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -534,9 +748,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/redemptions/sukuk/{sukuk_address}": {
-            "get": {
-                "description": "Get all redemption requests and approvals for a specific sukuk",
+        "/admin/investors/{address}/reviews": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Record a compliance review for an investor and move the investor to the review decision",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,28 +763,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "redemptions"
+                    "admin"
                 ],
-                "summary": "Get sukuk redemptions",
+                "summary": "Record KYC review",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"0x02ba44871BD555d6ebD541e2820796F9b88cBF75\"",
-                        "description": "Sukuk contract address",
-                        "name": "sukuk_address",
+                        "description": "Investor wallet address",
+                        "name": "address",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "KYC review",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.KYCReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Sukuk's redemptions",
+                    "201": {
+                        "description": "Investor profile with updated KYC status",
                         "schema": {
-                            "$ref": "#/definitions/models.RedemptionListResponse"
+                            "$ref": "#/definitions/models.InvestorProfile"
                         }
                     },
                     "400": {
-                        "description": "Invalid sukuk address",
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Investor profile not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid KYC status transition",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -585,38 +839,61 @@ const docTemplate = `{
                 }
             }
         },
-        "/redemptions/user/{address}": {
+        "/admin/issuers/{address}/investor-report": {
             "get": {
-                "description": "Get all redemption requests and approvals for a specific user",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "For every investor active during the month (Asia/Jakarta boundaries) on sukuk whose owner_address is the issuer, sum purchases, redemption requests, approved redemptions and yield claimed, one row per investor per sukuk, with the investor's KYC status when a profile exists. Amounts are raw values. CSV is streamed as a download; months without activity return only the header row",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "redemptions"
+                    "admin"
                 ],
-                "summary": "Get user redemptions",
+                "summary": "Get monthly investor activity report for an issuer",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
+                        "description": "Issuer (sukuk owner) address",
                         "name": "address",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-05",
+                        "description": "Month as YYYY-MM, defaults to the current month",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User's redemptions",
+                        "description": "Investor activity per sukuk",
                         "schema": {
-                            "$ref": "#/definitions/models.RedemptionListResponse"
+                            "$ref": "#/definitions/models.InvestorReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid address",
+                        "description": "Invalid address, month or format",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -624,21 +901,35 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
             }
         },
-        "/redemptions/{request_id}": {
+        "/admin/ledger": {
             "get": {
-                "description": "Get detailed information about a specific redemption request",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the double-entry ledger of purchases, redemption payouts and yield payments, newest first. Each movement is a debit to the account receiving value and an equal credit to the account paying it; a sukuk's treasury account is its contract address. With account, balances sums the account's matching entries per token (debits minus credits). Amounts are raw values",
                 "consumes": [
                     "application/json"
                 ],
@@ -646,27 +937,72 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "redemptions"
+                    "admin"
                 ],
-                "summary": "Get redemption by ID",
+                "summary": "List ledger entries",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Redemption request ID",
-                        "name": "request_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Wallet or sukuk treasury address; adds per-token balances",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "purchase",
+                            "redemption_payout",
+                            "yield_payment"
+                        ],
+                        "type": "string",
+                        "description": "Filter by movement",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range start, inclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range end, exclusive (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of entries to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Redemption details",
+                        "description": "Ledger entries",
                         "schema": {
-                            "$ref": "#/definitions/models.RedemptionRequest"
+                            "$ref": "#/definitions/models.LedgerListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request ID",
+                        "description": "Invalid filter",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -674,8 +1010,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Redemption not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -695,9 +1031,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/snapshots": {
-            "get": {
-                "description": "Get balance snapshots for all sukuk tokens",
+        "/admin/maintenance/cleanup-uploads": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete uploaded files that no sukuk metadata references and that are older than the grace period. With dry_run=true, only list the files that would be deleted",
                 "consumes": [
                     "application/json"
                 ],
@@ -705,22 +1046,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "snapshots"
+                    "admin"
                 ],
-                "summary": "Get all snapshots",
+                "summary": "Clean up orphaned uploads",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of snapshots to return (default: 50, max: 200)",
-                        "name": "limit",
+                        "type": "boolean",
+                        "description": "List candidates without deleting",
+                        "name": "dry_run",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "All snapshots",
+                        "description": "Cleanup result",
                         "schema": {
-                            "$ref": "#/definitions/handlers.AllSnapshotsResponse"
+                            "$ref": "#/definitions/services.UploadCleanupResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -735,9 +1085,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sukuk-metadata": {
-            "get": {
-                "description": "Get all sukuk metadata with optional filtering by ready status and latest 10 blockchain activities",
+        "/admin/maintenance/prune": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete processed sukuk purchase and redemption request events older than the configured retention (RETENTION_*_DAYS), in bounded batches. Unprocessed events are never deleted. With dry_run=true, only report the eligible rows per table",
                 "consumes": [
                     "application/json"
                 ],
@@ -745,29 +1100,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "List sukuk metadata with activities",
+                "summary": "Prune processed events",
                 "parameters": [
                     {
-                        "enum": [
-                            "true",
-                            "false"
-                        ],
-                        "type": "string",
-                        "example": "true",
-                        "description": "Filter by metadata_ready status",
-                        "name": "ready",
+                        "type": "boolean",
+                        "description": "Count eligible rows without deleting",
+                        "name": "dry_run",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of sukuk metadata with activities",
+                        "description": "Prune result per table",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SukukMetadataListResponse"
+                            "$ref": "#/definitions/services.RetentionResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -781,9 +1137,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create new sukuk with onchain and offchain metadata",
+            }
+        },
+        "/admin/payment-tokens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get all registered ERC-20 payment tokens with their symbol and decimals",
                 "consumes": [
                     "application/json"
                 ],
@@ -791,29 +1154,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
-                ],
-                "summary": "Create sukuk metadata",
-                "parameters": [
-                    {
-                        "description": "Sukuk metadata",
-                        "name": "sukuk",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataCreateRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List payment tokens",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "List of payment tokens",
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PaymentToken"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -822,7 +1177,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -831,11 +1186,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sukuk-metadata/sync": {
+            },
             "post": {
-                "description": "Manually sync metadata for a specific sukuk from indexer",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register an ERC-20 payment token. Symbol, name and decimals are read from the contract via RPC when auto_fetch is true or when symbol/decimals are omitted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -843,28 +1201,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "Trigger sukuk metadata sync",
+                "summary": "Register payment token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Token ID",
-                        "name": "tokenId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Contract Address",
-                        "name": "contractAddress",
-                        "in": "query",
-                        "required": true
+                        "description": "Payment token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentTokenCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Registered payment token",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaymentToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -872,8 +1231,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -881,41 +1240,26 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Payment token already registered",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/sukuk-metadata/tables": {
-            "get": {
-                "description": "Get all available sukuk creation tables from the indexer",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sukuk-metadata"
-                ],
-                "summary": "List sukuk creation tables",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "502": {
+                        "description": "Failed to read token metadata from RPC",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -926,9 +1270,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sukuk-metadata/{id}": {
+        "/admin/payment-tokens/{address}": {
             "get": {
-                "description": "Get a single sukuk metadata by ID with latest 10 blockchain activities",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a registered payment token by its contract address",
                 "consumes": [
                     "application/json"
                 ],
@@ -936,27 +1285,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "Get sukuk metadata by ID",
+                "summary": "Get payment token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Sukuk metadata ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Token contract address",
+                        "name": "address",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sukuk metadata with activities",
+                        "description": "Payment token",
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataListResponse"
+                            "$ref": "#/definitions/models.PaymentToken"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -965,7 +1323,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sukuk metadata not found",
+                        "description": "Payment token not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -985,7 +1343,12 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update existing sukuk metadata with offchain business information like tenor, imbal hasil, kuota nasional, etc. All fields are optional for partial updates. Onchain data (contract address, transaction hash, etc.) is preserved.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update the symbol, name or decimals of a registered payment token",
                 "consumes": [
                     "application/json"
                 ],
@@ -993,37 +1356,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "Update sukuk metadata with offchain business data",
+                "summary": "Update payment token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 36,
-                        "description": "Sukuk metadata ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Token contract address",
+                        "name": "address",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Offchain metadata to update",
-                        "name": "sukuk",
+                        "description": "Fields to update",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataUpdateRequest"
+                            "$ref": "#/definitions/models.PaymentTokenUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated sukuk metadata with both onchain and offchain data",
+                        "description": "Updated payment token",
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                            "$ref": "#/definitions/models.PaymentToken"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or ID format",
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1032,7 +1403,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sukuk metadata not found",
+                        "description": "Payment token not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1041,7 +1412,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to update sukuk metadata",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1050,11 +1421,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sukuk-metadata/{id}/ready": {
-            "put": {
-                "description": "Mark sukuk metadata as ready for public display. Only sukuk with metadata_ready=true will appear in filtered API responses. Use this after adding all required offchain metadata.",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove a payment token. Amounts in this token fall back to 18 decimals afterwards.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1062,28 +1436,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "Mark sukuk metadata as ready",
+                "summary": "Delete payment token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 36,
-                        "description": "Sukuk metadata ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Token contract address",
+                        "name": "address",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sukuk metadata marked as ready",
+                        "description": "Payment token deleted",
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                            "$ref": "#/definitions/handlers.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID format",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1092,7 +1465,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sukuk metadata not found",
+                        "description": "Payment token not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1101,7 +1474,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to update sukuk metadata",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1112,9 +1485,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/sukuk-metadata/{id}/unready": {
-            "put": {
-                "description": "Mark sukuk metadata as unready (metadata_ready=false). This removes it from public API responses filtered by ready=true. Useful for taking sukuk offline for maintenance or updates.",
+        "/admin/payouts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the issuer's payout instructions for approved redemptions, newest first, filtered by status and sukuk",
                 "consumes": [
                     "application/json"
                 ],
@@ -1122,28 +1500,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sukuk-metadata"
+                    "admin"
                 ],
-                "summary": "Mark sukuk metadata as unready",
+                "summary": "List payout instructions",
                 "parameters": [
                     {
+                        "enum": [
+                            "pending",
+                            "executed",
+                            "confirmed"
+                        ],
+                        "type": "string",
+                        "description": "Payout status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
                         "type": "integer",
-                        "example": 36,
-                        "description": "Sukuk metadata ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 50,
+                        "description": "Number of instructions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of instructions to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sukuk metadata marked as unready",
+                        "description": "Payout instructions",
                         "schema": {
-                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                            "$ref": "#/definitions/models.PayoutInstructionListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid status or sukuk address",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1151,8 +1555,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Sukuk metadata not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1161,7 +1565,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Failed to update sukuk metadata",
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1170,11 +1574,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sukuk/{sukukAddress}/snapshots": {
-            "get": {
-                "description": "Get balance snapshots for a sukuk token used for yield distribution calculations",
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Record the payout of a redemption approved onchain, in a payment token or by bank transfer. The amount defaults to the approved amount and the payment token to the redemption's unless bank_reference is set. The instruction starts pending; a redemption has at most one instruction",
                 "consumes": [
                     "application/json"
                 ],
@@ -1182,40 +1589,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "snapshots"
+                    "admin"
                 ],
-                "summary": "Get sukuk snapshots",
+                "summary": "Create payout instruction",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"0x71D7C963E607eeDAfAA7Ef8f8c92bBb878090650\"",
-                        "description": "Sukuk contract address",
-                        "name": "sukukAddress",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of snapshots to return (default: 10, max: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by specific snapshot ID",
-                        "name": "snapshot_id",
-                        "in": "query"
+                        "description": "Payout instruction",
+                        "name": "instruction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutInstructionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Sukuk snapshots",
+                    "201": {
+                        "description": "Pending instruction",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SnapshotsResponse"
+                            "$ref": "#/definitions/models.PayoutInstruction"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1224,7 +1629,25 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sukuk not found",
+                        "description": "Redemption request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Redemption already has a payout instruction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Redemption not approved",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1244,9 +1667,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/transaction-history/{address}": {
-            "get": {
-                "description": "Get all blockchain activities (purchases and redemptions) for a specific user address",
+        "/admin/payouts/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Record the tx hash, bank reference or notes of a payout, and move it from pending to executed once paid, then to confirmed once received. Executing requires a tx_hash or an uploaded proof, which can't be changed afterwards; a confirmed instruction is final",
                 "consumes": [
                     "application/json"
                 ],
@@ -1254,36 +1682,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction-history"
+                    "admin"
                 ],
-                "summary": "Get transaction history by address",
+                "summary": "Update payout instruction",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
-                        "name": "address",
+                        "type": "integer",
+                        "description": "Payout instruction ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "example": 20,
-                        "description": "Number of activities to retrieve",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Changes",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutInstructionUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction history",
+                        "description": "Updated instruction",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RiwayatResponse"
+                            "$ref": "#/definitions/models.PayoutInstruction"
                         }
                     },
                     "400": {
-                        "description": "Invalid address or limit",
+                        "description": "Invalid ID, payload or tx hash",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1291,59 +1719,35 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/transactions/{address}": {
-            "get": {
-                "description": "Get complete transaction history including purchases, redemptions, and yield claims",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "transactions"
-                ],
-                "summary": "Get transaction history",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "maximum": 200,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Number of transactions to return",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Transaction history",
+                    "404": {
+                        "description": "Payout instruction not found",
                         "schema": {
-                            "$ref": "#/definitions/models.TransactionHistoryResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid address or parameters",
+                    "409": {
+                        "description": "Transition not allowed, instruction confirmed or evidence already recorded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Payout has no tx hash or proof",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1363,38 +1767,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/yield-claims/{address}": {
+        "/admin/payouts/{id}/proof": {
             "get": {
-                "description": "Get all available yield claims across user's sukuk holdings",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Download the proof attached to a payout instruction",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "portfolio"
+                    "admin"
                 ],
-                "summary": "Get available yield claims",
+                "summary": "Download payout proof",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
-                        "description": "User wallet address",
-                        "name": "address",
+                        "type": "integer",
+                        "description": "Payout instruction ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Available yield claims",
+                        "description": "Proof file",
                         "schema": {
-                            "$ref": "#/definitions/models.YieldClaimsResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid address",
+                        "description": "Invalid ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1402,21 +1807,140 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Payout instruction or proof not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Attach a proof of payout, a PDF, PNG or JPEG of up to 10MB such as a bank transfer receipt, which lets the payout be executed without a tx hash. A pending instruction's proof may be replaced. Proofs are only downloadable by admins",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Upload payout proof",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Payout instruction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Proof file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Instruction with its proof",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutInstruction"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Payout instruction not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Instruction confirmed or proof already recorded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "/yield-distributions/{sukuk_address}": {
+        "/admin/reconciliation/{sukuk_address}": {
             "get": {
-                "description": "Get yield distribution history for a specific sukuk",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "For one sukuk, compare counts and summed amounts of purchases and redemption requests stored locally with the indexer, listing events missing on either side and amount mismatches (matched on tx_hash + log_index, at most 500 entries per list with a total count). fix=missing_investments first backfills purchases missing locally, in a transaction",
                 "consumes": [
                     "application/json"
                 ],
@@ -1424,9 +1948,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "portfolio"
+                    "admin"
                 ],
-                "summary": "Get yield distributions",
+                "summary": "Reconcile stored events with the indexer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1436,23 +1960,33 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Number of distributions to return",
-                        "name": "limit",
+                        "enum": [
+                            "missing_investments"
+                        ],
+                        "type": "string",
+                        "description": "Backfill straightforward gaps before reconciling",
+                        "name": "fix",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Yield distributions",
+                        "description": "Reconciliation report",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ReconciliationReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid parameters",
+                        "description": "Invalid address or fix",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1471,991 +2005,10347 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.AllSnapshotsResponse": {
-            "type": "object",
-            "properties": {
-                "snapshots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SnapshotEvent"
+        },
+        "/admin/referrals": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a marketing referral code owned by an address. Codes are 3-32 letters, digits, '-' or '_' and match case-insensitively (they are stored uppercased).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create referral code",
+                "parameters": [
+                    {
+                        "description": "Referral code",
+                        "name": "referral",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created referral",
+                        "schema": {
+                            "$ref": "#/definitions/models.Referral"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid code or owner address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Referral code already exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
-                },
-                "total_count": {
-                    "type": "integer"
                 }
             }
         },
-        "handlers.OwnedSukukResponse": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "sukuk": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SukukMetadataListResponse"
+        "/admin/reorgs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the chain reorgs the reorg reconciler detected, most recent first. An incident lists the transactions of one indexer event type that moved to its _reorg table and left the canonical one, and the derived rows (stored events and ledger entries) built from them, which were marked orphaned instead of deleted. Orphaned rows are left out of ledger balances and reconciliation, and are confirmed again if their transaction returns to the canonical table",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List reorg incidents",
+                "parameters": [
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of incidents to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of incidents to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reorg incidents",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReorgIncidentListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
-                },
-                "total_count": {
-                    "type": "integer"
+                }
+            }
+        },
+        "/admin/routes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Every route in the server's route table with its handler, auth level (public, optional API key, admin API key or webhook signature), rate limit class and whether it is exempt from api.read_only. Routes of optional features that are off are listed with enabled false",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List API routes",
+                "responses": {
+                    "200": {
+                        "description": "Route table",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.RouteInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Every registered runtime setting with its type, description and stored value. Settings without a value use their default, normally from configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List runtime settings",
+                "responses": {
+                    "200": {
+                        "description": "Runtime settings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.SettingValue"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set runtime settings by key; an empty value removes one so its default applies again. Unknown keys and values that don't parse as the setting's type are rejected and nothing is changed. Changes apply to this instance immediately and to other instances within SETTINGS_REFRESH_INTERVAL",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update runtime settings",
+                "parameters": [
+                    {
+                        "description": "Value per setting key",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SettingsUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Runtime settings after the change",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/services.SettingValue"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown setting or invalid value",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/conflicts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the conflicts the metadata sync raised, most recently updated first. The sync only overwrites sukuk_title, sukuk_code, jatuh_tempo and kuota_nasional while they still hold the value it last synced; when an admin has edited one of them and the chain value differs, the admin's value is kept and a conflict records both values until it is resolved. Times are UTC RFC 3339 and amounts are decimals in token units",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List sukuk metadata sync conflicts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "resolved",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "open",
+                        "description": "Conflict status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of conflicts to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of conflicts to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sync conflicts",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataConflictListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/conflicts/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Settle an open conflict. Choosing chain writes the chain value into the field, bumping the sukuk's version, and the sync keeps the field up to date again from then on. Choosing manual keeps the admin's current value; the same chain value isn't raised again, but a later different one is",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resolve a sukuk metadata sync conflict",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Conflict ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Value to keep",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukConflictResolveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resolved conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataConflict"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Conflict not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict already resolved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/validation-report": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check every stored sukuk against the date rules create and update enforce: purchase_period_order (periode_pembelian starts before it ends), first_coupon_after_purchase_period, maturity_after_first_coupon and coupon_day_matches_cadence (tanggal_bayar_kupon pays as often as penerimaan_kupon). Records saved before the rules are listed with their errors but still served. Missing or unreadable dates are warnings, as the rules depending on them can't be checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get sukuk metadata date validation report",
+                "responses": {
+                    "200": {
+                        "description": "Sukuk with date violations",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDateValidationReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/distribution-preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Split total_amount of a payment token across the current holders of a sukuk (latest non-zero balances from the indexer) in proportion to their balances, rounding each share down. Reports the rounding dust left over, min/max/median entitlement, and flags holders entitled to less than the minimum (YIELD_MIN_ENTITLEMENT unless min_entitlement is given). Nothing is written",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preview a yield distribution",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Planned distribution",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DistributionPreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entitlements per holder",
+                        "schema": {
+                            "$ref": "#/definitions/models.DistributionPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Holder balances changed during the preview",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Sukuk has no holders",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List all versions of the documents attached to a sukuk, including deactivated ones, grouped by type with the newest version first. Each version carries its download_count, the number of signed download links handed out for it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List sukuk document versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document versions by type",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDocumentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload a document for a sukuk. Prospectuses and sharia certificates must be PDFs; fact sheets may also be PNG or JPEG images. A document of a type the sukuk already has becomes its next version and the previous version is deactivated",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Upload sukuk document",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "prospectus",
+                            "fact_sheet",
+                            "sharia_certificate"
+                        ],
+                        "type": "string",
+                        "description": "Document type",
+                        "name": "type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Document file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stored document version",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, type or file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/documents/{document_id}/deactivate": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Withdraw a document version so it is no longer served publicly. The record and file are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Deactivate sukuk document",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Document ID",
+                        "name": "document_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deactivated document",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDocument"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata or document not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Apply the same body as PUT /sukuk-metadata/{id} to the stored record in memory and return the public list item it would produce, with the readiness report PUT /sukuk-metadata/{id}/ready would check: required fields filled in, purchase period in order and before the first coupon before maturity, coupon day matching the coupon cadence, and logo_url resolvable. No version is required and nothing is written. latest_activities is left empty and stats are not looked up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Preview a sukuk metadata update",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Offchain metadata to preview",
+                        "name": "sukuk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Would-be public payload and readiness report",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Status transition not allowed; body lists allowed_statuses",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the translated title, description and term labels of a sukuk metadata record per locale",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get sukuk metadata translations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Translations per locale",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataTranslationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/translations/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set translated values for title, description and term labels in a locale. Fields left out are unchanged; an empty value removes the translation so the field falls back to the base record. The default locale (id) is the base record itself and is edited through PUT /sukuk-metadata/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set sukuk metadata translations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Locale",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translated values keyed by field",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataTranslationsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Translations per locale",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataTranslationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, locale or field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sukuk-metadata/{id}/vault": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sum the indexed yield_deposit and yield distribution events of a sukuk per payment token and report the implied vault balance (deposited minus distributed) with the current vault address from the latest vault_update. The next coupon is expected to repeat the latest distribution and is dated by the coupon calendar when one can be estimated; funding_coverage is the balance as a percentage of it and low_funding is set below 100%. Coverage is omitted when the sukuk has no distribution yet or its calendar has no coupon left",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get sukuk yield vault balance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk Metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vault balance",
+                        "schema": {
+                            "$ref": "#/definitions/models.VaultBalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sync/health": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Events per cycle, cursor, indexer head and raised anomaly flags of the metadata sync and the activity stream. Flags are stall (no new events for SYNC_STALL_THRESHOLD while the indexer advanced), failure_ratio (more than SYNC_FAILURE_RATIO_THRESHOLD percent of a cycle's events failed, or the cycle failed) and cursor_regression (the last processed ID went backwards). Locks lists the instance holding each sync service's lock, only one replica runs a service's cycles at a time; a lock is stale when its holder stopped writing its heartbeat for SYNC_LOCK_STALE_AFTER. Responds 503 while any flag is raised or a lock is stale. Services appear after their first cycle on the instance answering",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get sync health",
+                "responses": {
+                    "200": {
+                        "description": "No anomaly flags raised or stale locks",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncHealthResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "At least one anomaly flag raised or lock stale",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncHealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/force-sync": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Manually trigger a sync cycle. Runs synchronously when few events are pending, otherwise starts a background job and returns 202 with a job ID. While a background job runs, returns 409 with its job ID. Finished jobs can be looked up for an hour.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Force blockchain sync",
+                "responses": {
+                    "200": {
+                        "description": "Sync completed",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ForceSyncResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Sync started in background",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncJobStartedResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Sync already in progress, with the job_id of a running background sync",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/sync-jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the status and result of a background sync started by force-sync",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get sync job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sync job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sync job",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Sync job not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system/sync-status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the current blockchain synchronization status and last processed event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get blockchain sync status",
+                "responses": {
+                    "200": {
+                        "description": "Sync status",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SyncStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/view-as/{address}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the portfolio, yield claims, latest 50 transactions and owned sukuk of a wallet in one payload, as the investor-facing endpoints serve them to the wallet. Each section carries when it was read and whether it came from the response cache; a section the indexer fails to serve carries its error instead of data, and the other sections are still returned. Every call is recorded in the audit log with the caller and the viewed address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "View as investor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "What the wallet sees",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ViewAsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/nonce/{address}": {
+            "get": {
+                "description": "Issue a single-use nonce to a wallet, with the Sign-In with Ethereum style message to sign with personal_sign and send to /auth/verify before the nonce expires",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get wallet sign-in nonce",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nonce and message to sign",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletAuthChallenge"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Wallet authentication not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify": {
+            "post": {
+                "description": "Check a message from /auth/nonce signed by its wallet, consume its nonce and return a short-lived session token for the wallet, sent as \"Authorization: Bearer \u003ctoken\u003e\". Each nonce signs in once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify wallet sign-in",
+                "parameters": [
+                    {
+                        "description": "Signed sign-in message",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletAuthVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session token",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletAuthSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Signature not made by the address, message expired, or nonce already used",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Wallet authentication not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/certificates/verify/{code}": {
+            "get": {
+                "description": "Look up a certificate by the verification code printed on it. The response holds the values as issued, not the investor's current balance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Verify investment certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "CERT-MFRGGZDFMZTWQ2LK",
+                        "description": "Verification code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Certificate as issued",
+                        "schema": {
+                            "$ref": "#/definitions/models.CertificateVerification"
+                        }
+                    },
+                    "404": {
+                        "description": "Certificate not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/indexer": {
+            "get": {
+                "description": "Test connection to indexer database and query sample data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Debug indexer connection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sukuk address to test",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Debug information",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/indexer-tables": {
+            "get": {
+                "description": "Get all discovered hash-prefixed indexer tables with metadata and row counts. Tables pinned by an override are flagged overridden, and latest_tables reflects the overrides",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "List indexer tables",
+                "responses": {
+                    "200": {
+                        "description": "Discovered indexer tables",
+                        "schema": {
+                            "$ref": "#/definitions/models.IndexerTablesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/indexer-tables/prefix/{hash_prefix}": {
+            "get": {
+                "description": "Get all tables that share the same hash prefix (deployment)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "Get tables by hash prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"f243\"",
+                        "description": "Hash prefix",
+                        "name": "hash_prefix",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tables with hash prefix",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid hash prefix",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/indexer-tables/validate": {
+            "get": {
+                "description": "Validate that all discovered indexer tables have expected columns",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "Validate indexer tables",
+                "responses": {
+                    "200": {
+                        "description": "Validation results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/indexer-tables/{table_name}": {
+            "get": {
+                "description": "Get detailed information about a specific indexer table including column structure",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "debug"
+                ],
+                "summary": "Get table details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"f243__sukuk_purchase\"",
+                        "description": "Table name",
+                        "name": "table_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Table details",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid table name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/generate-scenario": {
+            "post": {
+                "description": "Development only, enabled by DEV_EVENT_INJECTOR. Gives a wallet three purchases, a snapshot and two yield distributions in each sukuk, plus an approved redemption and a claimed distribution in the first one, spread over the last 30 days. The wallet defaults to a new random address and the sukuk to the first two ready sukuk metadata",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "Generate a demo wallet scenario",
+                "parameters": [
+                    {
+                        "description": "Wallet and sukuk",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/services.ScenarioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Wallet, sukuk and rows written",
+                        "schema": {
+                            "$ref": "#/definitions/services.Scenario"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "No ready sukuk metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/inject-event": {
+            "post": {
+                "description": "Development only, enabled by DEV_EVENT_INJECTOR. Writes a sukuk_purchase, redemption_request, redemption_approval, yield_distributed, yield_claim or snapshot_taken event into the synthetic indexer tables, with the columns the indexer uses. Purchases and redemption requests also write the holder_update row for the new balance. Payload fields follow the indexer columns (buyer, user, sukuk_address, payment_token, amount, distribution_id) plus an optional unix timestamp; amounts are raw integers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "Inject a synthetic indexer event",
+                "parameters": [
+                    {
+                        "description": "Event type and payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InjectEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rows written",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InjectEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported event type or invalid payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/files/{token}": {
+            "get": {
+                "description": "Stream the document file of a signed link from GET /sukuk-metadata/{id}/prospectus-link. Range requests are supported",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Download a file through a signed link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Link expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Prospectus downloads not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get overall system health including database and sync status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get system health",
+                "responses": {
+                    "200": {
+                        "description": "System health",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Database unreachable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/investors/{address}/status": {
+            "get": {
+                "description": "Get the KYC status for a wallet address without exposing identity data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "investors"
+                ],
+                "summary": "Get investor KYC status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "KYC status",
+                        "schema": {
+                            "$ref": "#/definitions/models.KYCStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Investor not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List purchase orders created for a wallet address, newest first. Wallets can only list their own orders and may omit the address; the API key lists any address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List purchase orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User wallet address, required with the API key",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Orders",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid wallet token or API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet token is for another address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a fiat on-ramp purchase intent for a sukuk. Wallets can only create orders for their own address; the API key can create them for any address. The investor must have acknowledged the sukuk's risks for its current prospectus (see POST /suitability/{address}). The fiat amount must be within the sukuk's minimum and maximum purchase. The token amount is quoted from it at the sukuk's Rp1 face value and must fit in the sukuk's remaining kuota_nasional capacity, less the tokens of its paid and unexpired orders. The order expires if the payment callback does not arrive in time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create purchase order",
+                "parameters": [
+                    {
+                        "description": "Purchase order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created order",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid wallet token or API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet token is for another address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Risk acknowledgement missing or for an outdated prospectus, with the suitability status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Sukuk not open for purchase, amount out of range or above the remaining capacity after outstanding orders",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a purchase order by ID, including its status and settlement transaction. Wallets only see their own orders; others are reported as not found",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get purchase order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid wallet token or API key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/payment-callback": {
+            "post": {
+                "description": "Called by the fiat partner once a payment clears or fails. The raw body must be signed with HMAC-SHA256 using the webhook secret and sent in the X-Webhook-Signature header. Each payment reference and status is applied once; repeating or replaying a callback is a no-op that returns the order as it is.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Order payment callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sha256=\u003chex HMAC of the body\u003e",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment result",
+                        "name": "callback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrderPaymentCallbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated order",
+                        "schema": {
+                            "$ref": "#/definitions/models.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or payment reference",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid webhook signature",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot change to the reported status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/owned-sukuk/{address}": {
+            "get": {
+                "description": "Get sukuk metadata for sukuk tokens owned by a specific wallet address. Only returns sukuk with metadata_ready=true by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "owned-sukuk"
+                ],
+                "summary": "Get sukuk owned by address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Owned sukuk metadata",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OwnedSukukResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the holdings count, total balance and claimable yield of up to 100 addresses, keyed by address as sent. Invalid addresses get an error entry without failing the rest. Holdings that could not be computed are left out of the totals and listed in warnings, with complete unset. Addresses not summarized within the server's time budget are listed in unprocessed, with truncated set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get portfolio summaries of several addresses",
+                "parameters": [
+                    {
+                        "description": "Addresses to summarize",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PortfolioBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Portfolio summaries",
+                        "schema": {
+                            "$ref": "#/definitions/models.PortfolioBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payload or more than 100 addresses",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/{address}": {
+            "get": {
+                "description": "Get complete portfolio showing all sukuk holdings with current balances and claimable yields. Includes the investor's KYC status when called with an API key. Holdings that can't be computed are left out and listed in warnings, with complete set to false; the request only fails when none can be.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get user portfolio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "idr,usd",
+                        "description": "Comma-separated currencies for summary.display_values, e.g. idr,usd; the rates used are listed in meta",
+                        "name": "fiat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User portfolio with holdings",
+                        "schema": {
+                            "$ref": "#/definitions/models.PortfolioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/{address}/balance-history/{sukuk_address}": {
+            "get": {
+                "description": "Page through the holder_update rows of an address on a sukuk, oldest first, with the signed change from the previous balance. Each change carries the type of the purchase, redemption request or yield claim in the same transaction, or \"transfer\" when there is none (e.g. a plain ERC-20 transfer). Balances are raw values",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get balance history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only changes at or after this unix timestamp in seconds",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only changes at or before this unix timestamp in seconds",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Changes per page",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Balance changes",
+                        "schema": {
+                            "$ref": "#/definitions/models.BalanceHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address, time range or pagination",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/{address}/certificate/{sukuk_address}": {
+            "get": {
+                "description": "Issue a PDF certificate of the address's current balance of a sukuk, with the sukuk code, title and issuer, the balance, its share of the supply, the issue time and a verification code. Every call stores a new certificate with the values printed on it, so it can be verified later regardless of balance changes. The layout comes from CERTIFICATE_LAYOUT_FILE",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Download investment certificate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Certificate PDF; the verification code is also in the X-Certificate-Code header",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Investor holds no balance of this sukuk",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/{address}/tax-report": {
+            "get": {
+                "description": "Aggregate the yield claims of an address within a calendar year (Asia/Jakarta boundaries), grouped by sukuk with per-sukuk and grand totals per payment token. Amounts are raw wei with values humanized by the payment token decimals. Years without claims return an empty report",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get yearly yield tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2024,
+                        "description": "Calendar year, defaults to the current year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Yearly yield income statement",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address, year or format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/preferences/{address}": {
+            "get": {
+                "description": "Get the notification preferences of a wallet, or the defaults (everything enabled, Indonesian, no email) if it has never set any. The email is masked unless called with an API key.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Get notification preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    }
+                ],
+                "description": "Change the given notification preferences of a wallet; omitted fields keep their value. Requires a wallet session token for the address from /auth/verify.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences to change",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferenceUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address or preferences",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired wallet token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet token is for another address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Wallet authentication not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/redemptions": {
+            "get": {
+                "description": "Get redemption requests with their approval status, supports pagination. The status filter and sort apply before limit/offset, and total_count counts every matching redemption",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemptions"
+                ],
+                "summary": "Get all redemptions",
+                "parameters": [
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of redemptions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of redemptions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "requested",
+                            "approved",
+                            "rejected",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "amount"
+                        ],
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of redemptions with status",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedemptionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sort",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/redemptions/stats": {
+            "get": {
+                "description": "Get comprehensive statistics about all redemptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemptions"
+                ],
+                "summary": "Get redemption statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "idr,usd",
+                        "description": "Comma-separated currencies for display_values, e.g. idr,usd; amounts are priced in each sukuk's payment token and the rates used are listed in meta",
+                        "name": "fiat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redemption statistics",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedemptionStatsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/redemptions/sukuk/{sukuk_address}": {
+            "get": {
+                "description": "Get all redemption requests and approvals for a specific sukuk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemptions"
+                ],
+                "summary": "Get sukuk redemptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0x02ba44871BD555d6ebD541e2820796F9b88cBF75\"",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sukuk's redemptions",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedemptionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sukuk address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/redemptions/user/{address}": {
+            "get": {
+                "description": "Get all redemption requests and approvals for a specific user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemptions"
+                ],
+                "summary": "Get user redemptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User's redemptions",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedemptionListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/redemptions/{request_id}": {
+            "get": {
+                "description": "Get detailed information about a specific redemption request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemptions"
+                ],
+                "summary": "Get redemption by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Redemption request ID",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Redemption details",
+                        "schema": {
+                            "$ref": "#/definitions/models.RedemptionRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Redemption not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/referrals/claim": {
+            "post": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    }
+                ],
+                "description": "Bind a referral code to the wallet of the session token before purchasing. A wallet can bind one code, once; purchases indexed from the moment of binding are attributed to it, earlier ones never are.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "referrals"
+                ],
+                "summary": "Claim referral code",
+                "parameters": [
+                    {
+                        "description": "Code, and optionally the wallet, which must be the signed-in one",
+                        "name": "claim",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralClaimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Binding",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralBinding"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid code",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Wallet token required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Address is not the signed-in wallet",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Referral code not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Address already has a referral code",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/referrals/{code}/stats": {
+            "get": {
+                "description": "Count the wallets bound to a referral code and the purchases attributed to it, with their summed raw amount. Purchases are attributed by the metadata sync, so recent ones appear after its next cycle.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "referrals"
+                ],
+                "summary": "Get referral stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Referral code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Referral stats",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid referral code",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Referral code not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/snapshots": {
+            "get": {
+                "description": "Get balance snapshots for all sukuk tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "snapshots"
+                ],
+                "summary": "Get all snapshots",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of snapshots to return (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All snapshots",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AllSnapshotsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/activities": {
+            "get": {
+                "description": "Server-Sent Events stream of purchases and redemption requests as they are indexed. Each message has event type \"activity\", an incrementing id and a JSON ActivityEvent payload. Reconnecting clients send Last-Event-ID (or last_event_id) to replay recent events they missed; ids restart when the server restarts.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "activities"
+                ],
+                "summary": "Stream new activities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only stream activities for this sukuk contract",
+                        "name": "sukuk_address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stream activities for this user address",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "purchase",
+                            "redemption_request",
+                            "yield_claim"
+                        ],
+                        "type": "string",
+                        "description": "Only stream this activity type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event id (alternative to the Last-Event-ID header)",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of activity events",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Last-Event-ID or activity type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/suitability/{address}": {
+            "get": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    }
+                ],
+                "description": "With sukuk_metadata_id, get whether the investor acknowledged the risks of that sukuk for its current prospectus: acknowledged, stale (a newer prospectus version was published since) or missing, with the prospectus to acknowledge. Without it, list the status of every sukuk the investor has acknowledged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suitability"
+                ],
+                "summary": "Get risk acknowledgement status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "sukuk_metadata_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statuses; a single models.SuitabilityStatus with sukuk_metadata_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuitabilityStatusesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address or sukuk ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or expired wallet token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet token is for another address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "WalletAuth": []
+                    }
+                ],
+                "description": "Record that the investor acknowledged the product risk of a sukuk as described in its current prospectus, which orders for the sukuk require. Send document_id of the current prospectus (omit it for a sukuk without one) and either a consent_token from the app's checkbox or a personal_sign signature of the message \"I acknowledge the risks of this Sukuk as described in its prospectus\\nAddress: \u003clowercase address\u003e\\nSukuk: \u003csukuk_metadata_id\u003e\\nProspectus version: \u003cversion\u003e\\nIssued at: \u003cRFC3339 UTC time\u003e\", issued within the last 10 minutes. Both need the wallet's session token for the address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suitability"
+                ],
+                "summary": "Acknowledge sukuk risks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Investor wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Acknowledgement",
+                        "name": "acknowledgement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SuitabilityAcknowledgementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Acknowledged",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuitabilityStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address, payload or message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid wallet token, or signature not made by the address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet token is for another address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Document is not the current prospectus",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata": {
+            "get": {
+                "description": "Get all sukuk metadata with optional filtering by ready status and latest 10 blockchain activities. Suspended sukuk are left out of ready=true listings unless include_suspended=true, and carry their suspension reason. Title, description and term labels are served in the locale from lang or Accept-Language, falling back to the Indonesian base record per field",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "List sukuk metadata with activities",
+                "parameters": [
+                    {
+                        "enum": [
+                            "true",
+                            "false"
+                        ],
+                        "type": "string",
+                        "example": "true",
+                        "description": "Filter by metadata_ready status",
+                        "name": "ready",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Keep suspended sukuk in ready=true listings",
+                        "name": "include_suspended",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response locale; overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred locales, e.g. en-US,en;q=0.9",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Connected wallet; adds user_balance, user_unclaimed_distribution_count and user_claimable_amount to each item",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Add investor_count, first_purchase_at and last_activity_at to each item",
+                        "name": "include_stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated top-level fields to return, e.g. sukuk_code,sukuk_title,imbal_hasil,logo_url. Latest activities, stats and wallet positions are only looked up when one of their fields is selected",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Latest activities per sukuk, 0 to 50; 0 skips the lookup. Defaults to the sukuk_metadata.activities_limit setting, then ACTIVITY_LATEST_LIMIT",
+                        "name": "activities_limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "imbal_hasil",
+                            "jatuh_tempo",
+                            "newest",
+                            "most_subscribed"
+                        ],
+                        "type": "string",
+                        "description": "Sort key; most_subscribed orders by purchase totals",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort direction; defaults to desc for imbal_hasil, newest and most_subscribed, asc for jatuh_tempo",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated statuses, e.g. active,paused",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Fixed Rate",
+                        "description": "Coupon type, case-insensitive",
+                        "name": "tipe_kupon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2 tahun",
+                        "description": "Minimum tenor; a bare number is in years",
+                        "name": "min_tenor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "18 bulan",
+                        "description": "Maximum tenor; a bare number is in years",
+                        "name": "max_tenor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "jatuh_tempo on or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "maturity_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "jatuh_tempo before (RFC3339 or YYYY-MM-DD)",
+                        "name": "maturity_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "example": 6.25,
+                        "description": "Minimum imbal_hasil in percent",
+                        "name": "min_yield",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response; 304 if the list is unchanged. Ignored with address, and unless activities_limit is 0 or fields leaves out latest_activities",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sukuk metadata with activities",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SukukMetadataListResponse"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Unsupported lang, invalid address, sort, filter or fields",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create new sukuk with onchain and offchain metadata. Dates that are set must be in order: purchase period start before end, first coupon after the purchase period, maturity after the first coupon, and tanggal_bayar_kupon matching the penerimaan_kupon cadence",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Create sukuk metadata",
+                "parameters": [
+                    {
+                        "description": "Sukuk metadata",
+                        "name": "sukuk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown status, or dates out of order; violations names each broken constraint",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/sync": {
+            "post": {
+                "description": "Manually sync metadata for a specific sukuk from indexer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Trigger sukuk metadata sync",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "tokenId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contract Address",
+                        "name": "contractAddress",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SukukMetadataSyncResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Metadata changed during the sync",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/tables": {
+            "get": {
+                "description": "Get all available sukuk creation tables from the indexer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "List sukuk creation tables",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SukukCreationTablesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}": {
+            "get": {
+                "description": "Get a single sukuk metadata by ID with latest 10 blockchain activities, localized like the listing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk metadata by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Response locale; overrides Accept-Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred locales, e.g. en-US,en;q=0.9",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Connected wallet; adds user_balance, user_unclaimed_distribution_count and user_claimable_amount",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Add investor_count, first_purchase_at and last_activity_at",
+                        "name": "include_stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated top-level fields to return, e.g. sukuk_code,sukuk_title,imbal_hasil,logo_url. Latest activities, stats and wallet positions are only looked up when one of their fields is selected",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Latest activities per sukuk, 0 to 50; 0 skips the lookup. Defaults to the sukuk_metadata.activities_limit setting, then ACTIVITY_LATEST_LIMIT",
+                        "name": "activities_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a previous response, the record's version; 304 if the record is unchanged. Ignored when the response carries latest activities, stats or wallet positions",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sukuk metadata with activities",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataListResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid ID format, unsupported lang, invalid address or fields",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update existing sukuk metadata with offchain business information like tenor, imbal hasil, kuota nasional, etc. All fields are optional for partial updates. Dates must stay in order: purchase period start before end, first coupon after the purchase period, maturity after the first coupon, and tanggal_bayar_kupon matching the penerimaan_kupon cadence. Onchain data (contract address, transaction hash, etc.) is preserved. The version read from GET must be sent as If-Match or a version field; a stale version returns 409 with the current record.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Update sukuk metadata with offchain business data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 36,
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The ETag or version field from the latest GET or write response; required unless the body has a version field",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Offchain metadata to update",
+                        "name": "sukuk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated sukuk metadata with both onchain and offchain data",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Version mismatch; body includes the current record",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Status transition not allowed, body lists allowed_statuses; or the edit puts dates out of order, violations names each broken constraint",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version field",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update sukuk metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/availability": {
+            "get": {
+                "description": "Compare the sukuk's kuota_nasional (scaled to wei by the token decimals) with the sum of its indexed purchase events, giving the remaining capacity and percent subscribed, and report whether today falls within periode_pembelian (Asia/Jakarta, last day inclusive). A sukuk without a kuota_nasional is unlimited; purchase_period_open is null when periode_pembelian can't be parsed. Amounts are raw wei",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk Metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "idr,usd",
+                        "description": "Comma-separated currencies for display_values, e.g. idr,usd; sukuk token amounts are priced as IDRX and the rates used are listed in meta",
+                        "name": "fiat",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Availability",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukAvailability"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/coupon-schedule": {
+            "get": {
+                "description": "Lay out every expected coupon from kupon_pertama to jatuh_tempo at the penerimaan_kupon frequency (monthly, quarterly, semiannual or annual) and mark each one paid by the nearest yield distribution within the grace window (coupon_schedule.grace_period setting, 7 days by default), missed once the window passes without one, or upcoming. Distributions matching no coupon are listed under extra_distributions. Without kupon_pertama or a recognizable frequency, estimated is false, coupons is empty and every distribution is extra",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk coupon schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk Metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Coupon schedule",
+                        "schema": {
+                            "$ref": "#/definitions/models.CouponScheduleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/documents": {
+            "get": {
+                "description": "Get the current version of each document attached to a sukuk, such as its prospectus, fact sheet and sharia certificate, grouped by type. The file_url of a prospectus is its prospectus-link endpoint, which hands out a signed download link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk documents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active documents by type",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDocumentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/export/activities": {
+            "get": {
+                "description": "Stream every purchase, redemption request and yield claim of a sukuk as newline-delimited JSON, one event per line, ordered by block number then log index. The export is read in batches and flushed as it goes, so it suits histories of any length; to continue an incremental or interrupted pull, pass the block_number of the last line received as from_block and skip the events of that block already stored, matched on id. Addresses are EIP-55 checksummed and amounts are raw. Errors after the first line end the stream early instead of changing the status",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Export sukuk activity history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk Metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Only export events from this block on",
+                        "name": "from_block",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One line per event",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityExportItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or from_block",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/prospectus-link": {
+            "get": {
+                "description": "Get a signed link to the current prospectus of a sukuk, valid for UPLOAD_LINK_TTL. Prospectus files are only served through these links, not under /uploads. Every link handed out counts as a download of the prospectus version, recorded with the requesting wallet when address is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get a prospectus download link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Requesting wallet address",
+                        "name": "address",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signed link",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukDocumentLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata or prospectus not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Prospectus downloads not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/ready": {
+            "put": {
+                "description": "Mark sukuk metadata as ready for public display. Only sukuk with metadata_ready=true will appear in filtered API responses. The record must pass the readiness checklist (required fields filled in, purchase period in order and before the first coupon before maturity, coupon day matching the coupon cadence, logo_url resolvable); otherwise 422 lists the failing checks. Use POST /admin/sukuk-metadata/{id}/preview to check an edit first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Mark sukuk metadata as ready",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 36,
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sukuk metadata marked as ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Readiness checks failed; body lists failures",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update sukuk metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/snapshots": {
+            "get": {
+                "description": "Get SnapshotTaken events for a sukuk ordered by snapshot ID (newest first). Use latest=true to return only the most recent snapshot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk snapshot history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of snapshots to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of snapshots to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return only the most recent snapshot",
+                        "name": "latest",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest snapshot (latest=true)",
+                        "schema": {
+                            "$ref": "#/definitions/models.SnapshotEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata or snapshot not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/timeseries": {
+            "get": {
+                "description": "Aggregate purchases and approved redemptions for a sukuk into day or week buckets. Amounts are wei strings with humanized counterparts; empty buckets are zero-filled. At most 400 buckets per request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Get sukuk supply and investment time-series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "day",
+                            "week"
+                        ],
+                        "type": "string",
+                        "default": "day",
+                        "description": "Bucket width",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD), defaults to 30 buckets before 'to'",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Time-series buckets",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukTimeSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters or range exceeds 400 buckets",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk-metadata/{id}/unready": {
+            "put": {
+                "description": "Mark sukuk metadata as unready (metadata_ready=false). This removes it from public API responses filtered by ready=true. Useful for taking sukuk offline for maintenance or updates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sukuk-metadata"
+                ],
+                "summary": "Mark sukuk metadata as unready",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 36,
+                        "description": "Sukuk metadata ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sukuk metadata marked as unready",
+                        "schema": {
+                            "$ref": "#/definitions/models.SukukMetadataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk metadata not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update sukuk metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sukuk/{sukukAddress}/snapshots": {
+            "get": {
+                "description": "Get balance snapshots for a sukuk token used for yield distribution calculations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "snapshots"
+                ],
+                "summary": "Get sukuk snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0x71D7C963E607eeDAfAA7Ef8f8c92bBb878090650\"",
+                        "description": "Sukuk contract address",
+                        "name": "sukukAddress",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of snapshots to return (default: 10, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by specific snapshot ID",
+                        "name": "snapshot_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sukuk snapshots",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SnapshotsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Sukuk not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction-history/{address}": {
+            "get": {
+                "description": "Get all blockchain activities (purchases, redemptions and transfers between wallets) for a specific user address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction-history"
+                ],
+                "summary": "Get transaction history by address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "example": 20,
+                        "description": "Number of activities to retrieve",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction history",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RiwayatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address or limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/transactions/{address}": {
+            "get": {
+                "description": "Get complete transaction history including purchases, redemptions, yield claims and transfers between wallets. A transfer is a balance change no purchase, redemption request or yield claim in the same transaction explains, e.g. a plain ERC-20 transfer; details carry the counterparty when exactly one other wallet moved the opposite way. Includes the investor's KYC status when called with an API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transactions"
+                ],
+                "summary": "Get transaction history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 200,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of transactions to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "purchase",
+                            "redemption_request",
+                            "yield_claim",
+                            "transfer_in",
+                            "transfer_out"
+                        ],
+                        "type": "string",
+                        "description": "Only return this activity type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction history",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransactionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address, parameters or activity type",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/unsubscribe": {
+            "get": {
+                "description": "Show what the signed token from a notification email unsubscribes and a button that POSTs it back. Nothing changes until it is confirmed, as link scanners and prefetchers follow GET links. Browsers get an HTML page; other clients get JSON.",
+                "produces": [
+                    "text/html",
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Confirm unsubscribing from notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token from the email",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnsubscribeConfirmation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Unsubscribe links not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Turn off one kind of notification (yield, redemption, digest) or all of them for the wallet in a signed token from a notification email. This is the RFC 8058 one-click endpoint for List-Unsubscribe-Post, and the target of the GET confirmation page's button. No other authentication is needed; links expire after EMAIL_UNSUBSCRIBE_TOKEN_TTL.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html",
+                    "application/json"
+                ],
+                "tags": [
+                    "preferences"
+                ],
+                "summary": "Unsubscribe from notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unsubscribe token from the email",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unsubscribed",
+                        "schema": {
+                            "$ref": "#/definitions/models.UnsubscribeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Unsubscribe links not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/yield-claims/{address}": {
+            "get": {
+                "description": "Get all available yield claims across user's sukuk holdings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get available yield claims",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Available yield claims",
+                        "schema": {
+                            "$ref": "#/definitions/models.YieldClaimsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/yield-claims/{address}/{sukuk_address}/claim-data": {
+            "get": {
+                "description": "Select every distribution of the sukuk the address can still claim on its current balance and return the ABI-encoded claimYield(uint256[]) calldata, the sukuk contract to send it to and a summary of what it pays out. Returns 409 with the reason when nothing is claimable, so wallets never submit a claim that would revert",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Build yield claim transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9\"",
+                        "description": "User wallet address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claim transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.YieldClaimDataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Nothing to claim",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/yield-distributions/{sukuk_address}": {
+            "get": {
+                "description": "Get yield distribution history for a specific sukuk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get yield distributions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sukuk contract address",
+                        "name": "sukuk_address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of distributions to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Yield distributions with amounts formatted in the payment token's decimals",
+                        "schema": {
+                            "$ref": "#/definitions/models.YieldDistributionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AllSnapshotsResponse": {
+            "type": "object",
+            "properties": {
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SnapshotEvent"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ForceSyncResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "last_processed_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.HealthData": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "type": "string"
+                },
+                "sukuk_metadata": {
+                    "description": "Number of sukuk metadata records",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handlers.HealthData"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"healthy\" or \"unhealthy\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.InjectEventRequest": {
+            "type": "object",
+            "required": [
+                "event_type",
+                "payload"
+            ],
+            "properties": {
+                "event_type": {
+                    "type": "string",
+                    "example": "sukuk_purchase"
+                },
+                "payload": {
+                    "type": "object"
+                }
+            }
+        },
+        "handlers.InjectEventResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.InjectedEvent"
+                    }
+                }
+            }
+        },
+        "handlers.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.OwnedSukukResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "sukuk": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukMetadataListResponse"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
                 }
             }
         },
         "handlers.RiwayatResponse": {
             "type": "object",
             "properties": {
-                "activities": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivityEvent"
+                    }
+                },
+                "address": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RouteInfo": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "description": "\"public\", \"optional\", \"admin\" or \"webhook\"",
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Mounted; optional features leave theirs off",
+                    "type": "boolean"
+                },
+                "handler": {
+                    "description": "Handler function, e.g. handlers.ListSettings",
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "\"api\" or \"none\"",
+                    "type": "string"
+                },
+                "read_only_exempt": {
+                    "description": "Mutations are still served under api.read_only",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.SnapshotHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "contract_address": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SnapshotEvent"
+                    }
+                },
+                "sukuk_id": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SnapshotsResponse": {
+            "type": "object",
+            "properties": {
+                "snapshots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SnapshotEvent"
+                    }
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SukukCreationTablesResponse": {
+            "type": "object",
+            "properties": {
+                "latest_table": {
+                    "type": "string"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SukukMetadataSyncResponse": {
+            "type": "object",
+            "properties": {
+                "contract_address": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SyncHealthResponse": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "type": "boolean"
+                },
+                "locks": {
+                    "description": "Instance holding each sync service's lock",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SyncLockStatus"
+                    }
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SyncServiceHealth"
+                    }
+                }
+            }
+        },
+        "handlers.SyncJob": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "result": {
+                    "$ref": "#/definitions/services.SyncResult"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"running\", \"completed\", \"failed\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SyncJobStartedResponse": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SyncStatus": {
+            "type": "object",
+            "properties": {
+                "last_processed_event_id": {
+                    "type": "string"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "sync_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SyncStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handlers.SyncStatus"
+                }
+            }
+        },
+        "handlers.ViewAsOwnedSukuk": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Served from the response cache, up to its TTL old",
+                    "type": "boolean"
+                },
+                "data": {
+                    "$ref": "#/definitions/handlers.OwnedSukukResponse"
+                },
+                "error": {
+                    "description": "Why the section could not be read; its data is then omitted",
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "description": "When the section was read, or served from the cache",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ViewAsPortfolio": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Served from the response cache, up to its TTL old",
+                    "type": "boolean"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.PortfolioResponse"
+                },
+                "error": {
+                    "description": "Why the section could not be read; its data is then omitted",
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "description": "When the section was read, or served from the cache",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ViewAsResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "owned_sukuk": {
+                    "$ref": "#/definitions/handlers.ViewAsOwnedSukuk"
+                },
+                "portfolio": {
+                    "$ref": "#/definitions/handlers.ViewAsPortfolio"
+                },
+                "transactions": {
+                    "$ref": "#/definitions/handlers.ViewAsTransactions"
+                },
+                "yield_claims": {
+                    "$ref": "#/definitions/handlers.ViewAsYieldClaims"
+                }
+            }
+        },
+        "handlers.ViewAsTransactions": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Served from the response cache, up to its TTL old",
+                    "type": "boolean"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.TransactionHistoryResponse"
+                },
+                "error": {
+                    "description": "Why the section could not be read; its data is then omitted",
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "description": "When the section was read, or served from the cache",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ViewAsYieldClaims": {
+            "type": "object",
+            "properties": {
+                "cached": {
+                    "description": "Served from the response cache, up to its TTL old",
+                    "type": "boolean"
+                },
+                "data": {
+                    "$ref": "#/definitions/models.YieldClaimsResponse"
+                },
+                "error": {
+                    "description": "Why the section could not be read; its data is then omitted",
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "description": "When the section was read, or served from the cache",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyUsage": {
+            "type": "object",
+            "properties": {
+                "bytes_out": {
+                    "description": "Response body bytes",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Responses with status 400 or above",
+                    "type": "integer"
+                },
+                "hour": {
+                    "description": "Start of the hour, UTC",
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIKeyUsageReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIKeyUsage"
+                    }
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/models.APIKeyUsageTotals"
+                }
+            }
+        },
+        "models.APIKeyUsageTotals": {
+            "type": "object",
+            "properties": {
+                "bytes_out": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityEvent": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Buyer or User address",
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "Token amount",
+                    "type": "string"
+                },
+                "counterparty": {
+                    "description": "Other wallet of a transfer, when known",
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "description": "Sukuk contract address",
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "description": "Sukuk symbol/code (e.g., \"SITI\")",
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "description": "Sukuk name/title",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Event timestamp",
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "description": "Transaction hash",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "\"purchase\" or \"redemption_request\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ActivityType"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.ActivityExportItem": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Buyer or user, EIP-55 checksummed",
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "Raw amount",
+                    "type": "string"
+                },
+                "block_number": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Indexer event id, \"\u003ctx_hash\u003e-\u003clog_index\u003e\"",
+                    "type": "string"
+                },
+                "log_index": {
+                    "type": "integer"
+                },
+                "payment_token": {
+                    "description": "Token the amount is denominated in; empty for claims of unindexed distributions",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ActivityType"
+                }
+            }
+        },
+        "models.ActivityFeedItem": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Buyer or user, EIP-55 checksummed",
+                    "type": "string"
+                },
+                "amount": {
+                    "description": "Raw amount",
+                    "type": "string"
+                },
+                "amount_formatted": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                },
+                "block_number": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Indexer event id, \"\u003ctx_hash\u003e-\u003clog_index\u003e\"",
+                    "type": "string"
+                },
+                "payment_token": {
+                    "description": "Token the amount is denominated in",
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "description": "EIP-55 checksummed",
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ActivityType"
+                }
+            }
+        },
+        "models.ActivityFeedResponse": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivityFeedItem"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Pass as ?cursor= for the next page; empty on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityType": {
+            "type": "string",
+            "enum": [
+                "purchase",
+                "redemption_request",
+                "yield_claim",
+                "transfer_in",
+                "transfer_out"
+            ],
+            "x-enum-varnames": [
+                "ActivityTypePurchase",
+                "ActivityTypeRedemptionRequest",
+                "ActivityTypeYieldClaim",
+                "ActivityTypeTransferIn",
+                "ActivityTypeTransferOut"
+            ]
+        },
+        "models.BalanceChange": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "delta": {
+                    "description": "Signed, e.g. \"-500\" when tokens left the wallet",
+                    "type": "string"
+                },
+                "event_type": {
+                    "description": "Activity type of the event in the same tx, or \"transfer\"",
+                    "type": "string"
+                },
+                "new_balance": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalanceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BalanceChange"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "total_count": {
+                    "description": "Changes matching the filters across all pages",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Certificate": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "Verification code printed on the document",
+                    "type": "string"
+                },
+                "investor_address": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "issuer_address": {
+                    "description": "Sukuk owner",
+                    "type": "string"
+                },
+                "share_percentage": {
+                    "description": "Balance as a percentage of the supply, e.g. 2.50; empty when the supply was unknown",
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                },
+                "supply_source": {
+                    "description": "Where TotalSupply was read from",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SupplySource"
+                        }
+                    ]
+                },
+                "total_supply": {
+                    "description": "0 when the supply was unknown",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CertificateVerification": {
+            "type": "object",
+            "properties": {
+                "certificate": {
+                    "description": "The values as issued, not the current balance",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Certificate"
+                        }
+                    ]
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CohortAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "cohorts": {
+                    "description": "One per month from From to To, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvestorCohort"
+                    }
+                },
+                "from": {
+                    "description": "First cohort month, YYYY-MM",
+                    "type": "string"
+                },
+                "metric": {
+                    "$ref": "#/definitions/models.CohortMetric"
+                },
+                "timezone": {
+                    "description": "Timezone months are taken in",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Last cohort and activity month, YYYY-MM",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CohortMetric": {
+            "type": "string",
+            "enum": [
+                "repeat_purchase",
+                "retained_balance"
+            ],
+            "x-enum-comments": {
+                "CohortMetricRepeatPurchase": "Investors with a purchase in the month",
+                "CohortMetricRetainedBalance": "Investors holding a balance at the end of the month"
+            },
+            "x-enum-descriptions": [
+                "Investors with a purchase in the month",
+                "Investors holding a balance at the end of the month"
+            ],
+            "x-enum-varnames": [
+                "CohortMetricRepeatPurchase",
+                "CohortMetricRetainedBalance"
+            ]
+        },
+        "models.CouponDistribution": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Raw payment token amount",
+                    "type": "string"
+                },
+                "distribution_id": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_token": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CouponScheduleResponse": {
+            "type": "object",
+            "properties": {
+                "contract_address": {
+                    "type": "string"
+                },
+                "coupons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduledCoupon"
+                    }
+                },
+                "estimated": {
+                    "description": "False when kupon_pertama or the payment frequency is unknown, leaving coupons empty",
+                    "type": "boolean"
+                },
+                "extra_distributions": {
+                    "description": "Distributions matching no scheduled coupon",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CouponDistribution"
+                    }
+                },
+                "frequency": {
+                    "description": "monthly, quarterly, semiannual or annual",
+                    "type": "string"
+                },
+                "grace_period": {
+                    "description": "How late a distribution may land and still pay a coupon",
+                    "type": "string"
+                },
+                "next_coupon_date": {
+                    "type": "string"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CouponStatus": {
+            "type": "string",
+            "enum": [
+                "paid",
+                "upcoming",
+                "missed"
+            ],
+            "x-enum-comments": {
+                "CouponStatusMissed": "The grace window passed without a distribution",
+                "CouponStatusPaid": "A yield distribution landed within the grace window",
+                "CouponStatusUpcoming": "Not yet due, or due and still within the grace window"
+            },
+            "x-enum-descriptions": [
+                "A yield distribution landed within the grace window",
+                "Not yet due, or due and still within the grace window",
+                "The grace window passed without a distribution"
+            ],
+            "x-enum-varnames": [
+                "CouponStatusPaid",
+                "CouponStatusUpcoming",
+                "CouponStatusMissed"
+            ]
+        },
+        "models.DigestBalanceChange": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "balance": {
+                    "description": "Balance after the change",
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestMaturity": {
+            "type": "object",
+            "properties": {
+                "days_remaining": {
+                    "type": "integer"
+                },
+                "jatuh_tempo": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestRedemptionUpdate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "requested or approved",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RedemptionStatus"
+                        }
+                    ]
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DigestResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "balance_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestBalanceChange"
+                    }
+                },
+                "deliver": {
+                    "description": "From the address's notification preferences; the worker sends only when Deliver is true",
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "locale": {
+                    "$ref": "#/definitions/models.Locale"
+                },
+                "redemption_updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestRedemptionUpdate"
+                    }
+                },
+                "since": {
+                    "description": "Inclusive",
+                    "type": "string"
+                },
+                "since_provided": {
+                    "description": "An explicit since does not advance last_digest_at",
+                    "type": "boolean"
+                },
+                "until": {
+                    "description": "Exclusive, and the start of the next digest",
+                    "type": "string"
+                },
+                "upcoming_maturities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestMaturity"
+                    }
+                },
+                "yield_distributions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DigestYieldDistribution"
+                    }
+                }
+            }
+        },
+        "models.DigestYieldDistribution": {
+            "type": "object",
+            "properties": {
+                "distributed_at": {
+                    "type": "string"
+                },
+                "distribution_id": {
+                    "type": "integer"
+                },
+                "entitled_amount": {
+                    "description": "Pro-rata share of the current balance, rounded down",
+                    "type": "string"
+                },
+                "payment_token": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "supply_source": {
+                    "description": "Where the supply behind EntitledAmount was read from",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SupplySource"
+                        }
+                    ]
+                },
+                "total_amount": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DisplayMeta": {
+            "type": "object",
+            "properties": {
+                "fx_rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FxRate"
+                    }
+                }
+            }
+        },
+        "models.DisplayValues": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "models.DistributionEntitlement": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string"
+                },
+                "below_minimum": {
+                    "type": "boolean"
+                },
+                "entitlement": {
+                    "type": "string"
+                },
+                "holder": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DistributionPreview": {
+            "type": "object",
+            "properties": {
+                "distributed_amount": {
+                    "description": "Sum of all entitlements",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FormattedAmount"
+                        }
+                    ]
+                },
+                "dust": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                },
+                "holders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DistributionEntitlement"
+                    }
+                },
+                "min_entitlement": {
+                    "description": "Holders entitled to less are flagged",
+                    "type": "string"
+                },
+                "payment_token": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.DistributionPreviewStats"
+                },
+                "total_amount": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                },
+                "total_supply": {
+                    "description": "Sum of the current holder balances",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DistributionPreviewRequest": {
+            "type": "object",
+            "required": [
+                "payment_token",
+                "total_amount"
+            ],
+            "properties": {
+                "min_entitlement": {
+                    "description": "Overrides the configured minimum entitlement",
+                    "type": "string"
+                },
+                "payment_token": {
+                    "description": "Payment token contract address",
+                    "type": "string"
+                },
+                "total_amount": {
+                    "description": "Raw amount in the payment token's smallest unit",
+                    "type": "string"
+                }
+            }
+        },
+        "models.DistributionPreviewStats": {
+            "type": "object",
+            "properties": {
+                "below_minimum_count": {
+                    "type": "integer"
+                },
+                "holder_count": {
+                    "type": "integer"
+                },
+                "max_entitlement": {
+                    "type": "string"
+                },
+                "median_entitlement": {
+                    "description": "Mean of the middle two, rounded down, for an even count",
+                    "type": "string"
+                },
+                "min_entitlement": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventStatus": {
+            "type": "string",
+            "enum": [
+                "confirmed",
+                "orphaned"
+            ],
+            "x-enum-comments": {
+                "EventStatusOrphaned": "Its transaction was dropped by a chain reorg"
+            },
+            "x-enum-descriptions": [
+                "Its transaction was dropped by a chain reorg"
+            ],
+            "x-enum-varnames": [
+                "EventStatusConfirmed",
+                "EventStatusOrphaned"
+            ]
+        },
+        "models.FormattedAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Raw amount in the token's smallest unit",
+                    "type": "string"
+                },
+                "decimals": {
+                    "description": "Decimals used for formatting",
+                    "type": "integer"
+                },
+                "formatted": {
+                    "description": "Amount scaled by the token decimals, e.g. \"1.5\"",
+                    "type": "string"
+                },
+                "symbol": {
+                    "description": "Token symbol, empty for unknown tokens",
+                    "type": "string"
+                },
+                "token_address": {
+                    "description": "Token contract address",
+                    "type": "string"
+                },
+                "unknown_token": {
+                    "description": "True when the token is not registered and 18 decimals were assumed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.FxRate": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Lowercase currency code, e.g. usd",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Price of one whole token",
+                    "type": "string"
+                },
+                "stale": {
+                    "description": "Past its refresh time because the rate source is slow or failing",
+                    "type": "boolean"
+                },
+                "token": {
+                    "description": "Token symbol, e.g. IDRX",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImbalHasilDetail": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "6.55% / Tahun"
+                },
+                "rate_bps": {
+                    "type": "integer",
+                    "example": 655
+                }
+            }
+        },
+        "models.IndexerTableInfo": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "type": "string"
+                },
+                "hash_prefix": {
+                    "type": "string"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "overridden": {
+                    "description": "Pinned for its event type by an override",
+                    "type": "boolean"
+                },
+                "row_count": {
+                    "type": "integer"
+                },
+                "table_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IndexerTableOverride": {
+            "type": "object",
+            "properties": {
+                "event_type": {
+                    "description": "Table suffix, e.g. holder_update",
+                    "type": "string"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IndexerTableOverridesRequest": {
+            "type": "object",
+            "required": [
+                "overrides"
+            ],
+            "properties": {
+                "overrides": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.IndexerTablesResponse": {
+            "type": "object",
+            "properties": {
+                "available_events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "latest_tables": {
+                    "description": "event_type -\u003e table_name mapping",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "overrides": {
+                    "description": "event_type -\u003e table_name pinned by an admin",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IndexerTableInfo"
+                    }
+                },
+                "total_tables": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InvestorCohort": {
+            "type": "object",
+            "properties": {
+                "cohort": {
+                    "description": "Month of first purchase, YYYY-MM in Jakarta time",
+                    "type": "string"
+                },
+                "rates": {
+                    "description": "Values as a fraction of size, to four decimal places",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "size": {
+                    "description": "Investors whose first purchase was in the month",
+                    "type": "integer"
+                },
+                "values": {
+                    "description": "Investors matching the metric, index N being N months after the cohort month, up to the end of the range",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.InvestorProfile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "document_refs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kyc_status": {
+                    "$ref": "#/definitions/models.KYCStatus"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.KYCReview"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                },
+                "wallet_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvestorProfileCreateRequest": {
+            "type": "object",
+            "required": [
+                "wallet_address"
+            ],
+            "properties": {
+                "document_refs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "wallet_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvestorProfileUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "document_refs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "kyc_status": {
+                    "$ref": "#/definitions/models.KYCStatus"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvestorReport": {
+            "type": "object",
+            "properties": {
+                "issuer_address": {
+                    "type": "string"
+                },
+                "month": {
+                    "description": "YYYY-MM",
+                    "type": "string"
+                },
+                "period_end": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "period_start": {
+                    "description": "Inclusive",
+                    "type": "string"
+                },
+                "rows": {
+                    "description": "One per investor per sukuk, sorted by investor then sukuk",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InvestorReportRow"
+                    }
+                },
+                "sukuk_count": {
+                    "description": "Sukuk owned by the issuer",
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InvestorReportRow": {
+            "type": "object",
+            "properties": {
+                "investor_address": {
+                    "type": "string"
+                },
+                "kyc_status": {
+                    "description": "Empty when the investor has no profile",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KYCStatus"
+                        }
+                    ]
+                },
+                "purchase_count": {
+                    "type": "integer"
+                },
+                "purchased_amount": {
+                    "type": "string"
+                },
+                "redemption_approved_amount": {
+                    "type": "string"
+                },
+                "redemption_count": {
+                    "description": "Redemption requests",
+                    "type": "integer"
+                },
+                "redemption_requested_amount": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "yield_claim_count": {
+                    "type": "integer"
+                },
+                "yield_claimed_amount": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KYCReview": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/models.KYCStatus"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "investor_profile_id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reviewer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KYCReviewRequest": {
+            "type": "object",
+            "required": [
+                "decision",
+                "reviewer"
+            ],
+            "properties": {
+                "decision": {
+                    "$ref": "#/definitions/models.KYCStatus"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "reviewer": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.KYCStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "verified",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "KYCStatusPending",
+                "KYCStatusVerified",
+                "KYCStatusRejected"
+            ]
+        },
+        "models.KYCStatusResponse": {
+            "type": "object",
+            "properties": {
+                "kyc_status": {
+                    "$ref": "#/definitions/models.KYCStatus"
+                },
+                "wallet_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string"
+                },
+                "credits": {
+                    "type": "string"
+                },
+                "debits": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerDirection": {
+            "type": "string",
+            "enum": [
+                "debit",
+                "credit"
+            ],
+            "x-enum-varnames": [
+                "LedgerDebit",
+                "LedgerCredit"
+            ]
+        },
+        "models.LedgerEntry": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "counter_account": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "direction": {
+                    "$ref": "#/definitions/models.LedgerDirection"
+                },
+                "event_type": {
+                    "$ref": "#/definitions/models.LedgerEventType"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "leg": {
+                    "description": "0 for the debit, 1 for the credit",
+                    "type": "integer"
+                },
+                "log_index": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.EventStatus"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LedgerEventType": {
+            "type": "string",
+            "enum": [
+                "purchase",
+                "redemption_payout",
+                "yield_payment"
+            ],
+            "x-enum-comments": {
+                "LedgerEventPurchase": "Buyer pays the sukuk treasury",
+                "LedgerEventRedemptionPayout": "Treasury pays out an approved redemption",
+                "LedgerEventYieldPayment": "Treasury pays a claimed yield distribution"
+            },
+            "x-enum-descriptions": [
+                "Buyer pays the sukuk treasury",
+                "Treasury pays out an approved redemption",
+                "Treasury pays a claimed yield distribution"
+            ],
+            "x-enum-varnames": [
+                "LedgerEventPurchase",
+                "LedgerEventRedemptionPayout",
+                "LedgerEventYieldPayment"
+            ]
+        },
+        "models.LedgerListResponse": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "description": "Per token, when filtered by account",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerBalance"
+                    }
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerEntry"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Locale": {
+            "type": "string",
+            "enum": [
+                "id",
+                "en",
+                "id"
+            ],
+            "x-enum-comments": {
+                "LocaleEN": "English",
+                "LocaleID": "Indonesian; the language the base record is written in"
+            },
+            "x-enum-descriptions": [
+                "Indonesian; the language the base record is written in",
+                "English"
+            ],
+            "x-enum-varnames": [
+                "LocaleID",
+                "LocaleEN",
+                "DefaultLocale"
+            ]
+        },
+        "models.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "yield",
+                "redemption",
+                "digest",
+                "all"
+            ],
+            "x-enum-comments": {
+                "NotificationKindAll": "Every kind; only valid for unsubscribing",
+                "NotificationKindDigest": "Periodic activity digest",
+                "NotificationKindRedemption": "Redemption requests and approvals",
+                "NotificationKindYield": "Yield distributions on held sukuk"
+            },
+            "x-enum-descriptions": [
+                "Yield distributions on held sukuk",
+                "Redemption requests and approvals",
+                "Periodic activity digest",
+                "Every kind; only valid for unsubscribing"
+            ],
+            "x-enum-varnames": [
+                "NotificationKindYield",
+                "NotificationKindRedemption",
+                "NotificationKindDigest",
+                "NotificationKindAll"
+            ]
+        },
+        "models.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "enable_digest": {
+                    "type": "boolean"
+                },
+                "enable_redemption_alerts": {
+                    "type": "boolean"
+                },
+                "enable_yield_alerts": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "$ref": "#/definitions/models.Locale"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NotificationPreferenceUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "An empty string removes the email",
+                    "type": "string"
+                },
+                "enable_digest": {
+                    "type": "boolean"
+                },
+                "enable_redemption_alerts": {
+                    "type": "boolean"
+                },
+                "enable_yield_alerts": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "en"
+                    ]
+                }
+            }
+        },
+        "models.Order": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "fiat_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "settled_at": {
+                    "type": "string"
+                },
+                "settlement_tx_hash": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OrderStatus"
+                },
+                "sukuk_address": {
+                    "description": "Copied from the metadata to match purchases",
+                    "type": "string"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "token_amount": {
+                    "description": "Quoted sukuk token amount in wei",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderCreateRequest": {
+            "type": "object",
+            "required": [
+                "fiat_amount",
+                "sukuk_metadata_id",
+                "user_address"
+            ],
+            "properties": {
+                "fiat_amount": {
+                    "description": "Rupiah; the token amount is quoted from it",
+                    "type": "number"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.OrderPaymentCallbackRequest": {
+            "type": "object",
+            "required": [
+                "payment_reference"
+            ],
+            "properties": {
+                "payment_reference": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OrderStatus"
+                }
+            }
+        },
+        "models.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "paid",
+                "settled",
+                "failed",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusCreated",
+                "OrderStatusPaid",
+                "OrderStatusSettled",
+                "OrderStatusFailed",
+                "OrderStatusExpired"
+            ]
+        },
+        "models.PaymentToken": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decimals": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentTokenCreateRequest": {
+            "type": "object",
+            "required": [
+                "address"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "auto_fetch": {
+                    "type": "boolean"
+                },
+                "decimals": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PaymentTokenUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "decimals": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutInstruction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "In the payment token's wei, or the fiat amount for a bank transfer",
+                    "type": "string"
+                },
+                "bank_reference": {
+                    "description": "Bank transfer reference of a fiat payout",
+                    "type": "string"
+                },
+                "confirmed_at": {
+                    "type": "string"
+                },
+                "confirmed_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "executed_at": {
+                    "type": "string"
+                },
+                "executed_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "payment_token": {
+                    "description": "Token paid out onchain",
+                    "type": "string"
+                },
+                "proof_url": {
+                    "description": "Uploaded transfer receipt",
+                    "type": "string"
+                },
+                "redemption_request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PayoutStatus"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "description": "Onchain payout transaction",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutInstructionListResponse": {
+            "type": "object",
+            "properties": {
+                "instructions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PayoutInstruction"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PayoutInstructionRequest": {
+            "type": "object",
+            "required": [
+                "redemption_request_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Defaults to the approved amount",
+                    "type": "string"
+                },
+                "bank_reference": {
+                    "description": "For a fiat payout",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "payment_token": {
+                    "description": "Defaults to the redemption's payment token unless bank_reference is set",
+                    "type": "string"
+                },
+                "redemption_request_id": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "models.PayoutInstructionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "bank_reference": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "executed",
+                        "confirmed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PayoutStatus"
+                        }
+                    ]
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PayoutStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "executed",
+                "confirmed"
+            ],
+            "x-enum-comments": {
+                "PayoutConfirmed": "Receipt confirmed; final",
+                "PayoutExecuted": "Paid by finance, with a tx hash or proof of transfer",
+                "PayoutPending": "Instructed, not paid yet"
+            },
+            "x-enum-descriptions": [
+                "Instructed, not paid yet",
+                "Paid by finance, with a tx hash or proof of transfer",
+                "Receipt confirmed; final"
+            ],
+            "x-enum-varnames": [
+                "PayoutPending",
+                "PayoutExecuted",
+                "PayoutConfirmed"
+            ]
+        },
+        "models.PeriodePembelianDetail": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "2025-06-19T00:00:00+07:00"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "16 Mei - 18 Jun 2025"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2025-05-16T00:00:00+07:00"
+                }
+            }
+        },
+        "models.PortfolioBatchEntry": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "description": "False when some holdings could not be computed, or for an error entry",
+                    "type": "boolean"
+                },
+                "error": {
+                    "description": "Set instead of the summary, e.g. for an invalid address",
+                    "type": "string"
+                },
+                "holdings_count": {
+                    "type": "integer"
+                },
+                "total_balance": {
+                    "description": "Raw sukuk token balance summed across holdings",
+                    "type": "string"
+                },
+                "total_claimable_yield": {
+                    "description": "Raw claimable yield summed across holdings",
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "Holdings left out of the totals of an incomplete summary",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PortfolioWarning"
+                    }
+                }
+            }
+        },
+        "models.PortfolioBatchRequest": {
+            "type": "object",
+            "required": [
+                "addresses"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PortfolioBatchResponse": {
+            "type": "object",
+            "properties": {
+                "portfolios": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.PortfolioBatchEntry"
+                    }
+                },
+                "truncated": {
+                    "description": "The time budget ran out before every address was summarized",
+                    "type": "boolean"
+                },
+                "unprocessed": {
+                    "description": "Addresses left out when truncated, to request again",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PortfolioResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "complete": {
+                    "description": "False when some holdings could not be computed",
+                    "type": "boolean"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukHolding"
+                    }
+                },
+                "kyc_status": {
+                    "description": "Only included for admin requests",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KYCStatus"
+                        }
+                    ]
+                },
+                "meta": {
+                    "description": "Exchange rates, with ?fiat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DisplayMeta"
+                        }
+                    ]
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.PortfolioSummary"
+                },
+                "total_holdings": {
+                    "type": "integer"
+                },
+                "total_value": {
+                    "description": "Total portfolio value in USD/base currency",
+                    "type": "string"
+                },
+                "warnings": {
+                    "description": "Holdings left out of an incomplete portfolio",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PortfolioWarning"
+                    }
+                }
+            }
+        },
+        "models.PortfolioSummary": {
+            "type": "object",
+            "properties": {
+                "active_sukuk_count": {
+                    "description": "Sukuk with non-zero balance",
+                    "type": "integer"
+                },
+                "display_values": {
+                    "description": "Fiat equivalents of total_claimable_yield and total_yield_claimed, with ?fiat",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.DisplayValues"
+                    }
+                },
+                "matured_sukuk_count": {
+                    "description": "Sukuk that have matured",
+                    "type": "integer"
+                },
+                "total_claimable_yield": {
+                    "type": "string"
+                },
+                "total_sukuk_count": {
+                    "type": "integer"
+                },
+                "total_yield_claimed": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PortfolioWarning": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReconciliationCategory": {
+            "type": "object",
+            "properties": {
+                "amount_mismatches": {
+                    "description": "Same event with different amounts",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReconciliationDiscrepancyList"
+                        }
+                    ]
+                },
+                "indexer_amount": {
+                    "description": "Summed raw amount",
+                    "type": "string"
+                },
+                "indexer_count": {
+                    "type": "integer"
+                },
+                "indexer_table": {
+                    "type": "string"
+                },
+                "local_amount": {
+                    "type": "string"
+                },
+                "local_count": {
+                    "type": "integer"
+                },
+                "local_table": {
+                    "type": "string"
+                },
+                "missing_in_indexer": {
+                    "description": "Stored locally but unknown to the indexer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReconciliationDiscrepancyList"
+                        }
+                    ]
+                },
+                "missing_locally": {
+                    "description": "In the indexer but not stored locally",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReconciliationDiscrepancyList"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReconciliationDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "indexer_amount": {
+                    "type": "string"
+                },
+                "local_amount": {
+                    "type": "string"
+                },
+                "log_index": {
+                    "type": "integer"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReconciliationDiscrepancyList": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReconciliationDiscrepancy"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReconciliationFix": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "inserted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReconciliationReport": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReconciliationCategory"
+                    }
+                },
+                "detail_limit": {
+                    "description": "Maximum entries listed per discrepancy",
+                    "type": "integer"
+                },
+                "fix": {
+                    "description": "Set when a fix was applied before reconciling",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ReconciliationFix"
+                        }
+                    ]
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "in_sync": {
+                    "description": "True when no category has discrepancies",
+                    "type": "boolean"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RedemptionListResponse": {
+            "type": "object",
+            "properties": {
+                "completed_redemption_amount": {
+                    "description": "Approved amounts of approved or completed redemptions",
+                    "type": "string"
+                },
+                "completed_redemption_amount_formatted": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                },
+                "redemptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RedemptionRequest"
+                    }
+                },
+                "skipped_rows": {
+                    "description": "Rows left out of the totals because of an empty or malformed amount",
+                    "type": "integer"
+                },
+                "status_counts": {
+                    "description": "requested: 5, approved: 2, etc.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "total_redemption_amount": {
+                    "description": "Totals in wei, over every row with a parseable amount",
+                    "type": "string"
+                },
+                "total_redemption_amount_formatted": {
+                    "description": "Totals in the payment token's decimals, set when every row uses the same payment token",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FormattedAmount"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.RedemptionRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "approval_block": {
+                    "type": "integer"
+                },
+                "approval_id": {
+                    "type": "string"
+                },
+                "approval_time": {
+                    "type": "string"
+                },
+                "approval_tx_hash": {
+                    "type": "string"
+                },
+                "approved_amount": {
+                    "type": "string"
+                },
+                "can_approve": {
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "description": "Metadata for UI/Business Logic",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukMetadata"
+                        }
+                    ]
+                },
+                "payment_token": {
+                    "type": "string"
+                },
+                "payout_status": {
+                    "description": "Issuer payout of an approved redemption, absent until finance creates its instruction",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PayoutStatus"
+                        }
+                    ]
+                },
+                "request_block": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "description": "Request Information",
+                    "type": "string"
+                },
+                "request_time": {
+                    "type": "string"
+                },
+                "request_tx_hash": {
+                    "type": "string"
+                },
+                "requires_manager_auth": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status and Approval Information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RedemptionStatus"
+                        }
+                    ]
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "total_supply": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RedemptionStatsResponse": {
+            "type": "object",
+            "properties": {
+                "approved_requests": {
+                    "type": "integer"
+                },
+                "by_sukuk": {
+                    "description": "By Sukuk breakdown",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.RedemptionSukukStats"
+                    }
+                },
+                "display_values": {
+                    "description": "Fiat equivalents of total_requested_amount and total_approved_amount, with ?fiat",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.DisplayValues"
+                    }
+                },
+                "meta": {
+                    "description": "Exchange rates, with ?fiat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DisplayMeta"
+                        }
+                    ]
+                },
+                "pending_requests": {
+                    "type": "integer"
+                },
+                "total_approved_amount": {
+                    "type": "string"
+                },
+                "total_requested_amount": {
+                    "type": "string"
+                },
+                "total_requests": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RedemptionStatus": {
+            "type": "string",
+            "enum": [
+                "requested",
+                "approved",
+                "rejected",
+                "completed"
+            ],
+            "x-enum-varnames": [
+                "RedemptionStatusRequested",
+                "RedemptionStatusApproved",
+                "RedemptionStatusRejected",
+                "RedemptionStatusCompleted"
+            ]
+        },
+        "models.RedemptionSukukStats": {
+            "type": "object",
+            "properties": {
+                "approved_amount": {
+                    "type": "string"
+                },
+                "approved_amount_formatted": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                },
+                "display_values": {
+                    "description": "Fiat equivalents of requested_amount and approved_amount, with ?fiat",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.DisplayValues"
+                    }
+                },
+                "payment_token": {
+                    "type": "string"
+                },
+                "request_count": {
+                    "type": "integer"
+                },
+                "requested_amount": {
+                    "type": "string"
+                },
+                "requested_amount_formatted": {
+                    "description": "Amounts in the payment token's decimals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FormattedAmount"
+                        }
+                    ]
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Referral": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReferralBinding": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bound_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "referral_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReferralClaimRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "address": {
+                    "description": "Optional, refused unless it is the signed-in wallet",
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReferralCreateRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "owner_address"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "owner_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReferralStatsResponse": {
+            "type": "object",
+            "properties": {
+                "attributed_purchases": {
+                    "type": "integer"
+                },
+                "bound_addresses": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "owner_address": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "description": "Raw sum of attributed purchase amounts",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReorgAffectedRow": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "table": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReorgIncident": {
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReorgAffectedRow"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "from_block": {
+                    "description": "Lowest block of a retracted transaction",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "indexer_table": {
+                    "type": "string"
+                },
+                "reorg_table": {
+                    "type": "string"
+                },
+                "to_block": {
+                    "description": "Highest block of a retracted transaction",
+                    "type": "integer"
+                },
+                "tx_hashes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ReorgIncidentListResponse": {
+            "type": "object",
+            "properties": {
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReorgIncident"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ScheduledCoupon": {
+            "type": "object",
+            "properties": {
+                "distribution": {
+                    "description": "Set when paid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CouponDistribution"
+                        }
+                    ]
+                },
+                "number": {
+                    "description": "1 for kupon_pertama",
+                    "type": "integer"
+                },
+                "scheduled_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.CouponStatus"
+                }
+            }
+        },
+        "models.SettingsUpdateRequest": {
+            "type": "object",
+            "required": [
+                "settings"
+            ],
+            "properties": {
+                "settings": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SnapshotEvent": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "eligible_count": {
+                    "type": "integer"
+                },
+                "holder_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "snapshot_id": {
+                    "type": "string"
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "total_supply": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuitabilityAcknowledgementRequest": {
+            "type": "object",
+            "required": [
+                "sukuk_metadata_id"
+            ],
+            "properties": {
+                "consent_token": {
+                    "description": "Sent instead of a signature for a checkbox",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "document_id": {
+                    "description": "Current prospectus; omit for a sukuk without one",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "See services.SuitabilityMessage",
+                    "type": "string"
+                },
+                "signature": {
+                    "description": "0x-prefixed 65-byte r || s || v",
+                    "type": "string"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SuitabilityDocument": {
+            "type": "object",
+            "properties": {
+                "file_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SuitabilityState": {
+            "type": "string",
+            "enum": [
+                "acknowledged",
+                "stale",
+                "missing"
+            ],
+            "x-enum-comments": {
+                "SuitabilityAcknowledged": "Acknowledged the current prospectus",
+                "SuitabilityMissing": "Never acknowledged",
+                "SuitabilityStale": "Acknowledged a prospectus version since replaced"
+            },
+            "x-enum-descriptions": [
+                "Acknowledged the current prospectus",
+                "Acknowledged a prospectus version since replaced",
+                "Never acknowledged"
+            ],
+            "x-enum-varnames": [
+                "SuitabilityAcknowledged",
+                "SuitabilityStale",
+                "SuitabilityMissing"
+            ]
+        },
+        "models.SuitabilityStatus": {
+            "type": "object",
+            "properties": {
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "acknowledged_document_id": {
+                    "description": "The latest acknowledgement, absent when missing",
+                    "type": "integer"
+                },
+                "acknowledged_document_version": {
+                    "type": "integer"
+                },
+                "required_document": {
+                    "description": "The prospectus to acknowledge, absent when the sukuk has none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SuitabilityDocument"
+                        }
+                    ]
+                },
+                "state": {
+                    "$ref": "#/definitions/models.SuitabilityState"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SuitabilityStatusesResponse": {
+            "type": "object",
+            "properties": {
+                "acknowledgements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SuitabilityStatus"
+                    }
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SukukAvailability": {
+            "type": "object",
+            "properties": {
+                "cap": {
+                    "description": "kuota_nasional scaled by the token decimals",
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "display_values": {
+                    "description": "Fiat equivalents of cap, total_purchased and remaining, with ?fiat",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.DisplayValues"
+                    }
+                },
+                "meta": {
+                    "description": "Exchange rates, with ?fiat",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DisplayMeta"
+                        }
+                    ]
+                },
+                "percent_subscribed": {
+                    "description": "\"0.00\" - \"100.00\", above 100 when oversubscribed",
+                    "type": "string"
+                },
+                "purchase_period": {
+                    "type": "string"
+                },
+                "purchase_period_end": {
+                    "description": "Exclusive",
+                    "type": "string"
+                },
+                "purchase_period_open": {
+                    "description": "Null when periode_pembelian can't be parsed",
+                    "type": "boolean"
+                },
+                "purchase_period_start": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "description": "Never negative",
+                    "type": "string"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "total_purchased": {
+                    "description": "Sum of indexed purchase events",
+                    "type": "string"
+                },
+                "unlimited": {
+                    "description": "No kuota_nasional is set",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SukukConflictChoice": {
+            "type": "string",
+            "enum": [
+                "chain",
+                "manual"
+            ],
+            "x-enum-comments": {
+                "SukukConflictChoiceChain": "Overwrite the admin edit with the chain value",
+                "SukukConflictChoiceManual": "Keep the admin edit"
+            },
+            "x-enum-descriptions": [
+                "Overwrite the admin edit with the chain value",
+                "Keep the admin edit"
+            ],
+            "x-enum-varnames": [
+                "SukukConflictChoiceChain",
+                "SukukConflictChoiceManual"
+            ]
+        },
+        "models.SukukConflictResolveRequest": {
+            "type": "object",
+            "required": [
+                "choice"
+            ],
+            "properties": {
+                "choice": {
+                    "enum": [
+                        "chain",
+                        "manual"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukConflictChoice"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.SukukConflictStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "SukukConflictOpen",
+                "SukukConflictResolved"
+            ]
+        },
+        "models.SukukDateConstraint": {
+            "type": "string",
+            "enum": [
+                "purchase_period_order",
+                "first_coupon_after_purchase_period",
+                "maturity_after_first_coupon",
+                "coupon_day_matches_cadence"
+            ],
+            "x-enum-comments": {
+                "SukukDateCouponDayMatchesCadence": "tanggal_bayar_kupon pays as often as penerimaan_kupon",
+                "SukukDateFirstCouponAfterSale": "kupon_pertama is after periode_pembelian ends",
+                "SukukDateMaturityAfterCoupon": "jatuh_tempo is after kupon_pertama",
+                "SukukDatePurchasePeriodOrder": "periode_pembelian starts before it ends"
+            },
+            "x-enum-descriptions": [
+                "periode_pembelian starts before it ends",
+                "kupon_pertama is after periode_pembelian ends",
+                "jatuh_tempo is after kupon_pertama",
+                "tanggal_bayar_kupon pays as often as penerimaan_kupon"
+            ],
+            "x-enum-varnames": [
+                "SukukDatePurchasePeriodOrder",
+                "SukukDateFirstCouponAfterSale",
+                "SukukDateMaturityAfterCoupon",
+                "SukukDateCouponDayMatchesCadence"
+            ]
+        },
+        "models.SukukDateSeverity": {
+            "type": "string",
+            "enum": [
+                "error",
+                "warning"
+            ],
+            "x-enum-comments": {
+                "SukukDateError": "The dates contradict each other",
+                "SukukDateWarning": "A date is missing or unreadable, so the rule can't be checked"
+            },
+            "x-enum-descriptions": [
+                "The dates contradict each other",
+                "A date is missing or unreadable, so the rule can't be checked"
+            ],
+            "x-enum-varnames": [
+                "SukukDateError",
+                "SukukDateWarning"
+            ]
+        },
+        "models.SukukDateValidationEntry": {
+            "type": "object",
+            "properties": {
+                "contract_address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "metadata_ready": {
+                    "type": "boolean"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukDateViolation"
+                    }
+                }
+            }
+        },
+        "models.SukukDateValidationReport": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "description": "Sukuk checked",
+                    "type": "integer"
+                },
+                "invalid": {
+                    "description": "Sukuk with at least one error",
+                    "type": "integer"
+                },
+                "records": {
+                    "description": "Sukuk with any violation, by ID",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukDateValidationEntry"
+                    }
+                },
+                "warned": {
+                    "description": "Sukuk with only warnings",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukDateViolation": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "$ref": "#/definitions/models.SukukDateConstraint"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "severity": {
+                    "$ref": "#/definitions/models.SukukDateSeverity"
+                }
+            }
+        },
+        "models.SukukDocument": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_count": {
+                    "description": "Downloads through signed links; only admin listings carry it",
+                    "type": "integer"
+                },
+                "file_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "sukuk_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.SukukDocumentType"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uploaded_at": {
+                    "type": "string"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukDocumentLinkResponse": {
+            "type": "object",
+            "properties": {
+                "document_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukDocumentType": {
+            "type": "string",
+            "enum": [
+                "prospectus",
+                "fact_sheet",
+                "sharia_certificate"
+            ],
+            "x-enum-comments": {
+                "SukukDocumentFactSheet": "Summary of terms for investors",
+                "SukukDocumentProspectus": "Offering prospectus; amendments are new versions",
+                "SukukDocumentShariaCertificate": "Fatwa or sharia compliance certificate"
+            },
+            "x-enum-descriptions": [
+                "Offering prospectus; amendments are new versions",
+                "Summary of terms for investors",
+                "Fatwa or sharia compliance certificate"
+            ],
+            "x-enum-varnames": [
+                "SukukDocumentProspectus",
+                "SukukDocumentFactSheet",
+                "SukukDocumentShariaCertificate"
+            ]
+        },
+        "models.SukukDocumentsResponse": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/models.SukukDocument"
+                        }
+                    }
+                },
+                "sukuk_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukHolding": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Current token balance",
+                    "type": "string"
+                },
+                "claimable_yield": {
+                    "description": "Available yield to claim",
+                    "type": "string"
+                },
+                "claimable_yield_formatted": {
+                    "description": "Claimable yield in the payment token's decimals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FormattedAmount"
+                        }
+                    ]
+                },
+                "last_activity": {
+                    "description": "Last purchase/redemption",
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Sukuk details",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukMetadata"
+                        }
+                    ]
+                },
+                "sukuk_address": {
+                    "type": "string"
+                },
+                "supply_source": {
+                    "description": "Where the supply claimable_yield is a share of came from",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SupplySource"
+                        }
+                    ]
+                },
+                "total_yield_claimed": {
+                    "description": "Total yield claimed historically",
+                    "type": "string"
+                },
+                "unclaimed_distribution_ids": {
+                    "description": "Distribution IDs available for claiming",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "yield_history": {
+                    "description": "Recent yield distributions, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.YieldDistribution"
+                    }
+                }
+            }
+        },
+        "models.SukukMetadata": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "contract_address": {
+                    "description": "Onchain Data",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Timestamps",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Primary Key",
+                    "type": "integer"
+                },
+                "imbal_hasil": {
+                    "description": "6.55% / Tahun",
+                    "type": "string"
+                },
+                "jatuh_tempo": {
+                    "description": "10 Jun 2030",
+                    "type": "string"
+                },
+                "kuota_nasional": {
+                    "description": "7000000000000, in token units",
+                    "type": "string"
+                },
+                "kupon_pertama": {
+                    "description": "11 Agustus 2025",
+                    "type": "string"
+                },
+                "logo_url": {
+                    "description": "Logo link",
+                    "type": "string"
+                },
+                "maksimum_pembelian": {
+                    "description": "Rp10,000,000,000",
+                    "type": "number"
+                },
+                "metadata_ready": {
+                    "description": "Metadata Status",
+                    "type": "boolean"
+                },
+                "minimum_pembelian": {
+                    "description": "Rp1,000,000",
+                    "type": "number"
+                },
+                "onchain_verified": {
+                    "description": "Token details were read from the contract",
+                    "type": "boolean"
+                },
+                "onchain_verified_block": {
+                    "description": "Block the contract was read at",
+                    "type": "integer"
+                },
+                "owner_address": {
+                    "type": "string"
+                },
+                "penerimaan_kupon": {
+                    "description": "Bulanan",
+                    "type": "string"
+                },
+                "periode_pembelian": {
+                    "description": "Ketentuan SR022-T5",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "draft, active, paused, matured, suspended",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukStatus"
+                        }
+                    ]
+                },
+                "sukuk_code": {
+                    "description": "Basic Info",
+                    "type": "string"
+                },
+                "sukuk_deskripsi": {
+                    "description": "Description",
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "description": "Sukuk Ritel",
+                    "type": "string"
+                },
+                "tanggal_bayar_kupon": {
+                    "description": "10 Setiap Bulan",
+                    "type": "string"
+                },
+                "tenor": {
+                    "description": "Main Features",
+                    "type": "string"
+                },
+                "tipe_kupon": {
+                    "description": "Fixed Rate",
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "integer"
+                },
+                "transaction_hash": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Optimistic lock, incremented on every write",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukMetadataConflict": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "chain_value": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "field": {
+                    "$ref": "#/definitions/models.SukukSyncField"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "manual_value": {
+                    "description": "Value when the conflict was last seen",
+                    "type": "string"
+                },
+                "resolution": {
+                    "$ref": "#/definitions/models.SukukConflictChoice"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SukukConflictStatus"
+                },
+                "sukuk_metadata_id": {
+                    "type": "integer"
+                },
+                "tx_hash": {
+                    "description": "Creation event the chain value came from",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SukukMetadataConflictListResponse": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukMetadataConflict"
+                    }
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukMetadataCreateRequest": {
+            "type": "object",
+            "required": [
+                "contract_address",
+                "owner_address",
+                "sukuk_code",
+                "token_id"
+            ],
+            "properties": {
+                "block_number": {
+                    "type": "integer"
+                },
+                "contract_address": {
+                    "description": "Onchain Data",
+                    "type": "string"
+                },
+                "imbal_hasil": {
+                    "description": "A label, e.g. \"6.55% / Tahun\", or an ImbalHasilDetail object",
+                    "type": "string"
+                },
+                "jatuh_tempo": {
+                    "type": "string"
+                },
+                "kuota_nasional": {
+                    "description": "Token units; a JSON number or Indonesian text, e.g. \"7 triliun\"",
+                    "type": "string"
+                },
+                "kupon_pertama": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "maksimum_pembelian": {
+                    "description": "Whole rupiah, e.g. \"Rp 10 miliar\"",
+                    "type": "string"
+                },
+                "minimum_pembelian": {
+                    "description": "Whole rupiah; a JSON number or Indonesian text, e.g. \"Rp 1.000.000\"",
+                    "type": "string"
+                },
+                "owner_address": {
+                    "type": "string"
+                },
+                "penerimaan_kupon": {
+                    "type": "string"
+                },
+                "periode_pembelian": {
+                    "description": "Ketentuan",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Defaults to draft",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukStatus"
+                        }
+                    ]
+                },
+                "sukuk_code": {
+                    "description": "Basic Info",
+                    "type": "string"
+                },
+                "sukuk_deskripsi": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                },
+                "tanggal_bayar_kupon": {
+                    "type": "string"
+                },
+                "tenor": {
+                    "description": "Main Features",
+                    "type": "string"
+                },
+                "tipe_kupon": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "integer"
+                },
+                "transaction_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SukukMetadataListResponse": {
+            "type": "object",
+            "properties": {
+                "available_distributions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukYieldDistribution"
+                    }
+                },
+                "block_number": {
+                    "type": "integer"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "first_purchase_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imbal_hasil": {
+                    "type": "string"
+                },
+                "imbal_hasil_detail": {
+                    "$ref": "#/definitions/models.ImbalHasilDetail"
+                },
+                "investor_count": {
+                    "description": "Onchain activity, omitted with ?include_stats=false. The dates stay empty for sukuk\nthat have not seen a purchase or redemption request yet",
+                    "type": "integer"
+                },
+                "jatuh_tempo": {
+                    "type": "string"
+                },
+                "kuota_nasional": {
+                    "type": "string"
+                },
+                "kuota_nasional_display": {
+                    "description": "7.000.000.000.000",
+                    "type": "string"
+                },
+                "kupon_pertama": {
+                    "type": "string"
+                },
+                "last_activity_at": {
+                    "type": "string"
+                },
+                "latest_activities": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.ActivityEvent"
                     }
                 },
-                "address": {
+                "logo_url": {
                     "type": "string"
                 },
-                "total_count": {
+                "maksimum_pembelian": {
+                    "type": "number"
+                },
+                "maksimum_pembelian_display": {
+                    "description": "Rp10.000.000.000",
+                    "type": "string"
+                },
+                "metadata_ready": {
+                    "type": "boolean"
+                },
+                "minimum_pembelian": {
+                    "type": "number"
+                },
+                "minimum_pembelian_display": {
+                    "description": "Rp1.000.000",
+                    "type": "string"
+                },
+                "owner_address": {
+                    "type": "string"
+                },
+                "penerimaan_kupon": {
+                    "type": "string"
+                },
+                "periode_pembelian": {
+                    "type": "string"
+                },
+                "periode_pembelian_detail": {
+                    "$ref": "#/definitions/models.PeriodePembelianDetail"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SukukStatus"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "sukuk_deskripsi": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                },
+                "suspension": {
+                    "description": "Set while the sukuk is suspended onchain",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukSuspension"
+                        }
+                    ]
+                },
+                "tanggal_bayar_kupon": {
+                    "type": "string"
+                },
+                "tenor": {
+                    "type": "string"
+                },
+                "tenor_detail": {
+                    "description": "Tenor, imbal hasil and periode pembelian parsed from their labels, served by v2 only.\nEach is omitted when its label doesn't parse",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TenorDetail"
+                        }
+                    ]
+                },
+                "tipe_kupon": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "integer"
+                },
+                "transaction_hash": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_balance": {
+                    "description": "Position of the wallet given in ?address=, omitted otherwise",
+                    "type": "string"
+                },
+                "user_claimable_amount": {
+                    "type": "string"
+                },
+                "user_unclaimed_distribution_count": {
+                    "type": "integer"
+                },
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "handlers.SnapshotsResponse": {
+        "models.SukukMetadataPreview": {
+            "type": "object",
+            "properties": {
+                "public": {
+                    "description": "The item the public list and detail would serve",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukMetadataListResponse"
+                        }
+                    ]
+                },
+                "readiness": {
+                    "$ref": "#/definitions/models.SukukReadinessReport"
+                }
+            }
+        },
+        "models.SukukMetadataResponse": {
             "type": "object",
             "properties": {
-                "snapshots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SnapshotEvent"
-                    }
+                "block_number": {
+                    "type": "integer"
                 },
-                "sukuk_address": {
+                "contract_address": {
                     "type": "string"
                 },
-                "total_count": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
-                }
-            }
-        },
-        "models.ActivityEvent": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "description": "Buyer or User address",
+                },
+                "imbal_hasil": {
                     "type": "string"
                 },
-                "amount": {
-                    "description": "Token amount",
+                "jatuh_tempo": {
                     "type": "string"
                 },
-                "sukuk_address": {
-                    "description": "Sukuk contract address",
+                "kuota_nasional": {
                     "type": "string"
                 },
-                "sukuk_code": {
-                    "description": "Sukuk symbol/code (e.g., \"SITI\")",
+                "kuota_nasional_display": {
+                    "description": "7.000.000.000.000",
                     "type": "string"
                 },
-                "sukuk_title": {
-                    "description": "Sukuk name/title",
+                "kupon_pertama": {
                     "type": "string"
                 },
-                "timestamp": {
-                    "description": "Event timestamp",
+                "logo_url": {
                     "type": "string"
                 },
-                "tx_hash": {
-                    "description": "Transaction hash",
+                "maksimum_pembelian": {
+                    "type": "number"
+                },
+                "maksimum_pembelian_display": {
+                    "description": "Rp10.000.000.000",
                     "type": "string"
                 },
-                "type": {
-                    "description": "\"purchase\" or \"redemption_request\"",
+                "metadata_ready": {
+                    "type": "boolean"
+                },
+                "minimum_pembelian": {
+                    "type": "number"
+                },
+                "minimum_pembelian_display": {
+                    "description": "Rp1.000.000",
                     "type": "string"
-                }
-            }
-        },
-        "models.IndexerTableInfo": {
-            "type": "object",
-            "properties": {
-                "event_type": {
+                },
+                "onchain_verified": {
+                    "type": "boolean"
+                },
+                "onchain_verified_block": {
+                    "type": "integer"
+                },
+                "owner_address": {
                     "type": "string"
                 },
-                "hash_prefix": {
+                "penerimaan_kupon": {
                     "type": "string"
                 },
-                "last_updated": {
+                "periode_pembelian": {
                     "type": "string"
                 },
-                "row_count": {
+                "status": {
+                    "$ref": "#/definitions/models.SukukStatus"
+                },
+                "sukuk_code": {
+                    "type": "string"
+                },
+                "sukuk_deskripsi": {
+                    "type": "string"
+                },
+                "sukuk_title": {
+                    "type": "string"
+                },
+                "tanggal_bayar_kupon": {
+                    "type": "string"
+                },
+                "tenor": {
+                    "type": "string"
+                },
+                "tipe_kupon": {
+                    "type": "string"
+                },
+                "token_id": {
                     "type": "integer"
                 },
-                "table_name": {
+                "transaction_hash": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "models.IndexerTablesResponse": {
+        "models.SukukMetadataTranslationsRequest": {
             "type": "object",
+            "required": [
+                "translations"
+            ],
             "properties": {
-                "available_events": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "latest_tables": {
-                    "description": "event_type -\u003e table_name mapping",
+                "translations": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
                     }
-                },
-                "tables": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.IndexerTableInfo"
-                    }
-                },
-                "total_tables": {
-                    "type": "integer"
                 }
             }
         },
-        "models.PortfolioResponse": {
+        "models.SukukMetadataTranslationsResponse": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "holdings": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SukukHolding"
-                    }
+                "default_locale": {
+                    "$ref": "#/definitions/models.Locale"
                 },
-                "summary": {
-                    "$ref": "#/definitions/models.PortfolioSummary"
-                },
-                "total_holdings": {
+                "sukuk_metadata_id": {
                     "type": "integer"
                 },
-                "total_value": {
-                    "description": "Total portfolio value in USD/base currency",
-                    "type": "string"
+                "translations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.Translations"
+                    }
                 }
             }
         },
-        "models.PortfolioSummary": {
+        "models.SukukMetadataUpdateRequest": {
             "type": "object",
             "properties": {
-                "active_sukuk_count": {
-                    "description": "Sukuk with non-zero balance",
-                    "type": "integer"
+                "imbal_hasil": {
+                    "description": "A label, e.g. \"6.55% / Tahun\", or an ImbalHasilDetail object",
+                    "type": "string"
                 },
-                "matured_sukuk_count": {
-                    "description": "Sukuk that have matured",
-                    "type": "integer"
+                "jatuh_tempo": {
+                    "type": "string"
                 },
-                "total_claimable_yield": {
+                "kuota_nasional": {
+                    "description": "Token units; a JSON number or Indonesian text, e.g. \"7 triliun\"",
                     "type": "string"
                 },
-                "total_sukuk_count": {
-                    "type": "integer"
+                "kupon_pertama": {
+                    "type": "string"
                 },
-                "total_yield_claimed": {
+                "logo_url": {
                     "type": "string"
-                }
-            }
-        },
-        "models.RedemptionListResponse": {
-            "type": "object",
-            "properties": {
-                "redemptions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.RedemptionRequest"
-                    }
                 },
-                "status_counts": {
-                    "description": "requested: 5, approved: 2, etc.",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "maksimum_pembelian": {
+                    "description": "Whole rupiah, e.g. \"Rp 10 miliar\"",
+                    "type": "string"
                 },
-                "total_count": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.RedemptionRequest": {
-            "type": "object",
-            "properties": {
-                "amount": {
+                "minimum_pembelian": {
+                    "description": "Whole rupiah; a JSON number or Indonesian text, e.g. \"Rp 1.000.000\"",
                     "type": "string"
                 },
-                "approval_block": {
-                    "type": "integer"
+                "penerimaan_kupon": {
+                    "type": "string"
                 },
-                "approval_id": {
+                "periode_pembelian": {
+                    "description": "Ketentuan",
                     "type": "string"
                 },
-                "approval_time": {
+                "status": {
+                    "description": "Must be an allowed transition from the current status",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SukukStatus"
+                        }
+                    ]
+                },
+                "sukuk_deskripsi": {
                     "type": "string"
                 },
-                "approval_tx_hash": {
+                "sukuk_title": {
+                    "description": "Basic Info",
                     "type": "string"
                 },
-                "approved_amount": {
+                "tanggal_bayar_kupon": {
                     "type": "string"
                 },
-                "can_approve": {
-                    "type": "boolean"
+                "tenor": {
+                    "description": "Main Features",
+                    "type": "string"
                 },
-                "metadata": {
-                    "description": "Metadata for UI/Business Logic",
+                "tipe_kupon": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version last read by the client; may be sent as an If-Match header instead",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SukukReadinessCheck": {
+            "type": "string",
+            "enum": [
+                "required_fields",
+                "dates",
+                "logo"
+            ],
+            "x-enum-comments": {
+                "SukukReadinessDates": "Purchase period, first coupon, maturity and coupon day are consistent",
+                "SukukReadinessLogo": "logo_url points at an existing image",
+                "SukukReadinessRequired": "Fields the public card shows are filled in"
+            },
+            "x-enum-descriptions": [
+                "Fields the public card shows are filled in",
+                "Purchase period, first coupon, maturity and coupon day are consistent",
+                "logo_url points at an existing image"
+            ],
+            "x-enum-varnames": [
+                "SukukReadinessRequired",
+                "SukukReadinessDates",
+                "SukukReadinessLogo"
+            ]
+        },
+        "models.SukukReadinessFailure": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "$ref": "#/definitions/models.SukukReadinessCheck"
+                },
+                "constraint": {
+                    "description": "Date rule broken, for dates failures",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.SukukMetadata"
+                            "$ref": "#/definitions/models.SukukDateConstraint"
                         }
                     ]
                 },
-                "payment_token": {
+                "field": {
                     "type": "string"
                 },
-                "request_block": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SukukReadinessReport": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukReadinessFailure"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SukukStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "active",
+                "paused",
+                "matured",
+                "suspended"
+            ],
+            "x-enum-varnames": [
+                "SukukStatusDraft",
+                "SukukStatusActive",
+                "SukukStatusPaused",
+                "SukukStatusMatured",
+                "SukukStatusSuspended"
+            ]
+        },
+        "models.SukukSuspension": {
+            "type": "object",
+            "properties": {
+                "block_number": {
                     "type": "integer"
                 },
-                "request_id": {
-                    "description": "Request Information",
-                    "type": "string"
-                },
-                "request_time": {
+                "created_at": {
                     "type": "string"
                 },
-                "request_tx_hash": {
+                "event_id": {
+                    "description": "Indexer row id of the suspension event",
                     "type": "string"
                 },
-                "requires_manager_auth": {
-                    "type": "boolean"
+                "id": {
+                    "type": "integer"
                 },
-                "status": {
-                    "description": "Status and Approval Information",
+                "prior_status": {
+                    "description": "Status restored on resume; empty when the sukuk wasn't synced yet",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.RedemptionStatus"
+                            "$ref": "#/definitions/models.SukukStatus"
                         }
                     ]
                 },
-                "sukuk_address": {
+                "reason": {
                     "type": "string"
                 },
-                "total_supply": {
+                "resume_event_id": {
                     "type": "string"
                 },
-                "user": {
+                "resume_tx_hash": {
                     "type": "string"
-                }
-            }
-        },
-        "models.RedemptionStatsResponse": {
-            "type": "object",
-            "properties": {
-                "approved_requests": {
-                    "type": "integer"
                 },
-                "by_sukuk": {
-                    "description": "By Sukuk breakdown",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/models.RedemptionSukukStats"
-                    }
+                "resumed_at": {
+                    "type": "string"
                 },
-                "pending_requests": {
-                    "type": "integer"
+                "sukuk_address": {
+                    "type": "string"
                 },
-                "total_approved_amount": {
+                "suspended_at": {
                     "type": "string"
                 },
-                "total_requested_amount": {
+                "tx_hash": {
                     "type": "string"
                 },
-                "total_requests": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.RedemptionStatus": {
+        "models.SukukSyncField": {
             "type": "string",
             "enum": [
-                "requested",
-                "approved",
-                "rejected",
-                "completed"
+                "sukuk_title",
+                "sukuk_code",
+                "jatuh_tempo",
+                "kuota_nasional"
             ],
             "x-enum-varnames": [
-                "RedemptionStatusRequested",
-                "RedemptionStatusApproved",
-                "RedemptionStatusRejected",
-                "RedemptionStatusCompleted"
+                "SukukSyncFieldTitle",
+                "SukukSyncFieldCode",
+                "SukukSyncFieldJatuhTempo",
+                "SukukSyncFieldKuotaNasional"
             ]
         },
-        "models.RedemptionSukukStats": {
+        "models.SukukTimeSeriesPoint": {
             "type": "object",
             "properties": {
-                "approved_amount": {
+                "bucket_start": {
                     "type": "string"
                 },
-                "request_count": {
-                    "type": "integer"
+                "cumulative_invested": {
+                    "description": "Total purchases up to the end of the bucket",
+                    "type": "string"
                 },
-                "requested_amount": {
+                "cumulative_invested_formatted": {
                     "type": "string"
                 },
-                "sukuk_address": {
+                "invested": {
+                    "description": "Purchases within the bucket",
                     "type": "string"
                 },
-                "sukuk_code": {
+                "invested_formatted": {
+                    "type": "string"
+                },
+                "outstanding_supply": {
+                    "description": "Cumulative purchases minus redemptions",
+                    "type": "string"
+                },
+                "outstanding_supply_formatted": {
+                    "type": "string"
+                },
+                "redeemed": {
+                    "description": "Approved redemptions within the bucket",
+                    "type": "string"
+                },
+                "redeemed_formatted": {
                     "type": "string"
                 }
             }
         },
-        "models.SnapshotEvent": {
+        "models.SukukTimeSeriesResponse": {
             "type": "object",
             "properties": {
-                "block_number": {
-                    "type": "integer"
-                },
-                "eligible_count": {
-                    "type": "integer"
-                },
-                "holder_count": {
-                    "type": "integer"
-                },
-                "id": {
+                "contract_address": {
                     "type": "string"
                 },
-                "snapshot_id": {
+                "from": {
                     "type": "string"
                 },
-                "sukuk_address": {
+                "interval": {
                     "type": "string"
                 },
-                "timestamp": {
-                    "type": "string"
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukTimeSeriesPoint"
+                    }
                 },
-                "total_supply": {
-                    "type": "string"
+                "sukuk_id": {
+                    "type": "integer"
                 },
-                "tx_hash": {
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "models.SukukHolding": {
+        "models.SukukYieldDistribution": {
             "type": "object",
             "properties": {
-                "balance": {
-                    "description": "Current token balance",
+                "amount": {
+                    "description": "Total distributed amount",
                     "type": "string"
                 },
-                "claimable_yield": {
-                    "description": "Available yield to claim",
+                "claimable": {
+                    "description": "Whether user can claim this distribution",
+                    "type": "boolean"
+                },
+                "claimed_amount": {
+                    "description": "Amount user has already claimed",
                     "type": "string"
                 },
-                "last_activity": {
-                    "description": "Last purchase/redemption",
+                "distribution_id": {
+                    "type": "integer"
+                },
+                "payment_token": {
+                    "description": "Token address (e.g., IDRX)",
                     "type": "string"
                 },
-                "metadata": {
-                    "description": "Sukuk details",
+                "supply_source": {
+                    "description": "Where the supply the claimable amount is a share of came from",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.SukukMetadata"
+                            "$ref": "#/definitions/models.SupplySource"
                         }
                     ]
                 },
-                "sukuk_address": {
+                "user_claimable_amount": {
+                    "description": "Amount user can claim based on holdings",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SupplySource": {
+            "type": "string",
+            "enum": [
+                "snapshot",
+                "redemption_request",
+                "none"
+            ],
+            "x-enum-comments": {
+                "SupplySourceNone": "No event recorded a supply",
+                "SupplySourceRedemption": "total_supply recorded by the latest RedemptionRequested event",
+                "SupplySourceSnapshot": "total_supply of the latest SnapshotTaken event"
+            },
+            "x-enum-descriptions": [
+                "total_supply of the latest SnapshotTaken event",
+                "total_supply recorded by the latest RedemptionRequested event",
+                "No event recorded a supply"
+            ],
+            "x-enum-varnames": [
+                "SupplySourceSnapshot",
+                "SupplySourceRedemption",
+                "SupplySourceNone"
+            ]
+        },
+        "models.TaxReport": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "total_yield_claimed": {
-                    "description": "Total yield claimed historically",
+                "claim_count": {
+                    "type": "integer"
+                },
+                "period_end": {
+                    "description": "Exclusive",
                     "type": "string"
                 },
-                "unclaimed_distribution_ids": {
-                    "description": "Distribution IDs available for claiming",
+                "period_start": {
+                    "description": "Inclusive",
+                    "type": "string"
+                },
+                "sukuk": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.TaxReportSukuk"
                     }
                 },
-                "yield_history": {
-                    "description": "Recent yield distributions",
+                "timezone": {
+                    "description": "Timezone of the calendar year boundaries",
+                    "type": "string"
+                },
+                "totals": {
+                    "description": "Grand total per payment token",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.YieldDistribution"
+                        "$ref": "#/definitions/models.TaxReportTotal"
                     }
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "models.SukukMetadata": {
+        "models.TaxReportClaim": {
             "type": "object",
             "properties": {
-                "block_number": {
-                    "type": "integer"
+                "amount": {
+                    "$ref": "#/definitions/models.FormattedAmount"
                 },
-                "contract_address": {
-                    "description": "Onchain Data",
+                "claimed_at": {
+                    "description": "In the report timezone",
                     "type": "string"
                 },
-                "created_at": {
-                    "description": "Timestamps",
-                    "type": "string"
+                "distribution_id": {
+                    "type": "integer"
                 },
-                "id": {
-                    "description": "Primary Key",
+                "tx_hash": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TaxReportSukuk": {
+            "type": "object",
+            "properties": {
+                "claim_count": {
                     "type": "integer"
                 },
-                "imbal_hasil": {
-                    "description": "6.55% / Tahun",
+                "claims": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TaxReportClaim"
+                    }
+                },
+                "issuer_address": {
+                    "description": "Owner of the sukuk contract",
                     "type": "string"
                 },
-                "jatuh_tempo": {
-                    "description": "10 Jun 2030",
+                "payment_token": {
                     "type": "string"
                 },
-                "kuota_nasional": {
-                    "description": "Rp7,000,000,000,000",
-                    "type": "number"
+                "sukuk_address": {
+                    "type": "string"
                 },
-                "kupon_pertama": {
-                    "description": "11 Agustus 2025",
+                "sukuk_code": {
                     "type": "string"
                 },
-                "logo_url": {
-                    "description": "Logo link",
+                "sukuk_title": {
                     "type": "string"
                 },
-                "maksimum_pembelian": {
-                    "description": "Rp10,000,000,000",
-                    "type": "number"
+                "total": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                }
+            }
+        },
+        "models.TaxReportTotal": {
+            "type": "object",
+            "properties": {
+                "claim_count": {
+                    "type": "integer"
                 },
-                "metadata_ready": {
-                    "description": "Metadata Status",
-                    "type": "boolean"
+                "payment_token": {
+                    "type": "string"
                 },
-                "minimum_pembelian": {
-                    "description": "Rp1,000,000",
-                    "type": "number"
+                "total": {
+                    "$ref": "#/definitions/models.FormattedAmount"
+                }
+            }
+        },
+        "models.TenorDetail": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "5 Tahun"
                 },
-                "owner_address": {
+                "months": {
+                    "type": "integer",
+                    "example": 60
+                }
+            }
+        },
+        "models.TransactionEvent": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "string"
                 },
-                "penerimaan_kupon": {
-                    "description": "Bulanan",
+                "block_number": {
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Additional event-specific data",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "status": {
+                    "description": "\"pending\", \"confirmed\", \"failed\"",
                     "type": "string"
                 },
-                "periode_pembelian": {
-                    "description": "Ketentuan SR022-T5",
+                "sukuk_address": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "Berlangsung",
+                "timestamp": {
                     "type": "string"
                 },
-                "sukuk_code": {
-                    "description": "Basic Info",
+                "tx_hash": {
                     "type": "string"
                 },
-                "sukuk_deskripsi": {
-                    "description": "Description",
+                "type": {
+                    "description": "\"purchase\", \"redemption_request\" or \"yield_claim\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ActivityType"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.TransactionHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "sukuk_title": {
-                    "description": "Sukuk Ritel",
-                    "type": "string"
+                "kyc_status": {
+                    "description": "Only included for admin requests",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.KYCStatus"
+                        }
+                    ]
                 },
-                "tanggal_bayar_kupon": {
-                    "description": "10 Setiap Bulan",
-                    "type": "string"
+                "total_count": {
+                    "type": "integer"
                 },
-                "tenor": {
-                    "description": "Main Features",
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransactionEvent"
+                    }
+                }
+            }
+        },
+        "models.Translations": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "models.UnsubscribeConfirmation": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "tipe_kupon": {
-                    "description": "Fixed Rate",
+                "confirm": {
                     "type": "string"
                 },
-                "token_id": {
-                    "type": "integer"
-                },
-                "transaction_hash": {
+                "notification": {
+                    "$ref": "#/definitions/models.NotificationKind"
+                }
+            }
+        },
+        "models.UnsubscribeResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "unsubscribed": {
+                    "$ref": "#/definitions/models.NotificationKind"
                 }
             }
         },
-        "models.SukukMetadataCreateRequest": {
+        "models.VaultBalanceResponse": {
             "type": "object",
-            "required": [
-                "contract_address",
-                "owner_address",
-                "sukuk_code",
-                "token_id"
-            ],
             "properties": {
-                "block_number": {
-                    "type": "integer"
+                "balances": {
+                    "description": "One per payment token, by address",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VaultTokenBalance"
+                    }
                 },
                 "contract_address": {
-                    "description": "Onchain Data",
-                    "type": "string"
-                },
-                "imbal_hasil": {
                     "type": "string"
                 },
-                "jatuh_tempo": {
+                "funding_coverage": {
+                    "description": "Balance as a percentage of next_coupon_amount, e.g. \"150.00\"",
                     "type": "string"
                 },
-                "kuota_nasional": {
-                    "type": "number"
+                "low_funding": {
+                    "description": "Coverage below 100%",
+                    "type": "boolean"
                 },
-                "kupon_pertama": {
+                "next_coupon_amount": {
                     "type": "string"
                 },
-                "logo_url": {
+                "next_coupon_date": {
                     "type": "string"
                 },
-                "maksimum_pembelian": {
-                    "type": "number"
+                "next_coupon_payment_token": {
+                    "description": "The next coupon is estimated as the latest distribution, in its payment token",
+                    "type": "string"
                 },
-                "minimum_pembelian": {
-                    "type": "number"
+                "sukuk_metadata_id": {
+                    "type": "integer"
                 },
-                "owner_address": {
+                "vault_address": {
+                    "description": "From the latest vault_update event",
                     "type": "string"
-                },
-                "penerimaan_kupon": {
+                }
+            }
+        },
+        "models.VaultTokenBalance": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Deposited minus distributed, negative when distributions exceed the recorded deposits",
                     "type": "string"
                 },
-                "periode_pembelian": {
-                    "description": "Ketentuan",
+                "deposited": {
+                    "description": "Raw amount deposited into the yield vault",
                     "type": "string"
                 },
-                "status": {
+                "distributed": {
+                    "description": "Raw amount paid out by yield distributions",
                     "type": "string"
                 },
-                "sukuk_code": {
-                    "description": "Basic Info",
+                "payment_token": {
                     "type": "string"
-                },
-                "sukuk_deskripsi": {
+                }
+            }
+        },
+        "models.WalletAuthChallenge": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "sukuk_title": {
+                "expires_at": {
                     "type": "string"
                 },
-                "tanggal_bayar_kupon": {
+                "message": {
+                    "description": "Sign exactly this with personal_sign",
                     "type": "string"
                 },
-                "tenor": {
-                    "description": "Main Features",
+                "nonce": {
                     "type": "string"
-                },
-                "tipe_kupon": {
+                }
+            }
+        },
+        "models.WalletAuthSession": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "token_id": {
-                    "type": "integer"
+                "expires_at": {
+                    "type": "string"
                 },
-                "transaction_hash": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "models.SukukMetadataListResponse": {
+        "models.WalletAuthVerifyRequest": {
             "type": "object",
+            "required": [
+                "message",
+                "signature"
+            ],
             "properties": {
-                "available_distributions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SukukYieldDistribution"
-                    }
-                },
-                "block_number": {
-                    "type": "integer"
-                },
-                "contract_address": {
+                "message": {
                     "type": "string"
                 },
-                "created_at": {
+                "signature": {
+                    "description": "0x-prefixed 65-byte r || s || v",
                     "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "imbal_hasil": {
+                }
+            }
+        },
+        "models.YieldClaimAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Raw payment token amount",
                     "type": "string"
                 },
-                "jatuh_tempo": {
+                "payment_token": {
                     "type": "string"
-                },
-                "kuota_nasional": {
-                    "type": "number"
-                },
-                "kupon_pertama": {
+                }
+            }
+        },
+        "models.YieldClaimDataResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
                     "type": "string"
                 },
-                "latest_activities": {
+                "amounts": {
+                    "description": "What the claim pays out per payment token",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.ActivityEvent"
+                        "$ref": "#/definitions/models.YieldClaimAmount"
                     }
                 },
-                "logo_url": {
+                "data": {
+                    "description": "ABI-encoded calldata: selector followed by the distribution IDs",
                     "type": "string"
                 },
-                "maksimum_pembelian": {
-                    "type": "number"
-                },
-                "metadata_ready": {
-                    "type": "boolean"
-                },
-                "minimum_pembelian": {
-                    "type": "number"
+                "distribution_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "owner_address": {
-                    "type": "string"
+                "distributions": {
+                    "description": "The distributions being claimed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SukukYieldDistribution"
+                    }
                 },
-                "penerimaan_kupon": {
+                "function": {
+                    "description": "e.g. claimYield(uint256[])",
                     "type": "string"
                 },
-                "periode_pembelian": {
+                "summary": {
+                    "description": "Human-readable description of the claim",
                     "type": "string"
                 },
-                "status": {
+                "to": {
+                    "description": "Sukuk contract the transaction is sent to",
                     "type": "string"
                 },
-                "sukuk_code": {
+                "value": {
+                    "description": "Native value to send, always \"0\"",
                     "type": "string"
-                },
-                "sukuk_deskripsi": {
+                }
+            }
+        },
+        "models.YieldClaimDetail": {
+            "type": "object",
+            "properties": {
+                "claimable_amount": {
                     "type": "string"
                 },
-                "sukuk_title": {
-                    "type": "string"
+                "distribution_count": {
+                    "type": "integer"
                 },
-                "tanggal_bayar_kupon": {
+                "last_distribution": {
                     "type": "string"
                 },
-                "tenor": {
-                    "type": "string"
+                "metadata": {
+                    "$ref": "#/definitions/models.SukukMetadata"
                 },
-                "tipe_kupon": {
+                "sukuk_address": {
                     "type": "string"
                 },
-                "token_id": {
-                    "type": "integer"
-                },
-                "transaction_hash": {
-                    "type": "string"
+                "unclaimed_distribution_ids": {
+                    "description": "Distribution IDs available for claiming",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "updated_at": {
+                "user_balance": {
                     "type": "string"
                 }
             }
         },
-        "models.SukukMetadataResponse": {
+        "models.YieldClaimsResponse": {
             "type": "object",
             "properties": {
-                "block_number": {
-                    "type": "integer"
-                },
-                "contract_address": {
+                "address": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "claims": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.YieldClaimDetail"
+                    }
                 },
-                "id": {
+                "skipped_rows": {
+                    "description": "Claims left out of the total because of an empty or malformed amount",
                     "type": "integer"
                 },
-                "imbal_hasil": {
-                    "type": "string"
-                },
-                "jatuh_tempo": {
-                    "type": "string"
-                },
-                "kuota_nasional": {
-                    "type": "number"
-                },
-                "kupon_pertama": {
+                "total_amount": {
+                    "description": "Total claimable across all sukuk",
                     "type": "string"
                 },
-                "logo_url": {
+                "total_claims": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.YieldDistribution": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "string"
                 },
-                "maksimum_pembelian": {
-                    "type": "number"
+                "amount_formatted": {
+                    "description": "Amount in the payment token's decimals",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FormattedAmount"
+                        }
+                    ]
                 },
-                "metadata_ready": {
-                    "type": "boolean"
+                "block_number": {
+                    "type": "integer"
                 },
-                "minimum_pembelian": {
-                    "type": "number"
+                "distribution_id": {
+                    "description": "Required for claiming yields",
+                    "type": "integer"
                 },
-                "owner_address": {
+                "id": {
                     "type": "string"
                 },
-                "penerimaan_kupon": {
+                "payment_token": {
                     "type": "string"
                 },
-                "periode_pembelian": {
+                "sukuk_address": {
                     "type": "string"
                 },
-                "status": {
+                "timestamp": {
                     "type": "string"
                 },
-                "sukuk_code": {
+                "tx_hash": {
                     "type": "string"
+                }
+            }
+        },
+        "models.YieldDistributionsResponse": {
+            "type": "object",
+            "properties": {
+                "distributions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.YieldDistribution"
+                    }
                 },
-                "sukuk_deskripsi": {
+                "sukuk_address": {
                     "type": "string"
                 },
-                "sukuk_title": {
-                    "type": "string"
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.InjectedEvent": {
+            "type": "object",
+            "properties": {
+                "block_number": {
+                    "type": "integer"
                 },
-                "tanggal_bayar_kupon": {
+                "event_type": {
                     "type": "string"
                 },
-                "tenor": {
+                "id": {
                     "type": "string"
                 },
-                "tipe_kupon": {
+                "table": {
                     "type": "string"
                 },
-                "token_id": {
+                "timestamp": {
                     "type": "integer"
                 },
-                "transaction_hash": {
+                "tx_hash": {
                     "type": "string"
+                }
+            }
+        },
+        "services.RetentionResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "updated_at": {
-                    "type": "string"
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.RetentionTableResult"
+                    }
                 }
             }
         },
-        "models.SukukMetadataUpdateRequest": {
+        "services.RetentionTableResult": {
             "type": "object",
             "properties": {
-                "imbal_hasil": {
+                "cutoff": {
+                    "description": "Processed events older than this are eligible",
                     "type": "string"
                 },
-                "jatuh_tempo": {
-                    "type": "string"
+                "deleted": {
+                    "type": "integer"
                 },
-                "kuota_nasional": {
-                    "type": "number"
+                "eligible": {
+                    "type": "integer"
                 },
-                "kupon_pertama": {
-                    "type": "string"
+                "retention_days": {
+                    "description": "0 keeps rows forever",
+                    "type": "integer"
                 },
-                "logo_url": {
+                "table": {
                     "type": "string"
+                }
+            }
+        },
+        "services.Scenario": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.InjectedEvent"
+                    }
                 },
-                "maksimum_pembelian": {
-                    "type": "number"
-                },
-                "minimum_pembelian": {
-                    "type": "number"
+                "sukuk_addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "penerimaan_kupon": {
+                "wallet_address": {
                     "type": "string"
+                }
+            }
+        },
+        "services.ScenarioRequest": {
+            "type": "object",
+            "properties": {
+                "sukuk_addresses": {
+                    "description": "Defaults to the first two ready sukuk",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "periode_pembelian": {
-                    "description": "Ketentuan",
+                "wallet_address": {
+                    "description": "Defaults to a new random wallet",
                     "type": "string"
-                },
-                "status": {
+                }
+            }
+        },
+        "services.SettingType": {
+            "type": "string",
+            "enum": [
+                "int",
+                "bool",
+                "duration"
+            ],
+            "x-enum-comments": {
+                "SettingTypeBool": "true/false, also 1/0, on/off, yes/no",
+                "SettingTypeDuration": "Positive Go duration such as 30s, or whole seconds",
+                "SettingTypeInt": "Positive integer"
+            },
+            "x-enum-descriptions": [
+                "Positive integer",
+                "true/false, also 1/0, on/off, yes/no",
+                "Positive Go duration such as 30s, or whole seconds"
+            ],
+            "x-enum-varnames": [
+                "SettingTypeInt",
+                "SettingTypeBool",
+                "SettingTypeDuration"
+            ]
+        },
+        "services.SettingValue": {
+            "type": "object",
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "sukuk_deskripsi": {
+                "key": {
                     "type": "string"
                 },
-                "sukuk_title": {
-                    "description": "Basic Info",
-                    "type": "string"
+                "type": {
+                    "$ref": "#/definitions/services.SettingType"
                 },
-                "tanggal_bayar_kupon": {
+                "value": {
+                    "description": "Empty while the reader's default applies",
                     "type": "string"
+                }
+            }
+        },
+        "services.SyncAnomaly": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/services.SyncAnomalyKind"
                 },
-                "tenor": {
-                    "description": "Main Features",
+                "message": {
                     "type": "string"
                 },
-                "tipe_kupon": {
+                "since": {
                     "type": "string"
                 }
             }
         },
-        "models.SukukYieldDistribution": {
+        "services.SyncAnomalyKind": {
+            "type": "string",
+            "enum": [
+                "stall",
+                "failure_ratio",
+                "cursor_regression",
+                "reorg"
+            ],
+            "x-enum-varnames": [
+                "SyncAnomalyStall",
+                "SyncAnomalyFailureRatio",
+                "SyncAnomalyCursorRegression",
+                "SyncAnomalyReorg"
+            ]
+        },
+        "services.SyncLockHolder": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "Total distributed amount",
-                    "type": "string"
-                },
-                "claimable": {
-                    "description": "Whether user can claim this distribution",
-                    "type": "boolean"
-                },
-                "claimed_amount": {
-                    "description": "Amount user has already claimed",
+                "acquired_at": {
                     "type": "string"
                 },
-                "distribution_id": {
-                    "type": "integer"
-                },
-                "payment_token": {
-                    "description": "Token address (e.g., IDRX)",
+                "heartbeat_at": {
                     "type": "string"
                 },
-                "user_claimable_amount": {
-                    "description": "Amount user can claim based on holdings",
+                "instance": {
                     "type": "string"
                 }
             }
         },
-        "models.TransactionEvent": {
+        "services.SyncLockStatus": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "string"
-                },
-                "block_number": {
-                    "type": "integer"
-                },
-                "details": {
-                    "description": "Additional event-specific data",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "status": {
-                    "description": "\"pending\", \"confirmed\", \"failed\"",
-                    "type": "string"
+                "held": {
+                    "type": "boolean"
                 },
-                "sukuk_address": {
-                    "type": "string"
+                "holder": {
+                    "$ref": "#/definitions/services.SyncLockHolder"
                 },
-                "timestamp": {
-                    "type": "string"
+                "mine": {
+                    "description": "Held by the instance answering",
+                    "type": "boolean"
                 },
-                "tx_hash": {
+                "service": {
                     "type": "string"
                 },
-                "type": {
-                    "description": "\"purchase\", \"redemption\", \"yield_claim\", \"yield_distribution\"",
-                    "type": "string"
+                "stale": {
+                    "description": "The holder stopped writing its heartbeat, e.g. it hung",
+                    "type": "boolean"
                 }
             }
         },
-        "models.TransactionHistoryResponse": {
+        "services.SyncResult": {
             "type": "object",
             "properties": {
-                "address": {
+                "failed": {
+                    "type": "integer"
+                },
+                "last_processed_id": {
                     "type": "string"
                 },
-                "total_count": {
+                "ledger_recorded": {
+                    "description": "Value movements added to the ledger, two entries each",
                     "type": "integer"
                 },
-                "transactions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TransactionEvent"
-                    }
+                "onchain_verified": {
+                    "description": "Sukuk whose token details were read from the contract",
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
                 }
             }
         },
-        "models.YieldClaimDetail": {
+        "services.SyncServiceHealth": {
             "type": "object",
             "properties": {
-                "claimable_amount": {
-                    "type": "string"
+                "anomalies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SyncAnomaly"
+                    }
                 },
-                "distribution_count": {
+                "cursor": {
                     "type": "integer"
                 },
-                "last_distribution": {
-                    "type": "string"
+                "cycles": {
+                    "type": "integer"
                 },
-                "metadata": {
-                    "$ref": "#/definitions/models.SukukMetadata"
+                "healthy": {
+                    "type": "boolean"
                 },
-                "sukuk_address": {
+                "indexer_max_block": {
+                    "type": "integer"
+                },
+                "last_cycle_at": {
                     "type": "string"
                 },
-                "unclaimed_distribution_ids": {
-                    "description": "Distribution IDs available for claiming",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "last_cycle_events": {
+                    "type": "integer"
                 },
-                "user_balance": {
+                "last_cycle_failed": {
+                    "type": "integer"
+                },
+                "last_event_at": {
+                    "description": "Startup time until the first event",
+                    "type": "string"
+                },
+                "service": {
                     "type": "string"
                 }
             }
         },
-        "models.YieldClaimsResponse": {
+        "services.UploadCleanupResult": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
+                "candidates": {
+                    "description": "Unreferenced files older than the grace period",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.UploadFile"
+                    }
                 },
-                "claims": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Candidates that could not be deleted",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.YieldClaimDetail"
+                        "type": "string"
                     }
                 },
-                "total_amount": {
-                    "description": "Total claimable across all sukuk",
+                "grace_period": {
                     "type": "string"
                 },
-                "total_claims": {
+                "scanned": {
                     "type": "integer"
                 }
             }
         },
-        "models.YieldDistribution": {
+        "services.UploadFile": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "string"
-                },
-                "block_number": {
-                    "type": "integer"
-                },
-                "distribution_id": {
-                    "description": "Required for claiming yields",
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "sukuk_address": {
+                "modified_at": {
                     "type": "string"
                 },
-                "timestamp": {
+                "name": {
                     "type": "string"
                 },
-                "tx_hash": {
-                    "type": "string"
+                "size": {
+                    "type": "integer"
                 }
             }
         }
@@ -2466,6 +12356,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "X-API-Key",
             "in": "header"
+        },
+        "WalletAuth": {
+            "description": "Wallet session token from /auth/verify, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/logger"
+)
+
+// ErrMiss is returned by Get when the key is absent or expired
+var ErrMiss = errors.New("cache miss")
+
+// Cache is a key/value store with per-entry TTL
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+	Close() error
+}
+
+// Default TTLs, overridden by Setup
+var (
+	PortfolioTTL  = 15 * time.Second
+	MetadataTTL   = time.Minute
+	StatsTTL      = time.Minute
+	ActivitiesTTL = 5 * time.Second
+)
+
+var (
+	mu         sync.RWMutex
+	store      Cache = NewMemoryCache()
+	keyVersion       = "v1"
+)
+
+// Setup selects the cache backend and TTLs from configuration
+func Setup(cfg config.CacheConfig) error {
+	var backend Cache
+	switch cfg.Driver {
+	case "redis":
+		redisCache, err := NewRedisCache(cfg.RedisURL)
+		if err != nil {
+			return err
+		}
+		backend = redisCache
+	case "none":
+		backend = noopCache{}
+	default:
+		backend = NewMemoryCache()
+	}
+
+	mu.Lock()
+	store = backend
+	if cfg.KeyVersion != "" {
+		keyVersion = cfg.KeyVersion
+	}
+	mu.Unlock()
+
+	if cfg.PortfolioTTL > 0 {
+		PortfolioTTL = cfg.PortfolioTTL
+	}
+	if cfg.MetadataTTL > 0 {
+		MetadataTTL = cfg.MetadataTTL
+	}
+	if cfg.StatsTTL > 0 {
+		StatsTTL = cfg.StatsTTL
+	}
+	if cfg.ActivitiesTTL > 0 {
+		ActivitiesTTL = cfg.ActivitiesTTL
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"driver":      cfg.Driver,
+		"key_version": cfg.KeyVersion,
+	}).Info("Cache initialized")
+	return nil
+}
+
+// Default returns the configured cache backend
+func Default() Cache {
+	mu.RLock()
+	defer mu.RUnlock()
+	return store
+}
+
+// SetDefault replaces the cache backend, e.g. with a fresh in-memory cache in tests
+func SetDefault(c Cache) {
+	mu.Lock()
+	defer mu.Unlock()
+	store = c
+}
+
+// Close releases the configured cache backend
+func Close() error {
+	return Default().Close()
+}
+
+// Key builds a namespaced cache key, e.g. "sukuk:v1:portfolio:0xabc"
+// The version segment lets deployments bust all entries written by older code
+func Key(parts ...string) string {
+	mu.RLock()
+	version := keyVersion
+	mu.RUnlock()
+	return "sukuk:" + version + ":" + strings.Join(parts, ":")
+}
+
+// PortfolioKey is the cache key for an address's portfolio
+func PortfolioKey(address string) string {
+	return Key("portfolio", strings.ToLower(address))
+}
+
+// SukukMetadataListKey is the cache key for a sukuk metadata list filter combination
+func SukukMetadataListKey(filter string) string {
+	return Key("sukuk-metadata", "list", filter)
+}
+
+// SukukStatsKey is the cache key for the activity stats of a set of sukuk, in any order
+func SukukStatsKey(addresses []string) string {
+	return Key("sukuk-metadata", "stats", addressSetHash(addresses))
+}
+
+// SukukPurchaseTotalsKey is the cache key for the purchase totals of a set of sukuk, in any order
+func SukukPurchaseTotalsKey(addresses []string) string {
+	return Key("sukuk-metadata", "purchase-totals", addressSetHash(addresses))
+}
+
+// addressSetHash identifies a set of addresses regardless of order and case
+func addressSetHash(addresses []string) string {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = strings.ToLower(address)
+	}
+	sort.Strings(normalized)
+	sum := sha256.Sum256([]byte(strings.Join(normalized, ",")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// RedemptionStatsKey is the cache key for the redemption statistics
+func RedemptionStatsKey() string {
+	return Key("redemptions", "stats")
+}
+
+// ActivityFeedKey is the cache key for the first page of the activity feed
+func ActivityFeedKey(activityType string, limit int) string {
+	return Key("activities", activityType, strconv.Itoa(limit))
+}
+
+// InvestorCohortsKey is the cache key for an investor cohort analysis
+func InvestorCohortsKey(metric, from, to string) string {
+	return Key("analytics", "cohorts", metric, from, to)
+}
+
+// InvalidateAddresses drops cached entries derived from the given addresses
+func InvalidateAddresses(ctx context.Context, addresses ...string) {
+	keys := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if address != "" {
+			keys = append(keys, PortfolioKey(address))
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	if err := Default().Delete(ctx, keys...); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate address cache entries")
+	}
+}
+
+// InvalidateSukukMetadata drops all cached sukuk metadata lists
+func InvalidateSukukMetadata(ctx context.Context) {
+	if err := Default().DeletePrefix(ctx, Key("sukuk-metadata")); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate sukuk metadata cache entries")
+	}
+}
+
+// InvalidateAll drops every entry written under the current key version
+func InvalidateAll(ctx context.Context) {
+	if err := Default().DeletePrefix(ctx, Key()); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate cache entries")
+	}
+}
+
+// Fetch returns the cached value for key, or calls load and caches its result
+// The boolean reports a cache hit. Cache backend errors are logged and treated as misses
+func Fetch[T any](ctx context.Context, key string, ttl time.Duration, load func() (T, error)) (T, bool, error) {
+	var value T
+	backend := Default()
+
+	data, err := backend.Get(ctx, key)
+	if err == nil {
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, true, nil
+		}
+		logger.WithField("key", key).Warn("Discarding undecodable cache entry")
+	} else if !errors.Is(err, ErrMiss) {
+		logger.WithError(err).WithField("key", key).Warn("Cache read failed")
+	}
+
+	value, err = load()
+	if err != nil {
+		return value, false, err
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		return value, false, fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if err := backend.Set(ctx, key, data, ttl); err != nil {
+		logger.WithError(err).WithField("key", key).Warn("Cache write failed")
+	}
+
+	return value, false, nil
+}
+
+// noopCache disables caching; every lookup is a miss
+type noopCache struct{}
+
+func (noopCache) Get(ctx context.Context, key string) ([]byte, error) { return nil, ErrMiss }
+func (noopCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+func (noopCache) Delete(ctx context.Context, keys ...string) error      { return nil }
+func (noopCache) DeletePrefix(ctx context.Context, prefix string) error { return nil }
+func (noopCache) Close() error                                          { return nil }
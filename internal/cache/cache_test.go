@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+
+	if err := c.Set(ctx, "short", []byte("1"), 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+	if err := c.Set(ctx, "long", []byte("2"), time.Minute); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Expected expired entry to miss, got %v", err)
+	}
+	if value, err := c.Get(ctx, "long"); err != nil || string(value) != "2" {
+		t.Errorf("Expected long-lived entry '2', got %q (%v)", value, err)
+	}
+}
+
+func TestMemoryCacheDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+
+	c.Set(ctx, "sukuk:v1:sukuk-metadata:list:all", []byte("a"), time.Minute)
+	c.Set(ctx, "sukuk:v1:sukuk-metadata:list:true", []byte("b"), time.Minute)
+	c.Set(ctx, "sukuk:v1:portfolio:0xabc", []byte("c"), time.Minute)
+
+	if err := c.DeletePrefix(ctx, "sukuk:v1:sukuk-metadata"); err != nil {
+		t.Fatalf("Failed to delete prefix: %v", err)
+	}
+
+	if _, err := c.Get(ctx, "sukuk:v1:sukuk-metadata:list:all"); !errors.Is(err, ErrMiss) {
+		t.Error("Expected metadata list entry to be deleted")
+	}
+	if _, err := c.Get(ctx, "sukuk:v1:portfolio:0xabc"); err != nil {
+		t.Errorf("Expected portfolio entry to survive, got %v", err)
+	}
+}
+
+func TestKeyIncludesVersion(t *testing.T) {
+	key := PortfolioKey("0xABC")
+	if !strings.HasPrefix(key, "sukuk:"+keyVersion+":") {
+		t.Errorf("Expected key to start with version prefix, got %s", key)
+	}
+	if !strings.HasSuffix(key, ":0xabc") {
+		t.Errorf("Expected address to be lowercased in key, got %s", key)
+	}
+}
+
+func TestFetchHitAndMiss(t *testing.T) {
+	SetDefault(NewMemoryCache())
+	ctx := context.Background()
+
+	calls := 0
+	load := func() (map[string]int, error) {
+		calls++
+		return map[string]int{"holdings": 3}, nil
+	}
+
+	value, hit, err := Fetch(ctx, PortfolioKey("0xabc"), time.Minute, load)
+	if err != nil || hit || value["holdings"] != 3 {
+		t.Fatalf("Expected miss with loaded value, got %v hit=%v err=%v", value, hit, err)
+	}
+
+	value, hit, err = Fetch(ctx, PortfolioKey("0xabc"), time.Minute, load)
+	if err != nil || !hit || value["holdings"] != 3 {
+		t.Fatalf("Expected hit with cached value, got %v hit=%v err=%v", value, hit, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected loader to run once, ran %d times", calls)
+	}
+
+	InvalidateAddresses(ctx, "0xABC")
+
+	if _, hit, _ = Fetch(ctx, PortfolioKey("0xabc"), time.Minute, load); hit {
+		t.Error("Expected miss after invalidating the address")
+	}
+	if calls != 2 {
+		t.Errorf("Expected loader to run again after invalidation, ran %d times", calls)
+	}
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	SetDefault(NewMemoryCache())
+	ctx := context.Background()
+
+	_, _, err := Fetch(ctx, RedemptionStatsKey(), time.Minute, func() (int, error) {
+		return 0, errors.New("indexer unavailable")
+	})
+	if err == nil {
+		t.Fatal("Expected loader error to be returned")
+	}
+
+	if _, err := Default().Get(ctx, RedemptionStatsKey()); !errors.Is(err, ErrMiss) {
+		t.Errorf("Expected failed load not to be cached, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval bounds how often expired entries are purged on write
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is a process-local cache, suitable for single-instance deployments
+type MemoryCache struct {
+	mu        sync.RWMutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]memoryEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the value for key or ErrMiss
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	m.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, ErrMiss
+	}
+	return entry.value, nil
+}
+
+// Set stores value under key for ttl
+func (m *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+
+	if now.Sub(m.lastSweep) > sweepInterval {
+		for k, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	return nil
+}
+
+// Delete removes the given keys
+func (m *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// DeletePrefix removes every key starting with prefix
+func (m *MemoryCache) DeletePrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+	return nil
+}
+
+// Close is a no-op for the in-memory cache
+func (m *MemoryCache) Close() error {
+	return nil
+}
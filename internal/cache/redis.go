@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// deleteBatchSize limits the number of keys removed per DEL during prefix deletion
+const deleteBatchSize = 500
+
+// RedisCache stores entries in Redis so they are shared across instances
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache connects to the Redis server at url (redis://host:port/db)
+func NewRedisCache(url string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisCache{client: client}, nil
+}
+
+// Get returns the value for key or ErrMiss
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return value, err
+}
+
+// Set stores value under key for ttl
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes the given keys
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// DeletePrefix removes every key starting with prefix using SCAN, which
+// does not block the server the way KEYS would
+func (r *RedisCache) DeletePrefix(ctx context.Context, prefix string) error {
+	batch := make([]string, 0, deleteBatchSize)
+	iter := r.client.Scan(ctx, 0, prefix+"*", deleteBatchSize).Iterator()
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == deleteBatchSize {
+			if err := r.client.Del(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return r.Delete(ctx, batch...)
+}
+
+// Close closes the Redis connection pool
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
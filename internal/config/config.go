@@ -10,14 +10,32 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// MaxActivityLatestLimit caps ACTIVITY_LATEST_LIMIT and the latest activities served per sukuk
+const MaxActivityLatestLimit = 50
+
 // Config holds all configuration for our application
 type Config struct {
-	App        AppConfig
-	Database   DatabaseConfig
-	Blockchain BlockchainConfig
-	API        APIConfig
-	Logger     LoggerConfig
-	Email      EmailConfig // Low priority
+	App          AppConfig
+	Database     DatabaseConfig
+	Blockchain   BlockchainConfig
+	API          APIConfig
+	Sync         SyncConfig
+	Cache        CacheConfig
+	Indexer      IndexerConfig
+	Orders       OrderConfig
+	Uploads      UploadConfig
+	Retention    RetentionConfig
+	Reorg        ReorgConfig
+	Activity     ActivityConfig
+	Yield        YieldConfig
+	Certificates CertificateConfig
+	Settings     SettingsConfig
+	Logger       LoggerConfig
+	AccessLog    AccessLogConfig
+	FX           FXConfig
+	Email        EmailConfig // Low priority
+	WalletAuth   WalletAuthConfig
+	Dev          DevConfig
 }
 
 type AppConfig struct {
@@ -28,18 +46,22 @@ type AppConfig struct {
 	Debug       bool
 	UploadDir   string
 	MaxFileSize int64 // in bytes
+	ReadOnly    bool  // Reject every mutating request and run no background writers
 }
 
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host               string
+	Port               int
+	User               string
+	Password           string
+	DBName             string
+	SSLMode            string
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	StatementTimeout   time.Duration // 0 disables the server-side timeout
+	SlowQueryThreshold time.Duration // Queries at least this slow are logged at warn level; 0 disables
+	LogSampleRate      float64       // Fraction of routine queries logged, 0 to 1
 }
 
 type IndexerDatabaseConfig struct {
@@ -64,6 +86,98 @@ type APIConfig struct {
 	RateLimitPerMin int
 	AllowedOrigins  []string
 	WebhookSecret   string
+	V1DeprecatedAt  time.Time // When /api/v1 was deprecated in favour of /api/v2; zero until announced
+	V1SunsetAt      time.Time // When /api/v1 stops being served; zero if not yet scheduled
+	MaxBodySize     int64     // Largest JSON request body in bytes; 0 disables the limit
+	MaxUploadSize   int64     // Largest multipart request body in bytes; 0 disables the limit
+
+	UsageFlushInterval time.Duration // How often per-API-key usage counters are written to api_key_usage
+
+	PortfolioBatchBudget time.Duration // Time a portfolio batch request may spend before returning partial results
+
+	CompressionEnabled bool     // Gzip or deflate responses for clients that accept it
+	CompressionMinSize int      // Smallest response body in bytes worth compressing
+	CompressionPaths   []string // Path prefixes whose responses are compressed
+}
+
+type SyncConfig struct {
+	Interval       time.Duration // Interval between scheduled metadata sync cycles
+	AsyncThreshold int           // Pending events above which manual syncs run in the background
+	SuspendEvent   string        // Indexer event (table suffix) for onchain emergency suspensions
+	ResumeEvent    string        // Indexer event for resumes; empty if the contract emits none
+
+	OnchainBackfill      bool          // Read unverified sukuk from their contracts over BLOCKCHAIN_RPC_ENDPOINT
+	OnchainBackfillBatch int           // Sukuk read from the chain per sync cycle
+	OnchainBackfillDelay time.Duration // Pause between contract reads
+
+	StallThreshold        time.Duration // No new events for this long while the indexer advances flags a stall; 0 disables
+	FailureRatioThreshold float64       // Percent of a cycle's events failing that flags the cycle; 0 disables
+	AlertWebhookURL       string        // Receives a POST for each raised sync anomaly; empty disables
+
+	InstanceID     string        // Names this replica as a sync lock holder; host and PID when empty
+	LockStaleAfter time.Duration // Age of a lock holder's heartbeat that flags the lock stale
+}
+
+type CacheConfig struct {
+	Driver        string        // "memory", "redis" or "none"
+	RedisURL      string        // e.g. redis://localhost:6379/0
+	KeyVersion    string        // Prefix segment bumped to invalidate all cached entries
+	PortfolioTTL  time.Duration // Per-address portfolio responses
+	MetadataTTL   time.Duration // Sukuk metadata list responses
+	StatsTTL      time.Duration // Aggregate statistics responses
+	ActivitiesTTL time.Duration // First page of the platform activity feed, and sukuk activity stats
+}
+
+type IndexerConfig struct {
+	RetryAttempts    int           // Retries of a read after a transient database error
+	RetryBaseDelay   time.Duration // First retry backoff, doubled per retry with jitter
+	BreakerThreshold int           // Consecutive failed reads that open the circuit breaker
+	BreakerCooldown  time.Duration // How long the open breaker rejects reads before probing
+}
+
+type OrderConfig struct {
+	TTL                    time.Duration // How long a created order waits for payment
+	ExpiryInterval         time.Duration // Interval between expiry sweeps of unpaid orders
+	SettlementToleranceBps int64         // Allowed difference between quoted and purchased token amounts, in basis points
+}
+
+type UploadConfig struct {
+	CleanupInterval time.Duration // Interval between orphaned upload sweeps; 0 disables the schedule
+	GracePeriod     time.Duration // Minimum age of an unreferenced upload before it is deleted
+	LinkSecret      string        // Signs temporary prospectus download links; empty disables them
+	LinkTTL         time.Duration // How long a prospectus download link stays valid
+}
+
+type RetentionConfig struct {
+	Interval                   time.Duration // Interval between prunes of processed events; 0 disables the schedule
+	BatchSize                  int           // Rows deleted per statement
+	BatchPause                 time.Duration // Pause between delete statements
+	PurchaseEventDays          int           // Days processed purchase events are kept; 0 keeps them forever
+	RedemptionRequestEventDays int           // Days processed redemption request events are kept; 0 keeps them forever
+}
+
+type ReorgConfig struct {
+	Interval       time.Duration // Interval between reconciliations against the indexer's _reorg tables; 0 disables them
+	LookbackBlocks int64         // Recent blocks of each event table compared, counted back from its head
+}
+
+type ActivityConfig struct {
+	ProjectionReads    bool          // Serve activity lists and the feed from activity_projections instead of the indexer tables
+	ProjectionInterval time.Duration // Interval between activity projector runs; 0 disables the projector
+	ProjectionBatch    int           // Indexer events copied per query
+	LatestLimit        int           // Latest activities per sukuk on sukuk metadata responses when neither the request nor the runtime setting gives one
+}
+
+type YieldConfig struct {
+	MinEntitlement string // Raw payment token amount below which a distribution preview flags a holder
+}
+
+type CertificateConfig struct {
+	LayoutFile string // JSON layout of investment certificate PDFs; empty uses the built-in layout
+}
+
+type SettingsConfig struct {
+	RefreshInterval time.Duration // How often runtime settings are reloaded from the database
 }
 
 type LoggerConfig struct {
@@ -71,13 +185,44 @@ type LoggerConfig struct {
 	Format string
 }
 
+type AccessLogConfig struct {
+	Sink              string  // none, stdout or file
+	FilePath          string  // Where the file sink writes
+	MaxSizeMB         int     // Size at which the file sink rotates
+	MaxBackups        int     // Rotated files kept by the file sink
+	SuccessSampleRate float64 // Fraction of successful requests logged, 0 to 1; errors are always logged
+	ScrubAddresses    bool    // Hash wallet addresses in paths and query strings
+	HashSalt          string  // Key of the address hashes, required when scrubbing
+}
+
+type FXConfig struct {
+	Provider    string        // none, static or http
+	StaticRates string        // "TOKEN:CURRENCY=rate" pairs for the static provider, e.g. "IDRX:IDR=1,IDRX:USD=0.000061"
+	RatesURL    string        // JSON rates source of the http provider
+	TTL         time.Duration // How long fetched rates are fresh
+	MaxStale    time.Duration // How long expired rates are still served while refreshing
+}
+
 type EmailConfig struct {
-	Enabled  bool
-	Host     string
-	Port     int
-	User     string
-	Password string
-	From     string
+	Enabled             bool
+	Host                string
+	Port                int
+	User                string
+	Password            string
+	From                string
+	UnsubscribeSecret   string        // Signs the unsubscribe links in notification emails; empty disables them
+	UnsubscribeTokenTTL time.Duration // How long an unsubscribe link stays valid
+}
+
+type WalletAuthConfig struct {
+	Secret   string        // Signs wallet session tokens; empty disables wallet sign-in and the endpoints needing it
+	Domain   string        // Domain named in sign-in messages, which must match on verification
+	NonceTTL time.Duration // How long an issued sign-in nonce stays usable
+	TokenTTL time.Duration // How long a wallet session token stays valid
+}
+
+type DevConfig struct {
+	EventInjector bool // Serve /dev endpoints that write synthetic indexer events; never in production
 }
 
 // Load reads configuration from environment variables
@@ -91,46 +236,154 @@ func Load() (*Config, error) {
 	}
 
 	config := &Config{}
+	env := &envReader{}
 
 	// App configuration
 	config.App = AppConfig{
 		Name:        getEnv("APP_NAME", "sukuk-poc-api"),
 		Version:     getEnv("APP_VERSION", "1.0.0"),
 		Environment: getEnv("APP_ENV", "development"),
-		Port:        getEnvAsInt("APP_PORT", 8080),
-		Debug:       getEnvAsBool("APP_DEBUG", true),
+		Port:        env.getEnvAsInt("APP_PORT", 8080),
+		Debug:       env.getEnvAsBool("APP_DEBUG", true),
 		UploadDir:   getEnv("APP_UPLOAD_DIR", "./uploads"),
-		MaxFileSize: getEnvAsInt64("APP_MAX_FILE_SIZE", 10485760), // 10MB default
+		MaxFileSize: env.getEnvAsInt64("APP_MAX_FILE_SIZE", 10485760), // 10MB default
+		ReadOnly:    env.getEnvAsBool("APP_READ_ONLY", false),
 	}
 
 	// Database configuration
 	config.Database = DatabaseConfig{
-		Host:            getEnv("DB_HOST", "localhost"),
-		Port:            getEnvAsInt("DB_PORT", 5432),
-		User:            getEnv("DB_USER", "postgres"),
-		Password:        getEnv("DB_PASSWORD", "postgres"),
-		DBName:          getEnv("DB_NAME", "sukuk_poc"),
-		SSLMode:         getEnv("DB_SSL_MODE", "disable"),
-		MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
-		MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
-		ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", time.Hour),
+		Host:               getEnv("DB_HOST", "localhost"),
+		Port:               env.getEnvAsInt("DB_PORT", 5432),
+		User:               getEnv("DB_USER", "postgres"),
+		Password:           getEnv("DB_PASSWORD", "postgres"),
+		DBName:             getEnv("DB_NAME", "sukuk_poc"),
+		SSLMode:            getEnv("DB_SSL_MODE", "disable"),
+		MaxOpenConns:       env.getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
+		MaxIdleConns:       env.getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime:    env.getEnvAsDuration("DB_CONN_MAX_LIFETIME", time.Hour),
+		StatementTimeout:   env.getEnvAsDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+		SlowQueryThreshold: time.Duration(env.getEnvAsInt("DB_SLOW_QUERY_MS", 200)) * time.Millisecond,
+		LogSampleRate:      env.getEnvAsFloat64("DB_LOG_SAMPLE_RATE", 0),
 	}
 
 	// Blockchain configuration (Base Testnet defaults)
 	config.Blockchain = BlockchainConfig{
-		ChainID:         getEnvAsInt64("BLOCKCHAIN_CHAIN_ID", 84532), // Base Testnet
+		ChainID:         env.getEnvAsInt64("BLOCKCHAIN_CHAIN_ID", 84532), // Base Testnet
 		RPCEndpoint:     getEnv("BLOCKCHAIN_RPC_ENDPOINT", "https://sepolia.base.org"),
 		WebSocketURL:    getEnv("BLOCKCHAIN_WEBSOCKET_URL", "wss://sepolia.base.org"),
 		ContractAddress: getEnv("BLOCKCHAIN_CONTRACT_ADDRESS", ""),
-		StartBlock:      getEnvAsInt64("BLOCKCHAIN_START_BLOCK", 0),
+		StartBlock:      env.getEnvAsInt64("BLOCKCHAIN_START_BLOCK", 0),
 	}
 
 	// API configuration
 	config.API = APIConfig{
 		APIKey:          getEnv("API_API_KEY", ""),
-		RateLimitPerMin: getEnvAsInt("API_RATE_LIMIT_PER_MIN", 100),
+		RateLimitPerMin: env.getEnvAsInt("API_RATE_LIMIT_PER_MIN", 100),
 		AllowedOrigins:  getEnvAsSlice("API_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 		WebhookSecret:   getEnv("API_WEBHOOK_SECRET", ""),
+		V1DeprecatedAt:  env.getEnvAsTime("API_V1_DEPRECATED_AT"),
+		V1SunsetAt:      env.getEnvAsTime("API_V1_SUNSET_AT"),
+		MaxBodySize:     env.getEnvAsInt64("API_MAX_BODY_SIZE", 1<<20),    // 1MB
+		MaxUploadSize:   env.getEnvAsInt64("API_MAX_UPLOAD_SIZE", 12<<20), // 12MB, room for a 10MB file plus form fields
+
+		UsageFlushInterval: env.getEnvAsDuration("API_USAGE_FLUSH_INTERVAL", time.Minute),
+
+		PortfolioBatchBudget: env.getEnvAsDuration("API_PORTFOLIO_BATCH_BUDGET", 10*time.Second),
+
+		CompressionEnabled: env.getEnvAsBool("API_COMPRESSION_ENABLED", true),
+		CompressionMinSize: env.getEnvAsInt("API_COMPRESSION_MIN_SIZE", 1024),
+		CompressionPaths:   getEnvAsSlice("API_COMPRESSION_PATHS", []string{"/api/"}),
+	}
+
+	// Sync configuration
+	config.Sync = SyncConfig{
+		Interval:       env.getEnvAsDuration("SYNC_INTERVAL", 5*time.Second),
+		AsyncThreshold: env.getEnvAsInt("SYNC_ASYNC_THRESHOLD", 50),
+		SuspendEvent:   getEnv("SYNC_SUSPEND_EVENT", "emergency_suspended"),
+		ResumeEvent:    getEnv("SYNC_RESUME_EVENT", ""),
+
+		OnchainBackfill:      env.getEnvAsBool("SYNC_ONCHAIN_BACKFILL", false),
+		OnchainBackfillBatch: env.getEnvAsInt("SYNC_ONCHAIN_BACKFILL_BATCH", 10),
+		OnchainBackfillDelay: env.getEnvAsDuration("SYNC_ONCHAIN_BACKFILL_DELAY", 500*time.Millisecond),
+
+		StallThreshold:        env.getEnvAsDuration("SYNC_STALL_THRESHOLD", 30*time.Minute),
+		FailureRatioThreshold: env.getEnvAsFloat64("SYNC_FAILURE_RATIO_THRESHOLD", 50),
+		AlertWebhookURL:       getEnv("SYNC_ALERT_WEBHOOK_URL", ""),
+
+		InstanceID:     getEnv("SYNC_INSTANCE_ID", ""),
+		LockStaleAfter: env.getEnvAsDuration("SYNC_LOCK_STALE_AFTER", 2*time.Minute),
+	}
+
+	// Cache configuration
+	config.Cache = CacheConfig{
+		Driver:        getEnv("CACHE_DRIVER", "memory"),
+		RedisURL:      getEnv("CACHE_REDIS_URL", "redis://localhost:6379/0"),
+		KeyVersion:    getEnv("CACHE_KEY_VERSION", "v1"),
+		PortfolioTTL:  env.getEnvAsDuration("CACHE_PORTFOLIO_TTL", 15*time.Second),
+		MetadataTTL:   env.getEnvAsDuration("CACHE_METADATA_TTL", time.Minute),
+		StatsTTL:      env.getEnvAsDuration("CACHE_STATS_TTL", time.Minute),
+		ActivitiesTTL: env.getEnvAsDuration("CACHE_ACTIVITIES_TTL", 5*time.Second),
+	}
+
+	// Indexer read resilience configuration
+	config.Indexer = IndexerConfig{
+		RetryAttempts:    env.getEnvAsInt("INDEXER_RETRY_ATTEMPTS", 2),
+		RetryBaseDelay:   env.getEnvAsDuration("INDEXER_RETRY_BASE_DELAY", 50*time.Millisecond),
+		BreakerThreshold: env.getEnvAsInt("INDEXER_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  env.getEnvAsDuration("INDEXER_BREAKER_COOLDOWN", 30*time.Second),
+	}
+
+	// Purchase order configuration
+	config.Orders = OrderConfig{
+		TTL:                    env.getEnvAsDuration("ORDER_TTL", 30*time.Minute),
+		ExpiryInterval:         env.getEnvAsDuration("ORDER_EXPIRY_INTERVAL", time.Minute),
+		SettlementToleranceBps: env.getEnvAsInt64("ORDER_SETTLEMENT_TOLERANCE_BPS", 50),
+	}
+
+	// Orphaned upload cleanup configuration
+	config.Uploads = UploadConfig{
+		CleanupInterval: env.getEnvAsDuration("UPLOAD_CLEANUP_INTERVAL", 24*time.Hour),
+		GracePeriod:     env.getEnvAsDuration("UPLOAD_CLEANUP_GRACE_PERIOD", 24*time.Hour),
+		LinkSecret:      getEnv("UPLOAD_LINK_SECRET", ""),
+		LinkTTL:         env.getEnvAsDuration("UPLOAD_LINK_TTL", 5*time.Minute),
+	}
+
+	// Processed event retention configuration
+	config.Retention = RetentionConfig{
+		Interval:                   env.getEnvAsDuration("RETENTION_INTERVAL", 24*time.Hour),
+		BatchSize:                  env.getEnvAsInt("RETENTION_BATCH_SIZE", 5000),
+		BatchPause:                 env.getEnvAsDuration("RETENTION_BATCH_PAUSE", 200*time.Millisecond),
+		PurchaseEventDays:          env.getEnvAsInt("RETENTION_PURCHASE_EVENT_DAYS", 0),
+		RedemptionRequestEventDays: env.getEnvAsInt("RETENTION_REDEMPTION_REQUEST_EVENT_DAYS", 0),
+	}
+
+	// Chain reorg reconciliation configuration
+	config.Reorg = ReorgConfig{
+		Interval:       env.getEnvAsDuration("REORG_INTERVAL", time.Minute),
+		LookbackBlocks: env.getEnvAsInt64("REORG_LOOKBACK_BLOCKS", 256),
+	}
+
+	// Activity read model configuration
+	config.Activity = ActivityConfig{
+		ProjectionReads:    env.getEnvAsBool("ACTIVITY_PROJECTION_READS", true),
+		ProjectionInterval: env.getEnvAsDuration("ACTIVITY_PROJECTION_INTERVAL", 10*time.Second),
+		ProjectionBatch:    env.getEnvAsInt("ACTIVITY_PROJECTION_BATCH_SIZE", 500),
+		LatestLimit:        env.getEnvAsInt("ACTIVITY_LATEST_LIMIT", 10),
+	}
+
+	// Yield distribution configuration
+	config.Yield = YieldConfig{
+		MinEntitlement: getEnv("YIELD_MIN_ENTITLEMENT", "1"),
+	}
+
+	// Investment certificate configuration
+	config.Certificates = CertificateConfig{
+		LayoutFile: getEnv("CERTIFICATE_LAYOUT_FILE", ""),
+	}
+
+	// Runtime settings configuration
+	config.Settings = SettingsConfig{
+		RefreshInterval: env.getEnvAsDuration("SETTINGS_REFRESH_INTERVAL", 30*time.Second),
 	}
 
 	// Logger configuration
@@ -139,39 +392,220 @@ func Load() (*Config, error) {
 		Format: getEnv("LOGGER_FORMAT", "json"),
 	}
 
+	// Access log configuration (disabled by default)
+	config.AccessLog = AccessLogConfig{
+		Sink:              getEnv("ACCESS_LOG_SINK", "none"),
+		FilePath:          getEnv("ACCESS_LOG_FILE", "logs/access.log"),
+		MaxSizeMB:         env.getEnvAsInt("ACCESS_LOG_MAX_SIZE_MB", 100),
+		MaxBackups:        env.getEnvAsInt("ACCESS_LOG_MAX_BACKUPS", 5),
+		SuccessSampleRate: env.getEnvAsFloat64("ACCESS_LOG_SUCCESS_SAMPLE_RATE", 1),
+		ScrubAddresses:    env.getEnvAsBool("ACCESS_LOG_SCRUB_ADDRESSES", true),
+		HashSalt:          getEnv("ACCESS_LOG_HASH_SALT", ""),
+	}
+
+	// Fiat display values (disabled by default)
+	config.FX = FXConfig{
+		Provider:    getEnv("FX_PROVIDER", "none"),
+		StaticRates: getEnv("FX_STATIC_RATES", ""),
+		RatesURL:    getEnv("FX_RATES_URL", ""),
+		TTL:         env.getEnvAsDuration("FX_RATES_TTL", 5*time.Minute),
+		MaxStale:    env.getEnvAsDuration("FX_RATES_MAX_STALE", time.Hour),
+	}
+
 	// Email configuration (disabled by default)
 	config.Email = EmailConfig{
-		Enabled:  getEnvAsBool("EMAIL_ENABLED", false),
+		Enabled:  env.getEnvAsBool("EMAIL_ENABLED", false),
 		Host:     getEnv("EMAIL_HOST", "smtp.gmail.com"),
-		Port:     getEnvAsInt("EMAIL_PORT", 587),
+		Port:     env.getEnvAsInt("EMAIL_PORT", 587),
 		User:     getEnv("EMAIL_USER", ""),
 		Password: getEnv("EMAIL_PASSWORD", ""),
 		From:     getEnv("EMAIL_FROM", "noreply@sukuk-poc.com"),
+
+		UnsubscribeSecret:   getEnv("EMAIL_UNSUBSCRIBE_SECRET", ""),
+		UnsubscribeTokenTTL: env.getEnvAsDuration("EMAIL_UNSUBSCRIBE_TOKEN_TTL", 30*24*time.Hour),
+	}
+
+	// Wallet sign-in (disabled without a secret)
+	config.WalletAuth = WalletAuthConfig{
+		Secret:   getEnv("WALLET_AUTH_SECRET", ""),
+		Domain:   getEnv("WALLET_AUTH_DOMAIN", "localhost"),
+		NonceTTL: env.getEnvAsDuration("WALLET_AUTH_NONCE_TTL", 5*time.Minute),
+		TokenTTL: env.getEnvAsDuration("WALLET_AUTH_TOKEN_TTL", 15*time.Minute),
 	}
 
-	// Validate configuration
-	if err := validateConfig(config); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+	// Local development tooling (disabled by default)
+	config.Dev = DevConfig{
+		EventInjector: env.getEnvAsBool("DEV_EVENT_INJECTOR", false),
+	}
+
+	// Report malformed values together with every invalid setting
+	if problems := append(env.problems, config.problems()...); len(problems) > 0 {
+		return nil, fmt.Errorf("config validation failed: %w", &ValidationError{Problems: problems})
 	}
 
 	return config, nil
 }
 
-// validateConfig validates the configuration
-func validateConfig(config *Config) error {
-	if config.App.Port <= 0 || config.App.Port > 65535 {
-		return fmt.Errorf("invalid port: %d", config.App.Port)
+// ValidationError lists every problem found in a configuration, each naming the environment
+// variable to fix
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the configuration, returning a *ValidationError with every problem found
+func (c *Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return &ValidationError{Problems: problems}
 	}
+	return nil
+}
 
-	if config.Blockchain.ChainID != 84532 {
-		return fmt.Errorf("this project is configured for Base Testnet (chain ID 84532), got: %d", config.Blockchain.ChainID)
+// problems returns every rule the configuration breaks
+func (c *Config) problems() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
 	}
 
-	if config.API.APIKey == "" {
-		return fmt.Errorf("API key is required")
+	if c.App.Port <= 0 || c.App.Port > 65535 {
+		add("APP_PORT must be between 1 and 65535, got %d", c.App.Port)
 	}
 
-	return nil
+	// The database is needed by every command
+	if c.Database.Host == "" {
+		add("DB_HOST is required")
+	}
+	if c.Database.Port <= 0 || c.Database.Port > 65535 {
+		add("DB_PORT must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if c.Database.User == "" || c.Database.Password == "" {
+		add("DB_USER and DB_PASSWORD are required")
+	}
+	if c.Database.DBName == "" {
+		add("DB_NAME is required")
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		add("DB_MAX_OPEN_CONNS must be positive, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.ConnMaxLifetime <= 0 {
+		add("DB_CONN_MAX_LIFETIME must be positive, got %s", c.Database.ConnMaxLifetime)
+	}
+
+	if c.Blockchain.ChainID != 84532 {
+		add("BLOCKCHAIN_CHAIN_ID must be 84532, this project is configured for Base Testnet, got %d", c.Blockchain.ChainID)
+	}
+	// The RPC endpoint is only read by the chain features that are enabled
+	if c.Sync.OnchainBackfill && !strings.HasPrefix(c.Blockchain.RPCEndpoint, "http://") && !strings.HasPrefix(c.Blockchain.RPCEndpoint, "https://") {
+		add("BLOCKCHAIN_RPC_ENDPOINT must be an http(s) URL when SYNC_ONCHAIN_BACKFILL is on, got %q", c.Blockchain.RPCEndpoint)
+	}
+
+	// Admin endpoints are only left without a key in development
+	if c.API.APIKey == "" && c.App.Environment != "development" {
+		add("API_API_KEY is required outside development (APP_ENV=%s)", c.App.Environment)
+	}
+	// An empty signing secret disables what it signs, which production must not ship with
+	if c.App.Environment == "production" {
+		if c.Uploads.LinkSecret == "" {
+			add("UPLOAD_LINK_SECRET is required in production")
+		}
+		if c.Email.UnsubscribeSecret == "" {
+			add("EMAIL_UNSUBSCRIBE_SECRET is required in production")
+		}
+		if c.WalletAuth.Secret == "" {
+			add("WALLET_AUTH_SECRET is required in production")
+		}
+	}
+	if c.API.RateLimitPerMin <= 0 {
+		add("API_RATE_LIMIT_PER_MIN must be positive, got %d", c.API.RateLimitPerMin)
+	}
+	if !c.API.V1SunsetAt.IsZero() && c.API.V1SunsetAt.Before(c.API.V1DeprecatedAt) {
+		add("API_V1_SUNSET_AT (%s) must not be before API_V1_DEPRECATED_AT (%s)",
+			c.API.V1SunsetAt.Format(time.RFC3339), c.API.V1DeprecatedAt.Format(time.RFC3339))
+	}
+	if c.API.PortfolioBatchBudget <= 0 {
+		add("API_PORTFOLIO_BATCH_BUDGET must be positive, got %s", c.API.PortfolioBatchBudget)
+	}
+
+	switch c.Cache.Driver {
+	case "memory", "redis", "none":
+	default:
+		add("CACHE_DRIVER must be memory, redis or none, got %q", c.Cache.Driver)
+	}
+
+	if c.Sync.Interval < 5*time.Second {
+		add("SYNC_INTERVAL must be at least 5s, got %s", c.Sync.Interval)
+	}
+	if c.Sync.StallThreshold < 0 {
+		add("SYNC_STALL_THRESHOLD must not be negative, got %s", c.Sync.StallThreshold)
+	}
+	if c.Sync.LockStaleAfter <= 0 {
+		add("SYNC_LOCK_STALE_AFTER must be positive, got %s", c.Sync.LockStaleAfter)
+	}
+	if c.Sync.FailureRatioThreshold < 0 || c.Sync.FailureRatioThreshold > 100 {
+		add("SYNC_FAILURE_RATIO_THRESHOLD must be a percentage between 0 and 100, got %g", c.Sync.FailureRatioThreshold)
+	}
+
+	if c.Reorg.LookbackBlocks <= 0 {
+		add("REORG_LOOKBACK_BLOCKS must be positive, got %d", c.Reorg.LookbackBlocks)
+	}
+
+	if c.Activity.ProjectionBatch <= 0 {
+		add("ACTIVITY_PROJECTION_BATCH_SIZE must be positive, got %d", c.Activity.ProjectionBatch)
+	}
+	// The request's ?activities_limit= takes the same range, 0 skipping the activity lookup
+	if c.Activity.LatestLimit < 0 || c.Activity.LatestLimit > MaxActivityLatestLimit {
+		add("ACTIVITY_LATEST_LIMIT must be between 0 and %d, got %d", MaxActivityLatestLimit, c.Activity.LatestLimit)
+	}
+
+	switch c.AccessLog.Sink {
+	case "none", "stdout":
+	case "file":
+		if c.AccessLog.MaxSizeMB <= 0 {
+			add("ACCESS_LOG_MAX_SIZE_MB must be positive, got %d", c.AccessLog.MaxSizeMB)
+		}
+	default:
+		add("ACCESS_LOG_SINK must be none, stdout or file, got %q", c.AccessLog.Sink)
+	}
+	if c.AccessLog.SuccessSampleRate < 0 || c.AccessLog.SuccessSampleRate > 1 {
+		add("ACCESS_LOG_SUCCESS_SAMPLE_RATE must be between 0 and 1, got %g", c.AccessLog.SuccessSampleRate)
+	}
+	if c.AccessLog.Sink != "none" && c.AccessLog.ScrubAddresses && c.AccessLog.HashSalt == "" {
+		add("ACCESS_LOG_HASH_SALT is required to scrub addresses from the access log")
+	}
+
+	switch c.FX.Provider {
+	case "none", "static":
+	case "http":
+		if c.FX.RatesURL == "" {
+			add("FX_RATES_URL is required for the http exchange rate provider")
+		}
+	default:
+		add("FX_PROVIDER must be none, static or http, got %q", c.FX.Provider)
+	}
+
+	if c.WalletAuth.NonceTTL <= 0 || c.WalletAuth.TokenTTL <= 0 {
+		add("WALLET_AUTH_NONCE_TTL and WALLET_AUTH_TOKEN_TTL must be positive")
+	}
+
+	if c.Dev.EventInjector && c.App.Environment == "production" {
+		add("DEV_EVENT_INJECTOR must not be enabled in production")
+	}
+
+	// A read-only replica runs no sync or other writer, so settings that only act there are a mistake
+	if c.App.ReadOnly {
+		if c.Sync.OnchainBackfill {
+			add("SYNC_ONCHAIN_BACKFILL runs in the metadata sync, which APP_READ_ONLY disables")
+		}
+		if c.Dev.EventInjector {
+			add("DEV_EVENT_INJECTOR writes indexer events, which APP_READ_ONLY forbids")
+		}
+	}
+
+	return problems
 }
 
 // Helper functions
@@ -182,33 +616,57 @@ func getEnv(key string, defaultVal string) string {
 	return defaultVal
 }
 
-func getEnvAsInt(key string, defaultVal int) int {
+// envReader parses typed environment variables, recording malformed values as problems
+// instead of silently falling back to the default. Unset and empty variables take the default
+type envReader struct {
+	problems []string
+}
+
+// parse returns the value of key parsed with parseValue, or ok false when it is unset or malformed
+func parse[T any](e *envReader, key, kind string, parseValue func(string) (T, error)) (T, bool) {
+	var zero T
 	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
+	if valueStr == "" {
+		return zero, false
+	}
+	value, err := parseValue(valueStr)
+	if err != nil {
+		e.problems = append(e.problems, fmt.Sprintf("%s must be %s, got %q", key, kind, valueStr))
+		return zero, false
+	}
+	return value, true
+}
+
+func (e *envReader) getEnvAsInt(key string, defaultVal int) int {
+	if value, ok := parse(e, key, "an integer", strconv.Atoi); ok {
 		return value
 	}
 	return defaultVal
 }
 
-func getEnvAsInt64(key string, defaultVal int64) int64 {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.ParseInt(valueStr, 10, 64); err == nil {
+func (e *envReader) getEnvAsInt64(key string, defaultVal int64) int64 {
+	if value, ok := parse(e, key, "an integer", func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }); ok {
 		return value
 	}
 	return defaultVal
 }
 
-func getEnvAsBool(key string, defaultVal bool) bool {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.ParseBool(valueStr); err == nil {
+func (e *envReader) getEnvAsFloat64(key string, defaultVal float64) float64 {
+	if value, ok := parse(e, key, "a number", func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }); ok {
 		return value
 	}
 	return defaultVal
 }
 
-func getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
-	valueStr := getEnv(key, "")
-	if value, err := time.ParseDuration(valueStr); err == nil {
+func (e *envReader) getEnvAsBool(key string, defaultVal bool) bool {
+	if value, ok := parse(e, key, "true or false", strconv.ParseBool); ok {
+		return value
+	}
+	return defaultVal
+}
+
+func (e *envReader) getEnvAsDuration(key string, defaultVal time.Duration) time.Duration {
+	if value, ok := parse(e, key, "a duration such as 30s or 5m", time.ParseDuration); ok {
 		return value
 	}
 	return defaultVal
@@ -220,4 +678,15 @@ func getEnvAsSlice(key string, defaultVal []string) []string {
 		return defaultVal
 	}
 	return strings.Split(valueStr, ",")
-}
\ No newline at end of file
+}
+
+// getEnvAsTime parses a YYYY-MM-DD or RFC3339 value, returning the zero time if unset
+func (e *envReader) getEnvAsTime(key string) time.Time {
+	value, _ := parse(e, key, "a YYYY-MM-DD or RFC3339 date", func(s string) (time.Time, error) {
+		if value, err := time.Parse(time.RFC3339, s); err == nil {
+			return value, nil
+		}
+		return time.Parse("2006-01-02", s)
+	})
+	return value
+}
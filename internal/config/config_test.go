@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -68,4 +71,209 @@ func TestConfigValidation(t *testing.T) {
 	if err == nil {
 		t.Error("Expected validation error for invalid port")
 	}
-}
\ No newline at end of file
+}
+
+func TestAPIV1DeprecationDates(t *testing.T) {
+	os.Setenv("API_API_KEY", "test-key")
+	os.Setenv("API_V1_DEPRECATED_AT", "2026-11-01")
+	os.Setenv("API_V1_SUNSET_AT", "2027-05-01T00:00:00Z")
+	defer func() {
+		os.Unsetenv("API_API_KEY")
+		os.Unsetenv("API_V1_DEPRECATED_AT")
+		os.Unsetenv("API_V1_SUNSET_AT")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.API.V1DeprecatedAt.Format("2006-01-02") != "2026-11-01" {
+		t.Errorf("Expected deprecation 2026-11-01, got %s", config.API.V1DeprecatedAt)
+	}
+	if config.API.V1SunsetAt.Format("2006-01-02") != "2027-05-01" {
+		t.Errorf("Expected sunset 2027-05-01, got %s", config.API.V1SunsetAt)
+	}
+
+	// A sunset before the deprecation is a misconfiguration
+	os.Setenv("API_V1_SUNSET_AT", "2026-10-01")
+	if _, err := Load(); err == nil {
+		t.Error("Expected validation error for sunset before deprecation")
+	}
+}
+
+func TestDevEventInjectorRefusedInProduction(t *testing.T) {
+	os.Setenv("API_API_KEY", "test-key")
+	os.Setenv("DEV_EVENT_INJECTOR", "true")
+	defer func() {
+		os.Unsetenv("API_API_KEY")
+		os.Unsetenv("DEV_EVENT_INJECTOR")
+		os.Unsetenv("APP_ENV")
+	}()
+
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !config.Dev.EventInjector {
+		t.Error("Expected the event injector enabled in development")
+	}
+
+	os.Setenv("APP_ENV", "production")
+	if _, err := Load(); err == nil {
+		t.Error("Expected validation error for the event injector in production")
+	}
+}
+
+func TestAccessLogScrubbingRequiresSalt(t *testing.T) {
+	os.Setenv("API_API_KEY", "test-key")
+	os.Setenv("ACCESS_LOG_SINK", "stdout")
+	defer func() {
+		os.Unsetenv("API_API_KEY")
+		os.Unsetenv("ACCESS_LOG_SINK")
+		os.Unsetenv("ACCESS_LOG_HASH_SALT")
+		os.Unsetenv("ACCESS_LOG_SCRUB_ADDRESSES")
+	}()
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected validation error for scrubbing without a salt")
+	}
+
+	os.Setenv("ACCESS_LOG_HASH_SALT", "pepper")
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if !config.AccessLog.ScrubAddresses || config.AccessLog.SuccessSampleRate != 1 {
+		t.Errorf("Expected scrubbing on and every request logged by default, got %+v", config.AccessLog)
+	}
+
+	os.Setenv("ACCESS_LOG_SINK", "syslog")
+	if _, err := Load(); err == nil {
+		t.Error("Expected validation error for an unknown sink")
+	}
+}
+
+// loadValid loads the configuration from an environment with only the API key and signing secrets set
+func loadValid(t *testing.T) *Config {
+	t.Helper()
+	t.Setenv("API_API_KEY", "test-key")
+	setSigningSecrets(t)
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return config
+}
+
+// setSigningSecrets sets the secrets production requires
+func setSigningSecrets(t *testing.T) {
+	t.Helper()
+	t.Setenv("UPLOAD_LINK_SECRET", "link-secret")
+	t.Setenv("EMAIL_UNSUBSCRIBE_SECRET", "unsubscribe-secret")
+	t.Setenv("WALLET_AUTH_SECRET", "wallet-secret")
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		envVar string // Named by the problem reported
+	}{
+		{"port zero", func(c *Config) { c.App.Port = 0 }, "APP_PORT"},
+		{"port too high", func(c *Config) { c.App.Port = 65536 }, "APP_PORT"},
+		{"api key in production", func(c *Config) { c.App.Environment, c.API.APIKey = "production", "" }, "API_API_KEY"},
+		{"api key in staging", func(c *Config) { c.App.Environment, c.API.APIKey = "staging", "" }, "API_API_KEY"},
+		{"link secret in production", func(c *Config) { c.App.Environment, c.Uploads.LinkSecret = "production", "" }, "UPLOAD_LINK_SECRET"},
+		{"unsubscribe secret in production", func(c *Config) { c.App.Environment, c.Email.UnsubscribeSecret = "production", "" }, "EMAIL_UNSUBSCRIBE_SECRET"},
+		{"wallet secret in production", func(c *Config) { c.App.Environment, c.WalletAuth.Secret = "production", "" }, "WALLET_AUTH_SECRET"},
+		{"db host", func(c *Config) { c.Database.Host = "" }, "DB_HOST"},
+		{"db user", func(c *Config) { c.Database.User = "" }, "DB_USER"},
+		{"db password", func(c *Config) { c.Database.Password = "" }, "DB_PASSWORD"},
+		{"db name", func(c *Config) { c.Database.DBName = "" }, "DB_NAME"},
+		{"db port", func(c *Config) { c.Database.Port = 70000 }, "DB_PORT"},
+		{"db connections", func(c *Config) { c.Database.MaxOpenConns = 0 }, "DB_MAX_OPEN_CONNS"},
+		{"db connection lifetime", func(c *Config) { c.Database.ConnMaxLifetime = 0 }, "DB_CONN_MAX_LIFETIME"},
+		{"chain", func(c *Config) { c.Blockchain.ChainID = 8453 }, "BLOCKCHAIN_CHAIN_ID"},
+		{"rpc for backfill", func(c *Config) { c.Sync.OnchainBackfill, c.Blockchain.RPCEndpoint = true, "" }, "BLOCKCHAIN_RPC_ENDPOINT"},
+		{"rpc scheme for backfill", func(c *Config) { c.Sync.OnchainBackfill, c.Blockchain.RPCEndpoint = true, "sepolia.base.org" }, "BLOCKCHAIN_RPC_ENDPOINT"},
+		{"rate limit", func(c *Config) { c.API.RateLimitPerMin = 0 }, "API_RATE_LIMIT_PER_MIN"},
+		{"sync interval", func(c *Config) { c.Sync.Interval = time.Second }, "SYNC_INTERVAL"},
+		{"sync lock", func(c *Config) { c.Sync.LockStaleAfter = 0 }, "SYNC_LOCK_STALE_AFTER"},
+		{"failure ratio", func(c *Config) { c.Sync.FailureRatioThreshold = 101 }, "SYNC_FAILURE_RATIO_THRESHOLD"},
+		{"cache driver", func(c *Config) { c.Cache.Driver = "memcached" }, "CACHE_DRIVER"},
+		{"reorg lookback", func(c *Config) { c.Reorg.LookbackBlocks = 0 }, "REORG_LOOKBACK_BLOCKS"},
+		{"projection batch", func(c *Config) { c.Activity.ProjectionBatch = 0 }, "ACTIVITY_PROJECTION_BATCH_SIZE"},
+		{"negative activities limit", func(c *Config) { c.Activity.LatestLimit = -1 }, "ACTIVITY_LATEST_LIMIT"},
+		{"activities limit above the cap", func(c *Config) { c.Activity.LatestLimit = MaxActivityLatestLimit + 1 }, "ACTIVITY_LATEST_LIMIT"},
+		{"access log sink", func(c *Config) { c.AccessLog.Sink, c.AccessLog.HashSalt = "syslog", "salt" }, "ACCESS_LOG_SINK"},
+		{"fx provider", func(c *Config) { c.FX.Provider = "ecb" }, "FX_PROVIDER"},
+		{"fx rates url", func(c *Config) { c.FX.Provider = "http" }, "FX_RATES_URL"},
+		{"portfolio batch budget", func(c *Config) { c.API.PortfolioBatchBudget = 0 }, "API_PORTFOLIO_BATCH_BUDGET"},
+		{"wallet token ttl", func(c *Config) { c.WalletAuth.TokenTTL = 0 }, "WALLET_AUTH_TOKEN_TTL"},
+		{"read-only with backfill", func(c *Config) { c.App.ReadOnly, c.Sync.OnchainBackfill = true, true }, "APP_READ_ONLY"},
+		{"read-only with event injector", func(c *Config) { c.App.ReadOnly, c.Dev.EventInjector = true, true }, "APP_READ_ONLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := loadValid(t)
+			if err := config.Validate(); err != nil {
+				t.Fatalf("Expected the loaded config to be valid, got %v", err)
+			}
+			tt.mutate(config)
+
+			var validationErr *ValidationError
+			if err := config.Validate(); !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], tt.envVar) {
+				t.Errorf("Expected one problem naming %s, got %q", tt.envVar, validationErr.Problems)
+			}
+		})
+	}
+
+	// Development doesn't need an API key or signing secrets
+	config := loadValid(t)
+	config.API.APIKey = ""
+	config.Uploads.LinkSecret, config.Email.UnsubscribeSecret, config.WalletAuth.Secret = "", "", ""
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no API key or secrets to be fine in development, got %v", err)
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("API_API_KEY", "")
+	t.Setenv("APP_PORT", "eighty")
+	t.Setenv("SYNC_INTERVAL", "1s")
+	t.Setenv("API_RATE_LIMIT_PER_MIN", "0")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "forever")
+	t.Setenv("APP_READ_ONLY", "yes please")
+	t.Setenv("API_V1_SUNSET_AT", "next year")
+	setSigningSecrets(t)
+
+	_, err := Load()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	// Malformed values are reported, not replaced by their defaults
+	for _, envVar := range []string{"APP_PORT", "DB_CONN_MAX_LIFETIME", "APP_READ_ONLY", "API_V1_SUNSET_AT", "API_API_KEY", "SYNC_INTERVAL", "API_RATE_LIMIT_PER_MIN"} {
+		if !strings.Contains(err.Error(), envVar) {
+			t.Errorf("Expected the error to name %s, got:\n%v", envVar, err)
+		}
+	}
+	if len(validationErr.Problems) != 7 {
+		t.Errorf("Expected 7 problems, got %d: %q", len(validationErr.Problems), validationErr.Problems)
+	}
+
+	// Empty typed values, as left in .env.example, take the default
+	t.Setenv("APP_ENV", "development")
+	for _, envVar := range []string{"APP_PORT", "SYNC_INTERVAL", "API_RATE_LIMIT_PER_MIN", "DB_CONN_MAX_LIFETIME", "APP_READ_ONLY", "API_V1_SUNSET_AT"} {
+		t.Setenv(envVar, "")
+	}
+	if _, err := Load(); err != nil {
+		t.Errorf("Expected empty values to take their defaults, got %v", err)
+	}
+}
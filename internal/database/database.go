@@ -11,7 +11,6 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	gormLogger "gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
@@ -28,15 +27,21 @@ func Connect(cfg *config.Config) error {
 		cfg.Database.SSLMode,
 	)
 
-	// Configure GORM logger based on environment
-	var gormLogLevel gormLogger.LogLevel
-	if cfg.App.Debug {
-		gormLogLevel = gormLogger.Info
-	} else {
-		gormLogLevel = gormLogger.Silent
+	// Abort runaway queries server-side, including indexer queries which share this connection
+	if cfg.Database.StatementTimeout > 0 {
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.Database.StatementTimeout.Milliseconds())
 	}
 
-	gormLog := gormLogger.Default.LogMode(gormLogLevel)
+	// Failed and slow queries are always logged; routine queries are sampled, or all
+	// logged in debug mode. Indexer queries share this connection and its logger
+	sampleRate := cfg.Database.LogSampleRate
+	if cfg.App.Debug {
+		sampleRate = 1
+	}
+	gormLog := logger.NewGormLogger(logger.GormConfig{
+		SlowThreshold: cfg.Database.SlowQueryThreshold,
+		SampleRate:    sampleRate,
+	})
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: gormLog,
@@ -45,6 +50,12 @@ func Connect(cfg *config.Config) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Tags request queries with their route and request ID; the indexer tables are read
+	// over this same connection, so indexer queries are tagged too
+	if err := db.Use(QueryTagPlugin{}); err != nil {
+		return fmt.Errorf("failed to register query tagging: %w", err)
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -69,25 +80,19 @@ func Connect(cfg *config.Config) error {
 		"max_open_conns":    cfg.Database.MaxOpenConns,
 		"max_idle_conns":    cfg.Database.MaxIdleConns,
 		"conn_max_lifetime": cfg.Database.ConnMaxLifetime.String(),
+		"statement_timeout": cfg.Database.StatementTimeout.String(),
+		"slow_query":        cfg.Database.SlowQueryThreshold.String(),
+		"log_sample_rate":   sampleRate,
 	}).Info("Database connection established successfully")
 	return nil
 }
 
-// Migrate runs database migrations
-func Migrate() error {
-	if DB == nil {
-		return fmt.Errorf("database connection not established")
-	}
-
-	logger.Info("Running database migrations...")
-
-	// Auto-migrate all models
-	if err := DB.AutoMigrate(models.AllModels()...); err != nil {
-		logger.WithError(err).Error("Failed to run database migrations")
-		return fmt.Errorf("failed to run migrations: %w", err)
+// AutoMigrate creates the schema directly from the models
+// Only intended for tests; deployed databases use the versioned migrations in cmd/migrate
+func AutoMigrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(models.AllModels()...); err != nil {
+		return fmt.Errorf("failed to auto-migrate models: %w", err)
 	}
-
-	logger.Info("Database migrations completed successfully")
 	return nil
 }
 
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS redemption_requested_events;
+DROP TABLE IF EXISTS sukuk_purchased_events;
+DROP TABLE IF EXISTS sukuk_metadata;
+DROP TABLE IF EXISTS system_states;
@@ -0,0 +1,88 @@
+CREATE TABLE IF NOT EXISTS system_states (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR(255) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_system_states_key ON system_states (key);
+
+CREATE TABLE IF NOT EXISTS sukuk_metadata (
+    id BIGSERIAL PRIMARY KEY,
+    contract_address VARCHAR(42) NOT NULL,
+    token_id BIGINT,
+    owner_address VARCHAR(42),
+    transaction_hash VARCHAR(66),
+    block_number BIGINT,
+    sukuk_code VARCHAR(20) NOT NULL,
+    sukuk_title VARCHAR(100),
+    sukuk_deskripsi TEXT,
+    status VARCHAR(20),
+    logo_url VARCHAR(255),
+    tenor VARCHAR(20),
+    imbal_hasil VARCHAR(20),
+    periode_pembelian VARCHAR(50),
+    jatuh_tempo TIMESTAMPTZ,
+    kuota_nasional DECIMAL(30,2),
+    penerimaan_kupon VARCHAR(20),
+    minimum_pembelian DECIMAL(20,2),
+    tanggal_bayar_kupon VARCHAR(50),
+    maksimum_pembelian DECIMAL(20,2),
+    kupon_pertama TIMESTAMPTZ,
+    tipe_kupon VARCHAR(20),
+    metadata_ready BOOLEAN DEFAULT false,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uni_sukuk_metadata_contract_address UNIQUE (contract_address)
+);
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_deleted_at ON sukuk_metadata (deleted_at);
+
+CREATE TABLE IF NOT EXISTS sukuk_purchased_events (
+    id BIGSERIAL PRIMARY KEY,
+    buyer VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    payment_token VARCHAR(42) NOT NULL,
+    amount VARCHAR(78) NOT NULL,
+    block_number BIGINT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index BIGINT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    processed BOOLEAN DEFAULT false,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_buyer ON sukuk_purchased_events (buyer);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_sukuk_address ON sukuk_purchased_events (sukuk_address);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_block_number ON sukuk_purchased_events (block_number);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_tx_hash ON sukuk_purchased_events (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_timestamp ON sukuk_purchased_events (timestamp);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_processed ON sukuk_purchased_events (processed);
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_deleted_at ON sukuk_purchased_events (deleted_at);
+
+CREATE TABLE IF NOT EXISTS redemption_requested_events (
+    id BIGSERIAL PRIMARY KEY,
+    "user" VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    amount VARCHAR(78) NOT NULL,
+    payment_token VARCHAR(42) NOT NULL,
+    total_supply VARCHAR(78) NOT NULL,
+    block_number BIGINT NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index BIGINT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    processed BOOLEAN DEFAULT false,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_user ON redemption_requested_events ("user");
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_sukuk_address ON redemption_requested_events (sukuk_address);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_block_number ON redemption_requested_events (block_number);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_tx_hash ON redemption_requested_events (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_timestamp ON redemption_requested_events (timestamp);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_processed ON redemption_requested_events (processed);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_deleted_at ON redemption_requested_events (deleted_at);
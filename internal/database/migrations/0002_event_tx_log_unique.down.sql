@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_tx_hash ON redemption_requested_events (tx_hash);
+DROP INDEX IF EXISTS idx_redemption_requested_tx_log;
+
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_tx_hash ON sukuk_purchased_events (tx_hash);
+DROP INDEX IF EXISTS idx_sukuk_purchased_tx_log;
@@ -0,0 +1,7 @@
+-- Replayed events are rejected by a unique (tx_hash, log_index) key.
+-- The composite index also serves tx_hash lookups, so the single-column index is dropped.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sukuk_purchased_tx_log ON sukuk_purchased_events (tx_hash, log_index);
+DROP INDEX IF EXISTS idx_sukuk_purchased_events_tx_hash;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_redemption_requested_tx_log ON redemption_requested_events (tx_hash, log_index);
+DROP INDEX IF EXISTS idx_redemption_requested_events_tx_hash;
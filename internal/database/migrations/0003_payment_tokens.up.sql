@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS payment_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    address VARCHAR(42) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    name VARCHAR(100),
+    decimals SMALLINT NOT NULL DEFAULT 18,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_tokens_address ON payment_tokens (address);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS kyc_reviews;
+DROP TABLE IF EXISTS investor_profiles;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS investor_profiles (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_address VARCHAR(42) NOT NULL,
+    name VARCHAR(255),
+    email VARCHAR(255),
+    kyc_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    verified_at TIMESTAMPTZ,
+    document_refs TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_investor_profiles_wallet_address ON investor_profiles (wallet_address);
+CREATE INDEX IF NOT EXISTS idx_investor_profiles_kyc_status ON investor_profiles (kyc_status);
+
+CREATE TABLE IF NOT EXISTS kyc_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    investor_profile_id BIGINT NOT NULL,
+    reviewer VARCHAR(100) NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_investor_profiles_reviews FOREIGN KEY (investor_profile_id) REFERENCES investor_profiles (id)
+);
+CREATE INDEX IF NOT EXISTS idx_kyc_reviews_investor_profile_id ON kyc_reviews (investor_profile_id);
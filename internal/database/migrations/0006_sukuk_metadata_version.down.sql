@@ -0,0 +1 @@
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS version;
@@ -0,0 +1 @@
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
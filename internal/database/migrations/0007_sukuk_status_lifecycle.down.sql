@@ -0,0 +1,3 @@
+-- The original free-form values can't be recovered; only the constraint and default are reverted
+ALTER TABLE sukuk_metadata DROP CONSTRAINT IF EXISTS chk_sukuk_metadata_status;
+ALTER TABLE sukuk_metadata ALTER COLUMN status DROP DEFAULT;
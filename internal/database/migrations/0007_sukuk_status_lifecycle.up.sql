@@ -0,0 +1,17 @@
+-- Normalize free-form statuses onto the lifecycle states. Every existing row
+-- is a deployed token, so empty and unrecognised values become active.
+UPDATE sukuk_metadata SET status = CASE lower(trim(coalesce(status, '')))
+    WHEN 'draft' THEN 'draft'
+    WHEN 'paused' THEN 'paused'
+    WHEN 'ditunda' THEN 'paused'
+    WHEN 'matured' THEN 'matured'
+    WHEN 'jatuh tempo' THEN 'matured'
+    WHEN 'selesai' THEN 'matured'
+    WHEN 'suspended' THEN 'suspended'
+    WHEN 'dibekukan' THEN 'suspended'
+    ELSE 'active'
+END;
+
+ALTER TABLE sukuk_metadata ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE sukuk_metadata ADD CONSTRAINT chk_sukuk_metadata_status
+    CHECK (status IN ('draft', 'active', 'paused', 'matured', 'suspended'));
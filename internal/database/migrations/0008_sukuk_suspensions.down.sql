@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sukuk_suspensions;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS sukuk_suspensions (
+    id BIGSERIAL PRIMARY KEY,
+    sukuk_address VARCHAR(42) NOT NULL,
+    reason TEXT,
+    event_id VARCHAR(255) NOT NULL,
+    block_number BIGINT,
+    tx_hash VARCHAR(66),
+    suspended_at TIMESTAMPTZ,
+    resume_event_id VARCHAR(255),
+    resume_tx_hash VARCHAR(66),
+    resumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sukuk_suspensions_sukuk_address ON sukuk_suspensions (sukuk_address);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sukuk_suspensions_event_id ON sukuk_suspensions (event_id);
+CREATE INDEX IF NOT EXISTS idx_sukuk_suspensions_resume_event_id ON sukuk_suspensions (resume_event_id);
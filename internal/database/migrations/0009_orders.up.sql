@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGSERIAL PRIMARY KEY,
+    user_address VARCHAR(42) NOT NULL,
+    sukuk_metadata_id BIGINT NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    fiat_amount DECIMAL(20,2) NOT NULL,
+    token_amount VARCHAR(78) NOT NULL,
+    payment_reference VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'created',
+    expires_at TIMESTAMPTZ NOT NULL,
+    paid_at TIMESTAMPTZ,
+    settled_at TIMESTAMPTZ,
+    settlement_tx_hash VARCHAR(66),
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_orders_user_address ON orders (user_address);
+CREATE INDEX IF NOT EXISTS idx_orders_sukuk_metadata_id ON orders (sukuk_metadata_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_payment_reference ON orders (payment_reference);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status);
+CREATE INDEX IF NOT EXISTS idx_orders_expires_at ON orders (expires_at);
+CREATE INDEX IF NOT EXISTS idx_orders_settlement_tx_hash ON orders (settlement_tx_hash);
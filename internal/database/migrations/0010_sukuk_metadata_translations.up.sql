@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sukuk_metadata_translations (
+    id BIGSERIAL PRIMARY KEY,
+    sukuk_metadata_id BIGINT NOT NULL,
+    locale VARCHAR(8) NOT NULL,
+    field VARCHAR(64) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sukuk_translation_field ON sukuk_metadata_translations (sukuk_metadata_id, locale, field);
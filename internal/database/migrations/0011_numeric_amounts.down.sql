@@ -0,0 +1,10 @@
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_token_amount;
+ALTER TABLE orders ALTER COLUMN token_amount TYPE VARCHAR(78) USING token_amount::text;
+
+ALTER TABLE redemption_requested_events DROP CONSTRAINT IF EXISTS chk_redemption_requested_amounts;
+ALTER TABLE redemption_requested_events
+    ALTER COLUMN amount TYPE VARCHAR(78) USING amount::text,
+    ALTER COLUMN total_supply TYPE VARCHAR(78) USING total_supply::text;
+
+ALTER TABLE sukuk_purchased_events DROP CONSTRAINT IF EXISTS chk_sukuk_purchased_amount;
+ALTER TABLE sukuk_purchased_events ALTER COLUMN amount TYPE VARCHAR(78) USING amount::text;
@@ -0,0 +1,18 @@
+-- Token amounts move from VARCHAR(78) to NUMERIC(78,0) so they sort, sum and compare
+-- as integers. Stray whitespace is trimmed first; any other value that is not a plain
+-- non-negative integer makes the cast fail and the migration roll back untouched.
+UPDATE sukuk_purchased_events SET amount = trim(amount) WHERE amount <> trim(amount);
+ALTER TABLE sukuk_purchased_events ALTER COLUMN amount TYPE NUMERIC(78,0) USING amount::numeric(78,0);
+ALTER TABLE sukuk_purchased_events ADD CONSTRAINT chk_sukuk_purchased_amount CHECK (amount >= 0);
+
+UPDATE redemption_requested_events SET amount = trim(amount), total_supply = trim(total_supply)
+    WHERE amount <> trim(amount) OR total_supply <> trim(total_supply);
+ALTER TABLE redemption_requested_events
+    ALTER COLUMN amount TYPE NUMERIC(78,0) USING amount::numeric(78,0),
+    ALTER COLUMN total_supply TYPE NUMERIC(78,0) USING total_supply::numeric(78,0);
+ALTER TABLE redemption_requested_events ADD CONSTRAINT chk_redemption_requested_amounts
+    CHECK (amount >= 0 AND total_supply >= 0);
+
+UPDATE orders SET token_amount = trim(token_amount) WHERE token_amount <> trim(token_amount);
+ALTER TABLE orders ALTER COLUMN token_amount TYPE NUMERIC(78,0) USING token_amount::numeric(78,0);
+ALTER TABLE orders ADD CONSTRAINT chk_orders_token_amount CHECK (token_amount >= 0);
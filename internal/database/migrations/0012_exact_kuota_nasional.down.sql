@@ -0,0 +1 @@
+ALTER TABLE sukuk_metadata ALTER COLUMN kuota_nasional TYPE DECIMAL(30,2);
@@ -0,0 +1,3 @@
+-- kuota_nasional is the sukuk's max supply in token units. DECIMAL(30,2) rounded away
+-- anything past the second decimal of an 18-decimal token, so widen the scale to 18.
+ALTER TABLE sukuk_metadata ALTER COLUMN kuota_nasional TYPE NUMERIC(78,18);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS referral_attributions;
+DROP TABLE IF EXISTS referral_bindings;
+DROP TABLE IF EXISTS referrals;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS referrals (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    owner_address VARCHAR(42) NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_code ON referrals (code);
+CREATE INDEX IF NOT EXISTS idx_referrals_owner_address ON referrals (owner_address);
+
+-- A wallet binds at most one code
+CREATE TABLE IF NOT EXISTS referral_bindings (
+    id BIGSERIAL PRIMARY KEY,
+    referral_id BIGINT NOT NULL REFERENCES referrals (id),
+    address VARCHAR(42) NOT NULL,
+    bound_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_bindings_address ON referral_bindings (address);
+CREATE INDEX IF NOT EXISTS idx_referral_bindings_referral_id ON referral_bindings (referral_id);
+
+-- A purchase is attributed at most once
+CREATE TABLE IF NOT EXISTS referral_attributions (
+    id BIGSERIAL PRIMARY KEY,
+    referral_id BIGINT NOT NULL REFERENCES referrals (id),
+    buyer_address VARCHAR(42) NOT NULL,
+    purchase_tx_hash VARCHAR(66) NOT NULL,
+    amount NUMERIC(78,0) NOT NULL CHECK (amount >= 0),
+    attributed_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referral_attributions_purchase_tx_hash ON referral_attributions (purchase_tx_hash);
+CREATE INDEX IF NOT EXISTS idx_referral_attributions_referral_id ON referral_attributions (referral_id);
+CREATE INDEX IF NOT EXISTS idx_referral_attributions_buyer_address ON referral_attributions (buyer_address);
@@ -0,0 +1,6 @@
+-- Explicit indexer table per event type, checked before table discovery
+CREATE TABLE IF NOT EXISTS indexer_table_overrides (
+    event_type VARCHAR(64) PRIMARY KEY,
+    table_name VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMPTZ
+);
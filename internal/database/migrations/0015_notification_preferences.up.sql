@@ -0,0 +1,11 @@
+-- Wallets without a row have every notification enabled
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    address VARCHAR(42) PRIMARY KEY,
+    email VARCHAR(255),
+    enable_yield_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    enable_redemption_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    enable_digest BOOLEAN NOT NULL DEFAULT TRUE,
+    locale VARCHAR(8) NOT NULL DEFAULT 'id',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
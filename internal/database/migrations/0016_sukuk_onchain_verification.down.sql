@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_sukuk_metadata_onchain_verified;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS onchain_verified_block;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS onchain_verified;
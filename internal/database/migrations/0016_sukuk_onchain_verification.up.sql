@@ -0,0 +1,4 @@
+-- Set once the sync read the sukuk's token details from its contract
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS onchain_verified BOOLEAN DEFAULT FALSE;
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS onchain_verified_block BIGINT;
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_onchain_verified ON sukuk_metadata (onchain_verified);
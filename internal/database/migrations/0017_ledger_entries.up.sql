@@ -0,0 +1,21 @@
+-- Every value movement is a debit and an equal credit, legs 0 and 1 of the same log
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    account VARCHAR(42) NOT NULL,
+    counter_account VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount NUMERIC(78,0) NOT NULL CHECK (amount >= 0),
+    token VARCHAR(42) NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index BIGINT NOT NULL,
+    leg BIGINT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_entries_tx_log_leg ON ledger_entries (tx_hash, log_index, leg);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries (account);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_sukuk_address ON ledger_entries (sukuk_address);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_event_type ON ledger_entries (event_type);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_timestamp ON ledger_entries (timestamp);
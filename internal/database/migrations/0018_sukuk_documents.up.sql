@@ -0,0 +1,17 @@
+-- Documents attached to a sukuk; each upload of a type is a new version and only the
+-- latest is active, while earlier versions keep their record
+CREATE TABLE IF NOT EXISTS sukuk_documents (
+    id BIGSERIAL PRIMARY KEY,
+    sukuk_id BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    file_url VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL,
+    uploaded_by VARCHAR(100),
+    uploaded_at TIMESTAMPTZ NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sukuk_documents_version ON sukuk_documents (sukuk_id, type, version);
+CREATE INDEX IF NOT EXISTS idx_sukuk_documents_active ON sukuk_documents (active);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sukuk_metadata_imbal_hasil_bps;
+DROP INDEX IF EXISTS idx_sukuk_metadata_tenor_months;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS imbal_hasil_bps;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS tenor_months;
@@ -0,0 +1,27 @@
+-- Tenor and imbal hasil parsed from their labels so the list sorts and filters in SQL.
+-- The model sets them on save; the patterns match models.ParseTenorMonths and ParseImbalHasilBps
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS tenor_months INTEGER;
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS imbal_hasil_bps INTEGER;
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_tenor_months ON sukuk_metadata (tenor_months);
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_imbal_hasil_bps ON sukuk_metadata (imbal_hasil_bps);
+
+-- A bare number is in years, like "5 Tahun"
+UPDATE sukuk_metadata AS s
+SET tenor_months = ROUND(parsed.amount * CASE WHEN parsed.unit IN ('m', 'mo', 'bln', 'bulan', 'month', 'months') THEN 1 ELSE 12 END)
+FROM (
+    SELECT id,
+           REPLACE(match[1], ',', '.')::numeric AS amount,
+           match[2] AS unit
+    FROM (
+        SELECT id, regexp_match(LOWER(tenor), '^\s*(\d+(?:[.,]\d+)?)\s*([a-z]*)') AS match
+        FROM sukuk_metadata
+    ) matched
+    WHERE match IS NOT NULL
+) AS parsed
+WHERE s.id = parsed.id
+  AND parsed.unit IN ('', 't', 'th', 'thn', 'tahun', 'y', 'yr', 'yrs', 'year', 'years',
+                      'm', 'mo', 'bln', 'bulan', 'month', 'months');
+
+UPDATE sukuk_metadata
+SET imbal_hasil_bps = ROUND(REPLACE(substring(imbal_hasil FROM '(\d+(?:[.,]\d+)?)'), ',', '.')::numeric * 100)
+WHERE imbal_hasil ~ '\d';
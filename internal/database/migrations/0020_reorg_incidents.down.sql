@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS reorg_incidents;
+DROP INDEX IF EXISTS idx_ledger_entries_status;
+DROP INDEX IF EXISTS idx_redemption_requested_events_status;
+DROP INDEX IF EXISTS idx_sukuk_purchased_events_status;
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS status;
+ALTER TABLE redemption_requested_events DROP COLUMN IF EXISTS status;
+ALTER TABLE sukuk_purchased_events DROP COLUMN IF EXISTS status;
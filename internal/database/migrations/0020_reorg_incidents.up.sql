@@ -0,0 +1,22 @@
+-- Derived rows whose transaction a chain reorg removed are kept, marked orphaned
+ALTER TABLE sukuk_purchased_events ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'confirmed';
+ALTER TABLE redemption_requested_events ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'confirmed';
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'confirmed';
+CREATE INDEX IF NOT EXISTS idx_sukuk_purchased_events_status ON sukuk_purchased_events (status);
+CREATE INDEX IF NOT EXISTS idx_redemption_requested_events_status ON redemption_requested_events (status);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_status ON ledger_entries (status);
+
+-- One row per reconciliation run that orphaned derived rows of an indexer event type
+CREATE TABLE IF NOT EXISTS reorg_incidents (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    indexer_table VARCHAR(128) NOT NULL,
+    reorg_table VARCHAR(128) NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    tx_hashes TEXT NOT NULL,
+    affected TEXT NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_reorg_incidents_detected_at ON reorg_incidents (detected_at);
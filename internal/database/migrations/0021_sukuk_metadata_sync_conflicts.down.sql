@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sukuk_metadata_conflicts;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS last_synced_values;
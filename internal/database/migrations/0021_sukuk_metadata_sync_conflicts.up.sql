@@ -0,0 +1,20 @@
+-- Chain values the metadata sync last wrote, so admin edits can be told apart from them
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS last_synced_values TEXT;
+
+-- Chain values that differ from an admin edit, kept until an admin picks one
+CREATE TABLE IF NOT EXISTS sukuk_metadata_conflicts (
+    id BIGSERIAL PRIMARY KEY,
+    sukuk_metadata_id BIGINT NOT NULL,
+    field VARCHAR(32) NOT NULL,
+    chain_value TEXT NOT NULL,
+    manual_value TEXT NOT NULL,
+    tx_hash VARCHAR(66),
+    block_number BIGINT,
+    status VARCHAR(10) NOT NULL DEFAULT 'open',
+    resolution VARCHAR(10),
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_conflicts_sukuk_metadata_id ON sukuk_metadata_conflicts (sukuk_metadata_id);
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_conflicts_status ON sukuk_metadata_conflicts (status);
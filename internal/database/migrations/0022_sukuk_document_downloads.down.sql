@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sukuk_document_downloads;
+ALTER TABLE sukuk_documents DROP COLUMN IF EXISTS download_count;
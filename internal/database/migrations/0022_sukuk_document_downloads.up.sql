@@ -0,0 +1,13 @@
+-- Downloads of sukuk documents through signed links; the counter on the document keeps
+-- admin listings cheap and the log keeps who asked for a link
+ALTER TABLE sukuk_documents ADD COLUMN IF NOT EXISTS download_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS sukuk_document_downloads (
+    id BIGSERIAL PRIMARY KEY,
+    document_id BIGINT NOT NULL,
+    sukuk_id BIGINT NOT NULL,
+    address VARCHAR(42),
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_sukuk_document_downloads_document_id ON sukuk_document_downloads (document_id);
+CREATE INDEX IF NOT EXISTS idx_sukuk_document_downloads_address ON sukuk_document_downloads (address);
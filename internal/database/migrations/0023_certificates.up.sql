@@ -0,0 +1,18 @@
+-- Investment certificates keep the values printed on the PDF, so verification does not
+-- depend on the investor's current balance
+CREATE TABLE IF NOT EXISTS certificates (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    investor_address VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    sukuk_code VARCHAR(20),
+    sukuk_title VARCHAR(100),
+    issuer_address VARCHAR(42),
+    balance NUMERIC(78,0) NOT NULL CHECK (balance > 0),
+    total_supply NUMERIC(78,0) NOT NULL DEFAULT 0, -- 0 when the supply was unknown
+    share_percentage VARCHAR(12),
+    issued_at TIMESTAMPTZ NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_certificates_code ON certificates (code);
+CREATE INDEX IF NOT EXISTS idx_certificates_investor_address ON certificates (investor_address);
+CREATE INDEX IF NOT EXISTS idx_certificates_sukuk_address ON certificates (sukuk_address);
@@ -0,0 +1 @@
+ALTER TABLE certificates DROP COLUMN IF EXISTS supply_source;
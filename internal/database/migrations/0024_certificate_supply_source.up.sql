@@ -0,0 +1,2 @@
+-- Where the total supply printed on a certificate was read from
+ALTER TABLE certificates ADD COLUMN IF NOT EXISTS supply_source VARCHAR(20) NOT NULL DEFAULT 'none';
@@ -0,0 +1,11 @@
+-- Hourly request, error and response byte counts per API key fingerprint, for support and billing
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    id BIGSERIAL PRIMARY KEY,
+    key_id VARCHAR(12) NOT NULL,
+    hour TIMESTAMPTZ NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    bytes_out BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_key_usage_key_hour ON api_key_usage (key_id, hour);
@@ -0,0 +1,2 @@
+-- Rounded amounts keep their whole rupiah value; only the constraint is reverted
+ALTER TABLE sukuk_metadata DROP CONSTRAINT IF EXISTS chk_sukuk_metadata_whole_rupiah;
@@ -0,0 +1,14 @@
+-- minimum_pembelian and maksimum_pembelian are whole rupiah; admin input is parsed with
+-- models.ParseRupiah, which refuses cents. Amounts saved with stray cents are rounded and
+-- negative ones cleared, which the readiness checklist then reports for minimum_pembelian.
+UPDATE sukuk_metadata SET minimum_pembelian = ROUND(minimum_pembelian)
+    WHERE minimum_pembelian <> ROUND(minimum_pembelian);
+UPDATE sukuk_metadata SET maksimum_pembelian = ROUND(maksimum_pembelian)
+    WHERE maksimum_pembelian <> ROUND(maksimum_pembelian);
+UPDATE sukuk_metadata SET minimum_pembelian = 0 WHERE minimum_pembelian < 0;
+UPDATE sukuk_metadata SET maksimum_pembelian = 0 WHERE maksimum_pembelian < 0;
+
+ALTER TABLE sukuk_metadata ADD CONSTRAINT chk_sukuk_metadata_whole_rupiah CHECK (
+    minimum_pembelian >= 0 AND minimum_pembelian = ROUND(minimum_pembelian)
+    AND maksimum_pembelian >= 0 AND maksimum_pembelian = ROUND(maksimum_pembelian)
+);
@@ -0,0 +1,17 @@
+-- Investor acknowledgements of a sukuk's product risk, one per prospectus version, required
+-- before an order can be created
+CREATE TABLE IF NOT EXISTS suitability_acknowledgements (
+    id BIGSERIAL PRIMARY KEY,
+    user_address VARCHAR(42) NOT NULL,
+    sukuk_metadata_id BIGINT NOT NULL,
+    document_id BIGINT,
+    document_version BIGINT NOT NULL DEFAULT 0,
+    method VARCHAR(16) NOT NULL,
+    message TEXT,
+    signature VARCHAR(132),
+    consent_token VARCHAR(128),
+    acknowledged_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suitability_acknowledgements_version ON suitability_acknowledgements (user_address, sukuk_metadata_id, document_version);
@@ -0,0 +1,25 @@
+-- Issuer payouts of approved redemptions, from instruction to confirmed receipt, one per
+-- redemption request
+CREATE TABLE IF NOT EXISTS payout_instructions (
+    id BIGSERIAL PRIMARY KEY,
+    redemption_request_id VARCHAR(100) NOT NULL,
+    user_address VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    amount NUMERIC(78,0) NOT NULL,
+    payment_token VARCHAR(42),
+    bank_reference VARCHAR(100),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    tx_hash VARCHAR(66),
+    proof_url VARCHAR(255),
+    notes TEXT,
+    created_by VARCHAR(100),
+    executed_by VARCHAR(100),
+    executed_at TIMESTAMPTZ,
+    confirmed_by VARCHAR(100),
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_instructions_redemption_request_id ON payout_instructions (redemption_request_id);
+CREATE INDEX IF NOT EXISTS idx_payout_instructions_sukuk_address ON payout_instructions (sukuk_address);
+CREATE INDEX IF NOT EXISTS idx_payout_instructions_status ON payout_instructions (status);
@@ -0,0 +1,20 @@
+-- Read model of the activity feed, copied from the indexer event tables by the activity
+-- projector, one row per event log
+CREATE TABLE IF NOT EXISTS activity_projections (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    address VARCHAR(42) NOT NULL,
+    sukuk_address VARCHAR(42) NOT NULL,
+    payment_token VARCHAR(42),
+    amount NUMERIC(78,0) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index BIGINT NOT NULL,
+    block_number BIGINT NOT NULL,
+    timestamp BIGINT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'confirmed'
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_activity_projections_tx_log ON activity_projections (tx_hash, log_index);
+CREATE INDEX IF NOT EXISTS idx_activity_projections_feed ON activity_projections (timestamp, event_id);
+CREATE INDEX IF NOT EXISTS idx_activity_projections_address_time ON activity_projections (address, timestamp);
+CREATE INDEX IF NOT EXISTS idx_activity_projections_sukuk_time ON activity_projections (sukuk_address, timestamp);
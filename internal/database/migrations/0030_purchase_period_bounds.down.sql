@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_sukuk_metadata_purchase_period_start;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS purchase_period_end;
+ALTER TABLE sukuk_metadata DROP COLUMN IF EXISTS purchase_period_start;
@@ -0,0 +1,49 @@
+-- Purchase period parsed from periode_pembelian in Jakarta time, served as a structured
+-- period beside the label. The model sets them on save; the pattern and month names match
+-- models.ParsePurchasePeriod, and the end is exclusive, midnight after the last day
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS purchase_period_start TIMESTAMPTZ;
+ALTER TABLE sukuk_metadata ADD COLUMN IF NOT EXISTS purchase_period_end TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_sukuk_metadata_purchase_period_start ON sukuk_metadata (purchase_period_start);
+
+WITH months (prefix, month) AS (
+    VALUES ('jan', 1), ('feb', 2), ('peb', 2), ('mar', 3), ('apr', 4), ('mei', 5), ('may', 5),
+           ('jun', 6), ('jul', 7), ('agu', 8), ('agt', 8), ('aug', 8), ('sep', 9), ('okt', 10),
+           ('oct', 10), ('nov', 11), ('nop', 11), ('des', 12), ('dec', 12)
+),
+matched AS (
+    SELECT id, regexp_match(BTRIM(periode_pembelian),
+        '^(\d{1,2})\s+([A-Za-z]+)\.?(?:\s+(\d{4}))?\s*[-–—]\s*(\d{1,2})\s+([A-Za-z]+)\.?\s+(\d{4})$') AS match
+    FROM sukuk_metadata
+    WHERE periode_pembelian IS NOT NULL
+),
+parts AS (
+    -- A start without a year takes the end's year, or the year before when its month comes later
+    SELECT matched.id,
+           match[1]::int AS start_day,
+           start_month.month AS start_month,
+           COALESCE(match[3]::int, CASE WHEN start_month.month > end_month.month THEN match[6]::int - 1 ELSE match[6]::int END) AS start_year,
+           match[4]::int AS end_day,
+           end_month.month AS end_month,
+           match[6]::int AS end_year
+    FROM matched
+    JOIN months start_month ON start_month.prefix = LOWER(LEFT(match[2], 3)) AND LENGTH(match[2]) >= 3
+    JOIN months end_month ON end_month.prefix = LOWER(LEFT(match[5], 3)) AND LENGTH(match[5]) >= 3
+    WHERE match IS NOT NULL
+),
+dated AS (
+    -- Days past the end of their month, such as 31 Feb, don't parse
+    SELECT id,
+           CASE WHEN start_day BETWEEN 1 AND EXTRACT(DAY FROM make_date(start_year, start_month, 1) + INTERVAL '1 month' - INTERVAL '1 day')
+                THEN make_date(start_year, start_month, start_day) END AS start_date,
+           CASE WHEN end_day BETWEEN 1 AND EXTRACT(DAY FROM make_date(end_year, end_month, 1) + INTERVAL '1 month' - INTERVAL '1 day')
+                THEN make_date(end_year, end_month, end_day) END AS end_date
+    FROM parts
+)
+UPDATE sukuk_metadata AS s
+SET purchase_period_start = dated.start_date::timestamp AT TIME ZONE 'Asia/Jakarta',
+    purchase_period_end = (dated.end_date + 1)::timestamp AT TIME ZONE 'Asia/Jakarta'
+FROM dated
+WHERE s.id = dated.id
+  AND dated.start_date IS NOT NULL
+  AND dated.end_date IS NOT NULL
+  AND dated.start_date <= dated.end_date;
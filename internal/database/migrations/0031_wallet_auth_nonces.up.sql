@@ -0,0 +1,10 @@
+-- Single-use nonces of wallet sign-in messages, consumed by POST /auth/verify
+CREATE TABLE IF NOT EXISTS wallet_auth_nonces (
+    nonce VARCHAR(32) PRIMARY KEY,
+    address VARCHAR(42) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_wallet_auth_nonces_address ON wallet_auth_nonces (address);
+CREATE INDEX IF NOT EXISTS idx_wallet_auth_nonces_expires_at ON wallet_auth_nonces (expires_at);
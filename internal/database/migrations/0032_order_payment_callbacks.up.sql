@@ -0,0 +1,10 @@
+-- Payment callbacks applied to orders, one per payment reference and status so replays are no-ops
+CREATE TABLE IF NOT EXISTS order_payment_callbacks (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    payment_reference VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_order_payment_callbacks_order_id ON order_payment_callbacks (order_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_payment_callbacks_reference_status ON order_payment_callbacks (payment_reference, status);
@@ -0,0 +1 @@
+ALTER TABLE sukuk_suspensions DROP COLUMN IF EXISTS prior_status;
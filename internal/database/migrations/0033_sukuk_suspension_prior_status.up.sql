@@ -0,0 +1,2 @@
+-- Status a sukuk had before its suspension, restored when the suspension is resumed
+ALTER TABLE sukuk_suspensions ADD COLUMN IF NOT EXISTS prior_status VARCHAR(20);
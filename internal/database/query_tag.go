@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sukuk-be/internal/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueryTagPlugin prefixes every query issued on behalf of a request with an SQL comment
+// naming its route and request ID, e.g. /* route=/portfolio/:address rid=abc123 */, so a
+// slow query in pg_stat_activity can be traced to the endpoint that issued it. Queries
+// whose context carries neither, such as those of background services, are left alone
+type QueryTagPlugin struct{}
+
+// Name returns the plugin name
+func (QueryTagPlugin) Name() string {
+	return "query_tag"
+}
+
+// Initialize registers the tagging callbacks ahead of the statements gorm builds and runs
+func (QueryTagPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("query_tag:create", tagQuery("INSERT")),
+		callbacks.Query().Before("gorm:query").Register("query_tag:query", tagQuery("SELECT")),
+		callbacks.Update().Before("gorm:update").Register("query_tag:update", tagQuery("UPDATE")),
+		callbacks.Delete().Before("gorm:delete").Register("query_tag:delete", tagQuery("DELETE")),
+		callbacks.Row().Before("gorm:row").Register("query_tag:row", tagQuery("SELECT")),
+		callbacks.Raw().Before("gorm:raw").Register("query_tag:raw", tagQuery("")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tagQuery returns a callback prefixing the statement with the tag comment. Raw SQL is
+// already written when the callback runs and is prefixed in place; built statements get
+// the comment ahead of their leading clause, which gorm writes later
+func tagQuery(leadingClause string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		comment := queryTagComment(db.Statement.Context)
+		if comment == "" {
+			return
+		}
+
+		stmt := db.Statement
+		if stmt.SQL.Len() > 0 {
+			sql := stmt.SQL.String()
+			if strings.HasPrefix(sql, "/* ") {
+				return // Tagged by an earlier run of the same statement
+			}
+			stmt.SQL.Reset()
+			stmt.SQL.WriteString(comment + " " + sql)
+			return
+		}
+		if leadingClause == "" {
+			return
+		}
+		c := stmt.Clauses[leadingClause]
+		c.BeforeExpression = clause.Expr{SQL: comment}
+		stmt.Clauses[leadingClause] = c
+	}
+}
+
+// queryTagComment builds the tag comment of ctx, or "" when it carries no route or request ID
+func queryTagComment(ctx context.Context) string {
+	var tags []string
+	if route := logger.RouteFromContext(ctx); route != "" {
+		tags = append(tags, "route="+escapeQueryTag(route))
+	}
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		tags = append(tags, "rid="+escapeQueryTag(requestID))
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return "/* " + strings.Join(tags, " ") + " */"
+}
+
+// escapeQueryTag percent-encodes every byte outside the characters routes and request IDs
+// are made of, so a value can neither end the comment nor add a bind placeholder
+func escapeQueryTag(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9',
+			b == '/', b == ':', b == '_', b == '-', b == '.':
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/logger"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type queryTagRow struct {
+	ID   uint
+	Name string
+}
+
+// openQueryTagDB opens a dry-run connection with the plugin, never dialling Postgres, and
+// collects the SQL of every statement gorm builds in order
+func openQueryTagDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("Failed to open gorm: %v", err)
+	}
+	if err := db.Use(QueryTagPlugin{}); err != nil {
+		t.Fatalf("Failed to register plugin: %v", err)
+	}
+
+	var statements []string
+	capture := func(db *gorm.DB) { statements = append(statements, db.Statement.SQL.String()) }
+	callbacks := db.Callback()
+	callbacks.Create().After("gorm:create").Register("test:capture_create", capture)
+	callbacks.Query().After("gorm:query").Register("test:capture_query", capture)
+	callbacks.Update().After("gorm:update").Register("test:capture_update", capture)
+	callbacks.Delete().After("gorm:delete").Register("test:capture_delete", capture)
+	callbacks.Raw().After("gorm:raw").Register("test:capture_raw", capture)
+	return db, &statements
+}
+
+func TestQueryTagPluginTagsRequestQueries(t *testing.T) {
+	db, statements := openQueryTagDB(t)
+	ctx := logger.ContextWithRoute(logger.ContextWithRequestID(context.Background(), "abc123"), "/api/v1/portfolio/:address")
+	tx := db.WithContext(ctx)
+
+	var rows []queryTagRow
+	tx.Where("name = ?", "x").Find(&rows)
+	tx.Raw("SELECT * FROM indexer_table WHERE id = ?", 1).Find(&rows)
+	tx.Create(&queryTagRow{Name: "x"})
+	tx.Model(&queryTagRow{}).Where("id = ?", 1).Update("name", "y")
+	tx.Where("id = ?", 1).Delete(&queryTagRow{})
+	tx.Exec("DELETE FROM indexer_table WHERE id = ?", 1)
+
+	const want = "/* route=/api/v1/portfolio/:address rid=abc123 */ "
+	if len(*statements) != 6 {
+		t.Fatalf("Expected 6 statements, got %v", *statements)
+	}
+	for _, sql := range *statements {
+		if !strings.HasPrefix(sql, want) || strings.Count(sql, "/*") != 1 {
+			t.Errorf("Expected one leading tag comment, got %q", sql)
+		}
+	}
+}
+
+func TestQueryTagPluginLeavesBackgroundQueries(t *testing.T) {
+	db, statements := openQueryTagDB(t)
+
+	var rows []queryTagRow
+	db.WithContext(context.Background()).Find(&rows)
+	db.Raw("SELECT 1").Find(&rows)
+
+	for _, sql := range *statements {
+		if strings.Contains(sql, "/*") {
+			t.Errorf("Expected no comment outside a request, got %q", sql)
+		}
+	}
+}
+
+func TestQueryTagPluginEscapesValues(t *testing.T) {
+	db, statements := openQueryTagDB(t)
+	ctx := logger.ContextWithRoute(context.Background(), "/files/*filepath */ DROP TABLE x; -- ?")
+
+	var rows []queryTagRow
+	db.WithContext(ctx).Where("id = ?", 7).Find(&rows)
+
+	sql := (*statements)[0]
+	const want = "/* route=/files/%2Afilepath%20%2A/%20DROP%20TABLE%20x%3B%20--%20%3F */ SELECT"
+	if !strings.HasPrefix(sql, want) {
+		t.Errorf("Expected the route percent-encoded, got %q", sql)
+	}
+	if strings.Count(sql, "*/") != 1 || !strings.Contains(sql, "$1") {
+		t.Errorf("Expected the comment closed once and the bind variable kept, got %q", sql)
+	}
+}
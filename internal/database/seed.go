@@ -0,0 +1,210 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SeedProfile selects how much sample data SeedData creates
+type SeedProfile string
+
+const (
+	SeedProfileMinimal  SeedProfile = "minimal"   // One payment token and one ready sukuk, no indexer events
+	SeedProfileDemo     SeedProfile = "demo"      // A few sukuk and investors with indexer events for every endpoint
+	SeedProfileLoadTest SeedProfile = "load-test" // Hundreds of sukuk and thousands of indexer events
+)
+
+// ErrWipeNotAllowed is returned when a wipe is attempted outside development and test
+var ErrWipeNotAllowed = errors.New("seed data can only be wiped in development or test")
+
+// ErrWipeNotConfirmed is returned when a wipe is attempted without confirming it
+var ErrWipeNotConfirmed = errors.New("wiping seed data must be confirmed")
+
+// wipeEnvironments are the environments whose seed data may be wiped
+var wipeEnvironments = map[string]bool{"development": true, "test": true}
+
+// seedSpec sizes the data set of a profile
+type seedSpec struct {
+	sukuk         int
+	investors     int
+	purchasesEach int  // Purchases per sukuk, spread across investors
+	indexerEvents bool // Whether to seed the synthetic indexer tables
+}
+
+var seedSpecs = map[SeedProfile]seedSpec{
+	SeedProfileMinimal:  {sukuk: 1, investors: 0},
+	SeedProfileDemo:     {sukuk: 4, investors: 3, purchasesEach: 6, indexerEvents: true},
+	SeedProfileLoadTest: {sukuk: 200, investors: 100, purchasesEach: 50, indexerEvents: true},
+}
+
+// SeedProfiles lists the available seed profiles
+func SeedProfiles() []SeedProfile {
+	return []SeedProfile{SeedProfileMinimal, SeedProfileDemo, SeedProfileLoadTest}
+}
+
+// ParseSeedProfile validates a profile name
+func ParseSeedProfile(name string) (SeedProfile, error) {
+	profile := SeedProfile(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := seedSpecs[profile]; !ok {
+		return "", fmt.Errorf("unknown seed profile %q (expected minimal, demo or load-test)", name)
+	}
+	return profile, nil
+}
+
+// seedBaseTime anchors every seeded timestamp so repeated runs produce identical rows
+var seedBaseTime = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// seedAddress builds a deterministic, recognisable address for seeded entities
+// kind separates sukuk, investors and tokens so their ranges never overlap
+func seedAddress(kind, index int) string {
+	return fmt.Sprintf("0x5eed%04x%032x", kind, index+1)
+}
+
+const (
+	seedKindSukuk    = 1
+	seedKindInvestor = 2
+	seedKindToken    = 3
+	seedKindVault    = 4
+)
+
+// seedPaymentTokens are registered by every profile
+var seedPaymentTokens = []models.PaymentToken{
+	{Address: seedAddress(seedKindToken, 0), Symbol: "IDRX", Name: "IDRX", Decimals: 2},
+	{Address: seedAddress(seedKindToken, 1), Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+}
+
+// SeedData creates the sample data of a profile. It is idempotent: rows are matched on
+// their natural keys (token and wallet addresses, sukuk contract address, indexer event id),
+// so running it again only fills in what is missing
+func SeedData(db *gorm.DB, profile SeedProfile) error {
+	spec, ok := seedSpecs[profile]
+	if !ok {
+		return fmt.Errorf("unknown seed profile %q", profile)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, token := range seedPaymentTokens {
+			if err := tx.Where(models.PaymentToken{Address: token.Address}).FirstOrCreate(&token).Error; err != nil {
+				return fmt.Errorf("failed to seed payment token %s: %w", token.Symbol, err)
+			}
+		}
+
+		for _, investor := range seedInvestors(spec) {
+			if err := tx.Where(models.InvestorProfile{WalletAddress: investor.WalletAddress}).FirstOrCreate(&investor).Error; err != nil {
+				return fmt.Errorf("failed to seed investor %s: %w", investor.WalletAddress, err)
+			}
+		}
+
+		for _, sukuk := range seedSukukMetadata(spec) {
+			if err := tx.Where(models.SukukMetadata{ContractAddress: sukuk.ContractAddress}).FirstOrCreate(&sukuk).Error; err != nil {
+				return fmt.Errorf("failed to seed sukuk metadata %s: %w", sukuk.SukukCode, err)
+			}
+		}
+
+		if spec.indexerEvents {
+			if err := seedIndexerEvents(tx, buildSeedEvents(spec)); err != nil {
+				return err
+			}
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"profile":   profile,
+			"sukuk":     spec.sukuk,
+			"investors": spec.investors,
+		}).Info("Seed data applied")
+		return nil
+	})
+}
+
+// seedTables are the application tables filled by SeedData, in truncation order
+var seedTables = []string{"kyc_reviews", "investor_profiles", "sukuk_suspensions", "sukuk_metadata", "payment_tokens"}
+
+// WipeSeedData empties the tables SeedData writes to and drops the synthetic indexer tables
+// It only runs in development or test, and only when the caller confirmed it
+func WipeSeedData(db *gorm.DB, environment string, confirmed bool) error {
+	if !wipeEnvironments[strings.ToLower(strings.TrimSpace(environment))] {
+		return fmt.Errorf("%w, not %q", ErrWipeNotAllowed, environment)
+	}
+	if !confirmed {
+		return ErrWipeNotConfirmed
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("TRUNCATE TABLE " + strings.Join(seedTables, ", ") + " RESTART IDENTITY CASCADE").Error; err != nil {
+			return fmt.Errorf("failed to truncate seed tables: %w", err)
+		}
+		for _, table := range seedIndexerTables {
+			if err := tx.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS "%s"`, SyntheticIndexerTableName(table.event))).Error; err != nil {
+				return fmt.Errorf("failed to drop %s: %w", table.event, err)
+			}
+		}
+
+		logger.WithField("tables", seedTables).Warn("Seed tables wiped")
+		return nil
+	})
+}
+
+// seedInvestors builds the investor profiles of a profile, cycling through KYC states
+func seedInvestors(spec seedSpec) []models.InvestorProfile {
+	statuses := []models.KYCStatus{models.KYCStatusVerified, models.KYCStatusPending, models.KYCStatusRejected}
+
+	investors := make([]models.InvestorProfile, spec.investors)
+	for i := range investors {
+		investors[i] = models.InvestorProfile{
+			WalletAddress: seedAddress(seedKindInvestor, i),
+			Name:          fmt.Sprintf("Demo Investor %d", i+1),
+			Email:         fmt.Sprintf("investor%d@example.com", i+1),
+			KYCStatus:     statuses[i%len(statuses)],
+			DocumentRefs:  []string{},
+		}
+		if investors[i].KYCStatus == models.KYCStatusVerified {
+			verifiedAt := seedBaseTime
+			investors[i].VerifiedAt = &verifiedAt
+		}
+	}
+	return investors
+}
+
+// seedSukukMetadata builds ready, active sukuk metadata rows of a profile
+func seedSukukMetadata(spec seedSpec) []models.SukukMetadata {
+	sukuk := make([]models.SukukMetadata, spec.sukuk)
+	for i := range sukuk {
+		tenorYears := 2 + i%4
+		sukuk[i] = models.SukukMetadata{
+			ContractAddress:   seedAddress(seedKindSukuk, i),
+			TokenID:           int64(i + 1),
+			OwnerAddress:      seedAddress(seedKindInvestor, 1000),
+			TransactionHash:   seedTxHash(seedKindSukuk, i),
+			BlockNumber:       int64(1000 + i),
+			SukukCode:         fmt.Sprintf("SR%03d-T%d", 22+i, tenorYears),
+			SukukTitle:        fmt.Sprintf("Sukuk Ritel Seri %03d", 22+i),
+			SukukDeskripsi:    "Seeded sukuk for local development",
+			Status:            models.SukukStatusActive,
+			Tenor:             fmt.Sprintf("%d Tahun", tenorYears),
+			ImbalHasil:        fmt.Sprintf("%.2f%% / Tahun", 5.5+float64(i%5)*0.25),
+			PeriodePembelian:  "1 Jun - 30 Jun 2025",
+			JatuhTempo:        seedBaseTime.AddDate(tenorYears, 0, 0),
+			KuotaNasional:     "7000000000000",
+			PenerimaanKupon:   "Bulanan",
+			MinimumPembelian:  1000000,
+			TanggalBayarKupon: "10 Setiap Bulan",
+			MaksimumPembelian: 10000000000,
+			KuponPertama:      seedBaseTime.AddDate(0, 2, 10),
+			TipeKupon:         "Fixed Rate",
+			MetadataReady:     true,
+		}
+	}
+	return sukuk
+}
+
+// seedTxHash builds a deterministic transaction hash
+func seedTxHash(kind, index int) string {
+	return fmt.Sprintf("0x5eed%04x%056x", kind, index+1)
+}
@@ -0,0 +1,232 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedIndexerPrefix is the hash prefix of the synthetic indexer tables. Table discovery
+// expects Ponder's "<hex>__<event>" names, and "5eed" keeps them recognisable
+const seedIndexerPrefix = "5eed"
+
+// seedIndexerTable describes one synthetic Ponder event table
+type seedIndexerTable struct {
+	event   string
+	columns string
+}
+
+// seedIndexerTables mirror the columns the indexer queries read. Both yield distribution
+// names are created because the queries look up each of them
+var seedIndexerTables = []seedIndexerTable{
+	{"sukuk_purchase", `buyer TEXT, sukuk_address TEXT, payment_token TEXT, amount NUMERIC(78,0)`},
+	{"redemption_request", `"user" TEXT, sukuk_address TEXT, amount NUMERIC(78,0), payment_token TEXT, total_supply NUMERIC(78,0)`},
+	{"redemption_approval", `"user" TEXT, sukuk_address TEXT, amount NUMERIC(78,0), total_supply NUMERIC(78,0)`},
+	{"yield_distributed", `sukuk_address TEXT, distribution_id BIGINT, payment_token TEXT, amount NUMERIC(78,0)`},
+	{"yield_distribution", `sukuk_address TEXT, distribution_id BIGINT, payment_token TEXT, amount NUMERIC(78,0)`},
+	{"yield_claim", `"user" TEXT, sukuk_address TEXT, distribution_id BIGINT, amount NUMERIC(78,0)`},
+	{"holder_update", `sukuk_address TEXT, holder TEXT, new_balance NUMERIC(78,0)`},
+	{"snapshot_taken", `sukuk_address TEXT, snapshot_id BIGINT, total_supply NUMERIC(78,0), holder_count BIGINT, eligible_count BIGINT`},
+	{"yield_deposit", `sukuk_address TEXT, depositor TEXT, payment_token TEXT, amount NUMERIC(78,0)`},
+	{"vault_update", `sukuk_address TEXT, old_vault TEXT, new_vault TEXT`},
+}
+
+// SyntheticIndexerTableName returns the synthetic table name for an event, shared by the
+// seed data and the dev event injector
+func SyntheticIndexerTableName(event string) string {
+	return seedIndexerPrefix + "__" + event
+}
+
+// SyntheticIndexerTableNames lists every synthetic table
+func SyntheticIndexerTableNames() []string {
+	names := make([]string, len(seedIndexerTables))
+	for i, table := range seedIndexerTables {
+		names[i] = SyntheticIndexerTableName(table.event)
+	}
+	return names
+}
+
+// CreateSyntheticIndexerTables creates the synthetic tables that don't exist yet
+func CreateSyntheticIndexerTables(tx *gorm.DB) error {
+	for _, table := range seedIndexerTables {
+		name := SyntheticIndexerTableName(table.event)
+		ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (id TEXT PRIMARY KEY, %s, block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`, name, table.columns)
+		if err := tx.Exec(ddl).Error; err != nil {
+			return fmt.Errorf("failed to create %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// seedEvents holds the synthetic indexer rows keyed by event
+type seedEvents map[string][]map[string]interface{}
+
+// seedEventBuilder assigns each event a unique transaction, block and timestamp
+type seedEventBuilder struct {
+	events seedEvents
+	seq    int
+}
+
+func (b *seedEventBuilder) add(event string, fields map[string]interface{}) {
+	txHash := seedTxHash(0x100, b.seq)
+	fields["id"] = txHash + "-0"
+	fields["tx_hash"] = txHash
+	fields["block_number"] = int64(2000 + b.seq)
+	fields["timestamp"] = seedBaseTime.Add(time.Duration(b.seq) * time.Hour).Unix()
+	b.seq++
+
+	b.events[event] = append(b.events[event], fields)
+}
+
+// tokens converts whole sukuk tokens to an 18-decimal wei string
+func tokens(whole int64) string {
+	if whole == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%d000000000000000000", whole)
+}
+
+// buildSeedEvents generates purchases, redemptions, yields, holder balances, a snapshot and vault funding
+// for every seeded sukuk. The output depends only on the spec, so reruns hit the same ids
+func buildSeedEvents(spec seedSpec) seedEvents {
+	b := &seedEventBuilder{events: seedEvents{}}
+	if spec.investors == 0 {
+		return b.events
+	}
+
+	for s := 0; s < spec.sukuk; s++ {
+		sukukAddress := seedAddress(seedKindSukuk, s)
+		paymentToken := seedPaymentTokens[s%len(seedPaymentTokens)].Address
+		balances := make(map[int]int64)
+		var supply int64
+
+		for k := 0; k < spec.purchasesEach; k++ {
+			investor := (s + k) % spec.investors
+			buyer := seedAddress(seedKindInvestor, investor)
+			amount := int64(k%5+1) * 100
+
+			b.add("sukuk_purchase", map[string]interface{}{
+				"buyer":         buyer,
+				"sukuk_address": sukukAddress,
+				"payment_token": paymentToken,
+				"amount":        tokens(amount),
+			})
+			balances[investor] += amount
+			supply += amount
+
+			// Every third purchase is partly redeemed, and every other redemption approved
+			if k%3 == 2 {
+				redeemed := amount / 2
+				b.add("redemption_request", map[string]interface{}{
+					"user":          buyer,
+					"sukuk_address": sukukAddress,
+					"amount":        tokens(redeemed),
+					"payment_token": paymentToken,
+					"total_supply":  tokens(supply),
+				})
+				if k%6 == 5 {
+					b.add("redemption_approval", map[string]interface{}{
+						"user":          buyer,
+						"sukuk_address": sukukAddress,
+						"amount":        tokens(redeemed),
+						"total_supply":  tokens(supply),
+					})
+				}
+				balances[investor] -= redeemed
+				supply -= redeemed
+			}
+		}
+
+		holders := 0
+		for investor := 0; investor < spec.investors; investor++ {
+			balance, ok := balances[investor]
+			if !ok {
+				continue
+			}
+			holders++
+			b.add("holder_update", map[string]interface{}{
+				"sukuk_address": sukukAddress,
+				"holder":        seedAddress(seedKindInvestor, investor),
+				"new_balance":   tokens(balance),
+			})
+		}
+
+		b.add("snapshot_taken", map[string]interface{}{
+			"sukuk_address":  sukukAddress,
+			"snapshot_id":    int64(1),
+			"total_supply":   tokens(supply),
+			"holder_count":   int64(holders),
+			"eligible_count": int64(holders),
+		})
+
+		// Two distributions; the first holder claims the first one
+		for distribution := int64(1); distribution <= 2; distribution++ {
+			fields := map[string]interface{}{
+				"sukuk_address":   sukukAddress,
+				"distribution_id": distribution,
+				"payment_token":   paymentToken,
+				"amount":          fmt.Sprintf("%d", distribution*5000000),
+			}
+			b.add("yield_distributed", fields)
+			b.events["yield_distribution"] = append(b.events["yield_distribution"], copyFields(fields))
+		}
+		b.add("yield_claim", map[string]interface{}{
+			"user":            seedAddress(seedKindInvestor, s%spec.investors),
+			"sukuk_address":   sukukAddress,
+			"distribution_id": int64(1),
+			"amount":          "1000000",
+		})
+	}
+
+	// Vault funding comes after every other event so adding it kept the earlier ids. Even
+	// sukuk hold enough for their next coupon, odd ones fall short of it
+	for s := 0; s < spec.sukuk; s++ {
+		sukukAddress := seedAddress(seedKindSukuk, s)
+		b.add("vault_update", map[string]interface{}{
+			"sukuk_address": sukukAddress,
+			"old_vault":     "0x0000000000000000000000000000000000000000",
+			"new_vault":     seedAddress(seedKindVault, s),
+		})
+		deposit := int64(30000000)
+		if s%2 == 1 {
+			deposit = 20000000
+		}
+		b.add("yield_deposit", map[string]interface{}{
+			"sukuk_address": sukukAddress,
+			"depositor":     seedAddress(seedKindInvestor, 1000), // The sukuk owner,
+			"payment_token": seedPaymentTokens[s%len(seedPaymentTokens)].Address,
+			"amount":        fmt.Sprintf("%d", deposit),
+		})
+	}
+
+	return b.events
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		copied[key] = value
+	}
+	return copied
+}
+
+// seedIndexerEvents creates the synthetic indexer tables and inserts rows not yet present
+func seedIndexerEvents(tx *gorm.DB, events seedEvents) error {
+	if err := CreateSyntheticIndexerTables(tx); err != nil {
+		return err
+	}
+	for _, table := range seedIndexerTables {
+		name := SyntheticIndexerTableName(table.event)
+		rows := events[table.event]
+		if len(rows) == 0 {
+			continue
+		}
+		err := tx.Table(name).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error
+		if err != nil {
+			return fmt.Errorf("failed to seed %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestParseSeedProfile(t *testing.T) {
+	for _, name := range []string{"minimal", "demo", "load-test", " Demo "} {
+		if _, err := ParseSeedProfile(name); err != nil {
+			t.Errorf("Expected %q to be a valid profile, got %v", name, err)
+		}
+	}
+	if _, err := ParseSeedProfile("huge"); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+}
+
+func TestWipeSeedDataGuards(t *testing.T) {
+	// The guards run before the database is touched
+	for _, environment := range []string{"production", "staging", ""} {
+		if err := WipeSeedData(nil, environment, true); !errors.Is(err, ErrWipeNotAllowed) {
+			t.Errorf("Environment %q: expected ErrWipeNotAllowed, got %v", environment, err)
+		}
+	}
+	if err := WipeSeedData(nil, "development", false); !errors.Is(err, ErrWipeNotConfirmed) {
+		t.Errorf("Expected ErrWipeNotConfirmed, got %v", err)
+	}
+}
+
+func TestBuildSeedEventsDeterministic(t *testing.T) {
+	spec := seedSpecs[SeedProfileDemo]
+	first, second := buildSeedEvents(spec), buildSeedEvents(spec)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("Expected identical events across builds")
+	}
+
+	if got := len(first["sukuk_purchase"]); got != spec.sukuk*spec.purchasesEach {
+		t.Errorf("Expected %d purchases, got %d", spec.sukuk*spec.purchasesEach, got)
+	}
+	for event, rows := range first {
+		ids := make(map[interface{}]bool, len(rows))
+		for _, row := range rows {
+			if ids[row["id"]] {
+				t.Errorf("Duplicate id %v in %s", row["id"], event)
+			}
+			ids[row["id"]] = true
+		}
+	}
+
+	if events := buildSeedEvents(seedSpecs[SeedProfileMinimal]); len(events) != 0 {
+		t.Errorf("Expected no events for the minimal profile, got %d tables", len(events))
+	}
+}
+
+// TestSeedDataIdempotent requires a migrated Postgres database, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func TestSeedDataIdempotent(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if err := AutoMigrate(db); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := WipeSeedData(db, "test", true); err != nil {
+		t.Fatalf("Failed to wipe: %v", err)
+	}
+	defer WipeSeedData(db, "test", true)
+
+	counts := func() []int64 {
+		var sukuk, investors, tokens, purchases int64
+		db.Model(&models.SukukMetadata{}).Count(&sukuk)
+		db.Model(&models.InvestorProfile{}).Count(&investors)
+		db.Model(&models.PaymentToken{}).Count(&tokens)
+		db.Table(SyntheticIndexerTableName("sukuk_purchase")).Count(&purchases)
+		return []int64{sukuk, investors, tokens, purchases}
+	}
+
+	if err := SeedData(db, SeedProfileDemo); err != nil {
+		t.Fatalf("First seed failed: %v", err)
+	}
+	first := counts()
+	if err := SeedData(db, SeedProfileDemo); err != nil {
+		t.Fatalf("Second seed failed: %v", err)
+	}
+	second := counts()
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected reseeding to add nothing, counts went from %v to %v", first, second)
+	}
+	spec := seedSpecs[SeedProfileDemo]
+	want := []int64{int64(spec.sukuk), int64(spec.investors), int64(len(seedPaymentTokens)), int64(spec.sukuk * spec.purchasesEach)}
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("Expected counts %v, got %v", want, first)
+	}
+}
@@ -63,7 +63,7 @@ func CreateDatabaseIfNotExists(cfg *config.Config) error {
 	return nil
 }
 
-// SetupDatabase creates database if needed, connects, and runs migrations
+// SetupDatabase creates database if needed, connects, and checks that migrations are applied
 func SetupDatabase(cfg *config.Config) error {
 	// Step 1: Create database if it doesn't exist
 	if err := CreateDatabaseIfNotExists(cfg); err != nil {
@@ -75,9 +75,9 @@ func SetupDatabase(cfg *config.Config) error {
 		return fmt.Errorf("database connection failed: %w", err)
 	}
 
-	// Step 3: Run migrations
-	if err := Migrate(); err != nil {
-		return fmt.Errorf("database migration failed: %w", err)
+	// Step 3: Verify schema version (migrations run via cmd/migrate)
+	if err := EnsureMigrated(); err != nil {
+		return fmt.Errorf("database migration check failed: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,95 @@
+// Package fx prices token amounts in fiat currencies for display
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/logger"
+)
+
+// SukukTokenSymbol is the quote used for sukuk token amounts, which are denominated in IDRX
+const SukukTokenSymbol = "IDRX"
+
+// Rates are fiat prices of whole tokens, fetched together
+type Rates struct {
+	Quotes map[string]map[string]*big.Rat // Token symbol (upper case) to currency code (lower case) to price
+	AsOf   time.Time                      // When the source quoted the rates
+	Source string                         // "static" or the rates URL
+	Stale  bool                           // Served past its TTL while a refresh is pending or failing
+}
+
+// Quote returns the price of one whole token in a currency
+func (r *Rates) Quote(token, currency string) (*big.Rat, bool) {
+	price, ok := r.Quotes[strings.ToUpper(token)][strings.ToLower(currency)]
+	return price, ok
+}
+
+// RateProvider supplies the current exchange rates
+type RateProvider interface {
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+var (
+	mu       sync.RWMutex
+	provider RateProvider
+)
+
+// Setup creates the configured provider and makes it the default
+func Setup(cfg config.FXConfig) error {
+	var p RateProvider
+	switch cfg.Provider {
+	case "static":
+		quotes, err := ParseStaticRates(cfg.StaticRates)
+		if err != nil {
+			return err
+		}
+		p = NewStaticProvider(quotes, time.Now())
+	case "http":
+		p = NewHTTPProvider(cfg.RatesURL, cfg.TTL, cfg.MaxStale)
+	}
+	SetDefault(p)
+
+	if p != nil {
+		logger.WithField("provider", cfg.Provider).Info("Exchange rate provider initialized")
+	}
+	return nil
+}
+
+// Default returns the configured provider, nil when fiat display values are disabled
+func Default() RateProvider {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider
+}
+
+// SetDefault replaces the provider, e.g. with a fake in tests
+func SetDefault(p RateProvider) {
+	mu.Lock()
+	defer mu.Unlock()
+	provider = p
+}
+
+// parseQuotes converts decimal price strings keyed by token and currency
+func parseQuotes(raw map[string]map[string]string) (map[string]map[string]*big.Rat, error) {
+	quotes := make(map[string]map[string]*big.Rat, len(raw))
+	for token, prices := range raw {
+		token = strings.ToUpper(strings.TrimSpace(token))
+		if quotes[token] == nil {
+			quotes[token] = make(map[string]*big.Rat, len(prices))
+		}
+		for currency, value := range prices {
+			price, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+			if !ok || price.Sign() < 0 {
+				return nil, fmt.Errorf("invalid %s/%s rate %q", token, currency, value)
+			}
+			quotes[token][strings.ToLower(strings.TrimSpace(currency))] = price
+		}
+	}
+	return quotes, nil
+}
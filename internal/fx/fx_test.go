@@ -0,0 +1,112 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseStaticRates(t *testing.T) {
+	quotes, err := ParseStaticRates("IDRX:IDR=1, idrx:usd=0.000061,USDC:USD=1")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	rates := NewStaticProvider(quotes, time.Now()).rates
+	if price, ok := rates.Quote("idrx", "USD"); !ok || price.FloatString(6) != "0.000061" {
+		t.Errorf("Expected IDRX/USD 0.000061 in any case, got %v", price)
+	}
+	if _, ok := rates.Quote("USDC", "idr"); ok {
+		t.Error("Expected no USDC/IDR quote")
+	}
+
+	for _, spec := range []string{"IDRX=1", "IDRX:IDR", "IDRX:IDR=abc", "IDRX:IDR=-1"} {
+		if _, err := ParseStaticRates(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+// fakeClock is a settable time source
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestHTTPProviderServesStaleWhileRevalidating(t *testing.T) {
+	var failing atomic.Bool
+	var fetches atomic.Int32
+	rate := atomic.Value{}
+	rate.Store(`"0.000061"`)
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"as_of": "2026-10-16T08:00:00Z", "rates": {"IDRX": {"idr": 1, "usd": ` + rate.Load().(string) + `}}}`))
+	}))
+	defer source.Close()
+
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)}
+	provider := NewHTTPProvider(source.URL, 5*time.Minute, time.Hour)
+	provider.now = clock.Now
+	ctx := context.Background()
+
+	rates, err := provider.Rates(ctx)
+	if err != nil || rates.Stale || !rates.AsOf.Equal(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected fresh rates as of the source time, got %+v (%v)", rates, err)
+	}
+	if price, _ := rates.Quote("IDRX", "usd"); price.FloatString(6) != "0.000061" {
+		t.Errorf("Expected a string rate to parse, got %v", price)
+	}
+	provider.Rates(ctx)
+	if fetches.Load() != 1 {
+		t.Errorf("Expected fresh rates to be cached, got %d fetches", fetches.Load())
+	}
+
+	// Expired: the stale rates are served at once while the refresh fails in the background
+	failing.Store(true)
+	clock.Advance(10 * time.Minute)
+	rates, err = provider.Rates(ctx)
+	if err != nil || !rates.Stale {
+		t.Fatalf("Expected stale rates, got %+v (%v)", rates, err)
+	}
+	provider.refreshed.Wait()
+	if rates, err = provider.Rates(ctx); err != nil || !rates.Stale {
+		t.Fatalf("Expected stale rates to survive a failed refresh, got %+v (%v)", rates, err)
+	}
+	provider.refreshed.Wait()
+
+	// The next refresh succeeds and replaces them
+	failing.Store(false)
+	rate.Store(`0.00007`)
+	provider.Rates(ctx)
+	provider.refreshed.Wait()
+	rates, err = provider.Rates(ctx)
+	if price, _ := rates.Quote("IDRX", "usd"); err != nil || rates.Stale || price.FloatString(5) != "0.00007" {
+		t.Errorf("Expected refreshed rates, got %+v (%v)", rates, err)
+	}
+
+	// Past the stale window a failing source is an error
+	failing.Store(true)
+	clock.Advance(2 * time.Hour)
+	if _, err := provider.Rates(ctx); err == nil {
+		t.Error("Expected an error once the rates are too old to serve")
+	}
+}
@@ -0,0 +1,143 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/logger"
+)
+
+// httpRatesTimeout bounds one fetch of the rates URL
+const httpRatesTimeout = 5 * time.Second
+
+// httpRatesResponse is the body the rates URL serves, e.g.
+// {"as_of": "2026-10-16T00:00:00Z", "rates": {"IDRX": {"idr": "1", "usd": "0.000061"}}}
+// Rates may be JSON numbers or decimal strings; a missing as_of means the time of the fetch
+type httpRatesResponse struct {
+	AsOf  *time.Time                        `json:"as_of"`
+	Rates map[string]map[string]json.Number `json:"rates"`
+}
+
+// HTTPProvider fetches rates from a URL and caches them for ttl. Expired rates are served,
+// flagged stale, for up to maxStale longer while a background refresh replaces them, so a
+// slow or failing source never delays a response. Past that, rates are fetched inline
+type HTTPProvider struct {
+	url      string
+	ttl      time.Duration
+	maxStale time.Duration
+	client   *http.Client
+	now      func() time.Time
+
+	mu         sync.Mutex
+	cached     *Rates
+	fetchedAt  time.Time
+	refreshing bool
+	refreshed  sync.WaitGroup // Tracks the background refresh, for tests
+}
+
+// NewHTTPProvider creates a provider reading url
+func NewHTTPProvider(url string, ttl, maxStale time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		url:      url,
+		ttl:      ttl,
+		maxStale: maxStale,
+		client:   &http.Client{Timeout: httpRatesTimeout},
+		now:      time.Now,
+	}
+}
+
+// Rates returns the cached rates, refreshing them as they age
+func (p *HTTPProvider) Rates(ctx context.Context) (*Rates, error) {
+	p.mu.Lock()
+	if p.cached != nil {
+		age := p.now().Sub(p.fetchedAt)
+		if age < p.ttl {
+			defer p.mu.Unlock()
+			return p.cached, nil
+		}
+		if age < p.ttl+p.maxStale {
+			if !p.refreshing {
+				p.refreshing = true
+				p.refreshed.Add(1)
+				go p.refresh()
+			}
+			stale := *p.cached
+			stale.Stale = true
+			p.mu.Unlock()
+			return &stale, nil
+		}
+	}
+	p.mu.Unlock()
+
+	rates, err := p.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.store(rates)
+	return rates, nil
+}
+
+// refresh replaces the cached rates in the background, keeping the stale ones on failure
+func (p *HTTPProvider) refresh() {
+	defer p.refreshed.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), httpRatesTimeout)
+	defer cancel()
+	rates, err := p.fetch(ctx)
+
+	p.mu.Lock()
+	p.refreshing = false
+	p.mu.Unlock()
+	if err != nil {
+		logger.WithError(err).Warn("Failed to refresh exchange rates, serving stale rates")
+		return
+	}
+	p.store(rates)
+}
+
+func (p *HTTPProvider) store(rates *Rates) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cached, p.fetchedAt = rates, p.now()
+}
+
+func (p *HTTPProvider) fetch(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rates request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rates source returned %d", resp.StatusCode)
+	}
+
+	var body httpRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+	raw := make(map[string]map[string]string, len(body.Rates))
+	for token, prices := range body.Rates {
+		raw[token] = make(map[string]string, len(prices))
+		for currency, price := range prices {
+			raw[token][currency] = price.String()
+		}
+	}
+	quotes, err := parseQuotes(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	asOf := p.now()
+	if body.AsOf != nil {
+		asOf = *body.AsOf
+	}
+	return &Rates{Quotes: quotes, AsOf: asOf.UTC(), Source: p.url}, nil
+}
@@ -0,0 +1,47 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// StaticProvider serves fixed rates from configuration
+type StaticProvider struct {
+	rates *Rates
+}
+
+// NewStaticProvider quotes the given rates as of asOf
+func NewStaticProvider(quotes map[string]map[string]*big.Rat, asOf time.Time) *StaticProvider {
+	return &StaticProvider{rates: &Rates{Quotes: quotes, AsOf: asOf.UTC(), Source: "static"}}
+}
+
+// Rates returns the configured rates
+func (p *StaticProvider) Rates(ctx context.Context) (*Rates, error) {
+	return p.rates, nil
+}
+
+// ParseStaticRates reads "TOKEN:CURRENCY=rate" pairs separated by commas, e.g.
+// "IDRX:IDR=1,IDRX:USD=0.000061,USDC:USD=1"
+func ParseStaticRates(spec string) (map[string]map[string]*big.Rat, error) {
+	raw := make(map[string]map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		symbols, value, ok := strings.Cut(pair, "=")
+		token, currency, ok2 := strings.Cut(symbols, ":")
+		if !ok || !ok2 || strings.TrimSpace(token) == "" || strings.TrimSpace(currency) == "" {
+			return nil, fmt.Errorf("invalid static rate %q, expected TOKEN:CURRENCY=rate", pair)
+		}
+		token = strings.TrimSpace(token)
+		if raw[token] == nil {
+			raw[token] = make(map[string]string)
+		}
+		raw[token][currency] = value
+	}
+	return parseQuotes(raw)
+}
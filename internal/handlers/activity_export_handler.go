@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// activityExportFlushLines is how many NDJSON lines are written between flushes
+const activityExportFlushLines = 500
+
+// ExportSukukActivities streams the full activity history of a sukuk as NDJSON
+// @Summary Export sukuk activity history
+// @Description Stream every purchase, redemption request and yield claim of a sukuk as newline-delimited JSON, one event per line, ordered by block number then log index. The export is read in batches and flushed as it goes, so it suits histories of any length; to continue an incremental or interrupted pull, pass the block_number of the last line received as from_block and skip the events of that block already stored, matched on id. Addresses are EIP-55 checksummed and amounts are raw. Errors after the first line end the stream early instead of changing the status
+// @Tags sukuk-metadata
+// @Produce application/x-ndjson
+// @Param id path int true "Sukuk Metadata ID"
+// @Param from_block query int false "Only export events from this block on" minimum(0)
+// @Success 200 {object} models.ActivityExportItem "One line per event"
+// @Failure 400 {object} map[string]string "Invalid ID format or from_block"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/export/activities [get]
+func ExportSukukActivities(c *gin.Context) {
+	var fromBlock int64
+	if raw := c.Query("from_block"); raw != "" {
+		block, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || block < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid from_block",
+				"details": "from_block must be a non-negative block number",
+			})
+			return
+		}
+		fromBlock = block
+	}
+
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	// Headers go out with the first line, so a failure before it still gets a JSON error
+	started := false
+	start := func() {
+		if !started {
+			started = true
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("Cache-Control", "no-cache")
+			c.Status(http.StatusOK)
+		}
+	}
+
+	ctx := c.Request.Context()
+	encoder := json.NewEncoder(c.Writer)
+	lines := 0
+	err := services.NewIndexerQueryService().ExportSukukActivities(ctx, sukukMetadata.ContractAddress, fromBlock, services.ActivityExportBatchSize,
+		func(item models.ActivityExportItem) error {
+			start()
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+			lines++
+			if lines%activityExportFlushLines == 0 {
+				c.Writer.Flush()
+			}
+			return nil
+		})
+
+	switch {
+	case err == nil:
+		start()
+		c.Writer.Flush()
+	case ctx.Err() != nil:
+		logger.WithField("lines", lines).Info("Activity export ended by the client")
+	case started:
+		logger.WithError(err).WithField("lines", lines).Error("Activity export failed mid-stream")
+	default:
+		logger.WithError(err).Error("Failed to export activities")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to export activities",
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newActivityExportRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata/:id/export/activities", ExportSukukActivities)
+	return router
+}
+
+func TestExportSukukActivitiesRejectsInvalidFromBlock(t *testing.T) {
+	router := newActivityExportRouter()
+	for _, fromBlock := range []string{"-1", "abc", "1.5"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sukuk-metadata/1/export/activities?from_block="+fromBlock, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Invalid from_block") {
+			t.Errorf("from_block=%s: expected 400, got %d: %s", fromBlock, w.Code, w.Body.String())
+		}
+	}
+}
+
+// cancellingRecorder cancels the request once it has received limit lines, like a client
+// disconnecting mid-download
+type cancellingRecorder struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+	limit  int
+	lines  int
+}
+
+func (r *cancellingRecorder) Write(p []byte) (int, error) {
+	r.lines += bytes.Count(p, []byte("\n"))
+	if r.lines >= r.limit {
+		r.cancel()
+	}
+	return r.ResponseRecorder.Write(p)
+}
+
+// readActivityExport parses an NDJSON export body
+func readActivityExport(t *testing.T, body string) []models.ActivityExportItem {
+	var items []models.ActivityExportItem
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var item models.ActivityExportItem
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			t.Fatalf("Failed to parse line %d %q: %v", len(items)+1, scanner.Text(), err)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	return items
+}
+
+// TestExportSukukActivitiesStreamsFullHistory streams 10k seeded events. It requires a
+// Postgres database, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func TestExportSukukActivitiesStreamsFullHistory(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	// Stand-in indexer tables, pinned with overrides so discovery can't pick real ones
+	eventTypes := []string{"sukuk_purchase", "redemption_request", "yield_claim", "yield_distributed"}
+	overrides, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range eventTypes {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range overrides {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range eventTypes {
+		table := "fe21__" + eventType
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, buyer TEXT, "user" TEXT, sukuk_address TEXT, payment_token TEXT, distribution_id BIGINT,
+			amount NUMERIC(78,0), block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+		if err := models.SetIndexerTableOverride(db, eventType, table); err != nil {
+			t.Fatalf("Failed to pin %s: %v", table, err)
+		}
+	}
+
+	const (
+		sukuk  = "0x00000000000000000000000000000000000fe021"
+		other  = "0x00000000000000000000000000000000000fe022"
+		holder = "0x00000000000000000000000000000000000000aa"
+		idrx   = "0x00000000000000000000000000000000000000cc"
+	)
+	metadata := models.SukukMetadata{ContractAddress: sukuk, SukukCode: "EXPORT", SukukTitle: "Export"}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	defer db.Unscoped().Delete(&models.SukukMetadata{}, metadata.ID)
+
+	// Event n is in block n/10 at log index (n%10)*7, so comparing log indexes as text would
+	// misorder them (14 < 7); the last digit picks the table. Another sukuk's events are mixed in
+	seed := []struct {
+		table, address, digits string
+	}{
+		{"fe21__sukuk_purchase", "buyer", "n % 10 < 5"},
+		{"fe21__redemption_request", `"user"`, "n % 10 BETWEEN 5 AND 7"},
+		{"fe21__yield_claim", `"user"`, "n % 10 >= 8"},
+	}
+	for _, s := range seed {
+		err := db.Exec(`INSERT INTO `+s.table+` (id, `+s.address+`, sukuk_address, payment_token, distribution_id, amount, block_number, tx_hash, timestamp)
+			SELECT '0x' || lpad(to_hex(n), 64, '0') || '-' || ((n % 10) * 7), ?, CASE WHEN n < 10000 THEN ? ELSE ? END, ?, 1,
+				1000 + n, (n % 10000) / 10, '0x' || lpad(to_hex(n), 64, '0'), 1700000000 + (n % 10000) / 10
+			FROM generate_series(0, 10199) n WHERE `+s.digits, holder, sukuk, other, idrx).Error
+		if err != nil {
+			t.Fatalf("Failed to seed %s: %v", s.table, err)
+		}
+	}
+	err := db.Exec(`INSERT INTO fe21__yield_distributed (id, sukuk_address, distribution_id, payment_token, amount, block_number, tx_hash, timestamp)
+		VALUES ('0xd-0', ?, 1, ?, 1, 0, '0xd', 1700000000)`, sukuk, idrx).Error
+	if err != nil {
+		t.Fatalf("Failed to seed distribution: %v", err)
+	}
+
+	router := newActivityExportRouter()
+	export := func(query string) []models.ActivityExportItem {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/sukuk-metadata/%d/export/activities%s", metadata.ID, query), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+			t.Errorf("Expected NDJSON, got %s", contentType)
+		}
+		return readActivityExport(t, w.Body.String())
+	}
+
+	items := export("")
+	if len(items) != 10000 {
+		t.Fatalf("Expected 10000 events, got %d", len(items))
+	}
+	counts := make(map[models.ActivityType]int)
+	for i, item := range items {
+		counts[item.Type]++
+		if item.BlockNumber != int64(i/10) || item.LogIndex != int64(i%10*7) {
+			t.Fatalf("Event %d out of order: block %d log %d", i, item.BlockNumber, item.LogIndex)
+		}
+		if item.Amount != fmt.Sprint(1000+i) || item.PaymentToken == "" {
+			t.Fatalf("Event %d has amount %s and payment token %q", i, item.Amount, item.PaymentToken)
+		}
+	}
+	if counts[models.ActivityTypePurchase] != 5000 || counts[models.ActivityTypeRedemptionRequest] != 3000 || counts[models.ActivityTypeYieldClaim] != 2000 {
+		t.Errorf("Unexpected counts per type: %v", counts)
+	}
+
+	// An incremental pull starts at the first event of from_block
+	items = export("?from_block=990")
+	if len(items) != 100 || items[0].BlockNumber != 990 || items[0].LogIndex != 0 {
+		t.Errorf("Expected the 100 events of blocks 990-999, got %d: %+v", len(items), items)
+	}
+
+	if items := export("?from_block=5000"); len(items) != 0 {
+		t.Errorf("Expected an empty export past the head, got %d events", len(items))
+	}
+
+	// A client leaving mid-download stops the export at the next batch
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancellingRecorder{ResponseRecorder: httptest.NewRecorder(), cancel: cancel, limit: 1500}
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/sukuk-metadata/%d/export/activities", metadata.ID), nil).WithContext(ctx)
+	router.ServeHTTP(w, req)
+	items = readActivityExport(t, w.Body.String())
+	if len(items) < 1500 || len(items) >= 10000 {
+		t.Errorf("Expected the export to stop soon after 1500 events, got %d", len(items))
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Activity feed page sizes
+const (
+	defaultActivityFeedLimit = 20
+	maxActivityFeedLimit     = 100
+)
+
+// GetActivityFeed returns the latest platform activity across every sukuk
+// @Summary Get the platform activity feed
+// @Description Newest purchases, redemption requests and yield claims across all sukuk, globally ordered by timestamp. Addresses are EIP-55 checksummed and amounts are returned raw and formatted in their payment token. Pass next_cursor as ?cursor= for the following page; the first page is cached for a few seconds. Transfers between wallets are only listed per wallet, so filtering by transfer_in or transfer_out returns an empty page
+// @Tags activities
+// @Produce json
+// @Param limit query int false "Number of activities to return (max 100; defaults to the activity_feed.default_limit setting)" default(20)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param type query string false "Only return this activity type" Enums(purchase, redemption_request, yield_claim)
+// @Success 200 {object} models.ActivityFeedResponse "Activity feed page"
+// @Failure 400 {object} map[string]string "Invalid cursor or activity type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /activities [get]
+func GetActivityFeed(c *gin.Context) {
+	activityType, ok := activityTypeQuery(c)
+	if !ok {
+		return
+	}
+
+	defaultLimit := services.Settings().GetInt(services.SettingActivityFeedDefaultLimit, defaultActivityFeedLimit)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxActivityFeedLimit {
+		limit = maxActivityFeedLimit
+	}
+	filter := services.ActivityFeedFilter{Type: activityType, Limit: limit}
+
+	var response *models.ActivityFeedResponse
+	if raw := c.Query("cursor"); raw != "" {
+		filter.After, err = services.ParseActivityCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid cursor",
+				"details": "cursor must be the next_cursor of a previous page",
+			})
+			return
+		}
+		response, err = buildActivityFeed(c.Request.Context(), filter)
+	} else {
+		// The first page is the hottest read on the site, so it is shared for a few seconds
+		var hit bool
+		response, hit, err = cache.Fetch(c.Request.Context(), cache.ActivityFeedKey(string(activityType), limit), cacheTTL(services.SettingCacheActivitiesTTL, cache.ActivitiesTTL), func() (*models.ActivityFeedResponse, error) {
+			return buildActivityFeed(c.Request.Context(), filter)
+		})
+		setCacheStatus(c, hit)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to get activity feed")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to get activity feed",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// buildActivityFeed reads one page of the activity feed
+func buildActivityFeed(ctx context.Context, filter services.ActivityFeedFilter) (*models.ActivityFeedResponse, error) {
+	activities, next, err := services.NewIndexerQueryService().GetActivityFeed(ctx, filter, loadTokenFormatter())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ActivityFeedResponse{Activities: activities}
+	if next != nil {
+		response.NextCursor = next.Encode()
+	}
+	return response, nil
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// activityTypeQuery reads the optional ?type= filter, responding 400 with the valid values
+// and returning false when it is not a registered activity type
+func activityTypeQuery(c *gin.Context) (models.ActivityType, bool) {
+	raw := c.Query("type")
+	if raw == "" {
+		return "", true
+	}
+	activityType, err := models.ParseActivityType(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       "Invalid activity type",
+			"details":     err.Error(),
+			"valid_types": models.ValidActivityTypes(),
+		})
+		return "", false
+	}
+	return activityType, true
+}
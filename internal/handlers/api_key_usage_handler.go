@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyIDPattern matches the fingerprints middleware.APIKeyID produces
+var apiKeyIDPattern = regexp.MustCompile(`^[0-9a-f]{12}$`)
+
+// GetAPIKeyUsage returns the hourly usage of an API key
+// @Summary Get API key usage
+// @Description Hourly requests, errors (status 400 and above) and response bytes of an API key, identified by the api_key_id fingerprint shown in the access log (first 12 hex digits of the key's SHA-256), with totals. Only requests the key authenticated are counted. from is rounded down and to up to whole hours; to defaults to now and from to a day before to, at most 31 days. Counts not yet flushed to the database are included. Hours without traffic are left out
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "API key fingerprint" Example(3f2a9c1b7d4e)
+// @Param from query string false "Window start (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Window end, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} models.APIKeyUsageReport "Hourly usage and totals"
+// @Failure 400 {object} map[string]string "Invalid key ID or window"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/api-keys/{id}/usage [get]
+func GetAPIKeyUsage(recorder *services.APIKeyUsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyID := c.Param("id")
+		if !apiKeyIDPattern.MatchString(keyID) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid key ID",
+				"details": "id must be the 12 lowercase hex digit api_key_id fingerprint",
+			})
+			return
+		}
+
+		var from, to *time.Time
+		for _, bound := range []struct {
+			param  string
+			target **time.Time
+		}{
+			{"from", &from},
+			{"to", &to},
+		} {
+			value := c.Query(bound.param)
+			if value == "" {
+				continue
+			}
+			t, err := parseTimeSeriesDate(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid " + bound.param,
+					"details": bound.param + " must be RFC3339 or YYYY-MM-DD",
+				})
+				return
+			}
+			*bound.target = &t
+		}
+
+		start, end, err := services.APIKeyUsageWindow(from, to, time.Now())
+		if errors.Is(err, services.ErrInvalidUsageWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid window",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		report, err := recorder.Usage(c.Request.Context(), keyID, start, end)
+		if err != nil {
+			logger.WithError(err).Error("Failed to load API key usage")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load API key usage",
+			})
+			return
+		}
+		respondJSON(c, http.StatusOK, report)
+	}
+}
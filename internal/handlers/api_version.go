@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersion selects how a handler serializes its response. Both versions load the same
+// data; v1 writes it bare with {"error": ...} errors, v2 wraps it in the APIResponse and
+// PaginatedResponse envelopes
+type APIVersion int
+
+const (
+	APIV1 APIVersion = 1
+	APIV2 APIVersion = 2
+)
+
+// Pagination defaults for v2 list endpoints
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+var (
+	errInvalidPage    = errors.New("page must be a positive integer")
+	errInvalidPerPage = fmt.Errorf("per_page must be between 1 and %d", MaxPerPage)
+)
+
+// respond writes a single resource
+func (v APIVersion) respond(c *gin.Context, status int, data interface{}) {
+	if v == APIV1 {
+		respondJSON(c, status, data)
+		return
+	}
+	SendSuccess(c, status, data, "")
+}
+
+// respondError writes an error; details are omitted when empty
+func (v APIVersion) respondError(c *gin.Context, status int, message, details string) {
+	if v == APIV1 {
+		body := gin.H{"error": message}
+		if details != "" {
+			body["details"] = details
+		}
+		c.JSON(status, body)
+		return
+	}
+	SendError(c, status, message, details)
+}
+
+// parsePagination reads page and per_page for v2 list endpoints. v1 lists are never paginated
+func (v APIVersion) parsePagination(c *gin.Context) (page, perPage int, err error) {
+	if v == APIV1 {
+		return 1, 0, nil
+	}
+	return parsePageQuery(c)
+}
+
+// parsePageQuery reads page and per_page, defaulting to the first page of DefaultPerPage items
+func parsePageQuery(c *gin.Context) (page, perPage int, err error) {
+	page, err = strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, errInvalidPage
+	}
+	perPage, err = strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(DefaultPerPage)))
+	if err != nil || perPage < 1 || perPage > MaxPerPage {
+		return 0, 0, errInvalidPerPage
+	}
+	return page, perPage, nil
+}
+
+// respondPage writes one page of items. v1 writes every item as a bare array
+func respondPage[T any](v APIVersion, c *gin.Context, items []T, page, perPage int) {
+	if v == APIV1 {
+		respondJSON(c, http.StatusOK, items)
+		return
+	}
+
+	pageItems, pagination := paginate(items, page, perPage)
+	SendPaginatedResponse(c, pageItems, pagination)
+}
+
+// paginate slices items to the requested page
+func paginate[T any](items []T, page, perPage int) ([]T, *Pagination) {
+	if items == nil {
+		items = []T{} // Keep data an array rather than null
+	}
+	total := len(items)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return items[start:end], newPagination(total, end-start, page, perPage)
+}
+
+// newPagination describes a page holding count of total items. Pages past the last one
+// are empty but keep the real total
+func newPagination(total, count, page, perPage int) *Pagination {
+	totalPages := (total + perPage - 1) / perPage
+	return &Pagination{
+		Total:       total,
+		Count:       count,
+		Page:        page,
+		PerPage:     perPage,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
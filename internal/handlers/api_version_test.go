@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// seedSukukMetadataList caches a sukuk metadata listing so the handlers serve it without a database
+func seedSukukMetadataList(t *testing.T, count int) []models.SukukMetadataListResponse {
+	t.Helper()
+	cache.SetDefault(cache.NewMemoryCache())
+	t.Cleanup(func() { cache.SetDefault(cache.NewMemoryCache()) })
+
+	list := make([]models.SukukMetadataListResponse, count)
+	for i := range list {
+		list[i] = models.SukukMetadataListResponse{
+			ID:               uint(i + 1),
+			ContractAddress:  fmt.Sprintf("0x%040x", i+1),
+			SukukCode:        fmt.Sprintf("SKK%d", i+1),
+			Status:           models.SukukStatusActive,
+			LatestActivities: []models.ActivityEvent{},
+		}
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("Failed to encode list: %v", err)
+	}
+	if err := cache.Default().Set(context.Background(), cache.SukukMetadataListKey("all"), data, cache.MetadataTTL); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+	return list
+}
+
+func newVersionedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/sukuk-metadata", ListSukukMetadata)
+	router.GET("/api/v2/sukuk-metadata", ListSukukMetadataV2)
+	router.GET("/api/v1/sukuk-metadata/:id", GetSukukMetadata)
+	router.GET("/api/v2/sukuk-metadata/:id", GetSukukMetadataV2)
+	return router
+}
+
+func TestListSukukMetadataVersionsShareData(t *testing.T) {
+	list := seedSukukMetadataList(t, 3)
+	router := newVersionedRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/sukuk-metadata", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected v1 status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var v1 []models.SukukMetadataListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &v1); err != nil {
+		t.Fatalf("Expected v1 to be a bare array: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/sukuk-metadata", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected v2 status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var v2 struct {
+		Success bool                               `json:"success"`
+		Data    []models.SukukMetadataListResponse `json:"data"`
+		Meta    *Pagination                        `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &v2); err != nil {
+		t.Fatalf("Expected v2 envelope: %v", err)
+	}
+
+	if !v2.Success || v2.Meta == nil {
+		t.Fatalf("Expected successful v2 envelope with meta, got %s", w.Body.String())
+	}
+	if !reflect.DeepEqual(v1, v2.Data) {
+		t.Errorf("Expected both versions to return the same sukuk\nv1: %+v\nv2: %+v", v1, v2.Data)
+	}
+	if len(v1) != len(list) || v2.Meta.Total != len(list) || v2.Meta.Page != 1 || v2.Meta.PerPage != DefaultPerPage {
+		t.Errorf("Unexpected counts: v1 %d, meta %+v", len(v1), v2.Meta)
+	}
+}
+
+func TestListSukukMetadataTermDetailsV2Only(t *testing.T) {
+	cache.SetDefault(cache.NewMemoryCache())
+	t.Cleanup(func() { cache.SetDefault(cache.NewMemoryCache()) })
+	sukuk := models.SukukMetadata{ID: 1, ContractAddress: fmt.Sprintf("0x%040x", 1), Tenor: "5 Tahun", ImbalHasil: "6.55% / Tahun", PeriodePembelian: "16 Mei - 18 Jun 2025"}
+	if err := sukuk.BeforeSave(nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal([]models.SukukMetadataListResponse{sukuk.ToListResponse()})
+	if err != nil {
+		t.Fatalf("Failed to encode list: %v", err)
+	}
+	if err := cache.Default().Set(context.Background(), cache.SukukMetadataListKey("all"), data, cache.MetadataTTL); err != nil {
+		t.Fatalf("Failed to seed cache: %v", err)
+	}
+	router := newVersionedRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/sukuk-metadata", nil))
+	var v1 []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &v1); err != nil || len(v1) != 1 {
+		t.Fatalf("Expected one v1 sukuk, got %s", w.Body.String())
+	}
+	if _, ok := v1[0]["tenor_detail"]; ok || v1[0]["tenor"] != "5 Tahun" {
+		t.Errorf("Expected v1 to serve only the tenor label, got %v", v1[0])
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/sukuk-metadata", nil))
+	var v2 struct {
+		Data []models.SukukMetadataListResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &v2); err != nil || len(v2.Data) != 1 {
+		t.Fatalf("Expected one v2 sukuk, got %s", w.Body.String())
+	}
+	got := v2.Data[0]
+	if got.TenorDetail == nil || got.TenorDetail.Months != 60 || got.ImbalHasilDetail == nil || got.ImbalHasilDetail.RateBps != 655 ||
+		got.PeriodePembelianDetail == nil || got.PeriodePembelianDetail.Label != "16 Mei - 18 Jun 2025" || got.Tenor != "5 Tahun" {
+		t.Errorf("Expected v2 to serve the details beside the labels, got %+v", got)
+	}
+}
+
+func TestListSukukMetadataV2Pagination(t *testing.T) {
+	list := seedSukukMetadataList(t, 5)
+	router := newVersionedRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/sukuk-metadata?page=2&per_page=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body struct {
+		Data []models.SukukMetadataListResponse `json:"data"`
+		Meta Pagination                         `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(body.Data) != 2 || body.Data[0].ID != list[2].ID || body.Data[1].ID != list[3].ID {
+		t.Errorf("Expected sukuk 3 and 4 on page 2, got %+v", body.Data)
+	}
+	want := Pagination{Total: 5, Count: 2, Page: 2, PerPage: 2, TotalPages: 3, HasNext: true, HasPrevious: true}
+	if body.Meta != want {
+		t.Errorf("Expected meta %+v, got %+v", want, body.Meta)
+	}
+}
+
+func TestSukukMetadataErrorShapes(t *testing.T) {
+	seedSukukMetadataList(t, 1)
+	router := newVersionedRouter()
+
+	// v1 errors stay {"error": ...}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/sukuk-metadata/abc", nil))
+	var v1 map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &v1)
+	if w.Code != http.StatusBadRequest || v1["error"] != "Invalid ID format" {
+		t.Errorf("Expected v1 400 with error string, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// v2 errors use the error envelope
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/sukuk-metadata/abc", nil))
+	var v2 APIResponse
+	json.Unmarshal(w.Body.Bytes(), &v2)
+	if w.Code != http.StatusBadRequest || v2.Success || v2.Error == nil || v2.Error.Code != http.StatusBadRequest || v2.Error.Message != "Invalid ID format" {
+		t.Errorf("Expected v2 400 error envelope, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Pagination parameters are validated on v2 only
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/sukuk-metadata?per_page=500", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for per_page above the maximum, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/sukuk-metadata?per_page=500", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected v1 to ignore per_page, got %d", w.Code)
+	}
+}
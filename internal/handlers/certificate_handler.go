@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetInvestmentCertificate issues an investment certificate PDF for an investor's holding of a sukuk
+// @Summary Download investment certificate
+// @Description Issue a PDF certificate of the address's current balance of a sukuk, with the sukuk code, title and issuer, the balance, its share of the supply, the issue time and a verification code. Every call stores a new certificate with the values printed on it, so it can be verified later regardless of balance changes. The layout comes from CERTIFICATE_LAYOUT_FILE
+// @Tags portfolio
+// @Produce application/pdf
+// @Param address path string true "Investor wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Param sukuk_address path string true "Sukuk contract address"
+// @Success 200 {file} file "Certificate PDF; the verification code is also in the X-Certificate-Code header"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 404 {object} map[string]string "Sukuk not found"
+// @Failure 409 {object} map[string]string "Investor holds no balance of this sukuk"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /portfolio/{address}/certificate/{sukuk_address} [get]
+func GetInvestmentCertificate(layoutFile string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.Param("address")
+		sukukAddress := c.Param("sukuk_address")
+		if !utils.IsValidEthereumAddress(address) || !utils.IsValidEthereumAddress(sukukAddress) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid address",
+			})
+			return
+		}
+
+		layout, err := services.LoadCertificateLayout(layoutFile)
+		if err != nil {
+			logger.WithError(err).Error("Failed to load certificate layout")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to generate certificate",
+			})
+			return
+		}
+
+		certificate, err := services.IssueCertificate(c.Request.Context(), currentDeps().Portfolio, address, sukukAddress, time.Now())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Sukuk not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrCertificateZeroBalance) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Investor holds no balance of this sukuk",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to issue certificate")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to generate certificate",
+			})
+			return
+		}
+
+		// Rendered in full before responding, so a layout error is still a JSON 500
+		var pdf bytes.Buffer
+		if err := services.RenderCertificatePDF(&pdf, certificate, layout); err != nil {
+			logger.WithError(err).Error("Failed to render certificate")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to generate certificate",
+			})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.CertificateFilename(certificate)))
+		c.Header("X-Certificate-Code", certificate.Code)
+		c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+	}
+}
+
+// VerifyCertificate looks up an investment certificate by its verification code
+// @Summary Verify investment certificate
+// @Description Look up a certificate by the verification code printed on it. The response holds the values as issued, not the investor's current balance
+// @Tags portfolio
+// @Produce json
+// @Param code path string true "Verification code" Example(CERT-MFRGGZDFMZTWQ2LK)
+// @Success 200 {object} models.CertificateVerification "Certificate as issued"
+// @Failure 404 {object} map[string]string "Certificate not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /certificates/verify/{code} [get]
+func VerifyCertificate(c *gin.Context) {
+	certificate, err := services.VerifyCertificate(c.Request.Context(), c.Param("code"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Certificate not found",
+		})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to verify certificate")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to verify certificate",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.CertificateVerification{
+		Valid:       true,
+		Certificate: certificate,
+	})
+}
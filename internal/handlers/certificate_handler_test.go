@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/mocks"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	certificateTestInvestor = "0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"
+	certificateTestSukuk    = "0xabcdef0000000000000000000000000000000001"
+)
+
+// serveCertificate runs one certificate request against a stub database answering respond,
+// returning the response and every certificate insert gorm sent
+func serveCertificate(t *testing.T, portfolio *mocks.PortfolioReader, respond func(query string) stubResult, target string) (*httptest.ResponseRecorder, []string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		inserts []string
+	)
+	previous := database.DB
+	stub := openStubDB(t, func(query string) stubResult {
+		if strings.Contains(query, `INSERT INTO "certificates"`) {
+			mu.Lock()
+			inserts = append(inserts, query)
+			mu.Unlock()
+			return stubResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+		}
+		return respond(query)
+	})
+	database.DB = stub.Session(&gorm.Session{SkipDefaultTransaction: true})
+	defer func() { database.DB = previous }()
+	defer SetDeps(SetDeps(Deps{Portfolio: portfolio}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/portfolio/:address/certificate/:sukuk_address", GetInvestmentCertificate(""))
+	router.GET("/certificates/verify/:code", VerifyCertificate)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w, inserts
+}
+
+// certificateSukukRows answers the sukuk metadata lookup with one sukuk
+func certificateSukukRows(query string) stubResult {
+	if strings.Contains(query, `FROM "sukuk_metadata"`) {
+		return stubResult{
+			columns: []string{"id", "contract_address", "owner_address", "sukuk_code", "sukuk_title"},
+			rows:    [][]driver.Value{{int64(7), certificateTestSukuk, "0xabcdef0000000000000000000000000000000002", "SR022-T5", "Sukuk Ritel"}},
+		}
+	}
+	return stubResult{}
+}
+
+func TestGetInvestmentCertificateIssuesPDF(t *testing.T) {
+	portfolio := &mocks.PortfolioReader{
+		GetUserShareFunc: func(ctx context.Context, address, sukukAddress string) (string, services.Supply, error) {
+			return "250", services.Supply{Amount: "10000", Source: models.SupplySourceSnapshot}, nil
+		},
+	}
+
+	w, inserts := serveCertificate(t, portfolio, certificateSukukRows, "/portfolio/"+certificateTestInvestor+"/certificate/"+certificateTestSukuk)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("Expected a PDF, got %s", w.Header().Get("Content-Type"))
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("Expected the body to be a PDF document")
+	}
+	code := w.Header().Get("X-Certificate-Code")
+	if !strings.HasPrefix(code, "CERT-") {
+		t.Errorf("Expected a verification code header, got %q", code)
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), code) {
+		t.Errorf("Expected the filename to carry the code, got %s", w.Header().Get("Content-Disposition"))
+	}
+	if len(inserts) != 1 {
+		t.Fatalf("Expected one stored certificate, got %v", inserts)
+	}
+}
+
+func TestGetInvestmentCertificateRejectsZeroBalance(t *testing.T) {
+	portfolio := &mocks.PortfolioReader{
+		GetUserShareFunc: func(ctx context.Context, address, sukukAddress string) (string, services.Supply, error) {
+			return "0", services.Supply{Source: models.SupplySourceNone}, nil
+		},
+	}
+
+	w, inserts := serveCertificate(t, portfolio, certificateSukukRows, "/portfolio/"+certificateTestInvestor+"/certificate/"+certificateTestSukuk)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(inserts) != 0 {
+		t.Errorf("Expected no certificate stored for a zero balance, got %v", inserts)
+	}
+}
+
+func TestGetInvestmentCertificateUnknownSukuk(t *testing.T) {
+	w, inserts := serveCertificate(t, &mocks.PortfolioReader{}, func(string) stubResult { return stubResult{} }, "/portfolio/"+certificateTestInvestor+"/certificate/"+certificateTestSukuk)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(inserts) != 0 {
+		t.Errorf("Expected no certificate stored, got %v", inserts)
+	}
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	issuedAt := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+	stored := func(query string) stubResult {
+		if strings.Contains(query, `FROM "certificates"`) {
+			return stubResult{
+				columns: []string{"id", "code", "investor_address", "sukuk_address", "sukuk_code", "balance", "total_supply", "share_percentage", "issued_at"},
+				rows:    [][]driver.Value{{int64(1), "CERT-MFRGGZDFMZTWQ2LK", strings.ToLower(certificateTestInvestor), certificateTestSukuk, "SR022-T5", "250", "10000", "2.50", issuedAt}},
+			}
+		}
+		return stubResult{}
+	}
+
+	w, _ := serveCertificate(t, &mocks.PortfolioReader{}, stored, "/certificates/verify/cert-mfrggzdfmztwq2lk")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response models.CertificateVerification
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !response.Valid || response.Certificate == nil || response.Certificate.Balance != "250" || response.Certificate.SharePercentage != "2.50" {
+		t.Errorf("Expected the certificate as issued, got %+v", response)
+	}
+
+	w, _ = serveCertificate(t, &mocks.PortfolioReader{}, func(string) stubResult { return stubResult{} }, "/certificates/verify/CERT-UNKNOWN")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown code, got %d", w.Code)
+	}
+}
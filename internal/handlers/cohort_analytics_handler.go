@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// investorCohortsTTL is how long a cohort analysis is cached, as each one scans every purchase
+const investorCohortsTTL = time.Hour
+
+// GetInvestorCohorts returns investor retention by month of first purchase
+// @Summary Get investor cohort analytics
+// @Description Bucket investors by the month of their first purchase (Asia/Jakarta) and count, per cohort, the investors matching metric in each month since, as a heatmap matrix: values[N] and rates[N] are the count and fraction of the cohort N months after its first purchase month, up to the to month. repeat_purchase counts investors with a purchase in the month, so month 0 is the whole cohort. retained_balance counts investors whose latest holder_update as of the end of the month is non-zero in at least one sukuk. Results are cached for an hour
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param metric query string false "Metric to count" Enums(repeat_purchase, retained_balance) default(repeat_purchase)
+// @Param from query string false "First cohort month, YYYY-MM; defaults to 11 months before to" Example(2025-01)
+// @Param to query string false "Last cohort and activity month, YYYY-MM; defaults to the current month. At most 24 months from from" Example(2025-12)
+// @Success 200 {object} models.CohortAnalyticsResponse "Cohort matrix"
+// @Failure 400 {object} map[string]string "Unknown metric, malformed or future month, or more than 24 months"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/analytics/cohorts [get]
+func GetInvestorCohorts(c *gin.Context) {
+	metric := models.CohortMetric(c.DefaultQuery("metric", string(models.CohortMetricRepeatPurchase)))
+	if !metric.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid metric",
+			"details": "metric must be repeat_purchase or retained_balance",
+		})
+		return
+	}
+	from, to, err := services.CohortRange(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid range",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	response, hit, err := cache.Fetch(ctx, cache.InvestorCohortsKey(string(metric), from, to), investorCohortsTTL, func() (*models.CohortAnalyticsResponse, error) {
+		return services.NewIndexerQueryService().GetInvestorCohorts(ctx, metric, from, to)
+	})
+	setCacheStatus(c, hit)
+	if err != nil {
+		logger.WithError(err).Error("Failed to compute investor cohorts")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to compute investor cohorts",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetInvestorCohortsRejectsInvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/analytics/cohorts", GetInvestorCohorts)
+
+	for _, path := range []string{
+		"/admin/analytics/cohorts?metric=churn",
+		"/admin/analytics/cohorts?from=2024-13",
+		"/admin/analytics/cohorts?to=2999-01",
+		"/admin/analytics/cohorts?from=2024-06&to=2024-05",
+		"/admin/analytics/cohorts?from=2022-01&to=2024-01",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
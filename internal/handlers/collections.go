@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSON writes v as JSON with every empty collection in it as [] rather than null
+func respondJSON(c *gin.Context, status int, v interface{}) {
+	c.JSON(status, emptyCollections(v))
+}
+
+// emptyCollections returns v with every nil slice it reaches, through pointers, interfaces,
+// structs, slices and maps, replaced by an empty one. GORM and append leave empty results
+// nil, which encoding/json writes as null. Byte slices are left alone, since they encode as
+// base64 strings or raw JSON, and fields tagged omitempty stay omitted either way
+func emptyCollections(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	value := reflect.New(reflect.TypeOf(v)).Elem()
+	value.Set(reflect.ValueOf(v))
+	fillCollections(value, make(map[uintptr]bool))
+	return value.Interface()
+}
+
+// fillCollections replaces nil slices under a settable value; seen guards against pointer cycles
+func fillCollections(v reflect.Value, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		fillCollections(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// The dynamic value isn't settable, so it is filled in a copy
+		inner := reflect.New(v.Elem().Type()).Elem()
+		inner.Set(v.Elem())
+		fillCollections(inner, seen)
+		v.Set(inner)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillCollections(v.Field(i), seen)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			fillCollections(v.Index(i), seen)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillCollections(v.Index(i), seen)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			fillCollections(elem, seen)
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
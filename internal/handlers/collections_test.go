@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func marshalCollections(t *testing.T, v interface{}) string {
+	t.Helper()
+	body, err := json.Marshal(emptyCollections(v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestEmptyCollectionsReplacesNilSlices(t *testing.T) {
+	var nilProfiles []models.InvestorProfile
+	if got := marshalCollections(t, nilProfiles); got != "[]" {
+		t.Errorf("Expected a nil top-level slice as [], got %s", got)
+	}
+
+	portfolio := &models.PortfolioResponse{Holdings: []models.SukukHolding{{SukukAddress: "0xb1"}}}
+	got := marshalCollections(t, portfolio)
+	var decoded struct {
+		Holdings []map[string]json.RawMessage `json:"holdings"`
+	}
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	holding := decoded.Holdings[0]
+	if string(holding["unclaimed_distribution_ids"]) != "[]" || string(holding["yield_history"]) != "[]" {
+		t.Errorf("Expected nested nil slices as [], got %s", got)
+	}
+
+	body := marshalCollections(t, gin.H{"data": []string(nil), "nested": map[string][]int{"ids": nil}})
+	if body != `{"data":[],"nested":{"ids":[]}}` {
+		t.Errorf("Expected slices inside maps and interfaces as [], got %s", body)
+	}
+}
+
+func TestEmptyCollectionsKeepsBytesAndOmitted(t *testing.T) {
+	type response struct {
+		Raw     json.RawMessage `json:"raw"`
+		Bytes   []byte          `json:"bytes"`
+		Omitted []string        `json:"omitted,omitempty"`
+		Items   []string        `json:"items"`
+	}
+	got := marshalCollections(t, response{})
+	if got != `{"raw":null,"bytes":null,"items":[]}` {
+		t.Errorf("Unexpected body %s", got)
+	}
+
+	// The caller's value only gains empty slices where it had nil ones
+	original := &response{Items: []string{"a"}}
+	marshalCollections(t, original)
+	if len(original.Items) != 1 || original.Items[0] != "a" {
+		t.Errorf("Expected filled slices untouched, got %+v", original)
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSukukCouponSchedule returns the expected coupon calendar of a sukuk
+// @Summary Get sukuk coupon schedule
+// @Description Lay out every expected coupon from kupon_pertama to jatuh_tempo at the penerimaan_kupon frequency (monthly, quarterly, semiannual or annual) and mark each one paid by the nearest yield distribution within the grace window (coupon_schedule.grace_period setting, 7 days by default), missed once the window passes without one, or upcoming. Distributions matching no coupon are listed under extra_distributions. Without kupon_pertama or a recognizable frequency, estimated is false, coupons is empty and every distribution is extra
+// @Tags sukuk-metadata
+// @Produce json
+// @Param id path int true "Sukuk Metadata ID"
+// @Success 200 {object} models.CouponScheduleResponse "Coupon schedule"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/coupon-schedule [get]
+func GetSukukCouponSchedule(c *gin.Context) {
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	grace := services.Settings().GetDuration(services.SettingCouponGracePeriod, services.DefaultCouponGracePeriod)
+	schedule, err := services.NewIndexerQueryService().GetCouponSchedule(c.Request.Context(), sukukMetadata, grace, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("Failed to build coupon schedule")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to build coupon schedule",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, schedule)
+}
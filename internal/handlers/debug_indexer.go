@@ -33,7 +33,7 @@ func DebugIndexerConnection(c *gin.Context) {
 	}
 	
 	// Get purchase events
-	purchases, err := indexerService.GetSukukPurchases(address, 5)
+	purchases, err := indexerService.GetSukukPurchases(c.Request.Context(), address, 5)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to query purchases",
@@ -43,7 +43,7 @@ func DebugIndexerConnection(c *gin.Context) {
 	}
 	
 	// Get redemption events
-	redemptions, err := indexerService.GetRedemptionRequests(address, 5)
+	redemptions, err := indexerService.GetRedemptionRequests(c.Request.Context(), address, 5)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to query redemptions", 
@@ -53,7 +53,7 @@ func DebugIndexerConnection(c *gin.Context) {
 	}
 	
 	// Get activities
-	activities, err := indexerService.GetLatestActivities(address, 10)
+	activities, err := indexerService.GetLatestActivities(c.Request.Context(), address, 10)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get activities",
@@ -63,7 +63,7 @@ func DebugIndexerConnection(c *gin.Context) {
 	}
 	
 	// Test all purchases without address filter to see what data exists
-	allPurchases, err := indexerService.GetSukukPurchases("", 10)
+	allPurchases, err := indexerService.GetSukukPurchases(c.Request.Context(), "", 10)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get all purchases",
@@ -72,7 +72,7 @@ func DebugIndexerConnection(c *gin.Context) {
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
+	respondJSON(c, http.StatusOK, gin.H{
 		"address": address,
 		"purchases_count": len(purchases),
 		"purchases": purchases,
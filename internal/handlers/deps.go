@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"sync"
+
+	"sukuk-be/internal/services"
+)
+
+// Deps are the services the portfolio, transaction history and redemption handlers read
+// through. Nil fields fall back to the concrete services backed by the database
+type Deps struct {
+	Portfolio   services.PortfolioReader
+	Activity    services.ActivityReader
+	Redemptions services.RedemptionReader
+}
+
+var (
+	depsMu sync.RWMutex
+	deps   Deps
+)
+
+// SetDeps replaces the handler dependencies and returns the previous ones, e.g. so tests
+// can swap in fakes and restore them afterwards
+func SetDeps(d Deps) Deps {
+	depsMu.Lock()
+	defer depsMu.Unlock()
+	previous := deps
+	deps = d
+	return previous
+}
+
+// currentDeps returns the dependencies with nil fields filled by the concrete services
+func currentDeps() Deps {
+	depsMu.RLock()
+	d := deps
+	depsMu.RUnlock()
+
+	if d.Portfolio == nil || d.Activity == nil {
+		indexerService := services.NewIndexerQueryService()
+		if d.Portfolio == nil {
+			d.Portfolio = indexerService
+		}
+		if d.Activity == nil {
+			d.Activity = indexerService
+		}
+	}
+	if d.Redemptions == nil {
+		d.Redemptions = services.NewRedemptionService()
+	}
+	return d
+}
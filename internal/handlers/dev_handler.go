@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventInjector writes synthetic indexer events for local development
+type EventInjector interface {
+	Inject(ctx context.Context, eventType string, payload json.RawMessage) ([]services.InjectedEvent, error)
+	GenerateScenario(ctx context.Context, req services.ScenarioRequest) (*services.Scenario, error)
+}
+
+// InjectEventRequest is the payload of POST /dev/inject-event
+type InjectEventRequest struct {
+	EventType string          `json:"event_type" binding:"required" example:"sukuk_purchase"`
+	Payload   json.RawMessage `json:"payload" binding:"required" swaggertype:"object"`
+}
+
+// InjectEventResponse lists the rows an injection wrote
+type InjectEventResponse struct {
+	Events []services.InjectedEvent `json:"events"`
+}
+
+// InjectEvent writes one synthetic indexer event
+// @Summary Inject a synthetic indexer event
+// @Description Development only, enabled by DEV_EVENT_INJECTOR. Writes a sukuk_purchase, redemption_request, redemption_approval, yield_distributed, yield_claim or snapshot_taken event into the synthetic indexer tables, with the columns the indexer uses. Purchases and redemption requests also write the holder_update row for the new balance. Payload fields follow the indexer columns (buyer, user, sukuk_address, payment_token, amount, distribution_id) plus an optional unix timestamp; amounts are raw integers
+// @Tags dev
+// @Accept json
+// @Produce json
+// @Param request body InjectEventRequest true "Event type and payload"
+// @Success 201 {object} InjectEventResponse "Rows written"
+// @Failure 400 {object} map[string]string "Unsupported event type or invalid payload"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /dev/inject-event [post]
+func InjectEvent(injector EventInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req InjectEventRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		events, err := injector.Inject(c.Request.Context(), req.EventType, req.Payload)
+		if err != nil {
+			respondInjectorError(c, err, "Failed to inject event")
+			return
+		}
+
+		// Every cached read may include the new event
+		cache.InvalidateAll(c.Request.Context())
+		respondJSON(c, http.StatusCreated, InjectEventResponse{Events: events})
+	}
+}
+
+// GenerateScenario seeds a demo wallet with a full history
+// @Summary Generate a demo wallet scenario
+// @Description Development only, enabled by DEV_EVENT_INJECTOR. Gives a wallet three purchases, a snapshot and two yield distributions in each sukuk, plus an approved redemption and a claimed distribution in the first one, spread over the last 30 days. The wallet defaults to a new random address and the sukuk to the first two ready sukuk metadata
+// @Tags dev
+// @Accept json
+// @Produce json
+// @Param request body services.ScenarioRequest false "Wallet and sukuk"
+// @Success 201 {object} services.Scenario "Wallet, sukuk and rows written"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 409 {object} map[string]string "No ready sukuk metadata"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /dev/generate-scenario [post]
+func GenerateScenario(injector EventInjector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req services.ScenarioRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid request payload",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
+		scenario, err := injector.GenerateScenario(c.Request.Context(), req)
+		if err != nil {
+			respondInjectorError(c, err, "Failed to generate scenario")
+			return
+		}
+
+		cache.InvalidateAll(c.Request.Context())
+		respondJSON(c, http.StatusCreated, scenario)
+	}
+}
+
+// respondInjectorError maps injector errors to their status
+func respondInjectorError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidInjectedEvent):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrNoScenarioSukuk):
+		status = http.StatusConflict
+	default:
+		logger.WithError(err).Error(message)
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeEventInjector struct {
+	err      error
+	scenario services.ScenarioRequest
+}
+
+func (f *fakeEventInjector) Inject(ctx context.Context, eventType string, payload json.RawMessage) ([]services.InjectedEvent, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []services.InjectedEvent{{EventType: eventType, BlockNumber: 1}}, nil
+}
+
+func (f *fakeEventInjector) GenerateScenario(ctx context.Context, req services.ScenarioRequest) (*services.Scenario, error) {
+	f.scenario = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &services.Scenario{WalletAddress: req.WalletAddress}, nil
+}
+
+func TestDevHandlersMapInjectorErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache.SetDefault(cache.NewMemoryCache())
+
+	tests := []struct {
+		path string
+		body string
+		err  error
+		want int
+	}{
+		{"/dev/inject-event", `{"event_type":"sukuk_purchase","payload":{}}`, nil, http.StatusCreated},
+		{"/dev/inject-event", `{"payload":{}}`, nil, http.StatusBadRequest},
+		{"/dev/inject-event", `{"event_type":"holder_update","payload":{}}`, fmt.Errorf("%w: unsupported", services.ErrInvalidInjectedEvent), http.StatusBadRequest},
+		{"/dev/inject-event", `{"event_type":"sukuk_purchase","payload":{}}`, fmt.Errorf("connection refused"), http.StatusInternalServerError},
+		{"/dev/generate-scenario", ``, nil, http.StatusCreated},
+		{"/dev/generate-scenario", `{"wallet_address":`, nil, http.StatusBadRequest},
+		{"/dev/generate-scenario", ``, services.ErrNoScenarioSukuk, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		injector := &fakeEventInjector{err: tt.err}
+		router := gin.New()
+		router.POST("/dev/inject-event", InjectEvent(injector))
+		router.POST("/dev/generate-scenario", GenerateScenario(injector))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.path, tt.body, tt.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestGenerateScenarioPassesRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache.SetDefault(cache.NewMemoryCache())
+	injector := &fakeEventInjector{}
+	router := gin.New()
+	router.POST("/dev/generate-scenario", GenerateScenario(injector))
+
+	const wallet = "0x00000000000000000000000000000000000000a1"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/dev/generate-scenario", strings.NewReader(`{"wallet_address":"`+wallet+`"}`)))
+	if w.Code != http.StatusCreated || injector.scenario.WalletAddress != wallet {
+		t.Errorf("Expected the wallet to reach the injector, got %d and %+v", w.Code, injector.scenario)
+	}
+}
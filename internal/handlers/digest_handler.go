@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAddressDigest returns what happened to an address's holdings since its previous digest
+// @Summary Get the activity digest of an address
+// @Description Compiles yield distributions on sukuk the address holds (with its pro-rata entitlement from the current balance), its redemption requests and approvals, its holder_update balance changes, and held sukuk maturing within 30 days. Without since, the window starts where the previous digest ended (or 24 hours ago for the first) and the next digest starts where this one ends, so events are never repeated. An explicit since replays from that time and leaves the bookkeeping alone. Amounts are raw values
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor address"
+// @Param since query int false "Unix timestamp in seconds to replay from"
+// @Success 200 {object} models.DigestResponse "Digest"
+// @Failure 400 {object} map[string]string "Invalid address or since"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Another digest for this address was compiled at the same time"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/digest/{address} [get]
+func GetAddressDigest(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	now := time.Now()
+	var since *time.Time
+	if raw := c.Query("since"); raw != "" {
+		seconds, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid since",
+				"details": "since must be a unix timestamp in seconds",
+			})
+			return
+		}
+		at := time.Unix(seconds, 0)
+		if at.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid since",
+				"details": "since must not be in the future",
+			})
+			return
+		}
+		since = &at
+	}
+
+	digest, err := services.GenerateDigest(c.Request.Context(), address, since, now)
+	if err != nil {
+		if errors.Is(err, services.ErrDigestConflict) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Digest already in progress",
+				"details": err.Error(),
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to generate digest")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to generate digest",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, digest)
+}
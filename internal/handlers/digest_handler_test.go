@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetAddressDigestRejectsInvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/digest/:address", GetAddressDigest)
+
+	const digest = "/admin/digest/0x00000000000000000000000000000000000000a1"
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	for _, path := range []string{
+		"/admin/digest/not-an-address",
+		digest + "?since=yesterday",
+		digest + "?since=-1",
+		digest + "?since=" + future,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewDistribution computes each holder's share of a planned yield distribution
+// @Summary Preview a yield distribution
+// @Description Split total_amount of a payment token across the current holders of a sukuk (latest non-zero balances from the indexer) in proportion to their balances, rounding each share down. Reports the rounding dust left over, min/max/median entitlement, and flags holders entitled to less than the minimum (YIELD_MIN_ENTITLEMENT unless min_entitlement is given). Nothing is written
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Param request body models.DistributionPreviewRequest true "Planned distribution"
+// @Success 200 {object} models.DistributionPreview "Entitlements per holder"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 409 {object} map[string]string "Holder balances changed during the preview"
+// @Failure 422 {object} map[string]string "Sukuk has no holders"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/distribution-preview [post]
+func PreviewDistribution(defaultMinEntitlement string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.DistributionPreviewRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		mathUtil := utils.GlobalTokenMath
+		if !mathUtil.IsPositive(req.TotalAmount) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid total amount",
+				"details": "total_amount must be a positive integer in the token's smallest unit",
+			})
+			return
+		}
+		if !utils.IsValidEthereumAddress(req.PaymentToken) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid payment token address",
+			})
+			return
+		}
+		minEntitlement := defaultMinEntitlement
+		if req.MinEntitlement != "" {
+			if cmp, err := mathUtil.CompareTokenAmounts(req.MinEntitlement, "0"); err != nil || cmp < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid minimum entitlement",
+					"details": "min_entitlement must be a non-negative integer",
+				})
+				return
+			}
+			minEntitlement = req.MinEntitlement
+		}
+
+		sukukMetadata, ok := findSukukMetadataByID(c)
+		if !ok {
+			return
+		}
+
+		formatter, err := services.LoadTokenFormatter()
+		if err != nil {
+			logger.WithError(err).Error("Failed to load payment tokens")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load payment tokens",
+			})
+			return
+		}
+
+		indexerService := services.NewIndexerQueryService()
+		if err := indexerService.ConnectToIndexer(); err != nil {
+			logger.WithError(err).Error("Failed to connect to indexer")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to connect to indexer",
+			})
+			return
+		}
+
+		previewer := services.NewDistributionPreviewer(indexerService, formatter)
+		preview, err := previewer.Preview(c.Request.Context(), sukukMetadata.ContractAddress,
+			utils.NormalizeAddress(req.PaymentToken), req.TotalAmount, minEntitlement)
+		switch {
+		case errors.Is(err, services.ErrNoHolders):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "Sukuk has no holders",
+			})
+			return
+		case errors.Is(err, services.ErrHoldersChanged):
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Holder balances changed during the preview",
+				"details": "Retry the preview",
+			})
+			return
+		case err != nil:
+			logger.WithError(err).Error("Failed to preview yield distribution")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to preview yield distribution",
+			})
+			return
+		}
+		preview.SukukMetadataID = sukukMetadata.ID
+
+		respondJSON(c, http.StatusOK, preview)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPreviewDistributionRejectsInvalidBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/sukuk-metadata/:id/distribution-preview", PreviewDistribution("1"))
+
+	const token = "0x00000000000000000000000000000000000000c1"
+	for _, body := range []string{
+		`{}`,
+		`{"total_amount": "0", "payment_token": "` + token + `"}`,
+		`{"total_amount": "1.5", "payment_token": "` + token + `"}`,
+		`{"total_amount": "100", "payment_token": "idrx"}`,
+		`{"total_amount": "100", "payment_token": "` + token + `", "min_entitlement": "-1"}`,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/admin/sukuk-metadata/1/distribution-preview", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+}
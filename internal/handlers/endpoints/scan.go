@@ -0,0 +1,86 @@
+// Package endpoints finds the handlers of a Go package from its source, for the generated
+// handler list the server checks its route table against
+package endpoints
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Scan returns the sorted names of the exported functions in the package directory that
+// take a *gin.Context, or return a gin.HandlerFunc. Test files and files excluded by build
+// constraints are skipped
+func Scan(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			if ignored(file) {
+				continue
+			}
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || !fn.Name.IsExported() {
+					continue
+				}
+				if takesContext(fn.Type) || returnsHandler(fn.Type) {
+					names = append(names, fn.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ignored reports whether the file is left out of normal builds with //go:build ignore
+func ignored(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.TrimSpace(comment.Text) == "//go:build ignore" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// takesContext matches func(c *gin.Context)
+func takesContext(fn *ast.FuncType) bool {
+	if fn.Results != nil || fn.Params == nil || len(fn.Params.List) != 1 || len(fn.Params.List[0].Names) > 1 {
+		return false
+	}
+	star, ok := fn.Params.List[0].Type.(*ast.StarExpr)
+	return ok && isGinType(star.X, "Context")
+}
+
+// returnsHandler matches functions returning a single gin.HandlerFunc
+func returnsHandler(fn *ast.FuncType) bool {
+	if fn.Results == nil || len(fn.Results.List) != 1 || len(fn.Results.List[0].Names) > 1 {
+		return false
+	}
+	return isGinType(fn.Results.List[0].Type, "HandlerFunc")
+}
+
+func isGinType(expr ast.Expr, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "gin"
+}
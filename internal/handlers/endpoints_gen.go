@@ -0,0 +1,119 @@
+// Code generated by gen_endpoints.go; DO NOT EDIT.
+
+package handlers
+
+// Endpoints lists every exported handler of the package by name, so the server can check
+// each one is routed
+var Endpoints = []string{
+	"ClaimReferral",
+	"CleanupUploads",
+	"CreateInvestorProfile",
+	"CreateKYCReview",
+	"CreateOrder",
+	"CreatePaymentToken",
+	"CreatePayoutInstruction",
+	"CreateReferral",
+	"CreateSukukMetadata",
+	"DeactivateSukukDocument",
+	"DebugIndexerConnection",
+	"DeleteInvestorProfile",
+	"DeletePaymentToken",
+	"DenyProspectusUploads",
+	"DownloadPayoutProof",
+	"ExportSukukActivities",
+	"ForceSync",
+	"GenerateScenario",
+	"GetAPIKeyUsage",
+	"GetActivityFeed",
+	"GetAddressDigest",
+	"GetAllRedemptions",
+	"GetAllSnapshots",
+	"GetBalanceHistory",
+	"GetHashPrefixTables",
+	"GetHealthStatus",
+	"GetInvestmentCertificate",
+	"GetInvestorCohorts",
+	"GetInvestorKYCStatus",
+	"GetInvestorProfile",
+	"GetIssuerInvestorReport",
+	"GetNotificationPreferences",
+	"GetOrder",
+	"GetPaymentToken",
+	"GetPortfolioBatch",
+	"GetProspectusLink",
+	"GetReconciliationReport",
+	"GetRedemptionByID",
+	"GetRedemptionStats",
+	"GetRedemptionsBySukuk",
+	"GetRedemptionsByUser",
+	"GetReferralStats",
+	"GetRiwayatByAddress",
+	"GetSuitability",
+	"GetSukukAvailability",
+	"GetSukukCouponSchedule",
+	"GetSukukDocuments",
+	"GetSukukMetadata",
+	"GetSukukMetadataSnapshots",
+	"GetSukukMetadataTranslations",
+	"GetSukukMetadataV2",
+	"GetSukukMetadataValidationReport",
+	"GetSukukOwnedByAddress",
+	"GetSukukOwnedByAddressV2",
+	"GetSukukSnapshots",
+	"GetSukukTimeSeries",
+	"GetSukukVaultBalance",
+	"GetSyncHealth",
+	"GetSyncJob",
+	"GetSyncStatus",
+	"GetTableDetails",
+	"GetTaxReport",
+	"GetTransactionHistory",
+	"GetUserPortfolio",
+	"GetWalletAuthNonce",
+	"GetYieldClaimData",
+	"GetYieldClaims",
+	"GetYieldDistributions",
+	"InjectEvent",
+	"ListIndexerTables",
+	"ListInvestorProfiles",
+	"ListLedgerEntries",
+	"ListOrders",
+	"ListPaymentTokens",
+	"ListPayoutInstructions",
+	"ListRedemptionsV2",
+	"ListReorgIncidents",
+	"ListRoutes",
+	"ListSettings",
+	"ListSukukCreationTables",
+	"ListSukukDocuments",
+	"ListSukukMetadata",
+	"ListSukukMetadataConflicts",
+	"ListSukukMetadataV2",
+	"MarkSukukMetadataReady",
+	"MarkSukukMetadataUnready",
+	"OrderPaymentCallback",
+	"PreviewDistribution",
+	"PreviewSukukMetadataUpdate",
+	"PruneEvents",
+	"RecordSuitability",
+	"ResolveSukukMetadataConflict",
+	"ServeFileLink",
+	"SetIndexerTableOverrides",
+	"SetSukukMetadataTranslations",
+	"StreamActivities",
+	"TriggerSukukMetadataSync",
+	"Unsubscribe",
+	"UnsubscribePage",
+	"UpdateInvestorProfile",
+	"UpdateNotificationPreferences",
+	"UpdatePaymentToken",
+	"UpdatePayoutInstruction",
+	"UpdateSettings",
+	"UpdateSukukMetadata",
+	"UploadPayoutProof",
+	"UploadSukukDocument",
+	"ValidateIndexerTables",
+	"VerifyCertificate",
+	"VerifyWalletAuth",
+	"ViewAsInvestor",
+}
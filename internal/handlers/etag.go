@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contentETag hashes the JSON encoding of parts into a weak ETag, so any change to the
+// data or to the parameters it was selected with yields a different tag
+func contentETag(parts ...interface{}) (string, error) {
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, part := range parts {
+		if err := encoder.Encode(part); err != nil {
+			return "", err
+		}
+	}
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, comparing weakly
+// as RFC 9110 requires: W/ prefixes are ignored and "*" matches anything
+func etagMatches(ifNoneMatch, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets the ETag header and, when the request's If-None-Match matches it,
+// responds 304 without a body; callers return without writing anything when it is true
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch == "" || !etagMatches(ifNoneMatch, etag) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
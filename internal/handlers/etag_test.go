@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `W/"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`W/"abc"`, true},
+		{`"abc"`, true}, // Weak comparison ignores W/
+		{`"xyz", W/"abc"`, true},
+		{`*`, true},
+		{`"xyz"`, false},
+		{`abc`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestContentETag(t *testing.T) {
+	a, _ := contentETag("ready", 1, []string{"SR001"})
+	b, _ := contentETag("ready", 1, []string{"SR001"})
+	if a != b {
+		t.Errorf("Expected equal inputs to share an ETag, got %s and %s", a, b)
+	}
+	for _, parts := range [][]interface{}{
+		{"all", 1, []string{"SR001"}},   // Different filter
+		{"ready", 2, []string{"SR001"}}, // Different page
+		{"ready", 1, []string{"SR002"}}, // Different data
+	} {
+		if other, _ := contentETag(parts...); other == a {
+			t.Errorf("Expected %v to change the ETag", parts)
+		}
+	}
+}
+
+func TestNotModifiedFlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []string{"SR001"}
+	router := gin.New()
+	router.GET("/items", func(c *gin.Context) {
+		etag, _ := contentETag(data)
+		if notModified(c, etag) {
+			return
+		}
+		c.JSON(http.StatusOK, data)
+	})
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("Expected 200 with an ETag and a body, got %d %q %q", first.Code, etag, first.Body.String())
+	}
+
+	second := get(etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 || second.Header().Get("ETag") != etag {
+		t.Errorf("Expected 304 with the ETag and no body, got %d %q %q", second.Code, second.Header().Get("ETag"), second.Body.String())
+	}
+
+	data = []string{"SR001", "SR002"}
+	third := get(etag)
+	if third.Code != http.StatusOK || third.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with a new ETag after a change, got %d %q", third.Code, third.Header().Get("ETag"))
+	}
+}
+
+func TestSukukMetadataETagsMatchWrites(t *testing.T) {
+	previous := database.DB
+	database.DB = openStubDB(t, oneSukukMetadata)
+	defer func() { database.DB = previous }()
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		cache.SetDefault(cache.NewMemoryCache())
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/sukuk-metadata", ListSukukMetadata)
+		router.GET("/sukuk-metadata/:id", GetSukukMetadata)
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The detail carries the record version, the ETag writes return and If-Match takes
+	const plain = "/sukuk-metadata/1?include_stats=false&activities_limit=0"
+	full := get(plain, "")
+	if full.Code != http.StatusOK || full.Header().Get("ETag") != versionETag(3) {
+		t.Fatalf("Expected 200 with the version ETag, got %d %q", full.Code, full.Header().Get("ETag"))
+	}
+	if w := get(plain, versionETag(3)); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 without a body for an unchanged record, got %d %q", w.Code, w.Body.String())
+	}
+	if w := get(plain, versionETag(2)); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 after a write moved the version, got %d", w.Code)
+	}
+
+	// Activities change at the same version, so a response carrying them is always sent
+	enriched := get("/sukuk-metadata/1?include_stats=false", versionETag(3))
+	if enriched.Code != http.StatusOK || enriched.Header().Get("ETag") != versionETag(3) {
+		t.Errorf("Expected 200 with the version ETag when activities are served, got %d %q", enriched.Code, enriched.Header().Get("ETag"))
+	}
+
+	// The list is only validated without activities
+	list := get("/sukuk-metadata?include_stats=false&activities_limit=0", "")
+	etag := list.Header().Get("ETag")
+	if list.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected 200 with an ETag for the list without activities, got %d %q", list.Code, etag)
+	}
+	if w := get("/sukuk-metadata?include_stats=false&activities_limit=0", etag); w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for an unchanged list, got %d", w.Code)
+	}
+	if w := get("/sukuk-metadata?include_stats=false", ""); w.Header().Get("ETag") != "" {
+		t.Errorf("Expected no ETag on a list carrying activities, got %q", w.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+
+	"sukuk-be/internal/fx"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fiatDisplay prices token amounts in the currencies of ?fiat=idr,usd
+type fiatDisplay struct {
+	rates      *fx.Rates
+	currencies []string
+	used       map[[2]string]bool // Token and currency of every quote applied
+}
+
+// newFiatDisplay reads ?fiat and the current exchange rates. It returns nil, leaving display
+// values out, when no currency is requested, no provider is configured or the provider fails
+func newFiatDisplay(c *gin.Context) *fiatDisplay {
+	var currencies []string
+	seen := make(map[string]bool)
+	for _, currency := range strings.Split(c.Query("fiat"), ",") {
+		currency = strings.ToLower(strings.TrimSpace(currency))
+		if currency != "" && !seen[currency] {
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+	provider := fx.Default()
+	if len(currencies) == 0 || provider == nil {
+		return nil
+	}
+
+	rates, err := provider.Rates(c.Request.Context())
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load exchange rates, omitting display values")
+		return nil
+	}
+	return &fiatDisplay{rates: rates, currencies: currencies, used: make(map[[2]string]bool)}
+}
+
+// values sums the fiat value of amounts in each requested currency. A currency is left out
+// when a non-zero amount has no quote in it or isn't a valid decimal; nil when none remain
+func (d *fiatDisplay) values(amounts ...models.FormattedAmount) models.DisplayValues {
+	if d == nil {
+		return nil
+	}
+	values := make(models.DisplayValues, len(d.currencies))
+	for _, currency := range d.currencies {
+		total, ok := new(big.Rat), true
+		var used [][2]string
+		for _, amount := range amounts {
+			whole, valid := new(big.Rat).SetString(amount.Formatted)
+			if !valid {
+				ok = false
+				break
+			}
+			if whole.Sign() == 0 {
+				continue
+			}
+			price, quoted := d.rates.Quote(amount.Symbol, currency)
+			if !quoted {
+				ok = false
+				break
+			}
+			total.Add(total, new(big.Rat).Mul(whole, price))
+			used = append(used, [2]string{strings.ToUpper(amount.Symbol), currency})
+		}
+		if !ok {
+			continue
+		}
+		values[currency] = total.FloatString(2)
+		for _, quote := range used {
+			d.used[quote] = true
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// fields collects display values by field name, leaving out fields that couldn't be priced
+func (d *fiatDisplay) fields(amounts map[string][]models.FormattedAmount) map[string]models.DisplayValues {
+	if d == nil {
+		return nil
+	}
+	fields := make(map[string]models.DisplayValues, len(amounts))
+	for field, values := range amounts {
+		if priced := d.values(values...); priced != nil {
+			fields[field] = priced
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// meta lists the quotes applied so far, nil when there are none
+func (d *fiatDisplay) meta() *models.DisplayMeta {
+	if d == nil || len(d.used) == 0 {
+		return nil
+	}
+	meta := &models.DisplayMeta{FxRates: make([]models.FxRate, 0, len(d.used))}
+	for quote := range d.used {
+		price, _ := d.rates.Quote(quote[0], quote[1])
+		meta.FxRates = append(meta.FxRates, models.FxRate{
+			Token:    quote[0],
+			Currency: quote[1],
+			Rate:     strings.TrimRight(strings.TrimRight(price.FloatString(18), "0"), "."),
+			AsOf:     d.rates.AsOf,
+			Stale:    d.rates.Stale,
+		})
+	}
+	sort.Slice(meta.FxRates, func(i, j int) bool {
+		a, b := meta.FxRates[i], meta.FxRates[j]
+		return a.Token < b.Token || (a.Token == b.Token && a.Currency < b.Currency)
+	})
+	return meta
+}
+
+// sukukTokenAmount formats a raw sukuk token amount, quoted as IDRX
+func sukukTokenAmount(formatter *services.TokenFormatter, amount, sukukAddress string) models.FormattedAmount {
+	formatted := formatter.FormatTokenAmount(amount, sukukAddress)
+	formatted.Symbol = fx.SukukTokenSymbol
+	return formatted
+}
+
+// formattedOrRaw returns a formatted amount, or the raw one without a token when it wasn't
+// formatted, which only prices when zero
+func formattedOrRaw(formatted *models.FormattedAmount, raw string) models.FormattedAmount {
+	if formatted != nil {
+		return *formatted
+	}
+	if raw == "" {
+		raw = "0"
+	}
+	return models.FormattedAmount{Amount: raw, Formatted: raw}
+}
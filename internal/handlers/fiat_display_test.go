@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/fx"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeRateProvider returns fixed rates or a fixed error
+type fakeRateProvider struct {
+	rates *fx.Rates
+	err   error
+}
+
+func (p *fakeRateProvider) Rates(ctx context.Context) (*fx.Rates, error) {
+	return p.rates, p.err
+}
+
+var fakeRatesAsOf = time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+func withFakeRates(t *testing.T, provider fx.RateProvider) {
+	t.Helper()
+	previous := fx.Default()
+	fx.SetDefault(provider)
+	t.Cleanup(func() { fx.SetDefault(previous) })
+}
+
+func fakeRates(stale bool) *fx.Rates {
+	return &fx.Rates{
+		Quotes: map[string]map[string]*big.Rat{
+			"IDRX": {"idr": big.NewRat(1, 1), "usd": big.NewRat(61, 1000000)},
+			"USDC": {"usd": big.NewRat(1, 1)},
+		},
+		AsOf:  fakeRatesAsOf,
+		Stale: stale,
+	}
+}
+
+func fiatContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+	return c
+}
+
+func TestAvailabilityDisplayValues(t *testing.T) {
+	withFakeRates(t, &fakeRateProvider{rates: fakeRates(false)})
+	const sukuk = "0x00000000000000000000000000000000000000aa"
+	formatter := services.NewTokenFormatter([]models.PaymentToken{{Address: sukuk, Symbol: "SR022", Decimals: 2}})
+
+	availability := &models.SukukAvailability{ContractAddress: sukuk, Cap: "500000", TotalPurchased: "150050", Remaining: "349950"}
+	display := newFiatDisplay(fiatContext("fiat=idr,USD,idr"))
+	addAvailabilityDisplayValues(availability, display, formatter)
+
+	purchased := availability.DisplayValues["total_purchased"]
+	if purchased["idr"] != "1500.50" || purchased["usd"] != "0.09" || len(availability.DisplayValues) != 3 {
+		t.Errorf("Expected 1500.50 IDRX priced in idr and usd for all three amounts, got %v", availability.DisplayValues)
+	}
+	if availability.Meta == nil || len(availability.Meta.FxRates) != 2 {
+		t.Fatalf("Expected the two IDRX rates in meta, got %+v", availability.Meta)
+	}
+	if rate := availability.Meta.FxRates[1]; rate.Token != "IDRX" || rate.Currency != "usd" || rate.Rate != "0.000061" || !rate.AsOf.Equal(fakeRatesAsOf) {
+		t.Errorf("Expected the IDRX/usd rate as of the quote, got %+v", rate)
+	}
+
+	// Unlimited sukuk have no cap or remaining to price
+	availability = &models.SukukAvailability{ContractAddress: sukuk, Unlimited: true, TotalPurchased: "0"}
+	addAvailabilityDisplayValues(availability, newFiatDisplay(fiatContext("fiat=usd")), formatter)
+	if len(availability.DisplayValues) != 1 || availability.DisplayValues["total_purchased"]["usd"] != "0.00" {
+		t.Errorf("Expected only total_purchased, got %v", availability.DisplayValues)
+	}
+}
+
+func TestRedemptionStatsDisplayValues(t *testing.T) {
+	withFakeRates(t, &fakeRateProvider{rates: fakeRates(true)})
+	idrx := &models.FormattedAmount{Formatted: "1000", Symbol: "IDRX"}
+	usdc := &models.FormattedAmount{Formatted: "2.5", Symbol: "USDC"}
+	stats := &models.RedemptionStatsResponse{BySukuk: map[string]models.RedemptionSukukStats{
+		"0xa": {RequestedAmount: "100000", RequestedAmountFormatted: idrx},
+		"0xb": {RequestedAmount: "2500000", RequestedAmountFormatted: usdc, ApprovedAmount: "2500000", ApprovedAmountFormatted: usdc},
+	}}
+
+	addRedemptionStatsDisplayValues(stats, newFiatDisplay(fiatContext("fiat=idr,usd")))
+
+	// USDC has no idr quote, so idr is left out wherever USDC is involved
+	if values := stats.BySukuk["0xa"].DisplayValues["requested_amount"]; values["idr"] != "1000.00" || values["usd"] != "0.06" {
+		t.Errorf("Expected IDRX priced in both currencies, got %v", values)
+	}
+	if values := stats.BySukuk["0xb"].DisplayValues["approved_amount"]; len(values) != 1 || values["usd"] != "2.50" {
+		t.Errorf("Expected USDC priced in usd only, got %v", values)
+	}
+	if values := stats.DisplayValues["total_requested_amount"]; len(values) != 1 || values["usd"] != "2.56" {
+		t.Errorf("Expected a usd-only total of 0.061 + 2.5, got %v", values)
+	}
+	if stats.Meta == nil || !stats.Meta.FxRates[0].Stale {
+		t.Errorf("Expected stale rates to be flagged in meta, got %+v", stats.Meta)
+	}
+}
+
+func TestFiatDisplayDegradesWithoutRates(t *testing.T) {
+	// Not requested
+	withFakeRates(t, &fakeRateProvider{rates: fakeRates(false)})
+	if display := newFiatDisplay(fiatContext("")); display != nil {
+		t.Error("Expected no display values without ?fiat")
+	}
+
+	// The provider failing leaves display values out instead of failing the request
+	withFakeRates(t, &fakeRateProvider{err: errors.New("rates source down")})
+	display := newFiatDisplay(fiatContext("fiat=usd"))
+	if display != nil {
+		t.Fatal("Expected no display values when rates are unavailable")
+	}
+	availability := &models.SukukAvailability{TotalPurchased: "100"}
+	addAvailabilityDisplayValues(availability, display, services.NewTokenFormatter(nil))
+	if availability.DisplayValues != nil || availability.Meta != nil {
+		t.Errorf("Expected nothing added, got %+v", availability)
+	}
+
+	// Not configured
+	withFakeRates(t, nil)
+	if display := newFiatDisplay(fiatContext("fiat=usd")); display != nil {
+		t.Error("Expected no display values without a provider")
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldset is the set of top-level response fields a client asked for with ?fields=, as in
+// JSON:API sparse fieldsets. A nil fieldset selects every field
+type fieldset map[string]bool
+
+// jsonFieldNames caches the JSON field names of response types by type
+var jsonFieldNames sync.Map
+
+// responseFields returns the top-level JSON field names of T, following embedded structs
+func responseFields[T any]() map[string]bool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if names, ok := jsonFieldNames.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	collectJSONFields(t, names)
+	jsonFieldNames.Store(t, names)
+	return names
+}
+
+func collectJSONFields(t reflect.Type, names map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			if inner := field.Type; inner.Kind() == reflect.Struct || (inner.Kind() == reflect.Pointer && inner.Elem().Kind() == reflect.Struct) {
+				collectJSONFields(inner, names)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+}
+
+// parseFieldset reads the comma-separated fields query parameter, checking every name
+// against the JSON fields of the response type T. No parameter selects every field
+func parseFieldset[T any](c *gin.Context) (fieldset, error) {
+	raw := c.Query("fields")
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	allowed := responseFields[T]()
+	fields := make(fieldset)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !allowed[name] {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// has reports whether any of the names is selected, so enrichments that only fill
+// unselected fields can be skipped
+func (f fieldset) has(names ...string) bool {
+	if f == nil {
+		return true
+	}
+	for _, name := range names {
+		if f[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the selected fields sorted and comma-joined, for cache keys and ETags; empty
+// when every field is selected
+func (f fieldset) key() string {
+	if f == nil {
+		return ""
+	}
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// sparseItem marshals item keeping only the selected fields. Every field is kept for a
+// nil fieldset
+func sparseItem[T any](item T, f fieldset) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	if f != nil {
+		for name := range object {
+			if !f[name] {
+				delete(object, name)
+			}
+		}
+	}
+	return object, nil
+}
+
+// sparseItems applies sparseItem to every item
+func sparseItems[T any](items []T, f fieldset) ([]map[string]json.RawMessage, error) {
+	objects := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		object, err := sparseItem(item, f)
+		if err != nil {
+			return nil, err
+		}
+		objects[i] = object
+	}
+	return objects, nil
+}
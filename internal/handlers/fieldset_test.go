@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResponseFieldsFollowJSONTags(t *testing.T) {
+	type inner struct {
+		Shared string `json:"shared"`
+	}
+	type response struct {
+		inner
+		ID      uint    `json:"id"`
+		Hidden  string  `json:"-"`
+		Maybe   *string `json:"maybe,omitempty"`
+		private string
+	}
+
+	fields := responseFields[response]()
+	for _, name := range []string{"id", "maybe", "shared"} {
+		if !fields[name] {
+			t.Errorf("Expected %s to be a field, got %v", name, fields)
+		}
+	}
+	if len(fields) != 3 {
+		t.Errorf("Expected exactly 3 fields, got %v", fields)
+	}
+}
+
+// serveSukukMetadata runs the sukuk metadata routes against a stub database holding one
+// sukuk, and returns the response with every statement gorm sent
+func serveSukukMetadata(t *testing.T, target string) (*httptest.ResponseRecorder, []string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		return oneSukukMetadata(query)
+	})
+	defer func() { database.DB = previous }()
+	return routeSukukMetadata(target), queries
+}
+
+// oneSukukMetadata answers the sukuk_metadata lookups with a single sukuk and nothing else
+func oneSukukMetadata(query string) stubResult {
+	if strings.Contains(query, `FROM "sukuk_metadata"`) {
+		return stubResult{
+			columns: []string{"id", "contract_address", "sukuk_code", "sukuk_title", "imbal_hasil", "logo_url", "status", "version"},
+			rows:    [][]driver.Value{{int64(1), "0x00000000000000000000000000000000000f1e1d", "SR021", "Sukuk Ritel", "6.40%", "/uploads/sr021.png", "active", int64(3)}},
+		}
+	}
+	return stubResult{}
+}
+
+// routeSukukMetadata serves target from the sukuk metadata list and detail routes with an empty cache
+func routeSukukMetadata(target string) *httptest.ResponseRecorder {
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata", ListSukukMetadata)
+	router.GET("/sukuk-metadata/:id", GetSukukMetadata)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+// readsIndexer reports whether any statement looked up indexer tables, which every activity
+// and stats enrichment starts with
+func readsIndexer(queries []string) bool {
+	for _, query := range queries {
+		if strings.Contains(query, "information_schema") || strings.Contains(query, "indexer_table_overrides") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSukukMetadataSparseFieldsets(t *testing.T) {
+	const minimal = "fields=sukuk_code,sukuk_title,imbal_hasil,logo_url"
+	address := "&address=0x00000000000000000000000000000000000000ab"
+
+	w, queries := serveSukukMetadata(t, "/sukuk-metadata?"+minimal+address)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var items []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("Failed to decode list: %v", err)
+	}
+	want := map[string]interface{}{"sukuk_code": "SR021", "sukuk_title": "Sukuk Ritel", "imbal_hasil": "6.40%", "logo_url": "/uploads/sr021.png"}
+	if len(items) != 1 || len(items[0]) != len(want) {
+		t.Fatalf("Expected one item with %d fields, got %v", len(want), items)
+	}
+	for name, value := range want {
+		if items[0][name] != value {
+			t.Errorf("Expected %s = %v, got %v", name, value, items[0][name])
+		}
+	}
+	if readsIndexer(queries) {
+		t.Errorf("Expected no indexer lookups without activity, stats or position fields, got %v", queries)
+	}
+
+	w, queries = serveSukukMetadata(t, "/sukuk-metadata/1?"+minimal)
+	var item map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &item); err != nil || w.Code != http.StatusOK || len(item) != len(want) {
+		t.Fatalf("Expected the detail with %d fields, got %d: %s", len(want), w.Code, w.Body.String())
+	}
+	if readsIndexer(queries) {
+		t.Errorf("Expected no indexer lookups for the detail, got %v", queries)
+	}
+
+	// Asking for activities, or for everything, still looks them up
+	for _, target := range []string{"/sukuk-metadata?fields=sukuk_code,latest_activities", "/sukuk-metadata", "/sukuk-metadata/1"} {
+		w, queries := serveSukukMetadata(t, target)
+		if w.Code != http.StatusOK || !readsIndexer(queries) {
+			t.Errorf("%s: expected the indexer to be read, got %d", target, w.Code)
+		}
+	}
+	var full []map[string]interface{}
+	w, _ = serveSukukMetadata(t, "/sukuk-metadata")
+	if err := json.Unmarshal(w.Body.Bytes(), &full); err != nil || len(full) != 1 || full[0]["latest_activities"] == nil {
+		t.Errorf("Expected the full response to keep latest_activities, got %s", w.Body.String())
+	}
+}
+
+func TestSukukMetadataRejectsUnknownFields(t *testing.T) {
+	for _, target := range []string{"/sukuk-metadata?fields=sukuk_code,password", "/sukuk-metadata/1?fields=SukukCode"} {
+		w, _ := serveSukukMetadata(t, target)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d: %s", target, w.Code, w.Body.String())
+		}
+	}
+}
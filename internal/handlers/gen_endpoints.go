@@ -0,0 +1,42 @@
+//go:build ignore
+
+// gen_endpoints writes endpoints_gen.go, the list of every exported handler in this package:
+// functions taking a *gin.Context, or returning a gin.HandlerFunc. Run it with go generate
+// after adding or removing a handler
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+
+	"sukuk-be/internal/handlers/endpoints"
+)
+
+func main() {
+	names, err := endpoints.Scan(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_endpoints.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package handlers\n\n")
+	buf.WriteString("// Endpoints lists every exported handler of the package by name, so the server can check\n")
+	buf.WriteString("// each one is routed\n")
+	buf.WriteString("var Endpoints = []string{\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\t%q,\n", name)
+	}
+	buf.WriteString("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("endpoints_gen.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,18 +1,21 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"sukuk-be/internal/database"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ListIndexerTables returns all discovered indexer tables with metadata
 // @Summary List indexer tables
-// @Description Get all discovered hash-prefixed indexer tables with metadata and row counts
+// @Description Get all discovered hash-prefixed indexer tables with metadata and row counts. Tables pinned by an override are flagged overridden, and latest_tables reflects the overrides
 // @Tags debug
 // @Accept json
 // @Produce json
@@ -51,6 +54,15 @@ func ListIndexerTables(c *gin.Context) {
 		return
 	}
 
+	overrides, err := tableService.TableOverrides()
+	if err != nil {
+		logger.WithError(err).Error("Failed to get indexer table overrides")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get indexer table overrides",
+		})
+		return
+	}
+
 	// Get available event types
 	eventTypes, err := tableService.GetAvailableEventTypes()
 	if err != nil {
@@ -67,6 +79,7 @@ func ListIndexerTables(c *gin.Context) {
 		AvailableEvents: eventTypes,
 		Tables:          make([]models.IndexerTableInfo, len(discoveredTables)),
 		LatestTables:    latestTables,
+		Overrides:       overrides,
 	}
 
 	// Populate table information with row counts
@@ -75,6 +88,7 @@ func ListIndexerTables(c *gin.Context) {
 			EventType:  table.EventType,
 			TableName:  table.FullName,
 			HashPrefix: table.HashPrefix,
+			Overridden: overrides[table.EventType] == table.FullName,
 		}
 
 		// Get row count for each table
@@ -86,7 +100,7 @@ func ListIndexerTables(c *gin.Context) {
 		response.Tables[i] = tableInfo
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // ValidateIndexerTables validates the structure of all discovered tables
@@ -151,7 +165,7 @@ func ValidateIndexerTables(c *gin.Context) {
 		"all_valid":       invalidTables == 0,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // GetTableDetails returns detailed information about a specific table
@@ -174,6 +188,14 @@ func GetTableDetails(c *gin.Context) {
 		})
 		return
 	}
+	// Names go into raw SQL, so anything but an indexer table name is refused before touching the DB
+	if err := services.ValidateTableName(tableName); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid table name",
+			"details": "table name must look like <hash>__<event>, e.g. f243__sukuk_purchase",
+		})
+		return
+	}
 
 	// Initialize table discovery service
 	tableService := services.NewIndexerTableService()
@@ -219,7 +241,7 @@ func GetTableDetails(c *gin.Context) {
 		"schema":     "public",
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // GetHashPrefixTables returns all tables with a specific hash prefix
@@ -287,5 +309,87 @@ func GetHashPrefixTables(c *gin.Context) {
 		"tables":       apiTables,
 	}
 
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	respondJSON(c, http.StatusOK, response)
+}
+// indexerTableOverrideEntity is the audit log entity type for indexer table overrides
+const indexerTableOverrideEntity = "indexer_table_override"
+
+// SetIndexerTableOverrides pins or unpins the indexer table read for event types
+// @Summary Set indexer table overrides
+// @Description Pin the table read for an event type (the table name suffix, e.g. holder_update), bypassing the max block / row count discovery heuristic. An empty table name removes the override. Every table must exist, belong to the event type and have the common event columns, or nothing is changed. Takes effect on the next indexer read
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param overrides body models.IndexerTableOverridesRequest true "Table name per event type"
+// @Success 200 {array} models.IndexerTableOverride "Overrides after the change"
+// @Failure 400 {object} map[string]string "Invalid or non-existent table"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/indexer-tables/overrides [put]
+func SetIndexerTableOverrides(c *gin.Context) {
+	var req models.IndexerTableOverridesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tableService := services.NewIndexerTableService()
+	for eventType, tableName := range req.Overrides {
+		if tableName == "" {
+			continue
+		}
+		if err := tableService.ValidateTableOverride(eventType, tableName); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, services.ErrInvalidTableOverride) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{
+				"error":   "Invalid indexer table override",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	var overrides []models.IndexerTableOverride
+	err := database.GetDB().WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		for eventType, tableName := range req.Overrides {
+			action := models.AuditActionUpdate
+			var err error
+			if tableName == "" {
+				action = models.AuditActionDelete
+				err = models.DeleteIndexerTableOverride(tx, eventType)
+			} else {
+				err = models.SetIndexerTableOverride(tx, eventType, tableName)
+			}
+			if err != nil {
+				return err
+			}
+			if err := models.RecordAudit(tx, action, indexerTableOverrideEntity, eventType, auditActor(c), gin.H{"table_name": tableName}); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		overrides, err = models.GetIndexerTableOverrides(tx)
+		return err
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to set indexer table overrides")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to set indexer table overrides",
+		})
+		return
+	}
+
+	if overrides == nil {
+		overrides = []models.IndexerTableOverride{}
+	}
+
+	logger.WithField("overrides", req.Overrides).Info("Indexer table overrides changed")
+	respondJSON(c, http.StatusOK, overrides)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetTableDetailsRejectsInvalidTableNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/debug/indexer-tables/:table_name", GetTableDetails)
+
+	// No database is configured, so only a validation failure can answer 400
+	for _, name := range []string{
+		"foo; DROP TABLE companies;--",
+		`f243__sukuk_purchase"; DROP TABLE sukuk_metadata;--`,
+		"sukuk_metadata",
+		"F243__sukuk_purchase",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/indexer-tables/"+url.PathEscape(name), nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", name, w.Code)
+		}
+	}
+}
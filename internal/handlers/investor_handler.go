@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const investorProfileEntity = "investor_profile"
+
+// ListInvestorProfiles returns all investor profiles
+// @Summary List investor profiles
+// @Description Get all investor profiles with optional filtering by KYC status
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param kyc_status query string false "Filter by KYC status" Enums(pending, verified, rejected)
+// @Success 200 {array} models.InvestorProfile "List of investor profiles"
+// @Failure 400 {object} map[string]string "Invalid KYC status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors [get]
+func ListInvestorProfiles(c *gin.Context) {
+	query := database.GetDB().Order("created_at DESC")
+
+	if status := c.Query("kyc_status"); status != "" {
+		if !models.KYCStatus(status).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid KYC status",
+			})
+			return
+		}
+		query = query.Where("kyc_status = ?", status)
+	}
+
+	var profiles []models.InvestorProfile
+	if err := query.Find(&profiles).Error; err != nil {
+		logger.WithError(err).Error("Failed to fetch investor profiles")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch investor profiles",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, profiles)
+}
+
+// GetInvestorProfile returns an investor profile with its KYC review history
+// @Summary Get investor profile
+// @Description Get an investor profile by wallet address, including KYC reviews
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor wallet address"
+// @Success 200 {object} models.InvestorProfile "Investor profile"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Investor profile not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors/{address} [get]
+func GetInvestorProfile(c *gin.Context) {
+	var profile models.InvestorProfile
+	err := database.GetDB().WithContext(c.Request.Context()).
+		Preload("Reviews", func(db *gorm.DB) *gorm.DB { return db.Order("created_at DESC") }).
+		Where("wallet_address = ?", utils.NormalizeAddress(c.Param("address"))).
+		First(&profile).Error
+	if !investorProfileFound(c, err) {
+		return
+	}
+
+	respondJSON(c, http.StatusOK, profile)
+}
+
+// CreateInvestorProfile creates a new investor profile with pending KYC status
+// @Summary Create investor profile
+// @Description Create an investor profile linked to a wallet address. New profiles start with pending KYC status.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param investor body models.InvestorProfileCreateRequest true "Investor profile"
+// @Success 201 {object} models.InvestorProfile "Created investor profile"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Investor profile already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors [post]
+func CreateInvestorProfile(c *gin.Context) {
+	var req models.InvestorProfileCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !utils.IsValidEthereumAddress(req.WalletAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid wallet address",
+		})
+		return
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	_, err := models.GetInvestorProfileByAddress(db, req.WalletAddress)
+	if err == nil {
+		respondInvestorProfileExists(c)
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.WithError(err).Error("Failed to fetch investor profile")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to create investor profile",
+		})
+		return
+	}
+
+	profile := models.InvestorProfile{
+		WalletAddress: req.WalletAddress,
+		Name:          req.Name,
+		Email:         req.Email,
+		KYCStatus:     models.KYCStatusPending,
+		DocumentRefs:  req.DocumentRefs,
+	}
+
+	// A concurrent create for the same wallet can still get past the lookup
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := models.CreateInvestorProfile(tx, &profile); err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionCreate, investorProfileEntity, profile.WalletAddress, auditActor(c), req)
+	})
+	if errors.Is(err, models.ErrInvestorProfileExists) {
+		respondInvestorProfileExists(c)
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to create investor profile")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to create investor profile",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// UpdateInvestorProfile updates an investor profile
+// @Summary Update investor profile
+// @Description Update investor details or KYC status. A rejected investor can only leave rejected, to pending or verified, by recording a new review.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor wallet address"
+// @Param investor body models.InvestorProfileUpdateRequest true "Fields to update"
+// @Success 200 {object} models.InvestorProfile "Updated investor profile"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Investor profile not found"
+// @Failure 422 {object} map[string]string "Invalid KYC status transition"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors/{address} [put]
+func UpdateInvestorProfile(c *gin.Context) {
+	var req models.InvestorProfileUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	profile, err := models.GetInvestorProfileByAddress(db, c.Param("address"))
+	if !investorProfileFound(c, err) {
+		return
+	}
+
+	if req.Name != nil {
+		profile.Name = *req.Name
+	}
+	if req.Email != nil {
+		profile.Email = *req.Email
+	}
+	if req.DocumentRefs != nil {
+		profile.DocumentRefs = req.DocumentRefs
+	}
+	if req.KYCStatus != nil {
+		if err := profile.ApplyKYCStatus(*req.KYCStatus, false); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(profile).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionUpdate, investorProfileEntity, profile.WalletAddress, auditActor(c), req)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to update investor profile")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update investor profile",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, profile)
+}
+
+// DeleteInvestorProfile removes an investor profile and its KYC reviews
+// @Summary Delete investor profile
+// @Description Remove an investor profile and its KYC review history
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor wallet address"
+// @Success 200 {object} MessageResponse "Investor profile deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Investor profile not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors/{address} [delete]
+func DeleteInvestorProfile(c *gin.Context) {
+	db := database.GetDB().WithContext(c.Request.Context())
+	profile, err := models.GetInvestorProfileByAddress(db, c.Param("address"))
+	if !investorProfileFound(c, err) {
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("investor_profile_id = ?", profile.ID).Delete(&models.KYCReview{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(profile).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionDelete, investorProfileEntity, profile.WalletAddress, auditActor(c), nil)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to delete investor profile")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete investor profile",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, MessageResponse{
+		Message: "Investor profile deleted",
+	})
+}
+
+// CreateKYCReview records a KYC review and applies its decision
+// @Summary Record KYC review
+// @Description Record a compliance review for an investor and move the investor to the review decision
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor wallet address"
+// @Param review body models.KYCReviewRequest true "KYC review"
+// @Success 201 {object} models.InvestorProfile "Investor profile with updated KYC status"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Investor profile not found"
+// @Failure 422 {object} map[string]string "Invalid KYC status transition"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/investors/{address}/reviews [post]
+func CreateKYCReview(c *gin.Context) {
+	var req models.KYCReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	profile, err := models.GetInvestorProfileByAddress(db, c.Param("address"))
+	if !investorProfileFound(c, err) {
+		return
+	}
+
+	if err := profile.ApplyKYCStatus(req.Decision, true); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	review := models.KYCReview{
+		InvestorProfileID: profile.ID,
+		Reviewer:          req.Reviewer,
+		Decision:          req.Decision,
+		Notes:             req.Notes,
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(profile).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionUpdate, investorProfileEntity, profile.WalletAddress, auditActor(c), req)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to record KYC review")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to record KYC review",
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"wallet_address": profile.WalletAddress,
+		"decision":       req.Decision,
+	}).Info("KYC review recorded")
+
+	profile.Reviews = []models.KYCReview{review}
+	c.JSON(http.StatusCreated, profile)
+}
+
+// GetInvestorKYCStatus returns only the KYC status for a wallet address
+// @Summary Get investor KYC status
+// @Description Get the KYC status for a wallet address without exposing identity data
+// @Tags investors
+// @Accept json
+// @Produce json
+// @Param address path string true "Investor wallet address"
+// @Success 200 {object} models.KYCStatusResponse "KYC status"
+// @Failure 404 {object} map[string]string "Investor not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /investors/{address}/status [get]
+func GetInvestorKYCStatus(c *gin.Context) {
+	profile, err := models.GetInvestorProfileByAddress(database.GetDB(), c.Param("address"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Investor not found",
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to fetch investor KYC status")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch KYC status",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.KYCStatusResponse{
+		WalletAddress: profile.WalletAddress,
+		KYCStatus:     profile.KYCStatus,
+	})
+}
+
+// adminKYCStatus returns the investor's KYC status for admin requests, empty otherwise
+func adminKYCStatus(c *gin.Context, address string) models.KYCStatus {
+	if !middleware.IsAdmin(c) {
+		return ""
+	}
+
+	profile, err := models.GetInvestorProfileByAddress(database.GetDB(), address)
+	if err != nil {
+		return ""
+	}
+	return profile.KYCStatus
+}
+
+// auditActor identifies the caller of an admin write for the audit log
+func auditActor(c *gin.Context) string {
+	return "api-key:" + c.ClientIP()
+}
+
+// investorProfileFound reports whether the investor profile lookup that returned err found
+// one, responding 404 when it is missing and with the query error status otherwise
+func investorProfileFound(c *gin.Context, err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Investor profile not found",
+		})
+		return false
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch investor profile")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch investor profile",
+		})
+		return false
+	}
+	return true
+}
+
+// respondInvestorProfileExists answers a create for a wallet that already has a profile
+func respondInvestorProfileExists(c *gin.Context) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error": "Investor profile already exists",
+	})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveInvestorProfiles serves one request from the investor profile routes, answering the
+// database from respond
+func serveInvestorProfiles(t *testing.T, respond func(query string) stubResult, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	previous := database.DB
+	database.DB = openStubDB(t, respond)
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/investors/:address", GetInvestorProfile)
+	router.POST("/admin/investors", CreateInvestorProfile)
+	router.PUT("/admin/investors/:address", UpdateInvestorProfile)
+	router.DELETE("/admin/investors/:address", DeleteInvestorProfile)
+	router.POST("/admin/investors/:address/reviews", CreateKYCReview)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestInvestorProfileLookupErrorsAreNotMissingProfiles(t *testing.T) {
+	const target = "/admin/investors/0x1234567890123456789012345678901234567890"
+	requests := []struct{ method, target, body string }{
+		{http.MethodGet, target, ""},
+		{http.MethodPut, target, `{"name":"Investor"}`},
+		{http.MethodDelete, target, ""},
+		{http.MethodPost, target + "/reviews", `{"reviewer":"compliance","decision":"verified"}`},
+		{http.MethodPost, "/admin/investors", `{"wallet_address":"0x1234567890123456789012345678901234567890"}`},
+	}
+
+	outage := func(string) stubResult { return stubResult{err: errors.New("connection refused")} }
+	missing := func(string) stubResult { return stubResult{} }
+	for _, r := range requests {
+		if w := serveInvestorProfiles(t, outage, r.method, r.target, r.body); w.Code != http.StatusInternalServerError {
+			t.Errorf("%s %s: expected 500 when the database fails, got %d", r.method, r.target, w.Code)
+		}
+		if r.target == "/admin/investors" {
+			continue
+		}
+		if w := serveInvestorProfiles(t, missing, r.method, r.target, r.body); w.Code != http.StatusNotFound {
+			t.Errorf("%s %s: expected 404 for a missing profile, got %d", r.method, r.target, w.Code)
+		}
+	}
+}
+
+func TestCreateInvestorProfileConflictsOnConcurrentCreate(t *testing.T) {
+	// The lookup finds nothing, then the insert hits the unique wallet address and stores no row
+	w := serveInvestorProfiles(t, func(string) stubResult { return stubResult{} }, http.MethodPost, "/admin/investors",
+		`{"wallet_address":"0x1234567890123456789012345678901234567890"}`)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 when the insert conflicts, got %d: %s", w.Code, w.Body.String())
+	}
+}
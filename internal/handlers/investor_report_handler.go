@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetIssuerInvestorReport returns a month of investor activity on an issuer's sukuk
+// @Summary Get monthly investor activity report for an issuer
+// @Description For every investor active during the month (Asia/Jakarta boundaries) on sukuk whose owner_address is the issuer, sum purchases, redemption requests, approved redemptions and yield claimed, one row per investor per sukuk, with the investor's KYC status when a profile exists. Amounts are raw values. CSV is streamed as a download; months without activity return only the header row
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param address path string true "Issuer (sukuk owner) address"
+// @Param month query string false "Month as YYYY-MM, defaults to the current month" Example(2024-05)
+// @Param format query string false "Output format" Enums(json, csv) default(csv)
+// @Success 200 {object} models.InvestorReport "Investor activity per sukuk"
+// @Failure 400 {object} map[string]string "Invalid address, month or format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/issuers/{address}/investor-report [get]
+func GetIssuerInvestorReport(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid issuer address",
+		})
+		return
+	}
+
+	now := time.Now()
+	month := c.DefaultQuery("month", services.CurrentReportMonth(now))
+	if err := services.ValidateReportMonth(month, now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid month",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", "csv"))
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported format",
+			"details": "format must be one of csv, json",
+		})
+		return
+	}
+
+	report, err := services.GenerateInvestorReport(c.Request.Context(), address, month)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate investor report")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to generate investor report",
+		})
+		return
+	}
+
+	if format == "json" {
+		respondJSON(c, http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.InvestorReportFilename(report)))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := services.WriteInvestorReportCSV(c.Writer, report); err != nil {
+		logger.WithError(err).Error("Failed to write investor report")
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetIssuerInvestorReportRejectsInvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/issuers/:address/investor-report", GetIssuerInvestorReport)
+
+	const issuer = "/admin/issuers/0x00000000000000000000000000000000000000a1/investor-report"
+	for _, path := range []string{
+		"/admin/issuers/not-an-address/investor-report",
+		issuer + "?month=2024-13",
+		issuer + "?month=2999-01",
+		issuer + "?month=2024-05&format=xlsx",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
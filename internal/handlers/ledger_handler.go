@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLedgerEntries returns ledger entries, with per-token balances when filtered by account
+// @Summary List ledger entries
+// @Description Get the double-entry ledger of purchases, redemption payouts and yield payments, newest first. Each movement is a debit to the account receiving value and an equal credit to the account paying it; a sukuk's treasury account is its contract address. With account, balances sums the account's matching entries per token (debits minus credits). Amounts are raw values
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param account query string false "Wallet or sukuk treasury address; adds per-token balances"
+// @Param sukuk_address query string false "Filter by sukuk contract address"
+// @Param type query string false "Filter by movement" Enums(purchase, redemption_payout, yield_payment)
+// @Param from query string false "Range start, inclusive (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Range end, exclusive (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Number of entries to return" default(50) minimum(1) maximum(200)
+// @Param offset query int false "Number of entries to skip" default(0) minimum(0)
+// @Success 200 {object} models.LedgerListResponse "Ledger entries"
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/ledger [get]
+func ListLedgerEntries(c *gin.Context) {
+	filter := models.LedgerFilter{
+		Account:      c.Query("account"),
+		SukukAddress: c.Query("sukuk_address"),
+		EventType:    models.LedgerEventType(c.Query("type")),
+	}
+	for _, param := range []string{"account", "sukuk_address"} {
+		if address := c.Query(param); address != "" && !utils.IsValidEthereumAddress(address) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid " + param,
+				"details": param + " must be a 0x-prefixed Ethereum address",
+			})
+			return
+		}
+	}
+	if filter.EventType != "" && !filter.EventType.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid type",
+			"details": "type must be purchase, redemption_payout or yield_payment",
+		})
+		return
+	}
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseTimeSeriesDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid " + bound.param,
+				"details": bound.param + " must be RFC3339 or YYYY-MM-DD",
+			})
+			return
+		}
+		*bound.target = &t
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	entries, total, err := models.ListLedgerEntries(db, filter)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch ledger entries")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch ledger entries",
+		})
+		return
+	}
+	response := models.LedgerListResponse{Entries: entries, TotalCount: total}
+
+	if filter.Account != "" {
+		if response.Balances, err = models.GetLedgerBalances(db, filter); err != nil {
+			logger.WithError(err).Error("Failed to compute ledger balances")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to compute ledger balances",
+			})
+			return
+		}
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const uploadsEntity = "uploads"
+
+// UploadCleaner finds and deletes orphaned upload files
+type UploadCleaner interface {
+	Run(ctx context.Context, dryRun bool) (*services.UploadCleanupResult, error)
+}
+
+// EventPruner counts and deletes processed events past their retention
+type EventPruner interface {
+	Run(ctx context.Context, dryRun bool) (*services.RetentionResult, error)
+}
+
+// CleanupUploads deletes upload files no longer referenced by any record
+// @Summary Clean up orphaned uploads
+// @Description Delete uploaded files that no sukuk metadata references and that are older than the grace period. With dry_run=true, only list the files that would be deleted
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "List candidates without deleting"
+// @Success 200 {object} services.UploadCleanupResult "Cleanup result"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance/cleanup-uploads [post]
+func CleanupUploads(cleaner UploadCleaner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun := c.Query("dry_run") == "true"
+
+		result, err := cleaner.Run(c.Request.Context(), dryRun)
+		if err != nil {
+			logger.WithError(err).Error("Failed to clean up uploads")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error":   "Failed to clean up uploads",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if !dryRun && result.Deleted > 0 {
+			if err := models.RecordAudit(database.GetDB(), models.AuditActionDelete, uploadsEntity, "orphaned", auditActor(c), result); err != nil {
+				logger.WithError(err).Warn("Failed to record upload cleanup audit")
+			}
+		}
+
+		respondJSON(c, http.StatusOK, result)
+	}
+}
+
+// PruneEvents deletes processed events older than each table's retention
+// @Summary Prune processed events
+// @Description Delete processed sukuk purchase and redemption request events older than the configured retention (RETENTION_*_DAYS), in bounded batches. Unprocessed events are never deleted. With dry_run=true, only report the eligible rows per table
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "Count eligible rows without deleting"
+// @Success 200 {object} services.RetentionResult "Prune result per table"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance/prune [post]
+func PruneEvents(pruner EventPruner) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun := c.Query("dry_run") == "true"
+
+		result, err := pruner.Run(c.Request.Context(), dryRun)
+		if err != nil {
+			logger.WithError(err).Error("Failed to prune events")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error":   "Failed to prune events",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := services.RecordRetentionAudit(database.GetDB(), auditActor(c), result); err != nil {
+			logger.WithError(err).Warn("Failed to record retention audit")
+		}
+
+		respondJSON(c, http.StatusOK, result)
+	}
+}
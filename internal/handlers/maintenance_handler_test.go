@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeUploadCleaner struct {
+	dryRun bool
+}
+
+func (f *fakeUploadCleaner) Run(ctx context.Context, dryRun bool) (*services.UploadCleanupResult, error) {
+	f.dryRun = dryRun
+	return &services.UploadCleanupResult{
+		DryRun:     dryRun,
+		Scanned:    2,
+		Candidates: []services.UploadFile{{Name: "logos/orphan.png", Size: 10}},
+	}, nil
+}
+
+func TestCleanupUploadsDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cleaner := &fakeUploadCleaner{}
+	router := gin.New()
+	router.POST("/admin/maintenance/cleanup-uploads", CleanupUploads(cleaner))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/maintenance/cleanup-uploads?dry_run=true", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !cleaner.dryRun {
+		t.Error("Expected dry_run=true to reach the cleaner")
+	}
+	var result services.UploadCleanupResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !result.DryRun || len(result.Candidates) != 1 || result.Candidates[0].Name != "logos/orphan.png" {
+		t.Errorf("Unexpected response: %+v", result)
+	}
+}
+
+type fakeEventPruner struct {
+	dryRun bool
+}
+
+func (f *fakeEventPruner) Run(ctx context.Context, dryRun bool) (*services.RetentionResult, error) {
+	f.dryRun = dryRun
+	return &services.RetentionResult{
+		DryRun: dryRun,
+		Tables: []services.RetentionTableResult{{Table: "sukuk_purchased_events", RetentionDays: 90, Eligible: 12}},
+	}, nil
+}
+
+func TestPruneEventsDryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pruner := &fakeEventPruner{}
+	router := gin.New()
+	router.POST("/admin/maintenance/prune", PruneEvents(pruner))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/maintenance/prune?dry_run=true", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !pruner.dryRun {
+		t.Error("Expected dry_run=true to reach the pruner")
+	}
+	var result services.RetentionResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !result.DryRun || len(result.Tables) != 1 || result.Tables[0].Eligible != 12 || result.Tables[0].Deleted != 0 {
+		t.Errorf("Unexpected response: %+v", result)
+	}
+}
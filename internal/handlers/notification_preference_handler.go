@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// GetNotificationPreferences returns a wallet's notification preferences
+// @Summary Get notification preferences
+// @Description Get the notification preferences of a wallet, or the defaults (everything enabled, Indonesian, no email) if it has never set any. The email is masked unless called with an API key.
+// @Tags preferences
+// @Produce json
+// @Param address path string true "Wallet address"
+// @Success 200 {object} models.NotificationPreference "Notification preferences"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /preferences/{address} [get]
+func GetNotificationPreferences(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	preference, err := models.GetNotificationPreference(database.GetDB().WithContext(c.Request.Context()), address)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get notification preferences")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to get notification preferences",
+		})
+		return
+	}
+
+	if !middleware.IsAdmin(c) {
+		preference.Email = maskEmail(preference.Email)
+	}
+	respondJSON(c, http.StatusOK, preference)
+}
+
+// UpdateNotificationPreferences changes a wallet's notification preferences
+// @Summary Update notification preferences
+// @Description Change the given notification preferences of a wallet; omitted fields keep their value. Requires a wallet session token for the address from /auth/verify.
+// @Tags preferences
+// @Accept json
+// @Produce json
+// @Security WalletAuth
+// @Param address path string true "Wallet address"
+// @Param preferences body models.NotificationPreferenceUpdateRequest true "Preferences to change"
+// @Success 200 {object} models.NotificationPreference "Updated preferences"
+// @Failure 400 {object} map[string]string "Invalid address or preferences"
+// @Failure 401 {object} map[string]string "Missing, invalid or expired wallet token"
+// @Failure 403 {object} map[string]string "Wallet token is for another address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Wallet authentication not configured"
+// @Router /preferences/{address} [put]
+func UpdateNotificationPreferences(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	var req models.NotificationPreferenceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Locale != nil && !req.Locale.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid locale",
+			"details": "locale must be id or en",
+		})
+		return
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	preference, err := models.GetNotificationPreference(db, address)
+	if err == nil {
+		req.Apply(preference)
+		err = models.SaveNotificationPreference(db, preference)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to update notification preferences")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to update notification preferences",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, preference)
+}
+
+// UnsubscribePage shows the confirmation for an unsubscribe link from a notification email
+// @Summary Confirm unsubscribing from notifications
+// @Description Show what the signed token from a notification email unsubscribes and a button that POSTs it back. Nothing changes until it is confirmed, as link scanners and prefetchers follow GET links. Browsers get an HTML page; other clients get JSON.
+// @Tags preferences
+// @Produce html,json
+// @Param token query string true "Unsubscribe token from the email"
+// @Success 200 {object} models.UnsubscribeConfirmation "Confirmation"
+// @Failure 400 {object} map[string]string "Invalid or expired token"
+// @Failure 503 {object} map[string]string "Unsubscribe links not configured"
+// @Router /unsubscribe [get]
+func UnsubscribePage(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		address, kind, ok := parseUnsubscribeLink(c, secret, token)
+		if !ok {
+			return
+		}
+
+		respondUnsubscribe(c, http.StatusOK, unsubscribePageData{
+			Title:   "Unsubscribe",
+			Message: fmt.Sprintf("Stop sending %s to wallet %s?", notificationKindLabel(kind), address),
+			Token:   token,
+		}, models.UnsubscribeConfirmation{
+			Address:      address,
+			Notification: kind,
+			Confirm:      "POST this URL to unsubscribe",
+		})
+	}
+}
+
+// Unsubscribe applies an unsubscribe link from a notification email
+// @Summary Unsubscribe from notifications
+// @Description Turn off one kind of notification (yield, redemption, digest) or all of them for the wallet in a signed token from a notification email. This is the RFC 8058 one-click endpoint for List-Unsubscribe-Post, and the target of the GET confirmation page's button. No other authentication is needed; links expire after EMAIL_UNSUBSCRIBE_TOKEN_TTL.
+// @Tags preferences
+// @Accept x-www-form-urlencoded
+// @Produce html,json
+// @Param token query string true "Unsubscribe token from the email"
+// @Success 200 {object} models.UnsubscribeResponse "Unsubscribed"
+// @Failure 400 {object} map[string]string "Invalid or expired token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Unsubscribe links not configured"
+// @Router /unsubscribe [post]
+func Unsubscribe(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address, kind, ok := parseUnsubscribeLink(c, secret, c.Query("token"))
+		if !ok {
+			return
+		}
+
+		db := database.GetDB().WithContext(c.Request.Context())
+		preference, err := models.GetNotificationPreference(db, address)
+		if err == nil {
+			preference.Unsubscribe(kind)
+			err = models.SaveNotificationPreference(db, preference)
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to unsubscribe")
+			respondUnsubscribe(c, queryErrorStatus(c, err), unsubscribePageData{
+				Title:   "Unsubscribe failed",
+				Message: "Something went wrong, please try again later.",
+			}, gin.H{
+				"error": "Failed to unsubscribe",
+			})
+			return
+		}
+
+		respondUnsubscribe(c, http.StatusOK, unsubscribePageData{
+			Title:   "Unsubscribed",
+			Message: fmt.Sprintf("Wallet %s will no longer get %s.", address, notificationKindLabel(kind)),
+		}, models.UnsubscribeResponse{
+			Address:      address,
+			Unsubscribed: kind,
+		})
+	}
+}
+
+// parseUnsubscribeLink returns the address and kind of an unsubscribe token, writing the error
+// response if the link can't be used
+func parseUnsubscribeLink(c *gin.Context, secret, token string) (string, models.NotificationKind, bool) {
+	if secret == "" {
+		respondUnsubscribe(c, http.StatusServiceUnavailable, unsubscribePageData{
+			Title:   "Unsubscribe unavailable",
+			Message: "Unsubscribe links are not available right now.",
+		}, gin.H{
+			"error": "Unsubscribe links not configured",
+		})
+		return "", "", false
+	}
+
+	address, kind, err := services.ParseUnsubscribeToken(secret, token, time.Now())
+	if err != nil {
+		message := "Invalid unsubscribe link"
+		if errors.Is(err, services.ErrUnsubscribeTokenExpired) {
+			message = "Unsubscribe link expired"
+		}
+		respondUnsubscribe(c, http.StatusBadRequest, unsubscribePageData{
+			Title:   message,
+			Message: "Change your notification settings in the app instead.",
+		}, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+		return "", "", false
+	}
+	return address, kind, true
+}
+
+// unsubscribePageData fills unsubscribePage; Token is only set on the confirmation, to post back
+type unsubscribePageData struct {
+	Title   string
+	Message string
+	Token   string
+}
+
+// unsubscribePage is the page browsers get from the unsubscribe endpoints
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{if .Token}}<form method="post" action="?token={{.Token}}">
+<input type="hidden" name="List-Unsubscribe" value="One-Click">
+<button type="submit">Unsubscribe</button>
+</form>{{end}}
+</body>
+</html>
+`))
+
+// respondUnsubscribe answers browsers with the page and other clients, such as mail clients
+// doing a one-click unsubscribe, with body as JSON
+func respondUnsubscribe(c *gin.Context, status int, page unsubscribePageData, body interface{}) {
+	if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) != binding.MIMEHTML {
+		c.JSON(status, body)
+		return
+	}
+
+	var html bytes.Buffer
+	if err := unsubscribePage.Execute(&html, page); err != nil {
+		logger.WithError(err).Error("Failed to render the unsubscribe page")
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", html.Bytes())
+}
+
+// notificationKindLabel names a kind of notification for the unsubscribe page
+func notificationKindLabel(kind models.NotificationKind) string {
+	if kind == models.NotificationKindAll {
+		return "any notifications"
+	}
+	return string(kind) + " notifications"
+}
+
+// maskEmail keeps the first character and the domain, e.g. a***@example.com
+func maskEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return ""
+	}
+	return local[:1] + "***@" + domain
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUnsubscribeConfirmsBeforeWriting(t *testing.T) {
+	const secret = "unsubscribe-secret"
+	token := services.NewUnsubscribeToken(secret, portfolioTestHolder, models.NotificationKindDigest, time.Now().Add(time.Hour))
+
+	var queries []string
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		queries = append(queries, query)
+		return stubResult{}
+	})
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/unsubscribe", UnsubscribePage(secret))
+	router.POST("/unsubscribe", Unsubscribe(secret))
+	serve := func(method, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/unsubscribe?token="+token, nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// A link scanner following the email link must not unsubscribe anyone
+	page := serve(http.MethodGet, "text/html")
+	if page.Code != http.StatusOK || !strings.Contains(page.Body.String(), `<form method="post"`) {
+		t.Fatalf("Expected a confirmation page with a form, got %d %q", page.Code, page.Body.String())
+	}
+	if len(queries) != 0 {
+		t.Fatalf("Expected GET to leave the preferences alone, got %v", queries)
+	}
+
+	confirmed := serve(http.MethodPost, "application/json")
+	if confirmed.Code != http.StatusOK || !strings.Contains(confirmed.Body.String(), `"unsubscribed":"digest"`) {
+		t.Fatalf("Expected POST to unsubscribe, got %d %q", confirmed.Code, confirmed.Body.String())
+	}
+	saved := false
+	for _, query := range queries {
+		saved = saved || strings.HasPrefix(query, `INSERT INTO "notification_preferences"`)
+	}
+	if !saved {
+		t.Errorf("Expected POST to save the preferences, got %v", queries)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/unsubscribe?token=forged", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a forged token, got %d", w.Code)
+	}
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateOrder returns a handler creating purchase orders that expire after ttl unless paid
+// @Summary Create purchase order
+// @Description Create a fiat on-ramp purchase intent for a sukuk. Wallets can only create orders for their own address; the API key can create them for any address. The investor must have acknowledged the sukuk's risks for its current prospectus (see POST /suitability/{address}). The fiat amount must be within the sukuk's minimum and maximum purchase. The token amount is quoted from it at the sukuk's Rp1 face value and must fit in the sukuk's remaining kuota_nasional capacity, less the tokens of its paid and unexpired orders. The order expires if the payment callback does not arrive in time.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security WalletAuth
+// @Security ApiKeyAuth
+// @Param order body models.OrderCreateRequest true "Purchase order"
+// @Success 201 {object} models.Order "Created order"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Missing or invalid wallet token or API key"
+// @Failure 403 {object} map[string]string "Wallet token is for another address"
+// @Failure 404 {object} map[string]string "Sukuk not found"
+// @Failure 412 {object} map[string]interface{} "Risk acknowledgement missing or for an outdated prospectus, with the suitability status"
+// @Failure 422 {object} map[string]string "Sukuk not open for purchase, amount out of range or above the remaining capacity after outstanding orders"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders [post]
+func CreateOrder(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.OrderCreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if !utils.IsValidEthereumAddress(req.UserAddress) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid user address",
+			})
+			return
+		}
+		if !ownsAddress(c, req.UserAddress) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Wallet token is for another address",
+			})
+			return
+		}
+
+		var sukuk models.SukukMetadata
+		err := database.GetDB().WithContext(c.Request.Context()).First(&sukuk, req.SukukMetadataID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Sukuk not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to fetch sukuk metadata for order")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create order",
+			})
+			return
+		}
+
+		if err := validateOrderAmount(&sukuk, req.FiatAmount); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		if err := services.CheckSuitability(c.Request.Context(), database.GetDB(), req.UserAddress, sukuk.ID); err != nil {
+			var required *services.SuitabilityRequiredError
+			if errors.As(err, &required) {
+				respondSuitabilityRequired(c, required)
+				return
+			}
+			logger.WithError(err).Error("Failed to check risk acknowledgement")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create order",
+			})
+			return
+		}
+
+		// The token amount is quoted here, never taken from the client
+		availabilityService := services.NewSukukAvailabilityService(database.GetDB(), services.NewIndexerQueryService())
+		tokenAmount, err := availabilityService.QuoteTokenAmount(c.Request.Context(), &sukuk, req.FiatAmount)
+		if err != nil {
+			logger.WithError(err).Error("Failed to quote order token amount")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create order",
+			})
+			return
+		}
+		reference, err := newPaymentReference()
+		if err != nil {
+			logger.WithError(err).Error("Failed to generate payment reference")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to create order",
+			})
+			return
+		}
+
+		order := models.Order{
+			UserAddress:      req.UserAddress,
+			SukukMetadataID:  sukuk.ID,
+			SukukAddress:     sukuk.ContractAddress,
+			FiatAmount:       req.FiatAmount,
+			TokenAmount:      tokenAmount,
+			PaymentReference: reference,
+			Status:           models.OrderStatusCreated,
+			ExpiresAt:        time.Now().Add(ttl),
+		}
+		// Checked and inserted under the sukuk's row lock, against purchases and outstanding orders
+		if err := availabilityService.ReserveOrder(c.Request.Context(), &order); err != nil {
+			var exceeded *services.AvailabilityExceededError
+			if errors.As(err, &exceeded) {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "Token amount exceeds the sukuk's remaining capacity",
+					"details": err.Error(),
+				})
+				return
+			}
+			logger.WithError(err).Error("Failed to create order")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create order",
+			})
+			return
+		}
+
+		c.JSON(http.StatusCreated, order)
+	}
+}
+
+// validateOrderAmount checks the sukuk is open for purchase and amount is within its limits
+// A zero maximum means no upper limit
+func validateOrderAmount(sukuk *models.SukukMetadata, amount float64) error {
+	if !sukuk.MetadataReady || sukuk.Status != models.SukukStatusActive {
+		return errors.New("sukuk is not open for purchase")
+	}
+	if amount < sukuk.MinimumPembelian {
+		return fmt.Errorf("fiat amount is below the minimum purchase of %s", models.FormatRupiah(sukuk.MinimumPembelian))
+	}
+	if sukuk.MaksimumPembelian > 0 && amount > sukuk.MaksimumPembelian {
+		return fmt.Errorf("fiat amount is above the maximum purchase of %s", models.FormatRupiah(sukuk.MaksimumPembelian))
+	}
+	return nil
+}
+
+// ownsAddress reports whether the request may reach the orders of address: the API key reaches
+// every address and a wallet session only its own
+func ownsAddress(c *gin.Context, address string) bool {
+	return middleware.IsAdmin(c) || strings.EqualFold(middleware.WalletAddress(c), address)
+}
+
+// newPaymentReference returns a random reference the fiat partner quotes back in callbacks
+func newPaymentReference() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "ORD-" + strings.ToUpper(hex.EncodeToString(buf)), nil
+}
+
+// GetOrder returns a purchase order by ID
+// @Summary Get purchase order
+// @Description Get a purchase order by ID, including its status and settlement transaction. Wallets only see their own orders; others are reported as not found
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security WalletAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.Order "Order"
+// @Failure 400 {object} map[string]string "Invalid order ID"
+// @Failure 401 {object} map[string]string "Missing or invalid wallet token or API key"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Router /orders/{id} [get]
+func GetOrder(c *gin.Context) {
+	order, ok := findOrder(c)
+	if !ok {
+		return
+	}
+	// Order IDs are sequential, so another wallet's order looks the same as a missing one
+	if !ownsAddress(c, order.UserAddress) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Order not found",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, order)
+}
+
+// ListOrders returns the purchase orders of an address
+// @Summary List purchase orders
+// @Description List purchase orders created for a wallet address, newest first. Wallets can only list their own orders and may omit the address; the API key lists any address
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security WalletAuth
+// @Security ApiKeyAuth
+// @Param address query string false "User wallet address, required with the API key"
+// @Success 200 {array} models.Order "Orders"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 401 {object} map[string]string "Missing or invalid wallet token or API key"
+// @Failure 403 {object} map[string]string "Wallet token is for another address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders [get]
+func ListOrders(c *gin.Context) {
+	address := c.Query("address")
+	if address == "" {
+		address = middleware.WalletAddress(c)
+	}
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid or missing address",
+		})
+		return
+	}
+	if !ownsAddress(c, address) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Wallet token is for another address",
+		})
+		return
+	}
+
+	orders := []models.Order{}
+	err := database.GetDB().WithContext(c.Request.Context()).
+		Where("user_address = ?", utils.NormalizeAddress(address)).
+		Order("created_at DESC").
+		Find(&orders).Error
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch orders")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch orders",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, orders)
+}
+
+// errCallbackReplayed ends the callback transaction of a payment result already applied
+var errCallbackReplayed = errors.New("callback already applied")
+
+// OrderPaymentCallback records the fiat partner's payment result for an order
+// @Summary Order payment callback
+// @Description Called by the fiat partner once a payment clears or fails. The raw body must be signed with HMAC-SHA256 using the webhook secret and sent in the X-Webhook-Signature header. Each payment reference and status is applied once; repeating or replaying a callback is a no-op that returns the order as it is.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "sha256=<hex HMAC of the body>"
+// @Param id path int true "Order ID"
+// @Param callback body models.OrderPaymentCallbackRequest true "Payment result"
+// @Success 200 {object} models.Order "Updated order"
+// @Failure 400 {object} map[string]string "Invalid request payload or payment reference"
+// @Failure 401 {object} map[string]string "Invalid webhook signature"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Order cannot change to the reported status"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/{id}/payment-callback [post]
+func OrderPaymentCallback(c *gin.Context) {
+	var req models.OrderPaymentCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+	if req.Status == "" {
+		req.Status = models.OrderStatusPaid
+	}
+	if req.Status != models.OrderStatusPaid && req.Status != models.OrderStatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Callback status must be paid or failed",
+		})
+		return
+	}
+
+	order, ok := findOrder(c)
+	if !ok {
+		return
+	}
+	if req.PaymentReference != order.PaymentReference {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Payment reference does not match the order",
+		})
+		return
+	}
+
+	// Each reference and status is applied once; partners retry callbacks and a signed body can
+	// be replayed, so a repeat succeeds with the order as it is now
+	var transitionErr error
+	err := database.GetDB().WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		first, err := models.RecordOrderPaymentCallback(tx, order, req.Status)
+		if err != nil {
+			return err
+		}
+		if !first || order.Status == req.Status {
+			return errCallbackReplayed
+		}
+
+		previous := order.Status
+		if req.Status == models.OrderStatusPaid {
+			transitionErr = order.MarkPaid(time.Now())
+		} else {
+			transitionErr = order.MarkFailed(req.Reason)
+		}
+		if transitionErr != nil {
+			return transitionErr
+		}
+
+		// The status guard keeps a concurrent expiry sweep or callback from being overwritten
+		result := tx.Model(order).
+			Where("status = ?", previous).
+			Updates(map[string]interface{}{
+				"status":         order.Status,
+				"paid_at":        order.PaidAt,
+				"failure_reason": order.FailureReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			transitionErr = errors.New("order changed concurrently, retry the callback")
+			return transitionErr
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errCallbackReplayed):
+		respondJSON(c, http.StatusOK, order)
+		return
+	case err != nil && errors.Is(err, transitionErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	case err != nil:
+		logger.WithError(err).Error("Failed to update order payment status")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to update order",
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"order_id": order.ID,
+		"status":   order.Status,
+	}).Info("Order payment callback applied")
+
+	respondJSON(c, http.StatusOK, order)
+}
+
+// findOrder loads the order named by the id path parameter, writing the error response if it can't
+func findOrder(c *gin.Context) (*models.Order, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid order ID",
+		})
+		return nil, false
+	}
+
+	var order models.Order
+	err = database.GetDB().WithContext(c.Request.Context()).First(&order, uint(id)).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Order not found",
+		})
+		return nil, false
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch order")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch order",
+		})
+		return nil, false
+	}
+
+	return &order, true
+}
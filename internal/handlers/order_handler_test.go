@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// orderDB answers order queries with order 3 of the suitability test investor
+func orderDB(query string) stubResult {
+	if strings.Contains(query, `FROM "orders"`) {
+		return stubResult{
+			columns: []string{"id", "user_address", "sukuk_metadata_id", "sukuk_address", "fiat_amount", "token_amount", "payment_reference", "status", "expires_at"},
+			rows: [][]driver.Value{{int64(3), suitabilityTestInvestor, int64(7), "0xabcdef0000000000000000000000000000000001", float64(1000000),
+				"1000000000000000000000000", "ORD-1", "created", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)}},
+		}
+	}
+	return suitabilityDB(2)(query)
+}
+
+// serveOrdersAs serves the order routes as a wallet session for wallet, or with the API key
+// when wallet is empty
+func serveOrdersAs(t *testing.T, wallet, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	previous := database.DB
+	database.DB = openStubDB(t, orderDB).Session(&gorm.Session{SkipDefaultTransaction: true})
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if wallet == "" {
+			c.Set(middleware.AdminContextKey, true)
+		} else {
+			c.Set(middleware.WalletAddressContextKey, wallet)
+		}
+	})
+	router.POST("/orders", CreateOrder(time.Hour))
+	router.GET("/orders", ListOrders)
+	router.GET("/orders/:id", GetOrder)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func TestOrdersAreScopedToTheWallet(t *testing.T) {
+	const other = "0x00000000000000000000000000000000000000a1"
+
+	tests := []struct {
+		name           string
+		wallet, method string
+		target, body   string
+		want           int
+	}{
+		{"create for another address", other, http.MethodPost, "/orders",
+			`{"user_address": "` + suitabilityTestInvestor + `", "sukuk_metadata_id": 7, "fiat_amount": 1000000}`, http.StatusForbidden},
+		{"list another address", other, http.MethodGet, "/orders?address=" + suitabilityTestInvestor, "", http.StatusForbidden},
+		{"list own orders", suitabilityTestInvestor, http.MethodGet, "/orders", "", http.StatusOK},
+		{"get another wallet's order", other, http.MethodGet, "/orders/3", "", http.StatusNotFound},
+		{"get own order", suitabilityTestInvestor, http.MethodGet, "/orders/3", "", http.StatusOK},
+		{"admin gets any order", "", http.MethodGet, "/orders/3", "", http.StatusOK},
+		{"admin lists without address", "", http.MethodGet, "/orders", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveOrdersAs(t, tt.wallet, tt.method, tt.target, tt.body)
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateOrderQuotesTheTokenAmount(t *testing.T) {
+	// The client's token_amount is ignored; Rp1,500,000 at the default 18 decimals is quoted
+	body := `{"user_address": "` + suitabilityTestInvestor + `", "sukuk_metadata_id": 7, "fiat_amount": 1500000, "token_amount": "1"}`
+	w := serveOrdersAs(t, suitabilityTestInvestor, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var order models.Order
+	if err := json.Unmarshal(w.Body.Bytes(), &order); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if order.TokenAmount != "1500000000000000000000000" {
+		t.Errorf("Expected 1.5e24 quoted, got %s", order.TokenAmount)
+	}
+}
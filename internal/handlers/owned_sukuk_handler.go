@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"sukuk-be/internal/database"
 	"sukuk-be/internal/logger"
@@ -23,48 +27,81 @@ import (
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /owned-sukuk/{address} [get]
 func GetSukukOwnedByAddress(c *gin.Context) {
+	getSukukOwnedByAddress(c, APIV1)
+}
+
+// GetSukukOwnedByAddressV2 serves GET /api/v2/owned-sukuk/:address: the v1 response wrapped in the response envelope
+func GetSukukOwnedByAddressV2(c *gin.Context) {
+	getSukukOwnedByAddress(c, APIV2)
+}
+
+func getSukukOwnedByAddress(c *gin.Context, version APIVersion) {
 	// Get address from path
 	address := c.Param("address")
 	if address == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Address is required",
-		})
+		version.respondError(c, http.StatusBadRequest, "Address is required", "")
+		return
+	}
+
+	ownedResponse, err := buildOwnedSukukResponse(c.Request.Context(), address)
+	if errors.Is(err, errOwnedSukukMetadata) {
+		logger.WithError(err).Error("Failed to fetch sukuk metadata")
+		version.respondError(c, http.StatusInternalServerError, "Failed to fetch sukuk metadata", "")
 		return
 	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch owned sukuk addresses")
+		version.respondError(c, queryErrorStatus(c, err), "Failed to fetch owned sukuk", "")
+		return
+	}
+
+	if version == APIV1 {
+		dropTermDetails(ownedResponse.Sukuk)
+	}
+	version.respond(c, http.StatusOK, ownedResponse)
+}
 
+// errOwnedSukukMetadata wraps failures to load the metadata of owned sukuk, as opposed to
+// failures to read their addresses from the indexer
+var errOwnedSukukMetadata = errors.New("failed to fetch sukuk metadata")
+
+// buildOwnedSukukResponse loads the ready sukuk an address holds with their latest activities
+// and the distributions the address can claim
+func buildOwnedSukukResponse(ctx context.Context, address string) (*OwnedSukukResponse, error) {
 	// Initialize indexer query service
 	indexerService := services.NewIndexerQueryService()
 
 	// Get all sukuk addresses owned by this user
-	sukukAddresses, err := indexerService.GetSukukOwnedByAddress(address)
+	sukukAddresses, err := currentDeps().Portfolio.GetSukukOwnedByAddress(ctx, address)
 	if err != nil {
-		logger.WithError(err).Error("Failed to fetch owned sukuk addresses")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch owned sukuk",
-		})
-		return
+		return nil, err
 	}
 
 	if len(sukukAddresses) == 0 {
 		// User doesn't own any sukuk
-		response := OwnedSukukResponse{
+		return &OwnedSukukResponse{
 			Address:    address,
 			TotalCount: 0,
 			Sukuk:      []models.SukukMetadataListResponse{},
-		}
-		c.JSON(http.StatusOK, response)
-		return
+		}, nil
 	}
 
 	// Get sukuk metadata for these addresses (only metadata_ready = true by default)
 	var sukukMetadata []models.SukukMetadata
-	result := database.GetDB().Where("contract_address IN ?", sukukAddresses).Where("metadata_ready = ?", true).Find(&sukukMetadata)
+	result := database.GetDB().WithContext(ctx).Where("contract_address IN ?", sukukAddresses).Where("metadata_ready = ?", true).Find(&sukukMetadata)
 	if result.Error != nil {
-		logger.WithError(result.Error).Error("Failed to fetch sukuk metadata")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch sukuk metadata",
-		})
-		return
+		return nil, fmt.Errorf("%w: %v", errOwnedSukukMetadata, result.Error)
+	}
+
+	// Get latest 10 activities for every owned sukuk in one pass over the indexer
+	ownedAddresses := make([]string, len(sukukMetadata))
+	for i, sukuk := range sukukMetadata {
+		ownedAddresses[i] = sukuk.ContractAddress
+	}
+	activitiesBySukuk, err := indexerService.GetLatestActivitiesBySukuk(ctx, ownedAddresses, 10)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to fetch activities for owned sukuk")
+		activitiesBySukuk = nil // Every sukuk falls back to an empty array
 	}
 
 	// Convert to response format with activities and distributions
@@ -72,15 +109,13 @@ func GetSukukOwnedByAddress(c *gin.Context) {
 	for i, sukuk := range sukukMetadata {
 		response := sukuk.ToListResponse()
 		
-		// Get latest 10 activities for this sukuk token directly from indexer
-		activities, err := indexerService.GetLatestActivities(sukuk.ContractAddress, 10)
-		if err != nil {
-			logger.WithError(err).Warn("Failed to fetch activities for sukuk:", sukuk.ContractAddress)
-			activities = []models.ActivityEvent{} // Set empty array if error
+		activities := activitiesBySukuk[strings.ToLower(sukuk.ContractAddress)]
+		if activities == nil {
+			activities = []models.ActivityEvent{} // Ensure it's never null
 		}
 		
 		// Get available yield distributions for this user and sukuk
-		distributions, err := indexerService.GetAvailableDistributions(address, sukuk.ContractAddress)
+		distributions, err := indexerService.GetAvailableDistributions(ctx, address, sukuk.ContractAddress)
 		if err != nil {
 			logger.WithError(err).Warn("Failed to fetch distributions for sukuk:", sukuk.ContractAddress)
 			distributions = []models.SukukYieldDistribution{} // Set empty array if error
@@ -92,13 +127,11 @@ func GetSukukOwnedByAddress(c *gin.Context) {
 	}
 
 	// Create response
-	ownedResponse := OwnedSukukResponse{
+	return &OwnedSukukResponse{
 		Address:    address,
 		TotalCount: len(responses),
 		Sukuk:      responses,
-	}
-
-	c.JSON(http.StatusOK, ownedResponse)
+	}, nil
 }
 
 // OwnedSukukResponse represents the response for owned sukuk
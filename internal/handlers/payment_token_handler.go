@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListPaymentTokens returns all registered payment tokens
+// @Summary List payment tokens
+// @Description Get all registered ERC-20 payment tokens with their symbol and decimals
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} models.PaymentToken "List of payment tokens"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payment-tokens [get]
+func ListPaymentTokens(c *gin.Context) {
+	tokens, err := models.GetAllPaymentTokens(database.GetDB())
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch payment tokens")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment tokens",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, tokens)
+}
+
+// GetPaymentToken returns a single payment token by contract address
+// @Summary Get payment token
+// @Description Get a registered payment token by its contract address
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Token contract address"
+// @Success 200 {object} models.PaymentToken "Payment token"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payment token not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payment-tokens/{address} [get]
+func GetPaymentToken(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token address",
+		})
+		return
+	}
+
+	token, err := models.GetPaymentTokenByAddress(database.GetDB(), address)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment token not found",
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to fetch payment token")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch payment token",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, token)
+}
+
+// CreatePaymentToken registers a new payment token
+// @Summary Register payment token
+// @Description Register an ERC-20 payment token. Symbol, name and decimals are read from the contract via RPC when auto_fetch is true or when symbol/decimals are omitted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param token body models.PaymentTokenCreateRequest true "Payment token"
+// @Success 201 {object} models.PaymentToken "Registered payment token"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Payment token already registered"
+// @Failure 502 {object} map[string]string "Failed to read token metadata from RPC"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payment-tokens [post]
+func CreatePaymentToken(rpcEndpoint string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PaymentTokenCreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if !utils.IsValidEthereumAddress(req.Address) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid token address",
+			})
+			return
+		}
+
+		// Checked before the RPC call, which a duplicate would waste
+		db := database.GetDB().WithContext(c.Request.Context())
+		_, err := models.GetPaymentTokenByAddress(db, req.Address)
+		if err == nil {
+			respondPaymentTokenExists(c)
+			return
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.WithError(err).Error("Failed to fetch payment token")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create payment token",
+			})
+			return
+		}
+
+		token := models.PaymentToken{
+			Address: req.Address,
+			Symbol:  req.Symbol,
+			Name:    req.Name,
+		}
+		if req.Decimals != nil {
+			token.Decimals = *req.Decimals
+		}
+
+		// Read metadata from the contract when requested or when required fields are missing
+		if req.AutoFetch || req.Symbol == "" || req.Decimals == nil {
+			metadata, err := services.NewERC20MetadataClient(rpcEndpoint).FetchMetadata(req.Address)
+			if err != nil {
+				logger.WithError(err).WithField("address", req.Address).Error("Failed to fetch token metadata")
+				c.JSON(http.StatusBadGateway, gin.H{
+					"error":   "Failed to read token metadata from RPC",
+					"details": err.Error(),
+				})
+				return
+			}
+
+			// Explicit values in the request take precedence over on-chain values
+			if req.Symbol == "" {
+				token.Symbol = metadata.Symbol
+			}
+			if req.Name == "" {
+				token.Name = metadata.Name
+			}
+			if req.Decimals == nil {
+				token.Decimals = metadata.Decimals
+			}
+		}
+
+		// A concurrent registration of the same address can still get past the lookup
+		err = models.CreatePaymentToken(db, &token)
+		if errors.Is(err, models.ErrPaymentTokenExists) {
+			respondPaymentTokenExists(c)
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to create payment token")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to create payment token",
+			})
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"address":  token.Address,
+			"symbol":   token.Symbol,
+			"decimals": token.Decimals,
+		}).Info("Payment token registered")
+
+		c.JSON(http.StatusCreated, token)
+	}
+}
+
+// UpdatePaymentToken updates a registered payment token
+// @Summary Update payment token
+// @Description Update the symbol, name or decimals of a registered payment token
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Token contract address"
+// @Param token body models.PaymentTokenUpdateRequest true "Fields to update"
+// @Success 200 {object} models.PaymentToken "Updated payment token"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payment token not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payment-tokens/{address} [put]
+func UpdatePaymentToken(c *gin.Context) {
+	var req models.PaymentTokenUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	token, err := models.GetPaymentTokenByAddress(database.GetDB(), c.Param("address"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment token not found",
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to fetch payment token")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update payment token",
+		})
+		return
+	}
+
+	if req.Symbol != nil {
+		token.Symbol = *req.Symbol
+	}
+	if req.Name != nil {
+		token.Name = *req.Name
+	}
+	if req.Decimals != nil {
+		token.Decimals = *req.Decimals
+	}
+
+	if err := database.GetDB().Save(token).Error; err != nil {
+		logger.WithError(err).Error("Failed to update payment token")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update payment token",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, token)
+}
+
+// DeletePaymentToken removes a registered payment token
+// @Summary Delete payment token
+// @Description Remove a payment token. Amounts in this token fall back to 18 decimals afterwards.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Token contract address"
+// @Success 200 {object} MessageResponse "Payment token deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payment token not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payment-tokens/{address} [delete]
+func DeletePaymentToken(c *gin.Context) {
+	token, err := models.GetPaymentTokenByAddress(database.GetDB(), c.Param("address"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payment token not found",
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to fetch payment token")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete payment token",
+		})
+		return
+	}
+
+	if err := database.GetDB().Delete(token).Error; err != nil {
+		logger.WithError(err).Error("Failed to delete payment token")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete payment token",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, MessageResponse{
+		Message: "Payment token deleted",
+	})
+}
+
+// loadTokenFormatter loads the payment token formatter, falling back to default decimals when the registry is unavailable
+func loadTokenFormatter() *services.TokenFormatter {
+	formatter, err := services.LoadTokenFormatter()
+	if err != nil {
+		logger.WithError(err).Warn("Failed to load payment tokens, using default decimals")
+		return services.NewTokenFormatter(nil)
+	}
+	return formatter
+}
+
+// respondPaymentTokenExists answers a registration of an address that is already registered
+func respondPaymentTokenExists(c *gin.Context) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error": "Payment token already registered",
+	})
+}
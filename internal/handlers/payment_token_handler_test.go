@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreatePaymentTokenSeparatesDuplicatesFromFailures(t *testing.T) {
+	// Symbol and decimals are given, so no RPC call is made
+	const body = `{"address":"0x036CbD53842c5426634e7929541eC2318f3dCF7e","symbol":"USDC","decimals":6}`
+	tests := []struct {
+		name    string
+		respond func(query string) stubResult
+		want    int
+	}{
+		{"lookup fails", func(string) stubResult { return stubResult{err: errors.New("connection refused")} }, http.StatusInternalServerError},
+		// The lookup finds nothing, then the insert hits the unique address and stores no row
+		{"concurrent registration", func(string) stubResult { return stubResult{} }, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := database.DB
+			database.DB = openStubDB(t, tt.respond)
+			defer func() { database.DB = previous }()
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/admin/payment-tokens", CreatePaymentToken("http://127.0.0.1:0"))
+			req := httptest.NewRequest(http.MethodPost, "/admin/payment-tokens", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
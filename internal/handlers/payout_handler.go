@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PayoutManager records the issuer's payouts of approved redemptions, e.g. services.PayoutService
+type PayoutManager interface {
+	Create(ctx context.Context, req models.PayoutInstructionRequest, actor string) (*models.PayoutInstruction, error)
+	Update(ctx context.Context, id uint, req models.PayoutInstructionUpdateRequest, actor string) (*models.PayoutInstruction, error)
+	AttachProof(ctx context.Context, id uint, upload services.PayoutProofUpload, actor string) (*models.PayoutInstruction, error)
+}
+
+// ListPayoutInstructions returns the payout instructions of approved redemptions
+// @Summary List payout instructions
+// @Description Get the issuer's payout instructions for approved redemptions, newest first, filtered by status and sukuk
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Payout status" Enums(pending, executed, confirmed)
+// @Param sukuk_address query string false "Sukuk contract address"
+// @Param limit query int false "Number of instructions to return" default(50) minimum(1) maximum(200)
+// @Param offset query int false "Number of instructions to skip" default(0) minimum(0)
+// @Success 200 {object} models.PayoutInstructionListResponse "Payout instructions"
+// @Failure 400 {object} map[string]string "Invalid status or sukuk address"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payouts [get]
+func ListPayoutInstructions(c *gin.Context) {
+	filter := models.PayoutListFilter{Status: models.PayoutStatus(c.Query("status"))}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status",
+			"details": "status must be pending, executed or confirmed",
+		})
+		return
+	}
+	if address := c.Query("sukuk_address"); address != "" {
+		if !utils.IsValidEthereumAddress(address) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid sukuk address",
+			})
+			return
+		}
+		filter.SukukAddress = address
+	}
+
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	instructions, total, err := models.ListPayoutInstructions(database.GetDB().WithContext(c.Request.Context()), filter)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch payout instructions")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch payout instructions",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.PayoutInstructionListResponse{Instructions: instructions, TotalCount: total})
+}
+
+// CreatePayoutInstruction records the payout to make for an approved redemption
+// @Summary Create payout instruction
+// @Description Record the payout of a redemption approved onchain, in a payment token or by bank transfer. The amount defaults to the approved amount and the payment token to the redemption's unless bank_reference is set. The instruction starts pending; a redemption has at most one instruction
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param instruction body models.PayoutInstructionRequest true "Payout instruction"
+// @Success 201 {object} models.PayoutInstruction "Pending instruction"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Redemption request not found"
+// @Failure 409 {object} map[string]string "Redemption already has a payout instruction"
+// @Failure 422 {object} map[string]string "Redemption not approved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payouts [post]
+func CreatePayoutInstruction(payouts PayoutManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PayoutInstructionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		instruction, err := payouts.Create(c.Request.Context(), req, auditActor(c))
+		if err != nil {
+			respondPayoutError(c, err, "Failed to create payout instruction")
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"payout_id":  instruction.ID,
+			"request_id": instruction.RedemptionRequestID,
+			"amount":     instruction.Amount,
+		}).Info("Payout instruction created")
+		c.JSON(http.StatusCreated, instruction)
+	}
+}
+
+// UpdatePayoutInstruction records a payout's evidence or advances its status
+// @Summary Update payout instruction
+// @Description Record the tx hash, bank reference or notes of a payout, and move it from pending to executed once paid, then to confirmed once received. Executing requires a tx_hash or an uploaded proof, which can't be changed afterwards; a confirmed instruction is final
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Payout instruction ID"
+// @Param update body models.PayoutInstructionUpdateRequest true "Changes"
+// @Success 200 {object} models.PayoutInstruction "Updated instruction"
+// @Failure 400 {object} map[string]string "Invalid ID, payload or tx hash"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payout instruction not found"
+// @Failure 409 {object} map[string]string "Transition not allowed, instruction confirmed or evidence already recorded"
+// @Failure 422 {object} map[string]string "Payout has no tx hash or proof"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payouts/{id} [put]
+func UpdatePayoutInstruction(payouts PayoutManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parsePayoutID(c)
+		if !ok {
+			return
+		}
+		var req models.PayoutInstructionUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		instruction, err := payouts.Update(c.Request.Context(), id, req, auditActor(c))
+		if err != nil {
+			respondPayoutError(c, err, "Failed to update payout instruction")
+			return
+		}
+
+		logger.WithFields(map[string]interface{}{
+			"payout_id":  instruction.ID,
+			"request_id": instruction.RedemptionRequestID,
+			"status":     instruction.Status,
+		}).Info("Payout instruction updated")
+		respondJSON(c, http.StatusOK, instruction)
+	}
+}
+
+// UploadPayoutProof attaches a proof of payout, such as a transfer receipt
+// @Summary Upload payout proof
+// @Description Attach a proof of payout, a PDF, PNG or JPEG of up to 10MB such as a bank transfer receipt, which lets the payout be executed without a tx hash. A pending instruction's proof may be replaced. Proofs are only downloadable by admins
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Payout instruction ID"
+// @Param file formData file true "Proof file"
+// @Success 200 {object} models.PayoutInstruction "Instruction with its proof"
+// @Failure 400 {object} map[string]string "Invalid ID or file"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payout instruction not found"
+// @Failure 409 {object} map[string]string "Instruction confirmed or proof already recorded"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payouts/{id}/proof [post]
+func UploadPayoutProof(payouts PayoutManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parsePayoutID(c)
+		if !ok {
+			return
+		}
+		fileHeader, err := c.FormFile("file")
+		if middleware.IsBodyTooLarge(err) {
+			middleware.AbortBodyTooLarge(c, 0)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Missing proof file",
+				"details": err.Error(),
+			})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Failed to read proof file",
+				"details": err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+
+		instruction, err := payouts.AttachProof(c.Request.Context(), id, services.PayoutProofUpload{
+			Filename: fileHeader.Filename,
+			Size:     fileHeader.Size,
+			Content:  file,
+		}, auditActor(c))
+		if err != nil {
+			respondPayoutError(c, err, "Failed to store payout proof")
+			return
+		}
+
+		logger.WithField("payout_id", instruction.ID).Info("Payout proof uploaded")
+		respondJSON(c, http.StatusOK, instruction)
+	}
+}
+
+// DownloadPayoutProof serves the proof of a payout
+// @Summary Download payout proof
+// @Description Download the proof attached to a payout instruction
+// @Tags admin
+// @Produce application/octet-stream
+// @Security ApiKeyAuth
+// @Param id path int true "Payout instruction ID"
+// @Success 200 {file} file "Proof file"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Payout instruction or proof not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/payouts/{id}/proof [get]
+func DownloadPayoutProof(storage services.FileOpener) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parsePayoutID(c)
+		if !ok {
+			return
+		}
+
+		var instruction models.PayoutInstruction
+		err := database.GetDB().WithContext(c.Request.Context()).First(&instruction, "id = ?", id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payout instruction not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to load payout instruction")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to load payout proof",
+			})
+			return
+		}
+
+		file, info, err := services.OpenPayoutProof(c.Request.Context(), storage, &instruction)
+		if errors.Is(err, services.ErrFileNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Payout proof not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to open payout proof")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load payout proof",
+			})
+			return
+		}
+		defer file.Close()
+
+		filename := fmt.Sprintf("payout_%d_proof%s", instruction.ID, path.Ext(info.Name))
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		c.Header("Cache-Control", "private, no-store")
+		http.ServeContent(c.Writer, c.Request, filename, info.ModTime, file)
+	}
+}
+
+// parsePayoutID reads the :id of a payout route, answering 400 when it isn't one
+func parsePayoutID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID format",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondPayoutError maps the errors of a PayoutManager to their responses
+func respondPayoutError(c *gin.Context, err error, message string) {
+	var status int
+	switch {
+	case errors.Is(err, services.ErrInvalidPayout):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrPayoutNotFound), errors.Is(err, services.ErrRedemptionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrPayoutExists), errors.Is(err, services.ErrPayoutConfirmed),
+		errors.Is(err, services.ErrPayoutEvidenceFixed), errors.Is(err, models.ErrInvalidPayoutTransition):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrRedemptionNotApproved), errors.Is(err, models.ErrPayoutEvidenceRequired):
+		status = http.StatusUnprocessableEntity
+	default:
+		logger.WithError(err).Error(message)
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": message,
+		})
+		return
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
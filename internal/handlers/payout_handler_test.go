@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakePayouts answers every PayoutManager call with err, or a pending instruction
+type fakePayouts struct {
+	err    error
+	update models.PayoutInstructionUpdateRequest
+}
+
+func (f *fakePayouts) Create(ctx context.Context, req models.PayoutInstructionRequest, actor string) (*models.PayoutInstruction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.PayoutInstruction{ID: 1, RedemptionRequestID: req.RedemptionRequestID, Amount: "1000", Status: models.PayoutPending, CreatedBy: actor}, nil
+}
+
+func (f *fakePayouts) Update(ctx context.Context, id uint, req models.PayoutInstructionUpdateRequest, actor string) (*models.PayoutInstruction, error) {
+	f.update = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.PayoutInstruction{ID: id, Amount: "1000", Status: req.Status}, nil
+}
+
+func (f *fakePayouts) AttachProof(ctx context.Context, id uint, upload services.PayoutProofUpload, actor string) (*models.PayoutInstruction, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &models.PayoutInstruction{ID: id, Amount: "1000", Status: models.PayoutPending, ProofURL: "/uploads/payouts/1/proof_1.pdf"}, nil
+}
+
+func servePayouts(payouts PayoutManager, method, target, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/payouts", ListPayoutInstructions)
+	router.POST("/admin/payouts", CreatePayoutInstruction(payouts))
+	router.PUT("/admin/payouts/:id", UpdatePayoutInstruction(payouts))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUpdatePayoutInstructionMapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", services.ErrPayoutNotFound, http.StatusNotFound},
+		{"skipped step", fmt.Errorf("%w: pending to confirmed", models.ErrInvalidPayoutTransition), http.StatusConflict},
+		{"confirmed", services.ErrPayoutConfirmed, http.StatusConflict},
+		{"evidence replaced", services.ErrPayoutEvidenceFixed, http.StatusConflict},
+		{"no evidence", models.ErrPayoutEvidenceRequired, http.StatusUnprocessableEntity},
+		{"invalid tx hash", fmt.Errorf("%w: tx_hash", services.ErrInvalidPayout), http.StatusBadRequest},
+		{"database down", fmt.Errorf("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := servePayouts(&fakePayouts{err: tt.err}, http.MethodPut, "/admin/payouts/3", `{"status": "confirmed"}`)
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdatePayoutInstructionValidatesRequest(t *testing.T) {
+	payouts := &fakePayouts{}
+	for target, body := range map[string]string{
+		"/admin/payouts/abc": `{"status": "executed"}`,
+		"/admin/payouts/0":   `{"status": "executed"}`,
+		"/admin/payouts/3":   `{"status": "paid"}`,
+	} {
+		if w := servePayouts(payouts, http.MethodPut, target, body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s %s, got %d", target, body, w.Code)
+		}
+	}
+
+	w := servePayouts(payouts, http.MethodPut, "/admin/payouts/3", `{"status": "executed", "tx_hash": "0xabc"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if payouts.update.Status != models.PayoutExecuted || payouts.update.TxHash == nil || *payouts.update.TxHash != "0xabc" {
+		t.Errorf("Expected the update passed on, got %+v", payouts.update)
+	}
+	if payouts.update.Notes != nil {
+		t.Error("Expected omitted fields left nil so they stay unchanged")
+	}
+}
+
+func TestCreatePayoutInstructionMapsErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusCreated},
+		{services.ErrRedemptionNotFound, http.StatusNotFound},
+		{services.ErrPayoutExists, http.StatusConflict},
+		{fmt.Errorf("%w: status is requested", services.ErrRedemptionNotApproved), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		w := servePayouts(&fakePayouts{err: tt.err}, http.MethodPost, "/admin/payouts", `{"redemption_request_id": "0xabc-1"}`)
+		if w.Code != tt.want {
+			t.Errorf("Expected %d for %v, got %d: %s", tt.want, tt.err, w.Code, w.Body.String())
+		}
+	}
+
+	if w := servePayouts(&fakePayouts{}, http.MethodPost, "/admin/payouts", `{"amount": "1000"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a redemption request ID, got %d", w.Code)
+	}
+}
+
+func TestListPayoutInstructionsFilters(t *testing.T) {
+	var queries []string
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		queries = append(queries, query)
+		if strings.Contains(query, "count(*)") {
+			return stubResult{columns: []string{"count"}, rows: [][]driver.Value{{int64(1)}}}
+		}
+		return stubResult{
+			columns: []string{"id", "redemption_request_id", "sukuk_address", "amount", "status", "tx_hash", "executed_at"},
+			rows: [][]driver.Value{{int64(4), "0xabc-1", "0x02ba44871bd555d6ebd541e2820796f9b88cbf75", "1000", "executed",
+				"0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060", time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)}},
+		}
+	}).Session(&gorm.Session{SkipDefaultTransaction: true})
+	defer func() { database.DB = previous }()
+
+	w := servePayouts(nil, http.MethodGet, "/admin/payouts?status=executed&sukuk_address=0x02ba44871BD555d6ebD541e2820796F9b88cBF75", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response models.PayoutInstructionListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.TotalCount != 1 || len(response.Instructions) != 1 || response.Instructions[0].Status != models.PayoutExecuted {
+		t.Errorf("Expected the executed instruction, got %+v", response)
+	}
+	for _, query := range queries {
+		if !strings.Contains(query, "status = $1") || !strings.Contains(query, "sukuk_address = $2") {
+			t.Errorf("Expected both filters in %s", query)
+		}
+	}
+
+	for _, target := range []string{"/admin/payouts?status=paid", "/admin/payouts?sukuk_address=0x123"} {
+		if w := servePayouts(nil, http.MethodGet, target, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", target, w.Code)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// portfolioBatchChunkSize is how many addresses each batched portfolio read covers, so a
+// request running out of time still returns the chunks read so far
+const portfolioBatchChunkSize = 25
+
+// GetPortfolioBatch summarizes the portfolios of up to 100 addresses
+// @Summary Get portfolio summaries of several addresses
+// @Description Get the holdings count, total balance and claimable yield of up to 100 addresses, keyed by address as sent. Invalid addresses get an error entry without failing the rest. Holdings that could not be computed are left out of the totals and listed in warnings, with complete unset. Addresses not summarized within the server's time budget are listed in unprocessed, with truncated set
+// @Tags portfolio
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.PortfolioBatchRequest true "Addresses to summarize"
+// @Success 200 {object} models.PortfolioBatchResponse "Portfolio summaries"
+// @Failure 400 {object} map[string]string "Invalid payload or more than 100 addresses"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /portfolio/batch [post]
+func GetPortfolioBatch(budget time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.PortfolioBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		response := models.PortfolioBatchResponse{
+			Portfolios: make(map[string]models.PortfolioBatchEntry, len(req.Addresses)),
+		}
+		// Each valid address is read once, however many spellings of it were sent
+		var holders []string
+		spellings := make(map[string][]string)
+		for _, address := range req.Addresses {
+			if !utils.IsValidEthereumAddress(address) {
+				response.Portfolios[address] = models.PortfolioBatchEntry{Error: "Invalid address"}
+				continue
+			}
+			key := strings.ToLower(address)
+			if _, seen := spellings[key]; !seen {
+				holders = append(holders, key)
+			}
+			spellings[key] = append(spellings[key], address)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), budget)
+		defer cancel()
+		reader := currentDeps().Portfolio
+		for start := 0; start < len(holders); start += portfolioBatchChunkSize {
+			end := min(start+portfolioBatchChunkSize, len(holders))
+			summaries, err := reader.GetPortfolioSummaries(ctx, holders[start:end])
+			if err != nil {
+				if ctx.Err() != nil && c.Request.Context().Err() == nil {
+					// Out of time: return what was read, listing the rest to request again
+					response.Truncated = true
+					for _, holder := range holders[start:] {
+						response.Unprocessed = append(response.Unprocessed, spellings[holder]...)
+					}
+					break
+				}
+				logger.WithError(err).Error("Failed to get portfolio summaries")
+				c.JSON(queryErrorStatus(c, err), gin.H{
+					"error": "Failed to get portfolio summaries",
+				})
+				return
+			}
+
+			for _, holder := range holders[start:end] {
+				summary := summaries[holder]
+				entry := models.PortfolioBatchEntry{
+					HoldingsCount:       summary.HoldingsCount,
+					TotalBalance:        summary.TotalBalance,
+					TotalClaimableYield: summary.TotalClaimableYield,
+					Complete:            len(summary.Errors) == 0,
+				}
+				// Holdings the service couldn't compute are listed rather than silently left out
+				for _, holdingErr := range summary.Errors {
+					entry.Warnings = append(entry.Warnings, models.PortfolioWarning{
+						SukukAddress: holdingErr.SukukAddress,
+						Reason:       holdingErr.Reason,
+					})
+				}
+				for _, address := range spellings[holder] {
+					response.Portfolios[address] = entry
+				}
+			}
+		}
+
+		if response.Truncated {
+			logger.WithFields(map[string]interface{}{
+				"requested":   len(req.Addresses),
+				"unprocessed": len(response.Unprocessed),
+			}).Warn("Portfolio batch ran out of time")
+		}
+		respondJSON(c, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/mocks"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// servePortfolioBatch posts addresses to the portfolio batch handler, reading through reader
+func servePortfolioBatch(t *testing.T, reader services.PortfolioReader, budget time.Duration, addresses []string) (int, models.PortfolioBatchResponse) {
+	t.Helper()
+	defer SetDeps(SetDeps(Deps{Portfolio: reader}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/portfolio/batch", GetPortfolioBatch(budget))
+
+	body, _ := json.Marshal(models.PortfolioBatchRequest{Addresses: addresses})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/portfolio/batch", strings.NewReader(string(body))))
+
+	var response models.PortfolioBatchResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return w.Code, response
+}
+
+func TestGetPortfolioBatch(t *testing.T) {
+	const holder = "0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"
+	const empty = "0x00000000000000000000000000000000000000e1"
+
+	var calls int
+	reader := &mocks.PortfolioReader{
+		GetPortfolioSummariesFunc: func(ctx context.Context, addresses []string) (map[string]services.PortfolioSummary, error) {
+			calls++
+			if len(addresses) != 2 {
+				t.Errorf("Expected the two valid addresses once each, got %v", addresses)
+			}
+			return map[string]services.PortfolioSummary{
+				strings.ToLower(holder): {HoldingsCount: 2, TotalBalance: "300", TotalClaimableYield: "40"},
+				empty:                   {TotalBalance: "0", TotalClaimableYield: "0"},
+			}, nil
+		},
+	}
+
+	code, response := servePortfolioBatch(t, reader, time.Second, []string{holder, strings.ToLower(holder), "not-an-address", "0x1234", empty})
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one batched read, got %d", calls)
+	}
+	if response.Truncated || len(response.Unprocessed) != 0 || len(response.Portfolios) != 5 {
+		t.Fatalf("Expected every address summarized, got %+v", response)
+	}
+
+	// Each spelling of the holder gets its summary
+	for _, address := range []string{holder, strings.ToLower(holder)} {
+		if entry := response.Portfolios[address]; entry.HoldingsCount != 2 || entry.TotalBalance != "300" || entry.TotalClaimableYield != "40" || entry.Error != "" {
+			t.Errorf("Expected the holder's summary for %s, got %+v", address, entry)
+		}
+	}
+	if entry := response.Portfolios[empty]; entry.HoldingsCount != 0 || entry.TotalBalance != "0" || entry.TotalClaimableYield != "0" || entry.Error != "" {
+		t.Errorf("Expected an empty portfolio for an address holding nothing, got %+v", entry)
+	}
+	for _, address := range []string{"not-an-address", "0x1234"} {
+		if entry := response.Portfolios[address]; entry.Error == "" {
+			t.Errorf("Expected an error entry for %s, got %+v", address, entry)
+		}
+	}
+}
+
+func TestGetPortfolioBatchLimits(t *testing.T) {
+	reader := &mocks.PortfolioReader{}
+	tooMany := make([]string, models.PortfolioBatchMaxAddresses+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("0x%040x", i+1)
+	}
+	for name, addresses := range map[string][]string{"none": {}, "too many": tooMany} {
+		if code, _ := servePortfolioBatch(t, reader, time.Second, addresses); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", name, code)
+		}
+	}
+
+	// Only invalid addresses need no read at all
+	code, response := servePortfolioBatch(t, reader, time.Second, []string{"nope"})
+	if code != http.StatusOK || response.Portfolios["nope"].Error == "" {
+		t.Errorf("Expected an error entry without reading, got %d %+v", code, response)
+	}
+}
+
+func TestGetPortfolioBatchTruncatesOnBudget(t *testing.T) {
+	addresses := make([]string, 60)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040x", i+1)
+	}
+
+	// The first chunk is read in time; the second outlasts the budget
+	var calls int
+	reader := &mocks.PortfolioReader{
+		GetPortfolioSummariesFunc: func(ctx context.Context, chunk []string) (map[string]services.PortfolioSummary, error) {
+			calls++
+			if calls > 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			summaries := make(map[string]services.PortfolioSummary, len(chunk))
+			for _, address := range chunk {
+				summaries[address] = services.PortfolioSummary{HoldingsCount: 1, TotalBalance: "10", TotalClaimableYield: "1"}
+			}
+			return summaries, nil
+		},
+	}
+
+	code, response := servePortfolioBatch(t, reader, 50*time.Millisecond, addresses)
+	if code != http.StatusOK {
+		t.Fatalf("Expected partial results with status 200, got %d", code)
+	}
+	if !response.Truncated {
+		t.Error("Expected the response to be truncated")
+	}
+	if len(response.Portfolios) != portfolioBatchChunkSize || len(response.Unprocessed) != len(addresses)-portfolioBatchChunkSize {
+		t.Errorf("Expected %d summaries and the rest unprocessed, got %d and %d", portfolioBatchChunkSize, len(response.Portfolios), len(response.Unprocessed))
+	}
+	if response.Portfolios[addresses[0]].HoldingsCount != 1 || response.Unprocessed[0] != addresses[portfolioBatchChunkSize] {
+		t.Errorf("Expected the first chunk summarized and the second unprocessed, got %+v", response)
+	}
+}
+
+func TestGetPortfolioBatchReportsFailedHoldings(t *testing.T) {
+	// The second holder also holds a sukuk whose distributed total is malformed
+	const broken = "0x00000000000000000000000000000000000000c3"
+	first, second := fmt.Sprintf("0x%040x", 0x1000), fmt.Sprintf("0x%040x", 0x1001)
+	indexer := portfolioBatchIndexer(2)
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		result := indexer(query)
+		switch {
+		case strings.Contains(query, "__holder_update"):
+			result.rows = append(result.rows, []driver.Value{"x", broken, second, "100", int64(1700000000), int64(10), "0xaa"})
+		case strings.Contains(query, "__yield_distributed"):
+			result.rows = append(result.rows, []driver.Value{broken, "not-an-amount"})
+		case strings.Contains(query, "__snapshot_taken"):
+			result.rows = append(result.rows, []driver.Value{broken, "4000"})
+		}
+		return result
+	})
+	defer func() { database.DB = previous }()
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	code, response := servePortfolioBatch(t, services.NewIndexerQueryService(), time.Second, []string{first, second})
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if entry := response.Portfolios[first]; !entry.Complete || len(entry.Warnings) != 0 || entry.HoldingsCount != 1 {
+		t.Errorf("Expected a complete summary for the first holder, got %+v", entry)
+	}
+	entry := response.Portfolios[second]
+	if entry.Complete || len(entry.Warnings) != 1 || entry.Warnings[0].SukukAddress != broken {
+		t.Fatalf("Expected the broken holding listed in the warnings, got %+v", entry)
+	}
+	if entry.HoldingsCount != 1 || entry.TotalBalance != "100" || entry.TotalClaimableYield != "20" {
+		t.Errorf("Expected the totals to cover only the computed holding, got %+v", entry)
+	}
+}
+
+// portfolioBatchIndexer answers the batch portfolio queries for count holders, each holding
+// the same sukuk: 1000 distributed on a supply of 4000, of which each holds 100 and claimed 5.
+// The first holder also has an emptied position in a second sukuk
+func portfolioBatchIndexer(count int) func(query string) stubResult {
+	const sukuk, emptied = "0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000b2"
+	holder := func(i int) string { return fmt.Sprintf("0x%040x", 0x1000+i) }
+
+	return func(query string) stubResult {
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			result := stubResult{columns: []string{"table_name", "table_schema"}}
+			for _, event := range []string{"holder_update", "yield_distributed", "yield_claim", "snapshot_taken", "redemption_request"} {
+				result.rows = append(result.rows, []driver.Value{"0a__" + event, "public"})
+			}
+			return result
+		case strings.Contains(query, "indexer_table_overrides"):
+			return stubResult{columns: []string{"id"}}
+		case strings.Contains(query, "__holder_update"):
+			result := stubResult{columns: []string{"id", "sukuk_address", "holder", "new_balance", "timestamp", "block_number", "tx_hash"}}
+			result.rows = append(result.rows, []driver.Value{"e", emptied, holder(0), "0", int64(1700000000), int64(10), "0xaa"})
+			for i := 0; i < count; i++ {
+				result.rows = append(result.rows, []driver.Value{fmt.Sprintf("h%d", i), sukuk, holder(i), "100", int64(1700000000), int64(10), "0xaa"})
+			}
+			return result
+		case strings.Contains(query, "__yield_distributed"):
+			return stubResult{columns: []string{"sukuk_address", "total"}, rows: [][]driver.Value{{sukuk, "1000"}}}
+		case strings.Contains(query, "__yield_claim"):
+			result := stubResult{columns: []string{"holder", "sukuk_address", "total"}}
+			for i := 0; i < count; i++ {
+				result.rows = append(result.rows, []driver.Value{holder(i), sukuk, "5"})
+			}
+			return result
+		case strings.Contains(query, "__snapshot_taken"):
+			return stubResult{columns: []string{"sukuk_address", "total"}, rows: [][]driver.Value{{sukuk, "4000"}}}
+		}
+		return stubResult{}
+	}
+}
+
+// countPortfolioBatchStatements summarizes the portfolios of count holders plus one address
+// holding nothing, and returns how many statements gorm executed
+func countPortfolioBatchStatements(t *testing.T, count int) int64 {
+	t.Helper()
+	db := openStubDB(t, portfolioBatchIndexer(count))
+
+	var statements int64
+	counter := func(*gorm.DB) { atomic.AddInt64(&statements, 1) }
+	db.Callback().Query().After("gorm:query").Register("test:count_query", counter)
+	db.Callback().Raw().After("gorm:raw").Register("test:count_raw", counter)
+
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	const nothing = "0x00000000000000000000000000000000000000e1"
+	addresses := []string{nothing}
+	for i := 0; i < count; i++ {
+		addresses = append(addresses, fmt.Sprintf("0x%040x", 0x1000+i))
+	}
+	summaries, err := services.NewIndexerQueryService().GetPortfolioSummaries(context.Background(), addresses)
+	if err != nil {
+		t.Fatalf("Failed to summarize %d portfolios: %v", count, err)
+	}
+	if len(summaries) != count+1 {
+		t.Fatalf("Expected %d summaries, got %d", count+1, len(summaries))
+	}
+	for _, address := range addresses[1:] {
+		if summary := summaries[address]; summary.HoldingsCount != 1 || summary.TotalBalance != "100" || summary.TotalClaimableYield != "20" {
+			t.Errorf("Expected one holding of 100 with 20 claimable for %s, got %+v", address, summary)
+		}
+	}
+	if summary := summaries[nothing]; summary.HoldingsCount != 0 || summary.TotalBalance != "0" || summary.TotalClaimableYield != "0" {
+		t.Errorf("Expected an empty summary for an address holding nothing, got %+v", summary)
+	}
+	return statements
+}
+
+func TestPortfolioBatchQueryCountIsIndependentOfAddresses(t *testing.T) {
+	single := countPortfolioBatchStatements(t, 1)
+	many := countPortfolioBatchStatements(t, 50)
+	if single == 0 {
+		t.Fatal("Expected the statement counter to see the portfolio queries")
+	}
+	if single != many {
+		t.Errorf("Expected the same number of statements for 1 and 50 addresses, got %d and %d", single, many)
+	}
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"sukuk-be/internal/cache"
 	"sukuk-be/internal/database"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
@@ -16,11 +20,12 @@ import (
 
 // GetUserPortfolio returns user's complete portfolio with holdings and claimable yields
 // @Summary Get user portfolio
-// @Description Get complete portfolio showing all sukuk holdings with current balances and claimable yields
+// @Description Get complete portfolio showing all sukuk holdings with current balances and claimable yields. Includes the investor's KYC status when called with an API key. Holdings that can't be computed are left out and listed in warnings, with complete set to false; the request only fails when none can be.
 // @Tags portfolio
 // @Accept json
 // @Produce json
 // @Param address path string true "User wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Param fiat query string false "Comma-separated currencies for summary.display_values, e.g. idr,usd; the rates used are listed in meta" Example(idr,usd)
 // @Success 200 {object} models.PortfolioResponse "User portfolio with holdings"
 // @Failure 400 {object} map[string]string "Invalid address"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -34,31 +39,67 @@ func GetUserPortfolio(c *gin.Context) {
 		return
 	}
 
-	// Initialize indexer query service
-	indexerService := services.NewIndexerQueryService()
-
-	// Get user portfolio from indexer
-	portfolio, err := indexerService.GetUserPortfolio(address)
+	response, hit, err := cache.Fetch(c.Request.Context(), cache.PortfolioKey(address), cacheTTL(services.SettingCachePortfolioTTL, cache.PortfolioTTL), func() (*models.PortfolioResponse, error) {
+		return buildPortfolioResponse(c.Request.Context(), address)
+	})
+	setCacheStatus(c, hit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get user portfolio")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get user portfolio",
 		})
 		return
 	}
 
-	// Initialize math utility and response
+	// KYC status is only shown to admins, so it is added after caching
+	response.KYCStatus = adminKYCStatus(c, address)
+
+	// Fiat display values follow the request and the current rates, so they are added after caching too
+	if display := newFiatDisplay(c); display != nil {
+		addPortfolioDisplayValues(response, display, loadTokenFormatter())
+	}
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// addPortfolioDisplayValues prices the summary totals from each holding's yield, paid in the
+// payment token of its latest distribution
+func addPortfolioDisplayValues(response *models.PortfolioResponse, display *fiatDisplay, formatter *services.TokenFormatter) {
+	var claimable, claimed []models.FormattedAmount
+	for _, holding := range response.Holdings {
+		var paymentToken string
+		if len(holding.YieldHistory) > 0 {
+			paymentToken = holding.YieldHistory[0].PaymentToken
+		}
+		claimable = append(claimable, formatter.FormatTokenAmount(holding.ClaimableYield, paymentToken))
+		claimed = append(claimed, formatter.FormatTokenAmount(holding.TotalYieldClaimed, paymentToken))
+	}
+
+	response.Summary.DisplayValues = display.fields(map[string][]models.FormattedAmount{
+		"total_claimable_yield": claimable,
+		"total_yield_claimed":   claimed,
+	})
+	response.Meta = display.meta()
+}
+
+// buildPortfolioResponse assembles holdings, yield history and summary for an address
+func buildPortfolioResponse(ctx context.Context, address string) (*models.PortfolioResponse, error) {
+	// Get user portfolio from indexer
+	portfolio, err := currentDeps().Portfolio.GetUserPortfolio(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize math utility, token formatter and response
 	mathUtil := utils.GlobalTokenMath
-	// Initialize with empty arrays to avoid null in JSON
+	tokenFormatter := loadTokenFormatter()
 	holdings := make([]models.SukukHolding, len(portfolio.Holdings))
-	if len(portfolio.Holdings) == 0 {
-		holdings = []models.SukukHolding{}
-	}
 
 	response := models.PortfolioResponse{
 		Address:       address,
 		TotalHoldings: len(portfolio.Holdings),
 		Holdings:      holdings,
+		Complete:      len(portfolio.Errors) == 0,
 		Summary: models.PortfolioSummary{
 			TotalSukukCount:     len(portfolio.Holdings),
 			TotalClaimableYield: "0",
@@ -68,55 +109,69 @@ func GetUserPortfolio(c *gin.Context) {
 		},
 	}
 
+	// Holdings the service couldn't compute are listed rather than silently left out
+	for _, holdingErr := range portfolio.Errors {
+		response.Warnings = append(response.Warnings, models.PortfolioWarning{
+			SukukAddress: holdingErr.SukukAddress,
+			Reason:       holdingErr.Reason,
+		})
+	}
+
+	// Load the metadata of every holding in one query
+	metadataByAddress := make(map[string]*models.SukukMetadata, len(portfolio.Holdings))
+	if len(portfolio.Holdings) > 0 {
+		sukukAddresses := make([]string, len(portfolio.Holdings))
+		for i, holding := range portfolio.Holdings {
+			sukukAddresses[i] = strings.ToLower(holding.SukukAddress)
+		}
+		var metadata []models.SukukMetadata
+		if err := database.GetDB().WithContext(ctx).Where("LOWER(contract_address) IN ?", sukukAddresses).Find(&metadata).Error; err != nil {
+			logger.WithError(err).Warn("Failed to load sukuk metadata for portfolio")
+		}
+		for i := range metadata {
+			metadataByAddress[strings.ToLower(metadata[i].ContractAddress)] = &metadata[i]
+		}
+	}
+
 	// Track totals for summary
 	var totalClaimableAmounts []string
 	var totalClaimedAmounts []string
 
-	// Get sukuk metadata for each holding and enrich response
+	// Enrich each holding from the batched results
 	for i, holding := range portfolio.Holdings {
 		// Convert service holding to API holding
 		apiHolding := models.SukukHolding{
-			SukukAddress:   holding.SukukAddress,
-			Balance:        holding.Balance,
-			ClaimableYield: holding.ClaimableYield,
-		}
-
-		// Get sukuk metadata from database
-		var sukukMetadata models.SukukMetadata
-		if err := database.GetDB().Where("contract_address = ?", holding.SukukAddress).First(&sukukMetadata).Error; err == nil {
-			apiHolding.Metadata = &sukukMetadata
+			SukukAddress:           holding.SukukAddress,
+			Balance:                holding.Balance,
+			ClaimableYield:         holding.ClaimableYield,
+			SupplySource:           holding.SupplySource,
+			TotalYieldClaimed:      holding.TotalYieldClaimed,
+			UnclaimedDistributions: holding.UnclaimedDistributions,
+			Metadata:               metadataByAddress[strings.ToLower(holding.SukukAddress)],
+			YieldHistory:           make([]models.YieldDistribution, len(holding.RecentDistributions)),
 		}
+		totalClaimedAmounts = append(totalClaimedAmounts, holding.TotalYieldClaimed)
 
-		// Get recent yield distributions for this sukuk
-		distributions, err := indexerService.GetYieldDistributions(holding.SukukAddress, 5)
-		if err == nil && len(distributions) > 0 {
-			apiHolding.YieldHistory = make([]models.YieldDistribution, len(distributions))
+		// Recent yield distributions for this sukuk, newest first
+		if distributions := holding.RecentDistributions; len(distributions) > 0 {
 			for j, dist := range distributions {
+				amountFormatted := tokenFormatter.FormatTokenAmount(dist.Amount, dist.PaymentToken)
 				apiHolding.YieldHistory[j] = models.YieldDistribution{
-					ID:             dist.ID,
-					SukukAddress:   dist.SukukAddress,
-					DistributionId: dist.DistributionId, // Include distribution ID
-					Amount:         dist.Amount,
-					Timestamp:      time.Unix(dist.Timestamp, 0),
-					TxHash:         dist.TxHash,
-					BlockNumber:    dist.BlockNumber,
+					ID:              dist.ID,
+					SukukAddress:    dist.SukukAddress,
+					DistributionId:  dist.DistributionId, // Include distribution ID
+					PaymentToken:    dist.PaymentToken,
+					Amount:          dist.Amount,
+					AmountFormatted: &amountFormatted,
+					Timestamp:       time.Unix(dist.Timestamp, 0),
+					TxHash:          dist.TxHash,
+					BlockNumber:     dist.BlockNumber,
 				}
 			}
-		}
-
-		// Get unclaimed distribution IDs for this user and sukuk
-		unclaimedIds, err := indexerService.GetUnclaimedDistributionIds(address, holding.SukukAddress)
-		if err == nil {
-			apiHolding.UnclaimedDistributions = unclaimedIds
-		} else {
-			apiHolding.UnclaimedDistributions = []int64{} // Ensure empty array instead of null
-		}
 
-		// Get total yield claimed by user for this sukuk
-		totalClaimed, err := indexerService.GetTotalYieldClaimed(address, holding.SukukAddress)
-		if err == nil {
-			apiHolding.TotalYieldClaimed = totalClaimed
-			totalClaimedAmounts = append(totalClaimedAmounts, totalClaimed)
+			// Yield is paid in the payment token of the latest distribution
+			claimableFormatted := tokenFormatter.FormatTokenAmount(holding.ClaimableYield, distributions[0].PaymentToken)
+			apiHolding.ClaimableYieldFormatted = &claimableFormatted
 		}
 
 		response.Holdings[i] = apiHolding
@@ -135,16 +190,11 @@ func GetUserPortfolio(c *gin.Context) {
 		// This would require checking maturity timestamp from metadata against current time
 	}
 
-	// Calculate summary totals
-	if totalClaimable, err := mathUtil.SumTokenAmounts(totalClaimableAmounts); err == nil {
-		response.Summary.TotalClaimableYield = totalClaimable
-	}
-	
-	if totalClaimed, err := mathUtil.SumTokenAmounts(totalClaimedAmounts); err == nil {
-		response.Summary.TotalYieldClaimed = totalClaimed
-	}
+	// Calculate summary totals, skipping malformed amounts rather than zeroing the whole total
+	response.Summary.TotalClaimableYield, _ = mathUtil.SumValidTokenAmounts(totalClaimableAmounts)
+	response.Summary.TotalYieldClaimed, _ = mathUtil.SumValidTokenAmounts(totalClaimedAmounts)
 
-	c.JSON(http.StatusOK, response)
+	return &response, nil
 }
 
 // GetYieldClaims returns available yield claims for a user
@@ -167,19 +217,28 @@ func GetYieldClaims(c *gin.Context) {
 		return
 	}
 
-	// Initialize indexer query service
-	indexerService := services.NewIndexerQueryService()
-
-	// Get sukuk addresses owned by user
-	sukukAddresses, err := indexerService.GetSukukOwnedByAddress(address)
+	response, err := buildYieldClaimsResponse(c.Request.Context(), address)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get owned sukuk addresses")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get yield claims",
 		})
 		return
 	}
 
+	respondJSON(c, http.StatusOK, response)
+}
+
+// buildYieldClaimsResponse collects the claimable yield of every sukuk an address holds
+func buildYieldClaimsResponse(ctx context.Context, address string) (*models.YieldClaimsResponse, error) {
+	indexerService := currentDeps().Portfolio
+
+	// Get sukuk addresses owned by user
+	sukukAddresses, err := indexerService.GetSukukOwnedByAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize math utility for amount calculations
 	mathUtil := utils.GlobalTokenMath
 	response := models.YieldClaimsResponse{
@@ -195,19 +254,25 @@ func GetYieldClaims(c *gin.Context) {
 	// For each sukuk, calculate claimable yield
 	for _, sukukAddr := range sukukAddresses {
 		// Get current balance
-		balance, err := indexerService.GetCurrentBalance(address, sukukAddr)
+		balance, err := indexerService.GetCurrentBalance(ctx, address, sukukAddr)
 		if err != nil || mathUtil.IsZero(balance) {
 			continue // Skip if no balance or error
 		}
 
 		// Get claimable yield
-		claimableAmount, err := indexerService.GetClaimableYield(address, sukukAddr)
+		claimableAmount, err := indexerService.GetClaimableYield(ctx, address, sukukAddr)
 		if err != nil {
 			continue
 		}
 
+		// Distributions the user can still claim; an empty list when they can't be read
+		unclaimed, err := indexerService.GetUnclaimedDistributionIds(ctx, address, sukukAddr)
+		if err != nil {
+			logger.WithError(err).WithField("sukuk_address", sukukAddr).Warn("Failed to get unclaimed distributions")
+		}
+
 		// Get latest yield distributions
-		distributions, err := indexerService.GetYieldDistributions(sukukAddr, 10)
+		distributions, err := indexerService.GetYieldDistributions(ctx, sukukAddr, 10)
 		var lastDistribution *time.Time
 		distributionCount := 0
 		if err == nil {
@@ -220,46 +285,122 @@ func GetYieldClaims(c *gin.Context) {
 
 		// Get sukuk metadata
 		var sukukMetadata models.SukukMetadata
-		if err := database.GetDB().Where("contract_address = ?", sukukAddr).First(&sukukMetadata).Error; err != nil {
+		if err := database.GetDB().WithContext(ctx).Where("contract_address = ?", sukukAddr).First(&sukukMetadata).Error; err != nil {
 			sukukMetadata.ContractAddress = sukukAddr // Fallback
 		}
 
 		claimDetail := models.YieldClaimDetail{
-			SukukAddress:      sukukAddr,
-			ClaimableAmount:   claimableAmount,
-			LastDistribution:  lastDistribution,
-			DistributionCount: distributionCount,
-			UserBalance:       balance,
-			Metadata:          &sukukMetadata,
+			SukukAddress:           sukukAddr,
+			ClaimableAmount:        claimableAmount,
+			LastDistribution:       lastDistribution,
+			DistributionCount:      distributionCount,
+			UserBalance:            balance,
+			UnclaimedDistributions: unclaimed,
+			Metadata:               &sukukMetadata,
 		}
 
 		response.Claims = append(response.Claims, claimDetail)
 		response.TotalClaims++
 
-		// Add claimable amount to total calculation
+		// Only positive amounts count towards the total; empty or malformed ones are reported
+		// as skipped rather than zeroing it
 		if mathUtil.IsPositive(claimableAmount) {
 			claimableAmounts = append(claimableAmounts, claimableAmount)
+		} else if _, err := mathUtil.CompareTokenAmounts(claimableAmount, "0"); err != nil || claimableAmount == "" {
+			response.SkippedRows++
 		}
 	}
 
 	// Calculate total claimable amount using proper BigInt math
-	if totalAmount, err := mathUtil.SumTokenAmounts(claimableAmounts); err == nil {
-		response.TotalAmount = totalAmount
+	response.TotalAmount, _ = mathUtil.SumValidTokenAmounts(claimableAmounts)
+
+	return &response, nil
+}
+
+// GetTaxReport returns a yearly statement of the yield an address claimed
+// @Summary Get yearly yield tax report
+// @Description Aggregate the yield claims of an address within a calendar year (Asia/Jakarta boundaries), grouped by sukuk with per-sukuk and grand totals per payment token. Amounts are raw wei with values humanized by the payment token decimals. Years without claims return an empty report
+// @Tags portfolio
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Param address path string true "User wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Param year query int false "Calendar year, defaults to the current year" Example(2024)
+// @Param format query string false "Output format" Enums(json, csv) default(json)
+// @Success 200 {object} models.TaxReport "Yearly yield income statement"
+// @Failure 400 {object} map[string]string "Invalid address, year or format"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /portfolio/{address}/tax-report [get]
+func GetTaxReport(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	now := time.Now()
+	year := services.CurrentTaxYear(now)
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid year",
+				"details": "year must be a number such as 2024",
+			})
+			return
+		}
+		year = parsed
+	}
+	if err := services.ValidateTaxYear(year, now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid year",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	renderer, ok := services.GetTaxReportRenderer(format)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported format",
+			"details": fmt.Sprintf("format must be one of %s", strings.Join(services.TaxReportFormats(), ", ")),
+		})
+		return
+	}
+
+	report, err := services.GenerateTaxReport(c.Request.Context(), address, year)
+	if err != nil {
+		logger.WithError(err).Error("Failed to generate tax report")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to generate tax report",
+		})
+		return
+	}
+
+	if format != "json" {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-report-%s-%d.%s"`, strings.ToLower(address), year, renderer.FileExtension()))
+	}
+	c.Header("Content-Type", renderer.ContentType())
+	c.Status(http.StatusOK)
+	if err := renderer.Render(c.Writer, report); err != nil {
+		logger.WithError(err).Error("Failed to render tax report")
+	}
 }
 
 // GetTransactionHistory returns complete transaction history for a user
 // @Summary Get transaction history
-// @Description Get complete transaction history including purchases, redemptions, and yield claims
+// @Description Get complete transaction history including purchases, redemptions, yield claims and transfers between wallets. A transfer is a balance change no purchase, redemption request or yield claim in the same transaction explains, e.g. a plain ERC-20 transfer; details carry the counterparty when exactly one other wallet moved the opposite way. Includes the investor's KYC status when called with an API key.
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Param address path string true "User wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
 // @Param limit query int false "Number of transactions to return" default(50) minimum(1) maximum(200)
+// @Param type query string false "Only return this activity type" Enums(purchase, redemption_request, yield_claim, transfer_in, transfer_out)
 // @Success 200 {object} models.TransactionHistoryResponse "Transaction history"
-// @Failure 400 {object} map[string]string "Invalid address or parameters"
+// @Failure 400 {object} map[string]string "Invalid address, parameters or activity type"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /transactions/{address} [get]
 func GetTransactionHistory(c *gin.Context) {
@@ -281,31 +422,42 @@ func GetTransactionHistory(c *gin.Context) {
 		limit = 200 // Cap at 200 for performance
 	}
 
-	// Initialize indexer query service
-	indexerService := services.NewIndexerQueryService()
+	activityType, ok := activityTypeQuery(c)
+	if !ok {
+		return
+	}
 
-	// Get all transactions efficiently with database-level filtering and sorting
-	allTransactions, err := indexerService.GetUserTransactionHistory(address, limit)
+	response, err := buildTransactionHistoryResponse(c.Request.Context(), address, activityType, limit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get user transaction history")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get transaction history",
 		})
 		return
 	}
+	response.KYCStatus = adminKYCStatus(c, address)
+
+	respondJSON(c, http.StatusOK, response)
+}
+
+// buildTransactionHistoryResponse reads the latest transactions of an address, newest first
+func buildTransactionHistoryResponse(ctx context.Context, address string, activityType models.ActivityType, limit int) (*models.TransactionHistoryResponse, error) {
+	// Get all transactions efficiently with database-level filtering and sorting
+	allTransactions, err := currentDeps().Activity.GetUserTransactionHistory(ctx, address, activityType, limit)
+	if err != nil {
+		return nil, err
+	}
 
 	// Ensure empty array instead of null
 	if allTransactions == nil {
 		allTransactions = []models.TransactionEvent{}
 	}
 
-	response := models.TransactionHistoryResponse{
+	return &models.TransactionHistoryResponse{
 		Address:      address,
 		TotalCount:   len(allTransactions),
 		Transactions: allTransactions,
-	}
-
-	c.JSON(http.StatusOK, response)
+	}, nil
 }
 
 // GetYieldDistributions returns yield distribution history for a sukuk
@@ -316,7 +468,7 @@ func GetTransactionHistory(c *gin.Context) {
 // @Produce json
 // @Param sukuk_address path string true "Sukuk contract address"
 // @Param limit query int false "Number of distributions to return" default(20)
-// @Success 200 {object} map[string]interface{} "Yield distributions"
+// @Success 200 {object} models.YieldDistributionsResponse "Yield distributions with amounts formatted in the payment token's decimals"
 // @Failure 400 {object} map[string]string "Invalid parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /yield-distributions/{sukuk_address} [get]
@@ -339,36 +491,128 @@ func GetYieldDistributions(c *gin.Context) {
 		limit = 100
 	}
 
-	// Initialize indexer query service
-	indexerService := services.NewIndexerQueryService()
-
 	// Get yield distributions
-	distributions, err := indexerService.GetYieldDistributions(sukukAddress, limit)
+	distributions, err := currentDeps().Portfolio.GetYieldDistributions(c.Request.Context(), sukukAddress, limit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get yield distributions")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get yield distributions",
 		})
 		return
 	}
 
-	// Convert to API format
+	// Convert to API format with amounts in the payment token's decimals
+	tokenFormatter := loadTokenFormatter()
 	apiDistributions := make([]models.YieldDistribution, len(distributions))
 	for i, dist := range distributions {
+		amountFormatted := tokenFormatter.FormatTokenAmount(dist.Amount, dist.PaymentToken)
 		apiDistributions[i] = models.YieldDistribution{
-			ID:             dist.ID,
-			SukukAddress:   dist.SukukAddress,
-			DistributionId: dist.DistributionId, // Include distribution ID
-			Amount:         dist.Amount,
-			Timestamp:      time.Unix(dist.Timestamp, 0),
-			TxHash:         dist.TxHash,
-			BlockNumber:    dist.BlockNumber,
+			ID:              dist.ID,
+			SukukAddress:    dist.SukukAddress,
+			DistributionId:  dist.DistributionId, // Include distribution ID
+			PaymentToken:    dist.PaymentToken,
+			Amount:          dist.Amount,
+			AmountFormatted: &amountFormatted,
+			Timestamp:       time.Unix(dist.Timestamp, 0),
+			TxHash:          dist.TxHash,
+			BlockNumber:     dist.BlockNumber,
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"sukuk_address":   sukukAddress,
-		"total_count":     len(apiDistributions),
-		"distributions":   apiDistributions,
+	respondJSON(c, http.StatusOK, models.YieldDistributionsResponse{
+		SukukAddress:  sukukAddress,
+		TotalCount:    len(apiDistributions),
+		Distributions: apiDistributions,
+	})
+}
+// GetBalanceHistory returns the timeline of an address's balance on one sukuk
+// @Summary Get balance history
+// @Description Page through the holder_update rows of an address on a sukuk, oldest first, with the signed change from the previous balance. Each change carries the type of the purchase, redemption request or yield claim in the same transaction, or "transfer" when there is none (e.g. a plain ERC-20 transfer). Balances are raw values
+// @Tags portfolio
+// @Produce json
+// @Param address path string true "User wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Param sukuk_address path string true "Sukuk contract address"
+// @Param from query int false "Only changes at or after this unix timestamp in seconds"
+// @Param to query int false "Only changes at or before this unix timestamp in seconds"
+// @Param page query int false "Page number" default(1) minimum(1)
+// @Param per_page query int false "Changes per page" default(20) minimum(1) maximum(100)
+// @Success 200 {object} models.BalanceHistoryResponse "Balance changes"
+// @Failure 400 {object} map[string]string "Invalid address, time range or pagination"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /portfolio/{address}/balance-history/{sukuk_address} [get]
+func GetBalanceHistory(c *gin.Context) {
+	address := c.Param("address")
+	sukukAddress := c.Param("sukuk_address")
+	if !utils.IsValidEthereumAddress(address) || !utils.IsValidEthereumAddress(sukukAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	from, ok := unixQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := unixQuery(c, "to")
+	if !ok {
+		return
+	}
+	if from != nil && to != nil && *to < *from {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid time range",
+			"details": "to must not be before from",
+		})
+		return
+	}
+
+	page, perPage, err := parsePageQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid pagination",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	changes, total, err := currentDeps().Portfolio.GetBalanceHistory(c.Request.Context(), address, sukukAddress, services.BalanceHistoryFilter{
+		From:   from,
+		To:     to,
+		Offset: (page - 1) * perPage,
+		Limit:  perPage,
 	})
-}
\ No newline at end of file
+	if err != nil {
+		logger.WithError(err).Error("Failed to get balance history")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to get balance history",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.BalanceHistoryResponse{
+		Address:      address,
+		SukukAddress: sukukAddress,
+		Changes:      changes,
+		TotalCount:   total,
+		Page:         page,
+		PerPage:      perPage,
+		TotalPages:   int((total + int64(perPage) - 1) / int64(perPage)),
+	})
+}
+
+// unixQuery parses an optional unix timestamp in seconds, responding 400 when it is malformed
+func unixQuery(c *gin.Context, name string) (*int64, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || seconds < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid " + name,
+			"details": name + " must be a unix timestamp in seconds",
+		})
+		return nil, false
+	}
+	return &seconds, true
+}
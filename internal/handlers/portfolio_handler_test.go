@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/mocks"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetTaxReportRejectsInvalidParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/portfolio/:address/tax-report", GetTaxReport)
+
+	const address = "0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"
+	nextYear := strconv.Itoa(time.Now().Year() + 2)
+
+	for _, path := range []string{
+		"/portfolio/not-an-address/tax-report",
+		"/portfolio/" + address + "/tax-report?year=" + nextYear,
+		"/portfolio/" + address + "/tax-report?year=last",
+		"/portfolio/" + address + "/tax-report?year=2024&format=pdf",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
+
+// servePortfolio runs one request against a router with the portfolio and transaction
+// history routes, reading through the given fakes
+func servePortfolio(t *testing.T, deps Deps, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	defer SetDeps(SetDeps(deps))
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/portfolio/:address", GetUserPortfolio)
+	router.GET("/portfolio/:address/balance-history/:sukuk_address", GetBalanceHistory)
+	router.GET("/transactions/:address", GetTransactionHistory)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetUserPortfolioFromReader(t *testing.T) {
+	const sukukA = "0x00000000000000000000000000000000000000a1"
+	const sukukB = "0x00000000000000000000000000000000000000b2"
+
+	// Sukuk metadata and payment tokens still come from the local database, stubbed here
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		if strings.Contains(query, "sukuk_metadata") {
+			return stubResult{columns: []string{"id", "contract_address", "sukuk_code"}, rows: [][]driver.Value{{int64(1), sukukA, "SR021"}}}
+		}
+		return stubResult{columns: []string{"id"}}
+	})
+	defer func() { database.DB = previous }()
+
+	reader := &mocks.PortfolioReader{
+		GetUserPortfolioFunc: func(ctx context.Context, address string) (*services.UserPortfolio, error) {
+			if address != portfolioTestHolder {
+				t.Errorf("Expected the holder address, got %s", address)
+			}
+			return &services.UserPortfolio{Address: address, Holdings: []services.SukukHolding{
+				{SukukAddress: sukukA, Balance: "100", ClaimableYield: "20", TotalYieldClaimed: "5", UnclaimedDistributions: []int64{2},
+					RecentDistributions: []services.IndexerYieldDistributed{{ID: "d1", SukukAddress: sukukA, DistributionId: 2, Amount: "600", Timestamp: 1700000000}}},
+				{SukukAddress: sukukB, Balance: "0", ClaimableYield: "0", TotalYieldClaimed: "7"},
+			}}, nil
+		},
+	}
+
+	w := servePortfolio(t, Deps{Portfolio: reader}, "/portfolio/"+portfolioTestHolder)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response models.PortfolioResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.TotalHoldings != 2 || response.Summary.ActiveSukukCount != 1 ||
+		response.Summary.TotalClaimableYield != "20" || response.Summary.TotalYieldClaimed != "12" {
+		t.Errorf("Unexpected summary: %+v", response.Summary)
+	}
+	if metadata := response.Holdings[0].Metadata; metadata == nil || metadata.SukukCode != "SR021" {
+		t.Errorf("Expected the first holding to carry its metadata, got %+v", metadata)
+	}
+	if response.Holdings[1].Metadata != nil || len(response.Holdings[0].YieldHistory) != 1 || response.Holdings[0].ClaimableYieldFormatted == nil {
+		t.Errorf("Unexpected holdings: %+v", response.Holdings)
+	}
+}
+
+func TestGetUserPortfolioIndexerUnavailable(t *testing.T) {
+	reader := &mocks.PortfolioReader{
+		GetUserPortfolioFunc: func(context.Context, string) (*services.UserPortfolio, error) {
+			return nil, &services.IndexerUnavailableError{RetryAfter: 3 * time.Second}
+		},
+	}
+
+	w := servePortfolio(t, Deps{Portfolio: reader}, "/portfolio/"+portfolioTestHolder)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "3" {
+		t.Errorf("Expected 503 with Retry-After 3, got %d and %q", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+// failingPortfolioIndexer answers the portfolio queries for a holder of three sukuk, with a
+// malformed distributed total for each sukuk in failing
+func failingPortfolioIndexer(failing ...string) func(query string) stubResult {
+	indexer := portfolioIndexer(3)
+	return func(query string) stubResult {
+		result := indexer(query)
+		if strings.Contains(query, "__yield_distributed") && !strings.Contains(query, "ROW_NUMBER") && !strings.Contains(query, "NOT EXISTS") {
+			for _, row := range result.rows {
+				for _, sukuk := range failing {
+					if row[0] == sukuk {
+						row[1] = "not-a-number"
+					}
+				}
+			}
+		}
+		return result
+	}
+}
+
+func TestGetUserPortfolioReportsFailedHoldings(t *testing.T) {
+	failing := fmt.Sprintf("0x%040x", 2)
+	previous := database.DB
+	database.DB = openStubDB(t, failingPortfolioIndexer(failing))
+	defer func() { database.DB = previous }()
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	w := servePortfolio(t, Deps{}, "/portfolio/"+portfolioTestHolder)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 for a partial portfolio, got %d: %s", w.Code, w.Body.String())
+	}
+	var response models.PortfolioResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// The other two holdings are still returned, with a warning for the failed one
+	if response.Complete || response.TotalHoldings != 2 || response.Summary.TotalClaimableYield != "40" {
+		t.Errorf("Expected an incomplete portfolio of two holdings, got %+v", response)
+	}
+	for _, holding := range response.Holdings {
+		if holding.SukukAddress == failing {
+			t.Errorf("Expected the failed holding to be left out, got %+v", holding)
+		}
+	}
+	if len(response.Warnings) != 1 || response.Warnings[0].SukukAddress != failing || response.Warnings[0].Reason == "" {
+		t.Errorf("Expected one warning for %s, got %+v", failing, response.Warnings)
+	}
+}
+
+func TestGetUserPortfolioComplete(t *testing.T) {
+	previous := database.DB
+	database.DB = openStubDB(t, failingPortfolioIndexer())
+	defer func() { database.DB = previous }()
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	w := servePortfolio(t, Deps{}, "/portfolio/"+portfolioTestHolder)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"complete":true`) || strings.Contains(w.Body.String(), `"warnings"`) {
+		t.Errorf("Expected a complete portfolio without warnings, got %s", w.Body.String())
+	}
+}
+
+func TestGetUserPortfolioNothingComputed(t *testing.T) {
+	previous := database.DB
+	database.DB = openStubDB(t, failingPortfolioIndexer(fmt.Sprintf("0x%040x", 1), fmt.Sprintf("0x%040x", 2), fmt.Sprintf("0x%040x", 3)))
+	defer func() { database.DB = previous }()
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	_, err := services.NewIndexerQueryService().GetUserPortfolio(context.Background(), portfolioTestHolder)
+	var portfolioErr *services.PortfolioError
+	if !errors.As(err, &portfolioErr) || len(portfolioErr.Errors) != 3 {
+		t.Fatalf("Expected a PortfolioError listing the three holdings, got %v", err)
+	}
+
+	if w := servePortfolio(t, Deps{}, "/portfolio/"+portfolioTestHolder); w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500 when no holding could be computed, got %d", w.Code)
+	}
+}
+
+func TestGetTransactionHistoryFromReader(t *testing.T) {
+	var gotType models.ActivityType
+	var gotLimit int
+	reader := &mocks.ActivityReader{
+		GetUserTransactionHistoryFunc: func(ctx context.Context, address string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error) {
+			gotType, gotLimit = activityType, limit
+			return nil, nil
+		},
+	}
+
+	w := servePortfolio(t, Deps{Activity: reader}, "/transactions/"+portfolioTestHolder+"?limit=500&type=yield_claim")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotType != models.ActivityTypeYieldClaim || gotLimit != 200 {
+		t.Errorf("Expected yield claims capped at 200, got %s and %d", gotType, gotLimit)
+	}
+	if !strings.Contains(w.Body.String(), `"transactions":[]`) {
+		t.Errorf("Expected an empty transactions array, got %s", w.Body.String())
+	}
+
+	reader.GetUserTransactionHistoryFunc = nil
+	if w := servePortfolio(t, Deps{Activity: reader}, "/transactions/"+portfolioTestHolder+"?type=transfer"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown type to be rejected before reading, got %d", w.Code)
+	}
+}
+
+func TestGetBalanceHistoryPagesReader(t *testing.T) {
+	const sukuk = "0x00000000000000000000000000000000000000a1"
+	var gotFilter services.BalanceHistoryFilter
+	reader := &mocks.PortfolioReader{
+		GetBalanceHistoryFunc: func(ctx context.Context, holder, sukukAddress string, filter services.BalanceHistoryFilter) ([]models.BalanceChange, int64, error) {
+			gotFilter = filter
+			return []models.BalanceChange{{NewBalance: "100", Delta: "100", EventType: "purchase"}}, 25, nil
+		},
+	}
+
+	w := servePortfolio(t, Deps{Portfolio: reader}, "/portfolio/"+portfolioTestHolder+"/balance-history/"+sukuk+"?page=2&per_page=10&from=1700000000")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotFilter.Offset != 10 || gotFilter.Limit != 10 || gotFilter.From == nil || *gotFilter.From != 1700000000 || gotFilter.To != nil {
+		t.Errorf("Unexpected filter: %+v", gotFilter)
+	}
+	var response models.BalanceHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.TotalCount != 25 || response.TotalPages != 3 || len(response.Changes) != 1 {
+		t.Errorf("Unexpected page: %+v", response)
+	}
+}
+
+func TestYieldClaimsTotalOnlyCountsPositiveAmounts(t *testing.T) {
+	claimable := map[string]string{"0xa1": "20", "0xa2": "-5", "0xa3": "0", "0xa4": "", "0xa5": "not-a-number"}
+	reader := &mocks.PortfolioReader{
+		GetSukukOwnedByAddressFunc: func(ctx context.Context, address string) ([]string, error) {
+			return []string{"0xa1", "0xa2", "0xa3", "0xa4", "0xa5"}, nil
+		},
+		GetCurrentBalanceFunc: func(ctx context.Context, address, sukukAddress string) (string, error) {
+			return "100", nil
+		},
+		GetClaimableYieldFunc: func(ctx context.Context, address, sukukAddress string) (string, error) {
+			return claimable[sukukAddress], nil
+		},
+	}
+	previous := database.DB
+	database.DB = openStubDB(t, func(string) stubResult { return stubResult{} })
+	defer func() { database.DB = previous }()
+	defer SetDeps(SetDeps(Deps{Portfolio: reader}))
+
+	response, err := buildYieldClaimsResponse(context.Background(), portfolioTestHolder)
+	if err != nil {
+		t.Fatalf("Failed to build yield claims: %v", err)
+	}
+	if response.TotalAmount != "20" {
+		t.Errorf("Expected a total of 20, leaving out the negative amount, got %s", response.TotalAmount)
+	}
+	if response.SkippedRows != 2 || response.TotalClaims != len(claimable) {
+		t.Errorf("Expected 2 skipped rows of %d claims, got %d of %d", len(claimable), response.SkippedRows, response.TotalClaims)
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const portfolioTestHolder = "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9"
+
+// stubResult is the columns and rows a stub query returns
+type stubResult struct {
+	columns []string
+	rows    [][]driver.Value
+	err     error // Returned instead of the rows, e.g. to simulate an outage
+}
+
+// stubDriver answers every query from respond, so handlers can run against gorm without Postgres
+type stubDriver struct {
+	respond func(query string) stubResult
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) { return &stubConn{driver: d}, nil }
+
+type stubConn struct{ driver *stubDriver }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+func (c *stubConn) Close() error              { return nil }
+func (c *stubConn) Begin() (driver.Tx, error) { return stubTx{}, nil }
+
+// stubTx lets handlers open transactions; the stub keeps no state, so there is nothing to undo
+type stubTx struct{}
+
+func (stubTx) Commit() error   { return nil }
+func (stubTx) Rollback() error { return nil }
+
+func (c *stubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	result := c.driver.respond(query)
+	if result.err != nil {
+		return nil, result.err
+	}
+	return &stubRows{result: result}, nil
+}
+
+// ExecContext lets handlers write; statements go through respond too, so tests can see them
+func (c *stubConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.driver.respond(query)
+	return driver.RowsAffected(1), nil
+}
+
+type stubRows struct {
+	result stubResult
+	next   int
+}
+
+func (r *stubRows) Columns() []string { return r.result.columns }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
+
+// stubDrivers numbers the registered stub drivers, as database/sql names must be unique
+var stubDrivers int64
+
+// openStubDB opens gorm on a stub driver answering every query from respond
+func openStubDB(t *testing.T, respond func(query string) stubResult) *gorm.DB {
+	t.Helper()
+	name := fmt.Sprintf("stub-%d", atomic.AddInt64(&stubDrivers, 1))
+	sql.Register(name, &stubDriver{respond: respond})
+	conn, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Failed to open stub database: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Failed to open gorm: %v", err)
+	}
+	return db
+}
+
+// portfolioIndexer answers the portfolio queries for a holder of count sukuk. Every sukuk had
+// 1000 distributed on a supply of 4000, of which the holder owns 100 and claimed 5
+func portfolioIndexer(count int) func(query string) stubResult {
+	sukuk := func(i int) string { return fmt.Sprintf("0x%040x", i+1) }
+	perSukuk := func(columns []string, row func(i int) []driver.Value) stubResult {
+		result := stubResult{columns: columns}
+		for i := 0; i < count; i++ {
+			result.rows = append(result.rows, row(i))
+		}
+		return result
+	}
+
+	return func(query string) stubResult {
+		switch {
+		case strings.Contains(query, "information_schema.tables"):
+			result := stubResult{columns: []string{"table_name", "table_schema"}}
+			for _, event := range []string{"holder_update", "yield_distributed", "yield_claim", "snapshot_taken", "redemption_request"} {
+				result.rows = append(result.rows, []driver.Value{"0a__" + event, "public"})
+			}
+			return result
+		case strings.Contains(query, "indexer_table_overrides"), strings.Contains(query, "payment_tokens"):
+			return stubResult{columns: []string{"id"}}
+		case strings.Contains(query, "sukuk_metadata"):
+			return perSukuk([]string{"id", "contract_address"}, func(i int) []driver.Value {
+				return []driver.Value{int64(i + 1), sukuk(i)}
+			})
+		case strings.Contains(query, "__holder_update"):
+			return perSukuk([]string{"id", "sukuk_address", "holder", "new_balance", "timestamp", "block_number", "tx_hash"}, func(i int) []driver.Value {
+				return []driver.Value{fmt.Sprintf("h%d", i), sukuk(i), portfolioTestHolder, "100", int64(1700000000), int64(10), "0xaa"}
+			})
+		case strings.Contains(query, "NOT EXISTS"):
+			return perSukuk([]string{"sukuk_address", "distribution_id"}, func(i int) []driver.Value {
+				return []driver.Value{sukuk(i), int64(2)}
+			})
+		case strings.Contains(query, "ROW_NUMBER"):
+			return perSukuk([]string{"id", "sukuk_address", "distribution_id", "payment_token", "amount", "timestamp", "block_number", "tx_hash"}, func(i int) []driver.Value {
+				return []driver.Value{fmt.Sprintf("d%d", i), sukuk(i), int64(2), "0xtoken", "600", int64(1700000100), int64(12), "0xbb"}
+			})
+		case strings.Contains(query, "__yield_distributed"):
+			return perSukuk([]string{"sukuk_address", "total"}, func(i int) []driver.Value { return []driver.Value{sukuk(i), "1000"} })
+		case strings.Contains(query, "__yield_claim"):
+			return perSukuk([]string{"sukuk_address", "total"}, func(i int) []driver.Value { return []driver.Value{sukuk(i), "5"} })
+		case strings.Contains(query, "__snapshot_taken"):
+			return perSukuk([]string{"sukuk_address", "total"}, func(i int) []driver.Value { return []driver.Value{sukuk(i), "4000"} })
+		}
+		return stubResult{}
+	}
+}
+
+// countPortfolioStatements builds the portfolio of a holder of count sukuk and returns how
+// many statements gorm executed
+func countPortfolioStatements(t *testing.T, count int) int64 {
+	t.Helper()
+	db := openStubDB(t, portfolioIndexer(count))
+
+	var statements int64
+	counter := func(*gorm.DB) { atomic.AddInt64(&statements, 1) }
+	db.Callback().Query().After("gorm:query").Register("test:count_query", counter)
+	db.Callback().Row().After("gorm:row").Register("test:count_row", counter)
+	db.Callback().Raw().After("gorm:raw").Register("test:count_raw", counter)
+
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	// Start from an empty supply cache so both counts include the supply lookups
+	defer services.SetDefaultSupplyService(services.SetDefaultSupplyService(services.NewIndexerSupplyService()))
+
+	response, err := buildPortfolioResponse(context.Background(), portfolioTestHolder)
+	if err != nil {
+		t.Fatalf("Failed to build portfolio of %d sukuk: %v", count, err)
+	}
+	if len(response.Holdings) != count {
+		t.Fatalf("Expected %d holdings, got %d", count, len(response.Holdings))
+	}
+	for _, holding := range response.Holdings {
+		if holding.Balance != "100" || holding.ClaimableYield != "20" || holding.TotalYieldClaimed != "5" || holding.SupplySource != models.SupplySourceSnapshot {
+			t.Errorf("Expected balance 100, claimable 20 and claimed 5 on a snapshot supply, got %+v", holding)
+		}
+		if len(holding.UnclaimedDistributions) != 1 || len(holding.YieldHistory) != 1 || holding.Metadata == nil {
+			t.Errorf("Expected unclaimed distributions, yield history and metadata, got %+v", holding)
+		}
+	}
+	return statements
+}
+
+func TestPortfolioQueryCountIsIndependentOfHoldings(t *testing.T) {
+	single := countPortfolioStatements(t, 1)
+	many := countPortfolioStatements(t, 20)
+	if single == 0 {
+		t.Fatal("Expected the statement counter to see the portfolio queries")
+	}
+	if single != many {
+		t.Errorf("Expected the same number of statements for 1 and 20 holdings, got %d and %d", single, many)
+	}
+}
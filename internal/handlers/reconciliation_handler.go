@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const reconciliationEntity = "reconciliation"
+
+// GetReconciliationReport compares the stored events of a sukuk with the indexer
+// @Summary Reconcile stored events with the indexer
+// @Description For one sukuk, compare counts and summed amounts of purchases and redemption requests stored locally with the indexer, listing events missing on either side and amount mismatches (matched on tx_hash + log_index, at most 500 entries per list with a total count). fix=missing_investments first backfills purchases missing locally, in a transaction
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sukuk_address path string true "Sukuk contract address"
+// @Param fix query string false "Backfill straightforward gaps before reconciling" Enums(missing_investments)
+// @Success 200 {object} models.ReconciliationReport "Reconciliation report"
+// @Failure 400 {object} map[string]string "Invalid address or fix"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/reconciliation/{sukuk_address} [get]
+func GetReconciliationReport(c *gin.Context) {
+	sukukAddress := c.Param("sukuk_address")
+	if !utils.IsValidEthereumAddress(sukukAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid sukuk address",
+		})
+		return
+	}
+
+	fix := c.Query("fix")
+	if fix != "" && fix != models.ReconciliationFixMissingInvestments {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported fix",
+			"details": "fix must be " + models.ReconciliationFixMissingInvestments,
+		})
+		return
+	}
+
+	service := services.NewReconciliationService(database.GetDB())
+
+	var applied *models.ReconciliationFix
+	if fix != "" {
+		applied = &models.ReconciliationFix{Action: fix}
+		err := database.GetDB().WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+			inserted, err := service.BackfillMissingPurchases(tx, sukukAddress)
+			if err != nil {
+				return err
+			}
+			applied.Inserted = inserted
+			return models.RecordAudit(tx, models.AuditActionUpdate, reconciliationEntity, utils.NormalizeAddress(sukukAddress), auditActor(c), applied)
+		})
+		if err != nil {
+			logger.WithError(err).Error("Failed to backfill missing purchases")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to backfill missing purchases",
+			})
+			return
+		}
+	}
+
+	report, err := service.Reconcile(c.Request.Context(), sukukAddress)
+	if err != nil {
+		logger.WithError(err).Error("Failed to reconcile sukuk events")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to reconcile sukuk events",
+		})
+		return
+	}
+	report.Fix = applied
+
+	respondJSON(c, http.StatusOK, report)
+}
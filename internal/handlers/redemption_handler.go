@@ -1,26 +1,32 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
+	"sukuk-be/internal/cache"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllRedemptions returns all redemption requests with their approval status
 // @Summary Get all redemptions
-// @Description Get all redemption requests with their approval status, supports pagination
+// @Description Get redemption requests with their approval status, supports pagination. The status filter and sort apply before limit/offset, and total_count counts every matching redemption
 // @Tags redemptions
 // @Accept json
 // @Produce json
 // @Param limit query int false "Number of redemptions to return" default(50) minimum(1) maximum(200)
 // @Param offset query int false "Number of redemptions to skip" default(0) minimum(0)
 // @Param status query string false "Filter by status" Enums(requested, approved, rejected, completed)
+// @Param sort query string false "Sort field" Enums(created_at, amount) default(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc) default(desc)
 // @Success 200 {object} models.RedemptionListResponse "List of redemptions with status"
+// @Failure 400 {object} map[string]string "Invalid sort"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /redemptions [get]
 func GetAllRedemptions(c *gin.Context) {
@@ -40,33 +46,90 @@ func GetAllRedemptions(c *gin.Context) {
 		offset = 0
 	}
 
-	// Initialize redemption service
-	redemptionService := services.NewRedemptionService()
+	sort, ascending, err := services.ParseRedemptionSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
-	// Get all redemptions
-	redemptions, err := redemptionService.GetAllRedemptions(limit, offset)
+	redemptionService := currentDeps().Redemptions
+
+	// Get the page of redemptions matching the status filter
+	redemptions, err := redemptionService.GetAllRedemptions(c.Request.Context(), services.RedemptionListFilter{
+		Status:    models.RedemptionStatus(c.Query("status")),
+		Sort:      sort,
+		Ascending: ascending,
+		Limit:     limit,
+		Offset:    offset,
+	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to get all redemptions")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get redemptions",
 		})
 		return
 	}
 
-	// Filter by status if specified
-	status := c.Query("status")
-	if status != "" {
-		filteredRedemptions := []models.RedemptionRequest{}
-		for _, r := range redemptions.Redemptions {
-			if string(r.Status) == status {
-				filteredRedemptions = append(filteredRedemptions, r)
+	respondJSON(c, http.StatusOK, redemptions)
+}
+
+// RedemptionLister reads one page of redemptions, e.g. services.ListRedemptions
+type RedemptionLister func(ctx context.Context, filter services.RedemptionListFilter) (*services.RedemptionPage, error)
+
+// redemptionStatuses are the values accepted by ?status=
+var redemptionStatuses = map[models.RedemptionStatus]bool{
+	models.RedemptionStatusRequested: true,
+	models.RedemptionStatusApproved:  true,
+	models.RedemptionStatusRejected:  true,
+	models.RedemptionStatusCompleted: true,
+}
+
+// ListRedemptionsV2 serves GET /api/v2/redemptions: redemption requests in the paginated
+// envelope, filtered by status, investor_address and sukuk_address and sorted by sort/order.
+// Filters and the page window run in the query, so meta.total counts every match and a page
+// past the end has an empty data array
+func ListRedemptionsV2(list RedemptionLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, perPage, err := parsePageQuery(c)
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid pagination", err.Error())
+			return
+		}
+
+		status := models.RedemptionStatus(c.Query("status"))
+		if status != "" && !redemptionStatuses[status] {
+			SendError(c, http.StatusBadRequest, "Invalid status", "status must be requested, approved, rejected or completed")
+			return
+		}
+
+		filter := services.RedemptionListFilter{Status: status, Limit: perPage, Offset: (page - 1) * perPage}
+		for param, target := range map[string]*string{"investor_address": &filter.User, "sukuk_address": &filter.SukukAddress} {
+			if address := c.Query(param); address != "" {
+				if !utils.IsValidEthereumAddress(address) {
+					SendError(c, http.StatusBadRequest, "Invalid "+param, "must be a 0x-prefixed 40 hex character address")
+					return
+				}
+				*target = utils.NormalizeAddress(address)
 			}
 		}
-		redemptions.Redemptions = filteredRedemptions
-		redemptions.TotalCount = len(filteredRedemptions)
-	}
 
-	c.JSON(http.StatusOK, redemptions)
+		filter.Sort, filter.Ascending, err = services.ParseRedemptionSort(c.Query("sort"), c.Query("order"))
+		if err != nil {
+			SendError(c, http.StatusBadRequest, "Invalid sort", err.Error())
+			return
+		}
+
+		result, err := list(c.Request.Context(), filter)
+		if err != nil {
+			logger.WithError(err).Error("Failed to list redemptions")
+			SendError(c, queryErrorStatus(c, err), "Failed to list redemptions", "")
+			return
+		}
+
+		SendPaginatedResponse(c, result.Redemptions, newPagination(int(result.Total), len(result.Redemptions), page, perPage))
+	}
 }
 
 // GetRedemptionsByUser returns redemptions for a specific user
@@ -89,20 +152,19 @@ func GetRedemptionsByUser(c *gin.Context) {
 		return
 	}
 
-	// Initialize redemption service
-	redemptionService := services.NewRedemptionService()
+	redemptionService := currentDeps().Redemptions
 
 	// Get user's redemptions
-	redemptions, err := redemptionService.GetRedemptionsByUser(address)
+	redemptions, err := redemptionService.GetRedemptionsByUser(c.Request.Context(), address)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get user redemptions")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get user redemptions",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, redemptions)
+	respondJSON(c, http.StatusOK, redemptions)
 }
 
 // GetRedemptionsBySukuk returns redemptions for a specific sukuk
@@ -125,20 +187,19 @@ func GetRedemptionsBySukuk(c *gin.Context) {
 		return
 	}
 
-	// Initialize redemption service
-	redemptionService := services.NewRedemptionService()
+	redemptionService := currentDeps().Redemptions
 
 	// Get sukuk's redemptions
-	redemptions, err := redemptionService.GetRedemptionsBySukuk(sukukAddress)
+	redemptions, err := redemptionService.GetRedemptionsBySukuk(c.Request.Context(), sukukAddress)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get sukuk redemptions")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get sukuk redemptions",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, redemptions)
+	respondJSON(c, http.StatusOK, redemptions)
 }
 
 // GetRedemptionStats returns overall redemption statistics
@@ -147,24 +208,55 @@ func GetRedemptionsBySukuk(c *gin.Context) {
 // @Tags redemptions
 // @Accept json
 // @Produce json
+// @Param fiat query string false "Comma-separated currencies for display_values, e.g. idr,usd; amounts are priced in each sukuk's payment token and the rates used are listed in meta" Example(idr,usd)
 // @Success 200 {object} models.RedemptionStatsResponse "Redemption statistics"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /redemptions/stats [get]
 func GetRedemptionStats(c *gin.Context) {
-	// Initialize redemption service
-	redemptionService := services.NewRedemptionService()
+	redemptionService := currentDeps().Redemptions
 
 	// Get redemption statistics
-	stats, err := redemptionService.GetRedemptionStats()
+	stats, hit, err := cache.Fetch(c.Request.Context(), cache.RedemptionStatsKey(), cacheTTL(services.SettingCacheStatsTTL, cache.StatsTTL), func() (*models.RedemptionStatsResponse, error) {
+		return redemptionService.GetRedemptionStats(c.Request.Context())
+	})
+	setCacheStatus(c, hit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get redemption stats")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get redemption statistics",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	// Fiat display values follow the request and the current rates, so they are added after caching
+	if display := newFiatDisplay(c); display != nil {
+		addRedemptionStatsDisplayValues(stats, display)
+	}
+
+	respondJSON(c, http.StatusOK, stats)
+}
+
+// addRedemptionStatsDisplayValues prices each sukuk's redemptions in its payment token and the
+// totals as their sum
+func addRedemptionStatsDisplayValues(stats *models.RedemptionStatsResponse, display *fiatDisplay) {
+	var requested, approved []models.FormattedAmount
+	for address, stat := range stats.BySukuk {
+		sukukRequested := formattedOrRaw(stat.RequestedAmountFormatted, stat.RequestedAmount)
+		sukukApproved := formattedOrRaw(stat.ApprovedAmountFormatted, stat.ApprovedAmount)
+		stat.DisplayValues = display.fields(map[string][]models.FormattedAmount{
+			"requested_amount": {sukukRequested},
+			"approved_amount":  {sukukApproved},
+		})
+		stats.BySukuk[address] = stat
+		requested = append(requested, sukukRequested)
+		approved = append(approved, sukukApproved)
+	}
+
+	stats.DisplayValues = display.fields(map[string][]models.FormattedAmount{
+		"total_requested_amount": requested,
+		"total_approved_amount":  approved,
+	})
+	stats.Meta = display.meta()
 }
 
 
@@ -189,16 +281,15 @@ func GetRedemptionByID(c *gin.Context) {
 		return
 	}
 
-	// Initialize redemption service
-	redemptionService := services.NewRedemptionService()
+	redemptionService := currentDeps().Redemptions
 
 	// Get all redemptions and find the specific one
 	// Note: This is not the most efficient, but works for MVP
 	// In production, you'd want a direct lookup method
-	allRedemptions, err := redemptionService.GetAllRedemptions(1000, 0)
+	allRedemptions, err := redemptionService.GetAllRedemptions(c.Request.Context(), services.RedemptionListFilter{Limit: 1000})
 	if err != nil {
 		logger.WithError(err).Error("Failed to get redemptions")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to get redemption",
 		})
 		return
@@ -207,7 +298,7 @@ func GetRedemptionByID(c *gin.Context) {
 	// Find the specific redemption
 	for _, r := range allRedemptions.Redemptions {
 		if r.RequestID == requestID {
-			c.JSON(http.StatusOK, r)
+			respondJSON(c, http.StatusOK, r)
 			return
 		}
 	}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/mocks"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeRedemptionLister pages through a fixed number of redemptions and records the filter
+type fakeRedemptionLister struct {
+	total  int
+	filter services.RedemptionListFilter
+	calls  int
+}
+
+func (f *fakeRedemptionLister) list(ctx context.Context, filter services.RedemptionListFilter) (*services.RedemptionPage, error) {
+	f.calls++
+	f.filter = filter
+	page := &services.RedemptionPage{Redemptions: []models.RedemptionRequest{}, Total: int64(f.total)}
+	for i := filter.Offset; i < f.total && i < filter.Offset+filter.Limit; i++ {
+		page.Redemptions = append(page.Redemptions, models.RedemptionRequest{Amount: "1"})
+	}
+	return page, nil
+}
+
+func serveRedemptionsV2(t *testing.T, lister *fakeRedemptionLister, target string) (int, PaginatedResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v2/redemptions", ListRedemptionsV2(lister.list))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var response PaginatedResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+	return w.Code, response
+}
+
+func TestListRedemptionsV2Pages(t *testing.T) {
+	lister := &fakeRedemptionLister{total: 45}
+
+	code, response := serveRedemptionsV2(t, lister, "/api/v2/redemptions?page=2&per_page=20")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if lister.filter.Limit != 20 || lister.filter.Offset != 20 {
+		t.Errorf("Expected limit 20 offset 20, got %+v", lister.filter)
+	}
+	meta := response.Meta
+	if meta.Total != 45 || meta.Count != 20 || meta.TotalPages != 3 || !meta.HasNext || !meta.HasPrevious {
+		t.Errorf("Unexpected meta for page 2: %+v", meta)
+	}
+
+	code, response = serveRedemptionsV2(t, lister, "/api/v2/redemptions?page=4&per_page=20")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200 past the last page, got %d", code)
+	}
+	if data, ok := response.Data.([]interface{}); !ok || len(data) != 0 {
+		t.Errorf("Expected an empty data array past the last page, got %#v", response.Data)
+	}
+	if meta := response.Meta; meta.Total != 45 || meta.Count != 0 || meta.HasNext || !meta.HasPrevious {
+		t.Errorf("Unexpected meta past the last page: %+v", meta)
+	}
+}
+
+func TestListRedemptionsV2PassesFilters(t *testing.T) {
+	lister := &fakeRedemptionLister{total: 3}
+
+	const investor = "0x00000000000000000000000000000000000000AB"
+	code, _ := serveRedemptionsV2(t, lister, "/api/v2/redemptions?status=approved&investor_address="+investor+"&sort=amount&order=asc")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	filter := lister.filter
+	if filter.Status != models.RedemptionStatusApproved || filter.User != "0x00000000000000000000000000000000000000ab" ||
+		filter.Sort != "amount" || !filter.Ascending {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+}
+
+func TestListRedemptionsV2RejectsInvalidQuery(t *testing.T) {
+	lister := &fakeRedemptionLister{total: 3}
+
+	for _, query := range []string{
+		"page=0",
+		"per_page=500",
+		"status=pending",
+		"investor_address=alice",
+		"sukuk_address=0x123",
+		"sort=user",
+		"order=sideways",
+	} {
+		if code, _ := serveRedemptionsV2(t, lister, "/api/v2/redemptions?"+query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, code)
+		}
+	}
+	if lister.calls != 0 {
+		t.Errorf("Expected invalid queries not to reach the lister, got %d calls", lister.calls)
+	}
+}
+
+// serveRedemptions runs one request against the v1 redemption routes, reading through reader
+func serveRedemptions(t *testing.T, reader *mocks.RedemptionReader, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	defer SetDeps(SetDeps(Deps{Redemptions: reader}))
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/redemptions", GetAllRedemptions)
+	router.GET("/redemptions/stats", GetRedemptionStats)
+	router.GET("/redemptions/user/:address", GetRedemptionsByUser)
+	router.GET("/redemptions/:request_id", GetRedemptionByID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestGetAllRedemptionsPassesFilter(t *testing.T) {
+	var got services.RedemptionListFilter
+	reader := &mocks.RedemptionReader{
+		GetAllRedemptionsFunc: func(ctx context.Context, filter services.RedemptionListFilter) (*models.RedemptionListResponse, error) {
+			got = filter
+			return &models.RedemptionListResponse{TotalCount: 1, Redemptions: []models.RedemptionRequest{{RequestID: "r1"}}}, nil
+		},
+	}
+
+	w := serveRedemptions(t, reader, "/redemptions?status=approved&limit=500&offset=-3&sort=amount&order=asc")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.Status != models.RedemptionStatusApproved || got.Limit != 200 || got.Offset != 0 || got.Sort != "amount" || !got.Ascending {
+		t.Errorf("Unexpected filter: %+v", got)
+	}
+
+	reader.GetAllRedemptionsFunc = nil
+	if w := serveRedemptions(t, reader, "/redemptions?sort=user"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an invalid sort to be rejected before reading, got %d", w.Code)
+	}
+}
+
+func TestGetRedemptionByIDFromReader(t *testing.T) {
+	reader := &mocks.RedemptionReader{
+		GetAllRedemptionsFunc: func(context.Context, services.RedemptionListFilter) (*models.RedemptionListResponse, error) {
+			return &models.RedemptionListResponse{Redemptions: []models.RedemptionRequest{{RequestID: "r1"}, {RequestID: "r2", Amount: "5"}}}, nil
+		},
+	}
+
+	w := serveRedemptions(t, reader, "/redemptions/r2")
+	var redemption models.RedemptionRequest
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &redemption) != nil || redemption.Amount != "5" {
+		t.Errorf("Expected redemption r2, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serveRedemptions(t, reader, "/redemptions/r3"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown request, got %d", w.Code)
+	}
+}
+
+func TestRedemptionHandlersReportReaderErrors(t *testing.T) {
+	// Every method is left unstubbed, so each read fails
+	reader := &mocks.RedemptionReader{}
+	for _, target := range []string{"/redemptions", "/redemptions/stats", "/redemptions/user/0xabc", "/redemptions/r1"} {
+		if w := serveRedemptions(t, reader, target); w.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected status 500, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestGetRedemptionStatsIsCached(t *testing.T) {
+	calls := 0
+	reader := &mocks.RedemptionReader{
+		GetRedemptionStatsFunc: func(context.Context) (*models.RedemptionStatsResponse, error) {
+			calls++
+			return &models.RedemptionStatsResponse{TotalRequests: 3, BySukuk: map[string]models.RedemptionSukukStats{}}, nil
+		},
+	}
+	defer SetDeps(SetDeps(Deps{Redemptions: reader}))
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/redemptions/stats", GetRedemptionStats)
+	for _, want := range []string{"miss", "hit"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redemptions/stats", nil))
+		if w.Code != http.StatusOK || w.Header().Get("Cache-Status") != want {
+			t.Errorf("Expected 200 with cache %s, got %d and %q", want, w.Code, w.Header().Get("Cache-Status"))
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the stats to be read once, got %d reads", calls)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const referralEntity = "referral"
+
+// CreateReferral creates a referral code
+// @Summary Create referral code
+// @Description Create a marketing referral code owned by an address. Codes are 3-32 letters, digits, '-' or '_' and match case-insensitively (they are stored uppercased).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param referral body models.ReferralCreateRequest true "Referral code"
+// @Success 201 {object} models.Referral "Created referral"
+// @Failure 400 {object} map[string]string "Invalid code or owner address"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Referral code already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/referrals [post]
+func CreateReferral(c *gin.Context) {
+	var req models.ReferralCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	code, err := models.NormalizeReferralCode(req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid referral code",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !utils.IsValidEthereumAddress(req.OwnerAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid owner address",
+		})
+		return
+	}
+
+	referral := models.Referral{
+		Code:         code,
+		OwnerAddress: req.OwnerAddress,
+	}
+	err = database.GetDB().WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := models.CreateReferral(tx, &referral); err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionCreate, referralEntity, referral.Code, auditActor(c), req)
+	})
+	if errors.Is(err, models.ErrReferralCodeTaken) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Referral code already exists",
+		})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to create referral")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to create referral",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, referral)
+}
+
+// ClaimReferral binds a referral code to the signed-in wallet
+// @Summary Claim referral code
+// @Description Bind a referral code to the wallet of the session token before purchasing. A wallet can bind one code, once; purchases indexed from the moment of binding are attributed to it, earlier ones never are.
+// @Tags referrals
+// @Accept json
+// @Produce json
+// @Security WalletAuth
+// @Param claim body models.ReferralClaimRequest true "Code, and optionally the wallet, which must be the signed-in one"
+// @Success 201 {object} models.ReferralBinding "Binding"
+// @Failure 400 {object} map[string]string "Invalid code"
+// @Failure 401 {object} map[string]string "Wallet token required"
+// @Failure 403 {object} map[string]string "Address is not the signed-in wallet"
+// @Failure 404 {object} map[string]string "Referral code not found"
+// @Failure 409 {object} map[string]string "Address already has a referral code"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /referrals/claim [post]
+func ClaimReferral(c *gin.Context) {
+	var req models.ReferralClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	code, err := models.NormalizeReferralCode(req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid referral code",
+			"details": err.Error(),
+		})
+		return
+	}
+	// The wallet comes from the session, so nobody can bind another wallet to their own code
+	address := middleware.WalletAddress(c)
+	if req.Address != "" && !strings.EqualFold(req.Address, address) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Address is not the signed-in wallet",
+		})
+		return
+	}
+
+	referral, ok := findReferral(c, code)
+	if !ok {
+		return
+	}
+
+	binding, err := models.BindReferral(database.GetDB().WithContext(c.Request.Context()), referral, address, time.Now())
+	if errors.Is(err, models.ErrReferralAlreadyBound) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Address already has a referral code",
+		})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to bind referral")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to claim referral code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, binding)
+}
+
+// GetReferralStats returns the purchases attributed to a referral code
+// @Summary Get referral stats
+// @Description Count the wallets bound to a referral code and the purchases attributed to it, with their summed raw amount. Purchases are attributed by the metadata sync, so recent ones appear after its next cycle.
+// @Tags referrals
+// @Produce json
+// @Param code path string true "Referral code"
+// @Success 200 {object} models.ReferralStatsResponse "Referral stats"
+// @Failure 400 {object} map[string]string "Invalid referral code"
+// @Failure 404 {object} map[string]string "Referral code not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /referrals/{code}/stats [get]
+func GetReferralStats(c *gin.Context) {
+	code, err := models.NormalizeReferralCode(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid referral code",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	referral, ok := findReferral(c, code)
+	if !ok {
+		return
+	}
+
+	stats, err := models.GetReferralStats(database.GetDB().WithContext(c.Request.Context()), referral)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get referral stats")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to get referral stats",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, stats)
+}
+
+// findReferral loads a referral by normalized code, responding 404 when it does not exist
+func findReferral(c *gin.Context, code string) (*models.Referral, bool) {
+	referral, err := models.GetReferralByCode(database.GetDB().WithContext(c.Request.Context()), code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Referral code not found",
+		})
+		return nil, false
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch referral")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch referral",
+		})
+		return nil, false
+	}
+	return referral, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReferralHandlersRejectInvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const address = "0x1234567890123456789012345678901234567890"
+	router := gin.New()
+	router.POST("/admin/referrals", CreateReferral)
+	router.POST("/referrals/claim", func(c *gin.Context) { c.Set(middleware.WalletAddressContextKey, address) }, ClaimReferral)
+	router.GET("/referrals/:code/stats", GetReferralStats)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"create with short code", http.MethodPost, "/admin/referrals", `{"code":"ab","owner_address":"` + address + `"}`},
+		{"create with bad owner", http.MethodPost, "/admin/referrals", `{"code":"LAUNCH","owner_address":"0x123"}`},
+		{"claim with bad code", http.MethodPost, "/referrals/claim", `{"code":"no spaces","address":"` + address + `"}`},
+		{"claim without body", http.MethodPost, "/referrals/claim", ``},
+		{"stats with bad code", http.MethodGet, "/referrals/a!/stats", ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestClaimReferralOnlyBindsTheSignedInWallet(t *testing.T) {
+	const wallet = "0x1234567890123456789012345678901234567890"
+	const other = "0x00000000000000000000000000000000000000ee"
+
+	var queries []string
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		queries = append(queries, query)
+		return stubResult{}
+	})
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/referrals/claim", func(c *gin.Context) { c.Set(middleware.WalletAddressContextKey, wallet) }, ClaimReferral)
+
+	req := httptest.NewRequest(http.MethodPost, "/referrals/claim", strings.NewReader(`{"code":"LAUNCH","address":"`+other+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected a claim for another wallet to be refused with 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(queries) != 0 {
+		t.Errorf("Expected no queries for a refused claim, got %v", queries)
+	}
+}
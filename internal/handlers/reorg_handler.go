@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListReorgIncidents returns the chain reorgs detected against the indexer
+// @Summary List reorg incidents
+// @Description Get the chain reorgs the reorg reconciler detected, most recent first. An incident lists the transactions of one indexer event type that moved to its _reorg table and left the canonical one, and the derived rows (stored events and ledger entries) built from them, which were marked orphaned instead of deleted. Orphaned rows are left out of ledger balances and reconciliation, and are confirmed again if their transaction returns to the canonical table
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Number of incidents to return" default(50) minimum(1) maximum(200)
+// @Param offset query int false "Number of incidents to skip" default(0) minimum(0)
+// @Success 200 {object} models.ReorgIncidentListResponse "Reorg incidents"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/reorgs [get]
+func ListReorgIncidents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	incidents, total, err := models.ListReorgIncidents(database.GetDB().WithContext(c.Request.Context()), limit, offset)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch reorg incidents")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch reorg incidents",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.ReorgIncidentListResponse{Incidents: incidents, TotalCount: total})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden response files")
+
+// assertGolden compares a JSON body with testdata/golden/<name>.json, key order included
+func assertGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		t.Fatalf("Invalid JSON body: %v", err)
+	}
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *updateGolden {
+		if err := os.WriteFile(path, indented.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(indented.Bytes(), want) {
+		t.Errorf("Response for %s changed:\n got: %s\nwant: %s", name, indented.Bytes(), want)
+	}
+}
+
+func marshalGolden(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestForceSyncResponsesMatchGolden(t *testing.T) {
+	syncer := &mockSyncer{
+		pending: 3,
+		result:  &services.SyncResult{Processed: 2, Failed: 1, LastProcessedID: "0xabc-1"},
+	}
+	w := httptest.NewRecorder()
+	newSyncRouter(syncer, 10).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/force-sync", nil))
+	assertGolden(t, "force_sync_completed", w.Body.Bytes())
+
+	// The job ID is generated, so the background response is pinned through its type
+	assertGolden(t, "force_sync_started", marshalGolden(t, SyncJobStartedResponse{
+		Message: "Sync started in background",
+		JobID:   "sync-1",
+		Pending: 50,
+	}))
+}
+
+func TestResponseTypesMatchGolden(t *testing.T) {
+	updated := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	const sukuk, token = "0x00000000000000000000000000000000000000b1", "0x00000000000000000000000000000000000000c1"
+
+	tests := []struct {
+		name     string
+		response interface{}
+	}{
+		{"sync_status", SyncStatusResponse{Data: SyncStatus{LastProcessedEventID: "42", SyncStatus: "active", LastUpdated: updated}}},
+		{"health_healthy", HealthResponse{Status: "healthy", Data: &HealthData{Database: "connected", SukukMetadata: 3}}},
+		{"health_unhealthy", HealthResponse{Status: "unhealthy", Error: "Database ping failed"}},
+		{"sukuk_metadata_sync", SukukMetadataSyncResponse{Message: "Sukuk metadata sync completed successfully", TokenID: 1, ContractAddress: sukuk}},
+		{"sukuk_creation_tables", SukukCreationTablesResponse{Tables: []string{"abcd__sukuk_creation"}, LatestTable: "abcd__sukuk_creation", TotalCount: 1}},
+		{"message", MessageResponse{Message: "Payment token deleted"}},
+		{"yield_distributions", models.YieldDistributionsResponse{
+			SukukAddress: sukuk,
+			TotalCount:   1,
+			Distributions: []models.YieldDistribution{{
+				ID:             "0xd1-0",
+				SukukAddress:   sukuk,
+				DistributionId: 1,
+				PaymentToken:   token,
+				Amount:         "150",
+				AmountFormatted: &models.FormattedAmount{
+					Amount: "150", Formatted: "1.5", Symbol: "IDRX", Decimals: 2, TokenAddress: token,
+				},
+				Timestamp:   updated,
+				TxHash:      "0xd1",
+				BlockNumber: 100,
+			}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, marshalGolden(t, tt.response))
+		})
+	}
+}
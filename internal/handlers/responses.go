@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // Standard API Response Models
@@ -84,6 +92,69 @@ type SystemResponse struct {
 	Data    interface{} `json:"data"`
 }
 
+// Fields of the types below are ordered alphabetically by JSON name: they replaced gin.H
+// maps, which encode keys in that order, and the golden tests pin the bytes
+
+// MessageResponse is a bare confirmation, e.g. after a delete
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// SyncStatusResponse represents the blockchain sync status
+type SyncStatusResponse struct {
+	Data SyncStatus `json:"data"`
+}
+
+// SyncStatus is the last indexer event processed by the sync
+type SyncStatus struct {
+	LastProcessedEventID string    `json:"last_processed_event_id"`
+	LastUpdated          time.Time `json:"last_updated"`
+	SyncStatus           string    `json:"sync_status"`
+}
+
+// ForceSyncResponse summarizes a sync cycle run by force-sync
+type ForceSyncResponse struct {
+	Failed          int    `json:"failed"`
+	LastProcessedID string `json:"last_processed_id"`
+	Message         string `json:"message"`
+	Processed       int    `json:"processed"`
+	Skipped         int    `json:"skipped"`
+}
+
+// SyncJobStartedResponse points to the background job started by force-sync
+type SyncJobStartedResponse struct {
+	JobID   string `json:"job_id"`
+	Message string `json:"message"`
+	Pending int64  `json:"pending"`
+}
+
+// HealthResponse represents the overall system health; Error is set when unhealthy
+type HealthResponse struct {
+	Data   *HealthData `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+	Status string      `json:"status"` // "healthy" or "unhealthy"
+}
+
+// HealthData holds the checks behind a healthy status
+type HealthData struct {
+	Database      string `json:"database"`
+	SukukMetadata int64  `json:"sukuk_metadata"` // Number of sukuk metadata records
+}
+
+// SukukMetadataSyncResponse confirms a manual sync of one sukuk
+type SukukMetadataSyncResponse struct {
+	ContractAddress string `json:"contract_address"`
+	Message         string `json:"message"`
+	TokenID         int64  `json:"token_id"`
+}
+
+// SukukCreationTablesResponse lists the indexer tables holding sukuk creation events
+type SukukCreationTablesResponse struct {
+	LatestTable string   `json:"latest_table"`
+	Tables      []string `json:"tables"`
+	TotalCount  int      `json:"total_count"`
+}
+
 // UploadResponse represents a file upload response
 type UploadResponse struct {
 	Success  bool   `json:"success"`
@@ -101,7 +172,7 @@ func SendSuccess(c *gin.Context, code int, data interface{}, message string) {
 		Message: message,
 		Data:    data,
 	}
-	c.JSON(code, response)
+	respondJSON(c, code, response)
 }
 
 // SendError sends an error response
@@ -124,7 +195,7 @@ func SendPaginatedResponse(c *gin.Context, data interface{}, pagination *Paginat
 		Data:    data,
 		Meta:    pagination,
 	}
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // Common Error Handlers
@@ -167,4 +238,49 @@ func Forbidden(c *gin.Context, message string) {
 // ValidationError sends a 422 error for validation failures
 func ValidationError(c *gin.Context, details string) {
 	SendError(c, http.StatusUnprocessableEntity, "Validation failed", details)
-}
\ No newline at end of file
+}
+// StatusClientClosedRequest is the non-standard status for requests abandoned by the client
+const StatusClientClosedRequest = 499
+
+// pgQueryCanceled is the Postgres error code raised when statement_timeout fires
+const pgQueryCanceled = "57014"
+
+// queryErrorStatus maps a failed database query to a response status: 499 when the
+// client disconnected, 503 when the query timed out or the indexer circuit breaker is
+// open (with Retry-After), 500 otherwise
+func queryErrorStatus(c *gin.Context, err error) int {
+	if errors.Is(c.Request.Context().Err(), context.Canceled) || errors.Is(err, context.Canceled) {
+		return StatusClientClosedRequest
+	}
+
+	var unavailable *services.IndexerUnavailableError
+	if errors.As(err, &unavailable) {
+		seconds := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		return http.StatusServiceUnavailable
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceled) {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// cacheTTL returns how long to cache a response: the runtime setting if set, else the configured TTL
+func cacheTTL(setting string, configured time.Duration) time.Duration {
+	return services.Settings().GetDuration(setting, configured)
+}
+
+// setCacheStatus reports whether the response was served from cache
+func setCacheStatus(c *gin.Context, hit bool) {
+	if hit {
+		c.Header("Cache-Status", "hit")
+	} else {
+		c.Header("Cache-Status", "miss")
+	}
+}
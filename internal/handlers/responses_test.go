@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestQueryErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		cancelled bool
+		err       error
+		want      int
+	}{
+		{"client disconnected", true, errors.New("conn closed"), StatusClientClosedRequest},
+		{"wrapped cancellation", false, fmt.Errorf("query failed: %w", context.Canceled), StatusClientClosedRequest},
+		{"context deadline", false, fmt.Errorf("query failed: %w", context.DeadlineExceeded), http.StatusServiceUnavailable},
+		{"statement timeout", false, &pgconn.PgError{Code: pgQueryCanceled}, http.StatusServiceUnavailable},
+		{"other error", false, errors.New("relation does not exist"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+			if got := queryErrorStatus(c, tt.err); got != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryErrorStatusIndexerUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := fmt.Errorf("failed to query: %w", &services.IndexerUnavailableError{RetryAfter: 12300 * time.Millisecond})
+	if got := queryErrorStatus(c, err); got != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "13" {
+		t.Errorf("Expected Retry-After 13, got %q", got)
+	}
+}
+
+// TestCancelledRequestStopsQuery requires a reachable Postgres, see testutil.Open
+func TestCancelledRequestStopsQuery(t *testing.T) {
+	db := testutil.Open(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/slow", func(c *gin.Context) {
+		if err := db.WithContext(c.Request.Context()).Exec("SELECT pg_sleep(10)").Error; err != nil {
+			c.JSON(queryErrorStatus(c, err), gin.H{"error": "Query failed"})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	router.ServeHTTP(w, req)
+	elapsed := time.Since(start)
+
+	if elapsed > 3*time.Second {
+		t.Errorf("Expected handler to return promptly after cancellation, took %s", elapsed)
+	}
+	if w.Code != StatusClientClosedRequest {
+		t.Errorf("Expected status %d, got %d", StatusClientClosedRequest, w.Code)
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 // GetRiwayatByAddress returns transaction history for a specific address
 // @Summary Get transaction history by address
-// @Description Get all blockchain activities (purchases and redemptions) for a specific user address
+// @Description Get all blockchain activities (purchases, redemptions and transfers between wallets) for a specific user address
 // @Tags transaction-history
 // @Accept json
 // @Produce json
@@ -47,10 +47,10 @@ func GetRiwayatByAddress(c *gin.Context) {
 	indexerService := services.NewIndexerQueryService()
 
 	// Get all activities for this address
-	activities, err := indexerService.GetActivitiesByAddress(address, limit)
+	activities, err := indexerService.GetActivitiesByAddress(c.Request.Context(), address, limit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch user activities")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to fetch transaction history",
 		})
 		return
@@ -63,7 +63,7 @@ func GetRiwayatByAddress(c *gin.Context) {
 		Activities:     activities,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // RiwayatResponse represents the response for transaction history
@@ -0,0 +1,36 @@
+package handlers
+
+//go:generate go run gen_endpoints.go
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo describes an entry of the server's route table
+type RouteInfo struct {
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	Handler        string `json:"handler"`          // Handler function, e.g. handlers.ListSettings
+	Auth           string `json:"auth"`             // "public", "optional", "admin" or "webhook"
+	RateLimit      string `json:"rate_limit"`       // "api" or "none"
+	ReadOnlyExempt bool   `json:"read_only_exempt"` // Mutations are still served under api.read_only
+	Enabled        bool   `json:"enabled"`          // Mounted; optional features leave theirs off
+}
+
+// ListRoutes dumps the route table with the auth, rate limit and read-only policy of each
+// route, for access audits
+// @Summary List API routes
+// @Description Every route in the server's route table with its handler, auth level (public, optional API key, admin API key or webhook signature), rate limit class and whether it is exempt from api.read_only. Routes of optional features that are off are listed with enabled false
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} RouteInfo "Route table"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/routes [get]
+func ListRoutes(routes func() []RouteInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondJSON(c, http.StatusOK, routes())
+	}
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"sukuk-be/internal/handlers/endpoints"
+)
+
+// The server checks its route table against Endpoints, so the list must follow the source
+func TestEndpointsListIsCurrent(t *testing.T) {
+	names, err := endpoints.Scan(".")
+	if err != nil {
+		t.Fatalf("Failed to scan handlers: %v", err)
+	}
+	if fmt.Sprint(names) != fmt.Sprint(Endpoints) {
+		t.Errorf("endpoints_gen.go is stale, run go generate ./internal/handlers\nsource:    %v\ngenerated: %v", names, Endpoints)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// settingEntity is the audit log entity type for runtime settings
+const settingEntity = "setting"
+
+// ListSettings returns every runtime setting with its stored value
+// @Summary List runtime settings
+// @Description Every registered runtime setting with its type, description and stored value. Settings without a value use their default, normally from configuration
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} services.SettingValue "Runtime settings"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/settings [get]
+func ListSettings(c *gin.Context) {
+	respondJSON(c, http.StatusOK, services.Settings().List())
+}
+
+// UpdateSettings sets or removes runtime settings
+// @Summary Update runtime settings
+// @Description Set runtime settings by key; an empty value removes one so its default applies again. Unknown keys and values that don't parse as the setting's type are rejected and nothing is changed. Changes apply to this instance immediately and to other instances within SETTINGS_REFRESH_INTERVAL
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param settings body models.SettingsUpdateRequest true "Value per setting key"
+// @Success 200 {array} services.SettingValue "Runtime settings after the change"
+// @Failure 400 {object} map[string]string "Unknown setting or invalid value"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/settings [put]
+func UpdateSettings(c *gin.Context) {
+	var req models.SettingsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	normalized := make(map[string]string, len(req.Settings))
+	for key, value := range req.Settings {
+		value, err := services.NormalizeSetting(key, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid setting",
+				"details": err.Error(),
+			})
+			return
+		}
+		normalized[key] = value
+	}
+
+	err := database.GetDB().WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		for key, value := range normalized {
+			if err := services.SaveSetting(tx, key, value); err != nil {
+				return err
+			}
+			action := models.AuditActionUpdate
+			if value == "" {
+				action = models.AuditActionDelete
+			}
+			if err := models.RecordAudit(tx, action, settingEntity, key, auditActor(c), gin.H{"value": value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to update runtime settings")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to update runtime settings",
+		})
+		return
+	}
+
+	// Apply here now rather than on the next refresh; other instances follow on theirs
+	if err := services.Settings().Reload(c.Request.Context()); err != nil {
+		logger.WithError(err).Warn("Failed to reload runtime settings after update")
+	}
+
+	logger.WithField("settings", normalized).Info("Runtime settings changed")
+	respondJSON(c, http.StatusOK, services.Settings().List())
+}
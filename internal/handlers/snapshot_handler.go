@@ -1,14 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"sukuk-be/internal/database"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetSukukSnapshots returns snapshot events for a specific sukuk
@@ -48,7 +51,7 @@ func GetSukukSnapshots(c *gin.Context) {
 
 		// Get specific snapshot
 		indexerService := services.NewIndexerQueryService()
-		snapshot, err := indexerService.GetSnapshotById(sukukAddress, snapshotId)
+		snapshot, err := indexerService.GetSnapshotById(c.Request.Context(), sukukAddress, snapshotId)
 		if err != nil {
 			logger.WithError(err).Error("Failed to fetch snapshot by ID")
 			c.JSON(http.StatusNotFound, gin.H{
@@ -57,7 +60,7 @@ func GetSukukSnapshots(c *gin.Context) {
 			return
 		}
 
-		c.JSON(http.StatusOK, snapshot)
+		respondJSON(c, http.StatusOK, snapshot)
 		return
 	}
 
@@ -75,10 +78,10 @@ func GetSukukSnapshots(c *gin.Context) {
 	indexerService := services.NewIndexerQueryService()
 
 	// Get snapshots for this sukuk
-	snapshots, err := indexerService.GetSnapshots(sukukAddress, limit)
+	snapshots, err := indexerService.GetSnapshots(c.Request.Context(), sukukAddress, limit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch sukuk snapshots")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to fetch snapshots",
 		})
 		return
@@ -91,7 +94,7 @@ func GetSukukSnapshots(c *gin.Context) {
 		Snapshots:    snapshots,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
 }
 
 // GetAllSnapshots returns snapshot events for all sukuk
@@ -119,10 +122,10 @@ func GetAllSnapshots(c *gin.Context) {
 	indexerService := services.NewIndexerQueryService()
 
 	// Get all snapshots (pass empty string for all sukuk)
-	snapshots, err := indexerService.GetAllSnapshots(limit)
+	snapshots, err := indexerService.GetAllSnapshots(c.Request.Context(), limit)
 	if err != nil {
 		logger.WithError(err).Error("Failed to fetch all snapshots")
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(queryErrorStatus(c, err), gin.H{
 			"error": "Failed to fetch snapshots",
 		})
 		return
@@ -134,7 +137,94 @@ func GetAllSnapshots(c *gin.Context) {
 		Snapshots:  snapshots,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSON(c, http.StatusOK, response)
+}
+
+// GetSukukMetadataSnapshots returns the snapshot history for a sukuk
+// @Summary Get sukuk snapshot history
+// @Description Get SnapshotTaken events for a sukuk ordered by snapshot ID (newest first). Use latest=true to return only the most recent snapshot
+// @Tags sukuk-metadata
+// @Accept json
+// @Produce json
+// @Param id path integer true "Sukuk metadata ID"
+// @Param limit query int false "Number of snapshots to return" default(20) minimum(1) maximum(100)
+// @Param offset query int false "Number of snapshots to skip" default(0) minimum(0)
+// @Param latest query bool false "Return only the most recent snapshot"
+// @Success 200 {object} SnapshotHistoryResponse "Snapshot history"
+// @Success 200 {object} models.SnapshotEvent "Latest snapshot (latest=true)"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Sukuk metadata or snapshot not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/snapshots [get]
+func GetSukukMetadataSnapshots(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID format",
+		})
+		return
+	}
+
+	var sukukMetadata models.SukukMetadata
+	if err := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sukuk metadata not found",
+		})
+		return
+	}
+
+	indexerService := services.NewIndexerQueryService()
+
+	if c.Query("latest") == "true" {
+		snapshot, err := indexerService.GetLatestSnapshot(c.Request.Context(), sukukMetadata.ContractAddress)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "No snapshots found for this sukuk",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to fetch latest snapshot")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to fetch latest snapshot",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusOK, snapshot)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	snapshots, total, err := indexerService.GetSnapshotHistory(c.Request.Context(), sukukMetadata.ContractAddress, limit, offset)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch snapshot history")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch snapshots",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, SnapshotHistoryResponse{
+		SukukID:         sukukMetadata.ID,
+		ContractAddress: sukukMetadata.ContractAddress,
+		Total:           total,
+		Limit:           limit,
+		Offset:          offset,
+		Snapshots:       snapshots,
+	})
 }
 
 // SnapshotsResponse represents the response for sukuk snapshots
@@ -148,4 +238,14 @@ type SnapshotsResponse struct {
 type AllSnapshotsResponse struct {
 	TotalCount int                     `json:"total_count"`
 	Snapshots  []models.SnapshotEvent  `json:"snapshots"`
-}
\ No newline at end of file
+}
+
+// SnapshotHistoryResponse represents a page of snapshot history for a sukuk
+type SnapshotHistoryResponse struct {
+	SukukID         uint                   `json:"sukuk_id"`
+	ContractAddress string                 `json:"contract_address"`
+	Total           int64                  `json:"total"`
+	Limit           int                    `json:"limit"`
+	Offset          int                    `json:"offset"`
+	Snapshots       []models.SnapshotEvent `json:"snapshots"`
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHeartbeatInterval is how often an idle stream sends a comment line to keep proxies from closing it
+const StreamHeartbeatInterval = 15 * time.Second
+
+// StreamActivities streams new blockchain activities as Server-Sent Events
+// @Summary Stream new activities
+// @Description Server-Sent Events stream of purchases and redemption requests as they are indexed. Each message has event type "activity", an incrementing id and a JSON ActivityEvent payload. Reconnecting clients send Last-Event-ID (or last_event_id) to replay recent events they missed; ids restart when the server restarts.
+// @Tags activities
+// @Produce text/event-stream
+// @Param sukuk_address query string false "Only stream activities for this sukuk contract"
+// @Param address query string false "Only stream activities for this user address"
+// @Param type query string false "Only stream this activity type" Enums(purchase, redemption_request, yield_claim)
+// @Param last_event_id query int false "Resume after this event id (alternative to the Last-Event-ID header)"
+// @Success 200 {object} models.ActivityEvent "Stream of activity events"
+// @Failure 400 {object} map[string]string "Invalid Last-Event-ID or activity type"
+// @Router /stream/activities [get]
+func StreamActivities(broker *stream.Broker, heartbeat time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		activityType, ok := activityTypeQuery(c)
+		if !ok {
+			return
+		}
+		filter := stream.Filter{
+			SukukAddress: c.Query("sukuk_address"),
+			Address:      c.Query("address"),
+			Type:         activityType,
+		}
+
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+		var resumeFrom uint64
+		if lastEventID != "" {
+			id, err := strconv.ParseUint(lastEventID, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Invalid Last-Event-ID",
+					"details": "Event id must be a positive integer",
+				})
+				return
+			}
+			resumeFrom = id
+		}
+
+		sub, replay := broker.Subscribe(filter, resumeFrom)
+		defer sub.Close()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		c.Writer.Flush()
+
+		for _, event := range replay {
+			if err := writeActivityEvent(c, event); err != nil {
+				return
+			}
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		ctx := c.Request.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				if dropped := sub.Dropped(); dropped > 0 {
+					logger.WithField("dropped", dropped).Warn("Activity stream client fell behind and missed events")
+				}
+				return
+			case <-broker.Done():
+				return // Shutting down; the client reconnects with its Last-Event-ID
+			case event := <-sub.C:
+				if err := writeActivityEvent(c, event); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// writeActivityEvent writes one SSE message and flushes it to the client
+func writeActivityEvent(c *gin.Context, event stream.Event) error {
+	data, err := json.Marshal(event.Activity)
+	if err != nil {
+		logger.WithError(err).Error("Failed to encode activity event")
+		return nil
+	}
+	if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: activity\ndata: %s\n\n", event.ID, data); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
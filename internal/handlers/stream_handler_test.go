@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sseMessage is one parsed Server-Sent Events message
+type sseMessage struct {
+	id, event, data, comment string
+}
+
+func newStreamServer(broker *stream.Broker, heartbeat time.Duration) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream/activities", StreamActivities(broker, heartbeat))
+	return httptest.NewServer(router)
+}
+
+// openStream connects to the stream and returns a channel of parsed messages
+func openStream(t *testing.T, ctx context.Context, url string, header http.Header) <-chan sseMessage {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Expected event-stream content type, got %q", ct)
+	}
+
+	messages := make(chan sseMessage, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(messages)
+		scanner := bufio.NewScanner(resp.Body)
+		var msg sseMessage
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				messages <- msg
+				msg = sseMessage{}
+			case strings.HasPrefix(line, ":"):
+				msg.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				msg.id = line[len("id: "):]
+			case strings.HasPrefix(line, "event: "):
+				msg.event = line[len("event: "):]
+			case strings.HasPrefix(line, "data: "):
+				msg.data = line[len("data: "):]
+			}
+		}
+	}()
+	return messages
+}
+
+// waitForMessage returns the first message satisfying match, failing after a deadline
+func waitForMessage(t *testing.T, messages <-chan sseMessage, match func(sseMessage) bool) sseMessage {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				t.Fatal("Stream closed before expected message")
+			}
+			if match(msg) {
+				return msg
+			}
+		case <-deadline:
+			t.Fatal("Timed out waiting for stream message")
+		}
+	}
+}
+
+func TestStreamActivitiesDeliversPublishedEvent(t *testing.T) {
+	broker := stream.NewBroker(10, 4)
+	srv := newStreamServer(broker, 20*time.Millisecond)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messages := openStream(t, ctx, srv.URL+"/stream/activities?sukuk_address=0xsukuk", nil)
+
+	// The first heartbeat proves the subscription is registered
+	waitForMessage(t, messages, func(m sseMessage) bool { return m.comment == "heartbeat" })
+
+	broker.Publish(models.ActivityEvent{Type: "purchase", Address: "0xUser", TxHash: "0xother", SukukAddress: "0xOther"})
+	broker.Publish(models.ActivityEvent{Type: "purchase", Address: "0xUser", Amount: "100", TxHash: "0xabc", SukukAddress: "0xSukuk"})
+
+	msg := waitForMessage(t, messages, func(m sseMessage) bool { return m.event == "activity" })
+	if msg.id != "2" {
+		t.Errorf("Expected event id 2, got %q", msg.id)
+	}
+	var activity models.ActivityEvent
+	if err := json.Unmarshal([]byte(msg.data), &activity); err != nil {
+		t.Fatalf("Failed to parse event data: %v", err)
+	}
+	if activity.TxHash != "0xabc" {
+		t.Errorf("Expected filtered event 0xabc, got %s", activity.TxHash)
+	}
+}
+
+func TestStreamActivitiesResumesFromLastEventID(t *testing.T) {
+	broker := stream.NewBroker(10, 4)
+	broker.Publish(models.ActivityEvent{Type: "purchase", TxHash: "0x1"})
+	broker.Publish(models.ActivityEvent{Type: "purchase", TxHash: "0x2"})
+	srv := newStreamServer(broker, time.Minute)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	messages := openStream(t, ctx, srv.URL+"/stream/activities", http.Header{"Last-Event-Id": {"1"}})
+
+	msg := waitForMessage(t, messages, func(m sseMessage) bool { return m.event == "activity" })
+	if msg.id != "2" || !strings.Contains(msg.data, `"tx_hash":"0x2"`) {
+		t.Errorf("Expected replay of event 2, got id=%q data=%s", msg.id, msg.data)
+	}
+}
+
+func TestStreamActivitiesRejectsInvalidLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream/activities", StreamActivities(stream.NewBroker(10, 4), time.Minute))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream/activities?last_event_id=abc", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestStreamActivitiesRejectsUnknownType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream/activities", StreamActivities(stream.NewBroker(10, 4), time.Minute))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream/activities?type=swap", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d", w.Code)
+	}
+	var body struct {
+		ValidTypes []string `json:"valid_types"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if strings.Join(body.ValidTypes, ",") != strings.Join(models.ValidActivityTypes(), ",") {
+		t.Errorf("Expected the registry's types to be listed, got %v", body.ValidTypes)
+	}
+}
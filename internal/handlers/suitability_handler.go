@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetSuitability returns an investor's risk acknowledgements
+// @Summary Get risk acknowledgement status
+// @Description With sukuk_metadata_id, get whether the investor acknowledged the risks of that sukuk for its current prospectus: acknowledged, stale (a newer prospectus version was published since) or missing, with the prospectus to acknowledge. Without it, list the status of every sukuk the investor has acknowledged.
+// @Tags suitability
+// @Produce json
+// @Param address path string true "Investor wallet address"
+// @Security WalletAuth
+// @Param sukuk_metadata_id query int false "Sukuk metadata ID"
+// @Success 200 {object} models.SuitabilityStatusesResponse "Statuses; a single models.SuitabilityStatus with sukuk_metadata_id"
+// @Failure 400 {object} map[string]string "Invalid address or sukuk ID"
+// @Failure 401 {object} map[string]string "Missing, invalid or expired wallet token"
+// @Failure 403 {object} map[string]string "Wallet token is for another address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /suitability/{address} [get]
+func GetSuitability(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+	db := database.GetDB().WithContext(c.Request.Context())
+
+	if raw := c.Query("sukuk_metadata_id"); raw != "" {
+		sukukID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || sukukID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid sukuk_metadata_id",
+			})
+			return
+		}
+		status, err := models.GetSuitabilityStatus(db, address, uint(sukukID))
+		if err != nil {
+			logger.WithError(err).Error("Failed to get risk acknowledgement status")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to get risk acknowledgement status",
+			})
+			return
+		}
+		respondJSON(c, http.StatusOK, status)
+		return
+	}
+
+	statuses, err := models.ListSuitabilityStatuses(db, address)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list risk acknowledgements")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to list risk acknowledgements",
+		})
+		return
+	}
+	respondJSON(c, http.StatusOK, models.SuitabilityStatusesResponse{
+		UserAddress:      utils.NormalizeAddress(address),
+		Acknowledgements: statuses,
+	})
+}
+
+// RecordSuitability records an investor's acknowledgement of a sukuk's risks
+// @Summary Acknowledge sukuk risks
+// @Description Record that the investor acknowledged the product risk of a sukuk as described in its current prospectus, which orders for the sukuk require. Send document_id of the current prospectus (omit it for a sukuk without one) and either a consent_token from the app's checkbox or a personal_sign signature of the message "I acknowledge the risks of this Sukuk as described in its prospectus\nAddress: <lowercase address>\nSukuk: <sukuk_metadata_id>\nProspectus version: <version>\nIssued at: <RFC3339 UTC time>", issued within the last 10 minutes. Both need the wallet's session token for the address.
+// @Tags suitability
+// @Accept json
+// @Produce json
+// @Param address path string true "Investor wallet address"
+// @Security WalletAuth
+// @Param acknowledgement body models.SuitabilityAcknowledgementRequest true "Acknowledgement"
+// @Success 201 {object} models.SuitabilityStatus "Acknowledged"
+// @Failure 400 {object} map[string]string "Invalid address, payload or message"
+// @Failure 401 {object} map[string]string "Missing or invalid wallet token, or signature not made by the address"
+// @Failure 403 {object} map[string]string "Wallet token is for another address"
+// @Failure 404 {object} map[string]string "Sukuk not found"
+// @Failure 409 {object} map[string]interface{} "Document is not the current prospectus"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /suitability/{address} [post]
+func RecordSuitability(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	var req models.SuitabilityAcknowledgementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+	signed := req.Signature != "" || req.Message != ""
+	if signed == (strings.TrimSpace(req.ConsentToken) != "") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Send either a message and signature or a consent_token",
+		})
+		return
+	}
+
+	db := database.GetDB().WithContext(c.Request.Context())
+	var sukuk models.SukukMetadata
+	err := db.Select("id").First(&sukuk, req.SukukMetadataID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sukuk not found",
+		})
+		return
+	}
+	var prospectus *models.SukukDocument
+	if err == nil {
+		prospectus, err = models.ActiveProspectus(db, sukuk.ID)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to load sukuk prospectus for acknowledgement")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to record risk acknowledgement",
+		})
+		return
+	}
+
+	// Only the current prospectus can be acknowledged, so an acknowledgement is never stale
+	// when recorded
+	acknowledgement := models.SuitabilityAcknowledgement{
+		UserAddress:     address,
+		SukukMetadataID: sukuk.ID,
+		ConsentToken:    strings.TrimSpace(req.ConsentToken),
+		AcknowledgedAt:  time.Now(),
+	}
+	current := models.NewSuitabilityStatus(address, sukuk.ID, nil, prospectus)
+	switch {
+	case prospectus == nil && req.DocumentID != 0, prospectus != nil && req.DocumentID != prospectus.ID:
+		c.JSON(http.StatusConflict, gin.H{
+			"error":             "Document is not the current prospectus of the sukuk",
+			"required_document": current.RequiredDocument,
+		})
+		return
+	case prospectus != nil:
+		acknowledgement.DocumentID = &prospectus.ID
+		acknowledgement.DocumentVersion = prospectus.Version
+	}
+
+	acknowledgement.Method = models.SuitabilityMethodCheckbox
+	if signed {
+		err := services.VerifySuitabilitySignature(address, sukuk.ID, acknowledgement.DocumentVersion, req.Message, req.Signature, time.Now())
+		if err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, services.ErrInvalidSuitabilityMessage) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{
+				"error":   "Invalid signature",
+				"details": err.Error(),
+			})
+			return
+		}
+		acknowledgement.Method = models.SuitabilityMethodSignature
+		acknowledgement.Message = req.Message
+		acknowledgement.Signature = req.Signature
+	}
+
+	if err := models.SaveSuitabilityAcknowledgement(db, &acknowledgement); err != nil {
+		logger.WithError(err).Error("Failed to record risk acknowledgement")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to record risk acknowledgement",
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"address":          acknowledgement.UserAddress,
+		"sukuk_id":         sukuk.ID,
+		"document_version": acknowledgement.DocumentVersion,
+		"method":           acknowledgement.Method,
+	}).Info("Risk acknowledgement recorded")
+
+	respondJSON(c, http.StatusCreated, models.NewSuitabilityStatus(address, sukuk.ID, &acknowledgement, prospectus))
+}
+
+// respondSuitabilityRequired writes the 412 for an order refused for want of a current risk
+// acknowledgement
+func respondSuitabilityRequired(c *gin.Context, required *services.SuitabilityRequiredError) {
+	message := "Risk acknowledgement required before purchasing this sukuk"
+	if required.Status.State == models.SuitabilityStale {
+		message = "Risk acknowledgement is for an outdated prospectus, acknowledge the current one before purchasing"
+	}
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":       message,
+		"suitability": required.Status,
+	})
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const suitabilityTestInvestor = "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9"
+
+// suitabilityDB answers the queries of an order for sukuk 7, which is open for purchase and
+// has prospectus version 2 active, with the investor's acknowledgement of acknowledgedVersion,
+// or none when it is negative
+func suitabilityDB(acknowledgedVersion int) func(query string) stubResult {
+	return func(query string) stubResult {
+		switch {
+		case strings.Contains(query, `FROM "suitability_acknowledgements"`):
+			if acknowledgedVersion < 0 {
+				return stubResult{}
+			}
+			return stubResult{
+				columns: []string{"id", "user_address", "sukuk_metadata_id", "document_id", "document_version", "method", "acknowledged_at"},
+				rows: [][]driver.Value{{int64(1), suitabilityTestInvestor, int64(7), int64(10 + acknowledgedVersion), int64(acknowledgedVersion),
+					"checkbox", time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)}},
+			}
+		case strings.Contains(query, `FROM "sukuk_documents"`):
+			return stubResult{
+				columns: []string{"id", "sukuk_id", "type", "title", "file_url", "version", "active"},
+				rows:    [][]driver.Value{{int64(12), int64(7), "prospectus", "Prospektus (amandemen)", "/uploads/prospektus-v2.pdf", int64(2), true}},
+			}
+		case strings.Contains(query, `FROM "sukuk_metadata"`):
+			return stubResult{
+				columns: []string{"id", "contract_address", "status", "metadata_ready", "minimum_pembelian", "maksimum_pembelian"},
+				rows:    [][]driver.Value{{int64(7), "0xabcdef0000000000000000000000000000000001", "active", true, float64(1000000), float64(0)}},
+			}
+		}
+		return stubResult{}
+	}
+}
+
+func serveSuitability(t *testing.T, respond func(query string) stubResult, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	return serveSuitabilityAs(t, respond, "", method, target, body)
+}
+
+// serveSuitabilityAs serves the suitability routes as if RequireWalletAuth had verified a
+// session token for wallet, or without a session when wallet is empty
+func serveSuitabilityAs(t *testing.T, respond func(query string) stubResult, wallet, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	previous := database.DB
+	database.DB = openStubDB(t, respond).Session(&gorm.Session{SkipDefaultTransaction: true})
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if wallet != "" {
+		router.Use(func(c *gin.Context) { c.Set(middleware.WalletAddressContextKey, wallet) })
+	}
+	router.POST("/orders", CreateOrder(time.Hour))
+	router.GET("/suitability/:address", GetSuitability)
+	router.POST("/suitability/:address", RecordSuitability)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func TestCreateOrderRequiresRiskAcknowledgement(t *testing.T) {
+	order := `{"user_address": "` + suitabilityTestInvestor + `", "sukuk_metadata_id": 7, "fiat_amount": 1000000}`
+
+	tests := []struct {
+		name                string
+		acknowledgedVersion int
+		wantState           models.SuitabilityState
+	}{
+		{"missing", -1, models.SuitabilityMissing},
+		{"stale", 1, models.SuitabilityStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSuitabilityAs(t, suitabilityDB(tt.acknowledgedVersion), suitabilityTestInvestor, http.MethodPost, "/orders", order)
+			if w.Code != http.StatusPreconditionFailed {
+				t.Fatalf("Expected status 412, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Error       string                   `json:"error"`
+				Suitability models.SuitabilityStatus `json:"suitability"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Suitability.State != tt.wantState {
+				t.Errorf("Expected state %s, got %s", tt.wantState, response.Suitability.State)
+			}
+			required := response.Suitability.RequiredDocument
+			if required == nil || required.ID != 12 || required.Version != 2 {
+				t.Errorf("Expected prospectus 12 version 2 to be required, got %+v", required)
+			}
+		})
+	}
+}
+
+func TestGetSuitabilityStatus(t *testing.T) {
+	tests := []struct {
+		name                string
+		acknowledgedVersion int
+		want                models.SuitabilityState
+	}{
+		{"fresh", 2, models.SuitabilityAcknowledged},
+		{"stale", 1, models.SuitabilityStale},
+		{"missing", -1, models.SuitabilityMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveSuitability(t, suitabilityDB(tt.acknowledgedVersion), http.MethodGet, "/suitability/"+suitabilityTestInvestor+"?sukuk_metadata_id=7", "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var status models.SuitabilityStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if status.State != tt.want {
+				t.Errorf("Expected state %s, got %s", tt.want, status.State)
+			}
+		})
+	}
+}
+
+func TestRecordSuitabilityRecordsConsentToken(t *testing.T) {
+	body := `{"sukuk_metadata_id": 7, "document_id": 12, "consent_token": "checkbox-1"}`
+
+	w := serveSuitabilityAs(t, suitabilityDB(-1), suitabilityTestInvestor, http.MethodPost, "/suitability/"+suitabilityTestInvestor, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected the checkbox acknowledgement to be recorded, got %d: %s", w.Code, w.Body.String())
+	}
+}
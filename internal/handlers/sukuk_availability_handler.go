@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSukukAvailability returns how much of a sukuk is still open for subscription
+// @Summary Get sukuk availability
+// @Description Compare the sukuk's kuota_nasional (scaled to wei by the token decimals) with the sum of its indexed purchase events, giving the remaining capacity and percent subscribed, and report whether today falls within periode_pembelian (Asia/Jakarta, last day inclusive). A sukuk without a kuota_nasional is unlimited; purchase_period_open is null when periode_pembelian can't be parsed. Amounts are raw wei
+// @Tags sukuk-metadata
+// @Accept json
+// @Produce json
+// @Param id path int true "Sukuk Metadata ID"
+// @Param fiat query string false "Comma-separated currencies for display_values, e.g. idr,usd; sukuk token amounts are priced as IDRX and the rates used are listed in meta" Example(idr,usd)
+// @Success 200 {object} models.SukukAvailability "Availability"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/availability [get]
+func GetSukukAvailability(c *gin.Context) {
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	availabilityService := services.NewSukukAvailabilityService(database.GetDB(), services.NewIndexerQueryService())
+	availability, err := availabilityService.Availability(c.Request.Context(), sukukMetadata)
+	if err != nil {
+		logger.WithError(err).Error("Failed to compute sukuk availability")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to compute sukuk availability",
+		})
+		return
+	}
+
+	if display := newFiatDisplay(c); display != nil {
+		addAvailabilityDisplayValues(availability, display, loadTokenFormatter())
+	}
+
+	respondJSON(c, http.StatusOK, availability)
+}
+
+// addAvailabilityDisplayValues prices the sukuk token amounts of an availability as IDRX
+func addAvailabilityDisplayValues(availability *models.SukukAvailability, display *fiatDisplay, formatter *services.TokenFormatter) {
+	amounts := map[string][]models.FormattedAmount{
+		"total_purchased": {sukukTokenAmount(formatter, availability.TotalPurchased, availability.ContractAddress)},
+	}
+	if !availability.Unlimited {
+		amounts["cap"] = []models.FormattedAmount{sukukTokenAmount(formatter, availability.Cap, availability.ContractAddress)}
+		amounts["remaining"] = []models.FormattedAmount{sukukTokenAmount(formatter, availability.Remaining, availability.ContractAddress)}
+	}
+	availability.DisplayValues = display.fields(amounts)
+	availability.Meta = display.meta()
+}
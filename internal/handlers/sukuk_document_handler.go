@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const sukukDocumentEntity = "sukuk_document"
+
+// SukukDocumentUploader stores uploaded sukuk documents as new versions
+type SukukDocumentUploader interface {
+	Upload(ctx context.Context, upload services.SukukDocumentUpload) (*models.SukukDocument, error)
+}
+
+// GetSukukDocuments returns the active documents of a sukuk grouped by type
+// @Summary Get sukuk documents
+// @Description Get the current version of each document attached to a sukuk, such as its prospectus, fact sheet and sharia certificate, grouped by type. The file_url of a prospectus is its prospectus-link endpoint, which hands out a signed download link
+// @Tags sukuk-metadata
+// @Accept json
+// @Produce json
+// @Param id path integer true "Sukuk metadata ID"
+// @Success 200 {object} models.SukukDocumentsResponse "Active documents by type"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/documents [get]
+func GetSukukDocuments(c *gin.Context) {
+	respondSukukDocuments(c, true)
+}
+
+// ListSukukDocuments returns every document version of a sukuk, active or not
+// @Summary List sukuk document versions
+// @Description List all versions of the documents attached to a sukuk, including deactivated ones, grouped by type with the newest version first. Each version carries its download_count, the number of signed download links handed out for it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Success 200 {object} models.SukukDocumentsResponse "Document versions by type"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/documents [get]
+func ListSukukDocuments(c *gin.Context) {
+	respondSukukDocuments(c, false)
+}
+
+func respondSukukDocuments(c *gin.Context, activeOnly bool) {
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	documents, err := models.ListSukukDocuments(database.GetDB().WithContext(c.Request.Context()), sukukMetadata.ID, activeOnly)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch sukuk documents")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch sukuk documents",
+		})
+		return
+	}
+	if activeOnly {
+		// Prospectus files are only served through signed links, and counts are for admins
+		for i := range documents {
+			documents[i].DownloadCount = nil
+			if documents[i].Type == models.SukukDocumentProspectus {
+				documents[i].FileURL = fmt.Sprintf("/api/v1/sukuk-metadata/%d/prospectus-link", sukukMetadata.ID)
+			}
+		}
+	}
+
+	respondJSON(c, http.StatusOK, models.SukukDocumentsResponse{
+		SukukID:   sukukMetadata.ID,
+		Documents: models.GroupSukukDocuments(documents),
+	})
+}
+
+// UploadSukukDocument attaches a document to a sukuk
+// @Summary Upload sukuk document
+// @Description Upload a document for a sukuk. Prospectuses and sharia certificates must be PDFs; fact sheets may also be PNG or JPEG images. A document of a type the sukuk already has becomes its next version and the previous version is deactivated
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Param type formData string true "Document type" Enums(prospectus, fact_sheet, sharia_certificate)
+// @Param title formData string true "Document title"
+// @Param file formData file true "Document file"
+// @Success 201 {object} models.SukukDocument "Stored document version"
+// @Failure 400 {object} map[string]string "Invalid ID, type or file"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/documents [post]
+func UploadSukukDocument(uploader SukukDocumentUploader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileHeader, err := c.FormFile("file")
+		if middleware.IsBodyTooLarge(err) {
+			middleware.AbortBodyTooLarge(c, 0)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Missing document file",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		sukukMetadata, ok := findSukukMetadataByID(c)
+		if !ok {
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Failed to read document file",
+				"details": err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+
+		document, err := uploader.Upload(c.Request.Context(), services.SukukDocumentUpload{
+			SukukID:    sukukMetadata.ID,
+			Type:       models.SukukDocumentType(c.PostForm("type")),
+			Title:      c.PostForm("title"),
+			Filename:   fileHeader.Filename,
+			Size:       fileHeader.Size,
+			Content:    file,
+			UploadedBy: auditActor(c),
+		})
+		if errors.Is(err, services.ErrInvalidSukukDocument) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid document",
+				"details": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to store sukuk document")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to store sukuk document",
+			})
+			return
+		}
+
+		if err := models.RecordAudit(database.GetDB(), models.AuditActionCreate, sukukDocumentEntity, strconv.FormatUint(uint64(document.ID), 10), auditActor(c), document); err != nil {
+			logger.WithError(err).Warn("Failed to record sukuk document audit")
+		}
+
+		c.JSON(http.StatusCreated, document)
+	}
+}
+
+// DeactivateSukukDocument withdraws a document version from the public listing, keeping its record
+// @Summary Deactivate sukuk document
+// @Description Withdraw a document version so it is no longer served publicly. The record and file are kept
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Param document_id path integer true "Document ID"
+// @Success 200 {object} models.SukukDocument "Deactivated document"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata or document not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/documents/{document_id}/deactivate [put]
+func DeactivateSukukDocument(c *gin.Context) {
+	documentID, err := strconv.ParseUint(c.Param("document_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid document ID format",
+		})
+		return
+	}
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	var document *models.SukukDocument
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		document, err = models.DeactivateSukukDocument(tx, sukukMetadata.ID, uint(documentID))
+		if err != nil {
+			return err
+		}
+		entityID := strconv.FormatUint(uint64(document.ID), 10)
+		return models.RecordAudit(tx, models.AuditActionUpdate, sukukDocumentEntity, entityID, auditActor(c), gin.H{"active": false})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sukuk document not found",
+		})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to deactivate sukuk document")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to deactivate sukuk document",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, document)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fileLinkPath is the path signed file links are served under
+const fileLinkPath = "/api/v1/files/"
+
+// GetProspectusLink hands out a short-lived signed link to a sukuk's prospectus
+// @Summary Get a prospectus download link
+// @Description Get a signed link to the current prospectus of a sukuk, valid for UPLOAD_LINK_TTL. Prospectus files are only served through these links, not under /uploads. Every link handed out counts as a download of the prospectus version, recorded with the requesting wallet when address is given
+// @Tags sukuk-metadata
+// @Accept json
+// @Produce json
+// @Param id path integer true "Sukuk metadata ID"
+// @Param address query string false "Requesting wallet address"
+// @Success 200 {object} models.SukukDocumentLinkResponse "Signed link"
+// @Failure 400 {object} map[string]string "Invalid ID format or address"
+// @Failure 404 {object} map[string]string "Sukuk metadata or prospectus not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Prospectus downloads not configured"
+// @Router /sukuk-metadata/{id}/prospectus-link [get]
+func GetProspectusLink(secret string, ttl time.Duration) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = services.DefaultFileLinkTTL
+	}
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Prospectus downloads not configured",
+			})
+			return
+		}
+
+		address := c.Query("address")
+		if address != "" && !utils.IsValidEthereumAddress(address) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid address",
+			})
+			return
+		}
+
+		sukukMetadata, ok := findSukukMetadataByID(c)
+		if !ok {
+			return
+		}
+
+		db := database.GetDB().WithContext(c.Request.Context())
+		document, err := models.GetActiveSukukDocument(db, sukukMetadata.ID, models.SukukDocumentProspectus)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Prospectus not found",
+			})
+			return
+		}
+		if err == nil {
+			err = models.RecordSukukDocumentDownload(db, document, address)
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to issue prospectus link")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to issue prospectus link",
+			})
+			return
+		}
+
+		expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, models.SukukDocumentLinkResponse{
+			DocumentID: document.ID,
+			Version:    document.Version,
+			URL:        fileLinkPath + services.NewFileLinkToken(secret, document.ID, expiresAt),
+			ExpiresAt:  expiresAt.UTC(),
+		})
+	}
+}
+
+// ServeFileLink streams the document file a signed link points to
+// @Summary Download a file through a signed link
+// @Description Stream the document file of a signed link from GET /sukuk-metadata/{id}/prospectus-link. Range requests are supported
+// @Tags sukuk-metadata
+// @Produce application/octet-stream
+// @Param token path string true "Signed link token"
+// @Success 200 {file} file "Document file"
+// @Failure 403 {object} map[string]string "Invalid link"
+// @Failure 404 {object} map[string]string "File not found"
+// @Failure 410 {object} map[string]string "Link expired"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Prospectus downloads not configured"
+// @Router /files/{token} [get]
+func ServeFileLink(secret string, storage services.FileOpener) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Prospectus downloads not configured",
+			})
+			return
+		}
+
+		documentID, err := services.ParseFileLinkToken(secret, c.Param("token"), time.Now())
+		if errors.Is(err, services.ErrFileLinkExpired) {
+			c.JSON(http.StatusGone, gin.H{
+				"error": "Link expired",
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Invalid link",
+			})
+			return
+		}
+
+		var document models.SukukDocument
+		err = database.GetDB().WithContext(c.Request.Context()).First(&document, "id = ?", documentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "File not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to load linked document")
+			c.JSON(queryErrorStatus(c, err), gin.H{
+				"error": "Failed to load file",
+			})
+			return
+		}
+
+		file, info, err := services.OpenSukukDocument(c.Request.Context(), storage, &document)
+		if errors.Is(err, services.ErrFileNotFound) {
+			logger.WithField("document_id", document.ID).Warn("Linked document file is missing")
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "File not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to open linked document")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to load file",
+			})
+			return
+		}
+		defer file.Close()
+
+		filename := fmt.Sprintf("sukuk_%d_%s_v%d%s", document.SukukID, document.Type, document.Version, path.Ext(info.Name))
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		c.Header("Cache-Control", "private, no-store")
+		http.ServeContent(c.Writer, c.Request, filename, info.ModTime, file)
+	}
+}
+
+// DenyProspectusUploads keeps prospectus files out of the static /uploads route, so they
+// are only downloaded through signed links, and payout proofs, which only admins download
+func DenyProspectusUploads(c *gin.Context) {
+	name := strings.TrimPrefix(c.Request.URL.Path, "/uploads")
+	if services.IsProspectusUpload(name) || services.IsPayoutProofUpload(name) {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+const fileLinkTestSecret = "file-link-test-secret"
+
+// newFileLinkRouter serves the prospectus link and file routes over storage rooted at dir
+func newFileLinkRouter(dir string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/sukuk-metadata/:id/prospectus-link", GetProspectusLink(fileLinkTestSecret, time.Minute))
+	router.GET("/api/v1/files/:token", ServeFileLink(fileLinkTestSecret, services.NewLocalUploadStorage(dir)))
+	router.Group("/uploads", DenyProspectusUploads).Static("/", dir)
+	return router
+}
+
+func serveFileLink(router *gin.Engine, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+// writeUpload stores content under name in dir
+func writeUpload(t *testing.T, dir, name, content string) {
+	t.Helper()
+	fullPath := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write upload: %v", err)
+	}
+}
+
+func TestServeFileLinkRejectsExpiredAndTamperedLinks(t *testing.T) {
+	router := newFileLinkRouter(t.TempDir())
+
+	expired := services.NewFileLinkToken(fileLinkTestSecret, 7, time.Now().Add(-time.Second))
+	if w := serveFileLink(router, "/api/v1/files/"+expired); w.Code != http.StatusGone {
+		t.Errorf("Expected status 410 for an expired link, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Pointing a valid link at another document breaks its signature
+	valid := services.NewFileLinkToken(fileLinkTestSecret, 7, time.Now().Add(time.Minute))
+	other := services.NewFileLinkToken(fileLinkTestSecret, 8, time.Now().Add(time.Minute))
+	payload, _, _ := strings.Cut(other, ".")
+	_, mac, _ := strings.Cut(valid, ".")
+	for _, token := range []string{
+		payload + "." + mac,
+		services.NewFileLinkToken("another-secret", 7, time.Now().Add(time.Minute)),
+		"not-a-token",
+	} {
+		if w := serveFileLink(router, "/api/v1/files/"+token); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for %s, got %d: %s", token, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestFileLinksRequireSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata/:id/prospectus-link", GetProspectusLink("", time.Minute))
+	router.GET("/files/:token", ServeFileLink("", services.NewLocalUploadStorage(t.TempDir())))
+
+	for _, target := range []string{"/sukuk-metadata/1/prospectus-link", "/files/token"} {
+		if w := serveFileLink(router, target); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: expected status 503, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestServeFileLinkStreamsDocument(t *testing.T) {
+	dir := t.TempDir()
+	writeUpload(t, dir, "documents/sukuk_3/prospectus_1700000000.pdf", "%PDF-1.4 prospectus")
+
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		return stubResult{
+			columns: []string{"id", "sukuk_id", "type", "file_url", "version", "active"},
+			rows:    [][]driver.Value{{int64(7), int64(3), "prospectus", "/uploads/documents/sukuk_3/prospectus_1700000000.pdf", int64(2), true}},
+		}
+	})
+	defer func() { database.DB = previous }()
+
+	router := newFileLinkRouter(dir)
+	token := services.NewFileLinkToken(fileLinkTestSecret, 7, time.Now().Add(time.Minute))
+	w := serveFileLink(router, "/api/v1/files/"+token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Body.String() != "%PDF-1.4 prospectus" {
+		t.Errorf("Expected the prospectus content, got %q", w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename=sukuk_3_prospectus_v2.pdf` {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+}
+
+func TestDenyProspectusUploads(t *testing.T) {
+	dir := t.TempDir()
+	writeUpload(t, dir, "documents/sukuk_3/prospectus_1700000000.pdf", "%PDF-1.4 prospectus")
+	writeUpload(t, dir, "documents/sukuk_3/fact_sheet_1700000000.pdf", "%PDF-1.4 fact sheet")
+	writeUpload(t, dir, "sukuk_3_prospectus.pdf", "%PDF-1.4 legacy")
+	writeUpload(t, dir, "payouts/4/proof_1700000000.pdf", "%PDF-1.4 transfer receipt")
+	router := newFileLinkRouter(dir)
+
+	for _, target := range []string{
+		"/uploads/documents/sukuk_3/prospectus_1700000000.pdf",
+		"/uploads/documents/sukuk_3/../sukuk_3/prospectus_1700000000.pdf",
+		"/uploads/sukuk_3_prospectus.pdf",
+		"/uploads/payouts/4/proof_1700000000.pdf",
+	} {
+		if w := serveFileLink(router, target); w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", target, w.Code)
+		}
+	}
+	if w := serveFileLink(router, "/uploads/documents/sukuk_3/fact_sheet_1700000000.pdf"); w.Code != http.StatusOK {
+		t.Errorf("Expected other documents to be served, got %d", w.Code)
+	}
+}
+
+// TestProspectusLinkCountsDownloads hands out links to a prospectus and downloads it
+// It requires a Postgres database, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func TestProspectusLinkCountsDownloads(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	cache.SetDefault(cache.NewMemoryCache())
+
+	metadata := models.SukukMetadata{
+		ContractAddress: "0x00000000000000000000000000000000000d0c01",
+		SukukCode:       "DOCLINK",
+		SukukTitle:      "Prospectus Link",
+	}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	defer db.Unscoped().Delete(&models.SukukMetadata{}, metadata.ID)
+
+	dir := t.TempDir()
+	writeUpload(t, dir, "documents/prospectus.pdf", "%PDF-1.4 prospectus")
+	document := models.SukukDocument{SukukID: metadata.ID, Type: models.SukukDocumentProspectus, Title: "Prospectus", FileURL: "/uploads/documents/prospectus.pdf"}
+	if err := models.CreateSukukDocumentVersion(db, &document); err != nil {
+		t.Fatalf("Failed to create document: %v", err)
+	}
+	defer db.Where("document_id = ?", document.ID).Delete(&models.SukukDocumentDownload{})
+	defer db.Delete(&models.SukukDocument{}, document.ID)
+
+	router := newFileLinkRouter(dir)
+	target := "/api/v1/sukuk-metadata/" + strconv.FormatUint(uint64(metadata.ID), 10) + "/prospectus-link"
+	if w := serveFileLink(router, target+"?address=0xnope"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an invalid address to be rejected, got %d", w.Code)
+	}
+	serveFileLink(router, target)
+	w := serveFileLink(router, target+"?address=0x00000000000000000000000000000000000000AB")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var link models.SukukDocumentLinkResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &link); err != nil {
+		t.Fatalf("Failed to decode link: %v", err)
+	}
+	if link.DocumentID != document.ID || !link.ExpiresAt.After(time.Now()) {
+		t.Errorf("Unexpected link %+v", link)
+	}
+
+	var stored models.SukukDocument
+	db.First(&stored, document.ID)
+	if stored.DownloadCount == nil || *stored.DownloadCount != 2 {
+		t.Errorf("Expected 2 downloads, got %v", stored.DownloadCount)
+	}
+	var downloads []models.SukukDocumentDownload
+	db.Where("document_id = ?", document.ID).Order("id").Find(&downloads)
+	if len(downloads) != 2 || downloads[0].Address != "" || downloads[1].Address != "0x00000000000000000000000000000000000000ab" {
+		t.Errorf("Expected an anonymous and an attributed download, got %+v", downloads)
+	}
+
+	if w := serveFileLink(router, link.URL); w.Code != http.StatusOK || w.Body.String() != "%PDF-1.4 prospectus" {
+		t.Errorf("Expected the link to serve the prospectus, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Sort keys of the sukuk list
+const (
+	sukukSortImbalHasil     = "imbal_hasil"
+	sukukSortJatuhTempo     = "jatuh_tempo"
+	sukukSortNewest         = "newest"
+	sukukSortMostSubscribed = "most_subscribed"
+)
+
+// sukukSortDescending is the default direction of each sort key
+var sukukSortDescending = map[string]bool{
+	sukukSortImbalHasil:     true,
+	sukukSortJatuhTempo:     false,
+	sukukSortNewest:         true,
+	sukukSortMostSubscribed: true,
+}
+
+// sukukListQuery holds the sort and filter parameters of the sukuk list
+type sukukListQuery struct {
+	Sort           string // Empty keeps the unsorted listing
+	Descending     bool
+	Statuses       []models.SukukStatus
+	TipeKupon      string
+	MinTenorMonths *int
+	MaxTenorMonths *int
+	MaturityBefore *time.Time
+	MaturityAfter  *time.Time
+	MinYieldBps    *int
+}
+
+// parseSukukListQuery reads sort, order, status, tipe_kupon, min_tenor, max_tenor,
+// maturity_before, maturity_after and min_yield, rejecting values it cannot apply
+func parseSukukListQuery(c *gin.Context) (sukukListQuery, error) {
+	var query sukukListQuery
+
+	if sortKey := c.Query("sort"); sortKey != "" {
+		descending, ok := sukukSortDescending[sortKey]
+		if !ok {
+			return query, fmt.Errorf("sort must be one of imbal_hasil, jatuh_tempo, newest, most_subscribed, got %q", sortKey)
+		}
+		query.Sort, query.Descending = sortKey, descending
+	}
+	switch order := c.Query("order"); order {
+	case "":
+	case "asc", "desc":
+		if query.Sort == "" {
+			return query, fmt.Errorf("order needs a sort")
+		}
+		query.Descending = order == "desc"
+	default:
+		return query, fmt.Errorf("order must be asc or desc, got %q", order)
+	}
+
+	if statuses := c.Query("status"); statuses != "" {
+		for _, value := range strings.Split(statuses, ",") {
+			status := models.SukukStatus(strings.TrimSpace(value))
+			if !status.IsValid() {
+				return query, fmt.Errorf("invalid status %q", value)
+			}
+			query.Statuses = append(query.Statuses, status)
+		}
+	}
+	query.TipeKupon = strings.TrimSpace(c.Query("tipe_kupon"))
+
+	for param, target := range map[string]**int{"min_tenor": &query.MinTenorMonths, "max_tenor": &query.MaxTenorMonths} {
+		if value := c.Query(param); value != "" {
+			months, ok := models.ParseTenorMonths(value)
+			if !ok {
+				return query, fmt.Errorf("%s must be a tenor such as 5, 5 tahun or 18 bulan, got %q", param, value)
+			}
+			*target = &months
+		}
+	}
+	if query.MinTenorMonths != nil && query.MaxTenorMonths != nil && *query.MinTenorMonths > *query.MaxTenorMonths {
+		return query, fmt.Errorf("min_tenor is longer than max_tenor")
+	}
+
+	for param, target := range map[string]**time.Time{"maturity_before": &query.MaturityBefore, "maturity_after": &query.MaturityAfter} {
+		if value := c.Query(param); value != "" {
+			date, err := parseTimeSeriesDate(value)
+			if err != nil {
+				return query, fmt.Errorf("%s must be RFC3339 or YYYY-MM-DD, got %q", param, value)
+			}
+			*target = &date
+		}
+	}
+
+	if value := c.Query("min_yield"); value != "" {
+		bps, ok := models.ParseImbalHasilBps(value)
+		if !ok {
+			return query, fmt.Errorf("min_yield must be a percentage such as 6.25, got %q", value)
+		}
+		query.MinYieldBps = &bps
+	}
+	return query, nil
+}
+
+// cacheKey identifies the query in the list cache key and ETag; empty without parameters
+func (q sukukListQuery) cacheKey() string {
+	values := url.Values{}
+	if q.Sort != "" {
+		order := "asc"
+		if q.Descending {
+			order = "desc"
+		}
+		values.Set("sort", q.Sort+"."+order)
+	}
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			statuses[i] = string(status)
+		}
+		sort.Strings(statuses)
+		values.Set("status", strings.Join(statuses, ","))
+	}
+	if q.TipeKupon != "" {
+		values.Set("tipe_kupon", strings.ToLower(q.TipeKupon))
+	}
+	if q.MinTenorMonths != nil {
+		values.Set("min_tenor", fmt.Sprint(*q.MinTenorMonths))
+	}
+	if q.MaxTenorMonths != nil {
+		values.Set("max_tenor", fmt.Sprint(*q.MaxTenorMonths))
+	}
+	if q.MaturityBefore != nil {
+		values.Set("maturity_before", q.MaturityBefore.UTC().Format(time.RFC3339))
+	}
+	if q.MaturityAfter != nil {
+		values.Set("maturity_after", q.MaturityAfter.UTC().Format(time.RFC3339))
+	}
+	if q.MinYieldBps != nil {
+		values.Set("min_yield", fmt.Sprint(*q.MinYieldBps))
+	}
+	return values.Encode()
+}
+
+// apply adds the filters and the ORDER BY of the query. Sukuk whose tenor or imbal hasil
+// could not be parsed drop out of those filters and sort last
+func (q sukukListQuery) apply(db *gorm.DB) *gorm.DB {
+	if len(q.Statuses) > 0 {
+		db = db.Where("status IN ?", q.Statuses)
+	}
+	if q.TipeKupon != "" {
+		db = db.Where("LOWER(tipe_kupon) = LOWER(?)", q.TipeKupon)
+	}
+	if q.MinTenorMonths != nil {
+		db = db.Where("tenor_months >= ?", *q.MinTenorMonths)
+	}
+	if q.MaxTenorMonths != nil {
+		db = db.Where("tenor_months <= ?", *q.MaxTenorMonths)
+	}
+	if q.MaturityBefore != nil {
+		db = db.Where("jatuh_tempo < ?", *q.MaturityBefore)
+	}
+	if q.MaturityAfter != nil {
+		db = db.Where("jatuh_tempo >= ?", *q.MaturityAfter)
+	}
+	if q.MinYieldBps != nil {
+		db = db.Where("imbal_hasil_bps >= ?", *q.MinYieldBps)
+	}
+
+	direction := "ASC"
+	if q.Descending {
+		direction = "DESC"
+	}
+	switch q.Sort {
+	case sukukSortImbalHasil:
+		db = db.Order("imbal_hasil_bps " + direction + " NULLS LAST").Order("id")
+	case sukukSortJatuhTempo:
+		db = db.Order("jatuh_tempo " + direction).Order("id")
+	case sukukSortNewest:
+		db = db.Order("created_at " + direction).Order("id " + direction)
+	case sukukSortMostSubscribed:
+		db = db.Order("id") // Purchase totals live in the indexer; sortByPurchaseTotals orders the list
+	}
+	return db
+}
+
+// PurchaseTotalsReader resolves the purchase totals of a set of sukuk
+type PurchaseTotalsReader interface {
+	GetPurchaseTotalsBySukuk(ctx context.Context, sukukAddresses []string) (map[string]string, error)
+}
+
+// sortByPurchaseTotals orders responses by their cached purchase totals, keeping the id order
+// between equal totals
+func sortByPurchaseTotals(ctx context.Context, reader PurchaseTotalsReader, responses []models.SukukMetadataListResponse, descending bool) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	addresses := make([]string, len(responses))
+	for i, response := range responses {
+		addresses[i] = response.ContractAddress
+	}
+
+	totals, _, err := cache.Fetch(ctx, cache.SukukPurchaseTotalsKey(addresses), cacheTTL(services.SettingCacheActivitiesTTL, cache.ActivitiesTTL), func() (map[string]string, error) {
+		return reader.GetPurchaseTotalsBySukuk(ctx, addresses)
+	})
+	if err != nil {
+		return err
+	}
+
+	amounts := make(map[uint]*big.Int, len(responses))
+	for _, response := range responses {
+		amount, ok := new(big.Int).SetString(totals[strings.ToLower(response.ContractAddress)], 10)
+		if !ok {
+			amount = new(big.Int)
+		}
+		amounts[response.ID] = amount
+	}
+	sort.SliceStable(responses, func(i, j int) bool {
+		cmp := amounts[responses[i].ID].Cmp(amounts[responses[j].ID])
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	return nil
+}
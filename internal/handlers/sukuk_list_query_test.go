@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func listQueryContext(rawQuery string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/sukuk-metadata?"+rawQuery, nil)
+	return c
+}
+
+func TestParseSukukListQueryRejectsInvalidValues(t *testing.T) {
+	for _, rawQuery := range []string{
+		"sort=price",
+		"sort=newest&order=up",
+		"order=asc",
+		"status=active,closed",
+		"min_tenor=lama",
+		"min_tenor=5 tahun&max_tenor=2 tahun",
+		"maturity_before=10-06-2030",
+		"min_yield=tinggi",
+	} {
+		if _, err := parseSukukListQuery(listQueryContext(strings.ReplaceAll(rawQuery, " ", "+"))); err == nil {
+			t.Errorf("%s: expected an error", rawQuery)
+		}
+	}
+}
+
+func TestListSukukMetadataRejectsInvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata", ListSukukMetadata)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sukuk-metadata?sort=price", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "imbal_hasil") {
+		t.Errorf("Expected 400 naming the sort keys, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSukukListQueryCombinesFiltersWithSort(t *testing.T) {
+	query, err := parseSukukListQuery(listQueryContext(
+		"sort=imbal_hasil&order=asc&status=active,paused&tipe_kupon=Fixed+Rate&min_tenor=2&max_tenor=60+bulan" +
+			"&maturity_after=2027-01-01&maturity_before=2031-01-01T00:00:00Z&min_yield=6,25"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Descending || *query.MinTenorMonths != 24 || *query.MaxTenorMonths != 60 || *query.MinYieldBps != 625 {
+		t.Errorf("Unexpected parsed query: %+v", query)
+	}
+
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sukuk []models.SukukMetadata
+	stmt := query.apply(db.Model(&models.SukukMetadata{})).Find(&sukuk).Statement
+	sql := stmt.SQL.String()
+	for _, clause := range []string{
+		"status IN ($1,$2)",
+		"LOWER(tipe_kupon) = LOWER($3)",
+		"tenor_months >= $4",
+		"tenor_months <= $5",
+		"jatuh_tempo < $6",
+		"jatuh_tempo >= $7",
+		"imbal_hasil_bps >= $8",
+		"ORDER BY imbal_hasil_bps ASC NULLS LAST,id",
+	} {
+		if !strings.Contains(sql, clause) {
+			t.Errorf("Expected %q in %s", clause, sql)
+		}
+	}
+	if len(stmt.Vars) != 8 || stmt.Vars[3] != 24 || stmt.Vars[7] != 625 {
+		t.Errorf("Unexpected bind values %v", stmt.Vars)
+	}
+}
+
+func TestSukukListQueryDefaultOrders(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"":                      "",
+		"sort=imbal_hasil":      "ORDER BY imbal_hasil_bps DESC NULLS LAST,id",
+		"sort=jatuh_tempo":      "ORDER BY jatuh_tempo ASC,id",
+		"sort=newest":           "ORDER BY created_at DESC,id DESC",
+		"sort=newest&order=asc": "ORDER BY created_at ASC,id ASC",
+		"sort=most_subscribed":  "ORDER BY id",
+	}
+	for rawQuery, want := range tests {
+		query, err := parseSukukListQuery(listQueryContext(rawQuery))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", rawQuery, err)
+		}
+		var sukuk []models.SukukMetadata
+		sql := query.apply(db.Model(&models.SukukMetadata{})).Find(&sukuk).Statement.SQL.String()
+		if want == "" && strings.Contains(sql, "ORDER BY") || want != "" && !strings.HasSuffix(sql, want) {
+			t.Errorf("%q: expected the SQL to end in %q, got %s", rawQuery, want, sql)
+		}
+	}
+}
+
+func TestSukukListQueryCacheKey(t *testing.T) {
+	a, _ := parseSukukListQuery(listQueryContext("status=paused,active&tipe_kupon=Fixed+Rate&min_tenor=2"))
+	b, _ := parseSukukListQuery(listQueryContext("min_tenor=24+bulan&tipe_kupon=fixed+rate&status=active,paused"))
+	if a.cacheKey() != b.cacheKey() {
+		t.Errorf("Expected equivalent queries to share a cache key, got %q and %q", a.cacheKey(), b.cacheKey())
+	}
+	c, _ := parseSukukListQuery(listQueryContext("status=active"))
+	if c.cacheKey() == a.cacheKey() {
+		t.Error("Expected different filters to get different cache keys")
+	}
+	if empty, _ := parseSukukListQuery(listQueryContext("")); empty.cacheKey() != "" {
+		t.Errorf("Expected no cache key without parameters, got %q", empty.cacheKey())
+	}
+}
+
+type fakePurchaseTotalsReader struct {
+	calls  int
+	totals map[string]string
+}
+
+func (r *fakePurchaseTotalsReader) GetPurchaseTotalsBySukuk(ctx context.Context, sukukAddresses []string) (map[string]string, error) {
+	r.calls++
+	return r.totals, nil
+}
+
+func TestSortByPurchaseTotals(t *testing.T) {
+	cache.SetDefault(cache.NewMemoryCache())
+	responses := []models.SukukMetadataListResponse{
+		{ID: 1, ContractAddress: "0x00000000000000000000000000000000000000E1"},
+		{ID: 2, ContractAddress: "0x00000000000000000000000000000000000000e2"},
+		{ID: 3, ContractAddress: "0x00000000000000000000000000000000000000e3"},
+		{ID: 4, ContractAddress: "0x00000000000000000000000000000000000000e4"},
+	}
+	reader := &fakePurchaseTotalsReader{totals: map[string]string{
+		"0x00000000000000000000000000000000000000e1": "900000000000000000000",
+		"0x00000000000000000000000000000000000000e2": "25000000000000000000000",
+		"0x00000000000000000000000000000000000000e3": "900000000000000000000",
+	}}
+
+	if err := sortByPurchaseTotals(context.Background(), reader, responses, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := []uint{responses[0].ID, responses[1].ID, responses[2].ID, responses[3].ID}; got[0] != 2 || got[1] != 1 || got[2] != 3 || got[3] != 4 {
+		t.Errorf("Expected most subscribed first with ties in id order, got %v", got)
+	}
+
+	// The totals of the same set of sukuk are served from the cache
+	if err := sortByPurchaseTotals(context.Background(), reader, responses, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if responses[0].ID != 4 || responses[3].ID != 2 || reader.calls != 1 {
+		t.Errorf("Expected least subscribed first from one lookup, got %+v after %d calls", responses, reader.calls)
+	}
+}
+
+// TestBuildSukukMetadataListSortsAndFilters requires a reachable Postgres, see TestUpdateSukukMetadataLostUpdate
+func TestBuildSukukMetadataListSortsAndFilters(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	// A coupon type of its own keeps other rows of the test database out of the list
+	const tipeKupon = "Sort Test Rate"
+	maturity := func(year int) time.Time { return time.Date(year, 6, 10, 0, 0, 0, 0, time.UTC) }
+	fixtures := []models.SukukMetadata{
+		{SukukCode: "SORT-1", Tenor: "2 Tahun", ImbalHasil: "6.10% / Tahun", JatuhTempo: maturity(2027), Status: models.SukukStatusActive},
+		{SukukCode: "SORT-2", Tenor: "5 Tahun", ImbalHasil: "6.55% / Tahun", JatuhTempo: maturity(2030), Status: models.SukukStatusActive},
+		{SukukCode: "SORT-3", Tenor: "36 bulan", ImbalHasil: "6,40%", JatuhTempo: maturity(2028), Status: models.SukukStatusPaused},
+		{SukukCode: "SORT-4", Tenor: "3 Tahun", ImbalHasil: "Mengambang", JatuhTempo: maturity(2028), Status: models.SukukStatusActive},
+		{SukukCode: "SORT-5", Tenor: "10 Tahun", ImbalHasil: "7.00%", JatuhTempo: maturity(2035), Status: models.SukukStatusActive},
+		{SukukCode: "SORT-6", Tenor: "3 Tahun", ImbalHasil: "6.80%", JatuhTempo: maturity(2028), Status: models.SukukStatusDraft},
+	}
+	for i := range fixtures {
+		fixtures[i].ContractAddress = "0x00000000000000000000000000000000000050" + string(rune('a'+i)) + "0"
+		fixtures[i].TipeKupon = tipeKupon
+		db.Unscoped().Where("contract_address = ?", fixtures[i].ContractAddress).Delete(&models.SukukMetadata{})
+		if err := db.Create(&fixtures[i]).Error; err != nil {
+			t.Fatalf("Failed to create %s: %v", fixtures[i].SukukCode, err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, sukuk := range fixtures {
+			db.Unscoped().Delete(&models.SukukMetadata{}, sukuk.ID)
+		}
+	})
+
+	codes := func(rawQuery string) []string {
+		t.Helper()
+		query, err := parseSukukListQuery(listQueryContext("tipe_kupon=sort+test+rate&" + rawQuery))
+		if err != nil {
+			t.Fatalf("%s: %v", rawQuery, err)
+		}
+		responses, err := buildSukukMetadataList(context.Background(), "all", false, 10, models.DefaultLocale, query)
+		if err != nil {
+			t.Fatalf("%s: %v", rawQuery, err)
+		}
+		result := make([]string, len(responses))
+		for i, response := range responses {
+			result[i] = response.SukukCode
+		}
+		return result
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"sort=imbal_hasil", "SORT-5,SORT-6,SORT-2,SORT-3,SORT-1,SORT-4"},
+		{"sort=imbal_hasil&status=active,paused&min_tenor=3&max_tenor=5+tahun", "SORT-2,SORT-3,SORT-4"},
+		{"sort=jatuh_tempo&order=desc&status=active&min_yield=6.5", "SORT-5,SORT-2"},
+		{"sort=jatuh_tempo&maturity_after=2028-01-01&maturity_before=2031-01-01&min_yield=6", "SORT-3,SORT-6,SORT-2"},
+		{"sort=imbal_hasil&order=asc&max_tenor=36+bulan&min_yield=6.2", "SORT-3,SORT-6"},
+		{"status=draft", "SORT-6"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(codes(tt.query), ","); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.query, tt.want, got)
+		}
+	}
+}
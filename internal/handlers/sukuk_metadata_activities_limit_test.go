@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// countIndexerStatements serves target against a stub database holding one sukuk and returns
+// the response with the number of statements gorm ran against the indexer
+func countIndexerStatements(t *testing.T, target string) (*httptest.ResponseRecorder, int64) {
+	t.Helper()
+	db := openStubDB(t, oneSukukMetadata)
+
+	var statements int64
+	counter := func(db *gorm.DB) {
+		if readsIndexer([]string{db.Statement.SQL.String()}) || strings.Contains(db.Statement.Table, "__") {
+			atomic.AddInt64(&statements, 1)
+		}
+	}
+	db.Callback().Query().After("gorm:query").Register("test:count_indexer_query", counter)
+	db.Callback().Row().After("gorm:row").Register("test:count_indexer_row", counter)
+	db.Callback().Raw().After("gorm:raw").Register("test:count_indexer_raw", counter)
+
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	return routeSukukMetadata(target), statements
+}
+
+func TestSukukMetadataActivitiesLimitZeroSkipsIndexer(t *testing.T) {
+	for _, target := range []string{"/sukuk-metadata?include_stats=false&activities_limit=0", "/sukuk-metadata/1?include_stats=false&activities_limit=0"} {
+		w, statements := countIndexerStatements(t, target)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", target, w.Code, w.Body.String())
+		}
+		if statements != 0 {
+			t.Errorf("%s: expected no indexer statements, got %d", target, statements)
+		}
+		if !strings.Contains(w.Body.String(), `"latest_activities":[]`) {
+			t.Errorf("%s: expected empty latest_activities, got %s", target, w.Body.String())
+		}
+	}
+
+	// Any other limit still looks activities up
+	for _, target := range []string{"/sukuk-metadata?include_stats=false&activities_limit=5", "/sukuk-metadata/1?include_stats=false"} {
+		if _, statements := countIndexerStatements(t, target); statements == 0 {
+			t.Errorf("%s: expected the indexer to be read", target)
+		}
+	}
+}
+
+func TestSukukMetadataRejectsActivitiesLimitOutOfRange(t *testing.T) {
+	for _, target := range []string{
+		"/sukuk-metadata?activities_limit=51",
+		"/sukuk-metadata?activities_limit=-1",
+		"/sukuk-metadata/1?activities_limit=1000",
+		"/sukuk-metadata/1?activities_limit=ten",
+	} {
+		w, statements := countIndexerStatements(t, target)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d: %s", target, w.Code, w.Body.String())
+		}
+		if statements != 0 {
+			t.Errorf("%s: expected no indexer statements, got %d", target, statements)
+		}
+	}
+}
+
+func TestRequestActivitiesLimit(t *testing.T) {
+	tests := []struct {
+		setting string // Stored sukuk_metadata.activities_limit, if any
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{"", "", services.DefaultLatestActivitiesLimit, false},
+		{"25", "", 25, false},
+		{"80", "", services.MaxLatestActivitiesLimit, false}, // The setting is capped too
+		{"25", "0", 0, false},
+		{"25", "50", 50, false},
+		{"", "51", 0, true},
+		{"", "-1", 0, true},
+		{"", "5.5", 0, true},
+	}
+	defer services.SetDefaultSettings(services.Settings())
+	for _, tt := range tests {
+		settings := services.NewSettingsService(openStubDB(t, func(query string) stubResult {
+			if tt.setting == "" || !strings.Contains(query, "system_states") {
+				return stubResult{}
+			}
+			return stubResult{
+				columns: []string{"key", "value"},
+				rows:    [][]driver.Value{{"setting:" + services.SettingSukukActivitiesLimit, tt.setting}},
+			}
+		}), 0)
+		if err := settings.Reload(context.Background()); err != nil {
+			t.Fatalf("Failed to load settings: %v", err)
+		}
+		services.SetDefaultSettings(settings)
+
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/sukuk-metadata?activities_limit="+tt.query, nil)
+		if tt.query == "" {
+			c.Request = httptest.NewRequest(http.MethodGet, "/sukuk-metadata", nil)
+		}
+		got, err := requestActivitiesLimit(c)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("setting %q, query %q: expected %d (error %v), got %d (%v)", tt.setting, tt.query, tt.want, tt.wantErr, got, err)
+		}
+	}
+
+	// Without the setting, ACTIVITY_LATEST_LIMIT applies, 0 leaving the activities out
+	defer services.SetLatestActivitiesLimit(services.SetLatestActivitiesLimit(0))
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/sukuk-metadata", nil)
+	if got, err := requestActivitiesLimit(c); err != nil || got != 0 {
+		t.Errorf("Expected the configured limit of 0, got %d (%v)", got, err)
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSukukMetadataConflicts returns the chain values the metadata sync held back
+// @Summary List sukuk metadata sync conflicts
+// @Description Get the conflicts the metadata sync raised, most recently updated first. The sync only overwrites sukuk_title, sukuk_code, jatuh_tempo and kuota_nasional while they still hold the value it last synced; when an admin has edited one of them and the chain value differs, the admin's value is kept and a conflict records both values until it is resolved. Times are UTC RFC 3339 and amounts are decimals in token units
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Conflict status" Enums(open, resolved, all) default(open)
+// @Param limit query int false "Number of conflicts to return" default(50) minimum(1) maximum(200)
+// @Param offset query int false "Number of conflicts to skip" default(0) minimum(0)
+// @Success 200 {object} models.SukukMetadataConflictListResponse "Sync conflicts"
+// @Failure 400 {object} map[string]string "Invalid status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/conflicts [get]
+func ListSukukMetadataConflicts(c *gin.Context) {
+	var status models.SukukConflictStatus
+	switch raw := c.DefaultQuery("status", string(models.SukukConflictOpen)); raw {
+	case string(models.SukukConflictOpen), string(models.SukukConflictResolved):
+		status = models.SukukConflictStatus(raw)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid status",
+			"details": "status must be open, resolved or all",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	conflicts, total, err := models.ListSukukMetadataConflicts(database.GetDB().WithContext(c.Request.Context()), status, limit, offset)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch sync conflicts")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to fetch sync conflicts",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.SukukMetadataConflictListResponse{Conflicts: conflicts, TotalCount: total})
+}
+
+// ResolveSukukMetadataConflict settles a sync conflict with the chain or the manual value
+// @Summary Resolve a sukuk metadata sync conflict
+// @Description Settle an open conflict. Choosing chain writes the chain value into the field, bumping the sukuk's version, and the sync keeps the field up to date again from then on. Choosing manual keeps the admin's current value; the same chain value isn't raised again, but a later different one is
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Conflict ID"
+// @Param request body models.SukukConflictResolveRequest true "Value to keep"
+// @Success 200 {object} models.SukukMetadataConflict "Resolved conflict"
+// @Failure 400 {object} map[string]string "Invalid ID format or request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Conflict not found"
+// @Failure 409 {object} map[string]string "Conflict already resolved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/conflicts/{id}/resolve [post]
+func ResolveSukukMetadataConflict(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID format",
+		})
+		return
+	}
+
+	var req models.SukukConflictResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request payload",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	conflict, err := services.ResolveSukukMetadataConflict(c.Request.Context(), database.GetDB(), uint(id), req.Choice)
+	switch {
+	case errors.Is(err, services.ErrSukukConflictNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Conflict not found",
+		})
+		return
+	case errors.Is(err, services.ErrSukukConflictResolved):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Conflict already resolved",
+		})
+		return
+	case err != nil:
+		logger.WithError(err).Error("Failed to resolve sync conflict")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to resolve sync conflict",
+		})
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"conflict_id": conflict.ID,
+		"sukuk_id":    conflict.SukukMetadataID,
+		"field":       conflict.Field,
+		"choice":      conflict.Resolution,
+	}).Info("Sukuk metadata sync conflict resolved")
+	respondJSON(c, http.StatusOK, conflict)
+}
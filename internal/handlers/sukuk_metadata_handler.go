@@ -1,120 +1,474 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"sukuk-be/internal/cache"
 	"sukuk-be/internal/database"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // ListSukukMetadata returns all sukuk metadata with latest activities
 // @Summary List sukuk metadata with activities
-// @Description Get all sukuk metadata with optional filtering by ready status and latest 10 blockchain activities
+// @Description Get all sukuk metadata with optional filtering by ready status and latest 10 blockchain activities. Suspended sukuk are left out of ready=true listings unless include_suspended=true, and carry their suspension reason. Title, description and term labels are served in the locale from lang or Accept-Language, falling back to the Indonesian base record per field
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
 // @Param ready query string false "Filter by metadata_ready status" Enums(true, false) Example(true)
+// @Param include_suspended query bool false "Keep suspended sukuk in ready=true listings" default(false)
+// @Param lang query string false "Response locale; overrides Accept-Language" Enums(id, en)
+// @Param Accept-Language header string false "Preferred locales, e.g. en-US,en;q=0.9"
+// @Param address query string false "Connected wallet; adds user_balance, user_unclaimed_distribution_count and user_claimable_amount to each item"
+// @Param include_stats query bool false "Add investor_count, first_purchase_at and last_activity_at to each item" default(true)
+// @Param fields query string false "Comma-separated top-level fields to return, e.g. sukuk_code,sukuk_title,imbal_hasil,logo_url. Latest activities, stats and wallet positions are only looked up when one of their fields is selected"
+// @Param activities_limit query int false "Latest activities per sukuk, 0 to 50; 0 skips the lookup. Defaults to the sukuk_metadata.activities_limit setting, then ACTIVITY_LATEST_LIMIT" minimum(0) maximum(50)
+// @Param sort query string false "Sort key; most_subscribed orders by purchase totals" Enums(imbal_hasil, jatuh_tempo, newest, most_subscribed)
+// @Param order query string false "Sort direction; defaults to desc for imbal_hasil, newest and most_subscribed, asc for jatuh_tempo" Enums(asc, desc)
+// @Param status query string false "Comma-separated statuses, e.g. active,paused"
+// @Param tipe_kupon query string false "Coupon type, case-insensitive" Example(Fixed Rate)
+// @Param min_tenor query string false "Minimum tenor; a bare number is in years" Example(2 tahun)
+// @Param max_tenor query string false "Maximum tenor; a bare number is in years" Example(18 bulan)
+// @Param maturity_after query string false "jatuh_tempo on or after (RFC3339 or YYYY-MM-DD)"
+// @Param maturity_before query string false "jatuh_tempo before (RFC3339 or YYYY-MM-DD)"
+// @Param min_yield query number false "Minimum imbal_hasil in percent" Example(6.25)
+// @Param If-None-Match header string false "ETag of a previous response; 304 if the list is unchanged. Ignored with address, and unless activities_limit is 0 or fields leaves out latest_activities"
 // @Success 200 {array} models.SukukMetadataListResponse "List of sukuk metadata with activities"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Unsupported lang, invalid address, sort, filter or fields"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /sukuk-metadata [get]
 func ListSukukMetadata(c *gin.Context) {
-	var sukukMetadata []models.SukukMetadata
-	
+	listSukukMetadata(c, APIV1)
+}
+
+// ListSukukMetadataV2 serves GET /api/v2/sukuk-metadata: the v1 listing wrapped in the
+// paginated envelope, with page and per_page query parameters
+func ListSukukMetadataV2(c *gin.Context) {
+	listSukukMetadata(c, APIV2)
+}
+
+func listSukukMetadata(c *gin.Context, version APIVersion) {
+	page, perPage, err := version.parsePagination(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid pagination", err.Error())
+		return
+	}
+	locale, err := requestLocale(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Unsupported locale", err.Error())
+		return
+	}
+	userAddress, ok := requestUserAddress(c, version)
+	if !ok {
+		return
+	}
+
 	// Check if filtering by ready status
 	readyFilter := c.Query("ready")
-	query := database.GetDB()
+	if readyFilter != "true" && readyFilter != "false" {
+		readyFilter = "all"
+	}
+	includeSuspended := c.Query("include_suspended") == "true"
+	includeStats := c.Query("include_stats") != "false"
+	listQuery, err := parseSukukListQuery(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid list parameters", err.Error())
+		return
+	}
+	fields, err := parseFieldset[models.SukukMetadataListResponse](c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid fields", err.Error())
+		return
+	}
+	activitiesLimit, err := requestActivitiesLimit(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid activities_limit", err.Error())
+		return
+	}
+	if !fields.has("latest_activities") {
+		activitiesLimit = 0
+	}
+	includeStats = includeStats && fields.has(sukukStatsFields...)
+
+	cacheFilter := readyFilter
+	if includeSuspended {
+		cacheFilter += ":include-suspended"
+	}
+	if locale != models.DefaultLocale {
+		cacheFilter += ":" + string(locale)
+	}
+	if key := listQuery.cacheKey(); key != "" {
+		cacheFilter += ":" + key
+	}
+	if activitiesLimit == 0 {
+		cacheFilter += ":no-activities"
+	} else if activitiesLimit != services.DefaultLatestActivitiesLimit {
+		cacheFilter += ":activities=" + strconv.Itoa(activitiesLimit)
+	}
+
+	responses, hit, err := cache.Fetch(c.Request.Context(), cache.SukukMetadataListKey(cacheFilter), cacheTTL(services.SettingCacheMetadataTTL, cache.MetadataTTL), func() ([]models.SukukMetadataListResponse, error) {
+		return buildSukukMetadataList(c.Request.Context(), readyFilter, includeSuspended, activitiesLimit, locale, listQuery)
+	})
+	setCacheStatus(c, hit)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch sukuk metadata")
+		version.respondError(c, queryErrorStatus(c, err), "Failed to fetch sukuk metadata", "")
+		return
+	}
+	if version == APIV1 {
+		dropTermDetails(responses)
+	}
+
+	// Purchase totals move with every purchase, so they are looked up apart from the cached list
+	if listQuery.Sort == sukukSortMostSubscribed {
+		if err := sortByPurchaseTotals(c.Request.Context(), services.NewIndexerQueryService(), responses, listQuery.Descending); err != nil {
+			logger.WithError(err).Error("Failed to fetch purchase totals for sukuk list")
+			version.respondError(c, queryErrorStatus(c, err), "Failed to fetch sukuk metadata", "")
+			return
+		}
+	}
+
+	// Stats are looked up for the requested page only and cached apart from the list, briefly,
+	// so a new purchase shows without waiting for the list to expire
+	if includeStats {
+		pageItems := responses // v1 serves the whole list as one page
+		if version == APIV2 {
+			pageItems, _ = paginate(responses, page, perPage)
+		}
+		if err := addSukukStats(c.Request.Context(), services.NewIndexerQueryService(), pageItems); err != nil {
+			logger.WithError(err).Warn("Failed to fetch activity stats for sukuk list")
+		}
+	}
+
+	// The ETag covers the list as served, stats included, and the parameters that selected it.
+	// Activities change with every purchase and a wallet's position is added per request, so
+	// responses carrying either are not validated
+	if userAddress == "" && activitiesLimit == 0 {
+		etagFilter := cacheFilter
+		if key := fields.key(); key != "" {
+			etagFilter += ":fields=" + key
+		}
+		etag, err := contentETag(version, etagFilter, page, perPage, responses)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to compute sukuk list ETag")
+		} else if notModified(c, etag) {
+			return
+		}
+	}
+
+	// The cached list is shared, so the wallet's position is added after the lookup
+	if userAddress != "" && fields.has(userPositionFields...) {
+		if err := addUserPositions(c.Request.Context(), services.NewIndexerQueryService(), userAddress, responses); err != nil {
+			logger.WithError(err).Warn("Failed to fetch user positions for sukuk list")
+		}
+	}
+
+	if fields != nil {
+		items, err := sparseItems(responses, fields)
+		if err != nil {
+			logger.WithError(err).Error("Failed to select sukuk metadata fields")
+			version.respondError(c, http.StatusInternalServerError, "Failed to fetch sukuk metadata", "")
+			return
+		}
+		respondPage(version, c, items, page, perPage)
+		return
+	}
+	respondPage(version, c, responses, page, perPage)
+}
+
+// Response fields filled by enrichments that are skipped when ?fields= leaves them out
+var (
+	sukukStatsFields   = []string{"investor_count", "first_purchase_at", "last_activity_at"}
+	userPositionFields = []string{"user_balance", "user_unclaimed_distribution_count", "user_claimable_amount"}
+)
+
+// dropTermDetails leaves the structured terms out of v1 responses, which serve only the labels
+func dropTermDetails(responses []models.SukukMetadataListResponse) {
+	for i := range responses {
+		responses[i].DropTermDetails()
+	}
+}
+
+// buildSukukMetadataList loads sukuk metadata matching the ready filter and list query, with up
+// to activitiesLimit latest activities per sukuk; zero skips the indexer. Suspended sukuk are
+// hidden from the ready listing unless includeSuspended is set
+func buildSukukMetadataList(ctx context.Context, readyFilter string, includeSuspended bool, activitiesLimit int, locale models.Locale, listQuery sukukListQuery) ([]models.SukukMetadataListResponse, error) {
+	var sukukMetadata []models.SukukMetadata
+	query := database.GetDB().WithContext(ctx)
 	
 	if readyFilter == "true" {
 		query = query.Where("metadata_ready = ?", true)
+		if !includeSuspended {
+			query = query.Where("status <> ?", models.SukukStatusSuspended)
+		}
 	} else if readyFilter == "false" {
 		query = query.Where("metadata_ready = ?", false)
 	}
 	// If no filter, return all sukuk metadata
+	query = listQuery.apply(query)
 	
-	result := query.Find(&sukukMetadata)
-	if result.Error != nil {
-		logger.WithError(result.Error).Error("Failed to fetch sukuk metadata")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch sukuk metadata",
-		})
-		return
+	if err := query.Find(&sukukMetadata).Error; err != nil {
+		return nil, err
 	}
 
-	// Initialize indexer query service
-	indexerService := services.NewIndexerQueryService()
+	suspensions, err := loadOpenSuspensions(ctx, sukukMetadata...)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, len(sukukMetadata))
+	ids := make([]uint, len(sukukMetadata))
+	for i, sukuk := range sukukMetadata {
+		addresses[i] = sukuk.ContractAddress
+		ids[i] = sukuk.ID
+	}
+
+	translations, err := models.GetTranslations(database.GetDB().WithContext(ctx), locale, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the latest activities for every sukuk in one pass over the indexer
+	var activitiesBySukuk map[string][]models.ActivityEvent
+	if activitiesLimit > 0 {
+		activitiesBySukuk, err = services.NewIndexerQueryService().GetLatestActivitiesBySukuk(ctx, addresses, activitiesLimit)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to fetch activities for sukuk list")
+			activitiesBySukuk = nil // Every sukuk falls back to an empty array
+		}
+	}
 	
 	// Convert to response format with activities
 	responses := make([]models.SukukMetadataListResponse, len(sukukMetadata))
 	for i, sukuk := range sukukMetadata {
-		response := sukuk.ToListResponse()
+		response := sukuk.ToLocalizedListResponse(translations[sukuk.ID])
 		
-		// Get latest 10 activities for this sukuk token directly from indexer
-		activities, err := indexerService.GetLatestActivities(sukuk.ContractAddress, 10)
-		if err != nil {
-			logger.WithError(err).Warn("Failed to fetch activities for sukuk:", sukuk.ContractAddress)
-			activities = make([]models.ActivityEvent, 0) // Set empty array if error
-		}
+		activities := activitiesBySukuk[strings.ToLower(sukuk.ContractAddress)]
 		if activities == nil {
 			activities = make([]models.ActivityEvent, 0) // Ensure it's never null
 		}
 		
 		response.LatestActivities = activities
+		response.Suspension = suspensions[strings.ToLower(sukuk.ContractAddress)]
 		responses[i] = response
 	}
 
-	c.JSON(http.StatusOK, responses)
+	return responses, nil
+}
+
+// SukukPositionReader resolves a wallet's position in a set of sukuk
+type SukukPositionReader interface {
+	GetSukukPositions(ctx context.Context, userAddress string, sukukAddresses []string) (map[string]services.SukukUserPosition, error)
+}
+
+// SukukStatsReader resolves the activity stats of a set of sukuk
+type SukukStatsReader interface {
+	GetSukukActivityStats(ctx context.Context, sukukAddresses []string) (map[string]services.SukukActivityStats, error)
+}
+
+// addSukukStats sets investor count and first/last activity on every response, resolving all
+// of them in a single cached reader call. Sukuk the reader has no stats for count zero investors
+func addSukukStats(ctx context.Context, reader SukukStatsReader, responses []models.SukukMetadataListResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	addresses := make([]string, len(responses))
+	for i, response := range responses {
+		addresses[i] = response.ContractAddress
+	}
+
+	stats, _, err := cache.Fetch(ctx, cache.SukukStatsKey(addresses), cacheTTL(services.SettingCacheActivitiesTTL, cache.ActivitiesTTL), func() (map[string]services.SukukActivityStats, error) {
+		return reader.GetSukukActivityStats(ctx, addresses)
+	})
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		sukukStats := stats[strings.ToLower(responses[i].ContractAddress)]
+		responses[i].InvestorCount = &sukukStats.InvestorCount
+		responses[i].FirstPurchaseAt = sukukStats.FirstPurchaseAt
+		responses[i].LastActivityAt = sukukStats.LastActivityAt
+	}
+	return nil
+}
+
+// requestUserAddress reads the optional ?address= wallet, responding 400 and returning false when it is invalid
+func requestUserAddress(c *gin.Context, version APIVersion) (string, bool) {
+	address := c.Query("address")
+	if address != "" && !utils.IsValidEthereumAddress(address) {
+		version.respondError(c, http.StatusBadRequest, "Invalid address", "address must be a 0x-prefixed Ethereum address")
+		return "", false
+	}
+	return address, true
+}
+
+// requestActivitiesLimit reads the optional ?activities_limit=, from 0 to the maximum, defaulting
+// to the sukuk_metadata.activities_limit setting, then ACTIVITY_LATEST_LIMIT. Zero skips the activity lookup
+func requestActivitiesLimit(c *gin.Context) (int, error) {
+	raw := c.Query("activities_limit")
+	if raw == "" {
+		limit := services.Settings().GetInt(services.SettingSukukActivitiesLimit, services.LatestActivitiesLimit())
+		return min(limit, services.MaxLatestActivitiesLimit), nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 || limit > services.MaxLatestActivitiesLimit {
+		return 0, fmt.Errorf("activities_limit must be an integer from 0 to %d", services.MaxLatestActivitiesLimit)
+	}
+	return limit, nil
+}
+
+// addUserPositions sets the wallet's balance and unclaimed yield on every response,
+// resolving all of them in a single reader call
+func addUserPositions(ctx context.Context, reader SukukPositionReader, userAddress string, responses []models.SukukMetadataListResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	addresses := make([]string, len(responses))
+	for i, response := range responses {
+		addresses[i] = response.ContractAddress
+	}
+
+	positions, err := reader.GetSukukPositions(ctx, userAddress, addresses)
+	if err != nil {
+		return err
+	}
+	for i := range responses {
+		position, ok := positions[strings.ToLower(responses[i].ContractAddress)]
+		if !ok {
+			continue
+		}
+		balance, count, claimable := position.Balance, position.UnclaimedDistributionCount, position.ClaimableAmount
+		responses[i].UserBalance = &balance
+		responses[i].UserUnclaimedDistributionCount = &count
+		responses[i].UserClaimableAmount = &claimable
+	}
+	return nil
+}
+
+// loadOpenSuspensions fetches the current suspension of each suspended sukuk, keyed by lowercase address
+func loadOpenSuspensions(ctx context.Context, sukukMetadata ...models.SukukMetadata) (map[string]*models.SukukSuspension, error) {
+	var addresses []string
+	for _, sukuk := range sukukMetadata {
+		if sukuk.Status == models.SukukStatusSuspended {
+			addresses = append(addresses, sukuk.ContractAddress)
+		}
+	}
+
+	suspensions, err := models.GetOpenSuspensions(database.GetDB().WithContext(ctx), addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*models.SukukSuspension, len(suspensions))
+	for address, suspension := range suspensions {
+		result[address] = &suspension
+	}
+	return result, nil
 }
 
 // GetSukukMetadata returns a single sukuk metadata by ID with latest activities
 // @Summary Get sukuk metadata by ID
-// @Description Get a single sukuk metadata by ID with latest 10 blockchain activities
+// @Description Get a single sukuk metadata by ID with latest 10 blockchain activities, localized like the listing
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
 // @Param id path integer true "Sukuk metadata ID"
+// @Param lang query string false "Response locale; overrides Accept-Language" Enums(id, en)
+// @Param Accept-Language header string false "Preferred locales, e.g. en-US,en;q=0.9"
+// @Param address query string false "Connected wallet; adds user_balance, user_unclaimed_distribution_count and user_claimable_amount"
+// @Param include_stats query bool false "Add investor_count, first_purchase_at and last_activity_at" default(true)
+// @Param fields query string false "Comma-separated top-level fields to return, e.g. sukuk_code,sukuk_title,imbal_hasil,logo_url. Latest activities, stats and wallet positions are only looked up when one of their fields is selected"
+// @Param activities_limit query int false "Latest activities per sukuk, 0 to 50; 0 skips the lookup. Defaults to the sukuk_metadata.activities_limit setting, then ACTIVITY_LATEST_LIMIT" minimum(0) maximum(50)
+// @Param If-None-Match header string false "ETag of a previous response, the record's version; 304 if the record is unchanged. Ignored when the response carries latest activities, stats or wallet positions"
 // @Success 200 {object} models.SukukMetadataListResponse "Sukuk metadata with activities"
-// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string "Invalid ID format, unsupported lang, invalid address or fields"
 // @Failure 404 {object} map[string]string "Sukuk metadata not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /sukuk-metadata/{id} [get]
 func GetSukukMetadata(c *gin.Context) {
+	getSukukMetadata(c, APIV1)
+}
+
+// GetSukukMetadataV2 serves GET /api/v2/sukuk-metadata/:id: the v1 detail wrapped in the response envelope
+func GetSukukMetadataV2(c *gin.Context) {
+	getSukukMetadata(c, APIV2)
+}
+
+func getSukukMetadata(c *gin.Context, version APIVersion) {
 	// Get ID from path
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid ID format",
-		})
+		version.respondError(c, http.StatusBadRequest, "Invalid ID format", "")
+		return
+	}
+	locale, err := requestLocale(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Unsupported locale", err.Error())
+		return
+	}
+	userAddress, ok := requestUserAddress(c, version)
+	if !ok {
+		return
+	}
+	fields, err := parseFieldset[models.SukukMetadataListResponse](c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid fields", err.Error())
+		return
+	}
+	activitiesLimit, err := requestActivitiesLimit(c)
+	if err != nil {
+		version.respondError(c, http.StatusBadRequest, "Invalid activities_limit", err.Error())
 		return
 	}
 
 	// Find sukuk metadata
 	var sukukMetadata models.SukukMetadata
-	result := database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
+	result := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("Failed to fetch sukuk metadata")
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Sukuk metadata not found",
-		})
+		version.respondError(c, http.StatusNotFound, "Sukuk metadata not found", "")
 		return
 	}
 
 	// Initialize indexer query service
 	indexerService := services.NewIndexerQueryService()
 	
+	translations, err := models.GetTranslations(database.GetDB().WithContext(c.Request.Context()), locale, sukukMetadata.ID)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to fetch translations for sukuk:", sukukMetadata.ContractAddress)
+	}
+
 	// Convert to response format with activities
-	response := sukukMetadata.ToListResponse()
+	response := sukukMetadata.ToLocalizedListResponse(translations[sukukMetadata.ID])
+	if version == APIV1 {
+		response.DropTermDetails()
+	}
 	
-	// Get latest 10 activities for this sukuk token directly from indexer
-	activities, err := indexerService.GetLatestActivities(sukukMetadata.ContractAddress, 10)
-	if err != nil {
-		logger.WithError(err).Warn("Failed to fetch activities for sukuk:", sukukMetadata.ContractAddress)
-		activities = make([]models.ActivityEvent, 0) // Set empty array if error
+	// Get the latest activities for this sukuk token directly from indexer
+	var activities []models.ActivityEvent
+	// Activities, stats and wallet positions change without a new record version
+	enriched := false
+	if activitiesLimit > 0 && fields.has("latest_activities") {
+		enriched = true
+		activities, err = indexerService.GetLatestActivities(c.Request.Context(), sukukMetadata.ContractAddress, activitiesLimit)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to fetch activities for sukuk:", sukukMetadata.ContractAddress)
+			activities = make([]models.ActivityEvent, 0) // Set empty array if error
+		}
 	}
 	if activities == nil {
 		activities = make([]models.ActivityEvent, 0) // Ensure it's never null
@@ -122,18 +476,167 @@ func GetSukukMetadata(c *gin.Context) {
 	
 	response.LatestActivities = activities
 
-	c.JSON(http.StatusOK, response)
+	suspensions, err := loadOpenSuspensions(c.Request.Context(), sukukMetadata)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to fetch suspension for sukuk:", sukukMetadata.ContractAddress)
+	}
+	response.Suspension = suspensions[strings.ToLower(sukukMetadata.ContractAddress)]
+
+	if c.Query("include_stats") != "false" && fields.has(sukukStatsFields...) {
+		enriched = true
+		responses := []models.SukukMetadataListResponse{response}
+		if err := addSukukStats(c.Request.Context(), indexerService, responses); err != nil {
+			logger.WithError(err).Warn("Failed to fetch activity stats for sukuk:", sukukMetadata.ContractAddress)
+		}
+		response = responses[0]
+	}
+
+	if userAddress != "" && fields.has(userPositionFields...) {
+		enriched = true
+		responses := []models.SukukMetadataListResponse{response}
+		if err := addUserPositions(c.Request.Context(), indexerService, userAddress, responses); err != nil {
+			logger.WithError(err).Warn("Failed to fetch user position for sukuk:", sukukMetadata.ContractAddress)
+		}
+		response = responses[0]
+	}
+
+	var body interface{} = response
+	if fields != nil {
+		item, err := sparseItem(response, fields)
+		if err != nil {
+			logger.WithError(err).Error("Failed to select sukuk metadata fields")
+			version.respondError(c, http.StatusInternalServerError, "Failed to fetch sukuk metadata", "")
+			return
+		}
+		body = item
+	}
+
+	// The ETag is the record's version, as on writes, so it can be sent back as If-Match.
+	// Enriched responses may change at the same version and are never answered 304
+	etag := versionETag(sukukMetadata.Version)
+	if enriched {
+		c.Header("ETag", etag)
+	} else if notModified(c, etag) {
+		return
+	}
+	version.respond(c, http.StatusOK, body)
+}
+
+// GetSukukTimeSeries returns cumulative investment and outstanding supply over time
+// @Summary Get sukuk supply and investment time-series
+// @Description Aggregate purchases and approved redemptions for a sukuk into day or week buckets. Amounts are wei strings with humanized counterparts; empty buckets are zero-filled. At most 400 buckets per request
+// @Tags sukuk-metadata
+// @Accept json
+// @Produce json
+// @Param id path integer true "Sukuk metadata ID"
+// @Param interval query string false "Bucket width" Enums(day, week) default(day)
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD), defaults to 30 buckets before 'to'"
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD), defaults to now"
+// @Success 200 {object} models.SukukTimeSeriesResponse "Time-series buckets"
+// @Failure 400 {object} map[string]string "Invalid parameters or range exceeds 400 buckets"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /sukuk-metadata/{id}/timeseries [get]
+func GetSukukTimeSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID format",
+		})
+		return
+	}
+
+	interval := services.TimeSeriesInterval(c.DefaultQuery("interval", string(services.TimeSeriesDay)))
+	if !interval.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": services.ErrInvalidInterval.Error(),
+		})
+		return
+	}
+
+	to := time.Now().UTC()
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = parseTimeSeriesDate(toStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid 'to' date",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	// Default to the 30 most recent buckets
+	from := to.AddDate(0, 0, -29*interval.Days())
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = parseTimeSeriesDate(fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid 'from' date",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'from' must be before 'to'",
+		})
+		return
+	}
+
+	var sukukMetadata models.SukukMetadata
+	if err := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sukuk metadata not found",
+		})
+		return
+	}
+
+	indexerService := services.NewIndexerQueryService()
+	points, err := indexerService.GetSukukTimeSeries(c.Request.Context(), sukukMetadata.ContractAddress, interval, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyBuckets) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		logger.WithError(err).Error("Failed to build sukuk time-series")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error":   "Failed to build time-series",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.SukukTimeSeriesResponse{
+		SukukID:         sukukMetadata.ID,
+		ContractAddress: sukukMetadata.ContractAddress,
+		Interval:        string(interval),
+		From:            from,
+		To:              to,
+		Points:          points,
+	})
+}
+
+// parseTimeSeriesDate accepts RFC3339 timestamps or plain YYYY-MM-DD dates
+func parseTimeSeriesDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
 }
 
 // CreateSukukMetadata creates new sukuk metadata
 // @Summary Create sukuk metadata
-// @Description Create new sukuk with onchain and offchain metadata
+// @Description Create new sukuk with onchain and offchain metadata. Dates that are set must be in order: purchase period start before end, first coupon after the purchase period, maturity after the first coupon, and tanggal_bayar_kupon matching the penerimaan_kupon cadence
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
 // @Param sukuk body models.SukukMetadataCreateRequest true "Sukuk metadata"
 // @Success 201 {object} models.SukukMetadataResponse
 // @Failure 400 {object} map[string]string
+// @Failure 422 {object} map[string]interface{} "Unknown status, or dates out of order; violations names each broken constraint"
 // @Failure 500 {object} map[string]string
 // @Router /sukuk-metadata [post]
 func CreateSukukMetadata(c *gin.Context) {
@@ -149,6 +652,23 @@ func CreateSukukMetadata(c *gin.Context) {
 		return
 	}
 
+	// New sukuk start as draft unless a valid lifecycle status is given
+	if req.Status == "" {
+		req.Status = models.SukukStatusDraft
+	}
+	if !req.Status.IsValid() || req.Status.IsEventDriven() {
+		details := fmt.Sprintf("Unknown status: %s", req.Status)
+		if req.Status.IsEventDriven() {
+			details = fmt.Sprintf("Status %s is only set by onchain events", req.Status)
+		}
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "Invalid sukuk status",
+			"details":          details,
+			"allowed_statuses": models.ManualSukukStatuses(),
+		})
+		return
+	}
+
 	// Create sukuk metadata model
 	sukukMetadata := models.SukukMetadata{
 		// Onchain Data
@@ -166,17 +686,17 @@ func CreateSukukMetadata(c *gin.Context) {
 		LogoURL:        req.LogoURL,
 		
 		// Main Features
-		Tenor:       req.Tenor,
-		ImbalHasil:  req.ImbalHasil,
+		Tenor:       string(req.Tenor),
+		ImbalHasil:  string(req.ImbalHasil),
 		
 		// Ketentuan
-		PeriodePembelian:  req.PeriodePembelian,
+		PeriodePembelian:  string(req.PeriodePembelian),
 		JatuhTempo:        req.JatuhTempo,
-		KuotaNasional:     req.KuotaNasional,
+		KuotaNasional:     req.KuotaNasional.Decimal(),
 		PenerimaanKupon:   req.PenerimaanKupon,
-		MinimumPembelian:  req.MinimumPembelian,
+		MinimumPembelian:  req.MinimumPembelian.Float64(),
 		TanggalBayarKupon: req.TanggalBayarKupon,
-		MaksimumPembelian: req.MaksimumPembelian,
+		MaksimumPembelian: req.MaksimumPembelian.Float64(),
 		KuponPertama:      req.KuponPertama,
 		TipeKupon:         req.TipeKupon,
 		
@@ -184,8 +704,14 @@ func CreateSukukMetadata(c *gin.Context) {
 		MetadataReady: false,
 	}
 
+	// Dates the coupon schedule can't follow are refused; missing ones are left to readiness
+	if violations := services.SukukDateErrors(services.ValidateSukukDates(&sukukMetadata)); len(violations) > 0 {
+		respondSukukDateViolations(c, violations)
+		return
+	}
+
 	// Create in database
-	if err := database.GetDB().Create(&sukukMetadata).Error; err != nil {
+	if err := database.GetDB().WithContext(c.Request.Context()).Create(&sukukMetadata).Error; err != nil {
 		logger.WithError(err).Error("Failed to create sukuk metadata")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to create sukuk metadata",
@@ -198,12 +724,14 @@ func CreateSukukMetadata(c *gin.Context) {
 		"id":         sukukMetadata.ID,
 	}).Info("Sukuk metadata created successfully")
 
+	cache.InvalidateSukukMetadata(c.Request.Context())
+
 	c.JSON(http.StatusCreated, sukukMetadata.ToResponse())
 }
 
 // MarkSukukMetadataReady marks sukuk metadata as ready for public display
 // @Summary Mark sukuk metadata as ready
-// @Description Mark sukuk metadata as ready for public display. Only sukuk with metadata_ready=true will appear in filtered API responses. Use this after adding all required offchain metadata.
+// @Description Mark sukuk metadata as ready for public display. Only sukuk with metadata_ready=true will appear in filtered API responses. The record must pass the readiness checklist (required fields filled in, purchase period in order and before the first coupon before maturity, coupon day matching the coupon cadence, logo_url resolvable); otherwise 422 lists the failing checks. Use POST /admin/sukuk-metadata/{id}/preview to check an edit first.
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
@@ -211,48 +739,65 @@ func CreateSukukMetadata(c *gin.Context) {
 // @Success 200 {object} models.SukukMetadataResponse "Sukuk metadata marked as ready"
 // @Failure 400 {object} map[string]string "Invalid ID format"
 // @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 422 {object} map[string]interface{} "Readiness checks failed; body lists failures"
 // @Failure 500 {object} map[string]string "Failed to update sukuk metadata"
 // @Router /sukuk-metadata/{id}/ready [put]
-func MarkSukukMetadataReady(c *gin.Context) {
-	// Get ID from path
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid ID format",
-		})
-		return
-	}
+func MarkSukukMetadataReady(checker *services.SukukReadinessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get ID from path
+		idStr := c.Param("id")
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid ID format",
+			})
+			return
+		}
 
-	// Find sukuk metadata
-	var sukukMetadata models.SukukMetadata
-	result := database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
-	if result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Sukuk metadata not found",
-		})
-		return
-	}
+		// Find sukuk metadata
+		var sukukMetadata models.SukukMetadata
+		result := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
+		if result.Error != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Sukuk metadata not found",
+			})
+			return
+		}
 
-	// Update metadata_ready flag
-	result = database.GetDB().Model(&sukukMetadata).Update("metadata_ready", true)
-	if result.Error != nil {
-		logger.WithError(result.Error).Error("Failed to update sukuk metadata")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to update sukuk metadata",
+		if report := checker.Check(c.Request.Context(), &sukukMetadata); !report.Ready {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":    "Sukuk metadata is not ready for public display",
+				"failures": report.Failures,
+			})
+			return
+		}
+
+		// Update metadata_ready flag
+		result = database.GetDB().WithContext(c.Request.Context()).Model(&sukukMetadata).Updates(map[string]interface{}{
+			"metadata_ready": true,
+			"version":        gorm.Expr("version + 1"),
 		})
-		return
-	}
+		if result.Error != nil {
+			logger.WithError(result.Error).Error("Failed to update sukuk metadata")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to update sukuk metadata",
+			})
+			return
+		}
 
-	// Reload the updated model
-	database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
+		// Reload the updated model
+		database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
 
-	logger.WithFields(map[string]interface{}{
-		"sukuk_code": sukukMetadata.SukukCode,
-		"id":         sukukMetadata.ID,
-	}).Info("Sukuk metadata marked as ready")
+		logger.WithFields(map[string]interface{}{
+			"sukuk_code": sukukMetadata.SukukCode,
+			"id":         sukukMetadata.ID,
+		}).Info("Sukuk metadata marked as ready")
+
+		cache.InvalidateSukukMetadata(c.Request.Context())
 
-	c.JSON(http.StatusOK, sukukMetadata.ToResponse())
+		c.Header("ETag", versionETag(sukukMetadata.Version))
+		respondJSON(c, http.StatusOK, sukukMetadata.ToResponse())
+	}
 }
 
 // MarkSukukMetadataUnready marks sukuk metadata as unready (not ready for public display)
@@ -280,7 +825,7 @@ func MarkSukukMetadataUnready(c *gin.Context) {
 
 	// Find sukuk metadata
 	var sukukMetadata models.SukukMetadata
-	result := database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
+	result := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Sukuk metadata not found",
@@ -289,7 +834,10 @@ func MarkSukukMetadataUnready(c *gin.Context) {
 	}
 
 	// Update metadata_ready flag to false
-	result = database.GetDB().Model(&sukukMetadata).Update("metadata_ready", false)
+	result = database.GetDB().WithContext(c.Request.Context()).Model(&sukukMetadata).Updates(map[string]interface{}{
+		"metadata_ready": false,
+		"version":        gorm.Expr("version + 1"),
+	})
 	if result.Error != nil {
 		logger.WithError(result.Error).Error("Failed to update sukuk metadata")
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -299,27 +847,34 @@ func MarkSukukMetadataUnready(c *gin.Context) {
 	}
 
 	// Reload the updated model
-	database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
+	database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
 
 	logger.WithFields(map[string]interface{}{
 		"sukuk_code": sukukMetadata.SukukCode,
 		"id":         sukukMetadata.ID,
 	}).Info("Sukuk metadata marked as unready")
 
-	c.JSON(http.StatusOK, sukukMetadata.ToResponse())
+	cache.InvalidateSukukMetadata(c.Request.Context())
+
+	c.Header("ETag", versionETag(sukukMetadata.Version))
+	respondJSON(c, http.StatusOK, sukukMetadata.ToResponse())
 }
 
 // UpdateSukukMetadata updates sukuk metadata with offchain data
 // @Summary Update sukuk metadata with offchain business data
-// @Description Update existing sukuk metadata with offchain business information like tenor, imbal hasil, kuota nasional, etc. All fields are optional for partial updates. Onchain data (contract address, transaction hash, etc.) is preserved.
+// @Description Update existing sukuk metadata with offchain business information like tenor, imbal hasil, kuota nasional, etc. All fields are optional for partial updates. Dates must stay in order: purchase period start before end, first coupon after the purchase period, maturity after the first coupon, and tanggal_bayar_kupon matching the penerimaan_kupon cadence. Onchain data (contract address, transaction hash, etc.) is preserved. The version read from GET must be sent as If-Match or a version field; a stale version returns 409 with the current record.
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
 // @Param id path int true "Sukuk metadata ID" Example(36)
+// @Param If-Match header string false "The ETag or version field from the latest GET or write response; required unless the body has a version field"
 // @Param sukuk body models.SukukMetadataUpdateRequest true "Offchain metadata to update"
 // @Success 200 {object} models.SukukMetadataResponse "Updated sukuk metadata with both onchain and offchain data"
 // @Failure 400 {object} map[string]string "Invalid request payload or ID format"
 // @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 409 {object} map[string]interface{} "Version mismatch; body includes the current record"
+// @Failure 422 {object} map[string]interface{} "Status transition not allowed, body lists allowed_statuses; or the edit puts dates out of order, violations names each broken constraint"
+// @Failure 428 {object} map[string]string "Missing If-Match header or version field"
 // @Failure 500 {object} map[string]string "Failed to update sukuk metadata"
 // @Router /sukuk-metadata/{id} [put]
 func UpdateSukukMetadata(c *gin.Context) {
@@ -344,76 +899,136 @@ func UpdateSukukMetadata(c *gin.Context) {
 		return
 	}
 
+	// Require the version the client last read so concurrent edits aren't silently lost
+	expectedVersion, ok, err := requestVersion(c, req.Version)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid version",
+			"details": err.Error(),
+		})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error":   "Version required",
+			"details": "Send the version from the latest GET as an If-Match header or a version field",
+		})
+		return
+	}
+
 	// Find sukuk metadata
 	var sukukMetadata models.SukukMetadata
-	result := database.GetDB().First(&sukukMetadata, "id = ?", uint(id))
+	result := database.GetDB().WithContext(c.Request.Context()).First(&sukukMetadata, "id = ?", uint(id))
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Sukuk metadata not found",
 		})
 		return
 	}
+	if sukukMetadata.Version != expectedVersion {
+		respondVersionConflict(c, &sukukMetadata)
+		return
+	}
 
 	// Update fields if provided
-	if req.SukukTitle != nil {
-		sukukMetadata.SukukTitle = *req.SukukTitle
-	}
-	if req.SukukDeskripsi != nil {
-		sukukMetadata.SukukDeskripsi = *req.SukukDeskripsi
-	}
-	if req.Status != nil {
-		sukukMetadata.Status = *req.Status
-	}
-	if req.LogoURL != nil {
-		sukukMetadata.LogoURL = *req.LogoURL
-	}
-	if req.Tenor != nil {
-		sukukMetadata.Tenor = *req.Tenor
-	}
-	if req.ImbalHasil != nil {  
-		sukukMetadata.ImbalHasil = *req.ImbalHasil
-	}
-	if req.PeriodePembelian != nil {
-		sukukMetadata.PeriodePembelian = *req.PeriodePembelian
-	}
-	if req.JatuhTempo != nil {
-		sukukMetadata.JatuhTempo = *req.JatuhTempo
-	}
-	if req.KuotaNasional != nil {
-		sukukMetadata.KuotaNasional = *req.KuotaNasional
-	}
-	if req.PenerimaanKupon != nil {
-		sukukMetadata.PenerimaanKupon = *req.PenerimaanKupon
-	}
-	if req.MinimumPembelian != nil {
-		sukukMetadata.MinimumPembelian = *req.MinimumPembelian
-	}
-	if req.TanggalBayarKupon != nil {
-		sukukMetadata.TanggalBayarKupon = *req.TanggalBayarKupon
-	}
-	if req.MaksimumPembelian != nil {
-		sukukMetadata.MaksimumPembelian = *req.MaksimumPembelian
-	}
-	if req.KuponPertama != nil {
-		sukukMetadata.KuponPertama = *req.KuponPertama
+	stored := sukukMetadata
+	if err := req.Apply(&sukukMetadata); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":            "Invalid status transition",
+			"details":          err.Error(),
+			"allowed_statuses": sukukMetadata.Status.NextStates(),
+		})
+		return
 	}
-	if req.TipeKupon != nil {
-		sukukMetadata.TipeKupon = *req.TipeKupon
+
+	// Refuse dates the edit puts out of order; ones already stored that way are in the validation report
+	if violations := services.NewSukukDateErrors(&stored, &sukukMetadata); len(violations) > 0 {
+		respondSukukDateViolations(c, violations)
+		return
 	}
 
-	// Save updates
-	if err := database.GetDB().Save(&sukukMetadata).Error; err != nil {
-		logger.WithError(err).Error("Failed to update sukuk metadata")
+	// Save updates only if nobody else wrote since the record was read
+	sukukMetadata.Version = expectedVersion + 1
+	result = database.GetDB().WithContext(c.Request.Context()).
+		Model(&sukukMetadata).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Updates(&sukukMetadata)
+	if result.Error != nil {
+		logger.WithError(result.Error).Error("Failed to update sukuk metadata")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to update sukuk metadata",
 		})
 		return
 	}
+	if result.RowsAffected == 0 {
+		var latest models.SukukMetadata
+		if err := database.GetDB().WithContext(c.Request.Context()).First(&latest, "id = ?", uint(id)).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Sukuk metadata not found",
+			})
+			return
+		}
+		respondVersionConflict(c, &latest)
+		return
+	}
 
 	logger.WithFields(map[string]interface{}{
 		"sukuk_code": sukukMetadata.SukukCode,
 		"id":         sukukMetadata.ID,
 	}).Info("Sukuk metadata updated successfully")
 
-	c.JSON(http.StatusOK, sukukMetadata.ToResponse())
-}
\ No newline at end of file
+	cache.InvalidateSukukMetadata(c.Request.Context())
+
+	c.Header("ETag", versionETag(sukukMetadata.Version))
+	respondJSON(c, http.StatusOK, sukukMetadata.ToResponse())
+}
+
+// requestLocale resolves the response locale from the lang query parameter, else Accept-Language
+// It sets Content-Language and Vary so shared caches keep locales apart
+func requestLocale(c *gin.Context) (models.Locale, error) {
+	locale := models.NegotiateLocale(c.GetHeader("Accept-Language"))
+	if lang, ok := c.GetQuery("lang"); ok {
+		parsed, valid := models.ParseLocale(lang)
+		if !valid {
+			return "", fmt.Errorf("lang must be one of %v, got %q", models.Locales(), lang)
+		}
+		locale = parsed
+	}
+
+	c.Header("Content-Language", string(locale))
+	c.Header("Vary", "Accept-Language")
+	return locale, nil
+}
+
+// versionETag formats a record version as a strong ETag
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// requestVersion returns the version the client expects, taken from If-Match
+// ("3", W/"3" or 3) or else the body field; ok is false when neither is present
+func requestVersion(c *gin.Context, bodyVersion *int64) (version int64, ok bool, err error) {
+	if ifMatch := strings.TrimSpace(c.GetHeader("If-Match")); ifMatch != "" {
+		tag := strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+		parsed, err := strconv.ParseInt(tag, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("If-Match must be a version number, got %s", ifMatch)
+		}
+		return parsed, true, nil
+	}
+	if bodyVersion != nil {
+		return *bodyVersion, true, nil
+	}
+	return 0, false, nil
+}
+
+// respondVersionConflict returns 409 with the current record so the client can merge and retry
+func respondVersionConflict(c *gin.Context, current *models.SukukMetadata) {
+	c.Header("ETag", versionETag(current.Version))
+	c.JSON(http.StatusConflict, gin.H{
+		"error":   "Sukuk metadata was modified by another request",
+		"details": fmt.Sprintf("Current version is %d; reload and reapply your changes", current.Version),
+		"current": current.ToResponse(),
+	})
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newMetadataRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/sukuk-metadata/:id", UpdateSukukMetadata)
+	return router
+}
+
+func putMetadata(router *gin.Engine, id uint, ifMatch, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/sukuk-metadata/%d", id), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestVersion(t *testing.T) {
+	bodyVersion := int64(7)
+	tests := []struct {
+		name    string
+		ifMatch string
+		body    *int64
+		want    int64
+		wantOK  bool
+		wantErr bool
+	}{
+		{name: "quoted etag", ifMatch: `"3"`, want: 3, wantOK: true},
+		{name: "weak etag", ifMatch: `W/"4"`, want: 4, wantOK: true},
+		{name: "bare number", ifMatch: "5", want: 5, wantOK: true},
+		{name: "header wins over body", ifMatch: `"3"`, body: &bodyVersion, want: 3, wantOK: true},
+		{name: "body only", body: &bodyVersion, want: 7, wantOK: true},
+		{name: "missing"},
+		{name: "wildcard", ifMatch: "*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			got, ok, err := requestVersion(c, tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Expected (%d, %v), got (%d, %v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestUpdateSukukMetadataRequiresVersion(t *testing.T) {
+	w := putMetadata(newMetadataRouter(), 1, "", `{"sukuk_title":"New title"}`)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("Expected status 428, got %d", w.Code)
+	}
+}
+
+func TestUpdateSukukMetadataRejectsInvalidIfMatch(t *testing.T) {
+	w := putMetadata(newMetadataRouter(), 1, "*", `{"sukuk_title":"New title"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestCreateSukukMetadataRejectsUnknownStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/sukuk-metadata", CreateSukukMetadata)
+
+	body := `{"contract_address":"0x00000000000000000000000000000000000c0ffe","token_id":1,"owner_address":"0x1111111111111111111111111111111111111111","sukuk_code":"SR1","status":"berlangsung"}`
+	req := httptest.NewRequest(http.MethodPost, "/sukuk-metadata", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", w.Code)
+	}
+	var resp struct {
+		AllowedStatuses []models.SukukStatus `json:"allowed_statuses"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.AllowedStatuses) != len(models.ManualSukukStatuses()) {
+		t.Errorf("Expected every status an admin may set to be listed, got %v", resp.AllowedStatuses)
+	}
+}
+
+// TestUpdateSukukMetadataLostUpdate requires a reachable Postgres, see testutil.Open
+func TestUpdateSukukMetadataLostUpdate(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	cache.SetDefault(cache.NewMemoryCache())
+
+	metadata := models.SukukMetadata{
+		ContractAddress: "0x00000000000000000000000000000000000c0ffe",
+		SukukCode:       "LOCK",
+		SukukTitle:      "Original",
+	}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	defer db.Unscoped().Delete(&models.SukukMetadata{}, metadata.ID)
+
+	// Both admins loaded version 1; the first write wins
+	router := newMetadataRouter()
+	w := putMetadata(router, metadata.ID, `"1"`, `{"sukuk_title":"Edited by A"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected first update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("Expected ETag \"2\", got %s", etag)
+	}
+
+	w = putMetadata(router, metadata.ID, "", `{"version":1,"tenor":"5 Tahun"}`)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected stale update to conflict, got %d: %s", w.Code, w.Body.String())
+	}
+	var conflict struct {
+		Current models.SukukMetadataResponse `json:"current"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &conflict); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if conflict.Current.Version != 2 || conflict.Current.SukukTitle != "Edited by A" {
+		t.Errorf("Expected conflict to return version 2 with A's title, got %+v", conflict.Current)
+	}
+
+	var stored models.SukukMetadata
+	db.First(&stored, metadata.ID)
+	if stored.SukukTitle != "Edited by A" || stored.Tenor != "" {
+		t.Errorf("Expected A's edit to survive untouched, got title=%q tenor=%q", stored.SukukTitle, stored.Tenor)
+	}
+}
+
+func TestListSukukMetadataRejectsUnsupportedLang(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata", ListSukukMetadata)
+	router.GET("/sukuk-metadata/:id", GetSukukMetadata)
+
+	for _, path := range []string{"/sukuk-metadata?lang=fr", "/sukuk-metadata/1?lang=fr"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestSukukMetadataRejectsInvalidAddress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/sukuk-metadata", ListSukukMetadata)
+	router.GET("/sukuk-metadata/:id", GetSukukMetadata)
+
+	for _, path := range []string{"/sukuk-metadata?address=0x123", "/sukuk-metadata/1?address=wallet"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", path, w.Code)
+		}
+	}
+}
+
+type fakePositionReader struct {
+	calls     int
+	requested []string
+	positions map[string]services.SukukUserPosition
+}
+
+func (r *fakePositionReader) GetSukukPositions(ctx context.Context, userAddress string, sukukAddresses []string) (map[string]services.SukukUserPosition, error) {
+	r.calls++
+	r.requested = sukukAddresses
+	return r.positions, nil
+}
+
+func TestAddUserPositions(t *testing.T) {
+	responses := []models.SukukMetadataListResponse{
+		{ID: 1, ContractAddress: "0x00000000000000000000000000000000000000C1"},
+		{ID: 2, ContractAddress: "0x00000000000000000000000000000000000000c2"},
+	}
+	reader := &fakePositionReader{positions: map[string]services.SukukUserPosition{
+		"0x00000000000000000000000000000000000000c1": {Balance: "100", UnclaimedDistributionCount: 2, ClaimableAmount: "40"},
+		"0x00000000000000000000000000000000000000c2": {Balance: "0", ClaimableAmount: "0"},
+	}}
+
+	if err := addUserPositions(context.Background(), reader, "0x00000000000000000000000000000000000000a1", responses); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reader.calls != 1 || len(reader.requested) != 2 {
+		t.Errorf("Expected one batched lookup of both sukuk, got %d calls for %v", reader.calls, reader.requested)
+	}
+
+	held := responses[0]
+	if held.UserBalance == nil || *held.UserBalance != "100" || *held.UserUnclaimedDistributionCount != 2 || *held.UserClaimableAmount != "40" {
+		t.Errorf("Unexpected position on the held sukuk: %+v", held)
+	}
+	body, _ := json.Marshal(responses[1])
+	for _, field := range []string{`"user_balance":"0"`, `"user_unclaimed_distribution_count":0`, `"user_claimable_amount":"0"`} {
+		if !strings.Contains(string(body), field) {
+			t.Errorf("Expected %s on the sukuk the wallet does not hold, got %s", field, body)
+		}
+	}
+}
+
+type fakeStatsReader struct {
+	calls int
+	stats map[string]services.SukukActivityStats
+}
+
+func (r *fakeStatsReader) GetSukukActivityStats(ctx context.Context, sukukAddresses []string) (map[string]services.SukukActivityStats, error) {
+	r.calls++
+	return r.stats, nil
+}
+
+func TestAddSukukStats(t *testing.T) {
+	responses := []models.SukukMetadataListResponse{
+		{ID: 1, ContractAddress: "0x00000000000000000000000000000000000000D1"},
+		{ID: 2, ContractAddress: "0x00000000000000000000000000000000000000d2"},
+	}
+	first := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	last := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	reader := &fakeStatsReader{stats: map[string]services.SukukActivityStats{
+		"0x00000000000000000000000000000000000000d1": {InvestorCount: 2, FirstPurchaseAt: &first, LastActivityAt: &last},
+	}}
+
+	if err := addSukukStats(context.Background(), reader, responses); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reader.calls != 1 {
+		t.Errorf("Expected one batched lookup, got %d", reader.calls)
+	}
+
+	bought := responses[0]
+	if bought.InvestorCount == nil || *bought.InvestorCount != 2 || !bought.FirstPurchaseAt.Equal(first) || !bought.LastActivityAt.Equal(last) {
+		t.Errorf("Unexpected stats on the purchased sukuk: %+v", bought)
+	}
+	body, _ := json.Marshal(responses[1])
+	if !strings.Contains(string(body), `"investor_count":0`) {
+		t.Errorf("Expected investor_count 0 on the sukuk without purchases, got %s", body)
+	}
+	if strings.Contains(string(body), "first_purchase_at") || strings.Contains(string(body), "last_activity_at") {
+		t.Errorf("Expected no activity dates on the sukuk without purchases, got %s", body)
+	}
+}
+
+func TestSukukMetadataListResponseWithoutAddressOmitsUserFields(t *testing.T) {
+	sukuk := models.SukukMetadata{ID: 1, ContractAddress: "0x00000000000000000000000000000000000000c1", SukukCode: "SR001"}
+	body, err := json.Marshal(sukuk.ToListResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), `"user_`) {
+		t.Errorf("Expected no user fields without ?address=, got %s", body)
+	}
+}
+
+func TestRequestLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           models.Locale
+	}{
+		{name: "default", want: models.DefaultLocale},
+		{name: "accept-language", acceptLanguage: "en-US,en;q=0.9", want: models.LocaleEN},
+		{name: "lang wins over header", query: "?lang=id", acceptLanguage: "en", want: models.LocaleID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			got, err := requestLocale(c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want || w.Header().Get("Content-Language") != string(tt.want) {
+				t.Errorf("Expected %s, got %s (Content-Language %q)", tt.want, got, w.Header().Get("Content-Language"))
+			}
+		})
+	}
+}
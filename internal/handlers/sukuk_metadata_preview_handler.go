@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewSukukMetadataUpdate shows what an update would publish without saving it
+// @Summary Preview a sukuk metadata update
+// @Description Apply the same body as PUT /sukuk-metadata/{id} to the stored record in memory and return the public list item it would produce, with the readiness report PUT /sukuk-metadata/{id}/ready would check: required fields filled in, purchase period in order and before the first coupon before maturity, coupon day matching the coupon cadence, and logo_url resolvable. No version is required and nothing is written. latest_activities is left empty and stats are not looked up
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Param sukuk body models.SukukMetadataUpdateRequest true "Offchain metadata to preview"
+// @Success 200 {object} models.SukukMetadataPreview "Would-be public payload and readiness report"
+// @Failure 400 {object} map[string]string "Invalid request payload or ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 422 {object} map[string]interface{} "Status transition not allowed; body lists allowed_statuses"
+// @Router /admin/sukuk-metadata/{id}/preview [post]
+func PreviewSukukMetadataUpdate(checker *services.SukukReadinessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.SukukMetadataUpdateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		sukukMetadata, ok := findSukukMetadataByID(c)
+		if !ok {
+			return
+		}
+		if err := req.Apply(sukukMetadata); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":            "Invalid status transition",
+				"details":          err.Error(),
+				"allowed_statuses": sukukMetadata.Status.NextStates(),
+			})
+			return
+		}
+
+		public := sukukMetadata.ToListResponse()
+		public.DropTermDetails() // The preview shows the v1 record
+		public.LatestActivities = make([]models.ActivityEvent, 0)
+		suspensions, err := loadOpenSuspensions(c.Request.Context(), *sukukMetadata)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to fetch suspension for sukuk:", sukukMetadata.ContractAddress)
+		}
+		public.Suspension = suspensions[strings.ToLower(sukukMetadata.ContractAddress)]
+
+		respondJSON(c, http.StatusOK, models.SukukMetadataPreview{
+			Public:    public,
+			Readiness: checker.Check(c.Request.Context(), sukukMetadata),
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// previewSukukRows answers the sukuk metadata lookup with a complete SR022-T5 whose logo is
+// logoURL, and everything else with no rows
+func previewSukukRows(logoURL string) func(query string) stubResult {
+	return func(query string) stubResult {
+		if !strings.Contains(query, `FROM "sukuk_metadata"`) {
+			return stubResult{}
+		}
+		return stubResult{
+			columns: []string{"id", "contract_address", "sukuk_code", "sukuk_title", "sukuk_deskripsi", "status", "logo_url",
+				"tenor", "imbal_hasil", "periode_pembelian", "jatuh_tempo", "penerimaan_kupon", "minimum_pembelian",
+				"tanggal_bayar_kupon", "kupon_pertama", "tipe_kupon", "version"},
+			rows: [][]driver.Value{{int64(36), "0xabcdef0000000000000000000000000000000001", "SR022-T5", "Sukuk Ritel", "Sukuk negara ritel", "draft", logoURL,
+				"5 Tahun", "6.55% / Tahun", "16 Mei - 18 Jun 2025", time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC), "Bulanan", float64(1000000),
+				"10 Setiap Bulan", time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), "Fixed Rate", int64(3)}},
+		}
+	}
+}
+
+// serveSukukMetadataPreview runs one request against the preview and ready endpoints, with
+// logos resolved against an upload directory holding logos/sr022.png
+func serveSukukMetadataPreview(t *testing.T, logoURL, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	uploadDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(uploadDir, "logos"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(uploadDir, "logos", "sr022.png"), []byte("\x89PNG"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = openStubDB(t, previewSukukRows(logoURL))
+	defer func() { database.DB = previous }()
+
+	checker := services.NewSukukReadinessChecker(services.NewLocalUploadStorage(uploadDir))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/sukuk-metadata/:id/preview", PreviewSukukMetadataUpdate(checker))
+	router.PUT("/sukuk-metadata/:id/ready", MarkSukukMetadataReady(checker))
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPreviewSukukMetadataUpdateAppliesBodyWithoutSaving(t *testing.T) {
+	w := serveSukukMetadataPreview(t, "/uploads/logos/sr022.png", http.MethodPost, "/admin/sukuk-metadata/36/preview", `{"sukuk_title":"Sukuk Ritel SR022"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var preview models.SukukMetadataPreview
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if preview.Public.SukukTitle != "Sukuk Ritel SR022" || preview.Public.Tenor != "5 Tahun" || preview.Public.LatestActivities == nil {
+		t.Errorf("Expected the edited title over the stored record, got %+v", preview.Public)
+	}
+	if !preview.Readiness.Ready || len(preview.Readiness.Failures) != 0 {
+		t.Errorf("Expected a complete record to be ready, got %+v", preview.Readiness)
+	}
+}
+
+func TestPreviewSukukMetadataUpdateReportsMissingLogo(t *testing.T) {
+	w := serveSukukMetadataPreview(t, "/uploads/logos/sr022.png", http.MethodPost, "/admin/sukuk-metadata/36/preview", `{"logo_url":""}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var preview models.SukukMetadataPreview
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if preview.Readiness.Ready || len(preview.Readiness.Failures) != 1 ||
+		preview.Readiness.Failures[0].Check != models.SukukReadinessLogo || preview.Readiness.Failures[0].Field != "logo_url" {
+		t.Errorf("Expected only the logo check to fail, got %+v", preview.Readiness)
+	}
+}
+
+func TestPreviewSukukMetadataUpdateRejectsStatusTransition(t *testing.T) {
+	w := serveSukukMetadataPreview(t, "/uploads/logos/sr022.png", http.MethodPost, "/admin/sukuk-metadata/36/preview", `{"status":"matured"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for draft to matured, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestMarkSukukMetadataReadyRefusesUnreadyRecord(t *testing.T) {
+	w := serveSukukMetadataPreview(t, "/uploads/logos/missing.png", http.MethodPut, "/sukuk-metadata/36/ready", "")
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Failures []models.SukukReadinessFailure `json:"failures"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Failures) != 1 || resp.Failures[0].Check != models.SukukReadinessLogo {
+		t.Errorf("Expected the unresolvable logo to be listed, got %+v", resp.Failures)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -18,8 +19,9 @@ import (
 // @Produce json
 // @Param tokenId query int true "Token ID"
 // @Param contractAddress query string true "Contract Address"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} SukukMetadataSyncResponse
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "Metadata changed during the sync"
 // @Failure 500 {object} map[string]string
 // @Router /sukuk-metadata/sync [post]
 func TriggerSukukMetadataSync(c *gin.Context) {
@@ -55,7 +57,13 @@ func TriggerSukukMetadataSync(c *gin.Context) {
 	syncService := services.NewSukukMetadataSyncService(0) // 0 interval for one-time sync
 	
 	// Sync specific sukuk
-	if err := syncService.SyncSpecificSukuk(tokenID, contractAddress); err != nil {
+	if err := syncService.SyncSpecificSukuk(c.Request.Context(), tokenID, contractAddress); err != nil {
+		if errors.Is(err, services.ErrMetadataChangedDuringSync) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Sukuk metadata changed during the sync, try again",
+			})
+			return
+		}
 		logger.WithError(err).Error("Failed to sync sukuk metadata")
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to sync sukuk metadata",
@@ -69,10 +77,10 @@ func TriggerSukukMetadataSync(c *gin.Context) {
 		"contract_address": contractAddress,
 	}).Info("Sukuk metadata sync triggered successfully")
 	
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Sukuk metadata sync completed successfully",
-		"token_id": tokenID,
-		"contract_address": contractAddress,
+	respondJSON(c, http.StatusOK, SukukMetadataSyncResponse{
+		Message:         "Sukuk metadata sync completed successfully",
+		TokenID:         tokenID,
+		ContractAddress: contractAddress,
 	})
 }
 
@@ -82,7 +90,7 @@ func TriggerSukukMetadataSync(c *gin.Context) {
 // @Tags sukuk-metadata
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} SukukCreationTablesResponse
 // @Failure 500 {object} map[string]string
 // @Router /sukuk-metadata/tables [get]
 func ListSukukCreationTables(c *gin.Context) {
@@ -107,9 +115,9 @@ func ListSukukCreationTables(c *gin.Context) {
 		latestTable = "error getting latest"
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"tables": tables,
-		"latest_table": latestTable,
-		"total_count": len(tables),
+	respondJSON(c, http.StatusOK, SukukCreationTablesResponse{
+		Tables:      tables,
+		LatestTable: latestTable,
+		TotalCount:  len(tables),
 	})
 }
\ No newline at end of file
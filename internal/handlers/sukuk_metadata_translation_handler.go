@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const sukukMetadataTranslationEntity = "sukuk_metadata_translation"
+
+// GetSukukMetadataTranslations returns every translation of a sukuk metadata record
+// @Summary Get sukuk metadata translations
+// @Description List the translated title, description and term labels of a sukuk metadata record per locale
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Success 200 {object} models.SukukMetadataTranslationsResponse "Translations per locale"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/translations [get]
+func GetSukukMetadataTranslations(c *gin.Context) {
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	translations, err := models.GetAllTranslations(database.GetDB(), sukukMetadata.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to fetch sukuk metadata translations")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch sukuk metadata translations",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, models.SukukMetadataTranslationsResponse{
+		SukukMetadataID: sukukMetadata.ID,
+		DefaultLocale:   models.DefaultLocale,
+		Translations:    translations,
+	})
+}
+
+// SetSukukMetadataTranslations upserts the translations of a sukuk metadata record in one locale
+// @Summary Set sukuk metadata translations
+// @Description Set translated values for title, description and term labels in a locale. Fields left out are unchanged; an empty value removes the translation so the field falls back to the base record. The default locale (id) is the base record itself and is edited through PUT /sukuk-metadata/{id}
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path integer true "Sukuk metadata ID"
+// @Param locale path string true "Locale" Enums(en)
+// @Param request body models.SukukMetadataTranslationsRequest true "Translated values keyed by field"
+// @Success 200 {object} models.SukukMetadataTranslationsResponse "Translations per locale"
+// @Failure 400 {object} map[string]string "Invalid ID, locale or field"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/translations/{locale} [put]
+func SetSukukMetadataTranslations(c *gin.Context) {
+	locale, valid := models.ParseLocale(c.Param("locale"))
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Unsupported locale",
+			"details": fmt.Sprintf("locale must be one of %v", models.Locales()),
+		})
+		return
+	}
+	if locale == models.DefaultLocale {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "The default locale is the base record",
+			"details": "Update the sukuk metadata itself to change its Indonesian values",
+		})
+		return
+	}
+
+	var req models.SukukMetadataTranslationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := models.ValidateTranslations(req.Translations); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid translation field",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	entityID := fmt.Sprintf("%d:%s", sukukMetadata.ID, locale)
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := models.SetTranslations(tx, sukukMetadata.ID, locale, req.Translations); err != nil {
+			return err
+		}
+		// Translations are part of the record as served, so its version and ETag move with them
+		if err := tx.Model(sukukMetadata).UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionUpdate, sukukMetadataTranslationEntity, entityID, auditActor(c), req)
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to save sukuk metadata translations")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to save sukuk metadata translations",
+		})
+		return
+	}
+
+	// Localized listings are cached per locale
+	cache.InvalidateSukukMetadata(c.Request.Context())
+
+	GetSukukMetadataTranslations(c)
+}
+
+// findSukukMetadataByID loads the sukuk metadata named by the id path parameter,
+// responding 400 or 404 itself when it cannot
+func findSukukMetadataByID(c *gin.Context) (*models.SukukMetadata, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID format",
+		})
+		return nil, false
+	}
+
+	var sukukMetadata models.SukukMetadata
+	if err := database.GetDB().First(&sukukMetadata, "id = ?", uint(id)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sukuk metadata not found",
+		})
+		return nil, false
+	}
+	return &sukukMetadata, true
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateSukukMetadataRejectsDatesOutOfOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/sukuk-metadata", CreateSukukMetadata)
+
+	body := `{"contract_address":"0x00000000000000000000000000000000000c0ffe","token_id":1,"owner_address":"0x1111111111111111111111111111111111111111","sukuk_code":"SR022",
+		"periode_pembelian":"16 Mei - 18 Jun 2025","kupon_pertama":"2025-08-10T00:00:00Z","jatuh_tempo":"2025-08-01T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/sukuk-metadata", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Violations []models.SukukDateViolation `json:"violations"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(resp.Violations) != 1 || resp.Violations[0].Constraint != models.SukukDateMaturityAfterCoupon {
+		t.Errorf("Expected the maturity constraint to be named, got %+v", resp.Violations)
+	}
+}
+
+func TestGetSukukMetadataValidationReport(t *testing.T) {
+	maturity := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+	firstCoupon := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+	previous := database.DB
+	database.DB = openStubDB(t, func(query string) stubResult {
+		if !strings.Contains(query, `FROM "sukuk_metadata"`) {
+			return stubResult{}
+		}
+		return stubResult{
+			columns: []string{"id", "contract_address", "sukuk_code", "periode_pembelian", "jatuh_tempo", "penerimaan_kupon", "tanggal_bayar_kupon", "kupon_pertama", "metadata_ready"},
+			rows: [][]driver.Value{
+				{int64(1), "0x00000000000000000000000000000000000000a1", "SR021", "16 Mei - 18 Jun 2025", maturity, "Bulanan", "10 Setiap Bulan", firstCoupon, true},
+				{int64(2), "0x00000000000000000000000000000000000000a2", "SR022", "16 Mei - 18 Jun 2025", maturity, "Bulanan", "10 Setiap Bulan", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), true},
+				{int64(3), "0x00000000000000000000000000000000000000a3", "SR018", "", nil, "Bulanan", "", nil, false},
+			},
+		}
+	})
+	defer func() { database.DB = previous }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/sukuk-metadata/validation-report", GetSukukMetadataValidationReport)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/sukuk-metadata/validation-report", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report models.SukukDateValidationReport
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if report.Checked != 3 || report.Invalid != 1 || report.Warned != 1 || len(report.Records) != 2 {
+		t.Fatalf("Expected one invalid and one warned record of three, got %+v", report)
+	}
+
+	// The ready record stored out of order is listed with the constraint it breaks
+	if invalid := report.Records[0]; invalid.ID != 2 || !invalid.MetadataReady || len(invalid.Violations) != 1 ||
+		invalid.Violations[0].Constraint != models.SukukDateFirstCouponAfterSale || invalid.Violations[0].Severity != models.SukukDateError {
+		t.Errorf("Expected SR022's first coupon error, got %+v", invalid)
+	}
+	// The legacy record without dates only warns
+	if legacy := report.Records[1]; legacy.ID != 3 || len(legacy.Violations) != 3 {
+		t.Errorf("Expected three warnings for SR018, got %+v", legacy)
+	}
+	for _, violation := range report.Records[1].Violations {
+		if violation.Severity != models.SukukDateWarning {
+			t.Errorf("Expected missing dates to warn, got %+v", violation)
+		}
+	}
+}
+
+func TestUpdateSukukMetadataRejectsDatesOutOfOrder(t *testing.T) {
+	previous := database.DB
+	database.DB = openStubDB(t, previewSukukRows("/uploads/logos/sr022.png"))
+	defer func() { database.DB = previous }()
+
+	// The stored SR022-T5 sells until 18 Jun 2025; a first coupon in June falls inside it
+	w := putMetadata(newMetadataRouter(), 36, `"3"`, `{"kupon_pertama":"2025-06-10T00:00:00Z"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), string(models.SukukDateFirstCouponAfterSale)) {
+		t.Errorf("Expected the first coupon constraint to be named, got %s", w.Body.String())
+	}
+}
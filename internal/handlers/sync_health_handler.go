@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHealthReader reports the anomaly flags of the sync services and who holds their locks
+type SyncHealthReader interface {
+	Health() []services.SyncServiceHealth
+	Locks(ctx context.Context) []services.SyncLockStatus
+}
+
+// SyncHealthResponse is the body of GET /admin/sync/health
+type SyncHealthResponse struct {
+	Healthy  bool                         `json:"healthy"`
+	Services []services.SyncServiceHealth `json:"services"`
+	Locks    []services.SyncLockStatus    `json:"locks"` // Instance holding each sync service's lock
+}
+
+// GetSyncHealth returns the anomaly flags of the sync services
+// @Summary Get sync health
+// @Description Events per cycle, cursor, indexer head and raised anomaly flags of the metadata sync and the activity stream. Flags are stall (no new events for SYNC_STALL_THRESHOLD while the indexer advanced), failure_ratio (more than SYNC_FAILURE_RATIO_THRESHOLD percent of a cycle's events failed, or the cycle failed) and cursor_regression (the last processed ID went backwards). Locks lists the instance holding each sync service's lock, only one replica runs a service's cycles at a time; a lock is stale when its holder stopped writing its heartbeat for SYNC_LOCK_STALE_AFTER. Responds 503 while any flag is raised or a lock is stale. Services appear after their first cycle on the instance answering
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} SyncHealthResponse "No anomaly flags raised or stale locks"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 503 {object} SyncHealthResponse "At least one anomaly flag raised or lock stale"
+// @Router /admin/sync/health [get]
+func GetSyncHealth(monitor SyncHealthReader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := SyncHealthResponse{
+			Healthy:  true,
+			Services: monitor.Health(),
+			Locks:    monitor.Locks(c.Request.Context()),
+		}
+		for _, service := range response.Services {
+			if !service.Healthy {
+				response.Healthy = false
+			}
+		}
+		for _, lock := range response.Locks {
+			if lock.Stale {
+				response.Healthy = false
+			}
+		}
+
+		status := http.StatusOK
+		if !response.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		respondJSON(c, status, response)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeSyncHealth []services.SyncServiceHealth
+
+func (f fakeSyncHealth) Health() []services.SyncServiceHealth { return f }
+
+func (f fakeSyncHealth) Locks(context.Context) []services.SyncLockStatus { return nil }
+
+// fakeSyncLocks adds lock holders to healthy services
+type fakeSyncLocks []services.SyncLockStatus
+
+func (f fakeSyncLocks) Health() []services.SyncServiceHealth { return nil }
+
+func (f fakeSyncLocks) Locks(context.Context) []services.SyncLockStatus { return f }
+
+func TestGetSyncHealthStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stall := services.SyncAnomaly{Kind: services.SyncAnomalyStall, Message: "no new events for 3h0m0s"}
+
+	tests := []struct {
+		health fakeSyncHealth
+		want   int
+	}{
+		{fakeSyncHealth{}, http.StatusOK},
+		{fakeSyncHealth{{Service: services.SyncServiceMetadata, Healthy: true}}, http.StatusOK},
+		{fakeSyncHealth{
+			{Service: services.SyncServiceActivity, Healthy: true},
+			{Service: services.SyncServiceMetadata, Anomalies: []services.SyncAnomaly{stall}},
+		}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		router := gin.New()
+		router.GET("/admin/sync/health", GetSyncHealth(tt.health))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/sync/health", nil))
+		if w.Code != tt.want {
+			t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+		}
+		if tt.want == http.StatusServiceUnavailable && !strings.Contains(w.Body.String(), `"kind":"stall"`) {
+			t.Errorf("Expected the stall flag in the body, got %s", w.Body.String())
+		}
+	}
+}
+
+func TestGetSyncHealthReportsLockHolders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	holder := &services.SyncLockHolder{Instance: "api-7f9c-1", AcquiredAt: time.Now(), HeartbeatAt: time.Now()}
+
+	tests := []struct {
+		locks fakeSyncLocks
+		want  int
+	}{
+		{fakeSyncLocks{{Service: services.SyncServiceMetadata}}, http.StatusOK},
+		{fakeSyncLocks{{Service: services.SyncServiceMetadata, Held: true, Holder: holder}}, http.StatusOK},
+		{fakeSyncLocks{{Service: services.SyncServiceMetadata, Held: true, Holder: holder, Stale: true}}, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		router := gin.New()
+		router.GET("/admin/sync/health", GetSyncHealth(tt.locks))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/sync/health", nil))
+		if w.Code != tt.want {
+			t.Errorf("Expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+		}
+		if tt.locks[0].Held && !strings.Contains(w.Body.String(), `"instance":"api-7f9c-1"`) {
+			t.Errorf("Expected the lock holder in the body, got %s", w.Body.String())
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,7 +24,7 @@ import (
 // @Tags Admin
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Sync status"
+// @Success 200 {object} SyncStatusResponse "Sync status"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Security ApiKeyAuth
 // @Router /admin/system/sync-status [get]
@@ -32,43 +39,209 @@ func GetSyncStatus(c *gin.Context) {
 
 	// Get total count of events in blockchain database (if accessible)
 	// For now, we'll return the last processed ID
-	c.JSON(http.StatusOK, gin.H{
-		"data": gin.H{
-			"last_processed_event_id": systemState.Value,
-			"sync_status":            "active",
-			"last_updated":           systemState.UpdatedAt,
+	respondJSON(c, http.StatusOK, SyncStatusResponse{
+		Data: SyncStatus{
+			LastProcessedEventID: systemState.Value,
+			SyncStatus:           "active",
+			LastUpdated:          systemState.UpdatedAt,
 		},
 	})
 }
 
+// EventSyncer runs indexer sync cycles on demand
+type EventSyncer interface {
+	PendingCount(ctx context.Context) (int64, error)
+	RunOnce(ctx context.Context) (*services.SyncResult, error)
+}
+
+// SyncJob tracks a manual sync running in the background
+type SyncJob struct {
+	ID         string               `json:"id"`
+	Status     string               `json:"status"` // "running", "completed", "failed"
+	Pending    int64                `json:"pending"`
+	Result     *services.SyncResult `json:"result,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+}
+
+var (
+	syncJobs   = make(map[string]*SyncJob)
+	syncJobsMu sync.RWMutex
+)
+
+// syncJobRetention is how long a finished sync job can still be looked up
+const syncJobRetention = time.Hour
+
+// runningSyncJob returns the background sync job still running, or nil; callers hold syncJobsMu
+func runningSyncJob() *SyncJob {
+	for _, job := range syncJobs {
+		if job.Status == "running" {
+			return job
+		}
+	}
+	return nil
+}
+
+// pruneSyncJobs forgets jobs that finished more than syncJobRetention before now, so the job
+// map doesn't grow with every manual sync; callers hold syncJobsMu
+func pruneSyncJobs(now time.Time) {
+	for id, job := range syncJobs {
+		if job.FinishedAt != nil && now.Sub(*job.FinishedAt) > syncJobRetention {
+			delete(syncJobs, id)
+		}
+	}
+}
+
 // ForceSync triggers a manual blockchain synchronization
 // @Summary Force blockchain sync
-// @Description Manually trigger blockchain event synchronization
+// @Description Manually trigger a sync cycle. Runs synchronously when few events are pending, otherwise starts a background job and returns 202 with a job ID. While a background job runs, returns 409 with its job ID. Finished jobs can be looked up for an hour.
 // @Tags Admin
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "Sync triggered"
+// @Success 200 {object} ForceSyncResponse "Sync completed"
+// @Success 202 {object} SyncJobStartedResponse "Sync started in background"
+// @Failure 409 {object} map[string]interface{} "Sync already in progress, with the job_id of a running background sync"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Security ApiKeyAuth
 // @Router /admin/system/force-sync [post]
-func ForceSync(c *gin.Context) {
-	// TODO: Implement actual sync triggering logic
-	// This would interact with the blockchain sync service
-	
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Blockchain sync triggered successfully",
-		"status":  "sync_started",
+func ForceSync(syncer EventSyncer, asyncThreshold int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		syncJobsMu.RLock()
+		running := runningSyncJob()
+		syncJobsMu.RUnlock()
+		if running != nil {
+			respondSyncJobRunning(c, running)
+			return
+		}
+
+		pending, err := syncer.PendingCount(c.Request.Context())
+		if err != nil {
+			logger.WithError(err).Error("Failed to count pending events")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to count pending events",
+			})
+			return
+		}
+
+		// Large backlogs run in the background so the request doesn't time out
+		if pending > int64(asyncThreshold) {
+			job := &SyncJob{
+				ID:        fmt.Sprintf("sync-%d", time.Now().UnixNano()),
+				Status:    "running",
+				Pending:   pending,
+				StartedAt: time.Now(),
+			}
+
+			// Checked again under the write lock, as another trigger may have started a job since
+			syncJobsMu.Lock()
+			if running := runningSyncJob(); running != nil {
+				syncJobsMu.Unlock()
+				respondSyncJobRunning(c, running)
+				return
+			}
+			pruneSyncJobs(job.StartedAt)
+			syncJobs[job.ID] = job
+			syncJobsMu.Unlock()
+
+			// Detach from the request so the job outlives the 202 response
+			go runSyncJob(context.WithoutCancel(c.Request.Context()), syncer, job)
+
+			c.JSON(http.StatusAccepted, SyncJobStartedResponse{
+				Message: "Sync started in background",
+				JobID:   job.ID,
+				Pending: pending,
+			})
+			return
+		}
+
+		result, err := syncer.RunOnce(c.Request.Context())
+		if errors.Is(err, services.ErrSyncInProgress) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "Sync already in progress",
+			})
+			return
+		}
+		if err != nil {
+			logger.WithError(err).Error("Manual sync failed")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Sync failed",
+			})
+			return
+		}
+
+		respondJSON(c, http.StatusOK, ForceSyncResponse{
+			Message:         "Sync completed",
+			Processed:       result.Processed,
+			Failed:          result.Failed,
+			Skipped:         result.Skipped,
+			LastProcessedID: result.LastProcessedID,
+		})
+	}
+}
+
+// respondSyncJobRunning writes the 409 for a sync triggered while a background job runs
+func respondSyncJobRunning(c *gin.Context, job *SyncJob) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":  "Sync already in progress",
+		"job_id": job.ID,
 	})
 }
 
+// runSyncJob runs a sync cycle and records the outcome on the job
+func runSyncJob(ctx context.Context, syncer EventSyncer, job *SyncJob) {
+	result, err := syncer.RunOnce(ctx)
+
+	syncJobsMu.Lock()
+	defer syncJobsMu.Unlock()
+
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		logger.WithError(err).WithField("job_id", job.ID).Error("Background sync failed")
+		job.Status = "failed"
+		job.Error = err.Error()
+		return
+	}
+
+	job.Status = "completed"
+	job.Result = result
+}
+
+// GetSyncJob returns the status of a background sync job
+// @Summary Get sync job status
+// @Description Get the status and result of a background sync started by force-sync
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Sync job ID"
+// @Success 200 {object} SyncJob "Sync job"
+// @Failure 404 {object} map[string]interface{} "Sync job not found"
+// @Security ApiKeyAuth
+// @Router /admin/system/sync-jobs/{id} [get]
+func GetSyncJob(c *gin.Context) {
+	syncJobsMu.RLock()
+	defer syncJobsMu.RUnlock()
+
+	job, exists := syncJobs[c.Param("id")]
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Sync job not found",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, job)
+}
+
 // GetHealthStatus returns the overall system health
 // @Summary Get system health
 // @Description Get overall system health including database and sync status
 // @Tags System
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "System health"
-// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Success 200 {object} HealthResponse "System health"
+// @Failure 500 {object} HealthResponse "Database unreachable"
 // @Router /health [get]
 func GetHealthStatus(c *gin.Context) {
 	db := database.GetDB()
@@ -76,17 +249,17 @@ func GetHealthStatus(c *gin.Context) {
 	// Check database connection
 	sqlDB, err := db.DB()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "unhealthy",
-			"error":  "Database connection failed",
+		c.JSON(http.StatusInternalServerError, HealthResponse{
+			Status: "unhealthy",
+			Error:  "Database connection failed",
 		})
 		return
 	}
 	
 	if err := sqlDB.Ping(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "unhealthy",
-			"error":  "Database ping failed",
+		c.JSON(http.StatusInternalServerError, HealthResponse{
+			Status: "unhealthy",
+			Error:  "Database ping failed",
 		})
 		return
 	}
@@ -96,11 +269,11 @@ func GetHealthStatus(c *gin.Context) {
 	
 	db.Model(&models.SukukMetadata{}).Count(&sukukMetadataCount)
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "healthy",
-		"data": gin.H{
-			"database":         "connected",
-			"sukuk_metadata":   sukukMetadataCount,
+	respondJSON(c, http.StatusOK, HealthResponse{
+		Status: "healthy",
+		Data: &HealthData{
+			Database:      "connected",
+			SukukMetadata: sukukMetadataCount,
 		},
 	})
 }
\ No newline at end of file
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mockSyncer struct {
+	pending int64
+	result  *services.SyncResult
+	err     error
+	calls   chan struct{}
+}
+
+func (m *mockSyncer) PendingCount(ctx context.Context) (int64, error) {
+	return m.pending, nil
+}
+
+func (m *mockSyncer) RunOnce(ctx context.Context) (*services.SyncResult, error) {
+	if m.calls != nil {
+		m.calls <- struct{}{}
+	}
+	return m.result, m.err
+}
+
+func newSyncRouter(syncer EventSyncer, asyncThreshold int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/force-sync", ForceSync(syncer, asyncThreshold))
+	router.GET("/sync-jobs/:id", GetSyncJob)
+	return router
+}
+
+func TestForceSyncRunsSynchronously(t *testing.T) {
+	syncer := &mockSyncer{
+		pending: 3,
+		result:  &services.SyncResult{Processed: 2, Failed: 1, LastProcessedID: "0xabc-1"},
+	}
+	router := newSyncRouter(syncer, 10)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/force-sync", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if body["processed"] != float64(2) {
+		t.Errorf("Expected processed 2, got %v", body["processed"])
+	}
+	if body["failed"] != float64(1) {
+		t.Errorf("Expected failed 1, got %v", body["failed"])
+	}
+	if body["last_processed_id"] != "0xabc-1" {
+		t.Errorf("Expected last_processed_id '0xabc-1', got %v", body["last_processed_id"])
+	}
+}
+
+func TestForceSyncRunsInBackground(t *testing.T) {
+	syncer := &mockSyncer{
+		pending: 500,
+		result:  &services.SyncResult{Processed: 100},
+		calls:   make(chan struct{}, 1),
+	}
+	router := newSyncRouter(syncer, 10)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/force-sync", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d", w.Code)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	jobID, ok := body["job_id"].(string)
+	if !ok || jobID == "" {
+		t.Fatalf("Expected job_id in response, got %v", body["job_id"])
+	}
+
+	select {
+	case <-syncer.calls:
+	case <-time.After(time.Second):
+		t.Fatal("Expected background sync to run")
+	}
+
+	// Wait for the job to record its result
+	var job SyncJob
+	for i := 0; i < 50; i++ {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sync-jobs/"+jobID, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for job lookup, got %d", w.Code)
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("Failed to parse job: %v", err)
+		}
+		if job.Status != "running" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status != "completed" {
+		t.Fatalf("Expected job status 'completed', got '%s'", job.Status)
+	}
+	if job.Result == nil || job.Result.Processed != 100 {
+		t.Errorf("Expected job result with 100 processed, got %+v", job.Result)
+	}
+}
+
+func TestForceSyncConflict(t *testing.T) {
+	syncer := &mockSyncer{pending: 1, err: services.ErrSyncInProgress}
+	router := newSyncRouter(syncer, 10)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/force-sync", nil))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d", w.Code)
+	}
+}
+
+func TestForceSyncWhileJobRuns(t *testing.T) {
+	running := &SyncJob{ID: "sync-running", Status: "running", StartedAt: time.Now()}
+	syncJobsMu.Lock()
+	syncJobs[running.ID] = running
+	syncJobsMu.Unlock()
+	defer func() {
+		syncJobsMu.Lock()
+		delete(syncJobs, running.ID)
+		syncJobsMu.Unlock()
+	}()
+
+	for _, pending := range []int64{1, 500} {
+		syncer := &mockSyncer{pending: pending, calls: make(chan struct{}, 1)}
+		w := httptest.NewRecorder()
+		newSyncRouter(syncer, 10).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/force-sync", nil))
+
+		if w.Code != http.StatusConflict {
+			t.Fatalf("Pending %d: expected status 409, got %d", pending, w.Code)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if body["job_id"] != running.ID {
+			t.Errorf("Pending %d: expected the running job's ID, got %v", pending, body["job_id"])
+		}
+		if len(syncer.calls) != 0 {
+			t.Errorf("Pending %d: expected no second sync to start", pending)
+		}
+	}
+}
+
+func TestPruneSyncJobs(t *testing.T) {
+	now := time.Now()
+	old, recent := now.Add(-2*syncJobRetention), now.Add(-time.Minute)
+	jobs := []*SyncJob{
+		{ID: "sync-old", Status: "completed", FinishedAt: &old},
+		{ID: "sync-recent", Status: "failed", FinishedAt: &recent},
+		{ID: "sync-unfinished", Status: "running"},
+	}
+
+	syncJobsMu.Lock()
+	defer syncJobsMu.Unlock()
+	for _, job := range jobs {
+		syncJobs[job.ID] = job
+		defer delete(syncJobs, job.ID)
+	}
+
+	pruneSyncJobs(now)
+	if _, ok := syncJobs["sync-old"]; ok {
+		t.Error("Expected a job finished past the retention to be pruned")
+	}
+	for _, id := range []string{"sync-recent", "sync-unfinished"} {
+		if _, ok := syncJobs[id]; !ok {
+			t.Errorf("Expected %s to be kept", id)
+		}
+	}
+}
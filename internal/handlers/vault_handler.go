@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSukukVaultBalance returns the yield vault funding of a sukuk
+// @Summary Get sukuk yield vault balance
+// @Description Sum the indexed yield_deposit and yield distribution events of a sukuk per payment token and report the implied vault balance (deposited minus distributed) with the current vault address from the latest vault_update. The next coupon is expected to repeat the latest distribution and is dated by the coupon calendar when one can be estimated; funding_coverage is the balance as a percentage of it and low_funding is set below 100%. Coverage is omitted when the sukuk has no distribution yet or its calendar has no coupon left
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Sukuk Metadata ID"
+// @Success 200 {object} models.VaultBalanceResponse "Vault balance"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 404 {object} map[string]string "Sukuk metadata not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/sukuk-metadata/{id}/vault [get]
+func GetSukukVaultBalance(c *gin.Context) {
+	sukukMetadata, ok := findSukukMetadataByID(c)
+	if !ok {
+		return
+	}
+
+	grace := services.Settings().GetDuration(services.SettingCouponGracePeriod, services.DefaultCouponGracePeriod)
+	vault, err := services.NewIndexerQueryService().GetVaultBalance(c.Request.Context(), sukukMetadata, grace, time.Now())
+	if err != nil {
+		logger.WithError(err).Error("Failed to compute vault balance")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to compute vault balance",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, vault)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const investorViewEntity = "investor_view"
+
+// viewAsTransactionLimit is how many recent transactions the view shows, the default of
+// the transaction history endpoint
+const viewAsTransactionLimit = 50
+
+// ViewAsSection tells when a section was read and why it is missing, if it is
+type ViewAsSection struct {
+	FetchedAt time.Time `json:"fetched_at"`      // When the section was read, or served from the cache
+	Cached    bool      `json:"cached"`          // Served from the response cache, up to its TTL old
+	Error     string    `json:"error,omitempty"` // Why the section could not be read; its data is then omitted
+}
+
+// ViewAsPortfolio is the wallet's portfolio as GET /portfolio/{address} serves it
+type ViewAsPortfolio struct {
+	ViewAsSection
+	Data *models.PortfolioResponse `json:"data,omitempty"`
+}
+
+// ViewAsYieldClaims is the wallet's claimable yield as GET /yield-claims/{address} serves it
+type ViewAsYieldClaims struct {
+	ViewAsSection
+	Data *models.YieldClaimsResponse `json:"data,omitempty"`
+}
+
+// ViewAsTransactions is the wallet's latest transactions as GET /transactions/{address} serves them
+type ViewAsTransactions struct {
+	ViewAsSection
+	Data *models.TransactionHistoryResponse `json:"data,omitempty"`
+}
+
+// ViewAsOwnedSukuk is the wallet's sukuk as GET /owned-sukuk/{address} serves them
+type ViewAsOwnedSukuk struct {
+	ViewAsSection
+	Data *OwnedSukukResponse `json:"data,omitempty"`
+}
+
+// ViewAsResponse bundles what an investor's wallet sees across the investor-facing endpoints
+type ViewAsResponse struct {
+	Address      string             `json:"address"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+	Portfolio    ViewAsPortfolio    `json:"portfolio"`
+	YieldClaims  ViewAsYieldClaims  `json:"yield_claims"`
+	Transactions ViewAsTransactions `json:"transactions"`
+	OwnedSukuk   ViewAsOwnedSukuk   `json:"owned_sukuk"`
+}
+
+// ViewAsInvestor shows support what a wallet sees without asking the investor
+// @Summary View as investor
+// @Description Get the portfolio, yield claims, latest 50 transactions and owned sukuk of a wallet in one payload, as the investor-facing endpoints serve them to the wallet. Each section carries when it was read and whether it came from the response cache; a section the indexer fails to serve carries its error instead of data, and the other sections are still returned. Every call is recorded in the audit log with the caller and the viewed address
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param address path string true "Investor wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Success 200 {object} ViewAsResponse "What the wallet sees"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/view-as/{address} [get]
+func ViewAsInvestor(c *gin.Context) {
+	address := c.Param("address")
+	if !utils.IsValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid address",
+		})
+		return
+	}
+
+	// The view is only served once it is on record
+	err := models.RecordAudit(database.GetDB().WithContext(c.Request.Context()), models.AuditActionView, investorViewEntity, strings.ToLower(address), auditActor(c), gin.H{
+		"request_id": logger.RequestIDFromContext(c.Request.Context()),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to record investor view audit")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to record investor view",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	response := ViewAsResponse{Address: address, GeneratedAt: time.Now().UTC()}
+
+	var hit bool
+	response.Portfolio.Data, hit, err = cache.Fetch(ctx, cache.PortfolioKey(address), cacheTTL(services.SettingCachePortfolioTTL, cache.PortfolioTTL), func() (*models.PortfolioResponse, error) {
+		return buildPortfolioResponse(ctx, address)
+	})
+	response.Portfolio.ViewAsSection = viewAsSection("portfolio", address, hit, err)
+
+	response.YieldClaims.Data, err = buildYieldClaimsResponse(ctx, address)
+	response.YieldClaims.ViewAsSection = viewAsSection("yield_claims", address, false, err)
+
+	response.Transactions.Data, err = buildTransactionHistoryResponse(ctx, address, "", viewAsTransactionLimit)
+	response.Transactions.ViewAsSection = viewAsSection("transactions", address, false, err)
+
+	response.OwnedSukuk.Data, err = buildOwnedSukukResponse(ctx, address)
+	response.OwnedSukuk.ViewAsSection = viewAsSection("owned_sukuk", address, false, err)
+
+	logger.WithFields(map[string]interface{}{
+		"address": address,
+		"actor":   auditActor(c),
+	}).Info("Investor view served")
+	respondJSON(c, http.StatusOK, response)
+}
+
+// viewAsSection describes a section read just now, logging the error it failed with
+func viewAsSection(section, address string, cached bool, err error) ViewAsSection {
+	result := ViewAsSection{FetchedAt: time.Now().UTC(), Cached: cached}
+	if err != nil {
+		logger.WithError(err).WithFields(map[string]interface{}{
+			"address": address,
+			"section": section,
+		}).Warn("Investor view section failed")
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/mocks"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const viewAsTestInvestor = "0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"
+
+// serveViewAs runs one view-as request against a stub database, returning the response and
+// every audit log insert gorm sent
+func serveViewAs(t *testing.T, deps Deps, target string) (*httptest.ResponseRecorder, []string) {
+	t.Helper()
+	var (
+		mu      sync.Mutex
+		inserts []string
+	)
+	previous := database.DB
+	stub := openStubDB(t, func(query string) stubResult {
+		if strings.Contains(query, `INSERT INTO "audit_logs"`) {
+			mu.Lock()
+			inserts = append(inserts, query)
+			mu.Unlock()
+			return stubResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}}}
+		}
+		return stubResult{}
+	})
+	database.DB = stub.Session(&gorm.Session{SkipDefaultTransaction: true})
+	defer func() { database.DB = previous }()
+	defer SetDeps(SetDeps(deps))
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/view-as/:address", ViewAsInvestor)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w, inserts
+}
+
+func TestViewAsInvestorReturnsHealthySections(t *testing.T) {
+	portfolio := &mocks.PortfolioReader{
+		GetUserPortfolioFunc: func(ctx context.Context, address string) (*services.UserPortfolio, error) {
+			return nil, &services.IndexerUnavailableError{RetryAfter: 30 * time.Second}
+		},
+		GetSukukOwnedByAddressFunc: func(ctx context.Context, address string) ([]string, error) {
+			return []string{}, nil
+		},
+	}
+	activity := &mocks.ActivityReader{
+		GetUserTransactionHistoryFunc: func(ctx context.Context, address string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error) {
+			if limit != viewAsTransactionLimit {
+				t.Errorf("Expected the latest %d transactions, got %d", viewAsTransactionLimit, limit)
+			}
+			return []models.TransactionEvent{{Type: models.ActivityTypePurchase, Amount: "1000", TxHash: "0xabc"}}, nil
+		},
+	}
+
+	w, inserts := serveViewAs(t, Deps{Portfolio: portfolio, Activity: activity}, "/admin/view-as/"+viewAsTestInvestor)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response ViewAsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if response.Portfolio.Error == "" || response.Portfolio.Data != nil {
+		t.Errorf("Expected the portfolio to carry the indexer error instead of data, got %+v", response.Portfolio)
+	}
+	if response.Transactions.Error != "" || response.Transactions.Data == nil || len(response.Transactions.Data.Transactions) != 1 {
+		t.Errorf("Expected the transactions section, got %+v", response.Transactions)
+	}
+	if response.YieldClaims.Error != "" || response.YieldClaims.Data == nil {
+		t.Errorf("Expected the yield claims section, got %+v", response.YieldClaims)
+	}
+	if response.OwnedSukuk.Error != "" || response.OwnedSukuk.Data == nil {
+		t.Errorf("Expected the owned sukuk section, got %+v", response.OwnedSukuk)
+	}
+	for name, section := range map[string]ViewAsSection{
+		"portfolio":    response.Portfolio.ViewAsSection,
+		"yield_claims": response.YieldClaims.ViewAsSection,
+		"transactions": response.Transactions.ViewAsSection,
+		"owned_sukuk":  response.OwnedSukuk.ViewAsSection,
+	} {
+		if section.FetchedAt.IsZero() {
+			t.Errorf("Expected %s to carry when it was read", name)
+		}
+	}
+
+	if len(inserts) != 1 {
+		t.Fatalf("Expected one audit log entry, got %v", inserts)
+	}
+}
+
+func TestViewAsInvestorRejectsInvalidAddress(t *testing.T) {
+	w, inserts := serveViewAs(t, Deps{Portfolio: &mocks.PortfolioReader{}, Activity: &mocks.ActivityReader{}}, "/admin/view-as/not-an-address")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	if len(inserts) != 0 {
+		t.Errorf("Expected no audit log entry for a rejected request, got %v", inserts)
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WalletAuthenticator signs wallets in, e.g. services.WalletAuthService
+type WalletAuthenticator interface {
+	IssueNonce(ctx context.Context, address string) (*models.WalletAuthChallenge, error)
+	Verify(ctx context.Context, message, signature string) (*models.WalletAuthSession, error)
+}
+
+// GetWalletAuthNonce issues a sign-in nonce to a wallet
+// @Summary Get wallet sign-in nonce
+// @Description Issue a single-use nonce to a wallet, with the Sign-In with Ethereum style message to sign with personal_sign and send to /auth/verify before the nonce expires
+// @Tags auth
+// @Produce json
+// @Param address path string true "Wallet address"
+// @Success 200 {object} models.WalletAuthChallenge "Nonce and message to sign"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Wallet authentication not configured"
+// @Router /auth/nonce/{address} [get]
+func GetWalletAuthNonce(auth WalletAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := c.Param("address")
+		if !utils.IsValidEthereumAddress(address) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid address",
+			})
+			return
+		}
+
+		challenge, err := auth.IssueNonce(c.Request.Context(), address)
+		if err != nil {
+			respondWalletAuthError(c, err, "Failed to issue nonce")
+			return
+		}
+		c.JSON(http.StatusOK, challenge)
+	}
+}
+
+// VerifyWalletAuth exchanges a signed sign-in message for a wallet session token
+// @Summary Verify wallet sign-in
+// @Description Check a message from /auth/nonce signed by its wallet, consume its nonce and return a short-lived session token for the wallet, sent as "Authorization: Bearer <token>". Each nonce signs in once
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.WalletAuthVerifyRequest true "Signed sign-in message"
+// @Success 200 {object} models.WalletAuthSession "Session token"
+// @Failure 400 {object} map[string]string "Invalid payload or message"
+// @Failure 401 {object} map[string]string "Signature not made by the address, message expired, or nonce already used"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 503 {object} map[string]string "Wallet authentication not configured"
+// @Router /auth/verify [post]
+func VerifyWalletAuth(auth WalletAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.WalletAuthVerifyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request payload",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		session, err := auth.Verify(c.Request.Context(), req.Message, req.Signature)
+		if err != nil {
+			respondWalletAuthError(c, err, "Sign-in failed")
+			return
+		}
+		logger.WithField("address", session.Address).Info("Wallet signed in")
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// respondWalletAuthError maps wallet sign-in errors to their status codes
+func respondWalletAuthError(c *gin.Context, err error, message string) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrWalletAuthDisabled):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrInvalidSignInMessage):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrSignInExpired), errors.Is(err, services.ErrSignInNonce), errors.Is(err, services.ErrSignInSignature):
+		status = http.StatusUnauthorized
+	}
+	if status == http.StatusInternalServerError {
+		logger.WithError(err).Error(message)
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": message,
+		})
+		return
+	}
+	c.JSON(status, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
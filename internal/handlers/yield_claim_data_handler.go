@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetYieldClaimData returns the claimYield transaction for a user's unclaimed distributions of a sukuk
+// @Summary Build yield claim transaction
+// @Description Select every distribution of the sukuk the address can still claim on its current balance and return the ABI-encoded claimYield(uint256[]) calldata, the sukuk contract to send it to and a summary of what it pays out. Returns 409 with the reason when nothing is claimable, so wallets never submit a claim that would revert
+// @Tags portfolio
+// @Produce json
+// @Param address path string true "User wallet address" Example("0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9")
+// @Param sukuk_address path string true "Sukuk contract address"
+// @Success 200 {object} models.YieldClaimDataResponse "Claim transaction"
+// @Failure 400 {object} map[string]string "Invalid address"
+// @Failure 409 {object} map[string]string "Nothing to claim"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /yield-claims/{address}/{sukuk_address}/claim-data [get]
+func GetYieldClaimData(c *gin.Context) {
+	address := c.Param("address")
+	sukukAddress := c.Param("sukuk_address")
+	if !utils.IsValidEthereumAddress(address) || !utils.IsValidEthereumAddress(sukukAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid address",
+			"details": "address and sukuk_address must be 0x-prefixed 40 hex character addresses",
+		})
+		return
+	}
+	address = utils.NormalizeAddress(address)
+	sukukAddress = utils.NormalizeAddress(sukukAddress)
+
+	distributions, err := services.NewIndexerQueryService().GetClaimableDistributions(c.Request.Context(), address, sukukAddress)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get claimable distributions")
+		c.JSON(queryErrorStatus(c, err), gin.H{
+			"error": "Failed to build yield claim",
+		})
+		return
+	}
+
+	claim, err := services.BuildYieldClaimData(address, sukukAddress, distributions)
+	if errors.Is(err, services.ErrNothingToClaim) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Nothing to claim",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to build yield claim")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to build yield claim",
+		})
+		return
+	}
+
+	respondJSON(c, http.StatusOK, claim)
+}
@@ -0,0 +1,281 @@
+// Package lifecycle starts the server's components in dependency order and stops them in
+// reverse, giving each a bounded time to drain its in-flight work.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sukuk-be/internal/logger"
+)
+
+// DefaultStopTimeout bounds a component's Stop when it sets no StopTimeout
+const DefaultStopTimeout = 10 * time.Second
+
+// Component is a unit the Manager starts and stops, such as a background worker or the
+// HTTP server
+type Component struct {
+	Name      string
+	DependsOn []string // Components started before this one and stopped after it
+
+	// Start returns once the component runs; work it spawns keeps running until Stop.
+	// Nil when there is nothing to start
+	Start func(ctx context.Context) error
+	// Stop returns once the component's in-flight work is drained or ctx, which carries
+	// the stop timeout, ends
+	Stop        func(ctx context.Context) error
+	StopTimeout time.Duration // DefaultStopTimeout when zero
+
+	// InFlight reports the work still in progress, logged when stopping. Work names it in
+	// the logs, e.g. "deliveries"
+	InFlight func() int
+	Work     string
+}
+
+// Service is a background service that starts with a context and stops by cancelling it,
+// the shape of the services package's schedulers
+type Service interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// FromService adapts a Service to a Component
+func FromService(name string, s Service, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(ctx context.Context) error {
+			s.Start(ctx)
+			return nil
+		},
+		Stop: func(context.Context) error {
+			s.Stop()
+			return nil
+		},
+	}
+}
+
+// Counter counts in-flight work for Component.InFlight. The zero value is ready to use
+type Counter struct {
+	n atomic.Int64
+}
+
+// Begin counts one unit of work as started
+func (c *Counter) Begin() {
+	c.n.Add(1)
+}
+
+// Done counts one unit of work as finished
+func (c *Counter) Done() {
+	c.n.Add(-1)
+}
+
+// Count returns the work in progress
+func (c *Counter) Count() int {
+	return int(c.n.Load())
+}
+
+// Manager starts registered components in dependency order and stops the started ones
+// in reverse
+type Manager struct {
+	mu         sync.Mutex
+	components []Component
+	started    []Component // In start order
+}
+
+// New creates an empty Manager
+func New() *Manager {
+	return &Manager{}
+}
+
+// Register adds a component. Names must be unique; dependencies are resolved on Start, so
+// components may be registered in any order
+func (m *Manager) Register(c Component) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c.Name == "" {
+		return errors.New("lifecycle: component without a name")
+	}
+	for _, existing := range m.components {
+		if existing.Name == c.Name {
+			return fmt.Errorf("lifecycle: component %q registered twice", c.Name)
+		}
+	}
+	m.components = append(m.components, c)
+	return nil
+}
+
+// MustRegister registers a component, panicking on a duplicate or missing name
+func (m *Manager) MustRegister(c Component) {
+	if err := m.Register(c); err != nil {
+		panic(err)
+	}
+}
+
+// Start starts every component after its dependencies. If one fails, the ones already
+// started are stopped again and the error is returned
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	order, err := startOrder(m.components)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range order {
+		if c.Start != nil {
+			if err := c.Start(ctx); err != nil {
+				logger.WithError(err).Errorf("%s: failed to start, stopping the components already started", c.Name)
+				if stopErr := m.Stop(context.Background()); stopErr != nil {
+					logger.WithError(stopErr).Error("Failed to stop components after a failed start")
+				}
+				return fmt.Errorf("lifecycle: starting %s: %w", c.Name, err)
+			}
+		}
+		m.mu.Lock()
+		m.started = append(m.started, c)
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse start order, each within its stop timeout.
+// A component that overruns is abandoned and the rest are still stopped; the errors of
+// every component that failed or timed out are returned together
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := stopComponent(ctx, started[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stopComponent runs a component's Stop bounded by its timeout and logs how long it took
+// and how much work it drained
+func stopComponent(ctx context.Context, c Component) error {
+	if c.Stop == nil {
+		return nil
+	}
+	timeout := c.StopTimeout
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+	inFlight := 0
+	if c.InFlight != nil {
+		inFlight = c.InFlight()
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	begin := time.Now()
+	done := make(chan error, 1) // Buffered so an abandoned Stop can still finish
+	go func() {
+		done <- c.Stop(stopCtx)
+	}()
+
+	select {
+	case err := <-done:
+		elapsed := time.Since(begin)
+		if err != nil {
+			logger.WithError(err).Errorf("%s: stop failed after %s", c.Name, formatElapsed(elapsed))
+			return fmt.Errorf("lifecycle: stopping %s: %w", c.Name, err)
+		}
+		logger.Info(stopMessage(c.Name, elapsed, inFlight, c.Work))
+		return nil
+	case <-stopCtx.Done():
+		remaining := 0
+		if c.InFlight != nil {
+			remaining = c.InFlight()
+		}
+		logger.Error(timeoutMessage(c.Name, time.Since(begin), remaining, c.Work))
+		return fmt.Errorf("lifecycle: stopping %s: %w", c.Name, stopCtx.Err())
+	}
+}
+
+// stopMessage describes a clean stop, e.g. "webhook dispatcher: waited 1.2s for 3 deliveries"
+func stopMessage(name string, elapsed time.Duration, inFlight int, work string) string {
+	if inFlight <= 0 {
+		return fmt.Sprintf("%s: stopped in %s", name, formatElapsed(elapsed))
+	}
+	return fmt.Sprintf("%s: waited %s for %d %s", name, formatElapsed(elapsed), inFlight, workNoun(work))
+}
+
+// timeoutMessage describes a stop abandoned at its timeout
+func timeoutMessage(name string, elapsed time.Duration, remaining int, work string) string {
+	if remaining <= 0 {
+		return fmt.Sprintf("%s: gave up stopping after %s", name, formatElapsed(elapsed))
+	}
+	return fmt.Sprintf("%s: gave up stopping after %s with %d %s in flight", name, formatElapsed(elapsed), remaining, workNoun(work))
+}
+
+func workNoun(work string) string {
+	if work == "" {
+		return "tasks"
+	}
+	return work
+}
+
+// formatElapsed rounds a duration to a tenth of a second, e.g. "1.2s"
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// startOrder sorts components so each comes after its dependencies, keeping the
+// registration order otherwise. Unknown dependencies and cycles are errors
+func startOrder(components []Component) ([]Component, error) {
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		byName[c.Name] = c
+	}
+	for _, c := range components {
+		for _, dep := range c.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("lifecycle: %s depends on unknown component %q", c.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(components))
+	order := make([]Component, 0, len(components))
+	var visit func(c Component, path []string) error
+	visit = func(c Component, path []string) error {
+		switch state[c.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("lifecycle: dependency cycle %s", strings.Join(append(path, c.Name), " -> "))
+		}
+		state[c.Name] = visiting
+		for _, dep := range c.DependsOn {
+			if err := visit(byName[dep], append(path, c.Name)); err != nil {
+				return err
+			}
+		}
+		state[c.Name] = visited
+		order = append(order, c)
+		return nil
+	}
+	for _, c := range components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the start and stop calls of fake components in order
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) list() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, " ")
+}
+
+// fake returns a component recording its calls whose Stop takes stopDelay, or until the
+// stop timeout when it ignores its context
+func fake(r *recorder, name string, stopDelay time.Duration, dependsOn ...string) Component {
+	return Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(context.Context) error {
+			r.add("start:" + name)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			select {
+			case <-time.After(stopDelay):
+			case <-ctx.Done():
+			}
+			r.add("stop:" + name)
+			return nil
+		},
+	}
+}
+
+func TestManagerStartsDependenciesFirstAndStopsInReverse(t *testing.T) {
+	r := &recorder{}
+	m := New()
+	m.MustRegister(fake(r, "http", 0, "sync", "usage"))
+	m.MustRegister(fake(r, "sync", 20*time.Millisecond, "settings"))
+	m.MustRegister(fake(r, "usage", 0))
+	m.MustRegister(fake(r, "settings", 0))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	want := "start:settings start:sync start:usage start:http stop:http stop:usage stop:sync stop:settings"
+	if got := r.list(); got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestManagerEnforcesStopTimeout(t *testing.T) {
+	r := &recorder{}
+	m := New()
+	m.MustRegister(fake(r, "settings", 0))
+	slow := fake(r, "dispatcher", time.Hour, "settings")
+	slow.Stop = func(context.Context) error { // Ignores its context, like a stuck delivery
+		time.Sleep(time.Hour)
+		return nil
+	}
+	slow.StopTimeout = 50 * time.Millisecond
+	slow.InFlight = func() int { return 3 }
+	slow.Work = "deliveries"
+	m.MustRegister(slow)
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	begin := time.Now()
+	err := m.Stop(context.Background())
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected the slow component to be abandoned after its timeout, Stop took %s", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "dispatcher") {
+		t.Errorf("Expected a timeout error naming the dispatcher, got %v", err)
+	}
+	if got := r.list(); !strings.HasSuffix(got, "stop:settings") {
+		t.Errorf("Expected the components after the slow one to still stop, got %s", got)
+	}
+}
+
+func TestManagerStopsStartedComponentsWhenStartFails(t *testing.T) {
+	r := &recorder{}
+	m := New()
+	m.MustRegister(fake(r, "settings", 0))
+	broken := fake(r, "sync", 0, "settings")
+	broken.Start = func(context.Context) error { return errors.New("indexer unreachable") }
+	m.MustRegister(broken)
+	m.MustRegister(fake(r, "http", 0, "sync"))
+
+	if err := m.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "indexer unreachable") {
+		t.Fatalf("Expected the start error, got %v", err)
+	}
+	if got := r.list(); got != "start:settings stop:settings" {
+		t.Errorf("Expected only settings to start and stop again, got %s", got)
+	}
+}
+
+func TestManagerRejectsInvalidDependencies(t *testing.T) {
+	m := New()
+	m.MustRegister(Component{Name: "a", DependsOn: []string{"b"}})
+	m.MustRegister(Component{Name: "b", DependsOn: []string{"a"}})
+	if err := m.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("Expected a dependency cycle error, got %v", err)
+	}
+
+	m = New()
+	m.MustRegister(Component{Name: "a", DependsOn: []string{"missing"}})
+	if err := m.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Expected an unknown dependency error, got %v", err)
+	}
+	if err := m.Register(Component{Name: "a"}); err == nil {
+		t.Error("Expected a duplicate name to be rejected")
+	}
+}
+
+func TestStopMessages(t *testing.T) {
+	if got := stopMessage("webhook dispatcher", 1200*time.Millisecond, 3, "deliveries"); got != "webhook dispatcher: waited 1.2s for 3 deliveries" {
+		t.Errorf("Unexpected message %q", got)
+	}
+	if got := stopMessage("retention", 10*time.Millisecond, 0, ""); got != "retention: stopped in 0.0s" {
+		t.Errorf("Unexpected message %q", got)
+	}
+	if got := timeoutMessage("http server", 15*time.Second, 2, "requests"); got != "http server: gave up stopping after 15.0s with 2 requests in flight" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
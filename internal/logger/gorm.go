@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// GormConfig controls which queries the GORM logger writes
+type GormConfig struct {
+	SlowThreshold time.Duration // Queries at least this slow are logged at Warn; 0 disables
+	SampleRate    float64       // Fraction of other successful queries logged at Info, 0 to 1
+}
+
+// GormLogger writes GORM logs through logrus: failed statements at Error, slow queries
+// at Warn, and a sample of routine queries at Info. Entries carry the request ID of the
+// query context when there is one
+type GormLogger struct {
+	config GormConfig
+	level  gormLogger.LogLevel
+	logger *logrus.Logger
+	sample func() float64
+}
+
+// NewGormLogger creates a GORM logger on the global logger
+func NewGormLogger(config GormConfig) *GormLogger {
+	return &GormLogger{
+		config: config,
+		level:  gormLogger.Warn,
+		logger: GetLogger(),
+		sample: rand.Float64,
+	}
+}
+
+// LogMode returns a copy of the logger at the given GORM level; Silent disables all output
+func (l *GormLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info logs a GORM message at Info level
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Info {
+		l.entry(ctx).Infof(msg, args...)
+	}
+}
+
+// Warn logs a GORM message at Warn level
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		l.entry(ctx).Warnf(msg, args...)
+	}
+}
+
+// Error logs a GORM message at Error level
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormLogger.Error {
+		l.entry(ctx).Errorf(msg, args...)
+	}
+}
+
+// Trace logs a finished statement according to its outcome and duration
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := l.config.SlowThreshold > 0 && elapsed >= l.config.SlowThreshold
+	sampled := l.level >= gormLogger.Info || (l.config.SampleRate > 0 && l.sample() < l.config.SampleRate)
+
+	switch {
+	case failed && l.level >= gormLogger.Error:
+		l.statement(ctx, elapsed, fc).WithError(err).Error("Query failed")
+	case slow && l.level >= gormLogger.Warn:
+		l.statement(ctx, elapsed, fc).
+			WithField("threshold_ms", l.config.SlowThreshold.Milliseconds()).
+			Warn("Slow query detected")
+	case !failed && sampled:
+		l.statement(ctx, elapsed, fc).Info("Query executed")
+	}
+}
+
+// statement builds an entry with the SQL, duration and affected rows of a query
+func (l *GormLogger) statement(ctx context.Context, elapsed time.Duration, fc func() (string, int64)) *logrus.Entry {
+	sql, rows := fc()
+	return l.entry(ctx).WithFields(logrus.Fields{
+		"sql":         sql,
+		"rows":        rows,
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+	})
+}
+
+// entry starts a log entry with the request ID of ctx, if any
+func (l *GormLogger) entry(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(l.logger).WithField("component", "gorm")
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		entry = entry.WithField("request_id", requestID)
+	}
+	return entry
+}
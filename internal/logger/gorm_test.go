@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func newTestGormLogger(config GormConfig) (*GormLogger, *test.Hook) {
+	base, hook := test.NewNullLogger()
+	base.SetLevel(logrus.DebugLevel)
+	l := NewGormLogger(config)
+	l.logger = base
+	return l, hook
+}
+
+func traceQuery(l *GormLogger, ctx context.Context, elapsed time.Duration, err error) {
+	l.Trace(ctx, time.Now().Add(-elapsed), func() (string, int64) { return "SELECT 1", 1 }, err)
+}
+
+func TestGormLoggerLevels(t *testing.T) {
+	l, hook := newTestGormLogger(GormConfig{SlowThreshold: 100 * time.Millisecond})
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	traceQuery(l, ctx, time.Millisecond, nil)
+	if len(hook.AllEntries()) != 0 {
+		t.Fatalf("Expected routine queries to be skipped without sampling, got %d entries", len(hook.AllEntries()))
+	}
+
+	traceQuery(l, ctx, 150*time.Millisecond, nil)
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.WarnLevel || entry.Data["sql"] != "SELECT 1" || entry.Data["request_id"] != "req-1" {
+		t.Fatalf("Expected a warn entry with the statement and request ID, got %+v", entry)
+	}
+
+	traceQuery(l, context.Background(), time.Millisecond, errors.New("syntax error"))
+	entry = hook.LastEntry()
+	if entry.Level != logrus.ErrorLevel || entry.Data["sql"] != "SELECT 1" {
+		t.Errorf("Expected an error entry with the statement, got %+v", entry)
+	}
+	if _, ok := entry.Data["request_id"]; ok {
+		t.Error("Expected no request ID outside a request")
+	}
+
+	hook.Reset()
+	traceQuery(l, ctx, time.Millisecond, gorm.ErrRecordNotFound)
+	if len(hook.AllEntries()) != 0 {
+		t.Error("Expected record-not-found to be treated as routine")
+	}
+
+	silent := l.LogMode(gormLogger.Silent).(*GormLogger)
+	traceQuery(silent, ctx, time.Second, errors.New("ignored"))
+	if len(hook.AllEntries()) != 0 {
+		t.Error("Expected the silent level to log nothing")
+	}
+}
+
+func TestGormLoggerSampling(t *testing.T) {
+	l, hook := newTestGormLogger(GormConfig{SampleRate: 0.25})
+	draws := []float64{0.1, 0.3, 0.24, 0.9}
+	l.sample = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	for i := 0; i < 4; i++ {
+		traceQuery(l, context.Background(), time.Millisecond, nil)
+	}
+	if len(hook.AllEntries()) != 2 {
+		t.Fatalf("Expected the 2 draws below the sample rate to be logged, got %d", len(hook.AllEntries()))
+	}
+	if hook.LastEntry().Level != logrus.InfoLevel {
+		t.Errorf("Expected sampled queries at info level, got %s", hook.LastEntry().Level)
+	}
+}
+
+// TestGormLoggerSlowQuery requires a reachable Postgres, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func TestGormLoggerSlowQuery(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	l, hook := newTestGormLogger(GormConfig{SlowThreshold: 50 * time.Millisecond})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: l})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	hook.Reset()
+
+	ctx := ContextWithRequestID(context.Background(), "req-slow")
+	if err := db.WithContext(ctx).Exec("SELECT pg_sleep(0.1)").Error; err != nil {
+		t.Fatalf("Failed to run query: %v", err)
+	}
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && entry.Data["sql"] == "SELECT pg_sleep(0.1)" {
+			if entry.Data["request_id"] != "req-slow" {
+				t.Errorf("Expected the slow query entry to carry the request ID, got %v", entry.Data["request_id"])
+			}
+			return
+		}
+	}
+	t.Fatalf("Expected a warn entry for the slow query, got %d entries", len(hook.AllEntries()))
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("Failed to open: %v", err)
+	}
+	defer f.Close()
+
+	// Each line fills most of a file, so every write after the first rotates
+	for _, line := range []string{"one----\n", "two----\n", "three--\n", "four---\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	expected := map[string]string{path: "four---\n", path + ".1": "three--\n", path + ".2": "two----\n"}
+	for name, content := range expected {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != content {
+			t.Errorf("Expected %s to hold %q, got %q (%v)", name, content, got, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected backups beyond the limit to be deleted")
+	}
+
+	// Reopening appends to the current file
+	f.Close()
+	f, err = OpenRotatingFile(path, 100, 2)
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	defer f.Close()
+	f.Write([]byte("five\n"))
+	if got, _ := os.ReadFile(path); string(got) != "four---\nfive\n" {
+		t.Errorf("Expected the reopened file to append, got %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+// Package metrics holds the Prometheus collectors exported on /metrics
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Indexer circuit breaker states reported by IndexerBreakerState
+const (
+	BreakerClosed   = 0
+	BreakerHalfOpen = 1
+	BreakerOpen     = 2
+)
+
+var (
+	// IndexerQueryRetries counts indexer reads retried after a transient error
+	IndexerQueryRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sukuk_indexer_query_retries_total",
+		Help: "Indexer reads retried after a transient database error.",
+	})
+
+	// IndexerQueryRejections counts indexer reads refused while the circuit breaker was open
+	IndexerQueryRejections = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sukuk_indexer_query_rejections_total",
+		Help: "Indexer reads refused without querying because the circuit breaker was open.",
+	})
+
+	// IndexerBreakerState is the current circuit breaker state: 0 closed, 1 half-open, 2 open
+	IndexerBreakerState = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sukuk_indexer_breaker_state",
+		Help: "Indexer circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	})
+
+	// IndexerBreakerTransitions counts circuit breaker state changes by the state entered
+	IndexerBreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sukuk_indexer_breaker_transitions_total",
+		Help: "Indexer circuit breaker state changes, labelled by the state entered.",
+	}, []string{"state"})
+
+	// SyncAnomalies counts anomaly flags raised by the sync health monitor
+	SyncAnomalies = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sukuk_sync_anomalies_total",
+		Help: "Sync anomaly flags raised, labelled by sync service and anomaly kind.",
+	}, []string{"service", "kind"})
+
+	// SyncAnomalyActive is 1 while an anomaly flag is raised
+	SyncAnomalyActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sukuk_sync_anomaly_active",
+		Help: "Whether a sync anomaly flag is currently raised (1) or not (0), by sync service and anomaly kind.",
+	}, []string{"service", "kind"})
+
+	// ReorgOrphanedRows counts derived rows marked orphaned by the reorg reconciler
+	ReorgOrphanedRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sukuk_reorg_orphaned_rows_total",
+		Help: "Derived rows marked orphaned after a chain reorg dropped their transaction, labelled by table.",
+	}, []string{"table"})
+)
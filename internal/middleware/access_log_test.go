@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func accessLogRecords(t *testing.T, buf *bytes.Buffer) []AccessLogRecord {
+	t.Helper()
+	var records []AccessLogRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record AccessLogRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid access log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAccessLogScrubsAddresses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	lowercase := strings.ToLower(checksummed)
+
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(AccessLog(AccessLogOptions{Writer: &buf, SuccessSampleRate: 1, ScrubAddresses: true, HashSalt: "pepper"}))
+	router.GET("/portfolio/:address", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	for _, target := range []string{
+		"/portfolio/" + checksummed + "?sukuk_address=" + lowercase,
+		"/portfolio/" + lowercase + "?from=" + checksummed + "&limit=5",
+	} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", "secret-admin-key")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	line := buf.String()
+	for _, raw := range []string{checksummed, lowercase, lowercase[2:], "secret-admin-key"} {
+		if strings.Contains(strings.ToLower(line), strings.ToLower(raw)) {
+			t.Fatalf("Expected %s to be scrubbed, got %s", raw, line)
+		}
+	}
+
+	records := accessLogRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("Expected two records, got %d", len(records))
+	}
+	first, second := records[0], records[1]
+	hash := first.Params["address"]
+	if !strings.HasPrefix(hash, "addr_") {
+		t.Fatalf("Expected a hashed address parameter, got %q", hash)
+	}
+	// Both casings of the wallet hash alike, across requests and in every position
+	if second.Params["address"] != hash || first.Query != "sukuk_address="+hash || second.Query != "from="+hash+"&limit=5" {
+		t.Errorf("Expected the stable hash %s everywhere, got %+v and %+v", hash, first, second)
+	}
+	if first.Route != "/portfolio/:address" || first.Path != "/portfolio/"+hash {
+		t.Errorf("Expected the route template and a scrubbed path, got %q and %q", first.Route, first.Path)
+	}
+	if first.APIKeyID != APIKeyID("secret-admin-key") || first.RequestID == "" || first.Status != http.StatusOK || first.Bytes != 2 {
+		t.Errorf("Expected key id, request id, status and size, got %+v", first)
+	}
+
+	// Another salt gives another hash
+	if scrubbed := newAddressScrubber("salt")(checksummed); scrubbed == hash {
+		t.Error("Expected the hash to depend on the salt")
+	}
+}
+
+func TestAccessLogSamplesSuccessOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	draw := 0.9
+	router := gin.New()
+	router.Use(accessLog(AccessLogOptions{Writer: &buf, SuccessSampleRate: 0.5}, func() float64 { return draw }))
+	router.GET("/status/:code", func(c *gin.Context) {
+		switch c.Param("code") {
+		case "404":
+			c.Status(http.StatusNotFound)
+		case "500":
+			c.Status(http.StatusInternalServerError)
+		default:
+			c.Status(http.StatusOK)
+		}
+	})
+	serve := func(code string) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/"+code, nil))
+	}
+
+	serve("200") // Sampled out
+	serve("404")
+	serve("500")
+	draw = 0.1
+	serve("200") // Sampled in
+
+	var statuses []int
+	for _, record := range accessLogRecords(t, &buf) {
+		statuses = append(statuses, record.Status)
+	}
+	if len(statuses) != 3 || statuses[0] != 404 || statuses[1] != 500 || statuses[2] != 200 {
+		t.Errorf("Expected 404, 500 and the sampled 200, got %v", statuses)
+	}
+
+	// Without scrubbing addresses are logged as sent
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/404?holder=0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil))
+	if !strings.Contains(buf.String(), "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") {
+		t.Errorf("Expected the raw address without scrubbing, got %s", buf.String())
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// UsageRecorder accumulates the responses served to an API key
+type UsageRecorder interface {
+	Record(keyID string, status, bytesOut int)
+}
+
+// APIKeyUsage counts every response served to a valid API key, by its APIKeyID fingerprint.
+// Keys are only known to be valid once the route's auth middleware ran, so requests without
+// one, or with a key that was rejected, are not counted
+func APIKeyUsage(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if !IsAdmin(c) {
+			return
+		}
+		recorder.Record(APIKeyID(extractAPIKey(c)), c.Writer.Status(), c.Writer.Size())
+	}
+}
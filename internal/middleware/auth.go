@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -17,14 +18,7 @@ func APIKeyAuth(apiKey string) gin.HandlerFunc {
 		}
 
 		// Check for API key in header
-		providedKey := c.GetHeader("X-API-Key")
-		if providedKey == "" {
-			// Also check Authorization header with Bearer format
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-				providedKey = strings.TrimPrefix(authHeader, "Bearer ")
-			}
-		}
+		providedKey := extractAPIKey(c)
 
 		if providedKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
@@ -34,7 +28,7 @@ func APIKeyAuth(apiKey string) gin.HandlerFunc {
 			return
 		}
 
-		if providedKey != apiKey {
+		if !apiKeyMatches(providedKey, apiKey) {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid API key",
 			})
@@ -42,6 +36,44 @@ func APIKeyAuth(apiKey string) gin.HandlerFunc {
 			return
 		}
 
+		c.Set(AdminContextKey, true)
 		c.Next()
 	}
+}
+
+// AdminContextKey is set in the gin context when the request carries a valid API key
+const AdminContextKey = "is_admin"
+
+// OptionalAPIKey marks requests with a valid API key as admin without rejecting anonymous requests
+func OptionalAPIKey(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if providedKey := extractAPIKey(c); providedKey != "" && apiKeyMatches(providedKey, apiKey) {
+			c.Set(AdminContextKey, true)
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the request was authenticated with the API key
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(AdminContextKey)
+}
+
+// apiKeyMatches compares a provided key to the API key in constant time, so response timing
+// doesn't reveal how much of a guess was right
+func apiKeyMatches(providedKey, apiKey string) bool {
+	return subtle.ConstantTimeCompare([]byte(providedKey), []byte(apiKey)) == 1
+}
+
+// extractAPIKey reads the API key from X-API-Key or a Bearer Authorization header
+func extractAPIKey(c *gin.Context) string {
+	providedKey := c.GetHeader("X-API-Key")
+	if providedKey == "" {
+		// Also check Authorization header with Bearer format
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
+			providedKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
+	}
+	return providedKey
 }
\ No newline at end of file
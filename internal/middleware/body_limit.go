@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimits bounds request body sizes; zero disables a limit
+type BodyLimits struct {
+	JSON   int64 // Every non-multipart body
+	Upload int64 // multipart/form-data bodies, including all file parts
+}
+
+// BodySizeLimit rejects request bodies over the limit for their content type with 413
+// Declared lengths are checked before anything is read. Other bodies are read up to the
+// limit so handlers never see a truncated payload; multipart bodies are streamed through
+// http.MaxBytesReader instead, and handlers report the overflow with AbortBodyTooLarge
+func BodySizeLimit(limits BodyLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := limits.JSON
+		multipart := isFileUpload(c)
+		if multipart {
+			limit = limits.Upload
+		}
+		if limit <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			AbortBodyTooLarge(c, limit)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		if multipart {
+			c.Set(bodyLimitContextKey, limit)
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if IsBodyTooLarge(err) {
+			AbortBodyTooLarge(c, limit)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+	}
+}
+
+// bodyLimitContextKey holds the limit applied to a streamed multipart body
+const bodyLimitContextKey = "body_limit"
+
+// IsBodyTooLarge reports whether err came from reading past the body size limit
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// AbortBodyTooLarge responds 413 with the applicable limit; limit 0 uses the one set by BodySizeLimit
+func AbortBodyTooLarge(c *gin.Context, limit int64) {
+	if limit == 0 {
+		limit = c.GetInt64(bodyLimitContextKey)
+	}
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":     "Request body too large",
+		"max_bytes": limit,
+	})
+	c.Abort()
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(t *testing.T, uploadDir string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.MaxMultipartMemory = 1 << 10
+	router.Use(BodySizeLimit(BodyLimits{JSON: 64, Upload: 4 << 10}))
+
+	router.POST("/json", func(c *gin.Context) {
+		var payload map[string]interface{}
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	router.POST("/upload", func(c *gin.Context) {
+		file, err := c.FormFile("file")
+		if IsBodyTooLarge(err) {
+			AbortBodyTooLarge(c, 0)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		config := utils.DefaultPDFConfig(uploadDir)
+		if _, _, err := utils.SaveFile(file, config, "1"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+
+	return router
+}
+
+func multipartBody(t *testing.T, size int) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "prospectus.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(bytes.Repeat([]byte("a"), size))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("Expected no files to be left behind, found %s", entry.Name())
+	}
+}
+
+func TestBodySizeLimitJSON(t *testing.T) {
+	router := newBodyLimitRouter(t, t.TempDir())
+	oversized := `{"note":"` + strings.Repeat("x", 100) + `"}`
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		want          int
+	}{
+		{"within limit", `{"note":"ok"}`, 0, http.StatusOK},
+		{"declared length over limit", oversized, 0, http.StatusRequestEntityTooLarge},
+		{"chunked body over limit", oversized, -1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.contentLength != 0 {
+				req.ContentLength = tt.contentLength
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.want == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), "Request body too large") {
+				t.Errorf("Expected a JSON error body, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBodySizeLimitMultipart(t *testing.T) {
+	for _, chunked := range []bool{false, true} {
+		name := "declared length"
+		if chunked {
+			name = "chunked"
+		}
+		t.Run(name, func(t *testing.T) {
+			uploadDir := t.TempDir()
+			router := newBodyLimitRouter(t, uploadDir)
+
+			body, contentType := multipartBody(t, 16<<10)
+			req := httptest.NewRequest(http.MethodPost, "/upload", io.NopCloser(body))
+			req.Header.Set("Content-Type", contentType)
+			if chunked {
+				req.ContentLength = -1
+			} else {
+				req.ContentLength = int64(body.Len())
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+			}
+			assertEmptyDir(t, uploadDir)
+		})
+	}
+}
+
+func TestBodySizeLimitMultipartWithinLimit(t *testing.T) {
+	uploadDir := t.TempDir()
+	router := newBodyLimitRouter(t, uploadDir)
+
+	body, contentType := multipartBody(t, 2<<10)
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	entries, _ := os.ReadDir(uploadDir)
+	if len(entries) != 1 || entries[0].Name() != "sukuk_1_prospectus.pdf" {
+		t.Errorf("Expected only the saved file, found %v", entries)
+	}
+}
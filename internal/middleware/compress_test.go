@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCompressRouter(release <-chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Compress(CompressionOptions{MinSize: 256, Paths: []string{"/api/"}}))
+
+	router.GET("/api/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("sukuk ", 200)})
+	})
+	router.GET("/api/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "sukuk"})
+	})
+	router.GET("/api/pdf", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/pdf", []byte(strings.Repeat("%PDF", 200)))
+	})
+	router.GET("/swagger/doc.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": strings.Repeat("sukuk ", 200)})
+	})
+	router.GET("/api/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Status(http.StatusOK)
+		c.Writer.WriteString("event: ready\ndata: {}\n\n")
+		c.Writer.Flush()
+		<-release
+	})
+
+	return router
+}
+
+func TestCompressGzipsLargeJSON(t *testing.T) {
+	router := newCompressRouter(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/large", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0, br;q=0.5")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected gzip encoding, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+	}
+	if w.Header().Get("Content-Length") != "" {
+		t.Error("Expected no Content-Length on a compressed response")
+	}
+
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Expected a gzip body: %v", err)
+	}
+	var body map[string]string
+	if err := json.NewDecoder(reader).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode the decompressed body: %v", err)
+	}
+	if body["data"] != strings.Repeat("sukuk ", 200) {
+		t.Error("Expected the decompressed body to match the response")
+	}
+}
+
+func TestCompressLeavesResponsesUnchanged(t *testing.T) {
+	router := newCompressRouter(nil)
+
+	tests := []struct {
+		name, path, acceptEncoding string
+		wantVary                   bool
+	}{
+		{"below threshold", "/api/small", "gzip", true},
+		{"already compressed type", "/api/pdf", "gzip", true},
+		{"client without gzip", "/api/large", "br", true},
+		{"gzip refused", "/api/large", "gzip;q=0, identity", true},
+		{"path not enabled", "/swagger/doc.json", "gzip", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != "" {
+				t.Errorf("Expected no Content-Encoding, got %q", got)
+			}
+			if got := w.Header().Get("Vary") == "Accept-Encoding"; got != tt.wantVary {
+				t.Errorf("Expected Vary set to be %v, got %q", tt.wantVary, w.Header().Get("Vary"))
+			}
+			if tt.path != "/api/pdf" && !json.Valid(w.Body.Bytes()) {
+				t.Errorf("Expected a plain JSON body, got %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompressDeflate(t *testing.T) {
+	router := newCompressRouter(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "deflate" {
+		t.Errorf("Expected deflate encoding, got %q", got)
+	}
+}
+
+func TestCompressDoesNotBufferEventStreams(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(newCompressRouter(release))
+	defer server.Close()
+	defer close(release)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Expected the stream to start while the handler is still running: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected an uncompressed stream, got %q", got)
+	}
+
+	// The first event must arrive before the handler returns
+	line, err := bufio.NewReader(io.LimitReader(resp.Body, 64)).ReadString('\n')
+	if err != nil || line != "event: ready\n" {
+		t.Errorf("Expected the first event unbuffered, got %q (%v)", line, err)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                         "",
+		"gzip":                     "gzip",
+		"GZIP":                     "gzip",
+		"deflate":                  "deflate",
+		"deflate, gzip":            "gzip",
+		"gzip;q=0.2, deflate;q=.8": "deflate",
+		"gzip;q=0":                 "",
+		"*":                        "gzip",
+		"br, identity":             "",
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q; want %q", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation marks every response of a deprecated API version with the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers and links to the successor version.
+// A zero deprecatedAt leaves responses untouched, so the middleware can be mounted
+// before the deprecation is announced
+func Deprecation(deprecatedAt, sunsetAt time.Time, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deprecatedAt.IsZero() {
+			c.Next()
+			return
+		}
+
+		c.Header("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+		if !sunsetAt.IsZero() {
+			c.Header("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if successor != "" {
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeprecationHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deprecatedAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.GET("/old", Deprecation(deprecatedAt, sunsetAt, "/api/v2"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/current", Deprecation(time.Time{}, time.Time{}, "/api/v2"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/old", nil))
+
+	if got := w.Header().Get("Deprecation"); got != "@1793491200" {
+		t.Errorf("Expected Deprecation @1793491200, got %q", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Sat, 01 May 2027 00:00:00 GMT" {
+		t.Errorf("Expected Sunset Sat, 01 May 2027 00:00:00 GMT, got %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v2>; rel="successor-version"` {
+		t.Errorf("Expected successor Link header, got %q", got)
+	}
+
+	// Until the deprecation is announced no headers are added
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/current", nil))
+	for _, header := range []string{"Deprecation", "Sunset", "Link"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("Expected no %s header, got %q", header, got)
+		}
+	}
+}
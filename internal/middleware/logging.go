@@ -20,7 +20,9 @@ func RequestLogger() gin.HandlerFunc {
 		method := c.Request.Method
 
 		// Log request
+		requestID := logger.RequestIDFromContext(c.Request.Context())
 		requestLogger := logger.WithFields(logrus.Fields{
+			"request_id": requestID,
 			"method":     method,
 			"path":       path,
 			"query":      c.Request.URL.RawQuery,
@@ -29,20 +31,17 @@ func RequestLogger() gin.HandlerFunc {
 			"referer":    c.Request.Referer(),
 		})
 
-		// Read and restore request body for POST/PUT requests (for debugging)
-		var requestBody string
+		// Peek at short POST/PUT bodies for debugging without buffering large ones or uploads
 		if method == "POST" || method == "PUT" || method == "PATCH" {
-			bodyBytes, err := io.ReadAll(c.Request.Body)
-			if err == nil {
-				requestBody = string(bodyBytes)
+			if isFileUpload(c) {
+				requestLogger = requestLogger.WithField("request_type", "file_upload")
+			} else if c.Request.Body != nil {
+				head, err := io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBodySize))
 				// Restore the body for the actual handler
-				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+				c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
 
-				// Only log body for non-file uploads and keep it reasonably short
-				if len(requestBody) < 1000 && !isFileUpload(c) {
-					requestLogger = requestLogger.WithField("request_body", requestBody)
-				} else if isFileUpload(c) {
-					requestLogger = requestLogger.WithField("request_type", "file_upload")
+				if err == nil && len(head) < maxLoggedBodySize {
+					requestLogger = requestLogger.WithField("request_body", string(head))
 				}
 			}
 		}
@@ -58,6 +57,7 @@ func RequestLogger() gin.HandlerFunc {
 
 		// Determine log level based on status code
 		responseLogger := logger.WithFields(logrus.Fields{
+			"request_id":    requestID,
 			"method":        method,
 			"path":          path,
 			"status":        status,
@@ -82,9 +82,10 @@ func RequestLogger() gin.HandlerFunc {
 			responseLogger.Info("Request completed successfully")
 		}
 
-		// Log slow requests
-		if duration > 1*time.Second {
+		// Log slow requests (long-lived event streams and exports are expected to be slow)
+		if duration > 1*time.Second && !isStreamedResponse(c) {
 			logger.WithFields(logrus.Fields{
+				"request_id":  requestID,
 				"method":      method,
 				"path":        path,
 				"duration_ms": duration.Milliseconds(),
@@ -93,6 +94,15 @@ func RequestLogger() gin.HandlerFunc {
 	}
 }
 
+// maxLoggedBodySize is the length from which request bodies are no longer logged
+const maxLoggedBodySize = 1000
+
+// readCloser reads from a reassembled body and closes the original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 // isFileUpload checks if the request is a file upload
 func isFileUpload(c *gin.Context) bool {
 	contentType := c.Request.Header.Get("Content-Type")
@@ -100,6 +110,12 @@ func isFileUpload(c *gin.Context) bool {
 		strings.HasPrefix(contentType, "multipart/form-data"))
 }
 
+// isStreamedResponse checks if the response is a Server-Sent Events stream or an NDJSON export
+func isStreamedResponse(c *gin.Context) bool {
+	contentType := c.Writer.Header().Get("Content-Type")
+	return strings.HasPrefix(contentType, "text/event-stream") || strings.HasPrefix(contentType, "application/x-ndjson")
+}
+
 // ErrorLogger logs errors that occur during request processing
 func ErrorLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
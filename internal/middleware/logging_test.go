@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLoggerRestoresBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	// Short bodies are logged in full; longer ones are only peeked at
+	for _, body := range []string{`{"a":1}`, strings.Repeat("x", 5000)} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body)))
+
+		if w.Body.String() != body {
+			t.Errorf("Expected the handler to receive all %d bytes, got %d", len(body), w.Body.Len())
+		}
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,7 +24,9 @@ func newRateLimiter(limit int) *rateLimiter {
 	}
 }
 
-func (rl *rateLimiter) allow(key string) bool {
+// allow records a request for key and reports whether it is within the limit
+// along with the number of requests remaining in the current window
+func (rl *rateLimiter) allow(key string) (bool, int) {
 	rl.mutex.Lock()
 	defer rl.mutex.Unlock()
 
@@ -43,12 +46,12 @@ func (rl *rateLimiter) allow(key string) bool {
 
 	// Check if limit exceeded
 	if len(rl.requests[key]) >= rl.limit {
-		return false
+		return false, 0
 	}
 
 	// Add current request
 	rl.requests[key] = append(rl.requests[key], now)
-	return true
+	return true, rl.limit - len(rl.requests[key])
 }
 
 var globalRateLimiter *rateLimiter
@@ -68,7 +71,12 @@ func RateLimit(requestsPerMinute int) gin.HandlerFunc {
 		// Use client IP as key
 		key := c.ClientIP()
 		
-		if !globalRateLimiter.allow(key) {
+		allowed, remaining := globalRateLimiter.allow(key)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(globalRateLimiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(globalRateLimiter.window.Seconds())))
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error": "Rate limit exceeded",
 			})
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readOnlyAllowedMethods are the methods a read-only replica still serves
+const readOnlyAllowedMethods = "GET, HEAD, OPTIONS"
+
+// ReadOnly rejects every request that could mutate state with 405, whatever route or
+// credentials it carries, so a replica stays read-only even if an API key leaks.
+// Mounted globally, it covers admin writes, uploads and webhooks without per-route checks
+func ReadOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isReadMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		rejectMutation(c)
+	}
+}
+
+// RuntimeReadOnly rejects mutating requests like ReadOnly while enabled reports true, so
+// read-only mode can be switched on and off without a restart. Routes in exempt, matched
+// on their registered path, stay writable so the switch itself can be turned back off
+func RuntimeReadOnly(enabled func() bool, exempt ...string) gin.HandlerFunc {
+	exemptRoutes := make(map[string]bool, len(exempt))
+	for _, route := range exempt {
+		exemptRoutes[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if isReadMethod(c.Request.Method) || exemptRoutes[c.FullPath()] || !enabled() {
+			c.Next()
+			return
+		}
+		rejectMutation(c)
+	}
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func rejectMutation(c *gin.Context) {
+	c.Header("Allow", readOnlyAllowedMethods)
+	c.AbortWithStatusJSON(http.StatusMethodNotAllowed, gin.H{
+		"error":   "Method not allowed",
+		"details": "This API is running in read-only mode",
+	})
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRuntimeReadOnlyFollowsSwitch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var enabled atomic.Bool
+	router := gin.New()
+	router.Use(RuntimeReadOnly(enabled.Load, "/settings"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/orders", ok)
+	router.POST("/orders", ok)
+	router.PUT("/settings", ok)
+
+	serve := func(method, path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w.Code
+	}
+
+	if code := serve(http.MethodPost, "/orders"); code != http.StatusOK {
+		t.Errorf("Expected writes while off, got %d", code)
+	}
+
+	enabled.Store(true)
+	if code := serve(http.MethodPost, "/orders"); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for writes while on, got %d", code)
+	}
+	if code := serve(http.MethodGet, "/orders"); code != http.StatusOK {
+		t.Errorf("Expected reads while on, got %d", code)
+	}
+	if code := serve(http.MethodPut, "/settings"); code != http.StatusOK {
+		t.Errorf("Expected the exempt route to stay writable, got %d", code)
+	}
+
+	enabled.Store(false)
+	if code := serve(http.MethodPost, "/orders"); code != http.StatusOK {
+		t.Errorf("Expected writes again once off, got %d", code)
+	}
+}
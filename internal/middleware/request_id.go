@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"sukuk-be/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestIDLength bounds a request ID accepted from the client
+const maxRequestIDLength = 128
+
+// RequestID assigns every request a correlation ID, reusing a well-formed X-Request-ID
+// from the client. The ID is echoed in the response and carried by the request context
+// with the matched route, so logs written further down, including database query logs,
+// can be correlated and queries are tagged with both
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(logger.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(logger.RequestIDHeader, requestID)
+		ctx := logger.ContextWithRequestID(c.Request.Context(), requestID)
+		if route := c.FullPath(); route != "" {
+			ctx = logger.ContextWithRoute(ctx, route)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// isValidRequestID accepts short IDs of letters, digits, '-', '_' and '.'
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
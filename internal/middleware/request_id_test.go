@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, logger.RequestIDFromContext(c.Request.Context()))
+	})
+
+	cases := []struct {
+		header string
+		reused bool
+	}{
+		{"abc-123", true},
+		{"", false},
+		{"bad id\n", false},
+		{strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.header != "" {
+			req.Header.Set(logger.RequestIDHeader, tc.header)
+		}
+		router.ServeHTTP(w, req)
+
+		id := w.Header().Get(logger.RequestIDHeader)
+		if id == "" || w.Body.String() != id {
+			t.Errorf("%q: expected the response header and context to carry the same ID, got %q and %q", tc.header, id, w.Body.String())
+		}
+		if (id == tc.header) != tc.reused {
+			t.Errorf("%q: expected reused=%v, got ID %q", tc.header, tc.reused, id)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WalletAddressContextKey is set in the gin context to the lowercase address a wallet
+// session token was issued to
+const WalletAddressContextKey = "wallet_address"
+
+// RequireWalletAuth rejects requests without a valid wallet session token in a Bearer
+// Authorization header, and on routes with an :address parameter, requests whose token was
+// issued to another address. Every request is refused while no secret is configured
+func RequireWalletAuth(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Wallet authentication not configured",
+			})
+			c.Abort()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Wallet token required",
+			})
+			c.Abort()
+			return
+		}
+		address, err := utils.ParseWalletToken(secret, token, time.Now())
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "Invalid wallet token",
+				"details": err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if param := c.Param("address"); param != "" && !strings.EqualFold(param, address) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Wallet token is for another address",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set(WalletAddressContextKey, address)
+		c.Next()
+	}
+}
+
+// WalletAddress returns the address RequireWalletAuth verified, or "" on other routes
+func WalletAddress(c *gin.Context) string {
+	return c.GetString(WalletAddressContextKey)
+}
+
+// WalletOrAPIKey lets requests with a valid API key through as admin and otherwise requires a
+// wallet session token as RequireWalletAuth does
+func WalletOrAPIKey(apiKey, secret string) gin.HandlerFunc {
+	walletAuth := RequireWalletAuth(secret)
+	return func(c *gin.Context) {
+		if providedKey := extractAPIKey(c); providedKey != "" && apiKey != "" && apiKeyMatches(providedKey, apiKey) {
+			c.Set(AdminContextKey, true)
+			c.Next()
+			return
+		}
+		walletAuth(c)
+	}
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireWalletAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const address = "0x00000000000000000000000000000000000000aa"
+	router := gin.New()
+	router.PUT("/preferences/:address", RequireWalletAuth("secret"), func(c *gin.Context) {
+		c.String(http.StatusOK, WalletAddress(c))
+	})
+	router.POST("/actions", RequireWalletAuth("secret"), func(c *gin.Context) {
+		c.String(http.StatusOK, WalletAddress(c))
+	})
+
+	now := time.Now()
+	valid, _ := utils.NewWalletToken("secret", address, now, now.Add(15*time.Minute))
+	expired, _ := utils.NewWalletToken("secret", address, now.Add(-time.Hour), now.Add(-10*time.Minute))
+	forged, _ := utils.NewWalletToken("other", address, now, now.Add(15*time.Minute))
+
+	tests := []struct {
+		name          string
+		method, path  string
+		authorization string
+		want          int
+	}{
+		{"own address", http.MethodPut, "/preferences/" + address, "Bearer " + valid, http.StatusOK},
+		{"own address checksummed", http.MethodPut, "/preferences/" + strings.ToUpper(address[:4]) + address[4:], "Bearer " + valid, http.StatusOK},
+		{"route without address", http.MethodPost, "/actions", "Bearer " + valid, http.StatusOK},
+		{"another address", http.MethodPut, "/preferences/0x00000000000000000000000000000000000000bb", "Bearer " + valid, http.StatusForbidden},
+		{"missing token", http.MethodPut, "/preferences/" + address, "", http.StatusUnauthorized},
+		{"not a bearer token", http.MethodPut, "/preferences/" + address, valid, http.StatusUnauthorized},
+		{"expired token", http.MethodPut, "/preferences/" + address, "Bearer " + expired, http.StatusUnauthorized},
+		{"forged token", http.MethodPut, "/preferences/" + address, "Bearer " + forged, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.want == http.StatusOK && w.Body.String() != address {
+				t.Errorf("Expected the handler to see %s, got %q", address, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireWalletAuthWithoutSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/actions", RequireWalletAuth(""), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Tokens signed with an empty key must not get through either
+	token, _ := utils.NewWalletToken("", "0x00000000000000000000000000000000000000aa", time.Now(), time.Now().Add(time.Minute))
+	req := httptest.NewRequest(http.MethodPost, "/actions", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without a secret, got %d", w.Code)
+	}
+}
+
+func TestWalletOrAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const address = "0x00000000000000000000000000000000000000aa"
+	router := gin.New()
+	router.GET("/orders", WalletOrAPIKey("api-key", "secret"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"admin": IsAdmin(c), "wallet": WalletAddress(c)})
+	})
+
+	valid, _ := utils.NewWalletToken("secret", address, time.Now(), time.Now().Add(15*time.Minute))
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+		body    string
+	}{
+		{"api key", map[string]string{"X-API-Key": "api-key"}, http.StatusOK, `{"admin":true,"wallet":""}`},
+		{"wallet token", map[string]string{"Authorization": "Bearer " + valid}, http.StatusOK, `{"admin":false,"wallet":"` + address + `"}`},
+		{"wrong api key", map[string]string{"X-API-Key": "guess"}, http.StatusUnauthorized, ""},
+		{"nothing", nil, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("Expected %s, got %s", tt.body, w.Body.String())
+			}
+		})
+	}
+}
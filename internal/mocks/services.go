@@ -0,0 +1,150 @@
+// Package mocks holds hand-written fakes of the service interfaces handlers depend on, so
+// handler tests run without a database. Each method calls the func field of the same name
+// and fails with ErrNotStubbed when it is unset
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/services"
+)
+
+// ErrNotStubbed is returned by a mock method whose func field is nil
+var ErrNotStubbed = errors.New("mock method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%s: %w", method, ErrNotStubbed)
+}
+
+// PortfolioReader is a services.PortfolioReader
+type PortfolioReader struct {
+	GetUserPortfolioFunc            func(ctx context.Context, userAddress string) (*services.UserPortfolio, error)
+	GetPortfolioSummariesFunc       func(ctx context.Context, addresses []string) (map[string]services.PortfolioSummary, error)
+	GetSukukOwnedByAddressFunc      func(ctx context.Context, userAddress string) ([]string, error)
+	GetCurrentBalanceFunc           func(ctx context.Context, userAddress, sukukAddress string) (string, error)
+	GetClaimableYieldFunc           func(ctx context.Context, userAddress, sukukAddress string) (string, error)
+	GetUserShareFunc                func(ctx context.Context, userAddress, sukukAddress string) (string, services.Supply, error)
+	GetUnclaimedDistributionIdsFunc func(ctx context.Context, userAddress, sukukAddress string) ([]int64, error)
+	GetYieldDistributionsFunc       func(ctx context.Context, sukukAddress string, limit int) ([]services.IndexerYieldDistributed, error)
+	GetBalanceHistoryFunc           func(ctx context.Context, holder, sukukAddress string, filter services.BalanceHistoryFilter) ([]models.BalanceChange, int64, error)
+}
+
+var _ services.PortfolioReader = (*PortfolioReader)(nil)
+
+func (m *PortfolioReader) GetUserPortfolio(ctx context.Context, userAddress string) (*services.UserPortfolio, error) {
+	if m.GetUserPortfolioFunc == nil {
+		return nil, notStubbed("GetUserPortfolio")
+	}
+	return m.GetUserPortfolioFunc(ctx, userAddress)
+}
+
+func (m *PortfolioReader) GetPortfolioSummaries(ctx context.Context, addresses []string) (map[string]services.PortfolioSummary, error) {
+	if m.GetPortfolioSummariesFunc == nil {
+		return nil, notStubbed("GetPortfolioSummaries")
+	}
+	return m.GetPortfolioSummariesFunc(ctx, addresses)
+}
+
+func (m *PortfolioReader) GetSukukOwnedByAddress(ctx context.Context, userAddress string) ([]string, error) {
+	if m.GetSukukOwnedByAddressFunc == nil {
+		return nil, notStubbed("GetSukukOwnedByAddress")
+	}
+	return m.GetSukukOwnedByAddressFunc(ctx, userAddress)
+}
+
+func (m *PortfolioReader) GetCurrentBalance(ctx context.Context, userAddress, sukukAddress string) (string, error) {
+	if m.GetCurrentBalanceFunc == nil {
+		return "", notStubbed("GetCurrentBalance")
+	}
+	return m.GetCurrentBalanceFunc(ctx, userAddress, sukukAddress)
+}
+
+func (m *PortfolioReader) GetClaimableYield(ctx context.Context, userAddress, sukukAddress string) (string, error) {
+	if m.GetClaimableYieldFunc == nil {
+		return "", notStubbed("GetClaimableYield")
+	}
+	return m.GetClaimableYieldFunc(ctx, userAddress, sukukAddress)
+}
+
+func (m *PortfolioReader) GetUserShare(ctx context.Context, userAddress, sukukAddress string) (string, services.Supply, error) {
+	if m.GetUserShareFunc == nil {
+		return "", services.Supply{}, notStubbed("GetUserShare")
+	}
+	return m.GetUserShareFunc(ctx, userAddress, sukukAddress)
+}
+
+func (m *PortfolioReader) GetUnclaimedDistributionIds(ctx context.Context, userAddress string, sukukAddress string) ([]int64, error) {
+	if m.GetUnclaimedDistributionIdsFunc == nil {
+		return nil, notStubbed("GetUnclaimedDistributionIds")
+	}
+	return m.GetUnclaimedDistributionIdsFunc(ctx, userAddress, sukukAddress)
+}
+
+func (m *PortfolioReader) GetYieldDistributions(ctx context.Context, sukukAddress string, limit int) ([]services.IndexerYieldDistributed, error) {
+	if m.GetYieldDistributionsFunc == nil {
+		return nil, notStubbed("GetYieldDistributions")
+	}
+	return m.GetYieldDistributionsFunc(ctx, sukukAddress, limit)
+}
+
+func (m *PortfolioReader) GetBalanceHistory(ctx context.Context, holder, sukukAddress string, filter services.BalanceHistoryFilter) ([]models.BalanceChange, int64, error) {
+	if m.GetBalanceHistoryFunc == nil {
+		return nil, 0, notStubbed("GetBalanceHistory")
+	}
+	return m.GetBalanceHistoryFunc(ctx, holder, sukukAddress, filter)
+}
+
+// ActivityReader is a services.ActivityReader
+type ActivityReader struct {
+	GetUserTransactionHistoryFunc func(ctx context.Context, userAddress string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error)
+}
+
+var _ services.ActivityReader = (*ActivityReader)(nil)
+
+func (m *ActivityReader) GetUserTransactionHistory(ctx context.Context, userAddress string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error) {
+	if m.GetUserTransactionHistoryFunc == nil {
+		return nil, notStubbed("GetUserTransactionHistory")
+	}
+	return m.GetUserTransactionHistoryFunc(ctx, userAddress, activityType, limit)
+}
+
+// RedemptionReader is a services.RedemptionReader
+type RedemptionReader struct {
+	GetAllRedemptionsFunc     func(ctx context.Context, filter services.RedemptionListFilter) (*models.RedemptionListResponse, error)
+	GetRedemptionsByUserFunc  func(ctx context.Context, userAddress string) (*models.RedemptionListResponse, error)
+	GetRedemptionsBySukukFunc func(ctx context.Context, sukukAddress string) (*models.RedemptionListResponse, error)
+	GetRedemptionStatsFunc    func(ctx context.Context) (*models.RedemptionStatsResponse, error)
+}
+
+var _ services.RedemptionReader = (*RedemptionReader)(nil)
+
+func (m *RedemptionReader) GetAllRedemptions(ctx context.Context, filter services.RedemptionListFilter) (*models.RedemptionListResponse, error) {
+	if m.GetAllRedemptionsFunc == nil {
+		return nil, notStubbed("GetAllRedemptions")
+	}
+	return m.GetAllRedemptionsFunc(ctx, filter)
+}
+
+func (m *RedemptionReader) GetRedemptionsByUser(ctx context.Context, userAddress string) (*models.RedemptionListResponse, error) {
+	if m.GetRedemptionsByUserFunc == nil {
+		return nil, notStubbed("GetRedemptionsByUser")
+	}
+	return m.GetRedemptionsByUserFunc(ctx, userAddress)
+}
+
+func (m *RedemptionReader) GetRedemptionsBySukuk(ctx context.Context, sukukAddress string) (*models.RedemptionListResponse, error) {
+	if m.GetRedemptionsBySukukFunc == nil {
+		return nil, notStubbed("GetRedemptionsBySukuk")
+	}
+	return m.GetRedemptionsBySukukFunc(ctx, sukukAddress)
+}
+
+func (m *RedemptionReader) GetRedemptionStats(ctx context.Context) (*models.RedemptionStatsResponse, error) {
+	if m.GetRedemptionStatsFunc == nil {
+		return nil, notStubbed("GetRedemptionStats")
+	}
+	return m.GetRedemptionStatsFunc(ctx)
+}
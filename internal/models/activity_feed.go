@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ActivityFeedItem is one event in the platform-wide activity feed
+type ActivityFeedItem struct {
+	ID              string          `json:"id"` // Indexer event id, "<tx_hash>-<log_index>"
+	Type            ActivityType    `json:"type"`
+	Address         string          `json:"address"`       // Buyer or user, EIP-55 checksummed
+	SukukAddress    string          `json:"sukuk_address"` // EIP-55 checksummed
+	SukukCode       string          `json:"sukuk_code"`
+	SukukTitle      string          `json:"sukuk_title"`
+	PaymentToken    string          `json:"payment_token"` // Token the amount is denominated in
+	Amount          string          `json:"amount"`        // Raw amount
+	AmountFormatted FormattedAmount `json:"amount_formatted"`
+	TxHash          string          `json:"tx_hash"`
+	BlockNumber     int64           `json:"block_number"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
+
+// ActivityFeedResponse is a page of the platform-wide activity feed, newest first
+type ActivityFeedResponse struct {
+	Activities []ActivityFeedItem `json:"activities"`
+	NextCursor string             `json:"next_cursor,omitempty"` // Pass as ?cursor= for the next page; empty on the last page
+}
+
+// ActivityExportItem is one line of a sukuk's NDJSON activity export
+type ActivityExportItem struct {
+	ID           string       `json:"id"` // Indexer event id, "<tx_hash>-<log_index>"
+	Type         ActivityType `json:"type"`
+	Address      string       `json:"address"`       // Buyer or user, EIP-55 checksummed
+	PaymentToken string       `json:"payment_token"` // Token the amount is denominated in; empty for claims of unindexed distributions
+	Amount       string       `json:"amount"`        // Raw amount
+	TxHash       string       `json:"tx_hash"`
+	BlockNumber  int64        `json:"block_number"`
+	LogIndex     int64        `json:"log_index"`
+	Timestamp    time.Time    `json:"timestamp"`
+}
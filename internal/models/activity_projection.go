@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ActivityProjection is an indexed purchase, redemption request or yield claim copied into
+// the main database by the activity projector, so activity reads don't depend on which
+// hash-prefixed indexer tables are current. Rows of transactions dropped by a chain reorg
+// are marked orphaned by the reorg reconciler and left out of reads
+type ActivityProjection struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	Type         ActivityType `gorm:"size:32;not null" json:"type"`
+	EventID      string       `gorm:"size:100;not null;index:idx_activity_projections_feed,priority:2" json:"event_id"`       // Indexer event id, "<tx_hash>-<log_index>"
+	Address      string       `gorm:"size:42;not null;index:idx_activity_projections_address_time,priority:1" json:"address"` // Buyer or user
+	SukukAddress string       `gorm:"size:42;not null;index:idx_activity_projections_sukuk_time,priority:1" json:"sukuk_address"`
+	PaymentToken string       `gorm:"size:42" json:"payment_token"` // Empty for claims of unindexed distributions
+	Amount       BigNumeric   `gorm:"type:numeric(78,0);not null" json:"amount"`
+	TxHash       string       `gorm:"size:66;not null;uniqueIndex:idx_activity_projections_tx_log" json:"tx_hash"`
+	LogIndex     uint         `gorm:"not null;uniqueIndex:idx_activity_projections_tx_log" json:"log_index"`
+	BlockNumber  int64        `gorm:"not null" json:"block_number"`
+	Timestamp    int64        `gorm:"not null;index:idx_activity_projections_feed,priority:1;index:idx_activity_projections_address_time,priority:2;index:idx_activity_projections_sukuk_time,priority:2" json:"timestamp"` // Unix seconds, as indexed
+	Status       EventStatus  `gorm:"size:10;not null;default:confirmed" json:"status"`
+}
+
+// TableName returns the table name for ActivityProjection model
+func (ActivityProjection) TableName() string {
+	return "activity_projections"
+}
+
+// BeforeCreate hook to normalize addresses and reject amounts that do not parse
+func (p *ActivityProjection) BeforeCreate(tx *gorm.DB) error {
+	p.Address = normalizeAddress(p.Address)
+	p.SukukAddress = normalizeAddress(p.SukukAddress)
+	p.PaymentToken = normalizeAddress(p.PaymentToken)
+	p.TxHash = strings.ToLower(p.TxHash)
+	return p.Amount.Validate("amount")
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActivityType is the kind of an activity or transaction event
+// It serializes as its lowercase name, which clients already depend on
+type ActivityType string
+
+const (
+	ActivityTypePurchase          ActivityType = "purchase"
+	ActivityTypeRedemptionRequest ActivityType = "redemption_request"
+	ActivityTypeYieldClaim        ActivityType = "yield_claim"
+	ActivityTypeTransferIn        ActivityType = "transfer_in"
+	ActivityTypeTransferOut       ActivityType = "transfer_out"
+)
+
+// ActivityTypeInfo describes where an activity type comes from and how to label it
+type ActivityTypeInfo struct {
+	Type        ActivityType
+	EventTable  string // Indexer event table suffix, as in services.EventTableMapping; empty for derived types
+	DerivedFrom string // Indexer table the type is derived from when it has no event table of its own
+	LabelEN     string
+	LabelID     string
+}
+
+// ActivityTypeRegistry lists every activity type, in the order valid values are reported
+var ActivityTypeRegistry = []ActivityTypeInfo{
+	{Type: ActivityTypePurchase, EventTable: "sukuk_purchase", LabelEN: "Purchase", LabelID: "Pembelian"},
+	{Type: ActivityTypeRedemptionRequest, EventTable: "redemption_request", LabelEN: "Redemption request", LabelID: "Permintaan penebusan"},
+	{Type: ActivityTypeYieldClaim, EventTable: "yield_claim", LabelEN: "Yield claim", LabelID: "Klaim imbal hasil"},
+	{Type: ActivityTypeTransferIn, DerivedFrom: "holder_update", LabelEN: "Transfer in", LabelID: "Transfer masuk"},
+	{Type: ActivityTypeTransferOut, DerivedFrom: "holder_update", LabelEN: "Transfer out", LabelID: "Transfer keluar"},
+}
+
+// EventActivityTypes returns the registered types read from an indexer event table of their
+// own, in registry order
+func EventActivityTypes() []ActivityTypeInfo {
+	types := make([]ActivityTypeInfo, 0, len(ActivityTypeRegistry))
+	for _, info := range ActivityTypeRegistry {
+		if info.EventTable != "" {
+			types = append(types, info)
+		}
+	}
+	return types
+}
+
+// lookup returns the registry entry of the type
+func (t ActivityType) lookup() (ActivityTypeInfo, bool) {
+	for _, info := range ActivityTypeRegistry {
+		if info.Type == t {
+			return info, true
+		}
+	}
+	return ActivityTypeInfo{}, false
+}
+
+// IsValid reports whether the type is registered
+func (t ActivityType) IsValid() bool {
+	_, ok := t.lookup()
+	return ok
+}
+
+// Label returns the human-readable name in the locale, falling back to English
+func (t ActivityType) Label(locale Locale) string {
+	info, ok := t.lookup()
+	if !ok {
+		return string(t)
+	}
+	if locale == LocaleID {
+		return info.LabelID
+	}
+	return info.LabelEN
+}
+
+// IsTransfer reports whether the type is a balance change between wallets, derived from
+// holder updates no purchase, redemption request or yield claim explains
+func (t ActivityType) IsTransfer() bool {
+	return t == ActivityTypeTransferIn || t == ActivityTypeTransferOut
+}
+
+// EventTable returns the indexer event table the type is read from, empty for derived types
+func (t ActivityType) EventTable() string {
+	info, _ := t.lookup()
+	return info.EventTable
+}
+
+// ActivityTypeForEventTable returns the activity type read from an indexer event table
+func ActivityTypeForEventTable(eventTable string) (ActivityType, bool) {
+	for _, info := range EventActivityTypes() {
+		if info.EventTable == eventTable {
+			return info.Type, true
+		}
+	}
+	return "", false
+}
+
+// ValidActivityTypes returns the names of every registered type
+func ValidActivityTypes() []string {
+	names := make([]string, len(ActivityTypeRegistry))
+	for i, info := range ActivityTypeRegistry {
+		names[i] = string(info.Type)
+	}
+	return names
+}
+
+// ParseActivityType validates a type name, listing the valid values when it is unknown
+func ParseActivityType(value string) (ActivityType, error) {
+	activityType := ActivityType(strings.ToLower(strings.TrimSpace(value)))
+	if !activityType.IsValid() {
+		return "", fmt.Errorf("unknown activity type %q, valid values are: %s", value, strings.Join(ValidActivityTypes(), ", "))
+	}
+	return activityType, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestActivityTypeSerializesAsLowercaseString(t *testing.T) {
+	data, err := json.Marshal(ActivityEvent{Type: ActivityTypeRedemptionRequest})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"type":"redemption_request"`) {
+		t.Errorf("Expected the type to stay a plain string, got %s", data)
+	}
+
+	var event TransactionEvent
+	if err := json.Unmarshal([]byte(`{"type":"yield_claim"}`), &event); err != nil || event.Type != ActivityTypeYieldClaim {
+		t.Errorf("Expected yield_claim to decode, got %q (%v)", event.Type, err)
+	}
+}
+
+func TestParseActivityType(t *testing.T) {
+	if got, err := ParseActivityType(" Purchase "); err != nil || got != ActivityTypePurchase {
+		t.Errorf("Expected purchase, got %q (%v)", got, err)
+	}
+	_, err := ParseActivityType("swap")
+	if err == nil {
+		t.Fatal("Expected an unknown type to be rejected")
+	}
+	for _, name := range ValidActivityTypes() {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected the error to list %q, got %v", name, err)
+		}
+	}
+}
+
+func TestActivityTypeLabelsAndTables(t *testing.T) {
+	if got := ActivityTypeYieldClaim.Label(LocaleID); got != "Klaim imbal hasil" {
+		t.Errorf("Expected the Indonesian label, got %q", got)
+	}
+	if got := ActivityTypeYieldClaim.Label(LocaleEN); got != "Yield claim" {
+		t.Errorf("Expected the English label, got %q", got)
+	}
+	for _, info := range EventActivityTypes() {
+		if got, ok := ActivityTypeForEventTable(info.EventTable); !ok || got != info.Type {
+			t.Errorf("Expected %q to map back to %q, got %q", info.EventTable, info.Type, got)
+		}
+	}
+
+	// Transfers are derived from holder updates, which stay a non-activity table
+	if _, ok := ActivityTypeForEventTable("holder_update"); ok {
+		t.Error("Expected holder_update not to map to an activity type")
+	}
+	if _, err := ParseActivityType("transfer_in"); err != nil || !ActivityTypeTransferOut.IsTransfer() || ActivityTypePurchase.IsTransfer() {
+		t.Errorf("Expected transfer types to be valid filters, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fiatFloatFields are Rupiah amounts with two decimals capped by DECIMAL(20,2), far below the
+// 1e21 at which encoding/json switches to exponent notation, so they may stay float64
+var fiatFloatFields = map[string]bool{
+	"MinimumPembelian":  true,
+	"MaksimumPembelian": true,
+	"FiatAmount":        true,
+}
+
+// financialResponses are the payloads covered by the amount formatting policy
+var financialResponses = []interface{}{
+	PortfolioResponse{},
+	YieldClaimsResponse{},
+	YieldDistributionsResponse{},
+	TransactionHistoryResponse{},
+	HoldingCalculation{},
+	RedemptionListResponse{},
+	RedemptionStatsResponse{},
+	DistributionPreview{},
+	DigestResponse{},
+	SukukAvailability{},
+	BalanceHistoryResponse{},
+	ActivityFeedResponse{},
+	SukukMetadataListResponse{},
+	SukukMetadataResponse{},
+	SukukTimeSeriesResponse{},
+	Order{},
+}
+
+// floatFields returns the dotted paths of float fields reachable from t
+func floatFields(t reflect.Type, path string, seen map[reflect.Type]bool, found *[]string) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		floatFields(t.Elem(), path, seen, found)
+	case reflect.Float32, reflect.Float64:
+		*found = append(*found, path)
+	case reflect.Struct:
+		if seen[t] || t == reflect.TypeOf(time.Time{}) {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if fiatFloatFields[field.Name] || field.Tag.Get("json") == "-" {
+				continue
+			}
+			floatFields(field.Type, path+"."+field.Name, seen, found)
+		}
+	}
+}
+
+func TestFinancialResponsesHaveNoFloatAmounts(t *testing.T) {
+	for _, response := range financialResponses {
+		var found []string
+		typ := reflect.TypeOf(response)
+		floatFields(typ, typ.Name(), map[reflect.Type]bool{}, &found)
+		for _, path := range found {
+			t.Errorf("%s is a float; amounts must be decimal strings and percentages fixed 2-decimal strings", path)
+		}
+	}
+}
+
+var exponentPattern = regexp.MustCompile(`^-?\d+(\.\d+)?[eE][-+]?\d+$`)
+
+// assertNoExponent walks decoded JSON and fails on any number or numeric string with an exponent
+func assertNoExponent(t *testing.T, name string, value interface{}) {
+	t.Helper()
+	switch v := value.(type) {
+	case json.Number:
+		if strings.ContainsAny(v.String(), "eE") {
+			t.Errorf("%s: number %s uses exponent notation", name, v)
+		}
+	case string:
+		if exponentPattern.MatchString(v) {
+			t.Errorf("%s: string %q uses exponent notation", name, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			assertNoExponent(t, name, item)
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			assertNoExponent(t, name+"."+key, item)
+		}
+	}
+}
+
+func TestFinancialResponsesRoundTripWithoutExponents(t *testing.T) {
+	huge := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	quota := NewDecimal(new(big.Rat).SetFrac(huge, big.NewInt(3)))
+	samples := map[string]interface{}{
+		"sukuk": SukukMetadataListResponse{
+			KuotaNasional:     quota,
+			MinimumPembelian:  1000000,
+			MaksimumPembelian: 99999999999999999.99,
+		},
+		"availability": SukukAvailability{
+			Cap:               huge.String(),
+			TotalPurchased:    huge.String(),
+			Remaining:         "0",
+			PercentSubscribed: "100.00",
+		},
+		"redemptions": RedemptionListResponse{
+			Redemptions:           []RedemptionRequest{{Amount: huge.String(), TotalSupply: huge.String()}},
+			TotalRedemptionAmount: huge.String(),
+		},
+		"holding": HoldingCalculation{CurrentBalance: huge.String(), DistributionShare: "33.33"},
+		"order":   Order{FiatAmount: 99999999999999999.99, TokenAmount: NewBigNumeric(huge)},
+	}
+
+	for name, sample := range samples {
+		data, err := json.Marshal(sample)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal: %v", name, err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			t.Fatalf("%s: failed to decode: %v", name, err)
+		}
+		assertNoExponent(t, name, decoded)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var request SukukMetadataUpdateRequest
+	if err := json.Unmarshal([]byte(`{"kuota_nasional": 7e12}`), &request); err != nil {
+		t.Fatalf("Expected a JSON number to be accepted: %v", err)
+	}
+	if request.KuotaNasional == nil || *request.KuotaNasional != "7000000000000" {
+		t.Errorf("Expected 7e12 to normalize to 7000000000000, got %v", request.KuotaNasional)
+	}
+	if err := json.Unmarshal([]byte(`{"kuota_nasional": "1.234,500"}`), &request); err != nil || *request.KuotaNasional != "1234.5" {
+		t.Errorf("Expected an Indonesian string to be accepted and trimmed, got %v (%v)", request.KuotaNasional, err)
+	}
+	for _, invalid := range []string{`"-1"`, `"abc"`, `"1/3"`, `"1234.500"`, `"0,0000000000000000001"`, `-1`} {
+		if err := json.Unmarshal([]byte(`{"kuota_nasional": `+invalid+`}`), &request); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+
+	data, _ := json.Marshal(SukukMetadataListResponse{KuotaNasional: "1500000000.25"})
+	if !bytes.Contains(data, []byte(`"kuota_nasional":"1500000000.25"`)) {
+		t.Errorf("Expected kuota_nasional as a decimal string, got %s", data)
+	}
+}
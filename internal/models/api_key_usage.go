@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// APIKeyUsage is the traffic of one API key in one hour, flushed from in-memory counters
+type APIKeyUsage struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	KeyID     string    `gorm:"size:12;not null;uniqueIndex:idx_api_key_usage_key_hour" json:"-"` // middleware.APIKeyID fingerprint
+	Hour      time.Time `gorm:"not null;uniqueIndex:idx_api_key_usage_key_hour" json:"hour"`      // Start of the hour, UTC
+	Requests  int64     `gorm:"not null;default:0" json:"requests"`
+	Errors    int64     `gorm:"not null;default:0" json:"errors"`    // Responses with status 400 or above
+	BytesOut  int64     `gorm:"not null;default:0" json:"bytes_out"` // Response body bytes
+	UpdatedAt time.Time `json:"-"`
+}
+
+// TableName overrides the table name
+func (APIKeyUsage) TableName() string {
+	return "api_key_usage"
+}
+
+// APIKeyUsageTotals sums the usage of a key over a window
+type APIKeyUsageTotals struct {
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// APIKeyUsageReport is the hourly usage of an API key over [from, to), oldest hour first.
+// Hours without traffic are left out
+type APIKeyUsageReport struct {
+	KeyID  string            `json:"key_id"`
+	From   time.Time         `json:"from"`
+	To     time.Time         `json:"to"`
+	Hours  []APIKeyUsage     `json:"hours"`
+	Totals APIKeyUsageTotals `json:"totals"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionView   = "view"
+)
+
+// AuditLog records administrative writes for compliance review
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Action     string    `gorm:"size:50;not null;index" json:"action"`
+	EntityType string    `gorm:"size:50;not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string    `gorm:"size:100;not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Actor      string    `gorm:"size:100" json:"actor"`
+	Details    string    `gorm:"type:text" json:"details,omitempty"` // JSON-encoded change details
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName returns the table name for AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// RecordAudit writes an audit log entry, encoding details as JSON
+// Pass the transaction used for the write so the entry commits or rolls back with it
+func RecordAudit(db *gorm.DB, action, entityType, entityID, actor string, details interface{}) error {
+	entry := AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Actor:      actor,
+	}
+
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		entry.Details = string(encoded)
+	}
+
+	return db.Create(&entry).Error
+}
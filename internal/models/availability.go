@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// SukukAvailability is how much of a sukuk's national quota is still open for subscription
+// Amounts are raw values in the smallest unit of the sukuk token
+type SukukAvailability struct {
+	SukukMetadataID     uint       `json:"sukuk_metadata_id"`
+	ContractAddress     string     `json:"contract_address"`
+	Unlimited           bool       `json:"unlimited"`                    // No kuota_nasional is set
+	Cap                 string     `json:"cap,omitempty"`                // kuota_nasional scaled by the token decimals
+	TotalPurchased      string     `json:"total_purchased"`              // Sum of indexed purchase events
+	Remaining           string     `json:"remaining,omitempty"`          // Never negative
+	PercentSubscribed   string     `json:"percent_subscribed,omitempty"` // "0.00" - "100.00", above 100 when oversubscribed
+	PurchasePeriod      string     `json:"purchase_period"`
+	PurchasePeriodStart *time.Time `json:"purchase_period_start,omitempty"`
+	PurchasePeriodEnd   *time.Time `json:"purchase_period_end,omitempty"` // Exclusive
+	PurchasePeriodOpen  *bool      `json:"purchase_period_open"`          // Null when periode_pembelian can't be parsed
+
+	// Fiat equivalents of cap, total_purchased and remaining, with ?fiat
+	DisplayValues map[string]DisplayValues `json:"display_values,omitempty"`
+	Meta          *DisplayMeta             `json:"meta,omitempty"` // Exchange rates, with ?fiat
+}
@@ -1,24 +1,30 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDuplicateEvent is returned when an event with the same tx hash and log index was already stored
+var ErrDuplicateEvent = errors.New("event already exists")
+
 // SukukPurchased represents a sukuk purchase event from the blockchain
 type SukukPurchased struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
 	Buyer         string         `gorm:"size:42;not null;index" json:"buyer"`
 	SukukAddress  string         `gorm:"size:42;not null;index" json:"sukuk_address"`
 	PaymentToken  string         `gorm:"size:42;not null" json:"payment_token"`
-	Amount        string         `gorm:"size:78;not null" json:"amount"`
+	Amount        BigNumeric     `gorm:"type:numeric(78,0);not null" json:"amount"`
 	BlockNumber   uint64         `gorm:"not null;index" json:"block_number"`
-	TxHash        string         `gorm:"size:66;not null;index" json:"tx_hash"`
-	LogIndex      uint           `gorm:"not null" json:"log_index"`
+	TxHash        string         `gorm:"size:66;not null;uniqueIndex:idx_sukuk_purchased_tx_log" json:"tx_hash"`
+	LogIndex      uint           `gorm:"not null;uniqueIndex:idx_sukuk_purchased_tx_log" json:"log_index"`
 	Timestamp     time.Time      `gorm:"not null;index" json:"timestamp"`
 	Processed     bool           `gorm:"default:false;index" json:"processed"`
 	ProcessedAt   *time.Time     `json:"processed_at,omitempty"`
+	Status        EventStatus    `gorm:"size:10;not null;default:confirmed;index" json:"status"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -29,12 +35,12 @@ func (SukukPurchased) TableName() string {
 	return "sukuk_purchased_events"
 }
 
-// BeforeCreate hook to normalize addresses
+// BeforeCreate hook to normalize addresses and reject amounts that do not parse
 func (sp *SukukPurchased) BeforeCreate(tx *gorm.DB) error {
 	sp.Buyer = normalizeAddress(sp.Buyer)
 	sp.SukukAddress = normalizeAddress(sp.SukukAddress)
 	sp.PaymentToken = normalizeAddress(sp.PaymentToken)
-	return nil
+	return sp.Amount.Validate("amount")
 }
 
 // RedemptionRequested represents a redemption request event from the blockchain
@@ -42,15 +48,16 @@ type RedemptionRequested struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
 	User          string         `gorm:"size:42;not null;index" json:"user"`
 	SukukAddress  string         `gorm:"size:42;not null;index" json:"sukuk_address"`
-	Amount        string         `gorm:"size:78;not null" json:"amount"`
+	Amount        BigNumeric     `gorm:"type:numeric(78,0);not null" json:"amount"`
 	PaymentToken  string         `gorm:"size:42;not null" json:"payment_token"`
-	TotalSupply   string         `gorm:"size:78;not null" json:"total_supply"`
+	TotalSupply   BigNumeric     `gorm:"type:numeric(78,0);not null" json:"total_supply"`
 	BlockNumber   uint64         `gorm:"not null;index" json:"block_number"`
-	TxHash        string         `gorm:"size:66;not null;index" json:"tx_hash"`
-	LogIndex      uint           `gorm:"not null" json:"log_index"`
+	TxHash        string         `gorm:"size:66;not null;uniqueIndex:idx_redemption_requested_tx_log" json:"tx_hash"`
+	LogIndex      uint           `gorm:"not null;uniqueIndex:idx_redemption_requested_tx_log" json:"log_index"`
 	Timestamp     time.Time      `gorm:"not null;index" json:"timestamp"`
 	Processed     bool           `gorm:"default:false;index" json:"processed"`
 	ProcessedAt   *time.Time     `json:"processed_at,omitempty"`
+	Status        EventStatus    `gorm:"size:10;not null;default:confirmed;index" json:"status"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -61,12 +68,15 @@ func (RedemptionRequested) TableName() string {
 	return "redemption_requested_events"
 }
 
-// BeforeCreate hook to normalize addresses
+// BeforeCreate hook to normalize addresses and reject amounts that do not parse
 func (rr *RedemptionRequested) BeforeCreate(tx *gorm.DB) error {
 	rr.User = normalizeAddress(rr.User)
 	rr.SukukAddress = normalizeAddress(rr.SukukAddress)
 	rr.PaymentToken = normalizeAddress(rr.PaymentToken)
-	return nil
+	if err := rr.Amount.Validate("amount"); err != nil {
+		return err
+	}
+	return rr.TotalSupply.Validate("total_supply")
 }
 
 // RedemptionApproved represents a redemption approval event from the blockchain
@@ -74,8 +84,8 @@ type RedemptionApproved struct {
 	ID            uint           `gorm:"primaryKey" json:"id"`
 	User          string         `gorm:"size:42;not null;index" json:"user"`
 	SukukAddress  string         `gorm:"size:42;not null;index" json:"sukuk_address"`
-	Amount        string         `gorm:"size:78;not null" json:"amount"`
-	TotalSupply   string         `gorm:"size:78;not null" json:"total_supply"`
+	Amount        BigNumeric     `gorm:"type:numeric(78,0);not null" json:"amount"`
+	TotalSupply   BigNumeric     `gorm:"type:numeric(78,0);not null" json:"total_supply"`
 	BlockNumber   uint64         `gorm:"not null;index" json:"block_number"`
 	TxHash        string         `gorm:"size:66;not null;index" json:"tx_hash"`
 	LogIndex      uint           `gorm:"not null" json:"log_index"`
@@ -92,18 +102,21 @@ func (RedemptionApproved) TableName() string {
 	return "redemption_approved_events"
 }
 
-// BeforeCreate hook to normalize addresses
+// BeforeCreate hook to normalize addresses and reject amounts that do not parse
 func (ra *RedemptionApproved) BeforeCreate(tx *gorm.DB) error {
 	ra.User = normalizeAddress(ra.User)
 	ra.SukukAddress = normalizeAddress(ra.SukukAddress)
-	return nil
+	if err := ra.Amount.Validate("amount"); err != nil {
+		return err
+	}
+	return ra.TotalSupply.Validate("total_supply")
 }
 
 
-// GetUnprocessedSukukPurchases retrieves unprocessed sukuk purchase events
+// GetUnprocessedSukukPurchases retrieves unprocessed sukuk purchase events, skipping orphaned ones
 func GetUnprocessedSukukPurchases(db *gorm.DB, limit int) ([]SukukPurchased, error) {
 	var events []SukukPurchased
-	query := db.Where("processed = ?", false).
+	query := db.Where("processed = ? AND status <> ?", false, EventStatusOrphaned).
 		Order("block_number ASC, log_index ASC")
 	
 	if limit > 0 {
@@ -114,10 +127,10 @@ func GetUnprocessedSukukPurchases(db *gorm.DB, limit int) ([]SukukPurchased, err
 	return events, err
 }
 
-// GetUnprocessedRedemptionRequests retrieves unprocessed redemption request events
+// GetUnprocessedRedemptionRequests retrieves unprocessed redemption request events, skipping orphaned ones
 func GetUnprocessedRedemptionRequests(db *gorm.DB, limit int) ([]RedemptionRequested, error) {
 	var events []RedemptionRequested
-	query := db.Where("processed = ?", false).
+	query := db.Where("processed = ? AND status <> ?", false, EventStatusOrphaned).
 		Order("block_number ASC, log_index ASC")
 	
 	if limit > 0 {
@@ -164,4 +177,34 @@ func GetRedemptionRequestByTxHashAndLogIndex(db *gorm.DB, txHash string, logInde
 		return nil, err
 	}
 	return &event, nil
-}
\ No newline at end of file
+}
+
+// eventConflictClause skips inserts that collide on the (tx_hash, log_index) unique index
+var eventConflictClause = clause.OnConflict{
+	Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+	DoNothing: true,
+}
+
+// CreateSukukPurchaseEvent stores a sukuk purchase event, returning ErrDuplicateEvent if it was already stored
+func CreateSukukPurchaseEvent(db *gorm.DB, event *SukukPurchased) error {
+	result := db.Clauses(eventConflictClause).Create(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicateEvent
+	}
+	return nil
+}
+
+// CreateRedemptionRequestEvent stores a redemption request event, returning ErrDuplicateEvent if it was already stored
+func CreateRedemptionRequestEvent(db *gorm.DB, event *RedemptionRequested) error {
+	result := db.Clauses(eventConflictClause).Create(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicateEvent
+	}
+	return nil
+}
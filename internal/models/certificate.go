@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Certificate is an investment certificate issued to an investor for one sukuk. It keeps the
+// values printed on the document, so a certificate still verifies after the balance changes
+type Certificate struct {
+	ID              uint         `gorm:"primaryKey" json:"-"`
+	Code            string       `gorm:"size:32;uniqueIndex;not null" json:"code"` // Verification code printed on the document
+	InvestorAddress string       `gorm:"size:42;not null;index" json:"investor_address"`
+	SukukAddress    string       `gorm:"size:42;not null;index" json:"sukuk_address"`
+	SukukCode       string       `gorm:"size:20" json:"sukuk_code"`
+	SukukTitle      string       `gorm:"size:100" json:"sukuk_title"`
+	IssuerAddress   string       `gorm:"size:42" json:"issuer_address"` // Sukuk owner
+	Balance         string       `gorm:"type:numeric(78,0);not null" json:"balance"`
+	TotalSupply     string       `gorm:"type:numeric(78,0);not null" json:"total_supply"`    // 0 when the supply was unknown
+	SharePercentage string       `gorm:"size:12" json:"share_percentage"`                    // Balance as a percentage of the supply, e.g. 2.50; empty when the supply was unknown
+	SupplySource    SupplySource `gorm:"size:20;not null;default:none" json:"supply_source"` // Where TotalSupply was read from
+	IssuedAt        time.Time    `gorm:"not null" json:"issued_at"`
+}
+
+// TableName returns the table name for Certificate model
+func (Certificate) TableName() string {
+	return "certificates"
+}
+
+// BeforeSave hook to normalize the addresses
+func (c *Certificate) BeforeSave(tx *gorm.DB) error {
+	c.InvestorAddress = normalizeAddress(c.InvestorAddress)
+	c.SukukAddress = normalizeAddress(c.SukukAddress)
+	c.IssuerAddress = normalizeAddress(c.IssuerAddress)
+	return nil
+}
+
+// CertificateVerification is the result of looking up a certificate by its verification code
+type CertificateVerification struct {
+	Valid       bool         `json:"valid"`
+	Certificate *Certificate `json:"certificate"` // The values as issued, not the current balance
+}
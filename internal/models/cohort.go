@@ -0,0 +1,35 @@
+package models
+
+// CohortMetric is what an investor cohort counts in each month after its first purchases
+type CohortMetric string
+
+const (
+	CohortMetricRepeatPurchase  CohortMetric = "repeat_purchase"  // Investors with a purchase in the month
+	CohortMetricRetainedBalance CohortMetric = "retained_balance" // Investors holding a balance at the end of the month
+)
+
+// IsValid reports whether m is a known cohort metric
+func (m CohortMetric) IsValid() bool {
+	return m == CohortMetricRepeatPurchase || m == CohortMetricRetainedBalance
+}
+
+// CohortMaxMonths caps the months of first purchases a cohort analysis covers
+const CohortMaxMonths = 24
+
+// InvestorCohort is one row of the cohort heatmap: the investors whose first purchase fell in
+// a month, and how many of them matched the metric in each month since
+type InvestorCohort struct {
+	Cohort string    `json:"cohort"` // Month of first purchase, YYYY-MM in Jakarta time
+	Size   int       `json:"size"`   // Investors whose first purchase was in the month
+	Values []int     `json:"values"` // Investors matching the metric, index N being N months after the cohort month, up to the end of the range
+	Rates  []float64 `json:"rates"`  // Values as a fraction of size, to four decimal places
+}
+
+// CohortAnalyticsResponse is a cohort heatmap of investors by month of first purchase
+type CohortAnalyticsResponse struct {
+	Metric   CohortMetric     `json:"metric"`
+	From     string           `json:"from"`     // First cohort month, YYYY-MM
+	To       string           `json:"to"`       // Last cohort and activity month, YYYY-MM
+	Timezone string           `json:"timezone"` // Timezone months are taken in
+	Cohorts  []InvestorCohort `json:"cohorts"`  // One per month from From to To, oldest first
+}
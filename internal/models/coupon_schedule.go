@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// CouponStatus is the state of a scheduled coupon
+type CouponStatus string
+
+const (
+	CouponStatusPaid     CouponStatus = "paid"     // A yield distribution landed within the grace window
+	CouponStatusUpcoming CouponStatus = "upcoming" // Not yet due, or due and still within the grace window
+	CouponStatusMissed   CouponStatus = "missed"   // The grace window passed without a distribution
+)
+
+// CouponDistribution is an indexed yield distribution of a sukuk
+type CouponDistribution struct {
+	DistributionID int64     `json:"distribution_id"`
+	PaymentToken   string    `json:"payment_token"`
+	Amount         string    `json:"amount"` // Raw payment token amount
+	TxHash         string    `json:"tx_hash"`
+	PaidAt         time.Time `json:"paid_at"`
+}
+
+// ScheduledCoupon is one expected coupon payment of a sukuk
+type ScheduledCoupon struct {
+	Number        int                 `json:"number"` // 1 for kupon_pertama
+	ScheduledDate time.Time           `json:"scheduled_date"`
+	Status        CouponStatus        `json:"status"`
+	Distribution  *CouponDistribution `json:"distribution,omitempty"` // Set when paid
+}
+
+// CouponScheduleResponse is the expected coupon calendar of a sukuk overlaid with its distributions
+type CouponScheduleResponse struct {
+	SukukMetadataID    uint                 `json:"sukuk_metadata_id"`
+	ContractAddress    string               `json:"contract_address"`
+	Estimated          bool                 `json:"estimated"`           // False when kupon_pertama or the payment frequency is unknown, leaving coupons empty
+	Frequency          string               `json:"frequency,omitempty"` // monthly, quarterly, semiannual or annual
+	GracePeriod        string               `json:"grace_period"`        // How late a distribution may land and still pay a coupon
+	NextCouponDate     *time.Time           `json:"next_coupon_date,omitempty"`
+	Coupons            []ScheduledCoupon    `json:"coupons"`
+	ExtraDistributions []CouponDistribution `json:"extra_distributions"` // Distributions matching no scheduled coupon
+}
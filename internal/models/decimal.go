@@ -0,0 +1,147 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DecimalScale is the number of fractional digits a Decimal keeps, matching NUMERIC(78,18)
+const DecimalScale = 18
+
+// Decimal is an exact non-negative decimal, such as a quantity in whole token units, stored
+// as NUMERIC(78,18). It serializes to JSON as a plain decimal string without an exponent;
+// requests may send it either as a string or as a JSON number
+type Decimal string
+
+// NewDecimal formats a rational exactly, rounding only beyond DecimalScale digits
+func NewDecimal(value *big.Rat) Decimal {
+	if value == nil {
+		return "0"
+	}
+	s := value.FloatString(DecimalScale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return Decimal(s)
+}
+
+// ParseDecimal parses a decimal, accepting exponent notation on input but rejecting
+// negative values and more than DecimalScale fractional digits
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+	rat, ok := new(big.Rat).SetString(value)
+	if value == "" || !ok || strings.Contains(value, "/") {
+		return "", fmt.Errorf("invalid decimal %q", value)
+	}
+	if rat.Sign() < 0 {
+		return "", fmt.Errorf("decimal %q must not be negative", value)
+	}
+	d := NewDecimal(rat)
+	if exact, _ := d.Rat(); exact.Cmp(rat) != 0 {
+		return "", fmt.Errorf("decimal %q has more than %d fractional digits", value, DecimalScale)
+	}
+	return d, nil
+}
+
+// Rat returns the value as a rational; the empty Decimal is zero
+func (d Decimal) Rat() (*big.Rat, error) {
+	if d == "" {
+		return new(big.Rat), nil
+	}
+	rat, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %q", string(d))
+	}
+	return rat, nil
+}
+
+// Sign returns -1, 0 or 1, treating values that do not parse as zero
+func (d Decimal) Sign() int {
+	rat, err := d.Rat()
+	if err != nil {
+		return 0
+	}
+	return rat.Sign()
+}
+
+// String returns the decimal representation, "0" for the empty Decimal
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+	return string(d)
+}
+
+// GormDataType maps Decimal columns to NUMERIC(78,18) in AutoMigrate
+func (Decimal) GormDataType() string {
+	return "numeric(78,18)"
+}
+
+// Value implements driver.Valuer, refusing values that do not parse
+func (d Decimal) Value() (driver.Value, error) {
+	parsed, err := ParseDecimal(d.String())
+	if err != nil {
+		return nil, err
+	}
+	return string(parsed), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC, text and numeric columns
+func (d *Decimal) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*d = ""
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes the value as a decimal string
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	raw := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(raw)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// DigestResponse summarizes what happened to an address's holdings during a window,
+// for the notification worker to render as a daily digest
+// Amounts are raw values in the smallest unit of their token
+type DigestResponse struct {
+	Address            string                    `json:"address"`
+	Since              time.Time                 `json:"since"`          // Inclusive
+	Until              time.Time                 `json:"until"`          // Exclusive, and the start of the next digest
+	SinceProvided      bool                      `json:"since_provided"` // An explicit since does not advance last_digest_at
+	YieldDistributions []DigestYieldDistribution `json:"yield_distributions"`
+	RedemptionUpdates  []DigestRedemptionUpdate  `json:"redemption_updates"`
+	BalanceChanges     []DigestBalanceChange     `json:"balance_changes"`
+	UpcomingMaturities []DigestMaturity          `json:"upcoming_maturities"`
+	// From the address's notification preferences; the worker sends only when Deliver is true
+	Deliver bool   `json:"deliver"` // Digests are enabled and there is something to send
+	Email   string `json:"email,omitempty"`
+	Locale  Locale `json:"locale"`
+}
+
+// IsEmpty reports whether the digest has nothing to send
+func (d *DigestResponse) IsEmpty() bool {
+	return len(d.YieldDistributions) == 0 && len(d.RedemptionUpdates) == 0 &&
+		len(d.BalanceChanges) == 0 && len(d.UpcomingMaturities) == 0
+}
+
+// DigestYieldDistribution is a yield distribution on a sukuk the address holds
+type DigestYieldDistribution struct {
+	SukukAddress   string       `json:"sukuk_address"`
+	SukukCode      string       `json:"sukuk_code"`
+	DistributionID int64        `json:"distribution_id"`
+	PaymentToken   string       `json:"payment_token"`
+	TotalAmount    string       `json:"total_amount"`
+	EntitledAmount string       `json:"entitled_amount"` // Pro-rata share of the current balance, rounded down
+	SupplySource   SupplySource `json:"supply_source"`   // Where the supply behind EntitledAmount was read from
+	DistributedAt  time.Time    `json:"distributed_at"`
+	TxHash         string       `json:"tx_hash"`
+}
+
+// DigestRedemptionUpdate is a redemption request by the address or its approval
+type DigestRedemptionUpdate struct {
+	SukukAddress string           `json:"sukuk_address"`
+	SukukCode    string           `json:"sukuk_code"`
+	Status       RedemptionStatus `json:"status"` // requested or approved
+	Amount       string           `json:"amount"`
+	At           time.Time        `json:"at"`
+	TxHash       string           `json:"tx_hash"`
+}
+
+// DigestBalanceChange is a holder_update of the address
+type DigestBalanceChange struct {
+	SukukAddress string    `json:"sukuk_address"`
+	SukukCode    string    `json:"sukuk_code"`
+	Balance      string    `json:"balance"` // Balance after the change
+	At           time.Time `json:"at"`
+	TxHash       string    `json:"tx_hash"`
+}
+
+// DigestMaturity is a held sukuk maturing soon
+// Maturities are reminders rather than events, so they appear in every digest until the date passes
+type DigestMaturity struct {
+	SukukAddress  string    `json:"sukuk_address"`
+	SukukCode     string    `json:"sukuk_code"`
+	SukukTitle    string    `json:"sukuk_title"`
+	JatuhTempo    time.Time `json:"jatuh_tempo"`
+	DaysRemaining int       `json:"days_remaining"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// DisplayValues are fiat equivalents of an amount keyed by lowercase currency code with two
+// decimals, e.g. {"idr": "1500000.00", "usd": "91.50"}. For display only
+type DisplayValues map[string]string
+
+// FxRate is an exchange rate behind display values
+type FxRate struct {
+	Token    string    `json:"token"`    // Token symbol, e.g. IDRX
+	Currency string    `json:"currency"` // Lowercase currency code, e.g. usd
+	Rate     string    `json:"rate"`     // Price of one whole token
+	AsOf     time.Time `json:"as_of"`
+	Stale    bool      `json:"stale"` // Past its refresh time because the rate source is slow or failing
+}
+
+// DisplayMeta lists the exchange rates used for the display values of a response
+type DisplayMeta struct {
+	FxRates []FxRate `json:"fx_rates"`
+}
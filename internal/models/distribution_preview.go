@@ -0,0 +1,41 @@
+package models
+
+// DistributionPreviewRequest is the yield a manager intends to distribute on-chain
+type DistributionPreviewRequest struct {
+	TotalAmount    string `json:"total_amount" binding:"required"`  // Raw amount in the payment token's smallest unit
+	PaymentToken   string `json:"payment_token" binding:"required"` // Payment token contract address
+	MinEntitlement string `json:"min_entitlement,omitempty"`        // Overrides the configured minimum entitlement
+}
+
+// DistributionPreview lists what each current holder would receive from a distribution
+// Entitlements are rounded down; Dust is what rounding leaves undistributed, so the
+// entitlements plus Dust always equal TotalAmount
+type DistributionPreview struct {
+	SukukMetadataID   uint                      `json:"sukuk_metadata_id"`
+	SukukAddress      string                    `json:"sukuk_address"`
+	PaymentToken      string                    `json:"payment_token"`
+	TotalAmount       FormattedAmount           `json:"total_amount"`
+	DistributedAmount FormattedAmount           `json:"distributed_amount"` // Sum of all entitlements
+	Dust              FormattedAmount           `json:"dust"`
+	TotalSupply       string                    `json:"total_supply"`    // Sum of the current holder balances
+	MinEntitlement    string                    `json:"min_entitlement"` // Holders entitled to less are flagged
+	Summary           DistributionPreviewStats  `json:"summary"`
+	Holders           []DistributionEntitlement `json:"holders"`
+}
+
+// DistributionPreviewStats summarizes the entitlements of a distribution preview
+type DistributionPreviewStats struct {
+	HolderCount       int    `json:"holder_count"`
+	BelowMinimumCount int    `json:"below_minimum_count"`
+	MinEntitlement    string `json:"min_entitlement"`
+	MaxEntitlement    string `json:"max_entitlement"`
+	MedianEntitlement string `json:"median_entitlement"` // Mean of the middle two, rounded down, for an even count
+}
+
+// DistributionEntitlement is one holder's pro-rata share of a distribution
+type DistributionEntitlement struct {
+	Holder       string `json:"holder"`
+	Balance      string `json:"balance"`
+	Entitlement  string `json:"entitlement"`
+	BelowMinimum bool   `json:"below_minimum"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IndexerTableOverride pins the indexer table read for an event type, bypassing discovery
+// Used when the discovery heuristic picks the wrong table after a Ponder redeploy
+type IndexerTableOverride struct {
+	EventType string    `gorm:"primaryKey;size:64" json:"event_type"` // Table suffix, e.g. holder_update
+	Table     string    `gorm:"column:table_name;size:255;not null" json:"table_name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for IndexerTableOverride model
+func (IndexerTableOverride) TableName() string {
+	return "indexer_table_overrides"
+}
+
+// IndexerTableOverridesRequest sets overrides by event type; an empty table name removes one
+type IndexerTableOverridesRequest struct {
+	Overrides map[string]string `json:"overrides" binding:"required"`
+}
+
+// GetIndexerTableOverrides returns every override, ordered by event type
+func GetIndexerTableOverrides(db *gorm.DB) ([]IndexerTableOverride, error) {
+	var overrides []IndexerTableOverride
+	err := db.Order("event_type ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// SetIndexerTableOverride creates or replaces the override of an event type
+func SetIndexerTableOverride(db *gorm.DB, eventType, table string) error {
+	override := IndexerTableOverride{EventType: eventType, Table: table}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"table_name", "updated_at"}),
+	}).Create(&override).Error
+}
+
+// DeleteIndexerTableOverride removes the override of an event type, if any
+func DeleteIndexerTableOverride(db *gorm.DB, eventType string) error {
+	return db.Where("event_type = ?", eventType).Delete(&IndexerTableOverride{}).Error
+}
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidAmount is returned for amounts that do not parse or could be read more than one way
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// MaxRupiah is the largest whole rupiah amount a DECIMAL(20,2) column holds
+const MaxRupiah int64 = 999_999_999_999_999_999
+
+// amountPattern splits an amount into its number and an optional suffix, e.g. "1,5 juta"
+var amountPattern = regexp.MustCompile(`^([0-9][0-9.,]*)\s*([a-z]*)$`)
+
+// amountSuffixes are the multipliers admins write after a number. Single letters are left
+// out: "M" is miliar in Indonesian but million in English
+var amountSuffixes = map[string]int64{
+	"":        1,
+	"rb":      1_000,
+	"ribu":    1_000,
+	"jt":      1_000_000,
+	"juta":    1_000_000,
+	"miliar":  1_000_000_000,
+	"milyar":  1_000_000_000,
+	"triliun": 1_000_000_000_000,
+}
+
+// ParseIndonesianAmount parses an amount written the Indonesian way: dots between groups of
+// three digits, a decimal comma, an optional Rp or IDR prefix, a trailing ",-" and a ribu, juta,
+// miliar or triliun suffix, e.g. "1.000.000", "Rp 1,5 juta" or "Rp 250.000,-". Inputs that
+// read differently with English separators, such as "1,000" or "1.500 juta", are rejected
+func ParseIndonesianAmount(value string) (*big.Rat, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	for _, prefix := range []string{"rp.", "rp", "idr"} {
+		if strings.HasPrefix(s, prefix) {
+			s = strings.TrimSpace(strings.TrimPrefix(s, prefix))
+			break
+		}
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(s, ",-"))
+
+	match := amountPattern.FindStringSubmatch(s)
+	if match == nil {
+		return nil, fmt.Errorf("%w %q: expected digits with an optional Rp prefix and ribu, juta, miliar or triliun suffix", ErrInvalidAmount, value)
+	}
+	number, suffix := match[1], match[2]
+	multiplier, ok := amountSuffixes[suffix]
+	if !ok {
+		return nil, fmt.Errorf("%w %q: unknown suffix %q, use ribu, juta, miliar or triliun", ErrInvalidAmount, value, suffix)
+	}
+
+	whole, fraction, hasComma := strings.Cut(number, ",")
+	switch {
+	case strings.Contains(fraction, ","):
+		return nil, fmt.Errorf("%w %q: more than one decimal comma; group thousands with dots", ErrInvalidAmount, value)
+	case strings.Contains(fraction, "."):
+		return nil, fmt.Errorf("%w %q: dots after the decimal comma; write thousands as 1.000.000 and decimals as 1,5", ErrInvalidAmount, value)
+	case hasComma && fraction == "":
+		return nil, fmt.Errorf("%w %q: no digits after the decimal comma", ErrInvalidAmount, value)
+	case hasComma && len(fraction) == 3 && !strings.Contains(whole, "."):
+		return nil, fmt.Errorf("%w %q: ambiguous, the comma may separate thousands; write 1.000 for a thousand or drop the trailing zeros of a decimal", ErrInvalidAmount, value)
+	}
+
+	groups := strings.Split(whole, ".")
+	for i, group := range groups {
+		if group == "" || (i == 0 && len(group) > 3 && len(groups) > 1) || (i > 0 && len(group) != 3) {
+			return nil, fmt.Errorf("%w %q: dots must separate groups of three digits; use a comma for decimals", ErrInvalidAmount, value)
+		}
+	}
+	if len(groups) == 2 && suffix != "" {
+		return nil, fmt.Errorf("%w %q: ambiguous, the dot may be a decimal point; write 1,5 %s or 1.500 without a suffix", ErrInvalidAmount, value, suffix)
+	}
+
+	digits := strings.Join(groups, "")
+	if fraction != "" {
+		digits += "." + fraction
+	}
+	amount, ok := new(big.Rat).SetString(digits)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidAmount, value)
+	}
+	return amount.Mul(amount, new(big.Rat).SetInt64(multiplier)), nil
+}
+
+// ParseRupiah parses an Indonesian amount that must be a whole number of rupiah
+func ParseRupiah(value string) (int64, error) {
+	amount, err := ParseIndonesianAmount(value)
+	if err != nil {
+		return 0, err
+	}
+	return wholeRupiah(amount, value)
+}
+
+// wholeRupiah checks that an amount is whole and fits a DECIMAL(20,2) column
+func wholeRupiah(amount *big.Rat, value string) (int64, error) {
+	if !amount.IsInt() {
+		return 0, fmt.Errorf("%w %q: fractions of a rupiah are not allowed", ErrInvalidAmount, value)
+	}
+	if amount.Num().Cmp(big.NewInt(MaxRupiah)) > 0 {
+		return 0, fmt.Errorf("%w %q: above the largest supported amount", ErrInvalidAmount, value)
+	}
+	return amount.Num().Int64(), nil
+}
+
+// ParseIndonesianDecimal parses an Indonesian amount into a Decimal, such as a quantity in
+// token units, rejecting more than DecimalScale fractional digits
+func ParseIndonesianDecimal(value string) (Decimal, error) {
+	amount, err := ParseIndonesianAmount(value)
+	if err != nil {
+		return "", err
+	}
+	d := NewDecimal(amount)
+	if exact, _ := d.Rat(); exact.Cmp(amount) != 0 {
+		return "", fmt.Errorf("%w %q: more than %d fractional digits", ErrInvalidAmount, value, DecimalScale)
+	}
+	return d, nil
+}
+
+// FormatIndonesianDecimal formats a decimal with dots between thousands and a decimal comma,
+// e.g. "7000000000000" as "7.000.000.000.000" and "1234.5" as "1.234,5"
+func FormatIndonesianDecimal(d Decimal) string {
+	sign, digits := "", d.String()
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	var grouped strings.Builder
+	grouped.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	if fraction != "" {
+		grouped.WriteString("," + fraction)
+	}
+	return grouped.String()
+}
+
+// FormatRupiah formats a rupiah amount of a DECIMAL(20,2) column for display, e.g. 1000000
+// as "Rp1.000.000" and 1500.5 as "Rp1.500,5"
+func FormatRupiah(amount float64) string {
+	cents, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', 2, 64))
+	return "Rp" + FormatIndonesianDecimal(NewDecimal(cents))
+}
+
+// RupiahInput is a whole rupiah amount sent either as a JSON number or as a string written
+// the Indonesian way, see ParseIndonesianAmount
+type RupiahInput int64
+
+// UnmarshalJSON accepts a whole JSON number or an Indonesian amount string
+func (r *RupiahInput) UnmarshalJSON(data []byte) error {
+	raw, isString, err := amountJSON(data)
+	if err != nil || raw == "" {
+		return err
+	}
+	var amount *big.Rat
+	if isString {
+		amount, err = ParseIndonesianAmount(raw)
+		if err != nil {
+			return err
+		}
+	} else {
+		var ok bool
+		if amount, ok = new(big.Rat).SetString(raw); !ok || amount.Sign() < 0 {
+			return fmt.Errorf("%w %s", ErrInvalidAmount, raw)
+		}
+	}
+	whole, err := wholeRupiah(amount, raw)
+	if err != nil {
+		return err
+	}
+	*r = RupiahInput(whole)
+	return nil
+}
+
+// Float64 returns the amount for the DECIMAL(20,2) rupiah columns
+func (r RupiahInput) Float64() float64 {
+	return float64(r)
+}
+
+// QuantityInput is a quantity such as kuota nasional in token units, sent either as a JSON
+// number or as a string written the Indonesian way, see ParseIndonesianAmount
+type QuantityInput Decimal
+
+// UnmarshalJSON accepts a JSON number or an Indonesian amount string
+func (q *QuantityInput) UnmarshalJSON(data []byte) error {
+	raw, isString, err := amountJSON(data)
+	if err != nil || raw == "" {
+		return err
+	}
+	var d Decimal
+	if isString {
+		d, err = ParseIndonesianDecimal(raw)
+	} else {
+		d, err = ParseDecimal(raw)
+	}
+	if err != nil {
+		return err
+	}
+	*q = QuantityInput(d)
+	return nil
+}
+
+// Decimal returns the parsed quantity
+func (q QuantityInput) Decimal() Decimal {
+	return Decimal(q)
+}
+
+// amountJSON returns the text of a JSON number or string, and whether it was a string; null
+// returns an empty text
+func amountJSON(data []byte) (string, bool, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		return string(data), false, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", false, err
+	}
+	if strings.TrimSpace(s) == "" {
+		return "", false, fmt.Errorf("%w: empty string", ErrInvalidAmount)
+	}
+	return s, true, nil
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseIndonesianAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // Exact value, empty when rejected
+	}{
+		{"1000000", "1000000"},
+		{"1.000.000", "1000000"},
+		{"Rp 1.000.000", "1000000"},
+		{"Rp1.000.000,00", "1000000"},
+		{"Rp. 250.000,-", "250000"},
+		{"IDR 5.000.000", "5000000"},
+		{"Rp 1,5 juta", "1500000"},
+		{"1,5jt", "1500000"},
+		{"500 ribu", "500000"},
+		{"500rb", "500000"},
+		{"10 Miliar", "10000000000"},
+		{"2,25 milyar", "2250000000"},
+		{"7 triliun", "7000000000000"},
+		{"1.500", "1500"},
+		{"1.234,5", "1234.5"},
+		{"1.000,500", "1000.5"},
+		{" 25.000.000 ", "25000000"},
+
+		{"1,000", ""},        // English thousands or a decimal with three places
+		{"1,000,000", ""},    // English thousands
+		{"1,000,000.00", ""}, // English format
+		{"1.5 juta", ""},     // English decimal point
+		{"1.500 juta", ""},   // 1,5 juta or 1,5 miliar
+		{"1.0000", ""},       // Not a group of three
+		{"10000.000", ""},    // Leading group over three digits
+		{"1,", ""},           // No decimals
+		{"5 M", ""},          // Miliar or million
+		{"5 lakh", ""},       // Unknown suffix
+		{"-1.000", ""},       // Negative
+		{"satu juta", ""},    // Words
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseIndonesianAmount(tt.input)
+		if tt.want == "" {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("ParseIndonesianAmount(%q) = %v, %v; want ErrInvalidAmount", tt.input, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIndonesianAmount(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if d := NewDecimal(got); string(d) != tt.want {
+			t.Errorf("ParseIndonesianAmount(%q) = %s; want %s", tt.input, d, tt.want)
+		}
+	}
+}
+
+func TestParseRupiahRequiresWholeAmounts(t *testing.T) {
+	if got, err := ParseRupiah("Rp 1,5 juta"); err != nil || got != 1500000 {
+		t.Errorf("Expected 1500000, got %d (%v)", got, err)
+	}
+	for _, input := range []string{"1,5", "1.234,56", "1.000.000.000.000.000.000"} {
+		if _, err := ParseRupiah(input); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Expected %q to be rejected, got %v", input, err)
+		}
+	}
+}
+
+func TestAmountInputJSON(t *testing.T) {
+	var request SukukMetadataUpdateRequest
+	body := `{"minimum_pembelian": "Rp 1.000.000", "maksimum_pembelian": 10000000000, "kuota_nasional": "7 triliun"}`
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if *request.MinimumPembelian != 1000000 || *request.MaksimumPembelian != 10000000000 || request.KuotaNasional.Decimal() != "7000000000000" {
+		t.Errorf("Expected 1000000, 10000000000 and 7000000000000, got %v, %v and %v",
+			*request.MinimumPembelian, *request.MaksimumPembelian, *request.KuotaNasional)
+	}
+
+	for _, invalid := range []string{
+		`{"minimum_pembelian": 1000000.5}`,
+		`{"minimum_pembelian": -1}`,
+		`{"minimum_pembelian": "1,000"}`,
+		`{"minimum_pembelian": ""}`,
+		`{"kuota_nasional": "1.5 triliun"}`,
+	} {
+		if err := json.Unmarshal([]byte(invalid), &SukukMetadataUpdateRequest{}); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid)
+		}
+	}
+}
+
+func TestFormatIndonesianAmounts(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{FormatRupiah(1000000), "Rp1.000.000"},
+		{FormatRupiah(10000000000), "Rp10.000.000.000"},
+		{FormatRupiah(999), "Rp999"},
+		{FormatRupiah(1500.5), "Rp1.500,5"},
+		{FormatRupiah(0), "Rp0"},
+		{FormatIndonesianDecimal("7000000000000"), "7.000.000.000.000"},
+		{FormatIndonesianDecimal("1234.5"), "1.234,5"},
+		{FormatIndonesianDecimal(""), "0"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Got %q; want %q", tt.got, tt.want)
+		}
+	}
+
+	response := (&SukukMetadata{KuotaNasional: "7000000000000", MinimumPembelian: 1000000}).ToListResponse()
+	if response.KuotaNasionalDisplay != "7.000.000.000.000" || response.MinimumPembelianDisplay != "Rp1.000.000" || response.MaksimumPembelianDisplay != "Rp0" {
+		t.Errorf("Expected display strings next to the values, got %+v", response)
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvestorProfileExists is returned when a wallet already has an investor profile
+var ErrInvestorProfileExists = errors.New("investor profile already exists")
+
+// KYCStatus represents the verification state of an investor
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusVerified KYCStatus = "verified"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// IsValid checks if the status is a known KYC status
+func (s KYCStatus) IsValid() bool {
+	switch s {
+	case KYCStatusPending, KYCStatusVerified, KYCStatusRejected:
+		return true
+	}
+	return false
+}
+
+// InvestorProfile links a wallet address to verified identity data
+type InvestorProfile struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	WalletAddress string      `gorm:"size:42;uniqueIndex;not null" json:"wallet_address"`
+	Name          string      `gorm:"size:255" json:"name"`
+	Email         string      `gorm:"size:255" json:"email"`
+	KYCStatus     KYCStatus   `gorm:"size:20;not null;default:pending;index" json:"kyc_status"`
+	VerifiedAt    *time.Time  `json:"verified_at,omitempty"`
+	DocumentRefs  []string    `gorm:"serializer:json;type:text" json:"document_refs"`
+	Reviews       []KYCReview `gorm:"foreignKey:InvestorProfileID" json:"reviews,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for InvestorProfile model
+func (InvestorProfile) TableName() string {
+	return "investor_profiles"
+}
+
+// BeforeSave hook to normalize the wallet address
+func (ip *InvestorProfile) BeforeSave(tx *gorm.DB) error {
+	ip.WalletAddress = normalizeAddress(ip.WalletAddress)
+	return nil
+}
+
+// KYCReview records a compliance review decision for an investor
+type KYCReview struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	InvestorProfileID uint      `gorm:"not null;index" json:"investor_profile_id"`
+	Reviewer          string    `gorm:"size:100;not null" json:"reviewer"`
+	Decision          KYCStatus `gorm:"size:20;not null" json:"decision"`
+	Notes             string    `gorm:"type:text" json:"notes"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for KYCReview model
+func (KYCReview) TableName() string {
+	return "kyc_reviews"
+}
+
+// ValidateKYCTransition checks whether an investor may move between KYC statuses
+// A rejected investor can only leave rejected through a new review record, so going back to
+// pending can't be used to verify them without one
+func ValidateKYCTransition(from, to KYCStatus, withReview bool) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid KYC status: %s", to)
+	}
+	if from == to {
+		return nil
+	}
+	if from == KYCStatusRejected && !withReview {
+		return fmt.Errorf("cannot change KYC status from %s to %s without a new review", from, to)
+	}
+	return nil
+}
+
+// ApplyKYCStatus transitions the profile to a new status, maintaining verified_at
+func (ip *InvestorProfile) ApplyKYCStatus(status KYCStatus, withReview bool) error {
+	if err := ValidateKYCTransition(ip.KYCStatus, status, withReview); err != nil {
+		return err
+	}
+
+	if status == KYCStatusVerified && ip.KYCStatus != KYCStatusVerified {
+		now := time.Now()
+		ip.VerifiedAt = &now
+	} else if status != KYCStatusVerified {
+		ip.VerifiedAt = nil
+	}
+	ip.KYCStatus = status
+
+	return nil
+}
+
+// InvestorProfileCreateRequest represents the request payload for creating an investor profile
+type InvestorProfileCreateRequest struct {
+	WalletAddress string   `json:"wallet_address" binding:"required"`
+	Name          string   `json:"name"`
+	Email         string   `json:"email" binding:"omitempty,email"`
+	DocumentRefs  []string `json:"document_refs"`
+}
+
+// InvestorProfileUpdateRequest represents the request payload for updating an investor profile
+// Moving a rejected investor to any other status requires a review via the reviews endpoint
+type InvestorProfileUpdateRequest struct {
+	Name         *string    `json:"name,omitempty"`
+	Email        *string    `json:"email,omitempty" binding:"omitempty,email"`
+	KYCStatus    *KYCStatus `json:"kyc_status,omitempty"`
+	DocumentRefs []string   `json:"document_refs,omitempty"`
+}
+
+// KYCReviewRequest represents the request payload for recording a KYC review
+type KYCReviewRequest struct {
+	Reviewer string    `json:"reviewer" binding:"required"`
+	Decision KYCStatus `json:"decision" binding:"required"`
+	Notes    string    `json:"notes"`
+}
+
+// KYCStatusResponse is the public view of an investor's KYC status
+type KYCStatusResponse struct {
+	WalletAddress string    `json:"wallet_address"`
+	KYCStatus     KYCStatus `json:"kyc_status"`
+}
+
+// CreateInvestorProfile stores an investor profile, returning ErrInvestorProfileExists if the
+// wallet already has one
+func CreateInvestorProfile(db *gorm.DB, profile *InvestorProfile) error {
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "wallet_address"}}, DoNothing: true}).Create(profile)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvestorProfileExists
+	}
+	return nil
+}
+
+// GetInvestorProfileByAddress retrieves an investor profile by wallet address
+func GetInvestorProfileByAddress(db *gorm.DB, address string) (*InvestorProfile, error) {
+	var profile InvestorProfile
+	err := db.Where("wallet_address = ?", normalizeAddress(address)).First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
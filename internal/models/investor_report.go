@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// InvestorReport lists the monthly activity of every investor on an issuer's sukuk
+// Amounts are raw values in the smallest unit of the token they were paid or redeemed in
+type InvestorReport struct {
+	IssuerAddress string              `json:"issuer_address"`
+	Month         string              `json:"month"` // YYYY-MM
+	Timezone      string              `json:"timezone"`
+	PeriodStart   time.Time           `json:"period_start"` // Inclusive
+	PeriodEnd     time.Time           `json:"period_end"`   // Exclusive
+	SukukCount    int                 `json:"sukuk_count"`  // Sukuk owned by the issuer
+	Rows          []InvestorReportRow `json:"rows"`         // One per investor per sukuk, sorted by investor then sukuk
+}
+
+// InvestorReportRow sums one investor's activity on one sukuk during the month
+type InvestorReportRow struct {
+	InvestorAddress           string    `json:"investor_address"`
+	KYCStatus                 KYCStatus `json:"kyc_status"` // Empty when the investor has no profile
+	SukukAddress              string    `json:"sukuk_address"`
+	SukukCode                 string    `json:"sukuk_code"`
+	PurchaseCount             int       `json:"purchase_count"`
+	PurchasedAmount           string    `json:"purchased_amount"`
+	RedemptionCount           int       `json:"redemption_count"` // Redemption requests
+	RedemptionRequestedAmount string    `json:"redemption_requested_amount"`
+	RedemptionApprovedAmount  string    `json:"redemption_approved_amount"`
+	YieldClaimCount           int       `json:"yield_claim_count"`
+	YieldClaimedAmount        string    `json:"yield_claimed_amount"`
+}
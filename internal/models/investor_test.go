@@ -0,0 +1,64 @@
+package models
+
+import "testing"
+
+func TestValidateKYCTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       KYCStatus
+		to         KYCStatus
+		withReview bool
+		wantErr    bool
+	}{
+		{"pending to verified", KYCStatusPending, KYCStatusVerified, false, false},
+		{"pending to rejected", KYCStatusPending, KYCStatusRejected, false, false},
+		{"verified to rejected", KYCStatusVerified, KYCStatusRejected, false, false},
+		{"rejected to pending without review", KYCStatusRejected, KYCStatusPending, false, true},
+		{"rejected to pending with review", KYCStatusRejected, KYCStatusPending, true, false},
+		{"rejected to verified without review", KYCStatusRejected, KYCStatusVerified, false, true},
+		{"rejected to verified with review", KYCStatusRejected, KYCStatusVerified, true, false},
+		{"unchanged status", KYCStatusRejected, KYCStatusRejected, false, false},
+		{"unknown status", KYCStatusPending, KYCStatus("approved"), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKYCTransition(tt.from, tt.to, tt.withReview)
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected error for %s -> %s", tt.from, tt.to)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error for %s -> %s, got %v", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestApplyKYCStatusVerifiedAt(t *testing.T) {
+	profile := &InvestorProfile{KYCStatus: KYCStatusPending}
+
+	if err := profile.ApplyKYCStatus(KYCStatusVerified, false); err != nil {
+		t.Fatalf("Failed to verify investor: %v", err)
+	}
+	if profile.VerifiedAt == nil {
+		t.Error("Expected verified_at to be set after verification")
+	}
+
+	if err := profile.ApplyKYCStatus(KYCStatusRejected, false); err != nil {
+		t.Fatalf("Failed to reject investor: %v", err)
+	}
+	if profile.VerifiedAt != nil {
+		t.Error("Expected verified_at to be cleared after rejection")
+	}
+
+	if err := profile.ApplyKYCStatus(KYCStatusVerified, false); err == nil {
+		t.Error("Expected rejected investor not to be verified without a review")
+	}
+	// Nor by way of pending
+	if err := profile.ApplyKYCStatus(KYCStatusPending, false); err == nil {
+		t.Error("Expected rejected investor not to go back to pending without a review")
+	}
+	if profile.KYCStatus != KYCStatusRejected {
+		t.Errorf("Expected status to stay rejected, got %s", profile.KYCStatus)
+	}
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerDirection is the side of a ledger entry: a debit is value the account received,
+// a credit value it paid out
+type LedgerDirection string
+
+const (
+	LedgerDebit  LedgerDirection = "debit"
+	LedgerCredit LedgerDirection = "credit"
+)
+
+// LedgerEventType is the value movement a pair of ledger entries records
+type LedgerEventType string
+
+const (
+	LedgerEventPurchase         LedgerEventType = "purchase"          // Buyer pays the sukuk treasury
+	LedgerEventRedemptionPayout LedgerEventType = "redemption_payout" // Treasury pays out an approved redemption
+	LedgerEventYieldPayment     LedgerEventType = "yield_payment"     // Treasury pays a claimed yield distribution
+)
+
+// IsValid reports whether the event type is a known value movement
+func (t LedgerEventType) IsValid() bool {
+	switch t {
+	case LedgerEventPurchase, LedgerEventRedemptionPayout, LedgerEventYieldPayment:
+		return true
+	}
+	return false
+}
+
+// ErrUnbalancedLedgerPair is returned for a pair of entries whose legs do not cancel out
+var ErrUnbalancedLedgerPair = errors.New("ledger entries are not balanced")
+
+// LedgerEntry is one leg of a double-entry record of an onchain value movement. Every movement
+// is stored as a debit to the account receiving value and an equal credit to the account paying
+// it. A sukuk's treasury account is its contract address
+type LedgerEntry struct {
+	ID             uint            `gorm:"primaryKey" json:"id"`
+	Account        string          `gorm:"size:42;not null;index" json:"account"`
+	CounterAccount string          `gorm:"size:42;not null" json:"counter_account"`
+	SukukAddress   string          `gorm:"size:42;not null;index" json:"sukuk_address"`
+	Direction      LedgerDirection `gorm:"size:6;not null" json:"direction"`
+	Amount         BigNumeric      `gorm:"type:numeric(78,0);not null" json:"amount"`
+	Token          string          `gorm:"size:42;not null" json:"token"`
+	EventType      LedgerEventType `gorm:"size:20;not null;index" json:"event_type"`
+	TxHash         string          `gorm:"size:66;not null;uniqueIndex:idx_ledger_entries_tx_log_leg" json:"tx_hash"`
+	LogIndex       uint            `gorm:"not null;uniqueIndex:idx_ledger_entries_tx_log_leg" json:"log_index"`
+	Leg            uint            `gorm:"not null;uniqueIndex:idx_ledger_entries_tx_log_leg" json:"leg"` // 0 for the debit, 1 for the credit
+	Timestamp      time.Time       `gorm:"not null;index" json:"timestamp"`
+	Status         EventStatus     `gorm:"size:10;not null;default:confirmed;index" json:"status"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// TableName returns the table name for LedgerEntry model
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+// BeforeCreate hook to normalize addresses and reject amounts that do not parse
+func (le *LedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	le.Account = normalizeAddress(le.Account)
+	le.CounterAccount = normalizeAddress(le.CounterAccount)
+	le.SukukAddress = normalizeAddress(le.SukukAddress)
+	le.Token = normalizeAddress(le.Token)
+	le.TxHash = strings.ToLower(le.TxHash)
+	return le.Amount.Validate("amount")
+}
+
+// LedgerMovement is an onchain transfer of amount of token from one account to another
+type LedgerMovement struct {
+	EventType    LedgerEventType
+	From         string // Account paying
+	To           string // Account receiving
+	SukukAddress string
+	Token        string
+	Amount       string // Raw token amount
+	TxHash       string
+	LogIndex     uint
+	Timestamp    time.Time
+}
+
+// Entries returns the balanced pair recording the movement: a debit to the receiving
+// account and an equal credit to the paying one
+func (m LedgerMovement) Entries() []LedgerEntry {
+	entry := func(direction LedgerDirection, account, counterAccount string, leg uint) LedgerEntry {
+		return LedgerEntry{
+			Account:        account,
+			CounterAccount: counterAccount,
+			SukukAddress:   m.SukukAddress,
+			Direction:      direction,
+			Amount:         BigNumeric(m.Amount),
+			Token:          m.Token,
+			EventType:      m.EventType,
+			TxHash:         m.TxHash,
+			LogIndex:       m.LogIndex,
+			Leg:            leg,
+			Timestamp:      m.Timestamp,
+		}
+	}
+	return []LedgerEntry{
+		entry(LedgerDebit, m.To, m.From, 0),
+		entry(LedgerCredit, m.From, m.To, 1),
+	}
+}
+
+// CreateLedgerMovement stores both entries of a movement in one transaction, returning
+// ErrDuplicateEvent if the movement was already recorded
+func CreateLedgerMovement(db *gorm.DB, movement LedgerMovement) error {
+	entries := movement.Entries()
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}, {Name: "leg"}},
+			DoNothing: true,
+		}).Create(&entries)
+		if result.Error != nil {
+			return result.Error
+		}
+		switch result.RowsAffected {
+		case int64(len(entries)):
+			return nil
+		case 0:
+			return ErrDuplicateEvent
+		default:
+			return ErrUnbalancedLedgerPair // Only one leg was new; roll it back
+		}
+	})
+}
+
+// LedgerFilter selects ledger entries; zero fields match everything
+type LedgerFilter struct {
+	Account      string
+	SukukAddress string
+	EventType    LedgerEventType
+	From         *time.Time // Inclusive
+	To           *time.Time // Exclusive
+	Limit        int
+	Offset       int
+}
+
+func (f LedgerFilter) apply(db *gorm.DB) *gorm.DB {
+	query := db.Model(&LedgerEntry{})
+	if f.Account != "" {
+		query = query.Where("account = ?", normalizeAddress(f.Account))
+	}
+	if f.SukukAddress != "" {
+		query = query.Where("sukuk_address = ?", normalizeAddress(f.SukukAddress))
+	}
+	if f.EventType != "" {
+		query = query.Where("event_type = ?", f.EventType)
+	}
+	if f.From != nil {
+		query = query.Where("timestamp >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("timestamp < ?", *f.To)
+	}
+	return query
+}
+
+// ListLedgerEntries returns a page of the entries matching the filter, newest first, with the
+// count of every matching entry
+func ListLedgerEntries(db *gorm.DB, filter LedgerFilter) ([]LedgerEntry, int64, error) {
+	var total int64
+	if err := filter.apply(db).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []LedgerEntry{}
+	err := filter.apply(db).
+		Order("timestamp DESC, tx_hash, log_index, leg").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&entries).Error
+	return entries, total, err
+}
+
+// LedgerBalance totals an account's entries in one token; amounts are raw and the balance
+// is debits minus credits
+type LedgerBalance struct {
+	Token   string `json:"token"`
+	Debits  string `json:"debits"`
+	Credits string `json:"credits"`
+	Balance string `json:"balance"`
+}
+
+// GetLedgerBalances sums the entries matching the filter per token, ignoring its paging.
+// Entries orphaned by a chain reorg are listed but never counted
+func GetLedgerBalances(db *gorm.DB, filter LedgerFilter) ([]LedgerBalance, error) {
+	balances := []LedgerBalance{}
+	err := filter.apply(db).
+		Where("status <> ?", EventStatusOrphaned).
+		Select(`token,
+			COALESCE(SUM(amount) FILTER (WHERE direction = ?), 0)::text AS debits,
+			COALESCE(SUM(amount) FILTER (WHERE direction = ?), 0)::text AS credits,
+			COALESCE(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 0)::text AS balance`,
+			LedgerDebit, LedgerCredit, LedgerDebit).
+		Group("token").
+		Order("token").
+		Scan(&balances).Error
+	return balances, err
+}
+
+// LedgerListResponse is a page of ledger entries
+type LedgerListResponse struct {
+	Entries    []LedgerEntry   `json:"entries"`
+	TotalCount int64           `json:"total_count"`
+	Balances   []LedgerBalance `json:"balances,omitempty"` // Per token, when filtered by account
+}
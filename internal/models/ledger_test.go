@@ -0,0 +1,54 @@
+package models
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestLedgerMovementEntriesBalance(t *testing.T) {
+	const (
+		investor = "0x00000000000000000000000000000000000000aa"
+		sukuk    = "0x00000000000000000000000000000000000000bb"
+		token    = "0x00000000000000000000000000000000000000cc"
+	)
+	movements := []LedgerMovement{
+		{EventType: LedgerEventPurchase, From: investor, To: sukuk, Amount: "1000000000000000000000"},
+		{EventType: LedgerEventRedemptionPayout, From: sukuk, To: investor, Amount: "250"},
+		{EventType: LedgerEventYieldPayment, From: sukuk, To: investor, Amount: "7"},
+	}
+
+	for i, movement := range movements {
+		movement.SukukAddress, movement.Token = sukuk, token
+		movement.TxHash, movement.LogIndex, movement.Timestamp = "0xabc", uint(i), time.Unix(1700000000, 0)
+
+		entries := movement.Entries()
+		debits, credits := new(big.Int), new(big.Int)
+		for _, entry := range entries {
+			amount, err := entry.Amount.Int()
+			if err != nil {
+				t.Fatalf("%s: invalid amount: %v", movement.EventType, err)
+			}
+			switch entry.Direction {
+			case LedgerDebit:
+				debits.Add(debits, amount)
+			case LedgerCredit:
+				credits.Add(credits, amount)
+			}
+			if entry.EventType != movement.EventType || entry.TxHash != movement.TxHash || entry.LogIndex != movement.LogIndex || entry.Token != token {
+				t.Errorf("%s: entry does not describe the movement: %+v", movement.EventType, entry)
+			}
+		}
+		if len(entries) != 2 || debits.Cmp(credits) != 0 {
+			t.Errorf("%s: expected a balanced pair, got %d entries with debits %s and credits %s", movement.EventType, len(entries), debits, credits)
+		}
+
+		debit, credit := entries[0], entries[1]
+		if debit.Direction != LedgerDebit || debit.Account != movement.To || debit.CounterAccount != movement.From || debit.Leg != 0 {
+			t.Errorf("%s: expected the receiving account debited on leg 0, got %+v", movement.EventType, debit)
+		}
+		if credit.Direction != LedgerCredit || credit.Account != movement.From || credit.CounterAccount != movement.To || credit.Leg != 1 {
+			t.Errorf("%s: expected the paying account credited on leg 1, got %+v", movement.EventType, credit)
+		}
+	}
+}
@@ -9,6 +9,30 @@ func AllModels() []interface{} {
 		&SukukMetadata{}, // Model for onchain + offchain metadata
 		&SukukPurchased{}, // Blockchain event for sukuk purchases
 		&RedemptionRequested{}, // Blockchain event for redemption requests
+		&PaymentToken{}, // ERC-20 metadata for payment tokens
+		&InvestorProfile{}, // Investor identity and KYC status
+		&KYCReview{}, // KYC review decisions
+		&AuditLog{}, // Audit trail for admin writes
+		&SukukSuspension{}, // Onchain emergency suspensions and resumes
+		&Order{}, // Fiat on-ramp purchase orders
+		&OrderPaymentCallback{}, // Payment callbacks applied to orders
+		&SukukMetadataTranslation{}, // Localized sukuk metadata fields
+		&Referral{}, // Marketing referral codes
+		&ReferralBinding{}, // Wallets bound to a referral code
+		&ReferralAttribution{}, // Purchases credited to a referral code
+		&IndexerTableOverride{}, // Pinned indexer tables per event type
+		&NotificationPreference{}, // Per-wallet notification settings
+		&LedgerEntry{}, // Double-entry record of onchain value movements
+		&SukukDocument{}, // Versioned documents attached to a sukuk
+		&ReorgIncident{}, // Derived rows orphaned by chain reorgs
+		&SukukMetadataConflict{}, // Chain values held back by admin edits
+		&SukukDocumentDownload{}, // Signed document links handed out
+		&Certificate{}, // Investment certificates issued to investors
+		&APIKeyUsage{}, // Hourly traffic per API key
+		&SuitabilityAcknowledgement{}, // Investor risk acknowledgements per prospectus version
+		&PayoutInstruction{}, // Issuer payouts of approved redemptions
+		&ActivityProjection{}, // Activity feed read model built from the indexer
+		&WalletAuthNonce{}, // Single-use wallet sign-in nonces
 		// Only keeping essential models for indexer data + metadata
 	}
 }
\ No newline at end of file
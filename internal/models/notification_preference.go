@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationKind is a kind of notification a wallet can opt out of
+type NotificationKind string
+
+const (
+	NotificationKindYield      NotificationKind = "yield"      // Yield distributions on held sukuk
+	NotificationKindRedemption NotificationKind = "redemption" // Redemption requests and approvals
+	NotificationKindDigest     NotificationKind = "digest"     // Periodic activity digest
+	NotificationKindAll        NotificationKind = "all"        // Every kind; only valid for unsubscribing
+)
+
+// IsValid reports whether the kind is known
+func (k NotificationKind) IsValid() bool {
+	switch k {
+	case NotificationKindYield, NotificationKindRedemption, NotificationKindDigest, NotificationKindAll:
+		return true
+	}
+	return false
+}
+
+// NotificationPreference is how a wallet wants to be notified
+// Wallets without a row get DefaultNotificationPreference
+type NotificationPreference struct {
+	Address                string    `gorm:"primaryKey;size:42" json:"address"`
+	Email                  string    `gorm:"size:255" json:"email"`
+	EnableYieldAlerts      bool      `gorm:"not null" json:"enable_yield_alerts"`
+	EnableRedemptionAlerts bool      `gorm:"not null" json:"enable_redemption_alerts"`
+	EnableDigest           bool      `gorm:"not null" json:"enable_digest"`
+	Locale                 Locale    `gorm:"size:8;not null" json:"locale"`
+	CreatedAt              time.Time `json:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for NotificationPreference model
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// BeforeSave hook to normalize the address
+func (p *NotificationPreference) BeforeSave(tx *gorm.DB) error {
+	p.Address = normalizeAddress(p.Address)
+	return nil
+}
+
+// DefaultNotificationPreference is every notification enabled in the default locale, with no email
+func DefaultNotificationPreference(address string) NotificationPreference {
+	return NotificationPreference{
+		Address:                normalizeAddress(address),
+		EnableYieldAlerts:      true,
+		EnableRedemptionAlerts: true,
+		EnableDigest:           true,
+		Locale:                 DefaultLocale,
+	}
+}
+
+// Allows reports whether the wallet wants notifications of the kind
+func (p *NotificationPreference) Allows(kind NotificationKind) bool {
+	switch kind {
+	case NotificationKindYield:
+		return p.EnableYieldAlerts
+	case NotificationKindRedemption:
+		return p.EnableRedemptionAlerts
+	case NotificationKindDigest:
+		return p.EnableDigest
+	}
+	return false
+}
+
+// Unsubscribe turns off the kind, or every kind for NotificationKindAll
+func (p *NotificationPreference) Unsubscribe(kind NotificationKind) {
+	if kind == NotificationKindYield || kind == NotificationKindAll {
+		p.EnableYieldAlerts = false
+	}
+	if kind == NotificationKindRedemption || kind == NotificationKindAll {
+		p.EnableRedemptionAlerts = false
+	}
+	if kind == NotificationKindDigest || kind == NotificationKindAll {
+		p.EnableDigest = false
+	}
+}
+
+// NotificationPreferenceUpdateRequest changes the given preferences of a wallet
+type NotificationPreferenceUpdateRequest struct {
+	Email                  *string `json:"email" binding:"omitempty,email"` // An empty string removes the email
+	EnableYieldAlerts      *bool   `json:"enable_yield_alerts"`
+	EnableRedemptionAlerts *bool   `json:"enable_redemption_alerts"`
+	EnableDigest           *bool   `json:"enable_digest"`
+	Locale                 *Locale `json:"locale" swaggertype:"string" enums:"id,en"`
+}
+
+// Apply copies the fields present in the request onto the preference
+func (r *NotificationPreferenceUpdateRequest) Apply(p *NotificationPreference) {
+	if r.Email != nil {
+		p.Email = *r.Email
+	}
+	if r.EnableYieldAlerts != nil {
+		p.EnableYieldAlerts = *r.EnableYieldAlerts
+	}
+	if r.EnableRedemptionAlerts != nil {
+		p.EnableRedemptionAlerts = *r.EnableRedemptionAlerts
+	}
+	if r.EnableDigest != nil {
+		p.EnableDigest = *r.EnableDigest
+	}
+	if r.Locale != nil {
+		p.Locale = *r.Locale
+	}
+}
+
+// UnsubscribeConfirmation describes what an unsubscribe link turns off once it is POSTed
+type UnsubscribeConfirmation struct {
+	Address      string           `json:"address"`
+	Notification NotificationKind `json:"notification"`
+	Confirm      string           `json:"confirm"`
+}
+
+// UnsubscribeResponse confirms an unsubscribe link was applied
+type UnsubscribeResponse struct {
+	Address      string           `json:"address"`
+	Unsubscribed NotificationKind `json:"unsubscribed"`
+}
+
+// GetNotificationPreference returns a wallet's preferences, or the defaults if it has none
+func GetNotificationPreference(db *gorm.DB, address string) (*NotificationPreference, error) {
+	var preference NotificationPreference
+	err := db.Where("address = ?", normalizeAddress(address)).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preference = DefaultNotificationPreference(address)
+		return &preference, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SaveNotificationPreference creates or replaces a wallet's preferences
+func SaveNotificationPreference(db *gorm.DB, preference *NotificationPreference) error {
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"email", "enable_yield_alerts", "enable_redemption_alerts", "enable_digest", "locale", "updated_at",
+		}),
+	}).Create(preference).Error
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// BigNumeric is a non-negative integer token amount stored as NUMERIC(78,0), wide enough
+// for any uint256. It serializes to JSON as a decimal string, like the plain string
+// amounts it replaces, so API payloads are unchanged
+type BigNumeric string
+
+// ZeroNumeric is the zero amount
+var ZeroNumeric BigNumeric = "0"
+
+// NewBigNumeric converts a big integer to a BigNumeric
+func NewBigNumeric(value *big.Int) BigNumeric {
+	if value == nil {
+		return ZeroNumeric
+	}
+	return BigNumeric(value.String())
+}
+
+// ParseBigNumeric parses a decimal integer string, rejecting signs, fractions and empty values
+func ParseBigNumeric(value string) (BigNumeric, error) {
+	n := BigNumeric(strings.TrimSpace(value))
+	if _, err := n.Int(); err != nil {
+		return "", err
+	}
+	return n.canonical(), nil
+}
+
+// Int returns the amount as a big integer, or an error when it does not parse
+func (n BigNumeric) Int() (*big.Int, error) {
+	s := string(n)
+	if s == "" || s[0] == '+' || s[0] == '-' {
+		return nil, fmt.Errorf("invalid numeric amount %q", s)
+	}
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid numeric amount %q", s)
+	}
+	if value.BitLen() > 256 {
+		return nil, fmt.Errorf("numeric amount %q exceeds 256 bits", s)
+	}
+	return value, nil
+}
+
+// Validate reports whether the amount parses, naming the field in the error
+func (n BigNumeric) Validate(field string) error {
+	if _, err := n.Int(); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+// String returns the decimal representation
+func (n BigNumeric) String() string {
+	return string(n)
+}
+
+// canonical strips leading zeros so equal amounts compare equal as strings
+func (n BigNumeric) canonical() BigNumeric {
+	value, err := n.Int()
+	if err != nil {
+		return n
+	}
+	return NewBigNumeric(value)
+}
+
+// GormDataType maps BigNumeric columns to NUMERIC(78,0) in AutoMigrate
+func (BigNumeric) GormDataType() string {
+	return "numeric(78,0)"
+}
+
+// Value implements driver.Valuer, refusing amounts that do not parse
+func (n BigNumeric) Value() (driver.Value, error) {
+	if _, err := n.Int(); err != nil {
+		return nil, err
+	}
+	return string(n.canonical()), nil
+}
+
+// Scan implements sql.Scanner for NUMERIC, text and integer columns
+func (n *BigNumeric) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*n = ""
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("cannot scan %T into BigNumeric", src)
+	}
+
+	parsed, err := ParseBigNumeric(s)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBigNumericScan(t *testing.T) {
+	maxUint256 := "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    BigNumeric
+		wantErr bool
+	}{
+		{"numeric as string", "1500000000000000000", "1500000000000000000", false},
+		{"numeric as bytes", []byte("42"), "42", false},
+		{"integer", int64(7), "7", false},
+		{"leading zeros", "007", "7", false},
+		{"max uint256", maxUint256, BigNumeric(maxUint256), false},
+		{"null", nil, "", false},
+
+		{"negative", "-1", "", true},
+		{"fraction", "1.5", "", true},
+		{"empty", "", "", true},
+		{"not a number", "abc", "", true},
+		{"over 256 bits", maxUint256 + "0", "", true},
+		{"float", 1.5, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n BigNumeric
+			err := n.Scan(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected %v to be rejected, got %q", tt.src, n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, n)
+			}
+		})
+	}
+}
+
+func TestBigNumericValueRejectsInvalidAmounts(t *testing.T) {
+	value, err := BigNumeric("0100").Value()
+	if err != nil || value != "100" {
+		t.Errorf("Expected canonical \"100\", got %v (%v)", value, err)
+	}
+
+	for _, invalid := range []BigNumeric{"", "-5", "+5", "1e18", " 1"} {
+		if _, err := invalid.Value(); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestBigNumericJSONIsString(t *testing.T) {
+	order := Order{TokenAmount: "1000000000000000000000"}
+	data, err := json.Marshal(order)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"token_amount":"1000000000000000000000"`) {
+		t.Errorf("Expected token_amount as a JSON string, got %s", data)
+	}
+
+	var decoded Order
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.TokenAmount != order.TokenAmount {
+		t.Errorf("Expected the amount to round-trip, got %q (%v)", decoded.TokenAmount, err)
+	}
+}
+
+func TestOrderBeforeSaveRejectsInvalidTokenAmount(t *testing.T) {
+	order := Order{TokenAmount: "12.5"}
+	if err := order.BeforeSave(nil); err == nil || !strings.Contains(err.Error(), "token_amount") {
+		t.Errorf("Expected a token_amount error, got %v", err)
+	}
+
+	order.TokenAmount = "125"
+	if err := order.BeforeSave(nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderStatus represents the state of a fiat purchase order
+type OrderStatus string
+
+const (
+	OrderStatusCreated OrderStatus = "created"
+	OrderStatusPaid    OrderStatus = "paid"
+	OrderStatusSettled OrderStatus = "settled"
+	OrderStatusFailed  OrderStatus = "failed"
+	OrderStatusExpired OrderStatus = "expired"
+)
+
+// orderStatusTransitions lists the states each state may move to
+// Settled, failed and expired orders are final
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusCreated: {OrderStatusPaid, OrderStatusFailed, OrderStatusExpired},
+	OrderStatusPaid:    {OrderStatusSettled, OrderStatusFailed},
+	OrderStatusSettled: {},
+	OrderStatusFailed:  {},
+	OrderStatusExpired: {},
+}
+
+// IsValid checks if the status is a known order status
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderStatusTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether no further transitions are possible from s
+func (s OrderStatus) IsFinal() bool {
+	return s.IsValid() && len(orderStatusTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOrderStatusTransition checks whether an order may move between states
+func ValidateOrderStatusTransition(from, to OrderStatus) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid order status: %s", to)
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("cannot change order status from %s to %s", from, to)
+	}
+	return nil
+}
+
+// Order is a fiat on-ramp purchase intent, reconciled against the partner's payment
+// and later against the onchain purchase
+type Order struct {
+	ID               uint        `gorm:"primaryKey" json:"id"`
+	UserAddress      string      `gorm:"size:42;not null;index" json:"user_address"`
+	SukukMetadataID  uint        `gorm:"not null;index" json:"sukuk_metadata_id"`
+	SukukAddress     string      `gorm:"size:42;not null" json:"sukuk_address"` // Copied from the metadata to match purchases
+	FiatAmount       float64     `gorm:"type:decimal(20,2);not null" json:"fiat_amount"`
+	TokenAmount      BigNumeric  `gorm:"type:numeric(78,0);not null" json:"token_amount"` // Quoted sukuk token amount in wei
+	PaymentReference string      `gorm:"size:64;uniqueIndex;not null" json:"payment_reference"`
+	Status           OrderStatus `gorm:"size:20;not null;default:created;index" json:"status"`
+	ExpiresAt        time.Time   `gorm:"not null;index" json:"expires_at"`
+	PaidAt           *time.Time  `json:"paid_at,omitempty"`
+	SettledAt        *time.Time  `json:"settled_at,omitempty"`
+	SettlementTxHash string      `gorm:"size:66;index" json:"settlement_tx_hash,omitempty"`
+	FailureReason    string      `gorm:"type:text" json:"failure_reason,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for Order model
+func (Order) TableName() string {
+	return "orders"
+}
+
+// BeforeSave hook to normalize addresses and reject token amounts that do not parse
+func (o *Order) BeforeSave(tx *gorm.DB) error {
+	o.UserAddress = normalizeAddress(o.UserAddress)
+	o.SukukAddress = normalizeAddress(o.SukukAddress)
+	return o.TokenAmount.Validate("token_amount")
+}
+
+// IsExpired reports whether an unpaid order is past its expiry at now
+func (o *Order) IsExpired(now time.Time) bool {
+	return o.Status == OrderStatusCreated && !now.Before(o.ExpiresAt)
+}
+
+// MarkPaid records the partner's payment confirmation
+// A created order past its expiry can no longer be paid
+func (o *Order) MarkPaid(at time.Time) error {
+	if o.IsExpired(at) {
+		return fmt.Errorf("order expired at %s", o.ExpiresAt.Format(time.RFC3339))
+	}
+	if err := ValidateOrderStatusTransition(o.Status, OrderStatusPaid); err != nil {
+		return err
+	}
+	o.Status = OrderStatusPaid
+	o.PaidAt = &at
+	return nil
+}
+
+// MarkFailed records a failed payment or settlement
+func (o *Order) MarkFailed(reason string) error {
+	if err := ValidateOrderStatusTransition(o.Status, OrderStatusFailed); err != nil {
+		return err
+	}
+	o.Status = OrderStatusFailed
+	o.FailureReason = reason
+	return nil
+}
+
+// Settle links the onchain purchase that fulfilled a paid order
+func (o *Order) Settle(txHash string, at time.Time) error {
+	if err := ValidateOrderStatusTransition(o.Status, OrderStatusSettled); err != nil {
+		return err
+	}
+	o.Status = OrderStatusSettled
+	o.SettlementTxHash = txHash
+	o.SettledAt = &at
+	return nil
+}
+
+// ExpireOrders moves created orders past their expiry to expired, returning how many changed
+func ExpireOrders(db *gorm.DB, now time.Time) (int64, error) {
+	result := db.Model(&Order{}).
+		Where("status = ? AND expires_at <= ?", OrderStatusCreated, now).
+		Update("status", OrderStatusExpired)
+	return result.RowsAffected, result.Error
+}
+
+// OutstandingOrderAmount sums the token amounts of a sukuk's orders that may still become
+// purchases: paid orders awaiting settlement and created orders not yet expired at now
+func OutstandingOrderAmount(db *gorm.DB, sukukID uint, now time.Time) (BigNumeric, error) {
+	var totals struct {
+		Outstanding string
+	}
+	err := db.Model(&Order{}).
+		Select("COALESCE(SUM(token_amount), 0)::text AS outstanding").
+		Where("sukuk_metadata_id = ? AND (status = ? OR (status = ? AND expires_at > ?))",
+			sukukID, OrderStatusPaid, OrderStatusCreated, now).
+		Scan(&totals).Error
+	if err != nil {
+		return "", err
+	}
+	return ParseBigNumeric(totals.Outstanding)
+}
+
+// OrderPaymentCallback records a payment result applied to an order, so a replayed callback
+// is recognised by its payment reference and status and applied only once
+type OrderPaymentCallback struct {
+	ID               uint        `gorm:"primaryKey" json:"id"`
+	OrderID          uint        `gorm:"not null;index" json:"order_id"`
+	PaymentReference string      `gorm:"size:64;not null;uniqueIndex:idx_order_payment_callbacks_reference_status" json:"payment_reference"`
+	Status           OrderStatus `gorm:"size:20;not null;uniqueIndex:idx_order_payment_callbacks_reference_status" json:"status"`
+	CreatedAt        time.Time   `json:"created_at"`
+}
+
+// TableName returns the table name for OrderPaymentCallback model
+func (OrderPaymentCallback) TableName() string {
+	return "order_payment_callbacks"
+}
+
+// RecordOrderPaymentCallback stores a payment result for an order, reporting false when the
+// same reference and status were already recorded
+func RecordOrderPaymentCallback(db *gorm.DB, order *Order, status OrderStatus) (bool, error) {
+	callback := OrderPaymentCallback{
+		OrderID:          order.ID,
+		PaymentReference: order.PaymentReference,
+		Status:           status,
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&callback)
+	return result.RowsAffected > 0, result.Error
+}
+
+// OrderCreateRequest represents the request payload for creating a purchase order
+type OrderCreateRequest struct {
+	UserAddress     string  `json:"user_address" binding:"required"`
+	SukukMetadataID uint    `json:"sukuk_metadata_id" binding:"required"`
+	FiatAmount      float64 `json:"fiat_amount" binding:"required,gt=0"` // Rupiah; the token amount is quoted from it
+}
+
+// OrderPaymentCallbackRequest is the partner's signed payment notification
+// Status defaults to paid; failed marks the order failed
+type OrderPaymentCallbackRequest struct {
+	PaymentReference string      `json:"payment_reference" binding:"required"`
+	Status           OrderStatus `json:"status"`
+	Reason           string      `json:"reason"`
+}
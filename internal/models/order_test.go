@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateOrderStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    OrderStatus
+		to      OrderStatus
+		wantErr bool
+	}{
+		{"created to paid", OrderStatusCreated, OrderStatusPaid, false},
+		{"created to failed", OrderStatusCreated, OrderStatusFailed, false},
+		{"created to expired", OrderStatusCreated, OrderStatusExpired, false},
+		{"paid to settled", OrderStatusPaid, OrderStatusSettled, false},
+		{"paid to failed", OrderStatusPaid, OrderStatusFailed, false},
+
+		{"created to settled", OrderStatusCreated, OrderStatusSettled, true},
+		{"paid to expired", OrderStatusPaid, OrderStatusExpired, true},
+		{"paid to created", OrderStatusPaid, OrderStatusCreated, true},
+		{"settled to failed", OrderStatusSettled, OrderStatusFailed, true},
+		{"expired to paid", OrderStatusExpired, OrderStatusPaid, true},
+		{"failed to paid", OrderStatusFailed, OrderStatusPaid, true},
+		{"unchanged status", OrderStatusPaid, OrderStatusPaid, true},
+		{"unknown target", OrderStatusCreated, OrderStatus("refunded"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOrderStatusTransition(tt.from, tt.to)
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected error for %s -> %s", tt.from, tt.to)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Unexpected error for %s -> %s: %v", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestOrderStatusIsFinal(t *testing.T) {
+	for _, status := range []OrderStatus{OrderStatusSettled, OrderStatusFailed, OrderStatusExpired} {
+		if !status.IsFinal() {
+			t.Errorf("Expected %s to be final", status)
+		}
+	}
+	for _, status := range []OrderStatus{OrderStatusCreated, OrderStatusPaid, OrderStatus("unknown")} {
+		if status.IsFinal() {
+			t.Errorf("Expected %s not to be final", status)
+		}
+	}
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{Status: OrderStatusCreated, ExpiresAt: now.Add(30 * time.Minute)}
+
+	if err := order.Settle("0xabc", now); err == nil {
+		t.Fatal("Expected an unpaid order not to settle")
+	}
+	if err := order.MarkPaid(now); err != nil {
+		t.Fatalf("Failed to mark paid: %v", err)
+	}
+	if order.Status != OrderStatusPaid || order.PaidAt == nil || !order.PaidAt.Equal(now) {
+		t.Errorf("Expected paid order with paid_at %s, got %s %v", now, order.Status, order.PaidAt)
+	}
+	if err := order.MarkPaid(now); err == nil {
+		t.Error("Expected a second payment to be rejected")
+	}
+	if err := order.Settle("0xabc", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to settle: %v", err)
+	}
+	if order.Status != OrderStatusSettled || order.SettlementTxHash != "0xabc" || order.SettledAt == nil {
+		t.Errorf("Expected settled order linked to 0xabc, got %+v", order)
+	}
+	if err := order.MarkFailed("late failure"); err == nil {
+		t.Error("Expected a settled order not to fail")
+	}
+}
+
+func TestOrderCannotBePaidAfterExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{Status: OrderStatusCreated, ExpiresAt: now}
+
+	// Expiry applies before the sweep has moved the order to expired
+	if !order.IsExpired(now) {
+		t.Fatal("Expected the order to be expired at expires_at")
+	}
+	if err := order.MarkPaid(now); err == nil {
+		t.Error("Expected payment after expiry to be rejected")
+	}
+	if order.Status != OrderStatusCreated || order.PaidAt != nil {
+		t.Errorf("Expected the order to be unchanged, got %+v", order)
+	}
+
+	if err := order.MarkFailed("payment declined"); err != nil {
+		t.Errorf("Expected an unpaid order to fail, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPaymentTokenExists is returned when a token address is already registered
+var ErrPaymentTokenExists = errors.New("payment token already registered")
+
+// DefaultTokenDecimals is assumed for tokens that are not registered as payment tokens
+const DefaultTokenDecimals uint8 = 18
+
+// PaymentToken stores ERC-20 metadata for tokens used to buy sukuk and pay yields
+type PaymentToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Address   string    `gorm:"size:42;uniqueIndex;not null" json:"address"`
+	Symbol    string    `gorm:"size:20;not null" json:"symbol"`
+	Name      string    `gorm:"size:100" json:"name"`
+	Decimals  uint8     `gorm:"not null;default:18" json:"decimals"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for PaymentToken model
+func (PaymentToken) TableName() string {
+	return "payment_tokens"
+}
+
+// BeforeSave hook to normalize the token address
+func (pt *PaymentToken) BeforeSave(tx *gorm.DB) error {
+	pt.Address = normalizeAddress(pt.Address)
+	return nil
+}
+
+// PaymentTokenCreateRequest represents the request payload for registering a payment token
+// Symbol, name and decimals are read from the contract when omitted or when auto_fetch is set
+type PaymentTokenCreateRequest struct {
+	Address   string `json:"address" binding:"required"`
+	Symbol    string `json:"symbol"`
+	Name      string `json:"name"`
+	Decimals  *uint8 `json:"decimals"`
+	AutoFetch bool   `json:"auto_fetch"`
+}
+
+// PaymentTokenUpdateRequest represents the request payload for updating a payment token
+type PaymentTokenUpdateRequest struct {
+	Symbol   *string `json:"symbol,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Decimals *uint8  `json:"decimals,omitempty"`
+}
+
+// FormattedAmount is a raw token amount together with its human-readable value
+type FormattedAmount struct {
+	Amount       string `json:"amount"`        // Raw amount in the token's smallest unit
+	Formatted    string `json:"formatted"`     // Amount scaled by the token decimals, e.g. "1.5"
+	Symbol       string `json:"symbol"`        // Token symbol, empty for unknown tokens
+	Decimals     uint8  `json:"decimals"`      // Decimals used for formatting
+	TokenAddress string `json:"token_address"` // Token contract address
+	UnknownToken bool   `json:"unknown_token"` // True when the token is not registered and 18 decimals were assumed
+}
+
+// GetPaymentTokenByAddress retrieves a payment token by contract address
+func GetPaymentTokenByAddress(db *gorm.DB, address string) (*PaymentToken, error) {
+	var token PaymentToken
+	err := db.Where("address = ?", normalizeAddress(address)).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CreatePaymentToken stores a payment token, returning ErrPaymentTokenExists if its address
+// is already registered
+func CreatePaymentToken(db *gorm.DB, token *PaymentToken) error {
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "address"}}, DoNothing: true}).Create(token)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentTokenExists
+	}
+	return nil
+}
+
+// GetAllPaymentTokens returns all registered payment tokens
+func GetAllPaymentTokens(db *gorm.DB) ([]PaymentToken, error) {
+	var tokens []PaymentToken
+	err := db.Order("symbol ASC").Find(&tokens).Error
+	return tokens, err
+}
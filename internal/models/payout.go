@@ -0,0 +1,219 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PayoutStatus is how far the issuer's payout of an approved redemption has gone
+type PayoutStatus string
+
+const (
+	PayoutPending   PayoutStatus = "pending"   // Instructed, not paid yet
+	PayoutExecuted  PayoutStatus = "executed"  // Paid by finance, with a tx hash or proof of transfer
+	PayoutConfirmed PayoutStatus = "confirmed" // Receipt confirmed; final
+)
+
+// payoutTransitions lists the statuses each status may move to
+var payoutTransitions = map[PayoutStatus][]PayoutStatus{
+	PayoutPending:  {PayoutExecuted},
+	PayoutExecuted: {PayoutConfirmed},
+}
+
+// IsValid checks if the payout status is supported
+func (s PayoutStatus) IsValid() bool {
+	return s == PayoutPending || s == PayoutExecuted || s == PayoutConfirmed
+}
+
+// CanTransitionTo reports whether an instruction with status s may move to next
+func (s PayoutStatus) CanTransitionTo(next PayoutStatus) bool {
+	for _, allowed := range payoutTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrInvalidPayoutTransition is returned for a status change the payout workflow doesn't allow
+	ErrInvalidPayoutTransition = errors.New("invalid payout status transition")
+	// ErrPayoutEvidenceRequired is returned when executing or confirming a payout that has
+	// neither an onchain tx hash nor an uploaded proof
+	ErrPayoutEvidenceRequired = errors.New("payout requires a tx hash or an uploaded proof")
+)
+
+// PayoutInstruction tracks the issuer's payout of an approved redemption, in a payment
+// token or by bank transfer, from instruction to confirmed receipt. There is at most one
+// instruction per redemption request
+type PayoutInstruction struct {
+	ID                  uint         `gorm:"primaryKey" json:"id"`
+	RedemptionRequestID string       `gorm:"size:100;not null;uniqueIndex" json:"redemption_request_id"`
+	UserAddress         string       `gorm:"size:42;not null" json:"user_address"`
+	SukukAddress        string       `gorm:"size:42;not null;index" json:"sukuk_address"`
+	Amount              BigNumeric   `gorm:"type:numeric(78,0);not null" json:"amount"` // In the payment token's wei, or the fiat amount for a bank transfer
+	PaymentToken        string       `gorm:"size:42" json:"payment_token,omitempty"`    // Token paid out onchain
+	BankReference       string       `gorm:"size:100" json:"bank_reference,omitempty"`  // Bank transfer reference of a fiat payout
+	Status              PayoutStatus `gorm:"size:16;not null;default:pending;index" json:"status"`
+	TxHash              string       `gorm:"size:66" json:"tx_hash,omitempty"`    // Onchain payout transaction
+	ProofURL            string       `gorm:"size:255" json:"proof_url,omitempty"` // Uploaded transfer receipt
+	Notes               string       `gorm:"type:text" json:"notes,omitempty"`
+	CreatedBy           string       `gorm:"size:100" json:"created_by"`
+	ExecutedBy          string       `gorm:"size:100" json:"executed_by,omitempty"`
+	ExecutedAt          *time.Time   `json:"executed_at,omitempty"`
+	ConfirmedBy         string       `gorm:"size:100" json:"confirmed_by,omitempty"`
+	ConfirmedAt         *time.Time   `json:"confirmed_at,omitempty"`
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+// TableName returns the table name for PayoutInstruction model
+func (PayoutInstruction) TableName() string {
+	return "payout_instructions"
+}
+
+// BeforeSave hook to normalize addresses
+func (p *PayoutInstruction) BeforeSave(tx *gorm.DB) error {
+	p.UserAddress = normalizeAddress(p.UserAddress)
+	p.SukukAddress = normalizeAddress(p.SukukAddress)
+	p.PaymentToken = normalizeAddress(p.PaymentToken)
+	p.TxHash = strings.ToLower(p.TxHash)
+	return p.Amount.Validate("amount")
+}
+
+// Transition moves the instruction to next, recording who made the change and when.
+// Executing or confirming a payout requires its evidence: an onchain tx hash or an
+// uploaded proof
+func (p *PayoutInstruction) Transition(next PayoutStatus, actor string, at time.Time) error {
+	if !p.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidPayoutTransition, p.Status, next)
+	}
+	if p.TxHash == "" && p.ProofURL == "" {
+		return ErrPayoutEvidenceRequired
+	}
+
+	switch next {
+	case PayoutExecuted:
+		p.ExecutedBy = actor
+		p.ExecutedAt = &at
+	case PayoutConfirmed:
+		p.ConfirmedBy = actor
+		p.ConfirmedAt = &at
+	}
+	p.Status = next
+	return nil
+}
+
+// PayoutInstructionRequest creates the payout instruction of an approved redemption
+type PayoutInstructionRequest struct {
+	RedemptionRequestID string `json:"redemption_request_id" binding:"required,max=100"`
+	Amount              string `json:"amount"`                                     // Defaults to the approved amount
+	PaymentToken        string `json:"payment_token"`                              // Defaults to the redemption's payment token unless bank_reference is set
+	BankReference       string `json:"bank_reference" binding:"omitempty,max=100"` // For a fiat payout
+	Notes               string `json:"notes"`
+}
+
+// PayoutInstructionUpdateRequest advances a payout instruction or records its evidence.
+// Omitted fields are left unchanged
+type PayoutInstructionUpdateRequest struct {
+	Status        PayoutStatus `json:"status" binding:"omitempty,oneof=pending executed confirmed"`
+	TxHash        *string      `json:"tx_hash"`
+	BankReference *string      `json:"bank_reference" binding:"omitempty,max=100"`
+	Notes         *string      `json:"notes"`
+}
+
+// PayoutInstructionListResponse is a page of payout instructions
+type PayoutInstructionListResponse struct {
+	Instructions []PayoutInstruction `json:"instructions"`
+	TotalCount   int64               `json:"total_count"`
+}
+
+// PayoutListFilter selects payout instructions; empty fields match every instruction
+type PayoutListFilter struct {
+	Status       PayoutStatus
+	SukukAddress string
+	Limit        int
+	Offset       int
+}
+
+// ListPayoutInstructions returns a page of instructions matching filter, newest first,
+// with the count of every match
+func ListPayoutInstructions(db *gorm.DB, filter PayoutListFilter) ([]PayoutInstruction, int64, error) {
+	filtered := func() *gorm.DB {
+		query := db.Model(&PayoutInstruction{})
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.SukukAddress != "" {
+			query = query.Where("sukuk_address = ?", normalizeAddress(filter.SukukAddress))
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	instructions := []PayoutInstruction{}
+	err := filtered().Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&instructions).Error
+	return instructions, total, err
+}
+
+// PayoutStatusesByRedemption returns the payout status of each of the redemption requests
+// that has an instruction
+func PayoutStatusesByRedemption(db *gorm.DB, requestIDs []string) (map[string]PayoutStatus, error) {
+	statuses := make(map[string]PayoutStatus)
+	if len(requestIDs) == 0 {
+		return statuses, nil
+	}
+
+	var instructions []PayoutInstruction
+	err := db.Select("redemption_request_id", "status").
+		Where("redemption_request_id IN ?", requestIDs).
+		Find(&instructions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, instruction := range instructions {
+		statuses[instruction.RedemptionRequestID] = instruction.Status
+	}
+	return statuses, nil
+}
+
+// ApplyPayoutStatuses sets the payout status of the redemptions that have an instruction
+func ApplyPayoutStatuses(redemptions []RedemptionRequest, statuses map[string]PayoutStatus) {
+	for i := range redemptions {
+		if status, ok := statuses[redemptions[i].RequestID]; ok {
+			redemptions[i].PayoutStatus = &status
+		}
+	}
+}
+
+// MaxPayoutProofSize bounds an uploaded proof of payout
+const MaxPayoutProofSize = 10 * 1024 * 1024
+
+// payoutProofExtensions are the file types accepted as proof of payout, e.g. a bank
+// transfer receipt as a PDF or screenshot
+var payoutProofExtensions = []string{".pdf", ".png", ".jpg", ".jpeg"}
+
+// ValidatePayoutProofFile checks an uploaded proof of payout by its name, size and
+// leading bytes
+func ValidatePayoutProofFile(filename string, size int64, head []byte) error {
+	if size > MaxPayoutProofSize {
+		return fmt.Errorf("file size too large (max %dMB)", MaxPayoutProofSize/(1024*1024))
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, allowed := range payoutProofExtensions {
+		if ext == allowed {
+			return validateFileContent(ext, head)
+		}
+	}
+	return fmt.Errorf("file type not allowed for a payout proof. Allowed types: %v", payoutProofExtensions)
+}
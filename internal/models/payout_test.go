@@ -0,0 +1,135 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPayoutTransitions(t *testing.T) {
+	tests := []struct {
+		from, to PayoutStatus
+		allowed  bool
+	}{
+		{PayoutPending, PayoutExecuted, true},
+		{PayoutExecuted, PayoutConfirmed, true},
+		{PayoutPending, PayoutConfirmed, false}, // Can't confirm a payout nobody executed
+		{PayoutExecuted, PayoutPending, false},
+		{PayoutConfirmed, PayoutExecuted, false}, // Confirmed is final
+		{PayoutConfirmed, PayoutPending, false},
+		{PayoutPending, PayoutPending, false},
+		{PayoutPending, "cancelled", false},
+	}
+	at := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.allowed {
+				t.Errorf("Expected CanTransitionTo to be %v, got %v", tt.allowed, got)
+			}
+
+			instruction := &PayoutInstruction{Status: tt.from, TxHash: "0xabc"}
+			err := instruction.Transition(tt.to, "api-key:10.0.0.1", at)
+			if tt.allowed {
+				if err != nil || instruction.Status != tt.to {
+					t.Errorf("Expected the move to %s, got %s (%v)", tt.to, instruction.Status, err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidPayoutTransition) {
+				t.Errorf("Expected ErrInvalidPayoutTransition, got %v", err)
+			}
+			if instruction.Status != tt.from {
+				t.Errorf("Expected the status to stay %s, got %s", tt.from, instruction.Status)
+			}
+		})
+	}
+}
+
+func TestPayoutTransitionRecordsActor(t *testing.T) {
+	executedAt := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	instruction := &PayoutInstruction{Status: PayoutPending, ProofURL: "/uploads/payouts/1/proof_1.pdf"}
+
+	if err := instruction.Transition(PayoutExecuted, "api-key:10.0.0.1", executedAt); err != nil {
+		t.Fatalf("Expected a payout with a proof to be executed, got %v", err)
+	}
+	if instruction.ExecutedBy != "api-key:10.0.0.1" || instruction.ExecutedAt == nil || !instruction.ExecutedAt.Equal(executedAt) {
+		t.Errorf("Expected the executor and time recorded, got %q at %v", instruction.ExecutedBy, instruction.ExecutedAt)
+	}
+
+	confirmedAt := executedAt.Add(24 * time.Hour)
+	if err := instruction.Transition(PayoutConfirmed, "api-key:10.0.0.2", confirmedAt); err != nil {
+		t.Fatalf("Expected an executed payout to be confirmed, got %v", err)
+	}
+	if instruction.ConfirmedBy != "api-key:10.0.0.2" || instruction.ConfirmedAt == nil || !instruction.ConfirmedAt.Equal(confirmedAt) {
+		t.Errorf("Expected the confirmer and time recorded, got %q at %v", instruction.ConfirmedBy, instruction.ConfirmedAt)
+	}
+	if instruction.ExecutedBy != "api-key:10.0.0.1" {
+		t.Errorf("Expected the executor kept, got %q", instruction.ExecutedBy)
+	}
+}
+
+func TestPayoutTransitionRequiresEvidence(t *testing.T) {
+	instruction := &PayoutInstruction{Status: PayoutPending, BankReference: "BCA-20250601-001"}
+
+	err := instruction.Transition(PayoutExecuted, "api-key:10.0.0.1", time.Now())
+	if !errors.Is(err, ErrPayoutEvidenceRequired) {
+		t.Fatalf("Expected ErrPayoutEvidenceRequired without a tx hash or proof, got %v", err)
+	}
+	if instruction.Status != PayoutPending || instruction.ExecutedAt != nil {
+		t.Errorf("Expected the instruction unchanged, got %+v", instruction)
+	}
+
+	instruction.TxHash = "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	if err := instruction.Transition(PayoutExecuted, "api-key:10.0.0.1", time.Now()); err != nil {
+		t.Errorf("Expected a payout with a tx hash to be executed, got %v", err)
+	}
+}
+
+func TestApplyPayoutStatuses(t *testing.T) {
+	redemptions := []RedemptionRequest{
+		{RequestID: "0xaa-1", Status: RedemptionStatusApproved},
+		{RequestID: "0xbb-2", Status: RedemptionStatusRequested},
+		{RequestID: "0xcc-3", Status: RedemptionStatusApproved},
+	}
+
+	ApplyPayoutStatuses(redemptions, map[string]PayoutStatus{
+		"0xaa-1": PayoutExecuted,
+		"0xcc-3": PayoutPending,
+		"0xdd-4": PayoutConfirmed, // Not on this page
+	})
+
+	want := map[string]PayoutStatus{"0xaa-1": PayoutExecuted, "0xcc-3": PayoutPending}
+	for _, redemption := range redemptions {
+		expected, ok := want[redemption.RequestID]
+		switch {
+		case !ok && redemption.PayoutStatus != nil:
+			t.Errorf("Expected no payout status for %s, got %s", redemption.RequestID, *redemption.PayoutStatus)
+		case ok && (redemption.PayoutStatus == nil || *redemption.PayoutStatus != expected):
+			t.Errorf("Expected payout status %s for %s, got %v", expected, redemption.RequestID, redemption.PayoutStatus)
+		}
+	}
+	if *redemptions[0].PayoutStatus == *redemptions[2].PayoutStatus {
+		t.Error("Expected each redemption to carry its own payout status")
+	}
+}
+
+func TestValidatePayoutProofFile(t *testing.T) {
+	pdf := []byte("%PDF-1.4 receipt")
+	png := []byte("\x89PNG\r\n\x1a\n receipt")
+
+	if err := ValidatePayoutProofFile("receipt.pdf", int64(len(pdf)), pdf); err != nil {
+		t.Errorf("Expected a PDF receipt accepted, got %v", err)
+	}
+	if err := ValidatePayoutProofFile("Transfer.PNG", int64(len(png)), png); err != nil {
+		t.Errorf("Expected a PNG screenshot accepted, got %v", err)
+	}
+	if err := ValidatePayoutProofFile("receipt.pdf", int64(len(png)), png); err == nil {
+		t.Error("Expected a PNG renamed to .pdf rejected")
+	}
+	if err := ValidatePayoutProofFile("receipt.xlsx", int64(len(pdf)), pdf); err == nil {
+		t.Error("Expected a spreadsheet rejected")
+	}
+	if err := ValidatePayoutProofFile("receipt.pdf", MaxPayoutProofSize+1, pdf); err == nil {
+		t.Error("Expected a file over the size limit rejected")
+	}
+}
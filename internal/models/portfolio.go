@@ -13,6 +13,16 @@ type PortfolioResponse struct {
 	Holdings     []SukukHolding    `json:"holdings"`
 	TotalValue   string            `json:"total_value,omitempty"`    // Total portfolio value in USD/base currency
 	Summary      PortfolioSummary  `json:"summary"`
+	KYCStatus    KYCStatus         `json:"kyc_status,omitempty"`     // Only included for admin requests
+	Meta         *DisplayMeta      `json:"meta,omitempty"`           // Exchange rates, with ?fiat
+	Complete     bool              `json:"complete"`                 // False when some holdings could not be computed
+	Warnings     []PortfolioWarning `json:"warnings,omitempty"`      // Holdings left out of an incomplete portfolio
+}
+
+// PortfolioWarning is a holding left out of a portfolio because it could not be computed
+type PortfolioWarning struct {
+	SukukAddress string `json:"sukuk_address"`
+	Reason       string `json:"reason"`
 }
 
 // SukukHolding represents user's holding in a specific sukuk
@@ -20,11 +30,13 @@ type SukukHolding struct {
 	SukukAddress           string               `json:"sukuk_address"`
 	Balance                string               `json:"balance"`                    // Current token balance
 	ClaimableYield         string               `json:"claimable_yield"`           // Available yield to claim
+	ClaimableYieldFormatted *FormattedAmount    `json:"claimable_yield_formatted,omitempty"` // Claimable yield in the payment token's decimals
+	SupplySource           SupplySource         `json:"supply_source"`             // Where the supply claimable_yield is a share of came from
 	TotalYieldClaimed      string               `json:"total_yield_claimed"`       // Total yield claimed historically
 	UnclaimedDistributions []int64              `json:"unclaimed_distribution_ids"` // Distribution IDs available for claiming
 	LastActivity           *time.Time           `json:"last_activity,omitempty"`   // Last purchase/redemption
 	Metadata               *SukukMetadata       `json:"metadata,omitempty"`        // Sukuk details
-	YieldHistory           []YieldDistribution  `json:"yield_history,omitempty"`   // Recent yield distributions
+	YieldHistory           []YieldDistribution  `json:"yield_history"`             // Recent yield distributions, newest first
 }
 
 // PortfolioSummary provides aggregate portfolio statistics
@@ -34,6 +46,9 @@ type PortfolioSummary struct {
 	TotalYieldClaimed    string `json:"total_yield_claimed"`
 	ActiveSukukCount     int    `json:"active_sukuk_count"`     // Sukuk with non-zero balance
 	MaturedSukukCount    int    `json:"matured_sukuk_count"`    // Sukuk that have matured
+
+	// Fiat equivalents of total_claimable_yield and total_yield_claimed, with ?fiat
+	DisplayValues map[string]DisplayValues `json:"display_values,omitempty"`
 }
 
 // YieldClaimsResponse represents available yield claims for a user
@@ -42,6 +57,7 @@ type YieldClaimsResponse struct {
 	TotalClaims  int                 `json:"total_claims"`
 	Claims       []YieldClaimDetail  `json:"claims"`
 	TotalAmount  string              `json:"total_amount"`   // Total claimable across all sukuk
+	SkippedRows  int                 `json:"skipped_rows"`   // Claims left out of the total because of an empty or malformed amount
 }
 
 // YieldClaimDetail represents claimable yield for a specific sukuk
@@ -60,12 +76,21 @@ type YieldDistribution struct {
 	ID             string    `json:"id"`
 	SukukAddress   string    `json:"sukuk_address"`
 	DistributionId int64     `json:"distribution_id"`  // Required for claiming yields
+	PaymentToken   string    `json:"payment_token"`
 	Amount         string    `json:"amount"`
+	AmountFormatted *FormattedAmount `json:"amount_formatted,omitempty"` // Amount in the payment token's decimals
 	Timestamp      time.Time `json:"timestamp"`
 	TxHash         string    `json:"tx_hash"`
 	BlockNumber    int64     `json:"block_number"`
 }
 
+// YieldDistributionsResponse represents the yield distribution history of a sukuk
+type YieldDistributionsResponse struct {
+	Distributions []YieldDistribution `json:"distributions"`
+	SukukAddress  string              `json:"sukuk_address"`
+	TotalCount    int                 `json:"total_count"`
+}
+
 // YieldClaim represents a yield claim event
 type YieldClaim struct {
 	ID           string    `json:"id"`
@@ -82,11 +107,12 @@ type TransactionHistoryResponse struct {
 	Address      string              `json:"address"`
 	TotalCount   int                 `json:"total_count"`
 	Transactions []TransactionEvent  `json:"transactions"`
+	KYCStatus    KYCStatus           `json:"kyc_status,omitempty"` // Only included for admin requests
 }
 
 // TransactionEvent represents any blockchain event related to the user
 type TransactionEvent struct {
-	Type         string    `json:"type"`           // "purchase", "redemption", "yield_claim", "yield_distribution"
+	Type         ActivityType `json:"type"`        // "purchase", "redemption_request" or "yield_claim"
 	SukukAddress string    `json:"sukuk_address"`
 	Amount       string    `json:"amount"`
 	TxHash       string    `json:"tx_hash"`
@@ -96,6 +122,56 @@ type TransactionEvent struct {
 	Details      map[string]interface{} `json:"details,omitempty"` // Additional event-specific data
 }
 
+// BalanceChangeTransfer labels a balance change with no purchase, redemption request or
+// yield claim in the same transaction, such as a plain ERC-20 transfer
+const BalanceChangeTransfer = "transfer"
+
+// BalanceHistoryResponse is a page of a holder's balance changes on one sukuk, oldest first
+type BalanceHistoryResponse struct {
+	Address      string          `json:"address"`
+	SukukAddress string          `json:"sukuk_address"`
+	Changes      []BalanceChange `json:"changes"`
+	TotalCount   int64           `json:"total_count"` // Changes matching the filters across all pages
+	Page         int             `json:"page"`
+	PerPage      int             `json:"per_page"`
+	TotalPages   int             `json:"total_pages"`
+}
+
+// BalanceChange is one holder_update row with its change from the previous balance
+type BalanceChange struct {
+	Timestamp   time.Time `json:"timestamp"`
+	NewBalance  string    `json:"new_balance"`
+	Delta       string    `json:"delta"`      // Signed, e.g. "-500" when tokens left the wallet
+	EventType   string    `json:"event_type"` // Activity type of the event in the same tx, or "transfer"
+	TxHash      string    `json:"tx_hash"`
+	BlockNumber int64     `json:"block_number"`
+}
+
+// PortfolioBatchMaxAddresses is the most addresses one portfolio batch request may carry
+const PortfolioBatchMaxAddresses = 100
+
+// PortfolioBatchRequest asks for the portfolio summaries of several addresses
+type PortfolioBatchRequest struct {
+	Addresses []string `json:"addresses" binding:"required,min=1,max=100"`
+}
+
+// PortfolioBatchEntry is the portfolio summary of one address in a batch, or why it has none
+type PortfolioBatchEntry struct {
+	HoldingsCount       int                `json:"holdings_count"`
+	TotalBalance        string             `json:"total_balance"`         // Raw sukuk token balance summed across holdings
+	TotalClaimableYield string             `json:"total_claimable_yield"` // Raw claimable yield summed across holdings
+	Complete            bool               `json:"complete"`              // False when some holdings could not be computed, or for an error entry
+	Warnings            []PortfolioWarning `json:"warnings,omitempty"`    // Holdings left out of the totals of an incomplete summary
+	Error               string             `json:"error,omitempty"`       // Set instead of the summary, e.g. for an invalid address
+}
+
+// PortfolioBatchResponse maps each requested address, as sent, to its portfolio summary
+type PortfolioBatchResponse struct {
+	Portfolios  map[string]PortfolioBatchEntry `json:"portfolios"`
+	Truncated   bool                           `json:"truncated"`             // The time budget ran out before every address was summarized
+	Unprocessed []string                       `json:"unprocessed,omitempty"` // Addresses left out when truncated, to request again
+}
+
 // IndexerTableInfo represents discovered indexer table information
 type IndexerTableInfo struct {
 	EventType    string `json:"event_type"`
@@ -103,6 +179,7 @@ type IndexerTableInfo struct {
 	HashPrefix   string `json:"hash_prefix"`
 	RowCount     int64  `json:"row_count,omitempty"`
 	LastUpdated  *time.Time `json:"last_updated,omitempty"`
+	Overridden   bool   `json:"overridden"` // Pinned for its event type by an override
 }
 
 // IndexerTablesResponse represents the debug response for indexer tables
@@ -111,6 +188,7 @@ type IndexerTablesResponse struct {
 	AvailableEvents  []string            `json:"available_events"`
 	Tables           []IndexerTableInfo  `json:"tables"`
 	LatestTables     map[string]string   `json:"latest_tables"`    // event_type -> table_name mapping
+	Overrides        map[string]string   `json:"overrides"`        // event_type -> table_name pinned by an admin
 }
 
 // HoldingCalculation represents intermediate calculation data
@@ -120,7 +198,7 @@ type HoldingCalculation struct {
 	CurrentBalance   string  `json:"current_balance"`
 	PurchaseHistory  []PurchaseEvent  `json:"purchase_history,omitempty"`
 	RedemptionHistory []RedemptionEvent `json:"redemption_history,omitempty"`
-	DistributionShare float64 `json:"distribution_share"`  // User's share of distributions (0.0-1.0)
+	DistributionShare string  `json:"distribution_share"`  // User's share of distributions in percent, e.g. "12.50"
 }
 
 // PurchaseEvent represents a sukuk purchase
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// ReconciliationDetailLimit caps each list of discrepancies in a reconciliation report
+const ReconciliationDetailLimit = 500
+
+// Reconciliation categories
+const (
+	ReconciliationPurchases          = "purchases"
+	ReconciliationRedemptionRequests = "redemption_requests"
+)
+
+// ReconciliationFixMissingInvestments backfills purchase events the local table is missing
+const ReconciliationFixMissingInvestments = "missing_investments"
+
+// ReconciliationReport compares the locally stored events of a sukuk with the indexer
+type ReconciliationReport struct {
+	SukukAddress string                   `json:"sukuk_address"`
+	GeneratedAt  time.Time                `json:"generated_at"`
+	InSync       bool                     `json:"in_sync"`      // True when no category has discrepancies
+	DetailLimit  int                      `json:"detail_limit"` // Maximum entries listed per discrepancy
+	Categories   []ReconciliationCategory `json:"categories"`
+	Fix          *ReconciliationFix       `json:"fix,omitempty"` // Set when a fix was applied before reconciling
+}
+
+// ReconciliationCategory compares one event type between the indexer and its local table
+type ReconciliationCategory struct {
+	Name             string                        `json:"name"`
+	IndexerTable     string                        `json:"indexer_table"`
+	LocalTable       string                        `json:"local_table"`
+	IndexerCount     int64                         `json:"indexer_count"`
+	LocalCount       int64                         `json:"local_count"`
+	IndexerAmount    string                        `json:"indexer_amount"` // Summed raw amount
+	LocalAmount      string                        `json:"local_amount"`
+	MissingLocally   ReconciliationDiscrepancyList `json:"missing_locally"`    // In the indexer but not stored locally
+	MissingInIndexer ReconciliationDiscrepancyList `json:"missing_in_indexer"` // Stored locally but unknown to the indexer
+	AmountMismatches ReconciliationDiscrepancyList `json:"amount_mismatches"`  // Same event with different amounts
+}
+
+// InSync reports whether the category has no discrepancies
+func (c ReconciliationCategory) InSync() bool {
+	return c.MissingLocally.Total == 0 && c.MissingInIndexer.Total == 0 && c.AmountMismatches.Total == 0
+}
+
+// ReconciliationDiscrepancyList holds the first entries of a discrepancy and its total count
+type ReconciliationDiscrepancyList struct {
+	Total   int64                       `json:"total"`
+	Entries []ReconciliationDiscrepancy `json:"entries"`
+}
+
+// ReconciliationDiscrepancy identifies an event by transaction hash and log index
+type ReconciliationDiscrepancy struct {
+	TxHash        string `json:"tx_hash"`
+	LogIndex      int64  `json:"log_index"`
+	IndexerAmount string `json:"indexer_amount,omitempty"`
+	LocalAmount   string `json:"local_amount,omitempty"`
+}
+
+// ReconciliationFix reports a fix applied by the reconciliation endpoint
+type ReconciliationFix struct {
+	Action   string `json:"action"`
+	Inserted int    `json:"inserted"`
+}
@@ -34,6 +34,9 @@ type RedemptionRequest struct {
 	ApprovalTime        *time.Time       `json:"approval_time,omitempty"`
 	ApprovalBlock       *int64           `json:"approval_block,omitempty"`
 	ApprovedAmount      *string          `json:"approved_amount,omitempty"`
+
+	// Issuer payout of an approved redemption, absent until finance creates its instruction
+	PayoutStatus *PayoutStatus `json:"payout_status,omitempty"`
 	
 	// Metadata for UI/Business Logic
 	Metadata            *SukukMetadata   `json:"metadata,omitempty"`
@@ -46,6 +49,15 @@ type RedemptionListResponse struct {
 	TotalCount   int                 `json:"total_count"`
 	Redemptions  []RedemptionRequest `json:"redemptions"`
 	StatusCounts map[string]int      `json:"status_counts"` // requested: 5, approved: 2, etc.
+
+	// Totals in wei, over every row with a parseable amount
+	TotalRedemptionAmount     string `json:"total_redemption_amount"`
+	CompletedRedemptionAmount string `json:"completed_redemption_amount"` // Approved amounts of approved or completed redemptions
+	SkippedRows               int    `json:"skipped_rows"`                // Rows left out of the totals because of an empty or malformed amount
+
+	// Totals in the payment token's decimals, set when every row uses the same payment token
+	TotalRedemptionAmountFormatted     *FormattedAmount `json:"total_redemption_amount_formatted,omitempty"`
+	CompletedRedemptionAmountFormatted *FormattedAmount `json:"completed_redemption_amount_formatted,omitempty"`
 }
 
 // RedemptionApprovalRequest for making approval calls
@@ -71,6 +83,10 @@ type RedemptionStatsResponse struct {
 	
 	// By Sukuk breakdown
 	BySukuk map[string]RedemptionSukukStats `json:"by_sukuk"`
+
+	// Fiat equivalents of total_requested_amount and total_approved_amount, with ?fiat
+	DisplayValues map[string]DisplayValues `json:"display_values,omitempty"`
+	Meta          *DisplayMeta             `json:"meta,omitempty"` // Exchange rates, with ?fiat
 }
 
 type RedemptionSukukStats struct {
@@ -79,6 +95,14 @@ type RedemptionSukukStats struct {
 	RequestCount    int    `json:"request_count"`
 	RequestedAmount string `json:"requested_amount"`
 	ApprovedAmount  string `json:"approved_amount"`
+	PaymentToken    string `json:"payment_token,omitempty"`
+
+	// Amounts in the payment token's decimals
+	RequestedAmountFormatted *FormattedAmount `json:"requested_amount_formatted,omitempty"`
+	ApprovedAmountFormatted  *FormattedAmount `json:"approved_amount_formatted,omitempty"`
+
+	// Fiat equivalents of requested_amount and approved_amount, with ?fiat
+	DisplayValues map[string]DisplayValues `json:"display_values,omitempty"`
 }
 
 // BlockchainCallRequest for making the actual approval transaction
@@ -0,0 +1,189 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Referral errors
+var (
+	ErrReferralCodeTaken    = errors.New("referral code already exists")
+	ErrReferralAlreadyBound = errors.New("address already has a referral code")
+)
+
+// ErrInvalidReferralCode is returned for codes that are not 3-32 letters, digits, '-' or '_'
+var ErrInvalidReferralCode = errors.New("referral code must be 3-32 letters, digits, '-' or '_'")
+
+var referralCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// NormalizeReferralCode uppercases a code so codes match case-insensitively, and validates it
+func NormalizeReferralCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !referralCodePattern.MatchString(code) {
+		return "", ErrInvalidReferralCode
+	}
+	return code, nil
+}
+
+// Referral is a marketing referral code owned by an address
+type Referral struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Code         string    `gorm:"size:32;uniqueIndex;not null" json:"code"`
+	OwnerAddress string    `gorm:"size:42;not null;index" json:"owner_address"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for Referral model
+func (Referral) TableName() string {
+	return "referrals"
+}
+
+// BeforeSave hook to normalize the owner address
+func (r *Referral) BeforeSave(tx *gorm.DB) error {
+	r.OwnerAddress = normalizeAddress(r.OwnerAddress)
+	return nil
+}
+
+// ReferralBinding ties a buyer's wallet to the one referral code it registered
+// Only purchases made at or after BoundAt are attributed to the code
+type ReferralBinding struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ReferralID uint      `gorm:"not null;index" json:"referral_id"`
+	Address    string    `gorm:"size:42;uniqueIndex;not null" json:"address"`
+	BoundAt    time.Time `gorm:"not null" json:"bound_at"`
+}
+
+// TableName returns the table name for ReferralBinding model
+func (ReferralBinding) TableName() string {
+	return "referral_bindings"
+}
+
+// BeforeSave hook to normalize the address
+func (rb *ReferralBinding) BeforeSave(tx *gorm.DB) error {
+	rb.Address = normalizeAddress(rb.Address)
+	return nil
+}
+
+// ReferralAttribution credits an onchain purchase to the referral code its buyer had bound
+type ReferralAttribution struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	ReferralID     uint       `gorm:"not null;index" json:"referral_id"`
+	BuyerAddress   string     `gorm:"size:42;not null;index" json:"buyer_address"`
+	PurchaseTxHash string     `gorm:"size:66;uniqueIndex;not null" json:"purchase_tx_hash"`
+	Amount         BigNumeric `gorm:"type:numeric(78,0);not null" json:"amount"`
+	AttributedAt   time.Time  `gorm:"not null" json:"attributed_at"`
+}
+
+// TableName returns the table name for ReferralAttribution model
+func (ReferralAttribution) TableName() string {
+	return "referral_attributions"
+}
+
+// BeforeCreate hook to normalize the buyer and reject amounts that do not parse
+func (ra *ReferralAttribution) BeforeCreate(tx *gorm.DB) error {
+	ra.BuyerAddress = normalizeAddress(ra.BuyerAddress)
+	return ra.Amount.Validate("amount")
+}
+
+// CreateReferral stores a referral code, returning ErrReferralCodeTaken if the code exists
+func CreateReferral(db *gorm.DB, referral *Referral) error {
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).Create(referral)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReferralCodeTaken
+	}
+	return nil
+}
+
+// GetReferralByCode retrieves a referral by its normalized code
+func GetReferralByCode(db *gorm.DB, code string) (*Referral, error) {
+	var referral Referral
+	if err := db.Where("code = ?", code).First(&referral).Error; err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// BindReferral binds the referral to an address from boundAt on, returning
+// ErrReferralAlreadyBound if the address already bound a code
+func BindReferral(db *gorm.DB, referral *Referral, address string, boundAt time.Time) (*ReferralBinding, error) {
+	binding := ReferralBinding{
+		ReferralID: referral.ID,
+		Address:    address,
+		BoundAt:    boundAt,
+	}
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "address"}}, DoNothing: true}).Create(&binding)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrReferralAlreadyBound
+	}
+	return &binding, nil
+}
+
+// CreateReferralAttribution stores an attribution, returning ErrDuplicateEvent if the purchase was already attributed
+func CreateReferralAttribution(db *gorm.DB, attribution *ReferralAttribution) error {
+	result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "purchase_tx_hash"}}, DoNothing: true}).Create(attribution)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDuplicateEvent
+	}
+	return nil
+}
+
+// GetReferralStats counts the wallets bound to a referral and sums its attributed purchases
+func GetReferralStats(db *gorm.DB, referral *Referral) (*ReferralStatsResponse, error) {
+	stats := ReferralStatsResponse{
+		Code:         referral.Code,
+		OwnerAddress: referral.OwnerAddress,
+	}
+	if err := db.Model(&ReferralBinding{}).Where("referral_id = ?", referral.ID).Count(&stats.BoundAddresses).Error; err != nil {
+		return nil, err
+	}
+
+	var totals struct {
+		AttributedPurchases int64
+		TotalAmount         string
+	}
+	err := db.Model(&ReferralAttribution{}).
+		Select("COUNT(*) AS attributed_purchases, COALESCE(SUM(amount), 0)::text AS total_amount").
+		Where("referral_id = ?", referral.ID).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.AttributedPurchases = totals.AttributedPurchases
+	stats.TotalAmount = totals.TotalAmount
+	return &stats, nil
+}
+
+// ReferralCreateRequest creates a referral code
+type ReferralCreateRequest struct {
+	Code         string `json:"code" binding:"required"`
+	OwnerAddress string `json:"owner_address" binding:"required"`
+}
+
+// ReferralClaimRequest binds a referral code to the signed-in wallet
+type ReferralClaimRequest struct {
+	Code    string `json:"code" binding:"required"`
+	Address string `json:"address,omitempty"` // Optional, refused unless it is the signed-in wallet
+}
+
+// ReferralStatsResponse summarizes the purchases attributed to a code
+type ReferralStatsResponse struct {
+	Code                string `json:"code"`
+	OwnerAddress        string `json:"owner_address"`
+	BoundAddresses      int64  `json:"bound_addresses"`
+	AttributedPurchases int64  `json:"attributed_purchases"`
+	TotalAmount         string `json:"total_amount"` // Raw sum of attributed purchase amounts
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeReferralCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"launch-2025", "LAUNCH-2025", false},
+		{"  sukuk_ri ", "SUKUK_RI", false},
+		{"AB", "", true},
+		{"has space", "", true},
+		{"emoji🙂", "", true},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeReferralCode(tt.code)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidReferralCode) {
+				t.Errorf("NormalizeReferralCode(%q) expected ErrInvalidReferralCode, got %q, %v", tt.code, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeReferralCode(%q) = %q, %v, want %q", tt.code, got, err, tt.want)
+		}
+	}
+}
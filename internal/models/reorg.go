@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventStatus tells whether the transaction of a stored event is still on the canonical chain
+type EventStatus string
+
+const (
+	EventStatusConfirmed EventStatus = "confirmed"
+	EventStatusOrphaned  EventStatus = "orphaned" // Its transaction was dropped by a chain reorg
+)
+
+// ReorgAffectedRow is a derived row orphaned by a reorg
+type ReorgAffectedRow struct {
+	Table  string `json:"table"`
+	ID     uint   `json:"id"`
+	TxHash string `json:"tx_hash"`
+}
+
+// ReorgIncident records the derived rows one reconciliation run orphaned for an indexer event
+// type, after finding their transactions in the reorg table but no longer in the canonical one
+type ReorgIncident struct {
+	ID           uint               `gorm:"primaryKey" json:"id"`
+	EventType    string             `gorm:"size:64;not null" json:"event_type"`
+	IndexerTable string             `gorm:"size:128;not null" json:"indexer_table"`
+	ReorgTable   string             `gorm:"size:128;not null" json:"reorg_table"`
+	FromBlock    int64              `gorm:"not null" json:"from_block"` // Lowest block of a retracted transaction
+	ToBlock      int64              `gorm:"not null" json:"to_block"`   // Highest block of a retracted transaction
+	TxHashes     []string           `gorm:"serializer:json;type:text;not null" json:"tx_hashes"`
+	Affected     []ReorgAffectedRow `gorm:"serializer:json;type:text;not null" json:"affected"`
+	DetectedAt   time.Time          `gorm:"not null;index" json:"detected_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// TableName returns the table name for ReorgIncident model
+func (ReorgIncident) TableName() string {
+	return "reorg_incidents"
+}
+
+// ReorgIncidentListResponse is a page of reorg incidents
+type ReorgIncidentListResponse struct {
+	Incidents  []ReorgIncident `json:"incidents"`
+	TotalCount int64           `json:"total_count"`
+}
+
+// ListReorgIncidents returns a page of incidents, most recently detected first, with the
+// count of every incident
+func ListReorgIncidents(db *gorm.DB, limit, offset int) ([]ReorgIncident, int64, error) {
+	var total int64
+	if err := db.Model(&ReorgIncident{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	incidents := []ReorgIncident{}
+	err := db.Order("detected_at DESC, id DESC").Limit(limit).Offset(offset).Find(&incidents).Error
+	return incidents, total, err
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuitabilityMethod is how an investor acknowledged a sukuk's risks
+type SuitabilityMethod string
+
+const (
+	SuitabilityMethodSignature SuitabilityMethod = "signature" // Wallet signed the acknowledgement message
+	SuitabilityMethodCheckbox  SuitabilityMethod = "checkbox"  // Ticked in the app, recorded with its consent token
+)
+
+// SuitabilityState is whether an investor's risk acknowledgement of a sukuk is current
+type SuitabilityState string
+
+const (
+	SuitabilityAcknowledged SuitabilityState = "acknowledged" // Acknowledged the current prospectus
+	SuitabilityStale        SuitabilityState = "stale"        // Acknowledged a prospectus version since replaced
+	SuitabilityMissing      SuitabilityState = "missing"      // Never acknowledged
+)
+
+// SuitabilityAcknowledgement records that an investor acknowledged the product risk of a
+// sukuk, as disclosed in a prospectus version, before purchasing. Rows are kept per version
+// as the regulatory record; acknowledging the same version again refreshes the row
+type SuitabilityAcknowledgement struct {
+	ID              uint              `gorm:"primaryKey" json:"id"`
+	UserAddress     string            `gorm:"size:42;not null;uniqueIndex:idx_suitability_acknowledgements_version" json:"user_address"`
+	SukukMetadataID uint              `gorm:"not null;uniqueIndex:idx_suitability_acknowledgements_version" json:"sukuk_metadata_id"`
+	DocumentID      *uint             `json:"document_id,omitempty"`                                                                           // Prospectus acknowledged; nil if the sukuk had none
+	DocumentVersion int               `gorm:"not null;default:0;uniqueIndex:idx_suitability_acknowledgements_version" json:"document_version"` // 0 without a prospectus
+	Method          SuitabilityMethod `gorm:"size:16;not null" json:"method"`
+	Message         string            `gorm:"type:text" json:"message,omitempty"`      // Signed message, for signature acknowledgements
+	Signature       string            `gorm:"size:132" json:"signature,omitempty"`     // personal_sign signature of Message
+	ConsentToken    string            `gorm:"size:128" json:"consent_token,omitempty"` // App's reference for a checkbox acknowledgement
+	AcknowledgedAt  time.Time         `gorm:"not null" json:"acknowledged_at"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// TableName returns the table name for SuitabilityAcknowledgement model
+func (SuitabilityAcknowledgement) TableName() string {
+	return "suitability_acknowledgements"
+}
+
+// BeforeSave hook to normalize the address
+func (a *SuitabilityAcknowledgement) BeforeSave(tx *gorm.DB) error {
+	a.UserAddress = normalizeAddress(a.UserAddress)
+	return nil
+}
+
+// SuitabilityAcknowledgementRequest acknowledges the risks of a sukuk, either with a
+// personal_sign signature of the acknowledgement message or a checkbox consent token
+type SuitabilityAcknowledgementRequest struct {
+	SukukMetadataID uint   `json:"sukuk_metadata_id" binding:"required"`
+	DocumentID      uint   `json:"document_id"`                               // Current prospectus; omit for a sukuk without one
+	Message         string `json:"message"`                                   // See services.SuitabilityMessage
+	Signature       string `json:"signature"`                                 // 0x-prefixed 65-byte r || s || v
+	ConsentToken    string `json:"consent_token" binding:"omitempty,max=128"` // Sent instead of a signature for a checkbox
+}
+
+// SuitabilityDocument is the prospectus an investor must acknowledge
+type SuitabilityDocument struct {
+	ID      uint   `json:"id"`
+	Version int    `json:"version"`
+	Title   string `json:"title"`
+	FileURL string `json:"file_url"`
+}
+
+// SuitabilityStatus is whether an investor may buy a sukuk as far as risk acknowledgement goes
+type SuitabilityStatus struct {
+	UserAddress     string           `json:"user_address"`
+	SukukMetadataID uint             `json:"sukuk_metadata_id"`
+	State           SuitabilityState `json:"state"`
+
+	// The latest acknowledgement, absent when missing
+	AcknowledgedDocumentID      *uint      `json:"acknowledged_document_id,omitempty"`
+	AcknowledgedDocumentVersion *int       `json:"acknowledged_document_version,omitempty"`
+	AcknowledgedAt              *time.Time `json:"acknowledged_at,omitempty"`
+
+	// The prospectus to acknowledge, absent when the sukuk has none
+	RequiredDocument *SuitabilityDocument `json:"required_document,omitempty"`
+}
+
+// SuitabilityStatusesResponse lists an investor's acknowledgements, one per sukuk
+type SuitabilityStatusesResponse struct {
+	UserAddress      string              `json:"user_address"`
+	Acknowledgements []SuitabilityStatus `json:"acknowledgements"`
+}
+
+// NewSuitabilityStatus evaluates the latest acknowledgement of an investor for a sukuk, nil
+// if there is none, against the sukuk's active prospectus, nil if it has none. An
+// acknowledgement of an older prospectus version is stale
+func NewSuitabilityStatus(address string, sukukID uint, latest *SuitabilityAcknowledgement, prospectus *SukukDocument) SuitabilityStatus {
+	status := SuitabilityStatus{
+		UserAddress:     normalizeAddress(address),
+		SukukMetadataID: sukukID,
+		State:           SuitabilityMissing,
+	}
+	if prospectus != nil {
+		status.RequiredDocument = &SuitabilityDocument{
+			ID:      prospectus.ID,
+			Version: prospectus.Version,
+			Title:   prospectus.Title,
+			FileURL: prospectus.FileURL,
+		}
+	}
+	if latest == nil {
+		return status
+	}
+
+	acknowledgedAt, version := latest.AcknowledgedAt, latest.DocumentVersion
+	status.AcknowledgedDocumentID = latest.DocumentID
+	status.AcknowledgedDocumentVersion = &version
+	status.AcknowledgedAt = &acknowledgedAt
+	status.State = SuitabilityAcknowledged
+	if prospectus != nil && latest.DocumentVersion < prospectus.Version {
+		status.State = SuitabilityStale
+	}
+	return status
+}
+
+// GetSuitabilityStatus returns the acknowledgement status of an investor for a sukuk
+func GetSuitabilityStatus(db *gorm.DB, address string, sukukID uint) (*SuitabilityStatus, error) {
+	var latest *SuitabilityAcknowledgement
+	var acknowledgement SuitabilityAcknowledgement
+	err := db.Where("user_address = ? AND sukuk_metadata_id = ?", normalizeAddress(address), sukukID).
+		Order("document_version DESC").
+		First(&acknowledgement).Error
+	switch {
+	case err == nil:
+		latest = &acknowledgement
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	prospectus, err := ActiveProspectus(db, sukukID)
+	if err != nil {
+		return nil, err
+	}
+	status := NewSuitabilityStatus(address, sukukID, latest, prospectus)
+	return &status, nil
+}
+
+// ListSuitabilityStatuses returns the status of every sukuk an investor has acknowledged
+func ListSuitabilityStatuses(db *gorm.DB, address string) ([]SuitabilityStatus, error) {
+	var acknowledgements []SuitabilityAcknowledgement
+	err := db.Where("user_address = ?", normalizeAddress(address)).
+		Order("sukuk_metadata_id ASC, document_version DESC").
+		Find(&acknowledgements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var sukukIDs []uint
+	latest := make(map[uint]*SuitabilityAcknowledgement)
+	for i := range acknowledgements {
+		acknowledgement := &acknowledgements[i]
+		if _, seen := latest[acknowledgement.SukukMetadataID]; !seen {
+			latest[acknowledgement.SukukMetadataID] = acknowledgement
+			sukukIDs = append(sukukIDs, acknowledgement.SukukMetadataID)
+		}
+	}
+
+	prospectuses := make(map[uint]*SukukDocument)
+	if len(sukukIDs) > 0 {
+		var documents []SukukDocument
+		err := db.Where("sukuk_id IN ? AND type = ? AND active", sukukIDs, SukukDocumentProspectus).
+			Order("version DESC").
+			Find(&documents).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range documents {
+			if _, seen := prospectuses[documents[i].SukukID]; !seen {
+				prospectuses[documents[i].SukukID] = &documents[i]
+			}
+		}
+	}
+
+	statuses := make([]SuitabilityStatus, 0, len(sukukIDs))
+	for _, sukukID := range sukukIDs {
+		statuses = append(statuses, NewSuitabilityStatus(address, sukukID, latest[sukukID], prospectuses[sukukID]))
+	}
+	return statuses, nil
+}
+
+// SaveSuitabilityAcknowledgement records an acknowledgement, replacing an earlier one of
+// the same investor, sukuk and prospectus version
+func SaveSuitabilityAcknowledgement(db *gorm.DB, acknowledgement *SuitabilityAcknowledgement) error {
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_address"}, {Name: "sukuk_metadata_id"}, {Name: "document_version"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"document_id", "method", "message", "signature", "consent_token", "acknowledged_at", "updated_at",
+		}),
+	}).Create(acknowledgement).Error
+}
+
+// ActiveProspectus returns the active prospectus of a sukuk, nil if it has none
+func ActiveProspectus(db *gorm.DB, sukukID uint) (*SukukDocument, error) {
+	prospectus, err := GetActiveSukukDocument(db, sukukID, SukukDocumentProspectus)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return prospectus, err
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSuitabilityStatus(t *testing.T) {
+	const address = "0xF57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"
+	acknowledgedAt := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	prospectusV1 := &SukukDocument{ID: 11, SukukID: 7, Type: SukukDocumentProspectus, Title: "Prospektus", Version: 1}
+	prospectusV2 := &SukukDocument{ID: 12, SukukID: 7, Type: SukukDocumentProspectus, Title: "Prospektus (amandemen)", Version: 2}
+	acknowledgedV1 := &SuitabilityAcknowledgement{SukukMetadataID: 7, DocumentID: &prospectusV1.ID, DocumentVersion: 1, AcknowledgedAt: acknowledgedAt}
+	acknowledgedNone := &SuitabilityAcknowledgement{SukukMetadataID: 7, AcknowledgedAt: acknowledgedAt}
+
+	tests := []struct {
+		name         string
+		latest       *SuitabilityAcknowledgement
+		prospectus   *SukukDocument
+		want         SuitabilityState
+		wantRequired uint
+	}{
+		{"fresh", acknowledgedV1, prospectusV1, SuitabilityAcknowledged, 11},
+		{"missing", nil, prospectusV1, SuitabilityMissing, 11},
+		{"missing without prospectus", nil, nil, SuitabilityMissing, 0},
+		{"stale after an amendment", acknowledgedV1, prospectusV2, SuitabilityStale, 12},
+		{"stale once a prospectus is published", acknowledgedNone, prospectusV1, SuitabilityStale, 11},
+		{"fresh without prospectus", acknowledgedNone, nil, SuitabilityAcknowledged, 0},
+		{"fresh after the prospectus is withdrawn", acknowledgedV1, nil, SuitabilityAcknowledged, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := NewSuitabilityStatus(address, 7, tt.latest, tt.prospectus)
+			if status.State != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, status.State)
+			}
+			if status.UserAddress != "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9" {
+				t.Errorf("Expected a normalized address, got %s", status.UserAddress)
+			}
+			if tt.wantRequired == 0 && status.RequiredDocument != nil {
+				t.Errorf("Expected no required document, got %+v", status.RequiredDocument)
+			}
+			if tt.wantRequired != 0 && (status.RequiredDocument == nil || status.RequiredDocument.ID != tt.wantRequired) {
+				t.Errorf("Expected required document %d, got %+v", tt.wantRequired, status.RequiredDocument)
+			}
+			if (tt.latest == nil) != (status.AcknowledgedAt == nil) {
+				t.Errorf("Expected the acknowledgement details only when acknowledged, got %+v", status)
+			}
+		})
+	}
+}
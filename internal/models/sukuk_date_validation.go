@@ -0,0 +1,46 @@
+package models
+
+// SukukDateConstraint names a rule between the dates of a sukuk the coupon schedule relies on
+type SukukDateConstraint string
+
+const (
+	SukukDatePurchasePeriodOrder     SukukDateConstraint = "purchase_period_order"              // periode_pembelian starts before it ends
+	SukukDateFirstCouponAfterSale    SukukDateConstraint = "first_coupon_after_purchase_period" // kupon_pertama is after periode_pembelian ends
+	SukukDateMaturityAfterCoupon     SukukDateConstraint = "maturity_after_first_coupon"        // jatuh_tempo is after kupon_pertama
+	SukukDateCouponDayMatchesCadence SukukDateConstraint = "coupon_day_matches_cadence"         // tanggal_bayar_kupon pays as often as penerimaan_kupon
+)
+
+// SukukDateSeverity says whether a date violation blocks a write
+type SukukDateSeverity string
+
+const (
+	SukukDateError   SukukDateSeverity = "error"   // The dates contradict each other
+	SukukDateWarning SukukDateSeverity = "warning" // A date is missing or unreadable, so the rule can't be checked
+)
+
+// SukukDateViolation is one broken or uncheckable date rule, e.g.
+// {"constraint":"maturity_after_first_coupon","field":"jatuh_tempo","severity":"error"}
+type SukukDateViolation struct {
+	Constraint SukukDateConstraint `json:"constraint"`
+	Field      string              `json:"field"`
+	Severity   SukukDateSeverity   `json:"severity"`
+	Message    string              `json:"message"`
+}
+
+// SukukDateValidationEntry lists the date violations of one stored sukuk
+type SukukDateValidationEntry struct {
+	ID              uint                 `json:"id"`
+	ContractAddress string               `json:"contract_address"`
+	SukukCode       string               `json:"sukuk_code"`
+	MetadataReady   bool                 `json:"metadata_ready"`
+	Violations      []SukukDateViolation `json:"violations"`
+}
+
+// SukukDateValidationReport lists the stored sukuk whose dates break or can't be checked
+// against the date rules
+type SukukDateValidationReport struct {
+	Checked int                        `json:"checked"` // Sukuk checked
+	Invalid int                        `json:"invalid"` // Sukuk with at least one error
+	Warned  int                        `json:"warned"`  // Sukuk with only warnings
+	Records []SukukDateValidationEntry `json:"records"` // Sukuk with any violation, by ID
+}
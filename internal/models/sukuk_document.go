@@ -0,0 +1,244 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SukukDocumentType is the kind of document attached to a sukuk
+type SukukDocumentType string
+
+const (
+	SukukDocumentProspectus        SukukDocumentType = "prospectus"         // Offering prospectus; amendments are new versions
+	SukukDocumentFactSheet         SukukDocumentType = "fact_sheet"         // Summary of terms for investors
+	SukukDocumentShariaCertificate SukukDocumentType = "sharia_certificate" // Fatwa or sharia compliance certificate
+)
+
+// SukukDocumentTypes returns all document types
+func SukukDocumentTypes() []SukukDocumentType {
+	return []SukukDocumentType{SukukDocumentProspectus, SukukDocumentFactSheet, SukukDocumentShariaCertificate}
+}
+
+// IsValid checks if the document type is supported
+func (t SukukDocumentType) IsValid() bool {
+	for _, documentType := range SukukDocumentTypes() {
+		if t == documentType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedExtensions returns the file extensions accepted for the type; prospectuses and
+// certificates are legal documents and must be PDFs
+func (t SukukDocumentType) AllowedExtensions() []string {
+	if t == SukukDocumentFactSheet {
+		return []string{".pdf", ".png", ".jpg", ".jpeg"}
+	}
+	return []string{".pdf"}
+}
+
+// MaxSukukDocumentSize bounds an uploaded document, matching the prospectus upload limit
+const MaxSukukDocumentSize = 50 * 1024 * 1024
+
+// ValidateSukukDocumentFile checks an upload of the given type by its name, size and
+// leading bytes, so a renamed file is not accepted as a PDF
+func ValidateSukukDocumentFile(documentType SukukDocumentType, filename string, size int64, head []byte) error {
+	if size > MaxSukukDocumentSize {
+		return fmt.Errorf("file size too large (max %dMB)", MaxSukukDocumentSize/(1024*1024))
+	}
+
+	allowed := documentType.AllowedExtensions()
+	ext := strings.ToLower(filepath.Ext(filename))
+	valid := false
+	for _, allowedExt := range allowed {
+		if ext == allowedExt {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("file type not allowed for %s. Allowed types: %v", documentType, allowed)
+	}
+
+	return validateFileContent(ext, head)
+}
+
+// validateFileContent checks that the leading bytes of a .pdf, .png, .jpg or .jpeg file
+// match its extension
+func validateFileContent(ext string, head []byte) error {
+	detected := http.DetectContentType(head)
+	var valid bool
+	switch ext {
+	case ".pdf":
+		valid = detected == "application/pdf"
+	case ".png":
+		valid = detected == "image/png"
+	default:
+		valid = detected == "image/jpeg"
+	}
+	if !valid {
+		return fmt.Errorf("file content does not match its %s extension (detected %s)", ext, detected)
+	}
+	return nil
+}
+
+// SukukDocument is a file attached to a sukuk. Uploading a document of a type already
+// attached adds a version and deactivates the previous one, which keeps its record
+type SukukDocument struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	SukukID    uint              `gorm:"not null;uniqueIndex:idx_sukuk_documents_version" json:"sukuk_id"`
+	Type       SukukDocumentType `gorm:"size:32;not null;uniqueIndex:idx_sukuk_documents_version" json:"type"`
+	Title      string            `gorm:"size:200;not null" json:"title"`
+	FileURL    string            `gorm:"size:255;not null" json:"file_url"`
+	Version    int               `gorm:"not null;uniqueIndex:idx_sukuk_documents_version" json:"version"`
+	UploadedBy string            `gorm:"size:100" json:"uploaded_by"`
+	UploadedAt time.Time         `gorm:"not null" json:"uploaded_at"`
+	Active     bool              `gorm:"not null;default:true;index" json:"active"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+
+	// Downloads through signed links; only admin listings carry it
+	DownloadCount *int64 `gorm:"not null;default:0" json:"download_count,omitempty"`
+}
+
+// TableName returns the table name for SukukDocument model
+func (SukukDocument) TableName() string {
+	return "sukuk_documents"
+}
+
+// SukukDocumentsResponse lists the active documents of a sukuk by type
+type SukukDocumentsResponse struct {
+	SukukID   uint                                  `json:"sukuk_id"`
+	Documents map[SukukDocumentType][]SukukDocument `json:"documents"`
+}
+
+// CreateSukukDocumentVersion stores doc as the next version of its type for its sukuk,
+// deactivating the versions before it. The sukuk row is locked so concurrent uploads
+// of one sukuk are numbered one after the other
+func CreateSukukDocumentVersion(db *gorm.DB, doc *SukukDocument) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var sukuk SukukMetadata
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&sukuk, "id = ?", doc.SukukID).Error
+		if err != nil {
+			return err
+		}
+
+		var latest int
+		err = tx.Model(&SukukDocument{}).
+			Where("sukuk_id = ? AND type = ?", doc.SukukID, doc.Type).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&SukukDocument{}).
+			Where("sukuk_id = ? AND type = ? AND active", doc.SukukID, doc.Type).
+			Update("active", false).Error
+		if err != nil {
+			return err
+		}
+
+		doc.Version = latest + 1
+		doc.Active = true
+		if doc.UploadedAt.IsZero() {
+			doc.UploadedAt = time.Now()
+		}
+		return tx.Create(doc).Error
+	})
+}
+
+// ListSukukDocuments returns the documents of a sukuk by type and newest version first,
+// only the active ones when activeOnly is set
+func ListSukukDocuments(db *gorm.DB, sukukID uint, activeOnly bool) ([]SukukDocument, error) {
+	query := db.Where("sukuk_id = ?", sukukID)
+	if activeOnly {
+		query = query.Where("active")
+	}
+
+	var documents []SukukDocument
+	err := query.Order("type ASC, version DESC").Find(&documents).Error
+	return documents, err
+}
+
+// GroupSukukDocuments groups documents by type, keeping their order
+func GroupSukukDocuments(documents []SukukDocument) map[SukukDocumentType][]SukukDocument {
+	grouped := make(map[SukukDocumentType][]SukukDocument)
+	for _, document := range documents {
+		grouped[document.Type] = append(grouped[document.Type], document)
+	}
+	return grouped
+}
+
+// DeactivateSukukDocument marks a document of a sukuk inactive; deactivating an
+// inactive document changes nothing. Returns gorm.ErrRecordNotFound for unknown documents
+func DeactivateSukukDocument(db *gorm.DB, sukukID, documentID uint) (*SukukDocument, error) {
+	var document SukukDocument
+	if err := db.First(&document, "id = ? AND sukuk_id = ?", documentID, sukukID).Error; err != nil {
+		return nil, err
+	}
+	if !document.Active {
+		return &document, nil
+	}
+
+	if err := db.Model(&document).Update("active", false).Error; err != nil {
+		return nil, err
+	}
+	document.Active = false
+	return &document, nil
+}
+
+// GetActiveSukukDocument returns the active document of a type for a sukuk. Returns
+// gorm.ErrRecordNotFound when the sukuk has none
+func GetActiveSukukDocument(db *gorm.DB, sukukID uint, documentType SukukDocumentType) (*SukukDocument, error) {
+	var document SukukDocument
+	err := db.Where("sukuk_id = ? AND type = ? AND active", sukukID, documentType).
+		Order("version DESC").
+		First(&document).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// SukukDocumentDownload records a signed download link handed out for a document
+type SukukDocumentDownload struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	DocumentID uint      `gorm:"not null;index" json:"document_id"`
+	SukukID    uint      `gorm:"not null" json:"sukuk_id"`
+	Address    string    `gorm:"size:42;index" json:"address,omitempty"` // Requesting wallet, when given
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for SukukDocumentDownload model
+func (SukukDocumentDownload) TableName() string {
+	return "sukuk_document_downloads"
+}
+
+// RecordSukukDocumentDownload logs a download of document and increments its counter
+func RecordSukukDocumentDownload(db *gorm.DB, document *SukukDocument, address string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		download := SukukDocumentDownload{DocumentID: document.ID, SukukID: document.SukukID, Address: strings.ToLower(address)}
+		if err := tx.Create(&download).Error; err != nil {
+			return err
+		}
+		return tx.Model(&SukukDocument{}).
+			Where("id = ?", document.ID).
+			UpdateColumn("download_count", gorm.Expr("download_count + 1")).Error
+	})
+}
+
+// SukukDocumentLinkResponse is a short-lived signed link to a document file
+type SukukDocumentLinkResponse struct {
+	DocumentID uint      `json:"document_id"`
+	Version    int       `json:"version"`
+	URL        string    `json:"url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "testing"
+
+func TestValidateSukukDocumentFile(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpeg := []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+
+	tests := []struct {
+		name         string
+		documentType SukukDocumentType
+		filename     string
+		size         int64
+		head         []byte
+		valid        bool
+	}{
+		{"prospectus pdf", SukukDocumentProspectus, "Prospectus SR022.PDF", 1024, pdf, true},
+		{"certificate pdf", SukukDocumentShariaCertificate, "fatwa.pdf", 1024, pdf, true},
+		{"fact sheet png", SukukDocumentFactSheet, "factsheet.png", 1024, png, true},
+		{"fact sheet jpeg", SukukDocumentFactSheet, "factsheet.jpeg", 1024, jpeg, true},
+		{"fact sheet pdf", SukukDocumentFactSheet, "factsheet.pdf", 1024, pdf, true},
+		{"prospectus image", SukukDocumentProspectus, "prospectus.png", 1024, png, false},
+		{"certificate image", SukukDocumentShariaCertificate, "fatwa.jpg", 1024, jpeg, false},
+		{"certificate word file", SukukDocumentShariaCertificate, "fatwa.docx", 1024, []byte("PK\x03\x04"), false},
+		{"renamed text file", SukukDocumentProspectus, "prospectus.pdf", 1024, []byte("just some text"), false},
+		{"image named pdf", SukukDocumentFactSheet, "factsheet.pdf", 1024, png, false},
+		{"oversized pdf", SukukDocumentProspectus, "prospectus.pdf", MaxSukukDocumentSize + 1, pdf, false},
+	}
+	for _, tt := range tests {
+		err := ValidateSukukDocumentFile(tt.documentType, tt.filename, tt.size, tt.head)
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestGroupSukukDocuments(t *testing.T) {
+	grouped := GroupSukukDocuments([]SukukDocument{
+		{ID: 1, Type: SukukDocumentFactSheet, Version: 1},
+		{ID: 3, Type: SukukDocumentProspectus, Version: 2},
+		{ID: 2, Type: SukukDocumentProspectus, Version: 1},
+	})
+
+	if len(grouped) != 2 || len(grouped[SukukDocumentFactSheet]) != 1 {
+		t.Fatalf("Expected two groups, got %+v", grouped)
+	}
+	if prospectus := grouped[SukukDocumentProspectus]; len(prospectus) != 2 || prospectus[0].ID != 3 || prospectus[1].ID != 2 {
+		t.Errorf("Expected prospectus versions in their listed order, got %+v", prospectus)
+	}
+}
@@ -17,22 +17,28 @@ type SukukMetadata struct {
 	OwnerAddress     string `gorm:"size:42" json:"owner_address"`
 	TransactionHash  string `gorm:"size:66" json:"transaction_hash"`
 	BlockNumber      int64  `json:"block_number"`
+	OnchainVerified      bool  `gorm:"default:false;index" json:"onchain_verified"`  // Token details were read from the contract
+	OnchainVerifiedBlock int64 `json:"onchain_verified_block,omitempty"`            // Block the contract was read at
 
 	// Basic Info
 	SukukCode      string `gorm:"size:20;not null" json:"sukuk_code"` // SR022-T5
 	SukukTitle     string `gorm:"size:100" json:"sukuk_title"`               // Sukuk Ritel
 	SukukDeskripsi string `gorm:"type:text" json:"sukuk_deskripsi"`          // Description
-	Status         string `gorm:"size:20" json:"status"`                     // Berlangsung
+	Status         SukukStatus `gorm:"size:20;default:draft" json:"status"` // draft, active, paused, matured, suspended
 	LogoURL        string `gorm:"size:255" json:"logo_url"`                  // Logo link
 
 	// Main Features
 	Tenor       string `gorm:"size:20" json:"tenor"`        // 5 Tahun
 	ImbalHasil  string `gorm:"size:20" json:"imbal_hasil"`  // 6.55% / Tahun
 
+	// Tenor and ImbalHasil parsed for filtering and sorting, set on save; nil when unparseable
+	TenorMonths   *int `gorm:"index" json:"-"` // 60
+	ImbalHasilBps *int `gorm:"index" json:"-"` // 655
+
 	// Ketentuan SR022-T5
 	PeriodePembelian     string    `gorm:"size:50" json:"periode_pembelian"`      // 16 Mei - 18 Jun 2025
 	JatuhTempo           time.Time `json:"jatuh_tempo"`                            // 10 Jun 2030
-	KuotaNasional        float64   `gorm:"type:decimal(30,2)" json:"kuota_nasional"` // Rp7,000,000,000,000
+	KuotaNasional        Decimal   `gorm:"type:numeric(78,18)" json:"kuota_nasional" swaggertype:"string"` // 7000000000000, in token units
 	PenerimaanKupon      string    `gorm:"size:20" json:"penerimaan_kupon"`       // Bulanan
 	MinimumPembelian     float64   `gorm:"type:decimal(20,2)" json:"minimum_pembelian"` // Rp1,000,000
 	TanggalBayarKupon    string    `gorm:"size:50" json:"tanggal_bayar_kupon"`    // 10 Setiap Bulan
@@ -40,9 +46,20 @@ type SukukMetadata struct {
 	KuponPertama         time.Time `json:"kupon_pertama"`                          // 11 Agustus 2025
 	TipeKupon            string    `gorm:"size:20" json:"tipe_kupon"`             // Fixed Rate
 
+	// PeriodePembelian parsed in Jakarta time, set on save; nil when unparseable. The end is
+	// exclusive, midnight after the last day
+	PurchasePeriodStart *time.Time `gorm:"index" json:"-"` // 2025-05-16 00:00 WIB
+	PurchasePeriodEnd   *time.Time `json:"-"`              // 2025-06-19 00:00 WIB
+
 	// Metadata Status
 	MetadataReady bool `gorm:"default:false" json:"metadata_ready"`
 
+	// Optimistic lock, incremented on every write
+	Version int64 `gorm:"not null;default:1" json:"version"`
+
+	// Chain values the metadata sync last wrote; fields that differ from them were edited by an admin
+	LastSyncedValues SukukSyncedValues `gorm:"serializer:json;type:text" json:"-"`
+
 	// Timestamps
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -68,23 +85,23 @@ type SukukMetadataCreateRequest struct {
 	SukukCode      string `json:"sukuk_code" binding:"required"`
 	SukukTitle     string `json:"sukuk_title"`
 	SukukDeskripsi string `json:"sukuk_deskripsi"`
-	Status         string `json:"status"`
+	Status         SukukStatus `json:"status"` // Defaults to draft
 	LogoURL        string `json:"logo_url"`
 
 	// Main Features
-	Tenor       string `json:"tenor"`
-	ImbalHasil  string `json:"imbal_hasil"`
+	Tenor       TenorInput      `json:"tenor" swaggertype:"string"`       // A label, e.g. "5 Tahun", or a TenorDetail object
+	ImbalHasil  ImbalHasilInput `json:"imbal_hasil" swaggertype:"string"` // A label, e.g. "6.55% / Tahun", or an ImbalHasilDetail object
 
 	// Ketentuan
-	PeriodePembelian     string    `json:"periode_pembelian"`
+	PeriodePembelian     PeriodePembelianInput `json:"periode_pembelian" swaggertype:"string"` // A label, e.g. "16 Mei - 18 Jun 2025", or a PeriodePembelianDetail object
 	JatuhTempo           time.Time `json:"jatuh_tempo"`
-	KuotaNasional        float64   `json:"kuota_nasional"`
-	PenerimaanKupon      string    `json:"penerimaan_kupon"`
-	MinimumPembelian     float64   `json:"minimum_pembelian"`
-	TanggalBayarKupon    string    `json:"tanggal_bayar_kupon"`
-	MaksimumPembelian    float64   `json:"maksimum_pembelian"`
-	KuponPertama         time.Time `json:"kupon_pertama"`
-	TipeKupon            string    `json:"tipe_kupon"`
+	KuotaNasional        QuantityInput `json:"kuota_nasional" swaggertype:"string"`     // Token units; a JSON number or Indonesian text, e.g. "7 triliun"
+	PenerimaanKupon      string        `json:"penerimaan_kupon"`
+	MinimumPembelian     RupiahInput   `json:"minimum_pembelian" swaggertype:"string"`  // Whole rupiah; a JSON number or Indonesian text, e.g. "Rp 1.000.000"
+	TanggalBayarKupon    string        `json:"tanggal_bayar_kupon"`
+	MaksimumPembelian    RupiahInput   `json:"maksimum_pembelian" swaggertype:"string"` // Whole rupiah, e.g. "Rp 10 miliar"
+	KuponPertama         time.Time     `json:"kupon_pertama"`
+	TipeKupon            string        `json:"tipe_kupon"`
 }
 
 // SukukMetadataUpdateRequest represents the request payload for updating sukuk metadata
@@ -93,23 +110,80 @@ type SukukMetadataUpdateRequest struct {
 	// Basic Info
 	SukukTitle     *string `json:"sukuk_title,omitempty"`
 	SukukDeskripsi *string `json:"sukuk_deskripsi,omitempty"`
-	Status         *string `json:"status,omitempty"`
+	Status         *SukukStatus `json:"status,omitempty"` // Must be an allowed transition from the current status
 	LogoURL        *string `json:"logo_url,omitempty"`
 
 	// Main Features
-	Tenor      *string `json:"tenor,omitempty"`
-	ImbalHasil *string `json:"imbal_hasil,omitempty"`
+	Tenor      *TenorInput      `json:"tenor,omitempty" swaggertype:"string"`       // A label, e.g. "5 Tahun", or a TenorDetail object
+	ImbalHasil *ImbalHasilInput `json:"imbal_hasil,omitempty" swaggertype:"string"` // A label, e.g. "6.55% / Tahun", or an ImbalHasilDetail object
 
 	// Ketentuan
-	PeriodePembelian     *string    `json:"periode_pembelian,omitempty"`
+	PeriodePembelian     *PeriodePembelianInput `json:"periode_pembelian,omitempty" swaggertype:"string"` // A label, e.g. "16 Mei - 18 Jun 2025", or a PeriodePembelianDetail object
 	JatuhTempo           *time.Time `json:"jatuh_tempo,omitempty"`
-	KuotaNasional        *float64   `json:"kuota_nasional,omitempty"`
-	PenerimaanKupon      *string    `json:"penerimaan_kupon,omitempty"`
-	MinimumPembelian     *float64   `json:"minimum_pembelian,omitempty"`
-	TanggalBayarKupon    *string    `json:"tanggal_bayar_kupon,omitempty"`
-	MaksimumPembelian    *float64   `json:"maksimum_pembelian,omitempty"`
+	KuotaNasional        *QuantityInput `json:"kuota_nasional,omitempty" swaggertype:"string"`     // Token units; a JSON number or Indonesian text, e.g. "7 triliun"
+	PenerimaanKupon      *string        `json:"penerimaan_kupon,omitempty"`
+	MinimumPembelian     *RupiahInput   `json:"minimum_pembelian,omitempty" swaggertype:"string"`  // Whole rupiah; a JSON number or Indonesian text, e.g. "Rp 1.000.000"
+	TanggalBayarKupon    *string        `json:"tanggal_bayar_kupon,omitempty"`
+	MaksimumPembelian    *RupiahInput   `json:"maksimum_pembelian,omitempty" swaggertype:"string"` // Whole rupiah, e.g. "Rp 10 miliar"
 	KuponPertama         *time.Time `json:"kupon_pertama,omitempty"`
 	TipeKupon            *string    `json:"tipe_kupon,omitempty"`
+
+	// Version last read by the client; may be sent as an If-Match header instead
+	Version *int64 `json:"version,omitempty"`
+}
+
+// Apply copies the fields present in the request onto the sukuk. A status that is not an
+// allowed transition from the current one is rejected before anything is changed
+func (r *SukukMetadataUpdateRequest) Apply(s *SukukMetadata) error {
+	if r.Status != nil {
+		if err := ValidateSukukStatusTransition(s.Status, *r.Status); err != nil {
+			return err
+		}
+		s.Status = *r.Status
+	}
+	if r.SukukTitle != nil {
+		s.SukukTitle = *r.SukukTitle
+	}
+	if r.SukukDeskripsi != nil {
+		s.SukukDeskripsi = *r.SukukDeskripsi
+	}
+	if r.LogoURL != nil {
+		s.LogoURL = *r.LogoURL
+	}
+	if r.Tenor != nil {
+		s.Tenor = string(*r.Tenor)
+	}
+	if r.ImbalHasil != nil {
+		s.ImbalHasil = string(*r.ImbalHasil)
+	}
+	if r.PeriodePembelian != nil {
+		s.PeriodePembelian = string(*r.PeriodePembelian)
+	}
+	if r.JatuhTempo != nil {
+		s.JatuhTempo = *r.JatuhTempo
+	}
+	if r.KuotaNasional != nil {
+		s.KuotaNasional = r.KuotaNasional.Decimal()
+	}
+	if r.PenerimaanKupon != nil {
+		s.PenerimaanKupon = *r.PenerimaanKupon
+	}
+	if r.MinimumPembelian != nil {
+		s.MinimumPembelian = r.MinimumPembelian.Float64()
+	}
+	if r.TanggalBayarKupon != nil {
+		s.TanggalBayarKupon = *r.TanggalBayarKupon
+	}
+	if r.MaksimumPembelian != nil {
+		s.MaksimumPembelian = r.MaksimumPembelian.Float64()
+	}
+	if r.KuponPertama != nil {
+		s.KuponPertama = *r.KuponPertama
+	}
+	if r.TipeKupon != nil {
+		s.TipeKupon = *r.TipeKupon
+	}
+	return nil
 }
 
 // SukukMetadataResponse represents the response for sukuk metadata
@@ -120,29 +194,41 @@ type SukukMetadataResponse struct {
 	OwnerAddress     string    `json:"owner_address"`
 	TransactionHash  string    `json:"transaction_hash"`
 	BlockNumber      int64     `json:"block_number"`
+	OnchainVerified  bool      `json:"onchain_verified"`
+	OnchainVerifiedBlock int64 `json:"onchain_verified_block,omitempty"`
 	SukukCode        string    `json:"sukuk_code"`
 	SukukTitle       string    `json:"sukuk_title"`
 	SukukDeskripsi   string    `json:"sukuk_deskripsi"`
-	Status           string    `json:"status"`
+	Status           SukukStatus `json:"status"`
 	LogoURL          string    `json:"logo_url"`
 	Tenor            string    `json:"tenor"`
 	ImbalHasil       string    `json:"imbal_hasil"`
 	PeriodePembelian string    `json:"periode_pembelian"`
 	JatuhTempo       time.Time `json:"jatuh_tempo"`
-	KuotaNasional    float64   `json:"kuota_nasional"`
+	KuotaNasional    Decimal   `json:"kuota_nasional" swaggertype:"string"`
+	KuotaNasionalDisplay string `json:"kuota_nasional_display"` // 7.000.000.000.000
 	PenerimaanKupon  string    `json:"penerimaan_kupon"`
 	MinimumPembelian float64   `json:"minimum_pembelian"`
+	MinimumPembelianDisplay string `json:"minimum_pembelian_display"` // Rp1.000.000
 	TanggalBayarKupon string    `json:"tanggal_bayar_kupon"`
 	MaksimumPembelian float64   `json:"maksimum_pembelian"`
+	MaksimumPembelianDisplay string `json:"maksimum_pembelian_display"` // Rp10.000.000.000
 	KuponPertama     time.Time `json:"kupon_pertama"`
 	TipeKupon        string    `json:"tipe_kupon"`
 	MetadataReady    bool      `json:"metadata_ready"`
+	Version          int64     `json:"version"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 }
 
-// ToResponse converts SukukMetadata to SukukMetadataResponse
+// ToResponse converts SukukMetadata to SukukMetadataResponse in the default locale
 func (s *SukukMetadata) ToResponse() *SukukMetadataResponse {
+	return s.ToLocalizedResponse(nil)
+}
+
+// ToLocalizedResponse converts SukukMetadata to SukukMetadataResponse, overlaying the
+// translated fields; fields without a translation keep the base record's value
+func (s *SukukMetadata) ToLocalizedResponse(t Translations) *SukukMetadataResponse {
 	return &SukukMetadataResponse{
 		ID:               s.ID,
 		ContractAddress:  s.ContractAddress,
@@ -150,23 +236,29 @@ func (s *SukukMetadata) ToResponse() *SukukMetadataResponse {
 		OwnerAddress:     s.OwnerAddress,
 		TransactionHash:  s.TransactionHash,
 		BlockNumber:      s.BlockNumber,
+		OnchainVerified:  s.OnchainVerified,
+		OnchainVerifiedBlock: s.OnchainVerifiedBlock,
 		SukukCode:        s.SukukCode,
-		SukukTitle:       s.SukukTitle,
-		SukukDeskripsi:   s.SukukDeskripsi,
+		SukukTitle:       t.translate(TranslationFieldTitle, s.SukukTitle),
+		SukukDeskripsi:   t.translate(TranslationFieldDescription, s.SukukDeskripsi),
 		Status:           s.Status,
 		LogoURL:          s.LogoURL,
-		Tenor:            s.Tenor,
-		ImbalHasil:       s.ImbalHasil,
-		PeriodePembelian: s.PeriodePembelian,
+		Tenor:            t.translate(TranslationFieldTenor, s.Tenor),
+		ImbalHasil:       t.translate(TranslationFieldImbalHasil, s.ImbalHasil),
+		PeriodePembelian: t.translate(TranslationFieldPeriodePembelian, s.PeriodePembelian),
 		JatuhTempo:       s.JatuhTempo,
 		KuotaNasional:    s.KuotaNasional,
-		PenerimaanKupon:  s.PenerimaanKupon,
+		KuotaNasionalDisplay: FormatIndonesianDecimal(s.KuotaNasional),
+		PenerimaanKupon:  t.translate(TranslationFieldPenerimaanKupon, s.PenerimaanKupon),
 		MinimumPembelian: s.MinimumPembelian,
-		TanggalBayarKupon: s.TanggalBayarKupon,
+		MinimumPembelianDisplay: FormatRupiah(s.MinimumPembelian),
+		TanggalBayarKupon: t.translate(TranslationFieldTanggalBayarKupon, s.TanggalBayarKupon),
 		MaksimumPembelian: s.MaksimumPembelian,
+		MaksimumPembelianDisplay: FormatRupiah(s.MaksimumPembelian),
 		KuponPertama:     s.KuponPertama,
-		TipeKupon:        s.TipeKupon,
+		TipeKupon:        t.translate(TranslationFieldTipeKupon, s.TipeKupon),
 		MetadataReady:    s.MetadataReady,
+		Version:          s.Version,
 		CreatedAt:        s.CreatedAt,
 		UpdatedAt:        s.UpdatedAt,
 	}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SukukSyncField is a sukuk metadata field the metadata sync derives from the creation event
+type SukukSyncField string
+
+const (
+	SukukSyncFieldTitle         SukukSyncField = "sukuk_title"
+	SukukSyncFieldCode          SukukSyncField = "sukuk_code"
+	SukukSyncFieldJatuhTempo    SukukSyncField = "jatuh_tempo"
+	SukukSyncFieldKuotaNasional SukukSyncField = "kuota_nasional"
+)
+
+// SukukSyncFields lists the synced fields in the order they are compared
+var SukukSyncFields = []SukukSyncField{
+	SukukSyncFieldTitle,
+	SukukSyncFieldCode,
+	SukukSyncFieldJatuhTempo,
+	SukukSyncFieldKuotaNasional,
+}
+
+// SukukSyncedValues holds the chain value last synced into each field, formatted by SyncedValue
+type SukukSyncedValues map[SukukSyncField]string
+
+// SyncedValue formats a synced field for comparison: times as UTC RFC 3339 and amounts as
+// normalized decimals, so values read back from the database compare equal to fresh ones
+func (m *SukukMetadata) SyncedValue(field SukukSyncField) string {
+	switch field {
+	case SukukSyncFieldTitle:
+		return m.SukukTitle
+	case SukukSyncFieldCode:
+		return m.SukukCode
+	case SukukSyncFieldJatuhTempo:
+		return m.JatuhTempo.UTC().Format(time.RFC3339Nano)
+	case SukukSyncFieldKuotaNasional:
+		rat, err := m.KuotaNasional.Rat()
+		if err != nil {
+			return m.KuotaNasional.String()
+		}
+		return string(NewDecimal(rat))
+	}
+	return ""
+}
+
+// SetSyncedValue sets a synced field from a value formatted by SyncedValue
+func (m *SukukMetadata) SetSyncedValue(field SukukSyncField, value string) error {
+	switch field {
+	case SukukSyncFieldTitle:
+		m.SukukTitle = value
+	case SukukSyncFieldCode:
+		m.SukukCode = value
+	case SukukSyncFieldJatuhTempo:
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+		m.JatuhTempo = parsed
+	case SukukSyncFieldKuotaNasional:
+		parsed, err := ParseDecimal(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", field, err)
+		}
+		m.KuotaNasional = parsed
+	default:
+		return fmt.Errorf("unknown synced field %q", field)
+	}
+	return nil
+}
+
+// SukukConflictStatus tracks whether an admin has settled a conflict
+type SukukConflictStatus string
+
+const (
+	SukukConflictOpen     SukukConflictStatus = "open"
+	SukukConflictResolved SukukConflictStatus = "resolved"
+)
+
+// SukukConflictChoice is the value an admin keeps when resolving a conflict
+type SukukConflictChoice string
+
+const (
+	SukukConflictChoiceChain  SukukConflictChoice = "chain"  // Overwrite the admin edit with the chain value
+	SukukConflictChoiceManual SukukConflictChoice = "manual" // Keep the admin edit
+)
+
+// SukukMetadataConflict records a chain value the metadata sync held back because an admin had
+// edited the field since it was last synced. A sukuk has at most one open conflict per field;
+// later chain values update it
+type SukukMetadataConflict struct {
+	ID              uint                `gorm:"primaryKey" json:"id"`
+	SukukMetadataID uint                `gorm:"not null;index" json:"sukuk_metadata_id"`
+	Field           SukukSyncField      `gorm:"size:32;not null" json:"field"`
+	ChainValue      string              `gorm:"type:text;not null" json:"chain_value"`
+	ManualValue     string              `gorm:"type:text;not null" json:"manual_value"` // Value when the conflict was last seen
+	TxHash          string              `gorm:"size:66" json:"tx_hash"`                 // Creation event the chain value came from
+	BlockNumber     int64               `json:"block_number"`
+	Status          SukukConflictStatus `gorm:"size:10;not null;default:open;index" json:"status"`
+	Resolution      SukukConflictChoice `gorm:"size:10" json:"resolution,omitempty"`
+	ResolvedAt      *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// TableName returns the table name for SukukMetadataConflict model
+func (SukukMetadataConflict) TableName() string {
+	return "sukuk_metadata_conflicts"
+}
+
+// SukukMetadataConflictListResponse is a page of sync conflicts
+type SukukMetadataConflictListResponse struct {
+	Conflicts  []SukukMetadataConflict `json:"conflicts"`
+	TotalCount int64                   `json:"total_count"`
+}
+
+// SukukConflictResolveRequest is the payload for resolving a sync conflict
+type SukukConflictResolveRequest struct {
+	Choice SukukConflictChoice `json:"choice" binding:"required,oneof=chain manual"`
+}
+
+// ListSukukMetadataConflicts returns a page of conflicts with the given status, all when
+// empty, most recent first, with the count of every matching conflict
+func ListSukukMetadataConflicts(db *gorm.DB, status SukukConflictStatus, limit, offset int) ([]SukukMetadataConflict, int64, error) {
+	filtered := func() *gorm.DB {
+		query := db.Model(&SukukMetadataConflict{})
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	conflicts := []SukukMetadataConflict{}
+	err := filtered().Order("updated_at DESC, id DESC").Limit(limit).Offset(offset).Find(&conflicts).Error
+	return conflicts, total, err
+}
@@ -12,7 +12,7 @@ type SukukMetadataWithActivities struct {
 
 // ActivityEvent represents a blockchain activity for a sukuk token
 type ActivityEvent struct {
-	Type         string    `json:"type"`          // "purchase" or "redemption_request"
+	Type         ActivityType `json:"type"`          // "purchase" or "redemption_request"
 	Address      string    `json:"address"`       // Buyer or User address
 	Amount       string    `json:"amount"`        // Token amount
 	TxHash       string    `json:"tx_hash"`       // Transaction hash
@@ -20,6 +20,7 @@ type ActivityEvent struct {
 	SukukAddress string    `json:"sukuk_address"` // Sukuk contract address
 	SukukCode    string    `json:"sukuk_code"`    // Sukuk symbol/code (e.g., "SITI")
 	SukukTitle   string    `json:"sukuk_title"`   // Sukuk name/title
+	Counterparty string    `json:"counterparty,omitempty"` // Other wallet of a transfer, when known
 }
 
 // SukukYieldDistribution represents a yield distribution for a sukuk with claim information
@@ -30,6 +31,7 @@ type SukukYieldDistribution struct {
 	Claimable            bool   `json:"claimable"`             // Whether user can claim this distribution
 	ClaimedAmount        string `json:"claimed_amount"`        // Amount user has already claimed
 	UserClaimableAmount  string `json:"user_claimable_amount"` // Amount user can claim based on holdings
+	SupplySource         SupplySource `json:"supply_source"`     // Where the supply the claimable amount is a share of came from
 }
 
 // SukukMetadataListResponse represents the response for listing sukuk metadata with activities
@@ -43,29 +45,57 @@ type SukukMetadataListResponse struct {
 	SukukCode              string              `json:"sukuk_code"`
 	SukukTitle             string              `json:"sukuk_title"`
 	SukukDeskripsi         string              `json:"sukuk_deskripsi"`
-	Status                 string              `json:"status"`
+	Status                 SukukStatus         `json:"status"`
 	LogoURL                string              `json:"logo_url"`
 	Tenor                  string              `json:"tenor"`
 	ImbalHasil             string              `json:"imbal_hasil"`
 	PeriodePembelian       string              `json:"periode_pembelian"`
 	JatuhTempo             time.Time           `json:"jatuh_tempo"`
-	KuotaNasional          float64             `json:"kuota_nasional"`
+	KuotaNasional          Decimal             `json:"kuota_nasional" swaggertype:"string"`
+	KuotaNasionalDisplay   string              `json:"kuota_nasional_display"` // 7.000.000.000.000
 	PenerimaanKupon        string              `json:"penerimaan_kupon"`
 	MinimumPembelian       float64             `json:"minimum_pembelian"`
+	MinimumPembelianDisplay string             `json:"minimum_pembelian_display"` // Rp1.000.000
 	TanggalBayarKupon      string              `json:"tanggal_bayar_kupon"`
 	MaksimumPembelian      float64             `json:"maksimum_pembelian"`
+	MaksimumPembelianDisplay string            `json:"maksimum_pembelian_display"` // Rp10.000.000.000
 	KuponPertama           time.Time           `json:"kupon_pertama"`
 	TipeKupon              string              `json:"tipe_kupon"`
 	MetadataReady          bool                `json:"metadata_ready"`
+	Version                int64               `json:"version"`
 	CreatedAt              time.Time           `json:"created_at"`
 	UpdatedAt              time.Time           `json:"updated_at"`
 	LatestActivities       []ActivityEvent     `json:"latest_activities"`
 	AvailableDistributions []SukukYieldDistribution `json:"available_distributions"`
+	Suspension             *SukukSuspension    `json:"suspension,omitempty"` // Set while the sukuk is suspended onchain
+
+	// Tenor, imbal hasil and periode pembelian parsed from their labels, served by v2 only.
+	// Each is omitted when its label doesn't parse
+	TenorDetail            *TenorDetail            `json:"tenor_detail,omitempty"`
+	ImbalHasilDetail       *ImbalHasilDetail       `json:"imbal_hasil_detail,omitempty"`
+	PeriodePembelianDetail *PeriodePembelianDetail `json:"periode_pembelian_detail,omitempty"`
+
+	// Onchain activity, omitted with ?include_stats=false. The dates stay empty for sukuk
+	// that have not seen a purchase or redemption request yet
+	InvestorCount   *int64     `json:"investor_count,omitempty"`
+	FirstPurchaseAt *time.Time `json:"first_purchase_at,omitempty"`
+	LastActivityAt  *time.Time `json:"last_activity_at,omitempty"`
+
+	// Position of the wallet given in ?address=, omitted otherwise
+	UserBalance                    *string `json:"user_balance,omitempty"`
+	UserUnclaimedDistributionCount *int    `json:"user_unclaimed_distribution_count,omitempty"`
+	UserClaimableAmount            *string `json:"user_claimable_amount,omitempty"`
 }
 
-// ToListResponse converts SukukMetadata to SukukMetadataListResponse
+// ToListResponse converts SukukMetadata to SukukMetadataListResponse in the default locale
 func (sm *SukukMetadata) ToListResponse() SukukMetadataListResponse {
-	return SukukMetadataListResponse{
+	return sm.ToLocalizedListResponse(nil)
+}
+
+// ToLocalizedListResponse converts SukukMetadata to SukukMetadataListResponse, overlaying
+// the translated fields; fields without a translation keep the base record's value
+func (sm *SukukMetadata) ToLocalizedListResponse(t Translations) SukukMetadataListResponse {
+	response := SukukMetadataListResponse{
 		ID:                     sm.ID,
 		ContractAddress:        sm.ContractAddress,
 		TokenID:                sm.TokenID,
@@ -73,25 +103,59 @@ func (sm *SukukMetadata) ToListResponse() SukukMetadataListResponse {
 		TransactionHash:        sm.TransactionHash,
 		BlockNumber:            sm.BlockNumber,
 		SukukCode:              sm.SukukCode,
-		SukukTitle:             sm.SukukTitle,
-		SukukDeskripsi:         sm.SukukDeskripsi,
+		SukukTitle:             t.translate(TranslationFieldTitle, sm.SukukTitle),
+		SukukDeskripsi:         t.translate(TranslationFieldDescription, sm.SukukDeskripsi),
 		Status:                 sm.Status,
 		LogoURL:                sm.LogoURL,
-		Tenor:                  sm.Tenor,
-		ImbalHasil:             sm.ImbalHasil,
-		PeriodePembelian:       sm.PeriodePembelian,
+		Tenor:                  t.translate(TranslationFieldTenor, sm.Tenor),
+		ImbalHasil:             t.translate(TranslationFieldImbalHasil, sm.ImbalHasil),
+		PeriodePembelian:       t.translate(TranslationFieldPeriodePembelian, sm.PeriodePembelian),
 		JatuhTempo:             sm.JatuhTempo,
 		KuotaNasional:          sm.KuotaNasional,
-		PenerimaanKupon:        sm.PenerimaanKupon,
+		KuotaNasionalDisplay:   FormatIndonesianDecimal(sm.KuotaNasional),
+		PenerimaanKupon:        t.translate(TranslationFieldPenerimaanKupon, sm.PenerimaanKupon),
 		MinimumPembelian:       sm.MinimumPembelian,
-		TanggalBayarKupon:      sm.TanggalBayarKupon,
+		MinimumPembelianDisplay: FormatRupiah(sm.MinimumPembelian),
+		TanggalBayarKupon:      t.translate(TranslationFieldTanggalBayarKupon, sm.TanggalBayarKupon),
 		MaksimumPembelian:      sm.MaksimumPembelian,
+		MaksimumPembelianDisplay: FormatRupiah(sm.MaksimumPembelian),
 		KuponPertama:           sm.KuponPertama,
-		TipeKupon:              sm.TipeKupon,
+		TipeKupon:              t.translate(TranslationFieldTipeKupon, sm.TipeKupon),
 		MetadataReady:          sm.MetadataReady,
+		Version:                sm.Version,
 		CreatedAt:              sm.CreatedAt,
 		UpdatedAt:              sm.UpdatedAt,
 		LatestActivities:       []ActivityEvent{}, // Will be populated by service
 		AvailableDistributions: []SukukYieldDistribution{}, // Will be populated by service
 	}
-}
\ No newline at end of file
+	response.TenorDetail, response.ImbalHasilDetail, response.PeriodePembelianDetail = sm.termDetails(response.Tenor, response.ImbalHasil, response.PeriodePembelian)
+	return response
+}
+
+// DropTermDetails leaves out the structured terms, for v1 responses that serve only the labels
+func (r *SukukMetadataListResponse) DropTermDetails() {
+	r.TenorDetail, r.ImbalHasilDetail, r.PeriodePembelianDetail = nil, nil, nil
+}
+// SukukTimeSeriesPoint is a single bucket of investment and redemption flow
+// Amounts are raw wei strings; *_formatted fields are scaled by the token decimals
+type SukukTimeSeriesPoint struct {
+	BucketStart                 time.Time `json:"bucket_start"`
+	Invested                    string    `json:"invested"`                      // Purchases within the bucket
+	Redeemed                    string    `json:"redeemed"`                      // Approved redemptions within the bucket
+	CumulativeInvested          string    `json:"cumulative_invested"`           // Total purchases up to the end of the bucket
+	OutstandingSupply           string    `json:"outstanding_supply"`            // Cumulative purchases minus redemptions
+	InvestedFormatted           string    `json:"invested_formatted"`
+	RedeemedFormatted           string    `json:"redeemed_formatted"`
+	CumulativeInvestedFormatted string    `json:"cumulative_invested_formatted"`
+	OutstandingSupplyFormatted  string    `json:"outstanding_supply_formatted"`
+}
+
+// SukukTimeSeriesResponse represents the supply and investment time-series for a sukuk
+type SukukTimeSeriesResponse struct {
+	SukukID         uint                   `json:"sukuk_id"`
+	ContractAddress string                 `json:"contract_address"`
+	Interval        string                 `json:"interval"`
+	From            time.Time              `json:"from"`
+	To              time.Time              `json:"to"`
+	Points          []SukukTimeSeriesPoint `json:"points"`
+}
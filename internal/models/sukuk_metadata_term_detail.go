@@ -0,0 +1,184 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidTerm is returned when a structured tenor, imbal hasil or purchase period can't be
+// turned into a label, or disagrees with the label sent with it
+var ErrInvalidTerm = errors.New("invalid sukuk term")
+
+// TenorDetail is a tenor label with its length in months
+type TenorDetail struct {
+	Months int    `json:"months" example:"60"`
+	Label  string `json:"label" example:"5 Tahun"`
+}
+
+// ImbalHasilDetail is an imbal hasil label with its rate in basis points
+type ImbalHasilDetail struct {
+	RateBps int    `json:"rate_bps" example:"655"`
+	Label   string `json:"label" example:"6.55% / Tahun"`
+}
+
+// PeriodePembelianDetail is a purchase period label with its bounds in Jakarta time. The end
+// is exclusive: midnight after the last day of the period
+type PeriodePembelianDetail struct {
+	Start time.Time `json:"start" example:"2025-05-16T00:00:00+07:00"`
+	End   time.Time `json:"end" example:"2025-06-19T00:00:00+07:00"`
+	Label string    `json:"label" example:"16 Mei - 18 Jun 2025"`
+}
+
+// purchasePeriodLabelMonths are the Indonesian month abbreviations purchase periods are labelled with
+var purchasePeriodLabelMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"}
+
+// FormatTenor labels a tenor the way the catalogue writes it, e.g. 60 as "5 Tahun" and 18 as "18 Bulan"
+func FormatTenor(months int) string {
+	if months%12 == 0 {
+		return strconv.Itoa(months/12) + " Tahun"
+	}
+	return strconv.Itoa(months) + " Bulan"
+}
+
+// FormatImbalHasil labels a rate in basis points, e.g. 655 as "6.55% / Tahun"
+func FormatImbalHasil(bps int) string {
+	return strconv.FormatFloat(float64(bps)/100, 'f', -1, 64) + "% / Tahun"
+}
+
+// FormatPurchasePeriod labels a period from its start and exclusive end, e.g.
+// "16 Mei - 18 Jun 2025", giving the start's year only when it differs from the end's
+func FormatPurchasePeriod(start, end time.Time) string {
+	start, last := start.In(jakartaLocation), end.In(jakartaLocation).AddDate(0, 0, -1)
+	from := fmt.Sprintf("%d %s", start.Day(), purchasePeriodLabelMonths[start.Month()-1])
+	if start.Year() != last.Year() {
+		from += " " + strconv.Itoa(start.Year())
+	}
+	return fmt.Sprintf("%s - %d %s %d", from, last.Day(), purchasePeriodLabelMonths[last.Month()-1], last.Year())
+}
+
+// label returns the label to store for the detail, formatting one from the months when none
+// was sent
+func (d TenorDetail) label() (string, error) {
+	if d.Label == "" {
+		if d.Months <= 0 {
+			return "", fmt.Errorf("%w: tenor needs a label or positive months", ErrInvalidTerm)
+		}
+		return FormatTenor(d.Months), nil
+	}
+	if months, ok := ParseTenorMonths(d.Label); d.Months != 0 && (!ok || months != d.Months) {
+		return "", fmt.Errorf("%w: tenor label %q is not %d months", ErrInvalidTerm, d.Label, d.Months)
+	}
+	return d.Label, nil
+}
+
+// label returns the label to store for the detail, formatting one from the rate when none
+// was sent
+func (d ImbalHasilDetail) label() (string, error) {
+	if d.Label == "" {
+		if d.RateBps <= 0 {
+			return "", fmt.Errorf("%w: imbal hasil needs a label or positive rate_bps", ErrInvalidTerm)
+		}
+		return FormatImbalHasil(d.RateBps), nil
+	}
+	if bps, ok := ParseImbalHasilBps(d.Label); d.RateBps != 0 && (!ok || bps != d.RateBps) {
+		return "", fmt.Errorf("%w: imbal hasil label %q is not %d bps", ErrInvalidTerm, d.Label, d.RateBps)
+	}
+	return d.Label, nil
+}
+
+// label returns the label to store for the detail, formatting one from the bounds when none
+// was sent. Bounds must fall on midnight in Jakarta, as parsed labels do
+func (d PeriodePembelianDetail) label() (string, error) {
+	if d.Start.IsZero() != d.End.IsZero() {
+		return "", fmt.Errorf("%w: periode pembelian needs both start and end", ErrInvalidTerm)
+	}
+	if d.Label == "" {
+		if d.Start.IsZero() {
+			return "", fmt.Errorf("%w: periode pembelian needs a label or start and end", ErrInvalidTerm)
+		}
+		if !d.End.After(d.Start) {
+			return "", fmt.Errorf("%w: periode pembelian must end after it starts", ErrInvalidTerm)
+		}
+		d.Label = FormatPurchasePeriod(d.Start, d.End)
+	}
+	start, end, ok := ParsePurchasePeriod(d.Label)
+	if !d.Start.IsZero() && (!ok || !start.Equal(d.Start) || !end.Equal(d.End)) {
+		return "", fmt.Errorf("%w: periode pembelian %q does not run from %s to %s", ErrInvalidTerm,
+			d.Label, d.Start.Format(time.RFC3339), d.End.Format(time.RFC3339))
+	}
+	return d.Label, nil
+}
+
+// TenorInput is a tenor sent either as its label or as a TenorDetail
+type TenorInput string
+
+// UnmarshalJSON accepts a label string or a TenorDetail object
+func (in *TenorInput) UnmarshalJSON(data []byte) error {
+	label, err := termJSON(data, TenorDetail.label)
+	*in = TenorInput(label)
+	return err
+}
+
+// ImbalHasilInput is an imbal hasil sent either as its label or as an ImbalHasilDetail
+type ImbalHasilInput string
+
+// UnmarshalJSON accepts a label string or an ImbalHasilDetail object
+func (in *ImbalHasilInput) UnmarshalJSON(data []byte) error {
+	label, err := termJSON(data, ImbalHasilDetail.label)
+	*in = ImbalHasilInput(label)
+	return err
+}
+
+// PeriodePembelianInput is a purchase period sent either as its label or as a PeriodePembelianDetail
+type PeriodePembelianInput string
+
+// UnmarshalJSON accepts a label string or a PeriodePembelianDetail object
+func (in *PeriodePembelianInput) UnmarshalJSON(data []byte) error {
+	label, err := termJSON(data, PeriodePembelianDetail.label)
+	*in = PeriodePembelianInput(label)
+	return err
+}
+
+// termJSON returns the label of a term sent as a JSON string, or of a detail object as
+// resolved by label; null returns an empty label
+func termJSON[T any](data []byte, label func(T) (string, error)) (string, error) {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		var detail T
+		if err := json.Unmarshal(data, &detail); err != nil {
+			return "", err
+		}
+		return label(detail)
+	}
+	var text *string
+	if err := json.Unmarshal(data, &text); err != nil || text == nil {
+		return "", err
+	}
+	return *text, nil
+}
+
+// termDetails returns the structured tenor, imbal hasil and purchase period of the sukuk
+// from its parsed columns, each labelled with the given, possibly translated, label. A
+// detail is nil when its label didn't parse
+func (s *SukukMetadata) termDetails(tenor, imbalHasil, periodePembelian string) (*TenorDetail, *ImbalHasilDetail, *PeriodePembelianDetail) {
+	var tenorDetail *TenorDetail
+	if s.TenorMonths != nil {
+		tenorDetail = &TenorDetail{Months: *s.TenorMonths, Label: tenor}
+	}
+	var imbalHasilDetail *ImbalHasilDetail
+	if s.ImbalHasilBps != nil {
+		imbalHasilDetail = &ImbalHasilDetail{RateBps: *s.ImbalHasilBps, Label: imbalHasil}
+	}
+	var periodDetail *PeriodePembelianDetail
+	if s.PurchasePeriodStart != nil && s.PurchasePeriodEnd != nil {
+		periodDetail = &PeriodePembelianDetail{
+			Start: s.PurchasePeriodStart.In(jakartaLocation),
+			End:   s.PurchasePeriodEnd.In(jakartaLocation),
+			Label: periodePembelian,
+		}
+	}
+	return tenorDetail, imbalHasilDetail, periodDetail
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTermInputsAcceptEitherForm(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"labels", `{"tenor": "5 Tahun", "imbal_hasil": "6,25% p.a.", "periode_pembelian": "16 Mei - 18 Jun 2025"}`},
+		{"details with labels", `{
+			"tenor": {"months": 60, "label": "5 Tahun"},
+			"imbal_hasil": {"rate_bps": 625, "label": "6,25% p.a."},
+			"periode_pembelian": {"start": "2025-05-16T00:00:00+07:00", "end": "2025-06-19T00:00:00+07:00", "label": "16 Mei - 18 Jun 2025"}
+		}`},
+		{"labels only", `{"tenor": {"label": "5 Tahun"}, "imbal_hasil": {"label": "6,25% p.a."}, "periode_pembelian": {"label": "16 Mei - 18 Jun 2025"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request SukukMetadataUpdateRequest
+			if err := json.Unmarshal([]byte(tt.body), &request); err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if request.Tenor == nil || *request.Tenor != "5 Tahun" || request.ImbalHasil == nil || *request.ImbalHasil != "6,25% p.a." ||
+				request.PeriodePembelian == nil || *request.PeriodePembelian != "16 Mei - 18 Jun 2025" {
+				t.Errorf("Expected the labels, got %v, %v and %v", request.Tenor, request.ImbalHasil, request.PeriodePembelian)
+			}
+		})
+	}
+
+	// Details without a label are labelled the way the catalogue writes them
+	var request SukukMetadataCreateRequest
+	err := json.Unmarshal([]byte(`{
+		"tenor": {"months": 18},
+		"imbal_hasil": {"rate_bps": 655},
+		"periode_pembelian": {"start": "2024-12-27T17:00:00Z", "end": "2025-01-11T00:00:00+07:00"}
+	}`), &request)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if request.Tenor != "18 Bulan" || request.ImbalHasil != "6.55% / Tahun" || request.PeriodePembelian != "28 Des 2024 - 10 Jan 2025" {
+		t.Errorf("Expected formatted labels, got %q, %q and %q", request.Tenor, request.ImbalHasil, request.PeriodePembelian)
+	}
+
+	for _, invalid := range []string{
+		`{"tenor": {"months": 24, "label": "5 Tahun"}}`,
+		`{"tenor": {}}`,
+		`{"imbal_hasil": {"rate_bps": 700, "label": "6.55%"}}`,
+		`{"imbal_hasil": {"rate_bps": -5}}`,
+		`{"periode_pembelian": {"start": "2025-05-16T00:00:00+07:00"}}`,
+		`{"periode_pembelian": {"start": "2025-06-19T00:00:00+07:00", "end": "2025-05-16T00:00:00+07:00"}}`,
+		`{"periode_pembelian": {"start": "2025-05-16T00:00:00+07:00", "end": "2025-06-18T00:00:00+07:00", "label": "16 Mei - 18 Jun 2025"}}`,
+		`{"periode_pembelian": {"start": "2025-05-16T09:00:00+07:00", "end": "2025-06-19T00:00:00+07:00", "label": "16 Mei - 18 Jun 2025"}}`,
+	} {
+		if err := json.Unmarshal([]byte(invalid), &SukukMetadataUpdateRequest{}); !errors.Is(err, ErrInvalidTerm) {
+			t.Errorf("Expected ErrInvalidTerm for %s, got %v", invalid, err)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"tenor": 60}`), &SukukMetadataUpdateRequest{}); err == nil {
+		t.Error("Expected a bare number rejected")
+	}
+}
+
+func TestTermDetailsRoundTrip(t *testing.T) {
+	labels := []struct{ tenor, imbalHasil, periodePembelian string }{
+		{"5 Tahun", "6.55% / Tahun", "16 Mei - 18 Jun 2025"},
+		{"18 Bulan", "6,45%", "28 Des - 10 Jan 2025"},
+		{"2 years", "Floating 6.25%", "1 June - 30 June 2025"},
+	}
+	for _, tt := range labels {
+		sukuk := SukukMetadata{Tenor: tt.tenor, ImbalHasil: tt.imbalHasil, PeriodePembelian: tt.periodePembelian}
+		if err := sukuk.BeforeSave(nil); err != nil {
+			t.Fatal(err)
+		}
+		response := sukuk.ToListResponse()
+		if response.TenorDetail == nil || response.ImbalHasilDetail == nil || response.PeriodePembelianDetail == nil {
+			t.Fatalf("Expected every detail for %+v, got %+v", tt, response)
+		}
+
+		// A detail sent back as served stores the same label
+		data, err := json.Marshal(map[string]interface{}{
+			"tenor":             response.TenorDetail,
+			"imbal_hasil":       response.ImbalHasilDetail,
+			"periode_pembelian": response.PeriodePembelianDetail,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var request SukukMetadataUpdateRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			t.Fatalf("Failed to decode %s: %v", data, err)
+		}
+		if string(*request.Tenor) != tt.tenor || string(*request.ImbalHasil) != tt.imbalHasil || string(*request.PeriodePembelian) != tt.periodePembelian {
+			t.Errorf("Expected %+v back, got %q, %q and %q", tt, *request.Tenor, *request.ImbalHasil, *request.PeriodePembelian)
+		}
+
+		// Without their labels, the formatted ones parse back to the same values
+		unlabelled := SukukMetadata{
+			Tenor:            FormatTenor(response.TenorDetail.Months),
+			ImbalHasil:       FormatImbalHasil(response.ImbalHasilDetail.RateBps),
+			PeriodePembelian: FormatPurchasePeriod(response.PeriodePembelianDetail.Start, response.PeriodePembelianDetail.End),
+		}
+		if err := unlabelled.BeforeSave(nil); err != nil {
+			t.Fatal(err)
+		}
+		if *unlabelled.TenorMonths != *sukuk.TenorMonths || *unlabelled.ImbalHasilBps != *sukuk.ImbalHasilBps ||
+			!unlabelled.PurchasePeriodStart.Equal(*sukuk.PurchasePeriodStart) || !unlabelled.PurchasePeriodEnd.Equal(*sukuk.PurchasePeriodEnd) {
+			t.Errorf("Expected %+v to parse back from %q, %q and %q", tt, unlabelled.Tenor, unlabelled.ImbalHasil, unlabelled.PeriodePembelian)
+		}
+	}
+
+	// Details are labelled with the translation; an unparseable label has none
+	sukuk := SukukMetadata{Tenor: "5 Tahun", ImbalHasil: "Mengambang", PeriodePembelian: "16 Mei - 18 Jun 2025"}
+	if err := sukuk.BeforeSave(nil); err != nil {
+		t.Fatal(err)
+	}
+	response := sukuk.ToLocalizedListResponse(Translations{TranslationFieldTenor: "5 Years"})
+	if response.TenorDetail == nil || response.TenorDetail.Label != "5 Years" || response.TenorDetail.Months != 60 {
+		t.Errorf("Expected the translated tenor label, got %+v", response.TenorDetail)
+	}
+	if response.ImbalHasilDetail != nil {
+		t.Errorf("Expected no imbal hasil detail, got %+v", response.ImbalHasilDetail)
+	}
+	if start := response.PeriodePembelianDetail.Start; start.Format(time.RFC3339) != "2025-05-16T00:00:00+07:00" {
+		t.Errorf("Expected the period served in Jakarta time, got %s", start.Format(time.RFC3339))
+	}
+
+	response.DropTermDetails()
+	if response.TenorDetail != nil || response.PeriodePembelianDetail != nil || response.Tenor != "5 Years" {
+		t.Errorf("Expected only the labels left, got %+v", response)
+	}
+}
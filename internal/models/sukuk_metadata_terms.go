@@ -0,0 +1,160 @@
+package models
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// tenorPattern matches a leading amount and its unit, e.g. "5 Tahun" or "18 bulan".
+// Migration 0019 backfills tenor_months with the same pattern
+var tenorPattern = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)\s*([a-z]*)`)
+
+// yieldPattern matches the first number of an imbal hasil label, e.g. "6.55% / Tahun"
+var yieldPattern = regexp.MustCompile(`(\d+(?:[.,]\d+)?)`)
+
+// tenorUnitMonths maps tenor units to months; a bare number is in years
+var tenorUnitMonths = map[string]float64{
+	"": 12, "t": 12, "th": 12, "thn": 12, "tahun": 12, "y": 12, "yr": 12, "yrs": 12, "year": 12, "years": 12,
+	"m": 1, "mo": 1, "bln": 1, "bulan": 1, "month": 1, "months": 1,
+}
+
+// ParseTenorMonths converts a tenor label such as "5 Tahun", "2 years" or "18 bulan" to months
+func ParseTenorMonths(tenor string) (int, bool) {
+	match := tenorPattern.FindStringSubmatch(strings.ToLower(tenor))
+	if match == nil {
+		return 0, false
+	}
+	perUnit, ok := tenorUnitMonths[match[2]]
+	if !ok {
+		return 0, false
+	}
+	amount, err := parseLocalizedNumber(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return int(math.Round(amount * perUnit)), true
+}
+
+// ParseImbalHasilBps converts an imbal hasil label such as "6.55% / Tahun" or "6,55%" to basis points
+func ParseImbalHasilBps(imbalHasil string) (int, bool) {
+	match := yieldPattern.FindString(imbalHasil)
+	if match == "" {
+		return 0, false
+	}
+	rate, err := parseLocalizedNumber(match)
+	if err != nil {
+		return 0, false
+	}
+	return int(math.Round(rate * 100)), true
+}
+
+// jakartaLocation is the timezone purchase periods are written in. It falls back to a fixed
+// UTC+7 zone (Jakarta has no DST) when tzdata is missing
+var jakartaLocation = func() *time.Location {
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return location
+}()
+
+// purchasePeriodPattern matches periods such as "16 Mei - 18 Jun 2025" or "28 Des 2024 - 10 Jan 2025".
+// Migration 0030 backfills purchase_period_start and purchase_period_end with the same pattern
+var purchasePeriodPattern = regexp.MustCompile(`^(\d{1,2})\s+([A-Za-z]+)\.?(?:\s+(\d{4}))?\s*[-–—]\s*(\d{1,2})\s+([A-Za-z]+)\.?\s+(\d{4})$`)
+
+// purchasePeriodMonths maps the first three letters of Indonesian and English month names
+var purchasePeriodMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "peb": time.February, "mar": time.March,
+	"apr": time.April, "mei": time.May, "may": time.May, "jun": time.June, "jul": time.July,
+	"agu": time.August, "agt": time.August, "aug": time.August, "sep": time.September,
+	"okt": time.October, "oct": time.October, "nov": time.November, "nop": time.November,
+	"des": time.December, "dec": time.December,
+}
+
+// ParsePurchasePeriod parses a periode_pembelian into its start and exclusive end in Jakarta time,
+// so the last day is open until midnight. A start without a year takes the end's year, or the
+// year before when its month comes later
+func ParsePurchasePeriod(period string) (time.Time, time.Time, bool) {
+	start, end, ok := parsePurchasePeriodDays(period)
+	if !ok || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end.AddDate(0, 0, 1), true
+}
+
+// PurchasePeriodReversed reports whether period reads as a date range ending before it starts,
+// which ParsePurchasePeriod rejects like any other label it can't read
+func PurchasePeriodReversed(period string) bool {
+	start, end, ok := parsePurchasePeriodDays(period)
+	return ok && end.Before(start)
+}
+
+// parsePurchasePeriodDays parses the first and last day of a periode_pembelian, in any order
+func parsePurchasePeriodDays(period string) (time.Time, time.Time, bool) {
+	match := purchasePeriodPattern.FindStringSubmatch(strings.TrimSpace(period))
+	if match == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	startMonth, ok1 := purchasePeriodMonth(match[2])
+	endMonth, ok2 := purchasePeriodMonth(match[5])
+	if !ok1 || !ok2 {
+		return time.Time{}, time.Time{}, false
+	}
+	startDay, _ := strconv.Atoi(match[1])
+	endDay, _ := strconv.Atoi(match[4])
+	endYear, _ := strconv.Atoi(match[6])
+	startYear := endYear
+	if match[3] != "" {
+		startYear, _ = strconv.Atoi(match[3])
+	} else if startMonth > endMonth {
+		startYear--
+	}
+
+	start, ok1 := purchasePeriodDate(startYear, startMonth, startDay)
+	end, ok2 := purchasePeriodDate(endYear, endMonth, endDay)
+	if !ok1 || !ok2 {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+func purchasePeriodMonth(name string) (time.Month, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	month, ok := purchasePeriodMonths[strings.ToLower(name[:3])]
+	return month, ok
+}
+
+// purchasePeriodDate rejects days time.Date would normalize into the next month
+func purchasePeriodDate(year int, month time.Month, day int) (time.Time, bool) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, jakartaLocation)
+	return date, date.Month() == month && date.Day() == day
+}
+
+// parseLocalizedNumber parses a decimal number written with either a dot or a comma
+func parseLocalizedNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// BeforeSave keeps the parsed tenor, imbal hasil and purchase period columns in line with their labels
+func (s *SukukMetadata) BeforeSave(tx *gorm.DB) error {
+	s.TenorMonths, s.ImbalHasilBps = nil, nil
+	s.PurchasePeriodStart, s.PurchasePeriodEnd = nil, nil
+	if months, ok := ParseTenorMonths(s.Tenor); ok {
+		s.TenorMonths = &months
+	}
+	if bps, ok := ParseImbalHasilBps(s.ImbalHasil); ok {
+		s.ImbalHasilBps = &bps
+	}
+	if start, end, ok := ParsePurchasePeriod(s.PeriodePembelian); ok {
+		s.PurchasePeriodStart, s.PurchasePeriodEnd = &start, &end
+	}
+	return nil
+}
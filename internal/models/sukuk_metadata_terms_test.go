@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTenorMonths(t *testing.T) {
+	tests := []struct {
+		tenor string
+		want  int
+		ok    bool
+	}{
+		{"5 Tahun", 60, true},
+		{"2 tahun", 24, true},
+		{"3 Years", 36, true},
+		{"1,5 Tahun", 18, true},
+		{"18 Bulan", 18, true},
+		{"6 months", 6, true},
+		{"24m", 24, true},
+		{"10", 120, true},
+		{" 5 Tahun (Non-tradable)", 60, true},
+		{"5 minggu", 0, false},
+		{"Lima tahun", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseTenorMonths(tt.tenor)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseTenorMonths(%q) = %d, %v; want %d, %v", tt.tenor, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseImbalHasilBps(t *testing.T) {
+	tests := []struct {
+		imbalHasil string
+		want       int
+		ok         bool
+	}{
+		{"6.55% / Tahun", 655, true},
+		{"6,45%", 645, true},
+		{"Floating 6.25%", 625, true},
+		{"7", 700, true},
+		{"6.125%", 613, true},
+		{"Mengambang", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseImbalHasilBps(tt.imbalHasil)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseImbalHasilBps(%q) = %d, %v; want %d, %v", tt.imbalHasil, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParsePurchasePeriod(t *testing.T) {
+	jakarta := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakartaLocation)
+	}
+	tests := []struct {
+		period     string
+		start, end time.Time
+		ok         bool
+	}{
+		{"16 Mei - 18 Jun 2025", jakarta(2025, time.May, 16), jakarta(2025, time.June, 19), true},
+		{"1 June - 30 June 2025", jakarta(2025, time.June, 1), jakarta(2025, time.July, 1), true},
+		{"28 Des - 10 Jan 2025", jakarta(2024, time.December, 28), jakarta(2025, time.January, 11), true},
+		{"28 Des 2024 – 10 Jan 2025", jakarta(2024, time.December, 28), jakarta(2025, time.January, 11), true},
+		{"31 Feb - 10 Mar 2025", time.Time{}, time.Time{}, false},
+		{"TBA", time.Time{}, time.Time{}, false},
+		{"", time.Time{}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		start, end, ok := ParsePurchasePeriod(tt.period)
+		if ok != tt.ok || !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("ParsePurchasePeriod(%q) = %s, %s, %v, want %s, %s, %v", tt.period, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestSukukMetadataBeforeSaveParsesTerms(t *testing.T) {
+	sukuk := SukukMetadata{Tenor: "5 Tahun", ImbalHasil: "6.55% / Tahun", PeriodePembelian: "16 Mei - 18 Jun 2025"}
+	if err := sukuk.BeforeSave(nil); err != nil {
+		t.Fatal(err)
+	}
+	if sukuk.TenorMonths == nil || *sukuk.TenorMonths != 60 || sukuk.ImbalHasilBps == nil || *sukuk.ImbalHasilBps != 655 {
+		t.Fatalf("Expected 60 months and 655 bps, got %v and %v", sukuk.TenorMonths, sukuk.ImbalHasilBps)
+	}
+	if sukuk.PurchasePeriodStart == nil || sukuk.PurchasePeriodEnd == nil || sukuk.PurchasePeriodEnd.Sub(*sukuk.PurchasePeriodStart) != 34*24*time.Hour {
+		t.Fatalf("Expected a 34 day purchase period, got %v to %v", sukuk.PurchasePeriodStart, sukuk.PurchasePeriodEnd)
+	}
+
+	// An edit to an unparseable label clears the stale value
+	sukuk.ImbalHasil = "Mengambang"
+	sukuk.PeriodePembelian = "TBA"
+	if err := sukuk.BeforeSave(nil); err != nil {
+		t.Fatal(err)
+	}
+	if sukuk.ImbalHasilBps != nil || sukuk.TenorMonths == nil {
+		t.Errorf("Expected only imbal_hasil_bps cleared, got %v and %v", sukuk.TenorMonths, sukuk.ImbalHasilBps)
+	}
+	if sukuk.PurchasePeriodStart != nil || sukuk.PurchasePeriodEnd != nil {
+		t.Errorf("Expected the purchase period cleared, got %v to %v", sukuk.PurchasePeriodStart, sukuk.PurchasePeriodEnd)
+	}
+}
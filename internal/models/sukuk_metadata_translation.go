@@ -0,0 +1,236 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Locale is a language the sukuk metadata can be served in
+type Locale string
+
+const (
+	LocaleID Locale = "id" // Indonesian; the language the base record is written in
+	LocaleEN Locale = "en" // English
+)
+
+// DefaultLocale is served when the client asks for no supported locale
+const DefaultLocale = LocaleID
+
+// Locales returns all supported locales, default first
+func Locales() []Locale {
+	return []Locale{LocaleID, LocaleEN}
+}
+
+// IsValid checks if the locale is supported
+func (l Locale) IsValid() bool {
+	for _, locale := range Locales() {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLocale normalizes a language tag such as "en-US" or "ID" to a supported locale
+func ParseLocale(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	locale := Locale(tag)
+	return locale, locale.IsValid()
+}
+
+// NegotiateLocale picks the supported locale with the highest quality from an
+// Accept-Language header, falling back to DefaultLocale
+func NegotiateLocale(acceptLanguage string) Locale {
+	best, bestQuality := DefaultLocale, 0.0
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(entry, ";")
+		locale, ok := ParseLocale(parts[0])
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = locale, quality
+		}
+	}
+	return best
+}
+
+// Translatable fields of sukuk metadata, named after their JSON keys
+const (
+	TranslationFieldTitle             = "sukuk_title"
+	TranslationFieldDescription       = "sukuk_deskripsi"
+	TranslationFieldTenor             = "tenor"
+	TranslationFieldImbalHasil        = "imbal_hasil"
+	TranslationFieldPeriodePembelian  = "periode_pembelian"
+	TranslationFieldPenerimaanKupon   = "penerimaan_kupon"
+	TranslationFieldTanggalBayarKupon = "tanggal_bayar_kupon"
+	TranslationFieldTipeKupon         = "tipe_kupon"
+)
+
+// TranslationFields returns the fields that accept translations
+func TranslationFields() []string {
+	return []string{
+		TranslationFieldTitle,
+		TranslationFieldDescription,
+		TranslationFieldTenor,
+		TranslationFieldImbalHasil,
+		TranslationFieldPeriodePembelian,
+		TranslationFieldPenerimaanKupon,
+		TranslationFieldTanggalBayarKupon,
+		TranslationFieldTipeKupon,
+	}
+}
+
+// IsTranslationField checks if field accepts translations
+func IsTranslationField(field string) bool {
+	for _, f := range TranslationFields() {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// SukukMetadataTranslation holds one translated field of a sukuk metadata record
+type SukukMetadataTranslation struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	SukukMetadataID uint      `gorm:"not null;uniqueIndex:idx_sukuk_translation_field" json:"sukuk_metadata_id"`
+	Locale          Locale    `gorm:"size:8;not null;uniqueIndex:idx_sukuk_translation_field" json:"locale"`
+	Field           string    `gorm:"size:64;not null;uniqueIndex:idx_sukuk_translation_field" json:"field"`
+	Value           string    `gorm:"type:text;not null" json:"value"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for SukukMetadataTranslation model
+func (SukukMetadataTranslation) TableName() string {
+	return "sukuk_metadata_translations"
+}
+
+// Translations maps a translatable field to its translated value for one locale
+type Translations map[string]string
+
+// SukukMetadataTranslationsRequest sets translations for one locale
+// An empty value removes the translation so the field falls back to the base record
+type SukukMetadataTranslationsRequest struct {
+	Translations map[string]string `json:"translations" binding:"required"`
+}
+
+// SukukMetadataTranslationsResponse lists the translations of a sukuk metadata record per locale
+type SukukMetadataTranslationsResponse struct {
+	SukukMetadataID uint                    `json:"sukuk_metadata_id"`
+	DefaultLocale   Locale                  `json:"default_locale"`
+	Translations    map[Locale]Translations `json:"translations"`
+}
+
+// ValidateTranslations checks that every field in the request accepts translations
+func ValidateTranslations(translations map[string]string) error {
+	var unknown []string
+	for field := range translations {
+		if !IsTranslationField(field) {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("fields cannot be translated: %s (allowed: %s)",
+			strings.Join(unknown, ", "), strings.Join(TranslationFields(), ", "))
+	}
+	return nil
+}
+
+// GetTranslations returns the translations of the given sukuk metadata in a locale, keyed by metadata ID
+func GetTranslations(db *gorm.DB, locale Locale, sukukMetadataIDs ...uint) (map[uint]Translations, error) {
+	result := make(map[uint]Translations)
+	if len(sukukMetadataIDs) == 0 || locale == DefaultLocale {
+		return result, nil
+	}
+
+	var rows []SukukMetadataTranslation
+	err := db.Where("locale = ? AND sukuk_metadata_id IN ?", locale, sukukMetadataIDs).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if result[row.SukukMetadataID] == nil {
+			result[row.SukukMetadataID] = make(Translations)
+		}
+		result[row.SukukMetadataID][row.Field] = row.Value
+	}
+	return result, nil
+}
+
+// GetAllTranslations returns every translation of a sukuk metadata record, keyed by locale
+func GetAllTranslations(db *gorm.DB, sukukMetadataID uint) (map[Locale]Translations, error) {
+	var rows []SukukMetadataTranslation
+	if err := db.Where("sukuk_metadata_id = ?", sukukMetadataID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[Locale]Translations)
+	for _, row := range rows {
+		if result[row.Locale] == nil {
+			result[row.Locale] = make(Translations)
+		}
+		result[row.Locale][row.Field] = row.Value
+	}
+	return result, nil
+}
+
+// SetTranslations upserts the translations of a sukuk metadata record in a locale
+// Fields with an empty value are deleted; fields not in the map are left untouched
+func SetTranslations(db *gorm.DB, sukukMetadataID uint, locale Locale, translations map[string]string) error {
+	for field, value := range translations {
+		if strings.TrimSpace(value) == "" {
+			err := db.Where("sukuk_metadata_id = ? AND locale = ? AND field = ?", sukukMetadataID, locale, field).
+				Delete(&SukukMetadataTranslation{}).Error
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		row := SukukMetadataTranslation{
+			SukukMetadataID: sukukMetadataID,
+			Locale:          locale,
+			Field:           field,
+			Value:           value,
+		}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "sukuk_metadata_id"}, {Name: "locale"}, {Name: "field"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&row).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translate returns the translated value of field, or base when there is none
+func (t Translations) translate(field, base string) string {
+	if value, ok := t[field]; ok && value != "" {
+		return value
+	}
+	return base
+}
@@ -0,0 +1,84 @@
+package models
+
+import "testing"
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", DefaultLocale},
+		{"en", LocaleEN},
+		{"en-US,en;q=0.9", LocaleEN},
+		{"ID", LocaleID},
+		{"fr-FR,fr;q=0.9", DefaultLocale},
+		{"fr;q=1.0, en;q=0.5", LocaleEN},
+		{"id;q=0.4, en;q=0.8", LocaleEN},
+		{"en;q=0.3, id_ID;q=0.6", LocaleID},
+		{"en;q=0", DefaultLocale},
+	}
+	for _, tt := range tests {
+		if got := NegotiateLocale(tt.header); got != tt.want {
+			t.Errorf("NegotiateLocale(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	if locale, ok := ParseLocale(" EN-gb "); !ok || locale != LocaleEN {
+		t.Errorf("Expected en, got %s (%v)", locale, ok)
+	}
+	if _, ok := ParseLocale("jp"); ok {
+		t.Error("Expected jp to be unsupported")
+	}
+}
+
+func TestLocalizedResponseFallsBackToBaseRecord(t *testing.T) {
+	sukuk := SukukMetadata{
+		SukukCode:      "SR1",
+		SukukTitle:     "Sukuk Ritel Seri 1",
+		SukukDeskripsi: "Sukuk negara untuk investor ritel",
+		Tenor:          "3 Tahun",
+		TipeKupon:      "Tetap",
+	}
+
+	// A partial translation overlays only the translated fields
+	translations := Translations{
+		TranslationFieldTitle:     "Retail Sukuk Series 1",
+		TranslationFieldTenor:     "3 Years",
+		TranslationFieldTipeKupon: "",
+	}
+
+	list := sukuk.ToLocalizedListResponse(translations)
+	if list.SukukTitle != "Retail Sukuk Series 1" || list.Tenor != "3 Years" {
+		t.Errorf("Expected translated title and tenor, got %q and %q", list.SukukTitle, list.Tenor)
+	}
+	if list.SukukDeskripsi != sukuk.SukukDeskripsi {
+		t.Errorf("Expected untranslated description to fall back, got %q", list.SukukDeskripsi)
+	}
+	if list.TipeKupon != "Tetap" {
+		t.Errorf("Expected an empty translation to fall back, got %q", list.TipeKupon)
+	}
+	if list.SukukCode != "SR1" {
+		t.Errorf("Expected non-translatable fields to be kept, got %q", list.SukukCode)
+	}
+
+	detail := sukuk.ToLocalizedResponse(translations)
+	if detail.SukukTitle != list.SukukTitle || detail.SukukDeskripsi != list.SukukDeskripsi {
+		t.Errorf("Expected detail and list responses to agree, got %q/%q", detail.SukukTitle, detail.SukukDeskripsi)
+	}
+
+	// Without translations the default responses are unchanged
+	if got := sukuk.ToListResponse(); got.SukukTitle != sukuk.SukukTitle || got.Tenor != sukuk.Tenor {
+		t.Errorf("Expected the default locale to serve the base record, got %+v", got)
+	}
+}
+
+func TestValidateTranslations(t *testing.T) {
+	if err := ValidateTranslations(map[string]string{TranslationFieldTitle: "Title", TranslationFieldImbalHasil: "6.25% p.a."}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateTranslations(map[string]string{"sukuk_code": "X", TranslationFieldTitle: "Title"}); err == nil {
+		t.Error("Expected sukuk_code to be rejected")
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// SukukReadinessCheck names a group of checks a sukuk must pass before it is marked ready
+type SukukReadinessCheck string
+
+const (
+	SukukReadinessRequired SukukReadinessCheck = "required_fields" // Fields the public card shows are filled in
+	SukukReadinessDates    SukukReadinessCheck = "dates"           // Purchase period, first coupon, maturity and coupon day are consistent
+	SukukReadinessLogo     SukukReadinessCheck = "logo"            // logo_url points at an existing image
+)
+
+// SukukReadinessFailure is one failing check, e.g. {"check":"required_fields","field":"tenor"}
+type SukukReadinessFailure struct {
+	Check      SukukReadinessCheck `json:"check"`
+	Constraint SukukDateConstraint `json:"constraint,omitempty"` // Date rule broken, for dates failures
+	Field      string              `json:"field"`
+	Message    string              `json:"message"`
+}
+
+// SukukReadinessReport lists the checks a sukuk fails; it is ready when there are none
+type SukukReadinessReport struct {
+	Ready    bool                    `json:"ready"`
+	Failures []SukukReadinessFailure `json:"failures"`
+}
+
+// SukukMetadataPreview is what an update would publish, without it being saved
+type SukukMetadataPreview struct {
+	Public    SukukMetadataListResponse `json:"public"` // The item the public list and detail would serve
+	Readiness SukukReadinessReport      `json:"readiness"`
+}
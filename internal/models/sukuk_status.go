@@ -0,0 +1,79 @@
+package models
+
+import "fmt"
+
+// SukukStatus represents the lifecycle state of a sukuk
+type SukukStatus string
+
+const (
+	SukukStatusDraft     SukukStatus = "draft"
+	SukukStatusActive    SukukStatus = "active"
+	SukukStatusPaused    SukukStatus = "paused"
+	SukukStatusMatured   SukukStatus = "matured"
+	SukukStatusSuspended SukukStatus = "suspended"
+)
+
+// sukukStatusTransitions lists the states an admin may move each state to
+// Suspension is entered and left only by the onchain emergency events, which the metadata sync
+// applies directly, so no manual transition reaches or leaves it
+var sukukStatusTransitions = map[SukukStatus][]SukukStatus{
+	SukukStatusDraft:     {SukukStatusActive},
+	SukukStatusActive:    {SukukStatusPaused, SukukStatusMatured},
+	SukukStatusPaused:    {SukukStatusActive},
+	SukukStatusMatured:   {},
+	SukukStatusSuspended: {},
+}
+
+// SukukStatuses returns every lifecycle state in order
+func SukukStatuses() []SukukStatus {
+	return []SukukStatus{SukukStatusDraft, SukukStatusActive, SukukStatusPaused, SukukStatusMatured, SukukStatusSuspended}
+}
+
+// ManualSukukStatuses returns the lifecycle states an admin may set, every state but suspended
+func ManualSukukStatuses() []SukukStatus {
+	return []SukukStatus{SukukStatusDraft, SukukStatusActive, SukukStatusPaused, SukukStatusMatured}
+}
+
+// IsEventDriven reports whether only onchain events move a sukuk into and out of s
+func (s SukukStatus) IsEventDriven() bool {
+	return s == SukukStatusSuspended
+}
+
+// IsValid checks if the status is a known sukuk status
+func (s SukukStatus) IsValid() bool {
+	_, ok := sukukStatusTransitions[s]
+	return ok
+}
+
+// NextStates returns the states the sukuk may move to from s
+func (s SukukStatus) NextStates() []SukukStatus {
+	next := sukukStatusTransitions[s]
+	return append(make([]SukukStatus, 0, len(next)), next...)
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed; staying put always is
+func (s SukukStatus) CanTransitionTo(next SukukStatus) bool {
+	if s == next {
+		return next.IsValid()
+	}
+	for _, allowed := range sukukStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSukukStatusTransition checks whether a sukuk may move between lifecycle states
+func ValidateSukukStatusTransition(from, to SukukStatus) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid sukuk status: %s", to)
+	}
+	if from != to && (from.IsEventDriven() || to.IsEventDriven()) {
+		return fmt.Errorf("cannot change sukuk status from %s to %s: suspension follows the onchain emergency events", from, to)
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("cannot change sukuk status from %s to %s", from, to)
+	}
+	return nil
+}
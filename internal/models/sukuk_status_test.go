@@ -0,0 +1,64 @@
+package models
+
+import "testing"
+
+func TestValidateSukukStatusTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    SukukStatus
+		to      SukukStatus
+		wantErr bool
+	}{
+		{"draft to active", SukukStatusDraft, SukukStatusActive, false},
+		{"active to paused", SukukStatusActive, SukukStatusPaused, false},
+		{"active to matured", SukukStatusActive, SukukStatusMatured, false},
+		{"paused to active", SukukStatusPaused, SukukStatusActive, false},
+		{"unchanged status", SukukStatusPaused, SukukStatusPaused, false},
+		{"unchanged suspension", SukukStatusSuspended, SukukStatusSuspended, false},
+
+		{"draft to paused", SukukStatusDraft, SukukStatusPaused, true},
+		{"draft to matured", SukukStatusDraft, SukukStatusMatured, true},
+		{"active to draft", SukukStatusActive, SukukStatusDraft, true},
+		{"paused to matured", SukukStatusPaused, SukukStatusMatured, true},
+		{"paused to draft", SukukStatusPaused, SukukStatusDraft, true},
+		{"matured to active", SukukStatusMatured, SukukStatusActive, true},
+		{"matured to paused", SukukStatusMatured, SukukStatusPaused, true},
+		// Suspension follows the onchain events only
+		{"active to suspended", SukukStatusActive, SukukStatusSuspended, true},
+		{"matured to suspended", SukukStatusMatured, SukukStatusSuspended, true},
+		{"suspended to active", SukukStatusSuspended, SukukStatusActive, true},
+		{"suspended to paused", SukukStatusSuspended, SukukStatusPaused, true},
+		{"suspended to draft", SukukStatusSuspended, SukukStatusDraft, true},
+		{"unknown target", SukukStatusActive, SukukStatus("berlangsung"), true},
+		{"unknown source", SukukStatus("Active"), SukukStatusPaused, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSukukStatusTransition(tt.from, tt.to)
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected error for %s -> %s", tt.from, tt.to)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no error for %s -> %s, got %v", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestSukukStatusNextStates(t *testing.T) {
+	next := SukukStatusActive.NextStates()
+	if len(next) != 2 {
+		t.Fatalf("Expected 2 next states from active, got %v", next)
+	}
+
+	// Callers may modify the returned slice without affecting the table
+	next[0] = SukukStatusDraft
+	if SukukStatusActive.NextStates()[0] != SukukStatusPaused {
+		t.Error("Expected NextStates to return a copy")
+	}
+
+	if next := SukukStatusSuspended.NextStates(); len(next) != 0 {
+		t.Errorf("Expected no manual way out of suspension, got %v", next)
+	}
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SukukSuspension records an onchain emergency suspension and, once seen, its resume
+type SukukSuspension struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	SukukAddress  string      `gorm:"size:42;not null;index" json:"sukuk_address"`
+	Reason        string      `gorm:"type:text" json:"reason"`
+	EventID       string      `gorm:"size:255;uniqueIndex;not null" json:"event_id"` // Indexer row id of the suspension event
+	BlockNumber   int64       `json:"block_number"`
+	TxHash        string      `gorm:"size:66" json:"tx_hash"`
+	SuspendedAt   time.Time   `json:"suspended_at"`
+	PriorStatus   SukukStatus `gorm:"size:20" json:"prior_status,omitempty"` // Status restored on resume; empty when the sukuk wasn't synced yet
+	ResumeEventID string      `gorm:"size:255;index" json:"resume_event_id,omitempty"`
+	ResumeTxHash  string      `gorm:"size:66" json:"resume_tx_hash,omitempty"`
+	ResumedAt     *time.Time  `json:"resumed_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// TableName returns the table name for SukukSuspension model
+func (SukukSuspension) TableName() string {
+	return "sukuk_suspensions"
+}
+
+// BeforeSave hook to normalize the sukuk address
+func (ss *SukukSuspension) BeforeSave(tx *gorm.DB) error {
+	ss.SukukAddress = normalizeAddress(ss.SukukAddress)
+	return nil
+}
+
+// GetOpenSuspensions returns the latest unresolved suspension for each of the given sukuk, keyed by lowercase address
+func GetOpenSuspensions(db *gorm.DB, sukukAddresses []string) (map[string]SukukSuspension, error) {
+	suspensions := make(map[string]SukukSuspension)
+	if len(sukukAddresses) == 0 {
+		return suspensions, nil
+	}
+
+	addresses := make([]string, len(sukukAddresses))
+	for i, address := range sukukAddresses {
+		addresses[i] = normalizeAddress(address)
+	}
+
+	var rows []SukukSuspension
+	err := db.Where("sukuk_address IN ? AND resumed_at IS NULL", addresses).
+		Order("suspended_at ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Later rows overwrite earlier ones so each address keeps its most recent suspension
+	for _, row := range rows {
+		suspensions[row.SukukAddress] = row
+	}
+	return suspensions, nil
+}
@@ -0,0 +1,10 @@
+package models
+
+// SupplySource is where the total supply a share or entitlement was computed against was read from
+type SupplySource string
+
+const (
+	SupplySourceSnapshot   SupplySource = "snapshot"           // total_supply of the latest SnapshotTaken event
+	SupplySourceRedemption SupplySource = "redemption_request" // total_supply recorded by the latest RedemptionRequested event
+	SupplySourceNone       SupplySource = "none"               // No event recorded a supply
+)
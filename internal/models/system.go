@@ -188,4 +188,8 @@ func GetAllSystemStates(db *gorm.DB) ([]SystemState, error) {
 // normalizeAddress converts an Ethereum address to lowercase
 func normalizeAddress(address string) string {
 	return strings.ToLower(address)
-}
\ No newline at end of file
+}
+// SettingsUpdateRequest sets runtime settings by key; an empty value removes one
+type SettingsUpdateRequest struct {
+	Settings map[string]string `json:"settings" binding:"required"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TaxReport is a yearly statement of the yield an address claimed, for tax filing
+// Amounts carry both the raw wei value and the value scaled by the payment token decimals
+type TaxReport struct {
+	Address     string           `json:"address"`
+	Year        int              `json:"year"`
+	Timezone    string           `json:"timezone"`     // Timezone of the calendar year boundaries
+	PeriodStart time.Time        `json:"period_start"` // Inclusive
+	PeriodEnd   time.Time        `json:"period_end"`   // Exclusive
+	ClaimCount  int              `json:"claim_count"`
+	Sukuk       []TaxReportSukuk `json:"sukuk"`
+	Totals      []TaxReportTotal `json:"totals"` // Grand total per payment token
+}
+
+// TaxReportSukuk totals the yield claimed from one sukuk in one payment token
+type TaxReportSukuk struct {
+	SukukAddress  string           `json:"sukuk_address"`
+	SukukCode     string           `json:"sukuk_code"`
+	SukukTitle    string           `json:"sukuk_title"`
+	IssuerAddress string           `json:"issuer_address"` // Owner of the sukuk contract
+	PaymentToken  string           `json:"payment_token"`
+	ClaimCount    int              `json:"claim_count"`
+	Total         FormattedAmount  `json:"total"`
+	Claims        []TaxReportClaim `json:"claims"`
+}
+
+// TaxReportClaim is a single yield claim within the report
+type TaxReportClaim struct {
+	DistributionID int64           `json:"distribution_id"`
+	TxHash         string          `json:"tx_hash"`
+	ClaimedAt      time.Time       `json:"claimed_at"` // In the report timezone
+	Amount         FormattedAmount `json:"amount"`
+}
+
+// TaxReportTotal is the grand total claimed in one payment token
+type TaxReportTotal struct {
+	PaymentToken string          `json:"payment_token"`
+	ClaimCount   int             `json:"claim_count"`
+	Total        FormattedAmount `json:"total"`
+}
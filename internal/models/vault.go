@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// VaultTokenBalance is the yield vault position of a sukuk in one payment token
+type VaultTokenBalance struct {
+	PaymentToken string `json:"payment_token"`
+	Deposited    string `json:"deposited"`   // Raw amount deposited into the yield vault
+	Distributed  string `json:"distributed"` // Raw amount paid out by yield distributions
+	Balance      string `json:"balance"`     // Deposited minus distributed, negative when distributions exceed the recorded deposits
+}
+
+// VaultBalanceResponse is the yield funding of a sukuk and its coverage of the next coupon
+type VaultBalanceResponse struct {
+	SukukMetadataID uint                `json:"sukuk_metadata_id"`
+	ContractAddress string              `json:"contract_address"`
+	VaultAddress    string              `json:"vault_address,omitempty"` // From the latest vault_update event
+	Balances        []VaultTokenBalance `json:"balances"`                // One per payment token, by address
+	NextCouponDate  *time.Time          `json:"next_coupon_date,omitempty"`
+	// The next coupon is estimated as the latest distribution, in its payment token
+	NextCouponPaymentToken string `json:"next_coupon_payment_token,omitempty"`
+	NextCouponAmount       string `json:"next_coupon_amount,omitempty"`
+	FundingCoverage        string `json:"funding_coverage,omitempty"` // Balance as a percentage of next_coupon_amount, e.g. "150.00"
+	LowFunding             bool   `json:"low_funding"`                // Coverage below 100%
+}
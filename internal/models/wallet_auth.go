@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WalletAuthNonce is a sign-in nonce issued to a wallet. The first sign-in presenting it
+// consumes it, and it expires unused after WALLET_AUTH_NONCE_TTL
+type WalletAuthNonce struct {
+	Nonce     string     `gorm:"primaryKey;size:32" json:"nonce"`
+	Address   string     `gorm:"size:42;not null;index" json:"address"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for WalletAuthNonce model
+func (WalletAuthNonce) TableName() string {
+	return "wallet_auth_nonces"
+}
+
+// BeforeCreate hook to normalize the address
+func (n *WalletAuthNonce) BeforeCreate(tx *gorm.DB) error {
+	n.Address = normalizeAddress(n.Address)
+	return nil
+}
+
+// WalletAuthChallenge is a nonce issued to a wallet with the sign-in message to sign with it
+type WalletAuthChallenge struct {
+	Address   string    `json:"address"`
+	Nonce     string    `json:"nonce"`
+	Message   string    `json:"message"` // Sign exactly this with personal_sign
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// WalletAuthVerifyRequest carries a signed sign-in message
+type WalletAuthVerifyRequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"` // 0x-prefixed 65-byte r || s || v
+}
+
+// WalletAuthSession is a wallet session token, sent as "Authorization: Bearer <token>" to
+// endpoints that act for the wallet
+type WalletAuthSession struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
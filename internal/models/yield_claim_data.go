@@ -0,0 +1,21 @@
+package models
+
+// YieldClaimAmount is the total a claim pays out in one payment token
+type YieldClaimAmount struct {
+	PaymentToken string `json:"payment_token"`
+	Amount       string `json:"amount"` // Raw payment token amount
+}
+
+// YieldClaimDataResponse is a ready-to-send claimYield transaction covering every distribution
+// a user can still claim from one sukuk
+type YieldClaimDataResponse struct {
+	Address         string                   `json:"address"`
+	To              string                   `json:"to"`       // Sukuk contract the transaction is sent to
+	Data            string                   `json:"data"`     // ABI-encoded calldata: selector followed by the distribution IDs
+	Value           string                   `json:"value"`    // Native value to send, always "0"
+	Function        string                   `json:"function"` // e.g. claimYield(uint256[])
+	DistributionIDs []int64                  `json:"distribution_ids"`
+	Distributions   []SukukYieldDistribution `json:"distributions"` // The distributions being claimed
+	Amounts         []YieldClaimAmount       `json:"amounts"`       // What the claim pays out per payment token
+	Summary         string                   `json:"summary"`       // Human-readable description of the claim
+}
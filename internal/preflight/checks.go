@@ -0,0 +1,265 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/utils"
+)
+
+// CheckConfig checks that the required settings are present and well-formed. config.Load
+// already rejects the settings the server cannot start without; these are the ones it
+// accepts but that fail later
+func CheckConfig(cfg *config.Config) []Result {
+	const check = "config"
+	var problems, warnings []string
+
+	db := cfg.Database
+	if db.Host == "" || db.User == "" || db.DBName == "" {
+		problems = append(problems, "DB_HOST, DB_USER and DB_NAME are required")
+	}
+	if db.Port <= 0 || db.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("invalid DB_PORT: %d", db.Port))
+	}
+	if cfg.App.UploadDir == "" {
+		problems = append(problems, "APP_UPLOAD_DIR is required")
+	}
+	if cfg.API.APIKey == "" && cfg.App.Environment != "development" {
+		problems = append(problems, "API_API_KEY is required")
+	}
+	if endpoint := cfg.Blockchain.RPCEndpoint; endpoint != "" && !hasScheme(endpoint, "http", "https") {
+		problems = append(problems, fmt.Sprintf("BLOCKCHAIN_RPC_ENDPOINT is not an http(s) URL: %q", endpoint))
+	}
+	if endpoint := cfg.Blockchain.WebSocketURL; endpoint != "" && !hasScheme(endpoint, "ws", "wss") {
+		problems = append(problems, fmt.Sprintf("BLOCKCHAIN_WEBSOCKET_URL is not a ws(s) URL: %q", endpoint))
+	}
+	if address := cfg.Blockchain.ContractAddress; address != "" && !utils.IsValidEthereumAddress(address) {
+		problems = append(problems, fmt.Sprintf("BLOCKCHAIN_CONTRACT_ADDRESS is not an address: %q", address))
+	}
+	if cfg.Cache.Driver == "redis" && !hasScheme(cfg.Cache.RedisURL, "redis", "rediss") {
+		problems = append(problems, "CACHE_REDIS_URL must be a redis:// URL when CACHE_DRIVER is redis")
+	}
+	if cfg.Sync.OnchainBackfill && cfg.Blockchain.RPCEndpoint == "" {
+		problems = append(problems, "SYNC_ONCHAIN_BACKFILL needs BLOCKCHAIN_RPC_ENDPOINT")
+	}
+	if cfg.Email.Enabled && (cfg.Email.Host == "" || cfg.Email.From == "") {
+		problems = append(problems, "EMAIL_HOST and EMAIL_FROM are required when EMAIL_ENABLED is set")
+	}
+
+	if cfg.API.APIKey == "" && cfg.App.Environment == "development" {
+		warnings = append(warnings, "API_API_KEY is not set, admin endpoints are unreachable")
+	}
+	if cfg.API.WebhookSecret == "" {
+		warnings = append(warnings, "API_WEBHOOK_SECRET is not set, order payment callbacks are rejected")
+	}
+	if cfg.Email.UnsubscribeSecret == "" {
+		warnings = append(warnings, "EMAIL_UNSUBSCRIBE_SECRET is not set, unsubscribe links are disabled")
+	}
+	if cfg.Uploads.LinkSecret == "" {
+		warnings = append(warnings, "UPLOAD_LINK_SECRET is not set, prospectus downloads are disabled")
+	}
+
+	results := make([]Result, 0, len(problems)+len(warnings)+1)
+	for _, problem := range problems {
+		results = append(results, fail(check, "%s", problem))
+	}
+	for _, warning := range warnings {
+		results = append(results, warn(check, "%s", warning))
+	}
+	if len(problems) == 0 {
+		results = append(results, pass(check, "required settings present (%s environment)", cfg.App.Environment))
+	}
+	return results
+}
+
+// hasScheme reports whether raw parses as a URL with a host and one of the schemes
+func hasScheme(raw string, schemes ...string) bool {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
+
+// Pinger checks a database connection, implemented by *sql.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// CheckDatabase checks that the main database answers
+func CheckDatabase(ctx context.Context, db Pinger) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return fail("database", "unreachable: %v", err)
+	}
+	return pass("database", "reachable")
+}
+
+// SchemaVersioner reports the applied and expected schema versions, implemented by
+// database.Migrator
+type SchemaVersioner interface {
+	Version() (int, error)
+	LatestVersion() int
+}
+
+// CheckMigrations checks that the schema is at the version this build expects. A schema
+// ahead of the build, as after a rollback of the binary, only warns
+func CheckMigrations(schema SchemaVersioner) Result {
+	const check = "migrations"
+	current, err := schema.Version()
+	if err != nil {
+		return fail(check, "failed to read schema version: %v", err)
+	}
+
+	expected := schema.LatestVersion()
+	switch {
+	case current < expected:
+		return fail(check, "schema at version %d, expected %d; run `go run ./cmd/migrate up`", current, expected)
+	case current > expected:
+		return warn(check, "schema at version %d is newer than this build (%d)", current, expected)
+	}
+	return pass(check, "schema at version %d", current)
+}
+
+// RequiredEventTypes are the indexer events the core endpoints read; the other events of
+// services.EventTableMapping are optional
+var RequiredEventTypes = []string{"sukuk_creation", "sukuk_purchase", "redemption_request"}
+
+// CheckIndexerTables checks that a table is discoverable for every indexer event type,
+// failing for missing required events and warning for missing optional ones. available
+// returns the table suffixes that have tables
+func CheckIndexerTables(available func() ([]string, error)) []Result {
+	const check = "indexer_tables"
+	suffixes, err := available()
+	if err != nil {
+		return []Result{fail(check, "failed to discover tables: %v", err)}
+	}
+
+	found := make(map[string]bool, len(suffixes))
+	for _, suffix := range suffixes {
+		found[suffix] = true
+	}
+	required := make(map[string]bool, len(RequiredEventTypes))
+	for _, eventType := range RequiredEventTypes {
+		required[eventType] = true
+	}
+
+	var missingRequired, missingOptional []string
+	for eventType, suffix := range services.EventTableMapping {
+		if found[suffix] {
+			continue
+		}
+		if required[eventType] {
+			missingRequired = append(missingRequired, eventType)
+		} else {
+			missingOptional = append(missingOptional, eventType)
+		}
+	}
+	sort.Strings(missingRequired)
+	sort.Strings(missingOptional)
+
+	var results []Result
+	if len(missingRequired) > 0 {
+		results = append(results, fail(check, "no table for required events: %s", strings.Join(missingRequired, ", ")))
+	}
+	if len(missingOptional) > 0 {
+		results = append(results, warn(check, "no table for optional events: %s", strings.Join(missingOptional, ", ")))
+	}
+	if len(results) == 0 {
+		results = append(results, pass(check, "tables found for all %d event types", len(services.EventTableMapping)))
+	}
+	return results
+}
+
+// CheckUploadDir checks that files can be written to the upload directory, creating it if
+// needed. A read-only server writes no uploads, so failures only warn there
+func CheckUploadDir(dir string, readOnly bool) Result {
+	const check = "upload_storage"
+	failed := fail
+	if readOnly {
+		failed = warn
+	}
+
+	if err := utils.EnsureUploadDir(dir); err != nil {
+		return failed(check, "cannot create %s: %v", dir, err)
+	}
+	probe, err := os.CreateTemp(dir, ".preflight-*")
+	if err != nil {
+		return failed(check, "%s is not writable: %v", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return pass(check, "%s is writable", dir)
+}
+
+// CheckRPC checks that the RPC endpoint answers eth_chainId with the configured chain.
+// Only the onchain backfill depends on it at runtime, so it fails only when required
+func CheckRPC(ctx context.Context, client *http.Client, endpoint string, chainID int64, required bool) Result {
+	const check = "rpc"
+	if endpoint == "" {
+		if required {
+			return fail(check, "no endpoint configured")
+		}
+		return pass(check, "not configured")
+	}
+	failed := warn
+	if required {
+		failed = fail
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	payload := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return failed(check, "invalid endpoint: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return failed(check, "unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return failed(check, "unexpected status %d", resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return failed(check, "invalid RPC response: %v", err)
+	}
+	if rpcResp.Error != nil {
+		return failed(check, "RPC error: %s", rpcResp.Error.Message)
+	}
+	got, ok := new(big.Int).SetString(strings.TrimPrefix(rpcResp.Result, "0x"), 16)
+	if !ok {
+		return failed(check, "invalid chain ID %q", rpcResp.Result)
+	}
+	if got.Cmp(big.NewInt(chainID)) != 0 {
+		return fail(check, "endpoint serves chain %s, expected %d", got, chainID)
+	}
+	return pass(check, "reachable, chain %d", chainID)
+}
@@ -0,0 +1,237 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/services"
+)
+
+func validConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.App.Environment = "test"
+	cfg.App.UploadDir = "./uploads"
+	cfg.Database = config.DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", DBName: "sukuk"}
+	cfg.Blockchain = config.BlockchainConfig{ChainID: 84532, RPCEndpoint: "https://sepolia.base.org", WebSocketURL: "wss://sepolia.base.org"}
+	cfg.API = config.APIConfig{APIKey: "key", WebhookSecret: "secret"}
+	cfg.Cache.Driver = "memory"
+	cfg.Email.UnsubscribeSecret = "secret"
+	cfg.Uploads.LinkSecret = "secret"
+	return cfg
+}
+
+func statuses(results []Result) map[Status]int {
+	counts := make(map[Status]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	return counts
+}
+
+func TestCheckConfig(t *testing.T) {
+	if got := statuses(CheckConfig(validConfig())); got[StatusPass] != 1 || len(got) != 1 {
+		t.Errorf("Expected a valid config to pass alone, got %v", got)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*config.Config)
+		want   Status
+	}{
+		{"missing db host", func(c *config.Config) { c.Database.Host = "" }, StatusFail},
+		{"missing upload dir", func(c *config.Config) { c.App.UploadDir = "" }, StatusFail},
+		{"rpc without scheme", func(c *config.Config) { c.Blockchain.RPCEndpoint = "sepolia.base.org" }, StatusFail},
+		{"websocket over http", func(c *config.Config) { c.Blockchain.WebSocketURL = "https://sepolia.base.org" }, StatusFail},
+		{"bad contract address", func(c *config.Config) { c.Blockchain.ContractAddress = "0x123" }, StatusFail},
+		{"redis without url", func(c *config.Config) { c.Cache.Driver, c.Cache.RedisURL = "redis", "localhost:6379" }, StatusFail},
+		{"backfill without rpc", func(c *config.Config) { c.Sync.OnchainBackfill, c.Blockchain.RPCEndpoint = true, "" }, StatusFail},
+		{"email without host", func(c *config.Config) { c.Email.Enabled, c.Email.Host = true, "" }, StatusFail},
+		{"no webhook secret", func(c *config.Config) { c.API.WebhookSecret = "" }, StatusWarn},
+		{"no api key in development", func(c *config.Config) { c.App.Environment, c.API.APIKey = "development", "" }, StatusWarn},
+		{"no api key outside development", func(c *config.Config) { c.API.APIKey = "" }, StatusFail},
+		{"no upload link secret", func(c *config.Config) { c.Uploads.LinkSecret = "" }, StatusWarn},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		tt.mutate(cfg)
+		got := statuses(CheckConfig(cfg))
+		if got[tt.want] != 1 {
+			t.Errorf("%s: expected one %s result, got %v", tt.name, tt.want, got)
+		}
+		if tt.want == StatusFail && got[StatusPass] != 0 {
+			t.Errorf("%s: expected no pass result next to a failure, got %v", tt.name, got)
+		}
+	}
+}
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error { return f(ctx) }
+
+func TestCheckDatabase(t *testing.T) {
+	if result := CheckDatabase(context.Background(), pingerFunc(func(context.Context) error { return nil })); result.Status != StatusPass {
+		t.Errorf("Expected pass, got %+v", result)
+	}
+	down := pingerFunc(func(context.Context) error { return errors.New("connection refused") })
+	if result := CheckDatabase(context.Background(), down); result.Status != StatusFail || !strings.Contains(result.Message, "connection refused") {
+		t.Errorf("Expected the ping error to fail the check, got %+v", result)
+	}
+}
+
+type fakeSchema struct {
+	version, latest int
+	err             error
+}
+
+func (s fakeSchema) Version() (int, error) { return s.version, s.err }
+func (s fakeSchema) LatestVersion() int    { return s.latest }
+
+func TestCheckMigrations(t *testing.T) {
+	tests := []struct {
+		schema fakeSchema
+		want   Status
+	}{
+		{fakeSchema{version: 18, latest: 18}, StatusPass},
+		{fakeSchema{version: 16, latest: 18}, StatusFail},
+		{fakeSchema{version: 19, latest: 18}, StatusWarn},
+		{fakeSchema{err: errors.New("relation schema_migrations does not exist"), latest: 18}, StatusFail},
+	}
+	for _, tt := range tests {
+		if result := CheckMigrations(tt.schema); result.Status != tt.want {
+			t.Errorf("%+v: expected %s, got %+v", tt.schema, tt.want, result)
+		}
+	}
+}
+
+func TestCheckIndexerTables(t *testing.T) {
+	all := func() ([]string, error) {
+		suffixes := make([]string, 0, len(services.EventTableMapping))
+		for _, suffix := range services.EventTableMapping {
+			suffixes = append(suffixes, suffix)
+		}
+		return suffixes, nil
+	}
+	if got := statuses(CheckIndexerTables(all)); got[StatusPass] != 1 || len(got) != 1 {
+		t.Errorf("Expected all tables to pass, got %v", got)
+	}
+
+	// Only the required events: optional ones warn
+	required := func() ([]string, error) {
+		var suffixes []string
+		for _, eventType := range RequiredEventTypes {
+			suffixes = append(suffixes, services.EventTableMapping[eventType])
+		}
+		return suffixes, nil
+	}
+	if got := statuses(CheckIndexerTables(required)); got[StatusWarn] != 1 || got[StatusFail] != 0 {
+		t.Errorf("Expected missing optional tables to warn, got %v", got)
+	}
+
+	// Purchases missing: fails, naming the event
+	results := CheckIndexerTables(func() ([]string, error) { return []string{"sukuk_creation", "redemption_request"}, nil })
+	if got := statuses(results); got[StatusFail] != 1 || !strings.Contains(results[0].Message, "sukuk_purchase") {
+		t.Errorf("Expected the missing purchase table to fail, got %+v", results)
+	}
+
+	results = CheckIndexerTables(func() ([]string, error) { return nil, errors.New("circuit breaker open") })
+	if len(results) != 1 || results[0].Status != StatusFail {
+		t.Errorf("Expected a discovery error to fail, got %+v", results)
+	}
+}
+
+func TestCheckUploadDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+	if result := CheckUploadDir(dir, false); result.Status != StatusPass {
+		t.Errorf("Expected a creatable directory to pass, got %+v", result)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the probe file to be removed, found %v", entries)
+	}
+
+	// A regular file in the way of the directory
+	blocked := filepath.Join(t.TempDir(), "uploads")
+	if err := os.WriteFile(blocked, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := CheckUploadDir(filepath.Join(blocked, "sub"), false); result.Status != StatusFail {
+		t.Errorf("Expected an unusable directory to fail, got %+v", result)
+	}
+	if result := CheckUploadDir(filepath.Join(blocked, "sub"), true); result.Status != StatusWarn {
+		t.Errorf("Expected an unusable directory to only warn in read-only mode, got %+v", result)
+	}
+}
+
+func TestCheckRPC(t *testing.T) {
+	rpc := func(body string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+	}
+	ctx := context.Background()
+
+	ok := rpc(`{"jsonrpc":"2.0","id":1,"result":"0x14a34"}`, http.StatusOK)
+	defer ok.Close()
+	if result := CheckRPC(ctx, ok.Client(), ok.URL, 84532, true); result.Status != StatusPass {
+		t.Errorf("Expected pass, got %+v", result)
+	}
+	if result := CheckRPC(ctx, ok.Client(), ok.URL, 8453, false); result.Status != StatusFail {
+		t.Errorf("Expected another chain to fail, got %+v", result)
+	}
+
+	rpcError := rpc(`{"jsonrpc":"2.0","id":1,"error":{"message":"rate limited"}}`, http.StatusOK)
+	defer rpcError.Close()
+	unavailable := rpc(`bad gateway`, http.StatusBadGateway)
+	defer unavailable.Close()
+	closed := rpc("", http.StatusOK)
+	closed.Close()
+
+	for name, server := range map[string]*httptest.Server{"rpc error": rpcError, "bad status": unavailable, "unreachable": closed} {
+		if result := CheckRPC(ctx, server.Client(), server.URL, 84532, true); result.Status != StatusFail {
+			t.Errorf("%s: expected fail when required, got %+v", name, result)
+		}
+		if result := CheckRPC(ctx, server.Client(), server.URL, 84532, false); result.Status != StatusWarn {
+			t.Errorf("%s: expected warn when optional, got %+v", name, result)
+		}
+	}
+
+	if result := CheckRPC(ctx, http.DefaultClient, "", 84532, false); result.Status != StatusPass {
+		t.Errorf("Expected an unconfigured optional endpoint to pass, got %+v", result)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	report := &Report{}
+	report.Add(pass("config", "ok"), warn("rpc", "slow"))
+	if report.Failed() {
+		t.Error("Expected warnings not to fail the report")
+	}
+	report.Add(fail("database", "unreachable"))
+	if !report.Failed() {
+		t.Error("Expected a failed check to fail the report")
+	}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "fail  database") || !strings.Contains(text.String(), "1 passed, 1 warnings, 1 failed") {
+		t.Errorf("Unexpected text report:\n%s", text.String())
+	}
+
+	var encoded bytes.Buffer
+	if err := report.WriteJSON(&encoded); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(encoded.String(), `"status": "fail"`) || !strings.Contains(encoded.String(), `"failed": 1`) {
+		t.Errorf("Unexpected JSON report:\n%s", encoded.String())
+	}
+}
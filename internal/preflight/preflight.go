@@ -0,0 +1,124 @@
+// Package preflight checks that a deployment can serve requests: configuration, database,
+// schema, indexer tables, upload storage and RPC endpoint. It backs the --preflight flag
+// and runs on every normal start, so misconfiguration fails the deploy instead of a request
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/services"
+
+	"gorm.io/gorm"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // Degraded but able to serve
+	StatusFail Status = "fail" // The server would fail requests or not start
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Check   string `json:"check"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+}
+
+func pass(check, format string, args ...interface{}) Result {
+	return Result{Check: check, Status: StatusPass, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(check, format string, args ...interface{}) Result {
+	return Result{Check: check, Status: StatusWarn, Message: fmt.Sprintf(format, args...)}
+}
+
+func fail(check, format string, args ...interface{}) Result {
+	return Result{Check: check, Status: StatusFail, Message: fmt.Sprintf(format, args...)}
+}
+
+// Report collects the results of a preflight run
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Add appends results to the report
+func (r *Report) Add(results ...Result) {
+	r.Results = append(r.Results, results...)
+}
+
+// Count returns the number of results with the status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// WriteText writes one line per check followed by a summary
+func (r *Report) WriteText(w io.Writer) error {
+	for _, result := range r.Results {
+		if _, err := fmt.Fprintf(w, "%-4s  %-18s  %s\n", result.Status, result.Check, result.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", r.Count(StatusPass), r.Count(StatusWarn), r.Count(StatusFail))
+	return err
+}
+
+// WriteJSON writes the report as a JSON document
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Results  []Result `json:"results"`
+		Passed   int      `json:"passed"`
+		Warnings int      `json:"warnings"`
+		Failed   int      `json:"failed"`
+	}{r.Results, r.Count(StatusPass), r.Count(StatusWarn), r.Count(StatusFail)})
+}
+
+// checkTimeout bounds each network check
+const checkTimeout = 5 * time.Second
+
+// Run checks cfg and the deployment it describes. db is the connected main database; when
+// connecting failed it is nil and dbErr is reported, skipping the checks that need it
+func Run(ctx context.Context, cfg *config.Config, db *gorm.DB, dbErr error) *Report {
+	report := &Report{}
+	report.Add(CheckConfig(cfg)...)
+
+	if dbErr != nil || db == nil {
+		report.Add(fail("database", "not connected: %v", dbErr))
+	} else if sqlDB, err := db.DB(); err != nil {
+		report.Add(fail("database", "%v", err))
+	} else {
+		report.Add(CheckDatabase(ctx, sqlDB))
+		if migrator, err := database.NewMigrator(db); err != nil {
+			report.Add(fail("migrations", "failed to load migrations: %v", err))
+		} else {
+			report.Add(CheckMigrations(migrator))
+		}
+		report.Add(CheckIndexerTables(services.NewIndexerTableService().GetAvailableEventTypes)...)
+	}
+
+	report.Add(CheckUploadDir(cfg.App.UploadDir, cfg.App.ReadOnly))
+	report.Add(CheckRPC(ctx, &http.Client{Timeout: checkTimeout}, cfg.Blockchain.RPCEndpoint, cfg.Blockchain.ChainID, cfg.Sync.OnchainBackfill))
+	return report
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"io"
+	"os"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/middleware"
+)
+
+// OpenAccessLog opens the configured access log sink, or returns nil when it is disabled
+func OpenAccessLog(cfg config.AccessLogConfig) (io.WriteCloser, error) {
+	switch cfg.Sink {
+	case "stdout":
+		return nopCloser{os.Stdout}, nil
+	case "file":
+		return logger.OpenRotatingFile(cfg.FilePath, int64(cfg.MaxSizeMB)<<20, cfg.MaxBackups)
+	default:
+		return nil, nil
+	}
+}
+
+// nopCloser keeps the shared stdout open when the access log is closed
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func accessLogOptions(cfg config.AccessLogConfig, w io.Writer) middleware.AccessLogOptions {
+	return middleware.AccessLogOptions{
+		Writer:            w,
+		SuccessSampleRate: cfg.SuccessSampleRate,
+		ScrubAddresses:    cfg.ScrubAddresses,
+		HashSalt:          cfg.HashSalt,
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"strings"
+	"time"
+
+	"sukuk-be/internal/config"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// corsMiddleware builds the CORS policy from the configured allowed origins
+// Origins may be exact ("https://app.example.com"), subdomain wildcards
+// ("https://*.example.com") or "*" to allow any origin without credentials
+func corsMiddleware(apiCfg config.APIConfig) gin.HandlerFunc {
+	corsConfig := cors.Config{
+		// Only the methods and headers the API actually uses
+		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "If-Match", "If-None-Match", "X-Request-ID"},
+		// Rate-limit, cache, version, deprecation and request ID headers need to be readable by the
+		// frontend; pagination travels in the response body
+		ExposeHeaders: []string{
+			"Content-Length",
+			"X-RateLimit-Limit",
+			"X-RateLimit-Remaining",
+			"Retry-After",
+			"Cache-Status",
+			"ETag",
+			"Deprecation",
+			"Sunset",
+			"Link",
+			"X-Request-ID",
+		},
+		AllowCredentials: true,
+		AllowWildcard:    true,
+		MaxAge:           12 * time.Hour,
+	}
+
+	origins := make([]string, 0, len(apiCfg.AllowedOrigins))
+	for _, origin := range apiCfg.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			// Browsers reject credentials with a wildcard origin, so disable them
+			corsConfig.AllowAllOrigins = true
+			corsConfig.AllowCredentials = false
+			return cors.New(corsConfig)
+		}
+		if origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+
+	corsConfig.AllowOrigins = origins
+	return cors.New(corsConfig)
+}
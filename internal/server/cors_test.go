@@ -0,0 +1,79 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sukuk-be/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(origins []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(corsMiddleware(config.APIConfig{AllowedOrigins: origins}))
+	router.GET("/api/v1/portfolio/:address", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func preflight(router *gin.Engine, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/portfolio/0xabc", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "X-API-Key")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPreflightAllowedOrigin(t *testing.T) {
+	router := newCORSRouter([]string{"http://localhost:3000", "https://*.sukuk-poc.com"})
+
+	for _, origin := range []string{"http://localhost:3000", "https://app.sukuk-poc.com"} {
+		w := preflight(router, origin)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Expected status 204 for %s, got %d", origin, w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+			t.Errorf("Expected Access-Control-Allow-Origin '%s', got '%s'", origin, got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("Expected credentials to be allowed for %s, got '%s'", origin, got)
+		}
+	}
+}
+
+func TestCORSPreflightDisallowedOrigin(t *testing.T) {
+	router := newCORSRouter([]string{"http://localhost:3000"})
+
+	w := preflight(router, "https://evil.example.com")
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin, got '%s'", got)
+	}
+}
+
+func TestCORSWildcardDisablesCredentials(t *testing.T) {
+	router := newCORSRouter([]string{"*"})
+
+	w := preflight(router, "https://any.example.com")
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected Access-Control-Allow-Origin '*', got '%s'", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected credentials to be disabled, got '%s'", got)
+	}
+}
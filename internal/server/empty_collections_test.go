@@ -0,0 +1,242 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/stream"
+	"sukuk-be/internal/testutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerSpec is the part of docs/swagger.json the contract tests read
+type swaggerSpec struct {
+	BasePath    string                            `json:"basePath"`
+	Paths       map[string]map[string]swaggerOp   `json:"paths"`
+	Definitions map[string]map[string]interface{} `json:"definitions"`
+}
+
+type swaggerOp struct {
+	Responses map[string]struct {
+		Schema map[string]interface{} `json:"schema"`
+	} `json:"responses"`
+}
+
+func loadSwaggerSpec(t *testing.T) *swaggerSpec {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "docs", "swagger.json"))
+	if err != nil {
+		t.Fatalf("Failed to read swagger.json: %v", err)
+	}
+	var spec swaggerSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Failed to parse swagger.json: %v", err)
+	}
+	return &spec
+}
+
+var routeParam = regexp.MustCompile(`[:*](\w+)`)
+
+// responseSchema returns the documented 200 schema of a GET route. v2 routes are checked
+// against their v1 schema inside the data envelope
+func (s *swaggerSpec) responseSchema(route string) (map[string]interface{}, bool) {
+	path, envelope := route, false
+	switch {
+	case strings.HasPrefix(route, s.BasePath+"/"):
+		path = strings.TrimPrefix(route, s.BasePath)
+	case strings.HasPrefix(route, "/api/v2/"):
+		path, envelope = strings.TrimPrefix(route, "/api/v2"), true
+	}
+	path = routeParam.ReplaceAllString(path, "{$1}")
+
+	op, ok := s.Paths[path]["get"]
+	if !ok {
+		return nil, false
+	}
+	schema := op.Responses["200"].Schema
+	if envelope {
+		schema = map[string]interface{}{"properties": map[string]interface{}{"data": schema}}
+	}
+	return schema, true
+}
+
+// nullArrays lists the JSON paths in value that are null where schema says array
+func (s *swaggerSpec) nullArrays(schema map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return s.nullArrays(s.Definitions[strings.TrimPrefix(ref, "#/definitions/")], value, path)
+	}
+
+	var found []string
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			if part, ok := part.(map[string]interface{}); ok {
+				found = append(found, s.nullArrays(part, value, path)...)
+			}
+		}
+	}
+
+	switch value := value.(type) {
+	case nil:
+		if schema["type"] == "array" {
+			found = append(found, path)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range value {
+				found = append(found, s.nullArrays(items, item, path+"["+itoa(i)+"]")...)
+			}
+		}
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for key, field := range value {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				found = append(found, s.nullArrays(property, field, path+"."+key)...)
+			} else if additional != nil {
+				found = append(found, s.nullArrays(additional, field, path+"."+key)...)
+			}
+		}
+	}
+	return found
+}
+
+func itoa(i int) string {
+	b, _ := json.Marshal(i)
+	return string(b)
+}
+
+func TestNullArraysFollowsSwaggerSchema(t *testing.T) {
+	spec := &swaggerSpec{Definitions: map[string]map[string]interface{}{
+		"Holding": {"properties": map[string]interface{}{
+			"ids":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "integer"}},
+			"label": map[string]interface{}{"type": "string"},
+		}},
+	}}
+	schema := map[string]interface{}{"properties": map[string]interface{}{
+		"holdings": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/definitions/Holding"}},
+		"byToken":  map[string]interface{}{"additionalProperties": map[string]interface{}{"type": "array"}},
+		"missing":  map[string]interface{}{"type": "array"},
+	}}
+
+	var body interface{}
+	json.Unmarshal([]byte(`{"holdings":[{"ids":[1],"label":null},{"ids":null}],"byToken":{"0xc1":null,"0xc2":[]}}`), &body)
+	got := spec.nullArrays(schema, body, "$")
+	if len(got) != 2 || !containsPath(got, "$.holdings[1].ids") || !containsPath(got, "$.byToken.0xc1") {
+		t.Errorf("Expected the null ids and byToken entry only, got %v", got)
+	}
+}
+
+func containsPath(paths []string, want string) bool {
+	for _, path := range paths {
+		if path == want {
+			return true
+		}
+	}
+	return false
+}
+
+// contractPathValues fill route parameters with values no fixture uses
+var contractPathValues = map[string]string{
+	"address":       "0x00000000000000000000000000000000c0ffee01",
+	"sukuk_address": "0x00000000000000000000000000000000c0ffee02",
+	"sukukAddress":  "0x00000000000000000000000000000000c0ffee02",
+	"code":          "NOCODE",
+	"table_name":    "5eed__sukuk_purchase",
+	"hash_prefix":   "5eed",
+	"locale":        "en",
+}
+
+// contractSkippedRoutes stream or serve files rather than JSON
+var contractSkippedRoutes = map[string]bool{
+	"/api/v1/files/:token":                         true,
+	"/api/v1/stream/activities":                    true,
+	"/api/v1/sukuk-metadata/:id/export/activities": true,
+	"/metrics":           true,
+	"/swagger/*any":      true,
+	"/uploads/*filepath": true,
+}
+
+// TestGETEndpointsRenderEmptyCollections requires TEST_DATABASE_DSN pointing at an empty
+// database; the schema is migrated and the synthetic indexer tables are created empty
+func TestGETEndpointsRenderEmptyCollections(t *testing.T) {
+	db := testutil.DB(t)
+	if err := database.CreateSyntheticIndexerTables(db); err != nil {
+		t.Fatalf("Failed to create indexer tables: %v", err)
+	}
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+	cache.SetDefault(cache.NewMemoryCache())
+
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		App: config.AppConfig{Environment: "test"},
+		API: config.APIConfig{APIKey: testAPIKey, RateLimitPerMin: 100000, MaxBodySize: 1 << 20, MaxUploadSize: 1 << 20},
+	}
+	s := New(cfg, nil, stream.NewBroker(stream.DefaultHistorySize, stream.DefaultBufferSize), nil, nil, nil,
+		services.NewSyncHealthMonitor(services.SyncThresholds{}, nil), nil, services.NewAPIKeyUsageRecorder(db, 0))
+	s.setupRoutes()
+	spec := loadSwaggerSpec(t)
+
+	checked := 0
+	for _, route := range s.router.Routes() {
+		if route.Method != http.MethodGet || contractSkippedRoutes[route.Path] {
+			continue
+		}
+		schema, ok := spec.responseSchema(route.Path)
+		if !ok {
+			if strings.HasPrefix(route.Path, "/api/") {
+				t.Errorf("%s is not documented in swagger.json", route.Path)
+			}
+			continue
+		}
+
+		path := routeParam.ReplaceAllStringFunc(route.Path, func(param string) string {
+			if value, ok := contractPathValues[param[1:]]; ok {
+				return value
+			}
+			return "1"
+		})
+		w := serve(s, http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Logf("%s: %d, not checked", path, w.Code)
+			continue
+		}
+		checked++
+
+		var body interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: invalid JSON: %v", path, err)
+			continue
+		}
+		for _, null := range spec.nullArrays(schema, body, "$") {
+			t.Errorf("%s: %s is null but documented as an array", path, null)
+		}
+	}
+	if checked == 0 {
+		t.Error("Expected at least one endpoint to answer 200")
+	}
+}
+
+func TestEveryAPIGETRouteIsDocumented(t *testing.T) {
+	spec := loadSwaggerSpec(t)
+	s := newReadOnlyServer(false)
+	for _, route := range s.router.Routes() {
+		if route.Method != http.MethodGet || contractSkippedRoutes[route.Path] || !strings.HasPrefix(route.Path, "/api/") {
+			continue
+		}
+		if _, ok := spec.responseSchema(route.Path); !ok {
+			t.Errorf("%s is not documented in swagger.json", route.Path)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/config"
+	"sukuk-be/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAPIKey = "test-api-key"
+
+func newReadOnlyServer(readOnly bool) *Server {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		App: config.AppConfig{Environment: "test", ReadOnly: readOnly},
+		API: config.APIConfig{
+			APIKey:          testAPIKey,
+			RateLimitPerMin: 1000,
+			AllowedOrigins:  []string{"http://localhost:3000"},
+			MaxBodySize:     1 << 20,
+			MaxUploadSize:   1 << 20,
+		},
+	}
+	s := New(cfg, nil, stream.NewBroker(stream.DefaultHistorySize, stream.DefaultBufferSize), nil, nil, nil, nil, nil, nil)
+	s.setupRoutes()
+	return s
+}
+
+func serve(s *Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestReadOnlyModeRejectsMutations(t *testing.T) {
+	s := newReadOnlyServer(true)
+
+	mutations := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/sukuk-metadata"},
+		{http.MethodPut, "/api/v1/sukuk-metadata/1"},
+		{http.MethodPost, "/api/v1/sukuk-metadata/sync"},
+		{http.MethodPost, "/api/v1/orders"},
+		{http.MethodPost, "/api/v1/orders/1/payment-callback"},
+		{http.MethodPost, "/api/v1/referrals/claim"},
+		{http.MethodPost, "/api/v1/admin/referrals"},
+		{http.MethodPut, "/api/v1/admin/investors/0x1234567890123456789012345678901234567890"},
+		{http.MethodDelete, "/api/v1/admin/payment-tokens/0x1234567890123456789012345678901234567890"},
+		{http.MethodPost, "/api/v1/admin/system/force-sync"},
+		{http.MethodPost, "/api/v1/admin/maintenance/cleanup-uploads"},
+		{http.MethodPatch, "/api/v1/sukuk-metadata/1"},
+	}
+	for _, m := range mutations {
+		w := serve(s, m.method, m.path, `{}`)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: expected 405, got %d", m.method, m.path, w.Code)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), "read-only") {
+			t.Errorf("%s %s: expected a read-only JSON message, got %s", m.method, m.path, w.Body.String())
+		}
+		if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS" {
+			t.Errorf("%s %s: expected Allow header, got %q", m.method, m.path, got)
+		}
+	}
+}
+
+func TestReadOnlyModeUnmountsWritingReads(t *testing.T) {
+	s := newReadOnlyServer(true)
+
+	// These GETs store something, so the method check alone would let them through
+	for _, path := range []string{
+		"/api/v1/sukuk-metadata/1/prospectus-link",
+		"/api/v1/auth/nonce/0x1234567890123456789012345678901234567890",
+		"/api/v1/admin/digest/0x1234567890123456789012345678901234567890",
+		"/api/v1/admin/view-as/0x1234567890123456789012345678901234567890",
+		"/api/v1/portfolio/0x1234567890123456789012345678901234567890/certificate/0x1234567890123456789012345678901234567890",
+	} {
+		if w := serve(s, http.MethodGet, path, ""); w.Code != http.StatusNotFound {
+			t.Errorf("GET %s: expected 404 on a read-only replica, got %d", path, w.Code)
+		}
+	}
+
+	// Unsubscribe links still render their confirmation, which writes nothing; applying one is a POST
+	if w := serve(s, http.MethodPost, "/api/v1/unsubscribe?token=x", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /api/v1/unsubscribe: expected 405 on a read-only replica, got %d", w.Code)
+	}
+}
+
+func TestReadOnlyModeServesReads(t *testing.T) {
+	for _, readOnly := range []bool{true, false} {
+		s := newReadOnlyServer(readOnly)
+
+		if w := serve(s, http.MethodGet, "/metrics", ""); w.Code != http.StatusOK {
+			t.Errorf("read_only=%v: expected /metrics to be served, got %d", readOnly, w.Code)
+		}
+		anonymous := httptest.NewRecorder()
+		s.router.ServeHTTP(anonymous, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		if anonymous.Code != http.StatusUnauthorized {
+			t.Errorf("read_only=%v: expected /metrics to need the API key, got %d", readOnly, anonymous.Code)
+		}
+		// Reads reach their handlers, which validate the input before touching the database
+		if w := serve(s, http.MethodGet, "/api/v1/referrals/a!/stats", ""); w.Code != http.StatusBadRequest {
+			t.Errorf("read_only=%v: expected the stats handler to reject the code with 400, got %d", readOnly, w.Code)
+		}
+
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/orders", nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Errorf("read_only=%v: expected CORS preflight to succeed, got %d", readOnly, w.Code)
+		}
+	}
+
+	// Without the flag mutations reach their handlers as before
+	s := newReadOnlyServer(false)
+	if w := serve(s, http.MethodPost, "/api/v1/auth/verify", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected the sign-in handler to reject an empty body with 400, got %d", w.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/handlers"
+)
+
+func TestRouteTableCoversEveryHandler(t *testing.T) {
+	s := newReadOnlyServer(false)
+	if err := checkRouteCoverage(s.table, handlers.Endpoints); err != nil {
+		t.Fatal(err)
+	}
+
+	// A handler left out of the table is reported by name
+	err := checkRouteCoverage(s.table[:1], []string{"ListSettings", "DenyProspectusUploads"})
+	if err == nil || !strings.Contains(err.Error(), "ListSettings") || strings.Contains(err.Error(), "DenyProspectusUploads") {
+		t.Errorf("Expected ListSettings to be reported as unrouted, got %v", err)
+	}
+}
+
+func TestAdminRoutesRequireAPIKey(t *testing.T) {
+	s := newReadOnlyServer(false)
+
+	for _, route := range s.table {
+		if strings.Contains(route.Path, "/admin/") && route.Auth != AuthAdmin {
+			t.Errorf("%s %s: expected admin auth, got %s", route.Method, route.Path, route.Auth)
+		}
+	}
+
+	checked := 0
+	for _, route := range s.router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/admin/") {
+			continue
+		}
+		checked++
+		path := routeParam.ReplaceAllString(route.Path, "x")
+		for _, key := range []string{"", "wrong-key"} {
+			req := httptest.NewRequest(route.Method, path, strings.NewReader(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			if key != "" {
+				req.Header.Set("X-API-Key", key)
+			}
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("%s %s with key %q: expected 401, got %d", route.Method, route.Path, key, w.Code)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Fatal("Expected admin routes to be mounted")
+	}
+}
+
+func TestListRoutesDumpsTable(t *testing.T) {
+	s := newReadOnlyServer(false)
+
+	w := serve(s, http.MethodGet, "/api/v1/admin/routes", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var routes []handlers.RouteInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil {
+		t.Fatalf("Failed to decode routes: %v", err)
+	}
+	if len(routes) != len(s.table) {
+		t.Errorf("Expected %d routes, got %d", len(s.table), len(routes))
+	}
+
+	byPath := make(map[string]handlers.RouteInfo)
+	for _, route := range routes {
+		byPath[route.Method+" "+route.Path] = route
+	}
+	if got := byPath["PUT /api/v1/admin/settings"]; got.Handler != "handlers.UpdateSettings" || got.Auth != "admin" || !got.ReadOnlyExempt {
+		t.Errorf("Unexpected settings route %+v", got)
+	}
+	if got := byPath["GET /api/v1/sukuk-metadata/:id/prospectus-link"]; got.Handler != "handlers.GetProspectusLink" || got.Auth != "public" || got.RateLimit != "api" {
+		t.Errorf("Expected constructed handlers to be named after their constructor, got %+v", got)
+	}
+	if got := byPath["POST /api/v1/dev/inject-event"]; got.Enabled {
+		t.Errorf("Expected the event injector routes to be listed as disabled, got %+v", got)
+	}
+	if got := byPath["GET /health"]; got.RateLimit != "none" {
+		t.Errorf("Expected /health not to be rate limited, got %+v", got)
+	}
+}
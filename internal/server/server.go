@@ -1,27 +1,42 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"time"
+	"io"
+	"net/http"
 
 	"sukuk-be/internal/config"
 	"sukuk-be/internal/handlers"
+	"sukuk-be/internal/lifecycle"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/middleware"
+	"sukuk-be/internal/services"
+	"sukuk-be/internal/stream"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 type Server struct {
-	cfg    *config.Config
-	router *gin.Engine
+	cfg          *config.Config
+	router       *gin.Engine
+	metadataSync *services.SukukMetadataSyncService
+	activities   *stream.Broker
+	uploads      *services.UploadCleanupService
+	retention    *services.RetentionService
+	injector     *services.EventInjector // Nil unless DEV_EVENT_INJECTOR is set
+	syncHealth   *services.SyncHealthMonitor
+	usage        *services.APIKeyUsageRecorder // Nil on read-only replicas
+	table        []Route // Route table, set by setupRoutes
+	http         *http.Server
+	inFlight     *lifecycle.Counter // Requests being served
 }
 
-func New(cfg *config.Config) *Server {
+// multipartMemory is how much of a multipart form is kept in memory while parsing
+const multipartMemory = 1 << 20
+
+func New(cfg *config.Config, metadataSync *services.SukukMetadataSyncService, activities *stream.Broker, uploads *services.UploadCleanupService, retention *services.RetentionService, injector *services.EventInjector, syncHealth *services.SyncHealthMonitor, accessLog io.Writer, usage *services.APIKeyUsageRecorder) *Server {
 	// Set gin mode based on environment
 	if cfg.App.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -29,105 +44,110 @@ func New(cfg *config.Config) *Server {
 
 	router := gin.New()
 
-	// Global middleware
+	// Multipart parts beyond this are spooled to temp files instead of held in memory
+	router.MaxMultipartMemory = multipartMemory
+
+	// Global middleware; requests are counted first so shutdown logs what it waited for
+	inFlight := &lifecycle.Counter{}
+	router.Use(countInFlight(inFlight))
+	router.Use(middleware.RequestID())
+	if accessLog != nil {
+		// Outside recovery and the read-only guard so panics and rejected requests are logged too
+		router.Use(middleware.AccessLog(accessLogOptions(cfg.AccessLog, accessLog)))
+	}
+	if usage != nil {
+		// Counts after the route's auth middleware has validated the key
+		router.Use(middleware.APIKeyUsage(usage))
+	}
 	router.Use(middleware.RequestLogger())
 	router.Use(middleware.ErrorLogger())
+	if cfg.API.CompressionEnabled {
+		// Outside recovery so the 500 written after a panic is completed too
+		router.Use(middleware.Compress(middleware.CompressionOptions{
+			MinSize: cfg.API.CompressionMinSize,
+			Paths:   cfg.API.CompressionPaths,
+		}))
+	}
 	router.Use(gin.Recovery())
 
 	// CORS middleware
-	corsConfig := cors.Config{
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-API-Key"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}
-	
-	// Check if we should allow all origins
-	if len(cfg.API.AllowedOrigins) == 1 && cfg.API.AllowedOrigins[0] == "*" {
-		corsConfig.AllowAllOrigins = true
-		corsConfig.AllowCredentials = false // Cannot use credentials with AllowAllOrigins
-	} else {
-		corsConfig.AllowOrigins = cfg.API.AllowedOrigins
+	router.Use(corsMiddleware(cfg.API))
+
+	// Read-only replicas reject mutations after CORS, so browsers can read the 405; the
+	// api.read_only setting does the same at runtime per route, see registerRoutes
+	if cfg.App.ReadOnly {
+		router.Use(middleware.ReadOnly())
 	}
-	
-	router.Use(cors.New(corsConfig))
 
 	return &Server{
-		cfg:    cfg,
-		router: router,
+		cfg:          cfg,
+		router:       router,
+		metadataSync: metadataSync,
+		activities:   activities,
+		uploads:      uploads,
+		retention:    retention,
+		injector:     injector,
+		syncHealth:   syncHealth,
+		usage:        usage,
+		http:         &http.Server{Addr: fmt.Sprintf(":%d", cfg.App.Port), Handler: router},
+		inFlight:     inFlight,
 	}
 }
 
-func (s *Server) setupRoutes() {
-	// Static file serving for uploads
-	s.router.Static("/uploads", "./uploads")
-
-	// Swagger documentation
-	s.router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Health check endpoint (no auth required)
-	s.router.GET("/health", handlers.GetHealthStatus)
-
-	// API v1 group with middleware
-	v1 := s.router.Group("/api/v1")
-	v1.Use(middleware.RateLimit(s.cfg.API.RateLimitPerMin))
-	{
-		// Sukuk Metadata endpoints (core functionality)
-		sukukMetadata := v1.Group("/sukuk-metadata")
-		{
-			sukukMetadata.GET("", handlers.ListSukukMetadata)
-			sukukMetadata.GET("/:id", handlers.GetSukukMetadata)
-			sukukMetadata.POST("", handlers.CreateSukukMetadata)
-			sukukMetadata.PUT("/:id", handlers.UpdateSukukMetadata)
-			sukukMetadata.PUT("/:id/ready", handlers.MarkSukukMetadataReady)
-			sukukMetadata.PUT("/:id/unready", handlers.MarkSukukMetadataUnready)
-			sukukMetadata.POST("/sync", handlers.TriggerSukukMetadataSync)
-			sukukMetadata.GET("/tables", handlers.ListSukukCreationTables)
-		}
-
-		// Transaction History endpoints (both old and new formats)
-		v1.GET("/transaction-history/:address", handlers.GetRiwayatByAddress)
-		v1.GET("/transactions/:address", handlers.GetTransactionHistory)
-
-		// Owned Sukuk endpoint (Portfolio)
-		v1.GET("/owned-sukuk/:address", handlers.GetSukukOwnedByAddress)
-
-		// Portfolio endpoints
-		v1.GET("/portfolio/:address", handlers.GetUserPortfolio)
-		v1.GET("/yield-claims/:address", handlers.GetYieldClaims)
-		v1.GET("/yield-distributions/:sukuk_address", handlers.GetYieldDistributions)
-		
-		// Snapshot endpoints
-		v1.GET("/snapshots", handlers.GetAllSnapshots)
-		v1.GET("/sukuk/:sukukAddress/snapshots", handlers.GetSukukSnapshots)
-
-		// Redemption endpoints
-		v1.GET("/redemptions", handlers.GetAllRedemptions)
-		v1.GET("/redemptions/stats", handlers.GetRedemptionStats)
-		v1.GET("/redemptions/user/:address", handlers.GetRedemptionsByUser)
-		v1.GET("/redemptions/sukuk/:sukuk_address", handlers.GetRedemptionsBySukuk)
-		v1.GET("/redemptions/:request_id", handlers.GetRedemptionByID)
-
-		// Debug endpoints (optional - remove in production)
-		debug := v1.Group("/debug")
-		{
-			debug.GET("/indexer", handlers.DebugIndexerConnection)
-			debug.GET("/indexer-tables", handlers.ListIndexerTables)
-			debug.GET("/indexer-tables/validate", handlers.ValidateIndexerTables)
-			debug.GET("/indexer-tables/:table_name", handlers.GetTableDetails)
-			debug.GET("/indexer-tables/prefix/:hash_prefix", handlers.GetHashPrefixTables)
-		}
+// countInFlight counts the requests being served
+func countInFlight(counter *lifecycle.Counter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		counter.Begin()
+		defer counter.Done()
+		c.Next()
 	}
 }
 
+// setupRoutes mounts the route table and the uploads directory
+func (s *Server) setupRoutes() {
+	// Static file serving for uploads; prospectus files are only served through signed links
+	s.router.Group("/uploads", handlers.DenyProspectusUploads).Static("/", "./uploads")
+
+	s.table = s.routes()
+	s.registerRoutes(s.table)
+}
+
+// Start serves HTTP until Shutdown, then returns nil
 func (s *Server) Start() error {
 	// Setup routes
 	s.setupRoutes()
+	if err := checkRouteCoverage(s.table, handlers.Endpoints); err != nil {
+		return err
+	}
 
 	// Start server
-	addr := fmt.Sprintf(":%d", s.cfg.App.Port)
-	logger.WithField("address", addr).Info("Server listening and serving HTTP")
+	logger.WithField("address", s.http.Addr).Info("Server listening and serving HTTP")
+
+	if err := s.http.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections, ends activity streams and waits for the requests
+// being served until ctx ends, then closes the connections left
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.activities != nil {
+		s.activities.Close()
+	}
+	if err := s.http.Shutdown(ctx); err != nil {
+		s.http.Close()
+		return err
+	}
+	return nil
+}
+
+// InFlight returns the number of requests being served
+func (s *Server) InFlight() int {
+	return s.inFlight.Count()
+}
 
-	return s.router.Run(addr)
+// bodyLimits returns the configured request body limits for the API groups
+func (s *Server) bodyLimits() middleware.BodyLimits {
+	return middleware.BodyLimits{JSON: s.cfg.API.MaxBodySize, Upload: s.cfg.API.MaxUploadSize}
 }
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ActivityExportBatchSize is how many events an export reads per query
+const ActivityExportBatchSize = 1000
+
+// activityExportPosition is the keyset of the last exported event. Log indexes are unique
+// within a block; the id breaks ties between tables that index the same log twice
+type activityExportPosition struct {
+	BlockNumber int64
+	LogIndex    int64
+	ID          string
+}
+
+// activityExportRow is a row of the export UNION
+type activityExportRow struct {
+	Type         string `gorm:"column:type"`
+	ID           string `gorm:"column:id"`
+	Address      string `gorm:"column:address"`
+	PaymentToken string `gorm:"column:payment_token"`
+	Amount       string `gorm:"column:amount"`
+	TxHash       string `gorm:"column:tx_hash"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+	LogIndex     int64  `gorm:"column:log_index"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+}
+
+// ExportSukukActivities passes every purchase, redemption request and yield claim of a sukuk
+// from fromBlock on to emit, ordered by block number then log index. Events are read in
+// keyset-paginated batches of batchSize, so memory stays flat however long the history is.
+// It stops at the first error of emit or of a query, e.g. once ctx is cancelled
+func (s *IndexerQueryService) ExportSukukActivities(ctx context.Context, sukukAddress string, fromBlock int64, batchSize int, emit func(models.ActivityExportItem) error) error {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return err
+		}
+	}
+	if batchSize <= 0 {
+		batchSize = ActivityExportBatchSize
+	}
+
+	branches, err := s.activityExportBranches()
+	if err != nil {
+		return err
+	}
+
+	var after *activityExportPosition
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		query, args := activityExportQuery(branches, utils.NormalizeAddress(sukukAddress), fromBlock, after, batchSize)
+		var rows []activityExportRow
+		err := s.read(ctx, func(db *gorm.DB) error {
+			return db.Raw(query, args).Scan(&rows).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query activities: %w", err)
+		}
+
+		for _, row := range rows {
+			err := emit(models.ActivityExportItem{
+				ID:           row.ID,
+				Type:         models.ActivityType(row.Type),
+				Address:      utils.ChecksumAddress(row.Address),
+				PaymentToken: utils.ChecksumAddress(row.PaymentToken),
+				Amount:       row.Amount,
+				TxHash:       row.TxHash,
+				BlockNumber:  row.BlockNumber,
+				LogIndex:     row.LogIndex,
+				Timestamp:    time.Unix(row.Timestamp, 0).UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		after = &activityExportPosition{BlockNumber: last.BlockNumber, LogIndex: last.LogIndex, ID: last.ID}
+	}
+}
+
+// activityExportBranches builds one SELECT per activity table the indexer has, each with
+// the placeholders activityExportQuery fills. Event types without a table are left out
+func (s *IndexerQueryService) activityExportBranches() ([]string, error) {
+	var branches []string
+	for _, info := range models.EventActivityTypes() {
+		table, err := s.tableService.GetLatestTableForEvent(info.EventTable)
+		if err != nil {
+			continue
+		}
+
+		// Yield claims take their payment token from the distribution they claim
+		paymentToken, join := "e.payment_token", ""
+		if info.Type == models.ActivityTypeYieldClaim {
+			paymentToken = "''"
+			if distributionTable, err := s.tableService.GetLatestTableForEvent("yield_distributed"); err == nil {
+				paymentToken = "COALESCE(d.payment_token, '')"
+				join = fmt.Sprintf("LEFT JOIN %s d ON d.sukuk_address = e.sukuk_address AND d.distribution_id = e.distribution_id", quoteIdentifier(distributionTable))
+			}
+		}
+
+		// Each branch is ordered and limited on its own so the outer sort only sees a batch per
+		// table; the block bound lets the scan start at the position instead of the first block
+		branches = append(branches, fmt.Sprintf(`(
+			SELECT * FROM (
+				SELECT '%s' AS type, e.id, e.%s AS address, %s AS payment_token, e.amount::text AS amount,
+					e.tx_hash, e.block_number, %s AS log_index, e.timestamp
+				FROM %s e %s
+				WHERE LOWER(e.sukuk_address) = @sukuk AND e.block_number >= @block
+			) a
+			WHERE (a.block_number, a.log_index, a.id) > (@block, @log_index, @id)
+			ORDER BY a.block_number, a.log_index, a.id
+			LIMIT @limit)`, info.Type, activityFeedAddressColumns[info.Type], paymentToken, indexerLogIndex, quoteIdentifier(table), join))
+	}
+	if len(branches) == 0 {
+		return nil, fmt.Errorf("no activity tables found")
+	}
+	return branches, nil
+}
+
+// activityExportQuery joins the branches into the query of the batch after position after,
+// from the start of fromBlock when after is nil
+func activityExportQuery(branches []string, sukukAddress string, fromBlock int64, after *activityExportPosition, limit int) (string, map[string]interface{}) {
+	position := activityExportPosition{BlockNumber: fromBlock, LogIndex: -1}
+	if after != nil {
+		position = *after
+	}
+
+	query := "SELECT * FROM (" + strings.Join(branches, " UNION ALL ") + ") export ORDER BY block_number, log_index, id LIMIT @limit"
+	return query, map[string]interface{}{
+		"sukuk":     sukukAddress,
+		"block":     position.BlockNumber,
+		"log_index": position.LogIndex,
+		"id":        position.ID,
+		"limit":     limit,
+	}
+}
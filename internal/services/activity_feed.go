@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidActivityCursor is returned for a cursor the activity feed did not issue
+var ErrInvalidActivityCursor = errors.New("invalid activity cursor")
+
+// ActivityCursor is the position of the last activity of a feed page
+// Activities are ordered by timestamp then id, both descending, so the pair is unique
+type ActivityCursor struct {
+	Timestamp int64
+	ID        string
+}
+
+// Encode returns the opaque ?cursor= value of the position
+func (c ActivityCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Timestamp, 10) + "|" + c.ID))
+}
+
+// ParseActivityCursor decodes a cursor returned by Encode
+func ParseActivityCursor(raw string) (*ActivityCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidActivityCursor
+	}
+	timestamp, id, ok := strings.Cut(string(decoded), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidActivityCursor
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidActivityCursor
+	}
+	return &ActivityCursor{Timestamp: seconds, ID: id}, nil
+}
+
+// ActivityFeedFilter selects a page of the platform-wide activity feed
+type ActivityFeedFilter struct {
+	Type  models.ActivityType // Empty for every type
+	After *ActivityCursor     // Continue after this position; nil for the first page
+	Limit int
+}
+
+// activityFeedAddressColumns is the column holding the acting address of each activity type
+var activityFeedAddressColumns = map[models.ActivityType]string{
+	models.ActivityTypePurchase:          "buyer",
+	models.ActivityTypeRedemptionRequest: `"user"`,
+	models.ActivityTypeYieldClaim:        `"user"`,
+}
+
+// activityFeedRow is a row of the activity feed UNION
+type activityFeedRow struct {
+	Type         string `gorm:"column:type"`
+	ID           string `gorm:"column:id"`
+	Address      string `gorm:"column:address"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	PaymentToken string `gorm:"column:payment_token"`
+	Amount       string `gorm:"column:amount"`
+	TxHash       string `gorm:"column:tx_hash"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+}
+
+// GetActivityFeed returns the newest purchases, redemption requests and yield claims across
+// every sukuk, from the activity projection or, with projection reads off, in one UNION query
+// of the indexer tables, so the database does the global ordering. Addresses are
+// checksummed and amounts formatted in their payment token. The returned cursor is nil on
+// the last page. Transfers are left out
+func (s *IndexerQueryService) GetActivityFeed(ctx context.Context, filter ActivityFeedFilter, formatter *TokenFormatter) ([]models.ActivityFeedItem, *ActivityCursor, error) {
+	// Transfers are only derived per wallet, so the platform-wide feed has none
+	if filter.Type.IsTransfer() {
+		return []models.ActivityFeedItem{}, nil, nil
+	}
+
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var rows []activityFeedRow
+	var err error
+	if s.projection {
+		rows, err = s.projectedActivityFeed(ctx, filter)
+	} else {
+		rows, err = s.indexedActivityFeed(ctx, filter)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// One extra row was read to tell whether another page follows
+	var next *ActivityCursor
+	if len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		next = &ActivityCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	metadata, err := s.sukukMetadataByAddress(ctx, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]models.ActivityFeedItem, len(rows))
+	for i, row := range rows {
+		sukuk := metadata[strings.ToLower(row.SukukAddress)]
+		items[i] = models.ActivityFeedItem{
+			ID:              row.ID,
+			Type:            models.ActivityType(row.Type),
+			Address:         utils.ChecksumAddress(row.Address),
+			SukukAddress:    utils.ChecksumAddress(row.SukukAddress),
+			SukukCode:       sukuk.SukukCode,
+			SukukTitle:      sukuk.SukukTitle,
+			PaymentToken:    utils.ChecksumAddress(row.PaymentToken),
+			Amount:          row.Amount,
+			AmountFormatted: formatter.FormatTokenAmount(row.Amount, row.PaymentToken),
+			TxHash:          row.TxHash,
+			BlockNumber:     row.BlockNumber,
+			Timestamp:       time.Unix(row.Timestamp, 0),
+		}
+	}
+	return items, next, nil
+}
+
+// indexedActivityFeed reads a page of the feed, plus one row, from the indexer tables
+func (s *IndexerQueryService) indexedActivityFeed(ctx context.Context, filter ActivityFeedFilter) ([]activityFeedRow, error) {
+	query, args, err := s.activityFeedQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []activityFeedRow
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Raw(query, args...).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity feed: %w", err)
+	}
+	return rows, nil
+}
+
+// activityFeedQuery builds the UNION of every selected activity table. Each branch is ordered
+// and limited on its own so the outer sort only sees limit+1 rows per table
+func (s *IndexerQueryService) activityFeedQuery(filter ActivityFeedFilter) (string, []interface{}, error) {
+	var branches []string
+	var args []interface{}
+	for _, info := range models.EventActivityTypes() {
+		if filter.Type != "" && filter.Type != info.Type {
+			continue
+		}
+		table, err := s.tableService.GetLatestTableForEvent(info.EventTable)
+		if err != nil && filter.Type != "" {
+			return "", nil, fmt.Errorf("failed to find %s table: %w", info.EventTable, err)
+		}
+		if err != nil {
+			// An unfiltered feed leaves out event types the indexer has no table for yet
+			continue
+		}
+
+		// Yield claims take their payment token from the distribution they claim
+		paymentToken, join := "e.payment_token", ""
+		if info.Type == models.ActivityTypeYieldClaim {
+			paymentToken = "''"
+			if distributionTable, err := s.tableService.GetLatestTableForEvent("yield_distributed"); err == nil {
+				paymentToken = "COALESCE(d.payment_token, '')"
+				join = fmt.Sprintf("LEFT JOIN %s d ON d.sukuk_address = e.sukuk_address AND d.distribution_id = e.distribution_id", quoteIdentifier(distributionTable))
+			}
+		}
+
+		condition := "TRUE"
+		if filter.After != nil {
+			condition = "(e.timestamp, e.id) < (?, ?)"
+			args = append(args, filter.After.Timestamp, filter.After.ID)
+		}
+		args = append(args, filter.Limit+1)
+
+		branches = append(branches, fmt.Sprintf(`(
+			SELECT '%s' AS type, e.id, e.%s AS address, e.sukuk_address, %s AS payment_token,
+				e.amount::text AS amount, e.tx_hash, e.timestamp, e.block_number
+			FROM %s e %s
+			WHERE %s
+			ORDER BY e.timestamp DESC, e.id DESC
+			LIMIT ?)`, info.Type, activityFeedAddressColumns[info.Type], paymentToken, quoteIdentifier(table), join, condition))
+	}
+
+	if len(branches) == 0 {
+		return "", nil, fmt.Errorf("no activity tables found")
+	}
+
+	query := "SELECT * FROM (" + strings.Join(branches, " UNION ALL ") + ") feed ORDER BY timestamp DESC, id DESC LIMIT ?"
+	return query, append(args, filter.Limit+1), nil
+}
+
+// sukukMetadataByAddress loads the metadata of every sukuk in rows, keyed by lowercase address
+func (s *IndexerQueryService) sukukMetadataByAddress(ctx context.Context, rows []activityFeedRow) (map[string]models.SukukMetadata, error) {
+	result := make(map[string]models.SukukMetadata)
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, row := range rows {
+		address := strings.ToLower(row.SukukAddress)
+		if !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+
+	var metadata []models.SukukMetadata
+	err := s.read(ctx, func(db *gorm.DB) error {
+		return db.Where("LOWER(contract_address) IN ?", addresses).Find(&metadata).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sukuk metadata: %w", err)
+	}
+	for _, sukuk := range metadata {
+		result[strings.ToLower(sukuk.ContractAddress)] = sukuk
+	}
+	return result, nil
+}
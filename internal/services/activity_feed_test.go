@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+	"sukuk-be/internal/utils"
+)
+
+func TestActivityCursorRoundTrip(t *testing.T) {
+	cursor := ActivityCursor{Timestamp: 1700000000, ID: "0xabc-3"}
+	parsed, err := ParseActivityCursor(cursor.Encode())
+	if err != nil || *parsed != cursor {
+		t.Fatalf("Expected %+v to round trip, got %+v (%v)", cursor, parsed, err)
+	}
+
+	for _, raw := range []string{"not base64!", "MTcwMDAwMDAwMA", "eHx5", "MTIzfA"} {
+		if _, err := ParseActivityCursor(raw); !errors.Is(err, ErrInvalidActivityCursor) {
+			t.Errorf("Expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
+
+// TestActivityFeedOrdersAcrossTypes requires a reachable Postgres, see testutil.Open
+func TestActivityFeedOrdersAcrossTypes(t *testing.T) {
+	db := testutil.DB(t)
+
+	// Stand-in indexer tables, pinned with overrides so discovery can't pick real ones
+	previous, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range []string{"sukuk_purchase", "redemption_request", "yield_claim", "yield_distributed"} {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range previous {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range []string{"sukuk_purchase", "redemption_request", "yield_claim", "yield_distributed"} {
+		table := "fe01__" + eventType
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, buyer TEXT, "user" TEXT, sukuk_address TEXT, payment_token TEXT, distribution_id BIGINT,
+			amount NUMERIC(78,0), total_supply NUMERIC(78,0), block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+		if err := models.SetIndexerTableOverride(db, eventType, table); err != nil {
+			t.Fatalf("Failed to pin %s: %v", table, err)
+		}
+	}
+
+	const sukuk = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	const holder = "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"
+	const token = "0x00000000000000000000000000000000000000cc"
+	seed := func(eventType, id string, timestamp int64) {
+		err := db.Exec(`INSERT INTO fe01__`+eventType+` (id, buyer, "user", sukuk_address, payment_token, distribution_id, amount, block_number, tx_hash, timestamp)
+			VALUES (?, ?, ?, ?, ?, 1, 1000, ?, ?, ?)`, id, holder, holder, sukuk, token, timestamp, fmt.Sprintf("0x%064x", timestamp), timestamp).Error
+		if err != nil {
+			t.Fatalf("Failed to seed %s %s: %v", eventType, id, err)
+		}
+	}
+	seed("sukuk_purchase", "p1", 100)
+	seed("redemption_request", "r1", 200)
+	seed("yield_distributed", "d1", 220)
+	seed("yield_claim", "c1", 250)
+	seed("sukuk_purchase", "p2", 300)
+	seed("redemption_request", "r2", 300) // Same second as p2; the id breaks the tie
+	db.Exec("UPDATE fe01__yield_claim SET payment_token = NULL")
+
+	service := &IndexerQueryService{indexerDB: db, tableService: &IndexerTableService{indexerDB: db}}
+	formatter := NewTokenFormatter([]models.PaymentToken{{Address: token, Symbol: "IDRX", Decimals: 2}})
+	ctx := context.Background()
+	expected := []string{"r2", "p2", "c1", "r1", "p1"}
+
+	// Pages of two walk the global order without gaps or repeats
+	var ids []string
+	var after *ActivityCursor
+	for page := 0; page < 5; page++ {
+		items, next, err := service.GetActivityFeed(ctx, ActivityFeedFilter{After: after, Limit: 2}, formatter)
+		if err != nil {
+			t.Fatalf("Failed to read page %d: %v", page, err)
+		}
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if next == nil {
+			break
+		}
+		// Cursors survive the round trip through the query string
+		if after, err = ParseActivityCursor(next.Encode()); err != nil {
+			t.Fatalf("Failed to parse cursor: %v", err)
+		}
+	}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("Expected activities %v across pages, got %v", expected, ids)
+	}
+
+	// Yield claims only, with the payment token of the claimed distribution
+	items, next, err := service.GetActivityFeed(ctx, ActivityFeedFilter{Type: models.ActivityTypeYieldClaim, Limit: 10}, formatter)
+	if err != nil || len(items) != 1 || next != nil {
+		t.Fatalf("Expected one yield claim on a single page, got %+v, %v (%v)", items, next, err)
+	}
+	claim := items[0]
+	if claim.Address != utils.ChecksumAddress(holder) || claim.SukukAddress != "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed" {
+		t.Errorf("Expected checksummed addresses, got %s and %s", claim.Address, claim.SukukAddress)
+	}
+	if claim.AmountFormatted.Formatted != "10" || claim.AmountFormatted.Symbol != "IDRX" {
+		t.Errorf("Expected 1000 to format as 10 IDRX, got %+v", claim.AmountFormatted)
+	}
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultActivityProjectionBatchSize bounds the events copied per query
+const DefaultActivityProjectionBatchSize = 500
+
+// activityProjectionCursorKeyPrefix namespaces, per indexer event type, the position of the
+// last event the projector copied
+const activityProjectionCursorKeyPrefix = "activity_projection_cursor:"
+
+// activityProjectionReads routes activity reads to the projection; off, they query the
+// indexer tables directly
+var activityProjectionReads atomic.Bool
+
+// SetActivityProjectionReads switches activity reads between the projection and the indexer
+// tables, returning the previous setting
+func SetActivityProjectionReads(enabled bool) bool {
+	return activityProjectionReads.Swap(enabled)
+}
+
+// ActivityProjectionReads reports whether activity reads are served from the projection
+func ActivityProjectionReads() bool {
+	return activityProjectionReads.Load()
+}
+
+// activityProjectionPosition is the (block_number, id) of an indexer event, the order events
+// are copied in
+type activityProjectionPosition struct {
+	Block int64
+	ID    string
+}
+
+func (p activityProjectionPosition) after(other activityProjectionPosition) bool {
+	return p.Block > other.Block || (p.Block == other.Block && p.ID > other.ID)
+}
+
+func (p activityProjectionPosition) String() string {
+	return strconv.FormatInt(p.Block, 10) + "|" + p.ID
+}
+
+// parseActivityProjectionPosition reads a position saved by String
+func parseActivityProjectionPosition(value string) (activityProjectionPosition, error) {
+	block, id, ok := strings.Cut(value, "|")
+	if !ok {
+		return activityProjectionPosition{}, fmt.Errorf("invalid activity projection cursor %q", value)
+	}
+	number, err := strconv.ParseInt(block, 10, 64)
+	if err != nil {
+		return activityProjectionPosition{}, fmt.Errorf("invalid activity projection cursor %q", value)
+	}
+	return activityProjectionPosition{Block: number, ID: id}, nil
+}
+
+// activityProjectionRow is an indexer event as selected by activityProjectionQuery
+type activityProjectionRow struct {
+	EventID      string
+	Address      string
+	SukukAddress string
+	PaymentToken string
+	Amount       string
+	TxHash       string
+	LogIndex     int64
+	BlockNumber  int64
+	Timestamp    int64
+}
+
+// ActivityProjectionResult reports a projector run
+type ActivityProjectionResult struct {
+	Projected map[models.ActivityType]int `json:"projected"` // Events copied for the first time, by type
+}
+
+// ActivityProjector copies purchases, redemption requests and yield claims from the indexer
+// tables into activity_projections, resuming each event type after the last event it copied.
+// Every run re-reads the last rewindBlocks blocks, so events a reorg moved to another block are
+// updated in place; events it dropped are orphaned by the reorg reconciler
+type ActivityProjector struct {
+	db           *gorm.DB
+	tableService *IndexerTableService
+	batchSize    int
+	rewindBlocks int64
+	interval     time.Duration
+	lock         *SyncLock // Keeps replicas from projecting at once; nil when not shared
+	cancel       context.CancelFunc
+	done         chan struct{} // Closed when the projector loop exits
+}
+
+// NewActivityProjector creates a projector on db, which also holds the indexer tables
+func NewActivityProjector(db *gorm.DB, batchSize int, rewindBlocks int64, interval time.Duration) *ActivityProjector {
+	if batchSize <= 0 {
+		batchSize = DefaultActivityProjectionBatchSize
+	}
+	return &ActivityProjector{
+		db:           db,
+		tableService: &IndexerTableService{indexerDB: db},
+		batchSize:    batchSize,
+		rewindBlocks: rewindBlocks,
+		interval:     interval,
+	}
+}
+
+// NewDefaultActivityProjector creates a projector on the application database
+func NewDefaultActivityProjector(batchSize int, rewindBlocks int64, interval time.Duration) *ActivityProjector {
+	return NewActivityProjector(database.GetDB(), batchSize, rewindBlocks, interval)
+}
+
+// SetSyncLock makes every scheduled run take lock first, skipping it while another
+// instance holds it
+func (p *ActivityProjector) SetSyncLock(lock *SyncLock) {
+	p.lock = lock
+}
+
+// Run copies the events indexed since the last run, batch by batch, until every event type
+// is caught up. Event types the indexer has no table for yet are skipped
+func (p *ActivityProjector) Run(ctx context.Context) (*ActivityProjectionResult, error) {
+	tables, err := p.tableService.GetAllLatestTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find indexer tables: %w", err)
+	}
+
+	result := &ActivityProjectionResult{Projected: make(map[models.ActivityType]int)}
+	for _, info := range models.EventActivityTypes() {
+		table, ok := tables[info.EventTable]
+		if !ok {
+			continue
+		}
+		projected, err := p.project(ctx, info, table, tables["yield_distributed"])
+		if err != nil {
+			return result, fmt.Errorf("failed to project %s: %w", info.EventTable, err)
+		}
+		result.Projected[info.Type] = projected
+	}
+	return result, nil
+}
+
+// project copies the events of one type from its indexer table, returning how many were
+// copied for the first time
+func (p *ActivityProjector) project(ctx context.Context, info models.ActivityTypeInfo, table, distributionTable string) (int, error) {
+	saved, err := p.readCursor(ctx, info.EventTable)
+	if err != nil {
+		return 0, err
+	}
+	start := saved
+	position := activityProjectionPosition{Block: saved.Block - p.rewindBlocks}
+	query := activityProjectionQuery(info.Type, table, distributionTable)
+
+	projected := 0
+	for {
+		var rows []activityProjectionRow
+		err := DefaultIndexerExecutor().Do(ctx, func() error {
+			return p.db.WithContext(ctx).Raw(query, position.Block, position.ID, p.batchSize).Scan(&rows).Error
+		})
+		if err != nil {
+			return projected, err
+		}
+		if len(rows) == 0 {
+			return projected, nil
+		}
+
+		projections := make([]models.ActivityProjection, len(rows))
+		for i, row := range rows {
+			projections[i] = models.ActivityProjection{
+				Type:         info.Type,
+				EventID:      row.EventID,
+				Address:      row.Address,
+				SukukAddress: row.SukukAddress,
+				PaymentToken: row.PaymentToken,
+				Amount:       models.BigNumeric(row.Amount),
+				TxHash:       row.TxHash,
+				LogIndex:     uint(row.LogIndex),
+				BlockNumber:  row.BlockNumber,
+				Timestamp:    row.Timestamp,
+				Status:       models.EventStatusConfirmed,
+			}
+			if (activityProjectionPosition{Block: row.BlockNumber, ID: row.EventID}).after(start) {
+				projected++
+			}
+		}
+		// An event read again is canonical, so a row orphaned by an earlier reorg is confirmed again
+		err = p.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type", "event_id", "address", "sukuk_address", "payment_token", "amount", "block_number", "timestamp", "status"}),
+		}).Create(&projections).Error
+		if err != nil {
+			return projected, fmt.Errorf("failed to save projected activities: %w", err)
+		}
+
+		last := rows[len(rows)-1]
+		position = activityProjectionPosition{Block: last.BlockNumber, ID: last.EventID}
+		// The rewound blocks were copied before, so the cursor only ever moves forward
+		if position.after(saved) {
+			if err := models.SetSystemState(p.db.WithContext(ctx), activityProjectionCursorKeyPrefix+info.EventTable, position.String()); err != nil {
+				return projected, fmt.Errorf("failed to save the activity projection cursor: %w", err)
+			}
+			saved = position
+		}
+		if len(rows) < p.batchSize {
+			return projected, nil
+		}
+	}
+}
+
+// readCursor returns the position of the last event of eventType copied, before the first
+// block when none was
+func (p *ActivityProjector) readCursor(ctx context.Context, eventType string) (activityProjectionPosition, error) {
+	state, err := models.GetSystemState(p.db.WithContext(ctx), activityProjectionCursorKeyPrefix+eventType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return activityProjectionPosition{Block: -1}, nil
+	}
+	if err != nil {
+		return activityProjectionPosition{}, err
+	}
+	return parseActivityProjectionPosition(state.Value)
+}
+
+// Reset empties the projection and its cursors, so the next run copies every event again
+func (p *ActivityProjector) Reset(ctx context.Context) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("key LIKE ?", activityProjectionCursorKeyPrefix+"%").Delete(&models.SystemState{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.ActivityProjection{}).Error
+	})
+}
+
+// activityProjectionQuery selects the events of an indexer table after a (block_number, id)
+// position, in that order. Yield claims take their payment token from the distribution they
+// claim, when it is indexed
+func activityProjectionQuery(activityType models.ActivityType, table, distributionTable string) string {
+	paymentToken, join := "e.payment_token", ""
+	if activityType == models.ActivityTypeYieldClaim {
+		paymentToken = "''"
+		if distributionTable != "" {
+			paymentToken = "COALESCE(d.payment_token, '')"
+			join = fmt.Sprintf("LEFT JOIN %s d ON d.sukuk_address = e.sukuk_address AND d.distribution_id = e.distribution_id", quoteIdentifier(distributionTable))
+		}
+	}
+
+	return fmt.Sprintf(`
+		SELECT e.id AS event_id, LOWER(e.%s) AS address, LOWER(e.sukuk_address) AS sukuk_address,
+		       LOWER(%s) AS payment_token, e.amount::text AS amount, LOWER(e.tx_hash) AS tx_hash,
+		       `+indexerLogIndex+` AS log_index, e.block_number, e.timestamp
+		FROM %s e %s
+		WHERE (e.block_number, e.id) > (?, ?)
+		ORDER BY e.block_number ASC, e.id ASC
+		LIMIT ?`, activityFeedAddressColumns[activityType], paymentToken, quoteIdentifier(table), join)
+}
+
+// Start projects every interval, once right away; a zero interval disables it
+func (p *ActivityProjector) Start(ctx context.Context) {
+	if p.interval <= 0 {
+		logger.Info("Activity projector disabled")
+		return
+	}
+	logger.Info("Starting activity projector")
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+	go p.loop(ctx)
+}
+
+// Stop stops the projector and waits for the running run to return
+func (p *ActivityProjector) Stop() {
+	if p.cancel != nil {
+		logger.Info("Stopping activity projector")
+		p.cancel()
+		<-p.done
+	}
+}
+
+func (p *ActivityProjector) loop(ctx context.Context) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.scheduledRun(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			p.scheduledRun(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scheduledRun runs the projector under the sync lock
+func (p *ActivityProjector) scheduledRun(ctx context.Context) {
+	if p.lock != nil {
+		lease, err := p.lock.TryAcquire(ctx)
+		if errors.Is(err, ErrSyncLockHeld) {
+			return // Logged when the lock was tried
+		}
+		if err != nil {
+			logger.WithError(err).Error("Activity projection skipped")
+			return
+		}
+		defer lease.Release()
+	}
+	result, err := p.Run(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("Activity projection failed")
+		}
+		return
+	}
+	for activityType, projected := range result.Projected {
+		if projected > 0 {
+			logger.WithFields(map[string]interface{}{
+				"type":      activityType,
+				"projected": projected,
+			}).Debug("Projected new activities")
+		}
+	}
+}
+
+// projectedActivities reads the newest purchases and redemption requests whose column
+// matches value from the projection, leaving out orphaned ones
+func (s *IndexerQueryService) projectedActivities(ctx context.Context, column, value string, limit int) ([]models.ActivityEvent, error) {
+	var rows []models.ActivityProjection
+	err := s.read(ctx, func(db *gorm.DB) error {
+		return db.Where(column+" = ?", strings.ToLower(value)).
+			Where("type IN ? AND status <> ?", projectedEventTypes, models.EventStatusOrphaned).
+			Order("timestamp DESC, event_id DESC").
+			Limit(limit).
+			Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projected activities: %w", err)
+	}
+	return projectionsToActivities(rows), nil
+}
+
+// projectedActivitiesBySukuk reads the newest limit purchases and redemption requests of
+// each sukuk from the projection
+func (s *IndexerQueryService) projectedActivitiesBySukuk(ctx context.Context, sukukAddresses []string, limit int) ([]models.ActivityEvent, error) {
+	addresses := make([]string, len(sukukAddresses))
+	for i, address := range sukukAddresses {
+		addresses[i] = strings.ToLower(address)
+	}
+
+	var rows []models.ActivityProjection
+	err := s.read(ctx, func(db *gorm.DB) error {
+		ranked := db.Model(&models.ActivityProjection{}).
+			Select("*, ROW_NUMBER() OVER (PARTITION BY sukuk_address ORDER BY timestamp DESC, event_id DESC) AS rn").
+			Where("sukuk_address IN ? AND type IN ? AND status <> ?", addresses, projectedEventTypes, models.EventStatusOrphaned)
+		return db.Table("(?) AS ranked", ranked).Where("rn <= ?", limit).Find(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projected activities: %w", err)
+	}
+	return projectionsToActivities(rows), nil
+}
+
+// projectedEventTypes are the activity types listed per sukuk and per wallet
+var projectedEventTypes = []models.ActivityType{models.ActivityTypePurchase, models.ActivityTypeRedemptionRequest}
+
+func projectionsToActivities(rows []models.ActivityProjection) []models.ActivityEvent {
+	activities := make([]models.ActivityEvent, len(rows))
+	for i, row := range rows {
+		activities[i] = models.ActivityEvent{
+			Type:         row.Type,
+			Address:      row.Address,
+			Amount:       string(row.Amount),
+			TxHash:       row.TxHash,
+			Timestamp:    time.Unix(row.Timestamp, 0),
+			SukukAddress: row.SukukAddress,
+		}
+	}
+	return activities
+}
+
+// projectedActivityFeed reads a page of the platform-wide activity feed from the projection,
+// in the order and with the ids of the indexer query, plus one row to tell whether another
+// page follows
+func (s *IndexerQueryService) projectedActivityFeed(ctx context.Context, filter ActivityFeedFilter) ([]activityFeedRow, error) {
+	var rows []activityFeedRow
+	err := s.read(ctx, func(db *gorm.DB) error {
+		query := db.Model(&models.ActivityProjection{}).
+			Select("type, event_id AS id, address, sukuk_address, payment_token, amount::text AS amount, tx_hash, timestamp, block_number").
+			Where("status <> ?", models.EventStatusOrphaned)
+		if filter.Type != "" {
+			query = query.Where("type = ?", filter.Type)
+		}
+		if filter.After != nil {
+			query = query.Where("(timestamp, event_id) < (?, ?)", filter.After.Timestamp, filter.After.ID)
+		}
+		return query.Order("timestamp DESC, event_id DESC").Limit(filter.Limit + 1).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity feed: %w", err)
+	}
+	return rows, nil
+}
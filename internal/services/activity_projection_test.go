@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestActivityProjectionPosition(t *testing.T) {
+	position := activityProjectionPosition{Block: 120, ID: "0xabc-3"}
+	parsed, err := parseActivityProjectionPosition(position.String())
+	if err != nil || parsed != position {
+		t.Fatalf("Expected %+v to round trip, got %+v (%v)", position, parsed, err)
+	}
+	for _, value := range []string{"", "120", "abc|0xabc-3"} {
+		if _, err := parseActivityProjectionPosition(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+
+	if !(activityProjectionPosition{Block: 121}).after(position) {
+		t.Error("Expected a later block to come after")
+	}
+	if !(activityProjectionPosition{Block: 120, ID: "0xabc-4"}).after(position) {
+		t.Error("Expected a later id in the same block to come after")
+	}
+	if position.after(position) || (activityProjectionPosition{Block: 119, ID: "0xfff-9"}).after(position) {
+		t.Error("Expected the same or an earlier position not to come after")
+	}
+}
+
+func TestActivityProjectionQuery(t *testing.T) {
+	purchases := activityProjectionQuery(models.ActivityTypePurchase, "5eed__sukuk_purchase", "5eed__yield_distributed")
+	if !strings.Contains(purchases, "LOWER(e.buyer) AS address") || !strings.Contains(purchases, "LOWER(e.payment_token)") {
+		t.Errorf("Expected purchases read with their buyer and payment token, got %s", purchases)
+	}
+	if strings.Contains(purchases, "JOIN") {
+		t.Errorf("Expected no distribution join for purchases, got %s", purchases)
+	}
+
+	claims := activityProjectionQuery(models.ActivityTypeYieldClaim, "5eed__yield_claim", "5eed__yield_distributed")
+	if !strings.Contains(claims, `LOWER(e."user") AS address`) || !strings.Contains(claims, `LEFT JOIN "5eed__yield_distributed" d`) {
+		t.Errorf("Expected claims joined to their distribution, got %s", claims)
+	}
+	unindexed := activityProjectionQuery(models.ActivityTypeYieldClaim, "5eed__yield_claim", "")
+	if strings.Contains(unindexed, "JOIN") || !strings.Contains(unindexed, "LOWER('') AS payment_token") {
+		t.Errorf("Expected claims without a payment token when distributions aren't indexed, got %s", unindexed)
+	}
+}
+
+// TestActivityProjectionMatchesIndexerQueries requires a reachable Postgres, see testutil.Open
+func TestActivityProjectionMatchesIndexerQueries(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	// Stand-in indexer tables, pinned with overrides so discovery can't pick real ones
+	eventTypes := []string{"sukuk_purchase", "redemption_request", "yield_claim", "yield_distributed"}
+	previous, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range eventTypes {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range previous {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range eventTypes {
+		table := "ap01__" + eventType
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, buyer TEXT, "user" TEXT, sukuk_address TEXT, payment_token TEXT, distribution_id BIGINT,
+			amount NUMERIC(78,0), total_supply NUMERIC(78,0), block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+		if err := models.SetIndexerTableOverride(db, eventType, table); err != nil {
+			t.Fatalf("Failed to pin %s: %v", table, err)
+		}
+	}
+
+	projector := NewActivityProjector(db, 2, 5, 0) // Small batches so a run takes several
+	if err := projector.Reset(ctx); err != nil {
+		t.Fatalf("Failed to reset the projection: %v", err)
+	}
+	defer projector.Reset(ctx)
+
+	const sukukA = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	const sukukB = "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"
+	const alice = "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"
+	const bob = "0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb"
+	const token = "0x00000000000000000000000000000000000000cc"
+	seed := func(eventType, wallet, sukuk string, block int64) {
+		txHash := fmt.Sprintf("0x%064x", block)
+		err := db.Exec(`INSERT INTO ap01__`+eventType+` (id, buyer, "user", sukuk_address, payment_token, distribution_id, amount, block_number, tx_hash, timestamp)
+			VALUES (?, ?, ?, ?, ?, 1, ?, ?, ?, ?)`, txHash+"-1", wallet, wallet, sukuk, token, block*100, block, txHash, 1700000000+block).Error
+		if err != nil {
+			t.Fatalf("Failed to seed %s at block %d: %v", eventType, block, err)
+		}
+	}
+	seed("sukuk_purchase", alice, sukukA, 10)
+	seed("sukuk_purchase", bob, sukukA, 11)
+	seed("redemption_request", alice, sukukA, 12)
+	seed("yield_distributed", alice, sukukA, 13)
+	seed("yield_claim", alice, sukukA, 14)
+	seed("sukuk_purchase", alice, sukukB, 15)
+	seed("redemption_request", bob, sukukB, 16)
+	seed("sukuk_purchase", bob, sukukA, 17)
+
+	result, err := projector.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to project: %v", err)
+	}
+	if result.Projected[models.ActivityTypePurchase] != 4 || result.Projected[models.ActivityTypeRedemptionRequest] != 2 || result.Projected[models.ActivityTypeYieldClaim] != 1 {
+		t.Errorf("Expected 4 purchases, 2 redemption requests and 1 claim projected, got %v", result.Projected)
+	}
+
+	indexed := &IndexerQueryService{indexerDB: db, tableService: &IndexerTableService{indexerDB: db}}
+	projected := &IndexerQueryService{indexerDB: db, tableService: &IndexerTableService{indexerDB: db}, projection: true}
+	compare := func(name string, read func(s *IndexerQueryService) (interface{}, error)) {
+		t.Helper()
+		want, err := read(indexed)
+		if err != nil {
+			t.Fatalf("%s: failed to query the indexer: %v", name, err)
+		}
+		got, err := read(projected)
+		if err != nil {
+			t.Fatalf("%s: failed to query the projection: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected the projection to match the indexer\n got: %+v\nwant: %+v", name, got, want)
+		}
+	}
+	assertMatches := func() {
+		t.Helper()
+		for _, limit := range []int{2, 10} {
+			compare(fmt.Sprintf("latest of sukuk A, limit %d", limit), func(s *IndexerQueryService) (interface{}, error) {
+				return s.GetLatestActivities(ctx, sukukA, limit)
+			})
+			compare(fmt.Sprintf("latest by sukuk, limit %d", limit), func(s *IndexerQueryService) (interface{}, error) {
+				return s.GetLatestActivitiesBySukuk(ctx, []string{sukukA, sukukB}, limit)
+			})
+			for _, wallet := range []string{alice, bob} {
+				compare(fmt.Sprintf("activities of %s, limit %d", wallet, limit), func(s *IndexerQueryService) (interface{}, error) {
+					return s.GetActivitiesByAddress(ctx, wallet, limit)
+				})
+			}
+		}
+
+		formatter := NewTokenFormatter([]models.PaymentToken{{Address: token, Symbol: "IDRX", Decimals: 2}})
+		for _, activityType := range []models.ActivityType{"", models.ActivityTypeYieldClaim} {
+			compare(fmt.Sprintf("feed of %q in pages of 3", activityType), func(s *IndexerQueryService) (interface{}, error) {
+				var pages [][]models.ActivityFeedItem
+				var after *ActivityCursor
+				for {
+					items, next, err := s.GetActivityFeed(ctx, ActivityFeedFilter{Type: activityType, After: after, Limit: 3}, formatter)
+					if err != nil {
+						return nil, err
+					}
+					pages = append(pages, items)
+					if next == nil {
+						return pages, nil
+					}
+					after = next
+				}
+			})
+		}
+	}
+	assertMatches()
+
+	// Later runs copy only what was indexed since, and the cursor never moves back
+	seed("redemption_request", alice, sukukA, 18)
+	result, err = projector.Run(ctx)
+	if err != nil {
+		t.Fatalf("Failed to project again: %v", err)
+	}
+	if result.Projected[models.ActivityTypeRedemptionRequest] != 1 || result.Projected[models.ActivityTypePurchase] != 0 {
+		t.Errorf("Expected only the new redemption request projected, got %v", result.Projected)
+	}
+	cursor, err := projector.readCursor(ctx, "redemption_request")
+	if err != nil || cursor.Block != 18 {
+		t.Errorf("Expected the redemption request cursor at block 18, got %+v (%v)", cursor, err)
+	}
+	assertMatches()
+
+	// A transaction a reorg dropped disappears from the canonical table; the reconciler
+	// orphans its projection, which reads leave out
+	dropped := fmt.Sprintf("0x%064x", 17)
+	db.Exec("DELETE FROM ap01__sukuk_purchase WHERE tx_hash = ?", dropped)
+	db.Model(&models.ActivityProjection{}).Where("tx_hash = ?", dropped).Update("status", models.EventStatusOrphaned)
+	if _, err := projector.Run(ctx); err != nil {
+		t.Fatalf("Failed to project after the reorg: %v", err)
+	}
+	assertMatches()
+
+	// Re-mined in a later block within the rewind window, it is confirmed again in place
+	db.Exec(`INSERT INTO ap01__sukuk_purchase (id, buyer, sukuk_address, payment_token, amount, block_number, tx_hash, timestamp)
+		VALUES (?, ?, ?, ?, 1700, 19, ?, 1700000019)`, dropped+"-1", bob, sukukA, token, dropped)
+	if _, err := projector.Run(ctx); err != nil {
+		t.Fatalf("Failed to project the re-mined purchase: %v", err)
+	}
+	var remined models.ActivityProjection
+	if err := db.Where("tx_hash = ?", dropped).First(&remined).Error; err != nil {
+		t.Fatalf("Failed to load the re-mined purchase: %v", err)
+	}
+	if remined.Status != models.EventStatusConfirmed || remined.BlockNumber != 19 {
+		t.Errorf("Expected the re-mined purchase confirmed at block 19, got %s at %d", remined.Status, remined.BlockNumber)
+	}
+	assertMatches()
+}
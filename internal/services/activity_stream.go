@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+)
+
+// activityBatchSize bounds how many new activities are fetched per poll
+const activityBatchSize = 200
+
+// ActivityPublisher receives activities as they are picked up from the indexer
+type ActivityPublisher interface {
+	Publish(activity models.ActivityEvent) uint64
+}
+
+// activitySource is the indexer query used by the activity stream service
+type activitySource interface {
+	GetActivitiesSince(ctx context.Context, since int64, limit int) ([]models.ActivityEvent, error)
+}
+
+// ActivityStreamService polls the indexer for new purchases and redemption
+// requests and publishes each one once, in timestamp order
+type ActivityStreamService struct {
+	source       activitySource
+	publisher    ActivityPublisher
+	pollInterval time.Duration
+	cancel       context.CancelFunc
+	head         indexerHeadReader  // Indexer head for the stall check
+	health       *SyncHealthMonitor // Nil when not monitored
+
+	// cursor is the newest timestamp published so far; seen holds the keys of
+	// activities at that timestamp so the inclusive re-query doesn't repeat them
+	cursor int64
+	seen   map[string]struct{}
+}
+
+// NewActivityStreamService creates a service publishing indexer activities to publisher
+func NewActivityStreamService(publisher ActivityPublisher, pollInterval time.Duration) *ActivityStreamService {
+	source := NewIndexerQueryService()
+	return &ActivityStreamService{
+		source:       source,
+		head:         source,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		seen:         make(map[string]struct{}),
+	}
+}
+
+// Start begins polling; only activities indexed from now on are published
+func (s *ActivityStreamService) Start(ctx context.Context) {
+	logger.Info("Starting activity stream service")
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.cursor = time.Now().Unix()
+
+	go s.pollLoop(ctx)
+}
+
+// Stop stops polling
+func (s *ActivityStreamService) Stop() {
+	logger.Info("Stopping activity stream service")
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+func (s *ActivityStreamService) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.poll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll fetches activities at or after the cursor and publishes the unseen ones
+func (s *ActivityStreamService) poll(ctx context.Context) {
+	activities, err := s.source.GetActivitiesSince(ctx, s.cursor, activityBatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("Failed to poll indexer for new activities")
+			s.recordHealth(ctx, 0, err)
+		}
+		return
+	}
+
+	published := s.publishNew(activities)
+	if published > 0 {
+		logger.WithField("count", published).Debug("Published new activities")
+	}
+	s.recordHealth(ctx, published, nil)
+}
+
+// publishNew publishes activities not seen before and advances the cursor.
+// activities must be sorted by timestamp ascending.
+func (s *ActivityStreamService) publishNew(activities []models.ActivityEvent) int {
+	published := 0
+	for _, activity := range activities {
+		ts := activity.Timestamp.Unix()
+		if ts < s.cursor {
+			continue
+		}
+		if ts > s.cursor {
+			s.cursor = ts
+			s.seen = make(map[string]struct{})
+		}
+
+		key := activityKey(activity)
+		if _, ok := s.seen[key]; ok {
+			continue
+		}
+		s.seen[key] = struct{}{}
+
+		s.publisher.Publish(activity)
+		published++
+	}
+	return published
+}
+
+// activityKey identifies an activity; the indexer row id isn't carried on ActivityEvent
+func activityKey(activity models.ActivityEvent) string {
+	return string(activity.Type) + "|" + activity.TxHash + "|" + activity.SukukAddress + "|" + activity.Address + "|" + activity.Amount
+}
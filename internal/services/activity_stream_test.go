@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+type recordingPublisher struct {
+	published []models.ActivityEvent
+}
+
+func (p *recordingPublisher) Publish(activity models.ActivityEvent) uint64 {
+	p.published = append(p.published, activity)
+	return uint64(len(p.published))
+}
+
+type fakeActivitySource struct {
+	activities []models.ActivityEvent
+	since      []int64
+}
+
+func (f *fakeActivitySource) GetActivitiesSince(ctx context.Context, since int64, limit int) ([]models.ActivityEvent, error) {
+	f.since = append(f.since, since)
+	var result []models.ActivityEvent
+	for _, a := range f.activities {
+		if a.Timestamp.Unix() >= since {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func streamActivity(ts int64, tx string) models.ActivityEvent {
+	return models.ActivityEvent{
+		Type:         "purchase",
+		Address:      "0xUser",
+		Amount:       "100",
+		TxHash:       tx,
+		Timestamp:    time.Unix(ts, 0),
+		SukukAddress: "0xSukuk",
+	}
+}
+
+func TestActivityStreamPublishesEachActivityOnce(t *testing.T) {
+	source := &fakeActivitySource{activities: []models.ActivityEvent{
+		streamActivity(90, "0xold"),
+		streamActivity(100, "0x1"),
+		streamActivity(100, "0x2"),
+	}}
+	publisher := &recordingPublisher{}
+	s := &ActivityStreamService{source: source, publisher: publisher, cursor: 100, seen: map[string]struct{}{}}
+
+	s.poll(context.Background())
+	if len(publisher.published) != 2 {
+		t.Fatalf("Expected 2 published activities, got %d", len(publisher.published))
+	}
+
+	// A later activity sharing the cursor timestamp is still picked up, earlier ones are not repeated
+	source.activities = append(source.activities, streamActivity(100, "0x3"), streamActivity(105, "0x4"))
+	s.poll(context.Background())
+
+	if len(publisher.published) != 4 {
+		t.Fatalf("Expected 4 published activities, got %d", len(publisher.published))
+	}
+	if publisher.published[2].TxHash != "0x3" || publisher.published[3].TxHash != "0x4" {
+		t.Errorf("Unexpected publish order: %s, %s", publisher.published[2].TxHash, publisher.published[3].TxHash)
+	}
+	if s.cursor != 105 {
+		t.Errorf("Expected cursor to advance to 105, got %d", s.cursor)
+	}
+
+	s.poll(context.Background())
+	if len(publisher.published) != 4 {
+		t.Errorf("Expected no republish on idle poll, got %d", len(publisher.published))
+	}
+	if got := source.since[len(source.since)-1]; got != 105 {
+		t.Errorf("Expected poll from cursor 105, got %d", got)
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultAPIKeyUsageFlushInterval is how often counters are written when none is configured
+	DefaultAPIKeyUsageFlushInterval = time.Minute
+	// apiKeyUsageDefaultWindow is the usage window returned when from is not given
+	apiKeyUsageDefaultWindow = 24 * time.Hour
+	// apiKeyUsageMaxWindow bounds a usage query to a month of hourly rows
+	apiKeyUsageMaxWindow = 31 * 24 * time.Hour
+	// apiKeyUsageStopTimeout bounds the final flush on shutdown
+	apiKeyUsageStopTimeout = 10 * time.Second
+)
+
+// ErrInvalidUsageWindow is returned for a usage window that is empty, reversed or too long
+var ErrInvalidUsageWindow = errors.New("invalid usage window")
+
+// UsageHour returns the start of the UTC hour containing at, the bucket its usage counts in
+func UsageHour(at time.Time) time.Time {
+	return at.UTC().Truncate(time.Hour)
+}
+
+// APIKeyUsageWindow resolves the hours [from, to) a usage query covers, widened to whole
+// hours: from is rounded down and to up. to defaults to now, so the current hour is included,
+// and from to a day before to
+func APIKeyUsageWindow(from, to *time.Time, now time.Time) (time.Time, time.Time, error) {
+	if to != nil {
+		now = *to
+	}
+	end := UsageHour(now)
+	if !end.Equal(now) {
+		end = end.Add(time.Hour)
+	}
+	start := end.Add(-apiKeyUsageDefaultWindow)
+	if from != nil {
+		start = UsageHour(*from)
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be before to", ErrInvalidUsageWindow)
+	}
+	if end.Sub(start) > apiKeyUsageMaxWindow {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: at most %d days per query", ErrInvalidUsageWindow, int(apiKeyUsageMaxWindow/(24*time.Hour)))
+	}
+	return start, end, nil
+}
+
+type apiKeyUsageBucket struct {
+	keyID string
+	hour  int64 // Unix seconds of the start of the hour
+}
+
+type apiKeyUsageCounters struct {
+	requests atomic.Int64
+	errors   atomic.Int64
+	bytesOut atomic.Int64
+}
+
+// APIKeyUsageRecorder counts requests, errors and response bytes per API key and hour in
+// memory and writes them to api_key_usage on an interval, so the request path never waits
+// on the database. Stop writes whatever is still pending
+type APIKeyUsageRecorder struct {
+	db       *gorm.DB
+	interval time.Duration
+	now      func() time.Time
+
+	// Record holds mu while it increments, so a flush swapping the counters out under the
+	// write lock never misses an increment
+	mu       sync.RWMutex
+	counters map[apiKeyUsageBucket]*apiKeyUsageCounters
+
+	flushMu sync.Mutex // One flush at a time, so a failed flush's counts are merged back in order
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewAPIKeyUsageRecorder creates a recorder flushing to db every interval
+func NewAPIKeyUsageRecorder(db *gorm.DB, interval time.Duration) *APIKeyUsageRecorder {
+	if interval <= 0 {
+		interval = DefaultAPIKeyUsageFlushInterval
+	}
+	return &APIKeyUsageRecorder{
+		db:       db,
+		interval: interval,
+		now:      time.Now,
+		counters: make(map[apiKeyUsageBucket]*apiKeyUsageCounters),
+	}
+}
+
+// NewDefaultAPIKeyUsageRecorder creates a recorder flushing to the application database
+func NewDefaultAPIKeyUsageRecorder(interval time.Duration) *APIKeyUsageRecorder {
+	return NewAPIKeyUsageRecorder(database.GetDB(), interval)
+}
+
+// Record counts one response of an API key. It only takes the write lock to add a new key or hour
+func (r *APIKeyUsageRecorder) Record(keyID string, status, bytesOut int) {
+	bucket := apiKeyUsageBucket{keyID: keyID, hour: UsageHour(r.now()).Unix()}
+
+	r.mu.RLock()
+	if counters, ok := r.counters[bucket]; ok {
+		counters.add(status, bytesOut)
+		r.mu.RUnlock()
+		return
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	counters, ok := r.counters[bucket]
+	if !ok {
+		counters = &apiKeyUsageCounters{}
+		r.counters[bucket] = counters
+	}
+	counters.add(status, bytesOut)
+	r.mu.Unlock()
+}
+
+func (c *apiKeyUsageCounters) add(status, bytesOut int) {
+	c.requests.Add(1)
+	if status >= 400 {
+		c.errors.Add(1)
+	}
+	if bytesOut > 0 {
+		c.bytesOut.Add(int64(bytesOut))
+	}
+}
+
+// pending returns the counted usage not yet flushed
+func (r *APIKeyUsageRecorder) pending() []models.APIKeyUsage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rows := make([]models.APIKeyUsage, 0, len(r.counters))
+	for bucket, counters := range r.counters {
+		rows = append(rows, models.APIKeyUsage{
+			KeyID:    bucket.keyID,
+			Hour:     time.Unix(bucket.hour, 0).UTC(),
+			Requests: counters.requests.Load(),
+			Errors:   counters.errors.Load(),
+			BytesOut: counters.bytesOut.Load(),
+		})
+	}
+	return rows
+}
+
+// InFlight returns the number of key and hour buckets waiting for the next flush
+func (r *APIKeyUsageRecorder) InFlight() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.counters)
+}
+
+// Flush adds the counted usage to api_key_usage. On failure the counts are kept for the
+// next flush
+func (r *APIKeyUsageRecorder) Flush(ctx context.Context) error {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	r.mu.Lock()
+	counters := r.counters
+	r.counters = make(map[apiKeyUsageBucket]*apiKeyUsageCounters)
+	r.mu.Unlock()
+	if len(counters) == 0 {
+		return nil
+	}
+
+	rows := make([]models.APIKeyUsage, 0, len(counters))
+	for bucket, c := range counters {
+		rows = append(rows, models.APIKeyUsage{
+			KeyID:     bucket.keyID,
+			Hour:      time.Unix(bucket.hour, 0).UTC(),
+			Requests:  c.requests.Load(),
+			Errors:    c.errors.Load(),
+			BytesOut:  c.bytesOut.Load(),
+			UpdatedAt: r.now(),
+		})
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key_id"}, {Name: "hour"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"requests":   gorm.Expr("api_key_usage.requests + EXCLUDED.requests"),
+			"errors":     gorm.Expr("api_key_usage.errors + EXCLUDED.errors"),
+			"bytes_out":  gorm.Expr("api_key_usage.bytes_out + EXCLUDED.bytes_out"),
+			"updated_at": gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		r.restore(counters)
+		return fmt.Errorf("failed to flush api key usage: %w", err)
+	}
+	return nil
+}
+
+// restore merges counters a failed flush took back into the live ones
+func (r *APIKeyUsageRecorder) restore(counters map[apiKeyUsageBucket]*apiKeyUsageCounters) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for bucket, c := range counters {
+		live, ok := r.counters[bucket]
+		if !ok {
+			r.counters[bucket] = c
+			continue
+		}
+		live.requests.Add(c.requests.Load())
+		live.errors.Add(c.errors.Load())
+		live.bytesOut.Add(c.bytesOut.Load())
+	}
+}
+
+// Usage returns the hourly usage of a key over [from, to), including counts not yet flushed
+func (r *APIKeyUsageRecorder) Usage(ctx context.Context, keyID string, from, to time.Time) (*models.APIKeyUsageReport, error) {
+	var stored []models.APIKeyUsage
+	err := r.db.WithContext(ctx).
+		Where("key_id = ? AND hour >= ? AND hour < ?", keyID, from, to).
+		Order("hour").
+		Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	return buildAPIKeyUsageReport(keyID, from, to, stored, r.pending()), nil
+}
+
+// buildAPIKeyUsageReport merges stored and pending rows of a key into hours within [from, to)
+func buildAPIKeyUsageReport(keyID string, from, to time.Time, stored, pending []models.APIKeyUsage) *models.APIKeyUsageReport {
+	byHour := make(map[int64]*models.APIKeyUsage)
+	for _, row := range append(stored, pending...) {
+		if row.KeyID != keyID || row.Hour.Before(from) || !row.Hour.Before(to) {
+			continue
+		}
+		hour, ok := byHour[row.Hour.Unix()]
+		if !ok {
+			hour = &models.APIKeyUsage{KeyID: keyID, Hour: row.Hour.UTC()}
+			byHour[row.Hour.Unix()] = hour
+		}
+		hour.Requests += row.Requests
+		hour.Errors += row.Errors
+		hour.BytesOut += row.BytesOut
+	}
+
+	report := &models.APIKeyUsageReport{KeyID: keyID, From: from, To: to, Hours: make([]models.APIKeyUsage, 0, len(byHour))}
+	for _, hour := range byHour {
+		report.Hours = append(report.Hours, *hour)
+		report.Totals.Requests += hour.Requests
+		report.Totals.Errors += hour.Errors
+		report.Totals.BytesOut += hour.BytesOut
+	}
+	sort.Slice(report.Hours, func(i, j int) bool { return report.Hours[i].Hour.Before(report.Hours[j].Hour) })
+	return report
+}
+
+// Start flushes on the interval until Stop
+func (r *APIKeyUsageRecorder) Start(ctx context.Context) {
+	logger.Info("Starting API key usage recorder")
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go r.flushLoop(ctx)
+}
+
+// Stop ends the flush loop and writes the usage still pending
+func (r *APIKeyUsageRecorder) Stop() {
+	if r.cancel != nil {
+		logger.Info("Stopping API key usage recorder")
+		r.cancel()
+		<-r.done
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyUsageStopTimeout)
+	defer cancel()
+	if err := r.Flush(ctx); err != nil {
+		logger.WithError(err).Error("Final API key usage flush failed")
+	}
+}
+
+func (r *APIKeyUsageRecorder) flushLoop(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+				logger.WithError(err).Warn("API key usage flush failed, retrying next interval")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
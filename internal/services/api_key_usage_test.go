@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// usageCaptureDB returns a dry-run database that records the usage rows each flush writes
+// instead of sending them, failing the writes while fail is set
+func usageCaptureDB(t *testing.T, fail *bool) (*gorm.DB, func() []models.APIKeyUsage) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=unused"}), &gorm.Config{DryRun: true, SkipDefaultTransaction: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Failed to open dry-run database: %v", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		written []models.APIKeyUsage
+	)
+	err = db.Callback().Create().After("gorm:create").Register("test:capture_usage", func(tx *gorm.DB) {
+		if fail != nil && *fail {
+			tx.AddError(errors.New("database unavailable"))
+			return
+		}
+		if rows, ok := tx.Statement.Dest.(*[]models.APIKeyUsage); ok {
+			mu.Lock()
+			written = append(written, *rows...)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Failed to register capture callback: %v", err)
+	}
+	return db, func() []models.APIKeyUsage {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]models.APIKeyUsage{}, written...)
+	}
+}
+
+func TestAPIKeyUsageWindow(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 25, 0, 0, time.UTC)
+	at := func(s string) *time.Time {
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &parsed
+	}
+
+	tests := []struct {
+		name      string
+		from, to  *time.Time
+		wantFrom  string
+		wantTo    string
+		wantError bool
+	}{
+		{name: "defaults to the day up to the current hour", wantFrom: "2025-03-09T15:00:00Z", wantTo: "2025-03-10T15:00:00Z"},
+		{name: "rounds from down and to up", from: at("2025-03-10T08:40:00Z"), to: at("2025-03-10T10:05:00Z"), wantFrom: "2025-03-10T08:00:00Z", wantTo: "2025-03-10T11:00:00Z"},
+		{name: "to on an hour boundary is exclusive", from: at("2025-03-10T08:00:00Z"), to: at("2025-03-10T10:00:00Z"), wantFrom: "2025-03-10T08:00:00Z", wantTo: "2025-03-10T10:00:00Z"},
+		{name: "converts offsets to UTC", from: at("2025-03-10T15:30:00+07:00"), to: at("2025-03-10T17:00:00+07:00"), wantFrom: "2025-03-10T08:00:00Z", wantTo: "2025-03-10T10:00:00Z"},
+		{name: "from within the hour of to", from: at("2025-03-10T10:10:00Z"), to: at("2025-03-10T10:20:00Z"), wantFrom: "2025-03-10T10:00:00Z", wantTo: "2025-03-10T11:00:00Z"},
+		{name: "reversed", from: at("2025-03-10T12:00:00Z"), to: at("2025-03-10T10:00:00Z"), wantError: true},
+		{name: "longer than 31 days", from: at("2025-01-01T00:00:00Z"), to: at("2025-03-01T00:00:00Z"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := APIKeyUsageWindow(tt.from, tt.to, now)
+			if tt.wantError {
+				if !errors.Is(err, ErrInvalidUsageWindow) {
+					t.Errorf("Expected ErrInvalidUsageWindow, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := from.Format(time.RFC3339); got != tt.wantFrom {
+				t.Errorf("Expected from %s, got %s", tt.wantFrom, got)
+			}
+			if got := to.Format(time.RFC3339); got != tt.wantTo {
+				t.Errorf("Expected to %s, got %s", tt.wantTo, got)
+			}
+		})
+	}
+}
+
+func TestAPIKeyUsageRecorderAggregatesPerHour(t *testing.T) {
+	db, _ := usageCaptureDB(t, nil)
+	recorder := NewAPIKeyUsageRecorder(db, time.Hour)
+	now := time.Date(2025, 3, 10, 9, 59, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+
+	recorder.Record("aaaaaaaaaaaa", 200, 100)
+	recorder.Record("aaaaaaaaaaaa", 500, 20)
+	recorder.Record("bbbbbbbbbbbb", 200, 7)
+	now = now.Add(2 * time.Minute) // Next hour
+	recorder.Record("aaaaaaaaaaaa", 404, -1)
+
+	from := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	report := buildAPIKeyUsageReport("aaaaaaaaaaaa", from, from.Add(2*time.Hour), nil, recorder.pending())
+	if len(report.Hours) != 2 {
+		t.Fatalf("Expected two hours, got %+v", report.Hours)
+	}
+	first, second := report.Hours[0], report.Hours[1]
+	if !first.Hour.Equal(from) || first.Requests != 2 || first.Errors != 1 || first.BytesOut != 120 {
+		t.Errorf("Expected 2 requests, 1 error and 120 bytes at 09:00, got %+v", first)
+	}
+	if !second.Hour.Equal(from.Add(time.Hour)) || second.Requests != 1 || second.Errors != 1 || second.BytesOut != 0 {
+		t.Errorf("Expected 1 failed request without a body at 10:00, got %+v", second)
+	}
+	if report.Totals != (models.APIKeyUsageTotals{Requests: 3, Errors: 2, BytesOut: 120}) {
+		t.Errorf("Expected totals of both hours, got %+v", report.Totals)
+	}
+
+	// Stored and pending counts of the same hour add up; hours outside the window are dropped
+	stored := []models.APIKeyUsage{
+		{KeyID: "aaaaaaaaaaaa", Hour: from, Requests: 10},
+		{KeyID: "aaaaaaaaaaaa", Hour: from.Add(-time.Hour), Requests: 99},
+	}
+	report = buildAPIKeyUsageReport("aaaaaaaaaaaa", from, from.Add(time.Hour), stored, recorder.pending())
+	if len(report.Hours) != 1 || report.Hours[0].Requests != 12 {
+		t.Errorf("Expected 12 requests at 09:00 only, got %+v", report.Hours)
+	}
+}
+
+func TestAPIKeyUsageRecorderFlushesOnStop(t *testing.T) {
+	db, written := usageCaptureDB(t, nil)
+	recorder := NewAPIKeyUsageRecorder(db, time.Hour) // The interval never elapses during the test
+	recorder.Start(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder.Record("aaaaaaaaaaaa", 200, 10)
+		}()
+	}
+	wg.Wait()
+	recorder.Stop()
+
+	rows := written()
+	if len(rows) != 1 || rows[0].Requests != 50 || rows[0].BytesOut != 500 {
+		t.Fatalf("Expected the final flush to write 50 requests, got %+v", rows)
+	}
+	if pending := recorder.pending(); len(pending) != 0 {
+		t.Errorf("Expected nothing pending after the final flush, got %+v", pending)
+	}
+}
+
+func TestAPIKeyUsageRecorderKeepsCountsOfFailedFlush(t *testing.T) {
+	fail := true
+	db, written := usageCaptureDB(t, &fail)
+	recorder := NewAPIKeyUsageRecorder(db, time.Hour)
+
+	recorder.Record("aaaaaaaaaaaa", 200, 10)
+	if err := recorder.Flush(context.Background()); err == nil {
+		t.Fatal("Expected the flush to fail")
+	}
+	recorder.Record("aaaaaaaaaaaa", 200, 10)
+
+	fail = false
+	if err := recorder.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if rows := written(); len(rows) != 1 || rows[0].Requests != 2 {
+		t.Errorf("Expected the failed flush's request to be written with the next, got %+v", rows)
+	}
+}
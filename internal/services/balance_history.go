@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// BalanceHistoryFilter selects a page of balance changes, optionally within a time range
+type BalanceHistoryFilter struct {
+	From   *int64 // Unix seconds, inclusive
+	To     *int64 // Unix seconds, inclusive
+	Offset int
+	Limit  int
+}
+
+// holderUpdateOrder orders holder_update rows from oldest to newest
+const holderUpdateOrder = "block_number ASC, timestamp ASC, id ASC"
+
+// GetBalanceHistory returns a page of a holder's balance changes on a sukuk, oldest first, and
+// how many changes match the filter. The first change on a page is compared against the balance
+// just before it, even when that row lies on a previous page or before From
+func (s *IndexerQueryService) GetBalanceHistory(ctx context.Context, holder, sukukAddress string, filter BalanceHistoryFilter) ([]models.BalanceChange, int64, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find holder_update table: %w", err)
+	}
+
+	pair := func(db *gorm.DB) *gorm.DB {
+		return db.Table(holderTable).Where("LOWER(holder) = LOWER(?) AND LOWER(sukuk_address) = LOWER(?)", holder, sukukAddress)
+	}
+	inRange := func(db *gorm.DB) *gorm.DB {
+		db = pair(db)
+		if filter.From != nil {
+			db = db.Where("timestamp >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("timestamp <= ?", *filter.To)
+		}
+		return db
+	}
+
+	var total int64
+	var updates []IndexerHolderUpdated
+	err = s.read(ctx, func(db *gorm.DB) error {
+		if err := inRange(db).Count(&total).Error; err != nil {
+			return err
+		}
+		return inRange(db).Order(holderUpdateOrder).Offset(filter.Offset).Limit(filter.Limit).Find(&updates).Error
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query balance history from %s: %w", holderTable, err)
+	}
+	if len(updates) == 0 {
+		return []models.BalanceChange{}, total, nil
+	}
+
+	// The balance before the page is the last row preceding its first one
+	first := updates[0]
+	var previous []IndexerHolderUpdated
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return pair(db).
+			Where("(block_number, timestamp, id) < (?, ?, ?)", first.BlockNumber, first.Timestamp, first.ID).
+			Order("block_number DESC, timestamp DESC, id DESC").
+			Limit(1).
+			Find(&previous).Error
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query previous balance from %s: %w", holderTable, err)
+	}
+	previousBalance := "0"
+	if len(previous) > 0 {
+		previousBalance = previous[0].Balance
+	}
+
+	eventTypes, err := s.balanceChangeEventTypes(ctx, sukukAddress, updates)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	changes, err := BuildBalanceChanges(previousBalance, updates, eventTypes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return changes, total, nil
+}
+
+// balanceChangeEventTypes maps the tx hashes of updates to the activity type of the event on
+// the same sukuk in that transaction. Registry order decides when a tx holds more than one
+func (s *IndexerQueryService) balanceChangeEventTypes(ctx context.Context, sukukAddress string, updates []IndexerHolderUpdated) (map[string]models.ActivityType, error) {
+	txHashes := make([]string, 0, len(updates))
+	for _, update := range updates {
+		txHashes = append(txHashes, update.TxHash)
+	}
+
+	eventTypes := make(map[string]models.ActivityType, len(updates))
+	for _, info := range models.EventActivityTypes() {
+		eventTable, err := s.tableService.GetLatestTableForEvent(info.EventTable)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s table: %w", info.EventTable, err)
+		}
+
+		var matched []string
+		err = s.read(ctx, func(db *gorm.DB) error {
+			return db.Table(eventTable).
+				Where("tx_hash IN ? AND LOWER(sukuk_address) = LOWER(?)", txHashes, sukukAddress).
+				Distinct().
+				Pluck("tx_hash", &matched).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s events from %s: %w", info.EventTable, eventTable, err)
+		}
+		for _, txHash := range matched {
+			if _, ok := eventTypes[txHash]; !ok {
+				eventTypes[txHash] = info.Type
+			}
+		}
+	}
+	return eventTypes, nil
+}
+
+// BuildBalanceChanges turns ordered holder_update rows into balance changes, computing each
+// delta against the balance before it, starting from previousBalance
+func BuildBalanceChanges(previousBalance string, updates []IndexerHolderUpdated, eventTypes map[string]models.ActivityType) ([]models.BalanceChange, error) {
+	mathUtil := utils.NewTokenMath()
+	changes := make([]models.BalanceChange, 0, len(updates))
+	for _, update := range updates {
+		delta, err := mathUtil.DiffTokenAmounts(previousBalance, update.Balance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance in tx %s: %w", update.TxHash, err)
+		}
+
+		eventType := models.BalanceChangeTransfer
+		if activityType, ok := eventTypes[update.TxHash]; ok {
+			eventType = string(activityType)
+		}
+
+		changes = append(changes, models.BalanceChange{
+			Timestamp:   time.Unix(update.Timestamp, 0),
+			NewBalance:  update.Balance,
+			Delta:       delta,
+			EventType:   eventType,
+			TxHash:      update.TxHash,
+			BlockNumber: update.BlockNumber,
+		})
+		previousBalance = update.Balance
+	}
+	return changes, nil
+}
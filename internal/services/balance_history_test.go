@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"sukuk-be/internal/models"
+)
+
+func TestBuildBalanceChangesInterleavesPurchasesAndTransfers(t *testing.T) {
+	updates := []IndexerHolderUpdated{
+		{Balance: "1000", TxHash: "0xp1", BlockNumber: 10, Timestamp: 100}, // purchase
+		{Balance: "600", TxHash: "0xt1", BlockNumber: 11, Timestamp: 110},  // transfer out
+		{Balance: "1600", TxHash: "0xp2", BlockNumber: 12, Timestamp: 120}, // purchase
+		{Balance: "1850", TxHash: "0xt2", BlockNumber: 13, Timestamp: 130}, // transfer in
+		{Balance: "850", TxHash: "0xr1", BlockNumber: 14, Timestamp: 140},  // redemption request
+	}
+	eventTypes := map[string]models.ActivityType{
+		"0xp1": models.ActivityTypePurchase,
+		"0xp2": models.ActivityTypePurchase,
+		"0xr1": models.ActivityTypeRedemptionRequest,
+	}
+
+	changes, err := BuildBalanceChanges("0", updates, eventTypes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct{ delta, eventType string }{
+		{"1000", "purchase"},
+		{"-400", "transfer"},
+		{"1000", "purchase"},
+		{"250", "transfer"},
+		{"-1000", "redemption_request"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %d", len(want), len(changes))
+	}
+	for i, w := range want {
+		if changes[i].Delta != w.delta || changes[i].EventType != w.eventType {
+			t.Errorf("Change %d: expected %s %s, got %s %s", i, w.delta, w.eventType, changes[i].Delta, changes[i].EventType)
+		}
+		if changes[i].NewBalance != updates[i].Balance || changes[i].TxHash != updates[i].TxHash || changes[i].BlockNumber != updates[i].BlockNumber {
+			t.Errorf("Change %d does not match its holder_update: %+v", i, changes[i])
+		}
+	}
+}
+
+func TestBuildBalanceChangesContinuesFromPreviousPage(t *testing.T) {
+	// The second page starts from the last balance of the first
+	changes, err := BuildBalanceChanges("1600", []IndexerHolderUpdated{{Balance: "1850", TxHash: "0xt2"}}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if changes[0].Delta != "250" || changes[0].EventType != models.BalanceChangeTransfer {
+		t.Errorf("Expected a 250 transfer, got %+v", changes[0])
+	}
+
+	if _, err := BuildBalanceChanges("0", []IndexerHolderUpdated{{Balance: "not-a-number"}}, nil); err == nil {
+		t.Error("Expected a malformed balance to be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+// TestCreateSukukPurchaseEventConcurrently requires a reachable Postgres, see testutil.Open
+func TestCreateSukukPurchaseEventConcurrently(t *testing.T) {
+	db := testutil.DB(t)
+
+	const txHash = "0x00000000000000000000000000000000000000000000000000000000000de901"
+	db.Unscoped().Where("tx_hash = ?", txHash).Delete(&models.SukukPurchased{})
+	t.Cleanup(func() {
+		db.Unscoped().Where("tx_hash = ?", txHash).Delete(&models.SukukPurchased{})
+	})
+
+	// Every submission carries the same event, as a replayed webhook or a double-clicked import would
+	timestamp := time.Unix(1700000000, 0)
+	newEvent := func() *models.SukukPurchased {
+		return &models.SukukPurchased{
+			Buyer:        "0x00000000000000000000000000000000000de9b1",
+			SukukAddress: "0x00000000000000000000000000000000000de9a1",
+			PaymentToken: "0x00000000000000000000000000000000000de9c1",
+			Amount:       models.BigNumeric("1000000000000000000"),
+			BlockNumber:  100,
+			TxHash:       txHash,
+			LogIndex:     3,
+			Timestamp:    timestamp,
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = models.CreateSukukPurchaseEvent(db, newEvent())
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, models.ErrDuplicateEvent):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("Expected exactly one submission to be stored, got %d", created)
+	}
+
+	var count int64
+	if err := db.Model(&models.SukukPurchased{}).Where("tx_hash = ? AND log_index = ?", txHash, 3).Count(&count).Error; err != nil {
+		t.Fatalf("Failed to count events: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected one stored purchase event, got %d", count)
+	}
+}
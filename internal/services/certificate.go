@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ErrCertificateZeroBalance is returned when the investor holds none of the sukuk
+var ErrCertificateZeroBalance = errors.New("investor holds no balance of this sukuk")
+
+// CertificateLayout places the text and logo of an investment certificate on the page. Each
+// text is a text/template executed with the models.Certificate, so wording and placement can
+// be changed without a release. Positions are in millimetres from the top left corner
+type CertificateLayout struct {
+	Orientation string            `json:"orientation"` // P (portrait) or L (landscape)
+	PageSize    string            `json:"page_size"`   // e.g. A4, Letter
+	Font        string            `json:"font"`        // One of the core PDF fonts: Helvetica, Times, Courier
+	Logo        *CertificateImage `json:"logo,omitempty"`
+	Texts       []CertificateText `json:"texts"`
+}
+
+// CertificateImage is a PNG or JPEG file drawn on the certificate
+type CertificateImage struct {
+	Path  string  `json:"path"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Width float64 `json:"width"` // Height follows the aspect ratio
+}
+
+// CertificateText is a line of text drawn on the certificate, e.g. "Investor: {{.InvestorAddress}}"
+type CertificateText struct {
+	Template string  `json:"template"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"` // Baseline
+	Size     float64 `json:"size"`
+	Bold     bool    `json:"bold"`
+}
+
+// DefaultCertificateLayout is the layout used when CERTIFICATE_LAYOUT_FILE is not set
+func DefaultCertificateLayout() *CertificateLayout {
+	return &CertificateLayout{
+		Orientation: "L",
+		PageSize:    "A4",
+		Font:        "Helvetica",
+		Texts: []CertificateText{
+			{Template: "Investment Certificate", X: 20, Y: 30, Size: 24, Bold: true},
+			{Template: "{{.SukukCode}} - {{.SukukTitle}}", X: 20, Y: 45, Size: 16, Bold: true},
+			{Template: "Issuer: {{.IssuerAddress}}", X: 20, Y: 60, Size: 11},
+			{Template: "Sukuk contract: {{.SukukAddress}}", X: 20, Y: 68, Size: 11},
+			{Template: "This certifies that the wallet", X: 20, Y: 90, Size: 12},
+			{Template: "{{.InvestorAddress}}", X: 20, Y: 100, Size: 14, Bold: true},
+			{Template: "holds {{amount .Balance}} units of {{.SukukCode}}{{if .SharePercentage}}, {{.SharePercentage}}% of the supply of {{amount .TotalSupply}}{{end}}.", X: 20, Y: 110, Size: 12},
+			{Template: "Issued {{datetime .IssuedAt}} (Asia/Jakarta)", X: 20, Y: 150, Size: 10},
+			{Template: "Verification code: {{.Code}}", X: 20, Y: 158, Size: 10, Bold: true},
+			{Template: "Verify at /api/v1/certificates/verify/{{.Code}}", X: 20, Y: 166, Size: 9},
+		},
+	}
+}
+
+// LoadCertificateLayout reads a JSON layout file, or returns the default layout for an empty path
+func LoadCertificateLayout(path string) (*CertificateLayout, error) {
+	if path == "" {
+		return DefaultCertificateLayout(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate layout: %w", err)
+	}
+	var layout CertificateLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate layout %s: %w", path, err)
+	}
+	if len(layout.Texts) == 0 {
+		return nil, fmt.Errorf("certificate layout %s has no texts", path)
+	}
+	return &layout, nil
+}
+
+// certificateTemplateFuncs are the functions layout texts can call
+var certificateTemplateFuncs = template.FuncMap{
+	"amount": utils.GlobalTokenMath.FormatTokenAmount,
+	"datetime": func(t time.Time) string {
+		return t.In(taxReportLocation).Format("02 Jan 2006 15:04")
+	},
+}
+
+// RenderCertificatePDF writes the certificate as a one-page PDF laid out by layout
+func RenderCertificatePDF(w io.Writer, certificate *models.Certificate, layout *CertificateLayout) error {
+	pdf := gofpdf.New(layout.Orientation, "mm", layout.PageSize, "")
+	pdf.SetTitle(fmt.Sprintf("Investment Certificate %s", certificate.Code), true)
+	pdf.SetCreationDate(certificate.IssuedAt)
+	pdf.AddPage()
+
+	if layout.Logo != nil && layout.Logo.Path != "" {
+		pdf.ImageOptions(layout.Logo.Path, layout.Logo.X, layout.Logo.Y, layout.Logo.Width, 0, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+	}
+
+	for i, text := range layout.Texts {
+		tmpl, err := template.New(fmt.Sprintf("text%d", i)).Funcs(certificateTemplateFuncs).Parse(text.Template)
+		if err != nil {
+			return fmt.Errorf("invalid certificate layout text %d: %w", i, err)
+		}
+		var line bytes.Buffer
+		if err := tmpl.Execute(&line, certificate); err != nil {
+			return fmt.Errorf("failed to render certificate layout text %d: %w", i, err)
+		}
+
+		style := ""
+		if text.Bold {
+			style = "B"
+		}
+		pdf.SetFont(layout.Font, style, text.Size)
+		pdf.Text(text.X, text.Y, line.String())
+	}
+
+	return pdf.Output(w)
+}
+
+// BuildCertificate snapshots an investor's holding of a sukuk, rejecting a zero balance with
+// ErrCertificateZeroBalance. An unknown supply leaves the share percentage empty
+func BuildCertificate(investorAddress string, sukuk *models.SukukMetadata, balance string, supply Supply, issuedAt time.Time) (*models.Certificate, error) {
+	mathUtil := utils.GlobalTokenMath
+	if !mathUtil.IsPositive(balance) {
+		return nil, ErrCertificateZeroBalance
+	}
+
+	certificate := &models.Certificate{
+		InvestorAddress: utils.NormalizeAddress(investorAddress),
+		SukukAddress:    utils.NormalizeAddress(sukuk.ContractAddress),
+		SukukCode:       sukuk.SukukCode,
+		SukukTitle:      sukuk.SukukTitle,
+		IssuerAddress:   utils.NormalizeAddress(sukuk.OwnerAddress),
+		Balance:         balance,
+		TotalSupply:     "0",
+		SupplySource:    models.SupplySourceNone,
+		IssuedAt:        issuedAt,
+	}
+	if mathUtil.IsPositive(supply.Amount) {
+		share, err := mathUtil.FormatPercent(balance, supply.Amount)
+		if err != nil {
+			return nil, err
+		}
+		certificate.TotalSupply = supply.Amount
+		certificate.SupplySource = supply.Source
+		certificate.SharePercentage = share
+	}
+	return certificate, nil
+}
+
+// certificateCodeEncoding writes verification codes without padding or easily confused symbols
+var certificateCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newCertificateCode returns a random verification code, e.g. CERT-MFRGGZDFMZTWQ2LK
+func newCertificateCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "CERT-" + certificateCodeEncoding.EncodeToString(buf), nil
+}
+
+// NormalizeCertificateCode uppercases a code so codes match case-insensitively
+func NormalizeCertificateCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IssueCertificate stores a certificate of the investor's current holding of a sukuk. It
+// returns gorm.ErrRecordNotFound for a sukuk without metadata and ErrCertificateZeroBalance
+// when the investor holds none of it
+func IssueCertificate(ctx context.Context, reader PortfolioReader, investorAddress, sukukAddress string, now time.Time) (*models.Certificate, error) {
+	db := database.GetDB().WithContext(ctx)
+	var sukuk models.SukukMetadata
+	if err := db.Where("LOWER(contract_address) = ?", utils.NormalizeAddress(sukukAddress)).First(&sukuk).Error; err != nil {
+		return nil, err
+	}
+
+	balance, supply, err := reader.GetUserShare(ctx, investorAddress, sukuk.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := BuildCertificate(investorAddress, &sukuk, balance, supply, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if certificate.Code, err = newCertificateCode(); err != nil {
+		return nil, err
+	}
+	if err := db.Create(certificate).Error; err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+// VerifyCertificate looks up a certificate by its verification code, returning
+// gorm.ErrRecordNotFound for an unknown code
+func VerifyCertificate(ctx context.Context, code string) (*models.Certificate, error) {
+	var certificate models.Certificate
+	err := database.GetDB().WithContext(ctx).Where("code = ?", NormalizeCertificateCode(code)).First(&certificate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+// CertificateFilename names the PDF download, e.g. certificate-SR022-T5-CERT-MFRGGZDFMZTWQ2LK.pdf
+func CertificateFilename(certificate *models.Certificate) string {
+	return fmt.Sprintf("certificate-%s-%s.pdf", certificate.SukukCode, certificate.Code)
+}
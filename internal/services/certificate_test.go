@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+var certificateTestSukuk = &models.SukukMetadata{
+	ContractAddress: "0xABCDEF0000000000000000000000000000000001",
+	OwnerAddress:    "0xABCDEF0000000000000000000000000000000002",
+	SukukCode:       "SR022-T5",
+	SukukTitle:      "Sukuk Ritel",
+}
+
+func TestBuildCertificateSnapshotsHolding(t *testing.T) {
+	issuedAt := time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC)
+	certificate, err := BuildCertificate("0xF57093EA18E5CFF6E7BB3BB770AE9C492277A5A9", certificateTestSukuk, "250", Supply{Amount: "10000", Source: models.SupplySourceSnapshot}, issuedAt)
+	if err != nil {
+		t.Fatalf("BuildCertificate returned error: %v", err)
+	}
+
+	if certificate.InvestorAddress != "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9" {
+		t.Errorf("Expected a normalized investor address, got %s", certificate.InvestorAddress)
+	}
+	if certificate.SukukCode != "SR022-T5" || certificate.SukukTitle != "Sukuk Ritel" {
+		t.Errorf("Expected the sukuk code and title, got %+v", certificate)
+	}
+	if certificate.IssuerAddress != "0xabcdef0000000000000000000000000000000002" {
+		t.Errorf("Expected the sukuk owner as issuer, got %s", certificate.IssuerAddress)
+	}
+	if certificate.Balance != "250" || certificate.TotalSupply != "10000" || certificate.SharePercentage != "2.50" {
+		t.Errorf("Expected balance 250 of 10000 (2.50%%), got %s of %s (%s%%)", certificate.Balance, certificate.TotalSupply, certificate.SharePercentage)
+	}
+	if certificate.SupplySource != models.SupplySourceSnapshot {
+		t.Errorf("Expected the supply source to be kept, got %s", certificate.SupplySource)
+	}
+	if !certificate.IssuedAt.Equal(issuedAt) {
+		t.Errorf("Expected issued at %s, got %s", issuedAt, certificate.IssuedAt)
+	}
+}
+
+func TestBuildCertificateRejectsZeroBalance(t *testing.T) {
+	for _, balance := range []string{"0", "", "-5"} {
+		if _, err := BuildCertificate("0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9", certificateTestSukuk, balance, Supply{Amount: "10000", Source: models.SupplySourceSnapshot}, time.Now()); !errors.Is(err, ErrCertificateZeroBalance) {
+			t.Errorf("Expected ErrCertificateZeroBalance for balance %q, got %v", balance, err)
+		}
+	}
+}
+
+func TestBuildCertificateWithoutSupply(t *testing.T) {
+	certificate, err := BuildCertificate("0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9", certificateTestSukuk, "250", Supply{Source: models.SupplySourceNone}, time.Now())
+	if err != nil {
+		t.Fatalf("BuildCertificate returned error: %v", err)
+	}
+	if certificate.TotalSupply != "0" || certificate.SharePercentage != "" || certificate.SupplySource != models.SupplySourceNone {
+		t.Errorf("Expected supply 0 from no source and no share, got %s from %s and %q", certificate.TotalSupply, certificate.SupplySource, certificate.SharePercentage)
+	}
+}
+
+func TestRenderCertificatePDF(t *testing.T) {
+	certificate, err := BuildCertificate("0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9", certificateTestSukuk, "250", Supply{Amount: "10000", Source: models.SupplySourceRedemption}, time.Now())
+	if err != nil {
+		t.Fatalf("BuildCertificate returned error: %v", err)
+	}
+	certificate.Code = "CERT-MFRGGZDFMZTWQ2LK"
+
+	var pdf bytes.Buffer
+	if err := RenderCertificatePDF(&pdf, certificate, DefaultCertificateLayout()); err != nil {
+		t.Fatalf("RenderCertificatePDF returned error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("Expected a PDF, got %q", pdf.Bytes()[:min(pdf.Len(), 16)])
+	}
+
+	layout := DefaultCertificateLayout()
+	layout.Texts = append(layout.Texts, CertificateText{Template: "{{.Missing}}", X: 10, Y: 10, Size: 10})
+	if err := RenderCertificatePDF(&bytes.Buffer{}, certificate, layout); err == nil {
+		t.Error("Expected an error for a text referencing an unknown field")
+	}
+}
+
+func TestLoadCertificateLayout(t *testing.T) {
+	layout, err := LoadCertificateLayout("")
+	if err != nil || len(layout.Texts) == 0 {
+		t.Fatalf("Expected the default layout for an empty path, got %+v, %v", layout, err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "layout.json")
+	custom := `{"orientation":"P","page_size":"A4","font":"Times","texts":[{"template":"{{.Code}}","x":20,"y":20,"size":12}]}`
+	if err := os.WriteFile(path, []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	layout, err = LoadCertificateLayout(path)
+	if err != nil {
+		t.Fatalf("LoadCertificateLayout returned error: %v", err)
+	}
+	if layout.Orientation != "P" || layout.Font != "Times" || len(layout.Texts) != 1 {
+		t.Errorf("Expected the custom layout, got %+v", layout)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if err := os.WriteFile(empty, []byte(`{"texts":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCertificateLayout(empty); err == nil || !strings.Contains(err.Error(), "no texts") {
+		t.Errorf("Expected an error for a layout without texts, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCouponGracePeriod is how far a distribution may land from its scheduled date and
+// still pay that coupon, overridable with the coupon_schedule.grace_period setting
+const DefaultCouponGracePeriod = 7 * 24 * time.Hour
+
+// maxScheduledCoupons caps a calendar, e.g. a monthly sukuk with a mistyped maturity
+const maxScheduledCoupons = 600
+
+// couponFrequencies maps penerimaan_kupon wording to months between coupons. Multi-month
+// terms come first so "3 Bulanan" is read as quarterly rather than monthly
+var couponFrequencies = []struct {
+	terms  []string
+	months int
+	name   string
+}{
+	{[]string{"triwulan", "kuartal", "quarter", "3 bulan", "tiga bulan"}, 3, "quarterly"},
+	{[]string{"semester", "semi", "6 bulan", "enam bulan"}, 6, "semiannual"},
+	{[]string{"tahun", "annual", "year"}, 12, "annual"},
+	{[]string{"bulan", "month"}, 1, "monthly"},
+}
+
+// CouponFrequency reads the months between coupons from the payment frequency wording
+// of penerimaan_kupon, falling back to tipe_kupon. Zero months means unknown
+func CouponFrequency(penerimaanKupon, tipeKupon string) (months int, name string) {
+	for _, text := range []string{penerimaanKupon, tipeKupon} {
+		text = strings.ToLower(text)
+		for _, frequency := range couponFrequencies {
+			for _, term := range frequency.terms {
+				if strings.Contains(text, term) {
+					return frequency.months, frequency.name
+				}
+			}
+		}
+	}
+	return 0, ""
+}
+
+// addCouponMonths moves first forward by months, keeping its day of month where the month
+// has it and the last day otherwise, so a 31st coupon stays at month end without drifting
+func addCouponMonths(first time.Time, months int) time.Time {
+	year, month, day := first.Date()
+	target := time.Date(year, month+time.Month(months), 1, first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), first.Location())
+	lastDay := target.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return target.AddDate(0, 0, day-1)
+}
+
+// GetCouponSchedule builds the coupon calendar of a sukuk and marks coupons paid from its
+// indexed yield distributions
+func (s *IndexerQueryService) GetCouponSchedule(ctx context.Context, sukuk *models.SukukMetadata, grace time.Duration, now time.Time) (*models.CouponScheduleResponse, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
+	}
+
+	var distributions []IndexerYieldDistributed
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(yieldTable).
+			Where("LOWER(sukuk_address) = ?", strings.ToLower(sukuk.ContractAddress)).
+			Order("timestamp ASC").
+			Find(&distributions).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch yield distributions: %w", err)
+	}
+
+	return BuildCouponSchedule(sukuk, distributions, grace, now), nil
+}
+
+// BuildCouponSchedule lays out one coupon per payment period from kupon_pertama through
+// jatuh_tempo (a year past now when no maturity is set), plus a final coupon at a maturity
+// off the cycle, and matches each distribution to the nearest unpaid coupon within grace.
+// Coupons still unpaid once their grace window has passed are missed; distributions
+// matching no coupon are listed as extra
+func BuildCouponSchedule(sukuk *models.SukukMetadata, distributions []IndexerYieldDistributed, grace time.Duration, now time.Time) *models.CouponScheduleResponse {
+	response := &models.CouponScheduleResponse{
+		SukukMetadataID:    sukuk.ID,
+		ContractAddress:    sukuk.ContractAddress,
+		GracePeriod:        grace.String(),
+		Coupons:            []models.ScheduledCoupon{},
+		ExtraDistributions: []models.CouponDistribution{},
+	}
+
+	sort.SliceStable(distributions, func(i, j int) bool {
+		return distributions[i].Timestamp < distributions[j].Timestamp
+	})
+
+	months, frequency := CouponFrequency(sukuk.PenerimaanKupon, sukuk.TipeKupon)
+	if sukuk.KuponPertama.IsZero() || months == 0 {
+		for _, distribution := range distributions {
+			response.ExtraDistributions = append(response.ExtraDistributions, couponDistribution(distribution))
+		}
+		return response
+	}
+	response.Estimated = true
+	response.Frequency = frequency
+
+	last := sukuk.JatuhTempo
+	if last.IsZero() {
+		last = now.AddDate(1, 0, 0)
+	}
+	for i := 0; i < maxScheduledCoupons; i++ {
+		date := addCouponMonths(sukuk.KuponPertama, i*months)
+		if date.After(last) {
+			break
+		}
+		response.Coupons = append(response.Coupons, models.ScheduledCoupon{
+			Number:        i + 1,
+			ScheduledDate: date,
+			Status:        models.CouponStatusUpcoming,
+		})
+	}
+	// A maturity off the coupon cycle ends with a final, shorter period paid at maturity
+	if count := len(response.Coupons); !sukuk.JatuhTempo.IsZero() && count > 0 && count < maxScheduledCoupons &&
+		sukuk.JatuhTempo.Sub(response.Coupons[count-1].ScheduledDate) > grace {
+		response.Coupons = append(response.Coupons, models.ScheduledCoupon{
+			Number:        count + 1,
+			ScheduledDate: sukuk.JatuhTempo,
+			Status:        models.CouponStatusUpcoming,
+		})
+	}
+
+	for _, distribution := range distributions {
+		paidAt := time.Unix(distribution.Timestamp, 0)
+		match := -1
+		var matchDistance time.Duration
+		for i := range response.Coupons {
+			if response.Coupons[i].Distribution != nil {
+				continue
+			}
+			distance := paidAt.Sub(response.Coupons[i].ScheduledDate)
+			if distance < 0 {
+				distance = -distance
+			}
+			if distance <= grace && (match < 0 || distance < matchDistance) {
+				match, matchDistance = i, distance
+			}
+		}
+
+		paid := couponDistribution(distribution)
+		if match < 0 {
+			response.ExtraDistributions = append(response.ExtraDistributions, paid)
+			continue
+		}
+		response.Coupons[match].Status = models.CouponStatusPaid
+		response.Coupons[match].Distribution = &paid
+	}
+
+	for i := range response.Coupons {
+		coupon := &response.Coupons[i]
+		if coupon.Distribution != nil {
+			continue
+		}
+		if now.After(coupon.ScheduledDate.Add(grace)) {
+			coupon.Status = models.CouponStatusMissed
+		} else if response.NextCouponDate == nil {
+			date := coupon.ScheduledDate
+			response.NextCouponDate = &date
+		}
+	}
+	return response
+}
+
+func couponDistribution(distribution IndexerYieldDistributed) models.CouponDistribution {
+	return models.CouponDistribution{
+		DistributionID: distribution.DistributionId,
+		PaymentToken:   distribution.PaymentToken,
+		Amount:         distribution.Amount,
+		TxHash:         distribution.TxHash,
+		PaidAt:         time.Unix(distribution.Timestamp, 0).UTC(),
+	}
+}
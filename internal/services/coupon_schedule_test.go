@@ -0,0 +1,156 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func couponDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func distributedAt(id int64, t time.Time) IndexerYieldDistributed {
+	return IndexerYieldDistributed{DistributionId: id, Amount: "1000", TxHash: "0xd", Timestamp: t.Unix()}
+}
+
+func couponStatuses(schedule *models.CouponScheduleResponse) []models.CouponStatus {
+	statuses := make([]models.CouponStatus, len(schedule.Coupons))
+	for i, coupon := range schedule.Coupons {
+		statuses[i] = coupon.Status
+	}
+	return statuses
+}
+
+func assertCouponStatuses(t *testing.T, schedule *models.CouponScheduleResponse, expected ...models.CouponStatus) {
+	t.Helper()
+	got := couponStatuses(schedule)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d coupons %v, got %v", len(expected), expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Expected statuses %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestCouponFrequency(t *testing.T) {
+	tests := []struct {
+		penerimaan string
+		tipe       string
+		months     int
+	}{
+		{"Bulanan", "Fixed Rate", 1},
+		{"Triwulanan", "", 3},
+		{"3 Bulanan", "", 3},
+		{"Quarterly", "", 3},
+		{"Semesteran", "", 6},
+		{"Tahunan", "", 12},
+		{"", "Kupon Bulanan", 1},
+		{"", "Fixed Rate", 0},
+	}
+	for _, tt := range tests {
+		if months, _ := CouponFrequency(tt.penerimaan, tt.tipe); months != tt.months {
+			t.Errorf("CouponFrequency(%q, %q) = %d months, expected %d", tt.penerimaan, tt.tipe, months, tt.months)
+		}
+	}
+}
+
+func TestMonthlyCouponScheduleWithMissedCoupon(t *testing.T) {
+	sukuk := &models.SukukMetadata{
+		PenerimaanKupon: "Bulanan",
+		KuponPertama:    couponDate(2025, time.January, 10),
+		JatuhTempo:      couponDate(2025, time.June, 10),
+	}
+	now := couponDate(2025, time.May, 20)
+	distributions := []IndexerYieldDistributed{
+		distributedAt(5, couponDate(2025, time.May, 10)),
+		distributedAt(1, couponDate(2025, time.January, 10)),
+		distributedAt(2, couponDate(2025, time.February, 12)), // Two days late, within grace
+		distributedAt(3, couponDate(2025, time.March, 25)),    // Too late to pay March
+		distributedAt(4, couponDate(2025, time.April, 9)),     // A day early
+	}
+
+	schedule := BuildCouponSchedule(sukuk, distributions, DefaultCouponGracePeriod, now)
+	if !schedule.Estimated || schedule.Frequency != "monthly" {
+		t.Fatalf("Expected an estimated monthly schedule, got %+v", schedule)
+	}
+	assertCouponStatuses(t, schedule,
+		models.CouponStatusPaid, models.CouponStatusPaid, models.CouponStatusMissed,
+		models.CouponStatusPaid, models.CouponStatusPaid, models.CouponStatusUpcoming)
+
+	if paid := schedule.Coupons[1].Distribution; paid.DistributionID != 2 || !paid.PaidAt.Equal(couponDate(2025, time.February, 12)) {
+		t.Errorf("Expected February paid by distribution 2 on the 12th, got %+v", paid)
+	}
+	if len(schedule.ExtraDistributions) != 1 || schedule.ExtraDistributions[0].DistributionID != 3 {
+		t.Errorf("Expected the late March distribution as extra, got %+v", schedule.ExtraDistributions)
+	}
+	if schedule.NextCouponDate == nil || !schedule.NextCouponDate.Equal(couponDate(2025, time.June, 10)) {
+		t.Errorf("Expected the next coupon on 10 June, got %v", schedule.NextCouponDate)
+	}
+}
+
+func TestQuarterlyCouponScheduleWithMissedCoupon(t *testing.T) {
+	sukuk := &models.SukukMetadata{
+		PenerimaanKupon: "Triwulanan",
+		KuponPertama:    couponDate(2025, time.January, 31),
+		JatuhTempo:      couponDate(2026, time.January, 31),
+	}
+	now := couponDate(2025, time.November, 15)
+	distributions := []IndexerYieldDistributed{
+		distributedAt(1, couponDate(2025, time.January, 31)),
+		distributedAt(2, couponDate(2025, time.April, 30)),
+		distributedAt(3, couponDate(2025, time.October, 30)),
+	}
+
+	schedule := BuildCouponSchedule(sukuk, distributions, DefaultCouponGracePeriod, now)
+	if schedule.Frequency != "quarterly" {
+		t.Fatalf("Expected a quarterly schedule, got %q", schedule.Frequency)
+	}
+	assertCouponStatuses(t, schedule,
+		models.CouponStatusPaid, models.CouponStatusPaid, models.CouponStatusMissed,
+		models.CouponStatusPaid, models.CouponStatusUpcoming)
+
+	// Month-end coupons stay at month end without drifting
+	expected := []time.Time{
+		couponDate(2025, time.January, 31), couponDate(2025, time.April, 30), couponDate(2025, time.July, 31),
+		couponDate(2025, time.October, 31), couponDate(2026, time.January, 31),
+	}
+	for i, coupon := range schedule.Coupons {
+		if !coupon.ScheduledDate.Equal(expected[i]) {
+			t.Errorf("Expected coupon %d on %s, got %s", coupon.Number, expected[i].Format("2006-01-02"), coupon.ScheduledDate.Format("2006-01-02"))
+		}
+	}
+	if len(schedule.ExtraDistributions) != 0 {
+		t.Errorf("Expected no extra distributions, got %+v", schedule.ExtraDistributions)
+	}
+}
+
+func TestCouponScheduleEndsAtOffCycleMaturity(t *testing.T) {
+	sukuk := &models.SukukMetadata{
+		PenerimaanKupon: "Bulanan",
+		KuponPertama:    couponDate(2025, time.August, 11),
+		JatuhTempo:      couponDate(2026, time.February, 10),
+	}
+
+	schedule := BuildCouponSchedule(sukuk, nil, DefaultCouponGracePeriod, couponDate(2025, time.August, 1))
+	last := schedule.Coupons[len(schedule.Coupons)-1]
+	if len(schedule.Coupons) != 7 || !last.ScheduledDate.Equal(sukuk.JatuhTempo) {
+		t.Errorf("Expected six monthly coupons and a final one at maturity, got %d ending %s", len(schedule.Coupons), last.ScheduledDate)
+	}
+}
+
+func TestCouponScheduleWithoutFirstCoupon(t *testing.T) {
+	sukuk := &models.SukukMetadata{PenerimaanKupon: "Bulanan", JatuhTempo: couponDate(2026, time.January, 10)}
+	distributions := []IndexerYieldDistributed{distributedAt(1, couponDate(2025, time.March, 10))}
+
+	schedule := BuildCouponSchedule(sukuk, distributions, DefaultCouponGracePeriod, couponDate(2025, time.May, 1))
+	if schedule.Estimated || len(schedule.Coupons) != 0 || schedule.NextCouponDate != nil {
+		t.Errorf("Expected no schedule without kupon_pertama, got %+v", schedule)
+	}
+	if len(schedule.ExtraDistributions) != 1 {
+		t.Errorf("Expected the distribution as extra, got %+v", schedule.ExtraDistributions)
+	}
+}
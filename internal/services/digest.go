@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DigestLookback is the window of an address's first digest
+const DigestLookback = 24 * time.Hour
+
+// DigestMaturityHorizon is how far ahead maturities of held sukuk are listed
+const DigestMaturityHorizon = 30 * 24 * time.Hour
+
+// digestStateKeyPrefix prefixes the system state key holding an address's last_digest_at
+const digestStateKeyPrefix = "last_digest_at:"
+
+// ErrDigestConflict is returned when another digest for the address advanced last_digest_at first
+var ErrDigestConflict = errors.New("a digest for this address was compiled concurrently")
+
+// digestStateKey is the system state key of an address's last_digest_at
+func digestStateKey(address string) string {
+	return digestStateKeyPrefix + utils.NormalizeAddress(address)
+}
+
+// DigestWindow returns the window [from, to) a digest compiled at now covers. An explicit since
+// replays from there and leaves the bookkeeping alone; otherwise the window starts where the
+// previous digest ended, or DigestLookback ago for the first one, and advance is true
+func DigestWindow(now time.Time, since, lastDigestAt *time.Time) (from, to time.Time, advance bool, err error) {
+	// Indexer timestamps are whole seconds, so consecutive windows must meet on a second
+	to = now.Truncate(time.Second)
+
+	if since != nil {
+		if since.After(to) {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("since %s is in the future", since.UTC().Format(time.RFC3339))
+		}
+		return *since, to, false, nil
+	}
+	if lastDigestAt != nil {
+		from = *lastDigestAt
+		if from.After(to) {
+			from = to
+		}
+		return from, to, true, nil
+	}
+	return to.Add(-DigestLookback), to, true, nil
+}
+
+// LoadLastDigestAt returns when the previous digest for an address ended, or nil if there was none
+func LoadLastDigestAt(db *gorm.DB, address string) (*time.Time, error) {
+	state, err := models.GetSystemState(db, digestStateKey(address))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := time.Parse(time.RFC3339, state.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last_digest_at for %s: %w", address, err)
+	}
+	return &at, nil
+}
+
+// AdvanceLastDigestAt moves an address's last_digest_at from previous to at, failing with
+// ErrDigestConflict if it no longer holds previous so a window is never handed out twice
+func AdvanceLastDigestAt(db *gorm.DB, address string, previous *time.Time, at time.Time) error {
+	key := digestStateKey(address)
+	value := at.UTC().Format(time.RFC3339)
+
+	if previous == nil {
+		state := models.SystemState{Key: key, Value: value}
+		result := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "key"}}, DoNothing: true}).Create(&state)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDigestConflict
+		}
+		return nil
+	}
+
+	result := db.Model(&models.SystemState{}).
+		Where("key = ? AND value = ?", key, previous.UTC().Format(time.RFC3339)).
+		Update("value", value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDigestConflict
+	}
+	return nil
+}
+
+// DigestEvents holds the indexer rows a digest is built from
+type DigestEvents struct {
+	Holdings       []IndexerHolderUpdated    // Latest balance per sukuk
+	Distributions  []IndexerYieldDistributed // On held sukuk during the window
+	Supplies       map[string]Supply         // Supply at the window end by lowercased sukuk address
+	Requests       []IndexerRedemptionRequest
+	Approvals      []IndexerRedemptionApproval
+	BalanceChanges []IndexerHolderUpdated
+}
+
+// GenerateDigest compiles the digest of an address at now and, unless since is given,
+// advances its last_digest_at so the next digest starts where this one ends
+func GenerateDigest(ctx context.Context, address string, since *time.Time, now time.Time) (*models.DigestResponse, error) {
+	db := database.GetDB().WithContext(ctx)
+
+	var lastDigestAt *time.Time
+	if since == nil {
+		var err error
+		if lastDigestAt, err = LoadLastDigestAt(db, address); err != nil {
+			return nil, err
+		}
+	}
+	from, to, advance, err := DigestWindow(now, since, lastDigestAt)
+	if err != nil {
+		return nil, err
+	}
+
+	indexerService := NewIndexerQueryService()
+	if err := indexerService.ConnectToIndexer(); err != nil {
+		return nil, err
+	}
+
+	var events DigestEvents
+	if events.Holdings, err = indexerService.GetHoldings(ctx, address); err != nil {
+		return nil, err
+	}
+	sources := []struct {
+		eventType string
+		column    string
+		dest      interface{}
+	}{
+		{"redemption_request", "user", &events.Requests},
+		{"redemption_approval", "user", &events.Approvals},
+		{"holder_update", "holder", &events.BalanceChanges},
+	}
+	for _, source := range sources {
+		if err := indexerService.GetAddressEventsBetween(ctx, source.eventType, source.column, address, from, to, source.dest); err != nil {
+			return nil, err
+		}
+	}
+
+	held := heldSukuk(events.Holdings)
+	if err := indexerService.GetSukukEventsBetween(ctx, "yield_distributed", held, from, to, &events.Distributions); err != nil {
+		return nil, err
+	}
+	distributed := make([]string, 0, len(events.Distributions))
+	for _, distribution := range events.Distributions {
+		distributed = append(distributed, utils.NormalizeAddress(distribution.SukukAddress))
+	}
+	if events.Supplies, err = DefaultSupplyService().GetTotalSuppliesAt(ctx, distributed, to); err != nil {
+		return nil, err
+	}
+
+	sukukAddresses := append([]string{}, held...)
+	for _, change := range events.BalanceChanges {
+		sukukAddresses = append(sukukAddresses, utils.NormalizeAddress(change.SukukAddress))
+	}
+	for _, request := range events.Requests {
+		sukukAddresses = append(sukukAddresses, utils.NormalizeAddress(request.SukukAddress))
+	}
+	for _, approval := range events.Approvals {
+		sukukAddresses = append(sukukAddresses, utils.NormalizeAddress(approval.SukukAddress))
+	}
+	var metadata []models.SukukMetadata
+	if len(sukukAddresses) > 0 {
+		if err := db.Where("LOWER(contract_address) IN ?", sukukAddresses).Find(&metadata).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	digest := BuildDigest(address, from, to, since != nil, metadata, events)
+
+	preference, err := models.GetNotificationPreference(db, address)
+	if err != nil {
+		return nil, err
+	}
+	digest.Deliver = preference.Allows(models.NotificationKindDigest) && !digest.IsEmpty()
+	digest.Email = preference.Email
+	digest.Locale = preference.Locale
+
+	if advance {
+		if err := AdvanceLastDigestAt(db, address, lastDigestAt, to); err != nil {
+			return nil, err
+		}
+	}
+	return digest, nil
+}
+
+// heldSukuk returns the lowercased addresses of sukuk with a positive latest balance
+func heldSukuk(holdings []IndexerHolderUpdated) []string {
+	mathUtil := utils.GlobalTokenMath
+	held := make([]string, 0, len(holdings))
+	for _, holding := range holdings {
+		if mathUtil.IsPositive(holding.Balance) {
+			held = append(held, utils.NormalizeAddress(holding.SukukAddress))
+		}
+	}
+	return held
+}
+
+// BuildDigest assembles a digest for [from, to) from indexer rows. Rows outside the window are
+// ignored, entitlements are the pro-rata share of the current balance, and maturities are
+// listed for held sukuk maturing within DigestMaturityHorizon of to
+func BuildDigest(address string, from, to time.Time, sinceProvided bool, metadata []models.SukukMetadata, events DigestEvents) *models.DigestResponse {
+	digest := &models.DigestResponse{
+		Address:            utils.NormalizeAddress(address),
+		Since:              from,
+		Until:              to,
+		SinceProvided:      sinceProvided,
+		YieldDistributions: make([]models.DigestYieldDistribution, 0),
+		RedemptionUpdates:  make([]models.DigestRedemptionUpdate, 0),
+		BalanceChanges:     make([]models.DigestBalanceChange, 0),
+		UpcomingMaturities: make([]models.DigestMaturity, 0),
+	}
+
+	bySukuk := make(map[string]models.SukukMetadata, len(metadata))
+	for _, sukuk := range metadata {
+		bySukuk[utils.NormalizeAddress(sukuk.ContractAddress)] = sukuk
+	}
+	mathUtil := utils.GlobalTokenMath
+	balances := make(map[string]string) // Positive current balances by lowercased sukuk address
+	for _, holding := range events.Holdings {
+		if mathUtil.IsPositive(holding.Balance) {
+			balances[utils.NormalizeAddress(holding.SukukAddress)] = holding.Balance
+		}
+	}
+	inWindow := func(timestamp int64) bool {
+		at := time.Unix(timestamp, 0)
+		return !at.Before(from) && at.Before(to)
+	}
+
+	for _, distribution := range events.Distributions {
+		sukukAddress := utils.NormalizeAddress(distribution.SukukAddress)
+		balance, held := balances[sukukAddress]
+		if !held || !inWindow(distribution.Timestamp) {
+			continue
+		}
+		supply, ok := events.Supplies[sukukAddress]
+		if !ok {
+			supply = Supply{Source: models.SupplySourceNone}
+		}
+		entitled, err := mathUtil.ProRataShare(distribution.Amount, balance, supply.Amount)
+		if err != nil {
+			entitled = "0"
+		}
+		digest.YieldDistributions = append(digest.YieldDistributions, models.DigestYieldDistribution{
+			SukukAddress:   sukukAddress,
+			SukukCode:      bySukuk[sukukAddress].SukukCode,
+			DistributionID: distribution.DistributionId,
+			PaymentToken:   utils.NormalizeAddress(distribution.PaymentToken),
+			TotalAmount:    distribution.Amount,
+			EntitledAmount: entitled,
+			SupplySource:   supply.Source,
+			DistributedAt:  time.Unix(distribution.Timestamp, 0).UTC(),
+			TxHash:         distribution.TxHash,
+		})
+	}
+
+	redemption := func(sukukAddress string, status models.RedemptionStatus, amount string, timestamp int64, txHash string) {
+		if !inWindow(timestamp) {
+			return
+		}
+		sukukAddress = utils.NormalizeAddress(sukukAddress)
+		digest.RedemptionUpdates = append(digest.RedemptionUpdates, models.DigestRedemptionUpdate{
+			SukukAddress: sukukAddress,
+			SukukCode:    bySukuk[sukukAddress].SukukCode,
+			Status:       status,
+			Amount:       amount,
+			At:           time.Unix(timestamp, 0).UTC(),
+			TxHash:       txHash,
+		})
+	}
+	for _, request := range events.Requests {
+		redemption(request.SukukAddress, models.RedemptionStatusRequested, request.Amount, request.Timestamp, request.TxHash)
+	}
+	for _, approval := range events.Approvals {
+		redemption(approval.SukukAddress, models.RedemptionStatusApproved, approval.Amount, approval.Timestamp, approval.TxHash)
+	}
+
+	for _, change := range events.BalanceChanges {
+		if !inWindow(change.Timestamp) {
+			continue
+		}
+		sukukAddress := utils.NormalizeAddress(change.SukukAddress)
+		digest.BalanceChanges = append(digest.BalanceChanges, models.DigestBalanceChange{
+			SukukAddress: sukukAddress,
+			SukukCode:    bySukuk[sukukAddress].SukukCode,
+			Balance:      change.Balance,
+			At:           time.Unix(change.Timestamp, 0).UTC(),
+			TxHash:       change.TxHash,
+		})
+	}
+
+	horizon := to.Add(DigestMaturityHorizon)
+	for sukukAddress := range balances {
+		sukuk, ok := bySukuk[sukukAddress]
+		if !ok || !sukuk.JatuhTempo.After(to) || sukuk.JatuhTempo.After(horizon) {
+			continue
+		}
+		digest.UpcomingMaturities = append(digest.UpcomingMaturities, models.DigestMaturity{
+			SukukAddress:  sukukAddress,
+			SukukCode:     sukuk.SukukCode,
+			SukukTitle:    sukuk.SukukTitle,
+			JatuhTempo:    sukuk.JatuhTempo,
+			DaysRemaining: int(math.Ceil(sukuk.JatuhTempo.Sub(to).Hours() / 24)),
+		})
+	}
+
+	sort.SliceStable(digest.YieldDistributions, func(i, j int) bool {
+		return digest.YieldDistributions[i].DistributedAt.Before(digest.YieldDistributions[j].DistributedAt)
+	})
+	sort.SliceStable(digest.RedemptionUpdates, func(i, j int) bool {
+		return digest.RedemptionUpdates[i].At.Before(digest.RedemptionUpdates[j].At)
+	})
+	sort.SliceStable(digest.BalanceChanges, func(i, j int) bool {
+		return digest.BalanceChanges[i].At.Before(digest.BalanceChanges[j].At)
+	})
+	sort.Slice(digest.UpcomingMaturities, func(i, j int) bool {
+		a, b := digest.UpcomingMaturities[i], digest.UpcomingMaturities[j]
+		if !a.JatuhTempo.Equal(b.JatuhTempo) {
+			return a.JatuhTempo.Before(b.JatuhTempo)
+		}
+		return strings.Compare(a.SukukAddress, b.SukukAddress) < 0
+	})
+	return digest
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestDigestWindowBookkeeping(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 8, 0, 0, 500, time.UTC)
+
+	// The first digest looks back a day and truncates to the second
+	from, to, advance, err := DigestWindow(day1, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := day1.Truncate(time.Second); !to.Equal(want) || !from.Equal(want.Add(-DigestLookback)) || !advance {
+		t.Errorf("Expected the first window to be the previous day, got [%s, %s) advance=%v", from, to, advance)
+	}
+
+	// The next digest starts exactly where the first ended, so nothing repeats or is skipped
+	day2 := day1.Add(24*time.Hour + 3*time.Second)
+	from2, to2, advance, _ := DigestWindow(day2, nil, &to)
+	if !from2.Equal(to) || !to2.Equal(day2.Truncate(time.Second)) || !advance {
+		t.Errorf("Expected the second window to start at %s, got [%s, %s) advance=%v", to, from2, to2, advance)
+	}
+
+	// An explicit since replays and leaves last_digest_at alone
+	since := day1.Add(-72 * time.Hour)
+	from3, _, advance, _ := DigestWindow(day2, &since, &to2)
+	if !from3.Equal(since) || advance {
+		t.Errorf("Expected an explicit since to replay without advancing, got from=%s advance=%v", from3, advance)
+	}
+
+	future := day2.Add(time.Hour)
+	if _, _, _, err := DigestWindow(day2, &future, nil); err == nil {
+		t.Error("Expected a future since to be rejected")
+	}
+
+	// A last_digest_at ahead of the clock yields an empty window rather than an inverted one
+	from4, to4, _, _ := DigestWindow(day1, nil, &future)
+	if !from4.Equal(to4) {
+		t.Errorf("Expected an empty window, got [%s, %s)", from4, to4)
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	const (
+		investor = "0x00000000000000000000000000000000000000A1"
+		held     = "0x00000000000000000000000000000000000000c1"
+		sold     = "0x00000000000000000000000000000000000000c2"
+	)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	metadata := []models.SukukMetadata{
+		{ContractAddress: held, SukukCode: "SRA", JatuhTempo: to.Add(10*24*time.Hour - time.Hour)},
+		{ContractAddress: sold, SukukCode: "SRB", JatuhTempo: to.Add(5 * 24 * time.Hour)},
+	}
+	events := DigestEvents{
+		Holdings: []IndexerHolderUpdated{
+			{SukukAddress: held, Balance: "25"},
+			{SukukAddress: sold, Balance: "0"},
+		},
+		Distributions: []IndexerYieldDistributed{
+			{SukukAddress: held, DistributionId: 2, Amount: "1000", Timestamp: to.Add(-time.Second).Unix()},
+			{SukukAddress: held, DistributionId: 3, Amount: "1000", Timestamp: to.Unix()}, // Belongs to the next digest
+			{SukukAddress: sold, DistributionId: 7, Amount: "1000", Timestamp: from.Unix()},
+		},
+		Supplies: map[string]Supply{held: {Amount: "100", Source: models.SupplySourceSnapshot}},
+		Requests: []IndexerRedemptionRequest{
+			{SukukAddress: sold, Amount: "10", Timestamp: from.Unix()},
+			{SukukAddress: sold, Amount: "15", Timestamp: from.Add(-time.Second).Unix()}, // In the previous digest
+		},
+		Approvals: []IndexerRedemptionApproval{
+			{SukukAddress: sold, Amount: "10", Timestamp: from.Add(time.Hour).Unix()},
+		},
+		BalanceChanges: []IndexerHolderUpdated{
+			{SukukAddress: sold, Balance: "0", Timestamp: from.Add(2 * time.Hour).Unix()},
+		},
+	}
+
+	digest := BuildDigest(investor, from, to, false, metadata, events)
+
+	if digest.Address != "0x00000000000000000000000000000000000000a1" {
+		t.Errorf("Expected a lowercased address, got %s", digest.Address)
+	}
+	if len(digest.YieldDistributions) != 1 {
+		t.Fatalf("Expected only the held sukuk's distribution within the window, got %+v", digest.YieldDistributions)
+	}
+	if d := digest.YieldDistributions[0]; d.DistributionID != 2 || d.EntitledAmount != "250" || d.SukukCode != "SRA" || d.SupplySource != models.SupplySourceSnapshot {
+		t.Errorf("Expected a 25%% entitlement of distribution 2, got %+v", d)
+	}
+	if len(digest.RedemptionUpdates) != 2 || digest.RedemptionUpdates[0].Status != models.RedemptionStatusRequested ||
+		digest.RedemptionUpdates[1].Status != models.RedemptionStatusApproved {
+		t.Errorf("Expected the request then its approval, got %+v", digest.RedemptionUpdates)
+	}
+	if len(digest.BalanceChanges) != 1 || digest.BalanceChanges[0].SukukCode != "SRB" {
+		t.Errorf("Expected the sale to show as a balance change, got %+v", digest.BalanceChanges)
+	}
+	if len(digest.UpcomingMaturities) != 1 || digest.UpcomingMaturities[0].SukukCode != "SRA" || digest.UpcomingMaturities[0].DaysRemaining != 10 {
+		t.Errorf("Expected only the held sukuk to mature in 10 days, got %+v", digest.UpcomingMaturities)
+	}
+}
+
+// TestAdvanceLastDigestAt requires a reachable Postgres, see testutil.Open
+func TestAdvanceLastDigestAt(t *testing.T) {
+	db := testutil.DB(t)
+
+	const address = "0x00000000000000000000000000000000000000d1"
+	db.Where("key = ?", digestStateKey(address)).Delete(&models.SystemState{})
+	t.Cleanup(func() { db.Where("key = ?", digestStateKey(address)).Delete(&models.SystemState{}) })
+
+	if last, err := LoadLastDigestAt(db, address); err != nil || last != nil {
+		t.Fatalf("Expected no previous digest, got %v (%v)", last, err)
+	}
+
+	first := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err := AdvanceLastDigestAt(db, address, nil, first); err != nil {
+		t.Fatalf("Failed to record the first digest: %v", err)
+	}
+	// A concurrent first digest loses
+	if err := AdvanceLastDigestAt(db, address, nil, first); !errors.Is(err, ErrDigestConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+
+	last, err := LoadLastDigestAt(db, address)
+	if err != nil || last == nil || !last.Equal(first) {
+		t.Fatalf("Expected last_digest_at %s, got %v (%v)", first, last, err)
+	}
+	second := first.Add(24 * time.Hour)
+	if err := AdvanceLastDigestAt(db, address, last, second); err != nil {
+		t.Fatalf("Failed to advance: %v", err)
+	}
+	// Advancing from a stale value fails instead of handing out the window again
+	if err := AdvanceLastDigestAt(db, address, last, second.Add(time.Hour)); !errors.Is(err, ErrDigestConflict) {
+		t.Errorf("Expected a conflict, got %v", err)
+	}
+}
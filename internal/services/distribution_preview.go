@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// DistributionPreviewBatchSize is how many holders are read from the indexer at a time
+const DistributionPreviewBatchSize = 1000
+
+var (
+	// ErrNoHolders is returned when a sukuk has no holder with a non-zero balance
+	ErrNoHolders = errors.New("sukuk has no holders")
+	// ErrHoldersChanged is returned when balances change while a preview reads them
+	ErrHoldersChanged = errors.New("holder balances changed during the preview")
+)
+
+// DistributionHolderSource reads the current holders of a sukuk
+// Implemented by IndexerQueryService
+type DistributionHolderSource interface {
+	GetHolderSupply(ctx context.Context, sukukAddress string) (string, error)
+	ForEachHolderBatch(ctx context.Context, sukukAddress string, batchSize int, fn func([]IndexerHolderUpdated) error) error
+}
+
+// DistributionPreviewer computes pro-rata yield entitlements without writing anything
+type DistributionPreviewer struct {
+	source    DistributionHolderSource
+	formatter *TokenFormatter
+	batchSize int
+	mathUtil  *utils.TokenMath
+}
+
+// NewDistributionPreviewer creates a previewer reading holders from source
+func NewDistributionPreviewer(source DistributionHolderSource, formatter *TokenFormatter) *DistributionPreviewer {
+	return &DistributionPreviewer{
+		source:    source,
+		formatter: formatter,
+		batchSize: DistributionPreviewBatchSize,
+		mathUtil:  utils.GlobalTokenMath,
+	}
+}
+
+// Preview splits totalAmount of paymentToken across the current holders of a sukuk in
+// proportion to their balances, rounding each share down. Holders entitled to less than
+// minEntitlement are flagged
+func (p *DistributionPreviewer) Preview(ctx context.Context, sukukAddress, paymentToken, totalAmount, minEntitlement string) (*models.DistributionPreview, error) {
+	minimum, ok := new(big.Int).SetString(minEntitlement, 10)
+	if !ok || minimum.Sign() < 0 {
+		return nil, fmt.Errorf("invalid minimum entitlement: %s", minEntitlement)
+	}
+
+	supply, err := p.source.GetHolderSupply(ctx, sukukAddress)
+	if err != nil {
+		return nil, err
+	}
+	if !p.mathUtil.IsPositive(supply) {
+		return nil, ErrNoHolders
+	}
+
+	preview := &models.DistributionPreview{
+		SukukAddress:   sukukAddress,
+		PaymentToken:   paymentToken,
+		TotalSupply:    supply,
+		MinEntitlement: minimum.String(),
+		Holders:        make([]models.DistributionEntitlement, 0),
+	}
+
+	distributed := new(big.Int)
+	balances := new(big.Int)
+	entitlements := make([]*big.Int, 0)
+	err = p.source.ForEachHolderBatch(ctx, sukukAddress, p.batchSize, func(batch []IndexerHolderUpdated) error {
+		for _, holder := range batch {
+			share, err := p.mathUtil.ProRataShare(totalAmount, holder.Balance, supply)
+			if err != nil {
+				return err
+			}
+			entitlement, _ := new(big.Int).SetString(share, 10)
+			balance, _ := new(big.Int).SetString(holder.Balance, 10)
+
+			distributed.Add(distributed, entitlement)
+			balances.Add(balances, balance)
+			entitlements = append(entitlements, entitlement)
+
+			below := entitlement.Cmp(minimum) < 0
+			if below {
+				preview.Summary.BelowMinimumCount++
+			}
+			preview.Holders = append(preview.Holders, models.DistributionEntitlement{
+				Holder:       holder.Holder,
+				Balance:      holder.Balance,
+				Entitlement:  share,
+				BelowMinimum: below,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Shares are only exact against the supply they were computed from
+	if balances.String() != supply {
+		return nil, ErrHoldersChanged
+	}
+
+	dust, err := p.mathUtil.SubtractTokenAmounts(totalAmount, distributed.String())
+	if err != nil {
+		return nil, err
+	}
+	preview.TotalAmount = p.formatter.FormatTokenAmount(totalAmount, paymentToken)
+	preview.DistributedAmount = p.formatter.FormatTokenAmount(distributed.String(), paymentToken)
+	preview.Dust = p.formatter.FormatTokenAmount(dust, paymentToken)
+	preview.Summary = entitlementStats(entitlements, preview.Summary.BelowMinimumCount)
+	return preview, nil
+}
+
+// entitlementStats computes the holder count and min, max and median entitlement
+func entitlementStats(entitlements []*big.Int, belowMinimum int) models.DistributionPreviewStats {
+	stats := models.DistributionPreviewStats{
+		HolderCount:       len(entitlements),
+		BelowMinimumCount: belowMinimum,
+		MinEntitlement:    "0",
+		MaxEntitlement:    "0",
+		MedianEntitlement: "0",
+	}
+	if len(entitlements) == 0 {
+		return stats
+	}
+
+	sorted := make([]*big.Int, len(entitlements))
+	copy(sorted, entitlements)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+
+	stats.MinEntitlement = sorted[0].String()
+	stats.MaxEntitlement = sorted[len(sorted)-1].String()
+
+	middle := len(sorted) / 2
+	median := new(big.Int).Set(sorted[middle])
+	if len(sorted)%2 == 0 {
+		median.Add(median, sorted[middle-1])
+		median.Quo(median, big.NewInt(2))
+	}
+	stats.MedianEntitlement = median.String()
+	return stats
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"sukuk-be/internal/models"
+)
+
+// fakeHolderSource serves holders from memory in the batches requested
+type fakeHolderSource struct {
+	holders []IndexerHolderUpdated
+	supply  string // Reported supply; summed from holders when empty
+	batches int
+}
+
+func (f *fakeHolderSource) GetHolderSupply(ctx context.Context, sukukAddress string) (string, error) {
+	if f.supply != "" {
+		return f.supply, nil
+	}
+	supply := new(big.Int)
+	for _, holder := range f.holders {
+		balance, _ := new(big.Int).SetString(holder.Balance, 10)
+		supply.Add(supply, balance)
+	}
+	return supply.String(), nil
+}
+
+func (f *fakeHolderSource) ForEachHolderBatch(ctx context.Context, sukukAddress string, batchSize int, fn func([]IndexerHolderUpdated) error) error {
+	for start := 0; start < len(f.holders); start += batchSize {
+		end := start + batchSize
+		if end > len(f.holders) {
+			end = len(f.holders)
+		}
+		f.batches++
+		if err := fn(f.holders[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func holdersWithBalances(balances ...string) []IndexerHolderUpdated {
+	holders := make([]IndexerHolderUpdated, len(balances))
+	for i, balance := range balances {
+		holders[i] = IndexerHolderUpdated{Holder: fmt.Sprintf("0x%040x", i+1), Balance: balance}
+	}
+	return holders
+}
+
+func TestDistributionPreviewRoundsDownAndReportsDust(t *testing.T) {
+	source := &fakeHolderSource{holders: holdersWithBalances("1", "1", "1", "3")}
+	previewer := NewDistributionPreviewer(source, NewTokenFormatter([]models.PaymentToken{{Address: "0xc1", Symbol: "IDRX", Decimals: 2}}))
+	previewer.batchSize = 3
+
+	preview, err := previewer.Preview(context.Background(), "0xb1", "0xc1", "100", "20")
+	if err != nil {
+		t.Fatalf("Failed to preview: %v", err)
+	}
+	if source.batches != 2 {
+		t.Errorf("Expected holders to be read in 2 batches, got %d", source.batches)
+	}
+
+	// 100 * 1/6 = 16.67 and 100 * 3/6 = 50, each rounded down
+	want := []string{"16", "16", "16", "50"}
+	for i, holder := range preview.Holders {
+		if holder.Entitlement != want[i] {
+			t.Errorf("Holder %d: expected %s, got %s", i, want[i], holder.Entitlement)
+		}
+		if holder.BelowMinimum != (i < 3) {
+			t.Errorf("Holder %d: expected below_minimum=%v", i, i < 3)
+		}
+	}
+	if preview.DistributedAmount.Amount != "98" || preview.Dust.Amount != "2" || preview.Dust.Formatted != "0.02" {
+		t.Errorf("Expected 98 distributed and 2 dust, got %+v and %+v", preview.DistributedAmount, preview.Dust)
+	}
+	stats := preview.Summary
+	if stats.HolderCount != 4 || stats.BelowMinimumCount != 3 || stats.MinEntitlement != "16" || stats.MaxEntitlement != "50" || stats.MedianEntitlement != "16" {
+		t.Errorf("Unexpected summary: %+v", stats)
+	}
+}
+
+func TestDistributionPreviewSumsToTotal(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	wei := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	for round := 0; round < 50; round++ {
+		balances := make([]string, 1+rng.Intn(400))
+		for i := range balances {
+			balance := new(big.Int).Mul(big.NewInt(rng.Int63n(1_000_000)+1), wei)
+			balance.Add(balance, big.NewInt(rng.Int63()))
+			balances[i] = balance.String()
+		}
+		total := new(big.Int).Mul(big.NewInt(rng.Int63n(1_000_000_000)+1), big.NewInt(rng.Int63n(1_000_000)+1))
+
+		previewer := NewDistributionPreviewer(&fakeHolderSource{holders: holdersWithBalances(balances...)}, NewTokenFormatter(nil))
+		previewer.batchSize = 1 + rng.Intn(64)
+
+		preview, err := previewer.Preview(context.Background(), "0xb1", "0xc1", total.String(), "0")
+		if err != nil {
+			t.Fatalf("Round %d: failed to preview: %v", round, err)
+		}
+
+		sum := new(big.Int)
+		for _, holder := range preview.Holders {
+			entitlement, _ := new(big.Int).SetString(holder.Entitlement, 10)
+			sum.Add(sum, entitlement)
+		}
+		dust, _ := new(big.Int).SetString(preview.Dust.Amount, 10)
+		if sum.Add(sum, dust).Cmp(total) != 0 {
+			t.Fatalf("Round %d: entitlements plus dust %s != total %s", round, sum, total)
+		}
+		// Each share loses less than one unit to rounding
+		if dust.Sign() < 0 || dust.Cmp(big.NewInt(int64(len(balances)))) >= 0 {
+			t.Fatalf("Round %d: dust %s out of range for %d holders", round, dust, len(balances))
+		}
+	}
+}
+
+func TestDistributionPreviewErrors(t *testing.T) {
+	previewer := NewDistributionPreviewer(&fakeHolderSource{}, NewTokenFormatter(nil))
+	if _, err := previewer.Preview(context.Background(), "0xb1", "0xc1", "100", "0"); !errors.Is(err, ErrNoHolders) {
+		t.Errorf("Expected ErrNoHolders, got %v", err)
+	}
+
+	// A balance moved between reading the supply and reading the holders
+	changed := &fakeHolderSource{holders: holdersWithBalances("5", "5"), supply: "8"}
+	previewer = NewDistributionPreviewer(changed, NewTokenFormatter(nil))
+	if _, err := previewer.Preview(context.Background(), "0xb1", "0xc1", "100", "0"); !errors.Is(err, ErrHoldersChanged) {
+		t.Errorf("Expected ErrHoldersChanged, got %v", err)
+	}
+}
+
+func TestEntitlementStatsEvenMedian(t *testing.T) {
+	stats := entitlementStats([]*big.Int{big.NewInt(9), big.NewInt(1), big.NewInt(4), big.NewInt(7)}, 0)
+	if stats.MedianEntitlement != "5" || stats.MinEntitlement != "1" || stats.MaxEntitlement != "9" {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,645 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInjectorInProduction is returned when the event injector is created in production
+var ErrInjectorInProduction = errors.New("the event injector is not available in production")
+
+// ErrInvalidInjectedEvent is returned for unknown event types and payloads that fail validation
+var ErrInvalidInjectedEvent = errors.New("invalid injected event")
+
+// ErrNoScenarioSukuk is returned when a scenario names no sukuk and none is ready
+var ErrNoScenarioSukuk = errors.New("no ready sukuk metadata to build a scenario on; run `go run ./cmd/seed` or pass sukuk_addresses")
+
+// injectorLockKey serializes injections, so block numbers keep increasing across requests
+const injectorLockKey = 0x5eed
+
+// InjectableEventTypes are the event types the injector writes. Purchases and redemption
+// requests also write the holder_update row the indexer would record for the balance change
+var InjectableEventTypes = []string{"sukuk_purchase", "redemption_request", "redemption_approval", "yield_distributed", "yield_claim", "snapshot_taken"}
+
+// InjectedEvent is a row written by the injector
+type InjectedEvent struct {
+	EventType   string `json:"event_type"`
+	Table       string `json:"table"`
+	ID          string `json:"id"`
+	TxHash      string `json:"tx_hash"`
+	BlockNumber int64  `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+// InjectPurchase is the payload of a sukuk_purchase event
+type InjectPurchase struct {
+	Buyer        string `json:"buyer"`
+	SukukAddress string `json:"sukuk_address"`
+	PaymentToken string `json:"payment_token"`
+	Amount       string `json:"amount"`              // Raw sukuk token amount
+	Timestamp    int64  `json:"timestamp,omitempty"` // Unix seconds; defaults to now
+}
+
+// InjectRedemption is the payload of a redemption_request or redemption_approval event
+type InjectRedemption struct {
+	User         string `json:"user"`
+	SukukAddress string `json:"sukuk_address"`
+	PaymentToken string `json:"payment_token,omitempty"` // Requests only
+	Amount       string `json:"amount"`
+	Timestamp    int64  `json:"timestamp,omitempty"`
+}
+
+// InjectYieldDistribution is the payload of a yield_distributed event
+type InjectYieldDistribution struct {
+	SukukAddress   string `json:"sukuk_address"`
+	DistributionID int64  `json:"distribution_id,omitempty"` // Defaults to the sukuk's next distribution
+	PaymentToken   string `json:"payment_token"`
+	Amount         string `json:"amount"` // Raw payment token amount
+	Timestamp      int64  `json:"timestamp,omitempty"`
+}
+
+// InjectYieldClaim is the payload of a yield_claim event
+type InjectYieldClaim struct {
+	User           string `json:"user"`
+	SukukAddress   string `json:"sukuk_address"`
+	DistributionID int64  `json:"distribution_id"`
+	Amount         string `json:"amount"`
+	Timestamp      int64  `json:"timestamp,omitempty"`
+}
+
+// InjectSnapshot is the payload of a snapshot_taken event; supply and holder counts are read
+// from the injected holder balances
+type InjectSnapshot struct {
+	SukukAddress string `json:"sukuk_address"`
+	Timestamp    int64  `json:"timestamp,omitempty"`
+}
+
+// EventInjector writes synthetic indexer events into the hash-prefixed tables the seed data
+// uses, with the columns of the Indexer* structs, so local reads run the production queries
+type EventInjector struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+// NewEventInjector creates an injector, refusing the production environment
+func NewEventInjector(db *gorm.DB, environment string) (*EventInjector, error) {
+	if strings.EqualFold(strings.TrimSpace(environment), "production") {
+		return nil, ErrInjectorInProduction
+	}
+	return &EventInjector{db: db, now: time.Now}, nil
+}
+
+// Inject validates and writes one event of eventType
+func (i *EventInjector) Inject(ctx context.Context, eventType string, payload json.RawMessage) ([]InjectedEvent, error) {
+	write, err := decodeInjectedEvent(eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []InjectedEvent
+	err = i.transaction(ctx, func(w *eventWriter) error {
+		if err := write(w); err != nil {
+			return err
+		}
+		events = w.events
+		return nil
+	})
+	return events, err
+}
+
+// decodeInjectedEvent parses and validates a payload, returning the write it describes
+func decodeInjectedEvent(eventType string, payload json.RawMessage) (func(*eventWriter) error, error) {
+	decode := func(target interface{}) error {
+		decoder := json.NewDecoder(strings.NewReader(string(payload)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(target); err != nil {
+			return fmt.Errorf("%w: invalid %s payload: %v", ErrInvalidInjectedEvent, eventType, err)
+		}
+		return nil
+	}
+
+	switch eventType {
+	case "sukuk_purchase":
+		var event InjectPurchase
+		if err := decode(&event); err != nil {
+			return nil, err
+		}
+		if err := validateInjected(event.Amount, event.Buyer, event.SukukAddress, event.PaymentToken); err != nil {
+			return nil, err
+		}
+		return func(w *eventWriter) error { return w.purchase(event) }, nil
+	case "redemption_request", "redemption_approval":
+		var event InjectRedemption
+		if err := decode(&event); err != nil {
+			return nil, err
+		}
+		addresses := []string{event.User, event.SukukAddress}
+		if eventType == "redemption_request" {
+			addresses = append(addresses, event.PaymentToken)
+		}
+		if err := validateInjected(event.Amount, addresses...); err != nil {
+			return nil, err
+		}
+		if eventType == "redemption_request" {
+			return func(w *eventWriter) error { return w.redemptionRequest(event) }, nil
+		}
+		return func(w *eventWriter) error { return w.redemptionApproval(event) }, nil
+	case "yield_distributed":
+		var event InjectYieldDistribution
+		if err := decode(&event); err != nil {
+			return nil, err
+		}
+		if err := validateInjected(event.Amount, event.SukukAddress, event.PaymentToken); err != nil {
+			return nil, err
+		}
+		return func(w *eventWriter) error { return w.yieldDistribution(event) }, nil
+	case "yield_claim":
+		var event InjectYieldClaim
+		if err := decode(&event); err != nil {
+			return nil, err
+		}
+		if err := validateInjected(event.Amount, event.User, event.SukukAddress); err != nil {
+			return nil, err
+		}
+		if event.DistributionID <= 0 {
+			return nil, fmt.Errorf("%w: distribution_id is required", ErrInvalidInjectedEvent)
+		}
+		return func(w *eventWriter) error { return w.yieldClaim(event) }, nil
+	case "snapshot_taken":
+		var event InjectSnapshot
+		if err := decode(&event); err != nil {
+			return nil, err
+		}
+		if err := validateInjectedAddresses(event.SukukAddress); err != nil {
+			return nil, err
+		}
+		return func(w *eventWriter) error { return w.snapshot(event) }, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported event type %q (expected one of %s)", ErrInvalidInjectedEvent, eventType, strings.Join(InjectableEventTypes, ", "))
+}
+
+// validateInjected checks that amount is a positive integer and every address is well-formed
+func validateInjected(amount string, addresses ...string) error {
+	if !utils.GlobalTokenMath.IsPositive(amount) {
+		return fmt.Errorf("%w: amount must be a positive integer, got %q", ErrInvalidInjectedEvent, amount)
+	}
+	return validateInjectedAddresses(addresses...)
+}
+
+// validateInjectedAddresses checks that every address is well-formed
+func validateInjectedAddresses(addresses ...string) error {
+	for _, address := range addresses {
+		if !utils.IsValidEthereumAddress(address) {
+			return fmt.Errorf("%w: invalid address %q", ErrInvalidInjectedEvent, address)
+		}
+	}
+	return nil
+}
+
+// transaction runs fn with a writer holding the injector lock, after creating missing tables
+func (i *EventInjector) transaction(ctx context.Context, fn func(*eventWriter) error) error {
+	return i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", injectorLockKey).Error; err != nil {
+			return fmt.Errorf("failed to lock the injector: %w", err)
+		}
+		if err := database.CreateSyntheticIndexerTables(tx); err != nil {
+			return err
+		}
+
+		var block int64
+		var unions []string
+		for _, table := range database.SyntheticIndexerTableNames() {
+			unions = append(unions, fmt.Sprintf("SELECT MAX(block_number) AS block_number FROM %s", quoteIdentifier(table)))
+		}
+		query := "SELECT COALESCE(MAX(block_number), 0) FROM (" + strings.Join(unions, " UNION ALL ") + ") blocks"
+		if err := tx.Raw(query).Scan(&block).Error; err != nil {
+			return fmt.Errorf("failed to read the latest injected block: %w", err)
+		}
+
+		return fn(&eventWriter{tx: tx, block: block, now: i.now})
+	})
+}
+
+// eventWriter writes events in one transaction, each in its own block and transaction hash
+type eventWriter struct {
+	tx                 *gorm.DB
+	block              int64
+	now                func() time.Time
+	events             []InjectedEvent
+	lastDistributionID int64
+}
+
+// eventMeta identifies the onchain transaction of an injected event
+type eventMeta struct {
+	txHash    string
+	block     int64
+	timestamp int64
+}
+
+// id is the Ponder row id of the transaction's log at logIndex
+func (m eventMeta) id(logIndex int) string {
+	return fmt.Sprintf("%s-%d", m.txHash, logIndex)
+}
+
+// next starts a new transaction in the next block, at timestamp or now
+func (w *eventWriter) next(timestamp int64) (eventMeta, error) {
+	hash := make([]byte, 32)
+	if _, err := rand.Read(hash); err != nil {
+		return eventMeta{}, fmt.Errorf("failed to generate a transaction hash: %w", err)
+	}
+	if timestamp == 0 {
+		timestamp = w.now().Unix()
+	}
+	w.block++
+	return eventMeta{txHash: "0x" + hex.EncodeToString(hash), block: w.block, timestamp: timestamp}, nil
+}
+
+// insert writes row into the synthetic table of event and records it
+func (w *eventWriter) insert(event string, meta eventMeta, id string, row interface{}) error {
+	table := database.SyntheticIndexerTableName(event)
+	if err := w.tx.Table(table).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to insert into %s: %w", table, err)
+	}
+	w.events = append(w.events, InjectedEvent{
+		EventType:   event,
+		Table:       table,
+		ID:          id,
+		TxHash:      meta.txHash,
+		BlockNumber: meta.block,
+		Timestamp:   meta.timestamp,
+	})
+	return nil
+}
+
+// balance reads the holder's latest injected balance of a sukuk
+func (w *eventWriter) balance(sukukAddress, holder string) (string, error) {
+	var balances []string
+	query := fmt.Sprintf(latestHoldersQuery, quoteIdentifier(database.SyntheticIndexerTableName("holder_update")), "AND LOWER(holder) = LOWER(?)")
+	err := w.tx.Raw("SELECT new_balance::text FROM ("+query+") latest", sukukAddress, holder).Scan(&balances).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to read balance: %w", err)
+	}
+	if len(balances) == 0 {
+		return "0", nil
+	}
+	return balances[0], nil
+}
+
+// supply sums the latest injected balances of a sukuk and counts its holders
+func (w *eventWriter) supply(sukukAddress string) (string, int64, error) {
+	var result struct {
+		Supply  string
+		Holders int64
+	}
+	query := fmt.Sprintf(latestHoldersQuery, quoteIdentifier(database.SyntheticIndexerTableName("holder_update")), "")
+	err := w.tx.Raw("SELECT COALESCE(SUM(new_balance), 0)::text AS supply, COUNT(*) AS holders FROM ("+query+") latest WHERE new_balance > 0", sukukAddress).
+		Scan(&result).Error
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read supply: %w", err)
+	}
+	return result.Supply, result.Holders, nil
+}
+
+// updateHolder records the holder's new balance in the same transaction as the event
+func (w *eventWriter) updateHolder(meta eventMeta, sukukAddress, holder, balance string) error {
+	id := meta.id(1)
+	return w.insert("holder_update", meta, id, &IndexerHolderUpdated{
+		ID:           id,
+		SukukAddress: sukukAddress,
+		Holder:       holder,
+		Balance:      balance,
+		Timestamp:    meta.timestamp,
+		BlockNumber:  meta.block,
+		TxHash:       meta.txHash,
+	})
+}
+
+func (w *eventWriter) purchase(event InjectPurchase) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+	balance, err := w.balance(event.SukukAddress, event.Buyer)
+	if err != nil {
+		return err
+	}
+	newBalance, err := utils.GlobalTokenMath.AddTokenAmounts(balance, event.Amount)
+	if err != nil {
+		return err
+	}
+
+	id := meta.id(0)
+	err = w.insert("sukuk_purchase", meta, id, &IndexerSukukPurchase{
+		ID:           id,
+		Buyer:        event.Buyer,
+		SukukAddress: event.SukukAddress,
+		PaymentToken: event.PaymentToken,
+		Amount:       event.Amount,
+		BlockNumber:  meta.block,
+		TxHash:       meta.txHash,
+		Timestamp:    meta.timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	return w.updateHolder(meta, event.SukukAddress, event.Buyer, newBalance)
+}
+
+func (w *eventWriter) redemptionRequest(event InjectRedemption) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+	balance, err := w.balance(event.SukukAddress, event.User)
+	if err != nil {
+		return err
+	}
+	if cmp, err := utils.GlobalTokenMath.CompareTokenAmounts(event.Amount, balance); err != nil || cmp > 0 {
+		return fmt.Errorf("%w: redemption of %s exceeds the balance of %s", ErrInvalidInjectedEvent, event.Amount, balance)
+	}
+	newBalance, err := utils.GlobalTokenMath.SubtractTokenAmounts(balance, event.Amount)
+	if err != nil {
+		return err
+	}
+	supply, _, err := w.supply(event.SukukAddress)
+	if err != nil {
+		return err
+	}
+
+	id := meta.id(0)
+	err = w.insert("redemption_request", meta, id, &IndexerRedemptionRequest{
+		ID:           id,
+		User:         event.User,
+		SukukAddress: event.SukukAddress,
+		Amount:       event.Amount,
+		PaymentToken: event.PaymentToken,
+		TotalSupply:  supply,
+		BlockNumber:  meta.block,
+		TxHash:       meta.txHash,
+		Timestamp:    meta.timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	return w.updateHolder(meta, event.SukukAddress, event.User, newBalance)
+}
+
+func (w *eventWriter) redemptionApproval(event InjectRedemption) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+	supply, _, err := w.supply(event.SukukAddress)
+	if err != nil {
+		return err
+	}
+
+	id := meta.id(0)
+	return w.insert("redemption_approval", meta, id, &IndexerRedemptionApproval{
+		ID:           id,
+		User:         event.User,
+		SukukAddress: event.SukukAddress,
+		Amount:       event.Amount,
+		TotalSupply:  supply,
+		BlockNumber:  meta.block,
+		TxHash:       meta.txHash,
+		Timestamp:    meta.timestamp,
+	})
+}
+
+// yieldDistribution writes the distribution to both yield distribution tables, as the seed
+// data does, because queries look up each of them
+func (w *eventWriter) yieldDistribution(event InjectYieldDistribution) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+	if event.DistributionID == 0 {
+		table := quoteIdentifier(database.SyntheticIndexerTableName("yield_distributed"))
+		err := w.tx.Raw("SELECT COALESCE(MAX(distribution_id), 0) + 1 FROM "+table+" WHERE LOWER(sukuk_address) = LOWER(?)", event.SukukAddress).
+			Scan(&event.DistributionID).Error
+		if err != nil {
+			return fmt.Errorf("failed to read the next distribution id: %w", err)
+		}
+	}
+
+	w.lastDistributionID = event.DistributionID
+
+	id := meta.id(0)
+	for _, table := range []string{"yield_distributed", "yield_distribution"} {
+		err := w.insert(table, meta, id, &IndexerYieldDistributed{
+			ID:             id,
+			SukukAddress:   event.SukukAddress,
+			DistributionId: event.DistributionID,
+			PaymentToken:   event.PaymentToken,
+			Amount:         event.Amount,
+			Timestamp:      meta.timestamp,
+			BlockNumber:    meta.block,
+			TxHash:         meta.txHash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *eventWriter) yieldClaim(event InjectYieldClaim) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	id := meta.id(0)
+	return w.insert("yield_claim", meta, id, &IndexerYieldClaimed{
+		ID:             id,
+		User:           event.User,
+		SukukAddress:   event.SukukAddress,
+		DistributionId: event.DistributionID,
+		Amount:         event.Amount,
+		Timestamp:      meta.timestamp,
+		BlockNumber:    meta.block,
+		TxHash:         meta.txHash,
+	})
+}
+
+func (w *eventWriter) snapshot(event InjectSnapshot) error {
+	meta, err := w.next(event.Timestamp)
+	if err != nil {
+		return err
+	}
+	supply, holders, err := w.supply(event.SukukAddress)
+	if err != nil {
+		return err
+	}
+	var snapshotID int64
+	table := quoteIdentifier(database.SyntheticIndexerTableName(snapshotEventType))
+	err = w.tx.Raw("SELECT COALESCE(MAX(snapshot_id), 0) + 1 FROM "+table+" WHERE LOWER(sukuk_address) = LOWER(?)", event.SukukAddress).
+		Scan(&snapshotID).Error
+	if err != nil {
+		return fmt.Errorf("failed to read the next snapshot id: %w", err)
+	}
+
+	id := meta.id(0)
+	return w.insert(snapshotEventType, meta, id, &IndexerSnapshotTaken{
+		ID:            id,
+		SukukAddress:  event.SukukAddress,
+		SnapshotId:    snapshotID,
+		TotalSupply:   supply,
+		HolderCount:   holders,
+		EligibleCount: holders,
+		Timestamp:     meta.timestamp,
+		BlockNumber:   meta.block,
+		TxHash:        meta.txHash,
+	})
+}
+
+// ScenarioRequest selects the wallet and sukuk of a demo scenario
+type ScenarioRequest struct {
+	WalletAddress  string   `json:"wallet_address,omitempty"`  // Defaults to a new random wallet
+	SukukAddresses []string `json:"sukuk_addresses,omitempty"` // Defaults to the first two ready sukuk
+}
+
+// Scenario is the outcome of GenerateScenario
+type Scenario struct {
+	WalletAddress  string          `json:"wallet_address"`
+	SukukAddresses []string        `json:"sukuk_addresses"`
+	Events         []InjectedEvent `json:"events"`
+}
+
+// Scenario amounts, in whole sukuk tokens of 18 decimals and raw payment token units
+var (
+	scenarioPurchases     = []int64{100, 250, 150}
+	scenarioDistributions = []string{"5000000", "7500000"}
+)
+
+// scenarioRedemption is the amount the wallet redeems from its first sukuk
+const scenarioRedemption = 50
+
+// scenarioClaim is the yield the wallet claims from the first distribution of its first sukuk
+const scenarioClaim = "1000000"
+
+// ScenarioBalance is the balance a scenario leaves the wallet with in its nth sukuk
+func ScenarioBalance(n int) string {
+	var total int64
+	for _, amount := range scenarioPurchases {
+		total += amount
+	}
+	if n == 0 {
+		total -= scenarioRedemption
+	}
+	return wholeTokens(total)
+}
+
+// wholeTokens converts whole sukuk tokens to an 18-decimal raw amount
+func wholeTokens(whole int64) string {
+	return fmt.Sprintf("%d000000000000000000", whole)
+}
+
+// GenerateScenario gives a wallet a full demo history in every sukuk of the request: three
+// purchases, a snapshot and two yield distributions each, plus a claimed distribution and
+// an approved redemption in the first sukuk. Events are spread over the last 30 days
+func (i *EventInjector) GenerateScenario(ctx context.Context, req ScenarioRequest) (*Scenario, error) {
+	wallet := req.WalletAddress
+	if wallet == "" {
+		address := make([]byte, 20)
+		if _, err := rand.Read(address); err != nil {
+			return nil, fmt.Errorf("failed to generate a wallet: %w", err)
+		}
+		wallet = "0x" + hex.EncodeToString(address)
+	}
+	if !utils.IsValidEthereumAddress(wallet) {
+		return nil, fmt.Errorf("%w: invalid wallet_address %q", ErrInvalidInjectedEvent, wallet)
+	}
+	if err := validateInjectedAddresses(req.SukukAddresses...); err != nil {
+		return nil, err
+	}
+
+	sukukAddresses := req.SukukAddresses
+	if len(sukukAddresses) == 0 {
+		err := i.db.WithContext(ctx).Model(&models.SukukMetadata{}).
+			Where("metadata_ready = ?", true).
+			Order("id").Limit(2).
+			Pluck("contract_address", &sukukAddresses).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sukuk metadata: %w", err)
+		}
+		if len(sukukAddresses) == 0 {
+			return nil, ErrNoScenarioSukuk
+		}
+	}
+
+	paymentToken := "0x0000000000000000000000000000000000000000"
+	tokens, err := models.GetAllPaymentTokens(i.db.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment tokens: %w", err)
+	}
+	if len(tokens) > 0 {
+		paymentToken = tokens[0].Address
+	}
+
+	scenario := &Scenario{WalletAddress: wallet, SukukAddresses: sukukAddresses}
+	err = i.transaction(ctx, func(w *eventWriter) error {
+		at := i.now().AddDate(0, 0, -30)
+		tick := func() int64 {
+			at = at.Add(12 * time.Hour)
+			return at.Unix()
+		}
+
+		for n, sukuk := range sukukAddresses {
+			for _, amount := range scenarioPurchases {
+				purchase := InjectPurchase{Buyer: wallet, SukukAddress: sukuk, PaymentToken: paymentToken, Amount: wholeTokens(amount), Timestamp: tick()}
+				if err := w.purchase(purchase); err != nil {
+					return err
+				}
+			}
+			if n == 0 {
+				redemption := InjectRedemption{User: wallet, SukukAddress: sukuk, PaymentToken: paymentToken, Amount: wholeTokens(scenarioRedemption), Timestamp: tick()}
+				if err := w.redemptionRequest(redemption); err != nil {
+					return err
+				}
+				redemption.Timestamp = tick()
+				if err := w.redemptionApproval(redemption); err != nil {
+					return err
+				}
+			}
+			if err := w.snapshot(InjectSnapshot{SukukAddress: sukuk, Timestamp: tick()}); err != nil {
+				return err
+			}
+
+			first := int64(0)
+			for _, amount := range scenarioDistributions {
+				if err := w.yieldDistribution(InjectYieldDistribution{SukukAddress: sukuk, PaymentToken: paymentToken, Amount: amount, Timestamp: tick()}); err != nil {
+					return err
+				}
+				if first == 0 {
+					first = w.lastDistributionID
+				}
+			}
+			if n == 0 {
+				claim := InjectYieldClaim{User: wallet, SukukAddress: sukuk, DistributionID: first, Amount: scenarioClaim, Timestamp: tick()}
+				if err := w.yieldClaim(claim); err != nil {
+					return err
+				}
+			}
+		}
+		scenario.Events = w.events
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
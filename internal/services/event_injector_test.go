@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/testutil"
+)
+
+func TestNewEventInjectorRefusesProduction(t *testing.T) {
+	for _, environment := range []string{"production", " Production "} {
+		if _, err := NewEventInjector(nil, environment); !errors.Is(err, ErrInjectorInProduction) {
+			t.Errorf("%q: expected ErrInjectorInProduction, got %v", environment, err)
+		}
+	}
+	if _, err := NewEventInjector(nil, "development"); err != nil {
+		t.Errorf("Expected the injector in development, got %v", err)
+	}
+}
+
+func TestInjectRejectsInvalidEvents(t *testing.T) {
+	// Validation runs before the database is touched, so a nil connection is enough
+	injector, _ := NewEventInjector(nil, "development")
+	const (
+		wallet = "0x00000000000000000000000000000000000000a1"
+		sukuk  = "0x00000000000000000000000000000000000000b1"
+		token  = "0x00000000000000000000000000000000000000c1"
+	)
+	tests := []struct {
+		eventType string
+		payload   string
+	}{
+		{"holder_update", `{}`},
+		{"sukuk_purchase", `{"buyer":"` + wallet + `","sukuk_address":"` + sukuk + `","payment_token":"` + token + `","amount":"-5"}`},
+		{"sukuk_purchase", `{"buyer":"` + wallet + `","sukuk_address":"` + sukuk + `","payment_token":"` + token + `","amount":"1.5"}`},
+		{"sukuk_purchase", `{"buyer":"wallet","sukuk_address":"` + sukuk + `","payment_token":"` + token + `","amount":"100"}`},
+		{"sukuk_purchase", `{"buyer":"` + wallet + `","sukuk":"` + sukuk + `","payment_token":"` + token + `","amount":"100"}`},
+		{"redemption_request", `{"user":"` + wallet + `","sukuk_address":"` + sukuk + `","amount":"100"}`},
+		{"yield_claim", `{"user":"` + wallet + `","sukuk_address":"` + sukuk + `","amount":"100"}`},
+		{"snapshot_taken", `{"sukuk_address":"0x1234"}`},
+		{"yield_distributed", `[]`},
+	}
+	for _, tt := range tests {
+		if _, err := injector.Inject(context.Background(), tt.eventType, json.RawMessage(tt.payload)); !errors.Is(err, ErrInvalidInjectedEvent) {
+			t.Errorf("%s %s: expected ErrInvalidInjectedEvent, got %v", tt.eventType, tt.payload, err)
+		}
+	}
+}
+
+func TestScenarioBalance(t *testing.T) {
+	if got := ScenarioBalance(0); got != "450000000000000000000" {
+		t.Errorf("Expected 450 tokens in the first sukuk, got %s", got)
+	}
+	if got := ScenarioBalance(1); got != "500000000000000000000" {
+		t.Errorf("Expected 500 tokens in the other sukuk, got %s", got)
+	}
+}
+
+// TestGenerateScenarioPortfolio requires a reachable Postgres, see testutil.Open
+func TestGenerateScenarioPortfolio(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	injector, err := NewEventInjector(db, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	scenario, err := injector.GenerateScenario(ctx, ScenarioRequest{SukukAddresses: []string{
+		"0x00000000000000000000000000000000005eedd1",
+		"0x00000000000000000000000000000000005eedd2",
+	}})
+	if err != nil {
+		t.Fatalf("Failed to generate the scenario: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, table := range database.SyntheticIndexerTableNames() {
+			db.Exec(`DELETE FROM "`+table+`" WHERE tx_hash IN ?`, scenarioTxHashes(scenario))
+		}
+	})
+
+	portfolio, err := NewIndexerQueryService().GetUserPortfolio(ctx, scenario.WalletAddress)
+	if err != nil {
+		t.Fatalf("Failed to read the portfolio: %v", err)
+	}
+	if len(portfolio.Holdings) != len(scenario.SukukAddresses) {
+		t.Fatalf("Expected %d holdings, got %+v", len(scenario.SukukAddresses), portfolio.Holdings)
+	}
+	for _, holding := range portfolio.Holdings {
+		want := ScenarioBalance(1)
+		if holding.SukukAddress == scenario.SukukAddresses[0] {
+			want = ScenarioBalance(0)
+		}
+		if holding.Balance != want {
+			t.Errorf("%s: expected balance %s, got %s", holding.SukukAddress, want, holding.Balance)
+		}
+	}
+}
+
+func scenarioTxHashes(scenario *Scenario) []string {
+	hashes := make([]string, len(scenario.Events))
+	for i, event := range scenario.Events {
+		hashes[i] = event.TxHash
+	}
+	return hashes
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// DefaultFileLinkTTL is how long a signed file link stays valid when none is configured
+const DefaultFileLinkTTL = 5 * time.Minute
+
+// File link errors
+var (
+	ErrInvalidFileLink = errors.New("invalid file link")
+	ErrFileLinkExpired = errors.New("file link expired")
+	ErrFileNotFound    = errors.New("file not found")
+)
+
+// NewFileLinkToken returns a token for a link to a document file, authenticating the
+// document ID with an HMAC-SHA256 under secret until expiresAt
+func NewFileLinkToken(secret string, documentID uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d|%d", documentID, expiresAt.Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(fileLinkMAC(secret, encoded))
+}
+
+// ParseFileLinkToken returns the document ID of a token made by NewFileLinkToken
+// The signature is checked before the expiry, so an expired token is still a genuine one
+func ParseFileLinkToken(secret, token string, now time.Time) (uint, error) {
+	encoded, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, ErrInvalidFileLink
+	}
+	provided, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(provided, fileLinkMAC(secret, encoded)) {
+		return 0, ErrInvalidFileLink
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidFileLink
+	}
+	id, expiry, ok := strings.Cut(string(payload), "|")
+	if !ok {
+		return 0, ErrInvalidFileLink
+	}
+	documentID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil || documentID == 0 {
+		return 0, ErrInvalidFileLink
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidFileLink
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return 0, ErrFileLinkExpired
+	}
+	return uint(documentID), nil
+}
+
+func fileLinkMAC(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("file-link|"))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// FileOpener opens stored files for streaming
+type FileOpener interface {
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, UploadFile, error)
+}
+
+// Open returns a stored file for reading. Returns ErrFileNotFound when it is missing
+func (s *LocalUploadStorage) Open(ctx context.Context, name string) (io.ReadSeekCloser, UploadFile, error) {
+	clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+	file, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(clean)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, UploadFile{}, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, UploadFile{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, UploadFile{}, err
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, UploadFile{}, ErrFileNotFound
+	}
+	return file, UploadFile{Name: clean, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// OpenSukukDocument opens the stored file of a document. Returns ErrFileNotFound when
+// its URL is outside the upload path or the file is gone
+func OpenSukukDocument(ctx context.Context, storage FileOpener, document *models.SukukDocument) (io.ReadSeekCloser, UploadFile, error) {
+	name := uploadNameFromURL(document.FileURL)
+	if name == "" {
+		return nil, UploadFile{}, ErrFileNotFound
+	}
+	return storage.Open(ctx, name)
+}
+
+// sukukDocumentDir matches the directory Upload stores a sukuk's documents in
+var sukukDocumentDir = regexp.MustCompile(`^documents/sukuk_[0-9]+/$`)
+
+// IsProspectusUpload reports whether a stored upload name is a prospectus file, which is
+// only served through signed links: a prospectus document version, or a file of the
+// former single-prospectus upload
+func IsProspectusUpload(name string) bool {
+	clean := strings.ToLower(strings.TrimPrefix(path.Clean("/"+name), "/"))
+	if legacyProspectusName.MatchString(path.Base(clean)) {
+		return true
+	}
+	dir, file := path.Split(clean)
+	return sukukDocumentDir.MatchString(dir) && strings.HasPrefix(file, string(models.SukukDocumentProspectus)+"_")
+}
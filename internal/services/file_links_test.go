@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFileLinkToken(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	token := NewFileLinkToken("secret", 42, now.Add(5*time.Minute))
+
+	documentID, err := ParseFileLinkToken("secret", token, now)
+	if err != nil || documentID != 42 {
+		t.Fatalf("Expected document 42, got %d, %v", documentID, err)
+	}
+	if _, err := ParseFileLinkToken("other", token, now); !errors.Is(err, ErrInvalidFileLink) {
+		t.Errorf("Expected a token under another secret to be rejected, got %v", err)
+	}
+	if _, err := ParseFileLinkToken("secret", token+"x", now); !errors.Is(err, ErrInvalidFileLink) {
+		t.Errorf("Expected a tampered token to be rejected, got %v", err)
+	}
+	if _, err := ParseFileLinkToken("secret", token, now.Add(5*time.Minute)); !errors.Is(err, ErrFileLinkExpired) {
+		t.Errorf("Expected the token to expire, got %v", err)
+	}
+
+	// Unsubscribe tokens under the same secret don't open files
+	unsubscribe := NewUnsubscribeToken("secret", "0x00000000000000000000000000000000000000ab", "all", now.Add(time.Hour))
+	if _, err := ParseFileLinkToken("secret", unsubscribe, now); !errors.Is(err, ErrInvalidFileLink) {
+		t.Errorf("Expected an unsubscribe token to be rejected, got %v", err)
+	}
+}
+
+func TestIsProspectusUpload(t *testing.T) {
+	for name, want := range map[string]bool{
+		"documents/sukuk_3/prospectus_1700000000.pdf":  true,
+		"/documents/sukuk_3/PROSPECTUS_1700000000.pdf": true,
+		"documents/sukuk_3/x/../prospectus_1.pdf":      true,
+		"sukuk_12_prospectus.pdf":                      true,
+		"documents/sukuk_3/fact_sheet_1700000000.pdf":  false,
+		"logos/prospectus_1.png":                       false,
+		"documents/sukuk_3/":                           false,
+	} {
+		if got := IsProspectusUpload(name); got != want {
+			t.Errorf("IsProspectusUpload(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
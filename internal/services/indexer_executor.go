@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/metrics"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrIndexerUnavailable is returned without querying while the indexer circuit breaker is open
+var ErrIndexerUnavailable = errors.New("indexer unavailable")
+
+// IndexerUnavailableError reports an open circuit breaker and when it will next let a read through
+// It matches ErrIndexerUnavailable with errors.Is
+type IndexerUnavailableError struct {
+	RetryAfter time.Duration
+}
+
+func (e *IndexerUnavailableError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrIndexerUnavailable, e.RetryAfter.Round(time.Second))
+}
+
+func (e *IndexerUnavailableError) Is(target error) bool {
+	return target == ErrIndexerUnavailable
+}
+
+// BreakerState is the state of a CircuitBreaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = metrics.BreakerClosed   // Reads flow normally
+	BreakerHalfOpen BreakerState = metrics.BreakerHalfOpen // One probe read decides whether to close again
+	BreakerOpen     BreakerState = metrics.BreakerOpen     // Reads fail fast until the cool-down ends
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker opens after threshold consecutive failures and rejects calls for cooldown,
+// then lets a single probe through: success closes it, failure opens it again
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// State returns the current state, moving an expired open breaker to half-open
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expireLocked()
+	return b.state
+}
+
+// Allow reports whether a call may proceed, or how long until the breaker may let one through
+func (b *CircuitBreaker) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.expireLocked()
+	switch b.state {
+	case BreakerOpen:
+		return false, b.cooldown - b.now().Sub(b.openedAt)
+	case BreakerHalfOpen:
+		if b.probing {
+			return false, b.cooldown
+		}
+		b.probing = true
+	}
+	return true, 0
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	b.setStateLocked(BreakerClosed)
+}
+
+// RecordFailure counts a failure, opening the breaker at the threshold or after a failed probe
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setStateLocked(BreakerOpen)
+	}
+}
+
+// releaseProbe lets another call probe a half-open breaker after an inconclusive one
+func (b *CircuitBreaker) releaseProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *CircuitBreaker) expireLocked() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.setStateLocked(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) setStateLocked(state BreakerState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	metrics.IndexerBreakerState.Set(float64(state))
+	metrics.IndexerBreakerTransitions.WithLabelValues(state.String()).Inc()
+
+	entry := logger.WithField("state", state.String())
+	if state == BreakerOpen {
+		entry.WithField("cooldown", b.cooldown.String()).Warn("Indexer circuit breaker opened")
+	} else {
+		entry.Info("Indexer circuit breaker state changed")
+	}
+}
+
+// IndexerExecutorConfig tunes retries and the circuit breaker
+type IndexerExecutorConfig struct {
+	MaxRetries       int           // Retries after the first attempt for transient errors
+	BaseDelay        time.Duration // First backoff delay, doubled per retry
+	MaxDelay         time.Duration // Backoff cap
+	BreakerThreshold int           // Consecutive failed reads that open the breaker
+	BreakerCooldown  time.Duration // How long the breaker stays open before probing
+}
+
+// DefaultIndexerExecutorConfig returns the settings used when none are configured
+func DefaultIndexerExecutorConfig() IndexerExecutorConfig {
+	return IndexerExecutorConfig{
+		MaxRetries:       2,
+		BaseDelay:        50 * time.Millisecond,
+		MaxDelay:         time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// IndexerExecutor runs indexer reads with bounded, jittered retries for transient errors
+// behind a shared circuit breaker
+type IndexerExecutor struct {
+	cfg     IndexerExecutorConfig
+	breaker *CircuitBreaker
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewIndexerExecutor creates an executor with its own circuit breaker
+func NewIndexerExecutor(cfg IndexerExecutorConfig) *IndexerExecutor {
+	return &IndexerExecutor{
+		cfg:     cfg,
+		breaker: NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		sleep:   sleepContext,
+	}
+}
+
+// Breaker exposes the executor's circuit breaker
+func (e *IndexerExecutor) Breaker() *CircuitBreaker {
+	return e.breaker
+}
+
+// Do runs read, retrying transient errors. Any error that isn't the caller giving up counts
+// against the breaker once the retries are spent, timeouts and permanent errors included, as
+// a struggling indexer shows up as both; a missing row is an answer, not a failure
+func (e *IndexerExecutor) Do(ctx context.Context, read func() error) error {
+	allowed, retryAfter := e.breaker.Allow()
+	if !allowed {
+		metrics.IndexerQueryRejections.Inc()
+		return &IndexerUnavailableError{RetryAfter: retryAfter}
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = read()
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) {
+			e.breaker.RecordSuccess()
+			return err
+		}
+		if ctx.Err() != nil || !isTransientIndexerError(err) || attempt >= e.cfg.MaxRetries {
+			break
+		}
+
+		metrics.IndexerQueryRetries.Inc()
+		if sleepErr := e.sleep(ctx, e.backoff(attempt)); sleepErr != nil {
+			break
+		}
+	}
+
+	// A cancelled caller says nothing about the indexer
+	if ctx.Err() != nil {
+		e.breaker.releaseProbe()
+	} else {
+		e.breaker.RecordFailure()
+	}
+	return err
+}
+
+// backoff returns a full-jitter delay for the given retry
+func (e *IndexerExecutor) backoff(attempt int) time.Duration {
+	delay := e.cfg.BaseDelay << attempt
+	if delay <= 0 || delay > e.cfg.MaxDelay {
+		delay = e.cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// transientPgCodes are Postgres errors worth retrying: serialization failures, deadlocks,
+// connection limits and server restarts. Statement timeouts are not retried
+var transientPgCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// isTransientIndexerError reports whether err is likely to clear up on retry
+func isTransientIndexerError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientPgCodes[pgErr.Code] || strings.HasPrefix(pgErr.Code, "08") // connection_exception class
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, driver.ErrBadConn)
+}
+
+var (
+	indexerExecutorMu sync.RWMutex
+	indexerExecutor   = NewIndexerExecutor(DefaultIndexerExecutorConfig())
+)
+
+// DefaultIndexerExecutor returns the executor shared by all indexer reads
+func DefaultIndexerExecutor() *IndexerExecutor {
+	indexerExecutorMu.RLock()
+	defer indexerExecutorMu.RUnlock()
+	return indexerExecutor
+}
+
+// SetDefaultIndexerExecutor replaces the shared executor, e.g. with configured settings
+func SetDefaultIndexerExecutor(e *IndexerExecutor) {
+	indexerExecutorMu.Lock()
+	defer indexerExecutorMu.Unlock()
+	indexerExecutor = e
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// errorInjector fails the next n reads with err, then succeeds
+type errorInjector struct {
+	err   error
+	fails int
+	calls int
+}
+
+func (f *errorInjector) read() error {
+	f.calls++
+	if f.fails > 0 {
+		f.fails--
+		return f.err
+	}
+	return nil
+}
+
+func newTestExecutor(threshold, retries int, cooldown time.Duration) (*IndexerExecutor, *time.Time) {
+	e := NewIndexerExecutor(IndexerExecutorConfig{
+		MaxRetries:       retries,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         time.Millisecond,
+		BreakerThreshold: threshold,
+		BreakerCooldown:  cooldown,
+	})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	e.breaker.now = func() time.Time { return now }
+	e.sleep = func(context.Context, time.Duration) error { return nil }
+	return e, &now
+}
+
+func TestIndexerExecutorRetriesTransientErrors(t *testing.T) {
+	e, _ := newTestExecutor(5, 2, time.Minute)
+	injector := &errorInjector{err: &pgconn.PgError{Code: "57P01"}, fails: 2}
+
+	if err := e.Do(context.Background(), injector.read); err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if injector.calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", injector.calls)
+	}
+	if state := e.Breaker().State(); state != BreakerClosed {
+		t.Errorf("Expected breaker closed, got %s", state)
+	}
+}
+
+func TestIndexerExecutorDoesNotRetryPermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		errors.New("relation does not exist"),
+		&pgconn.PgError{Code: "57014"}, // statement timeout
+		context.Canceled,
+	} {
+		e, _ := newTestExecutor(5, 3, time.Minute)
+		injector := &errorInjector{err: err, fails: 1}
+		if got := e.Do(context.Background(), injector.read); !errors.Is(got, err) {
+			t.Errorf("Expected %v to be returned, got %v", err, got)
+		}
+		if injector.calls != 1 {
+			t.Errorf("Expected %v not to be retried, got %d attempts", err, injector.calls)
+		}
+	}
+}
+
+func TestIndexerExecutorCountsEveryFailure(t *testing.T) {
+	for _, err := range []error{
+		errors.New("relation does not exist"),
+		&pgconn.PgError{Code: "57014"}, // statement timeout
+	} {
+		e, _ := newTestExecutor(2, 3, time.Minute)
+		for i := 0; i < 2; i++ {
+			e.Do(context.Background(), (&errorInjector{err: err, fails: 1}).read)
+		}
+		if state := e.Breaker().State(); state != BreakerOpen {
+			t.Errorf("Expected repeated %v to open the breaker, got %s", err, state)
+		}
+	}
+
+	// Neither a caller giving up nor a missing row says anything about the indexer
+	e, _ := newTestExecutor(1, 3, time.Minute)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Do(cancelled, (&errorInjector{err: context.Canceled, fails: 1}).read)
+	if err := e.Do(context.Background(), (&errorInjector{err: gorm.ErrRecordNotFound, fails: 1}).read); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the missing row to be returned, got %v", err)
+	}
+	if state := e.Breaker().State(); state != BreakerClosed {
+		t.Errorf("Expected the breaker to stay closed, got %s", state)
+	}
+}
+
+func TestIndexerExecutorBreakerCycle(t *testing.T) {
+	e, now := newTestExecutor(2, 1, 30*time.Second)
+	down := &errorInjector{err: &pgconn.ConnectError{}, fails: 100}
+
+	// Two reads exhaust their retries and open the breaker
+	for i := 0; i < 2; i++ {
+		if err := e.Do(context.Background(), down.read); errors.Is(err, ErrIndexerUnavailable) {
+			t.Fatalf("Read %d was rejected before the threshold", i)
+		}
+	}
+	if state := e.Breaker().State(); state != BreakerOpen {
+		t.Fatalf("Expected breaker open, got %s", state)
+	}
+
+	// Open: rejected without querying
+	calls := down.calls
+	err := e.Do(context.Background(), down.read)
+	var unavailable *IndexerUnavailableError
+	if !errors.As(err, &unavailable) || !errors.Is(err, ErrIndexerUnavailable) {
+		t.Fatalf("Expected ErrIndexerUnavailable, got %v", err)
+	}
+	if unavailable.RetryAfter != 30*time.Second {
+		t.Errorf("Expected Retry-After of 30s, got %s", unavailable.RetryAfter)
+	}
+	if down.calls != calls {
+		t.Error("Expected the open breaker to skip the query")
+	}
+
+	// Half-open: a failed probe opens it again
+	*now = now.Add(30 * time.Second)
+	if state := e.Breaker().State(); state != BreakerHalfOpen {
+		t.Fatalf("Expected breaker half-open after the cool-down, got %s", state)
+	}
+	if err := e.Do(context.Background(), down.read); errors.Is(err, ErrIndexerUnavailable) {
+		t.Fatal("Expected the probe to reach the database")
+	}
+	if state := e.Breaker().State(); state != BreakerOpen {
+		t.Fatalf("Expected a failed probe to reopen the breaker, got %s", state)
+	}
+
+	// Half-open: a successful probe closes it
+	*now = now.Add(30 * time.Second)
+	up := &errorInjector{}
+	if err := e.Do(context.Background(), up.read); err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if state := e.Breaker().State(); state != BreakerClosed {
+		t.Errorf("Expected breaker closed after a successful probe, got %s", state)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	b := NewCircuitBreaker(1, time.Second)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	b.RecordFailure()
+	now = now.Add(time.Second)
+
+	if ok, _ := b.Allow(); !ok {
+		t.Fatal("Expected the first half-open call to be allowed")
+	}
+	if ok, _ := b.Allow(); ok {
+		t.Error("Expected concurrent calls to wait for the probe")
+	}
+}
+
+func TestIsTransientIndexerError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pgconn.PgError{Code: "40001"}, true},
+		{&pgconn.PgError{Code: "08006"}, true},
+		{&pgconn.PgError{Code: "57014"}, false},
+		{&pgconn.PgError{Code: "42P01"}, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := isTransientIndexerError(tt.err); got != tt.want {
+			t.Errorf("isTransientIndexerError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"sukuk-be/internal/config"
 	"sukuk-be/internal/database"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/utils"
@@ -15,12 +19,16 @@ import (
 type IndexerQueryService struct {
 	indexerDB    *gorm.DB
 	tableService *IndexerTableService
+	supply       *SupplyService // Total supply lookups, shared so every calculation uses the same figure
+	projection   bool           // Activity lists read the activity_projections read model instead of the indexer tables
 }
 
 // NewIndexerQueryService creates a new service to query indexer database
 func NewIndexerQueryService() *IndexerQueryService {
 	return &IndexerQueryService{
 		tableService: NewIndexerTableService(),
+		supply:       DefaultSupplyService(),
+		projection:   ActivityProjectionReads(),
 	}
 }
 
@@ -35,18 +43,141 @@ func (s *IndexerQueryService) ConnectToIndexer() error {
 	return s.tableService.ConnectToIndexer()
 }
 
-// GetLatestActivities queries the indexer database directly for latest activities
-func (s *IndexerQueryService) GetLatestActivities(sukukAddress string, limit int) ([]models.ActivityEvent, error) {
+// supplies returns the injected supply service, or the shared one
+func (s *IndexerQueryService) supplies() *SupplyService {
+	if s.supply != nil {
+		return s.supply
+	}
+	return DefaultSupplyService()
+}
+
+// read runs a query against the indexer through the shared executor, which retries
+// transient failures and fails fast while the indexer circuit breaker is open
+func (s *IndexerQueryService) read(ctx context.Context, query func(db *gorm.DB) error) error {
+	return DefaultIndexerExecutor().Do(ctx, func() error {
+		return query(s.indexerDB.WithContext(ctx))
+	})
+}
+
+// GetMaxBlockNumber returns the highest block number in the latest tables of eventTypes, or -1
+// when none of them has a table or a row
+func (s *IndexerQueryService) GetMaxBlockNumber(ctx context.Context, eventTypes ...string) (int64, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return -1, err
+		}
+	}
+
+	tables, err := s.tableService.GetAllLatestTables()
+	if err != nil {
+		return -1, fmt.Errorf("failed to find indexer tables: %w", err)
+	}
+
+	maxBlock := int64(-1)
+	for _, eventType := range eventTypes {
+		table, ok := tables[eventType]
+		if !ok {
+			continue
+		}
+		var block int64
+		err := s.read(ctx, func(db *gorm.DB) error {
+			return db.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), -1) FROM %s", quoteIdentifier(table))).Scan(&block).Error
+		})
+		if err != nil {
+			return -1, fmt.Errorf("failed to read the max block of %s: %w", table, err)
+		}
+		if block > maxBlock {
+			maxBlock = block
+		}
+	}
+	return maxBlock, nil
+}
+
+// Latest activities served per sukuk; the maximum also bounds ACTIVITY_LATEST_LIMIT
+const (
+	DefaultLatestActivitiesLimit = 10
+	MaxLatestActivitiesLimit     = config.MaxActivityLatestLimit
+)
+
+// latestActivitiesLimit is the deployment's activities limit when neither the request nor the
+// sukuk_metadata.activities_limit setting gives one
+var (
+	latestActivitiesLimitMu sync.RWMutex
+	latestActivitiesLimit   = DefaultLatestActivitiesLimit
+)
+
+// LatestActivitiesLimit returns the deployment's activities limit
+func LatestActivitiesLimit() int {
+	latestActivitiesLimitMu.RLock()
+	defer latestActivitiesLimitMu.RUnlock()
+	return latestActivitiesLimit
+}
+
+// SetLatestActivitiesLimit replaces the deployment's activities limit, e.g. from
+// ACTIVITY_LATEST_LIMIT, returning the previous limit
+func SetLatestActivitiesLimit(limit int) int {
+	latestActivitiesLimitMu.Lock()
+	defer latestActivitiesLimitMu.Unlock()
+	previous := latestActivitiesLimit
+	latestActivitiesLimit = limit
+	return previous
+}
+
+// clampActivitiesLimit defaults an unset activities limit and caps it at the maximum
+func clampActivitiesLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLatestActivitiesLimit
+	}
+	return min(limit, MaxLatestActivitiesLimit)
+}
+
+// GetLatestActivities gets the latest purchases and redemption requests of a sukuk, from the
+// activity projection or, with projection reads off, the indexer tables
+func (s *IndexerQueryService) GetLatestActivities(ctx context.Context, sukukAddress string, limit int) ([]models.ActivityEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
 		}
 	}
 
-	if limit == 0 {
-		limit = 10
+	limit = clampActivitiesLimit(limit)
+
+	var activities []models.ActivityEvent
+	var err error
+	if s.projection {
+		activities, err = s.projectedActivities(ctx, "sukuk_address", sukukAddress, limit)
+	} else {
+		activities, err = s.indexedActivities(ctx, "sukuk_address", "sukuk_address", sukukAddress, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort by timestamp descending and limit
+	for i := 0; i < len(activities)-1; i++ {
+		for j := i + 1; j < len(activities); j++ {
+			if activities[i].Timestamp.Before(activities[j].Timestamp) {
+				activities[i], activities[j] = activities[j], activities[i]
+			}
+		}
 	}
 
+	if len(activities) > limit {
+		activities = activities[:limit]
+	}
+
+	// Enrich activities with sukuk metadata
+	enrichedActivities, err := s.enrichActivitiesWithSukukMetadata(ctx, activities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enrich activities with sukuk metadata: %w", err)
+	}
+
+	return enrichedActivities, nil
+}
+
+// indexedActivities queries the indexer tables for the newest purchases whose purchaseColumn
+// and redemption requests whose redemptionColumn matches value
+func (s *IndexerQueryService) indexedActivities(ctx context.Context, purchaseColumn, redemptionColumn, value string, limit int) ([]models.ActivityEvent, error) {
 	var activities []models.ActivityEvent
 
 	// Get latest table names using dynamic discovery
@@ -60,32 +191,33 @@ func (s *IndexerQueryService) GetLatestActivities(sukukAddress string, limit int
 		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
 	}
 
-	// Query sukuk_purchase table directly from indexer
 	var purchases []IndexerSukukPurchase
-	err = s.indexerDB.Table(purchaseTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		Limit(limit).
-		Find(&purchases).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(purchaseTable).
+			Where(purchaseColumn+" = ?", value).
+			Order("timestamp DESC").
+			Limit(limit).
+			Find(&purchases).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sukuk purchases from %s: %w", purchaseTable, err)
 	}
 
-	// Query redemption_request table directly from indexer
 	var redemptions []IndexerRedemptionRequest
-	err = s.indexerDB.Table(redemptionTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		Limit(limit).
-		Find(&redemptions).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(redemptionTable).
+			Where(redemptionColumn+" = ?", value).
+			Order("timestamp DESC").
+			Limit(limit).
+			Find(&redemptions).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query redemption requests from %s: %w", redemptionTable, err)
 	}
 
-	// Convert to ActivityEvent and merge
 	for _, p := range purchases {
 		activities = append(activities, models.ActivityEvent{
-			Type:         "purchase",
+			Type:         models.ActivityTypePurchase,
 			Address:      p.Buyer,
 			Amount:       p.Amount,
 			TxHash:       p.TxHash,
@@ -96,7 +228,7 @@ func (s *IndexerQueryService) GetLatestActivities(sukukAddress string, limit int
 
 	for _, r := range redemptions {
 		activities = append(activities, models.ActivityEvent{
-			Type:         "redemption_request",
+			Type:         models.ActivityTypeRedemptionRequest,
 			Address:      r.User,
 			Amount:       r.Amount,
 			TxHash:       r.TxHash,
@@ -104,31 +236,128 @@ func (s *IndexerQueryService) GetLatestActivities(sukukAddress string, limit int
 			SukukAddress: r.SukukAddress,
 		})
 	}
+	return activities, nil
+}
 
-	// Sort by timestamp descending and limit
-	for i := 0; i < len(activities)-1; i++ {
-		for j := i + 1; j < len(activities); j++ {
-			if activities[i].Timestamp.Before(activities[j].Timestamp) {
-				activities[i], activities[j] = activities[j], activities[i]
-			}
+// GetLatestActivitiesBySukuk gets the latest activities for several sukuk at once, keyed by
+// lowercase sukuk address. Each table is queried once with a per-sukuk row limit instead of
+// once per sukuk.
+func (s *IndexerQueryService) GetLatestActivitiesBySukuk(ctx context.Context, sukukAddresses []string, limit int) (map[string][]models.ActivityEvent, error) {
+	if len(sukukAddresses) == 0 {
+		return map[string][]models.ActivityEvent{}, nil
+	}
+
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
 		}
 	}
 
-	if len(activities) > limit {
-		activities = activities[:limit]
+	limit = clampActivitiesLimit(limit)
+
+	var activities []models.ActivityEvent
+	var err error
+	if s.projection {
+		activities, err = s.projectedActivitiesBySukuk(ctx, sukukAddresses, limit)
+	} else {
+		activities, err = s.indexedActivitiesBySukuk(ctx, sukukAddresses, limit)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// Enrich activities with sukuk metadata
-	enrichedActivities, err := s.enrichActivitiesWithSukukMetadata(activities)
+	// One metadata lookup for every sukuk instead of one per sukuk
+	enrichedActivities, err := s.enrichActivitiesWithSukukMetadata(ctx, activities)
 	if err != nil {
 		return nil, fmt.Errorf("failed to enrich activities with sukuk metadata: %w", err)
 	}
 
-	return enrichedActivities, nil
+	return groupLatestActivities(enrichedActivities, limit), nil
+}
+
+// indexedActivitiesBySukuk queries the indexer tables for the newest limit purchases and
+// redemption requests of each sukuk
+func (s *IndexerQueryService) indexedActivitiesBySukuk(ctx context.Context, sukukAddresses []string, limit int) ([]models.ActivityEvent, error) {
+	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sukuk_purchase table: %w", err)
+	}
+
+	redemptionTable, err := s.tableService.GetLatestTableForEvent("redemption_request")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
+	}
+
+	var purchases []IndexerSukukPurchase
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table("(?) AS ranked", latestPerSukuk(db, purchaseTable, sukukAddresses)).
+			Where("rn <= ?", limit).
+			Find(&purchases).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sukuk purchases from %s: %w", purchaseTable, err)
+	}
+
+	var redemptions []IndexerRedemptionRequest
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table("(?) AS ranked", latestPerSukuk(db, redemptionTable, sukukAddresses)).
+			Where("rn <= ?", limit).
+			Find(&redemptions).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query redemption requests from %s: %w", redemptionTable, err)
+	}
+
+	activities := make([]models.ActivityEvent, 0, len(purchases)+len(redemptions))
+	for _, p := range purchases {
+		activities = append(activities, models.ActivityEvent{
+			Type:         models.ActivityTypePurchase,
+			Address:      p.Buyer,
+			Amount:       p.Amount,
+			TxHash:       p.TxHash,
+			Timestamp:    time.Unix(p.Timestamp, 0),
+			SukukAddress: p.SukukAddress,
+		})
+	}
+	for _, r := range redemptions {
+		activities = append(activities, models.ActivityEvent{
+			Type:         models.ActivityTypeRedemptionRequest,
+			Address:      r.User,
+			Amount:       r.Amount,
+			TxHash:       r.TxHash,
+			Timestamp:    time.Unix(r.Timestamp, 0),
+			SukukAddress: r.SukukAddress,
+		})
+	}
+	return activities, nil
+}
+
+// latestPerSukuk ranks a table's rows by recency within each sukuk
+func latestPerSukuk(db *gorm.DB, table string, sukukAddresses []string) *gorm.DB {
+	return db.Table(table).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY sukuk_address ORDER BY timestamp DESC) AS rn").
+		Where("sukuk_address IN ?", sukukAddresses)
+}
+
+// groupLatestActivities groups activities by lowercase sukuk address, newest first,
+// keeping at most limit per sukuk
+func groupLatestActivities(activities []models.ActivityEvent, limit int) map[string][]models.ActivityEvent {
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Timestamp.After(activities[j].Timestamp)
+	})
+
+	grouped := make(map[string][]models.ActivityEvent)
+	for _, activity := range activities {
+		key := strings.ToLower(activity.SukukAddress)
+		if len(grouped[key]) < limit {
+			grouped[key] = append(grouped[key], activity)
+		}
+	}
+	return grouped
 }
 
 // GetSukukPurchases gets purchase events for a specific sukuk
-func (s *IndexerQueryService) GetSukukPurchases(sukukAddress string, limit int) ([]IndexerSukukPurchase, error) {
+func (s *IndexerQueryService) GetSukukPurchases(ctx context.Context, sukukAddress string, limit int) ([]IndexerSukukPurchase, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -142,23 +371,52 @@ func (s *IndexerQueryService) GetSukukPurchases(sukukAddress string, limit int)
 	}
 
 	var purchases []IndexerSukukPurchase
-	query := s.indexerDB.Table(purchaseTable).
-		Order("timestamp DESC")
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := db.Table(purchaseTable).
+			Order("timestamp DESC")
+
+		if sukukAddress != "" {
+			query = query.Where("sukuk_address = ?", sukukAddress)
+		}
+
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+
+		return query.Find(&purchases).Error
+	})
+	return purchases, err
+}
 
-	if sukukAddress != "" {
-		query = query.Where("sukuk_address = ?", sukukAddress)
+// GetTotalPurchased sums the amount of every purchase event of a sukuk, as a raw integer string
+func (s *IndexerQueryService) GetTotalPurchased(ctx context.Context, sukukAddress string) (string, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return "", err
+		}
 	}
 
-	if limit > 0 {
-		query = query.Limit(limit)
+	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
+	if err != nil {
+		return "", fmt.Errorf("failed to find sukuk_purchase table: %w", err)
 	}
 
-	err = query.Find(&purchases).Error
-	return purchases, err
+	var total string
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT COALESCE(SUM(amount), 0)::text
+			FROM %s
+			WHERE LOWER(sukuk_address) = LOWER(?)`, quoteIdentifier(purchaseTable))
+		return db.Raw(query, sukukAddress).Scan(&total).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sum purchases: %w", err)
+	}
+	return total, nil
 }
 
 // GetRedemptionRequests gets redemption request events for a specific sukuk
-func (s *IndexerQueryService) GetRedemptionRequests(sukukAddress string, limit int) ([]IndexerRedemptionRequest, error) {
+func (s *IndexerQueryService) GetRedemptionRequests(ctx context.Context, sukukAddress string, limit int) ([]IndexerRedemptionRequest, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -172,23 +430,27 @@ func (s *IndexerQueryService) GetRedemptionRequests(sukukAddress string, limit i
 	}
 
 	var redemptions []IndexerRedemptionRequest
-	query := s.indexerDB.Table(redemptionTable).
-		Order("timestamp DESC")
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := db.Table(redemptionTable).
+			Order("timestamp DESC")
 
-	if sukukAddress != "" {
-		query = query.Where("sukuk_address = ?", sukukAddress)
-	}
+		if sukukAddress != "" {
+			query = query.Where("sukuk_address = ?", sukukAddress)
+		}
 
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
 
-	err = query.Find(&redemptions).Error
+		return query.Find(&redemptions).Error
+	})
 	return redemptions, err
 }
 
-// GetActivitiesByAddress gets all activities (purchases + redemptions) for a specific address
-func (s *IndexerQueryService) GetActivitiesByAddress(userAddress string, limit int) ([]models.ActivityEvent, error) {
+// GetActivitiesByAddress gets all activities (purchases, redemptions and transfers) for a specific address.
+// Purchases and redemptions come from the activity projection unless projection reads are off;
+// transfers are always derived from the indexer's holder updates
+func (s *IndexerQueryService) GetActivitiesByAddress(ctx context.Context, userAddress string, limit int) ([]models.ActivityEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -200,8 +462,60 @@ func (s *IndexerQueryService) GetActivitiesByAddress(userAddress string, limit i
 	}
 
 	var activities []models.ActivityEvent
+	var err error
+	if s.projection {
+		activities, err = s.projectedActivities(ctx, "address", userAddress, limit)
+	} else {
+		activities, err = s.indexedActivities(ctx, "buyer", `"user"`, userAddress, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Transfers from and to other wallets; an indexer without holder updates has none
+	if _, err := s.tableService.GetLatestTableForEvent("holder_update"); err == nil {
+		transfers, err := s.GetUserTransfers(ctx, userAddress, "", limit)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, transfersToActivities(userAddress, transfers)...)
+	}
+
+	// Sort by timestamp descending and limit
+	for i := 0; i < len(activities)-1; i++ {
+		for j := i + 1; j < len(activities); j++ {
+			if activities[i].Timestamp.Before(activities[j].Timestamp) {
+				activities[i], activities[j] = activities[j], activities[i]
+			}
+		}
+	}
+
+	if len(activities) > limit {
+		activities = activities[:limit]
+	}
+
+	// Enrich activities with sukuk metadata
+	enrichedActivities, err := s.enrichActivitiesWithSukukMetadata(ctx, activities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enrich activities with sukuk metadata: %w", err)
+	}
+
+	return enrichedActivities, nil
+}
+
+// GetActivitiesSince gets purchases and redemption requests across all sukuk with a
+// timestamp at or after since (unix seconds), oldest first
+func (s *IndexerQueryService) GetActivitiesSince(ctx context.Context, since int64, limit int) ([]models.ActivityEvent, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit == 0 {
+		limit = 100
+	}
 
-	// Get latest table names using dynamic discovery
 	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find sukuk_purchase table: %w", err)
@@ -212,32 +526,34 @@ func (s *IndexerQueryService) GetActivitiesByAddress(userAddress string, limit i
 		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
 	}
 
-	// Query sukuk_purchase table for user's purchases
 	var purchases []IndexerSukukPurchase
-	err = s.indexerDB.Table(purchaseTable).
-		Where("buyer = ?", userAddress).
-		Order("timestamp DESC").
-		Limit(limit).
-		Find(&purchases).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(purchaseTable).
+			Where("timestamp >= ?", since).
+			Order("timestamp ASC").
+			Limit(limit).
+			Find(&purchases).Error
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user purchases from %s: %w", purchaseTable, err)
+		return nil, fmt.Errorf("failed to query sukuk purchases from %s: %w", purchaseTable, err)
 	}
 
-	// Query redemption_request table for user's redemptions
 	var redemptions []IndexerRedemptionRequest
-	err = s.indexerDB.Table(redemptionTable).
-		Where("user = ?", userAddress).
-		Order("timestamp DESC").
-		Limit(limit).
-		Find(&redemptions).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(redemptionTable).
+			Where("timestamp >= ?", since).
+			Order("timestamp ASC").
+			Limit(limit).
+			Find(&redemptions).Error
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query user redemptions from %s: %w", redemptionTable, err)
+		return nil, fmt.Errorf("failed to query redemption requests from %s: %w", redemptionTable, err)
 	}
 
-	// Convert purchases to ActivityEvent
+	activities := make([]models.ActivityEvent, 0, len(purchases)+len(redemptions))
 	for _, p := range purchases {
 		activities = append(activities, models.ActivityEvent{
-			Type:         "purchase",
+			Type:         models.ActivityTypePurchase,
 			Address:      p.Buyer,
 			Amount:       p.Amount,
 			TxHash:       p.TxHash,
@@ -245,11 +561,9 @@ func (s *IndexerQueryService) GetActivitiesByAddress(userAddress string, limit i
 			SukukAddress: p.SukukAddress,
 		})
 	}
-
-	// Convert redemptions to ActivityEvent
 	for _, r := range redemptions {
 		activities = append(activities, models.ActivityEvent{
-			Type:         "redemption_request",
+			Type:         models.ActivityTypeRedemptionRequest,
 			Address:      r.User,
 			Amount:       r.Amount,
 			TxHash:       r.TxHash,
@@ -258,30 +572,21 @@ func (s *IndexerQueryService) GetActivitiesByAddress(userAddress string, limit i
 		})
 	}
 
-	// Sort by timestamp descending and limit
-	for i := 0; i < len(activities)-1; i++ {
-		for j := i + 1; j < len(activities); j++ {
-			if activities[i].Timestamp.Before(activities[j].Timestamp) {
-				activities[i], activities[j] = activities[j], activities[i]
-			}
-		}
-	}
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Timestamp.Before(activities[j].Timestamp)
+	})
 
+	// Each table was limited on its own; trim so the caller's cursor never skips rows
+	// from the other table that fall before the last returned timestamp
 	if len(activities) > limit {
 		activities = activities[:limit]
 	}
 
-	// Enrich activities with sukuk metadata
-	enrichedActivities, err := s.enrichActivitiesWithSukukMetadata(activities)
-	if err != nil {
-		return nil, fmt.Errorf("failed to enrich activities with sukuk metadata: %w", err)
-	}
-
-	return enrichedActivities, nil
+	return s.enrichActivitiesWithSukukMetadata(ctx, activities)
 }
 
 // GetSukukOwnedByAddress gets unique sukuk addresses that a user has purchased
-func (s *IndexerQueryService) GetSukukOwnedByAddress(userAddress string) ([]string, error) {
+func (s *IndexerQueryService) GetSukukOwnedByAddress(ctx context.Context, userAddress string) ([]string, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -297,10 +602,12 @@ func (s *IndexerQueryService) GetSukukOwnedByAddress(userAddress string) ([]stri
 	var sukukAddresses []string
 	
 	// Query for distinct sukuk addresses from purchases
-	err = s.indexerDB.Table(purchaseTable).
-		Select("DISTINCT sukuk_address").
-		Where("buyer = ?", userAddress).
-		Pluck("sukuk_address", &sukukAddresses).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(purchaseTable).
+			Select("DISTINCT sukuk_address").
+			Where("buyer = ?", userAddress).
+			Pluck("sukuk_address", &sukukAddresses).Error
+	})
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to query owned sukuk addresses from %s: %w", purchaseTable, err)
@@ -310,7 +617,7 @@ func (s *IndexerQueryService) GetSukukOwnedByAddress(userAddress string) ([]stri
 }
 
 // GetUnclaimedDistributionIds returns distribution IDs that a user can claim for a specific sukuk
-func (s *IndexerQueryService) GetUnclaimedDistributionIds(userAddress string, sukukAddress string) ([]int64, error) {
+func (s *IndexerQueryService) GetUnclaimedDistributionIds(ctx context.Context, userAddress string, sukukAddress string) ([]int64, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -330,19 +637,23 @@ func (s *IndexerQueryService) GetUnclaimedDistributionIds(userAddress string, su
 
 	// Get all yield distributions for this sukuk
 	var distributions []IndexerYieldDistributed
-	err = s.indexerDB.Table(distributedTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("distribution_id ASC").
-		Find(&distributions).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(distributedTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("distribution_id ASC").
+			Find(&distributions).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query yield distributions from %s: %w", distributedTable, err)
 	}
 
 	// Get all yield claims by this user for this sukuk
 	var claims []IndexerYieldClaimed
-	err = s.indexerDB.Table(claimedTable).
-		Where("user = ? AND sukuk_address = ?", userAddress, sukukAddress).
-		Find(&claims).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(claimedTable).
+			Where("user = ? AND sukuk_address = ?", userAddress, sukukAddress).
+			Find(&claims).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query yield claims from %s: %w", claimedTable, err)
 	}
@@ -365,7 +676,7 @@ func (s *IndexerQueryService) GetUnclaimedDistributionIds(userAddress string, su
 }
 
 // enrichActivitiesWithSukukMetadata enriches activities with sukuk metadata (code and title)
-func (s *IndexerQueryService) enrichActivitiesWithSukukMetadata(activities []models.ActivityEvent) ([]models.ActivityEvent, error) {
+func (s *IndexerQueryService) enrichActivitiesWithSukukMetadata(ctx context.Context, activities []models.ActivityEvent) ([]models.ActivityEvent, error) {
 	if len(activities) == 0 {
 		return activities, nil
 	}
@@ -384,7 +695,9 @@ func (s *IndexerQueryService) enrichActivitiesWithSukukMetadata(activities []mod
 
 	// Batch fetch sukuk metadata
 	var sukukMetadata []models.SukukMetadata
-	err := s.indexerDB.Where("contract_address IN ?", sukukAddresses).Find(&sukukMetadata).Error
+	err := s.read(ctx, func(db *gorm.DB) error {
+		return db.Where("contract_address IN ?", sukukAddresses).Find(&sukukMetadata).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch sukuk metadata: %w", err)
 	}
@@ -466,6 +779,32 @@ type IndexerYieldClaimed struct {
 	TxHash         string `gorm:"column:tx_hash"`
 }
 
+// IndexerYieldDeposited is a yield_deposit event: an issuer funding the yield vault of a sukuk
+type IndexerYieldDeposited struct {
+	ID           string `gorm:"column:id"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	Depositor    string `gorm:"column:depositor"`
+	PaymentToken string `gorm:"column:payment_token"`
+	Amount       string `gorm:"column:amount"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+	TxHash       string `gorm:"column:tx_hash"`
+}
+
+// IndexerVaultUpdated is a vault_update event: a sukuk pointing its yield vault at a new address
+type IndexerVaultUpdated struct {
+	ID           string `gorm:"column:id"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	OldVault     string `gorm:"column:old_vault"`
+	NewVault     string `gorm:"column:new_vault"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+	TxHash       string `gorm:"column:tx_hash"`
+}
+
+// snapshotEventType is the EventTableMapping key for SnapshotTaken tables
+const snapshotEventType = "snapshot_taken"
+
 type IndexerSnapshotTaken struct {
 	ID            string `gorm:"column:id"`
 	SukukAddress  string `gorm:"column:sukuk_address"`
@@ -490,139 +829,174 @@ type IndexerHolderUpdated struct {
 
 // Portfolio and yield calculation methods
 
-// GetUserPortfolio calculates user's portfolio with holdings and claimable yields
-func (s *IndexerQueryService) GetUserPortfolio(userAddress string) (*UserPortfolio, error) {
+// GetSukukPositions resolves a user's position in each of the given sukuk, keyed by lowercase
+// address. Balances come from one holder_update query; unclaimed distributions and claimable
+// yield are only queried for sukuk the user holds, the rest get a zero position
+func (s *IndexerQueryService) GetSukukPositions(ctx context.Context, userAddress string, sukukAddresses []string) (map[string]SukukUserPosition, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
 		}
 	}
 
-	portfolio := &UserPortfolio{
-		Address:  userAddress,
-		Holdings: []SukukHolding{},
-	}
-
-	// Get all sukuk addresses owned by user
-	sukukAddresses, err := s.GetSukukOwnedByAddress(userAddress)
+	holdings, err := s.GetHoldings(ctx, userAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get owned sukuk: %w", err)
+		return nil, err
+	}
+	mathUtil := utils.GlobalTokenMath
+	balances := make(map[string]IndexerHolderUpdated, len(holdings))
+	for _, holding := range holdings {
+		if mathUtil.IsPositive(holding.Balance) {
+			balances[strings.ToLower(holding.SukukAddress)] = holding
+		}
 	}
 
-	// For each sukuk, calculate holdings and claimable yields
-	for _, sukukAddr := range sukukAddresses {
-		holding, err := s.GetSukukHolding(userAddress, sukukAddr)
-		if err != nil {
-			// Log error but continue with other sukuk
+	positions := make(map[string]SukukUserPosition, len(sukukAddresses))
+	for _, sukukAddress := range sukukAddresses {
+		key := strings.ToLower(sukukAddress)
+		holding, held := balances[key]
+		if !held {
+			positions[key] = SukukUserPosition{Balance: "0", ClaimableAmount: "0"}
 			continue
 		}
-		if holding != nil {
-			portfolio.Holdings = append(portfolio.Holdings, *holding)
+
+		// Per-sukuk queries use the indexer's own spelling of the addresses
+		unclaimed, err := s.GetUnclaimedDistributionIds(ctx, holding.Holder, holding.SukukAddress)
+		if err != nil {
+			return nil, err
+		}
+		claimable, err := s.GetClaimableYield(ctx, holding.Holder, holding.SukukAddress)
+		if err != nil {
+			return nil, err
+		}
+		positions[key] = SukukUserPosition{
+			Balance:                    holding.Balance,
+			UnclaimedDistributionCount: len(unclaimed),
+			ClaimableAmount:            claimable,
 		}
 	}
-
-	return portfolio, nil
+	return positions, nil
 }
 
-// GetSukukHolding calculates user's holding and claimable yield for a specific sukuk
-func (s *IndexerQueryService) GetSukukHolding(userAddress, sukukAddress string) (*SukukHolding, error) {
-	// Get current balance from holder_update table
-	balance, err := s.GetCurrentBalance(userAddress, sukukAddress)
+// GetCurrentBalance gets user's current balance for a sukuk from holder_update table
+func (s *IndexerQueryService) GetCurrentBalance(ctx context.Context, userAddress, sukukAddress string) (string, error) {
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
 	if err != nil {
-		return nil, err
+		return "0", fmt.Errorf("failed to find holder_update table: %w", err)
 	}
 
-	if balance == "0" {
-		return nil, nil // User doesn't hold this sukuk anymore
-	}
+	var holder IndexerHolderUpdated
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(holderTable).
+			Where("holder = ? AND sukuk_address = ?", userAddress, sukukAddress).
+			Order("timestamp DESC").
+			First(&holder).Error
+	})
 
-	// Get claimable yield
-	claimableYield, err := s.GetClaimableYield(userAddress, sukukAddress)
 	if err != nil {
-		return nil, err
-	}
-
-	holding := &SukukHolding{
-		SukukAddress:   sukukAddress,
-		Balance:        balance,
-		ClaimableYield: claimableYield,
+		if err == gorm.ErrRecordNotFound {
+			return "0", nil
+		}
+		return "0", fmt.Errorf("failed to query current balance: %w", err)
 	}
 
-	return holding, nil
+	return holder.Balance, nil
 }
 
-// GetCurrentBalance gets user's current balance for a sukuk from holder_update table
-func (s *IndexerQueryService) GetCurrentBalance(userAddress, sukukAddress string) (string, error) {
+// latestHoldersQuery selects the latest holder_update row per holder of a sukuk,
+// formatted with the table name and an extra condition on holder
+const latestHoldersQuery = `
+	SELECT DISTINCT ON (holder) holder, new_balance
+	FROM %s
+	WHERE LOWER(sukuk_address) = LOWER(?) %s
+	ORDER BY holder, block_number DESC, timestamp DESC, id DESC`
+
+// GetHolderSupply sums the current non-zero balances of every holder of a sukuk
+func (s *IndexerQueryService) GetHolderSupply(ctx context.Context, sukukAddress string) (string, error) {
 	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
 	if err != nil {
 		return "0", fmt.Errorf("failed to find holder_update table: %w", err)
 	}
 
-	var holder IndexerHolderUpdated
-	err = s.indexerDB.Table(holderTable).
-		Where("holder = ? AND sukuk_address = ?", userAddress, sukukAddress).
-		Order("timestamp DESC").
-		First(&holder).Error
+	var supply string
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`SELECT COALESCE(SUM(new_balance), 0)::text FROM (`+latestHoldersQuery+`) latest WHERE new_balance > 0`, quoteIdentifier(holderTable), "")
+		return db.Raw(query, sukukAddress).Scan(&supply).Error
+	})
+	if err != nil {
+		return "0", fmt.Errorf("failed to query holder supply: %w", err)
+	}
+	return supply, nil
+}
 
+// ForEachHolderBatch passes the current non-zero holders of a sukuk to fn in batches,
+// ordered by holder address, reading one batch at a time
+func (s *IndexerQueryService) ForEachHolderBatch(ctx context.Context, sukukAddress string, batchSize int, fn func([]IndexerHolderUpdated) error) error {
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return "0", nil
-		}
-		return "0", fmt.Errorf("failed to query current balance: %w", err)
+		return fmt.Errorf("failed to find holder_update table: %w", err)
 	}
 
-	return holder.Balance, nil
+	query := fmt.Sprintf(`SELECT holder, new_balance::text AS new_balance FROM (`+latestHoldersQuery+`) latest
+		WHERE new_balance > 0 ORDER BY holder LIMIT ?`, quoteIdentifier(holderTable), "AND holder > ?")
+	after := ""
+	for {
+		var batch []IndexerHolderUpdated
+		err := s.read(ctx, func(db *gorm.DB) error {
+			return db.Raw(query, sukukAddress, after, batchSize).Scan(&batch).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to query holders: %w", err)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].Holder
+	}
 }
 
 // GetClaimableYield calculates claimable yield by comparing distributed vs claimed
-func (s *IndexerQueryService) GetClaimableYield(userAddress, sukukAddress string) (string, error) {
-	mathUtil := utils.GlobalTokenMath
-	
+func (s *IndexerQueryService) GetClaimableYield(ctx context.Context, userAddress, sukukAddress string) (string, error) {
 	// Get total yield distributed for this sukuk
-	totalDistributed, err := s.GetTotalYieldDistributed(sukukAddress)
+	totalDistributed, err := s.GetTotalYieldDistributed(ctx, sukukAddress)
 	if err != nil {
 		return "0", err
 	}
 
 	// Get total yield claimed by user for this sukuk  
-	totalClaimed, err := s.GetTotalYieldClaimed(userAddress, sukukAddress)
+	totalClaimed, err := s.GetTotalYieldClaimed(ctx, userAddress, sukukAddress)
 	if err != nil {
 		return "0", err
 	}
 
-	// Get user's share percentage based on current holdings
+	// Get user's share based on current holdings
 	// This is simplified - ideally should check balance at each distribution snapshot
-	sharePercentage, err := s.GetUserSharePercentage(userAddress, sukukAddress)
+	userBalance, supply, err := s.GetUserShare(ctx, userAddress, sukukAddress)
 	if err != nil {
 		return "0", err
 	}
-
-	// Calculate user's entitled yield = totalDistributed * sharePercentage
-	entitledYield, err := mathUtil.MultiplyTokenAmount(totalDistributed, sharePercentage)
-	if err != nil {
-		return "0", fmt.Errorf("failed to calculate entitled yield: %w", err)
-	}
-
-	// Calculate claimable = entitledYield - totalClaimed
-	claimableYield, err := mathUtil.SubtractTokenAmounts(entitledYield, totalClaimed)
-	if err != nil {
-		return "0", fmt.Errorf("failed to calculate claimable yield: %w", err)
-	}
-
-	return claimableYield, nil
+	return claimableYield(totalDistributed, totalClaimed, userBalance, supply.Amount)
 }
 
 // GetTotalYieldDistributed gets total yield distributed for a sukuk
-func (s *IndexerQueryService) GetTotalYieldDistributed(sukukAddress string) (string, error) {
+func (s *IndexerQueryService) GetTotalYieldDistributed(ctx context.Context, sukukAddress string) (string, error) {
 	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
 	if err != nil {
 		return "0", fmt.Errorf("failed to find yield_distributed table: %w", err)
 	}
 
 	var yields []IndexerYieldDistributed
-	err = s.indexerDB.Table(yieldTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Find(&yields).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(yieldTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Find(&yields).Error
+	})
 
 	if err != nil {
 		return "0", fmt.Errorf("failed to query yield distributions: %w", err)
@@ -644,16 +1018,18 @@ func (s *IndexerQueryService) GetTotalYieldDistributed(sukukAddress string) (str
 }
 
 // GetTotalYieldClaimed gets total yield claimed by user for a sukuk
-func (s *IndexerQueryService) GetTotalYieldClaimed(userAddress, sukukAddress string) (string, error) {
+func (s *IndexerQueryService) GetTotalYieldClaimed(ctx context.Context, userAddress, sukukAddress string) (string, error) {
 	claimedTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
 	if err != nil {
 		return "0", fmt.Errorf("failed to find yield_claim table: %w", err)
 	}
 
 	var claims []IndexerYieldClaimed
-	err = s.indexerDB.Table(claimedTable).
-		Where("user = ? AND sukuk_address = ?", userAddress, sukukAddress).
-		Find(&claims).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(claimedTable).
+			Where("user = ? AND sukuk_address = ?", userAddress, sukukAddress).
+			Find(&claims).Error
+	})
 
 	if err != nil {
 		return "0", fmt.Errorf("failed to query yield claims: %w", err)
@@ -674,139 +1050,193 @@ func (s *IndexerQueryService) GetTotalYieldClaimed(userAddress, sukukAddress str
 	return total, nil
 }
 
-// GetUserSharePercentage calculates user's ownership percentage of a sukuk
-func (s *IndexerQueryService) GetUserSharePercentage(userAddress, sukukAddress string) (float64, error) {
+// GetUserShare returns a user's current balance of a sukuk and the current supply it is a share of
+// A user without a balance gets a zero balance and models.SupplySourceNone without further lookups
+func (s *IndexerQueryService) GetUserShare(ctx context.Context, userAddress, sukukAddress string) (string, Supply, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return "0", Supply{Source: models.SupplySourceNone}, err
+		}
+	}
 	mathUtil := utils.GlobalTokenMath
 	
 	// Get user's current balance
-	userBalance, err := s.GetCurrentBalance(userAddress, sukukAddress)
+	userBalance, err := s.GetCurrentBalance(ctx, userAddress, sukukAddress)
 	if err != nil {
-		return 0, err
+		return "0", Supply{Source: models.SupplySourceNone}, err
 	}
 
 	// If user has no balance, share is 0%
 	if mathUtil.IsZero(userBalance) {
-		return 0.0, nil
+		return "0", Supply{Source: models.SupplySourceNone}, nil
 	}
 
-	// Get total supply from latest redemption request (which includes totalSupply)
-	// or snapshot table which also tracks totalSupply
-	totalSupply, err := s.getTotalSupplyFromSnapshot(sukukAddress)
+	supply, err := s.supplies().GetTotalSupplyAt(ctx, sukukAddress, time.Now())
 	if err != nil {
-		// Fallback: try to get from redemption events
-		totalSupply, err = s.getTotalSupplyFromRedemption(sukukAddress)
-		if err != nil {
-			return 0, fmt.Errorf("failed to get total supply: %w", err)
-		}
+		return "0", Supply{Source: models.SupplySourceNone}, fmt.Errorf("failed to get total supply: %w", err)
 	}
-
-	// Calculate percentage: userBalance / totalSupply
-	percentage, err := mathUtil.CalculatePercentage(userBalance, totalSupply)
-	if err != nil {
-		return 0, fmt.Errorf("failed to calculate percentage: %w", err)
-	}
-
-	return percentage, nil
+	return userBalance, supply, nil
 }
 
 // GetYieldDistributions gets yield distribution events for a sukuk
-func (s *IndexerQueryService) GetYieldDistributions(sukukAddress string, limit int) ([]IndexerYieldDistributed, error) {
+func (s *IndexerQueryService) GetYieldDistributions(ctx context.Context, sukukAddress string, limit int) ([]IndexerYieldDistributed, error) {
 	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
 	}
 
 	var yields []IndexerYieldDistributed
-	query := s.indexerDB.Table(yieldTable).
-		Order("timestamp DESC")
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := db.Table(yieldTable).
+			Order("timestamp DESC")
 
-	if sukukAddress != "" {
-		query = query.Where("sukuk_address = ?", sukukAddress)
-	}
+		if sukukAddress != "" {
+			query = query.Where("sukuk_address = ?", sukukAddress)
+		}
 
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
 
-	err = query.Find(&yields).Error
+		return query.Find(&yields).Error
+	})
 	return yields, err
 }
 
 // GetYieldClaims gets yield claim events for a user/sukuk
-func (s *IndexerQueryService) GetYieldClaims(userAddress, sukukAddress string, limit int) ([]IndexerYieldClaimed, error) {
+func (s *IndexerQueryService) GetYieldClaims(ctx context.Context, userAddress, sukukAddress string, limit int) ([]IndexerYieldClaimed, error) {
 	claimedTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find yield_claim table: %w", err)
 	}
 
 	var claims []IndexerYieldClaimed
-	query := s.indexerDB.Table(claimedTable).
-		Order("timestamp DESC")
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := db.Table(claimedTable).
+			Order("timestamp DESC")
 
-	if userAddress != "" {
-		query = query.Where("user = ?", userAddress)
-	}
+		if userAddress != "" {
+			query = query.Where("user = ?", userAddress)
+		}
 
-	if sukukAddress != "" {
-		query = query.Where("sukuk_address = ?", sukukAddress)
-	}
+		if sukukAddress != "" {
+			query = query.Where("sukuk_address = ?", sukukAddress)
+		}
 
-	if limit > 0 {
-		query = query.Limit(limit)
+		if limit > 0 {
+			query = query.Limit(limit)
+		}
+
+		return query.Find(&claims).Error
+	})
+	return claims, err
+}
+
+// GetYieldClaimsBetween gets a user's yield claim events with from <= timestamp < to, oldest first
+func (s *IndexerQueryService) GetYieldClaimsBetween(ctx context.Context, userAddress string, from, to time.Time) ([]IndexerYieldClaimed, error) {
+	claimedTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_claim table: %w", err)
 	}
 
-	err = query.Find(&claims).Error
+	var claims []IndexerYieldClaimed
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(claimedTable).
+			Where("user = ? AND timestamp >= ? AND timestamp < ?", userAddress, from.Unix(), to.Unix()).
+			Order("timestamp ASC").
+			Find(&claims).Error
+	})
 	return claims, err
 }
 
-// getTotalSupplyFromSnapshot gets total supply from snapshot table
-func (s *IndexerQueryService) getTotalSupplyFromSnapshot(sukukAddress string) (string, error) {
-	snapshotTable, err := s.tableService.GetLatestTableForEvent("snapshot")
+// GetSukukEventsBetween loads the events of one indexer event type, e.g. "sukuk_purchase",
+// emitted by the given sukuk with from <= timestamp < to into dest, oldest first
+func (s *IndexerQueryService) GetSukukEventsBetween(ctx context.Context, eventType string, sukukAddresses []string, from, to time.Time, dest interface{}) error {
+	if len(sukukAddresses) == 0 {
+		return nil
+	}
+
+	table, err := s.tableService.GetLatestTableForEvent(eventType)
 	if err != nil {
-		return "0", fmt.Errorf("failed to find snapshot table: %w", err)
+		return fmt.Errorf("failed to find %s table: %w", eventType, err)
 	}
 
-	var snapshot struct {
-		TotalSupply string `gorm:"column:total_supply"`
+	lowered := make([]string, len(sukukAddresses))
+	for i, address := range sukukAddresses {
+		lowered[i] = strings.ToLower(address)
 	}
 
-	err = s.indexerDB.Table(snapshotTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		First(&snapshot).Error
+	return s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(table).
+			Where("LOWER(sukuk_address) IN ? AND timestamp >= ? AND timestamp < ?", lowered, from.Unix(), to.Unix()).
+			Order("timestamp ASC").
+			Find(dest).Error
+	})
+}
 
+// GetAddressEventsBetween loads the events of one indexer event type whose addressColumn,
+// e.g. "user" or "holder", is address, with from <= timestamp < to into dest, oldest first
+func (s *IndexerQueryService) GetAddressEventsBetween(ctx context.Context, eventType, addressColumn, address string, from, to time.Time, dest interface{}) error {
+	table, err := s.tableService.GetLatestTableForEvent(eventType)
 	if err != nil {
-		return "0", fmt.Errorf("failed to get total supply from snapshot: %w", err)
+		return fmt.Errorf("failed to find %s table: %w", eventType, err)
 	}
 
-	return snapshot.TotalSupply, nil
+	// The column is quoted because "user" is a reserved word
+	condition := fmt.Sprintf(`LOWER(%s) = ? AND timestamp >= ? AND timestamp < ?`, quoteIdentifier(addressColumn))
+	return s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(table).
+			Where(condition, strings.ToLower(address), from.Unix(), to.Unix()).
+			Order("timestamp ASC").
+			Find(dest).Error
+	})
 }
 
-// getTotalSupplyFromRedemption gets total supply from latest redemption request
-func (s *IndexerQueryService) getTotalSupplyFromRedemption(sukukAddress string) (string, error) {
-	redemptionTable, err := s.tableService.GetLatestTableForEvent("redemption_request")
+// GetHoldings returns the latest holder_update of a holder on every sukuk, including zero balances
+func (s *IndexerQueryService) GetHoldings(ctx context.Context, holder string) ([]IndexerHolderUpdated, error) {
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
 	if err != nil {
-		return "0", fmt.Errorf("failed to find redemption_request table: %w", err)
+		return nil, fmt.Errorf("failed to find holder_update table: %w", err)
 	}
 
-	var redemption struct {
-		TotalSupply string `gorm:"column:total_supply"`
+	var holdings []IndexerHolderUpdated
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT ON (LOWER(sukuk_address)) id, sukuk_address, holder, new_balance::text AS new_balance, timestamp, block_number, tx_hash
+			FROM %s
+			WHERE LOWER(holder) = LOWER(?)
+			ORDER BY LOWER(sukuk_address), block_number DESC, timestamp DESC, id DESC`, quoteIdentifier(holderTable))
+		return db.Raw(query, holder).Scan(&holdings).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holdings: %w", err)
 	}
+	return holdings, nil
+}
 
-	err = s.indexerDB.Table(redemptionTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		First(&redemption).Error
+// GetYieldDistributionsBySukuk gets every yield distribution of the given sukuk
+func (s *IndexerQueryService) GetYieldDistributionsBySukuk(ctx context.Context, sukukAddresses []string) ([]IndexerYieldDistributed, error) {
+	if len(sukukAddresses) == 0 {
+		return nil, nil
+	}
 
+	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
 	if err != nil {
-		return "0", fmt.Errorf("failed to get total supply from redemption: %w", err)
+		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
 	}
 
-	return redemption.TotalSupply, nil
+	var yields []IndexerYieldDistributed
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(yieldTable).
+			Where("sukuk_address IN ?", sukukAddresses).
+			Find(&yields).Error
+	})
+	return yields, err
 }
 
 // GetUserTransactionHistory gets all transactions for a user efficiently with database-level filtering and sorting
-func (s *IndexerQueryService) GetUserTransactionHistory(userAddress string, limit int) ([]models.TransactionEvent, error) {
+// An empty activityType returns every type; otherwise only that type's table is queried
+func (s *IndexerQueryService) GetUserTransactionHistory(ctx context.Context, userAddress string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -815,20 +1245,24 @@ func (s *IndexerQueryService) GetUserTransactionHistory(userAddress string, limi
 
 	allTransactions := make([]models.TransactionEvent, 0)
 
+	includes := func(t models.ActivityType) bool { return activityType == "" || activityType == t }
+
 	// Get purchases with database filtering
-	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
-	if err == nil {
+	purchaseTable, err := s.tableService.GetLatestTableForEvent(models.ActivityTypePurchase.EventTable())
+	if err == nil && includes(models.ActivityTypePurchase) {
 		var purchases []IndexerSukukPurchase
-		err = s.indexerDB.Table(purchaseTable).
-			Where("buyer = ?", userAddress).
-			Order("timestamp DESC").
-			Limit(limit).
-			Find(&purchases).Error
+		err = s.read(ctx, func(db *gorm.DB) error {
+			return db.Table(purchaseTable).
+				Where("buyer = ?", userAddress).
+				Order("timestamp DESC").
+				Limit(limit).
+				Find(&purchases).Error
+		})
 
 		if err == nil {
 			for _, p := range purchases {
 				allTransactions = append(allTransactions, models.TransactionEvent{
-					Type:         "purchase",
+					Type:         models.ActivityTypePurchase,
 					SukukAddress: p.SukukAddress,
 					Amount:       p.Amount,
 					TxHash:       p.TxHash,
@@ -845,19 +1279,21 @@ func (s *IndexerQueryService) GetUserTransactionHistory(userAddress string, limi
 	}
 
 	// Get redemption requests with database filtering
-	redemptionTable, err := s.tableService.GetLatestTableForEvent("redemption_request")
-	if err == nil {
+	redemptionTable, err := s.tableService.GetLatestTableForEvent(models.ActivityTypeRedemptionRequest.EventTable())
+	if err == nil && includes(models.ActivityTypeRedemptionRequest) {
 		var redemptions []IndexerRedemptionRequest
-		err = s.indexerDB.Table(redemptionTable).
-			Where("user = ?", userAddress).
-			Order("timestamp DESC").
-			Limit(limit).
-			Find(&redemptions).Error
+		err = s.read(ctx, func(db *gorm.DB) error {
+			return db.Table(redemptionTable).
+				Where("user = ?", userAddress).
+				Order("timestamp DESC").
+				Limit(limit).
+				Find(&redemptions).Error
+		})
 
 		if err == nil {
 			for _, r := range redemptions {
 				allTransactions = append(allTransactions, models.TransactionEvent{
-					Type:         "redemption_request",
+					Type:         models.ActivityTypeRedemptionRequest,
 					SukukAddress: r.SukukAddress,
 					Amount:       r.Amount,
 					TxHash:       r.TxHash,
@@ -874,19 +1310,21 @@ func (s *IndexerQueryService) GetUserTransactionHistory(userAddress string, limi
 	}
 
 	// Get yield claims with database filtering
-	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
-	if err == nil {
+	yieldTable, err := s.tableService.GetLatestTableForEvent(models.ActivityTypeYieldClaim.EventTable())
+	if err == nil && includes(models.ActivityTypeYieldClaim) {
 		var claims []IndexerYieldClaimed
-		err = s.indexerDB.Table(yieldTable).
-			Where("user = ?", userAddress).
-			Order("timestamp DESC").
-			Limit(limit).
-			Find(&claims).Error
+		err = s.read(ctx, func(db *gorm.DB) error {
+			return db.Table(yieldTable).
+				Where("user = ?", userAddress).
+				Order("timestamp DESC").
+				Limit(limit).
+				Find(&claims).Error
+		})
 
 		if err == nil {
 			for _, y := range claims {
 				allTransactions = append(allTransactions, models.TransactionEvent{
-					Type:         "yield_claim",
+					Type:         models.ActivityTypeYieldClaim,
 					SukukAddress: y.SukukAddress,
 					Amount:       y.Amount,
 					TxHash:       y.TxHash,
@@ -901,6 +1339,14 @@ func (s *IndexerQueryService) GetUserTransactionHistory(userAddress string, limi
 		}
 	}
 
+	// Get transfers between wallets, derived from holder updates no event above explains
+	if includes(models.ActivityTypeTransferIn) || includes(models.ActivityTypeTransferOut) {
+		transfers, err := s.GetUserTransfers(ctx, userAddress, activityType, limit)
+		if err == nil {
+			allTransactions = append(allTransactions, transfers...)
+		}
+	}
+
 	// Sort by timestamp descending using Go's sort package (more efficient than bubble sort)
 	sort.Slice(allTransactions, func(i, j int) bool {
 		return allTransactions[i].Timestamp.After(allTransactions[j].Timestamp)
@@ -920,35 +1366,47 @@ func (s *IndexerQueryService) GetAvailableTables() (map[string]string, error) {
 }
 
 // GetAvailableDistributions gets yield distributions for a sukuk with claim information for a specific user
-func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddress string) ([]models.SukukYieldDistribution, error) {
+func (s *IndexerQueryService) GetAvailableDistributions(ctx context.Context, userAddress, sukukAddress string) ([]models.SukukYieldDistribution, error) {
 	// Always return an empty slice if there are any errors - don't fail the entire owned-sukuk response
+	distributions, err := s.GetClaimableDistributions(ctx, userAddress, sukukAddress)
+	if err != nil {
+		return []models.SukukYieldDistribution{}, nil
+	}
+	return distributions, nil
+}
+
+// GetClaimableDistributions lists every yield distribution of a sukuk, oldest first, with what
+// the user already claimed from it and what is left to claim on their current balance
+func (s *IndexerQueryService) GetClaimableDistributions(ctx context.Context, userAddress, sukukAddress string) ([]models.SukukYieldDistribution, error) {
 	emptyResult := []models.SukukYieldDistribution{}
 
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
-			return emptyResult, nil // Return empty instead of error
+			return nil, err
 		}
 	}
 
 	// Get latest table names using dynamic discovery
-	distributionTable, err := s.tableService.GetLatestTableForEvent("yield_distribution")
+	distributionTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
 	if err != nil {
-		return emptyResult, nil // Return empty instead of error
+		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
 	}
 
 	claimTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
 	if err != nil {
-		return emptyResult, nil // Return empty instead of error
+		return nil, fmt.Errorf("failed to find yield_claim table: %w", err)
 	}
 
 	// Get all yield distributions for this sukuk
 	var distributions []IndexerYieldDistributed
-	err = s.indexerDB.Table(distributionTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("distribution_id ASC").
-		Find(&distributions).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(distributionTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("distribution_id ASC").
+			Find(&distributions).Error
+	})
 	if err != nil {
-		return emptyResult, nil // Return empty instead of error
+		return nil, fmt.Errorf("failed to fetch yield distributions: %w", err)
 	}
 
 	// If no distributions found, return empty result
@@ -958,11 +1416,13 @@ func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddres
 
 	// Get all yield claims by this user for this sukuk
 	var claims []IndexerYieldClaimed
-	err = s.indexerDB.Table(claimTable).
-		Where("user = ? AND sukuk_address = ?", userAddress, sukukAddress).
-		Find(&claims).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(claimTable).
+			Where(`"user" = ? AND sukuk_address = ?`, userAddress, sukukAddress).
+			Find(&claims).Error
+	})
 	if err != nil {
-		return emptyResult, nil // Return empty instead of error
+		return nil, fmt.Errorf("failed to fetch yield claims: %w", err)
 	}
 
 	// Create a map of claimed amounts by distribution ID
@@ -982,20 +1442,17 @@ func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddres
 	}
 
 	// Get user's current balance to calculate claimable amount
-	userBalance, err := s.GetCurrentBalance(userAddress, sukukAddress)
+	userBalance, err := s.GetCurrentBalance(ctx, userAddress, sukukAddress)
 	if err != nil {
 		userBalance = "0" // Default to 0 if error
 	}
 
-	// Get total supply to calculate user's share
-	totalSupply, err := s.getTotalSupplyFromSnapshot(sukukAddress)
+	// Get the current total supply to calculate user's share
+	supply, err := s.supplies().GetTotalSupplyAt(ctx, sukukAddress, time.Now())
 	if err != nil {
-		// Fallback to redemption data
-		totalSupply, err = s.getTotalSupplyFromRedemption(sukukAddress)
-		if err != nil {
-			totalSupply = "0" // Default to 0 if error
-		}
+		supply = Supply{Source: models.SupplySourceNone} // Nothing is claimable without a supply
 	}
+	totalSupply := supply.Amount
 
 	// Build result
 	result := make([]models.SukukYieldDistribution, len(distributions))
@@ -1012,17 +1469,13 @@ func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddres
 		claimable := false
 		
 		if !mathUtil.IsZero(userBalance) && !mathUtil.IsZero(totalSupply) {
-			// Calculate user's percentage: userBalance / totalSupply
-			percentage, err := mathUtil.CalculatePercentage(userBalance, totalSupply)
+			// Calculate user's entitled amount: distribution.Amount * userBalance / totalSupply
+			entitledAmount, err := mathUtil.ProRataShare(dist.Amount, userBalance, totalSupply)
 			if err == nil {
-				// Calculate user's entitled amount: distribution.Amount * percentage
-				entitledAmount, err := mathUtil.MultiplyTokenAmount(dist.Amount, percentage)
-				if err == nil {
-					// Calculate claimable: entitledAmount - claimedAmount
-					userClaimableAmount, err = mathUtil.SubtractTokenAmounts(entitledAmount, claimedAmount)
-					if err == nil && !mathUtil.IsZero(userClaimableAmount) {
-						claimable = true
-					}
+				// Calculate claimable: entitledAmount - claimedAmount
+				userClaimableAmount, err = mathUtil.SubtractTokenAmounts(entitledAmount, claimedAmount)
+				if err == nil && !mathUtil.IsZero(userClaimableAmount) {
+					claimable = true
 				}
 			}
 		}
@@ -1034,6 +1487,7 @@ func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddres
 			Claimable:           claimable,
 			ClaimedAmount:       claimedAmount,
 			UserClaimableAmount: userClaimableAmount,
+			SupplySource:        supply.Source,
 		}
 	}
 
@@ -1044,16 +1498,43 @@ func (s *IndexerQueryService) GetAvailableDistributions(userAddress, sukukAddres
 type UserPortfolio struct {
 	Address  string         `json:"address"`
 	Holdings []SukukHolding `json:"holdings"`
+	Errors   []HoldingError `json:"errors,omitempty"` // Holdings left out because they could not be computed
+}
+
+// HoldingError is a holding left out of a portfolio because it could not be computed
+type HoldingError struct {
+	SukukAddress string `json:"sukuk_address"`
+	Reason       string `json:"reason"`
+}
+
+// PortfolioError is returned when none of the holdings of a portfolio could be computed
+type PortfolioError struct {
+	Errors []HoldingError
+}
+
+func (e *PortfolioError) Error() string {
+	return fmt.Sprintf("failed to compute any of %d holdings, first %s: %s", len(e.Errors), e.Errors[0].SukukAddress, e.Errors[0].Reason)
 }
 
 type SukukHolding struct {
-	SukukAddress   string `json:"sukuk_address"`
-	Balance        string `json:"balance"`
-	ClaimableYield string `json:"claimable_yield"`
+	SukukAddress           string                    `json:"sukuk_address"`
+	Balance                string                    `json:"balance"`
+	ClaimableYield         string                    `json:"claimable_yield"`
+	TotalYieldClaimed      string                    `json:"total_yield_claimed"`
+	UnclaimedDistributions []int64                   `json:"unclaimed_distributions"`
+	RecentDistributions    []IndexerYieldDistributed `json:"recent_distributions"` // Newest first
+	SupplySource           models.SupplySource       `json:"supply_source"`        // Where the supply ClaimableYield is a share of came from
+}
+
+// SukukUserPosition is a user's balance and unclaimed yield in one sukuk
+type SukukUserPosition struct {
+	Balance                    string
+	UnclaimedDistributionCount int
+	ClaimableAmount            string
 }
 
 // GetSnapshots gets snapshot events for a sukuk
-func (s *IndexerQueryService) GetSnapshots(sukukAddress string, limit int) ([]models.SnapshotEvent, error) {
+func (s *IndexerQueryService) GetSnapshots(ctx context.Context, sukukAddress string, limit int) ([]models.SnapshotEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -1065,42 +1546,28 @@ func (s *IndexerQueryService) GetSnapshots(sukukAddress string, limit int) ([]mo
 	}
 
 	// Get latest table name using dynamic discovery
-	snapshotTable, err := s.tableService.GetLatestTableForEvent("snapshot")
+	snapshotTable, err := s.tableService.GetLatestTableForEvent(snapshotEventType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snapshot table: %w", err)
 	}
 
 	var snapshots []IndexerSnapshotTaken
-	err = s.indexerDB.Table(snapshotTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("snapshot_id DESC").
-		Limit(limit).
-		Find(&snapshots).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(snapshotTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("snapshot_id DESC").
+			Limit(limit).
+			Find(&snapshots).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query snapshots from %s: %w", snapshotTable, err)
 	}
 
-	// Convert to SnapshotEvent
-	result := make([]models.SnapshotEvent, len(snapshots))
-	for i, snap := range snapshots {
-		result[i] = models.SnapshotEvent{
-			ID:           snap.ID,
-			SukukAddress: snap.SukukAddress,
-			SnapshotId:   fmt.Sprintf("%d", snap.SnapshotId),
-			TotalSupply:  snap.TotalSupply,
-			HolderCount:  snap.HolderCount,
-			EligibleCount: snap.EligibleCount,
-			Timestamp:    time.Unix(snap.Timestamp, 0),
-			TxHash:       snap.TxHash,
-			BlockNumber:  snap.BlockNumber,
-		}
-	}
-
-	return result, nil
+	return toSnapshotEvents(snapshots), nil
 }
 
 // GetSnapshotById gets a specific snapshot by ID
-func (s *IndexerQueryService) GetSnapshotById(sukukAddress string, snapshotId int64) (*models.SnapshotEvent, error) {
+func (s *IndexerQueryService) GetSnapshotById(ctx context.Context, sukukAddress string, snapshotId int64) (*models.SnapshotEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -1108,36 +1575,27 @@ func (s *IndexerQueryService) GetSnapshotById(sukukAddress string, snapshotId in
 	}
 
 	// Get latest table name using dynamic discovery
-	snapshotTable, err := s.tableService.GetLatestTableForEvent("snapshot")
+	snapshotTable, err := s.tableService.GetLatestTableForEvent(snapshotEventType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snapshot table: %w", err)
 	}
 
 	var snapshot IndexerSnapshotTaken
-	err = s.indexerDB.Table(snapshotTable).
-		Where("sukuk_address = ? AND snapshot_id = ?", sukukAddress, snapshotId).
-		First(&snapshot).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(snapshotTable).
+			Where("sukuk_address = ? AND snapshot_id = ?", sukukAddress, snapshotId).
+			First(&snapshot).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query snapshot from %s: %w", snapshotTable, err)
 	}
 
-	result := &models.SnapshotEvent{
-		ID:           snapshot.ID,
-		SukukAddress: snapshot.SukukAddress,
-		SnapshotId:   fmt.Sprintf("%d", snapshot.SnapshotId),
-		TotalSupply:  snapshot.TotalSupply,
-		HolderCount:  snapshot.HolderCount,
-		EligibleCount: snapshot.EligibleCount,
-		Timestamp:    time.Unix(snapshot.Timestamp, 0),
-		TxHash:       snapshot.TxHash,
-		BlockNumber:  snapshot.BlockNumber,
-	}
-
-	return result, nil
+	result := toSnapshotEvent(snapshot)
+	return &result, nil
 }
 
 // GetAllSnapshots gets snapshot events for all sukuk
-func (s *IndexerQueryService) GetAllSnapshots(limit int) ([]models.SnapshotEvent, error) {
+func (s *IndexerQueryService) GetAllSnapshots(ctx context.Context, limit int) ([]models.SnapshotEvent, error) {
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return nil, err
@@ -1149,35 +1607,115 @@ func (s *IndexerQueryService) GetAllSnapshots(limit int) ([]models.SnapshotEvent
 	}
 
 	// Get latest table name using dynamic discovery
-	snapshotTable, err := s.tableService.GetLatestTableForEvent("snapshot")
+	snapshotTable, err := s.tableService.GetLatestTableForEvent(snapshotEventType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find snapshot table: %w", err)
 	}
 
 	var snapshots []IndexerSnapshotTaken
-	err = s.indexerDB.Table(snapshotTable).
-		Order("timestamp DESC").
-		Limit(limit).
-		Find(&snapshots).Error
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(snapshotTable).
+			Order("timestamp DESC").
+			Limit(limit).
+			Find(&snapshots).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query snapshots from %s: %w", snapshotTable, err)
 	}
 
-	// Convert to SnapshotEvent
-	result := make([]models.SnapshotEvent, len(snapshots))
-	for i, snap := range snapshots {
-		result[i] = models.SnapshotEvent{
-			ID:           snap.ID,
-			SukukAddress: snap.SukukAddress,
-			SnapshotId:   fmt.Sprintf("%d", snap.SnapshotId),
-			TotalSupply:  snap.TotalSupply,
-			HolderCount:  snap.HolderCount,
-			EligibleCount: snap.EligibleCount,
-			Timestamp:    time.Unix(snap.Timestamp, 0),
-			TxHash:       snap.TxHash,
-			BlockNumber:  snap.BlockNumber,
+	return toSnapshotEvents(snapshots), nil
+}
+
+// GetSnapshotHistory gets a page of snapshots for a sukuk, newest snapshot_id first,
+// along with the total number of snapshots
+func (s *IndexerQueryService) GetSnapshotHistory(ctx context.Context, sukukAddress string, limit, offset int) ([]models.SnapshotEvent, int64, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, 0, err
 		}
 	}
 
-	return result, nil
-}
\ No newline at end of file
+	snapshotTable, err := s.tableService.GetLatestTableForEvent(snapshotEventType)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find snapshot table: %w", err)
+	}
+
+	// Session makes the filtered query reusable for the count, the fetch and their retries
+	query := s.indexerDB.WithContext(ctx).Table(snapshotTable).
+		Where("sukuk_address = ?", sukukAddress).
+		Session(&gorm.Session{})
+
+	var total int64
+	err = s.read(ctx, func(*gorm.DB) error {
+		return query.Count(&total).Error
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count snapshots in %s: %w", snapshotTable, err)
+	}
+
+	var snapshots []IndexerSnapshotTaken
+	err = s.read(ctx, func(*gorm.DB) error {
+		return query.
+			Order("snapshot_id DESC").
+			Limit(limit).
+			Offset(offset).
+			Find(&snapshots).Error
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query snapshots from %s: %w", snapshotTable, err)
+	}
+
+	return toSnapshotEvents(snapshots), total, nil
+}
+
+// GetLatestSnapshot gets the snapshot with the highest snapshot_id for a sukuk
+func (s *IndexerQueryService) GetLatestSnapshot(ctx context.Context, sukukAddress string) (*models.SnapshotEvent, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshotTable, err := s.tableService.GetLatestTableForEvent(snapshotEventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find snapshot table: %w", err)
+	}
+
+	var snapshot IndexerSnapshotTaken
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(snapshotTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("snapshot_id DESC").
+			First(&snapshot).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toSnapshotEvent(snapshot)
+	return &result, nil
+}
+
+// toSnapshotEvent converts an indexer snapshot row to the API model
+func toSnapshotEvent(snap IndexerSnapshotTaken) models.SnapshotEvent {
+	return models.SnapshotEvent{
+		ID:            snap.ID,
+		SukukAddress:  snap.SukukAddress,
+		SnapshotId:    fmt.Sprintf("%d", snap.SnapshotId),
+		TotalSupply:   snap.TotalSupply,
+		HolderCount:   snap.HolderCount,
+		EligibleCount: snap.EligibleCount,
+		Timestamp:     time.Unix(snap.Timestamp, 0),
+		TxHash:        snap.TxHash,
+		BlockNumber:   snap.BlockNumber,
+	}
+}
+
+// toSnapshotEvents converts indexer snapshot rows to API models
+func toSnapshotEvents(snapshots []IndexerSnapshotTaken) []models.SnapshotEvent {
+	result := make([]models.SnapshotEvent, len(snapshots))
+	for i, snap := range snapshots {
+		result[i] = toSnapshotEvent(snap)
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// GetLatestTableForEvent matches on the discovered table suffix, so the snapshot
+// lookup key must be the SnapshotTaken mapping key whose suffix is itself.
+// It was previously "snapshot", which never matched any table
+func TestSnapshotEventTypeMatchesTableMapping(t *testing.T) {
+	suffix, ok := EventTableMapping[snapshotEventType]
+	if !ok {
+		t.Fatalf("Snapshot event type %q is not an EventTableMapping key", snapshotEventType)
+	}
+	if suffix != snapshotEventType {
+		t.Errorf("Expected snapshot table suffix %q, got %q", snapshotEventType, suffix)
+	}
+	if snapshotEventType == "snapshot" {
+		t.Error("Snapshot event type must not be the unmapped key \"snapshot\"")
+	}
+}
+
+func TestToSnapshotEvent(t *testing.T) {
+	snap := IndexerSnapshotTaken{
+		ID:            "0xabc-1",
+		SukukAddress:  "0x71d7c963e607eedafaa7ef8f8c92bbb878090650",
+		SnapshotId:    7,
+		TotalSupply:   "1000000000000000000000",
+		HolderCount:   12,
+		EligibleCount: 10,
+		Timestamp:     1735689600,
+		BlockNumber:   123,
+		TxHash:        "0xdef",
+	}
+
+	event := toSnapshotEvent(snap)
+	if event.SnapshotId != "7" {
+		t.Errorf("Expected snapshot_id '7', got '%s'", event.SnapshotId)
+	}
+	if event.HolderCount != 12 || event.EligibleCount != 10 {
+		t.Errorf("Expected holder/eligible counts 12/10, got %d/%d", event.HolderCount, event.EligibleCount)
+	}
+	if !event.Timestamp.Equal(time.Unix(1735689600, 0)) {
+		t.Errorf("Expected timestamp %v, got %v", time.Unix(1735689600, 0), event.Timestamp)
+	}
+
+	events := toSnapshotEvents([]IndexerSnapshotTaken{snap, snap})
+	if len(events) != 2 || events[1].TxHash != "0xdef" {
+		t.Errorf("Expected 2 converted snapshots, got %+v", events)
+	}
+}
+
+func TestGroupLatestActivities(t *testing.T) {
+	base := time.Unix(1735689600, 0)
+	activities := []models.ActivityEvent{
+		{Type: "purchase", SukukAddress: "0xAAA", TxHash: "a1", Timestamp: base},
+		{Type: "purchase", SukukAddress: "0xaaa", TxHash: "a3", Timestamp: base.Add(2 * time.Minute)},
+		{Type: "redemption_request", SukukAddress: "0xaaa", TxHash: "a2", Timestamp: base.Add(time.Minute)},
+		{Type: "purchase", SukukAddress: "0xbbb", TxHash: "b1", Timestamp: base},
+	}
+
+	grouped := groupLatestActivities(activities, 2)
+
+	if len(grouped) != 2 {
+		t.Fatalf("Expected 2 sukuk, got %d", len(grouped))
+	}
+	aaa := grouped["0xaaa"]
+	if len(aaa) != 2 || aaa[0].TxHash != "a3" || aaa[1].TxHash != "a2" {
+		t.Errorf("Expected newest two activities [a3 a2] for 0xaaa, got %+v", aaa)
+	}
+	if bbb := grouped["0xbbb"]; len(bbb) != 1 || bbb[0].TxHash != "b1" {
+		t.Errorf("Expected [b1] for 0xbbb, got %+v", bbb)
+	}
+}
+
+func TestClampActivitiesLimit(t *testing.T) {
+	for limit, want := range map[int]int{0: 10, -3: 10, 1: 1, 50: 50, 51: 50, 1000: 50} {
+		if got := clampActivitiesLimit(limit); got != want {
+			t.Errorf("clampActivitiesLimit(%d): expected %d, got %d", limit, want, got)
+		}
+	}
+}
+
+// Every event table is either read into an activity type or deliberately left out of the feeds,
+// so a new indexer event can't appear without a decision about its type
+func TestActivityTypeRegistryCoversEventTables(t *testing.T) {
+	nonActivityTables := map[string]bool{
+		"sukuk_creation": true, "redemption_approval": true, "yield_distribution": true,
+		"snapshot_taken": true, "snapshot_criteria_update": true, "holder_addition": true,
+		"holder_update": true, "manager_update": true, "vault_update": true,
+		"sale_status_change": true, "sukuk_status_update": true, "yield_deposit": true,
+		"yield_vault_manager_addition": true, "yield_vault_manager_removal": true,
+		"minter_addition": true, "minter_removal": true, "status_change": true,
+	}
+
+	mapped := make(map[string]bool, len(EventTableMapping))
+	for _, suffix := range EventTableMapping {
+		mapped[suffix] = true
+		_, registered := models.ActivityTypeForEventTable(suffix)
+		if registered == nonActivityTables[suffix] {
+			t.Errorf("Event table %q must be either registered as an activity type or listed as non-activity", suffix)
+		}
+	}
+
+	seen := make(map[models.ActivityType]bool)
+	for _, info := range models.ActivityTypeRegistry {
+		source := info.EventTable
+		if source == "" {
+			source = info.DerivedFrom
+		}
+		if !mapped[source] {
+			t.Errorf("Activity type %q reads from %q, which is not in EventTableMapping", info.Type, source)
+		}
+		if seen[info.Type] {
+			t.Errorf("Activity type %q is registered twice", info.Type)
+		}
+		seen[info.Type] = true
+		if info.LabelEN == "" || info.LabelID == "" {
+			t.Errorf("Activity type %q is missing a label", info.Type)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
 
 	"gorm.io/gorm"
 )
@@ -23,9 +27,39 @@ func NewIndexerTableService() *IndexerTableService {
 // ConnectToIndexer connects to the Ponder indexer database
 func (s *IndexerTableService) ConnectToIndexer() error {
 	s.indexerDB = database.GetDB()
+	if s.indexerDB == nil {
+		return errors.New("database is not connected")
+	}
 	return nil
 }
 
+// indexerTableNamePattern matches Ponder's hash-prefixed event tables, e.g. f243__sukuk_purchase
+var indexerTableNamePattern = regexp.MustCompile(`^[a-f0-9]+(_reorg)?__[a-z_]+$`)
+
+// ErrInvalidTableName is returned for table names that are not indexer event tables
+var ErrInvalidTableName = errors.New("invalid indexer table name")
+
+// ValidateTableName rejects anything that is not a hash-prefixed indexer table name,
+// so names reaching raw SQL can only be identifiers
+func ValidateTableName(name string) error {
+	if !indexerTableNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidTableName, name)
+	}
+	return nil
+}
+
+// indexerEventTypePattern matches the event type suffix of an indexer table name
+var indexerEventTypePattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// ErrInvalidTableOverride is returned for overrides that don't name an existing table of the event type
+var ErrInvalidTableOverride = errors.New("invalid indexer table override")
+
+// quoteIdentifier double-quotes an identifier for interpolation into raw SQL,
+// doubling any embedded quotes so the name can't end the identifier early
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
 // EventTableMapping defines the expected event table suffixes
 var EventTableMapping = map[string]string{
 	"sukuk_creation":         "sukuk_creation",
@@ -79,7 +113,13 @@ func (s *IndexerTableService) DiscoverAllTables() ([]TableInfo, error) {
 		ORDER BY table_name DESC
 	`
 	
-	rows, err := s.indexerDB.Raw(query).Rows()
+	// Discovery runs before every indexer read, so it shares the executor's retries and breaker
+	var rows *sql.Rows
+	err := DefaultIndexerExecutor().Do(context.Background(), func() error {
+		var queryErr error
+		rows, queryErr = s.indexerDB.Raw(query).Rows()
+		return queryErr
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -94,6 +134,9 @@ func (s *IndexerTableService) DiscoverAllTables() ([]TableInfo, error) {
 			continue
 		}
 
+		if ValidateTableName(tableName) != nil {
+			continue
+		}
 		matches := tableRegex.FindStringSubmatch(tableName)
 		if len(matches) == 3 {
 			tables = append(tables, TableInfo{
@@ -108,9 +151,67 @@ func (s *IndexerTableService) DiscoverAllTables() ([]TableInfo, error) {
 	return tables, nil
 }
 
+// TableOverrides returns the pinned table of each overridden event type
+// Overrides live beside the indexer tables, so they are read on every lookup and a change
+// takes effect immediately
+func (s *IndexerTableService) TableOverrides() (map[string]string, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	overrides, err := models.GetIndexerTableOverrides(s.indexerDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load indexer table overrides: %w", err)
+	}
+	tables := make(map[string]string, len(overrides))
+	for _, override := range overrides {
+		tables[override.EventType] = override.Table
+	}
+	return tables, nil
+}
+
+// ValidateTableOverride checks that tableName is an existing indexer table of eventType with
+// the columns every event table has, so an override can't point reads at the wrong table
+func (s *IndexerTableService) ValidateTableOverride(eventType, tableName string) error {
+	if !indexerEventTypePattern.MatchString(eventType) {
+		return fmt.Errorf("%w: invalid event type %q", ErrInvalidTableOverride, eventType)
+	}
+	if err := ValidateTableName(tableName); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTableOverride, err)
+	}
+	if !strings.HasSuffix(tableName, "__"+eventType) {
+		return fmt.Errorf("%w: %s is not a %s table", ErrInvalidTableOverride, tableName, eventType)
+	}
+
+	exists, err := s.CheckTableExists(tableName)
+	if err != nil {
+		return fmt.Errorf("failed to check table %s: %w", tableName, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: table %s does not exist", ErrInvalidTableOverride, tableName)
+	}
+	if err := s.ValidateTableStructure(tableName, eventType); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTableOverride, err)
+	}
+	return nil
+}
+
+// ErrNoEventTable is returned by GetLatestTableForEvent when the indexer has no table for an event
+var ErrNoEventTable = errors.New("no tables found for event type")
+
 // GetLatestTableForEvent finds the latest table for a specific event type
-// Uses max block number and row count to determine the most relevant table
+// An override wins; otherwise max block number and row count determine the most relevant table
 func (s *IndexerTableService) GetLatestTableForEvent(eventType string) (string, error) {
+	overrides, err := s.TableOverrides()
+	if err != nil {
+		return "", err
+	}
+	if table, ok := overrides[eventType]; ok {
+		return table, nil
+	}
+
 	tables, err := s.DiscoverAllTables()
 	if err != nil {
 		return "", err
@@ -125,7 +226,7 @@ func (s *IndexerTableService) GetLatestTableForEvent(eventType string) (string,
 	}
 
 	if len(eventTables) == 0 {
-		return "", fmt.Errorf("no tables found for event type: %s", eventType)
+		return "", fmt.Errorf("%w: %s", ErrNoEventTable, eventType)
 	}
 
 	// If only one table, return it
@@ -141,7 +242,7 @@ func (s *IndexerTableService) GetLatestTableForEvent(eventType string) (string,
 	for _, table := range eventTables {
 		// Get max block number for this table
 		var maxBlock int64
-		err := s.indexerDB.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), -1) FROM %s", table.FullName)).Scan(&maxBlock).Error
+		err := s.indexerDB.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), -1) FROM %s", quoteIdentifier(table.FullName))).Scan(&maxBlock).Error
 		if err != nil {
 			// If query fails, skip this table
 			continue
@@ -149,7 +250,7 @@ func (s *IndexerTableService) GetLatestTableForEvent(eventType string) (string,
 
 		// Get row count for this table
 		var rowCount int64
-		err = s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", table.FullName)).Scan(&rowCount).Error
+		err = s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdentifier(table.FullName))).Scan(&rowCount).Error
 		if err != nil {
 			// If query fails, skip this table
 			continue
@@ -186,8 +287,13 @@ func (s *IndexerTableService) GetLatestTableForEvent(eventType string) (string,
 }
 
 // GetAllLatestTables returns a map of event type to latest table name
-// Uses the same improved logic as GetLatestTableForEvent
+// Uses the same improved logic as GetLatestTableForEvent, overrides included
 func (s *IndexerTableService) GetAllLatestTables() (map[string]string, error) {
+	overrides, err := s.TableOverrides()
+	if err != nil {
+		return nil, err
+	}
+
 	tables, err := s.DiscoverAllTables()
 	if err != nil {
 		return nil, err
@@ -216,7 +322,7 @@ func (s *IndexerTableService) GetAllLatestTables() (map[string]string, error) {
 		for _, table := range eventTables {
 			// Get max block number for this table
 			var maxBlock int64
-			err := s.indexerDB.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), -1) FROM %s", table.FullName)).Scan(&maxBlock).Error
+			err := s.indexerDB.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), -1) FROM %s", quoteIdentifier(table.FullName))).Scan(&maxBlock).Error
 			if err != nil {
 				// If query fails, skip this table
 				continue
@@ -224,7 +330,7 @@ func (s *IndexerTableService) GetAllLatestTables() (map[string]string, error) {
 
 			// Get row count for this table
 			var rowCount int64
-			err = s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", table.FullName)).Scan(&rowCount).Error
+			err = s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdentifier(table.FullName))).Scan(&rowCount).Error
 			if err != nil {
 				// If query fails, skip this table
 				continue
@@ -260,6 +366,10 @@ func (s *IndexerTableService) GetAllLatestTables() (map[string]string, error) {
 		latestTables[eventType] = bestTable.FullName
 	}
 
+	for eventType, table := range overrides {
+		latestTables[eventType] = table
+	}
+
 	return latestTables, nil
 }
 
@@ -303,8 +413,11 @@ func (s *IndexerTableService) GetTablesByHashPrefix(hashPrefix string) ([]TableI
 	return filteredTables, nil
 }
 
-// GetTableRowCount returns the number of rows in a table
+// GetTableRowCount returns the number of rows in an indexer table, rejecting names that fail ValidateTableName
 func (s *IndexerTableService) GetTableRowCount(tableName string) (int64, error) {
+	if err := ValidateTableName(tableName); err != nil {
+		return 0, err
+	}
 	if s.indexerDB == nil {
 		if err := s.ConnectToIndexer(); err != nil {
 			return 0, err
@@ -312,7 +425,7 @@ func (s *IndexerTableService) GetTableRowCount(tableName string) (int64, error)
 	}
 
 	var count int64
-	err := s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", tableName)).Scan(&count).Error
+	err := s.indexerDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdentifier(tableName))).Scan(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("failed to count rows in table %s: %w", tableName, err)
 	}
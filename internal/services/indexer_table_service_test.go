@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+var injectedTableNames = []string{
+	"foo; DROP TABLE companies;--",
+	`f243__sukuk_purchase"; DROP TABLE sukuk_metadata;--`,
+	"f243__sukuk_purchase; SELECT 1",
+	"f243__sukuk_purchase --",
+	"public.f243__sukuk_purchase",
+	"F243__sukuk_purchase",
+	"f243_sukuk_purchase",
+	"f243__",
+	"",
+}
+
+func TestValidateTableName(t *testing.T) {
+	for _, name := range []string{"f243__sukuk_purchase", "0a1b2c__holder_update", "f243_reorg__yield_claim"} {
+		if err := ValidateTableName(name); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range injectedTableNames {
+		if err := ValidateTableName(name); !errors.Is(err, ErrInvalidTableName) {
+			t.Errorf("Expected %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestGetTableRowCountRejectsInvalidNamesBeforeQuerying(t *testing.T) {
+	// No database is configured, so reaching the query would fail differently
+	service := NewIndexerTableService()
+	for _, name := range injectedTableNames {
+		if _, err := service.GetTableRowCount(name); !errors.Is(err, ErrInvalidTableName) {
+			t.Errorf("Expected %q to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	if got := quoteIdentifier("f243__sukuk_purchase"); got != `"f243__sukuk_purchase"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+	// An embedded quote is doubled so it stays inside the identifier
+	if got := quoteIdentifier(`x"; DROP TABLE companies;--`); got != `"x""; DROP TABLE companies;--"` {
+		t.Errorf("Unexpected quoting: %s", got)
+	}
+}
+
+func TestValidateTableOverrideRejectsMismatchesBeforeQuerying(t *testing.T) {
+	service := NewIndexerTableService()
+	tests := []struct{ eventType, table string }{
+		{"holder_update", "f243__sukuk_purchase"},    // another event's table
+		{"holder_update", "f243__holder_update; --"}, // not a table name
+		{"Holder-Update", "f243__holder_update"},     // not an event type
+		{"holder_update", ""},
+	}
+	for _, tt := range tests {
+		if err := service.ValidateTableOverride(tt.eventType, tt.table); !errors.Is(err, ErrInvalidTableOverride) {
+			t.Errorf("Expected %s -> %q to be rejected, got %v", tt.eventType, tt.table, err)
+		}
+	}
+}
+
+// TestTableOverrideWinsOverDiscovery requires a reachable Postgres, see testutil.Open
+func TestTableOverrideWinsOverDiscovery(t *testing.T) {
+	db := testutil.DB(t)
+
+	// Two deployments of the same event; the newer one has indexed further
+	const eventType = "overridetest_event"
+	const older, newer = "0a01__" + eventType, "0a02__" + eventType
+	for table, maxBlock := range map[string]int{older: 100, newer: 200} {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		if err := db.Exec("CREATE TABLE " + table + " (id TEXT PRIMARY KEY, block_number BIGINT, tx_hash TEXT, timestamp BIGINT)").Error; err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+		db.Exec("INSERT INTO "+table+" VALUES (?, ?, ?, ?)", "1", maxBlock, "0x1", 1)
+	}
+	defer models.DeleteIndexerTableOverride(db, eventType)
+
+	service := &IndexerTableService{indexerDB: db}
+	if table, err := service.GetLatestTableForEvent(eventType); err != nil || table != newer {
+		t.Fatalf("Expected discovery to pick %s, got %s (%v)", newer, table, err)
+	}
+
+	if err := service.ValidateTableOverride(eventType, "0a03__"+eventType); !errors.Is(err, ErrInvalidTableOverride) {
+		t.Errorf("Expected an override to a missing table to be rejected, got %v", err)
+	}
+	if err := service.ValidateTableOverride(eventType, older); err != nil {
+		t.Fatalf("Expected an override to %s to be valid, got %v", older, err)
+	}
+	if err := models.SetIndexerTableOverride(db, eventType, older); err != nil {
+		t.Fatalf("Failed to set override: %v", err)
+	}
+
+	if table, err := service.GetLatestTableForEvent(eventType); err != nil || table != older {
+		t.Errorf("Expected the override %s to win over %s, got %s (%v)", older, newer, table, err)
+	}
+	latest, err := service.GetAllLatestTables()
+	if err != nil || latest[eventType] != older {
+		t.Errorf("Expected GetAllLatestTables to honor the override, got %s (%v)", latest[eventType], err)
+	}
+
+	// Removing the override hands the choice back to discovery
+	if err := models.DeleteIndexerTableOverride(db, eventType); err != nil {
+		t.Fatalf("Failed to delete override: %v", err)
+	}
+	if table, _ := service.GetLatestTableForEvent(eventType); table != newer {
+		t.Errorf("Expected discovery to pick %s again, got %s", newer, table)
+	}
+}
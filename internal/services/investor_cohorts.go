@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// cohortCount is the number of investors of a cohort matching a metric some months after
+// the cohort month
+type cohortCount struct {
+	Cohort      string `gorm:"column:cohort"`
+	MonthOffset int    `gorm:"column:month_offset"`
+	Investors   int    `gorm:"column:investors"`
+}
+
+// cohortFirstPurchases buckets every buyer by the Jakarta month of their first purchase,
+// formatted with the purchase table
+const cohortFirstPurchases = `
+	firsts AS (
+		SELECT LOWER(buyer) AS investor,
+			date_trunc('month', MIN(to_timestamp(timestamp) AT TIME ZONE @tz)) AS cohort
+		FROM %[1]s
+		GROUP BY LOWER(buyer)
+	)`
+
+// cohortMonthOffset is the months from a cohort to month
+const cohortMonthOffset = `((EXTRACT(YEAR FROM %[1]s) - EXTRACT(YEAR FROM cohort)) * 12 + EXTRACT(MONTH FROM %[1]s) - EXTRACT(MONTH FROM cohort))::int`
+
+// CohortRange resolves the first and last cohort months of a cohort analysis from YYYY-MM
+// parameters, by default the twelve months up to the current one in Jakarta
+func CohortRange(from, to string, now time.Time) (string, string, error) {
+	if to == "" {
+		to = CurrentReportMonth(now)
+	}
+	if err := ValidateReportMonth(to, now); err != nil {
+		return "", "", fmt.Errorf("to: %w", err)
+	}
+	end, _, _ := ReportMonthRange(to)
+	if from == "" {
+		from = end.AddDate(0, -11, 0).Format(InvestorReportMonthLayout)
+	}
+	start, _, err := ReportMonthRange(from)
+	if err != nil {
+		return "", "", fmt.Errorf("from: %w", err)
+	}
+	if start.After(end) {
+		return "", "", fmt.Errorf("from %s is after to %s", from, to)
+	}
+	if months := cohortMonthsBetween(start, end) + 1; months > models.CohortMaxMonths {
+		return "", "", fmt.Errorf("%s to %s covers %d months, at most %d are allowed", from, to, months, models.CohortMaxMonths)
+	}
+	return from, to, nil
+}
+
+// cohortMonthsBetween counts the calendar months from start to end
+func cohortMonthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+}
+
+// GetInvestorCohorts buckets investors by the Jakarta month of their first purchase from
+// from to to (YYYY-MM), and counts per cohort how many match metric in each month since,
+// up to to. Both are counted in SQL: a repeat purchase is any purchase in a later month, and
+// a retained balance is a non-zero latest holder_update, as of the end of the month, in at
+// least one sukuk
+func (s *IndexerQueryService) GetInvestorCohorts(ctx context.Context, metric models.CohortMetric, from, to string) (*models.CohortAnalyticsResponse, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sukuk_purchase table: %w", err)
+	}
+	args := map[string]interface{}{"tz": TaxReportTimezone, "from": from, "to": to}
+
+	var sizes []cohortCount
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`WITH `+cohortFirstPurchases+`
+			SELECT to_char(cohort, 'YYYY-MM') AS cohort, 0 AS month_offset, COUNT(*) AS investors
+			FROM firsts
+			WHERE to_char(cohort, 'YYYY-MM') BETWEEN @from AND @to
+			GROUP BY cohort`, quoteIdentifier(purchaseTable))
+		return db.Raw(query, args).Scan(&sizes).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cohorts: %w", err)
+	}
+
+	var matches []cohortCount
+	switch metric {
+	case models.CohortMetricRepeatPurchase:
+		err = s.read(ctx, func(db *gorm.DB) error {
+			query := fmt.Sprintf(`WITH `+cohortFirstPurchases+`,
+				active AS (
+					SELECT DISTINCT LOWER(buyer) AS investor, date_trunc('month', to_timestamp(timestamp) AT TIME ZONE @tz) AS month
+					FROM %[1]s
+				)
+				SELECT to_char(cohort, 'YYYY-MM') AS cohort, `+fmt.Sprintf(cohortMonthOffset, "a.month")+` AS month_offset, COUNT(*) AS investors
+				FROM firsts f
+				JOIN active a ON a.investor = f.investor AND a.month > f.cohort AND to_char(a.month, 'YYYY-MM') <= @to
+				WHERE to_char(cohort, 'YYYY-MM') BETWEEN @from AND @to
+				GROUP BY cohort, a.month`, quoteIdentifier(purchaseTable))
+			return db.Raw(query, args).Scan(&matches).Error
+		})
+	case models.CohortMetricRetainedBalance:
+		holderTable, tableErr := s.tableService.GetLatestTableForEvent("holder_update")
+		if tableErr != nil {
+			return nil, fmt.Errorf("failed to find holder_update table: %w", tableErr)
+		}
+		err = s.read(ctx, func(db *gorm.DB) error {
+			query := fmt.Sprintf(`WITH `+cohortFirstPurchases+`,
+				cohort_months AS (
+					SELECT f.investor, f.cohort, m.month
+					FROM firsts f
+					CROSS JOIN LATERAL generate_series(f.cohort, to_date(@to, 'YYYY-MM')::timestamp, interval '1 month') AS m(month)
+					WHERE to_char(f.cohort, 'YYYY-MM') BETWEEN @from AND @to
+				),
+				latest AS (
+					SELECT DISTINCT ON (cm.investor, cm.month, LOWER(h.sukuk_address)) cm.investor, cm.cohort, cm.month, h.new_balance
+					FROM cohort_months cm
+					JOIN %[2]s h ON LOWER(h.holder) = cm.investor
+						AND to_timestamp(h.timestamp) AT TIME ZONE @tz < cm.month + interval '1 month'
+					ORDER BY cm.investor, cm.month, LOWER(h.sukuk_address), h.block_number DESC, h.timestamp DESC, h.id DESC
+				)
+				SELECT to_char(cohort, 'YYYY-MM') AS cohort, `+fmt.Sprintf(cohortMonthOffset, "month")+` AS month_offset, COUNT(DISTINCT investor) AS investors
+				FROM latest
+				WHERE new_balance > 0
+				GROUP BY cohort, month`, quoteIdentifier(purchaseTable), quoteIdentifier(holderTable))
+			return db.Raw(query, args).Scan(&matches).Error
+		})
+	default:
+		return nil, fmt.Errorf("unknown cohort metric %q", metric)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cohort %s: %w", metric, err)
+	}
+
+	return buildInvestorCohorts(metric, from, to, sizes, matches), nil
+}
+
+// buildInvestorCohorts lays the counts out as a heatmap with a row for every month from from
+// to to, empty cohorts included, and a column for every month from the cohort to to. Every
+// investor purchased in their cohort month, so month 0 of repeat_purchase is the cohort size
+func buildInvestorCohorts(metric models.CohortMetric, from, to string, sizes, matches []cohortCount) *models.CohortAnalyticsResponse {
+	start, _, _ := ReportMonthRange(from)
+	end, _, _ := ReportMonthRange(to)
+
+	response := &models.CohortAnalyticsResponse{
+		Metric:   metric,
+		From:     from,
+		To:       to,
+		Timezone: TaxReportTimezone,
+		Cohorts:  []models.InvestorCohort{},
+	}
+	index := make(map[string]int)
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		width := cohortMonthsBetween(month, end) + 1
+		cohort := models.InvestorCohort{
+			Cohort: month.Format(InvestorReportMonthLayout),
+			Values: make([]int, width),
+			Rates:  make([]float64, width),
+		}
+		index[cohort.Cohort] = len(response.Cohorts)
+		response.Cohorts = append(response.Cohorts, cohort)
+	}
+
+	for _, size := range sizes {
+		if i, ok := index[size.Cohort]; ok {
+			response.Cohorts[i].Size = size.Investors
+			if metric == models.CohortMetricRepeatPurchase {
+				response.Cohorts[i].Values[0] = size.Investors
+			}
+		}
+	}
+	for _, match := range matches {
+		if i, ok := index[match.Cohort]; ok && match.MonthOffset >= 0 && match.MonthOffset < len(response.Cohorts[i].Values) {
+			response.Cohorts[i].Values[match.MonthOffset] = match.Investors
+		}
+	}
+
+	for i := range response.Cohorts {
+		cohort := &response.Cohorts[i]
+		if cohort.Size == 0 {
+			continue
+		}
+		for n, value := range cohort.Values {
+			cohort.Rates[n] = math.Round(float64(value)/float64(cohort.Size)*10000) / 10000
+		}
+	}
+	return response
+}
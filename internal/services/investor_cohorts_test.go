@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestCohortRange(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		from, to         string
+		wantFrom, wantTo string
+		wantErr          string
+	}{
+		{"", "", "2024-07", "2025-06", ""},
+		{"", "2025-03", "2024-04", "2025-03", ""},
+		{"2025-02", "", "2025-02", "2025-06", ""},
+		{"2023-07", "2025-06", "2023-07", "2025-06", ""},
+		{"2023-06", "2025-06", "", "", "at most 24"},
+		{"2025-05", "2025-04", "", "", "after"},
+		{"2025-01", "2025-07", "", "", "to:"},
+		{"2025-1", "2025-06", "", "", "from:"},
+	}
+	for _, tt := range tests {
+		from, to, err := CohortRange(tt.from, tt.to, now)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q to %q: expected an error containing %q, got %v", tt.from, tt.to, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || from != tt.wantFrom || to != tt.wantTo {
+			t.Errorf("%q to %q: expected %s to %s, got %s to %s (%v)", tt.from, tt.to, tt.wantFrom, tt.wantTo, from, to, err)
+		}
+	}
+}
+
+func TestBuildInvestorCohorts(t *testing.T) {
+	sizes := []cohortCount{
+		{Cohort: "2025-01", Investors: 4},
+		{Cohort: "2025-03", Investors: 3},
+		{Cohort: "2024-12", Investors: 9}, // Outside the range
+	}
+	matches := []cohortCount{
+		{Cohort: "2025-01", MonthOffset: 1, Investors: 2},
+		{Cohort: "2025-01", MonthOffset: 2, Investors: 1},
+		{Cohort: "2025-03", MonthOffset: 0, Investors: 3},
+	}
+
+	repeat := buildInvestorCohorts(models.CohortMetricRepeatPurchase, "2025-01", "2025-03", sizes, matches[:2])
+	if len(repeat.Cohorts) != 3 {
+		t.Fatalf("Expected a row for each of three months, got %+v", repeat.Cohorts)
+	}
+	want := []models.InvestorCohort{
+		{Cohort: "2025-01", Size: 4, Values: []int{4, 2, 1}, Rates: []float64{1, 0.5, 0.25}},
+		{Cohort: "2025-02", Size: 0, Values: []int{0, 0}, Rates: []float64{0, 0}},
+		{Cohort: "2025-03", Size: 3, Values: []int{3}, Rates: []float64{1}},
+	}
+	if !reflect.DeepEqual(repeat.Cohorts, want) {
+		t.Errorf("Expected %+v, got %+v", want, repeat.Cohorts)
+	}
+
+	// Retention isn't implied by the first purchase, so month 0 is only what was counted
+	retained := buildInvestorCohorts(models.CohortMetricRetainedBalance, "2025-01", "2025-03", sizes, matches)
+	if got := retained.Cohorts[0].Values; !reflect.DeepEqual(got, []int{0, 2, 1}) {
+		t.Errorf("Expected January retention [0 2 1], got %v", got)
+	}
+	if got := retained.Cohorts[2].Rates; !reflect.DeepEqual(got, []float64{1}) {
+		t.Errorf("Expected March rate [1], got %v", got)
+	}
+}
+
+// TestGetInvestorCohorts requires a reachable Postgres, see TestSyncLedgerEntries. Three cohorts
+// are injected in 2019, ahead of anything else the test database holds
+func TestGetInvestorCohorts(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	injector, err := NewEventInjector(db, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		sukuk = "0x00000000000000000000000000000000000c0401"
+		token = "0x00000000000000000000000000000000000c0402"
+		// January: a buys again in March and holds, b redeems everything in February
+		a = "0x00000000000000000000000000000000000c04a1"
+		b = "0x00000000000000000000000000000000000c04b1"
+		// February: c buys twice in February and holds
+		c = "0x00000000000000000000000000000000000c04c1"
+		// March: d buys again in April and redeems everything the same month
+		d = "0x00000000000000000000000000000000000c04d1"
+	)
+	at := func(month time.Month, day int) int64 {
+		return time.Date(2019, month, day, 0, 0, 0, 0, time.UTC).Unix()
+	}
+	type cohortEvent struct {
+		eventType string
+		payload   json.RawMessage
+	}
+	purchase := func(buyer, amount string, timestamp int64) cohortEvent {
+		return cohortEvent{"sukuk_purchase", json.RawMessage(fmt.Sprintf(`{"buyer":%q,"sukuk_address":%q,"payment_token":%q,"amount":%q,"timestamp":%d}`,
+			buyer, sukuk, token, amount, timestamp))}
+	}
+	redemption := func(user, amount string, timestamp int64) cohortEvent {
+		return cohortEvent{"redemption_request", json.RawMessage(fmt.Sprintf(`{"user":%q,"sukuk_address":%q,"payment_token":%q,"amount":%q,"timestamp":%d}`,
+			user, sukuk, token, amount, timestamp))}
+	}
+	events := []cohortEvent{
+		purchase(a, "100", at(time.January, 10)),
+		purchase(b, "100", at(time.January, 12)),
+		redemption(b, "100", at(time.February, 5)),
+		purchase(c, "100", at(time.February, 8)),
+		purchase(c, "50", at(time.February, 20)),
+		purchase(a, "100", at(time.March, 3)),
+		purchase(d, "100", at(time.March, 15)),
+		purchase(d, "100", at(time.April, 2)),
+		redemption(d, "200", at(time.April, 20)),
+	}
+
+	ctx := context.Background()
+	var hashes []string
+	t.Cleanup(func() {
+		for _, table := range database.SyntheticIndexerTableNames() {
+			db.Exec(`DELETE FROM "`+table+`" WHERE tx_hash IN ?`, hashes)
+		}
+	})
+	for _, event := range events {
+		injected, err := injector.Inject(ctx, event.eventType, event.payload)
+		if err != nil {
+			t.Fatalf("Failed to inject %s %s: %v", event.eventType, event.payload, err)
+		}
+		for _, e := range injected {
+			hashes = append(hashes, e.TxHash)
+		}
+	}
+
+	tests := []struct {
+		metric models.CohortMetric
+		want   map[string][]int
+	}{
+		{models.CohortMetricRepeatPurchase, map[string][]int{
+			"2019-01": {2, 0, 1, 0},
+			"2019-02": {1, 0, 0},
+			"2019-03": {1, 1},
+			"2019-04": {0},
+		}},
+		{models.CohortMetricRetainedBalance, map[string][]int{
+			"2019-01": {2, 1, 1, 1},
+			"2019-02": {1, 1, 1},
+			"2019-03": {1, 0},
+			"2019-04": {0},
+		}},
+	}
+	sizes := map[string]int{"2019-01": 2, "2019-02": 1, "2019-03": 1, "2019-04": 0}
+	for _, tt := range tests {
+		response, err := NewIndexerQueryService().GetInvestorCohorts(ctx, tt.metric, "2019-01", "2019-04")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.metric, err)
+		}
+		if len(response.Cohorts) != len(tt.want) {
+			t.Fatalf("%s: expected %d cohorts, got %+v", tt.metric, len(tt.want), response.Cohorts)
+		}
+		for _, cohort := range response.Cohorts {
+			if cohort.Size != sizes[cohort.Cohort] {
+				t.Errorf("%s %s: expected size %d, got %d", tt.metric, cohort.Cohort, sizes[cohort.Cohort], cohort.Size)
+			}
+			if !reflect.DeepEqual(cohort.Values, tt.want[cohort.Cohort]) {
+				t.Errorf("%s %s: expected %v, got %v", tt.metric, cohort.Cohort, tt.want[cohort.Cohort], cohort.Values)
+			}
+		}
+	}
+}
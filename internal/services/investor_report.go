@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// InvestorReportMonthLayout is the format of the month an investor report covers
+const InvestorReportMonthLayout = "2006-01"
+
+// investorReportFlushRows is how many CSV rows are written between flushes
+const investorReportFlushRows = 100
+
+// ReportMonthRange returns the start (inclusive) and end (exclusive) of a YYYY-MM month in Jakarta time
+func ReportMonthRange(month string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(InvestorReportMonthLayout, month, taxReportLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("month must be formatted as YYYY-MM, got %q", month)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// CurrentReportMonth returns the month in Jakarta at now, formatted as YYYY-MM
+func CurrentReportMonth(now time.Time) string {
+	return now.In(taxReportLocation).Format(InvestorReportMonthLayout)
+}
+
+// ValidateReportMonth rejects malformed months and months that have not started in Jakarta
+func ValidateReportMonth(month string, now time.Time) error {
+	start, _, err := ReportMonthRange(month)
+	if err != nil {
+		return err
+	}
+	if start.After(now) {
+		return fmt.Errorf("month %s is in the future (current month is %s)", month, CurrentReportMonth(now))
+	}
+	return nil
+}
+
+// GenerateInvestorReport aggregates a month of investor activity on the sukuk owned by an issuer
+func GenerateInvestorReport(ctx context.Context, issuerAddress, month string) (*models.InvestorReport, error) {
+	from, to, err := ReportMonthRange(month)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.GetDB().WithContext(ctx)
+	var metadata []models.SukukMetadata
+	if err := db.Where("LOWER(owner_address) = ?", utils.NormalizeAddress(issuerAddress)).Find(&metadata).Error; err != nil {
+		return nil, err
+	}
+
+	sukukAddresses := make([]string, len(metadata))
+	for i, sukuk := range metadata {
+		sukukAddresses[i] = sukuk.ContractAddress
+	}
+
+	indexerService := NewIndexerQueryService()
+	var (
+		purchases []IndexerSukukPurchase
+		requests  []IndexerRedemptionRequest
+		approvals []IndexerRedemptionApproval
+		claims    []IndexerYieldClaimed
+	)
+	if len(sukukAddresses) > 0 {
+		if err := indexerService.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+		sources := []struct {
+			eventType string
+			dest      interface{}
+		}{
+			{"sukuk_purchase", &purchases},
+			{"redemption_request", &requests},
+			{"redemption_approval", &approvals},
+			{"yield_claim", &claims},
+		}
+		for _, source := range sources {
+			if err := indexerService.GetSukukEventsBetween(ctx, source.eventType, sukukAddresses, from, to, source.dest); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report := BuildInvestorReport(issuerAddress, month, metadata, purchases, requests, approvals, claims)
+
+	// KYC status is attached for investors with a profile
+	investors := make([]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		investors = append(investors, row.InvestorAddress)
+	}
+	if len(investors) > 0 {
+		var profiles []models.InvestorProfile
+		if err := db.Where("wallet_address IN ?", investors).Find(&profiles).Error; err != nil {
+			return nil, err
+		}
+		statuses := make(map[string]models.KYCStatus, len(profiles))
+		for _, profile := range profiles {
+			statuses[utils.NormalizeAddress(profile.WalletAddress)] = profile.KYCStatus
+		}
+		for i := range report.Rows {
+			report.Rows[i].KYCStatus = statuses[report.Rows[i].InvestorAddress]
+		}
+	}
+
+	return report, nil
+}
+
+// BuildInvestorReport sums purchases, redemptions and yield claims per investor per sukuk
+// Events outside the month or from sukuk not in metadata are ignored
+func BuildInvestorReport(issuerAddress, month string, metadata []models.SukukMetadata, purchases []IndexerSukukPurchase, requests []IndexerRedemptionRequest, approvals []IndexerRedemptionApproval, claims []IndexerYieldClaimed) *models.InvestorReport {
+	from, to, _ := ReportMonthRange(month)
+	report := &models.InvestorReport{
+		IssuerAddress: utils.NormalizeAddress(issuerAddress),
+		Month:         month,
+		Timezone:      TaxReportTimezone,
+		PeriodStart:   from,
+		PeriodEnd:     to,
+		SukukCount:    len(metadata),
+		Rows:          make([]models.InvestorReportRow, 0),
+	}
+
+	sukukCodes := make(map[string]string, len(metadata))
+	for _, sukuk := range metadata {
+		sukukCodes[utils.NormalizeAddress(sukuk.ContractAddress)] = sukuk.SukukCode
+	}
+
+	mathUtil := utils.GlobalTokenMath
+	rows := make(map[string]*models.InvestorReportRow)
+	// row returns the row of an investor and sukuk, or nil for events the report ignores
+	row := func(investor, sukukAddress string, timestamp int64) *models.InvestorReportRow {
+		at := time.Unix(timestamp, 0)
+		if at.Before(from) || !at.Before(to) {
+			return nil
+		}
+		investor, sukukAddress = utils.NormalizeAddress(investor), utils.NormalizeAddress(sukukAddress)
+		code, owned := sukukCodes[sukukAddress]
+		if !owned {
+			return nil
+		}
+
+		key := investor + "|" + sukukAddress
+		if r, ok := rows[key]; ok {
+			return r
+		}
+		r := &models.InvestorReportRow{
+			InvestorAddress:           investor,
+			SukukAddress:              sukukAddress,
+			SukukCode:                 code,
+			PurchasedAmount:           "0",
+			RedemptionRequestedAmount: "0",
+			RedemptionApprovedAmount:  "0",
+			YieldClaimedAmount:        "0",
+		}
+		rows[key] = r
+		return r
+	}
+	// add sums amounts, counting malformed ones as zero
+	add := func(total *string, amount string) {
+		if sum, err := mathUtil.AddTokenAmounts(*total, amount); err == nil {
+			*total = sum
+		}
+	}
+
+	for _, purchase := range purchases {
+		if r := row(purchase.Buyer, purchase.SukukAddress, purchase.Timestamp); r != nil {
+			r.PurchaseCount++
+			add(&r.PurchasedAmount, purchase.Amount)
+		}
+	}
+	for _, request := range requests {
+		if r := row(request.User, request.SukukAddress, request.Timestamp); r != nil {
+			r.RedemptionCount++
+			add(&r.RedemptionRequestedAmount, request.Amount)
+		}
+	}
+	for _, approval := range approvals {
+		if r := row(approval.User, approval.SukukAddress, approval.Timestamp); r != nil {
+			add(&r.RedemptionApprovedAmount, approval.Amount)
+		}
+	}
+	for _, claim := range claims {
+		if r := row(claim.User, claim.SukukAddress, claim.Timestamp); r != nil {
+			r.YieldClaimCount++
+			add(&r.YieldClaimedAmount, claim.Amount)
+		}
+	}
+
+	for _, r := range rows {
+		report.Rows = append(report.Rows, *r)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].InvestorAddress != report.Rows[j].InvestorAddress {
+			return report.Rows[i].InvestorAddress < report.Rows[j].InvestorAddress
+		}
+		return report.Rows[i].SukukAddress < report.Rows[j].SukukAddress
+	})
+	return report
+}
+
+// investorReportHeader is the first CSV row, written even for months without activity
+var investorReportHeader = []string{
+	"month", "investor_address", "kyc_status", "sukuk_address", "sukuk_code",
+	"purchase_count", "purchased_amount", "redemption_count", "redemption_requested_amount",
+	"redemption_approved_amount", "yield_claim_count", "yield_claimed_amount",
+}
+
+// WriteInvestorReportCSV streams the report as CSV, one row per investor per sukuk
+func WriteInvestorReportCSV(w io.Writer, report *models.InvestorReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(investorReportHeader); err != nil {
+		return err
+	}
+
+	for i, row := range report.Rows {
+		err := writer.Write([]string{
+			report.Month, row.InvestorAddress, string(row.KYCStatus), row.SukukAddress, row.SukukCode,
+			strconv.Itoa(row.PurchaseCount), row.PurchasedAmount,
+			strconv.Itoa(row.RedemptionCount), row.RedemptionRequestedAmount, row.RedemptionApprovedAmount,
+			strconv.Itoa(row.YieldClaimCount), row.YieldClaimedAmount,
+		})
+		if err != nil {
+			return err
+		}
+		if (i+1)%investorReportFlushRows == 0 {
+			writer.Flush()
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// InvestorReportFilename names the CSV download, e.g. investor-report-0xabc-2024-05.csv
+func InvestorReportFilename(report *models.InvestorReport) string {
+	return fmt.Sprintf("investor-report-%s-%s.csv", strings.ToLower(report.IssuerAddress), report.Month)
+}
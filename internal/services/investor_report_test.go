@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func TestReportMonthRangeUsesJakartaBoundaries(t *testing.T) {
+	from, to, err := ReportMonthRange("2024-02")
+	if err != nil {
+		t.Fatalf("Failed to parse month: %v", err)
+	}
+	// Midnight on 1 February in Jakarta (UTC+7) is 17:00 UTC on 31 January; 2024 is a leap year
+	if want := time.Date(2024, 1, 31, 17, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("Expected the month to start at %s, got %s", want, from.UTC())
+	}
+	if want := time.Date(2024, 2, 29, 17, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("Expected the month to end at %s, got %s", want, to.UTC())
+	}
+
+	for _, month := range []string{"2024-13", "2024-2", "May 2024", ""} {
+		if _, _, err := ReportMonthRange(month); err == nil {
+			t.Errorf("Expected %q to be rejected", month)
+		}
+	}
+}
+
+func TestValidateReportMonth(t *testing.T) {
+	// 18:00 UTC on 31 May 2024 is already June in Jakarta
+	now := time.Date(2024, 5, 31, 18, 0, 0, 0, time.UTC)
+
+	if err := ValidateReportMonth("2024-06", now); err != nil {
+		t.Errorf("Expected the current Jakarta month to be valid, got %v", err)
+	}
+	if err := ValidateReportMonth("2024-07", now); err == nil {
+		t.Error("Expected a future month to be rejected")
+	}
+}
+
+func TestBuildInvestorReportMonthBoundaries(t *testing.T) {
+	const (
+		alice  = "0x00000000000000000000000000000000000000A1"
+		bob    = "0x00000000000000000000000000000000000000b2"
+		sukukA = "0x00000000000000000000000000000000000000c1"
+		sukukB = "0x00000000000000000000000000000000000000c2"
+		other  = "0x00000000000000000000000000000000000000ff" // Another issuer's sukuk
+	)
+	metadata := []models.SukukMetadata{
+		{ContractAddress: sukukA, SukukCode: "SRA"},
+		{ContractAddress: sukukB, SukukCode: "SRB"},
+	}
+	start := time.Date(2024, 4, 30, 17, 0, 0, 0, time.UTC) // 00:00 on 1 May in Jakarta
+	end := time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC)   // 00:00 on 1 June in Jakarta
+	before, last := start.Add(-time.Second).Unix(), end.Add(-time.Second).Unix()
+
+	purchases := []IndexerSukukPurchase{
+		{Buyer: alice, SukukAddress: sukukA, Amount: "100", Timestamp: before}, // 30 April in Jakarta
+		{Buyer: alice, SukukAddress: sukukA, Amount: "200", Timestamp: start.Unix()},
+		{Buyer: alice, SukukAddress: sukukA, Amount: "300", Timestamp: last},
+		{Buyer: alice, SukukAddress: sukukA, Amount: "400", Timestamp: end.Unix()}, // June in Jakarta
+		{Buyer: bob, SukukAddress: other, Amount: "500", Timestamp: start.Unix()},
+	}
+	requests := []IndexerRedemptionRequest{
+		{User: bob, SukukAddress: sukukB, Amount: "70", Timestamp: start.Unix()},
+	}
+	approvals := []IndexerRedemptionApproval{
+		{User: bob, SukukAddress: sukukB, Amount: "60", Timestamp: last},
+	}
+	claims := []IndexerYieldClaimed{
+		{User: alice, SukukAddress: sukukA, Amount: "5", Timestamp: start.Unix()},
+		{User: alice, SukukAddress: sukukB, Amount: "7", Timestamp: last},
+	}
+
+	report := BuildInvestorReport(alice, "2024-05", metadata, purchases, requests, approvals, claims)
+
+	if len(report.Rows) != 3 {
+		t.Fatalf("Expected 3 investor/sukuk rows, got %+v", report.Rows)
+	}
+	a := report.Rows[0]
+	if a.InvestorAddress != "0x00000000000000000000000000000000000000a1" || a.SukukCode != "SRA" {
+		t.Fatalf("Expected alice's SRA row first, got %+v", a)
+	}
+	if a.PurchaseCount != 2 || a.PurchasedAmount != "500" || a.YieldClaimedAmount != "5" {
+		t.Errorf("Expected only the purchases within May, got %+v", a)
+	}
+	if b := report.Rows[1]; b.SukukCode != "SRB" || b.PurchasedAmount != "0" || b.YieldClaimedAmount != "7" {
+		t.Errorf("Unexpected alice SRB row: %+v", b)
+	}
+	bobRow := report.Rows[2]
+	if bobRow.RedemptionCount != 1 || bobRow.RedemptionRequestedAmount != "70" || bobRow.RedemptionApprovedAmount != "60" || bobRow.PurchaseCount != 0 {
+		t.Errorf("Expected bob's redemption only, got %+v", bobRow)
+	}
+}
+
+func TestWriteInvestorReportCSVEmptyMonth(t *testing.T) {
+	report := BuildInvestorReport("0x00000000000000000000000000000000000000a1", "2024-05", nil, nil, nil, nil, nil)
+
+	var buf bytes.Buffer
+	if err := WriteInvestorReportCSV(&buf, report); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse csv: %v", err)
+	}
+	if len(rows) != 1 || rows[0][0] != "month" {
+		t.Errorf("Expected only the header row, got %v", rows)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+)
+
+// ledgerBatchSize bounds how many movements of each kind are recorded per cycle
+const ledgerBatchSize = 500
+
+// indexerLogIndex is the log index of the indexer row aliased e, the suffix of its
+// "<tx_hash>-<log_index>" id
+const indexerLogIndex = `COALESCE(substring(e.id from '-([0-9]+)$')::bigint, 0)`
+
+// notInLedger keeps the indexer rows aliased e whose movement was not recorded yet. Every
+// recorded movement is a pair, so history is replayed into the ledger batch by batch
+const notInLedger = `NOT EXISTS (SELECT 1 FROM ledger_entries l WHERE l.tx_hash = LOWER(e.tx_hash) AND l.log_index = ` + indexerLogIndex + `)`
+
+// ledgerSource reads one kind of value movement from the indexer. The query is formatted
+// with the quoted tables of events, in order, and selects ledgerMovementRow columns
+type ledgerSource struct {
+	eventType models.LedgerEventType
+	events    []string
+	query     string
+}
+
+var ledgerSources = []ledgerSource{
+	{
+		eventType: models.LedgerEventPurchase,
+		events:    []string{"sukuk_purchase"},
+		query: `
+			SELECT e.buyer AS from_account, e.sukuk_address AS to_account, e.sukuk_address, e.payment_token AS token,
+			       e.amount::text AS amount, e.tx_hash, ` + indexerLogIndex + ` AS log_index, e.timestamp
+			FROM %[1]s e
+			WHERE ` + notInLedger + `
+			ORDER BY e.block_number ASC, e.id ASC
+			LIMIT ?`,
+	},
+	{
+		// Approvals carry no token; the payout is in the payment token of the user's latest
+		// request before it, as approvals are matched to requests by user and sukuk
+		eventType: models.LedgerEventRedemptionPayout,
+		events:    []string{"redemption_approval", "redemption_request"},
+		query: `
+			SELECT e.sukuk_address AS from_account, e."user" AS to_account, e.sukuk_address, r.payment_token AS token,
+			       e.amount::text AS amount, e.tx_hash, ` + indexerLogIndex + ` AS log_index, e.timestamp
+			FROM %[1]s e
+			JOIN LATERAL (
+				SELECT payment_token FROM %[2]s
+				WHERE "user" = e."user" AND sukuk_address = e.sukuk_address AND timestamp <= e.timestamp
+				ORDER BY timestamp DESC
+				LIMIT 1
+			) r ON true
+			WHERE ` + notInLedger + `
+			ORDER BY e.block_number ASC, e.id ASC
+			LIMIT ?`,
+	},
+	{
+		eventType: models.LedgerEventYieldPayment,
+		events:    []string{"yield_claim", "yield_distributed"},
+		query: `
+			SELECT e.sukuk_address AS from_account, e."user" AS to_account, e.sukuk_address, d.payment_token AS token,
+			       e.amount::text AS amount, e.tx_hash, ` + indexerLogIndex + ` AS log_index, e.timestamp
+			FROM %[1]s e
+			JOIN %[2]s d ON d.sukuk_address = e.sukuk_address AND d.distribution_id = e.distribution_id
+			WHERE ` + notInLedger + `
+			ORDER BY e.block_number ASC, e.id ASC
+			LIMIT ?`,
+	},
+}
+
+// ledgerMovementRow is an indexed value movement; the timestamp is in unix seconds
+type ledgerMovementRow struct {
+	FromAccount  string
+	ToAccount    string
+	SukukAddress string
+	Token        string
+	Amount       string
+	TxHash       string
+	LogIndex     int64
+	Timestamp    int64
+}
+
+func (r ledgerMovementRow) movement(eventType models.LedgerEventType) models.LedgerMovement {
+	return models.LedgerMovement{
+		EventType:    eventType,
+		From:         r.FromAccount,
+		To:           r.ToAccount,
+		SukukAddress: r.SukukAddress,
+		Token:        r.Token,
+		Amount:       r.Amount,
+		TxHash:       r.TxHash,
+		LogIndex:     uint(r.LogIndex),
+		Timestamp:    time.Unix(r.Timestamp, 0).UTC(),
+	}
+}
+
+// syncLedgerEntries records new purchases, redemption payouts and yield payments in the
+// ledger, each as a balanced pair of entries. Kinds whose indexer tables don't exist yet
+// are skipped
+func (s *SukukMetadataSyncService) syncLedgerEntries(ctx context.Context, result *SyncResult) error {
+sources:
+	for _, source := range ledgerSources {
+		tables := make([]interface{}, len(source.events))
+		for i, event := range source.events {
+			table, err := s.findLatestEventTable(ctx, event)
+			if err != nil {
+				return err
+			}
+			if table == "" {
+				continue sources
+			}
+			tables[i] = quoteIdentifier(table)
+		}
+
+		var rows []ledgerMovementRow
+		if err := s.db.WithContext(ctx).Raw(fmt.Sprintf(source.query, tables...), ledgerBatchSize).Scan(&rows).Error; err != nil {
+			return fmt.Errorf("failed to fetch %s movements: %w", source.eventType, err)
+		}
+
+		for _, row := range rows {
+			err := models.CreateLedgerMovement(s.db.WithContext(ctx), row.movement(source.eventType))
+			if errors.Is(err, models.ErrDuplicateEvent) {
+				continue
+			}
+			if err != nil {
+				logger.WithError(err).WithFields(map[string]interface{}{
+					"event_type": source.eventType,
+					"tx_hash":    row.TxHash,
+					"log_index":  row.LogIndex,
+				}).Error("Failed to record ledger entries")
+				result.Failed++
+				continue
+			}
+			result.LedgerRecorded++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+// TestSyncLedgerEntries requires a reachable Postgres, see testutil.Open
+func TestSyncLedgerEntries(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	// Stand-in indexer tables, named to sort after any real <hash>__<event> table
+	tables := map[string]string{
+		"zzledgertest__sukuk_purchase":      `buyer TEXT, payment_token TEXT`,
+		"zzledgertest__redemption_request":  `"user" TEXT, payment_token TEXT`,
+		"zzledgertest__redemption_approval": `"user" TEXT`,
+		"zzledgertest__yield_distributed":   `distribution_id BIGINT, payment_token TEXT`,
+		"zzledgertest__yield_claim":         `"user" TEXT, distribution_id BIGINT`,
+	}
+	for table, columns := range tables {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (id TEXT PRIMARY KEY, sukuk_address TEXT, amount NUMERIC(78,0),
+			block_number BIGINT, tx_hash TEXT, timestamp BIGINT, ` + columns + `)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+	}
+
+	const (
+		sukuk    = "0x00000000000000000000000000000000001ed001"
+		investor = "0x00000000000000000000000000000000000000aa"
+		idrx     = "0x00000000000000000000000000000000000000cc"
+	)
+	defer db.Where("sukuk_address = ?", sukuk).Delete(&models.LedgerEntry{})
+
+	seed := []struct {
+		query string
+		args  []interface{}
+	}{
+		// Two purchase logs in one transaction
+		{`INSERT INTO zzledgertest__sukuk_purchase (id, buyer, sukuk_address, payment_token, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED1-0", investor, sukuk, idrx, "1000", 100, "0xLED1", 1700000000}},
+		{`INSERT INTO zzledgertest__sukuk_purchase (id, buyer, sukuk_address, payment_token, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED1-1", investor, sukuk, idrx, "500", 100, "0xLED1", 1700000000}},
+		{`INSERT INTO zzledgertest__redemption_request (id, "user", sukuk_address, payment_token, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED2-0", investor, sukuk, idrx, "300", 110, "0xLED2", 1700000100}},
+		{`INSERT INTO zzledgertest__redemption_approval (id, "user", sukuk_address, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED3-2", investor, sukuk, "300", 120, "0xLED3", 1700000200}},
+		{`INSERT INTO zzledgertest__yield_distributed (id, sukuk_address, distribution_id, payment_token, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED4-0", sukuk, 1, idrx, "90", 130, "0xLED4", 1700000300}},
+		{`INSERT INTO zzledgertest__yield_claim (id, "user", sukuk_address, distribution_id, amount, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			[]interface{}{"0xLED5-0", investor, sukuk, 1, "30", 140, "0xLED5", 1700000400}},
+	}
+	for _, row := range seed {
+		if err := db.Exec(row.query, row.args...).Error; err != nil {
+			t.Fatalf("Failed to seed indexer row: %v", err)
+		}
+	}
+
+	service := &SukukMetadataSyncService{db: db}
+	result := &SyncResult{}
+	if err := service.syncLedgerEntries(ctx, result); err != nil {
+		t.Fatalf("Ledger sync failed: %v", err)
+	}
+	if result.LedgerRecorded != 4 || result.Failed != 0 {
+		t.Errorf("Expected 4 movements recorded, got %+v", result)
+	}
+
+	// A second cycle finds nothing new
+	result = &SyncResult{}
+	if err := service.syncLedgerEntries(ctx, result); err != nil {
+		t.Fatalf("Second ledger sync failed: %v", err)
+	}
+	if result.LedgerRecorded != 0 {
+		t.Errorf("Expected no movements recorded twice, got %d", result.LedgerRecorded)
+	}
+
+	// Every transaction balances
+	var unbalanced []string
+	err := db.Raw(`SELECT tx_hash FROM ledger_entries WHERE sukuk_address = ?
+		GROUP BY tx_hash
+		HAVING SUM(CASE WHEN direction = 'debit' THEN amount ELSE 0 END) <> SUM(CASE WHEN direction = 'credit' THEN amount ELSE 0 END)`, sukuk).
+		Scan(&unbalanced).Error
+	if err != nil {
+		t.Fatalf("Failed to check balance: %v", err)
+	}
+	if len(unbalanced) != 0 {
+		t.Errorf("Expected debits to equal credits per tx, unbalanced: %v", unbalanced)
+	}
+
+	entries, total, err := models.ListLedgerEntries(db, models.LedgerFilter{SukukAddress: sukuk, Limit: 50})
+	if err != nil {
+		t.Fatalf("Failed to list entries: %v", err)
+	}
+	if total != 8 || len(entries) != 8 {
+		t.Errorf("Expected 8 entries, got %d of %d", len(entries), total)
+	}
+
+	// The investor paid 1500 and received 300 + 30 back
+	balances, err := models.GetLedgerBalances(db, models.LedgerFilter{Account: investor, SukukAddress: sukuk})
+	if err != nil {
+		t.Fatalf("Failed to compute balances: %v", err)
+	}
+	if len(balances) != 1 || balances[0].Token != idrx || balances[0].Debits != "330" || balances[0].Credits != "1500" || balances[0].Balance != "-1170" {
+		t.Errorf("Unexpected investor balances: %+v", balances)
+	}
+}
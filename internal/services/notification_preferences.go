@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// Unsubscribe token errors
+var (
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+	ErrUnsubscribeTokenExpired = errors.New("unsubscribe token expired")
+)
+
+// NewUnsubscribeToken returns a token for an unsubscribe link in a notification email,
+// authenticating the address and kind with an HMAC-SHA256 under secret until expiresAt
+func NewUnsubscribeToken(secret, address string, kind models.NotificationKind, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%s|%s|%d", strings.ToLower(address), kind, expiresAt.Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(unsubscribeMAC(secret, encoded))
+}
+
+// ParseUnsubscribeToken returns the address and kind of a token made by NewUnsubscribeToken
+// The signature is checked before the expiry, so an expired token is still a genuine one
+func ParseUnsubscribeToken(secret, token string, now time.Time) (string, models.NotificationKind, error) {
+	encoded, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	provided, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(provided, unsubscribeMAC(secret, encoded)) {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != 3 {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	kind := models.NotificationKind(parts[1])
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if !utils.IsValidEthereumAddress(parts[0]) || !kind.IsValid() || err != nil {
+		return "", "", ErrInvalidUnsubscribeToken
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return "", "", ErrUnsubscribeTokenExpired
+	}
+	return parts[0], kind, nil
+}
+
+func unsubscribeMAC(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
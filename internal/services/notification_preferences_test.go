@@ -0,0 +1,66 @@
+package services
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// personalSign signs message the way a wallet's personal_sign does, returning r || s || v with v 27/28
+func personalSign(key *secp256k1.PrivateKey, message string) string {
+	compact := ecdsa.SignCompact(key, utils.PersonalMessageHash(message), false)
+	signature := append(append([]byte{}, compact[1:]...), compact[0])
+	return "0x" + hex.EncodeToString(signature)
+}
+
+func TestUnsubscribeTokenExpiry(t *testing.T) {
+	const secret = "unsubscribe-secret"
+	const address = "0x00000000000000000000000000000000000000aa"
+	issued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := issued.Add(30 * 24 * time.Hour)
+	token := NewUnsubscribeToken(secret, address, models.NotificationKindYield, expiresAt)
+
+	gotAddress, kind, err := ParseUnsubscribeToken(secret, token, expiresAt.Add(-time.Second))
+	if err != nil || gotAddress != address || kind != models.NotificationKindYield {
+		t.Fatalf("Expected a valid token for %s yield, got %s %s %v", address, gotAddress, kind, err)
+	}
+	if _, _, err := ParseUnsubscribeToken(secret, token, expiresAt); !errors.Is(err, ErrUnsubscribeTokenExpired) {
+		t.Errorf("Expected the token to expire at expiresAt, got %v", err)
+	}
+
+	if _, _, err := ParseUnsubscribeToken("another-secret", token, issued); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Errorf("Expected a token signed with another secret to be rejected, got %v", err)
+	}
+	// Extending the expiry invalidates the signature
+	forged := NewUnsubscribeToken("forger", address, models.NotificationKindAll, expiresAt.Add(time.Hour))
+	payload, _, _ := strings.Cut(forged, ".")
+	_, mac, _ := strings.Cut(token, ".")
+	if _, _, err := ParseUnsubscribeToken(secret, payload+"."+mac, issued); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Errorf("Expected a forged payload to be rejected, got %v", err)
+	}
+	for _, invalid := range []string{"", "abc", "abc.def"} {
+		if _, _, err := ParseUnsubscribeToken(secret, invalid, issued); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+			t.Errorf("Expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+}
+
+func TestNotificationPreferenceUnsubscribe(t *testing.T) {
+	preference := models.DefaultNotificationPreference("0x00000000000000000000000000000000000000AA")
+	preference.Unsubscribe(models.NotificationKindRedemption)
+	if preference.Allows(models.NotificationKindRedemption) || !preference.Allows(models.NotificationKindYield) || !preference.Allows(models.NotificationKindDigest) {
+		t.Errorf("Expected only redemption alerts to be off, got %+v", preference)
+	}
+	preference.Unsubscribe(models.NotificationKindAll)
+	if preference.Allows(models.NotificationKindYield) || preference.Allows(models.NotificationKindDigest) {
+		t.Errorf("Expected everything to be off, got %+v", preference)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OrderExpiryService periodically expires purchase orders left unpaid past expires_at
+type OrderExpiryService struct {
+	db       *gorm.DB
+	interval time.Duration
+	cancel   context.CancelFunc
+}
+
+// NewOrderExpiryService creates a service sweeping unpaid orders every interval
+func NewOrderExpiryService(interval time.Duration) *OrderExpiryService {
+	return &OrderExpiryService{
+		db:       database.GetDB(),
+		interval: interval,
+	}
+}
+
+// Start begins sweeping; it runs until ctx is cancelled or Stop is called
+func (s *OrderExpiryService) Start(ctx context.Context) {
+	logger.Info("Starting order expiry service")
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.sweepLoop(ctx)
+}
+
+// Stop stops sweeping
+func (s *OrderExpiryService) Stop() {
+	logger.Info("Stopping order expiry service")
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+func (s *OrderExpiryService) sweepLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep expires every created order whose expiry has passed, unless the
+// jobs.order_expiry.enabled setting turns sweeps off
+func (s *OrderExpiryService) sweep(ctx context.Context) {
+	if !Settings().GetBool(SettingOrderExpiryEnabled, true) {
+		logger.Debug("Order expiry disabled by runtime setting, skipping sweep")
+		return
+	}
+	expired, err := models.ExpireOrders(s.db.WithContext(ctx), time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Error("Failed to expire unpaid orders")
+		}
+		return
+	}
+	if expired > 0 {
+		logger.WithField("count", expired).Info("Expired unpaid orders")
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+)
+
+// DefaultOrderSettlementToleranceBps is the default allowed difference between an order's
+// quoted token amount and the purchased amount, in basis points
+const DefaultOrderSettlementToleranceBps = 50
+
+// orderSettlementBatchSize bounds how many paid orders are reconciled per cycle
+const orderSettlementBatchSize = 100
+
+// orderSettlement pairs a paid order with the onchain purchase that fulfilled it
+type orderSettlement struct {
+	order    models.Order
+	purchase IndexerSukukPurchase
+}
+
+// syncOrderSettlements settles paid orders whose onchain purchase has been indexed
+// A purchase matches when buyer and sukuk are the same, it happened after the order was
+// created, and its amount is within the tolerance of the quote. Each purchase settles one order
+func (s *SukukMetadataSyncService) syncOrderSettlements(ctx context.Context, result *SyncResult) error {
+	var orders []models.Order
+	err := s.db.WithContext(ctx).
+		Where("status = ?", models.OrderStatusPaid).
+		Order("created_at ASC").
+		Limit(orderSettlementBatchSize).
+		Find(&orders).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch paid orders: %w", err)
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+
+	tableName, err := s.findLatestEventTable(ctx, "sukuk_purchase")
+	if err != nil {
+		return err
+	}
+	if tableName == "" {
+		return nil
+	}
+
+	buyers := make([]string, 0, len(orders))
+	for _, order := range orders {
+		buyers = append(buyers, order.UserAddress)
+	}
+
+	var purchases []IndexerSukukPurchase
+	err = s.db.WithContext(ctx).Table(tableName).
+		Where("LOWER(buyer) IN ? AND timestamp >= ?", buyers, orders[0].CreatedAt.Unix()).
+		Order("block_number ASC").
+		Find(&purchases).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch purchases from %s: %w", tableName, err)
+	}
+	if len(purchases) == 0 {
+		return nil
+	}
+
+	// Purchases already linked to an earlier order can't settle another one
+	txHashes := make([]string, 0, len(purchases))
+	for _, purchase := range purchases {
+		txHashes = append(txHashes, purchase.TxHash)
+	}
+	var linked []string
+	err = s.db.WithContext(ctx).Model(&models.Order{}).
+		Where("settlement_tx_hash IN ?", txHashes).
+		Pluck("settlement_tx_hash", &linked).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch settled purchases: %w", err)
+	}
+
+	for _, settlement := range matchOrderSettlements(orders, purchases, linked, s.settlementToleranceBps) {
+		if err := s.settleOrder(ctx, settlement); err != nil {
+			logger.WithError(err).WithField("order_id", settlement.order.ID).Error("Failed to settle order")
+			result.Failed++
+			continue
+		}
+		result.Processed++
+	}
+
+	return nil
+}
+
+// settleOrder marks the order settled unless it left the paid state meanwhile
+func (s *SukukMetadataSyncService) settleOrder(ctx context.Context, settlement orderSettlement) error {
+	order := settlement.order
+	if err := order.Settle(settlement.purchase.TxHash, time.Now()); err != nil {
+		return err
+	}
+
+	err := s.db.WithContext(ctx).Model(&models.Order{}).
+		Where("id = ? AND status = ?", order.ID, models.OrderStatusPaid).
+		Updates(map[string]interface{}{
+			"status":             order.Status,
+			"settlement_tx_hash": order.SettlementTxHash,
+			"settled_at":         order.SettledAt,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"order_id": order.ID,
+		"tx_hash":  order.SettlementTxHash,
+	}).Info("Order settled onchain")
+	return nil
+}
+
+// matchOrderSettlements assigns each order, oldest first, the earliest unused purchase
+// that fulfils it. Purchases whose tx hash is in linked are skipped
+func matchOrderSettlements(orders []models.Order, purchases []IndexerSukukPurchase, linked []string, toleranceBps int64) []orderSettlement {
+	used := make(map[string]bool, len(linked)+len(orders))
+	for _, txHash := range linked {
+		used[strings.ToLower(txHash)] = true
+	}
+
+	sorted := append([]models.Order(nil), orders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var settlements []orderSettlement
+	for _, order := range sorted {
+		for _, purchase := range purchases {
+			if used[strings.ToLower(purchase.TxHash)] || !purchaseFulfilsOrder(order, purchase, toleranceBps) {
+				continue
+			}
+			used[strings.ToLower(purchase.TxHash)] = true
+			settlements = append(settlements, orderSettlement{order: order, purchase: purchase})
+			break
+		}
+	}
+	return settlements
+}
+
+// purchaseFulfilsOrder reports whether purchase is the onchain side of order
+func purchaseFulfilsOrder(order models.Order, purchase IndexerSukukPurchase, toleranceBps int64) bool {
+	if !strings.EqualFold(order.UserAddress, purchase.Buyer) || !strings.EqualFold(order.SukukAddress, purchase.SukukAddress) {
+		return false
+	}
+	if purchase.Timestamp < order.CreatedAt.Unix() {
+		return false
+	}
+	return amountWithinTolerance(order.TokenAmount.String(), purchase.Amount, toleranceBps)
+}
+
+// amountWithinTolerance reports whether actual differs from quoted by at most toleranceBps
+func amountWithinTolerance(quoted, actual string, toleranceBps int64) bool {
+	q, ok := new(big.Int).SetString(quoted, 10)
+	if !ok || q.Sign() <= 0 {
+		return false
+	}
+	a, ok := new(big.Int).SetString(actual, 10)
+	if !ok {
+		return false
+	}
+
+	// |actual - quoted| * 10000 <= quoted * toleranceBps
+	diff := new(big.Int).Abs(new(big.Int).Sub(a, q))
+	diff.Mul(diff, big.NewInt(10000))
+	allowed := new(big.Int).Mul(q, big.NewInt(toleranceBps))
+	return diff.Cmp(allowed) <= 0
+}
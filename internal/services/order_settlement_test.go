@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func TestAmountWithinTolerance(t *testing.T) {
+	tests := []struct {
+		quoted, actual string
+		bps            int64
+		want           bool
+	}{
+		{"1000000", "1000000", 0, true},
+		{"1000000", "1005000", 50, true},
+		{"1000000", "995000", 50, true},
+		{"1000000", "1005001", 50, false},
+		{"1000000", "994999", 50, false},
+		{"0", "0", 50, false},
+		{"abc", "1000000", 50, false},
+		{"1000000", "", 50, false},
+	}
+	for _, tt := range tests {
+		if got := amountWithinTolerance(tt.quoted, tt.actual, tt.bps); got != tt.want {
+			t.Errorf("amountWithinTolerance(%s, %s, %d) = %v, want %v", tt.quoted, tt.actual, tt.bps, got, tt.want)
+		}
+	}
+}
+
+func TestMatchOrderSettlements(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	buyer := "0x00000000000000000000000000000000000000aa"
+	sukuk := "0x00000000000000000000000000000000000000bb"
+
+	orders := []models.Order{
+		{ID: 2, UserAddress: buyer, SukukAddress: sukuk, TokenAmount: "1000", Status: models.OrderStatusPaid, CreatedAt: created.Add(time.Minute)},
+		{ID: 1, UserAddress: buyer, SukukAddress: sukuk, TokenAmount: "1000", Status: models.OrderStatusPaid, CreatedAt: created},
+		{ID: 3, UserAddress: buyer, SukukAddress: sukuk, TokenAmount: "5000", Status: models.OrderStatusPaid, CreatedAt: created},
+	}
+	purchase := func(txHash, buyer, sukuk, amount string, at time.Time) IndexerSukukPurchase {
+		return IndexerSukukPurchase{TxHash: txHash, Buyer: buyer, SukukAddress: sukuk, Amount: amount, Timestamp: at.Unix()}
+	}
+	purchases := []IndexerSukukPurchase{
+		purchase("0x01", buyer, sukuk, "1000", created.Add(-time.Hour)),                        // before any order
+		purchase("0x02", "0x00000000000000000000000000000000000000cc", sukuk, "1000", created), // other buyer
+		purchase("0x03", "0x00000000000000000000000000000000000000AA", sukuk, "1002", created.Add(2*time.Minute)),
+		purchase("0x04", buyer, sukuk, "999", created.Add(3*time.Minute)),
+		purchase("0x05", buyer, sukuk, "5000", created.Add(4*time.Minute)),
+	}
+
+	settlements := matchOrderSettlements(orders, purchases, []string{"0x05"}, 50)
+
+	got := make(map[uint]string)
+	for _, settlement := range settlements {
+		got[settlement.order.ID] = settlement.purchase.TxHash
+	}
+	want := map[uint]string{1: "0x03", 2: "0x04"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected settlements %v, got %v", want, got)
+	}
+	for id, txHash := range want {
+		if got[id] != txHash {
+			t.Errorf("Expected order %d to settle with %s, got %q", id, txHash, got[id])
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// TokenFormatter humanizes token amounts using registered payment token metadata
+type TokenFormatter struct {
+	tokens   map[string]models.PaymentToken
+	mathUtil *utils.TokenMath
+}
+
+// NewTokenFormatter creates a formatter for the given payment tokens
+func NewTokenFormatter(tokens []models.PaymentToken) *TokenFormatter {
+	tokenMap := make(map[string]models.PaymentToken, len(tokens))
+	for _, token := range tokens {
+		tokenMap[strings.ToLower(token.Address)] = token
+	}
+
+	return &TokenFormatter{
+		tokens:   tokenMap,
+		mathUtil: utils.GlobalTokenMath,
+	}
+}
+
+// LoadTokenFormatter creates a formatter with all payment tokens from the database
+func LoadTokenFormatter() (*TokenFormatter, error) {
+	tokens, err := models.GetAllPaymentTokens(database.GetDB())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment tokens: %w", err)
+	}
+	return NewTokenFormatter(tokens), nil
+}
+
+// FormatTokenAmount formats a raw amount with the decimals and symbol of the given token
+// Unknown tokens fall back to 18 decimals and are flagged in the result
+func (f *TokenFormatter) FormatTokenAmount(amount, tokenAddress string) models.FormattedAmount {
+	if amount == "" {
+		amount = "0"
+	}
+
+	result := models.FormattedAmount{
+		Amount:       amount,
+		TokenAddress: tokenAddress,
+		Decimals:     models.DefaultTokenDecimals,
+		UnknownToken: true,
+	}
+
+	if token, exists := f.tokens[strings.ToLower(tokenAddress)]; exists {
+		result.Symbol = token.Symbol
+		result.Decimals = token.Decimals
+		result.UnknownToken = false
+	}
+
+	formatted, err := f.mathUtil.FormatUnits(amount, result.Decimals)
+	if err != nil {
+		// Keep the raw value when the amount isn't a valid integer
+		formatted = amount
+	}
+	result.Formatted = formatted
+
+	return result
+}
+
+// Decimals returns the decimals of a registered token, or the 18 decimals default for unknown tokens
+func (f *TokenFormatter) Decimals(tokenAddress string) uint8 {
+	if token, exists := f.tokens[strings.ToLower(tokenAddress)]; exists {
+		return token.Decimals
+	}
+	return models.DefaultTokenDecimals
+}
+
+// ERC20MetadataClient reads token metadata from an ERC-20 contract over JSON-RPC
+type ERC20MetadataClient struct {
+	rpcEndpoint string
+	httpClient  *http.Client
+}
+
+// NewERC20MetadataClient creates a new client for the given RPC endpoint
+func NewERC20MetadataClient(rpcEndpoint string) *ERC20MetadataClient {
+	return &ERC20MetadataClient{
+		rpcEndpoint: rpcEndpoint,
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ERC-20 function selectors
+const (
+	erc20NameSelector     = "0x06fdde03"
+	erc20SymbolSelector   = "0x95d89b41"
+	erc20DecimalsSelector = "0x313ce567"
+)
+
+// FetchMetadata reads name, symbol and decimals from the token contract
+func (c *ERC20MetadataClient) FetchMetadata(tokenAddress string) (*models.PaymentToken, error) {
+	if c.rpcEndpoint == "" {
+		return nil, fmt.Errorf("RPC endpoint is not configured")
+	}
+
+	symbolData, err := c.call(tokenAddress, erc20SymbolSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbol: %w", err)
+	}
+	symbol, err := decodeABIString(symbolData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode symbol: %w", err)
+	}
+
+	nameData, err := c.call(tokenAddress, erc20NameSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read name: %w", err)
+	}
+	name, err := decodeABIString(nameData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode name: %w", err)
+	}
+
+	decimalsData, err := c.call(tokenAddress, erc20DecimalsSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decimals: %w", err)
+	}
+	if len(decimalsData) < 32 {
+		return nil, fmt.Errorf("invalid decimals response")
+	}
+	decimals := new(big.Int).SetBytes(decimalsData[:32])
+	if !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return nil, fmt.Errorf("decimals out of range: %s", decimals.String())
+	}
+
+	return &models.PaymentToken{
+		Address:  tokenAddress,
+		Symbol:   symbol,
+		Name:     name,
+		Decimals: uint8(decimals.Uint64()),
+	}, nil
+}
+
+// call performs an eth_call against the token contract and returns the raw result bytes
+func (c *ERC20MetadataClient) call(tokenAddress, data string) ([]byte, error) {
+	return c.callAt(context.Background(), tokenAddress, data, "latest")
+}
+
+// callAt performs an eth_call against a contract at block, "latest" or a hex block number
+func (c *ERC20MetadataClient) callAt(ctx context.Context, contractAddress, data, block string) ([]byte, error) {
+	var result string
+	params := []interface{}{map[string]string{"to": contractAddress, "data": data}, block}
+	if err := c.request(ctx, "eth_call", params, &result); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimPrefix(result, "0x"))
+}
+
+// blockNumber returns the latest block number of the chain
+func (c *ERC20MetadataClient) blockNumber(ctx context.Context) (int64, error) {
+	var result string
+	if err := c.request(ctx, "eth_blockNumber", []interface{}{}, &result); err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimPrefix(result, "0x"), 16, 64)
+}
+
+// request performs a JSON-RPC request and decodes its result into result
+func (c *ERC20MetadataClient) request(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.rpcEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Gateways answer errors with HTML or plain text, which would only show up as a parse error
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("RPC endpoint returned %s", resp.Status)
+	}
+
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("invalid RPC response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("RPC error: %s", rpcResp.Error.Message)
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("invalid RPC result: %w", err)
+	}
+	return nil
+}
+
+// decodeABIString decodes an ABI-encoded string return value
+// Some older tokens return bytes32 instead of string, which is also handled
+func decodeABIString(data []byte) (string, error) {
+	if len(data) == 32 {
+		return strings.TrimRight(string(data), "\x00"), nil
+	}
+	if len(data) < 64 {
+		return "", fmt.Errorf("response too short: %d bytes", len(data))
+	}
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", fmt.Errorf("invalid string offset")
+	}
+	start := offset.Uint64()
+
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsUint64() || start+32+length.Uint64() > uint64(len(data)) {
+		return "", fmt.Errorf("invalid string length")
+	}
+
+	return string(data[start+32 : start+32+length.Uint64()]), nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/models"
+)
+
+func TestFormatTokenAmount(t *testing.T) {
+	formatter := NewTokenFormatter([]models.PaymentToken{
+		{Address: "0x036cbd53842c5426634e7929541ec2318f3dcf7e", Symbol: "USDC", Decimals: 6},
+		{Address: "0x29f2d40b0605204364af54ec677bd022da425d03", Symbol: "WBTC", Decimals: 8},
+		{Address: "0x4200000000000000000000000000000000000006", Symbol: "WETH", Decimals: 18},
+	})
+
+	tests := []struct {
+		name      string
+		amount    string
+		token     string
+		formatted string
+		symbol    string
+		decimals  uint8
+	}{
+		{"6 decimals", "1500000", "0x036CbD53842c5426634e7929541eC2318f3dCF7e", "1.5", "USDC", 6},
+		{"6 decimals below one unit", "1", "0x036cbd53842c5426634e7929541ec2318f3dcf7e", "0.000001", "USDC", 6},
+		{"8 decimals", "250000000", "0x29f2d40b0605204364af54ec677bd022da425d03", "2.5", "WBTC", 8},
+		{"18 decimals", "1000000000000000000000", "0x4200000000000000000000000000000000000006", "1000", "WETH", 18},
+		{"zero amount", "0", "0x036cbd53842c5426634e7929541ec2318f3dcf7e", "0", "USDC", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.FormatTokenAmount(tt.amount, tt.token)
+
+			if result.Formatted != tt.formatted {
+				t.Errorf("Expected formatted '%s', got '%s'", tt.formatted, result.Formatted)
+			}
+			if result.Symbol != tt.symbol {
+				t.Errorf("Expected symbol '%s', got '%s'", tt.symbol, result.Symbol)
+			}
+			if result.Decimals != tt.decimals {
+				t.Errorf("Expected decimals %d, got %d", tt.decimals, result.Decimals)
+			}
+			if result.UnknownToken {
+				t.Error("Expected registered token not to be flagged as unknown")
+			}
+			if result.Amount != tt.amount {
+				t.Errorf("Expected raw amount '%s', got '%s'", tt.amount, result.Amount)
+			}
+		})
+	}
+}
+
+func TestFormatTokenAmountUnknownToken(t *testing.T) {
+	formatter := NewTokenFormatter(nil)
+
+	result := formatter.FormatTokenAmount("1500000000000000000", "0x0000000000000000000000000000000000000001")
+
+	if !result.UnknownToken {
+		t.Error("Expected unregistered token to be flagged as unknown")
+	}
+	if result.Decimals != models.DefaultTokenDecimals {
+		t.Errorf("Expected fallback decimals %d, got %d", models.DefaultTokenDecimals, result.Decimals)
+	}
+	if result.Formatted != "1.5" {
+		t.Errorf("Expected formatted '1.5', got '%s'", result.Formatted)
+	}
+	if result.Symbol != "" {
+		t.Errorf("Expected empty symbol, got '%s'", result.Symbol)
+	}
+}
+
+func TestDecodeABIString(t *testing.T) {
+	// ABI-encoded "USDC": offset 0x20, length 4, data
+	encoded := make([]byte, 96)
+	encoded[31] = 0x20
+	encoded[63] = 4
+	copy(encoded[64:], "USDC")
+
+	symbol, err := decodeABIString(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode string: %v", err)
+	}
+	if symbol != "USDC" {
+		t.Errorf("Expected 'USDC', got '%s'", symbol)
+	}
+
+	// Legacy bytes32 encoding
+	legacy := make([]byte, 32)
+	copy(legacy, "MKR")
+
+	symbol, err = decodeABIString(legacy)
+	if err != nil {
+		t.Fatalf("Failed to decode bytes32: %v", err)
+	}
+	if symbol != "MKR" {
+		t.Errorf("Expected 'MKR', got '%s'", symbol)
+	}
+}
+
+func TestERC20MetadataClientChecksHTTPStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>502 Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	_, err := NewERC20MetadataClient(server.URL).FetchMetadata("0x1234567890123456789012345678901234567890")
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected the 502 to be reported, got %v", err)
+	}
+}
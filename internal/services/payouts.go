@@ -0,0 +1,287 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// payoutEntity is the audit log entity type of payout instructions
+const payoutEntity = "payout_instruction"
+
+// payoutProofDir is the upload directory of payout proofs, which are kept off the public
+// /uploads route
+const payoutProofDir = "payouts/"
+
+var (
+	// ErrPayoutNotFound is returned for a payout instruction that doesn't exist
+	ErrPayoutNotFound = errors.New("payout instruction not found")
+	// ErrPayoutExists is returned when instructing a redemption that already has an instruction
+	ErrPayoutExists = errors.New("redemption already has a payout instruction")
+	// ErrPayoutConfirmed is returned when changing a confirmed payout, which is final
+	ErrPayoutConfirmed = errors.New("payout instruction is confirmed")
+	// ErrPayoutEvidenceFixed is returned when replacing the tx hash or proof of an executed
+	// payout, which are its record
+	ErrPayoutEvidenceFixed = errors.New("evidence of an executed payout can't be changed")
+	// ErrRedemptionNotFound is returned when instructing a payout for an unknown redemption request
+	ErrRedemptionNotFound = errors.New("redemption request not found")
+	// ErrRedemptionNotApproved is returned when instructing a payout for a redemption not approved onchain
+	ErrRedemptionNotApproved = errors.New("redemption is not approved")
+	// ErrInvalidPayout is returned for instructions with an invalid amount, address, tx hash or proof file
+	ErrInvalidPayout = errors.New("invalid payout instruction")
+)
+
+// PayoutProofUpload is a proof of payout file, e.g. a bank transfer receipt
+type PayoutProofUpload struct {
+	Filename string // Original file name, for its extension
+	Size     int64
+	Content  io.Reader
+}
+
+// PayoutService records the issuer's payouts of approved redemptions. Every change is
+// audit-logged in the transaction that makes it
+type PayoutService struct {
+	db          *gorm.DB
+	redemptions RedemptionReader
+	storage     DocumentStorage
+	now         func() time.Time
+}
+
+// NewPayoutService creates a payout service looking up redemptions with redemptions and
+// saving proofs to storage
+func NewPayoutService(db *gorm.DB, redemptions RedemptionReader, storage DocumentStorage) *PayoutService {
+	return &PayoutService{db: db, redemptions: redemptions, storage: storage, now: time.Now}
+}
+
+// NewDefaultPayoutService saves proofs to the local upload directory
+func NewDefaultPayoutService(uploadDir string) *PayoutService {
+	return NewPayoutService(database.GetDB(), NewRedemptionService(), NewLocalUploadStorage(uploadDir))
+}
+
+// Create instructs the payout of an approved redemption. The amount defaults to the
+// approved amount, and the payment token to the redemption's unless a bank reference is given
+func (s *PayoutService) Create(ctx context.Context, req models.PayoutInstructionRequest, actor string) (*models.PayoutInstruction, error) {
+	page, err := s.redemptions.GetAllRedemptions(ctx, RedemptionListFilter{RequestID: req.RedemptionRequestID, Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load redemption: %w", err)
+	}
+	if len(page.Redemptions) == 0 {
+		return nil, ErrRedemptionNotFound
+	}
+	redemption := page.Redemptions[0]
+	if redemption.Status != models.RedemptionStatusApproved || redemption.ApprovedAmount == nil {
+		return nil, fmt.Errorf("%w: status is %s", ErrRedemptionNotApproved, redemption.Status)
+	}
+
+	amount := strings.TrimSpace(req.Amount)
+	if amount == "" {
+		amount = *redemption.ApprovedAmount
+	}
+	parsed, err := models.ParseBigNumeric(amount)
+	if err != nil || !utils.NewTokenMath().IsPositive(parsed.String()) {
+		return nil, fmt.Errorf("%w: amount must be a positive integer", ErrInvalidPayout)
+	}
+
+	instruction := &models.PayoutInstruction{
+		RedemptionRequestID: redemption.RequestID,
+		UserAddress:         redemption.User,
+		SukukAddress:        redemption.SukukAddress,
+		Amount:              parsed,
+		PaymentToken:        strings.TrimSpace(req.PaymentToken),
+		BankReference:       strings.TrimSpace(req.BankReference),
+		Status:              models.PayoutPending,
+		Notes:               req.Notes,
+		CreatedBy:           actor,
+	}
+	if instruction.PaymentToken == "" && instruction.BankReference == "" {
+		instruction.PaymentToken = redemption.PaymentToken
+	}
+	if instruction.PaymentToken != "" && !utils.IsValidEthereumAddress(instruction.PaymentToken) {
+		return nil, fmt.Errorf("%w: payment_token must be a 0x-prefixed 40 hex character address", ErrInvalidPayout)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(instruction)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPayoutExists
+		}
+		return models.RecordAudit(tx, models.AuditActionCreate, payoutEntity, payoutEntityID(instruction), actor, instruction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instruction, nil
+}
+
+// Update records the evidence of a payout and moves it along pending → executed →
+// confirmed. Executing needs a tx hash, given here or before, or an uploaded proof, which
+// can't be changed afterwards. A confirmed instruction can't be changed at all
+func (s *PayoutService) Update(ctx context.Context, id uint, req models.PayoutInstructionUpdateRequest, actor string) (*models.PayoutInstruction, error) {
+	if req.TxHash != nil && *req.TxHash != "" && !utils.IsValidTransactionHash(*req.TxHash) {
+		return nil, fmt.Errorf("%w: tx_hash must be a 0x-prefixed 64 hex character hash", ErrInvalidPayout)
+	}
+
+	var instruction models.PayoutInstruction
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lock(tx, id, &instruction); err != nil {
+			return err
+		}
+		from := instruction.Status
+
+		if req.TxHash != nil && !strings.EqualFold(*req.TxHash, instruction.TxHash) {
+			if from != models.PayoutPending && instruction.TxHash != "" {
+				return ErrPayoutEvidenceFixed
+			}
+			instruction.TxHash = *req.TxHash
+		}
+		if req.BankReference != nil {
+			instruction.BankReference = strings.TrimSpace(*req.BankReference)
+		}
+		if req.Notes != nil {
+			instruction.Notes = *req.Notes
+		}
+		if req.Status != "" && req.Status != from {
+			if err := instruction.Transition(req.Status, actor, s.now().UTC()); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(&instruction).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionUpdate, payoutEntity, payoutEntityID(&instruction), actor, map[string]interface{}{
+			"from":    from,
+			"to":      instruction.Status,
+			"changes": req,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instruction, nil
+}
+
+// AttachProof stores a proof of payout, e.g. a transfer receipt. A pending instruction's
+// proof may be replaced; the file is removed again when the instruction cannot be updated
+func (s *PayoutService) AttachProof(ctx context.Context, id uint, upload PayoutProofUpload, actor string) (*models.PayoutInstruction, error) {
+	// The leading bytes identify the file format
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	head = head[:n]
+	if err := models.ValidatePayoutProofFile(upload.Filename, upload.Size, head); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayout, err)
+	}
+
+	name := fmt.Sprintf("%s%d/proof_%d%s", payoutProofDir, id, s.now().UnixNano(), strings.ToLower(filepath.Ext(upload.Filename)))
+	var instruction models.PayoutInstruction
+	var url string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.lock(tx, id, &instruction); err != nil {
+			return err
+		}
+		if instruction.Status != models.PayoutPending && instruction.ProofURL != "" {
+			return ErrPayoutEvidenceFixed
+		}
+		var err error
+		url, err = s.storage.Save(ctx, name, io.MultiReader(bytes.NewReader(head), upload.Content))
+		if err != nil {
+			return err
+		}
+		previous := instruction.ProofURL
+		instruction.ProofURL = url
+		if err := tx.Save(&instruction).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditActionUpdate, payoutEntity, payoutEntityID(&instruction), actor, map[string]interface{}{
+			"proof_url":          url,
+			"previous_proof_url": previous,
+		})
+	})
+	if err != nil {
+		if url != "" {
+			if deleteErr := s.storage.Delete(ctx, name); deleteErr != nil {
+				logger.WithError(deleteErr).WithField("file", name).Warn("Failed to remove proof of a failed upload")
+			}
+		}
+		return nil, err
+	}
+	return &instruction, nil
+}
+
+// lock loads instruction id for update, refusing confirmed instructions
+func (s *PayoutService) lock(tx *gorm.DB, id uint, instruction *models.PayoutInstruction) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(instruction, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPayoutNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if instruction.Status == models.PayoutConfirmed {
+		return ErrPayoutConfirmed
+	}
+	return nil
+}
+
+func payoutEntityID(instruction *models.PayoutInstruction) string {
+	return strconv.FormatUint(uint64(instruction.ID), 10)
+}
+
+// OpenPayoutProof opens the stored proof of a payout instruction. Returns ErrFileNotFound
+// when it has none or the file is gone
+func OpenPayoutProof(ctx context.Context, storage FileOpener, instruction *models.PayoutInstruction) (io.ReadSeekCloser, UploadFile, error) {
+	name := uploadNameFromURL(instruction.ProofURL)
+	if name == "" {
+		return nil, UploadFile{}, ErrFileNotFound
+	}
+	return storage.Open(ctx, name)
+}
+
+// IsPayoutProofUpload reports whether a stored upload name is a payout proof, which is only
+// served to admins
+func IsPayoutProofUpload(name string) bool {
+	clean := strings.ToLower(strings.TrimPrefix(path.Clean("/"+name), "/"))
+	return strings.HasPrefix(clean, payoutProofDir)
+}
+
+// PayoutProofUploadReferences collects the uploads referenced by payout instructions
+func PayoutProofUploadReferences(db *gorm.DB) UploadReferences {
+	return func(ctx context.Context) (map[string]bool, error) {
+		var urls []string
+		err := db.WithContext(ctx).Model(&models.PayoutInstruction{}).
+			Where("proof_url <> ''").
+			Pluck("proof_url", &urls).Error
+		if err != nil {
+			return nil, err
+		}
+
+		references := make(map[string]bool, len(urls))
+		for _, url := range urls {
+			if name := uploadNameFromURL(url); name != "" {
+				references[name] = true
+			}
+		}
+		return references, nil
+	}
+}
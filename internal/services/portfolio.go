@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// portfolioHistoryLimit is how many recent distributions each holding carries
+const portfolioHistoryLimit = 5
+
+// sukukTotal is a per-sukuk aggregate read from an indexer table
+type sukukTotal struct {
+	SukukAddress string `gorm:"column:sukuk_address"`
+	Total        string `gorm:"column:total"`
+}
+
+// sukukDistributionID is a distribution of a sukuk the user has not claimed yet
+type sukukDistributionID struct {
+	SukukAddress   string `gorm:"column:sukuk_address"`
+	DistributionId int64  `gorm:"column:distribution_id"`
+}
+
+// GetUserPortfolio calculates user's portfolio with holdings and claimable yields
+// Every indexer table is read once for all holdings, so the number of queries does not grow
+// with the number of sukuk held. A holding that can't be computed is left out and listed in
+// Errors; a *PortfolioError is returned when none of them could be
+func (s *IndexerQueryService) GetUserPortfolio(ctx context.Context, userAddress string) (*UserPortfolio, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	portfolio := &UserPortfolio{
+		Address:  userAddress,
+		Holdings: []SukukHolding{},
+	}
+
+	// Latest balance per sukuk from one holder_update query
+	latest, err := s.GetHoldings(ctx, userAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owned sukuk: %w", err)
+	}
+	mathUtil := utils.GlobalTokenMath
+	var sukukAddresses []string
+	for _, holding := range latest {
+		if mathUtil.IsPositive(holding.Balance) {
+			sukukAddresses = append(sukukAddresses, strings.ToLower(holding.SukukAddress))
+		}
+	}
+	if len(sukukAddresses) == 0 {
+		return portfolio, nil
+	}
+
+	distributed, err := s.getTotalsBySukuk(ctx, "yield_distributed", sukukAddresses, "")
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.getTotalsBySukuk(ctx, "yield_claim", sukukAddresses, `LOWER("user") = LOWER(?)`, userAddress)
+	if err != nil {
+		return nil, err
+	}
+	unclaimed, err := s.getUnclaimedDistributionIdsBySukuk(ctx, userAddress, sukukAddresses)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.getRecentDistributionsBySukuk(ctx, sukukAddresses, portfolioHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	supplies, err := s.supplies().GetTotalSuppliesAt(ctx, sukukAddresses, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, holding := range latest {
+		key := strings.ToLower(holding.SukukAddress)
+		if !mathUtil.IsPositive(holding.Balance) {
+			continue
+		}
+
+		totalClaimed := claimed[key]
+		if totalClaimed == "" {
+			totalClaimed = "0"
+		}
+		claimable, err := claimableYield(distributed[key], totalClaimed, holding.Balance, supplies[key].Amount)
+		if err != nil {
+			// A malformed amount leaves the holding out rather than failing the whole portfolio
+			logger.WithError(err).WithFields(map[string]interface{}{
+				"address":       userAddress,
+				"sukuk_address": holding.SukukAddress,
+			}).Warn("Failed to compute portfolio holding")
+			portfolio.Errors = append(portfolio.Errors, HoldingError{
+				SukukAddress: holding.SukukAddress,
+				Reason:       err.Error(),
+			})
+			continue
+		}
+
+		unclaimedIds := unclaimed[key]
+		if unclaimedIds == nil {
+			unclaimedIds = []int64{}
+		}
+		portfolio.Holdings = append(portfolio.Holdings, SukukHolding{
+			SukukAddress:           holding.SukukAddress,
+			Balance:                holding.Balance,
+			ClaimableYield:         claimable,
+			TotalYieldClaimed:      totalClaimed,
+			UnclaimedDistributions: unclaimedIds,
+			RecentDistributions:    history[key],
+			SupplySource:           supplies[key].Source,
+		})
+	}
+
+	if len(portfolio.Holdings) == 0 && len(portfolio.Errors) > 0 {
+		return nil, &PortfolioError{Errors: portfolio.Errors}
+	}
+	return portfolio, nil
+}
+
+// PortfolioSummary is the size and value of an address's portfolio
+type PortfolioSummary struct {
+	HoldingsCount       int            // Sukuk held with a positive balance
+	TotalBalance        string         // Raw sukuk token balance summed across holdings
+	TotalClaimableYield string         // Raw claimable yield summed across holdings
+	Errors              []HoldingError // Holdings left out of the totals because they could not be computed
+}
+
+// holderSukukTotal is a per-holder, per-sukuk aggregate read from an indexer table
+type holderSukukTotal struct {
+	Holder       string `gorm:"column:holder"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	Total        string `gorm:"column:total"`
+}
+
+// GetPortfolioSummaries summarizes the portfolio of each address, keyed by lowercase address.
+// Every address is in the result, with zero holdings when it holds nothing. Like
+// GetUserPortfolio, each indexer table is read once for all addresses and holdings, and a
+// holding that can't be computed is left out and listed in the summary's Errors
+func (s *IndexerQueryService) GetPortfolioSummaries(ctx context.Context, addresses []string) (map[string]PortfolioSummary, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	holders := make([]string, len(addresses))
+	summaries := make(map[string]PortfolioSummary, len(addresses))
+	for i, address := range addresses {
+		holders[i] = strings.ToLower(address)
+		summaries[holders[i]] = PortfolioSummary{TotalBalance: "0", TotalClaimableYield: "0"}
+	}
+	if len(holders) == 0 {
+		return summaries, nil
+	}
+
+	latest, err := s.getHoldingsByHolder(ctx, holders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get owned sukuk: %w", err)
+	}
+	mathUtil := utils.GlobalTokenMath
+	var held []IndexerHolderUpdated
+	seen := make(map[string]bool)
+	var sukukAddresses []string
+	for _, holding := range latest {
+		if !mathUtil.IsPositive(holding.Balance) {
+			continue
+		}
+		held = append(held, holding)
+		if key := strings.ToLower(holding.SukukAddress); !seen[key] {
+			seen[key] = true
+			sukukAddresses = append(sukukAddresses, key)
+		}
+	}
+	if len(held) == 0 {
+		return summaries, nil
+	}
+
+	distributed, err := s.getTotalsBySukuk(ctx, "yield_distributed", sukukAddresses, "")
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.getClaimedByHolder(ctx, holders, sukukAddresses)
+	if err != nil {
+		return nil, err
+	}
+	supplies, err := s.supplies().GetTotalSuppliesAt(ctx, sukukAddresses, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string][]string)
+	claimables := make(map[string][]string)
+	for _, holding := range held {
+		holder, key := strings.ToLower(holding.Holder), strings.ToLower(holding.SukukAddress)
+		totalClaimed := claimed[holder+"|"+key]
+		if totalClaimed == "" {
+			totalClaimed = "0"
+		}
+		claimable, err := claimableYield(distributed[key], totalClaimed, holding.Balance, supplies[key].Amount)
+		summary := summaries[holder]
+		if err != nil {
+			// A malformed amount leaves the holding out rather than failing the whole batch
+			logger.WithError(err).WithFields(map[string]interface{}{
+				"address":       holder,
+				"sukuk_address": holding.SukukAddress,
+			}).Warn("Failed to compute portfolio holding")
+			summary.Errors = append(summary.Errors, HoldingError{
+				SukukAddress: holding.SukukAddress,
+				Reason:       err.Error(),
+			})
+			summaries[holder] = summary
+			continue
+		}
+
+		summary.HoldingsCount++
+		summaries[holder] = summary
+		balances[holder] = append(balances[holder], holding.Balance)
+		claimables[holder] = append(claimables[holder], claimable)
+	}
+	for holder, summary := range summaries {
+		summary.TotalBalance, _ = mathUtil.SumValidTokenAmounts(balances[holder])
+		summary.TotalClaimableYield, _ = mathUtil.SumValidTokenAmounts(claimables[holder])
+		summaries[holder] = summary
+	}
+
+	return summaries, nil
+}
+
+// getHoldingsByHolder returns the latest holder_update row of every holder and sukuk pair
+// among the given lowercase holders, in one scan
+func (s *IndexerQueryService) getHoldingsByHolder(ctx context.Context, holders []string) ([]IndexerHolderUpdated, error) {
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find holder_update table: %w", err)
+	}
+
+	var holdings []IndexerHolderUpdated
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT ON (LOWER(holder), LOWER(sukuk_address)) id, sukuk_address, holder, new_balance::text AS new_balance, timestamp, block_number, tx_hash
+			FROM %s
+			WHERE LOWER(holder) IN ?
+			ORDER BY LOWER(holder), LOWER(sukuk_address), block_number DESC, timestamp DESC, id DESC`, quoteIdentifier(holderTable))
+		return db.Raw(query, holders).Scan(&holdings).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holdings: %w", err)
+	}
+	return holdings, nil
+}
+
+// getClaimedByHolder sums the yield each of the given lowercase holders claimed from each sukuk,
+// keyed by "holder|sukuk" in lowercase
+func (s *IndexerQueryService) getClaimedByHolder(ctx context.Context, holders, sukukAddresses []string) (map[string]string, error) {
+	table, err := s.tableService.GetLatestTableForEvent("yield_claim")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_claim table: %w", err)
+	}
+
+	var totals []holderSukukTotal
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(table).
+			Select(`LOWER("user") AS holder, LOWER(sukuk_address) AS sukuk_address, SUM(amount)::text AS total`).
+			Where(`LOWER("user") IN ? AND LOWER(sukuk_address) IN ?`, holders, sukukAddresses).
+			Group(`LOWER("user"), LOWER(sukuk_address)`).
+			Scan(&totals).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum amounts from %s: %w", table, err)
+	}
+
+	result := make(map[string]string, len(totals))
+	for _, total := range totals {
+		result[total.Holder+"|"+total.SukukAddress] = total.Total
+	}
+	return result, nil
+}
+
+// claimableYield is the pro-rata share of everything distributed less what was already claimed
+// A sukuk without a known supply has nothing claimable
+func claimableYield(totalDistributed, totalClaimed, balance, totalSupply string) (string, error) {
+	mathUtil := utils.GlobalTokenMath
+	if totalDistributed == "" || totalSupply == "" || mathUtil.IsZero(balance) {
+		return "0", nil
+	}
+
+	// Entitled yield = totalDistributed * balance / totalSupply, rounded down
+	entitledYield, err := mathUtil.ProRataShare(totalDistributed, balance, totalSupply)
+	if err != nil {
+		return "0", fmt.Errorf("failed to calculate entitled yield: %w", err)
+	}
+	claimable, err := mathUtil.SubtractTokenAmounts(entitledYield, totalClaimed)
+	if err != nil {
+		return "0", fmt.Errorf("failed to calculate claimable yield: %w", err)
+	}
+	return claimable, nil
+}
+
+// getTotalsBySukuk sums the amount column of an event table per sukuk, keyed by lowercase
+// address, with an optional extra condition
+func (s *IndexerQueryService) getTotalsBySukuk(ctx context.Context, eventType string, sukukAddresses []string, condition string, args ...interface{}) (map[string]string, error) {
+	table, err := s.tableService.GetLatestTableForEvent(eventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s table: %w", eventType, err)
+	}
+
+	var totals []sukukTotal
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := db.Table(table).
+			Select("LOWER(sukuk_address) AS sukuk_address, SUM(amount)::text AS total").
+			Where("LOWER(sukuk_address) IN ?", sukukAddresses)
+		if condition != "" {
+			query = query.Where(condition, args...)
+		}
+		return query.Group("LOWER(sukuk_address)").Scan(&totals).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum amounts from %s: %w", table, err)
+	}
+
+	result := make(map[string]string, len(totals))
+	for _, total := range totals {
+		result[total.SukukAddress] = total.Total
+	}
+	return result, nil
+}
+
+// getUnclaimedDistributionIdsBySukuk returns the distributions of each sukuk the user has not
+// claimed, keyed by lowercase address and in distribution order
+func (s *IndexerQueryService) getUnclaimedDistributionIdsBySukuk(ctx context.Context, userAddress string, sukukAddresses []string) (map[string][]int64, error) {
+	distributedTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
+	}
+	claimedTable, err := s.tableService.GetLatestTableForEvent("yield_claim")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_claim table: %w", err)
+	}
+
+	var rows []sukukDistributionID
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT LOWER(d.sukuk_address) AS sukuk_address, d.distribution_id
+			FROM %s d
+			WHERE LOWER(d.sukuk_address) IN ?
+			AND NOT EXISTS (
+				SELECT 1 FROM %s c
+				WHERE LOWER(c.sukuk_address) = LOWER(d.sukuk_address)
+				AND c.distribution_id = d.distribution_id
+				AND LOWER(c."user") = LOWER(?)
+			)
+			ORDER BY d.distribution_id ASC`, quoteIdentifier(distributedTable), quoteIdentifier(claimedTable))
+		return db.Raw(query, sukukAddresses, userAddress).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unclaimed distributions: %w", err)
+	}
+
+	unclaimed := make(map[string][]int64)
+	for _, row := range rows {
+		unclaimed[row.SukukAddress] = append(unclaimed[row.SukukAddress], row.DistributionId)
+	}
+	return unclaimed, nil
+}
+
+// getRecentDistributionsBySukuk returns up to limit of the newest distributions of each sukuk,
+// keyed by lowercase address and newest first
+func (s *IndexerQueryService) getRecentDistributionsBySukuk(ctx context.Context, sukukAddresses []string, limit int) (map[string][]IndexerYieldDistributed, error) {
+	yieldTable, err := s.tableService.GetLatestTableForEvent("yield_distributed")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find yield_distributed table: %w", err)
+	}
+
+	var distributions []IndexerYieldDistributed
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT id, sukuk_address, distribution_id, payment_token, amount::text AS amount, timestamp, block_number, tx_hash
+			FROM (
+				SELECT *, ROW_NUMBER() OVER (PARTITION BY LOWER(sukuk_address) ORDER BY timestamp DESC, id DESC) AS position
+				FROM %s
+				WHERE LOWER(sukuk_address) IN ?
+			) ranked
+			WHERE position <= ?
+			ORDER BY timestamp DESC, id DESC`, quoteIdentifier(yieldTable))
+		return db.Raw(query, sukukAddresses, limit).Scan(&distributions).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent distributions: %w", err)
+	}
+
+	recent := make(map[string][]IndexerYieldDistributed)
+	for _, distribution := range distributions {
+		key := strings.ToLower(distribution.SukukAddress)
+		recent[key] = append(recent[key], distribution)
+	}
+	return recent, nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// portfolioCacheCursorKeyPrefix prefixes the system state holding, per event type, the last
+// block whose holders had their cached portfolios dropped
+const portfolioCacheCursorKeyPrefix = "portfolio_cache_cursor:"
+
+// holderEventSources are the indexer events that change a wallet's holdings, with the column
+// naming the wallet. Transfers show up as a holder_update for each side
+var holderEventSources = []struct {
+	event  string
+	column string
+}{
+	{"sukuk_purchase", "buyer"},
+	{"holder_update", "holder"},
+	{"redemption_request", "user"},
+	{"redemption_approval", "user"},
+}
+
+// holderEventRow is a wallet touched by events up to block
+type holderEventRow struct {
+	Address string
+	Block   int64
+}
+
+// invalidateForHolderEvents drops the cached portfolios, and with them the holdings, of the
+// wallets in purchase, transfer and redemption events indexed since the previous cycle. On
+// the first run the cursor starts at the newest block, as the entries expire anyway
+func (s *SukukMetadataSyncService) invalidateForHolderEvents(ctx context.Context) error {
+	db := s.db.WithContext(ctx)
+	for _, source := range holderEventSources {
+		table, err := s.findLatestEventTable(ctx, source.event)
+		if err != nil {
+			return err
+		}
+		if table == "" {
+			continue
+		}
+
+		key := portfolioCacheCursorKeyPrefix + source.event
+		cursor, found, err := readBlockCursor(db, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			var newest int64
+			if err := db.Raw(fmt.Sprintf("SELECT COALESCE(MAX(block_number), 0) FROM %s", quoteIdentifier(table))).Scan(&newest).Error; err != nil {
+				return fmt.Errorf("failed to read the newest %s block: %w", source.event, err)
+			}
+			if err := models.SetSystemState(db, key, strconv.FormatInt(newest, 10)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var rows []holderEventRow
+		query := fmt.Sprintf(`SELECT LOWER(%s) AS address, MAX(block_number) AS block FROM %s WHERE block_number > ? GROUP BY 1`,
+			quoteIdentifier(source.column), quoteIdentifier(table))
+		if err := db.Raw(query, cursor).Scan(&rows).Error; err != nil {
+			return fmt.Errorf("failed to fetch %s holders: %w", source.event, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		addresses := make([]string, len(rows))
+		for i, row := range rows {
+			addresses[i] = row.Address
+			if row.Block > cursor {
+				cursor = row.Block
+			}
+		}
+		cache.InvalidateAddresses(ctx, addresses...)
+		if err := models.SetSystemState(db, key, strconv.FormatInt(cursor, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBlockCursor returns the block number stored under key, and whether there was one
+func readBlockCursor(db *gorm.DB, key string) (int64, bool, error) {
+	state, err := models.GetSystemState(db, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	block, err := strconv.ParseInt(state.Value, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return block, true, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+// TestInvalidateForHolderEvents requires a reachable Postgres, see testutil.Open
+func TestInvalidateForHolderEvents(t *testing.T) {
+	db := testutil.DB(t)
+	cache.SetDefault(cache.NewMemoryCache())
+	ctx := context.Background()
+	store := cache.Default()
+
+	const table = "cafe02__holder_update"
+	db.Exec("DROP TABLE IF EXISTS " + table)
+	if err := db.Exec("CREATE TABLE " + table + ` (
+		id TEXT PRIMARY KEY, sukuk_address TEXT, holder TEXT, new_balance NUMERIC(78,0),
+		block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error; err != nil {
+		t.Fatalf("Failed to create %s: %v", table, err)
+	}
+	defer db.Exec("DROP TABLE IF EXISTS " + table)
+	defer db.Where("key LIKE ?", portfolioCacheCursorKeyPrefix+"%").Delete(&models.SystemState{})
+
+	const sender, receiver, bystander = "0x00000000000000000000000000000000000ca001", "0x00000000000000000000000000000000000ca002", "0x00000000000000000000000000000000000ca003"
+	insert := func(id, holder string, block int64) {
+		db.Exec("INSERT INTO "+table+" VALUES (?, ?, ?, ?, ?, ?, ?)", id, "0xsukuk", holder, 1, block, "0x"+id, 1700000000)
+	}
+	insert("a-1", bystander, 10)
+
+	service := &SukukMetadataSyncService{db: db}
+	// The first run only places the cursor
+	if err := service.invalidateForHolderEvents(ctx); err != nil {
+		t.Fatalf("Invalidation failed: %v", err)
+	}
+
+	for _, address := range []string{sender, receiver, bystander} {
+		store.Set(ctx, cache.PortfolioKey(address), []byte("{}"), time.Minute)
+	}
+	// A transfer updates both holders
+	insert("b-1", sender, 11)
+	insert("b-2", receiver, 11)
+	if err := service.invalidateForHolderEvents(ctx); err != nil {
+		t.Fatalf("Invalidation failed: %v", err)
+	}
+
+	for _, address := range []string{sender, receiver} {
+		if _, err := store.Get(ctx, cache.PortfolioKey(address)); !errors.Is(err, cache.ErrMiss) {
+			t.Errorf("Expected the portfolio of %s to be invalidated", address)
+		}
+	}
+	if _, err := store.Get(ctx, cache.PortfolioKey(bystander)); err != nil {
+		t.Errorf("Expected the portfolio of a wallet without new events to stay cached, got %v", err)
+	}
+}
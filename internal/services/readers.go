@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"sukuk-be/internal/models"
+)
+
+// PortfolioReader reads the holdings, balances and yield of an address from the indexer
+type PortfolioReader interface {
+	GetUserPortfolio(ctx context.Context, userAddress string) (*UserPortfolio, error)
+	GetPortfolioSummaries(ctx context.Context, addresses []string) (map[string]PortfolioSummary, error)
+	GetSukukOwnedByAddress(ctx context.Context, userAddress string) ([]string, error)
+	GetCurrentBalance(ctx context.Context, userAddress, sukukAddress string) (string, error)
+	GetClaimableYield(ctx context.Context, userAddress, sukukAddress string) (string, error)
+	GetUserShare(ctx context.Context, userAddress, sukukAddress string) (string, Supply, error)
+	GetUnclaimedDistributionIds(ctx context.Context, userAddress string, sukukAddress string) ([]int64, error)
+	GetYieldDistributions(ctx context.Context, sukukAddress string, limit int) ([]IndexerYieldDistributed, error)
+	GetBalanceHistory(ctx context.Context, holder, sukukAddress string, filter BalanceHistoryFilter) ([]models.BalanceChange, int64, error)
+}
+
+// ActivityReader reads the transaction history of an address from the indexer
+type ActivityReader interface {
+	GetUserTransactionHistory(ctx context.Context, userAddress string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error)
+}
+
+// RedemptionReader reads redemption requests merged with their approvals
+type RedemptionReader interface {
+	GetAllRedemptions(ctx context.Context, filter RedemptionListFilter) (*models.RedemptionListResponse, error)
+	GetRedemptionsByUser(ctx context.Context, userAddress string) (*models.RedemptionListResponse, error)
+	GetRedemptionsBySukuk(ctx context.Context, sukukAddress string) (*models.RedemptionListResponse, error)
+	GetRedemptionStats(ctx context.Context) (*models.RedemptionStatsResponse, error)
+}
+
+var (
+	_ PortfolioReader  = (*IndexerQueryService)(nil)
+	_ ActivityReader   = (*IndexerQueryService)(nil)
+	_ RedemptionReader = (*RedemptionService)(nil)
+)
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+	"sukuk-be/internal/validation"
+
+	"gorm.io/gorm"
+)
+
+// ReconciliationService compares the event tables stored by the backend with the indexer
+// Events are matched on transaction hash and log index; indexer rows carry the log index
+// as the suffix of their "<tx_hash>-<log_index>" id
+type ReconciliationService struct {
+	db              *gorm.DB
+	tableService    *IndexerTableService
+	purchaseTable   string // Indexer table overrides; discovered when empty
+	redemptionTable string
+}
+
+// NewReconciliationService creates a reconciliation service on the given database,
+// which also holds the indexer tables
+func NewReconciliationService(db *gorm.DB) *ReconciliationService {
+	return &ReconciliationService{
+		db:           db,
+		tableService: NewIndexerTableService(),
+	}
+}
+
+// SetIndexerTables overrides the indexer tables compared against instead of discovering them
+func (s *ReconciliationService) SetIndexerTables(purchaseTable, redemptionTable string) {
+	s.purchaseTable = purchaseTable
+	s.redemptionTable = redemptionTable
+}
+
+// reconciliationSource pairs an indexer event table with the local table storing the same events
+type reconciliationSource struct {
+	name         string
+	indexerTable string
+	localTable   string
+}
+
+// Reconcile builds the reconciliation report for a sukuk
+func (s *ReconciliationService) Reconcile(ctx context.Context, sukukAddress string) (*models.ReconciliationReport, error) {
+	sources, err := s.sources()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconciliationReport{
+		SukukAddress: utils.NormalizeAddress(sukukAddress),
+		GeneratedAt:  time.Now().UTC(),
+		InSync:       true,
+		DetailLimit:  models.ReconciliationDetailLimit,
+		Categories:   make([]models.ReconciliationCategory, 0, len(sources)),
+	}
+
+	for _, source := range sources {
+		category, err := s.reconcileCategory(ctx, source, report.SukukAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile %s: %w", source.name, err)
+		}
+		report.InSync = report.InSync && category.InSync()
+		report.Categories = append(report.Categories, *category)
+	}
+	return report, nil
+}
+
+// BackfillMissingPurchases stores the purchase events of a sukuk that the indexer has and
+// the local table lacks. Run it inside a transaction; it returns the number of rows inserted
+func (s *ReconciliationService) BackfillMissingPurchases(tx *gorm.DB, sukukAddress string) (int, error) {
+	sources, err := s.sources()
+	if err != nil {
+		return 0, err
+	}
+	purchases := sources[0]
+
+	// Purchases before the retention watermark were pruned on purpose, not lost
+	prunedBefore, err := RetentionWatermark(tx, purchases.localTable)
+	if err != nil {
+		return 0, err
+	}
+
+	var missing []struct {
+		IndexerSukukPurchase
+		LogIndex int64 `gorm:"column:log_index"`
+	}
+	query := fmt.Sprintf(`
+		SELECT i.*, COALESCE(substring(i.id from '-([0-9]+)$')::bigint, 0) AS log_index
+		FROM %s i
+		WHERE LOWER(i.sukuk_address) = ?
+		  AND i.timestamp >= ?
+		  AND NOT EXISTS (
+			SELECT 1 FROM %s l
+			WHERE l.deleted_at IS NULL
+			  AND LOWER(l.tx_hash) = LOWER(i.tx_hash)
+			  AND l.log_index = COALESCE(substring(i.id from '-([0-9]+)$')::bigint, 0)
+		  )
+		ORDER BY i.block_number, i.id`, quoteIdentifier(purchases.indexerTable), quoteIdentifier(purchases.localTable))
+	if err := tx.Raw(query, utils.NormalizeAddress(sukukAddress), watermarkUnix(prunedBefore)).Scan(&missing).Error; err != nil {
+		return 0, err
+	}
+
+	inserted := 0
+	now := time.Now()
+	for _, row := range missing {
+		event := models.SukukPurchased{
+			Buyer:        row.Buyer,
+			SukukAddress: row.SukukAddress,
+			PaymentToken: row.PaymentToken,
+			Amount:       models.BigNumeric(row.Amount),
+			BlockNumber:  uint64(row.BlockNumber),
+			TxHash:       row.TxHash,
+			LogIndex:     uint(row.LogIndex),
+			Timestamp:    time.Unix(row.Timestamp, 0).UTC(),
+		}
+		// Indexer rows failing the event checks are anomalies to investigate, not purchases to store
+		if err := validation.SukukPurchased(&event, now); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"sukuk_address": row.SukukAddress,
+				"tx_hash":       row.TxHash,
+				"log_index":     row.LogIndex,
+			}).WithError(err).Warn("Skipping invalid indexer purchase")
+			continue
+		}
+		err := models.CreateSukukPurchaseEvent(tx, &event)
+		if errors.Is(err, models.ErrDuplicateEvent) {
+			continue
+		}
+		if err != nil {
+			return inserted, fmt.Errorf("failed to backfill purchase %s: %w", row.TxHash, err)
+		}
+		inserted++
+	}
+	return inserted, nil
+}
+
+// watermarkUnix is a retention watermark as an indexer timestamp; 0 when the table was never pruned
+func watermarkUnix(prunedBefore time.Time) int64 {
+	if prunedBefore.IsZero() {
+		return 0
+	}
+	return prunedBefore.Unix()
+}
+
+// sources resolves the indexer tables for every reconciled category, purchases first
+func (s *ReconciliationService) sources() ([]reconciliationSource, error) {
+	purchaseTable, err := s.indexerTable(s.purchaseTable, "sukuk_purchase")
+	if err != nil {
+		return nil, err
+	}
+	redemptionTable, err := s.indexerTable(s.redemptionTable, "redemption_request")
+	if err != nil {
+		return nil, err
+	}
+
+	return []reconciliationSource{
+		{models.ReconciliationPurchases, purchaseTable, models.SukukPurchased{}.TableName()},
+		{models.ReconciliationRedemptionRequests, redemptionTable, models.RedemptionRequested{}.TableName()},
+	}, nil
+}
+
+func (s *ReconciliationService) indexerTable(override, eventType string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	table, err := s.tableService.GetLatestTableForEvent(eventType)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s table: %w", eventType, err)
+	}
+	return table, nil
+}
+
+// reconcileCategory counts, sums and diffs one event type for a sukuk
+func (s *ReconciliationService) reconcileCategory(ctx context.Context, source reconciliationSource, sukukAddress string) (*models.ReconciliationCategory, error) {
+	category := &models.ReconciliationCategory{
+		Name:         source.name,
+		IndexerTable: source.indexerTable,
+		LocalTable:   source.localTable,
+	}
+
+	// Events before the retention watermark may have been pruned locally, so neither side counts them;
+	// orphaned local events are missing from the indexer on purpose after a reorg
+	prunedBefore, err := RetentionWatermark(s.db.WithContext(ctx), source.localTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both sides reduced to (tx_hash, log_index, amount) so the queries below stay set operations
+	sides := fmt.Sprintf(`
+		WITH i AS (
+			SELECT LOWER(tx_hash) AS tx_hash,
+			       COALESCE(substring(id from '-([0-9]+)$')::bigint, 0) AS log_index,
+			       amount::numeric AS amount
+			FROM %s WHERE LOWER(sukuk_address) = @sukuk AND timestamp >= @pruned_before_unix
+		), l AS (
+			SELECT LOWER(tx_hash) AS tx_hash, log_index::bigint AS log_index, amount::numeric AS amount
+			FROM %s WHERE sukuk_address = @sukuk AND deleted_at IS NULL AND status <> 'orphaned' AND timestamp >= @pruned_before
+		)`, quoteIdentifier(source.indexerTable), quoteIdentifier(source.localTable))
+	args := map[string]interface{}{
+		"sukuk":              sukukAddress,
+		"limit":              models.ReconciliationDetailLimit,
+		"pruned_before":      prunedBefore,
+		"pruned_before_unix": watermarkUnix(prunedBefore),
+	}
+
+	err = DefaultIndexerExecutor().Do(ctx, func() error {
+		db := s.db.WithContext(ctx)
+
+		var totals struct {
+			IndexerCount  int64
+			IndexerAmount string
+			LocalCount    int64
+			LocalAmount   string
+		}
+		err := db.Raw(sides+`
+			SELECT (SELECT COUNT(*) FROM i) AS indexer_count,
+			       (SELECT COALESCE(SUM(amount), 0)::text FROM i) AS indexer_amount,
+			       (SELECT COUNT(*) FROM l) AS local_count,
+			       (SELECT COALESCE(SUM(amount), 0)::text FROM l) AS local_amount`, args).Scan(&totals).Error
+		if err != nil {
+			return err
+		}
+		category.IndexerCount, category.IndexerAmount = totals.IndexerCount, totals.IndexerAmount
+		category.LocalCount, category.LocalAmount = totals.LocalCount, totals.LocalAmount
+
+		lists := []struct {
+			target *models.ReconciliationDiscrepancyList
+			query  string
+		}{
+			{&category.MissingLocally, `
+				SELECT i.tx_hash, i.log_index, i.amount::text AS indexer_amount, NULL::text AS local_amount, COUNT(*) OVER () AS total
+				FROM i LEFT JOIN l ON l.tx_hash = i.tx_hash AND l.log_index = i.log_index
+				WHERE l.tx_hash IS NULL`},
+			{&category.MissingInIndexer, `
+				SELECT l.tx_hash, l.log_index, NULL::text AS indexer_amount, l.amount::text AS local_amount, COUNT(*) OVER () AS total
+				FROM l LEFT JOIN i ON i.tx_hash = l.tx_hash AND i.log_index = l.log_index
+				WHERE i.tx_hash IS NULL`},
+			{&category.AmountMismatches, `
+				SELECT i.tx_hash, i.log_index, i.amount::text AS indexer_amount, l.amount::text AS local_amount, COUNT(*) OVER () AS total
+				FROM i JOIN l ON l.tx_hash = i.tx_hash AND l.log_index = i.log_index
+				WHERE i.amount <> l.amount`},
+		}
+		for _, list := range lists {
+			if err := scanDiscrepancies(db, sides+list.query+` ORDER BY 1, 2 LIMIT @limit`, args, list.target); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// scanDiscrepancies fills list with the capped rows of query; every row carries the uncapped total
+func scanDiscrepancies(db *gorm.DB, query string, args map[string]interface{}, list *models.ReconciliationDiscrepancyList) error {
+	var rows []struct {
+		TxHash        string
+		LogIndex      int64
+		IndexerAmount *string
+		LocalAmount   *string
+		Total         int64
+	}
+	if err := db.Raw(query, args).Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	list.Entries = make([]models.ReconciliationDiscrepancy, len(rows))
+	for i, row := range rows {
+		entry := models.ReconciliationDiscrepancy{TxHash: row.TxHash, LogIndex: row.LogIndex}
+		if row.IndexerAmount != nil {
+			entry.IndexerAmount = *row.IndexerAmount
+		}
+		if row.LocalAmount != nil {
+			entry.LocalAmount = *row.LocalAmount
+		}
+		list.Entries[i] = entry
+		list.Total = row.Total
+	}
+	return nil
+}
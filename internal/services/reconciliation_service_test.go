@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+// TestReconcileDivergedEvents requires a reachable Postgres, see testutil.Open
+func TestReconcileDivergedEvents(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	// Stand-in indexer tables with Ponder's "<tx_hash>-<log_index>" ids
+	const purchaseTable, redemptionTable = "recontest_sukuk_purchase", "recontest_redemption_request"
+	for _, table := range []string{purchaseTable, redemptionTable} {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, buyer TEXT, "user" TEXT, sukuk_address TEXT, payment_token TEXT,
+			amount NUMERIC(78,0), total_supply NUMERIC(78,0), block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+	}
+
+	const sukuk = "0x00000000000000000000000000000000000Dec01"
+	const buyer = "0x00000000000000000000000000000000000000aa"
+	defer db.Unscoped().Where("sukuk_address = LOWER(?)", sukuk).Delete(&models.SukukPurchased{})
+	defer db.Unscoped().Where("sukuk_address = LOWER(?)", sukuk).Delete(&models.RedemptionRequested{})
+
+	txHash := func(n int) string { return fmt.Sprintf("0x%064x", 0xdec000+n) }
+	indexerPurchase := func(n, logIndex int, amount string) {
+		err := db.Exec(`INSERT INTO `+purchaseTable+` (id, buyer, sukuk_address, payment_token, amount, block_number, tx_hash, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			fmt.Sprintf("%s-%d", txHash(n), logIndex), buyer, sukuk, "0x00000000000000000000000000000000000000cc", amount, 100+n, txHash(n), time.Now().Unix()).Error
+		if err != nil {
+			t.Fatalf("Failed to seed indexer purchase: %v", err)
+		}
+	}
+	localPurchase := func(n, logIndex int, amount string) {
+		event := models.SukukPurchased{
+			Buyer: buyer, SukukAddress: sukuk, PaymentToken: "0x00000000000000000000000000000000000000cc",
+			Amount: models.BigNumeric(amount), BlockNumber: uint64(100 + n), TxHash: txHash(n), LogIndex: uint(logIndex), Timestamp: time.Now(),
+		}
+		if err := models.CreateSukukPurchaseEvent(db, &event); err != nil {
+			t.Fatalf("Failed to seed local purchase: %v", err)
+		}
+	}
+
+	indexerPurchase(1, 0, "1000") // in sync
+	localPurchase(1, 0, "1000")
+	indexerPurchase(2, 0, "2000") // skipped by the sync
+	indexerPurchase(2, 1, "500")  // second log in the same transaction, also skipped
+	indexerPurchase(3, 0, "3000") // amount drifted
+	localPurchase(3, 0, "3100")
+	localPurchase(4, 0, "4000") // unknown to the indexer
+
+	service := NewReconciliationService(db)
+	service.SetIndexerTables(purchaseTable, redemptionTable)
+
+	report, err := service.Reconcile(ctx, sukuk)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if report.InSync || len(report.Categories) != 2 {
+		t.Fatalf("Expected two diverged categories, got %+v", report)
+	}
+
+	purchases := report.Categories[0]
+	if purchases.IndexerCount != 4 || purchases.LocalCount != 3 {
+		t.Errorf("Expected 4 indexer and 3 local purchases, got %d and %d", purchases.IndexerCount, purchases.LocalCount)
+	}
+	if purchases.IndexerAmount != "6500" || purchases.LocalAmount != "8100" {
+		t.Errorf("Expected summed amounts 6500 and 8100, got %s and %s", purchases.IndexerAmount, purchases.LocalAmount)
+	}
+	if purchases.MissingLocally.Total != 2 || purchases.MissingLocally.Entries[1].LogIndex != 1 {
+		t.Errorf("Expected both logs of tx 2 to be missing locally, got %+v", purchases.MissingLocally)
+	}
+	if purchases.MissingInIndexer.Total != 1 || purchases.MissingInIndexer.Entries[0].TxHash != txHash(4) {
+		t.Errorf("Expected tx 4 to be missing in the indexer, got %+v", purchases.MissingInIndexer)
+	}
+	mismatch := purchases.AmountMismatches
+	if mismatch.Total != 1 || mismatch.Entries[0].IndexerAmount != "3000" || mismatch.Entries[0].LocalAmount != "3100" {
+		t.Errorf("Expected the tx 3 amount mismatch, got %+v", mismatch)
+	}
+	if !report.Categories[1].InSync() {
+		t.Errorf("Expected empty redemption tables to be in sync, got %+v", report.Categories[1])
+	}
+
+	// Backfilling inserts only the missing purchases and is idempotent
+	for _, want := range []int{2, 0} {
+		var inserted int
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			inserted, err = service.BackfillMissingPurchases(tx, sukuk)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Failed to backfill: %v", err)
+		}
+		if inserted != want {
+			t.Errorf("Expected %d backfilled purchases, got %d", want, inserted)
+		}
+	}
+
+	report, err = service.Reconcile(ctx, sukuk)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if purchases := report.Categories[0]; purchases.MissingLocally.Total != 0 || purchases.AmountMismatches.Total != 1 {
+		t.Errorf("Expected only the amount mismatch to remain after backfill, got %+v", purchases)
+	}
+}
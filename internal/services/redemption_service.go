@@ -1,12 +1,17 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"sukuk-be/internal/database"
 	"sukuk-be/internal/models"
 	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
 )
 
 type RedemptionService struct {
@@ -21,109 +26,270 @@ func NewRedemptionService() *RedemptionService {
 	}
 }
 
-// GetAllRedemptions returns all redemptions with their approval status
-func (s *RedemptionService) GetAllRedemptions(limit int, offset int) (*models.RedemptionListResponse, error) {
+// ErrInvalidRedemptionSort is returned for a sort field or order ListRedemptions does not support
+var ErrInvalidRedemptionSort = errors.New("sort must be created_at or amount and order asc or desc")
+
+// redemptionSortColumns whitelists the ?sort= fields and the request column each orders by,
+// so user input never reaches ORDER BY
+var redemptionSortColumns = map[string]string{
+	"created_at": "r.timestamp",
+	"amount":     "r.amount",
+}
+
+// RedemptionListFilter selects and orders a page of redemption requests
+type RedemptionListFilter struct {
+	Status       models.RedemptionStatus // Empty for every status
+	User         string                  // Lowercase address; empty for every user
+	SukukAddress string                  // Lowercase address; empty for every sukuk
+	RequestID    string                  // A single request; empty for every request
+	Sort         string                  // created_at (default) or amount
+	Ascending    bool                    // Newest or largest first unless set
+	Limit        int                     // 0 for no limit
+	Offset       int
+}
+
+// ParseRedemptionSort validates ?sort= and ?order=, defaulting to created_at descending
+func ParseRedemptionSort(sort, order string) (string, bool, error) {
+	if sort == "" {
+		sort = "created_at"
+	}
+	if _, ok := redemptionSortColumns[sort]; !ok {
+		return "", false, ErrInvalidRedemptionSort
+	}
+	switch order {
+	case "", "desc":
+		return sort, false, nil
+	case "asc":
+		return sort, true, nil
+	}
+	return "", false, ErrInvalidRedemptionSort
+}
+
+// RedemptionPage is a page of redemptions and the number matching the filter on every page
+type RedemptionPage struct {
+	Redemptions []models.RedemptionRequest
+	Total       int64
+}
+
+// ListRedemptions returns one page of redemption requests with their approval status.
+// Filters, ordering and the page window run in the query; Total comes from a COUNT over
+// the same filters
+func (s *RedemptionService) ListRedemptions(ctx context.Context, filter RedemptionListFilter) (*RedemptionPage, error) {
 	if err := s.indexerService.ConnectToIndexer(); err != nil {
 		return nil, err
 	}
 
-	// Get redemption requests
-	requests, err := s.getRedemptionRequests(limit, offset)
+	column, ok := redemptionSortColumns[filter.Sort]
+	if filter.Sort == "" {
+		column, ok = redemptionSortColumns["created_at"], true
+	}
+	if !ok {
+		return nil, ErrInvalidRedemptionSort
+	}
+	direction := "DESC"
+	if filter.Ascending {
+		direction = "ASC"
+	}
+
+	tableService := s.indexerService.tableService
+	requestTable, err := tableService.GetLatestTableForEvent("redemption_request")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
+	}
+	approvalTable, err := tableService.GetLatestTableForEvent("redemption_approval")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemption_approval table: %w", err)
+	}
+
+	// A request is approved once its user has an approval for the sukuk, as in mergeRedemptionsWithApprovals
+	approved := fmt.Sprintf(`EXISTS (SELECT 1 FROM %s a WHERE a."user" = r."user" AND a.sukuk_address = r.sukuk_address)`, quoteIdentifier(approvalTable))
+	filtered := func(db *gorm.DB) *gorm.DB {
+		query := db.Table(quoteIdentifier(requestTable) + " r")
+		switch filter.Status {
+		case "":
+		case models.RedemptionStatusRequested:
+			query = query.Where("NOT " + approved)
+		case models.RedemptionStatusApproved:
+			query = query.Where(approved)
+		default:
+			// Rejections and completions are not indexed, so no request has those statuses
+			query = query.Where("FALSE")
+		}
+		if filter.User != "" {
+			query = query.Where(`r."user" = ?`, filter.User)
+		}
+		if filter.SukukAddress != "" {
+			query = query.Where("r.sukuk_address = ?", filter.SukukAddress)
+		}
+		if filter.RequestID != "" {
+			query = query.Where("r.id = ?", filter.RequestID)
+		}
+		return query
+	}
+
+	page := &RedemptionPage{Redemptions: []models.RedemptionRequest{}}
+	var requests []IndexerRedemptionRequest
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		if err := filtered(db).Count(&page.Total).Error; err != nil {
+			return err
+		}
+		// The id breaks ties so pages never overlap
+		query := filtered(db).Select("r.*").Order(fmt.Sprintf("%s %s, r.id %s", column, direction, direction))
+		if filter.Limit > 0 {
+			query = query.Limit(filter.Limit)
+		}
+		if filter.Offset > 0 {
+			query = query.Offset(filter.Offset)
+		}
+		return query.Find(&requests).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get redemption requests: %w", err)
 	}
+	if len(requests) == 0 {
+		return page, nil
+	}
 
-	// Get redemption approvals
-	approvals, err := s.getRedemptionApprovals()
+	// Approvals and metadata are read only for the users and sukuk on this page
+	users := make([]string, 0, len(requests))
+	sukukAddresses := make([]string, 0, len(requests))
+	for _, request := range requests {
+		users = append(users, request.User)
+		sukukAddresses = append(sukukAddresses, request.SukukAddress)
+	}
+
+	var approvals []IndexerRedemptionApproval
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		return db.Table(approvalTable).
+			Where(`"user" IN ?`, users).
+			Order("timestamp DESC").
+			Find(&approvals).Error
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get redemption approvals: %w", err)
 	}
 
-	// Merge and create comprehensive redemption list
-	redemptions := s.mergeRedemptionsWithApprovals(requests, approvals)
+	var metadata []models.SukukMetadata
+	if err := database.GetDB().WithContext(ctx).Where("contract_address IN ?", sukukAddresses).Find(&metadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sukuk metadata: %w", err)
+	}
+	metadataByAddress := make(map[string]*models.SukukMetadata, len(metadata))
+	for i := range metadata {
+		metadataByAddress[metadata[i].ContractAddress] = &metadata[i]
+	}
+
+	page.Redemptions = s.mergeRedemptionsWithApprovals(requests, approvals)
+	for i := range page.Redemptions {
+		page.Redemptions[i].Metadata = metadataByAddress[page.Redemptions[i].SukukAddress]
 
-	// Add metadata for each redemption
-	for i := range redemptions {
-		var sukukMetadata models.SukukMetadata
-		if err := database.GetDB().Where("contract_address = ?", redemptions[i].SukukAddress).First(&sukukMetadata).Error; err == nil {
-			redemptions[i].Metadata = &sukukMetadata
-		}
-		
 		// Determine if can be approved (not already approved)
-		redemptions[i].CanApprove = redemptions[i].Status == models.RedemptionStatusRequested
-		redemptions[i].RequiresManagerAuth = true
+		page.Redemptions[i].CanApprove = page.Redemptions[i].Status == models.RedemptionStatusRequested
+		page.Redemptions[i].RequiresManagerAuth = true
+	}
+	if err := attachPayoutStatuses(ctx, page.Redemptions); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ListRedemptions reads a page of redemptions with a new RedemptionService
+func ListRedemptions(ctx context.Context, filter RedemptionListFilter) (*RedemptionPage, error) {
+	return NewRedemptionService().ListRedemptions(ctx, filter)
+}
+
+// GetAllRedemptions returns a page of redemptions with their approval status. TotalCount
+// counts every redemption matching the filter; status counts and totals cover the page
+func (s *RedemptionService) GetAllRedemptions(ctx context.Context, filter RedemptionListFilter) (*models.RedemptionListResponse, error) {
+	page, err := s.ListRedemptions(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate status counts
 	statusCounts := make(map[string]int)
-	for _, r := range redemptions {
+	for _, r := range page.Redemptions {
 		statusCounts[string(r.Status)]++
 	}
 
-	return &models.RedemptionListResponse{
-		TotalCount:   len(redemptions),
-		Redemptions:  redemptions,
+	response := &models.RedemptionListResponse{
+		TotalCount:   int(page.Total),
+		Redemptions:  page.Redemptions,
 		StatusCounts: statusCounts,
-	}, nil
+	}
+	s.applyRedemptionTotals(response)
+
+	return response, nil
 }
 
 // GetRedemptionsByUser returns redemptions for a specific user
-func (s *RedemptionService) GetRedemptionsByUser(userAddress string) (*models.RedemptionListResponse, error) {
+func (s *RedemptionService) GetRedemptionsByUser(ctx context.Context, userAddress string) (*models.RedemptionListResponse, error) {
 	if err := s.indexerService.ConnectToIndexer(); err != nil {
 		return nil, err
 	}
 
 	// Get user's redemption requests
-	requests, err := s.getUserRedemptionRequests(userAddress)
+	requests, err := s.getUserRedemptionRequests(ctx, userAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user redemption requests: %w", err)
 	}
 
 	// Get user's redemption approvals
-	approvals, err := s.getUserRedemptionApprovals(userAddress)
+	approvals, err := s.getUserRedemptionApprovals(ctx, userAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user redemption approvals: %w", err)
 	}
 
 	// Merge and create comprehensive redemption list
 	redemptions := s.mergeRedemptionsWithApprovals(requests, approvals)
+	if err := attachPayoutStatuses(ctx, redemptions); err != nil {
+		return nil, err
+	}
 
-	return &models.RedemptionListResponse{
+	response := &models.RedemptionListResponse{
 		TotalCount:  len(redemptions),
 		Redemptions: redemptions,
-	}, nil
+	}
+	s.applyRedemptionTotals(response)
+
+	return response, nil
 }
 
 // GetRedemptionsBySukuk returns redemptions for a specific sukuk
-func (s *RedemptionService) GetRedemptionsBySukuk(sukukAddress string) (*models.RedemptionListResponse, error) {
+func (s *RedemptionService) GetRedemptionsBySukuk(ctx context.Context, sukukAddress string) (*models.RedemptionListResponse, error) {
 	if err := s.indexerService.ConnectToIndexer(); err != nil {
 		return nil, err
 	}
 
 	// Get sukuk's redemption requests
-	requests, err := s.getSukukRedemptionRequests(sukukAddress)
+	requests, err := s.getSukukRedemptionRequests(ctx, sukukAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sukuk redemption requests: %w", err)
 	}
 
 	// Get sukuk's redemption approvals
-	approvals, err := s.getSukukRedemptionApprovals(sukukAddress)
+	approvals, err := s.getSukukRedemptionApprovals(ctx, sukukAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sukuk redemption approvals: %w", err)
 	}
 
 	// Merge and create comprehensive redemption list
 	redemptions := s.mergeRedemptionsWithApprovals(requests, approvals)
+	if err := attachPayoutStatuses(ctx, redemptions); err != nil {
+		return nil, err
+	}
 
-	return &models.RedemptionListResponse{
+	response := &models.RedemptionListResponse{
 		TotalCount:  len(redemptions),
 		Redemptions: redemptions,
-	}, nil
+	}
+	s.applyRedemptionTotals(response)
+
+	return response, nil
 }
 
 // GetRedemptionStats returns overall redemption statistics
-func (s *RedemptionService) GetRedemptionStats() (*models.RedemptionStatsResponse, error) {
-	allRedemptions, err := s.GetAllRedemptions(1000, 0) // Get a large set for stats
+func (s *RedemptionService) GetRedemptionStats(ctx context.Context) (*models.RedemptionStatsResponse, error) {
+	allRedemptions, err := s.GetAllRedemptions(ctx, RedemptionListFilter{Limit: 1000}) // Get a large set for stats
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +324,7 @@ func (s *RedemptionService) GetRedemptionStats() (*models.RedemptionStatsRespons
 			sukukStats[r.SukukAddress] = &models.RedemptionSukukStats{
 				SukukAddress: r.SukukAddress,
 				SukukCode:    sukukCode,
+				PaymentToken: r.PaymentToken,
 			}
 		}
 		
@@ -192,8 +359,22 @@ func (s *RedemptionService) GetRedemptionStats() (*models.RedemptionStatsRespons
 		stats.TotalApprovedAmount = totalApproved
 	}
 
+	// Format per-sukuk amounts with the payment token decimals
+	tokenFormatter, err := LoadTokenFormatter()
+	if err != nil {
+		tokenFormatter = NewTokenFormatter(nil)
+	}
+
 	// Convert map to response format
 	for addr, stat := range sukukStats {
+		if stat.PaymentToken != "" {
+			requested := tokenFormatter.FormatTokenAmount(stat.RequestedAmount, stat.PaymentToken)
+			stat.RequestedAmountFormatted = &requested
+			if stat.ApprovedAmount != "" {
+				approved := tokenFormatter.FormatTokenAmount(stat.ApprovedAmount, stat.PaymentToken)
+				stat.ApprovedAmountFormatted = &approved
+			}
+		}
 		stats.BySukuk[addr] = *stat
 	}
 
@@ -202,7 +383,7 @@ func (s *RedemptionService) GetRedemptionStats() (*models.RedemptionStatsRespons
 
 // Private helper methods
 
-func (s *RedemptionService) getRedemptionRequests(limit, offset int) ([]IndexerRedemptionRequest, error) {
+func (s *RedemptionService) getUserRedemptionRequests(ctx context.Context, userAddress string) ([]IndexerRedemptionRequest, error) {
 	tableService := NewIndexerTableService()
 	if err := tableService.ConnectToIndexer(); err != nil {
 		return nil, err
@@ -214,60 +395,17 @@ func (s *RedemptionService) getRedemptionRequests(limit, offset int) ([]IndexerR
 	}
 
 	var requests []IndexerRedemptionRequest
-	query := s.indexerService.indexerDB.Table(requestTable).
-		Order("timestamp DESC")
-
-	if limit > 0 {
-		query = query.Limit(limit)
-	}
-	if offset > 0 {
-		query = query.Offset(offset)
-	}
-
-	err = query.Find(&requests).Error
-	return requests, err
-}
-
-func (s *RedemptionService) getRedemptionApprovals() ([]IndexerRedemptionApproval, error) {
-	tableService := NewIndexerTableService()
-	if err := tableService.ConnectToIndexer(); err != nil {
-		return nil, err
-	}
-
-	approvalTable, err := tableService.GetLatestTableForEvent("redemption_approval")
-	if err != nil {
-		return nil, fmt.Errorf("failed to find redemption_approval table: %w", err)
-	}
-
-	var approvals []IndexerRedemptionApproval
-	err = s.indexerService.indexerDB.Table(approvalTable).
-		Order("timestamp DESC").
-		Find(&approvals).Error
-	
-	return approvals, err
-}
-
-func (s *RedemptionService) getUserRedemptionRequests(userAddress string) ([]IndexerRedemptionRequest, error) {
-	tableService := NewIndexerTableService()
-	if err := tableService.ConnectToIndexer(); err != nil {
-		return nil, err
-	}
-
-	requestTable, err := tableService.GetLatestTableForEvent("redemption_request")
-	if err != nil {
-		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
-	}
-
-	var requests []IndexerRedemptionRequest
-	err = s.indexerService.indexerDB.Table(requestTable).
-		Where("user = ?", userAddress).
-		Order("timestamp DESC").
-		Find(&requests).Error
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		return db.Table(requestTable).
+			Where("user = ?", userAddress).
+			Order("timestamp DESC").
+			Find(&requests).Error
+	})
 	
 	return requests, err
 }
 
-func (s *RedemptionService) getUserRedemptionApprovals(userAddress string) ([]IndexerRedemptionApproval, error) {
+func (s *RedemptionService) getUserRedemptionApprovals(ctx context.Context, userAddress string) ([]IndexerRedemptionApproval, error) {
 	tableService := NewIndexerTableService()
 	if err := tableService.ConnectToIndexer(); err != nil {
 		return nil, err
@@ -279,15 +417,17 @@ func (s *RedemptionService) getUserRedemptionApprovals(userAddress string) ([]In
 	}
 
 	var approvals []IndexerRedemptionApproval
-	err = s.indexerService.indexerDB.Table(approvalTable).
-		Where("user = ?", userAddress).
-		Order("timestamp DESC").
-		Find(&approvals).Error
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		return db.Table(approvalTable).
+			Where("user = ?", userAddress).
+			Order("timestamp DESC").
+			Find(&approvals).Error
+	})
 	
 	return approvals, err
 }
 
-func (s *RedemptionService) getSukukRedemptionRequests(sukukAddress string) ([]IndexerRedemptionRequest, error) {
+func (s *RedemptionService) getSukukRedemptionRequests(ctx context.Context, sukukAddress string) ([]IndexerRedemptionRequest, error) {
 	tableService := NewIndexerTableService()
 	if err := tableService.ConnectToIndexer(); err != nil {
 		return nil, err
@@ -299,15 +439,17 @@ func (s *RedemptionService) getSukukRedemptionRequests(sukukAddress string) ([]I
 	}
 
 	var requests []IndexerRedemptionRequest
-	err = s.indexerService.indexerDB.Table(requestTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		Find(&requests).Error
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		return db.Table(requestTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("timestamp DESC").
+			Find(&requests).Error
+	})
 	
 	return requests, err
 }
 
-func (s *RedemptionService) getSukukRedemptionApprovals(sukukAddress string) ([]IndexerRedemptionApproval, error) {
+func (s *RedemptionService) getSukukRedemptionApprovals(ctx context.Context, sukukAddress string) ([]IndexerRedemptionApproval, error) {
 	tableService := NewIndexerTableService()
 	if err := tableService.ConnectToIndexer(); err != nil {
 		return nil, err
@@ -319,10 +461,12 @@ func (s *RedemptionService) getSukukRedemptionApprovals(sukukAddress string) ([]
 	}
 
 	var approvals []IndexerRedemptionApproval
-	err = s.indexerService.indexerDB.Table(approvalTable).
-		Where("sukuk_address = ?", sukukAddress).
-		Order("timestamp DESC").
-		Find(&approvals).Error
+	err = s.indexerService.read(ctx, func(db *gorm.DB) error {
+		return db.Table(approvalTable).
+			Where("sukuk_address = ?", sukukAddress).
+			Order("timestamp DESC").
+			Find(&approvals).Error
+	})
 	
 	return approvals, err
 }
@@ -367,4 +511,73 @@ func (s *RedemptionService) mergeRedemptionsWithApprovals(requests []IndexerRede
 	}
 
 	return redemptions
-}
\ No newline at end of file
+}
+
+// attachPayoutStatuses sets the payout status of the redemptions that have a payout instruction
+func attachPayoutStatuses(ctx context.Context, redemptions []models.RedemptionRequest) error {
+	if len(redemptions) == 0 {
+		return nil
+	}
+	requestIDs := make([]string, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		requestIDs = append(requestIDs, redemption.RequestID)
+	}
+	statuses, err := models.PayoutStatusesByRedemption(database.GetDB().WithContext(ctx), requestIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get payout statuses: %w", err)
+	}
+	models.ApplyPayoutStatuses(redemptions, statuses)
+	return nil
+}
+
+// applyRedemptionTotals fills in the wei totals of a redemption list and, when every row
+// shares a payment token, their formatted values
+func (s *RedemptionService) applyRedemptionTotals(response *models.RedemptionListResponse) {
+	response.TotalRedemptionAmount, response.CompletedRedemptionAmount, response.SkippedRows = s.sumRedemptions(response.Redemptions)
+
+	paymentToken := ""
+	for i, r := range response.Redemptions {
+		if i > 0 && !strings.EqualFold(r.PaymentToken, paymentToken) {
+			return
+		}
+		paymentToken = r.PaymentToken
+	}
+	if paymentToken == "" {
+		return
+	}
+
+	tokenFormatter, err := LoadTokenFormatter()
+	if err != nil {
+		tokenFormatter = NewTokenFormatter(nil)
+	}
+	total := tokenFormatter.FormatTokenAmount(response.TotalRedemptionAmount, paymentToken)
+	completed := tokenFormatter.FormatTokenAmount(response.CompletedRedemptionAmount, paymentToken)
+	response.TotalRedemptionAmountFormatted = &total
+	response.CompletedRedemptionAmountFormatted = &completed
+}
+
+// sumRedemptions totals requested amounts and the approved amounts of approved or completed
+// redemptions. Rows with an empty or malformed amount are left out and counted as skipped
+func (s *RedemptionService) sumRedemptions(redemptions []models.RedemptionRequest) (total, completed string, skipped int) {
+	var requestedAmounts, completedAmounts []string
+	for _, r := range redemptions {
+		amounts := []string{r.Amount}
+		isCompleted := (r.Status == models.RedemptionStatusApproved || r.Status == models.RedemptionStatusCompleted) && r.ApprovedAmount != nil
+		if isCompleted {
+			amounts = append(amounts, *r.ApprovedAmount)
+		}
+		if _, invalid := s.mathUtil.SumValidTokenAmounts(amounts); invalid > 0 {
+			skipped++
+			continue
+		}
+
+		requestedAmounts = append(requestedAmounts, r.Amount)
+		if isCompleted {
+			completedAmounts = append(completedAmounts, *r.ApprovedAmount)
+		}
+	}
+
+	total, _ = s.mathUtil.SumValidTokenAmounts(requestedAmounts)
+	completed, _ = s.mathUtil.SumValidTokenAmounts(completedAmounts)
+	return total, completed, skipped
+}
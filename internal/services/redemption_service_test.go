@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"sukuk-be/internal/models"
+)
+
+func TestSumRedemptions(t *testing.T) {
+	approved := func(amount string) *string { return &amount }
+	redemptions := []models.RedemptionRequest{
+		{RequestID: "1", Amount: "1000000000000000000", Status: models.RedemptionStatusRequested},
+		{RequestID: "2", Amount: "2500000000000000000", Status: models.RedemptionStatusApproved, ApprovedAmount: approved("2500000000000000000")},
+		{RequestID: "3", Amount: "500", Status: models.RedemptionStatusCompleted, ApprovedAmount: approved("400")},
+		{RequestID: "4", Amount: "", Status: models.RedemptionStatusRequested},
+		{RequestID: "5", Amount: "12abc", Status: models.RedemptionStatusRequested},
+		{RequestID: "6", Amount: "700", Status: models.RedemptionStatusApproved, ApprovedAmount: approved("not-a-number")},
+	}
+
+	total, completed, skipped := NewRedemptionService().sumRedemptions(redemptions)
+
+	if total != "3500000000000000500" {
+		t.Errorf("Expected total 3500000000000000500, got %s", total)
+	}
+	if completed != "2500000000000000400" {
+		t.Errorf("Expected completed 2500000000000000400, got %s", completed)
+	}
+	if skipped != 3 {
+		t.Errorf("Expected 3 skipped rows, got %d", skipped)
+	}
+}
+
+func TestSumRedemptionsEmpty(t *testing.T) {
+	total, completed, skipped := NewRedemptionService().sumRedemptions(nil)
+	if total != "0" || completed != "0" || skipped != 0 {
+		t.Errorf("Expected zero totals, got %s, %s, %d", total, completed, skipped)
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+)
+
+// referralAttributionBatchSize bounds how many purchases are attributed per cycle
+const referralAttributionBatchSize = 500
+
+// referralPurchase is an indexed purchase by a buyer who had bound a referral code before it
+type referralPurchase struct {
+	ReferralID uint
+	Buyer      string
+	Amount     string
+	TxHash     string
+}
+
+// syncReferralAttributions credits new purchases to the referral code their buyer bound
+// Purchases from before the binding (compared in whole seconds, like the indexer's block
+// timestamps) are never attributed, and each purchase tx is attributed at most once
+func (s *SukukMetadataSyncService) syncReferralAttributions(ctx context.Context, result *SyncResult) error {
+	tableName, err := s.findLatestEventTable(ctx, "sukuk_purchase")
+	if err != nil {
+		return err
+	}
+	if tableName == "" {
+		return nil
+	}
+
+	var purchases []referralPurchase
+	query := fmt.Sprintf(`
+		SELECT b.referral_id, p.buyer, p.amount::text AS amount, p.tx_hash
+		FROM %s p
+		JOIN referral_bindings b ON b.address = LOWER(p.buyer)
+		WHERE p.timestamp >= FLOOR(EXTRACT(EPOCH FROM b.bound_at))
+		AND NOT EXISTS (SELECT 1 FROM referral_attributions a WHERE a.purchase_tx_hash = p.tx_hash)
+		ORDER BY p.block_number ASC, p.tx_hash ASC
+		LIMIT ?`, quoteIdentifier(tableName))
+	if err := s.db.WithContext(ctx).Raw(query, referralAttributionBatchSize).Scan(&purchases).Error; err != nil {
+		return fmt.Errorf("failed to fetch referred purchases from %s: %w", tableName, err)
+	}
+
+	now := time.Now()
+	for _, purchase := range purchases {
+		attribution := models.ReferralAttribution{
+			ReferralID:     purchase.ReferralID,
+			BuyerAddress:   purchase.Buyer,
+			PurchaseTxHash: purchase.TxHash,
+			Amount:         models.BigNumeric(purchase.Amount),
+			AttributedAt:   now,
+		}
+		err := models.CreateReferralAttribution(s.db.WithContext(ctx), &attribution)
+		if errors.Is(err, models.ErrDuplicateEvent) {
+			continue // Another purchase log in the same tx was attributed first
+		}
+		if err != nil {
+			logger.WithError(err).WithField("tx_hash", purchase.TxHash).Error("Failed to attribute referred purchase")
+			result.Failed++
+			continue
+		}
+		result.Processed++
+	}
+
+	return nil
+}
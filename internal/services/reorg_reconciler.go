@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/metrics"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// reorgAlertRowLimit caps the orphaned rows listed in an alert message; the incident keeps them all
+const reorgAlertRowLimit = 20
+
+// reorgDerivedTable is a local table holding rows built from an indexer event type, matched
+// to it on transaction hash. filter narrows tables shared by several event types
+type reorgDerivedTable struct {
+	table     string
+	eventType string
+	filter    string
+}
+
+// reorgDerivedTables lists every local table whose rows a reorg can leave without a
+// canonical transaction, grouped by indexer event type in the order they are checked
+var reorgDerivedTables = []reorgDerivedTable{
+	{models.SukukPurchased{}.TableName(), "sukuk_purchase", ""},
+	{models.LedgerEntry{}.TableName(), "sukuk_purchase", fmt.Sprintf("event_type = '%s'", models.LedgerEventPurchase)},
+	{models.RedemptionRequested{}.TableName(), "redemption_request", ""},
+	{models.LedgerEntry{}.TableName(), "redemption_approval", fmt.Sprintf("event_type = '%s'", models.LedgerEventRedemptionPayout)},
+	{models.LedgerEntry{}.TableName(), "yield_claim", fmt.Sprintf("event_type = '%s'", models.LedgerEventYieldPayment)},
+	{models.ActivityProjection{}.TableName(), "sukuk_purchase", fmt.Sprintf("type = '%s'", models.ActivityTypePurchase)},
+	{models.ActivityProjection{}.TableName(), "redemption_request", fmt.Sprintf("type = '%s'", models.ActivityTypeRedemptionRequest)},
+	{models.ActivityProjection{}.TableName(), "yield_claim", fmt.Sprintf("type = '%s'", models.ActivityTypeYieldClaim)},
+}
+
+// ReorgReconciler finds transactions a chain reorg dropped and orphans the rows derived from
+// them. Ponder moves rows it reverts into a "<hash>_reorg__<event>" table, so a transaction in
+// the recent blocks of the reorg table that is gone from the canonical table was reorged away.
+// Derived rows of such transactions are marked orphaned, never deleted, and each run that
+// orphans rows records a ReorgIncident and alerts. Rows whose transaction is canonical again,
+// e.g. re-mined in a later block, are confirmed again
+type ReorgReconciler struct {
+	db             *gorm.DB
+	tableService   *IndexerTableService
+	lookbackBlocks int64
+	interval       time.Duration
+	alerter        SyncAlerter // Nil when no alert webhook is configured
+	lock           *SyncLock   // Keeps replicas from reconciling at once; nil when not shared
+	cancel         context.CancelFunc
+	now            func() time.Time
+}
+
+// NewReorgReconciler creates a reconciler on db, which also holds the indexer tables,
+// comparing the last lookbackBlocks blocks of every event table; alerter may be nil
+func NewReorgReconciler(db *gorm.DB, lookbackBlocks int64, interval time.Duration, alerter SyncAlerter) *ReorgReconciler {
+	return &ReorgReconciler{
+		db:             db,
+		tableService:   &IndexerTableService{indexerDB: db},
+		lookbackBlocks: lookbackBlocks,
+		interval:       interval,
+		alerter:        alerter,
+		now:            time.Now,
+	}
+}
+
+// NewDefaultReorgReconciler creates a reconciler on the application database
+func NewDefaultReorgReconciler(lookbackBlocks int64, interval time.Duration, alerter SyncAlerter) *ReorgReconciler {
+	return NewReorgReconciler(database.GetDB(), lookbackBlocks, interval, alerter)
+}
+
+// retractedTx is a transaction found in the reorg table but not in the canonical one
+type retractedTx struct {
+	TxHash    string
+	FromBlock int64
+	ToBlock   int64
+}
+
+// Run reconciles every event type with derived rows, returning the incidents it recorded
+func (r *ReorgReconciler) Run(ctx context.Context) ([]models.ReorgIncident, error) {
+	tables, err := r.tableService.GetAllLatestTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find indexer tables: %w", err)
+	}
+
+	incidents := []models.ReorgIncident{}
+	for _, eventType := range reorgEventTypes() {
+		canonical, ok := tables[eventType]
+		if !ok {
+			continue
+		}
+		reorg, ok := reorgTableName(canonical)
+		if !ok {
+			continue
+		}
+		exists, err := r.tableService.CheckTableExists(reorg)
+		if err != nil {
+			return incidents, fmt.Errorf("failed to check %s: %w", reorg, err)
+		}
+		if !exists {
+			continue
+		}
+
+		incident, err := r.reconcile(ctx, eventType, canonical, reorg)
+		if err != nil {
+			return incidents, fmt.Errorf("failed to reconcile %s: %w", eventType, err)
+		}
+		if incident != nil {
+			incidents = append(incidents, *incident)
+			r.alert(ctx, incident)
+		}
+	}
+	return incidents, nil
+}
+
+// reconcile orphans and restores the derived rows of one event type, recording an incident
+// when it orphaned any
+func (r *ReorgReconciler) reconcile(ctx context.Context, eventType, canonical, reorg string) (*models.ReorgIncident, error) {
+	// The window ends at the higher head of both tables, as a reorg can leave the canonical
+	// table behind the reverted rows
+	query := fmt.Sprintf(`
+		WITH head AS (
+			SELECT GREATEST(
+				(SELECT COALESCE(MAX(block_number), 0) FROM %[1]s),
+				(SELECT COALESCE(MAX(block_number), 0) FROM %[2]s)) AS block
+		)
+		SELECT LOWER(r.tx_hash) AS tx_hash, MIN(r.block_number)::bigint AS from_block, MAX(r.block_number)::bigint AS to_block
+		FROM %[2]s r, head
+		WHERE r.block_number > head.block - ?
+		  AND NOT EXISTS (SELECT 1 FROM %[1]s c WHERE LOWER(c.tx_hash) = LOWER(r.tx_hash))
+		GROUP BY LOWER(r.tx_hash)`, quoteIdentifier(canonical), quoteIdentifier(reorg))
+
+	var retracted []retractedTx
+	err := DefaultIndexerExecutor().Do(ctx, func() error {
+		return r.db.WithContext(ctx).Raw(query, r.lookbackBlocks).Scan(&retracted).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(map[string]retractedTx, len(retracted))
+	hashes := make([]string, 0, len(retracted))
+	for _, tx := range retracted {
+		blocks[tx.TxHash] = tx
+		hashes = append(hashes, tx.TxHash)
+	}
+
+	var incident *models.ReorgIncident
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var affected []models.ReorgAffectedRow
+		for _, derived := range reorgDerivedTables {
+			if derived.eventType != eventType {
+				continue
+			}
+			if err := r.restore(tx, derived, canonical); err != nil {
+				return err
+			}
+			if len(hashes) == 0 {
+				continue
+			}
+			rows, err := r.orphan(tx, derived, hashes)
+			if err != nil {
+				return err
+			}
+			affected = append(affected, rows...)
+		}
+		if len(affected) == 0 {
+			return nil
+		}
+
+		incident = newReorgIncident(eventType, canonical, reorg, affected, blocks, r.now())
+		return tx.Create(incident).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// orphan marks the rows of a derived table built from any of hashes, returning the rows it marked
+func (r *ReorgReconciler) orphan(tx *gorm.DB, derived reorgDerivedTable, hashes []string) ([]models.ReorgAffectedRow, error) {
+	var rows []struct {
+		ID     uint
+		TxHash string
+	}
+	query := tx.Table(derived.table).Select("id, LOWER(tx_hash) AS tx_hash").
+		Where("LOWER(tx_hash) IN ? AND status <> ?", hashes, models.EventStatusOrphaned)
+	if derived.filter != "" {
+		query = query.Where(derived.filter)
+	}
+	if err := query.Order("id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(rows))
+	affected := make([]models.ReorgAffectedRow, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		affected[i] = models.ReorgAffectedRow{Table: derived.table, ID: row.ID, TxHash: row.TxHash}
+	}
+	if err := tx.Table(derived.table).Where("id IN ?", ids).Update("status", models.EventStatusOrphaned).Error; err != nil {
+		return nil, err
+	}
+	metrics.ReorgOrphanedRows.WithLabelValues(derived.table).Add(float64(len(rows)))
+	return affected, nil
+}
+
+// restore confirms the orphaned rows of a derived table whose transaction is canonical again
+func (r *ReorgReconciler) restore(tx *gorm.DB, derived reorgDerivedTable, canonical string) error {
+	query := tx.Table(derived.table).
+		Where("status = ?", models.EventStatusOrphaned).
+		Where(fmt.Sprintf("EXISTS (SELECT 1 FROM %s c WHERE LOWER(c.tx_hash) = LOWER(%s.tx_hash))",
+			quoteIdentifier(canonical), quoteIdentifier(derived.table)))
+	if derived.filter != "" {
+		query = query.Where(derived.filter)
+	}
+	result := query.Update("status", models.EventStatusConfirmed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.WithFields(map[string]interface{}{
+			"table":    derived.table,
+			"restored": result.RowsAffected,
+		}).Info("Confirmed orphaned rows whose transaction is canonical again")
+	}
+	return nil
+}
+
+// newReorgIncident records the orphaned rows, with the transactions and block range behind them
+func newReorgIncident(eventType, canonical, reorg string, affected []models.ReorgAffectedRow, blocks map[string]retractedTx, now time.Time) *models.ReorgIncident {
+	incident := &models.ReorgIncident{
+		EventType:    eventType,
+		IndexerTable: canonical,
+		ReorgTable:   reorg,
+		Affected:     affected,
+		DetectedAt:   now.UTC(),
+	}
+	seen := make(map[string]bool)
+	for _, row := range affected {
+		if seen[row.TxHash] {
+			continue
+		}
+		seen[row.TxHash] = true
+		incident.TxHashes = append(incident.TxHashes, row.TxHash)
+
+		block := blocks[row.TxHash]
+		if len(incident.TxHashes) == 1 || block.FromBlock < incident.FromBlock {
+			incident.FromBlock = block.FromBlock
+		}
+		if block.ToBlock > incident.ToBlock {
+			incident.ToBlock = block.ToBlock
+		}
+	}
+	sort.Strings(incident.TxHashes)
+	return incident
+}
+
+// alert logs the incident and notifies the alerter, listing the orphaned rows
+func (r *ReorgReconciler) alert(ctx context.Context, incident *models.ReorgIncident) {
+	logger.WithFields(map[string]interface{}{
+		"event_type": incident.EventType,
+		"tx_hashes":  incident.TxHashes,
+		"from_block": incident.FromBlock,
+		"to_block":   incident.ToBlock,
+		"orphaned":   len(incident.Affected),
+	}).Error("Chain reorg dropped transactions with derived rows, marked them orphaned")
+
+	if r.alerter == nil {
+		return
+	}
+	anomaly := SyncAnomaly{Kind: SyncAnomalyReorg, Message: reorgAlertMessage(incident), Since: incident.DetectedAt}
+	if err := r.alerter.Alert(ctx, SyncServiceReorg, anomaly); err != nil {
+		logger.WithError(err).Warn("Failed to send reorg alert")
+	}
+}
+
+// reorgAlertMessage summarizes an incident, listing up to reorgAlertRowLimit orphaned rows
+func reorgAlertMessage(incident *models.ReorgIncident) string {
+	rows := make([]string, 0, reorgAlertRowLimit)
+	for i, row := range incident.Affected {
+		if i == reorgAlertRowLimit {
+			rows = append(rows, fmt.Sprintf("and %d more", len(incident.Affected)-i))
+			break
+		}
+		rows = append(rows, fmt.Sprintf("%s#%d (%s)", row.Table, row.ID, row.TxHash))
+	}
+	return fmt.Sprintf("Reorg incident %d: %d %s transactions in blocks %d-%d left %s; orphaned %s",
+		incident.ID, len(incident.TxHashes), incident.EventType, incident.FromBlock, incident.ToBlock,
+		incident.IndexerTable, strings.Join(rows, ", "))
+}
+
+// reorgEventTypes lists the event types of reorgDerivedTables once each, in order
+func reorgEventTypes() []string {
+	var eventTypes []string
+	seen := make(map[string]bool)
+	for _, derived := range reorgDerivedTables {
+		if !seen[derived.eventType] {
+			seen[derived.eventType] = true
+			eventTypes = append(eventTypes, derived.eventType)
+		}
+	}
+	return eventTypes
+}
+
+// reorgTableName returns the reorg table Ponder keeps next to a canonical event table,
+// e.g. f243_reorg__sukuk_purchase for f243__sukuk_purchase
+func reorgTableName(canonical string) (string, bool) {
+	prefix, eventType, ok := strings.Cut(canonical, "__")
+	if !ok || strings.HasSuffix(prefix, "_reorg") {
+		return "", false
+	}
+	return prefix + "_reorg__" + eventType, true
+}
+
+// SetSyncLock makes every scheduled run take lock first, skipping it while another
+// instance holds it
+func (r *ReorgReconciler) SetSyncLock(lock *SyncLock) {
+	r.lock = lock
+}
+
+// Start reconciles every interval; a zero interval disables it
+func (r *ReorgReconciler) Start(ctx context.Context) {
+	if r.interval <= 0 {
+		logger.Info("Reorg reconciliation disabled")
+		return
+	}
+	logger.Info("Starting reorg reconciler")
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	go r.loop(ctx)
+}
+
+// Stop stops the scheduled reconciliation
+func (r *ReorgReconciler) Stop() {
+	if r.cancel != nil {
+		logger.Info("Stopping reorg reconciler")
+		r.cancel()
+	}
+}
+
+func (r *ReorgReconciler) loop(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.scheduledRun(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scheduledRun runs a reconciliation under the sync lock
+func (r *ReorgReconciler) scheduledRun(ctx context.Context) {
+	if r.lock != nil {
+		lease, err := r.lock.TryAcquire(ctx)
+		if errors.Is(err, ErrSyncLockHeld) {
+			return // Logged when the lock was tried
+		}
+		if err != nil {
+			logger.WithError(err).Error("Reorg reconciliation skipped")
+			return
+		}
+		defer lease.Release()
+	}
+	if _, err := r.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.WithError(err).Error("Reorg reconciliation failed")
+	}
+}
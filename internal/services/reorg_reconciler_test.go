@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestReorgTableName(t *testing.T) {
+	tests := []struct {
+		canonical string
+		want      string
+		ok        bool
+	}{
+		{"f243__sukuk_purchase", "f243_reorg__sukuk_purchase", true},
+		{"5eed__yield_claim", "5eed_reorg__yield_claim", true},
+		{"f243_reorg__sukuk_purchase", "", false},
+		{"sukuk_purchase", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := reorgTableName(tt.canonical)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("reorgTableName(%q) = %q, %v; want %q, %v", tt.canonical, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNewReorgIncident(t *testing.T) {
+	affected := []models.ReorgAffectedRow{
+		{Table: "sukuk_purchased_events", ID: 7, TxHash: "0xbb"},
+		{Table: "ledger_entries", ID: 12, TxHash: "0xbb"},
+		{Table: "sukuk_purchased_events", ID: 8, TxHash: "0xaa"},
+	}
+	blocks := map[string]retractedTx{
+		"0xaa": {TxHash: "0xaa", FromBlock: 1004, ToBlock: 1004},
+		"0xbb": {TxHash: "0xbb", FromBlock: 1002, ToBlock: 1003},
+		"0xcc": {TxHash: "0xcc", FromBlock: 990, ToBlock: 990}, // Retracted, but nothing was built from it
+	}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	incident := newReorgIncident("sukuk_purchase", "f243__sukuk_purchase", "f243_reorg__sukuk_purchase", affected, blocks, now)
+	if strings.Join(incident.TxHashes, ",") != "0xaa,0xbb" {
+		t.Errorf("Expected the tx hashes of the affected rows only, got %v", incident.TxHashes)
+	}
+	if incident.FromBlock != 1002 || incident.ToBlock != 1004 {
+		t.Errorf("Expected blocks 1002-1004, got %d-%d", incident.FromBlock, incident.ToBlock)
+	}
+	if !incident.DetectedAt.Equal(now) || incident.DetectedAt.Location() != time.UTC {
+		t.Errorf("Expected detection time in UTC, got %v", incident.DetectedAt)
+	}
+}
+
+func TestReorgAlertMessageCapsRows(t *testing.T) {
+	incident := &models.ReorgIncident{ID: 3, EventType: "sukuk_purchase", IndexerTable: "f243__sukuk_purchase", TxHashes: []string{"0xaa"}, FromBlock: 10, ToBlock: 10}
+	for i := 0; i < reorgAlertRowLimit+5; i++ {
+		incident.Affected = append(incident.Affected, models.ReorgAffectedRow{Table: "ledger_entries", ID: uint(i + 1), TxHash: "0xaa"})
+	}
+
+	message := reorgAlertMessage(incident)
+	if !strings.HasPrefix(message, "Reorg incident 3: 1 sukuk_purchase transactions in blocks 10-10 left f243__sukuk_purchase") {
+		t.Errorf("Unexpected summary: %s", message)
+	}
+	if strings.Count(message, "ledger_entries#") != reorgAlertRowLimit || !strings.HasSuffix(message, "and 5 more") {
+		t.Errorf("Expected %d rows listed and the rest counted, got %s", reorgAlertRowLimit, message)
+	}
+}
+
+// TestReorgReconcilerOrphansRetractedRows simulates a reorg with canonical and _reorg tables
+// that disagree. It requires a Postgres database, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func TestReorgReconcilerOrphansRetractedRows(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	// Stand-in canonical and reorg tables, pinned with overrides so discovery can't pick real ones
+	eventTypes := reorgEventTypes()
+	previous, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range eventTypes {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range previous {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range eventTypes {
+		for _, table := range []string{"fe20__" + eventType, "fe20_reorg__" + eventType} {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+			err := db.Exec("CREATE TABLE " + table + ` (id TEXT PRIMARY KEY, sukuk_address TEXT, amount NUMERIC(78,0),
+				block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+			if err != nil {
+				t.Fatalf("Failed to create %s: %v", table, err)
+			}
+			defer db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+		if err := models.SetIndexerTableOverride(db, eventType, "fe20__"+eventType); err != nil {
+			t.Fatalf("Failed to pin %s: %v", eventType, err)
+		}
+	}
+
+	const (
+		sukuk    = "0x00000000000000000000000000000000000fe020"
+		investor = "0x00000000000000000000000000000000000000aa"
+		idrx     = "0x00000000000000000000000000000000000000cc"
+	)
+	txHash := func(n int) string { return fmt.Sprintf("0x%064x", 0xfe2000+n) }
+	kept, retracted, ancient, remined := txHash(1), txHash(2), txHash(3), txHash(4)
+
+	seedIndexer := func(table, tx string, block int64) {
+		err := db.Exec("INSERT INTO "+table+` (id, sukuk_address, amount, block_number, tx_hash, timestamp) VALUES (?, ?, 1000, ?, ?, ?)`,
+			tx+"-0", sukuk, block, tx, 1700000000+block).Error
+		if err != nil {
+			t.Fatalf("Failed to seed %s: %v", table, err)
+		}
+	}
+	// kept survived the reorg; retracted was reorged out; ancient was reverted long before the
+	// lookback window; remined will come back in a later block
+	seedIndexer("fe20__sukuk_purchase", kept, 1000)
+	seedIndexer("fe20_reorg__sukuk_purchase", kept, 1000)
+	seedIndexer("fe20_reorg__sukuk_purchase", retracted, 1005)
+	seedIndexer("fe20_reorg__sukuk_purchase", remined, 1006)
+	seedIndexer("fe20_reorg__sukuk_purchase", ancient, 10)
+	seedIndexer("fe20__redemption_request", kept, 1001)
+	seedIndexer("fe20_reorg__redemption_request", kept, 1001)
+
+	hashes := []string{kept, retracted, ancient, remined}
+	defer db.Where("tx_hash IN ?", hashes).Delete(&models.LedgerEntry{})
+	defer db.Unscoped().Where("tx_hash IN ?", hashes).Delete(&models.SukukPurchased{})
+	defer db.Unscoped().Where("tx_hash IN ?", hashes).Delete(&models.RedemptionRequested{})
+	defer db.Where("indexer_table = ?", "fe20__sukuk_purchase").Delete(&models.ReorgIncident{})
+
+	timestamp := time.Unix(1700000000, 0).UTC()
+	for i, tx := range []string{kept, retracted, ancient, remined} {
+		purchase := &models.SukukPurchased{Buyer: investor, SukukAddress: sukuk, PaymentToken: idrx, Amount: "1000",
+			BlockNumber: uint64(1000 + i), TxHash: tx, Timestamp: timestamp}
+		if err := models.CreateSukukPurchaseEvent(db, purchase); err != nil {
+			t.Fatalf("Failed to store purchase %s: %v", tx, err)
+		}
+		err := models.CreateLedgerMovement(db, models.LedgerMovement{EventType: models.LedgerEventPurchase, From: investor, To: sukuk,
+			SukukAddress: sukuk, Token: idrx, Amount: "1000", TxHash: tx, Timestamp: timestamp})
+		if err != nil {
+			t.Fatalf("Failed to record movement %s: %v", tx, err)
+		}
+	}
+	request := &models.RedemptionRequested{User: investor, SukukAddress: sukuk, Amount: "1000", PaymentToken: idrx, TotalSupply: "1000",
+		BlockNumber: 1001, TxHash: kept, Timestamp: timestamp}
+	if err := models.CreateRedemptionRequestEvent(db, request); err != nil {
+		t.Fatalf("Failed to store redemption request: %v", err)
+	}
+
+	alerter := &recordingAlerter{}
+	reconciler := NewReorgReconciler(db, 100, 0, alerter)
+
+	incidents, err := reconciler.Run(ctx)
+	if err != nil {
+		t.Fatalf("Reconciliation failed: %v", err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("Expected one incident, got %+v", incidents)
+	}
+	incident := incidents[0]
+	if incident.EventType != "sukuk_purchase" || incident.ReorgTable != "fe20_reorg__sukuk_purchase" {
+		t.Errorf("Unexpected incident source: %+v", incident)
+	}
+	if strings.Join(incident.TxHashes, ",") != retracted+","+remined || incident.FromBlock != 1005 || incident.ToBlock != 1006 {
+		t.Errorf("Expected the retracted and remined transactions in blocks 1005-1006, got %v in %d-%d",
+			incident.TxHashes, incident.FromBlock, incident.ToBlock)
+	}
+	// Per transaction, the purchase event and both ledger legs
+	if len(incident.Affected) != 6 {
+		t.Errorf("Expected 6 orphaned rows, got %+v", incident.Affected)
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Kind != SyncAnomalyReorg || !strings.Contains(alerter.alerts[0].Message, retracted) {
+		t.Errorf("Expected one reorg alert naming the retracted transaction, got %+v", alerter.alerts)
+	}
+
+	status := func(model interface{}, tx string) models.EventStatus {
+		var s models.EventStatus
+		if err := db.Model(model).Where("tx_hash = ?", tx).Limit(1).Pluck("status", &s).Error; err != nil {
+			t.Fatalf("Failed to read status of %s: %v", tx, err)
+		}
+		return s
+	}
+	for tx, want := range map[string]models.EventStatus{
+		kept:      models.EventStatusConfirmed,
+		retracted: models.EventStatusOrphaned,
+		ancient:   models.EventStatusConfirmed, // Outside the lookback window
+		remined:   models.EventStatusOrphaned,
+	} {
+		if got := status(&models.SukukPurchased{}, tx); got != want {
+			t.Errorf("Expected purchase %s to be %s, got %s", tx, want, got)
+		}
+		if got := status(&models.LedgerEntry{}, tx); got != want {
+			t.Errorf("Expected ledger entries of %s to be %s, got %s", tx, want, got)
+		}
+	}
+	if got := status(&models.RedemptionRequested{}, kept); got != models.EventStatusConfirmed {
+		t.Errorf("Expected the redemption request to stay confirmed, got %s", got)
+	}
+
+	// Orphaned movements no longer count towards balances
+	balances, err := models.GetLedgerBalances(db, models.LedgerFilter{Account: sukuk})
+	if err != nil {
+		t.Fatalf("Failed to compute balances: %v", err)
+	}
+	if len(balances) != 1 || balances[0].Debits != "2000" {
+		t.Errorf("Expected the sukuk to have received 2000 from confirmed purchases, got %+v", balances)
+	}
+
+	// Rows already orphaned don't raise a second incident
+	if incidents, err := reconciler.Run(ctx); err != nil || len(incidents) != 0 {
+		t.Errorf("Expected no new incident, got %+v, %v", incidents, err)
+	}
+
+	// Re-mined in a later block, the transaction is canonical again and its rows are confirmed
+	seedIndexer("fe20__sukuk_purchase", remined, 1010)
+	if incidents, err := reconciler.Run(ctx); err != nil || len(incidents) != 0 {
+		t.Errorf("Expected no new incident, got %+v, %v", incidents, err)
+	}
+	if got := status(&models.SukukPurchased{}, remined); got != models.EventStatusConfirmed {
+		t.Errorf("Expected the re-mined purchase to be confirmed again, got %s", got)
+	}
+	if got := status(&models.LedgerEntry{}, retracted); got != models.EventStatusOrphaned {
+		t.Errorf("Expected the retracted ledger entries to stay orphaned, got %s", got)
+	}
+
+	listed, _, err := models.ListReorgIncidents(db, 200, 0)
+	if err != nil {
+		t.Fatalf("Failed to list incidents: %v", err)
+	}
+	found := false
+	for _, listedIncident := range listed {
+		if listedIncident.ID == incident.ID {
+			found = len(listedIncident.Affected) == 6 && len(listedIncident.TxHashes) == 2
+		}
+	}
+	if !found {
+		t.Errorf("Expected incident %d to be listed with its rows, got %+v", incident.ID, listed)
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultRetentionBatchSize bounds the rows removed per DELETE so no statement holds its locks for long
+const DefaultRetentionBatchSize = 5000
+
+// retentionEntity is the audit log entity of prune runs; the entity ID is the table
+const retentionEntity = "retention"
+
+// retentionWatermarkKeyPrefix namespaces, per table, the latest cutoff rows were pruned before
+const retentionWatermarkKeyPrefix = "retention_pruned_before:"
+
+// RetentionTables are the local event tables the retention job prunes, in run order.
+// Only processed rows are eligible: unprocessed events are kept regardless of age
+var RetentionTables = []string{
+	models.SukukPurchased{}.TableName(),
+	models.RedemptionRequested{}.TableName(),
+}
+
+// RetentionTableResult reports the prune of one table
+type RetentionTableResult struct {
+	Table         string     `json:"table"`
+	RetentionDays int        `json:"retention_days"`   // 0 keeps rows forever
+	Cutoff        *time.Time `json:"cutoff,omitempty"` // Processed events older than this are eligible
+	Eligible      int64      `json:"eligible"`
+	Deleted       int64      `json:"deleted"`
+}
+
+// RetentionResult reports a prune run
+type RetentionResult struct {
+	DryRun bool                   `json:"dry_run"`
+	Tables []RetentionTableResult `json:"tables"`
+}
+
+// RetentionService deletes processed event rows older than each table's retention
+type RetentionService struct {
+	db         *gorm.DB
+	days       map[string]int // Retention in days by table; tables missing or at 0 are kept
+	batchSize  int
+	batchPause time.Duration
+	interval   time.Duration
+	cancel     context.CancelFunc
+	now        func() time.Time
+}
+
+// NewRetentionService creates a retention job on db, deleting up to batchSize rows per
+// statement with batchPause between statements
+func NewRetentionService(db *gorm.DB, days map[string]int, batchSize int, batchPause, interval time.Duration) *RetentionService {
+	if batchSize <= 0 {
+		batchSize = DefaultRetentionBatchSize
+	}
+	return &RetentionService{
+		db:         db,
+		days:       days,
+		batchSize:  batchSize,
+		batchPause: batchPause,
+		interval:   interval,
+		now:        time.Now,
+	}
+}
+
+// NewDefaultRetentionService creates a retention job on the application database
+func NewDefaultRetentionService(days map[string]int, batchSize int, batchPause, interval time.Duration) *RetentionService {
+	return NewRetentionService(database.GetDB(), days, batchSize, batchPause, interval)
+}
+
+// Run counts the eligible rows of every table and, unless dryRun is set, deletes them
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) (*RetentionResult, error) {
+	result := &RetentionResult{DryRun: dryRun, Tables: make([]RetentionTableResult, 0, len(RetentionTables))}
+	for _, table := range RetentionTables {
+		tableResult := RetentionTableResult{Table: table, RetentionDays: s.days[table]}
+		if tableResult.RetentionDays > 0 {
+			cutoff := s.now().UTC().AddDate(0, 0, -tableResult.RetentionDays)
+			tableResult.Cutoff = &cutoff
+
+			if err := s.prune(ctx, &tableResult, dryRun); err != nil {
+				return nil, fmt.Errorf("failed to prune %s: %w", table, err)
+			}
+		}
+		result.Tables = append(result.Tables, tableResult)
+	}
+	return result, nil
+}
+
+// prune counts and deletes the processed rows of a table older than its cutoff, one batch at a time
+func (s *RetentionService) prune(ctx context.Context, result *RetentionTableResult, dryRun bool) error {
+	db := s.db.WithContext(ctx)
+	if err := db.Table(result.Table).Where("processed = ? AND timestamp < ?", true, *result.Cutoff).Count(&result.Eligible).Error; err != nil {
+		return err
+	}
+	if dryRun || result.Eligible == 0 {
+		return nil
+	}
+
+	// Reconciliation stops comparing events before the watermark, so it is moved first:
+	// a run cut short never leaves pruned rows reported as missing locally
+	if err := advanceRetentionWatermark(db, result.Table, *result.Cutoff); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %[1]s WHERE id IN (
+		SELECT id FROM %[1]s WHERE processed = true AND timestamp < ? ORDER BY id LIMIT ?)`, quoteIdentifier(result.Table))
+	for {
+		deleted := db.Exec(query, *result.Cutoff, s.batchSize)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Deleted += deleted.RowsAffected
+		if deleted.RowsAffected < int64(s.batchSize) {
+			return nil
+		}
+
+		select {
+		case <-time.After(s.batchPause):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RetentionWatermark returns the latest cutoff a table was pruned before; the zero time
+// when it was never pruned. Events older than the watermark may be gone from the table
+func RetentionWatermark(db *gorm.DB, table string) (time.Time, error) {
+	state, err := models.GetSystemState(db, retentionWatermarkKeyPrefix+table)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, state.Value)
+}
+
+// advanceRetentionWatermark moves the watermark of a table forward to cutoff, never back
+func advanceRetentionWatermark(db *gorm.DB, table string, cutoff time.Time) error {
+	current, err := RetentionWatermark(db, table)
+	if err != nil {
+		return err
+	}
+	if !cutoff.After(current) {
+		return nil
+	}
+	return models.SetSystemState(db, retentionWatermarkKeyPrefix+table, cutoff.UTC().Format(time.RFC3339))
+}
+
+// RecordRetentionAudit writes one audit entry per table a run deleted rows from
+func RecordRetentionAudit(db *gorm.DB, actor string, result *RetentionResult) error {
+	if result.DryRun {
+		return nil
+	}
+	for _, table := range result.Tables {
+		if table.Deleted == 0 {
+			continue
+		}
+		if err := models.RecordAudit(db, models.AuditActionDelete, retentionEntity, table.Table, actor, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start prunes every interval; a zero interval leaves it to the admin endpoint
+func (s *RetentionService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		logger.Info("Retention schedule disabled")
+		return
+	}
+	logger.Info("Starting retention service")
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.pruneLoop(ctx)
+}
+
+// Stop stops the scheduled prune
+func (s *RetentionService) Stop() {
+	if s.cancel != nil {
+		logger.Info("Stopping retention service")
+		s.cancel()
+	}
+}
+
+func (s *RetentionService) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !Settings().GetBool(SettingRetentionEnabled, true) {
+				logger.Debug("Retention disabled by runtime setting, skipping prune")
+				continue
+			}
+			result, err := s.Run(ctx, false)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.WithError(err).Error("Retention prune failed")
+				}
+				continue
+			}
+			if err := RecordRetentionAudit(s.db, "system:retention", result); err != nil {
+				logger.WithError(err).Warn("Failed to record retention audit")
+			}
+			for _, table := range result.Tables {
+				if table.Deleted > 0 {
+					logger.WithFields(map[string]interface{}{
+						"table":   table.Table,
+						"deleted": table.Deleted,
+						"cutoff":  table.Cutoff,
+					}).Info("Pruned processed events")
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+// TestRetentionPrunesOnlyOldProcessedEvents requires a reachable Postgres, see testutil.Open
+func TestRetentionPrunesOnlyOldProcessedEvents(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	const sukuk = "0x00000000000000000000000000000000000De7e1"
+	const buyer = "0x00000000000000000000000000000000000000aa"
+	purchaseTable := models.SukukPurchased{}.TableName()
+	redemptionTable := models.RedemptionRequested{}.TableName()
+	defer db.Unscoped().Where("sukuk_address = LOWER(?)", sukuk).Delete(&models.SukukPurchased{})
+	defer db.Unscoped().Where("sukuk_address = LOWER(?)", sukuk).Delete(&models.RedemptionRequested{})
+	defer db.Where("key IN ?", []string{retentionWatermarkKeyPrefix + purchaseTable, retentionWatermarkKeyPrefix + redemptionTable}).Delete(&models.SystemState{})
+
+	now := time.Now().UTC()
+	old, recent := now.AddDate(0, 0, -100), now.AddDate(0, 0, -10)
+	txHash := func(n int) string { return fmt.Sprintf("0x%064x", 0xde7e000+n) }
+	seeded := 0
+	purchase := func(timestamp time.Time, processed bool) {
+		seeded++
+		event := models.SukukPurchased{
+			Buyer: buyer, SukukAddress: sukuk, PaymentToken: "0x00000000000000000000000000000000000000cc",
+			Amount: "1000", BlockNumber: uint64(seeded), TxHash: txHash(seeded), Timestamp: timestamp, Processed: processed,
+		}
+		if err := models.CreateSukukPurchaseEvent(db, &event); err != nil {
+			t.Fatalf("Failed to seed purchase: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		purchase(old, true) // Eligible, in three batches of two
+	}
+	purchase(old, false)   // Unprocessed, kept regardless of age
+	purchase(recent, true) // Within retention
+
+	redemption := models.RedemptionRequested{
+		User: buyer, SukukAddress: sukuk, Amount: "1000", PaymentToken: "0x00000000000000000000000000000000000000cc",
+		TotalSupply: "1000", TxHash: txHash(100), Timestamp: old, Processed: true,
+	}
+	if err := models.CreateRedemptionRequestEvent(db, &redemption); err != nil {
+		t.Fatalf("Failed to seed redemption request: %v", err)
+	}
+
+	// Redemption requests have no retention configured and are kept
+	service := NewRetentionService(db, map[string]int{purchaseTable: 30}, 2, time.Millisecond, 0)
+	remaining := func() (purchases, processed, redemptions int64) {
+		db.Model(&models.SukukPurchased{}).Where("sukuk_address = LOWER(?)", sukuk).Count(&purchases)
+		db.Model(&models.SukukPurchased{}).Where("sukuk_address = LOWER(?) AND processed", sukuk).Count(&processed)
+		db.Model(&models.RedemptionRequested{}).Where("sukuk_address = LOWER(?)", sukuk).Count(&redemptions)
+		return
+	}
+
+	dryRun, err := service.Run(ctx, true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if dryRun.Tables[0].Eligible < 5 || dryRun.Tables[0].Deleted != 0 || dryRun.Tables[1].Cutoff != nil {
+		t.Errorf("Unexpected dry run: %+v", dryRun.Tables)
+	}
+	if purchases, _, _ := remaining(); purchases != 7 {
+		t.Fatalf("Expected the dry run to keep all 7 purchases, got %d", purchases)
+	}
+
+	result, err := service.Run(ctx, false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if result.Tables[0].Deleted < 5 {
+		t.Errorf("Expected at least the 5 seeded rows deleted, got %+v", result.Tables[0])
+	}
+	purchases, processed, redemptions := remaining()
+	if purchases != 2 || processed != 1 || redemptions != 1 {
+		t.Errorf("Expected the unprocessed and recent purchases and the redemption kept, got %d purchases (%d processed) and %d redemptions",
+			purchases, processed, redemptions)
+	}
+
+	watermark, err := RetentionWatermark(db, purchaseTable)
+	if err != nil || !watermark.Equal(result.Tables[0].Cutoff.Truncate(time.Second)) {
+		t.Errorf("Expected the watermark at the cutoff %v, got %v (%v)", result.Tables[0].Cutoff, watermark, err)
+	}
+}
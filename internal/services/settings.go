@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SettingType is how the value of a runtime setting is parsed
+type SettingType string
+
+const (
+	SettingTypeInt      SettingType = "int"      // Positive integer
+	SettingTypeBool     SettingType = "bool"     // true/false, also 1/0, on/off, yes/no
+	SettingTypeDuration SettingType = "duration" // Positive Go duration such as 30s, or whole seconds
+)
+
+// Runtime setting keys
+const (
+	SettingActivityFeedDefaultLimit = "activity_feed.default_limit"
+	SettingReadOnly                 = "api.read_only"
+	SettingSyncEnabled              = "sync.enabled"
+	SettingSyncInterval             = "sync.interval"
+	SettingOrderExpiryEnabled       = "jobs.order_expiry.enabled"
+	SettingUploadCleanupEnabled     = "jobs.upload_cleanup.enabled"
+	SettingRetentionEnabled         = "jobs.retention.enabled"
+	SettingCachePortfolioTTL        = "cache.portfolio_ttl"
+	SettingCacheMetadataTTL         = "cache.metadata_ttl"
+	SettingCacheStatsTTL            = "cache.stats_ttl"
+	SettingCacheActivitiesTTL       = "cache.activities_ttl"
+	SettingCouponGracePeriod        = "coupon_schedule.grace_period"
+	SettingSukukActivitiesLimit     = "sukuk_metadata.activities_limit"
+)
+
+// settingStateKeyPrefix namespaces runtime settings among the other system_states rows
+const settingStateKeyPrefix = "setting:"
+
+var (
+	// ErrUnknownSetting is returned for a key no setting is registered under
+	ErrUnknownSetting = errors.New("unknown setting")
+	// ErrInvalidSetting is returned for a value that does not parse as the setting's type
+	ErrInvalidSetting = errors.New("invalid setting value")
+)
+
+// SettingDefinition describes a runtime setting. Settings have no stored default: an
+// unset setting falls back to the default its reader passes, normally from configuration
+type SettingDefinition struct {
+	Key         string      `json:"key"`
+	Type        SettingType `json:"type"`
+	Description string      `json:"description"`
+}
+
+var (
+	settingSchemaMu sync.RWMutex
+	settingSchema   = make(map[string]SettingDefinition)
+)
+
+func init() {
+	for _, definition := range []SettingDefinition{
+		{SettingActivityFeedDefaultLimit, SettingTypeInt, "Activity feed page size when ?limit= is omitted (default 20, capped at 100)"},
+		{SettingReadOnly, SettingTypeBool, "Reject mutating API requests except settings updates (default false; APP_READ_ONLY replicas are always read-only)"},
+		{SettingSyncEnabled, SettingTypeBool, "Run scheduled metadata sync cycles (default true; manual syncs still run)"},
+		{SettingSyncInterval, SettingTypeDuration, "Interval between scheduled metadata sync cycles (default SYNC_INTERVAL)"},
+		{SettingOrderExpiryEnabled, SettingTypeBool, "Run scheduled expiry sweeps of unpaid orders (default true)"},
+		{SettingUploadCleanupEnabled, SettingTypeBool, "Run scheduled orphaned upload cleanup (default true)"},
+		{SettingRetentionEnabled, SettingTypeBool, "Run scheduled pruning of processed events (default true)"},
+		{SettingCachePortfolioTTL, SettingTypeDuration, "TTL for portfolio responses (default CACHE_PORTFOLIO_TTL)"},
+		{SettingCacheMetadataTTL, SettingTypeDuration, "TTL for sukuk metadata lists (default CACHE_METADATA_TTL)"},
+		{SettingCacheStatsTTL, SettingTypeDuration, "TTL for redemption statistics (default CACHE_STATS_TTL)"},
+		{SettingCacheActivitiesTTL, SettingTypeDuration, "TTL for the first page of the activity feed and sukuk activity stats (default CACHE_ACTIVITIES_TTL)"},
+		{SettingCouponGracePeriod, SettingTypeDuration, "How far a yield distribution may land from a scheduled coupon and still pay it (default 168h)"},
+		{SettingSukukActivitiesLimit, SettingTypeInt, "Latest activities per sukuk on sukuk metadata list and detail responses when ?activities_limit= is omitted (default ACTIVITY_LATEST_LIMIT, capped at 50)"},
+	} {
+		RegisterSetting(definition)
+	}
+}
+
+// RegisterSetting adds a setting to the schema, replacing any definition with the same key
+func RegisterSetting(definition SettingDefinition) {
+	settingSchemaMu.Lock()
+	defer settingSchemaMu.Unlock()
+	settingSchema[definition.Key] = definition
+}
+
+// SettingDefinitions returns every registered setting, ordered by key
+func SettingDefinitions() []SettingDefinition {
+	settingSchemaMu.RLock()
+	defer settingSchemaMu.RUnlock()
+
+	definitions := make([]SettingDefinition, 0, len(settingSchema))
+	for _, definition := range settingSchema {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Key < definitions[j].Key
+	})
+	return definitions
+}
+
+func lookupSetting(key string) (SettingDefinition, bool) {
+	settingSchemaMu.RLock()
+	defer settingSchemaMu.RUnlock()
+	definition, ok := settingSchema[key]
+	return definition, ok
+}
+
+// NormalizeSetting validates value against the schema of key and returns its canonical
+// form, e.g. "on" becomes "true" and "90" becomes "1m30s". An empty value is returned as is
+func NormalizeSetting(key, value string) (string, error) {
+	definition, ok := lookupSetting(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	if value == "" {
+		return "", nil
+	}
+
+	switch definition.Type {
+	case SettingTypeInt:
+		n, err := parseSettingInt(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a positive integer", ErrInvalidSetting, key)
+		}
+		return strconv.Itoa(n), nil
+	case SettingTypeBool:
+		b, err := parseSettingBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be true or false", ErrInvalidSetting, key)
+		}
+		return strconv.FormatBool(b), nil
+	case SettingTypeDuration:
+		d, err := parseSettingDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a positive duration such as 30s", ErrInvalidSetting, key)
+		}
+		return d.String(), nil
+	}
+	return "", fmt.Errorf("%w: %s has unsupported type %s", ErrInvalidSetting, key, definition.Type)
+}
+
+func parseSettingInt(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%d is not positive", n)
+	}
+	return n, nil
+}
+
+func parseSettingBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+func parseSettingDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		// Bare numbers are seconds
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, err
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s is not positive", d)
+	}
+	return d, nil
+}
+
+// SaveSetting validates and stores a setting in db; an empty value removes it so readers
+// fall back to their default. Running instances pick it up on their next Reload
+func SaveSetting(db *gorm.DB, key, value string) error {
+	normalized, err := NormalizeSetting(key, value)
+	if err != nil {
+		return err
+	}
+	if normalized == "" {
+		return db.Where("key = ?", settingStateKeyPrefix+key).Delete(&models.SystemState{}).Error
+	}
+	return models.SetSystemState(db, settingStateKeyPrefix+key, normalized)
+}
+
+// SettingValue is a registered setting with its stored value, if any
+type SettingValue struct {
+	SettingDefinition
+	Value string `json:"value,omitempty"` // Empty while the reader's default applies
+}
+
+// SettingsService serves runtime settings from memory, reloading them from system_states
+// every refresh interval so changes reach every instance without a restart
+type SettingsService struct {
+	db       *gorm.DB
+	interval time.Duration
+	cancel   context.CancelFunc
+
+	mu       sync.RWMutex
+	values   map[string]string // Stored values by key
+	watchers map[*settingWatcher]struct{}
+}
+
+// settingWatcher is signalled when one of its keys changes
+type settingWatcher struct {
+	keys    map[string]bool
+	changes chan struct{}
+}
+
+// NewSettingsService creates a service reading settings from db every interval
+// Without a database every getter returns its default
+func NewSettingsService(db *gorm.DB, interval time.Duration) *SettingsService {
+	return &SettingsService{
+		db:       db,
+		interval: interval,
+		values:   make(map[string]string),
+		watchers: make(map[*settingWatcher]struct{}),
+	}
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   = NewSettingsService(nil, 0)
+)
+
+// Settings returns the shared settings service
+func Settings() *SettingsService {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// SetDefaultSettings replaces the shared settings service, e.g. with one backed by the database
+func SetDefaultSettings(s *SettingsService) {
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	settings = s
+}
+
+// Start reloads settings every refresh interval until ctx is cancelled or Stop is called
+func (s *SettingsService) Start(ctx context.Context) {
+	if s.db == nil || s.interval <= 0 {
+		logger.Info("Runtime settings refresh disabled")
+		return
+	}
+	logger.Info("Starting runtime settings service")
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.refreshLoop(ctx)
+}
+
+// Stop stops reloading settings
+func (s *SettingsService) Stop() {
+	if s.cancel != nil {
+		logger.Info("Stopping runtime settings service")
+		s.cancel()
+	}
+}
+
+func (s *SettingsService) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil && ctx.Err() == nil {
+				logger.WithError(err).Error("Failed to reload runtime settings")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload reads every stored setting and notifies watchers of the ones that changed
+// Rows for keys that are no longer registered are ignored
+func (s *SettingsService) Reload(ctx context.Context) error {
+	if s.db == nil {
+		return nil
+	}
+
+	var states []models.SystemState
+	if err := s.db.WithContext(ctx).Where("key LIKE ?", settingStateKeyPrefix+"%").Find(&states).Error; err != nil {
+		return fmt.Errorf("failed to load runtime settings: %w", err)
+	}
+
+	values := make(map[string]string, len(states))
+	for _, state := range states {
+		key := strings.TrimPrefix(state.Key, settingStateKeyPrefix)
+		if _, ok := lookupSetting(key); ok {
+			values[key] = state.Value
+		}
+	}
+	s.apply(values)
+	return nil
+}
+
+// apply replaces the in-memory values and signals the watchers of every changed key
+func (s *SettingsService) apply(values map[string]string) {
+	s.mu.Lock()
+	var changed []string
+	for key, value := range values {
+		if previous, ok := s.values[key]; !ok || previous != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range s.values {
+		if _, ok := values[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	s.values = values
+
+	var notify []*settingWatcher
+	for watcher := range s.watchers {
+		for _, key := range changed {
+			if watcher.keys[key] {
+				notify = append(notify, watcher)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Strings(changed)
+	for _, key := range changed {
+		logger.WithFields(map[string]interface{}{
+			"key":   key,
+			"value": values[key],
+		}).Info("Runtime setting changed")
+	}
+
+	// Signals coalesce: a watcher that has not caught up yet already has one pending
+	for _, watcher := range notify {
+		select {
+		case watcher.changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Watch returns a channel signalled whenever one of keys changes, until ctx is done
+// Watchers read the new value through the getters
+func (s *SettingsService) Watch(ctx context.Context, keys ...string) <-chan struct{} {
+	watcher := &settingWatcher{keys: make(map[string]bool, len(keys)), changes: make(chan struct{}, 1)}
+	for _, key := range keys {
+		watcher.keys[key] = true
+	}
+
+	s.mu.Lock()
+	s.watchers[watcher] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.watchers, watcher)
+		s.mu.Unlock()
+	}()
+	return watcher.changes
+}
+
+// value returns the stored value of key, if any
+func (s *SettingsService) value(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// GetInt returns an int setting, or def while it is unset or unparseable
+func (s *SettingsService) GetInt(key string, def int) int {
+	if value, ok := s.value(key); ok {
+		if n, err := parseSettingInt(value); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// GetBool returns a bool setting, or def while it is unset or unparseable
+func (s *SettingsService) GetBool(key string, def bool) bool {
+	if value, ok := s.value(key); ok {
+		if b, err := parseSettingBool(value); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// GetDuration returns a duration setting, or def while it is unset or unparseable
+func (s *SettingsService) GetDuration(key string, def time.Duration) time.Duration {
+	if value, ok := s.value(key); ok {
+		if d, err := parseSettingDuration(value); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// List returns every registered setting with its stored value, ordered by key
+func (s *SettingsService) List() []SettingValue {
+	definitions := SettingDefinitions()
+	result := make([]SettingValue, len(definitions))
+	for i, definition := range definitions {
+		value, _ := s.value(definition.Key)
+		result[i] = SettingValue{SettingDefinition: definition, Value: value}
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/testutil"
+)
+
+func TestNormalizeSettingCoercesValues(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected string
+		err      error
+	}{
+		{SettingActivityFeedDefaultLimit, " 50 ", "50", nil},
+		{SettingActivityFeedDefaultLimit, "0", "", ErrInvalidSetting},
+		{SettingActivityFeedDefaultLimit, "ten", "", ErrInvalidSetting},
+		{SettingSyncEnabled, "on", "true", nil},
+		{SettingSyncEnabled, "0", "false", nil},
+		{SettingSyncEnabled, "No", "false", nil},
+		{SettingSyncEnabled, "maybe", "", ErrInvalidSetting},
+		{SettingSyncInterval, "90", "1m30s", nil},
+		{SettingSyncInterval, "1500ms", "1.5s", nil},
+		{SettingSyncInterval, "-5s", "", ErrInvalidSetting},
+		{SettingSyncInterval, "", "", nil},
+		{"sync.intervall", "5s", "", ErrUnknownSetting},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeSetting(tt.key, tt.value)
+		if !errors.Is(err, tt.err) || got != tt.expected {
+			t.Errorf("NormalizeSetting(%q, %q) = %q, %v; expected %q, %v", tt.key, tt.value, got, err, tt.expected, tt.err)
+		}
+	}
+}
+
+func TestSettingsGettersFallBackToDefaults(t *testing.T) {
+	s := NewSettingsService(nil, 0)
+	if s.GetInt(SettingActivityFeedDefaultLimit, 20) != 20 || !s.GetBool(SettingSyncEnabled, true) || s.GetDuration(SettingSyncInterval, 5*time.Second) != 5*time.Second {
+		t.Error("Expected unset settings to return their defaults")
+	}
+
+	// Rows edited by hand into something unparseable are ignored rather than crashing readers
+	s.apply(map[string]string{
+		SettingActivityFeedDefaultLimit: "lots",
+		SettingSyncEnabled:              "no",
+		SettingSyncInterval:             "2m",
+	})
+	if got := s.GetInt(SettingActivityFeedDefaultLimit, 20); got != 20 {
+		t.Errorf("Expected an unparseable int to fall back to 20, got %d", got)
+	}
+	if s.GetBool(SettingSyncEnabled, true) {
+		t.Error("Expected sync.enabled=no to read as false")
+	}
+	if got := s.GetDuration(SettingSyncInterval, 5*time.Second); got != 2*time.Minute {
+		t.Errorf("Expected sync.interval 2m, got %s", got)
+	}
+}
+
+func TestSettingsWatchersSeeLiveUpdates(t *testing.T) {
+	s := NewSettingsService(nil, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interval := s.Watch(ctx, SettingSyncInterval)
+	limit := s.Watch(ctx, SettingActivityFeedDefaultLimit)
+
+	s.apply(map[string]string{SettingSyncInterval: "10s"})
+	select {
+	case <-interval:
+	default:
+		t.Fatal("Expected the sync.interval watcher to be signalled")
+	}
+	if got := s.GetDuration(SettingSyncInterval, time.Second); got != 10*time.Second {
+		t.Errorf("Expected the new interval after the signal, got %s", got)
+	}
+	select {
+	case <-limit:
+		t.Error("Expected the activity feed watcher not to be signalled by another key")
+	default:
+	}
+
+	// Reloading the same values is not a change; removing one is
+	s.apply(map[string]string{SettingSyncInterval: "10s"})
+	select {
+	case <-interval:
+		t.Error("Expected no signal for an unchanged value")
+	default:
+	}
+	s.apply(map[string]string{})
+	select {
+	case <-interval:
+	default:
+		t.Fatal("Expected a signal when the setting is removed")
+	}
+	if got := s.GetDuration(SettingSyncInterval, time.Second); got != time.Second {
+		t.Errorf("Expected the default once removed, got %s", got)
+	}
+
+	// Cancelled watchers are dropped
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.RLock()
+		remaining := len(s.watchers)
+		s.mu.RUnlock()
+		if remaining == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected watchers to be removed after cancel, %d left", remaining)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestSettingsReloadFromSystemState requires a reachable Postgres, see testutil.Open
+func TestSettingsReloadFromSystemState(t *testing.T) {
+	db := testutil.DB(t)
+	defer SaveSetting(db, SettingActivityFeedDefaultLimit, "")
+
+	s := NewSettingsService(db, time.Minute)
+	if err := SaveSetting(db, SettingActivityFeedDefaultLimit, "35"); err != nil {
+		t.Fatalf("Failed to save setting: %v", err)
+	}
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if got := s.GetInt(SettingActivityFeedDefaultLimit, 20); got != 35 {
+		t.Errorf("Expected the stored limit 35, got %d", got)
+	}
+
+	if err := SaveSetting(db, SettingActivityFeedDefaultLimit, ""); err != nil {
+		t.Fatalf("Failed to remove setting: %v", err)
+	}
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if got := s.GetInt(SettingActivityFeedDefaultLimit, 20); got != 20 {
+		t.Errorf("Expected the default once removed, got %d", got)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// SuitabilitySignatureMaxAge is how long a signed acknowledgement message stays valid
+const SuitabilitySignatureMaxAge = 10 * time.Minute
+
+// suitabilityMessageTitle is the first line of the message a wallet signs to acknowledge a sukuk's risks
+const suitabilityMessageTitle = "I acknowledge the risks of this Sukuk as described in its prospectus"
+
+// ErrInvalidSuitabilityMessage is returned for acknowledgement messages not made by SuitabilityMessage
+// for the acknowledged address, sukuk and prospectus version, or no longer valid
+var ErrInvalidSuitabilityMessage = errors.New("invalid acknowledgement message")
+
+// SuitabilityMessage is the message a wallet signs with personal_sign to acknowledge a sukuk's
+// risks, e.g.
+//
+//	I acknowledge the risks of this Sukuk as described in its prospectus
+//	Address: 0xabc...
+//	Sukuk: 7
+//	Prospectus version: 2
+//	Issued at: 2025-06-01T08:00:00Z
+//
+// The prospectus version is 0 for a sukuk without a prospectus
+func SuitabilityMessage(address string, sukukID uint, documentVersion int, issuedAt time.Time) string {
+	return fmt.Sprintf("%s\nAddress: %s\nSukuk: %d\nProspectus version: %d\nIssued at: %s",
+		suitabilityMessageTitle, strings.ToLower(address), sukukID, documentVersion, issuedAt.UTC().Format(time.RFC3339))
+}
+
+// VerifySuitabilitySignature checks that message is the acknowledgement message of address for
+// the sukuk and prospectus version, issued within SuitabilitySignatureMaxAge of now and signed
+// by address
+func VerifySuitabilitySignature(address string, sukukID uint, documentVersion int, message, signature string, now time.Time) error {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+	if len(lines) != 5 || lines[0] != suitabilityMessageTitle {
+		return fmt.Errorf("%w: expected the format of SuitabilityMessage", ErrInvalidSuitabilityMessage)
+	}
+	fields := make(map[string]string, 4)
+	for _, line := range lines[1:] {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return fmt.Errorf("%w: expected the format of SuitabilityMessage", ErrInvalidSuitabilityMessage)
+		}
+		fields[name] = value
+	}
+	if !strings.EqualFold(fields["Address"], address) {
+		return fmt.Errorf("%w: signed for another address", ErrInvalidSuitabilityMessage)
+	}
+	if fields["Sukuk"] != strconv.FormatUint(uint64(sukukID), 10) {
+		return fmt.Errorf("%w: signed for another sukuk", ErrInvalidSuitabilityMessage)
+	}
+	if fields["Prospectus version"] != strconv.Itoa(documentVersion) {
+		return fmt.Errorf("%w: signed for prospectus version %s, the current version is %d", ErrInvalidSuitabilityMessage, fields["Prospectus version"], documentVersion)
+	}
+	issuedAt, err := time.Parse(time.RFC3339, fields["Issued at"])
+	if err != nil {
+		return fmt.Errorf("%w: issued at must be RFC3339", ErrInvalidSuitabilityMessage)
+	}
+	if age := now.Sub(issuedAt); age > SuitabilitySignatureMaxAge || age < -time.Minute {
+		return fmt.Errorf("%w: expired", ErrInvalidSuitabilityMessage)
+	}
+
+	return utils.VerifyPersonalSignature(address, message, signature)
+}
+
+// SuitabilityRequiredError is returned by CheckSuitability when an investor has not
+// acknowledged the current prospectus of a sukuk
+type SuitabilityRequiredError struct {
+	Status models.SuitabilityStatus
+}
+
+func (e *SuitabilityRequiredError) Error() string {
+	if e.Status.State == models.SuitabilityStale {
+		return fmt.Sprintf("risk acknowledgement of sukuk %d is for an outdated prospectus", e.Status.SukukMetadataID)
+	}
+	return fmt.Sprintf("risk acknowledgement of sukuk %d is missing", e.Status.SukukMetadataID)
+}
+
+// CheckSuitability returns a *SuitabilityRequiredError unless address acknowledged the risks of
+// the sukuk for its current prospectus. Every purchase path must check it before accepting a
+// purchase
+func CheckSuitability(ctx context.Context, db *gorm.DB, address string, sukukID uint) error {
+	status, err := models.GetSuitabilityStatus(db.WithContext(ctx), address, sukukID)
+	if err != nil {
+		return fmt.Errorf("failed to load risk acknowledgement: %w", err)
+	}
+	if status.State != models.SuitabilityAcknowledged {
+		return &SuitabilityRequiredError{Status: *status}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestVerifySuitabilitySignature(t *testing.T) {
+	key, _ := secp256k1.GeneratePrivateKey()
+	address := utils.AddressFromPublicKey(key.PubKey())
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	message := SuitabilityMessage(address, 7, 2, now.Add(-time.Minute))
+	signature := personalSign(key, message)
+
+	if err := VerifySuitabilitySignature(address, 7, 2, message, signature, now); err != nil {
+		t.Fatalf("Expected a valid acknowledgement, got %v", err)
+	}
+
+	// A signature over an earlier prospectus version doesn't acknowledge the amendment
+	if err := VerifySuitabilitySignature(address, 7, 3, message, signature, now); !errors.Is(err, ErrInvalidSuitabilityMessage) {
+		t.Errorf("Expected an outdated prospectus version to be rejected, got %v", err)
+	}
+	if err := VerifySuitabilitySignature(address, 8, 2, message, signature, now); !errors.Is(err, ErrInvalidSuitabilityMessage) {
+		t.Errorf("Expected an acknowledgement of another sukuk to be rejected, got %v", err)
+	}
+	if err := VerifySuitabilitySignature(address, 7, 2, message, signature, now.Add(SuitabilitySignatureMaxAge)); !errors.Is(err, ErrInvalidSuitabilityMessage) {
+		t.Errorf("Expected an old message to be rejected, got %v", err)
+	}
+
+	other, _ := secp256k1.GeneratePrivateKey()
+	if err := VerifySuitabilitySignature(address, 7, 2, message, personalSign(other, message), now); !errors.Is(err, utils.ErrSignatureMismatch) {
+		t.Errorf("Expected another wallet's signature to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SukukActivityStats summarizes the onchain activity of one sukuk
+type SukukActivityStats struct {
+	InvestorCount   int64      // Distinct buyers across purchase events
+	FirstPurchaseAt *time.Time // Nil until the first purchase
+	LastActivityAt  *time.Time // Latest purchase or redemption request; nil without either
+}
+
+// sukukActivityStatsRow is one grouped row of the stats query; timestamps are unix seconds
+type sukukActivityStatsRow struct {
+	SukukAddress  string `gorm:"column:sukuk_address"`
+	InvestorCount int64  `gorm:"column:investor_count"`
+	FirstPurchase *int64 `gorm:"column:first_purchase"`
+	LastActivity  *int64 `gorm:"column:last_activity"`
+}
+
+// GetSukukActivityStats returns investor count and first/last activity of every sukuk in one
+// grouped query, keyed by lowercase address. Sukuk without activity get zero stats
+func (s *IndexerQueryService) GetSukukActivityStats(ctx context.Context, sukukAddresses []string) (map[string]SukukActivityStats, error) {
+	stats := make(map[string]SukukActivityStats, len(sukukAddresses))
+	if len(sukukAddresses) == 0 {
+		return stats, nil
+	}
+
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sukuk_purchase table: %w", err)
+	}
+	redemptionTable, err := s.tableService.GetLatestTableForEvent("redemption_request")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemption_request table: %w", err)
+	}
+
+	// Activity covers the same events as latest_activities: purchases and redemption requests
+	query := fmt.Sprintf(`SELECT sukuk_address,
+			COUNT(DISTINCT buyer) AS investor_count,
+			MIN(timestamp) FILTER (WHERE buyer IS NOT NULL) AS first_purchase,
+			MAX(timestamp) AS last_activity
+		FROM (
+			SELECT LOWER(sukuk_address) AS sukuk_address, LOWER(buyer) AS buyer, timestamp FROM %s WHERE sukuk_address IN ?
+			UNION ALL
+			SELECT LOWER(sukuk_address), NULL, timestamp FROM %s WHERE sukuk_address IN ?
+		) activity
+		GROUP BY sukuk_address`, quoteIdentifier(purchaseTable), quoteIdentifier(redemptionTable))
+
+	var rows []sukukActivityStatsRow
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Raw(query, sukukAddresses, sukukAddresses).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sukuk activity stats: %w", err)
+	}
+
+	for _, address := range sukukAddresses {
+		stats[strings.ToLower(address)] = SukukActivityStats{}
+	}
+	for _, row := range rows {
+		stats[row.SukukAddress] = row.toStats()
+	}
+	return stats, nil
+}
+
+func (r sukukActivityStatsRow) toStats() SukukActivityStats {
+	stats := SukukActivityStats{InvestorCount: r.InvestorCount}
+	if r.FirstPurchase != nil {
+		first := time.Unix(*r.FirstPurchase, 0)
+		stats.FirstPurchaseAt = &first
+	}
+	if r.LastActivity != nil {
+		last := time.Unix(*r.LastActivity, 0)
+		stats.LastActivityAt = &last
+	}
+	return stats
+}
+
+// GetPurchaseTotalsBySukuk sums the purchases of every sukuk in one grouped query, keyed by
+// lowercase address, as raw integer strings. Sukuk without purchases get "0"
+func (s *IndexerQueryService) GetPurchaseTotalsBySukuk(ctx context.Context, sukukAddresses []string) (map[string]string, error) {
+	totals := make(map[string]string, len(sukukAddresses))
+	if len(sukukAddresses) == 0 {
+		return totals, nil
+	}
+
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	addresses := make([]string, len(sukukAddresses))
+	for i, address := range sukukAddresses {
+		addresses[i] = strings.ToLower(address)
+		totals[addresses[i]] = "0"
+	}
+	purchased, err := s.getTotalsBySukuk(ctx, "sukuk_purchase", addresses, "")
+	if err != nil {
+		return nil, err
+	}
+	for address, total := range purchased {
+		totals[address] = total
+	}
+	return totals, nil
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AvailabilityExceededError is returned by ReserveOrder when an amount is more than a sukuk has left
+type AvailabilityExceededError struct {
+	Requested string
+	Remaining string
+}
+
+func (e *AvailabilityExceededError) Error() string {
+	return fmt.Sprintf("amount %s exceeds the remaining capacity of %s", e.Requested, e.Remaining)
+}
+
+// PurchaseTotaler sums the indexed purchases of a sukuk
+type PurchaseTotaler interface {
+	GetTotalPurchased(ctx context.Context, sukukAddress string) (string, error)
+}
+
+// SukukAvailabilityService compares a sukuk's purchases against its kuota_nasional
+type SukukAvailabilityService struct {
+	db        *gorm.DB
+	purchases PurchaseTotaler
+	now       func() time.Time
+}
+
+// NewSukukAvailabilityService creates an availability service reading purchases from the given source
+func NewSukukAvailabilityService(db *gorm.DB, purchases PurchaseTotaler) *SukukAvailabilityService {
+	return &SukukAvailabilityService{
+		db:        db,
+		purchases: purchases,
+		now:       time.Now,
+	}
+}
+
+// Availability returns the cap, purchases and purchase period state of a sukuk
+func (s *SukukAvailabilityService) Availability(ctx context.Context, sukuk *models.SukukMetadata) (*models.SukukAvailability, error) {
+	tokens, err := models.GetAllPaymentTokens(s.db.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payment tokens: %w", err)
+	}
+	decimals := NewTokenFormatter(tokens).Decimals(sukuk.ContractAddress)
+
+	purchased, err := s.purchases.GetTotalPurchased(ctx, sukuk.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildAvailability(sukuk, decimals, purchased, s.now())
+}
+
+// ReserveOrder creates order when its token amount fits in what the sukuk has left after its
+// purchases and outstanding orders, and returns an *AvailabilityExceededError otherwise. The
+// sukuk row stays locked until the order is inserted, so concurrent orders can't both take the
+// last of the capacity. Sukuk without a kuota_nasional are unlimited and never touch the indexer
+func (s *SukukAvailabilityService) ReserveOrder(ctx context.Context, order *models.Order) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sukuk models.SukukMetadata
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sukuk, order.SukukMetadataID).Error; err != nil {
+			return err
+		}
+
+		if sukuk.KuotaNasional.Sign() > 0 {
+			availability, err := s.Availability(ctx, &sukuk)
+			if err != nil {
+				return err
+			}
+			outstanding, err := models.OutstandingOrderAmount(tx, sukuk.ID, s.now())
+			if err != nil {
+				return fmt.Errorf("failed to sum outstanding orders: %w", err)
+			}
+			if err := CheckAmountAvailable(withoutOutstanding(availability, outstanding), order.TokenAmount); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(order).Error
+	})
+}
+
+// withoutOutstanding returns a copy of availability with the outstanding order amount taken out
+// of what remains, never going below zero
+func withoutOutstanding(availability *models.SukukAvailability, outstanding models.BigNumeric) *models.SukukAvailability {
+	reduced := *availability
+	if reduced.Unlimited {
+		return &reduced
+	}
+	mathUtil := utils.NewTokenMath()
+	if cmp, err := mathUtil.CompareTokenAmounts(outstanding.String(), reduced.Remaining); err == nil && cmp >= 0 {
+		reduced.Remaining = "0"
+	} else if remaining, err := mathUtil.SubtractTokenAmounts(reduced.Remaining, outstanding.String()); err == nil {
+		reduced.Remaining = remaining
+	}
+	return &reduced
+}
+
+// QuoteTokenAmount returns the sukuk tokens, in the sukuk's smallest unit, that fiatAmount
+// rupiah buys. Sukuk tokens are sold at a face value of Rp1, like kuota_nasional
+func (s *SukukAvailabilityService) QuoteTokenAmount(ctx context.Context, sukuk *models.SukukMetadata, fiatAmount float64) (models.BigNumeric, error) {
+	tokens, err := models.GetAllPaymentTokens(s.db.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to load payment tokens: %w", err)
+	}
+	return QuoteTokenAmount(fiatAmount, NewTokenFormatter(tokens).Decimals(sukuk.ContractAddress))
+}
+
+// QuoteTokenAmount converts a rupiah amount with at most two decimals to token units
+func QuoteTokenAmount(fiatAmount float64, decimals uint8) (models.BigNumeric, error) {
+	amount, err := utils.NewTokenMath().ParseUnits(strconv.FormatFloat(fiatAmount, 'f', 2, 64), decimals)
+	if err != nil {
+		return "", err
+	}
+	return models.ParseBigNumeric(amount)
+}
+
+// BuildAvailability computes availability from the sukuk's quota in token units and the raw purchased total
+func BuildAvailability(sukuk *models.SukukMetadata, decimals uint8, totalPurchased string, now time.Time) (*models.SukukAvailability, error) {
+	mathUtil := utils.NewTokenMath()
+	if totalPurchased == "" {
+		totalPurchased = "0"
+	}
+
+	availability := &models.SukukAvailability{
+		SukukMetadataID: sukuk.ID,
+		ContractAddress: sukuk.ContractAddress,
+		Unlimited:       sukuk.KuotaNasional.Sign() <= 0,
+		TotalPurchased:  totalPurchased,
+		PurchasePeriod:  sukuk.PeriodePembelian,
+	}
+
+	if !availability.Unlimited {
+		capAmount, err := mathUtil.ParseUnits(sukuk.KuotaNasional.String(), decimals)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kuota_nasional: %w", err)
+		}
+		remaining := "0"
+		if cmp, err := mathUtil.CompareTokenAmounts(totalPurchased, capAmount); err != nil {
+			return nil, fmt.Errorf("invalid purchased total: %w", err)
+		} else if cmp < 0 {
+			remaining, _ = mathUtil.SubtractTokenAmounts(capAmount, totalPurchased)
+		}
+		percent, err := mathUtil.FormatPercent(totalPurchased, capAmount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid purchased total: %w", err)
+		}
+
+		availability.Cap = capAmount
+		availability.Remaining = remaining
+		availability.PercentSubscribed = percent
+	}
+
+	if start, end, ok := models.ParsePurchasePeriod(sukuk.PeriodePembelian); ok {
+		open := !now.Before(start) && now.Before(end)
+		availability.PurchasePeriodStart = &start
+		availability.PurchasePeriodEnd = &end
+		availability.PurchasePeriodOpen = &open
+	}
+
+	return availability, nil
+}
+
+// CheckAmountAvailable returns an *AvailabilityExceededError when amount is more than the remaining capacity
+func CheckAmountAvailable(availability *models.SukukAvailability, amount models.BigNumeric) error {
+	if availability.Unlimited {
+		return nil
+	}
+	cmp, err := utils.NewTokenMath().CompareTokenAmounts(amount.String(), availability.Remaining)
+	if err != nil {
+		return err
+	}
+	if cmp > 0 {
+		return &AvailabilityExceededError{Requested: amount.String(), Remaining: availability.Remaining}
+	}
+	return nil
+}
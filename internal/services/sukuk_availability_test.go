@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestCheckAmountAvailableBoundaries(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	// A quota of 1.5 tokens with 1 token sold leaves exactly 0.5 tokens
+	sukuk := &models.SukukMetadata{ContractAddress: "0xc1", KuotaNasional: "1.5"}
+	availability, err := BuildAvailability(sukuk, 18, "1000000000000000000", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if availability.Cap != "1500000000000000000" || availability.Remaining != "500000000000000000" {
+		t.Fatalf("Expected a 1.5e18 cap with 5e17 remaining, got %+v", availability)
+	}
+	if availability.PercentSubscribed != "66.67" {
+		t.Errorf("Expected two thirds subscribed, got %q", availability.PercentSubscribed)
+	}
+
+	if err := CheckAmountAvailable(availability, "500000000000000000"); err != nil {
+		t.Errorf("Expected the exact remaining amount to fit, got %v", err)
+	}
+	err = CheckAmountAvailable(availability, "500000000000000001")
+	var exceeded *AvailabilityExceededError
+	if !errors.As(err, &exceeded) || exceeded.Remaining != "500000000000000000" {
+		t.Errorf("Expected one wei over to be rejected, got %v", err)
+	}
+
+	// Once full, nothing fits and remaining never goes negative
+	full, _ := BuildAvailability(sukuk, 18, "1500000000000000000", now)
+	if full.Remaining != "0" || full.PercentSubscribed != "100.00" {
+		t.Errorf("Expected a full sukuk, got %+v", full)
+	}
+	if err := CheckAmountAvailable(full, "1"); !errors.As(err, &exceeded) {
+		t.Errorf("Expected a full sukuk to reject 1 wei, got %v", err)
+	}
+	over, _ := BuildAvailability(sukuk, 18, "2000000000000000000", now)
+	if over.Remaining != "0" {
+		t.Errorf("Expected an oversubscribed sukuk to have nothing remaining, got %s", over.Remaining)
+	}
+}
+
+func TestBuildAvailabilityUnlimited(t *testing.T) {
+	sukuk := &models.SukukMetadata{ContractAddress: "0xc1"}
+	availability, err := BuildAvailability(sukuk, 18, "", time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !availability.Unlimited || availability.Cap != "" || availability.PercentSubscribed != "" || availability.TotalPurchased != "0" {
+		t.Errorf("Expected an unlimited sukuk, got %+v", availability)
+	}
+	if err := CheckAmountAvailable(availability, "1000000000000000000000000"); err != nil {
+		t.Errorf("Expected no limit without a quota, got %v", err)
+	}
+}
+
+func TestBuildAvailabilityPurchasePeriod(t *testing.T) {
+	jakarta := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, taxReportLocation)
+	}
+
+	// The last day stays open until midnight in Jakarta
+	sukuk := &models.SukukMetadata{PeriodePembelian: "16 Mei - 18 Jun 2025"}
+	lastEvening := jakarta(2025, time.June, 18).Add(23 * time.Hour)
+	if a, _ := BuildAvailability(sukuk, 18, "0", lastEvening); a.PurchasePeriodOpen == nil || !*a.PurchasePeriodOpen {
+		t.Error("Expected the period to be open on its last evening")
+	}
+	if a, _ := BuildAvailability(sukuk, 18, "0", jakarta(2025, time.June, 19)); a.PurchasePeriodOpen == nil || *a.PurchasePeriodOpen {
+		t.Error("Expected the period to be closed the day after")
+	}
+	if a, _ := BuildAvailability(&models.SukukMetadata{PeriodePembelian: "TBA"}, 18, "0", lastEvening); a.PurchasePeriodOpen != nil {
+		t.Error("Expected an unparseable period to leave purchase_period_open null")
+	}
+}
+
+func TestQuoteTokenAmount(t *testing.T) {
+	tests := []struct {
+		fiat     float64
+		decimals uint8
+		want     models.BigNumeric
+		wantErr  bool
+	}{
+		{1000000, 18, "1000000000000000000000000", false},
+		{1500000.5, 6, "1500000500000", false},
+		{1000000, 0, "1000000", false},
+		{1000000.25, 0, "", true},
+	}
+	for _, tt := range tests {
+		got, err := QuoteTokenAmount(tt.fiat, tt.decimals)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("QuoteTokenAmount(%v, %d) error = %v, want error %v", tt.fiat, tt.decimals, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("QuoteTokenAmount(%v, %d) = %s, want %s", tt.fiat, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestWithoutOutstanding(t *testing.T) {
+	availability := &models.SukukAvailability{Cap: "10", Remaining: "4"}
+	tests := []struct {
+		outstanding models.BigNumeric
+		want        string
+	}{
+		{"0", "4"},
+		{"3", "1"},
+		{"4", "0"},
+		{"9", "0"},
+	}
+	for _, tt := range tests {
+		if got := withoutOutstanding(availability, tt.outstanding).Remaining; got != tt.want {
+			t.Errorf("withoutOutstanding(%s) remaining = %s, want %s", tt.outstanding, got, tt.want)
+		}
+	}
+	if availability.Remaining != "4" {
+		t.Errorf("Expected the availability to be left unchanged, got %s remaining", availability.Remaining)
+	}
+}
+
+// fixedPurchases reports the same purchased total for every sukuk
+type fixedPurchases string
+
+func (p fixedPurchases) GetTotalPurchased(context.Context, string) (string, error) {
+	return string(p), nil
+}
+
+// TestReserveOrderConcurrently requires a reachable Postgres, see testutil.Open
+func TestReserveOrderConcurrently(t *testing.T) {
+	db := testutil.DB(t)
+
+	// A quota of 3 tokens with 1 purchased and 1 in a paid order leaves room for one more
+	sukuk := models.SukukMetadata{ContractAddress: "0x00000000000000000000000000000000000a7a01", SukukCode: "AVAIL-1", KuotaNasional: "3"}
+	db.Unscoped().Where("contract_address = ?", sukuk.ContractAddress).Delete(&models.SukukMetadata{})
+	if err := db.Create(&sukuk).Error; err != nil {
+		t.Fatalf("Failed to create sukuk: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("sukuk_metadata_id = ?", sukuk.ID).Delete(&models.Order{})
+		db.Unscoped().Delete(&sukuk)
+	})
+
+	const token = "1000000000000000000"
+	newOrder := func(i int, status models.OrderStatus) *models.Order {
+		return &models.Order{
+			UserAddress:      "0x00000000000000000000000000000000000000b1",
+			SukukMetadataID:  sukuk.ID,
+			SukukAddress:     sukuk.ContractAddress,
+			FiatAmount:       1,
+			TokenAmount:      token,
+			PaymentReference: fmt.Sprintf("ORD-AVAIL-%d-%d", sukuk.ID, i),
+			Status:           status,
+			ExpiresAt:        time.Now().Add(time.Hour),
+		}
+	}
+	if err := db.Create(newOrder(0, models.OrderStatusPaid)).Error; err != nil {
+		t.Fatalf("Failed to create the paid order: %v", err)
+	}
+
+	service := NewSukukAvailabilityService(db, fixedPurchases(token))
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = service.ReserveOrder(context.Background(), newOrder(i+1, models.OrderStatusCreated))
+		}(i)
+	}
+	wg.Wait()
+
+	reserved := 0
+	for _, err := range errs {
+		var exceeded *AvailabilityExceededError
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.As(err, &exceeded):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if reserved != 1 {
+		t.Errorf("Expected exactly one order to take the last token, got %d", reserved)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"sukuk-be/internal/models"
+)
+
+// sukukDateLayout is how dates are written in date violation messages
+const sukukDateLayout = "2006-01-02"
+
+// ValidateSukukDates checks the purchase period, first coupon, maturity and coupon day of a sukuk
+// against each other, as the coupon schedule assumes they follow. Dates that contradict each
+// other are errors; a date that is missing or unreadable, as on records predating these rules,
+// only warns that the rules depending on it can't be checked
+func ValidateSukukDates(sukuk *models.SukukMetadata) []models.SukukDateViolation {
+	var violations []models.SukukDateViolation
+	add := func(constraint models.SukukDateConstraint, severity models.SukukDateSeverity, field, format string, args ...interface{}) {
+		violations = append(violations, models.SukukDateViolation{
+			Constraint: constraint,
+			Field:      field,
+			Severity:   severity,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	_, end, periodOK := models.ParsePurchasePeriod(sukuk.PeriodePembelian)
+	switch {
+	case strings.TrimSpace(sukuk.PeriodePembelian) == "":
+		add(models.SukukDatePurchasePeriodOrder, models.SukukDateWarning, "periode_pembelian", "periode_pembelian is not set")
+	case models.PurchasePeriodReversed(sukuk.PeriodePembelian):
+		add(models.SukukDatePurchasePeriodOrder, models.SukukDateError, "periode_pembelian", "periode_pembelian %q ends before it starts", sukuk.PeriodePembelian)
+	case !periodOK:
+		add(models.SukukDatePurchasePeriodOrder, models.SukukDateWarning, "periode_pembelian", "periode_pembelian %q is not a date range such as 16 Mei - 18 Jun 2025", sukuk.PeriodePembelian)
+	}
+
+	switch {
+	case sukuk.KuponPertama.IsZero():
+		add(models.SukukDateFirstCouponAfterSale, models.SukukDateWarning, "kupon_pertama", "kupon_pertama is not set")
+	case periodOK && sukuk.KuponPertama.Before(end):
+		add(models.SukukDateFirstCouponAfterSale, models.SukukDateError, "kupon_pertama", "kupon_pertama %s must be after periode_pembelian ends on %s",
+			sukuk.KuponPertama.Format(sukukDateLayout), end.AddDate(0, 0, -1).Format(sukukDateLayout))
+	}
+
+	switch {
+	case sukuk.JatuhTempo.IsZero():
+		add(models.SukukDateMaturityAfterCoupon, models.SukukDateWarning, "jatuh_tempo", "jatuh_tempo is not set")
+	case !sukuk.KuponPertama.IsZero() && !sukuk.KuponPertama.Before(sukuk.JatuhTempo):
+		add(models.SukukDateMaturityAfterCoupon, models.SukukDateError, "jatuh_tempo", "jatuh_tempo %s must be after kupon_pertama %s",
+			sukuk.JatuhTempo.Format(sukukDateLayout), sukuk.KuponPertama.Format(sukukDateLayout))
+	}
+
+	// Only wording both fields have a cadence in is compared, e.g. "10 Setiap Bulan" against "Triwulanan"
+	paidMonths, paid := CouponFrequency(sukuk.TanggalBayarKupon, "")
+	months, frequency := CouponFrequency(sukuk.PenerimaanKupon, sukuk.TipeKupon)
+	if paidMonths != 0 && months != 0 && paidMonths != months {
+		add(models.SukukDateCouponDayMatchesCadence, models.SukukDateError, "tanggal_bayar_kupon", "tanggal_bayar_kupon %q is %s but penerimaan_kupon %q is %s",
+			sukuk.TanggalBayarKupon, paid, sukuk.PenerimaanKupon, frequency)
+	}
+
+	return violations
+}
+
+// SukukDateErrors returns the violations that block a write
+func SukukDateErrors(violations []models.SukukDateViolation) []models.SukukDateViolation {
+	var errs []models.SukukDateViolation
+	for _, violation := range violations {
+		if violation.Severity == models.SukukDateError {
+			errs = append(errs, violation)
+		}
+	}
+	return errs
+}
+
+// NewSukukDateErrors returns the date errors of after that before doesn't already break, so an
+// edit of a record stored before these rules isn't refused for dates it leaves as they were
+func NewSukukDateErrors(before, after *models.SukukMetadata) []models.SukukDateViolation {
+	existing := make(map[models.SukukDateConstraint]bool)
+	for _, violation := range SukukDateErrors(ValidateSukukDates(before)) {
+		existing[violation.Constraint] = true
+	}
+
+	var errs []models.SukukDateViolation
+	for _, violation := range SukukDateErrors(ValidateSukukDates(after)) {
+		if !existing[violation.Constraint] {
+			errs = append(errs, violation)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func TestValidateSukukDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(*models.SukukMetadata)
+		want     models.SukukDateConstraint
+		severity models.SukukDateSeverity
+	}{
+		{"purchase period ending before it starts", func(s *models.SukukMetadata) { s.PeriodePembelian = "18 Jun - 16 Jun 2025" }, models.SukukDatePurchasePeriodOrder, models.SukukDateError},
+		{"purchase period across years ending before it starts", func(s *models.SukukMetadata) { s.PeriodePembelian = "16 Mei 2026 - 18 Jun 2025" }, models.SukukDatePurchasePeriodOrder, models.SukukDateError},
+		{"first coupon during the purchase period", func(s *models.SukukMetadata) { s.KuponPertama = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }, models.SukukDateFirstCouponAfterSale, models.SukukDateError},
+		{"first coupon on the last purchase day", func(s *models.SukukMetadata) { s.KuponPertama = time.Date(2025, 6, 18, 12, 0, 0, 0, time.UTC) }, models.SukukDateFirstCouponAfterSale, models.SukukDateError},
+		{"maturity before the first coupon", func(s *models.SukukMetadata) { s.JatuhTempo = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) }, models.SukukDateMaturityAfterCoupon, models.SukukDateError},
+		{"maturity on the first coupon", func(s *models.SukukMetadata) { s.JatuhTempo = s.KuponPertama }, models.SukukDateMaturityAfterCoupon, models.SukukDateError},
+		{"quarterly coupon day on a monthly sukuk", func(s *models.SukukMetadata) { s.TanggalBayarKupon = "10 Setiap 3 Bulan" }, models.SukukDateCouponDayMatchesCadence, models.SukukDateError},
+		{"monthly coupon day on an annual sukuk", func(s *models.SukukMetadata) { s.PenerimaanKupon = "Tahunan" }, models.SukukDateCouponDayMatchesCadence, models.SukukDateError},
+		{"unreadable purchase period", func(s *models.SukukMetadata) { s.PeriodePembelian = "Mid 2025" }, models.SukukDatePurchasePeriodOrder, models.SukukDateWarning},
+		{"missing purchase period", func(s *models.SukukMetadata) { s.PeriodePembelian = "" }, models.SukukDatePurchasePeriodOrder, models.SukukDateWarning},
+		{"missing first coupon", func(s *models.SukukMetadata) { s.KuponPertama = time.Time{} }, models.SukukDateFirstCouponAfterSale, models.SukukDateWarning},
+		{"missing maturity", func(s *models.SukukMetadata) { s.JatuhTempo = time.Time{} }, models.SukukDateMaturityAfterCoupon, models.SukukDateWarning},
+	}
+
+	if violations := ValidateSukukDates(readySukuk("")); len(violations) != 0 {
+		t.Fatalf("Expected consistent dates to pass, got %+v", violations)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sukuk := readySukuk("")
+			tt.mutate(sukuk)
+
+			violations := ValidateSukukDates(sukuk)
+			if len(violations) != 1 || violations[0].Constraint != tt.want || violations[0].Severity != tt.severity || violations[0].Message == "" {
+				t.Errorf("Expected one %s %s, got %+v", tt.severity, tt.want, violations)
+			}
+		})
+	}
+}
+
+func TestValidateSukukDatesOnLegacyRecords(t *testing.T) {
+	// Records saved before the rules often have no dates at all; they warn but never fail
+	legacy := &models.SukukMetadata{SukukCode: "SR018", PenerimaanKupon: "Bulanan"}
+	violations := ValidateSukukDates(legacy)
+	if len(violations) != 3 || len(SukukDateErrors(violations)) != 0 {
+		t.Errorf("Expected three warnings and no errors, got %+v", violations)
+	}
+
+	// Coupon wording without a cadence in it can't be compared
+	sukuk := readySukuk("")
+	sukuk.TanggalBayarKupon = "10 Maret dan 10 September"
+	if violations := ValidateSukukDates(sukuk); len(violations) != 0 {
+		t.Errorf("Expected coupon days without a cadence to pass, got %+v", violations)
+	}
+}
+
+func TestNewSukukDateErrors(t *testing.T) {
+	stored := readySukuk("")
+	stored.JatuhTempo = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// Editing another field of a record already stored out of order is allowed
+	edited := *stored
+	edited.SukukTitle = "Sukuk Ritel Seri 22"
+	if errs := NewSukukDateErrors(stored, &edited); len(errs) != 0 {
+		t.Errorf("Expected an unrelated edit to pass, got %+v", errs)
+	}
+
+	// Putting another date out of order isn't
+	edited.KuponPertama = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	errs := NewSukukDateErrors(stored, &edited)
+	if len(errs) != 1 || errs[0].Constraint != models.SukukDateFirstCouponAfterSale {
+		t.Errorf("Expected the new first coupon error only, got %+v", errs)
+	}
+}
+
+func TestSukukReadinessNamesDateConstraints(t *testing.T) {
+	checker := NewSukukReadinessChecker(NewLocalUploadStorage(t.TempDir()))
+	sukuk := readySukuk("")
+	sukuk.PeriodePembelian = "18 Jun - 16 Jun 2025"
+	sukuk.TanggalBayarKupon = "10 Setiap 6 Bulan"
+
+	failed := make(map[models.SukukDateConstraint]string)
+	for _, failure := range checker.Check(context.Background(), sukuk).Failures {
+		if failure.Check == models.SukukReadinessDates {
+			failed[failure.Constraint] = failure.Field
+		}
+	}
+	if len(failed) != 2 || failed[models.SukukDatePurchasePeriodOrder] != "periode_pembelian" || failed[models.SukukDateCouponDayMatchesCadence] != "tanggal_bayar_kupon" {
+		t.Errorf("Expected the reversed period and the coupon cadence to fail, got %v", failed)
+	}
+}
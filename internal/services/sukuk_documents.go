@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSukukDocument is returned for uploads of an unsupported type or file
+var ErrInvalidSukukDocument = errors.New("invalid sukuk document")
+
+// DocumentStorage stores sukuk document files next to the other uploads
+type DocumentStorage interface {
+	UploadStorage
+	Save(ctx context.Context, name string, content io.Reader) (string, error)
+}
+
+// SukukDocumentUpload is a document file uploaded for a sukuk
+type SukukDocumentUpload struct {
+	SukukID    uint
+	Type       models.SukukDocumentType
+	Title      string
+	Filename   string // Original file name, for its extension
+	Size       int64
+	Content    io.Reader
+	UploadedBy string
+}
+
+// SukukDocumentService stores uploaded sukuk documents and their versions
+type SukukDocumentService struct {
+	db      *gorm.DB
+	storage DocumentStorage
+	now     func() time.Time
+}
+
+// NewSukukDocumentService creates a document service saving files to storage
+func NewSukukDocumentService(db *gorm.DB, storage DocumentStorage) *SukukDocumentService {
+	return &SukukDocumentService{db: db, storage: storage, now: time.Now}
+}
+
+// NewDefaultSukukDocumentService saves documents to the local upload directory
+func NewDefaultSukukDocumentService(uploadDir string) *SukukDocumentService {
+	return NewSukukDocumentService(database.GetDB(), NewLocalUploadStorage(uploadDir))
+}
+
+// Upload validates and stores a document as the next version of its type, deactivating
+// the previous version. The file is removed again when its record cannot be written
+func (s *SukukDocumentService) Upload(ctx context.Context, upload SukukDocumentUpload) (*models.SukukDocument, error) {
+	if !upload.Type.IsValid() {
+		return nil, fmt.Errorf("%w: type must be one of %v", ErrInvalidSukukDocument, models.SukukDocumentTypes())
+	}
+	title := strings.TrimSpace(upload.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidSukukDocument)
+	}
+
+	// The leading bytes identify the file format
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	head = head[:n]
+	if err := models.ValidateSukukDocumentFile(upload.Type, upload.Filename, upload.Size, head); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSukukDocument, err)
+	}
+
+	now := s.now()
+	name := fmt.Sprintf("documents/sukuk_%d/%s_%d%s", upload.SukukID, upload.Type, now.UnixNano(),
+		strings.ToLower(filepath.Ext(upload.Filename)))
+	url, err := s.storage.Save(ctx, name, io.MultiReader(bytes.NewReader(head), upload.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	document := &models.SukukDocument{
+		SukukID:    upload.SukukID,
+		Type:       upload.Type,
+		Title:      title,
+		FileURL:    url,
+		UploadedBy: upload.UploadedBy,
+		UploadedAt: now,
+	}
+	if err := models.CreateSukukDocumentVersion(s.db.WithContext(ctx), document); err != nil {
+		if deleteErr := s.storage.Delete(ctx, name); deleteErr != nil {
+			logger.WithError(deleteErr).WithField("file", name).Warn("Failed to remove document of a failed upload")
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+// legacyProspectusName matches the files of the former single-prospectus upload
+var legacyProspectusName = regexp.MustCompile(`^sukuk_([0-9]+)_prospectus\.pdf$`)
+
+// ImportLegacyProspectuses records each prospectus saved by the former single-file upload
+// as version 1 of its sukuk's prospectus. Sukuk that already have a prospectus document,
+// or no longer exist, are skipped, so the import can run on every start
+func (s *SukukDocumentService) ImportLegacyProspectuses(ctx context.Context) (int, error) {
+	files, err := s.storage.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, file := range files {
+		match := legacyProspectusName.FindStringSubmatch(file.Name)
+		if match == nil {
+			continue
+		}
+		sukukID, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		var sukuk models.SukukMetadata
+		err = s.db.WithContext(ctx).Select("id", "sukuk_code").First(&sukuk, "id = ?", uint(sukukID)).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return imported, err
+		}
+
+		var existing int64
+		err = s.db.WithContext(ctx).Model(&models.SukukDocument{}).
+			Where("sukuk_id = ? AND type = ?", sukuk.ID, models.SukukDocumentProspectus).
+			Count(&existing).Error
+		if err != nil {
+			return imported, err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		document := &models.SukukDocument{
+			SukukID:    sukuk.ID,
+			Type:       models.SukukDocumentProspectus,
+			Title:      strings.TrimSpace("Prospectus " + sukuk.SukukCode),
+			FileURL:    uploadURLPrefix + file.Name,
+			UploadedBy: "legacy-import",
+			UploadedAt: file.ModTime,
+		}
+		if err := models.CreateSukukDocumentVersion(s.db.WithContext(ctx), document); err != nil {
+			return imported, err
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+// SukukDocumentUploadReferences collects the uploads referenced by sukuk documents,
+// including inactive versions, whose records are kept
+func SukukDocumentUploadReferences(db *gorm.DB) UploadReferences {
+	return func(ctx context.Context) (map[string]bool, error) {
+		var urls []string
+		if err := db.WithContext(ctx).Model(&models.SukukDocument{}).Pluck("file_url", &urls).Error; err != nil {
+			return nil, err
+		}
+
+		references := make(map[string]bool, len(urls))
+		for _, url := range urls {
+			if name := uploadNameFromURL(url); name != "" {
+				references[name] = true
+			}
+		}
+		return references, nil
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+
+	"gorm.io/gorm"
+)
+
+var testPDF = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n")
+
+// Save stores the file in memory under name
+func (s *fakeUploadStorage) Save(ctx context.Context, name string, content io.Reader) (string, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	s.files[name] = UploadFile{Name: name, Size: int64(len(data)), ModTime: time.Now()}
+	return uploadURLPrefix + name, nil
+}
+
+func pdfUpload(sukukID uint, documentType models.SukukDocumentType, title string) SukukDocumentUpload {
+	return SukukDocumentUpload{
+		SukukID:    sukukID,
+		Type:       documentType,
+		Title:      title,
+		Filename:   "document.pdf",
+		Size:       int64(len(testPDF)),
+		Content:    bytes.NewReader(testPDF),
+		UploadedBy: "test",
+	}
+}
+
+func TestSukukDocumentUploadRejectsInvalidFiles(t *testing.T) {
+	storage := newFakeUploadStorage()
+	service := NewSukukDocumentService(nil, storage)
+
+	invalid := []SukukDocumentUpload{
+		{SukukID: 1, Type: "annual_report", Title: "Report", Filename: "report.pdf", Content: bytes.NewReader(testPDF)},
+		{SukukID: 1, Type: models.SukukDocumentProspectus, Title: " ", Filename: "p.pdf", Content: bytes.NewReader(testPDF)},
+		{SukukID: 1, Type: models.SukukDocumentShariaCertificate, Title: "Fatwa", Filename: "fatwa.png",
+			Content: strings.NewReader("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+		{SukukID: 1, Type: models.SukukDocumentProspectus, Title: "Prospectus", Filename: "p.pdf",
+			Content: strings.NewReader("not a pdf")},
+	}
+	for i, upload := range invalid {
+		if _, err := service.Upload(context.Background(), upload); !errors.Is(err, ErrInvalidSukukDocument) {
+			t.Errorf("upload %d: expected ErrInvalidSukukDocument, got %v", i, err)
+		}
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("Expected no file saved for rejected uploads, got %v", storage.files)
+	}
+}
+
+// TestSukukDocumentVersions requires a reachable Postgres, see testutil.Open
+func TestSukukDocumentVersions(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	sukuk := models.SukukMetadata{ContractAddress: "0x00000000000000000000000000000000000d0c01", SukukCode: "DOC-1"}
+	db.Unscoped().Where("contract_address = ?", sukuk.ContractAddress).Delete(&models.SukukMetadata{})
+	if err := db.Create(&sukuk).Error; err != nil {
+		t.Fatalf("Failed to create sukuk: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("sukuk_id = ?", sukuk.ID).Delete(&models.SukukDocument{})
+		db.Unscoped().Delete(&sukuk)
+	})
+
+	storage := newFakeUploadStorage()
+	service := NewSukukDocumentService(db, storage)
+
+	first, err := service.Upload(ctx, pdfUpload(sukuk.ID, models.SukukDocumentProspectus, "Prospectus"))
+	if err != nil {
+		t.Fatalf("Failed to upload the prospectus: %v", err)
+	}
+	amended, err := service.Upload(ctx, pdfUpload(sukuk.ID, models.SukukDocumentProspectus, "Amended prospectus"))
+	if err != nil {
+		t.Fatalf("Failed to upload the amended prospectus: %v", err)
+	}
+	certificate, err := service.Upload(ctx, pdfUpload(sukuk.ID, models.SukukDocumentShariaCertificate, "Fatwa"))
+	if err != nil {
+		t.Fatalf("Failed to upload the certificate: %v", err)
+	}
+	if first.Version != 1 || amended.Version != 2 || certificate.Version != 1 {
+		t.Errorf("Expected versions 1, 2 and 1, got %d, %d and %d", first.Version, amended.Version, certificate.Version)
+	}
+	if first.FileURL == amended.FileURL || len(storage.files) != 3 {
+		t.Errorf("Expected every version stored as its own file, got %v", storage.files)
+	}
+
+	// The first prospectus keeps its record but is no longer served
+	all, err := models.ListSukukDocuments(db, sukuk.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to list documents: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 document records, got %d", len(all))
+	}
+	active := models.GroupSukukDocuments(mustListSukukDocuments(t, db, sukuk.ID))
+	if prospectus := active[models.SukukDocumentProspectus]; len(prospectus) != 1 || prospectus[0].ID != amended.ID {
+		t.Errorf("Expected only the amended prospectus active, got %+v", prospectus)
+	}
+	if certificates := active[models.SukukDocumentShariaCertificate]; len(certificates) != 1 || certificates[0].ID != certificate.ID {
+		t.Errorf("Expected the certificate active, got %+v", certificates)
+	}
+
+	deactivated, err := models.DeactivateSukukDocument(db, sukuk.ID, certificate.ID)
+	if err != nil || deactivated.Active {
+		t.Fatalf("Failed to deactivate the certificate: %+v (%v)", deactivated, err)
+	}
+	if _, err := models.DeactivateSukukDocument(db, sukuk.ID+1, amended.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected documents of another sukuk not to be found, got %v", err)
+	}
+	active = models.GroupSukukDocuments(mustListSukukDocuments(t, db, sukuk.ID))
+	if _, ok := active[models.SukukDocumentShariaCertificate]; ok || len(active) != 1 {
+		t.Errorf("Expected only the prospectus active, got %+v", active)
+	}
+}
+
+// TestImportLegacyProspectuses requires a reachable Postgres, see TestSukukDocumentVersions
+func TestImportLegacyProspectuses(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	sukuk := models.SukukMetadata{ContractAddress: "0x00000000000000000000000000000000000d0c02", SukukCode: "DOC-2"}
+	db.Unscoped().Where("contract_address = ?", sukuk.ContractAddress).Delete(&models.SukukMetadata{})
+	if err := db.Create(&sukuk).Error; err != nil {
+		t.Fatalf("Failed to create sukuk: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("sukuk_id = ?", sukuk.ID).Delete(&models.SukukDocument{})
+		db.Unscoped().Delete(&sukuk)
+	})
+
+	legacyName := "sukuk_" + strconv.FormatUint(uint64(sukuk.ID), 10) + "_prospectus.pdf"
+	storage := newFakeUploadStorage(
+		UploadFile{Name: legacyName, ModTime: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)},
+		UploadFile{Name: "sukuk_999999999_prospectus.pdf"}, // Sukuk no longer exists
+		UploadFile{Name: "logos/logo.png"},
+	)
+	service := NewSukukDocumentService(db, storage)
+
+	for run := 0; run < 2; run++ {
+		if _, err := service.ImportLegacyProspectuses(ctx); err != nil {
+			t.Fatalf("Import %d failed: %v", run, err)
+		}
+	}
+
+	documents := mustListSukukDocuments(t, db, sukuk.ID)
+	if len(documents) != 1 {
+		t.Fatalf("Expected one imported prospectus after two runs, got %+v", documents)
+	}
+	if doc := documents[0]; doc.Type != models.SukukDocumentProspectus || doc.Version != 1 || doc.FileURL != "/uploads/"+legacyName {
+		t.Errorf("Unexpected imported document: %+v", doc)
+	}
+}
+
+func mustListSukukDocuments(t *testing.T, db *gorm.DB, sukukID uint) []models.SukukDocument {
+	t.Helper()
+	documents, err := models.ListSukukDocuments(db, sukukID, true)
+	if err != nil {
+		t.Fatalf("Failed to list active documents: %v", err)
+	}
+	return documents
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSukukConflictNotFound is returned when resolving a conflict that doesn't exist
+	ErrSukukConflictNotFound = errors.New("sync conflict not found")
+	// ErrSukukConflictResolved is returned when resolving a conflict an admin already settled
+	ErrSukukConflictResolved = errors.New("sync conflict already resolved")
+)
+
+// syncConflict is a synced field whose chain value differs from an admin edit
+type syncConflict struct {
+	Field       models.SukukSyncField
+	ChainValue  string
+	ManualValue string
+}
+
+// syncedValues snapshots the synced fields of metadata as the values last synced from the chain
+func syncedValues(metadata *models.SukukMetadata) models.SukukSyncedValues {
+	values := make(models.SukukSyncedValues, len(models.SukukSyncFields))
+	for _, field := range models.SukukSyncFields {
+		values[field] = metadata.SyncedValue(field)
+	}
+	return values
+}
+
+// mergeChainValues copies the synced fields of chain into metadata where they still hold the
+// value last synced, or already agree with the chain, and returns the fields an admin edited
+// to something else. Those keep the admin's value. Records synced before the last values were
+// kept count as unedited, so they are overwritten as before and start being tracked
+func mergeChainValues(metadata, chain *models.SukukMetadata) ([]syncConflict, error) {
+	var conflicts []syncConflict
+	for _, field := range models.SukukSyncFields {
+		incoming := chain.SyncedValue(field)
+		current := metadata.SyncedValue(field)
+		previous, tracked := metadata.LastSyncedValues[field]
+		if !tracked {
+			previous = current
+		}
+
+		if current == previous || current == incoming {
+			if err := metadata.SetSyncedValue(field, incoming); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		conflicts = append(conflicts, syncConflict{Field: field, ChainValue: incoming, ManualValue: current})
+	}
+
+	metadata.LastSyncedValues = syncedValues(chain)
+	return conflicts, nil
+}
+
+// recordSyncConflict stores a conflict found while syncing metadata. An open conflict for the
+// field is updated with the latest values; one an admin resolved by keeping the manual value
+// is not raised again until the chain value changes
+func recordSyncConflict(tx *gorm.DB, metadata *models.SukukMetadata, conflict syncConflict, event *SukukCreationEvent) error {
+	var latest models.SukukMetadataConflict
+	err := tx.Where("sukuk_metadata_id = ? AND field = ?", metadata.ID, conflict.Field).
+		Order("id DESC").Limit(1).Find(&latest).Error
+	if err != nil {
+		return fmt.Errorf("failed to load sync conflicts: %w", err)
+	}
+
+	switch {
+	case latest.ID != 0 && latest.Status == models.SukukConflictOpen:
+		if latest.ChainValue == conflict.ChainValue && latest.ManualValue == conflict.ManualValue {
+			return nil
+		}
+		err = tx.Model(&latest).Updates(map[string]interface{}{
+			"chain_value":  conflict.ChainValue,
+			"manual_value": conflict.ManualValue,
+			"tx_hash":      event.TxHash,
+			"block_number": event.BlockNumber,
+		}).Error
+	case latest.ID != 0 && latest.Resolution == models.SukukConflictChoiceManual && latest.ChainValue == conflict.ChainValue:
+		return nil
+	default:
+		latest = models.SukukMetadataConflict{
+			SukukMetadataID: metadata.ID,
+			Field:           conflict.Field,
+			ChainValue:      conflict.ChainValue,
+			ManualValue:     conflict.ManualValue,
+			TxHash:          event.TxHash,
+			BlockNumber:     event.BlockNumber,
+			Status:          models.SukukConflictOpen,
+		}
+		err = tx.Create(&latest).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record sync conflict: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"conflict_id":  latest.ID,
+		"sukuk_id":     metadata.ID,
+		"sukuk_code":   metadata.SukukCode,
+		"field":        conflict.Field,
+		"chain_value":  conflict.ChainValue,
+		"manual_value": conflict.ManualValue,
+		"tx_hash":      event.TxHash,
+	}).Warn("Sukuk metadata sync conflict: chain value differs from admin edit")
+	return nil
+}
+
+// ResolveSukukMetadataConflict settles an open conflict. Choosing chain writes the chain value
+// into the field, which the sync then keeps up to date again; choosing manual keeps the admin's
+// value and stops the same chain value from being raised again
+func ResolveSukukMetadataConflict(ctx context.Context, db *gorm.DB, id uint, choice models.SukukConflictChoice) (*models.SukukMetadataConflict, error) {
+	var conflict models.SukukMetadataConflict
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&conflict, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSukukConflictNotFound
+			}
+			return err
+		}
+		if conflict.Status != models.SukukConflictOpen {
+			return ErrSukukConflictResolved
+		}
+
+		var metadata models.SukukMetadata
+		if err := tx.First(&metadata, "id = ?", conflict.SukukMetadataID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSukukConflictNotFound
+			}
+			return err
+		}
+
+		if choice == models.SukukConflictChoiceChain {
+			if err := metadata.SetSyncedValue(conflict.Field, conflict.ChainValue); err != nil {
+				return err
+			}
+			if metadata.LastSyncedValues == nil {
+				metadata.LastSyncedValues = models.SukukSyncedValues{}
+			}
+			metadata.LastSyncedValues[conflict.Field] = conflict.ChainValue
+
+			// Bump the version so admin edits based on the old values are rejected
+			metadata.Version++
+			if err := tx.Save(&metadata).Error; err != nil {
+				return fmt.Errorf("failed to update sukuk metadata: %w", err)
+			}
+		}
+
+		// A kept manual value is recorded as it stands now, in case it was edited again since.
+		// Only the first of concurrent resolutions goes through
+		now := time.Now().UTC()
+		updates := map[string]interface{}{
+			"status":      models.SukukConflictResolved,
+			"resolution":  choice,
+			"resolved_at": now,
+		}
+		if choice == models.SukukConflictChoiceManual {
+			conflict.ManualValue = metadata.SyncedValue(conflict.Field)
+			updates["manual_value"] = conflict.ManualValue
+		}
+		result := tx.Model(&conflict).Where("status = ?", models.SukukConflictOpen).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSukukConflictResolved
+		}
+		conflict.Status = models.SukukConflictResolved
+		conflict.Resolution = choice
+		conflict.ResolvedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if choice == models.SukukConflictChoiceChain {
+		cache.InvalidateSukukMetadata(ctx)
+	}
+	return &conflict, nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestMergeChainValuesKeepsAdminEdits(t *testing.T) {
+	maturity := time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC)
+	metadata := &models.SukukMetadata{SukukTitle: "Sukuk Ritel", SukukCode: "SR022", JatuhTempo: maturity, KuotaNasional: "7000"}
+	metadata.LastSyncedValues = syncedValues(metadata)
+
+	// An admin corrects the title; the amount reads back from NUMERIC with trailing zeros
+	metadata.SukukTitle = "Sukuk Ritel Seri 22"
+	metadata.KuotaNasional = "7000.000000000000000000"
+
+	chain := &models.SukukMetadata{SukukTitle: "Sukuk Ritel", SukukCode: "SR022", JatuhTempo: maturity.AddDate(0, 1, 0), KuotaNasional: "8000"}
+	conflicts, err := mergeChainValues(metadata, chain)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Field != models.SukukSyncFieldTitle ||
+		conflicts[0].ChainValue != "Sukuk Ritel" || conflicts[0].ManualValue != "Sukuk Ritel Seri 22" {
+		t.Fatalf("Expected a title conflict, got %+v", conflicts)
+	}
+	if metadata.SukukTitle != "Sukuk Ritel Seri 22" {
+		t.Errorf("Expected the admin title to be kept, got %q", metadata.SukukTitle)
+	}
+	if !metadata.JatuhTempo.Equal(maturity.AddDate(0, 1, 0)) || metadata.KuotaNasional != "8000" {
+		t.Errorf("Expected unedited fields to follow the chain, got %v and %s", metadata.JatuhTempo, metadata.KuotaNasional)
+	}
+	if metadata.LastSyncedValues[models.SukukSyncFieldTitle] != "Sukuk Ritel" {
+		t.Errorf("Expected the chain title to be remembered, got %v", metadata.LastSyncedValues)
+	}
+
+	// The edit stays protected on the next sync, and a chain catching up with it ends the conflict
+	chain.SukukTitle = "Sukuk Ritel Seri 22"
+	if conflicts, _ := mergeChainValues(metadata, chain); len(conflicts) != 0 {
+		t.Errorf("Expected no conflict once the chain agrees, got %+v", conflicts)
+	}
+	chain.SukukTitle = "Sukuk Ritel SR022"
+	if _, err := mergeChainValues(metadata, chain); err != nil || metadata.SukukTitle != "Sukuk Ritel SR022" {
+		t.Errorf("Expected the title to follow the chain again, got %q, %v", metadata.SukukTitle, err)
+	}
+}
+
+func TestMergeChainValuesOverwritesUntrackedRecords(t *testing.T) {
+	// Records synced before last values were kept behave as before
+	metadata := &models.SukukMetadata{SukukTitle: "Edited", SukukCode: "SR022", KuotaNasional: "1"}
+	chain := &models.SukukMetadata{SukukTitle: "Chain", SukukCode: "SR022", KuotaNasional: "1"}
+	conflicts, err := mergeChainValues(metadata, chain)
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("Expected no conflicts, got %+v, %v", conflicts, err)
+	}
+	if metadata.SukukTitle != "Chain" || metadata.LastSyncedValues[models.SukukSyncFieldTitle] != "Chain" {
+		t.Errorf("Expected the chain title to be written and tracked, got %q, %v", metadata.SukukTitle, metadata.LastSyncedValues)
+	}
+}
+
+// TestAdminEditSurvivesResync syncs a sukuk, edits it as an admin, and syncs it again. It
+// requires a reachable Postgres, see testutil.Open
+func TestAdminEditSurvivesResync(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+	service := &SukukMetadataSyncService{db: db}
+
+	event := &SukukCreationEvent{
+		TokenAddress:      "0x00000000000000000000000000000000000fe210",
+		Name:              "Sukuk Ritel",
+		Symbol:            "SR021",
+		MaxSupply:         "7000000000000000000000",
+		MaturityTimestamp: 1907798400,
+		BlockNumber:       100,
+		TxHash:            "0xfe21",
+	}
+	if err := service.createSukukMetadata(event); err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	load := func() models.SukukMetadata {
+		var metadata models.SukukMetadata
+		if err := db.First(&metadata, "contract_address = ?", event.TokenAddress).Error; err != nil {
+			t.Fatalf("Failed to load metadata: %v", err)
+		}
+		return metadata
+	}
+	metadata := load()
+	defer db.Where("sukuk_metadata_id = ?", metadata.ID).Delete(&models.SukukMetadataConflict{})
+	defer db.Unscoped().Delete(&models.SukukMetadata{}, metadata.ID)
+
+	resync := func() {
+		current := load()
+		if err := service.updateSukukMetadata(&current, event); err != nil {
+			t.Fatalf("Resync failed: %v", err)
+		}
+	}
+	conflicts := func() []models.SukukMetadataConflict {
+		var conflicts []models.SukukMetadataConflict
+		db.Where("sukuk_metadata_id = ?", metadata.ID).Order("id").Find(&conflicts)
+		return conflicts
+	}
+
+	// An admin corrects the title, then the same event is synced again with a new supply
+	db.Model(&models.SukukMetadata{}).Where("id = ?", metadata.ID).Update("sukuk_title", "Sukuk Ritel Seri 21")
+	event.MaxSupply = "8000000000000000000000"
+	resync()
+	resync()
+
+	synced := load()
+	if synced.SukukTitle != "Sukuk Ritel Seri 21" || synced.KuotaNasional.String() != "8000" {
+		t.Errorf("Expected the admin title and the chain supply, got %q and %s", synced.SukukTitle, synced.KuotaNasional)
+	}
+	open := conflicts()
+	if len(open) != 1 || open[0].Field != models.SukukSyncFieldTitle || open[0].ChainValue != "Sukuk Ritel" ||
+		open[0].ManualValue != "Sukuk Ritel Seri 21" || open[0].Status != models.SukukConflictOpen {
+		t.Fatalf("Expected one open title conflict across both syncs, got %+v", open)
+	}
+
+	listed, total, err := models.ListSukukMetadataConflicts(db, models.SukukConflictOpen, 200, 0)
+	if err != nil || total == 0 || len(listed) == 0 {
+		t.Errorf("Expected the conflict to be listed, got %d of %d, %v", len(listed), total, err)
+	}
+
+	// Keeping the manual value stops the same chain value from coming back
+	if _, err := ResolveSukukMetadataConflict(ctx, db, open[0].ID, models.SukukConflictChoiceManual); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	if _, err := ResolveSukukMetadataConflict(ctx, db, open[0].ID, models.SukukConflictChoiceChain); !errors.Is(err, ErrSukukConflictResolved) {
+		t.Errorf("Expected a second resolution to be rejected, got %v", err)
+	}
+	resync()
+	if got := conflicts(); len(got) != 1 || load().SukukTitle != "Sukuk Ritel Seri 21" {
+		t.Errorf("Expected no new conflict for the same chain value, got %+v", got)
+	}
+
+	// A new chain title raises a new conflict, and choosing chain writes it
+	event.Name = "Sukuk Ritel SR021"
+	resync()
+	all := conflicts()
+	if len(all) != 2 || all[1].Status != models.SukukConflictOpen || all[1].ChainValue != "Sukuk Ritel SR021" {
+		t.Fatalf("Expected a new open conflict, got %+v", all)
+	}
+	before := load().Version
+	if _, err := ResolveSukukMetadataConflict(ctx, db, all[1].ID, models.SukukConflictChoiceChain); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	resolved := load()
+	if resolved.SukukTitle != "Sukuk Ritel SR021" || resolved.Version != before+1 {
+		t.Errorf("Expected the chain title at version %d, got %q at %d", before+1, resolved.SukukTitle, resolved.Version)
+	}
+
+	// From then on the title follows the chain again
+	event.Name = "Sukuk Ritel SR021 (Final)"
+	resync()
+	if got := load().SukukTitle; got != event.Name || len(conflicts()) != 2 {
+		t.Errorf("Expected the title to follow the chain without a conflict, got %q", got)
+	}
+}
@@ -1,13 +1,21 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"sukuk-be/internal/cache"
 	"sukuk-be/internal/database"
+	"sukuk-be/internal/lifecycle"
 	"sukuk-be/internal/logger"
 	"sukuk-be/internal/models"
+	"sukuk-be/internal/validation"
 
 	"gorm.io/gorm"
 )
@@ -16,8 +24,37 @@ import (
 type SukukMetadataSyncService struct {
 	db              *gorm.DB
 	syncInterval    time.Duration
-	stopChan        chan bool
+	cancel          context.CancelFunc
+	done            chan struct{} // Closed when the sync loop exits
 	lastProcessedID uint64
+	mu              sync.Mutex        // Prevents overlapping scheduled and manual sync cycles
+	cycles          lifecycle.Counter // Sync cycles running, reported on shutdown
+	suspendEvent    string     // Indexer event for emergency suspensions
+	resumeEvent     string     // Indexer event for resumes, empty when not emitted
+
+	settlementToleranceBps int64 // Allowed quote difference when settling purchase orders
+
+	contractReader SukukContractReader // Onchain backfill of unverified sukuk; nil when disabled
+	backfillBatch  int                 // Sukuk read from the chain per cycle
+	backfillDelay  time.Duration       // Pause between contract reads
+	backfillCursor uint                // Last sukuk ID read, so failing contracts never starve the rest
+
+	head   indexerHeadReader  // Indexer head for the stall check
+	health *SyncHealthMonitor // Nil when not monitored
+	lock   *SyncLock          // Keeps replicas from syncing at once; nil when not shared
+}
+
+// ErrSyncInProgress is returned when a sync cycle is already running
+var ErrSyncInProgress = errors.New("sync already in progress")
+
+// SyncResult summarizes a single sync cycle
+type SyncResult struct {
+	Processed       int    `json:"processed"`
+	Failed          int    `json:"failed"`
+	Skipped         int    `json:"skipped"`
+	LastProcessedID string `json:"last_processed_id"`
+	OnchainVerified int    `json:"onchain_verified,omitempty"` // Sukuk whose token details were read from the contract
+	LedgerRecorded  int    `json:"ledger_recorded,omitempty"`  // Value movements added to the ledger, two entries each
 }
 
 // SukukCreationEvent represents a sukuk creation event from the indexer
@@ -41,49 +78,91 @@ func NewSukukMetadataSyncService(syncInterval time.Duration) *SukukMetadataSyncS
 	return &SukukMetadataSyncService{
 		db:           database.GetDB(),
 		syncInterval: syncInterval,
-		stopChan:     make(chan bool),
+		suspendEvent: DefaultSuspendEvent,
+		head:         NewIndexerQueryService(),
+
+		settlementToleranceBps: DefaultOrderSettlementToleranceBps,
 	}
 }
 
-// Start begins the sync process
-func (s *SukukMetadataSyncService) Start() {
+// SetSuspensionEvents overrides the indexer events used for emergency suspensions and resumes
+// An empty resume event disables resume handling
+func (s *SukukMetadataSyncService) SetSuspensionEvents(suspendEvent, resumeEvent string) {
+	s.suspendEvent = suspendEvent
+	s.resumeEvent = resumeEvent
+}
+
+// SetOrderSettlementTolerance overrides the allowed difference, in basis points, between an
+// order's quoted token amount and the onchain purchase that settles it
+func (s *SukukMetadataSyncService) SetOrderSettlementTolerance(bps int64) {
+	s.settlementToleranceBps = bps
+}
+
+// Start begins the sync process; it runs until ctx is cancelled or Stop is called
+func (s *SukukMetadataSyncService) Start(ctx context.Context) {
 	logger.Info("Starting sukuk metadata sync service")
-	
+
+	ctx, s.cancel = context.WithCancel(ctx)
+
 	// Load last processed ID
 	s.loadLastProcessedID()
 	
 	// Start sync loop
-	go s.syncLoop()
+	s.done = make(chan struct{})
+	go s.syncLoop(ctx)
 }
 
-// Stop stops the sync service
+// Stop stops the sync service, cancelling any in-flight queries, and waits for the running
+// cycle to return
 func (s *SukukMetadataSyncService) Stop() {
 	logger.Info("Stopping sukuk metadata sync service")
-	close(s.stopChan)
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+}
+
+// InFlight returns the number of sync cycles running
+func (s *SukukMetadataSyncService) InFlight() int {
+	return s.cycles.Count()
 }
 
-// syncLoop runs the main sync loop
-func (s *SukukMetadataSyncService) syncLoop() {
-	ticker := time.NewTicker(s.syncInterval)
+// syncLoop runs the main sync loop; the sync.interval setting overrides the configured
+// interval and takes effect as soon as it changes
+func (s *SukukMetadataSyncService) syncLoop(ctx context.Context) {
+	defer close(s.done)
+	settings := Settings()
+	interval := settings.GetDuration(SettingSyncInterval, s.syncInterval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
+	changes := settings.Watch(ctx, SettingSyncInterval)
 
 	// Run immediately on start
-	s.syncEvents()
+	s.syncEvents(ctx)
 
 	for {
 		select {
 		case <-ticker.C:
-			s.syncEvents()
-		case <-s.stopChan:
+			s.syncEvents(ctx)
+		case <-changes:
+			if next := settings.GetDuration(SettingSyncInterval, s.syncInterval); next != interval {
+				interval = next
+				ticker.Reset(interval)
+				logger.WithField("interval", interval.String()).Info("Metadata sync interval changed")
+			}
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// metadataSyncCursorKey is the system state key of the persisted last processed ID
+const metadataSyncCursorKey = "sukuk_metadata_last_event_id"
+
 // loadLastProcessedID loads the last processed event ID from system state
 func (s *SukukMetadataSyncService) loadLastProcessedID() {
 	var state models.SystemState
-	result := s.db.Where("key = ?", "sukuk_metadata_last_event_id").First(&state)
+	result := s.db.Where("key = ?", metadataSyncCursorKey).First(&state)
 	
 	if result.Error == nil {
 		// Parse the string value to uint64
@@ -101,20 +180,36 @@ func (s *SukukMetadataSyncService) loadLastProcessedID() {
 	}
 }
 
+// readLastProcessedID reads the persisted last processed ID, 0 when none is saved
+func (s *SukukMetadataSyncService) readLastProcessedID(ctx context.Context) (uint64, error) {
+	var state models.SystemState
+	err := s.db.WithContext(ctx).Where("key = ?", metadataSyncCursorKey).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(state.Value, 10, 64)
+}
+
 // saveLastProcessedID saves the last processed event ID
 func (s *SukukMetadataSyncService) saveLastProcessedID(eventID uint64) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		var state models.SystemState
-		result := tx.Where("key = ?", "sukuk_metadata_last_event_id").First(&state)
+		result := tx.Where("key = ?", metadataSyncCursorKey).First(&state)
 		
-		if result.Error == gorm.ErrRecordNotFound {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			// Create new state
 			state = models.SystemState{
-				Key:   "sukuk_metadata_last_event_id",
+				Key:   metadataSyncCursorKey,
 				Value: strconv.FormatUint(eventID, 10),
 			}
 			return tx.Create(&state).Error
 		}
+		if result.Error != nil {
+			return result.Error
+		}
 		
 		// Update existing state
 		state.Value = strconv.FormatUint(eventID, 10)
@@ -122,81 +217,265 @@ func (s *SukukMetadataSyncService) saveLastProcessedID(eventID uint64) error {
 	})
 }
 
-// syncEvents fetches and processes new events from the indexer
-func (s *SukukMetadataSyncService) syncEvents() {
+// metadataSyncJobRoute names scheduled sync cycles in query tags; manual cycles keep the
+// route of the admin request that triggered them
+const metadataSyncJobRoute = "job:metadata_sync"
+
+// syncEvents runs a scheduled sync cycle, skipping it if a manual sync is running or
+// scheduled syncs are turned off by the sync.enabled setting
+func (s *SukukMetadataSyncService) syncEvents(ctx context.Context) {
+	if !Settings().GetBool(SettingSyncEnabled, true) {
+		logger.Debug("Scheduled sync disabled by runtime setting, skipping cycle")
+		return
+	}
+	if !s.mu.TryLock() {
+		logger.Debug("Sync already in progress, skipping scheduled cycle")
+		return
+	}
+	defer s.mu.Unlock()
+
+	// Tag the cycle's queries the way requests are, with a per-cycle ID
+	ctx = logger.ContextWithRoute(ctx, metadataSyncJobRoute)
+	ctx = logger.ContextWithRequestID(ctx, fmt.Sprintf("cycle-%d", time.Now().UnixMilli()))
+	// A cycle skipped for another instance's lock was logged when the lock was tried
+	if _, err := s.runCycle(ctx); err != nil && !errors.Is(err, ErrSyncLockHeld) {
+		logger.WithError(err).Error("Metadata sync cycle failed")
+	}
+}
+
+// RunOnce runs a single sync cycle, returning ErrSyncInProgress if another cycle is running,
+// here or, as ErrSyncLockHeld, on another instance
+func (s *SukukMetadataSyncService) RunOnce(ctx context.Context) (*SyncResult, error) {
+	if !s.mu.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer s.mu.Unlock()
+
+	return s.runCycle(ctx)
+}
+
+// PendingCount returns the number of sukuk creation events without metadata
+func (s *SukukMetadataSyncService) PendingCount(ctx context.Context) (int64, error) {
+	tableName, err := s.FindLatestSukukCreationTable()
+	if err != nil {
+		return 0, err
+	}
+	if tableName == "" {
+		return 0, nil
+	}
+
+	var count int64
+	err = s.db.WithContext(ctx).Table(tableName).
+		Where("token_address NOT IN (?)", s.db.Model(&models.SukukMetadata{}).Select("contract_address")).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending events: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetSyncLock makes every cycle take lock first, skipping it while another instance holds it
+func (s *SukukMetadataSyncService) SetSyncLock(lock *SyncLock) {
+	s.lock = lock
+}
+
+// runCycle fetches and processes new events from the indexer, reporting the cycle to the
+// health monitor. It returns ErrSyncLockHeld while another instance runs a cycle. Callers
+// must hold s.mu
+func (s *SukukMetadataSyncService) runCycle(ctx context.Context) (*SyncResult, error) {
+	if s.lock != nil {
+		lease, err := s.lock.TryAcquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer lease.Release()
+	}
+
+	s.cycles.Begin()
+	defer s.cycles.Done()
+	result, err := s.syncCycle(ctx)
+	s.recordHealth(ctx, result, err)
+	return result, err
+}
+
+// syncCycle runs every step of a sync cycle
+func (s *SukukMetadataSyncService) syncCycle(ctx context.Context) (*SyncResult, error) {
 	logger.Debug("Starting metadata sync cycle")
-	
+	result := &SyncResult{}
+
+	// Reloaded every cycle, so a reset of the persisted cursor shows up as a regression
+	if lastID, err := s.readLastProcessedID(ctx); err != nil {
+		logger.WithError(err).Warn("Failed to reload the last processed ID")
+	} else {
+		s.lastProcessedID = lastID
+	}
+
+	if err := s.syncCreationEvents(ctx, result); err != nil {
+		return nil, err
+	}
+
+	// Suspensions apply to metadata created above, so they run second
+	if err := s.syncSuspensionEvents(ctx, result); err != nil {
+		logger.WithError(err).Error("Failed to sync suspension events")
+	}
+
+	if err := s.syncOrderSettlements(ctx, result); err != nil {
+		logger.WithError(err).Error("Failed to settle purchase orders")
+	}
+
+	if err := s.syncReferralAttributions(ctx, result); err != nil {
+		logger.WithError(err).Error("Failed to attribute referred purchases")
+	}
+
+	if err := s.syncLedgerEntries(ctx, result); err != nil {
+		logger.WithError(err).Error("Failed to record ledger entries")
+	}
+
+	if err := s.invalidateForHolderEvents(ctx); err != nil {
+		logger.WithError(err).Error("Failed to invalidate the portfolios of new holders")
+	}
+
+	// Runs last so metadata created this cycle is read from its contract right away
+	if s.contractReader != nil {
+		if err := s.backfillOnchainMetadata(ctx, result); err != nil {
+			logger.WithError(err).Error("Failed to backfill onchain sukuk details")
+		}
+	}
+
+	return result, nil
+}
+
+// syncCreationEvents creates or refreshes metadata from sukuk creation events
+func (s *SukukMetadataSyncService) syncCreationEvents(ctx context.Context, result *SyncResult) error {
 	// First, find the most recent sukuk creation table
 	tableName, err := s.FindLatestSukukCreationTable()
 	if err != nil {
-		logger.WithError(err).Error("Failed to find sukuk creation table")
-		return
+		return fmt.Errorf("failed to find sukuk creation table: %w", err)
 	}
 	
 	if tableName == "" {
 		logger.Debug("No sukuk creation tables found")
-		return
+		return nil
 	}
 	
 	logger.WithField("table_name", tableName).Debug("Using sukuk creation table")
 	
 	// Query new events from the latest table
 	var events []SukukCreationEvent
-	result := s.db.Table(tableName).
+	queryResult := s.db.WithContext(ctx).Table(tableName).
 		Order("timestamp DESC").  // Use timestamp for ordering instead of hex ID
 		Limit(100).
 		Find(&events)
 	
-	if result.Error != nil {
-		logger.WithError(result.Error).Error("Failed to fetch events from indexer")
-		return
+	if queryResult.Error != nil {
+		return fmt.Errorf("failed to fetch events from indexer: %w", queryResult.Error)
 	}
 	
 	if len(events) == 0 {
 		logger.Debug("No sukuk events to process")
-		return
+		return nil
 	}
 	
 	logger.WithField("count", len(events)).Info("Processing sukuk metadata events")
 	
+	// Event IDs are not ordered, so the last processed ID is the block of the newest event synced
+	newest := s.lastProcessedID
+	defer func() {
+		if newest > s.lastProcessedID {
+			if err := s.saveLastProcessedID(newest); err != nil {
+				logger.WithError(err).Error("Failed to save the last processed ID")
+				return
+			}
+			s.lastProcessedID = newest
+		}
+	}()
+
 	// Process each event
 	for _, event := range events {
 		// Check if we already have this sukuk to avoid duplicates
 		var existing models.SukukMetadata
-		existsResult := s.db.Where("contract_address = ?", event.TokenAddress).First(&existing)
+		existsResult := s.db.WithContext(ctx).Where("contract_address = ?", event.TokenAddress).First(&existing)
 		
 		if existsResult.Error == nil {
 			logger.WithField("contract_address", event.TokenAddress).Debug("Sukuk already exists, skipping")
+			result.Skipped++
 			continue
 		}
 		
-		if err := s.processEvent(&event); err != nil {
+		if err := s.processEvent(ctx, &event); err != nil {
 			logger.WithError(err).WithField("event_id", event.ID).Error("Failed to process event")
+			result.Failed++
 			continue
 		}
+
+		result.Processed++
+		// Events are read newest first, so the first one processed is the newest
+		if result.LastProcessedID == "" {
+			result.LastProcessedID = event.ID
+		}
+		if block := uint64(event.BlockNumber); block > newest {
+			newest = block
+		}
 	}
+
+	return nil
 }
 
 // processEvent processes a single sukuk creation event
-func (s *SukukMetadataSyncService) processEvent(event *SukukCreationEvent) error {
+func (s *SukukMetadataSyncService) processEvent(ctx context.Context, event *SukukCreationEvent) error {
 	logger.WithFields(map[string]interface{}{
 		"symbol":   event.Symbol,
 		"name":     event.Name,
 		"tx_hash":  event.TxHash,
 		"event_id": event.ID,
 	}).Info("Processing sukuk creation event")
+	if err := validateCreationEvent(event, time.Now()); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"tx_hash":  event.TxHash,
+			"event_id": event.ID,
+		}).WithError(err).Warn("Sukuk creation event failed validation")
+	}
 	
 	// Check if metadata already exists (using token_address as unique identifier)
 	var existing models.SukukMetadata
-	result := s.db.Where("contract_address = ?", event.TokenAddress).First(&existing)
+	result := s.db.WithContext(ctx).Where("contract_address = ?", event.TokenAddress).First(&existing)
 	
+	var err error
 	if result.Error == nil {
 		// Update existing metadata
-		return s.updateSukukMetadata(&existing, event)
+		err = s.updateSukukMetadata(&existing, event)
+	} else {
+		// Create new metadata
+		err = s.createSukukMetadata(event)
 	}
-	
-	// Create new metadata
-	return s.createSukukMetadata(event)
+	if err != nil {
+		return err
+	}
+
+	s.invalidateForEvent(ctx, event)
+	return nil
+}
+
+// validateCreationEvent applies the event checks to an indexer row without changing it
+// Anomalies are only logged; the row is still synced so the sync cursor keeps moving
+func validateCreationEvent(event *SukukCreationEvent, now time.Time) error {
+	tokenAddress, issuer, manager := event.TokenAddress, event.Issuer, event.Manager
+	var check validation.Checker
+	check.Address("token_address", &tokenAddress)
+	check.Address("issuer", &issuer)
+	check.Address("manager", &manager)
+	check.TxHash("tx_hash", event.TxHash)
+	check.Timestamp("timestamp", time.Unix(event.Timestamp, 0), now)
+	return check.Err()
+}
+
+// invalidateForEvent drops cached responses affected by a processed creation event
+// Holder portfolios embed sukuk metadata but are left to expire via their short TTL; those of
+// wallets in purchases, transfers and redemptions are dropped by invalidateForHolderEvents
+func (s *SukukMetadataSyncService) invalidateForEvent(ctx context.Context, event *SukukCreationEvent) {
+	cache.InvalidateAddresses(ctx, event.Issuer, event.Manager)
+	cache.InvalidateSukukMetadata(ctx)
 }
 
 // createSukukMetadata creates new sukuk metadata from blockchain event
@@ -212,7 +491,7 @@ func (s *SukukMetadataSyncService) createSukukMetadata(event *SukukCreationEvent
 		// Basic info from event
 		SukukCode:  event.Symbol,
 		SukukTitle: event.Name,
-		Status:     "berlangsung", // Default status
+		Status:     models.SukukStatusActive, // Deployed onchain, so already live
 		
 		// Financial info (will need offchain data for complete info)
 		KuotaNasional: s.parseAmount(event.MaxSupply),
@@ -230,6 +509,16 @@ func (s *SukukMetadataSyncService) createSukukMetadata(event *SukukCreationEvent
 		// Not ready until offchain data is added
 		MetadataReady: false,
 	}
+	metadata.LastSyncedValues = syncedValues(&metadata)
+
+	// A suspension event seen before the sukuk was synced stays open until this row exists
+	open, err := models.GetOpenSuspensions(s.db, []string{metadata.ContractAddress})
+	if err != nil {
+		return fmt.Errorf("failed to check pending suspensions: %w", err)
+	}
+	if len(open) > 0 {
+		metadata.Status = models.SukukStatusSuspended
+	}
 	
 	// Save to database
 	if err := s.db.Create(&metadata).Error; err != nil {
@@ -240,57 +529,81 @@ func (s *SukukMetadataSyncService) createSukukMetadata(event *SukukCreationEvent
 	return nil
 }
 
+// ErrMetadataChangedDuringSync is returned when sukuk metadata was written between the sync
+// reading and updating it; syncing again applies the event to the new record
+var ErrMetadataChangedDuringSync = errors.New("sukuk metadata changed during sync")
+
 // updateSukukMetadata updates existing metadata with new blockchain data
+// Fields an admin corrected since the last sync keep the admin's value and raise a conflict
 func (s *SukukMetadataSyncService) updateSukukMetadata(metadata *models.SukukMetadata, event *SukukCreationEvent) error {
 	// Update onchain data if changed
 	metadata.TransactionHash = event.TxHash
 	metadata.BlockNumber = event.BlockNumber
 	
-	// Update basic info
-	metadata.SukukTitle = event.Name
-	metadata.SukukCode = event.Symbol
-	metadata.JatuhTempo = time.Unix(event.MaturityTimestamp, 0)
-	metadata.KuotaNasional = s.parseAmount(event.MaxSupply)
+	// Update basic info where it still holds the chain value
+	chain := models.SukukMetadata{
+		SukukTitle:    event.Name,
+		SukukCode:     event.Symbol,
+		JatuhTempo:    time.Unix(event.MaturityTimestamp, 0),
+		KuotaNasional: s.parseAmount(event.MaxSupply),
+	}
+	conflicts, err := mergeChainValues(metadata, &chain)
+	if err != nil {
+		return fmt.Errorf("failed to merge chain values: %w", err)
+	}
+	
+	// Bump the version so admin edits based on the old values are rejected
+	expectedVersion := metadata.Version
+	metadata.Version++
 	
-	// Save updates
-	if err := s.db.Save(metadata).Error; err != nil {
-		return fmt.Errorf("failed to update sukuk metadata: %w", err)
+	// Save updates together with the conflicts they held back, guarded by version so an admin
+	// edit made since the record was read is never overwritten
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		updated := tx.Model(metadata).Where("version = ?", expectedVersion).Select("*").Updates(metadata)
+		if updated.Error != nil {
+			return fmt.Errorf("failed to update sukuk metadata: %w", updated.Error)
+		}
+		if updated.RowsAffected == 0 {
+			return ErrMetadataChangedDuringSync
+		}
+		for _, conflict := range conflicts {
+			if err := recordSyncConflict(tx, metadata, conflict, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	
-	logger.WithField("sukuk_code", metadata.SukukCode).Info("Updated sukuk metadata from blockchain event")
+	logger.WithFields(map[string]interface{}{
+		"sukuk_code": metadata.SukukCode,
+		"conflicts":  len(conflicts),
+	}).Info("Updated sukuk metadata from blockchain event")
 	return nil
 }
 
 
-// parseAmount parses amount string to float64, handling large numbers
-func (s *SukukMetadataSyncService) parseAmount(amount string) float64 {
-	if amount == "" {
-		return 0
-	}
-	
-	// Try to parse as regular number first
-	if val, err := strconv.ParseFloat(amount, 64); err == nil {
-		// Convert from wei to normal units if the number is very large (likely wei)
-		if val > 1e18 {
-			return val / 1e18 // Convert from wei to ether/token units
-		}
-		return val
+// weiPerToken scales an 18-decimal on-chain amount to whole tokens
+var weiPerToken = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+// parseAmount converts an on-chain max supply to whole token units with exact rational math
+// Values above 10^18 are taken to be wei and scaled down; anything unparseable is zero
+func (s *SukukMetadataSyncService) parseAmount(amount string) models.Decimal {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || value.Sign() < 0 {
+		return models.Decimal("0")
 	}
-	
-	// If parsing fails, try to extract numeric part
-	var result float64
-	fmt.Sscanf(amount, "%f", &result)
-	
-	// Convert from wei if very large
-	if result > 1e18 {
-		return result / 1e18
+
+	if value.Cmp(weiPerToken) > 0 {
+		value.Quo(value, weiPerToken) // Convert from wei to token units
 	}
-	
-	return result
+	return models.NewDecimal(value)
 }
 
 // SyncSpecificSukuk manually syncs a specific sukuk by contract address
-func (s *SukukMetadataSyncService) SyncSpecificSukuk(tokenID int64, contractAddress string) error {
+func (s *SukukMetadataSyncService) SyncSpecificSukuk(ctx context.Context, tokenID int64, contractAddress string) error {
 	// Find all sukuk creation tables and search through them
 	tables, err := s.FindAllSukukCreationTables()
 	if err != nil {
@@ -300,7 +613,7 @@ func (s *SukukMetadataSyncService) SyncSpecificSukuk(tokenID int64, contractAddr
 	// Search through all tables for the specific contract address
 	for _, tableName := range tables {
 		var event SukukCreationEvent
-		result := s.db.Table(tableName).
+		result := s.db.WithContext(ctx).Table(tableName).
 			Where("token_address = ?", contractAddress).
 			First(&event)
 		
@@ -310,7 +623,7 @@ func (s *SukukMetadataSyncService) SyncSpecificSukuk(tokenID int64, contractAddr
 				"table_name": tableName,
 				"contract_address": contractAddress,
 			}).Info("Found sukuk in table")
-			return s.processEvent(&event)
+			return s.processEvent(ctx, &event)
 		}
 	}
 	
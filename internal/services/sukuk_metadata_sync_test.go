@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+)
+
+func TestInvalidateForEventDropsAffectedEntries(t *testing.T) {
+	cache.SetDefault(cache.NewMemoryCache())
+	ctx := context.Background()
+	store := cache.Default()
+
+	issuerKey := cache.PortfolioKey("0x1111111111111111111111111111111111111111")
+	otherKey := cache.PortfolioKey("0x2222222222222222222222222222222222222222")
+	listKey := cache.SukukMetadataListKey("all")
+
+	for _, key := range []string{issuerKey, otherKey, listKey} {
+		store.Set(ctx, key, []byte("{}"), time.Minute)
+	}
+
+	service := &SukukMetadataSyncService{}
+	service.invalidateForEvent(ctx, &SukukCreationEvent{
+		TokenAddress: "0x71D7C963E607eeDAfAA7Ef8f8c92bBb878090650",
+		Issuer:       "0x1111111111111111111111111111111111111111",
+		Manager:      "",
+	})
+
+	if _, err := store.Get(ctx, issuerKey); !errors.Is(err, cache.ErrMiss) {
+		t.Error("Expected issuer portfolio to be invalidated")
+	}
+	if _, err := store.Get(ctx, listKey); !errors.Is(err, cache.ErrMiss) {
+		t.Error("Expected sukuk metadata list to be invalidated")
+	}
+	if _, err := store.Get(ctx, otherKey); err != nil {
+		t.Errorf("Expected unrelated portfolio to stay cached, got %v", err)
+	}
+}
+
+func TestParseAmountIsExact(t *testing.T) {
+	service := &SukukMetadataSyncService{}
+	tests := map[string]models.Decimal{
+		"7000000000000000000000000000000": "7000000000000", // 7e12 tokens in wei
+		"1000000000000000000000000000001": "1000000000000.000000000000000001",
+		"1500000000000000000":             "1.5",
+		"1000000000000000000":             "1000000000000000000", // Not above 10^18, so taken as units
+		"250":                             "250",
+		"":                                "0",
+		"not a number":                    "0",
+	}
+	for input, want := range tests {
+		if got := service.parseAmount(input); got != want {
+			t.Errorf("parseAmount(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// DefaultOnchainBackfillBatch is how many unverified sukuk one sync cycle reads from the chain
+const DefaultOnchainBackfillBatch = 10
+
+// zeroAddress is returned by owner() on contracts whose ownership was renounced
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// SukukTokenInfo is what a sukuk contract reports about its token, read at BlockNumber
+type SukukTokenInfo struct {
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	MaxSupply   string // Raw token amount
+	Owner       string // owner(), or manager() on contracts without an owner
+	BlockNumber int64
+}
+
+// SukukContractReader reads token details from a deployed sukuk contract
+type SukukContractReader interface {
+	ReadSukukToken(ctx context.Context, contractAddress string) (*SukukTokenInfo, error)
+}
+
+// RPCSukukContractReader reads sukuk contracts over JSON-RPC
+type RPCSukukContractReader struct {
+	client *ERC20MetadataClient
+}
+
+// NewRPCSukukContractReader creates a reader for the given RPC endpoint
+func NewRPCSukukContractReader(rpcEndpoint string) *RPCSukukContractReader {
+	return &RPCSukukContractReader{client: NewERC20MetadataClient(rpcEndpoint)}
+}
+
+// ReadSukukToken reads every field at one block so they describe the same contract state
+func (r *RPCSukukContractReader) ReadSukukToken(ctx context.Context, contractAddress string) (*SukukTokenInfo, error) {
+	if r.client.rpcEndpoint == "" {
+		return nil, fmt.Errorf("RPC endpoint is not configured")
+	}
+
+	block, err := r.client.blockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block number: %w", err)
+	}
+	info := &SukukTokenInfo{BlockNumber: block}
+	blockTag := "0x" + strconv.FormatInt(block, 16)
+	call := func(signature string) ([]byte, error) {
+		return r.client.callAt(ctx, contractAddress, utils.EncodeHex(utils.FunctionSelector(signature)), blockTag)
+	}
+
+	for _, field := range []struct {
+		signature string
+		target    *string
+	}{
+		{"name()", &info.Name},
+		{"symbol()", &info.Symbol},
+	} {
+		data, err := call(field.signature)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", field.signature, err)
+		}
+		if *field.target, err = decodeABIString(data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", field.signature, err)
+		}
+	}
+
+	decimals, err := callUint(call, "decimals()")
+	if err != nil {
+		return nil, err
+	}
+	if !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return nil, fmt.Errorf("decimals out of range: %s", decimals)
+	}
+	info.Decimals = uint8(decimals.Uint64())
+
+	maxSupply, err := callUint(call, "maxSupply()")
+	if err != nil {
+		return nil, err
+	}
+	info.MaxSupply = maxSupply.String()
+
+	// Sukuk deployed through the factory expose manager() rather than owner()
+	for _, signature := range []string{"owner()", "manager()"} {
+		data, err := call(signature)
+		if err != nil || len(data) < 32 {
+			continue
+		}
+		if owner := utils.EncodeHex(data[12:32]); owner != zeroAddress {
+			info.Owner = owner
+			break
+		}
+	}
+	return info, nil
+}
+
+// callUint reads a uint256 return value
+func callUint(call func(signature string) ([]byte, error), signature string) (*big.Int, error) {
+	data, err := call(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", signature, err)
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("invalid %s response", signature)
+	}
+	return new(big.Int).SetBytes(data[:32]), nil
+}
+
+// SetOnchainBackfill reads unverified sukuk from the chain after each sync cycle, at most
+// batch per cycle with delay between contracts. A nil reader turns the backfill off
+func (s *SukukMetadataSyncService) SetOnchainBackfill(reader SukukContractReader, batch int, delay time.Duration) {
+	if batch <= 0 {
+		batch = DefaultOnchainBackfillBatch
+	}
+	s.contractReader = reader
+	s.backfillBatch = batch
+	s.backfillDelay = delay
+}
+
+// backfillOnchainMetadata fills the fields creation events lacked from the next batch of
+// unverified sukuk. Contracts that fail to read stay unverified and are retried on a later
+// cycle; the cursor walks past them so they never starve the rest
+func (s *SukukMetadataSyncService) backfillOnchainMetadata(ctx context.Context, result *SyncResult) error {
+	var pending []models.SukukMetadata
+	err := s.db.WithContext(ctx).
+		Where("onchain_verified = ? AND id > ?", false, s.backfillCursor).
+		Order("id").
+		Limit(s.backfillBatch).
+		Find(&pending).Error
+	if err != nil {
+		return fmt.Errorf("failed to fetch unverified sukuk: %w", err)
+	}
+	if len(pending) < s.backfillBatch {
+		s.backfillCursor = 0 // Start over next cycle
+	} else {
+		s.backfillCursor = pending[len(pending)-1].ID
+	}
+
+	for i := range pending {
+		metadata := &pending[i]
+		if i > 0 && s.backfillDelay > 0 {
+			select {
+			case <-time.After(s.backfillDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		info, err := s.contractReader.ReadSukukToken(ctx, metadata.ContractAddress)
+		if err != nil {
+			logger.WithError(err).WithField("contract_address", metadata.ContractAddress).Warn("Failed to read sukuk contract, will retry")
+			continue
+		}
+
+		// Guarded by version so an admin edit made meanwhile is never overwritten
+		updated := s.db.WithContext(ctx).Model(&models.SukukMetadata{}).
+			Where("id = ? AND version = ?", metadata.ID, metadata.Version).
+			Updates(onchainBackfillUpdates(metadata, info))
+		if updated.Error != nil {
+			logger.WithError(updated.Error).WithField("contract_address", metadata.ContractAddress).Warn("Failed to store onchain sukuk details")
+			continue
+		}
+		if updated.RowsAffected == 0 {
+			continue // Edited meanwhile; read again next time round
+		}
+		result.OnchainVerified++
+	}
+
+	if result.OnchainVerified > 0 {
+		cache.InvalidateSukukMetadata(ctx)
+	}
+	return nil
+}
+
+// onchainBackfillUpdates fills the columns a creation event left empty from the contract and
+// marks the record verified at the block read; values already set are never replaced
+func onchainBackfillUpdates(metadata *models.SukukMetadata, info *SukukTokenInfo) map[string]interface{} {
+	updates := map[string]interface{}{
+		"onchain_verified":       true,
+		"onchain_verified_block": info.BlockNumber,
+		"version":                gorm.Expr("version + 1"),
+	}
+	if metadata.SukukCode == "" && info.Symbol != "" && len(info.Symbol) <= 20 {
+		updates["sukuk_code"] = info.Symbol
+	}
+	if metadata.SukukTitle == "" && info.Name != "" && len(info.Name) <= 100 {
+		updates["sukuk_title"] = info.Name
+	}
+	if metadata.KuotaNasional.Sign() == 0 {
+		if supply, ok := new(big.Rat).SetString(info.MaxSupply); ok && supply.Sign() > 0 {
+			scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(info.Decimals)), nil)
+			updates["kuota_nasional"] = models.NewDecimal(supply.Quo(supply, new(big.Rat).SetInt(scale)))
+		}
+	}
+	if metadata.OwnerAddress == "" && utils.IsValidEthereumAddress(info.Owner) {
+		updates["owner_address"] = utils.NormalizeAddress(info.Owner)
+	}
+	return updates
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+	"sukuk-be/internal/utils"
+)
+
+// fakeSukukContractReader serves token details by contract address and fails for the rest
+type fakeSukukContractReader struct {
+	tokens map[string]*SukukTokenInfo
+	reads  []string
+}
+
+func (f *fakeSukukContractReader) ReadSukukToken(ctx context.Context, contractAddress string) (*SukukTokenInfo, error) {
+	f.reads = append(f.reads, contractAddress)
+	if info, ok := f.tokens[contractAddress]; ok {
+		return info, nil
+	}
+	return nil, errors.New("execution reverted")
+}
+
+func TestOnchainBackfillUpdatesFillsOnlyMissing(t *testing.T) {
+	info := &SukukTokenInfo{
+		Name: "Sukuk Ritel SR022", Symbol: "SR022", Decimals: 6, MaxSupply: "7000000000000",
+		Owner: "0x00000000000000000000000000000000000000AB", BlockNumber: 1234,
+	}
+
+	updates := onchainBackfillUpdates(&models.SukukMetadata{}, info)
+	if updates["sukuk_code"] != "SR022" || updates["sukuk_title"] != "Sukuk Ritel SR022" ||
+		updates["owner_address"] != "0x00000000000000000000000000000000000000ab" ||
+		updates["onchain_verified"] != true || updates["onchain_verified_block"] != int64(1234) {
+		t.Errorf("Expected every missing field filled, got %+v", updates)
+	}
+	if quota, ok := updates["kuota_nasional"].(models.Decimal); !ok || quota.String() != "7000000" {
+		t.Errorf("Expected max supply scaled by 6 decimals to 7000000, got %v", updates["kuota_nasional"])
+	}
+
+	existing := &models.SukukMetadata{SukukCode: "SR022-T5", SukukTitle: "Edited", KuotaNasional: "5", OwnerAddress: "0x1"}
+	updates = onchainBackfillUpdates(existing, info)
+	for _, column := range []string{"sukuk_code", "sukuk_title", "kuota_nasional", "owner_address"} {
+		if _, ok := updates[column]; ok {
+			t.Errorf("Expected %s to keep its value, got %v", column, updates[column])
+		}
+	}
+	if updates["onchain_verified"] != true {
+		t.Error("Expected the record marked verified")
+	}
+}
+
+// abiWordHex encodes a uint256 result
+func abiWordHex(value int64) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+// abiStringHex encodes a string result
+func abiStringHex(value string) string {
+	padded := make([]byte, (len(value)+31)/32*32)
+	copy(padded, value)
+	return abiWordHex(32) + abiWordHex(int64(len(value))) + hex.EncodeToString(padded)
+}
+
+func TestRPCSukukContractReader(t *testing.T) {
+	results := map[string]string{
+		"name()":      abiStringHex("Sukuk Ritel SR022"),
+		"symbol()":    abiStringHex("SR022"),
+		"decimals()":  abiWordHex(18),
+		"maxSupply()": abiWordHex(1000000),
+		"owner()":     abiWordHex(0), // Renounced, so manager() is used
+		"manager()":   strings.Repeat("0", 24) + "00000000000000000000000000000000000000cd",
+	}
+	selectors := make(map[string]string)
+	for signature, result := range results {
+		selectors[utils.EncodeHex(utils.FunctionSelector(signature))] = "0x" + result
+	}
+
+	var blocks []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result string
+		switch req.Method {
+		case "eth_blockNumber":
+			result = "0x4d2"
+		case "eth_call":
+			var call struct {
+				Data string `json:"data"`
+			}
+			var block string
+			json.Unmarshal(req.Params[0], &call)
+			json.Unmarshal(req.Params[1], &block)
+			blocks = append(blocks, block)
+			result = selectors[call.Data]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	defer server.Close()
+
+	info, err := NewRPCSukukContractReader(server.URL).ReadSukukToken(context.Background(), "0x00000000000000000000000000000000000000ef")
+	if err != nil {
+		t.Fatalf("Failed to read contract: %v", err)
+	}
+	if info.Name != "Sukuk Ritel SR022" || info.Symbol != "SR022" || info.Decimals != 18 || info.MaxSupply != "1000000" ||
+		info.Owner != "0x00000000000000000000000000000000000000cd" || info.BlockNumber != 1234 {
+		t.Errorf("Unexpected token info: %+v", info)
+	}
+	for _, block := range blocks {
+		if block != "0x4d2" {
+			t.Fatalf("Expected every call pinned to block 0x4d2, got %v", blocks)
+		}
+	}
+}
+
+// TestBackfillOnchainMetadata requires a reachable Postgres, see testutil.Open
+func TestBackfillOnchainMetadata(t *testing.T) {
+	db := testutil.DB(t)
+	ctx := context.Background()
+
+	// Earlier runs may have left unverified rows behind; mark them so only ours are read
+	db.Model(&models.SukukMetadata{}).Where("onchain_verified = ?", false).Update("onchain_verified", true)
+
+	const readable, reverting = "0x00000000000000000000000000000000000Bf001", "0x00000000000000000000000000000000000Bf002"
+	defer db.Unscoped().Where("contract_address IN ?", []string{readable, reverting}).Delete(&models.SukukMetadata{})
+	for _, address := range []string{readable, reverting} {
+		metadata := models.SukukMetadata{ContractAddress: address, SukukCode: "BF", KuotaNasional: models.Decimal("0")}
+		if err := db.Create(&metadata).Error; err != nil {
+			t.Fatalf("Failed to seed metadata: %v", err)
+		}
+	}
+
+	reader := &fakeSukukContractReader{tokens: map[string]*SukukTokenInfo{
+		readable: {Name: "Backfilled", Symbol: "BF-X", Decimals: 18, MaxSupply: new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil).String(),
+			Owner: "0x00000000000000000000000000000000000000aa", BlockNumber: 77},
+	}}
+	service := &SukukMetadataSyncService{db: db}
+	service.SetOnchainBackfill(reader, 10, 0)
+
+	result := &SyncResult{}
+	if err := service.backfillOnchainMetadata(ctx, result); err != nil {
+		t.Fatalf("Backfill failed: %v", err)
+	}
+	if result.OnchainVerified != 1 || len(reader.reads) != 2 {
+		t.Errorf("Expected both contracts read and one verified, got %d verified after reads %v", result.OnchainVerified, reader.reads)
+	}
+
+	var verified, unverified models.SukukMetadata
+	db.Where("contract_address = ?", readable).First(&verified)
+	db.Where("contract_address = ?", reverting).First(&unverified)
+	if !verified.OnchainVerified || verified.OnchainVerifiedBlock != 77 || verified.SukukCode != "BF" ||
+		verified.SukukTitle != "Backfilled" || verified.KuotaNasional.String() != "1000" ||
+		verified.OwnerAddress != "0x00000000000000000000000000000000000000aa" || verified.Version != 2 {
+		t.Errorf("Unexpected verified record: %+v", verified)
+	}
+	if unverified.OnchainVerified {
+		t.Error("Expected the reverting contract to stay unverified")
+	}
+
+	// Verified records are skipped; the failing one is retried
+	reader.reads = nil
+	if err := service.backfillOnchainMetadata(ctx, &SyncResult{}); err != nil {
+		t.Fatalf("Second backfill failed: %v", err)
+	}
+	if len(reader.reads) != 1 || reader.reads[0] != reverting {
+		t.Errorf("Expected only the unverified contract read again, got %v", reader.reads)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// sukukLogoTimeout bounds the request checking that an external logo_url resolves
+const sukukLogoTimeout = 5 * time.Second
+
+// SukukReadinessChecker runs the checklist a sukuk must pass before metadata_ready is set, so
+// the public card never renders with blank fields, out-of-order dates or a broken logo
+type SukukReadinessChecker struct {
+	uploads FileOpener
+	client  *http.Client
+}
+
+// NewSukukReadinessChecker creates a checker resolving /uploads logos against uploads and
+// other logos over HTTP
+func NewSukukReadinessChecker(uploads FileOpener) *SukukReadinessChecker {
+	return &SukukReadinessChecker{
+		uploads: uploads,
+		client:  &http.Client{Timeout: sukukLogoTimeout},
+	}
+}
+
+// Check returns the failing checks of a sukuk; the report is ready when there are none
+func (c *SukukReadinessChecker) Check(ctx context.Context, sukuk *models.SukukMetadata) models.SukukReadinessReport {
+	var failures []models.SukukReadinessFailure
+	fail := func(check models.SukukReadinessCheck, field, format string, args ...interface{}) {
+		failures = append(failures, models.SukukReadinessFailure{Check: check, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	required := []struct {
+		field string
+		empty bool
+	}{
+		{"sukuk_code", strings.TrimSpace(sukuk.SukukCode) == ""},
+		{"sukuk_title", strings.TrimSpace(sukuk.SukukTitle) == ""},
+		{"sukuk_deskripsi", strings.TrimSpace(sukuk.SukukDeskripsi) == ""},
+		{"tenor", strings.TrimSpace(sukuk.Tenor) == ""},
+		{"imbal_hasil", strings.TrimSpace(sukuk.ImbalHasil) == ""},
+		{"periode_pembelian", strings.TrimSpace(sukuk.PeriodePembelian) == ""},
+		{"jatuh_tempo", sukuk.JatuhTempo.IsZero()},
+		{"penerimaan_kupon", strings.TrimSpace(sukuk.PenerimaanKupon) == ""},
+		{"minimum_pembelian", sukuk.MinimumPembelian <= 0},
+		{"tanggal_bayar_kupon", strings.TrimSpace(sukuk.TanggalBayarKupon) == ""},
+		{"kupon_pertama", sukuk.KuponPertama.IsZero()},
+		{"tipe_kupon", strings.TrimSpace(sukuk.TipeKupon) == ""},
+	}
+	for _, field := range required {
+		if field.empty {
+			fail(models.SukukReadinessRequired, field.field, "%s is required", field.field)
+		}
+	}
+
+	// Missing dates are already reported as required, so only the date rules broken by
+	// filled-in dates fail here, plus a purchase period that can't be read at all
+	if sukuk.PeriodePembelian != "" {
+		if _, _, ok := models.ParsePurchasePeriod(sukuk.PeriodePembelian); !ok && !models.PurchasePeriodReversed(sukuk.PeriodePembelian) {
+			fail(models.SukukReadinessDates, "periode_pembelian", "periode_pembelian %q is not a date range such as 16 Mei - 18 Jun 2025", sukuk.PeriodePembelian)
+		}
+	}
+	for _, violation := range SukukDateErrors(ValidateSukukDates(sukuk)) {
+		failures = append(failures, models.SukukReadinessFailure{
+			Check:      models.SukukReadinessDates,
+			Constraint: violation.Constraint,
+			Field:      violation.Field,
+			Message:    violation.Message,
+		})
+	}
+	if sukuk.MaksimumPembelian > 0 && sukuk.MaksimumPembelian < sukuk.MinimumPembelian {
+		fail(models.SukukReadinessRequired, "maksimum_pembelian", "maksimum_pembelian must not be below minimum_pembelian")
+	}
+
+	if err := c.checkLogo(ctx, sukuk.LogoURL); err != nil {
+		fail(models.SukukReadinessLogo, "logo_url", "%s", err.Error())
+	}
+
+	return models.SukukReadinessReport{
+		Ready:    len(failures) == 0,
+		Failures: append(make([]models.SukukReadinessFailure, 0, len(failures)), failures...),
+	}
+}
+
+// checkLogo resolves a logo stored under /uploads against the upload storage, and any other
+// logo with a HEAD request
+func (c *SukukReadinessChecker) checkLogo(ctx context.Context, logoURL string) error {
+	logoURL = strings.TrimSpace(logoURL)
+	if logoURL == "" {
+		return errors.New("logo_url is required")
+	}
+
+	if name := uploadNameFromURL(logoURL); name != "" {
+		file, _, err := c.uploads.Open(ctx, name)
+		if errors.Is(err, ErrFileNotFound) {
+			return fmt.Errorf("logo_url %s is not a stored upload", logoURL)
+		}
+		if err != nil {
+			return fmt.Errorf("logo_url %s could not be opened: %w", logoURL, err)
+		}
+		file.Close()
+		return nil
+	}
+
+	parsed, err := url.Parse(logoURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("logo_url %s must be an http(s) URL or an upload", logoURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, logoURL, nil)
+	if err != nil {
+		return fmt.Errorf("logo_url %s is invalid: %w", logoURL, err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("logo_url %s could not be reached: %w", logoURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("logo_url %s returned %d", logoURL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func readySukuk(logoURL string) *models.SukukMetadata {
+	return &models.SukukMetadata{
+		SukukCode:         "SR022-T5",
+		SukukTitle:        "Sukuk Ritel",
+		SukukDeskripsi:    "Sukuk negara ritel",
+		LogoURL:           logoURL,
+		Tenor:             "5 Tahun",
+		ImbalHasil:        "6.55% / Tahun",
+		PeriodePembelian:  "16 Mei - 18 Jun 2025",
+		JatuhTempo:        time.Date(2030, 6, 10, 0, 0, 0, 0, time.UTC),
+		PenerimaanKupon:   "Bulanan",
+		MinimumPembelian:  1000000,
+		TanggalBayarKupon: "10 Setiap Bulan",
+		KuponPertama:      time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC),
+		TipeKupon:         "Fixed Rate",
+	}
+}
+
+func TestSukukReadinessChecksExternalLogo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/sr022.png" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	checker := NewSukukReadinessChecker(NewLocalUploadStorage(t.TempDir()))
+
+	if report := checker.Check(context.Background(), readySukuk(server.URL+"/sr022.png")); !report.Ready {
+		t.Errorf("Expected a reachable logo to pass, got %+v", report.Failures)
+	}
+	report := checker.Check(context.Background(), readySukuk(server.URL+"/gone.png"))
+	if report.Ready || len(report.Failures) != 1 || report.Failures[0].Check != models.SukukReadinessLogo {
+		t.Errorf("Expected a 404 logo to fail, got %+v", report.Failures)
+	}
+}
+
+func TestSukukReadinessChecksDatesAndRequiredFields(t *testing.T) {
+	checker := NewSukukReadinessChecker(NewLocalUploadStorage(t.TempDir()))
+	sukuk := readySukuk("ftp://example.com/logo.png")
+	sukuk.Tenor = " "
+	sukuk.KuponPertama = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) // During the purchase period
+	sukuk.JatuhTempo = time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	failed := make(map[string]models.SukukReadinessCheck)
+	for _, failure := range checker.Check(context.Background(), sukuk).Failures {
+		failed[failure.Field] = failure.Check
+	}
+	expected := map[string]models.SukukReadinessCheck{
+		"tenor":         models.SukukReadinessRequired,
+		"kupon_pertama": models.SukukReadinessDates,
+		"jatuh_tempo":   models.SukukReadinessDates,
+		"logo_url":      models.SukukReadinessLogo,
+	}
+	if len(failed) != len(expected) {
+		t.Errorf("Expected failures %v, got %v", expected, failed)
+	}
+	for field, check := range expected {
+		if failed[field] != check {
+			t.Errorf("Expected %s to fail %s, got %v", field, check, failed)
+		}
+	}
+}
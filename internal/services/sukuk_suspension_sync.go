@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultSuspendEvent is the indexer event emitted by an onchain emergency suspension
+const DefaultSuspendEvent = "emergency_suspended"
+
+// suspensionBatchSize bounds how many recent suspension and resume events are read per cycle
+const suspensionBatchSize = 100
+
+// SukukSuspensionEvent is an emergency suspension or resume event from the indexer
+// Resume events carry no reason
+type SukukSuspensionEvent struct {
+	ID           string `gorm:"column:id;primaryKey"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	Reason       string `gorm:"column:reason"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+	TxHash       string `gorm:"column:tx_hash"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+	Resume       bool   `gorm:"-"`
+}
+
+// syncSuspensionEvents applies recent suspension and resume events in block order
+// Already-recorded events are skipped, so rereading the same window is harmless
+func (s *SukukMetadataSyncService) syncSuspensionEvents(ctx context.Context, result *SyncResult) error {
+	suspensions, err := s.loadSuspensionEvents(ctx, s.suspendEvent, false)
+	if err != nil {
+		return err
+	}
+	resumes, err := s.loadSuspensionEvents(ctx, s.resumeEvent, true)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range orderSuspensionEvents(suspensions, resumes) {
+		var applied bool
+		if event.Resume {
+			applied, err = s.processEmergencyResumedEvent(ctx, &event)
+		} else {
+			applied, err = s.processEmergencySuspendedEvent(ctx, &event)
+		}
+		switch {
+		case err != nil:
+			logger.WithError(err).WithField("event_id", event.ID).Error("Failed to process suspension event")
+			result.Failed++
+		case applied:
+			result.Processed++
+		}
+	}
+
+	return nil
+}
+
+// loadSuspensionEvents reads the most recent events for eventName, or nothing if the
+// event is unset or the indexer has no table for it
+func (s *SukukMetadataSyncService) loadSuspensionEvents(ctx context.Context, eventName string, resume bool) ([]SukukSuspensionEvent, error) {
+	if eventName == "" {
+		return nil, nil
+	}
+
+	tableName, err := s.findLatestEventTable(ctx, eventName)
+	if err != nil {
+		return nil, err
+	}
+	if tableName == "" {
+		logger.WithField("event", eventName).Debug("No indexer table for event")
+		return nil, nil
+	}
+
+	var events []SukukSuspensionEvent
+	err = s.db.WithContext(ctx).Table(tableName).
+		Order("block_number DESC").
+		Limit(suspensionBatchSize).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s events from %s: %w", eventName, tableName, err)
+	}
+
+	for i := range events {
+		events[i].Resume = resume
+	}
+	return events, nil
+}
+
+// findLatestEventTable returns the indexer table for an event as every other indexer read
+// picks it, honouring table overrides, or "" when the indexer has none
+func (s *SukukMetadataSyncService) findLatestEventTable(ctx context.Context, eventName string) (string, error) {
+	tables := &IndexerTableService{indexerDB: s.db.WithContext(ctx)}
+	tableName, err := tables.GetLatestTableForEvent(eventName)
+	if errors.Is(err, ErrNoEventTable) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the %s table: %w", eventName, err)
+	}
+	return tableName, nil
+}
+
+// orderSuspensionEvents merges suspensions and resumes oldest first
+// Within a block a suspension sorts before a resume so a same-block pair nets out
+func orderSuspensionEvents(suspensions, resumes []SukukSuspensionEvent) []SukukSuspensionEvent {
+	events := make([]SukukSuspensionEvent, 0, len(suspensions)+len(resumes))
+	events = append(events, suspensions...)
+	events = append(events, resumes...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		if events[i].Timestamp != events[j].Timestamp {
+			return events[i].Timestamp < events[j].Timestamp
+		}
+		return !events[i].Resume && events[j].Resume
+	})
+	return events
+}
+
+// processEmergencySuspendedEvent records the suspension and marks the sukuk suspended
+// It reports false if the event was already recorded
+func (s *SukukMetadataSyncService) processEmergencySuspendedEvent(ctx context.Context, event *SukukSuspensionEvent) (bool, error) {
+	applied := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.SukukSuspension{}).Where("event_id = ?", event.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		prior, err := statusBeforeSuspension(tx, event.SukukAddress)
+		if err != nil {
+			return err
+		}
+		// The suspension is recorded even before the sukuk is synced; createSukukMetadata
+		// applies it once the metadata row exists
+		suspension := models.SukukSuspension{
+			SukukAddress: event.SukukAddress,
+			Reason:       event.Reason,
+			EventID:      event.ID,
+			BlockNumber:  event.BlockNumber,
+			TxHash:       event.TxHash,
+			SuspendedAt:  time.Unix(event.Timestamp, 0),
+			PriorStatus:  prior,
+		}
+		if err := tx.Create(&suspension).Error; err != nil {
+			return fmt.Errorf("failed to record suspension: %w", err)
+		}
+
+		// Emergency suspension is allowed from every state, so no transition check is needed
+		err = tx.Model(&models.SukukMetadata{}).
+			Where("LOWER(contract_address) = LOWER(?) AND status <> ?", event.SukukAddress, models.SukukStatusSuspended).
+			Updates(map[string]interface{}{
+				"status":  models.SukukStatusSuspended,
+				"version": gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to suspend sukuk metadata: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil || !applied {
+		return false, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"sukuk_address": event.SukukAddress,
+		"reason":        event.Reason,
+		"tx_hash":       event.TxHash,
+	}).Warn("Sukuk suspended onchain")
+
+	cache.InvalidateSukukMetadata(ctx)
+	return true, nil
+}
+
+// statusBeforeSuspension returns the status a new suspension of address should restore: the
+// sukuk's current status, or while an earlier suspension is still open, the status that one
+// restores. It is empty when the sukuk has no metadata yet
+func statusBeforeSuspension(tx *gorm.DB, address string) (models.SukukStatus, error) {
+	var metadata models.SukukMetadata
+	err := tx.Select("status").Where("LOWER(contract_address) = LOWER(?)", address).First(&metadata).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read sukuk status: %w", err)
+	}
+	if metadata.Status != models.SukukStatusSuspended {
+		return metadata.Status, nil
+	}
+
+	open, err := models.GetOpenSuspensions(tx, []string{address})
+	if err != nil {
+		return "", err
+	}
+	if suspension, ok := open[strings.ToLower(address)]; ok {
+		return suspension.PriorStatus, nil
+	}
+	return "", nil
+}
+
+// processEmergencyResumedEvent closes the open suspension and, once none is left open, returns
+// the sukuk to the status it had before being suspended
+// It reports false if the event was already recorded or nothing was suspended
+func (s *SukukMetadataSyncService) processEmergencyResumedEvent(ctx context.Context, event *SukukSuspensionEvent) (bool, error) {
+	applied := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.SukukSuspension{}).Where("resume_event_id = ?", event.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		var suspension models.SukukSuspension
+		err := tx.Where("sukuk_address = LOWER(?) AND resumed_at IS NULL", event.SukukAddress).
+			Order("suspended_at DESC").
+			First(&suspension).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.WithField("sukuk_address", event.SukukAddress).Debug("Resume event without an open suspension, skipping")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resumedAt := time.Unix(event.Timestamp, 0)
+		suspension.ResumeEventID = event.ID
+		suspension.ResumeTxHash = event.TxHash
+		suspension.ResumedAt = &resumedAt
+		if err := tx.Save(&suspension).Error; err != nil {
+			return fmt.Errorf("failed to record resume: %w", err)
+		}
+
+		// Overlapping suspensions keep the sukuk suspended until the last one is resumed
+		stillOpen, err := models.GetOpenSuspensions(tx, []string{event.SukukAddress})
+		if err != nil {
+			return err
+		}
+		if len(stillOpen) > 0 {
+			applied = true
+			return nil
+		}
+
+		// Suspensions seen before the sukuk was synced have no prior status; synced sukuk start active
+		restored := suspension.PriorStatus
+		if restored == "" {
+			restored = models.SukukStatusActive
+		}
+		err = tx.Model(&models.SukukMetadata{}).
+			Where("LOWER(contract_address) = LOWER(?) AND status = ?", event.SukukAddress, models.SukukStatusSuspended).
+			Updates(map[string]interface{}{
+				"status":  restored,
+				"version": gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to resume sukuk metadata: %w", err)
+		}
+
+		applied = true
+		return nil
+	})
+	if err != nil || !applied {
+		return false, err
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"sukuk_address": event.SukukAddress,
+		"tx_hash":       event.TxHash,
+	}).Info("Sukuk resumed onchain")
+
+	cache.InvalidateSukukMetadata(ctx)
+	return true, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/cache"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestOrderSuspensionEvents(t *testing.T) {
+	suspensions := []SukukSuspensionEvent{
+		{ID: "s2", BlockNumber: 20, Timestamp: 200},
+		{ID: "s1", BlockNumber: 10, Timestamp: 100},
+	}
+	resumes := []SukukSuspensionEvent{
+		{ID: "r2", BlockNumber: 20, Timestamp: 200, Resume: true},
+		{ID: "r1", BlockNumber: 15, Timestamp: 150, Resume: true},
+	}
+
+	ordered := orderSuspensionEvents(suspensions, resumes)
+
+	want := []string{"s1", "r1", "s2", "r2"}
+	if len(ordered) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(ordered))
+	}
+	for i, id := range want {
+		if ordered[i].ID != id {
+			t.Errorf("Expected event %d to be %s, got %s", i, id, ordered[i].ID)
+		}
+	}
+}
+
+// TestSuspendThenResume requires a reachable Postgres, see testutil.Open
+func TestSuspendThenResume(t *testing.T) {
+	db := testutil.DB(t)
+	cache.SetDefault(cache.NewMemoryCache())
+	ctx := context.Background()
+
+	// Stand-in indexer tables named like Ponder's <hash>__<event>, found by table discovery
+	const suspendTable, resumeTable = "cafe01__emergency_suspended", "cafe01__emergency_resumed"
+	for _, table := range []string{suspendTable, resumeTable} {
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, sukuk_address TEXT, reason TEXT,
+			block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+	}
+
+	const address = "0x00000000000000000000000000000000005AFE01"
+	metadata := models.SukukMetadata{ContractAddress: address, SukukCode: "SUSP", Status: models.SukukStatusPaused, MetadataReady: true}
+	if err := db.Create(&metadata).Error; err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	defer db.Unscoped().Delete(&models.SukukMetadata{}, metadata.ID)
+	defer db.Where("sukuk_address = LOWER(?)", address).Delete(&models.SukukSuspension{})
+
+	service := &SukukMetadataSyncService{db: db}
+	service.SetSuspensionEvents(DefaultSuspendEvent, "emergency_resumed")
+
+	db.Exec("INSERT INTO "+suspendTable+" VALUES (?, ?, ?, ?, ?, ?)", "0xaaa-1", address, "Custodian breach", 100, "0xaaa", 1700000000)
+
+	// Running twice must record the suspension once
+	for i := 0; i < 2; i++ {
+		if err := service.syncSuspensionEvents(ctx, &SyncResult{}); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+	}
+
+	var stored models.SukukMetadata
+	db.First(&stored, metadata.ID)
+	if stored.Status != models.SukukStatusSuspended {
+		t.Fatalf("Expected status suspended, got %s", stored.Status)
+	}
+
+	open, err := models.GetOpenSuspensions(db, []string{address})
+	if err != nil {
+		t.Fatalf("Failed to load suspensions: %v", err)
+	}
+	suspension, ok := open[strings.ToLower(address)]
+	if !ok || suspension.Reason != "Custodian breach" || suspension.TxHash != "0xaaa" || suspension.PriorStatus != models.SukukStatusPaused {
+		t.Fatalf("Expected open suspension with reason, got %+v", open)
+	}
+	var count int64
+	db.Model(&models.SukukSuspension{}).Where("sukuk_address = LOWER(?)", address).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 suspension record, got %d", count)
+	}
+
+	db.Exec("INSERT INTO "+resumeTable+" (id, sukuk_address, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?)", "0xbbb-1", address, 120, "0xbbb", 1700000600)
+	if err := service.syncSuspensionEvents(ctx, &SyncResult{}); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	db.First(&stored, metadata.ID)
+	if stored.Status != models.SukukStatusPaused {
+		t.Errorf("Expected the paused status restored after resume, got %s", stored.Status)
+	}
+	open, _ = models.GetOpenSuspensions(db, []string{address})
+	if len(open) != 0 {
+		t.Errorf("Expected no open suspensions after resume, got %+v", open)
+	}
+}
+
+// TestSuspensionBeforeSync requires a reachable Postgres, see testutil.Open
+func TestSuspensionBeforeSync(t *testing.T) {
+	db := testutil.DB(t)
+	cache.SetDefault(cache.NewMemoryCache())
+
+	const address = "0x00000000000000000000000000000000005afe02"
+	defer db.Unscoped().Where("contract_address = ?", address).Delete(&models.SukukMetadata{})
+	defer db.Where("sukuk_address = ?", address).Delete(&models.SukukSuspension{})
+
+	// The suspension arrives before the sukuk creation is synced
+	service := &SukukMetadataSyncService{db: db}
+	applied, err := service.processEmergencySuspendedEvent(context.Background(), &SukukSuspensionEvent{
+		ID: "0xccc-1", SukukAddress: address, Reason: "Early", BlockNumber: 5, TxHash: "0xccc", Timestamp: 1700000000,
+	})
+	if err != nil || !applied {
+		t.Fatalf("Expected the suspension to be recorded, got %v (%v)", applied, err)
+	}
+
+	if err := service.createSukukMetadata(&SukukCreationEvent{TokenAddress: address, Symbol: "EARLY", MaxSupply: "0"}); err != nil {
+		t.Fatalf("Failed to create metadata: %v", err)
+	}
+	var stored models.SukukMetadata
+	db.Where("contract_address = ?", address).First(&stored)
+	if stored.Status != models.SukukStatusSuspended {
+		t.Errorf("Expected the pending suspension to apply on creation, got %s", stored.Status)
+	}
+}
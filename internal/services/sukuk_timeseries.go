@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// TimeSeriesInterval is the bucket width of a time-series
+type TimeSeriesInterval string
+
+const (
+	TimeSeriesDay  TimeSeriesInterval = "day"
+	TimeSeriesWeek TimeSeriesInterval = "week"
+)
+
+// MaxTimeSeriesBuckets caps the number of buckets a single request may span
+const MaxTimeSeriesBuckets = 400
+
+var (
+	// ErrInvalidInterval is returned for intervals other than day or week
+	ErrInvalidInterval = errors.New("interval must be 'day' or 'week'")
+	// ErrTooManyBuckets is returned when the requested range exceeds MaxTimeSeriesBuckets
+	ErrTooManyBuckets = fmt.Errorf("requested range exceeds %d buckets", MaxTimeSeriesBuckets)
+)
+
+// IsValid checks if the interval is supported
+func (i TimeSeriesInterval) IsValid() bool {
+	return i == TimeSeriesDay || i == TimeSeriesWeek
+}
+
+// Truncate rounds t down to the start of its bucket in UTC
+// Weeks start on Monday to match Postgres date_trunc('week', ...)
+func (i TimeSeriesInterval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if i == TimeSeriesWeek {
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -daysSinceMonday)
+	}
+	return day
+}
+
+// Days returns the bucket width in days
+func (i TimeSeriesInterval) Days() int {
+	if i == TimeSeriesWeek {
+		return 7
+	}
+	return 1
+}
+
+// Next returns the start of the bucket following start
+func (i TimeSeriesInterval) Next(start time.Time) time.Time {
+	return start.AddDate(0, 0, i.Days())
+}
+
+// BucketCount returns the number of buckets covering [from, to]
+func (i TimeSeriesInterval) BucketCount(from, to time.Time) int {
+	count := 0
+	for bucket := i.Truncate(from); !bucket.After(to); bucket = i.Next(bucket) {
+		count++
+		if count > MaxTimeSeriesBuckets {
+			break
+		}
+	}
+	return count
+}
+
+// timeSeriesBucketRow is a per-bucket sum returned by the indexer query
+type timeSeriesBucketRow struct {
+	Bucket time.Time `gorm:"column:bucket"`
+	Total  string    `gorm:"column:total"`
+}
+
+// GetSukukTimeSeries aggregates purchases and approved redemptions for a sukuk into
+// day or week buckets covering [from, to]. Empty buckets are returned as zeros
+func (s *IndexerQueryService) GetSukukTimeSeries(ctx context.Context, sukukAddress string, interval TimeSeriesInterval, from, to time.Time) ([]models.SukukTimeSeriesPoint, error) {
+	if !interval.IsValid() {
+		return nil, ErrInvalidInterval
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("'from' must be before 'to'")
+	}
+
+	count := interval.BucketCount(from, to)
+	if count > MaxTimeSeriesBuckets {
+		return nil, ErrTooManyBuckets
+	}
+
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	start := interval.Truncate(from)
+	end := interval.Truncate(to)
+	end = interval.Next(end)
+
+	purchaseTable, err := s.tableService.GetLatestTableForEvent("sukuk_purchase")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sukuk_purchase table: %w", err)
+	}
+	approvalTable, err := s.tableService.GetLatestTableForEvent("redemption_approval")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find redemption_approval table: %w", err)
+	}
+
+	// Flows before the range seed the running totals
+	openingInvested, err := s.sumBefore(ctx, purchaseTable, sukukAddress, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum purchases: %w", err)
+	}
+	openingRedeemed, err := s.sumBefore(ctx, approvalTable, sukukAddress, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum redemption approvals: %w", err)
+	}
+
+	invested, err := s.sumByBucket(ctx, purchaseTable, sukukAddress, interval, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate purchases: %w", err)
+	}
+	redeemed, err := s.sumByBucket(ctx, approvalTable, sukukAddress, interval, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate redemption approvals: %w", err)
+	}
+
+	// Amounts are in the sukuk token's units, so they're formatted with its registered decimals
+	formatter, err := LoadTokenFormatter()
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimeSeries(interval, start, count, formatter.Decimals(sukukAddress), openingInvested, openingRedeemed, invested, redeemed)
+}
+
+// sumBefore sums the amount column of an event table for events before t
+func (s *IndexerQueryService) sumBefore(ctx context.Context, table, sukukAddress string, t time.Time) (string, error) {
+	var total string
+	err := s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(table).
+			Select("COALESCE(SUM(amount::numeric), 0)::text").
+			Where("sukuk_address = ? AND timestamp < ?", sukukAddress, t.Unix()).
+			Scan(&total).Error
+	})
+	return total, err
+}
+
+// sumByBucket sums the amount column of an event table per interval bucket in [start, end)
+func (s *IndexerQueryService) sumByBucket(ctx context.Context, table, sukukAddress string, interval TimeSeriesInterval, start, end time.Time) (map[int64]string, error) {
+	var rows []timeSeriesBucketRow
+	err := s.read(ctx, func(db *gorm.DB) error {
+		return db.Table(table).
+			Select("date_trunc(?, to_timestamp(timestamp) AT TIME ZONE 'UTC') AS bucket, SUM(amount::numeric)::text AS total", string(interval)).
+			Where("sukuk_address = ? AND timestamp >= ? AND timestamp < ?", sukukAddress, start.Unix(), end.Unix()).
+			Group("bucket").
+			Order("bucket").
+			Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		// AT TIME ZONE 'UTC' yields a timestamp without zone, read back as UTC wall time
+		bucket := time.Date(row.Bucket.Year(), row.Bucket.Month(), row.Bucket.Day(), 0, 0, 0, 0, time.UTC)
+		sums[bucket.Unix()] = row.Total
+	}
+	return sums, nil
+}
+
+// buildTimeSeries fills every bucket from start and computes running totals, formatting
+// amounts with decimals
+func buildTimeSeries(interval TimeSeriesInterval, start time.Time, count int, decimals uint8, openingInvested, openingRedeemed string, invested, redeemed map[int64]string) ([]models.SukukTimeSeriesPoint, error) {
+	mathUtil := utils.GlobalTokenMath
+
+	cumulativeInvested := openingInvested
+	cumulativeRedeemed := openingRedeemed
+	points := make([]models.SukukTimeSeriesPoint, 0, count)
+
+	bucket := start
+	for i := 0; i < count; i++ {
+		point := models.SukukTimeSeriesPoint{
+			BucketStart: bucket,
+			Invested:    "0",
+			Redeemed:    "0",
+		}
+		if amount, ok := invested[bucket.Unix()]; ok {
+			point.Invested = amount
+		}
+		if amount, ok := redeemed[bucket.Unix()]; ok {
+			point.Redeemed = amount
+		}
+
+		var err error
+		cumulativeInvested, err = mathUtil.AddTokenAmounts(cumulativeInvested, point.Invested)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum invested amounts: %w", err)
+		}
+		cumulativeRedeemed, err = mathUtil.AddTokenAmounts(cumulativeRedeemed, point.Redeemed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sum redeemed amounts: %w", err)
+		}
+		outstanding, err := mathUtil.SubtractTokenAmounts(cumulativeInvested, cumulativeRedeemed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute outstanding supply: %w", err)
+		}
+
+		point.CumulativeInvested = cumulativeInvested
+		point.OutstandingSupply = outstanding
+		point.InvestedFormatted, _ = mathUtil.FormatUnits(point.Invested, decimals)
+		point.RedeemedFormatted, _ = mathUtil.FormatUnits(point.Redeemed, decimals)
+		point.CumulativeInvestedFormatted, _ = mathUtil.FormatUnits(point.CumulativeInvested, decimals)
+		point.OutstandingSupplyFormatted, _ = mathUtil.FormatUnits(point.OutstandingSupply, decimals)
+
+		points = append(points, point)
+		bucket = interval.Next(bucket)
+	}
+
+	return points, nil
+}
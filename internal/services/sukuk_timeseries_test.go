@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func TestTimeSeriesIntervalTruncate(t *testing.T) {
+	// Thursday 2025-01-16 15:30 UTC
+	ts := time.Date(2025, 1, 16, 15, 30, 0, 0, time.UTC)
+
+	if got := TimeSeriesDay.Truncate(ts); !got.Equal(time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected day bucket 2025-01-16, got %s", got)
+	}
+	if got := TimeSeriesWeek.Truncate(ts); !got.Equal(time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected week bucket starting Monday 2025-01-13, got %s", got)
+	}
+}
+
+func TestTimeSeriesBucketCount(t *testing.T) {
+	from := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := TimeSeriesDay.BucketCount(from, from.AddDate(0, 0, 9)); got != 10 {
+		t.Errorf("Expected 10 day buckets, got %d", got)
+	}
+	if got := TimeSeriesDay.BucketCount(from, from.AddDate(2, 0, 0)); got <= MaxTimeSeriesBuckets {
+		t.Errorf("Expected two years of days to exceed %d buckets, got %d", MaxTimeSeriesBuckets, got)
+	}
+	if got := TimeSeriesWeek.BucketCount(from, from.AddDate(2, 0, 0)); got > MaxTimeSeriesBuckets {
+		t.Errorf("Expected two years of weeks to fit in %d buckets, got %d", MaxTimeSeriesBuckets, got)
+	}
+}
+
+func TestBuildTimeSeriesFillsGapsAndAccumulates(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	invested := map[int64]string{
+		start.Unix():                  "1000000000000000000",
+		start.AddDate(0, 0, 2).Unix(): "500000000000000000",
+	}
+	redeemed := map[int64]string{
+		start.AddDate(0, 0, 3).Unix(): "300000000000000000",
+	}
+
+	points, err := buildTimeSeries(TimeSeriesDay, start, 4, models.DefaultTokenDecimals, "2000000000000000000", "0", invested, redeemed)
+	if err != nil {
+		t.Fatalf("Failed to build time-series: %v", err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("Expected 4 points, got %d", len(points))
+	}
+
+	if points[1].Invested != "0" || points[1].Redeemed != "0" {
+		t.Errorf("Expected empty bucket to be zero-filled, got %+v", points[1])
+	}
+	if points[1].CumulativeInvested != "3000000000000000000" {
+		t.Errorf("Expected cumulative to carry over empty bucket, got %s", points[1].CumulativeInvested)
+	}
+
+	last := points[3]
+	if last.CumulativeInvested != "3500000000000000000" {
+		t.Errorf("Expected cumulative invested 3.5e18, got %s", last.CumulativeInvested)
+	}
+	if last.OutstandingSupply != "3200000000000000000" {
+		t.Errorf("Expected outstanding supply 3.2e18, got %s", last.OutstandingSupply)
+	}
+	if last.OutstandingSupplyFormatted != "3.2" {
+		t.Errorf("Expected formatted supply 3.2, got %s", last.OutstandingSupplyFormatted)
+	}
+	if !last.BucketStart.Equal(start.AddDate(0, 0, 3)) {
+		t.Errorf("Expected last bucket %s, got %s", start.AddDate(0, 0, 3), last.BucketStart)
+	}
+}
+
+func TestBuildTimeSeriesUsesTokenDecimals(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	points, err := buildTimeSeries(TimeSeriesDay, start, 1, 6, "0", "0", map[int64]string{start.Unix(): "1500000"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build time-series: %v", err)
+	}
+	if points[0].InvestedFormatted != "1.5" || points[0].OutstandingSupplyFormatted != "1.5" {
+		t.Errorf("Expected amounts formatted with 6 decimals, got %+v", points[0])
+	}
+}
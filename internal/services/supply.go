@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// supplySources are the sources in the order they are consulted. There is no supply column
+// in sukuk_metadata, so a sukuk that neither source covers is SupplySourceNone
+var supplySources = []models.SupplySource{models.SupplySourceSnapshot, models.SupplySourceRedemption}
+
+// Supply is a sukuk's total supply and where it was read from; Amount is empty for SupplySourceNone
+type Supply struct {
+	Amount string
+	Source models.SupplySource
+}
+
+// SupplyLookup reads the latest total supply per sukuk recorded by one source before a time,
+// keyed by lowercase address. Sukuk the source has no record of are left out
+type SupplyLookup interface {
+	LatestSupplies(ctx context.Context, source models.SupplySource, sukukAddresses []string, before time.Time) (map[string]string, error)
+}
+
+const (
+	supplyBucket          = time.Hour      // Lookups are cached per sukuk per hour
+	supplyOpenBucketTTL   = time.Minute    // The current hour can still gain events
+	supplyClosedTTL       = 24 * time.Hour // Past hours only change if the indexer lags behind
+	supplyCacheMaxEntries = 10000
+)
+
+type supplyCacheKey struct {
+	sukukAddress string
+	bucket       int64 // Unix seconds of the start of the hour
+}
+
+type supplyCacheEntry struct {
+	supply  Supply
+	expires time.Time
+}
+
+// SupplyService resolves the total supply of sukuk at a point in time, so portfolio yield,
+// claimable distributions, digests and certificates all compute against the same figure
+type SupplyService struct {
+	lookup SupplyLookup
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[supplyCacheKey]supplyCacheEntry
+}
+
+// NewSupplyService creates a supply service reading from lookup
+func NewSupplyService(lookup SupplyLookup) *SupplyService {
+	return &SupplyService{
+		lookup: lookup,
+		now:    time.Now,
+		cache:  make(map[supplyCacheKey]supplyCacheEntry),
+	}
+}
+
+// NewIndexerSupplyService creates a supply service reading the indexer tables
+func NewIndexerSupplyService() *SupplyService {
+	return NewSupplyService(indexerSupplyLookup{})
+}
+
+var (
+	supplyServiceMu sync.RWMutex
+	supplyService   = NewIndexerSupplyService()
+)
+
+// DefaultSupplyService returns the supply service shared by all indexer queries
+func DefaultSupplyService() *SupplyService {
+	supplyServiceMu.RLock()
+	defer supplyServiceMu.RUnlock()
+	return supplyService
+}
+
+// SetDefaultSupplyService replaces the shared supply service and returns the previous one,
+// e.g. so tests start from an empty cache
+func SetDefaultSupplyService(s *SupplyService) *SupplyService {
+	supplyServiceMu.Lock()
+	defer supplyServiceMu.Unlock()
+	previous := supplyService
+	supplyService = s
+	return previous
+}
+
+// GetTotalSupplyAt returns a sukuk's total supply as of the hour containing at
+func (s *SupplyService) GetTotalSupplyAt(ctx context.Context, sukukAddress string, at time.Time) (Supply, error) {
+	supplies, err := s.GetTotalSuppliesAt(ctx, []string{sukukAddress}, at)
+	if err != nil {
+		return Supply{}, err
+	}
+	return supplies[strings.ToLower(sukukAddress)], nil
+}
+
+// GetTotalSuppliesAt returns the total supply of each sukuk as of the end of the hour
+// containing at, keyed by lowercase address. Snapshots take precedence over redemption
+// requests, and a sukuk neither recorded gets SupplySourceNone. Each source is read once
+// for all sukuk missing from the cache
+func (s *SupplyService) GetTotalSuppliesAt(ctx context.Context, sukukAddresses []string, at time.Time) (map[string]Supply, error) {
+	now := s.now()
+	start := at.Truncate(supplyBucket)
+	end := start.Add(supplyBucket)
+
+	supplies := make(map[string]Supply, len(sukukAddresses))
+	var missing []string
+	s.mu.Lock()
+	for _, address := range sukukAddresses {
+		address = strings.ToLower(address)
+		if _, seen := supplies[address]; seen {
+			continue
+		}
+		entry, ok := s.cache[supplyCacheKey{address, start.Unix()}]
+		if ok && now.Before(entry.expires) {
+			supplies[address] = entry.supply
+			continue
+		}
+		supplies[address] = Supply{Source: models.SupplySourceNone}
+		missing = append(missing, address)
+	}
+	s.mu.Unlock()
+
+	resolved := make(map[string]Supply, len(missing))
+	for _, source := range supplySources {
+		if len(missing) == 0 {
+			break
+		}
+		amounts, err := s.lookup.LatestSupplies(ctx, source, missing, end)
+		if err != nil {
+			return nil, err
+		}
+		var stillMissing []string
+		for _, address := range missing {
+			if amount, ok := amounts[address]; ok && amount != "" {
+				resolved[address] = Supply{Amount: amount, Source: source}
+			} else {
+				stillMissing = append(stillMissing, address)
+			}
+		}
+		missing = stillMissing
+	}
+	for _, address := range missing {
+		resolved[address] = Supply{Source: models.SupplySourceNone}
+	}
+
+	expires := now.Add(supplyOpenBucketTTL)
+	if !end.After(now) {
+		expires = now.Add(supplyClosedTTL)
+	}
+	s.mu.Lock()
+	if len(s.cache)+len(resolved) > supplyCacheMaxEntries {
+		s.evictExpired(now)
+	}
+	for address, supply := range resolved {
+		supplies[address] = supply
+		if len(s.cache) < supplyCacheMaxEntries {
+			s.cache[supplyCacheKey{address, start.Unix()}] = supplyCacheEntry{supply: supply, expires: expires}
+		}
+	}
+	s.mu.Unlock()
+	return supplies, nil
+}
+
+// evictExpired drops expired cache entries; called with mu held
+func (s *SupplyService) evictExpired(now time.Time) {
+	for key, entry := range s.cache {
+		if !now.Before(entry.expires) {
+			delete(s.cache, key)
+		}
+	}
+}
+
+// supplyEventTables are the indexer event type and per-sukuk order of each source
+var supplyEventTables = map[models.SupplySource]struct {
+	eventType string
+	order     string
+}{
+	models.SupplySourceSnapshot:   {snapshotEventType, "snapshot_id DESC"},
+	models.SupplySourceRedemption: {"redemption_request", "timestamp DESC"},
+}
+
+// indexerSupplyLookup reads supplies from the latest indexer tables
+type indexerSupplyLookup struct{}
+
+func (indexerSupplyLookup) LatestSupplies(ctx context.Context, source models.SupplySource, sukukAddresses []string, before time.Time) (map[string]string, error) {
+	// Built without a supply service of its own, as it is the one doing the lookup
+	s := &IndexerQueryService{tableService: NewIndexerTableService()}
+	if err := s.ConnectToIndexer(); err != nil {
+		return nil, err
+	}
+	return s.LatestSupplies(ctx, source, sukukAddresses, before)
+}
+
+// LatestSupplies reads the total_supply of the latest row per sukuk before a time in the
+// table of a supply source. A sukuk without rows, or a source without a table, is left out
+func (s *IndexerQueryService) LatestSupplies(ctx context.Context, source models.SupplySource, sukukAddresses []string, before time.Time) (map[string]string, error) {
+	supplies := make(map[string]string, len(sukukAddresses))
+	table, ok := supplyEventTables[source]
+	if !ok {
+		return nil, fmt.Errorf("unknown supply source %q", source)
+	}
+	tableName, err := s.tableService.GetLatestTableForEvent(table.eventType)
+	if err != nil {
+		return supplies, nil
+	}
+
+	var rows []sukukTotal
+	err = s.read(ctx, func(db *gorm.DB) error {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT ON (LOWER(sukuk_address)) LOWER(sukuk_address) AS sukuk_address, total_supply::text AS total
+			FROM %s
+			WHERE LOWER(sukuk_address) IN ? AND timestamp < ?
+			ORDER BY LOWER(sukuk_address), %s`, quoteIdentifier(tableName), table.order)
+		return db.Raw(query, sukukAddresses, before.Unix()).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query total supply from %s: %w", tableName, err)
+	}
+
+	for _, row := range rows {
+		supplies[row.SukukAddress] = row.Total
+	}
+	return supplies, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// fakeSupplyLookup serves supplies per source from memory and records each lookup
+type fakeSupplyLookup struct {
+	supplies map[models.SupplySource]map[string]string
+	calls    []fakeSupplyCall
+	err      error
+}
+
+type fakeSupplyCall struct {
+	source    models.SupplySource
+	addresses []string
+	before    time.Time
+}
+
+func (f *fakeSupplyLookup) LatestSupplies(ctx context.Context, source models.SupplySource, sukukAddresses []string, before time.Time) (map[string]string, error) {
+	f.calls = append(f.calls, fakeSupplyCall{source, append([]string{}, sukukAddresses...), before})
+	if f.err != nil {
+		return nil, f.err
+	}
+	supplies := make(map[string]string)
+	for _, address := range sukukAddresses {
+		if amount, ok := f.supplies[source][address]; ok {
+			supplies[address] = amount
+		}
+	}
+	return supplies, nil
+}
+
+func newTestSupplyService(lookup SupplyLookup, now time.Time) *SupplyService {
+	s := NewSupplyService(lookup)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSupplyServicePrecedenceWhenSourcesDisagree(t *testing.T) {
+	lookup := &fakeSupplyLookup{supplies: map[models.SupplySource]map[string]string{
+		models.SupplySourceSnapshot:   {"0xb1": "1000"},
+		models.SupplySourceRedemption: {"0xb1": "900", "0xb2": "500"},
+	}}
+	at := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	s := newTestSupplyService(lookup, at)
+
+	supplies, err := s.GetTotalSuppliesAt(context.Background(), []string{"0xB1", "0xb2", "0xb3"}, at)
+	if err != nil {
+		t.Fatalf("GetTotalSuppliesAt failed: %v", err)
+	}
+
+	expected := map[string]Supply{
+		"0xb1": {Amount: "1000", Source: models.SupplySourceSnapshot},
+		"0xb2": {Amount: "500", Source: models.SupplySourceRedemption},
+		"0xb3": {Source: models.SupplySourceNone},
+	}
+	for address, want := range expected {
+		if got := supplies[address]; got != want {
+			t.Errorf("Expected %s to have supply %+v, got %+v", address, want, got)
+		}
+	}
+
+	// The redemption lookup only asks for sukuk the snapshots did not cover
+	if len(lookup.calls) != 2 || lookup.calls[0].source != models.SupplySourceSnapshot || len(lookup.calls[1].addresses) != 2 {
+		t.Errorf("Expected a snapshot lookup then a redemption lookup of the rest, got %+v", lookup.calls)
+	}
+	if end := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC); !lookup.calls[0].before.Equal(end) {
+		t.Errorf("Expected lookups as of the end of the hour %s, got %s", end, lookup.calls[0].before)
+	}
+}
+
+func TestSupplyServiceCachesPerHourBucket(t *testing.T) {
+	lookup := &fakeSupplyLookup{supplies: map[models.SupplySource]map[string]string{
+		models.SupplySourceSnapshot: {"0xb1": "1000"},
+	}}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := newTestSupplyService(lookup, now)
+	ctx := context.Background()
+
+	if _, err := s.GetTotalSupplyAt(ctx, "0xb1", now.Add(-50*time.Minute)); err != nil {
+		t.Fatalf("GetTotalSupplyAt failed: %v", err)
+	}
+	supply, err := s.GetTotalSupplyAt(ctx, "0xb1", now.Add(-20*time.Minute))
+	if err != nil {
+		t.Fatalf("GetTotalSupplyAt failed: %v", err)
+	}
+	if supply.Amount != "1000" || len(lookup.calls) != 1 {
+		t.Errorf("Expected the second call in the same hour to be served from cache, got %+v after %d lookups", supply, len(lookup.calls))
+	}
+
+	if _, err := s.GetTotalSupplyAt(ctx, "0xb1", now.Add(-90*time.Minute)); err != nil {
+		t.Fatalf("GetTotalSupplyAt failed: %v", err)
+	}
+	if len(lookup.calls) != 2 {
+		t.Errorf("Expected another hour to be looked up, got %d lookups", len(lookup.calls))
+	}
+}
+
+func TestSupplyServiceReturnsLookupErrors(t *testing.T) {
+	lookup := &fakeSupplyLookup{err: errors.New("indexer down")}
+	s := newTestSupplyService(lookup, time.Now())
+
+	if _, err := s.GetTotalSupplyAt(context.Background(), "0xb1", time.Now()); err == nil {
+		t.Fatal("Expected the lookup error")
+	}
+	lookup.err = nil
+	if _, err := s.GetTotalSupplyAt(context.Background(), "0xb1", time.Now()); err != nil || len(lookup.calls) != 3 {
+		t.Errorf("Expected a failed lookup not to be cached, got %v after %d lookups", err, len(lookup.calls))
+	}
+}
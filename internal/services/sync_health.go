@@ -0,0 +1,368 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/metrics"
+)
+
+// Names the sync services report their cycles and alerts under
+const (
+	SyncServiceMetadata           = "metadata_sync"
+	SyncServiceActivity           = "activity_stream"
+	SyncServiceReorg              = "reorg_reconciler"
+	SyncServiceActivityProjection = "activity_projection"
+)
+
+// SyncAnomalyKind identifies a condition flagged by the sync health monitor
+type SyncAnomalyKind string
+
+const (
+	// SyncAnomalyStall: no new events for longer than the stall threshold while the indexer advanced
+	SyncAnomalyStall SyncAnomalyKind = "stall"
+	// SyncAnomalyFailureRatio: the share of a cycle's events that failed exceeded the threshold
+	SyncAnomalyFailureRatio SyncAnomalyKind = "failure_ratio"
+	// SyncAnomalyCursorRegression: the last processed ID went backwards, e.g. its state was reset
+	SyncAnomalyCursorRegression SyncAnomalyKind = "cursor_regression"
+	// SyncAnomalyReorg: a chain reorg dropped transactions that derived rows were built from
+	SyncAnomalyReorg SyncAnomalyKind = "reorg"
+)
+
+// SyncAnomaly is a raised anomaly flag
+type SyncAnomaly struct {
+	Kind    SyncAnomalyKind `json:"kind"`
+	Message string          `json:"message"`
+	Since   time.Time       `json:"since"`
+}
+
+// SyncThresholds configure when the monitor raises anomaly flags
+type SyncThresholds struct {
+	StallAfter        time.Duration // No new events for this long while the indexer advances; 0 disables
+	MaxFailurePercent float64       // Failed share of a cycle's events, in percent; 0 disables
+}
+
+// SyncCycleStats is what a sync service reports after each cycle
+type SyncCycleStats struct {
+	Events          int    // New events seen, failed ones included
+	Failed          int    // Events that failed to process
+	Err             error  // Set when the cycle itself failed, which counts as a 100% failure ratio
+	Cursor          uint64 // Last processed ID after the cycle
+	IndexerMaxBlock int64  // Highest block in the tables the service reads; -1 if unknown
+}
+
+// SyncServiceHealth is the state of one sync service as reported by GET /admin/sync/health
+type SyncServiceHealth struct {
+	Service         string        `json:"service"`
+	Healthy         bool          `json:"healthy"`
+	Cycles          int64         `json:"cycles"`
+	LastCycleAt     *time.Time    `json:"last_cycle_at,omitempty"`
+	LastEventAt     *time.Time    `json:"last_event_at,omitempty"` // Startup time until the first event
+	LastCycleEvents int           `json:"last_cycle_events"`
+	LastCycleFailed int           `json:"last_cycle_failed"`
+	Cursor          uint64        `json:"cursor"`
+	IndexerMaxBlock int64         `json:"indexer_max_block"`
+	Anomalies       []SyncAnomaly `json:"anomalies"`
+}
+
+// SyncAlerter is notified when an anomaly flag is raised
+type SyncAlerter interface {
+	Alert(ctx context.Context, service string, anomaly SyncAnomaly) error
+}
+
+// syncServiceState is the monitor's record of one service
+type syncServiceState struct {
+	health           SyncServiceHealth
+	blockAtLastEvent int64
+	regressedFrom    uint64
+	anomalies        map[SyncAnomalyKind]SyncAnomaly
+}
+
+// SyncHealthMonitor tracks events per cycle of the sync services and flags stalls, failure
+// spikes and cursor regressions. Raising a flag logs an error, counts it in
+// sukuk_sync_anomalies_total and notifies the alerter, once per flag
+type SyncHealthMonitor struct {
+	thresholds SyncThresholds
+	alerter    SyncAlerter // Nil when no alert webhook is configured
+	now        func() time.Time
+
+	mu       sync.Mutex
+	services map[string]*syncServiceState
+	locks    []*SyncLock
+}
+
+// NewSyncHealthMonitor creates a monitor; alerter may be nil
+func NewSyncHealthMonitor(thresholds SyncThresholds, alerter SyncAlerter) *SyncHealthMonitor {
+	return &SyncHealthMonitor{
+		thresholds: thresholds,
+		alerter:    alerter,
+		now:        time.Now,
+		services:   make(map[string]*syncServiceState),
+	}
+}
+
+// RecordCycle evaluates the thresholds against a finished cycle of service
+func (m *SyncHealthMonitor) RecordCycle(ctx context.Context, service string, stats SyncCycleStats) {
+	m.mu.Lock()
+	now := m.now()
+	state, ok := m.services[service]
+	if !ok {
+		// The stall clock starts with the first cycle
+		state = &syncServiceState{
+			health:           SyncServiceHealth{Service: service, LastEventAt: &now},
+			blockAtLastEvent: stats.IndexerMaxBlock,
+			anomalies:        make(map[SyncAnomalyKind]SyncAnomaly),
+		}
+		m.services[service] = state
+	}
+	previousCursor := state.health.Cursor
+
+	health := &state.health
+	health.Cycles++
+	health.LastCycleAt = &now
+	health.LastCycleEvents = stats.Events
+	health.LastCycleFailed = stats.Failed
+	if stats.Err == nil {
+		health.Cursor = stats.Cursor // A failed cycle reports no cursor
+	}
+	if stats.IndexerMaxBlock >= 0 {
+		health.IndexerMaxBlock = stats.IndexerMaxBlock
+	}
+	if stats.Events > 0 {
+		health.LastEventAt = &now
+		state.blockAtLastEvent = health.IndexerMaxBlock
+	}
+
+	var raised []SyncAnomaly
+	set := func(kind SyncAnomalyKind, active bool, message string) {
+		_, wasActive := state.anomalies[kind]
+		switch {
+		case active && !wasActive:
+			anomaly := SyncAnomaly{Kind: kind, Message: message, Since: now}
+			state.anomalies[kind] = anomaly
+			raised = append(raised, anomaly)
+		case !active && wasActive:
+			delete(state.anomalies, kind)
+			metrics.SyncAnomalyActive.WithLabelValues(service, string(kind)).Set(0)
+			logger.WithFields(map[string]interface{}{"service": service, "anomaly": kind}).Info("Sync anomaly resolved")
+		}
+	}
+
+	// Stall: nothing new since the threshold although the indexer wrote blocks after the last event
+	idle := now.Sub(*health.LastEventAt)
+	set(SyncAnomalyStall,
+		m.thresholds.StallAfter > 0 && idle > m.thresholds.StallAfter &&
+			state.blockAtLastEvent >= 0 && health.IndexerMaxBlock > state.blockAtLastEvent,
+		fmt.Sprintf("no new events for %s while the indexer advanced from block %d to %d",
+			idle.Truncate(time.Second), state.blockAtLastEvent, health.IndexerMaxBlock))
+
+	// Failure ratio of this cycle; a failed cycle counts as 100%
+	var failurePercent float64
+	switch {
+	case stats.Err != nil:
+		failurePercent = 100
+	case stats.Events > 0:
+		failurePercent = float64(stats.Failed) * 100 / float64(stats.Events)
+	}
+	failureMessage := fmt.Sprintf("%d of %d events failed (%.0f%%, threshold %.0f%%)", stats.Failed, stats.Events, failurePercent, m.thresholds.MaxFailurePercent)
+	if stats.Err != nil {
+		failureMessage = "cycle failed: " + stats.Err.Error()
+	}
+	set(SyncAnomalyFailureRatio, m.thresholds.MaxFailurePercent > 0 && failurePercent > m.thresholds.MaxFailurePercent, failureMessage)
+
+	// Cursor regression stays flagged until the cursor is back where it dropped from
+	if ok && health.Cursor < previousCursor {
+		if _, active := state.anomalies[SyncAnomalyCursorRegression]; !active {
+			state.regressedFrom = previousCursor
+		}
+	}
+	regressed := state.regressedFrom > 0 && health.Cursor < state.regressedFrom
+	if !regressed {
+		state.regressedFrom = 0
+	}
+	set(SyncAnomalyCursorRegression, regressed,
+		fmt.Sprintf("last processed ID went back from %d to %d", state.regressedFrom, health.Cursor))
+
+	health.Anomalies = sortedAnomalies(state.anomalies)
+	health.Healthy = len(health.Anomalies) == 0
+	m.mu.Unlock()
+
+	// Alerts go out without the lock, so a slow webhook never blocks Health
+	for _, anomaly := range raised {
+		metrics.SyncAnomalies.WithLabelValues(service, string(anomaly.Kind)).Inc()
+		metrics.SyncAnomalyActive.WithLabelValues(service, string(anomaly.Kind)).Set(1)
+		logger.WithFields(map[string]interface{}{
+			"service": service,
+			"anomaly": anomaly.Kind,
+		}).Error("Sync anomaly: " + anomaly.Message)
+		if m.alerter != nil {
+			if err := m.alerter.Alert(ctx, service, anomaly); err != nil {
+				logger.WithError(err).WithField("service", service).Warn("Failed to send sync anomaly alert")
+			}
+		}
+	}
+}
+
+// Health returns the state of every service that has reported a cycle, by service name
+func (m *SyncHealthMonitor) Health() []SyncServiceHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]SyncServiceHealth, 0, len(m.services))
+	for _, state := range m.services {
+		health := state.health
+		health.Anomalies = append([]SyncAnomaly{}, health.Anomalies...)
+		result = append(result, health)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Service < result[j].Service })
+	return result
+}
+
+// AddLock reports who holds lock alongside the services' health
+func (m *SyncHealthMonitor) AddLock(lock *SyncLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.locks = append(m.locks, lock)
+}
+
+// Locks returns who holds each added sync lock, read from their heartbeats
+func (m *SyncHealthMonitor) Locks(ctx context.Context) []SyncLockStatus {
+	m.mu.Lock()
+	locks := append([]*SyncLock{}, m.locks...)
+	m.mu.Unlock()
+
+	result := make([]SyncLockStatus, 0, len(locks))
+	for _, lock := range locks {
+		status, err := lock.Status(ctx)
+		if err != nil {
+			logger.WithError(err).WithField("service", lock.service).Warn("Failed to read the sync lock heartbeat")
+			continue
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+// sortedAnomalies lists raised flags by kind
+func sortedAnomalies(anomalies map[SyncAnomalyKind]SyncAnomaly) []SyncAnomaly {
+	result := make([]SyncAnomaly, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		result = append(result, anomaly)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Kind < result[j].Kind })
+	return result
+}
+
+// WebhookSyncAlerter posts raised anomalies as JSON to a URL
+type WebhookSyncAlerter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSyncAlerter creates an alerter posting to url
+func NewWebhookSyncAlerter(url string) *WebhookSyncAlerter {
+	return &WebhookSyncAlerter{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// syncAlertPayload is the body of an anomaly webhook
+type syncAlertPayload struct {
+	Service string          `json:"service"`
+	Kind    SyncAnomalyKind `json:"kind"`
+	Message string          `json:"message"`
+	Since   time.Time       `json:"since"`
+}
+
+// Alert posts the anomaly, failing on any non-2xx response
+func (a *WebhookSyncAlerter) Alert(ctx context.Context, service string, anomaly SyncAnomaly) error {
+	body, err := json.Marshal(syncAlertPayload{Service: service, Kind: anomaly.Kind, Message: anomaly.Message, Since: anomaly.Since})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post sync alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sync alert webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// indexerHeadReader reads how far the indexer has written the tables a sync service reads
+type indexerHeadReader interface {
+	GetMaxBlockNumber(ctx context.Context, eventTypes ...string) (int64, error)
+}
+
+// indexerMaxBlock is the head of eventTypes, or -1 when it can't be read
+func indexerMaxBlock(ctx context.Context, head indexerHeadReader, eventTypes []string) int64 {
+	if head == nil {
+		return -1
+	}
+	block, err := head.GetMaxBlockNumber(ctx, eventTypes...)
+	if err != nil {
+		logger.WithError(err).Debug("Failed to read the indexer head for sync health")
+		return -1
+	}
+	return block
+}
+
+// metadataSyncHeadEvents are the indexer events a metadata sync cycle turns into work: new sukuk,
+// settled orders and referrals, and ledger movements
+var metadataSyncHeadEvents = []string{"sukuk_creation", "sukuk_purchase", "redemption_approval", "yield_claim"}
+
+// activityStreamHeadEvents are the indexer events the activity stream publishes
+var activityStreamHeadEvents = []string{"sukuk_purchase", "redemption_request"}
+
+// SetHealthMonitor reports every sync cycle, scheduled or manual, to monitor
+func (s *SukukMetadataSyncService) SetHealthMonitor(monitor *SyncHealthMonitor) {
+	s.health = monitor
+}
+
+// recordHealth reports a finished cycle; ledger movements count as events alongside the rest
+func (s *SukukMetadataSyncService) recordHealth(ctx context.Context, result *SyncResult, err error) {
+	if s.health == nil {
+		return
+	}
+	stats := SyncCycleStats{
+		Err:             err,
+		Cursor:          s.lastProcessedID,
+		IndexerMaxBlock: indexerMaxBlock(ctx, s.head, metadataSyncHeadEvents),
+	}
+	if result != nil {
+		stats.Events = result.Processed + result.Failed + result.LedgerRecorded
+		stats.Failed = result.Failed
+	}
+	s.health.RecordCycle(ctx, SyncServiceMetadata, stats)
+}
+
+// SetHealthMonitor reports every poll to monitor
+func (s *ActivityStreamService) SetHealthMonitor(monitor *SyncHealthMonitor) {
+	s.health = monitor
+}
+
+// recordHealth reports a finished poll, with the timestamp cursor as its last processed ID
+func (s *ActivityStreamService) recordHealth(ctx context.Context, published int, err error) {
+	if s.health == nil {
+		return
+	}
+	s.health.RecordCycle(ctx, SyncServiceActivity, SyncCycleStats{
+		Events:          published,
+		Err:             err,
+		Cursor:          uint64(s.cursor),
+		IndexerMaxBlock: indexerMaxBlock(ctx, s.head, activityStreamHeadEvents),
+	})
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// fakeClock is a settable clock for the monitor
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+func newFakeClock() *fakeClock               { return &fakeClock{now: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)} }
+
+type recordingAlerter struct {
+	alerts []SyncAnomaly
+}
+
+func (a *recordingAlerter) Alert(ctx context.Context, service string, anomaly SyncAnomaly) error {
+	a.alerts = append(a.alerts, anomaly)
+	return nil
+}
+
+func newTestMonitor(clock *fakeClock, alerter SyncAlerter) *SyncHealthMonitor {
+	monitor := NewSyncHealthMonitor(SyncThresholds{StallAfter: time.Hour, MaxFailurePercent: 50}, alerter)
+	monitor.now = clock.Now
+	return monitor
+}
+
+func serviceHealth(t *testing.T, monitor *SyncHealthMonitor, service string) SyncServiceHealth {
+	t.Helper()
+	for _, health := range monitor.Health() {
+		if health.Service == service {
+			return health
+		}
+	}
+	t.Fatalf("No health reported for %s", service)
+	return SyncServiceHealth{}
+}
+
+func hasAnomaly(health SyncServiceHealth, kind SyncAnomalyKind) bool {
+	for _, anomaly := range health.Anomalies {
+		if anomaly.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncHealthFlagsStallWhileIndexerAdvances(t *testing.T) {
+	clock := newFakeClock()
+	alerter := &recordingAlerter{}
+	monitor := newTestMonitor(clock, alerter)
+	ctx := context.Background()
+
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Events: 3, IndexerMaxBlock: 100})
+
+	// Three hours of empty cycles while the indexer keeps writing blocks
+	block := int64(100)
+	for i := 0; i < 36; i++ {
+		clock.Advance(5 * time.Minute)
+		block += 10
+		monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{IndexerMaxBlock: block})
+		health := serviceHealth(t, monitor, SyncServiceMetadata)
+		stalled := clock.now.Sub(*health.LastEventAt) > time.Hour
+		if hasAnomaly(health, SyncAnomalyStall) != stalled {
+			t.Fatalf("After %s idle: expected stall flagged %v, got %+v", clock.now.Sub(*health.LastEventAt), stalled, health.Anomalies)
+		}
+	}
+	if len(alerter.alerts) != 1 || alerter.alerts[0].Kind != SyncAnomalyStall {
+		t.Errorf("Expected one stall alert for the whole stall, got %+v", alerter.alerts)
+	}
+
+	// Events resume and the flag clears
+	clock.Advance(5 * time.Minute)
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Events: 1, IndexerMaxBlock: block})
+	if health := serviceHealth(t, monitor, SyncServiceMetadata); !health.Healthy {
+		t.Errorf("Expected the stall cleared once events resume, got %+v", health.Anomalies)
+	}
+}
+
+func TestSyncHealthIgnoresQuietIndexer(t *testing.T) {
+	clock := newFakeClock()
+	monitor := newTestMonitor(clock, nil)
+	ctx := context.Background()
+
+	monitor.RecordCycle(ctx, SyncServiceActivity, SyncCycleStats{Events: 1, IndexerMaxBlock: 100})
+	clock.Advance(3 * time.Hour)
+	monitor.RecordCycle(ctx, SyncServiceActivity, SyncCycleStats{IndexerMaxBlock: 100})
+	clock.Advance(time.Hour)
+	monitor.RecordCycle(ctx, SyncServiceActivity, SyncCycleStats{IndexerMaxBlock: -1})
+
+	if health := serviceHealth(t, monitor, SyncServiceActivity); !health.Healthy {
+		t.Errorf("Expected no stall while the indexer head stands still or is unknown, got %+v", health.Anomalies)
+	}
+}
+
+func TestSyncHealthFlagsFailureRatio(t *testing.T) {
+	clock := newFakeClock()
+	alerter := &recordingAlerter{}
+	monitor := newTestMonitor(clock, alerter)
+	ctx := context.Background()
+
+	tests := []struct {
+		stats SyncCycleStats
+		want  bool
+	}{
+		{SyncCycleStats{Events: 10, Failed: 5}, false},
+		{SyncCycleStats{Events: 10, Failed: 6}, true},
+		{SyncCycleStats{Events: 4, Failed: 4}, true},
+		{SyncCycleStats{}, false},
+		{SyncCycleStats{Err: errors.New("indexer unreachable")}, true},
+	}
+	for i, tt := range tests {
+		clock.Advance(time.Minute)
+		monitor.RecordCycle(ctx, SyncServiceMetadata, tt.stats)
+		if got := hasAnomaly(serviceHealth(t, monitor, SyncServiceMetadata), SyncAnomalyFailureRatio); got != tt.want {
+			t.Errorf("Cycle %d %+v: expected failure_ratio flagged %v, got %v", i, tt.stats, tt.want, got)
+		}
+	}
+	if len(alerter.alerts) != 2 {
+		t.Errorf("Expected an alert each time the flag was raised, got %+v", alerter.alerts)
+	}
+}
+
+func TestSyncHealthFlagsCursorRegression(t *testing.T) {
+	clock := newFakeClock()
+	monitor := newTestMonitor(clock, nil)
+	ctx := context.Background()
+
+	for _, cursor := range []uint64{0, 120, 150} {
+		monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Cursor: cursor})
+	}
+	if health := serviceHealth(t, monitor, SyncServiceMetadata); !health.Healthy {
+		t.Fatalf("Expected an advancing cursor to be healthy, got %+v", health.Anomalies)
+	}
+
+	// A reset of the persisted state stays flagged until the cursor is back past 150
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Cursor: 0})
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Cursor: 140})
+	if health := serviceHealth(t, monitor, SyncServiceMetadata); !hasAnomaly(health, SyncAnomalyCursorRegression) {
+		t.Fatalf("Expected cursor_regression flagged, got %+v", health.Anomalies)
+	}
+
+	// A failed cycle reports no cursor and is not a regression
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Cursor: 150})
+	monitor.RecordCycle(ctx, SyncServiceMetadata, SyncCycleStats{Err: errors.New("timeout")})
+	if health := serviceHealth(t, monitor, SyncServiceMetadata); hasAnomaly(health, SyncAnomalyCursorRegression) || health.Cursor != 150 {
+		t.Errorf("Expected the regression cleared at 150, got cursor %d and %+v", health.Cursor, health.Anomalies)
+	}
+}
+
+func TestActivityStreamReportsPolls(t *testing.T) {
+	clock := newFakeClock()
+	monitor := newTestMonitor(clock, nil)
+	source := &fakeActivitySource{activities: []models.ActivityEvent{streamActivity(100, "0x1"), streamActivity(101, "0x2")}}
+	s := &ActivityStreamService{source: source, publisher: &recordingPublisher{}, cursor: 100, seen: map[string]struct{}{}, health: monitor}
+
+	s.poll(context.Background())
+
+	health := serviceHealth(t, monitor, SyncServiceActivity)
+	if health.Cycles != 1 || health.LastCycleEvents != 2 || health.Cursor != 101 || health.IndexerMaxBlock != 0 {
+		t.Errorf("Unexpected activity stream health %+v", health)
+	}
+}
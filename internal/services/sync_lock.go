@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultSyncLockStaleAfter is how old a holder's heartbeat may get before the lock is reported stale
+const DefaultSyncLockStaleAfter = 2 * time.Minute
+
+// ErrSyncLockHeld is returned for a cycle skipped because another instance holds the service's lock
+var ErrSyncLockHeld = fmt.Errorf("%w on another instance", ErrSyncInProgress)
+
+// syncLockStatePrefix prefixes the system state key of a lock's heartbeat
+const syncLockStatePrefix = "sync_lock:"
+
+// SyncLockHolder is the instance holding a sync lock, as written to its heartbeat
+type SyncLockHolder struct {
+	Instance    string    `json:"instance"`
+	AcquiredAt  time.Time `json:"acquired_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+}
+
+// SyncLockStatus is who holds a sync lock, as reported by GET /admin/sync/health
+type SyncLockStatus struct {
+	Service string          `json:"service"`
+	Held    bool            `json:"held"`
+	Holder  *SyncLockHolder `json:"holder,omitempty"`
+	Mine    bool            `json:"mine"`  // Held by the instance answering
+	Stale   bool            `json:"stale"` // The holder stopped writing its heartbeat, e.g. it hung
+}
+
+// SyncLock keeps two replicas from running a sync service's cycle at once, e.g. during a
+// rolling deploy, with a Postgres session advisory lock keyed by the service name. Each cycle
+// takes it with pg_try_advisory_lock and releases it at the end. While held, the holder
+// writes a heartbeat to system_states; Postgres frees the lock of a process that died with its
+// connection, so a heartbeat older than the stale threshold means a holder that hung
+type SyncLock struct {
+	db         *gorm.DB
+	service    string
+	key        int64
+	instance   string
+	staleAfter time.Duration
+	now        func() time.Time
+
+	mu   sync.Mutex
+	held bool // Held by this instance
+}
+
+// NewSyncLock creates the lock of service for this instance; staleAfter defaults to
+// DefaultSyncLockStaleAfter and instance to DefaultSyncInstanceID
+func NewSyncLock(db *gorm.DB, service, instance string, staleAfter time.Duration) *SyncLock {
+	if staleAfter <= 0 {
+		staleAfter = DefaultSyncLockStaleAfter
+	}
+	if instance == "" {
+		instance = DefaultSyncInstanceID()
+	}
+	return &SyncLock{
+		db:         db,
+		service:    service,
+		key:        syncLockKey(service),
+		instance:   instance,
+		staleAfter: staleAfter,
+		now:        time.Now,
+	}
+}
+
+// DefaultSyncInstanceID names this process by host and PID
+func DefaultSyncInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// syncLockKey derives the advisory lock key of a service from its name
+func syncLockKey(service string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("sukuk-be:" + service))
+	return int64(h.Sum64())
+}
+
+// SyncLockLease is a held sync lock; Release must be called once the cycle ends
+type SyncLockLease struct {
+	lock      *SyncLock
+	conn      *sql.Conn
+	stop      context.CancelFunc
+	heartbeat chan struct{} // Closed when the heartbeat loop exits
+}
+
+// TryAcquire takes the lock without waiting. It returns ErrSyncLockHeld, logging the holder,
+// when another instance holds it
+func (l *SyncLock) TryAcquire(ctx context.Context) (*SyncLockLease, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// Advisory locks belong to a session, so the lock and unlock run on one connection
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a connection for the %s lock: %w", l.service, err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take the %s lock: %w", l.service, err)
+	}
+	if !acquired {
+		conn.Close()
+		l.logHeld(ctx)
+		return nil, ErrSyncLockHeld
+	}
+
+	now := l.now()
+	holder := SyncLockHolder{Instance: l.instance, AcquiredAt: now, HeartbeatAt: now}
+	if err := l.writeHeartbeat(ctx, holder); err != nil {
+		logger.WithError(err).WithField("service", l.service).Warn("Failed to write the sync lock heartbeat")
+	}
+	l.mu.Lock()
+	l.held = true
+	l.mu.Unlock()
+
+	heartbeatCtx, stop := context.WithCancel(context.Background())
+	lease := &SyncLockLease{lock: l, conn: conn, stop: stop, heartbeat: make(chan struct{})}
+	go lease.keepAlive(heartbeatCtx, holder)
+	return lease, nil
+}
+
+// keepAlive refreshes the heartbeat while the lease is held, so a long cycle isn't reported stale
+func (lease *SyncLockLease) keepAlive(ctx context.Context, holder SyncLockHolder) {
+	defer close(lease.heartbeat)
+	ticker := time.NewTicker(lease.lock.staleAfter / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			holder.HeartbeatAt = lease.lock.now()
+			if err := lease.lock.writeHeartbeat(ctx, holder); err != nil && ctx.Err() == nil {
+				logger.WithError(err).WithField("service", lease.lock.service).Warn("Failed to write the sync lock heartbeat")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Release clears the heartbeat and unlocks. A connection that fails to unlock is discarded
+// rather than returned to the pool, which would keep the lock held
+func (lease *SyncLockLease) Release() {
+	l := lease.lock
+	lease.stop()
+	<-lease.heartbeat
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.db.WithContext(ctx).Where("key = ?", syncLockStatePrefix+l.service).Delete(&models.SystemState{}).Error; err != nil {
+		logger.WithError(err).WithField("service", l.service).Warn("Failed to clear the sync lock heartbeat")
+	}
+	if _, err := lease.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		logger.WithError(err).WithField("service", l.service).Error("Failed to release the sync lock, closing its connection")
+		lease.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	lease.conn.Close()
+
+	l.mu.Lock()
+	l.held = false
+	l.mu.Unlock()
+}
+
+// writeHeartbeat records holder in system_states
+func (l *SyncLock) writeHeartbeat(ctx context.Context, holder SyncLockHolder) error {
+	value, err := json.Marshal(holder)
+	if err != nil {
+		return err
+	}
+	return l.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&models.SystemState{Key: syncLockStatePrefix + l.service, Value: string(value)}).Error
+}
+
+// Status reads who holds the lock from its heartbeat
+func (l *SyncLock) Status(ctx context.Context) (SyncLockStatus, error) {
+	state, err := models.GetSystemState(l.db.WithContext(ctx), syncLockStatePrefix+l.service)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return l.status(nil), nil
+	}
+	if err != nil {
+		return SyncLockStatus{Service: l.service}, err
+	}
+	var holder SyncLockHolder
+	if err := json.Unmarshal([]byte(state.Value), &holder); err != nil {
+		return SyncLockStatus{Service: l.service}, fmt.Errorf("invalid %s lock heartbeat: %w", l.service, err)
+	}
+	return l.status(&holder), nil
+}
+
+// status describes the lock given its holder's heartbeat, nil when nobody holds it
+func (l *SyncLock) status(holder *SyncLockHolder) SyncLockStatus {
+	status := SyncLockStatus{Service: l.service, Holder: holder, Held: holder != nil}
+	if holder != nil {
+		l.mu.Lock()
+		status.Mine = l.held && holder.Instance == l.instance
+		l.mu.Unlock()
+		status.Stale = l.now().Sub(holder.HeartbeatAt) > l.staleAfter
+	}
+	return status
+}
+
+// logHeld logs the skipped cycle with the instance holding the lock, as an error when its
+// heartbeat is stale
+func (l *SyncLock) logHeld(ctx context.Context) {
+	status, err := l.Status(ctx)
+	if err != nil || status.Holder == nil {
+		logger.WithField("service", l.service).Warn("Sync lock held by another instance, skipping cycle")
+		return
+	}
+	fields := map[string]interface{}{
+		"service":      l.service,
+		"holder":       status.Holder.Instance,
+		"heartbeat_at": status.Holder.HeartbeatAt,
+	}
+	if status.Stale {
+		logger.WithFields(fields).Errorf("Sync lock held by %s, whose heartbeat is older than %s; it may have hung, skipping cycle", status.Holder.Instance, l.staleAfter)
+		return
+	}
+	logger.WithFields(fields).Warnf("Sync lock held by %s, skipping cycle", status.Holder.Instance)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestSyncLockKeys(t *testing.T) {
+	if syncLockKey(SyncServiceMetadata) != syncLockKey(SyncServiceMetadata) {
+		t.Error("Expected the key of a service to be stable across instances")
+	}
+	if syncLockKey(SyncServiceMetadata) == syncLockKey(SyncServiceReorg) {
+		t.Error("Expected services to lock different keys")
+	}
+}
+
+func TestSyncLockStatusStaleness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	lock := NewSyncLock(nil, SyncServiceMetadata, "api-1", time.Minute)
+	lock.now = func() time.Time { return now }
+
+	if status := lock.status(nil); status.Held || status.Stale {
+		t.Errorf("Expected a free lock, got %+v", status)
+	}
+
+	fresh := &SyncLockHolder{Instance: "api-2", AcquiredAt: now.Add(-time.Hour), HeartbeatAt: now.Add(-20 * time.Second)}
+	if status := lock.status(fresh); !status.Held || status.Stale || status.Mine {
+		t.Errorf("Expected a lock held by another instance with a recent heartbeat, got %+v", status)
+	}
+
+	// A long cycle keeps the lock fresh through its heartbeat; a hung one doesn't
+	hung := &SyncLockHolder{Instance: "api-2", AcquiredAt: now.Add(-time.Hour), HeartbeatAt: now.Add(-2 * time.Minute)}
+	if status := lock.status(hung); !status.Stale {
+		t.Errorf("Expected a heartbeat older than a minute to be stale, got %+v", status)
+	}
+
+	lock.held = true
+	if status := lock.status(&SyncLockHolder{Instance: "api-1", HeartbeatAt: now}); !status.Mine {
+		t.Errorf("Expected the lock to be reported as held by this instance, got %+v", status)
+	}
+}
+
+// TestSyncLockAllowsOneInstance requires a reachable Postgres, see testutil.Open
+func TestSyncLockAllowsOneInstance(t *testing.T) {
+	db := testutil.DB(t)
+	previous := database.DB
+	database.DB = db
+	defer func() { database.DB = previous }()
+
+	// A stand-in creation table sorting after any real one, with one new sukuk
+	const table = "zzlk__sukuk_creation"
+	const token = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	db.Exec("DROP TABLE IF EXISTS " + table)
+	err := db.Exec("CREATE TABLE " + table + ` (
+		id TEXT PRIMARY KEY, token_address TEXT, name TEXT, symbol TEXT, issuer TEXT, manager TEXT,
+		max_supply NUMERIC(78,0), maturity_timestamp BIGINT, block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", table, err)
+	}
+	defer db.Exec("DROP TABLE IF EXISTS " + table)
+	cleanup := func() {
+		db.Unscoped().Where("contract_address = ?", token).Delete(&models.SukukMetadata{})
+		db.Where("key = ?", metadataSyncCursorKey).Delete(&models.SystemState{})
+	}
+	cleanup()
+	defer cleanup()
+	err = db.Exec(`INSERT INTO `+table+` VALUES ('0x01', ?, 'Sukuk Lock', 'SLK01', ?, ?, 1000, ?, 10, ?, ?)`,
+		token, token, token, time.Now().AddDate(1, 0, 0).Unix(), "0x"+fmt.Sprintf("%064x", 1), time.Now().Unix()).Error
+	if err != nil {
+		t.Fatalf("Failed to seed the creation event: %v", err)
+	}
+
+	const service = "lock_test"
+	instance := func(name string) *SukukMetadataSyncService {
+		s := NewSukukMetadataSyncService(time.Hour)
+		s.SetSyncLock(NewSyncLock(db, service, name, 0))
+		return s
+	}
+	first, second := instance("first"), instance("second")
+	metadataRows := func() int64 {
+		var count int64
+		db.Model(&models.SukukMetadata{}).Where("contract_address = ?", token).Count(&count)
+		return count
+	}
+
+	// While another instance is mid-cycle, a cycle is skipped without touching events
+	holder := NewSyncLock(db, service, "holder", 0)
+	lease, err := holder.TryAcquire(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to take the lock: %v", err)
+	}
+	if _, err := second.RunOnce(context.Background()); !errors.Is(err, ErrSyncLockHeld) {
+		t.Errorf("Expected the cycle to be skipped for the held lock, got %v", err)
+	}
+	if status, err := holder.Status(context.Background()); err != nil || status.Holder == nil || status.Holder.Instance != "holder" || status.Stale {
+		t.Errorf("Expected the holder in the lock status, got %+v (%v)", status, err)
+	}
+	if metadataRows() != 0 {
+		t.Error("Expected no event processed while the lock was held elsewhere")
+	}
+	lease.Release()
+	if status, _ := holder.Status(context.Background()); status.Held {
+		t.Errorf("Expected the heartbeat to be cleared on release, got %+v", status)
+	}
+
+	// Both instances at once: one syncs the event, the other is skipped or finds it synced
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		processed int
+	)
+	for _, s := range []*SukukMetadataSyncService{first, second} {
+		wg.Add(1)
+		go func(s *SukukMetadataSyncService) {
+			defer wg.Done()
+			result, err := s.RunOnce(context.Background())
+			if err != nil && !errors.Is(err, ErrSyncLockHeld) {
+				t.Errorf("Sync cycle failed: %v", err)
+				return
+			}
+			if result != nil {
+				mu.Lock()
+				processed += result.Processed
+				mu.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+	if processed != 1 || metadataRows() != 1 {
+		t.Errorf("Expected the event processed once, got %d processed and %d rows", processed, metadataRows())
+	}
+}
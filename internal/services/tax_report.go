@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// TaxReportTimezone is the timezone whose calendar year the tax report covers
+const TaxReportTimezone = "Asia/Jakarta"
+
+// taxReportLocation falls back to a fixed UTC+7 zone (Jakarta has no DST) when tzdata is missing
+var taxReportLocation = func() *time.Location {
+	location, err := time.LoadLocation(TaxReportTimezone)
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return location
+}()
+
+// TaxYearRange returns the start (inclusive) and end (exclusive) of a calendar year in Jakarta time
+func TaxYearRange(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, taxReportLocation)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// CurrentTaxYear returns the calendar year in Jakarta at now
+func CurrentTaxYear(now time.Time) int {
+	return now.In(taxReportLocation).Year()
+}
+
+// ValidateTaxYear rejects years before the sukuk launched and years that have not started in Jakarta
+func ValidateTaxYear(year int, now time.Time) error {
+	if year < 2000 {
+		return fmt.Errorf("year must be 2000 or later, got %d", year)
+	}
+	if current := CurrentTaxYear(now); year > current {
+		return fmt.Errorf("year %d is in the future (current year is %d)", year, current)
+	}
+	return nil
+}
+
+// GenerateTaxReport aggregates the yield an address claimed during a calendar year
+func GenerateTaxReport(ctx context.Context, address string, year int) (*models.TaxReport, error) {
+	from, to := TaxYearRange(year)
+
+	indexerService := NewIndexerQueryService()
+	claims, err := indexerService.GetYieldClaimsBetween(ctx, address, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var sukukAddresses []string
+	seen := make(map[string]bool)
+	for _, claim := range claims {
+		if !seen[claim.SukukAddress] {
+			seen[claim.SukukAddress] = true
+			sukukAddresses = append(sukukAddresses, claim.SukukAddress)
+		}
+	}
+
+	distributions, err := indexerService.GetYieldDistributionsBySukuk(ctx, sukukAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata []models.SukukMetadata
+	if len(sukukAddresses) > 0 {
+		normalized := make([]string, len(sukukAddresses))
+		for i, sukukAddress := range sukukAddresses {
+			normalized[i] = utils.NormalizeAddress(sukukAddress)
+		}
+		if err := database.GetDB().WithContext(ctx).Where("contract_address IN ?", normalized).Find(&metadata).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	formatter, err := LoadTokenFormatter()
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildTaxReport(address, year, claims, distributions, metadata, formatter), nil
+}
+
+// BuildTaxReport groups claims by sukuk and payment token and totals them
+// Claims outside the calendar year are ignored; the payment token of a claim is
+// taken from the distribution it was paid from
+func BuildTaxReport(address string, year int, claims []IndexerYieldClaimed, distributions []IndexerYieldDistributed, metadata []models.SukukMetadata, formatter *TokenFormatter) *models.TaxReport {
+	from, to := TaxYearRange(year)
+	report := &models.TaxReport{
+		Address:     address,
+		Year:        year,
+		Timezone:    TaxReportTimezone,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Sukuk:       make([]models.TaxReportSukuk, 0),
+		Totals:      make([]models.TaxReportTotal, 0),
+	}
+
+	paymentTokens := make(map[string]string, len(distributions))
+	for _, distribution := range distributions {
+		paymentTokens[distributionKey(distribution.SukukAddress, distribution.DistributionId)] = distribution.PaymentToken
+	}
+	metadataByAddress := make(map[string]models.SukukMetadata, len(metadata))
+	for _, sukuk := range metadata {
+		metadataByAddress[strings.ToLower(sukuk.ContractAddress)] = sukuk
+	}
+
+	type group struct {
+		line    *models.TaxReportSukuk
+		amounts []string
+	}
+	groups := make(map[string]*group)
+	tokenAmounts := make(map[string][]string)
+
+	for _, claim := range claims {
+		claimedAt := time.Unix(claim.Timestamp, 0).In(taxReportLocation)
+		if claimedAt.Before(from) || !claimedAt.Before(to) {
+			continue
+		}
+
+		sukukAddress := strings.ToLower(claim.SukukAddress)
+		paymentToken := strings.ToLower(paymentTokens[distributionKey(claim.SukukAddress, claim.DistributionId)])
+
+		key := sukukAddress + "|" + paymentToken
+		g, ok := groups[key]
+		if !ok {
+			sukuk := metadataByAddress[sukukAddress]
+			g = &group{line: &models.TaxReportSukuk{
+				SukukAddress:  sukukAddress,
+				SukukCode:     sukuk.SukukCode,
+				SukukTitle:    sukuk.SukukTitle,
+				IssuerAddress: sukuk.OwnerAddress,
+				PaymentToken:  paymentToken,
+				Claims:        make([]models.TaxReportClaim, 0),
+			}}
+			groups[key] = g
+		}
+
+		g.line.Claims = append(g.line.Claims, models.TaxReportClaim{
+			DistributionID: claim.DistributionId,
+			TxHash:         claim.TxHash,
+			ClaimedAt:      claimedAt,
+			Amount:         formatter.FormatTokenAmount(claim.Amount, paymentToken),
+		})
+		g.amounts = append(g.amounts, claim.Amount)
+		tokenAmounts[paymentToken] = append(tokenAmounts[paymentToken], claim.Amount)
+		report.ClaimCount++
+	}
+
+	mathUtil := utils.GlobalTokenMath
+	for _, g := range groups {
+		total, _ := mathUtil.SumValidTokenAmounts(g.amounts)
+		g.line.ClaimCount = len(g.line.Claims)
+		g.line.Total = formatter.FormatTokenAmount(total, g.line.PaymentToken)
+		report.Sukuk = append(report.Sukuk, *g.line)
+	}
+	sort.Slice(report.Sukuk, func(i, j int) bool {
+		a, b := report.Sukuk[i], report.Sukuk[j]
+		if a.SukukCode != b.SukukCode {
+			return a.SukukCode < b.SukukCode
+		}
+		if a.SukukAddress != b.SukukAddress {
+			return a.SukukAddress < b.SukukAddress
+		}
+		return a.PaymentToken < b.PaymentToken
+	})
+
+	for paymentToken, amounts := range tokenAmounts {
+		total, _ := mathUtil.SumValidTokenAmounts(amounts)
+		report.Totals = append(report.Totals, models.TaxReportTotal{
+			PaymentToken: paymentToken,
+			ClaimCount:   len(amounts),
+			Total:        formatter.FormatTokenAmount(total, paymentToken),
+		})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].PaymentToken < report.Totals[j].PaymentToken
+	})
+
+	return report
+}
+
+func distributionKey(sukukAddress string, distributionID int64) string {
+	return strings.ToLower(sukukAddress) + "|" + strconv.FormatInt(distributionID, 10)
+}
+
+// TaxReportRenderer writes a tax report in one output format
+type TaxReportRenderer interface {
+	ContentType() string
+	FileExtension() string
+	Render(w io.Writer, report *models.TaxReport) error
+}
+
+var taxReportRenderers = map[string]TaxReportRenderer{
+	"json": jsonTaxReportRenderer{},
+	"csv":  csvTaxReportRenderer{},
+}
+
+// GetTaxReportRenderer returns the renderer for a format, e.g. "json" or "csv"
+func GetTaxReportRenderer(format string) (TaxReportRenderer, bool) {
+	renderer, ok := taxReportRenderers[format]
+	return renderer, ok
+}
+
+// TaxReportFormats lists the supported tax report formats
+func TaxReportFormats() []string {
+	formats := make([]string, 0, len(taxReportRenderers))
+	for format := range taxReportRenderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+type jsonTaxReportRenderer struct{}
+
+func (jsonTaxReportRenderer) ContentType() string   { return "application/json; charset=utf-8" }
+func (jsonTaxReportRenderer) FileExtension() string { return "json" }
+
+func (jsonTaxReportRenderer) Render(w io.Writer, report *models.TaxReport) error {
+	return json.NewEncoder(w).Encode(report)
+}
+
+// csvTaxReportRenderer writes one row per claim, then a subtotal row per sukuk and a total row per payment token
+type csvTaxReportRenderer struct{}
+
+func (csvTaxReportRenderer) ContentType() string   { return "text/csv; charset=utf-8" }
+func (csvTaxReportRenderer) FileExtension() string { return "csv" }
+
+func (csvTaxReportRenderer) Render(w io.Writer, report *models.TaxReport) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{
+		"row_type", "sukuk_code", "sukuk_title", "sukuk_address", "issuer_address",
+		"distribution_id", "claimed_at", "tx_hash", "payment_token", "symbol", "amount_wei", "amount",
+	}}
+
+	for _, sukuk := range report.Sukuk {
+		for _, claim := range sukuk.Claims {
+			rows = append(rows, []string{
+				"claim", sukuk.SukukCode, sukuk.SukukTitle, sukuk.SukukAddress, sukuk.IssuerAddress,
+				strconv.FormatInt(claim.DistributionID, 10), claim.ClaimedAt.Format(time.RFC3339), claim.TxHash,
+				sukuk.PaymentToken, claim.Amount.Symbol, claim.Amount.Amount, claim.Amount.Formatted,
+			})
+		}
+		rows = append(rows, []string{
+			"sukuk_total", sukuk.SukukCode, sukuk.SukukTitle, sukuk.SukukAddress, sukuk.IssuerAddress,
+			"", "", "", sukuk.PaymentToken, sukuk.Total.Symbol, sukuk.Total.Amount, sukuk.Total.Formatted,
+		})
+	}
+	for _, total := range report.Totals {
+		rows = append(rows, []string{
+			"total", "", "", "", "", "", "", "",
+			total.PaymentToken, total.Total.Symbol, total.Total.Amount, total.Total.Formatted,
+		})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
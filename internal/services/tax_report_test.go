@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func TestTaxYearRangeUsesJakartaBoundaries(t *testing.T) {
+	from, to := TaxYearRange(2024)
+
+	// Midnight on 1 January in Jakarta (UTC+7) is 17:00 UTC on 31 December
+	if want := time.Date(2023, 12, 31, 17, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("Expected the year to start at %s, got %s", want, from.UTC())
+	}
+	if want := time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("Expected the year to end at %s, got %s", want, to.UTC())
+	}
+}
+
+func TestValidateTaxYear(t *testing.T) {
+	// 18:00 UTC on 31 December 2024 is already 2025 in Jakarta
+	now := time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+
+	for _, year := range []int{2020, 2024, 2025} {
+		if err := ValidateTaxYear(year, now); err != nil {
+			t.Errorf("Expected %d to be valid, got %v", year, err)
+		}
+	}
+	for _, year := range []int{2026, 1999} {
+		if err := ValidateTaxYear(year, now); err == nil {
+			t.Errorf("Expected %d to be rejected", year)
+		}
+	}
+}
+
+func TestBuildTaxReport(t *testing.T) {
+	const (
+		user   = "0x00000000000000000000000000000000000000aa"
+		sukukA = "0x00000000000000000000000000000000000000b1"
+		sukukB = "0x00000000000000000000000000000000000000B2"
+		idrx   = "0x00000000000000000000000000000000000000c1"
+		usdc   = "0x00000000000000000000000000000000000000c2"
+	)
+	formatter := NewTokenFormatter([]models.PaymentToken{
+		{Address: idrx, Symbol: "IDRX", Decimals: 2},
+		{Address: usdc, Symbol: "USDC", Decimals: 6},
+	})
+	distributions := []IndexerYieldDistributed{
+		{SukukAddress: sukukA, DistributionId: 1, PaymentToken: idrx},
+		{SukukAddress: sukukA, DistributionId: 2, PaymentToken: idrx},
+		{SukukAddress: sukukA, DistributionId: 3, PaymentToken: idrx},
+		{SukukAddress: sukukB, DistributionId: 1, PaymentToken: usdc},
+	}
+	metadata := []models.SukukMetadata{
+		{ContractAddress: sukukA, SukukCode: "SR1", SukukTitle: "Sukuk Ritel 1", OwnerAddress: "0x00000000000000000000000000000000000000dd"},
+	}
+	claim := func(sukuk string, distributionID int64, amount string, at time.Time) IndexerYieldClaimed {
+		return IndexerYieldClaimed{User: user, SukukAddress: sukuk, DistributionId: distributionID, Amount: amount, Timestamp: at.Unix(), TxHash: "0x" + amount}
+	}
+	claims := []IndexerYieldClaimed{
+		claim(sukukA, 1, "100", time.Date(2023, 12, 31, 16, 59, 59, 0, time.UTC)), // 23:59:59 on 31 Dec 2023 in Jakarta
+		claim(sukukA, 2, "250", time.Date(2023, 12, 31, 17, 0, 0, 0, time.UTC)),   // 00:00 on 1 Jan 2024 in Jakarta
+		claim(sukukA, 3, "1000", time.Date(2024, 12, 31, 16, 59, 59, 0, time.UTC)),
+		claim(sukukB, 1, "1500000", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		claim(sukukA, 3, "7", time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC)), // already 2025 in Jakarta
+	}
+
+	report := BuildTaxReport(user, 2024, claims, distributions, metadata, formatter)
+
+	if report.ClaimCount != 3 {
+		t.Fatalf("Expected 3 claims within 2024 Jakarta time, got %d", report.ClaimCount)
+	}
+	if len(report.Sukuk) != 2 {
+		t.Fatalf("Expected 2 sukuk lines, got %+v", report.Sukuk)
+	}
+
+	// Sukuk without metadata sort first on their empty code
+	b, a := report.Sukuk[0], report.Sukuk[1]
+	if a.SukukCode != "SR1" || a.IssuerAddress != metadata[0].OwnerAddress || a.ClaimCount != 2 {
+		t.Errorf("Unexpected SR1 line: %+v", a)
+	}
+	if a.Total.Amount != "1250" || a.Total.Formatted != "12.5" || a.Total.Symbol != "IDRX" {
+		t.Errorf("Expected SR1 total 1250 wei (12.5 IDRX), got %+v", a.Total)
+	}
+	if first := a.Claims[0].ClaimedAt; first.Year() != 2024 || first.Hour() != 0 {
+		t.Errorf("Expected claim times in Jakarta time, got %s", first)
+	}
+	if b.SukukAddress != "0x00000000000000000000000000000000000000b2" || b.Total.Formatted != "1.5" || b.Total.Symbol != "USDC" {
+		t.Errorf("Expected the second sukuk total in USDC decimals, got %+v", b)
+	}
+
+	if len(report.Totals) != 2 {
+		t.Fatalf("Expected a grand total per payment token, got %+v", report.Totals)
+	}
+	if report.Totals[0].PaymentToken != idrx || report.Totals[0].Total.Amount != "1250" || report.Totals[1].Total.Amount != "1500000" {
+		t.Errorf("Unexpected grand totals: %+v", report.Totals)
+	}
+}
+
+func TestBuildTaxReportEmptyYear(t *testing.T) {
+	report := BuildTaxReport("0x00000000000000000000000000000000000000aa", 2022, nil, nil, nil, NewTokenFormatter(nil))
+
+	if report.ClaimCount != 0 || report.Sukuk == nil || len(report.Sukuk) != 0 || report.Totals == nil || len(report.Totals) != 0 {
+		t.Errorf("Expected an empty report with empty arrays, got %+v", report)
+	}
+}
+
+func TestCSVTaxReportRenderer(t *testing.T) {
+	formatter := NewTokenFormatter([]models.PaymentToken{{Address: "0xc1", Symbol: "IDRX", Decimals: 2}})
+	claims := []IndexerYieldClaimed{
+		{SukukAddress: "0xb1", DistributionId: 1, Amount: "150", Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix(), TxHash: "0x01"},
+	}
+	distributions := []IndexerYieldDistributed{{SukukAddress: "0xb1", DistributionId: 1, PaymentToken: "0xc1"}}
+	report := BuildTaxReport("0xaa", 2024, claims, distributions, nil, formatter)
+
+	renderer, ok := GetTaxReportRenderer("csv")
+	if !ok {
+		t.Fatal("Expected a csv renderer")
+	}
+	if _, ok := GetTaxReportRenderer("pdf"); ok {
+		t.Error("Expected pdf not to be supported yet")
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse csv: %v", err)
+	}
+
+	wantTypes := []string{"row_type", "claim", "sukuk_total", "total"}
+	if len(rows) != len(wantTypes) {
+		t.Fatalf("Expected %d rows, got %v", len(wantTypes), rows)
+	}
+	for i, want := range wantTypes {
+		if rows[i][0] != want {
+			t.Errorf("Row %d: expected %s, got %s", i, want, rows[i][0])
+		}
+	}
+	if claim := rows[1]; claim[10] != "150" || claim[11] != "1.5" || claim[6] != "2024-03-01T07:00:00+07:00" {
+		t.Errorf("Unexpected claim row: %v", claim)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// balanceDeltaRow is a holder_update row with the signed change from the holder's previous
+// balance on the sukuk, as a raw integer string
+type balanceDeltaRow struct {
+	ID           string `gorm:"column:id"`
+	SukukAddress string `gorm:"column:sukuk_address"`
+	Holder       string `gorm:"column:holder"`
+	TxHash       string `gorm:"column:tx_hash"`
+	BlockNumber  int64  `gorm:"column:block_number"`
+	Timestamp    int64  `gorm:"column:timestamp"`
+	Delta        string `gorm:"column:delta"`
+}
+
+// incoming reports whether the balance went up
+func (r balanceDeltaRow) incoming() bool {
+	return !strings.HasPrefix(r.Delta, "-")
+}
+
+// GetUserTransfers returns the newest balance changes of an address that no purchase,
+// redemption request or yield claim on the same sukuk in the same transaction explains,
+// such as plain ERC-20 transfers between wallets. activityType selects transfer_in or
+// transfer_out, empty for both. The counterparty is set when exactly one other wallet's
+// balance moved the opposite way in the transaction
+func (s *IndexerQueryService) GetUserTransfers(ctx context.Context, userAddress string, activityType models.ActivityType, limit int) ([]models.TransactionEvent, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	holderTable, err := s.tableService.GetLatestTableForEvent("holder_update")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find holder_update table: %w", err)
+	}
+
+	conditions := []string{"c.change <> 0"}
+	switch activityType {
+	case models.ActivityTypeTransferIn:
+		conditions = append(conditions, "c.change > 0")
+	case models.ActivityTypeTransferOut:
+		conditions = append(conditions, "c.change < 0")
+	}
+	// Balance changes of activities are already listed as those activities
+	for _, info := range models.EventActivityTypes() {
+		eventTable, err := s.tableService.GetLatestTableForEvent(info.EventTable)
+		if err != nil {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s e WHERE e.tx_hash = c.tx_hash AND LOWER(e.sukuk_address) = LOWER(c.sukuk_address))", quoteIdentifier(eventTable)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT c.id, c.sukuk_address, c.holder, c.tx_hash, c.block_number, c.timestamp, c.change::text AS delta
+		FROM (
+			SELECT h.id, h.sukuk_address, h.holder, h.tx_hash, h.block_number, h.timestamp,
+				h.new_balance - COALESCE(LAG(h.new_balance) OVER (PARTITION BY LOWER(h.sukuk_address) ORDER BY %s), 0) AS change
+			FROM %s h
+			WHERE LOWER(h.holder) = LOWER(?)
+		) c
+		WHERE %s
+		ORDER BY c.timestamp DESC, c.id DESC
+		LIMIT ?`, holderUpdateOrder, quoteIdentifier(holderTable), strings.Join(conditions, " AND "))
+
+	var rows []balanceDeltaRow
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Raw(query, userAddress, limit).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers from %s: %w", holderTable, err)
+	}
+	if len(rows) == 0 {
+		return []models.TransactionEvent{}, nil
+	}
+
+	txHashes := make([]string, len(rows))
+	for i, row := range rows {
+		txHashes[i] = row.TxHash
+	}
+
+	// The other side of each transfer is another holder updated in the same transaction
+	var others []balanceDeltaRow
+	err = s.read(ctx, func(db *gorm.DB) error {
+		return db.Raw(fmt.Sprintf(`
+			SELECT h.id, h.sukuk_address, h.holder, h.tx_hash, h.block_number, h.timestamp,
+				(h.new_balance - COALESCE((
+					SELECT p.new_balance FROM %[1]s p
+					WHERE LOWER(p.holder) = LOWER(h.holder) AND LOWER(p.sukuk_address) = LOWER(h.sukuk_address)
+						AND (p.block_number, p.timestamp, p.id) < (h.block_number, h.timestamp, h.id)
+					ORDER BY p.block_number DESC, p.timestamp DESC, p.id DESC
+					LIMIT 1), 0))::text AS delta
+			FROM %[1]s h
+			WHERE h.tx_hash IN ? AND LOWER(h.holder) <> LOWER(?)`, quoteIdentifier(holderTable)), txHashes, userAddress).Scan(&others).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer counterparties from %s: %w", holderTable, err)
+	}
+
+	return buildTransfers(rows, others), nil
+}
+
+// buildTransfers turns an address's unexplained balance changes into transfer events, taking
+// the counterparty from others, the balance changes of other holders in the same transactions
+func buildTransfers(rows []balanceDeltaRow, others []balanceDeltaRow) []models.TransactionEvent {
+	transfers := make([]models.TransactionEvent, 0, len(rows))
+	for _, row := range rows {
+		transfer := models.TransactionEvent{
+			Type:         models.ActivityTypeTransferOut,
+			SukukAddress: row.SukukAddress,
+			Amount:       strings.TrimPrefix(row.Delta, "-"),
+			TxHash:       row.TxHash,
+			Timestamp:    time.Unix(row.Timestamp, 0),
+			BlockNumber:  row.BlockNumber,
+			Status:       "confirmed",
+			Details: map[string]interface{}{
+				"holder": row.Holder,
+			},
+		}
+		if row.incoming() {
+			transfer.Type = models.ActivityTypeTransferIn
+		}
+		if counterparty := transferCounterparty(row, others); counterparty != "" {
+			transfer.Details["counterparty"] = counterparty
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
+
+// transferCounterparty returns the one other holder whose balance on the sukuk moved the
+// opposite way in the transaction, or empty when there is none or more than one
+func transferCounterparty(row balanceDeltaRow, others []balanceDeltaRow) string {
+	counterparty := ""
+	for _, other := range others {
+		if other.TxHash != row.TxHash || !strings.EqualFold(other.SukukAddress, row.SukukAddress) {
+			continue
+		}
+		if other.Delta == "0" || other.incoming() == row.incoming() {
+			continue
+		}
+		if counterparty != "" && !strings.EqualFold(counterparty, other.Holder) {
+			return ""
+		}
+		counterparty = other.Holder
+	}
+	return counterparty
+}
+
+// transfersToActivities lists transfers as activities of the address
+func transfersToActivities(userAddress string, transfers []models.TransactionEvent) []models.ActivityEvent {
+	activities := make([]models.ActivityEvent, 0, len(transfers))
+	for _, transfer := range transfers {
+		counterparty, _ := transfer.Details["counterparty"].(string)
+		activities = append(activities, models.ActivityEvent{
+			Type:         transfer.Type,
+			Address:      userAddress,
+			Amount:       transfer.Amount,
+			TxHash:       transfer.TxHash,
+			Timestamp:    transfer.Timestamp,
+			SukukAddress: transfer.SukukAddress,
+			Counterparty: counterparty,
+		})
+	}
+	return activities
+}
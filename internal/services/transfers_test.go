@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+func TestBuildTransfersFindsCounterparty(t *testing.T) {
+	const sender = "0x00000000000000000000000000000000000000a1"
+	const receiver = "0x00000000000000000000000000000000000000b2"
+	rows := []balanceDeltaRow{
+		{SukukAddress: "0xSUKUK", Holder: sender, TxHash: "0x01", Timestamp: 200, Delta: "-400"},
+		{SukukAddress: "0xSUKUK", Holder: sender, TxHash: "0x02", Timestamp: 100, Delta: "250"},
+	}
+	others := []balanceDeltaRow{
+		{SukukAddress: "0xsukuk", Holder: receiver, TxHash: "0x01", Delta: "400"},
+		// Another sukuk moved in the same transaction; it is not the other side
+		{SukukAddress: "0xother", Holder: "0x00000000000000000000000000000000000000c3", TxHash: "0x01", Delta: "400"},
+		// Two senders into one wallet leave the counterparty unknown
+		{SukukAddress: "0xsukuk", Holder: receiver, TxHash: "0x02", Delta: "-100"},
+		{SukukAddress: "0xsukuk", Holder: "0x00000000000000000000000000000000000000d4", TxHash: "0x02", Delta: "-150"},
+	}
+
+	transfers := buildTransfers(rows, others)
+	if len(transfers) != 2 {
+		t.Fatalf("Expected 2 transfers, got %+v", transfers)
+	}
+	out, in := transfers[0], transfers[1]
+	if out.Type != models.ActivityTypeTransferOut || out.Amount != "400" || out.Details["counterparty"] != receiver {
+		t.Errorf("Expected 400 out to the receiver, got %+v", out)
+	}
+	if in.Type != models.ActivityTypeTransferIn || in.Amount != "250" {
+		t.Errorf("Expected 250 in, got %+v", in)
+	}
+	if _, ok := in.Details["counterparty"]; ok {
+		t.Errorf("Expected no counterparty with two senders, got %v", in.Details["counterparty"])
+	}
+}
+
+// TestUserTransactionHistoryIncludesTransfers requires a reachable Postgres, see testutil.Open
+func TestUserTransactionHistoryIncludesTransfers(t *testing.T) {
+	db := testutil.DB(t)
+
+	// Stand-in indexer tables, pinned with overrides so discovery can't pick real ones
+	eventTypes := []string{"sukuk_purchase", "redemption_request", "yield_claim", "holder_update"}
+	previous, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range eventTypes {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range previous {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range eventTypes {
+		table := "tr01__" + eventType
+		db.Exec("DROP TABLE IF EXISTS " + table)
+		err := db.Exec("CREATE TABLE " + table + ` (
+			id TEXT PRIMARY KEY, buyer TEXT, "user" TEXT, holder TEXT, sukuk_address TEXT, payment_token TEXT,
+			amount NUMERIC(78,0), new_balance NUMERIC(78,0), block_number BIGINT, tx_hash TEXT, timestamp BIGINT)`).Error
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", table, err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS " + table)
+		if err := models.SetIndexerTableOverride(db, eventType, table); err != nil {
+			t.Fatalf("Failed to pin %s: %v", table, err)
+		}
+	}
+
+	const sukuk = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+	const sender = "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"
+	const receiver = "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"
+	exec := func(query string, args ...interface{}) {
+		if err := db.Exec(query, args...).Error; err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+	holderUpdate := func(id, holder, balance, txHash string, block int64) {
+		exec(`INSERT INTO tr01__holder_update (id, holder, sukuk_address, new_balance, block_number, tx_hash, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, holder, sukuk, balance, block, txHash, block*10)
+	}
+
+	// The sender buys 1000, then sends 400 to the receiver in a plain transfer
+	exec(`INSERT INTO tr01__sukuk_purchase (id, buyer, sukuk_address, payment_token, amount, block_number, tx_hash, timestamp) VALUES ('p1', ?, ?, '0xcc', 1000, 1, '0xp1', 10)`, sender, sukuk)
+	holderUpdate("h1", sender, "1000", "0xp1", 1)
+	holderUpdate("h2", sender, "600", "0xt1", 2)
+	holderUpdate("h3", receiver, "400", "0xt1", 2)
+
+	service := &IndexerQueryService{indexerDB: db, tableService: &IndexerTableService{indexerDB: db}}
+	ctx := context.Background()
+
+	history, err := service.GetUserTransactionHistory(ctx, sender, "", 50)
+	if err != nil {
+		t.Fatalf("Failed to read the sender's history: %v", err)
+	}
+	if len(history) != 2 || history[0].Type != models.ActivityTypeTransferOut || history[1].Type != models.ActivityTypePurchase {
+		t.Fatalf("Expected a transfer out after the purchase, and the purchase counted once, got %+v", history)
+	}
+	if history[0].Amount != "400" || history[0].Details["counterparty"] != receiver {
+		t.Errorf("Expected 400 sent to the receiver, got %+v", history[0])
+	}
+
+	received, err := service.GetUserTransactionHistory(ctx, receiver, models.ActivityTypeTransferIn, 50)
+	if err != nil || len(received) != 1 || received[0].Amount != "400" || received[0].Details["counterparty"] != sender {
+		t.Errorf("Expected 400 received from the sender, got %+v (%v)", received, err)
+	}
+	if sent, err := service.GetUserTransactionHistory(ctx, receiver, models.ActivityTypeTransferOut, 50); err != nil || len(sent) != 0 {
+		t.Errorf("Expected the receiver to have sent nothing, got %+v (%v)", sent, err)
+	}
+
+	activities, err := service.GetActivitiesByAddress(ctx, receiver, 50)
+	if err != nil || len(activities) != 1 || activities[0].Type != models.ActivityTypeTransferIn || activities[0].Counterparty != sender {
+		t.Errorf("Expected the transfer in the receiver's activities, got %+v (%v)", activities, err)
+	}
+}
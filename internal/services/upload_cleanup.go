@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UploadFile is a stored upload, named relative to the storage root with forward slashes
+type UploadFile struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modified_at"`
+}
+
+// UploadStorage lists and deletes stored uploads; implemented for the local upload
+// directory, and by object stores that list keys under a prefix
+type UploadStorage interface {
+	List(ctx context.Context) ([]UploadFile, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// LocalUploadStorage stores uploads in a directory served under /uploads
+type LocalUploadStorage struct {
+	dir string
+}
+
+// NewLocalUploadStorage creates storage rooted at dir
+func NewLocalUploadStorage(dir string) *LocalUploadStorage {
+	return &LocalUploadStorage{dir: dir}
+}
+
+// List returns every regular file below the storage root; a missing root has no files
+func (s *LocalUploadStorage) List(ctx context.Context) ([]UploadFile, error) {
+	var files []UploadFile
+	err := filepath.WalkDir(s.dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == s.dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, UploadFile{Name: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// Delete removes a stored file; files already gone are not an error
+func (s *LocalUploadStorage) Delete(ctx context.Context, name string) error {
+	clean := path.Clean("/" + name)
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(clean)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Save stores content under name, creating its directories, and returns the URL it is
+// served at. The file is written aside and renamed into place, so a failed copy never
+// leaves a partial file behind
+func (s *LocalUploadStorage) Save(ctx context.Context, name string, content io.Reader) (string, error) {
+	clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+	fullPath := filepath.Join(s.dir, filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	dst, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create destination file: %w", err)
+	}
+	tmpPath := dst.Name()
+	if _, err := dst.ReadFrom(content); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	return uploadURLPrefix + clean, nil
+}
+
+// UploadReferences returns the storage names still referenced by a database row
+type UploadReferences func(ctx context.Context) (map[string]bool, error)
+
+// uploadURLPrefix is the path uploads are served under
+const uploadURLPrefix = "/uploads/"
+
+// SukukMetadataUploadReferences collects the uploads referenced by sukuk metadata logos
+// Deleted sukuk no longer count, so their files become orphans
+func SukukMetadataUploadReferences(db *gorm.DB) UploadReferences {
+	return func(ctx context.Context) (map[string]bool, error) {
+		var urls []string
+		err := db.WithContext(ctx).Model(&models.SukukMetadata{}).
+			Where("logo_url <> ''").
+			Pluck("logo_url", &urls).Error
+		if err != nil {
+			return nil, err
+		}
+
+		references := make(map[string]bool, len(urls))
+		for _, url := range urls {
+			if name := uploadNameFromURL(url); name != "" {
+				references[name] = true
+			}
+		}
+		return references, nil
+	}
+}
+
+// uploadNameFromURL maps a stored URL such as "https://api/uploads/a/b.png" to "a/b.png"
+// URLs outside the upload path yield an empty name
+func uploadNameFromURL(url string) string {
+	i := strings.LastIndex(url, uploadURLPrefix)
+	if i < 0 {
+		return ""
+	}
+	name := url[i+len(uploadURLPrefix):]
+	if j := strings.IndexAny(name, "?#"); j >= 0 {
+		name = name[:j]
+	}
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// DefaultUploadGracePeriod keeps files this young even when unreferenced, so uploads
+// whose database write is still in flight are never removed
+const DefaultUploadGracePeriod = 24 * time.Hour
+
+// UploadCleanupResult reports a cleanup run
+type UploadCleanupResult struct {
+	DryRun      bool         `json:"dry_run"`
+	GracePeriod string       `json:"grace_period"`
+	Scanned     int          `json:"scanned"`
+	Candidates  []UploadFile `json:"candidates"` // Unreferenced files older than the grace period
+	Deleted     int          `json:"deleted"`
+	Failed      []string     `json:"failed,omitempty"` // Candidates that could not be deleted
+}
+
+// UploadCleanupService removes stored uploads that no database row references
+type UploadCleanupService struct {
+	storage     UploadStorage
+	references  []UploadReferences
+	gracePeriod time.Duration
+	interval    time.Duration
+	cancel      context.CancelFunc
+	now         func() time.Time
+}
+
+// NewUploadCleanupService creates a cleanup of storage; files referenced by any of the
+// reference sources, or younger than gracePeriod, are kept
+func NewUploadCleanupService(storage UploadStorage, gracePeriod, interval time.Duration, references ...UploadReferences) *UploadCleanupService {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultUploadGracePeriod
+	}
+	return &UploadCleanupService{
+		storage:     storage,
+		references:  references,
+		gracePeriod: gracePeriod,
+		interval:    interval,
+		now:         time.Now,
+	}
+}
+
+// NewDefaultUploadCleanupService cleans the local upload directory against sukuk metadata
+// logos, documents and payout proofs
+func NewDefaultUploadCleanupService(uploadDir string, gracePeriod, interval time.Duration) *UploadCleanupService {
+	return NewUploadCleanupService(NewLocalUploadStorage(uploadDir), gracePeriod, interval,
+		SukukMetadataUploadReferences(database.GetDB()), SukukDocumentUploadReferences(database.GetDB()),
+		PayoutProofUploadReferences(database.GetDB()))
+}
+
+// Run finds orphaned uploads and, unless dryRun is set, deletes them
+func (s *UploadCleanupService) Run(ctx context.Context, dryRun bool) (*UploadCleanupResult, error) {
+	files, err := s.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// References are read after listing: a file saved after the listing is not a
+	// candidate, and a row saved before this point protects its file
+	referenced := make(map[string]bool)
+	for _, source := range s.references {
+		refs, err := source(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for name := range refs {
+			referenced[name] = true
+		}
+	}
+
+	result := &UploadCleanupResult{
+		DryRun:      dryRun,
+		GracePeriod: s.gracePeriod.String(),
+		Scanned:     len(files),
+		Candidates:  make([]UploadFile, 0),
+	}
+	cutoff := s.now().Add(-s.gracePeriod)
+	for _, file := range files {
+		if referenced[file.Name] || file.ModTime.After(cutoff) {
+			continue
+		}
+		result.Candidates = append(result.Candidates, file)
+	}
+	sort.Slice(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].Name < result.Candidates[j].Name
+	})
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, file := range result.Candidates {
+		if err := s.storage.Delete(ctx, file.Name); err != nil {
+			logger.WithError(err).WithField("file", file.Name).Warn("Failed to delete orphaned upload")
+			result.Failed = append(result.Failed, file.Name)
+			continue
+		}
+		logger.WithFields(map[string]interface{}{
+			"file":        file.Name,
+			"size":        file.Size,
+			"modified_at": file.ModTime,
+		}).Info("Deleted orphaned upload")
+		result.Deleted++
+	}
+	return result, nil
+}
+
+// Start runs the cleanup every interval; a zero interval leaves it to the admin endpoint
+func (s *UploadCleanupService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		logger.Info("Upload cleanup schedule disabled")
+		return
+	}
+	logger.Info("Starting upload cleanup service")
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.cleanupLoop(ctx)
+}
+
+// Stop stops the scheduled cleanup
+func (s *UploadCleanupService) Stop() {
+	if s.cancel != nil {
+		logger.Info("Stopping upload cleanup service")
+		s.cancel()
+	}
+}
+
+func (s *UploadCleanupService) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !Settings().GetBool(SettingUploadCleanupEnabled, true) {
+				logger.Debug("Upload cleanup disabled by runtime setting, skipping sweep")
+				continue
+			}
+			result, err := s.Run(ctx, false)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.WithError(err).Error("Upload cleanup failed")
+				}
+				continue
+			}
+			if result.Deleted > 0 || len(result.Failed) > 0 {
+				logger.WithFields(map[string]interface{}{
+					"deleted": result.Deleted,
+					"failed":  len(result.Failed),
+				}).Info("Removed orphaned uploads")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeUploadStorage holds files in memory, standing in for local disk or an object store
+type fakeUploadStorage struct {
+	files     map[string]UploadFile
+	deleted   []string
+	failNames map[string]bool
+}
+
+func newFakeUploadStorage(files ...UploadFile) *fakeUploadStorage {
+	storage := &fakeUploadStorage{files: make(map[string]UploadFile), failNames: make(map[string]bool)}
+	for _, file := range files {
+		storage.files[file.Name] = file
+	}
+	return storage
+}
+
+func (s *fakeUploadStorage) List(ctx context.Context) ([]UploadFile, error) {
+	files := make([]UploadFile, 0, len(s.files))
+	for _, file := range s.files {
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func (s *fakeUploadStorage) Delete(ctx context.Context, name string) error {
+	if s.failNames[name] {
+		return errors.New("permission denied")
+	}
+	delete(s.files, name)
+	s.deleted = append(s.deleted, name)
+	return nil
+}
+
+func staticReferences(names ...string) UploadReferences {
+	return func(ctx context.Context) (map[string]bool, error) {
+		references := make(map[string]bool, len(names))
+		for _, name := range names {
+			references[name] = true
+		}
+		return references, nil
+	}
+}
+
+func TestUploadCleanupKeepsReferencedAndRecentFiles(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	storage := newFakeUploadStorage(
+		UploadFile{Name: "logos/used.png", ModTime: old},
+		UploadFile{Name: "logos/orphan.png", ModTime: old},
+		UploadFile{Name: "logos/fresh.png", ModTime: now.Add(-time.Hour)}, // row may not be committed yet
+		UploadFile{Name: ".upload-123", ModTime: old},                     // abandoned temp file
+	)
+	service := NewUploadCleanupService(storage, 24*time.Hour, 0, staticReferences("logos/used.png"))
+	service.now = func() time.Time { return now }
+
+	result, err := service.Run(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if result.Scanned != 4 || len(result.Candidates) != 2 {
+		t.Fatalf("Expected 2 candidates out of 4 files, got %+v", result)
+	}
+	if result.Candidates[0].Name != ".upload-123" || result.Candidates[1].Name != "logos/orphan.png" {
+		t.Errorf("Unexpected candidates: %+v", result.Candidates)
+	}
+	if result.Deleted != 0 || len(storage.deleted) != 0 {
+		t.Errorf("Expected a dry run not to delete, deleted %v", storage.deleted)
+	}
+
+	storage.failNames[".upload-123"] = true
+	result, err = service.Run(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if result.Deleted != 1 || len(result.Failed) != 1 || result.Failed[0] != ".upload-123" {
+		t.Errorf("Expected one deletion and one failure, got %+v", result)
+	}
+	if _, ok := storage.files["logos/used.png"]; !ok {
+		t.Error("Expected the referenced file to be kept")
+	}
+	if _, ok := storage.files["logos/fresh.png"]; !ok {
+		t.Error("Expected the file inside the grace period to be kept")
+	}
+}
+
+func TestUploadCleanupStopsOnReferenceError(t *testing.T) {
+	storage := newFakeUploadStorage(UploadFile{Name: "a.png", ModTime: time.Unix(0, 0)})
+	failing := func(ctx context.Context) (map[string]bool, error) { return nil, errors.New("database unavailable") }
+	service := NewUploadCleanupService(storage, time.Hour, 0, failing)
+
+	if _, err := service.Run(context.Background(), false); err == nil {
+		t.Fatal("Expected the reference error to be returned")
+	}
+	if len(storage.deleted) != 0 {
+		t.Errorf("Expected nothing deleted without references, deleted %v", storage.deleted)
+	}
+}
+
+func TestUploadNameFromURL(t *testing.T) {
+	cases := map[string]string{
+		"https://api.example.com/uploads/logos/a.png": "logos/a.png",
+		"/uploads/a.png?v=2":                          "a.png",
+		"/uploads/../../etc/passwd":                   "etc/passwd",
+		"https://cdn.example.com/images/a.png":        "",
+		"":                                            "",
+	}
+	for url, want := range cases {
+		if got := uploadNameFromURL(url); got != want {
+			t.Errorf("%q: expected %q, got %q", url, want, got)
+		}
+	}
+}
+
+func TestLocalUploadStorage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "logos"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logos", "a.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	storage := NewLocalUploadStorage(dir)
+	files, err := storage.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "logos/a.png" || files[0].Size != 3 {
+		t.Fatalf("Unexpected files: %+v", files)
+	}
+
+	if err := storage.Delete(context.Background(), "logos/a.png"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if err := storage.Delete(context.Background(), "logos/a.png"); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got %v", err)
+	}
+
+	missing := NewLocalUploadStorage(filepath.Join(dir, "missing"))
+	if files, err := missing.List(context.Background()); err != nil || len(files) != 0 {
+		t.Errorf("Expected a missing directory to have no files, got %v, %v", files, err)
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// GetVaultBalance sums the yield deposits and distributions of a sukuk and measures the
+// implied vault balance against its next coupon. Event tables the indexer doesn't have
+// count as empty, since older deployments never indexed deposits or vault updates
+func (s *IndexerQueryService) GetVaultBalance(ctx context.Context, sukuk *models.SukukMetadata, grace time.Duration, now time.Time) (*models.VaultBalanceResponse, error) {
+	if s.indexerDB == nil {
+		if err := s.ConnectToIndexer(); err != nil {
+			return nil, err
+		}
+	}
+
+	tables, err := s.tableService.GetAllLatestTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find indexer tables: %w", err)
+	}
+
+	var deposits []IndexerYieldDeposited
+	var distributions []IndexerYieldDistributed
+	var vaultUpdates []IndexerVaultUpdated
+	queries := []struct {
+		eventType string
+		dest      interface{}
+	}{
+		{"yield_deposit", &deposits},
+		{"yield_distributed", &distributions},
+		{"vault_update", &vaultUpdates},
+	}
+	for _, query := range queries {
+		table, ok := tables[query.eventType]
+		if !ok {
+			continue
+		}
+		err := s.read(ctx, func(db *gorm.DB) error {
+			return db.Table(table).
+				Where("LOWER(sukuk_address) = ?", strings.ToLower(sukuk.ContractAddress)).
+				Order("timestamp ASC").
+				Find(query.dest).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s events: %w", query.eventType, err)
+		}
+	}
+
+	return BuildVaultBalance(sukuk, deposits, distributions, vaultUpdates, grace, now)
+}
+
+// vaultPosition accumulates the raw amounts of one payment token
+type vaultPosition struct {
+	deposited   *big.Int
+	distributed *big.Int
+}
+
+func (p *vaultPosition) balance() *big.Int {
+	return new(big.Int).Sub(p.deposited, p.distributed)
+}
+
+// BuildVaultBalance totals deposits and distributions per payment token. The next coupon is
+// expected to repeat the latest distribution; when the coupon calendar can be estimated it
+// also dates the coupon, and a calendar with nothing left to pay leaves coverage unset.
+// Without any distribution there is nothing to measure coverage against
+func BuildVaultBalance(sukuk *models.SukukMetadata, deposits []IndexerYieldDeposited, distributions []IndexerYieldDistributed, vaultUpdates []IndexerVaultUpdated, grace time.Duration, now time.Time) (*models.VaultBalanceResponse, error) {
+	response := &models.VaultBalanceResponse{
+		SukukMetadataID: sukuk.ID,
+		ContractAddress: sukuk.ContractAddress,
+		Balances:        []models.VaultTokenBalance{},
+	}
+
+	positions := make(map[string]*vaultPosition)
+	position := func(token string) *vaultPosition {
+		token = strings.ToLower(token)
+		if positions[token] == nil {
+			positions[token] = &vaultPosition{deposited: new(big.Int), distributed: new(big.Int)}
+		}
+		return positions[token]
+	}
+
+	for _, deposit := range deposits {
+		amount, ok := new(big.Int).SetString(deposit.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid yield deposit amount %q in %s", deposit.Amount, deposit.TxHash)
+		}
+		p := position(deposit.PaymentToken)
+		p.deposited.Add(p.deposited, amount)
+	}
+
+	var latest *IndexerYieldDistributed
+	var latestAmount *big.Int
+	for i, distribution := range distributions {
+		amount, ok := new(big.Int).SetString(distribution.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid yield distribution amount %q in %s", distribution.Amount, distribution.TxHash)
+		}
+		p := position(distribution.PaymentToken)
+		p.distributed.Add(p.distributed, amount)
+		if latest == nil || distribution.Timestamp >= latest.Timestamp {
+			latest, latestAmount = &distributions[i], amount
+		}
+	}
+
+	tokens := make([]string, 0, len(positions))
+	for token := range positions {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		response.Balances = append(response.Balances, models.VaultTokenBalance{
+			PaymentToken: token,
+			Deposited:    positions[token].deposited.String(),
+			Distributed:  positions[token].distributed.String(),
+			Balance:      positions[token].balance().String(),
+		})
+	}
+
+	var vaultUpdatedAt int64
+	for i, update := range vaultUpdates {
+		if i == 0 || update.Timestamp >= vaultUpdatedAt {
+			response.VaultAddress, vaultUpdatedAt = update.NewVault, update.Timestamp
+		}
+	}
+
+	if latest == nil || latestAmount.Sign() <= 0 {
+		return response, nil
+	}
+	token := strings.ToLower(latest.PaymentToken)
+
+	schedule := BuildCouponSchedule(sukuk, append([]IndexerYieldDistributed(nil), distributions...), grace, now)
+	if schedule.Estimated {
+		if schedule.NextCouponDate == nil {
+			return response, nil
+		}
+		response.NextCouponDate = schedule.NextCouponDate
+	}
+
+	balance := positions[token].balance()
+	coverage, err := utils.NewTokenMath().FormatPercent(balance.String(), latestAmount.String())
+	if err != nil {
+		return nil, err
+	}
+	response.NextCouponPaymentToken = token
+	response.NextCouponAmount = latestAmount.String()
+	response.FundingCoverage = coverage
+	response.LowFunding = balance.Cmp(latestAmount) < 0
+	return response, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/testutil"
+)
+
+const vaultToken = "0x00000000000000000000000000000000000000cc"
+
+func monthlySukuk() *models.SukukMetadata {
+	return &models.SukukMetadata{
+		ContractAddress: "0x00000000000000000000000000000000000Ba001",
+		PenerimaanKupon: "Bulanan",
+		KuponPertama:    couponDate(2025, time.January, 10),
+		JatuhTempo:      couponDate(2025, time.December, 10),
+	}
+}
+
+func TestBuildVaultBalance(t *testing.T) {
+	deposits := []IndexerYieldDeposited{
+		{PaymentToken: vaultToken, Amount: "3000"},
+		{PaymentToken: "0x00000000000000000000000000000000000000CC", Amount: "1500"}, // Same token, other casing
+	}
+	distributions := []IndexerYieldDistributed{
+		{DistributionId: 1, PaymentToken: vaultToken, Amount: "1000", Timestamp: couponDate(2025, time.January, 10).Unix()},
+		{DistributionId: 2, PaymentToken: vaultToken, Amount: "1200", Timestamp: couponDate(2025, time.February, 10).Unix()},
+	}
+	updates := []IndexerVaultUpdated{
+		{NewVault: "0xvault2", Timestamp: 200},
+		{NewVault: "0xvault1", Timestamp: 100},
+	}
+
+	vault, err := BuildVaultBalance(monthlySukuk(), deposits, distributions, updates, DefaultCouponGracePeriod, couponDate(2025, time.March, 1))
+	if err != nil {
+		t.Fatalf("Failed to build vault balance: %v", err)
+	}
+	if len(vault.Balances) != 1 {
+		t.Fatalf("Expected one token balance, got %+v", vault.Balances)
+	}
+	if balance := vault.Balances[0]; balance.Deposited != "4500" || balance.Distributed != "2200" || balance.Balance != "2300" {
+		t.Errorf("Expected 4500 deposited, 2200 distributed and 2300 left, got %+v", balance)
+	}
+	if vault.VaultAddress != "0xvault2" {
+		t.Errorf("Expected the latest vault address, got %s", vault.VaultAddress)
+	}
+	if vault.NextCouponDate == nil || !vault.NextCouponDate.Equal(couponDate(2025, time.March, 10)) {
+		t.Errorf("Expected the March coupon next, got %v", vault.NextCouponDate)
+	}
+	// 2300 left against a 1200 coupon
+	if vault.NextCouponAmount != "1200" || vault.FundingCoverage != "191.67" || vault.LowFunding {
+		t.Errorf("Expected 191.67%% coverage of 1200, got %s of %s (low %v)", vault.FundingCoverage, vault.NextCouponAmount, vault.LowFunding)
+	}
+}
+
+func TestBuildVaultBalanceLowFunding(t *testing.T) {
+	deposits := []IndexerYieldDeposited{{PaymentToken: vaultToken, Amount: "1500"}}
+	distributions := []IndexerYieldDistributed{
+		{DistributionId: 1, PaymentToken: vaultToken, Amount: "1000", Timestamp: couponDate(2025, time.January, 10).Unix()},
+	}
+
+	vault, err := BuildVaultBalance(monthlySukuk(), deposits, distributions, nil, DefaultCouponGracePeriod, couponDate(2025, time.February, 1))
+	if err != nil {
+		t.Fatalf("Failed to build vault balance: %v", err)
+	}
+	if vault.FundingCoverage != "50.00" || !vault.LowFunding {
+		t.Errorf("Expected 50.00%% coverage flagged low, got %s (low %v)", vault.FundingCoverage, vault.LowFunding)
+	}
+
+	// Distributions beyond the recorded deposits leave a negative balance
+	distributions = append(distributions, IndexerYieldDistributed{DistributionId: 2, PaymentToken: vaultToken, Amount: "1000", Timestamp: couponDate(2025, time.February, 10).Unix()})
+	vault, _ = BuildVaultBalance(monthlySukuk(), deposits, distributions, nil, DefaultCouponGracePeriod, couponDate(2025, time.March, 1))
+	if vault.Balances[0].Balance != "-500" || vault.FundingCoverage != "-50.00" || !vault.LowFunding {
+		t.Errorf("Expected -500 left at -50.00%% coverage, got %+v at %s", vault.Balances[0], vault.FundingCoverage)
+	}
+}
+
+func TestBuildVaultBalanceWithoutNextCoupon(t *testing.T) {
+	deposits := []IndexerYieldDeposited{{PaymentToken: vaultToken, Amount: "500"}}
+
+	// No distribution to estimate the coupon from
+	vault, err := BuildVaultBalance(monthlySukuk(), deposits, nil, nil, DefaultCouponGracePeriod, couponDate(2025, time.February, 1))
+	if err != nil {
+		t.Fatalf("Failed to build vault balance: %v", err)
+	}
+	if vault.FundingCoverage != "" || vault.LowFunding || vault.Balances[0].Balance != "500" {
+		t.Errorf("Expected a 500 balance without coverage, got %+v", vault)
+	}
+
+	// Past maturity the calendar has nothing left to pay
+	distributions := []IndexerYieldDistributed{
+		{DistributionId: 1, PaymentToken: vaultToken, Amount: "1000", Timestamp: couponDate(2025, time.January, 10).Unix()},
+	}
+	vault, _ = BuildVaultBalance(monthlySukuk(), deposits, distributions, nil, DefaultCouponGracePeriod, couponDate(2026, time.June, 1))
+	if vault.NextCouponDate != nil || vault.FundingCoverage != "" || vault.LowFunding {
+		t.Errorf("Expected no coverage after maturity, got %+v", vault)
+	}
+
+	// Without a calendar the latest distribution still sets the expected coupon
+	sukuk := monthlySukuk()
+	sukuk.PenerimaanKupon = ""
+	vault, _ = BuildVaultBalance(sukuk, deposits, distributions, nil, DefaultCouponGracePeriod, couponDate(2026, time.June, 1))
+	if vault.NextCouponDate != nil || vault.FundingCoverage != "-50.00" || !vault.LowFunding {
+		t.Errorf("Expected undated -50.00%% coverage, got %+v", vault)
+	}
+
+	if _, err := BuildVaultBalance(monthlySukuk(), []IndexerYieldDeposited{{Amount: "1e3"}}, nil, nil, DefaultCouponGracePeriod, time.Now()); err == nil {
+		t.Error("Expected an invalid deposit amount to fail")
+	}
+}
+
+// TestGetVaultBalanceFromSeedData requires a reachable Postgres, see testutil.Open
+func TestGetVaultBalanceFromSeedData(t *testing.T) {
+	db := testutil.DB(t)
+	if err := database.SeedData(db, database.SeedProfileDemo); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+	defer database.WipeSeedData(db, "test", true)
+
+	// Pin the synthetic tables so discovery can't pick real ones
+	eventTypes := []string{"yield_deposit", "yield_distributed", "vault_update"}
+	previous, _ := models.GetIndexerTableOverrides(db)
+	defer func() {
+		for _, eventType := range eventTypes {
+			models.DeleteIndexerTableOverride(db, eventType)
+		}
+		for _, override := range previous {
+			models.SetIndexerTableOverride(db, override.EventType, override.Table)
+		}
+	}()
+	for _, eventType := range eventTypes {
+		if err := models.SetIndexerTableOverride(db, eventType, database.SyntheticIndexerTableName(eventType)); err != nil {
+			t.Fatalf("Failed to pin %s: %v", eventType, err)
+		}
+	}
+
+	var sukuk []models.SukukMetadata
+	if err := db.Where("contract_address LIKE ?", "0x5eed%").Order("token_id ASC").Limit(2).Find(&sukuk).Error; err != nil || len(sukuk) != 2 {
+		t.Fatalf("Expected two seeded sukuk, got %d (%v)", len(sukuk), err)
+	}
+
+	service := &IndexerQueryService{indexerDB: db, tableService: &IndexerTableService{indexerDB: db}}
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expected := []struct {
+		balance  string
+		coverage string
+		low      bool
+	}{
+		{"15000000", "150.00", false}, // 30000000 deposited, 5000000 and 10000000 distributed
+		{"5000000", "50.00", true},    // 20000000 deposited
+	}
+	for i, want := range expected {
+		vault, err := service.GetVaultBalance(context.Background(), &sukuk[i], DefaultCouponGracePeriod, now)
+		if err != nil {
+			t.Fatalf("Failed to read the vault of %s: %v", sukuk[i].ContractAddress, err)
+		}
+		if len(vault.Balances) != 1 || vault.Balances[0].Balance != want.balance {
+			t.Errorf("Expected a %s balance for %s, got %+v", want.balance, sukuk[i].ContractAddress, vault.Balances)
+		}
+		if vault.FundingCoverage != want.coverage || vault.LowFunding != want.low || vault.NextCouponDate == nil {
+			t.Errorf("Expected %s%% coverage (low %v) for %s, got %+v", want.coverage, want.low, sukuk[i].ContractAddress, vault)
+		}
+		if vault.VaultAddress == "" {
+			t.Errorf("Expected the seeded vault address for %s", sukuk[i].ContractAddress)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/database"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// signInStatement is the statement line of the sign-in message, shown to the user by the wallet
+const signInStatement = "Sign in to Sukuk to manage your account."
+
+// Wallet sign-in errors
+var (
+	ErrWalletAuthDisabled   = errors.New("wallet authentication not configured")
+	ErrInvalidSignInMessage = errors.New("invalid sign-in message")
+	ErrSignInExpired        = errors.New("sign-in message expired")
+	ErrSignInNonce          = errors.New("sign-in nonce unknown, already used or expired")
+	ErrSignInSignature      = errors.New("sign-in message not signed by its address")
+)
+
+// WalletNonceStore keeps the nonces issued to wallets
+type WalletNonceStore interface {
+	// Create stores a newly issued nonce
+	Create(ctx context.Context, nonce *models.WalletAuthNonce) error
+	// Consume marks the unused nonce issued to address used, reporting false when there is
+	// no such nonce or it expired before now
+	Consume(ctx context.Context, nonce, address string, now time.Time) (bool, error)
+}
+
+// gormWalletNonceStore keeps nonces in the wallet_auth_nonces table
+type gormWalletNonceStore struct {
+	db *gorm.DB
+}
+
+// NewWalletNonceStore returns a WalletNonceStore on the wallet_auth_nonces table
+func NewWalletNonceStore(db *gorm.DB) WalletNonceStore {
+	return &gormWalletNonceStore{db: db}
+}
+
+func (s *gormWalletNonceStore) Create(ctx context.Context, nonce *models.WalletAuthNonce) error {
+	// Expired nonces of the wallet are of no use, so they go as it asks for another
+	db := s.db.WithContext(ctx)
+	if err := db.Where("address = ? AND expires_at < ?", strings.ToLower(nonce.Address), time.Now()).Delete(&models.WalletAuthNonce{}).Error; err != nil {
+		return fmt.Errorf("failed to delete expired nonces: %w", err)
+	}
+	return db.Create(nonce).Error
+}
+
+func (s *gormWalletNonceStore) Consume(ctx context.Context, nonce, address string, now time.Time) (bool, error) {
+	// A single conditional update, so two sign-ins racing with the same nonce can't both win
+	result := s.db.WithContext(ctx).Model(&models.WalletAuthNonce{}).
+		Where("nonce = ? AND address = ? AND used_at IS NULL AND expires_at > ?", nonce, strings.ToLower(address), now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to consume nonce: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// WalletAuthService signs wallets in with Sign-In with Ethereum (EIP-4361) style messages:
+// a wallet asks for a nonce, signs the message issued with it, and exchanges the signature
+// for a short-lived session token bound to its address
+type WalletAuthService struct {
+	nonces   WalletNonceStore
+	secret   string
+	domain   string
+	chainID  int64
+	nonceTTL time.Duration
+	tokenTTL time.Duration
+	now      func() time.Time
+}
+
+// NewWalletAuthService creates a wallet sign-in service issuing messages for domain and
+// chainID and session tokens signed with secret. An empty secret disables sign-in
+func NewWalletAuthService(nonces WalletNonceStore, secret, domain string, chainID int64, nonceTTL, tokenTTL time.Duration) *WalletAuthService {
+	return &WalletAuthService{
+		nonces:   nonces,
+		secret:   secret,
+		domain:   domain,
+		chainID:  chainID,
+		nonceTTL: nonceTTL,
+		tokenTTL: tokenTTL,
+		now:      time.Now,
+	}
+}
+
+// NewDefaultWalletAuthService creates a wallet sign-in service keeping nonces in the main database
+func NewDefaultWalletAuthService(secret, domain string, chainID int64, nonceTTL, tokenTTL time.Duration) *WalletAuthService {
+	return NewWalletAuthService(NewWalletNonceStore(database.GetDB()), secret, domain, chainID, nonceTTL, tokenTTL)
+}
+
+// SignInMessage is the message a wallet signs with personal_sign to sign in, e.g.
+//
+//	sukuk.example wants you to sign in with your Ethereum account:
+//	0xAbC...
+//
+//	Sign in to Sukuk to manage your account.
+//
+//	URI: https://sukuk.example
+//	Version: 1
+//	Chain ID: 84532
+//	Nonce: 5f2b...
+//	Issued At: 2025-06-01T08:00:00Z
+//	Expiration Time: 2025-06-01T08:05:00Z
+func SignInMessage(domain, address string, chainID int64, nonce string, issuedAt, expiresAt time.Time) string {
+	return fmt.Sprintf("%s wants you to sign in with your Ethereum account:\n%s\n\n%s\n\nURI: https://%s\nVersion: 1\nChain ID: %d\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		domain, utils.ChecksumAddress(address), signInStatement, domain, chainID, nonce,
+		issuedAt.UTC().Format(time.RFC3339), expiresAt.UTC().Format(time.RFC3339))
+}
+
+// IssueNonce issues a single-use nonce to address with the sign-in message to sign with it
+func (s *WalletAuthService) IssueNonce(ctx context.Context, address string) (*models.WalletAuthChallenge, error) {
+	if s.secret == "" {
+		return nil, ErrWalletAuthDisabled
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	now := s.now().UTC().Truncate(time.Second)
+	nonce := &models.WalletAuthNonce{
+		Nonce:     hex.EncodeToString(raw),
+		Address:   strings.ToLower(address),
+		ExpiresAt: now.Add(s.nonceTTL),
+	}
+	if err := s.nonces.Create(ctx, nonce); err != nil {
+		return nil, fmt.Errorf("failed to store nonce: %w", err)
+	}
+
+	return &models.WalletAuthChallenge{
+		Address:   nonce.Address,
+		Nonce:     nonce.Nonce,
+		Message:   SignInMessage(s.domain, address, s.chainID, nonce.Nonce, now, nonce.ExpiresAt),
+		ExpiresAt: nonce.ExpiresAt,
+	}, nil
+}
+
+// Verify checks a sign-in message issued by IssueNonce and its signature, consumes its nonce
+// and returns a session token for the signer. Times in the message are allowed
+// utils.WalletClockSkew of drift
+func (s *WalletAuthService) Verify(ctx context.Context, message, signature string) (*models.WalletAuthSession, error) {
+	if s.secret == "" {
+		return nil, ErrWalletAuthDisabled
+	}
+	signIn, err := s.parseSignInMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if signIn.issuedAt.After(now.Add(utils.WalletClockSkew)) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidSignInMessage)
+	}
+	if !now.Add(-utils.WalletClockSkew).Before(signIn.expiresAt) {
+		return nil, ErrSignInExpired
+	}
+
+	// The signature is checked before the nonce is consumed, so nobody but the wallet can use it up
+	if err := utils.VerifyPersonalSignature(signIn.address, message, signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignInSignature, err)
+	}
+	consumed, err := s.nonces.Consume(ctx, signIn.nonce, signIn.address, now.Add(-utils.WalletClockSkew))
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrSignInNonce
+	}
+
+	expiresAt := now.Add(s.tokenTTL).Truncate(time.Second)
+	token, err := utils.NewWalletToken(s.secret, signIn.address, now, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session token: %w", err)
+	}
+	return &models.WalletAuthSession{Token: token, Address: signIn.address, ExpiresAt: expiresAt}, nil
+}
+
+// signInMessage holds the fields of a sign-in message Verify checks
+type signInMessage struct {
+	address   string
+	nonce     string
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+// parseSignInMessage reads a message in the format of SignInMessage, checking it was issued
+// for this service's domain and chain
+func (s *WalletAuthService) parseSignInMessage(message string) (*signInMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], " wants you to sign in with your Ethereum account:") {
+		return nil, fmt.Errorf("%w: expected the format of SignInMessage", ErrInvalidSignInMessage)
+	}
+	if domain := strings.TrimSuffix(lines[0], " wants you to sign in with your Ethereum account:"); domain != s.domain {
+		return nil, fmt.Errorf("%w: issued for %s", ErrInvalidSignInMessage, domain)
+	}
+	if !utils.IsValidEthereumAddress(lines[1]) {
+		return nil, fmt.Errorf("%w: invalid address", ErrInvalidSignInMessage)
+	}
+
+	fields := make(map[string]string)
+	for _, line := range lines[2:] {
+		if name, value, ok := strings.Cut(line, ": "); ok {
+			fields[name] = value
+		}
+	}
+	if fields["Version"] != "1" || fields["URI"] != "https://"+s.domain {
+		return nil, fmt.Errorf("%w: expected the format of SignInMessage", ErrInvalidSignInMessage)
+	}
+	if fields["Chain ID"] != strconv.FormatInt(s.chainID, 10) {
+		return nil, fmt.Errorf("%w: issued for chain %s", ErrInvalidSignInMessage, fields["Chain ID"])
+	}
+	if fields["Nonce"] == "" {
+		return nil, fmt.Errorf("%w: missing nonce", ErrInvalidSignInMessage)
+	}
+	issuedAt, err1 := time.Parse(time.RFC3339, fields["Issued At"])
+	expiresAt, err2 := time.Parse(time.RFC3339, fields["Expiration Time"])
+	if err1 != nil || err2 != nil {
+		return nil, fmt.Errorf("%w: issued at and expiration time must be RFC3339", ErrInvalidSignInMessage)
+	}
+
+	return &signInMessage{
+		address:   strings.ToLower(lines[1]),
+		nonce:     fields["Nonce"],
+		issuedAt:  issuedAt,
+		expiresAt: expiresAt,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// memoryNonceStore is a WalletNonceStore in memory
+type memoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]*models.WalletAuthNonce
+}
+
+func (s *memoryNonceStore) Create(ctx context.Context, nonce *models.WalletAuthNonce) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nonces == nil {
+		s.nonces = make(map[string]*models.WalletAuthNonce)
+	}
+	s.nonces[nonce.Nonce] = nonce
+	return nil
+}
+
+func (s *memoryNonceStore) Consume(ctx context.Context, nonce, address string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.nonces[nonce]
+	if !ok || stored.Address != strings.ToLower(address) || stored.UsedAt != nil || !stored.ExpiresAt.After(now) {
+		return false, nil
+	}
+	stored.UsedAt = &now
+	return true, nil
+}
+
+func TestWalletSignIn(t *testing.T) {
+	key, _ := secp256k1.GeneratePrivateKey()
+	address := utils.AddressFromPublicKey(key.PubKey())
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	service := NewWalletAuthService(&memoryNonceStore{}, "secret", "sukuk.example", 84532, 5*time.Minute, 15*time.Minute)
+	service.now = func() time.Time { return now }
+
+	challenge, err := service.IssueNonce(context.Background(), utils.ChecksumAddress(address))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if challenge.Address != address || !strings.Contains(challenge.Message, "Nonce: "+challenge.Nonce) ||
+		!strings.Contains(challenge.Message, "\n"+utils.ChecksumAddress(address)+"\n") {
+		t.Fatalf("Unexpected challenge %+v", challenge)
+	}
+
+	// The signer's wallet gets a token for its address
+	now = now.Add(time.Minute)
+	session, err := service.Verify(context.Background(), challenge.Message, personalSign(key, challenge.Message))
+	if err != nil {
+		t.Fatalf("Expected sign-in, got %v", err)
+	}
+	if got, err := utils.ParseWalletToken("secret", session.Token, now); err != nil || got != address || session.Address != address {
+		t.Errorf("Expected a token for %s, got %q (%v) for %s", address, got, err, session.Address)
+	}
+	if !session.ExpiresAt.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("Expected the token to expire after the token TTL, got %s", session.ExpiresAt)
+	}
+
+	// Replaying the same signed message is refused
+	if _, err := service.Verify(context.Background(), challenge.Message, personalSign(key, challenge.Message)); !errors.Is(err, ErrSignInNonce) {
+		t.Errorf("Expected a replayed nonce to be rejected, got %v", err)
+	}
+}
+
+func TestWalletSignInRejections(t *testing.T) {
+	key, _ := secp256k1.GeneratePrivateKey()
+	other, _ := secp256k1.GeneratePrivateKey()
+	address := utils.AddressFromPublicKey(key.PubKey())
+	otherAddress := utils.AddressFromPublicKey(other.PubKey())
+	issued := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	store := &memoryNonceStore{}
+	service := NewWalletAuthService(store, "secret", "sukuk.example", 84532, 5*time.Minute, 15*time.Minute)
+	now := issued
+	service.now = func() time.Time { return now }
+	issue := func(address string) *models.WalletAuthChallenge {
+		now = issued
+		challenge, err := service.IssueNonce(context.Background(), address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return challenge
+	}
+
+	// Another wallet signing the message doesn't get the address, nor use up its nonce
+	challenge := issue(address)
+	if _, err := service.Verify(context.Background(), challenge.Message, personalSign(other, challenge.Message)); !errors.Is(err, ErrSignInSignature) {
+		t.Errorf("Expected another wallet's signature to be rejected, got %v", err)
+	}
+	if _, err := service.Verify(context.Background(), challenge.Message, personalSign(key, challenge.Message)); err != nil {
+		t.Errorf("Expected the nonce to survive a failed signature, got %v", err)
+	}
+
+	// Rewriting the address of a message for another wallet breaks the signature
+	challenge = issue(address)
+	swapped := strings.Replace(challenge.Message, utils.ChecksumAddress(address), utils.ChecksumAddress(otherAddress), 1)
+	if _, err := service.Verify(context.Background(), swapped, personalSign(key, challenge.Message)); !errors.Is(err, ErrSignInSignature) {
+		t.Errorf("Expected a message rewritten for another address to be rejected, got %v", err)
+	}
+	// A nonce issued to one wallet can't sign in another, even signed by that wallet
+	if _, err := service.Verify(context.Background(), swapped, personalSign(other, swapped)); !errors.Is(err, ErrSignInNonce) {
+		t.Errorf("Expected a nonce issued to another address to be rejected, got %v", err)
+	}
+
+	// Wallets that report v as 0/1 instead of 27/28
+	challenge = issue(address)
+	raw, _ := hex.DecodeString(personalSign(key, challenge.Message)[2:])
+	raw[64] -= 27
+	if _, err := service.Verify(context.Background(), challenge.Message, hex.EncodeToString(raw)); err != nil {
+		t.Errorf("Expected a 0/1 recovery id to be accepted, got %v", err)
+	}
+
+	// Expiry is checked with a minute of clock skew
+	challenge = issue(address)
+	now = challenge.ExpiresAt.Add(30 * time.Second)
+	if _, err := service.Verify(context.Background(), challenge.Message, personalSign(key, challenge.Message)); err != nil {
+		t.Errorf("Expected a message expiring within the skew to be accepted, got %v", err)
+	}
+	challenge = issue(address)
+	now = challenge.ExpiresAt.Add(utils.WalletClockSkew)
+	if _, err := service.Verify(context.Background(), challenge.Message, personalSign(key, challenge.Message)); !errors.Is(err, ErrSignInExpired) {
+		t.Errorf("Expected an expired message to be rejected, got %v", err)
+	}
+	// Moving the expiration forward to extend the message's life breaks the signature
+	extended := SignInMessage("sukuk.example", address, 84532, challenge.Nonce, issued, now.Add(time.Hour))
+	if _, err := service.Verify(context.Background(), extended, personalSign(key, challenge.Message)); !errors.Is(err, ErrSignInSignature) {
+		t.Errorf("Expected a tampered message to be rejected, got %v", err)
+	}
+
+	// Messages for another site or chain, made-up nonces and arbitrary text
+	now = issued
+	for name, message := range map[string]string{
+		"another domain": SignInMessage("evil.example", address, 84532, issue(address).Nonce, issued, issued.Add(time.Minute)),
+		"another chain":  SignInMessage("sukuk.example", address, 8453, issue(address).Nonce, issued, issued.Add(time.Minute)),
+		"arbitrary text": "I own " + address,
+	} {
+		if _, err := service.Verify(context.Background(), message, personalSign(key, message)); !errors.Is(err, ErrInvalidSignInMessage) {
+			t.Errorf("%s: expected an invalid message error, got %v", name, err)
+		}
+	}
+	unknown := SignInMessage("sukuk.example", address, 84532, "00000000000000000000000000000000", issued, issued.Add(time.Minute))
+	if _, err := service.Verify(context.Background(), unknown, personalSign(key, unknown)); !errors.Is(err, ErrSignInNonce) {
+		t.Errorf("Expected an unknown nonce to be rejected, got %v", err)
+	}
+	for _, invalid := range []string{"", "0x1234"} {
+		if _, err := service.Verify(context.Background(), unknown, invalid); !errors.Is(err, ErrSignInSignature) {
+			t.Errorf("Expected signature %q to be rejected, got %v", invalid, err)
+		}
+	}
+
+	disabled := NewWalletAuthService(store, "", "sukuk.example", 84532, 5*time.Minute, 15*time.Minute)
+	if _, err := disabled.IssueNonce(context.Background(), address); !errors.Is(err, ErrWalletAuthDisabled) {
+		t.Errorf("Expected sign-in refused without a secret, got %v", err)
+	}
+}
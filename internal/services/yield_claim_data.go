@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// ClaimYieldSignature is the sukuk contract function that claims a list of distributions
+const ClaimYieldSignature = "claimYield(uint256[])"
+
+// ErrNothingToClaim is returned when a user has no distribution left to claim
+var ErrNothingToClaim = errors.New("nothing to claim")
+
+// BuildYieldClaimData selects the claimable distributions of a sukuk, as returned by
+// GetClaimableDistributions, and encodes the claimYield call that claims them, lowest ID first
+func BuildYieldClaimData(userAddress, sukukAddress string, distributions []models.SukukYieldDistribution) (*models.YieldClaimDataResponse, error) {
+	var claimable []models.SukukYieldDistribution
+	for _, distribution := range distributions {
+		if distribution.Claimable {
+			claimable = append(claimable, distribution)
+		}
+	}
+	if len(claimable) == 0 {
+		if len(distributions) == 0 {
+			return nil, fmt.Errorf("%w: no yield has been distributed for this sukuk", ErrNothingToClaim)
+		}
+		return nil, fmt.Errorf("%w: all %d distributions are already claimed or the address holds no tokens", ErrNothingToClaim, len(distributions))
+	}
+
+	ids := make([]int64, len(claimable))
+	values := make([]*big.Int, len(claimable))
+	labels := make([]string, len(claimable))
+	for i, distribution := range claimable {
+		ids[i] = distribution.DistributionId
+		values[i] = big.NewInt(distribution.DistributionId)
+		labels[i] = fmt.Sprintf("#%d", distribution.DistributionId)
+	}
+	data, err := utils.EncodeUint256ArrayCall(ClaimYieldSignature, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claim: %w", err)
+	}
+
+	amounts, err := claimAmountsByToken(claimable)
+	if err != nil {
+		return nil, err
+	}
+	payouts := make([]string, len(amounts))
+	for i, amount := range amounts {
+		payouts[i] = fmt.Sprintf("%s of token %s", amount.Amount, amount.PaymentToken)
+	}
+
+	return &models.YieldClaimDataResponse{
+		Address:         userAddress,
+		To:              sukukAddress,
+		Data:            utils.EncodeHex(data),
+		Value:           "0",
+		Function:        ClaimYieldSignature,
+		DistributionIDs: ids,
+		Distributions:   claimable,
+		Amounts:         amounts,
+		Summary: fmt.Sprintf("Claim yield from %d distribution(s) (%s) of sukuk %s, paying %s",
+			len(claimable), strings.Join(labels, ", "), sukukAddress, strings.Join(payouts, " and ")),
+	}, nil
+}
+
+// claimAmountsByToken sums the claimable amounts per payment token, in order of first appearance
+func claimAmountsByToken(distributions []models.SukukYieldDistribution) ([]models.YieldClaimAmount, error) {
+	mathUtil := utils.GlobalTokenMath
+	var amounts []models.YieldClaimAmount
+	index := make(map[string]int)
+	for _, distribution := range distributions {
+		token := strings.ToLower(distribution.PaymentToken)
+		i, ok := index[token]
+		if !ok {
+			index[token] = len(amounts)
+			amounts = append(amounts, models.YieldClaimAmount{PaymentToken: token, Amount: distribution.UserClaimableAmount})
+			continue
+		}
+		total, err := mathUtil.AddTokenAmounts(amounts[i].Amount, distribution.UserClaimableAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to total distribution %d: %w", distribution.DistributionId, err)
+		}
+		amounts[i].Amount = total
+	}
+	return amounts, nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/utils"
+)
+
+// Expected calldata was produced with go-ethereum's abi.Pack for claimYield(uint256[])
+const claimYieldSelector = "0x25141d68"
+
+func TestEncodeClaimYieldCall(t *testing.T) {
+	tests := []struct {
+		ids      []int64
+		expected string
+	}{
+		{nil, claimYieldSelector +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000000"},
+		{[]int64{1}, claimYieldSelector +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000001"},
+		{[]int64{1, 3, 256}, claimYieldSelector +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000003" +
+			"0000000000000000000000000000000000000000000000000000000000000001" +
+			"0000000000000000000000000000000000000000000000000000000000000003" +
+			"0000000000000000000000000000000000000000000000000000000000000100"},
+	}
+	for _, tt := range tests {
+		values := make([]*big.Int, len(tt.ids))
+		for i, id := range tt.ids {
+			values[i] = big.NewInt(id)
+		}
+		data, err := utils.EncodeUint256ArrayCall(ClaimYieldSignature, values)
+		if err != nil {
+			t.Fatalf("Failed to encode %v: %v", tt.ids, err)
+		}
+		if got := utils.EncodeHex(data); got != tt.expected {
+			t.Errorf("Encoding %v:\nexpected %s\ngot      %s", tt.ids, tt.expected, got)
+		}
+	}
+
+	if _, err := utils.EncodeUint256ArrayCall(ClaimYieldSignature, []*big.Int{big.NewInt(-1)}); err == nil {
+		t.Error("Expected a negative value to be rejected")
+	}
+}
+
+func TestBuildYieldClaimDataSelectsClaimable(t *testing.T) {
+	const (
+		user  = "0x00000000000000000000000000000000000000a1"
+		sukuk = "0x00000000000000000000000000000000000000b2"
+		idrx  = "0x00000000000000000000000000000000000000c3"
+	)
+	distributions := []models.SukukYieldDistribution{
+		{DistributionId: 1, PaymentToken: idrx, Claimable: true, UserClaimableAmount: "1000"},
+		{DistributionId: 2, PaymentToken: idrx, Claimable: false, UserClaimableAmount: "0"},
+		{DistributionId: 3, PaymentToken: strings.ToUpper(idrx[:2]) + idrx[2:], Claimable: true, UserClaimableAmount: "500"},
+	}
+
+	claim, err := BuildYieldClaimData(user, sukuk, distributions)
+	if err != nil {
+		t.Fatalf("Failed to build claim: %v", err)
+	}
+	if claim.To != sukuk || claim.Value != "0" || len(claim.DistributionIDs) != 2 ||
+		claim.DistributionIDs[0] != 1 || claim.DistributionIDs[1] != 3 {
+		t.Errorf("Unexpected claim: %+v", claim)
+	}
+	expected := claimYieldSelector +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000003"
+	if claim.Data != expected {
+		t.Errorf("Expected calldata %s, got %s", expected, claim.Data)
+	}
+	if len(claim.Amounts) != 1 || claim.Amounts[0].Amount != "1500" {
+		t.Errorf("Expected 1500 of one payment token, got %+v", claim.Amounts)
+	}
+	if !strings.Contains(claim.Summary, "2 distribution(s) (#1, #3)") {
+		t.Errorf("Unexpected summary %q", claim.Summary)
+	}
+}
+
+func TestBuildYieldClaimDataNothingToClaim(t *testing.T) {
+	for _, distributions := range [][]models.SukukYieldDistribution{
+		nil,
+		{{DistributionId: 1, Claimable: false, ClaimedAmount: "1000", UserClaimableAmount: "0"}},
+	} {
+		if _, err := BuildYieldClaimData("0xa", "0xb", distributions); !errors.Is(err, ErrNothingToClaim) {
+			t.Errorf("Expected ErrNothingToClaim for %+v, got %v", distributions, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package stream fans out newly indexed activities to connected clients.
+package stream
+
+import (
+	"strings"
+	"sync"
+
+	"sukuk-be/internal/models"
+)
+
+// Default sizes used by NewBroker when zero values are passed
+const (
+	DefaultHistorySize = 500
+	DefaultBufferSize  = 64
+)
+
+// Event is an activity tagged with its broker-assigned sequence id
+type Event struct {
+	ID       uint64
+	Activity models.ActivityEvent
+}
+
+// Filter narrows a subscription; empty fields match everything
+type Filter struct {
+	SukukAddress string
+	Address      string
+	Type         models.ActivityType
+}
+
+// Match reports whether the activity passes the filter (addresses compare case-insensitively)
+func (f Filter) Match(activity models.ActivityEvent) bool {
+	if f.SukukAddress != "" && !strings.EqualFold(f.SukukAddress, activity.SukukAddress) {
+		return false
+	}
+	if f.Address != "" && !strings.EqualFold(f.Address, activity.Address) {
+		return false
+	}
+	if f.Type != "" && f.Type != activity.Type {
+		return false
+	}
+	return true
+}
+
+// Subscription receives events published after it was created
+type Subscription struct {
+	C       <-chan Event
+	ch      chan Event
+	filter  Filter
+	broker  *Broker
+	dropped uint64
+}
+
+// Dropped returns how many events were discarded because the client fell behind
+func (s *Subscription) Dropped() uint64 {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.dropped
+}
+
+// Close detaches the subscription from the broker
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	delete(s.broker.subs, s)
+}
+
+// Broker is an in-process pub/sub for activity events. Publish never blocks:
+// each subscriber has a bounded buffer and the oldest pending event is dropped
+// when a slow client lets it fill up. A ring of recent events allows reconnecting
+// clients to resume from a Last-Event-ID.
+type Broker struct {
+	mu          sync.Mutex
+	subs        map[*Subscription]struct{}
+	history     []Event
+	historySize int
+	bufferSize  int
+	lastID      uint64
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewBroker creates a broker keeping historySize recent events and giving each
+// subscriber a buffer of bufferSize events
+func NewBroker(historySize, bufferSize int) *Broker {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		subs:        make(map[*Subscription]struct{}),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		closed:      make(chan struct{}),
+	}
+}
+
+// Publish assigns the next id to the activity and delivers it to matching subscribers
+func (b *Broker) Publish(activity models.ActivityEvent) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := Event{ID: b.lastID, Activity: activity}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subs {
+		if sub.filter.Match(activity) {
+			b.deliver(sub, event)
+		}
+	}
+
+	return event.ID
+}
+
+// deliver sends without blocking, evicting the oldest buffered event if needed.
+// Callers hold b.mu, so only the subscriber's reader competes for the channel.
+func (b *Broker) deliver(sub *Subscription, event Event) {
+	for {
+		select {
+		case sub.ch <- event:
+			return
+		default:
+		}
+		select {
+		case <-sub.ch:
+			sub.dropped++
+		default:
+		}
+	}
+}
+
+// Subscribe registers a subscriber and returns the retained events with an id
+// greater than lastEventID that match the filter. Pass 0 to skip the replay.
+func (b *Broker) Subscribe(filter Filter, lastEventID uint64) (*Subscription, []Event) {
+	ch := make(chan Event, b.bufferSize)
+	sub := &Subscription{C: ch, ch: ch, filter: filter, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastEventID > 0 && lastEventID < b.lastID {
+		for _, event := range b.history {
+			if event.ID > lastEventID && filter.Match(event.Activity) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	b.subs[sub] = struct{}{}
+	return sub, replay
+}
+
+// LastID returns the id of the most recently published event
+func (b *Broker) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Close tells subscribers the broker is shutting down; their clients reconnect and resume
+// from their Last-Event-ID
+func (b *Broker) Close() {
+	b.closeOnce.Do(func() { close(b.closed) })
+}
+
+// Done is closed once Close is called
+func (b *Broker) Done() <-chan struct{} {
+	return b.closed
+}
@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+func activity(sukuk, address, tx string) models.ActivityEvent {
+	return models.ActivityEvent{
+		Type:         models.ActivityTypePurchase,
+		Address:      address,
+		Amount:       "100",
+		TxHash:       tx,
+		SukukAddress: sukuk,
+	}
+}
+
+func TestSubscribeReceivesPublishedEvent(t *testing.T) {
+	broker := NewBroker(10, 4)
+	sub, _ := broker.Subscribe(Filter{}, 0)
+	defer sub.Close()
+
+	id := broker.Publish(activity("0xSukuk", "0xUser", "0x1"))
+
+	select {
+	case event := <-sub.C:
+		if event.ID != id {
+			t.Errorf("Expected event id %d, got %d", id, event.ID)
+		}
+		if event.Activity.TxHash != "0x1" {
+			t.Errorf("Expected tx 0x1, got %s", event.Activity.TxHash)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+}
+
+func TestFilterMatchesCaseInsensitively(t *testing.T) {
+	broker := NewBroker(10, 4)
+	sub, _ := broker.Subscribe(Filter{SukukAddress: "0xsukuk", Address: "0XUSER"}, 0)
+	defer sub.Close()
+
+	broker.Publish(activity("0xOther", "0xUser", "0x1"))
+	broker.Publish(activity("0xSukuk", "0xSomeoneElse", "0x2"))
+	broker.Publish(activity("0xSukuk", "0xUser", "0x3"))
+
+	select {
+	case event := <-sub.C:
+		if event.Activity.TxHash != "0x3" {
+			t.Errorf("Expected only tx 0x3 to match, got %s", event.Activity.TxHash)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	if len(sub.C) != 0 {
+		t.Errorf("Expected no further events, got %d buffered", len(sub.C))
+	}
+}
+
+func TestFilterMatchesType(t *testing.T) {
+	filter := Filter{Type: models.ActivityTypeRedemptionRequest}
+	redemption := activity("0xSukuk", "0xUser", "0x1")
+	redemption.Type = models.ActivityTypeRedemptionRequest
+
+	if filter.Match(activity("0xSukuk", "0xUser", "0x2")) {
+		t.Error("Expected a purchase not to match a redemption_request filter")
+	}
+	if !filter.Match(redemption) {
+		t.Error("Expected a redemption request to match")
+	}
+}
+
+func TestSlowSubscriberDropsOldest(t *testing.T) {
+	broker := NewBroker(10, 2)
+	sub, _ := broker.Subscribe(Filter{}, 0)
+	defer sub.Close()
+
+	for _, tx := range []string{"0x1", "0x2", "0x3", "0x4"} {
+		broker.Publish(activity("0xSukuk", "0xUser", tx))
+	}
+
+	if got := sub.Dropped(); got != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", got)
+	}
+	first := <-sub.C
+	second := <-sub.C
+	if first.Activity.TxHash != "0x3" || second.Activity.TxHash != "0x4" {
+		t.Errorf("Expected newest events 0x3, 0x4; got %s, %s", first.Activity.TxHash, second.Activity.TxHash)
+	}
+}
+
+func TestSubscribeReplaysAfterLastEventID(t *testing.T) {
+	broker := NewBroker(3, 4)
+	for _, tx := range []string{"0x1", "0x2", "0x3", "0x4", "0x5"} {
+		broker.Publish(activity("0xSukuk", "0xUser", tx))
+	}
+
+	sub, replay := broker.Subscribe(Filter{}, 3)
+	defer sub.Close()
+	if len(replay) != 2 || replay[0].ID != 4 || replay[1].ID != 5 {
+		t.Fatalf("Expected replay of ids 4 and 5, got %+v", replay)
+	}
+
+	// Ids older than the retained history replay everything still held
+	_, replay = broker.Subscribe(Filter{}, 1)
+	if len(replay) != 3 || replay[0].ID != 3 {
+		t.Errorf("Expected replay of the 3 retained events, got %+v", replay)
+	}
+
+	// An id from before a restart (ahead of the broker) replays nothing
+	_, replay = broker.Subscribe(Filter{}, 42)
+	if len(replay) != 0 {
+		t.Errorf("Expected no replay for unknown id, got %+v", replay)
+	}
+}
+
+func TestClosedSubscriptionStopsReceiving(t *testing.T) {
+	broker := NewBroker(10, 4)
+	sub, _ := broker.Subscribe(Filter{}, 0)
+	sub.Close()
+
+	broker.Publish(activity("0xSukuk", "0xUser", "0x1"))
+	if len(sub.C) != 0 {
+		t.Error("Expected closed subscription to receive nothing")
+	}
+}
@@ -0,0 +1,38 @@
+// Package testutil holds helpers shared by tests across packages
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"sukuk-be/internal/database"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Open connects to the Postgres in TEST_DATABASE_DSN, skipping the test when it is unset, e.g.
+// TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=sukuk_test sslmode=disable"
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	return db
+}
+
+// DB is Open with the application schema migrated
+func DB(t testing.TB) *gorm.DB {
+	t.Helper()
+	db := Open(t)
+	if err := database.AutoMigrate(db); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
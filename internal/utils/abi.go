@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// abiWordSize is the size of one ABI-encoded head or tail slot
+const abiWordSize = 32
+
+// FunctionSelector returns the 4-byte selector of a canonical function signature such as
+// "claimYield(uint256[])": the first four bytes of its keccak256 hash
+func FunctionSelector(signature string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(signature))
+	return hash.Sum(nil)[:4]
+}
+
+// EncodeUint256ArrayCall ABI-encodes a call to a function whose only argument is a
+// uint256[]: the selector, the offset of the array, its length and one word per value,
+// the same bytes go-ethereum's abi.Pack produces
+func EncodeUint256ArrayCall(signature string, values []*big.Int) ([]byte, error) {
+	data := make([]byte, 0, 4+abiWordSize*(2+len(values)))
+	data = append(data, FunctionSelector(signature)...)
+	data = append(data, abiWord(big.NewInt(abiWordSize))...)
+	data = append(data, abiWord(big.NewInt(int64(len(values))))...)
+	for _, value := range values {
+		if value.Sign() < 0 || value.BitLen() > 256 {
+			return nil, fmt.Errorf("value %s does not fit in uint256", value)
+		}
+		data = append(data, abiWord(value)...)
+	}
+	return data, nil
+}
+
+// EncodeHex returns data as a 0x-prefixed hex string
+func EncodeHex(data []byte) string {
+	return "0x" + hex.EncodeToString(data)
+}
+
+// abiWord left-pads a non-negative value to one 32-byte word
+func abiWord(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, abiWordSize))
+}
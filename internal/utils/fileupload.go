@@ -98,22 +98,31 @@ func SaveFile(file *multipart.FileHeader, config FileUploadConfig, id string) (s
 	filename := config.FilenameFn(file.Filename, id)
 	fullPath := filepath.Join(config.UploadDir, filename)
 	
-	// Create a temporary file to save the upload
 	src, err := file.Open()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to open uploaded file: %v", err)
 	}
 	defer src.Close()
 
-	// Create destination file
-	dst, err := os.Create(fullPath)
+	// Write to a temporary file and rename it into place, so a failed copy
+	// never leaves a partial file at the final path
+	dst, err := os.CreateTemp(config.UploadDir, ".upload-*")
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create destination file: %v", err)
 	}
-	defer dst.Close()
+	tmpPath := dst.Name()
 
-	// Copy file content
 	if _, err := dst.ReadFrom(src); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return "", "", fmt.Errorf("failed to save file: %v", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", "", fmt.Errorf("failed to save file: %v", err)
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
 		return "", "", fmt.Errorf("failed to save file: %v", err)
 	}
 
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+
+	"sukuk-be/internal/models"
 )
 
 // TokenMath provides utilities for token amount calculations
@@ -70,37 +72,6 @@ func (tm *TokenMath) SubtractTokenAmounts(amount1, amount2 string) (string, erro
 	return result.String(), nil
 }
 
-// MultiplyTokenAmount multiplies a token amount by a percentage (0.0 - 1.0)
-func (tm *TokenMath) MultiplyTokenAmount(amount string, percentage float64) (string, error) {
-	if amount == "" {
-		amount = "0"
-	}
-
-	// Convert amount to big integer
-	bigAmount, ok := new(big.Int).SetString(amount, 10)
-	if !ok {
-		return "0", fmt.Errorf("invalid amount: %s", amount)
-	}
-
-	// Convert percentage to big rational (for precision)
-	percentageStr := fmt.Sprintf("%.18f", percentage)
-	percentageBig, ok := new(big.Rat).SetString(percentageStr)
-	if !ok {
-		return "0", fmt.Errorf("invalid percentage: %f", percentage)
-	}
-
-	// Convert amount to rational
-	amountRat := new(big.Rat).SetInt(bigAmount)
-	
-	// Multiply
-	result := new(big.Rat).Mul(amountRat, percentageBig)
-	
-	// Convert back to integer (truncate decimals)
-	resultInt := new(big.Int).Div(result.Num(), result.Denom())
-	
-	return resultInt.String(), nil
-}
-
 // CompareTokenAmounts compares two token amounts
 // Returns: -1 if amount1 < amount2, 0 if equal, 1 if amount1 > amount2
 func (tm *TokenMath) CompareTokenAmounts(amount1, amount2 string) (int, error) {
@@ -125,6 +96,29 @@ func (tm *TokenMath) CompareTokenAmounts(amount1, amount2 string) (int, error) {
 	return big1.Cmp(big2), nil
 }
 
+// DiffTokenAmounts returns amount2 minus amount1, negative when the amount went down
+// Unlike SubtractTokenAmounts the result is never clamped, e.g. DiffTokenAmounts("5", "3") returns "-2"
+func (tm *TokenMath) DiffTokenAmounts(amount1, amount2 string) (string, error) {
+	if amount1 == "" {
+		amount1 = "0"
+	}
+	if amount2 == "" {
+		amount2 = "0"
+	}
+
+	big1, ok1 := new(big.Int).SetString(amount1, 10)
+	big2, ok2 := new(big.Int).SetString(amount2, 10)
+
+	if !ok1 {
+		return "0", fmt.Errorf("invalid amount1: %s", amount1)
+	}
+	if !ok2 {
+		return "0", fmt.Errorf("invalid amount2: %s", amount2)
+	}
+
+	return new(big.Int).Sub(big2, big1).String(), nil
+}
+
 // IsZero checks if a token amount is zero
 func (tm *TokenMath) IsZero(amount string) bool {
 	if amount == "" || amount == "0" {
@@ -168,38 +162,47 @@ func (tm *TokenMath) SumTokenAmounts(amounts []string) (string, error) {
 	return total, nil
 }
 
-// CalculatePercentage calculates what percentage amount1 is of amount2
-// Returns percentage as float64 (0.0 - 1.0)
-func (tm *TokenMath) CalculatePercentage(amount1, amount2 string) (float64, error) {
-	if amount1 == "" {
-		amount1 = "0"
-	}
-	if amount2 == "" || amount2 == "0" {
-		return 0.0, nil // Division by zero returns 0%
+// SumValidTokenAmounts sums token amounts, skipping empty or malformed ones
+// Returns the total and how many amounts were skipped
+func (tm *TokenMath) SumValidTokenAmounts(amounts []string) (string, int) {
+	total := new(big.Int)
+	skipped := 0
+	
+	for _, amount := range amounts {
+		bigAmount, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+		if !ok {
+			skipped++
+			continue
+		}
+		total.Add(total, bigAmount)
 	}
-
-	// Convert to big integers
-	big1, ok1 := new(big.Int).SetString(amount1, 10)
-	big2, ok2 := new(big.Int).SetString(amount2, 10)
 	
-	if !ok1 {
-		return 0.0, fmt.Errorf("invalid amount1: %s", amount1)
+	return total.String(), skipped
+}
+
+// FormatPercent returns part as a percentage of whole with exactly two decimals, rounded half
+// away from zero, e.g. FormatPercent("2", "3") returns "66.67". A zero whole is "0.00"
+func (tm *TokenMath) FormatPercent(part, whole string) (string, error) {
+	if part == "" {
+		part = "0"
 	}
-	if !ok2 {
-		return 0.0, fmt.Errorf("invalid amount2: %s", amount2)
+	bigPart, ok := new(big.Int).SetString(part, 10)
+	if !ok {
+		return "0.00", fmt.Errorf("invalid amount: %s", part)
+	}
+	if whole == "" {
+		return "0.00", nil
+	}
+	bigWhole, ok := new(big.Int).SetString(whole, 10)
+	if !ok {
+		return "0.00", fmt.Errorf("invalid amount: %s", whole)
+	}
+	if bigWhole.Sign() == 0 {
+		return "0.00", nil
 	}
 
-	// Convert to rationals for precise division
-	rat1 := new(big.Rat).SetInt(big1)
-	rat2 := new(big.Rat).SetInt(big2)
-	
-	// Calculate percentage
-	result := new(big.Rat).Quo(rat1, rat2)
-	
-	// Convert to float64
-	percentage, _ := result.Float64()
-	
-	return percentage, nil
+	percent := new(big.Rat).SetFrac(new(big.Int).Mul(bigPart, big.NewInt(100)), bigWhole)
+	return percent.FloatString(2), nil
 }
 
 // FormatTokenAmount formats a token amount for display (adds commas, etc.)
@@ -221,6 +224,86 @@ func (tm *TokenMath) FormatTokenAmount(amount string) string {
 	return addCommas(cleaned)
 }
 
+// FormatUnits scales a raw token amount by the token decimals
+// e.g. FormatUnits("1500000", 6) returns "1.5"
+func (tm *TokenMath) FormatUnits(amount string, decimals uint8) (string, error) {
+	if amount == "" {
+		amount = "0"
+	}
+
+	bigAmount, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return "0", fmt.Errorf("invalid amount: %s", amount)
+	}
+
+	negative := bigAmount.Sign() < 0
+	digits := new(big.Int).Abs(bigAmount).String()
+
+	// Left-pad so there is always at least one integer digit
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	integerPart := digits[:len(digits)-int(decimals)]
+	fractionPart := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+
+	result := integerPart
+	if fractionPart != "" {
+		result = integerPart + "." + fractionPart
+	}
+	if negative {
+		result = "-" + result
+	}
+
+	return result, nil
+}
+
+// ParseUnits is the inverse of FormatUnits, scaling a decimal amount to the token's smallest unit
+// e.g. ParseUnits("1.5", 6) returns "1500000"; more fractional digits than decimals is an error
+func (tm *TokenMath) ParseUnits(amount string, decimals uint8) (string, error) {
+	integerPart, fractionPart, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if integerPart == "" {
+		integerPart = "0"
+	}
+	fractionPart = strings.TrimRight(fractionPart, "0")
+	if len(fractionPart) > int(decimals) {
+		return "0", fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+
+	digits := integerPart + fractionPart + strings.Repeat("0", int(decimals)-len(fractionPart))
+	bigAmount, ok := new(big.Int).SetString(digits, 10)
+	if !ok || strings.ContainsAny(fractionPart, "+-") {
+		return "0", fmt.Errorf("invalid amount: %s", amount)
+	}
+
+	return bigAmount.String(), nil
+}
+
+// ProRataShare returns the share of total owed to balance out of supply, rounded down
+// e.g. ProRataShare("100", "1", "3") returns "33"
+func (tm *TokenMath) ProRataShare(total, balance, supply string) (string, error) {
+	bigTotal, ok := new(big.Int).SetString(total, 10)
+	if !ok {
+		return "0", fmt.Errorf("invalid total: %s", total)
+	}
+	bigBalance, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return "0", fmt.Errorf("invalid balance: %s", balance)
+	}
+	bigSupply, ok := new(big.Int).SetString(supply, 10)
+	if !ok {
+		return "0", fmt.Errorf("invalid supply: %s", supply)
+	}
+	if bigSupply.Sign() <= 0 {
+		return "0", fmt.Errorf("supply must be positive: %s", supply)
+	}
+
+	// Multiply before dividing so only the final division truncates
+	share := new(big.Int).Mul(bigTotal, bigBalance)
+	share.Quo(share, bigSupply)
+	return share.String(), nil
+}
+
 // addCommas adds comma separators to a numeric string
 func addCommas(s string) string {
 	// Handle negative numbers
@@ -256,4 +339,59 @@ func reverse(s string) string {
 }
 
 // Global instance for convenience
-var GlobalTokenMath = NewTokenMath()
\ No newline at end of file
+var GlobalTokenMath = NewTokenMath()
+// AddNumeric adds two BigNumeric amounts, rejecting either if it does not parse
+func (tm *TokenMath) AddNumeric(amount1, amount2 models.BigNumeric) (models.BigNumeric, error) {
+	a, err := amount1.Int()
+	if err != nil {
+		return models.ZeroNumeric, err
+	}
+	b, err := amount2.Int()
+	if err != nil {
+		return models.ZeroNumeric, err
+	}
+	return models.NewBigNumeric(new(big.Int).Add(a, b)), nil
+}
+
+// SubtractNumeric subtracts amount2 from amount1, returning an error rather than a negative amount
+func (tm *TokenMath) SubtractNumeric(amount1, amount2 models.BigNumeric) (models.BigNumeric, error) {
+	a, err := amount1.Int()
+	if err != nil {
+		return models.ZeroNumeric, err
+	}
+	b, err := amount2.Int()
+	if err != nil {
+		return models.ZeroNumeric, err
+	}
+	if a.Cmp(b) < 0 {
+		return models.ZeroNumeric, fmt.Errorf("subtracting %s from %s would be negative", amount2, amount1)
+	}
+	return models.NewBigNumeric(new(big.Int).Sub(a, b)), nil
+}
+
+// CompareNumeric compares two BigNumeric amounts
+// Returns -1 if amount1 < amount2, 0 if equal, 1 if amount1 > amount2
+func (tm *TokenMath) CompareNumeric(amount1, amount2 models.BigNumeric) (int, error) {
+	a, err := amount1.Int()
+	if err != nil {
+		return 0, err
+	}
+	b, err := amount2.Int()
+	if err != nil {
+		return 0, err
+	}
+	return a.Cmp(b), nil
+}
+
+// SumNumeric sums BigNumeric amounts, failing on the first that does not parse
+func (tm *TokenMath) SumNumeric(amounts []models.BigNumeric) (models.BigNumeric, error) {
+	total := new(big.Int)
+	for _, amount := range amounts {
+		value, err := amount.Int()
+		if err != nil {
+			return models.ZeroNumeric, err
+		}
+		total.Add(total, value)
+	}
+	return models.NewBigNumeric(total), nil
+}
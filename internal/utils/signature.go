@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrSignatureMismatch is returned when a signature was not made by the expected address
+var ErrSignatureMismatch = errors.New("signature does not match address")
+
+// PersonalMessageHash returns the EIP-191 hash wallets sign for personal_sign:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+func PersonalMessageHash(message string) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(hash, "\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	return hash.Sum(nil)
+}
+
+// AddressFromPublicKey returns the lowercase Ethereum address of a public key
+func AddressFromPublicKey(key *secp256k1.PublicKey) string {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(key.SerializeUncompressed()[1:])
+	return "0x" + hex.EncodeToString(hash.Sum(nil)[12:])
+}
+
+// RecoverPersonalSignAddress returns the lowercase address that signed message with personal_sign,
+// the same recovery go-ethereum's crypto.Ecrecover performs. The signature is the 65-byte hex
+// r || s || v, with v either 27/28 or 0/1
+func RecoverPersonalSignAddress(message, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", fmt.Errorf("signature must be 65 bytes of hex")
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", fmt.Errorf("invalid signature recovery id %d", sig[64])
+	}
+
+	// Recovery expects the compact form: 27 + recovery id, then r || s
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+	key, _, err := ecdsa.RecoverCompact(compact, PersonalMessageHash(message))
+	if err != nil {
+		return "", fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return AddressFromPublicKey(key), nil
+}
+
+// VerifyPersonalSignature returns ErrSignatureMismatch unless address signed message with personal_sign
+func VerifyPersonalSignature(address, message, signature string) error {
+	signer, err := RecoverPersonalSignAddress(message, signature)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(signer, address) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
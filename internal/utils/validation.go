@@ -3,6 +3,8 @@ package utils
 import (
 	"regexp"
 	"strings"
+
+	"golang.org/x/crypto/sha3"
 )
 
 // IsValidEthereumAddress validates if a string is a valid Ethereum address
@@ -38,4 +40,29 @@ func IsValidTransactionHash(hash string) bool {
 // NormalizeAddress converts an Ethereum address to lowercase
 func NormalizeAddress(address string) string {
 	return strings.ToLower(address)
-}
\ No newline at end of file
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case form of an Ethereum address,
+// or the address unchanged when it is not valid
+func ChecksumAddress(address string) string {
+	if !IsValidEthereumAddress(address) {
+		return address
+	}
+
+	lower := []byte(strings.ToLower(address[2:]))
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(lower)
+	digest := hash.Sum(nil)
+
+	// A hex letter is uppercased when the matching nibble of the hash is 8 or more
+	for i, ch := range lower {
+		nibble := digest[i/2] >> 4
+		if i%2 == 1 {
+			nibble = digest[i/2] & 0x0f
+		}
+		if ch >= 'a' && nibble >= 8 {
+			lower[i] = ch - 'a' + 'A'
+		}
+	}
+	return "0x" + string(lower)
+}
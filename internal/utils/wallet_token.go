@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// WalletClockSkew is how far a wallet's clock may be from ours when its sign-in message and
+// session token times are checked
+const WalletClockSkew = time.Minute
+
+// walletTokenAudience keeps wallet session tokens from being accepted as any other token
+// signed with the same secret
+const walletTokenAudience = "sukuk-wallet"
+
+// Wallet session token errors
+var (
+	ErrInvalidWalletToken = errors.New("invalid wallet token")
+	ErrWalletTokenExpired = errors.New("wallet token expired")
+)
+
+// walletClaims are the claims of a wallet session token
+type walletClaims struct {
+	Subject   string `json:"sub"` // Lowercase wallet address
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// NewWalletToken returns a session token for a wallet that proved it controls address,
+// an HS256 JWT under secret valid until expiresAt
+func NewWalletToken(secret, address string, issuedAt, expiresAt time.Time) (string, error) {
+	return SignJWT(secret, walletClaims{
+		Subject:   strings.ToLower(address),
+		Audience:  walletTokenAudience,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+}
+
+// ParseWalletToken returns the address of a token made by NewWalletToken. The signature is
+// checked before the expiry, so an expired token is still a genuine one
+func ParseWalletToken(secret, token string, now time.Time) (string, error) {
+	var claims walletClaims
+	if err := ParseJWT(secret, token, &claims); err != nil {
+		return "", ErrInvalidWalletToken
+	}
+	if claims.Audience != walletTokenAudience || !IsValidEthereumAddress(claims.Subject) {
+		return "", ErrInvalidWalletToken
+	}
+	if now.Add(-WalletClockSkew).Unix() >= claims.ExpiresAt || now.Add(WalletClockSkew).Unix() < claims.IssuedAt {
+		return "", ErrWalletTokenExpired
+	}
+	return claims.Subject, nil
+}
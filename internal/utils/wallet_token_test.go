@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWalletToken(t *testing.T) {
+	const address = "0x00000000000000000000000000000000000000aa"
+	issued := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	expiresAt := issued.Add(15 * time.Minute)
+	token, err := NewWalletToken("secret", strings.ToUpper(address[:4])+address[4:], issued, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := ParseWalletToken("secret", token, issued.Add(time.Minute)); err != nil || got != address {
+		t.Errorf("Expected %s, got %q and %v", address, got, err)
+	}
+
+	// Clocks a little apart still accept the token at either end of its life
+	if _, err := ParseWalletToken("secret", token, issued.Add(-30*time.Second)); err != nil {
+		t.Errorf("Expected a token issued just ahead of our clock to be accepted, got %v", err)
+	}
+	if _, err := ParseWalletToken("secret", token, expiresAt.Add(30*time.Second)); err != nil {
+		t.Errorf("Expected a token just past its expiry to be accepted within the skew, got %v", err)
+	}
+	if _, err := ParseWalletToken("secret", token, expiresAt.Add(WalletClockSkew)); !errors.Is(err, ErrWalletTokenExpired) {
+		t.Errorf("Expected an expired token to be rejected, got %v", err)
+	}
+	if _, err := ParseWalletToken("secret", token, issued.Add(-2*WalletClockSkew)); !errors.Is(err, ErrWalletTokenExpired) {
+		t.Errorf("Expected a token from the future to be rejected, got %v", err)
+	}
+
+	if _, err := ParseWalletToken("other", token, issued); !errors.Is(err, ErrInvalidWalletToken) {
+		t.Errorf("Expected a token under another secret to be rejected, got %v", err)
+	}
+	parts := strings.Split(token, ".")
+	for _, invalid := range []string{"", "a.b", parts[0] + "." + parts[2] + "." + parts[1], "eyJhbGciOiJub25lIn0." + parts[1] + "."} {
+		if _, err := ParseWalletToken("secret", invalid, issued); !errors.Is(err, ErrInvalidWalletToken) {
+			t.Errorf("Expected token %q to be rejected, got %v", invalid, err)
+		}
+	}
+}
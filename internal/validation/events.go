@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+// SukukPurchased checks a purchase event and lowercases its addresses, returning Errors
+// for every invalid field
+func SukukPurchased(event *models.SukukPurchased, now time.Time) error {
+	var check Checker
+	check.Address("buyer", &event.Buyer)
+	check.Address("sukuk_address", &event.SukukAddress)
+	check.Address("payment_token", &event.PaymentToken)
+	check.PositiveAmount("amount", event.Amount.String())
+	check.TxHash("tx_hash", event.TxHash)
+	check.Timestamp("timestamp", event.Timestamp, now)
+	return check.Err()
+}
+
+// RedemptionRequested checks a redemption request event and lowercases its addresses,
+// returning Errors for every invalid field
+func RedemptionRequested(event *models.RedemptionRequested, now time.Time) error {
+	var check Checker
+	check.Address("user", &event.User)
+	check.Address("sukuk_address", &event.SukukAddress)
+	check.Address("payment_token", &event.PaymentToken)
+	check.PositiveAmount("amount", event.Amount.String())
+	check.PositiveAmount("total_supply", event.TotalSupply.String())
+	check.TxHash("tx_hash", event.TxHash)
+	check.Timestamp("timestamp", event.Timestamp, now)
+	return check.Err()
+}
@@ -0,0 +1,104 @@
+// Package validation checks blockchain event data before it is stored, whether it arrives
+// from an API payload or from an indexer row
+package validation
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"sukuk-be/internal/utils"
+)
+
+// MinTimestamp is the Ethereum genesis block; no event can be older
+var MinTimestamp = time.Date(2015, time.July, 30, 0, 0, 0, 0, time.UTC)
+
+// MaxFutureSkew is how far ahead of the local clock an event timestamp may be
+const MaxFutureSkew = time.Hour
+
+// txHashPattern matches a lowercase transaction hash
+var txHashPattern = regexp.MustCompile(`^0x[0-9a-f]{64}$`)
+
+// FieldError is a rule one field failed
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors are the field errors of one payload, in the order the fields were checked
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Field + ": " + fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Checker collects field errors so a payload reports every invalid field at once
+type Checker struct {
+	errors Errors
+}
+
+// Fail records a field error
+func (c *Checker) Fail(field, message string) {
+	c.errors = append(c.errors, FieldError{Field: field, Message: message})
+}
+
+// Err returns the collected Errors, or nil when every check passed
+func (c *Checker) Err() error {
+	if len(c.errors) == 0 {
+		return nil
+	}
+	return c.errors
+}
+
+// Address checks an Ethereum address and lowercases it in place
+func (c *Checker) Address(field string, address *string) {
+	if !utils.IsValidEthereumAddress(*address) {
+		c.Fail(field, "must be a 0x-prefixed 40 character hex address")
+		return
+	}
+	*address = utils.NormalizeAddress(*address)
+}
+
+// PositiveAmount checks that amount is a base-10 integer greater than zero
+func (c *Checker) PositiveAmount(field, amount string) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		c.Fail(field, "must be an integer amount")
+		return
+	}
+	if value.Sign() <= 0 {
+		c.Fail(field, "must be greater than zero")
+	}
+}
+
+// TxHash checks that hash is a lowercase 0x-prefixed 64 character hex transaction hash
+func (c *Checker) TxHash(field, hash string) {
+	if !txHashPattern.MatchString(hash) {
+		c.Fail(field, "must match ^0x[0-9a-f]{64}$")
+	}
+}
+
+// LogIndex checks that a log index is not negative
+func (c *Checker) LogIndex(field string, index int64) {
+	if index < 0 {
+		c.Fail(field, "must not be negative")
+	}
+}
+
+// Timestamp checks that an event time is set, not before MinTimestamp and at most
+// MaxFutureSkew after now
+func (c *Checker) Timestamp(field string, timestamp, now time.Time) {
+	switch {
+	case timestamp.IsZero() || timestamp.Unix() == 0:
+		c.Fail(field, "is required")
+	case timestamp.Before(MinTimestamp):
+		c.Fail(field, "is before the first Ethereum block")
+	case timestamp.After(now.Add(MaxFutureSkew)):
+		c.Fail(field, "is in the future")
+	}
+}
@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"sukuk-be/internal/models"
+)
+
+var testNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+const testTxHash = "0x" + "ab12000000000000000000000000000000000000000000000000000000000000"
+
+func TestCheckerRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(c *Checker)
+		valid bool
+	}{
+		{"checksummed address", func(c *Checker) { a := "0xf57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"; c.Address("a", &a) }, true},
+		{"short address", func(c *Checker) { a := "0xf57093"; c.Address("a", &a) }, false},
+		{"address without prefix", func(c *Checker) { a := "f57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9aa"; c.Address("a", &a) }, false},
+		{"non-hex address", func(c *Checker) { a := "0xg57093Ea18E5CfF6E7bB3bb770Ae9C492277A5a9"; c.Address("a", &a) }, false},
+		{"positive amount", func(c *Checker) { c.PositiveAmount("n", "1000000000000000000000000000000") }, true},
+		{"zero amount", func(c *Checker) { c.PositiveAmount("n", "0") }, false},
+		{"negative amount", func(c *Checker) { c.PositiveAmount("n", "-5") }, false},
+		{"decimal amount", func(c *Checker) { c.PositiveAmount("n", "1.5") }, false},
+		{"empty amount", func(c *Checker) { c.PositiveAmount("n", "") }, false},
+		{"lowercase tx hash", func(c *Checker) { c.TxHash("h", testTxHash) }, true},
+		{"uppercase tx hash", func(c *Checker) { c.TxHash("h", "0xAB"+testTxHash[4:]) }, false},
+		{"short tx hash", func(c *Checker) { c.TxHash("h", testTxHash[:65]) }, false},
+		{"zero log index", func(c *Checker) { c.LogIndex("i", 0) }, true},
+		{"negative log index", func(c *Checker) { c.LogIndex("i", -1) }, false},
+		{"recent timestamp", func(c *Checker) { c.Timestamp("t", testNow.Add(-time.Hour), testNow) }, true},
+		{"slightly ahead timestamp", func(c *Checker) { c.Timestamp("t", testNow.Add(time.Minute), testNow) }, true},
+		{"zero timestamp", func(c *Checker) { c.Timestamp("t", time.Time{}, testNow) }, false},
+		{"unix epoch timestamp", func(c *Checker) { c.Timestamp("t", time.Unix(0, 0), testNow) }, false},
+		{"pre-genesis timestamp", func(c *Checker) { c.Timestamp("t", MinTimestamp.Add(-time.Second), testNow) }, false},
+		{"far future timestamp", func(c *Checker) { c.Timestamp("t", testNow.AddDate(1, 0, 0), testNow) }, false},
+	}
+	for _, tt := range tests {
+		var check Checker
+		tt.check(&check)
+		if valid := check.Err() == nil; valid != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.name, tt.valid, check.Err())
+		}
+	}
+}
+
+func TestSukukPurchasedReportsEveryField(t *testing.T) {
+	event := models.SukukPurchased{
+		Buyer:        "0xF57093EA18E5CFF6E7BB3BB770AE9C492277A5A9",
+		SukukAddress: "0x00000000000000000000000000000000000Dec01",
+		PaymentToken: "0x00000000000000000000000000000000000000cc",
+		Amount:       "1000",
+		TxHash:       testTxHash,
+		Timestamp:    testNow,
+	}
+	if err := SukukPurchased(&event, testNow); err != nil {
+		t.Fatalf("Expected a valid purchase, got %v", err)
+	}
+	if event.Buyer != "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9" || event.SukukAddress != "0x00000000000000000000000000000000000dec01" {
+		t.Errorf("Expected addresses to be lowercased, got %s and %s", event.Buyer, event.SukukAddress)
+	}
+
+	invalid := models.SukukPurchased{Buyer: "nope", SukukAddress: event.SukukAddress, PaymentToken: event.PaymentToken, Amount: "-1", TxHash: "0x1"}
+	var fieldErrors Errors
+	if err := SukukPurchased(&invalid, testNow); !errors.As(err, &fieldErrors) {
+		t.Fatalf("Expected field errors, got %v", err)
+	}
+	var fields []string
+	for _, fieldError := range fieldErrors {
+		fields = append(fields, fieldError.Field)
+	}
+	if got := strings.Join(fields, ","); got != "buyer,amount,tx_hash,timestamp" {
+		t.Errorf("Expected errors for buyer, amount, tx_hash and timestamp, got %s", got)
+	}
+}
+
+func TestRedemptionRequestedRequiresTotalSupply(t *testing.T) {
+	event := models.RedemptionRequested{
+		User:         "0xf57093ea18e5cff6e7bb3bb770ae9c492277a5a9",
+		SukukAddress: "0x00000000000000000000000000000000000dec01",
+		PaymentToken: "0x00000000000000000000000000000000000000cc",
+		Amount:       "1000",
+		TotalSupply:  "0",
+		TxHash:       testTxHash,
+		Timestamp:    testNow,
+	}
+	var fieldErrors Errors
+	if err := RedemptionRequested(&event, testNow); !errors.As(err, &fieldErrors) || len(fieldErrors) != 1 || fieldErrors[0].Field != "total_supply" {
+		t.Errorf("Expected only total_supply to be rejected, got %v", err)
+	}
+}
@@ -1,12 +1,24 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"sukuk-be/internal/cache"
 	"sukuk-be/internal/config"
 	"sukuk-be/internal/database"
+	"sukuk-be/internal/fx"
+	"sukuk-be/internal/lifecycle"
 	"sukuk-be/internal/logger"
+	"sukuk-be/internal/models"
+	"sukuk-be/internal/preflight"
 	"sukuk-be/internal/server"
 	"sukuk-be/internal/services"
-	"time"
+	"sukuk-be/internal/stream"
 
 	_ "sukuk-be/docs" // This will be generated by swag init
 )
@@ -32,9 +44,24 @@ import (
 // @name X-API-Key
 // @description API key for accessing protected admin endpoints
 
+// @securityDefinitions.apikey WalletAuth
+// @in header
+// @name Authorization
+// @description Wallet session token from /auth/verify, as "Bearer <token>"
+
+// httpShutdownTimeout bounds the wait for requests in flight on shutdown
+const httpShutdownTimeout = 15 * time.Second
+
 func main() {
+	preflightOnly := flag.Bool("preflight", false, "Check configuration, database, schema, indexer tables, upload storage and RPC, print a report and exit")
+	preflightFormat := flag.String("preflight-format", "text", "Preflight report format: text or json")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
+	if *preflightOnly {
+		os.Exit(runPreflight(cfg, err, *preflightFormat))
+	}
 	if err != nil {
 		logger.Fatalf("Failed to load configuration: %v", err)
 	}
@@ -49,16 +76,240 @@ func main() {
 	}
 	defer database.Close()
 
+	// Response cache for hot read endpoints
+	if err := cache.Setup(cfg.Cache); err != nil {
+		logger.Fatalf("Failed to setup cache: %v", err)
+	}
+	defer cache.Close()
+
+	// Exchange rates for ?fiat display values; none configured leaves them out
+	if err := fx.Setup(cfg.FX); err != nil {
+		logger.Fatalf("Failed to setup exchange rates: %v", err)
+	}
+
+	// Indexer reads retry transient failures and fail fast while the indexer is down
+	indexerExecutorConfig := services.DefaultIndexerExecutorConfig()
+	indexerExecutorConfig.MaxRetries = cfg.Indexer.RetryAttempts
+	indexerExecutorConfig.BaseDelay = cfg.Indexer.RetryBaseDelay
+	indexerExecutorConfig.BreakerThreshold = cfg.Indexer.BreakerThreshold
+	indexerExecutorConfig.BreakerCooldown = cfg.Indexer.BreakerCooldown
+	services.SetDefaultIndexerExecutor(services.NewIndexerExecutor(indexerExecutorConfig))
+
+	// Latest activities on sukuk metadata responses when neither the request nor a runtime setting sets them
+	services.SetLatestActivitiesLimit(cfg.Activity.LatestLimit)
+
+	// Startup runs the same checks as --preflight, refusing to serve with failures
+	report := preflight.Run(context.Background(), cfg, database.GetDB(), nil)
+	for _, result := range report.Results {
+		entry := logger.WithFields(map[string]interface{}{"check": result.Check, "status": result.Status})
+		switch result.Status {
+		case preflight.StatusFail:
+			entry.Error(result.Message)
+		case preflight.StatusWarn:
+			entry.Warn(result.Message)
+		}
+	}
+	if report.Failed() {
+		logger.Fatalf("Preflight checks failed: %d failed, %d warnings", report.Count(preflight.StatusFail), report.Count(preflight.StatusWarn))
+	}
+
+	// Background services share a cancellable context so in-flight queries stop on shutdown.
+	// They are started and stopped through the lifecycle manager: dependencies first on
+	// start, last on stop, with the HTTP server stopped before everything it calls
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	components := lifecycle.New()
+	var workers []string // Components the HTTP server depends on
+	register := func(c lifecycle.Component) {
+		components.MustRegister(c)
+		workers = append(workers, c.Name)
+	}
+
+	// Runtime settings (admin-editable tunables in system_states), loaded before the services reading them
+	settingsService := services.NewSettingsService(database.GetDB(), cfg.Settings.RefreshInterval)
+	if err := settingsService.Reload(ctx); err != nil {
+		logger.WithError(err).Warn("Failed to load runtime settings, using defaults")
+	}
+	services.SetDefaultSettings(settingsService)
+	register(lifecycle.FromService("runtime settings", settingsService))
+
+	// Anomaly flags of the metadata sync and activity stream, served on /admin/sync/health
+	var syncAlerter services.SyncAlerter
+	if cfg.Sync.AlertWebhookURL != "" {
+		syncAlerter = services.NewWebhookSyncAlerter(cfg.Sync.AlertWebhookURL)
+	}
+	syncHealth := services.NewSyncHealthMonitor(services.SyncThresholds{
+		StallAfter:        cfg.Sync.StallThreshold,
+		MaxFailurePercent: cfg.Sync.FailureRatioThreshold,
+	}, syncAlerter)
+
 	// Sukuk Metadata sync service (syncs from indexer to metadata table)
-	metadataSyncService := services.NewSukukMetadataSyncService(5 * time.Second)
-	go metadataSyncService.Start()
-	defer metadataSyncService.Stop()
+	metadataSyncService := services.NewSukukMetadataSyncService(cfg.Sync.Interval)
+	metadataSyncService.SetHealthMonitor(syncHealth)
+	metadataSyncLock := services.NewSyncLock(database.GetDB(), services.SyncServiceMetadata, cfg.Sync.InstanceID, cfg.Sync.LockStaleAfter)
+	metadataSyncService.SetSyncLock(metadataSyncLock)
+	syncHealth.AddLock(metadataSyncLock)
+	metadataSyncService.SetSuspensionEvents(cfg.Sync.SuspendEvent, cfg.Sync.ResumeEvent)
+	metadataSyncService.SetOrderSettlementTolerance(cfg.Orders.SettlementToleranceBps)
+	if cfg.Sync.OnchainBackfill {
+		metadataSyncService.SetOnchainBackfill(services.NewRPCSukukContractReader(cfg.Blockchain.RPCEndpoint),
+			cfg.Sync.OnchainBackfillBatch, cfg.Sync.OnchainBackfillDelay)
+	}
+
+	uploadCleanupService := services.NewDefaultUploadCleanupService(cfg.App.UploadDir, cfg.Uploads.GracePeriod, cfg.Uploads.CleanupInterval)
+	retentionService := services.NewDefaultRetentionService(map[string]int{
+		models.SukukPurchased{}.TableName():      cfg.Retention.PurchaseEventDays,
+		models.RedemptionRequested{}.TableName(): cfg.Retention.RedemptionRequestEventDays,
+	}, cfg.Retention.BatchSize, cfg.Retention.BatchPause, cfg.Retention.Interval)
+	reorgReconciler := services.NewDefaultReorgReconciler(cfg.Reorg.LookbackBlocks, cfg.Reorg.Interval, syncAlerter)
+	reorgLock := services.NewSyncLock(database.GetDB(), services.SyncServiceReorg, cfg.Sync.InstanceID, cfg.Sync.LockStaleAfter)
+	reorgReconciler.SetSyncLock(reorgLock)
+	syncHealth.AddLock(reorgLock)
+
+	// Activity lists and the feed read the projection the projector keeps up to date, unless
+	// ACTIVITY_PROJECTION_READS switches them back to the indexer tables. It re-reads the reorg
+	// lookback window every run so reorged events are updated in place
+	services.SetActivityProjectionReads(cfg.Activity.ProjectionReads)
+	activityProjector := services.NewDefaultActivityProjector(cfg.Activity.ProjectionBatch, cfg.Reorg.LookbackBlocks, cfg.Activity.ProjectionInterval)
+	projectionLock := services.NewSyncLock(database.GetDB(), services.SyncServiceActivityProjection, cfg.Sync.InstanceID, cfg.Sync.LockStaleAfter)
+	activityProjector.SetSyncLock(projectionLock)
+	syncHealth.AddLock(projectionLock)
+
+	// A read-only replica serves reads only, so it runs none of the services that write
+	if cfg.App.ReadOnly {
+		logger.Warn("Read-only mode: mutating requests are rejected and background sync is disabled")
+	} else {
+		metadataSync := lifecycle.FromService("metadata sync", metadataSyncService, "runtime settings")
+		metadataSync.InFlight, metadataSync.Work = metadataSyncService.InFlight, "sync cycles"
+		register(metadataSync)
+
+		// Purchase order expiry (unpaid orders past expires_at)
+		register(lifecycle.FromService("order expiry", services.NewOrderExpiryService(cfg.Orders.ExpiryInterval), "runtime settings"))
+
+		// Prospectuses from the former single-file upload become version 1 documents
+		if imported, err := services.NewDefaultSukukDocumentService(cfg.App.UploadDir).ImportLegacyProspectuses(ctx); err != nil {
+			logger.WithError(err).Warn("Failed to import legacy prospectuses")
+		} else if imported > 0 {
+			logger.WithField("imported", imported).Info("Imported legacy prospectuses as sukuk documents")
+		}
 
-	// Start server
-	srv := server.New(cfg)
-	logger.WithField("port", cfg.App.Port).Info("Server starting")
+		// Orphaned upload cleanup (files no record references, past the grace period)
+		register(lifecycle.FromService("upload cleanup", uploadCleanupService, "runtime settings"))
 
-	if err := srv.Start(); err != nil {
-		logger.Fatalf("Server failed to start: %v", err)
+		// Processed event retention (deletes processed events past RETENTION_*_DAYS)
+		register(lifecycle.FromService("retention", retentionService, "runtime settings"))
+
+		// Chain reorg reconciliation (orphans derived rows of transactions dropped from the indexer)
+		register(lifecycle.FromService("reorg reconciler", reorgReconciler))
+
+		// Activity read model (copies new indexer events into activity_projections)
+		register(lifecycle.FromService("activity projector", activityProjector))
+	}
+
+	// Activity stream service (publishes newly indexed activities to SSE clients)
+	activityBroker := stream.NewBroker(stream.DefaultHistorySize, stream.DefaultBufferSize)
+	activityStreamService := services.NewActivityStreamService(activityBroker, cfg.Sync.Interval)
+	activityStreamService.SetHealthMonitor(syncHealth)
+	register(lifecycle.FromService("activity stream", activityStreamService))
+
+	// Synthetic indexer events for local development; config validation keeps it out of production
+	var eventInjector *services.EventInjector
+	if cfg.Dev.EventInjector {
+		injector, err := services.NewEventInjector(database.GetDB(), cfg.App.Environment)
+		if err != nil {
+			logger.Fatalf("Failed to enable the event injector: %v", err)
+		}
+		eventInjector = injector
+		logger.Warn("Dev event injector enabled: /api/v1/dev writes synthetic indexer events")
+	}
+
+	// Access log for forensics, apart from the application log; disabled unless ACCESS_LOG_SINK is set
+	accessLog, err := server.OpenAccessLog(cfg.AccessLog)
+	if err != nil {
+		logger.Fatalf("Failed to open the access log: %v", err)
+	}
+	if accessLog != nil {
+		defer accessLog.Close()
+	}
+
+	// Per-API-key usage counters, flushed on an interval and once more on shutdown; a
+	// read-only replica can't write them
+	var apiKeyUsage *services.APIKeyUsageRecorder
+	if !cfg.App.ReadOnly {
+		apiKeyUsage = services.NewDefaultAPIKeyUsageRecorder(cfg.API.UsageFlushInterval)
+		usage := lifecycle.FromService("api key usage", apiKeyUsage)
+		usage.InFlight, usage.Work = apiKeyUsage.InFlight, "usage buckets"
+		register(usage)
+	}
+
+	// HTTP server, stopped first so no request reaches a stopped service
+	srv := server.New(cfg, metadataSyncService, activityBroker, uploadCleanupService, retentionService, eventInjector, syncHealth, accessLog, apiKeyUsage)
+	serveErr := make(chan error, 1)
+	components.MustRegister(lifecycle.Component{
+		Name:      "http server",
+		DependsOn: workers,
+		Start: func(context.Context) error {
+			logger.WithField("port", cfg.App.Port).Info("Server starting")
+			go func() { serveErr <- srv.Start() }()
+			return nil
+		},
+		Stop:        srv.Shutdown,
+		StopTimeout: httpShutdownTimeout,
+		InFlight:    srv.InFlight,
+		Work:        "requests",
+	})
+
+	if err := components.Start(ctx); err != nil {
+		logger.Fatalf("Failed to start: %v", err)
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	select {
+	case <-signals.Done():
+		logger.Info("Shutting down")
+		err = nil
+	case err = <-serveErr:
+		logger.WithError(err).Error("Server stopped, shutting down")
+	}
+	if stopErr := components.Stop(context.Background()); stopErr != nil {
+		logger.WithError(stopErr).Error("Shutdown did not complete cleanly")
+	}
+	if err != nil {
+		logger.Fatalf("Server failed: %v", err)
+	}
+}
+
+// runPreflight checks the deployment without starting the server, printing the report to
+// stdout. It returns the exit code: 1 when a check failed, 2 for an unknown format
+func runPreflight(cfg *config.Config, configErr error, format string) int {
+	if format != "text" && format != "json" {
+		logger.Errorf("Unknown preflight format %q, expected text or json", format)
+		return 2
+	}
+
+	report := &preflight.Report{}
+	if configErr != nil {
+		report.Add(preflight.Result{Check: "config", Status: preflight.StatusFail, Message: configErr.Error()})
+	} else {
+		logger.Init("error", cfg.Logger.Format)
+		dbErr := database.Connect(cfg)
+		if dbErr == nil {
+			defer database.Close()
+		}
+		report = preflight.Run(context.Background(), cfg, database.GetDB(), dbErr)
+	}
+
+	write := report.WriteText
+	if format == "json" {
+		write = report.WriteJSON
+	}
+	if err := write(os.Stdout); err != nil {
+		logger.Errorf("Failed to write preflight report: %v", err)
+		return 1
+	}
+	if report.Failed() {
+		return 1
 	}
+	return 0
 }